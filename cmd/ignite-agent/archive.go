@@ -0,0 +1,131 @@
+package main
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+)
+
+func handleArchive(w http.ResponseWriter, r *http.Request) {
+	filePath := r.URL.Query().Get(protocol.PathParam)
+	if !path.IsAbs(filePath) {
+		http.Error(w, fmt.Sprintf("the %s parameter needs to be an absolute path", protocol.PathParam), http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if _, err := os.Lstat(filePath); err != nil {
+			fileError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-tar")
+		if err := protocol.Archive(w, filePath); err != nil {
+			log.Printf("Failed to send %q: %v", filePath, err)
+		}
+
+	case http.MethodPut:
+		n, err := copyArchive(filePath, r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to copy the files: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		unix.Sync()
+		log.Printf("Copied %d files to %q", n, filePath)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// copyArchive copies the directory in the tar stream written by protocol.Archive to dest,
+// and returns how many files it wrote. Files that exist already are replaced.
+func copyArchive(dest string, r io.Reader) (n int, err error) {
+	tr := tar.NewReader(r)
+	var target string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		// The first entry is the copied directory itself
+		if len(target) == 0 {
+			if target, err = copyTarget(dest, hdr); err != nil {
+				return n, err
+			}
+		}
+
+		// Archive doesn't write hardlinks, which could link to files anywhere in the VM
+		if hdr.Typeflag == tar.TypeLink {
+			return n, fmt.Errorf("%q is a hardlink", hdr.Name)
+		}
+
+		// The archive can't write through the symlinks it created
+		name, err := protocol.ArchiveJoin(target, hdr.Name)
+		if err != nil {
+			return n, err
+		}
+
+		if fi, err := os.Lstat(name); err == nil {
+			if fi.IsDir() && hdr.Typeflag == tar.TypeDir {
+				continue
+			}
+
+			if err := os.Remove(name); err != nil {
+				return n, err
+			}
+		} else if !os.IsNotExist(err) {
+			return n, err
+		}
+
+		if err := extractFile(target, name, hdr, tr); err != nil {
+			return n, fmt.Errorf("failed to copy %q: %v", hdr.Name, err)
+		}
+		n++
+	}
+}
+
+// copyTarget returns where the directory of the tar header is copied to when copying it
+// to dest. If dest is a directory with a different name, the directory is copied into it.
+func copyTarget(dest string, hdr *tar.Header) (string, error) {
+	if hdr.Typeflag != tar.TypeDir {
+		return "", fmt.Errorf("%q is not a directory", hdr.Name)
+	}
+
+	fi, err := os.Stat(dest)
+	if os.IsNotExist(err) {
+		return dest, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if !fi.IsDir() {
+		return "", fmt.Errorf("cannot overwrite non-directory %q with directory %q", dest, hdr.Name)
+	}
+
+	if path.Base(dest) == path.Base(hdr.Name) {
+		return dest, nil
+	}
+
+	target := path.Join(dest, path.Base(hdr.Name))
+	if fi, err := os.Stat(target); err == nil && !fi.IsDir() {
+		return "", fmt.Errorf("cannot overwrite non-directory %q with directory %q", target, hdr.Name)
+	}
+
+	return target, nil
+}
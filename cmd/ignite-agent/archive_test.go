@@ -0,0 +1,67 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyArchiveSymlinkEscape(t *testing.T) {
+	dir, err := ioutil.TempDir("", "agent-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	outside := filepath.Join(dir, "outside")
+	if err := os.Mkdir(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		headers []*tar.Header
+	}{
+		{
+			name: "file through symlink",
+			headers: []*tar.Header{
+				{Name: "project/etc", Typeflag: tar.TypeSymlink, Linkname: outside},
+				{Name: "project/etc/passwd", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+		},
+		{
+			name: "hardlink",
+			headers: []*tar.Header{
+				{Name: "project/passwd", Typeflag: tar.TypeLink, Linkname: "/etc/passwd"},
+			},
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			dest := filepath.Join(dir, "copy")
+			defer os.RemoveAll(dest)
+
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			for _, hdr := range append([]*tar.Header{{Name: "project", Typeflag: tar.TypeDir, Mode: 0755}}, rt.headers...) {
+				if err := tw.WriteHeader(hdr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := copyArchive(dest, &buf); err == nil {
+				t.Fatal("expected an error")
+			}
+			if _, err := os.Lstat(filepath.Join(outside, "passwd")); !os.IsNotExist(err) {
+				t.Errorf("expected no file written outside of the destination, got %v", err)
+			}
+		})
+	}
+}
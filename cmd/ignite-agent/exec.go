@@ -0,0 +1,370 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+)
+
+// handleExec starts the requested command, and upgrades the connection to an exec
+// stream connecting the command to the host until it exits
+func handleExec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !strings.EqualFold(r.Header.Get("Upgrade"), protocol.ExecUpgrade) {
+		http.Error(w, fmt.Sprintf("exec requests need to upgrade to %q", protocol.ExecUpgrade), http.StatusUpgradeRequired)
+		return
+	}
+
+	req := &protocol.ExecRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid exec request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Command) == 0 {
+		http.Error(w, "no command given", http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection can't be upgraded", http.StatusInternalServerError)
+		return
+	}
+
+	cmd := exec.Command(req.Command[0], req.Command[1:]...)
+	cmd.Env = append(os.Environ(), req.Env...)
+	cmd.Dir = req.Dir
+	if len(req.Root) > 0 {
+		// The executable is looked up inside of the root, the command sees it as /
+		executable, err := lookPathIn(req.Root, req.Command[0], req.Env)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cmd.Path = executable
+		cmd.Env = req.Env
+		cmd.SysProcAttr = &syscall.SysProcAttr{Chroot: req.Root}
+	}
+
+	if len(req.ID) > 0 && !processes.reserve(req.ID) {
+		http.Error(w, fmt.Sprintf("a command with ID %q is running already", req.ID), http.StatusConflict)
+		return
+	}
+	defer processes.release(req.ID)
+
+	p, err := startProcess(cmd, req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to run %q: %v", req.Command[0], err), http.StatusInternalServerError)
+		return
+	}
+	processes.set(req.ID, p)
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		log.Printf("Failed to upgrade the exec connection: %v", err)
+		p.kill()
+		return
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: %s\r\n\r\n", protocol.ExecUpgrade)
+	if err := rw.Flush(); err != nil {
+		log.Printf("Failed to upgrade the exec connection: %v", err)
+		p.kill()
+		return
+	}
+
+	fw := protocol.NewFrameWriter(conn)
+	go p.handleInput(rw.Reader)
+
+	code := p.wait(fw)
+	if err := fw.WriteFrame(protocol.StreamExit, protocol.EncodeExitCode(code)); err != nil {
+		log.Printf("Failed to send the exit code of %q: %v", req.Command[0], err)
+	}
+}
+
+// process is a command started by an exec request
+type process struct {
+	cmd *exec.Cmd
+	// stdin is closed when the host closes its input
+	stdin io.WriteCloser
+	// outputs are copied to the host until they're closed
+	outputs map[protocol.StreamType]io.ReadCloser
+	// pty is the master end of the pseudo-terminal of the command, if any
+	pty *os.File
+}
+
+// startProcess starts cmd connected to pipes, or a pseudo-terminal if requested
+func startProcess(cmd *exec.Cmd, req *protocol.ExecRequest) (*process, error) {
+	p := &process{cmd: cmd}
+
+	if req.Tty {
+		master, slave, err := openPty()
+		if err != nil {
+			return nil, err
+		}
+		defer slave.Close()
+
+		if req.Size.Width > 0 && req.Size.Height > 0 {
+			if err := resizePty(master, req.Size); err != nil {
+				master.Close()
+				return nil, err
+			}
+		}
+
+		// Run the command in a new session controlled by the terminal, like a login shell
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+		if cmd.SysProcAttr == nil {
+			cmd.SysProcAttr = &syscall.SysProcAttr{}
+		}
+		cmd.SysProcAttr.Setsid, cmd.SysProcAttr.Setctty = true, true
+		if err := cmd.Start(); err != nil {
+			master.Close()
+			return nil, err
+		}
+
+		p.pty = master
+		p.stdin = master
+		p.outputs = map[protocol.StreamType]io.ReadCloser{protocol.StreamStdout: master}
+		return p, nil
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p.stdin = stdin
+	p.outputs = map[protocol.StreamType]io.ReadCloser{
+		protocol.StreamStdout: stdout,
+		protocol.StreamStderr: stderr,
+	}
+	return p, nil
+}
+
+// handleInput forwards the frames sent by the host to the process. The connection
+// is closed once the process has exited, if it's closed by the host before that,
+// the process is killed.
+func (p *process) handleInput(r io.Reader) {
+	for {
+		t, payload, err := protocol.ReadFrame(r)
+		if err != nil {
+			p.kill()
+			return
+		}
+
+		switch t {
+		case protocol.StreamStdin:
+			if len(payload) > 0 {
+				_, err = p.stdin.Write(payload)
+			} else if p.pty == nil {
+				// Closing the master end would hang up the terminal, so
+				// only the stdin of commands without one is closed
+				err = p.stdin.Close()
+			}
+		case protocol.StreamResize:
+			var size protocol.WindowSize
+			if size, err = protocol.DecodeWindowSize(payload); err == nil && p.pty != nil {
+				err = resizePty(p.pty, size)
+			}
+		default:
+			err = fmt.Errorf("unexpected frame of type %d", t)
+		}
+
+		if err != nil {
+			log.Printf("Failed to handle exec input: %v", err)
+		}
+	}
+}
+
+// wait copies the output of the process to the host until it exits, and returns its exit code
+func (p *process) wait(fw *protocol.FrameWriter) int {
+	var wg sync.WaitGroup
+	for t, output := range p.outputs {
+		wg.Add(1)
+		go func(t protocol.StreamType, output io.Reader) {
+			defer wg.Done()
+			// Reading the master end of a terminal fails with EIO once the command
+			// has exited, so errors just mark the end of the output
+			io.Copy(fw.Stream(t), output)
+		}(t, output)
+	}
+
+	// The output pipes need to be drained before waiting for the command, while
+	// the terminal output is only complete once the command has exited
+	if p.pty == nil {
+		wg.Wait()
+	}
+
+	err := p.cmd.Wait()
+	if p.pty != nil {
+		wg.Wait()
+		p.pty.Close()
+	}
+
+	return exitCode(err)
+}
+
+func (p *process) kill() {
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+}
+
+// defaultPath is the $PATH of chrooted commands without one in their environment
+const defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// lookPathIn returns the path of the executable inside of root, like exec.LookPath
+// does with the $PATH of env. The last component of the path isn't resolved, as
+// absolute symlinks point into the root only once the command is chrooted.
+func lookPathIn(root, file string, env []string) (string, error) {
+	if strings.Contains(file, "/") {
+		return file, nil
+	}
+
+	pathVar := defaultPath
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "PATH=") {
+			pathVar = strings.TrimPrefix(kv, "PATH=")
+		}
+	}
+
+	for _, dir := range filepath.SplitList(pathVar) {
+		executable := path.Join("/", dir, file)
+		fi, err := os.Lstat(path.Join(root, executable))
+		if err != nil {
+			continue
+		}
+
+		if fi.Mode()&os.ModeSymlink != 0 || (fi.Mode().IsRegular() && fi.Mode()&0111 != 0) {
+			return executable, nil
+		}
+	}
+
+	return "", fmt.Errorf("executable %q not found in $PATH (%s) of %s", file, pathVar, root)
+}
+
+// processRegistry holds the running commands started with an ID, for signal requests
+type processRegistry struct {
+	mu        sync.Mutex
+	processes map[string]*process
+}
+
+var processes = &processRegistry{processes: map[string]*process{}}
+
+// reserve reserves the ID for a command about to start, it returns false if it's taken
+func (r *processRegistry) reserve(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.processes[id]; ok {
+		return false
+	}
+
+	r.processes[id] = nil
+	return true
+}
+
+// set registers the started command under its reserved ID
+func (r *processRegistry) set(id string, p *process) {
+	if len(id) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.processes[id] = p
+}
+
+// release frees the ID once the command has exited, or failed to start
+func (r *processRegistry) release(id string) {
+	if len(id) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.processes, id)
+}
+
+// handleSignal sends a signal to a command started with an ID
+func handleSignal(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sig, err := strconv.Atoi(r.URL.Query().Get(protocol.SignalParam))
+	if err != nil || sig <= 0 {
+		http.Error(w, fmt.Sprintf("invalid %s parameter", protocol.SignalParam), http.StatusBadRequest)
+		return
+	}
+
+	id := r.URL.Query().Get(protocol.IDParam)
+	processes.mu.Lock()
+	p := processes.processes[id]
+	processes.mu.Unlock()
+
+	if p == nil || p.cmd.Process == nil {
+		http.Error(w, fmt.Sprintf("no command with ID %q is running", id), http.StatusNotFound)
+		return
+	}
+
+	if err := p.cmd.Process.Signal(syscall.Signal(sig)); err != nil {
+		http.Error(w, fmt.Sprintf("failed to signal the command: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// exitCode returns the exit code of a command like a shell does, commands killed
+// by a signal exit with 128 + the signal number
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal())
+		}
+
+		return exitErr.ExitCode()
+	}
+
+	log.Printf("Failed to wait for the command: %v", err)
+	return 1
+}
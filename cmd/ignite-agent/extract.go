@@ -0,0 +1,120 @@
+package main
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// xattrPrefix prefixes the PAX records holding the extended attributes of a file
+const xattrPrefix = "SCHILY.xattr."
+
+func handleExtract(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	n, err := extract("/", r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to extract the files: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Make sure the files are on the disk before the host records them as loaded
+	unix.Sync()
+	log.Printf("Extracted %d files", n)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// extract writes the files of the tar stream below root, and returns how many it wrote.
+// Files that exist already are kept, as the VM may have written them since it booted.
+func extract(root string, r io.Reader) (n int, err error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		name := path.Join(root, path.Clean("/"+hdr.Name))
+		if _, err := os.Lstat(name); err == nil {
+			continue
+		} else if !os.IsNotExist(err) {
+			return n, err
+		}
+
+		if err := extractFile(root, name, hdr, tr); err != nil {
+			return n, fmt.Errorf("failed to extract %q: %v", hdr.Name, err)
+		}
+		n++
+	}
+}
+
+// extractFile creates the file described by the tar header at name, with the owner, mode,
+// extended attributes and modification time of the header
+func extractFile(root, name string, hdr *tar.Header, r io.Reader) error {
+	if err := os.MkdirAll(path.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	mode := hdr.FileInfo().Mode()
+	perm := mode & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+
+	var err error
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		err = os.Mkdir(name, 0700)
+	case tar.TypeReg:
+		err = writeFile(name, r, 0600)
+	case tar.TypeSymlink:
+		err = os.Symlink(hdr.Linkname, name)
+	case tar.TypeLink:
+		// A hardlink shares the inode of its target, which has its attributes already
+		return os.Link(path.Join(root, path.Clean("/"+hdr.Linkname)), name)
+	case tar.TypeChar:
+		err = unix.Mknod(name, unix.S_IFCHR, int(unix.Mkdev(uint32(hdr.Devmajor), uint32(hdr.Devminor))))
+	case tar.TypeBlock:
+		err = unix.Mknod(name, unix.S_IFBLK, int(unix.Mkdev(uint32(hdr.Devmajor), uint32(hdr.Devminor))))
+	case tar.TypeFifo:
+		err = unix.Mkfifo(name, 0600)
+	default:
+		return fmt.Errorf("unsupported type %q", hdr.Typeflag)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Changing the owner clears the setuid and setgid bits, so it's done first
+	if err := os.Lchown(name, hdr.Uid, hdr.Gid); err != nil {
+		return err
+	}
+
+	for key, value := range hdr.PAXRecords {
+		if strings.HasPrefix(key, xattrPrefix) {
+			if err := unix.Lsetxattr(name, strings.TrimPrefix(key, xattrPrefix), []byte(value), 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	if hdr.Typeflag == tar.TypeSymlink {
+		return nil
+	}
+
+	if err := os.Chmod(name, perm); err != nil {
+		return err
+	}
+
+	return os.Chtimes(name, hdr.ModTime, hdr.ModTime)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+)
+
+// shutdownDelay gives the response to a shutdown request time to reach the host
+const shutdownDelay = 500 * time.Millisecond
+
+func handleFile(w http.ResponseWriter, r *http.Request) {
+	filePath := r.URL.Query().Get(protocol.PathParam)
+	if !path.IsAbs(filePath) {
+		http.Error(w, fmt.Sprintf("the %s parameter needs to be an absolute path", protocol.PathParam), http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		f, err := os.Open(filePath)
+		if err != nil {
+			fileError(w, err)
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if _, err := io.Copy(w, f); err != nil {
+			log.Printf("Failed to send %q: %v", filePath, err)
+		}
+
+	case http.MethodPut:
+		mode, err := strconv.ParseUint(r.URL.Query().Get(protocol.ModeParam), 8, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid %s parameter: %v", protocol.ModeParam, err), http.StatusBadRequest)
+			return
+		}
+
+		if err := writeFile(filePath, r.Body, os.FileMode(mode)); err != nil {
+			fileError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeFile replaces the file at filePath with the contents of r. The contents
+// are written to a temporary file first, so a failed write leaves the file intact.
+func writeFile(filePath string, r io.Reader, mode os.FileMode) (err error) {
+	f, err := ioutil.TempFile(path.Dir(filePath), "."+path.Base(filePath))
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	if _, err = io.Copy(f, r); err != nil {
+		return
+	}
+
+	if err = f.Chmod(mode); err != nil {
+		return
+	}
+
+	if err = f.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(f.Name(), filePath)
+}
+
+func fileError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if os.IsNotExist(err) {
+		status = http.StatusNotFound
+	} else if os.IsPermission(err) {
+		status = http.StatusForbidden
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics, err := readMetrics()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read metrics: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, metrics)
+}
+
+// readMetrics collects the metrics of the VM from procfs and the root filesystem
+func readMetrics() (*protocol.Metrics, error) {
+	metrics := &protocol.Metrics{}
+
+	uptime, err := ioutil.ReadFile("/proc/uptime")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fmt.Sscan(string(uptime), &metrics.UptimeSeconds); err != nil {
+		return nil, fmt.Errorf("failed to parse /proc/uptime: %v", err)
+	}
+
+	loadavg, err := ioutil.ReadFile("/proc/loadavg")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fmt.Sscan(string(loadavg), &metrics.Load[0], &metrics.Load[1], &metrics.Load[2]); err != nil {
+		return nil, fmt.Errorf("failed to parse /proc/loadavg: %v", err)
+	}
+
+	meminfo, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+	defer meminfo.Close()
+
+	// The lines have the "MemTotal:       500000 kB" format
+	scanner := bufio.NewScanner(meminfo)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		var field *uint64
+		switch fields[0] {
+		case "MemTotal:":
+			field = &metrics.MemoryTotal
+		case "MemAvailable:":
+			field = &metrics.MemoryAvailable
+		default:
+			continue
+		}
+
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse /proc/meminfo: %v", err)
+		}
+		*field = kb * 1024
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var stat unix.Statfs_t
+	if err := unix.Statfs("/", &stat); err != nil {
+		return nil, err
+	}
+
+	metrics.DiskTotal = stat.Blocks * uint64(stat.Bsize)
+	metrics.DiskAvailable = stat.Bavail * uint64(stat.Bsize)
+
+	return metrics, nil
+}
+
+func handleShutdown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	go shutdown()
+}
+
+// shutdown reboots the VM through its init system, so its services are stopped
+// gracefully. Firecracker doesn't support powering VMs off, the VMM exits when
+// the guest reboots instead, which is also how "ignite stop" shuts VMs down.
+func shutdown() {
+	time.Sleep(shutdownDelay)
+	log.Print("Shutting down the VM")
+
+	err := exec.Command("reboot").Run()
+	if err == nil {
+		return
+	}
+
+	log.Printf("Failed to reboot using the init system, rebooting directly: %v", err)
+	syscall.Sync()
+	if err := unix.Reboot(unix.LINUX_REBOOT_CMD_RESTART); err != nil {
+		log.Printf("Failed to reboot: %v", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+)
+
+const (
+	hostnameFile = "/etc/hostname"
+	hostsFile    = "/etc/hosts"
+)
+
+func handleIdentity(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		identity, _, err := readIdentity()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read the identity: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, identity)
+
+	case http.MethodPut:
+		identity := &protocol.Identity{}
+		if err := json.NewDecoder(r.Body).Decode(identity); err != nil {
+			http.Error(w, fmt.Sprintf("invalid identity: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if err := setIdentity(identity); err != nil {
+			http.Error(w, fmt.Sprintf("failed to set the identity: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// defaultRoute is the interface and gateway of the IPv4 default route
+type defaultRoute struct {
+	iface   *net.Interface
+	gateway net.IP
+	// address is the first IPv4 address of the interface
+	address *net.IPNet
+}
+
+// readIdentity returns the identity of the VM, and its default route if it has one
+func readIdentity() (*protocol.Identity, *defaultRoute, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	identity := &protocol.Identity{Hostname: hostname}
+	route, err := readDefaultRoute()
+	if err != nil || route == nil {
+		return identity, nil, err
+	}
+
+	identity.MACAddress = route.iface.HardwareAddr.String()
+	if route.address != nil {
+		identity.Address = route.address.IP.String()
+	}
+
+	return identity, route, nil
+}
+
+// readDefaultRoute looks up the IPv4 default route in /proc/net/route, which has the
+// "Iface Destination Gateway ..." columns with the addresses in little-endian hex
+func readDefaultRoute() (*defaultRoute, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+
+		gateway, err := hex.DecodeString(fields[2])
+		if err != nil || len(gateway) != net.IPv4len {
+			return nil, fmt.Errorf("failed to parse /proc/net/route: invalid gateway %q", fields[2])
+		}
+
+		iface, err := net.InterfaceByName(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		route := &defaultRoute{iface: iface, gateway: make(net.IP, net.IPv4len)}
+		binary.BigEndian.PutUint32(route.gateway, binary.LittleEndian.Uint32(gateway))
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				route.address = ipNet
+				break
+			}
+		}
+
+		return route, nil
+	}
+
+	return nil, scanner.Err()
+}
+
+// setIdentity changes the hostname and address of the VM to the given ones, and
+// replaces the previous ones in /etc/hosts
+func setIdentity(identity *protocol.Identity) error {
+	current, route, err := readIdentity()
+	if err != nil {
+		return err
+	}
+
+	replacements := make(map[string]string)
+	if len(identity.Hostname) > 0 && identity.Hostname != current.Hostname {
+		if err := unix.Sethostname([]byte(identity.Hostname)); err != nil {
+			return err
+		}
+
+		if err := writeFile(hostnameFile, strings.NewReader(identity.Hostname+"\n"), 0644); err != nil {
+			return err
+		}
+
+		replacements[current.Hostname] = identity.Hostname
+		log.Printf("Changed the hostname from %q to %q", current.Hostname, identity.Hostname)
+	}
+
+	if len(identity.Address) > 0 && identity.Address != current.Address {
+		if route == nil || route.address == nil {
+			return fmt.Errorf("the VM has no default route with an IPv4 address to change")
+		}
+
+		if err := changeAddress(route, identity.Address, identity.Gateway); err != nil {
+			return err
+		}
+
+		replacements[current.Address] = identity.Address
+		log.Printf("Changed the address of %s from %s to %s", route.iface.Name, current.Address, identity.Address)
+	}
+
+	if len(replacements) == 0 {
+		return nil
+	}
+
+	return replaceHosts(replacements)
+}
+
+// changeAddress replaces the address of the interface of the default route, keeping
+// its prefix length. Removing the previous address removes the default route, so it's
+// added back, through the given gateway if set.
+func changeAddress(route *defaultRoute, address, gateway string) error {
+	ip := net.ParseIP(address)
+	if ip == nil || ip.To4() == nil {
+		return fmt.Errorf("invalid IPv4 address %q", address)
+	}
+
+	gw := route.gateway
+	if len(gateway) > 0 {
+		if gw = net.ParseIP(gateway); gw == nil || gw.To4() == nil {
+			return fmt.Errorf("invalid IPv4 gateway %q", gateway)
+		}
+	}
+
+	ones, _ := route.address.Mask.Size()
+	for _, args := range [][]string{
+		{"addr", "add", fmt.Sprintf("%s/%d", ip, ones), "dev", route.iface.Name},
+		{"addr", "del", fmt.Sprintf("%s/%d", route.address.IP, ones), "dev", route.iface.Name},
+		{"route", "replace", "default", "via", gw.String(), "dev", route.iface.Name},
+	} {
+		if out, err := exec.Command("ip", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("ip %s failed: %v: %s", strings.Join(args, " "), err, bytes.TrimSpace(out))
+		}
+	}
+
+	return nil
+}
+
+// replaceHosts replaces the fields of /etc/hosts that are keys of replacements by their
+// values, so only whole addresses and hostnames are replaced
+func replaceHosts(replacements map[string]string) error {
+	content, err := ioutil.ReadFile(hostsFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		changed := false
+		for j, field := range fields {
+			if replacement, ok := replacements[field]; ok {
+				fields[j] = replacement
+				changed = true
+			}
+		}
+
+		if changed {
+			lines[i] = strings.Join(fields, "\t")
+		}
+	}
+
+	return writeFile(hostsFile, strings.NewReader(strings.Join(lines, "\n")), 0644)
+}
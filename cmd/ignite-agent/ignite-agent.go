@@ -0,0 +1,37 @@
+// ignite-agent is the guest agent of ignite. It runs inside of VMs based on images
+// imported with "ignite image import --agent", and serves the API defined in
+// pkg/agent/protocol to the host over virtio-vsock. This gives the host access to
+// VMs based on images without an SSH server.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+)
+
+func main() {
+	port := flag.Uint("port", protocol.Port, "The vsock port to serve the agent API on")
+	flag.Parse()
+
+	l, err := listenVsock(uint32(*port))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(protocol.ExecPath, handleExec)
+	mux.HandleFunc(protocol.FilePath, handleFile)
+	mux.HandleFunc(protocol.MetricsPath, handleMetrics)
+	mux.HandleFunc(protocol.ShutdownPath, handleShutdown)
+	mux.HandleFunc(protocol.SecretsPath, handleSecrets)
+	mux.HandleFunc(protocol.ExtractPath, handleExtract)
+	mux.HandleFunc(protocol.IdentityPath, handleIdentity)
+	mux.HandleFunc(protocol.SignalPath, handleSignal)
+	mux.HandleFunc(protocol.ArchivePath, handleArchive)
+
+	log.Printf("Serving the ignite guest agent on vsock port %d", *port)
+	log.Fatal(http.Serve(l, mux))
+}
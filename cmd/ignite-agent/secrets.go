@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+)
+
+func handleSecrets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var secrets []protocol.Secret
+	if err := json.NewDecoder(r.Body).Decode(&secrets); err != nil {
+		http.Error(w, fmt.Sprintf("invalid secrets: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, secret := range secrets {
+		if len(secret.Name) == 0 || strings.Contains(secret.Name, "/") || strings.HasPrefix(secret.Name, ".") {
+			http.Error(w, fmt.Sprintf("invalid secret name %q", secret.Name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := writeSecrets(secrets); err != nil {
+		http.Error(w, fmt.Sprintf("failed to write the secrets: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeSecrets replaces the secrets in SecretsDir, mounting a tmpfs there first
+// if there's none, so the secrets never reach the disk
+func writeSecrets(secrets []protocol.Secret) error {
+	if err := mountSecretsDir(); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		names[secret.Name] = true
+		filePath := path.Join(protocol.SecretsDir, secret.Name)
+		if err := writeFile(filePath, bytes.NewReader(secret.Data), os.FileMode(secret.Mode)); err != nil {
+			return err
+		}
+	}
+
+	// Remove the secrets that were dropped since the last delivery
+	entries, err := ioutil.ReadDir(protocol.SecretsDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !names[entry.Name()] {
+			if err := os.Remove(path.Join(protocol.SecretsDir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// mountSecretsDir mounts a tmpfs only root can access at SecretsDir, if it's not mounted yet
+func mountSecretsDir() error {
+	if err := os.MkdirAll(protocol.SecretsDir, 0700); err != nil {
+		return err
+	}
+
+	// /run is usually a tmpfs too, SecretsDir gets its own one only root can access.
+	// A mount point is on another device than its parent.
+	var dir, parent unix.Stat_t
+	if err := unix.Stat(protocol.SecretsDir, &dir); err != nil {
+		return err
+	}
+	if err := unix.Stat(path.Dir(protocol.SecretsDir), &parent); err != nil {
+		return err
+	}
+
+	if dir.Dev != parent.Dev {
+		return nil
+	}
+
+	return unix.Mount("tmpfs", protocol.SecretsDir, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, "mode=0700")
+}
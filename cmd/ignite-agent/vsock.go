@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// vsockAddr is the address of a virtio-vsock socket
+type vsockAddr struct {
+	cid  uint32
+	port uint32
+}
+
+var _ net.Addr = &vsockAddr{}
+
+func (a *vsockAddr) Network() string { return "vsock" }
+func (a *vsockAddr) String() string  { return fmt.Sprintf("%d:%d", a.cid, a.port) }
+
+// vsockListener accepts connections from the host on a vsock port. The net
+// package doesn't support the AF_VSOCK family, so the socket is handled manually.
+type vsockListener struct {
+	fd   int
+	addr *vsockAddr
+}
+
+var _ net.Listener = &vsockListener{}
+
+// listenVsock listens for connections on the given vsock port of the VM
+func listenVsock(port uint32) (net.Listener, error) {
+	fd, err := unix.Socket(unix.AF_VSOCK, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vsock socket: %v", err)
+	}
+
+	if err := unix.Bind(fd, &unix.SockaddrVM{CID: unix.VMADDR_CID_ANY, Port: port}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to bind vsock port %d: %v", port, err)
+	}
+
+	if err := unix.Listen(fd, unix.SOMAXCONN); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to listen on vsock port %d: %v", port, err)
+	}
+
+	return &vsockListener{fd, &vsockAddr{unix.VMADDR_CID_ANY, port}}, nil
+}
+
+func (l *vsockListener) Accept() (net.Conn, error) {
+	fd, sa, err := unix.Accept4(l.fd, unix.SOCK_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+
+	// A non-blocking file is registered with the runtime poller, which
+	// supports the deadlines the HTTP server sets on its connections
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	remote := &vsockAddr{}
+	if vmAddr, ok := sa.(*unix.SockaddrVM); ok {
+		remote.cid, remote.port = vmAddr.CID, vmAddr.Port
+	}
+
+	return &vsockConn{os.NewFile(uintptr(fd), "vsock"), l.addr, remote}, nil
+}
+
+func (l *vsockListener) Close() error {
+	return unix.Close(l.fd)
+}
+
+func (l *vsockListener) Addr() net.Addr {
+	return l.addr
+}
+
+// vsockConn is a connection accepted by vsockListener
+type vsockConn struct {
+	*os.File
+	local  *vsockAddr
+	remote *vsockAddr
+}
+
+var _ net.Conn = &vsockConn{}
+
+func (c *vsockConn) LocalAddr() net.Addr  { return c.local }
+func (c *vsockConn) RemoteAddr() net.Addr { return c.remote }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -10,10 +11,10 @@ import (
 	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/container"
-	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/operations"
 	"github.com/weaveworks/ignite/pkg/prometheus"
 	"github.com/weaveworks/ignite/pkg/util"
-	patchutil "github.com/weaveworks/libgitops/pkg/util/patch"
+	"github.com/weaveworks/libgitops/pkg/runtime"
 )
 
 func main() {
@@ -43,31 +44,66 @@ func StartVM(vm *api.VM) (err error) {
 	// Setup networking inside of the container, return the available interfaces
 	fcIfaces, dhcpIfaces, err := container.SetupContainerNetworking(vm)
 	if err != nil {
-		return fmt.Errorf("network setup failed: %v", err)
+		err = fmt.Errorf("network setup failed: %v", err)
+		patchNetworkFailed(vm, err)
+		return
 	}
 
 	// Serve DHCP requests for those interfaces
 	// This function returns the available IP addresses that are being
 	// served over DHCP now
 	if err = container.StartDHCPServers(vm, dhcpIfaces); err != nil {
+		patchNetworkFailed(vm, err)
 		return
 	}
 
+	// Resolve the kernel command line now that the addresses of the VM are known
+	cmdLine, err := container.KernelCmdLine(vm, dhcpIfaces)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the kernel command line: %v", err)
+	}
+
 	// Serve metrics over an unix socket in the VM's own directory
 	metricsSocket := path.Join(vm.ObjectPath(), constants.PROMETHEUS_SOCKET)
 	serveMetrics(metricsSocket)
 
-	// Patches the VM object to set state to stopped, and clear IP addresses
-	defer util.DeferErr(&err, func() error { return patchStopped(vm) })
+	// Watch the overlay usage against the VM's overlay size limit
+	stopOverlayMonitor := monitorOverlay(vm)
+	defer close(stopOverlayMonitor)
 
-	// Remove the snapshot overlay post-run, which also removes the detached backing loop devices
-	defer util.DeferErr(&err, func() error { return dmlegacy.DeactivateSnapshot(vm) })
+	// Patches the VM object to set state to stopped, clear IP addresses and record how Firecracker exited
+	exitCode := 1
+	oomKilled := false
+	defer util.DeferErr(&err, func() error { return patchStopped(vm, exitCode, oomKilled) })
+
+	// Watch if the SSH server within the VM accepts connections, stopped before the VM is patched as stopped
+	stopSSHMonitor := monitorSSH(vm, dhcpIfaces)
+	defer close(stopSSHMonitor)
+
+	// Remove the snapshot overlay post-run, for dmlegacy this also removes the detached backing loop devices
+	defer util.DeferErr(&err, func() error { return operations.DeactivateSnapshot(vm) })
 
 	// Remove the Prometheus socket post-run
 	defer util.DeferErr(&err, func() error { return os.Remove(metricsSocket) })
 
-	// Execute Firecracker
-	if err = container.ExecuteFirecracker(vm, fcIfaces); err != nil {
+	// Look up the block device to boot from, it's been activated by ignite before starting the container
+	drivePath, err := operations.SnapshotDevice(vm)
+	if err != nil {
+		return
+	}
+
+	// Execute Firecracker, or QEMU if the VM is emulated, boots a disk image with UEFI firmware,
+	// or has host PCI devices or nested virtualization. The VMM ran out of memory if the
+	// container has OOM kills it didn't have before.
+	oomKills := container.OOMKills()
+	if emulate || vm.QEMU() {
+		err = container.ExecuteQEMU(vm, fcIfaces, cmdLine, drivePath, consoleLog, emulate)
+	} else {
+		err = container.ExecuteFirecracker(vm, fcIfaces, cmdLine, drivePath, consoleLog)
+	}
+	exitCode = firecrackerExitCode(err)
+	oomKilled = exitCode != 0 && container.OOMKills() > oomKills
+	if err != nil {
 		return fmt.Errorf("runtime error for VM %q: %v", vm.GetUID(), err)
 	}
 
@@ -84,16 +120,72 @@ func serveMetrics(metricsSocket string) {
 	}()
 }
 
+// firecrackerExitCode returns the exit code of the Firecracker process, 1 if it failed otherwise
+func firecrackerExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *container.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode
+	}
+
+	return 1
+}
+
 // TODO: Get rid of this with the daemon architecture
-func patchStopped(vm *api.VM) error {
+func patchStopped(vm *api.VM, exitCode int, oomKilled bool) error {
 	/*
 		Perform a static patch, setting the following:
 		vm.status.running = false
+		vm.status.paused = false
 		vm.status.ipAddresses = nil
 		vm.status.runtime = nil
 		vm.status.startTime = nil
+		vm.status.lastExit = <how Firecracker exited>
+		vm.status.conditions = <NetworkReady, Booted and SSHReachable false, Degraded if crashed>
 	*/
 
-	patch := []byte(`{"status":{"running":false,"network":null,"runtime":null,"startTime":null}}`)
-	return patchutil.NewPatcher(scheme.Serializer).ApplyOnFile(constants.IGNITE_SPAWN_VM_FILE_PATH, patch, vm.GroupVersionKind())
+	lastExit := &api.VMExitStatus{
+		Time:      runtime.Timestamp(),
+		ExitCode:  exitCode,
+		Requested: operations.ConsumeStopRequested(vm),
+	}
+	lastExit.Reason = lastExit.ExitReason()
+	if operations.ConsumeUnhealthy(vm) && !lastExit.Requested {
+		lastExit.Reason = api.ExitReasonUnhealthy
+	} else if oomKilled && !lastExit.Requested {
+		lastExit.Reason = api.ExitReasonOOMKilled
+	}
+
+	return patchStatus(vm, map[string]interface{}{
+		"running":   false,
+		"paused":    false,
+		"network":   nil,
+		"runtime":   nil,
+		"startTime": nil,
+		"lastExit":  lastExit,
+	}, func(current *api.VM) {
+		exited := fmt.Sprintf("Firecracker exited with code %d", exitCode)
+		current.SetCondition(api.VMNetworkReady, api.ConditionFalse, "Stopped", "")
+		current.SetCondition(api.VMBooted, api.ConditionFalse, "Exited", exited)
+		current.SetCondition(api.VMSSHReachable, api.ConditionFalse, "Stopped", "")
+		if lastExit.Reason == api.ExitReasonOOMKilled {
+			current.SetCondition(api.VMDegraded, api.ConditionTrue, string(api.ExitReasonOOMKilled), "Firecracker ran out of memory")
+		} else if exitCode != 0 && !lastExit.Requested {
+			current.SetCondition(api.VMDegraded, api.ConditionTrue, "Crashed", exited)
+		} else {
+			current.SetCondition(api.VMDegraded, api.ConditionFalse, "Stopped", "")
+		}
+	})
+}
+
+// patchNetworkFailed records the failed network setup in the NetworkReady condition
+func patchNetworkFailed(vm *api.VM, err error) {
+	if patchErr := patchConditions(vm, func(current *api.VM) {
+		current.SetCondition(api.VMNetworkReady, api.ConditionFalse, "NetworkSetupFailed", err.Error())
+	}); patchErr != nil {
+		log.Errorf("Failed to update the conditions of VM %q: %v", vm.GetUID(), patchErr)
+	}
 }
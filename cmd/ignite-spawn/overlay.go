@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+)
+
+// monitorOverlay periodically checks the host disk usage of the VM's overlay
+// while the VM is running. When the usage reaches the warning threshold of the
+// VM's overlay size limit, a warning is logged and the VM status is updated.
+// Close the returned channel to stop monitoring.
+func monitorOverlay(vm *api.VM) chan struct{} {
+	stop := make(chan struct{})
+	if vm.Spec.OverlaySizeLimit == meta.EmptySize {
+		return stop // Nothing to enforce
+	}
+
+	go func() {
+		ticker := time.NewTicker(constants.OVERLAY_USAGE_CHECK_INTERVAL)
+		defer ticker.Stop()
+
+		nearLimit := false
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			usage, err := operations.OverlayUsage(vm)
+			if errors.Is(err, snapshotter.ErrUsageUnknown) {
+				log.Warnf("The overlay usage of VM %q can't be monitored with the %q snapshotter", vm.GetUID(), vm.Status.Snapshotter)
+				return
+			} else if err != nil {
+				log.Errorf("Failed to get overlay usage for VM %q: %v", vm.GetUID(), err)
+				continue
+			}
+
+			// Only record transitions, the usage itself is not worth a write every interval
+			if near := dmlegacy.OverlayNearLimit(vm, usage); near != nearLimit {
+				nearLimit = near
+				if nearLimit {
+					log.Warnf("VM %q overlay usage %s is approaching its limit of %s", vm.GetUID(), usage, vm.Spec.OverlaySizeLimit)
+				}
+
+				if err := patchOverlayStatus(vm, &api.OverlayStatus{Usage: usage, NearLimit: nearLimit}); err != nil {
+					log.Errorf("Failed to update overlay status for VM %q: %v", vm.GetUID(), err)
+				}
+			}
+		}
+	}()
+
+	return stop
+}
+
+// TODO: Get rid of this with the daemon architecture
+func patchOverlayStatus(vm *api.VM, status *api.OverlayStatus) error {
+	return patchStatus(vm, map[string]interface{}{"overlay": status}, func(current *api.VM) {
+		if status.NearLimit {
+			current.SetCondition(api.VMDegraded, api.ConditionTrue, "OverlayNearLimit",
+				fmt.Sprintf("overlay usage %s is approaching its limit of %s", status.Usage, vm.Spec.OverlaySizeLimit))
+		} else if c := current.Condition(api.VMDegraded); c != nil && c.Reason == "OverlayNearLimit" {
+			current.SetCondition(api.VMDegraded, api.ConditionFalse, "AsExpected", "")
+		}
+	})
+}
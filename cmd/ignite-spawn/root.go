@@ -6,12 +6,22 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	"github.com/weaveworks/ignite/pkg/logs"
 	logflag "github.com/weaveworks/ignite/pkg/logs/flag"
 	"github.com/weaveworks/ignite/pkg/util"
 )
 
-var logLevel = logrus.InfoLevel
+var (
+	logLevel  = logrus.InfoLevel
+	logFormat = logs.FormatText
+	// consoleLog configures the recording of the console output, ignite sets it from its configuration
+	consoleLog        api.ConsoleLogConfiguration
+	consoleLogMaxSize uint64
+	// emulate runs the VM with QEMU's emulation instead of Firecracker, ignite sets it if KVM is unavailable
+	emulate bool
+)
 
 // RunIgniteSpawn runs the root command for ignite-spawn
 func RunIgniteSpawn() {
@@ -20,8 +30,11 @@ func RunIgniteSpawn() {
 	}
 
 	addGlobalFlags(fs)
+	addConsoleLogFlags(fs)
 	util.GenericCheckErr(fs.Parse(os.Args[1:]))
 	logs.Logger.SetLevel(logLevel)
+	util.GenericCheckErr(logs.SetFormat(logFormat))
+	consoleLog.MaxSize = meta.NewSizeFromBytes(consoleLogMaxSize)
 
 	if len(fs.Args()) != 1 {
 		usage()
@@ -38,10 +51,19 @@ func RunIgniteSpawn() {
 }
 
 func usage() {
-	util.GenericCheckErr(fmt.Errorf("usage: ignite-spawn [--log-level <level>] <vm>"))
+	util.GenericCheckErr(fmt.Errorf("usage: ignite-spawn [--log-level <level>] [--log-format <format>] [--console-log-<option> <value>] [--emulate] <vm>"))
 }
 
 func addGlobalFlags(fs *pflag.FlagSet) {
 	// TODO: Add a version flag
 	logflag.LogLevelFlagVar(fs, &logLevel)
+	logflag.LogFormatFlagVar(fs, &logFormat)
+	fs.BoolVar(&emulate, "emulate", false, "Emulate the VM with QEMU instead of running it with Firecracker, which needs KVM")
+}
+
+func addConsoleLogFlags(fs *pflag.FlagSet) {
+	fs.Uint64Var(&consoleLogMaxSize, "console-log-max-size", 0, "Size in bytes at which the console log is rotated")
+	fs.DurationVar(&consoleLog.MaxAge.Duration, "console-log-max-age", 0, "Age of the oldest line at which the console log is rotated")
+	fs.Uint64Var(&consoleLog.MaxFiles, "console-log-max-files", 0, "Number of rotated console logs to keep")
+	fs.BoolVar(&consoleLog.Compress, "console-log-compress", false, "Compress the rotated console logs")
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/container"
+)
+
+// sshDialTimeout is how long a check waits for the SSH server within the VM to accept the connection
+const sshDialTimeout = 2 * time.Second
+
+// monitorSSH periodically checks if the SSH server within the VM accepts connections
+// on the first address served over DHCP, and records it in the SSHReachable condition.
+// Close the returned channel to stop monitoring.
+func monitorSSH(vm *api.VM, dhcpIfaces []container.DHCPInterface) chan struct{} {
+	stop := make(chan struct{})
+	if len(dhcpIfaces) == 0 {
+		return stop // No address to check
+	}
+
+	addr := net.JoinHostPort(dhcpIfaces[0].VMIPNet.IP.String(), "22")
+	go func() {
+		ticker := time.NewTicker(constants.SSH_CHECK_INTERVAL)
+		defer ticker.Stop()
+
+		var reachable *bool
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			conn, dialErr := net.DialTimeout("tcp", addr, sshDialTimeout)
+			if conn != nil {
+				conn.Close()
+			}
+
+			// Only record transitions, the VM is patched as stopped once the monitor is stopped
+			ok := dialErr == nil
+			if reachable != nil && *reachable == ok {
+				continue
+			}
+
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			reachable = &ok
+			if err := patchConditions(vm, func(current *api.VM) {
+				if ok {
+					current.SetCondition(api.VMSSHReachable, api.ConditionTrue, "Listening", "")
+				} else {
+					current.SetCondition(api.VMSSHReachable, api.ConditionFalse, "NotListening", dialErr.Error())
+				}
+			}); err != nil {
+				log.Errorf("Failed to update the SSHReachable condition of VM %q: %v", vm.GetUID(), err)
+			}
+		}
+	}()
+
+	return stop
+}
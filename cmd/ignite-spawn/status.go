@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"sync"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/constants"
+	patchutil "github.com/weaveworks/libgitops/pkg/util/patch"
+)
+
+// statusMu serializes the patches of the VM status, the monitors patch it concurrently
+var statusMu sync.Mutex
+
+// patchStatus patches the given fields of the VM status, and the conditions as changed by
+// setConditions. The conditions are read from the stored VM first, as ignite updates them too.
+// TODO: Get rid of this with the daemon architecture
+func patchStatus(vm *api.VM, fields map[string]interface{}, setConditions func(current *api.VM)) error {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	status := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		status[k] = v
+	}
+
+	if setConditions != nil {
+		current, err := decodeVM(vm.GetUID().String())
+		if err != nil {
+			return err
+		}
+
+		setConditions(current)
+		status["conditions"] = current.Status.Conditions
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return err
+	}
+
+	return patchutil.NewPatcher(scheme.Serializer).ApplyOnFile(constants.IGNITE_SPAWN_VM_FILE_PATH, patch, vm.GroupVersionKind())
+}
+
+// patchConditions patches the conditions of the VM as changed by setConditions
+func patchConditions(vm *api.VM, setConditions func(current *api.VM)) error {
+	return patchStatus(vm, nil, setConditions)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+	networkflag "github.com/weaveworks/ignite/pkg/network/flag"
+	"github.com/weaveworks/ignite/pkg/providers"
+	runtimeflag "github.com/weaveworks/ignite/pkg/runtime/flag"
+)
+
+// NewCmdApply applies manifests of Images, Kernels and VMs
+func NewCmdApply(out io.Writer) *cobra.Command {
+	af := &run.ApplyFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "apply -f <file|directory|->",
+		Short: "Create or update Images, Kernels and VMs from manifests",
+		Long: dedent.Dedent(`
+			Bring the Images, Kernels and VMs described by the given manifests to the
+			described state. A manifest is a file with one or more "---" separated
+			YAML or JSON documents, the files of a directory are read in lexical order,
+			and "-" reads from stdin. Documents encrypted with SOPS are decrypted with
+			the sops binary, which finds the keys, e.g. in $SOPS_AGE_KEY_FILE.
+
+			Images and Kernels that don't exist are imported, and VMs that don't exist
+			are created. The labels and annotations of existing objects are updated, as
+			is the spec of existing VMs, which need to be stopped for it. VMs are matched
+			by their UID if given, and by their name otherwise, so every VM needs a name.
+			VMs that set "status.running: true" are started. Objects are never stopped
+			or removed, and applying the same manifests again doesn't change anything.
+
+			All manifests are checked before any change is made, and the changes are
+			printed for every object. With the dry-run flag (--dry-run) they're only
+			printed, and not made.
+
+			Example usage:
+				$ ignite apply -f my-vms.yaml
+				$ ignite apply -f manifests/ --dry-run
+				$ cat my-vms.yaml | ignite apply -f -
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				ao, err := af.NewApplyOptions()
+				if err != nil {
+					return err
+				}
+
+				return run.Apply(ao, cmd.Flags())
+			}())
+		},
+	}
+
+	addApplyFlags(cmd.Flags(), af)
+	runtimeflag.RuntimeVar(cmd.Flags(), &providers.RuntimeName)
+	networkflag.NetworkPluginVar(cmd.Flags(), &providers.NetworkPluginName)
+	return cmd
+}
+
+func addApplyFlags(fs *pflag.FlagSet, af *run.ApplyFlags) {
+	fs.StringSliceVarP(&af.Filenames, "filename", "f", nil, "Manifest file or directory to apply, - reads from stdin. Can be given multiple times")
+	fs.BoolVar(&af.DryRun, "dry-run", false, "Print the changes without making them")
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lithammer/dedent"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+	"github.com/weaveworks/ignite/pkg/audit"
+	"github.com/weaveworks/ignite/pkg/logs"
+)
+
+// NewCmdAudit queries the audit log
+func NewCmdAudit(out io.Writer) *cobra.Command {
+	af := &run.AuditFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query the audit log of the mutating operations",
+		Long: dedent.Dedent(`
+			Print the entries of the audit log, oldest first. Every ignite command that
+			changes a VM, image or kernel, like create, start, stop, rm or import, every
+			such request to the ignited API and every change applied by the reconciliation
+			of ignited is recorded with the user that requested it, its arguments, when it
+			started and finished and whether it succeeded.
+
+			The audit log is appended to /var/lib/firecracker/audit.log, or the file in the
+			audit section of the ignite configuration. If the configuration sends it to
+			syslog instead, query it there.
+
+			Example usage:
+				$ ignite audit --since 24h --actor alice
+				$ ignite audit --operation "vm rm" --failed -o json
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(run.Audit(af))
+		},
+	}
+
+	addAuditFlags(cmd.Flags(), af)
+	return cmd
+}
+
+func addAuditFlags(fs *pflag.FlagSet, af *run.AuditFlags) {
+	fs.StringVar(&af.File, "file", af.File, "Audit log file to read, the one of the ignite configuration by default")
+	fs.DurationVar(&af.Since, "since", af.Since, "Only show the operations started within the duration, e.g. 24h")
+	fs.StringVar(&af.Actor, "actor", af.Actor, "Only show the operations requested by the user")
+	fs.StringVar(&af.Operation, "operation", af.Operation, "Only show the operations containing the string, e.g. \"start\" or \"/v1/vms\"")
+	fs.BoolVar(&af.Failed, "failed", af.Failed, "Only show the operations that failed")
+	fs.StringVarP(&af.Output, "output", "o", af.Output, "Output format: table|json")
+}
+
+// unauditedCommands only read, so they aren't recorded in the audit log. The reconciliation
+// of "ignite gitops" records its own changes.
+var unauditedCommands = map[string]bool{
+	"audit":   true,
+	"events":  true,
+	"gitops":  true,
+	"list":    true,
+	"logs":    true,
+	"ls":      true,
+	"metrics": true,
+	"sbom":    true,
+	"stats":   true,
+	"wait":    true,
+}
+
+// commandAudit is the audit entry of the running command, if it's audited
+var commandAudit *audit.Entry
+
+// fatalMessage is the error the command failed with
+var fatalMessage string
+
+// fatalHook records the error of the command from the log line it's fatally logged with
+type fatalHook struct{}
+
+func (fatalHook) Levels() []log.Level {
+	return []log.Level{log.FatalLevel, log.PanicLevel}
+}
+
+func (fatalHook) Fire(entry *log.Entry) error {
+	fatalMessage = entry.Message
+	return nil
+}
+
+// beginAudit starts the audit entry of the command if it changes anything. The entry is
+// recorded when the command finishes, or when it fails and exits.
+func beginAudit(cmd *cobra.Command) {
+	if isNonRootCommand(cmd.Name(), cmd.Parent().Name()) || unauditedCommands[cmd.Name()] || !cmd.Runnable() {
+		return
+	}
+
+	commandAudit = audit.Begin(audit.SourceCLI, audit.LocalActor(), strings.TrimPrefix(cmd.CommandPath(), "ignite "), os.Args[1:]...)
+	logs.Logger.AddHook(fatalHook{})
+	log.RegisterExitHandler(func() {
+		finishAudit(errors.New(fatalMessage))
+	})
+}
+
+// finishAudit records the audit entry of the command with its result
+func finishAudit(err error) {
+	if commandAudit == nil {
+		return
+	}
+
+	commandAudit.Finish(err)
+	commandAudit = nil
+}
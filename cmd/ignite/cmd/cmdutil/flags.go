@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/pflag"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/constants"
 )
 
@@ -36,6 +37,48 @@ func AddSSHFlags(fs *pflag.FlagSet, identityFile *string, timeout *uint32) {
 	fs.Uint32Var(timeout, "timeout", constants.SSH_DEFAULT_TIMEOUT_SECONDS, "Timeout waiting for connection in seconds")
 }
 
+func AddOutputFlag(fs *pflag.FlagSet, output *string) {
+	fs.StringVarP(output, "output", "o", "table", "Output the list in the specified format: table, json, yaml, go-template=<template> or custom-columns=<header>:<field path>,...")
+}
+
+// AddResultOutputFlag adds the flag printing the result of a command for the tools wrapping ignite
+func AddResultOutputFlag(fs *pflag.FlagSet, output *string) {
+	fs.StringVarP(output, "output", "o", "", "Print the result in the specified format: json. The log lines are written to stderr instead")
+}
+
 func AddRegistryConfigDirFlag(fs *pflag.FlagSet, dir *string) {
 	fs.StringVar(dir, "registry-config-dir", "", "Directory containing the registry configuration (default ~/.docker/)")
 }
+
+func AddImageScanFlags(fs *pflag.FlagSet, cfg *api.ImageScanConfiguration) {
+	fs.StringVar(&cfg.Scanner, "scanner", cfg.Scanner, "Scan the image for vulnerabilities with the scanner, trivy or grype (default from the ignite configuration)")
+	fs.StringVar((*string)(&cfg.FailOn), "scan-fail-on", string(cfg.FailOn), "Fail the import if the image has vulnerabilities of the severity or higher: Low, Medium, High or Critical")
+}
+
+func AddSBOMFlag(fs *pflag.FlagSet, format *api.SBOMFormat) {
+	fs.StringVar((*string)(format), "sbom", string(*format), "Generate the SBOM of the image in the format, spdx or cyclonedx (default from the ignite configuration)")
+}
+
+func AddImportModeFlag(fs *pflag.FlagSet, mode *api.ImageImportMode) {
+	fs.StringVar((*string)(mode), "import-mode", string(*mode), "Build the filesystem of the image by mounting it (mount), or without mounting it, which doesn't require root (offline) (default from the ignite configuration, or mount)")
+}
+
+func AddLazyImportFlag(fs *pflag.FlagSet, lazy *bool) {
+	fs.BoolVar(lazy, "lazy", *lazy, "Import images with eStargz layers with only the files they need to boot, the rest is loaded into the VMs when they're first started (default from the ignite configuration)")
+}
+
+func AddBootCacheFlag(fs *pflag.FlagSet, enabled *bool) {
+	fs.BoolVar(enabled, "boot-cache", *enabled, "Restore the VM from a snapshot of the first VM of its image, kernel and size if it hasn't been started before, or snapshot it once it has booted (default from the ignite configuration)")
+}
+
+func AddParallelFlag(fs *pflag.FlagSet, parallel *int) {
+	fs.IntVar(parallel, "parallel", 1, "How many VMs to process at once, with 1 the VMs are processed in order until one fails")
+}
+
+func AddEmulationFlag(fs *pflag.FlagSet, mode *api.EmulationMode) {
+	fs.StringVar((*string)(mode), "emulation", string(*mode), "Emulate the VM with QEMU instead of running it with Firecracker, which needs KVM: never, auto (if /dev/kvm is unavailable) or always. Emulated VMs run a lot slower (default from the ignite configuration, or never)")
+}
+
+func AddSelectorFlag(fs *pflag.FlagSet, selector *string) {
+	fs.StringVarP(selector, "selector", "l", *selector, "Select the objects by label, supports '=', '==', '!=', 'in', 'notin' and existence (e.g. -l app=ci,tier!=db)")
+}
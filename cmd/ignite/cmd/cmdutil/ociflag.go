@@ -16,7 +16,7 @@ func (of *OCIImageRefFlag) Set(val string) error {
 }
 
 func (of *OCIImageRefFlag) String() string {
-	if of.value == nil {
+	if of.value == nil || of.value.IsUnset() {
 		return ""
 	}
 	return of.value.String()
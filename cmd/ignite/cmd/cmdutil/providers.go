@@ -20,3 +20,95 @@ func ResolveRegistryConfigDir() {
 		}
 	}
 }
+
+// ResolveImageScan reads the ignite configuration to resolve the vulnerability
+// scan of the imported images. The scan flags override the configuration.
+func ResolveImageScan() {
+	if providers.ComponentConfig == nil {
+		return
+	}
+
+	cfg := providers.ComponentConfig.Spec.ImageScan
+	if providers.ImageScan.Scanner == "" {
+		providers.ImageScan.Scanner = cfg.Scanner
+		providers.ImageScan.Path = cfg.Path
+	} else if cfg.Scanner != "" {
+		log.Debug("scanner flag overriding the ignite configuration")
+	}
+
+	if providers.ImageScan.FailOn == "" {
+		providers.ImageScan.FailOn = cfg.FailOn
+	} else if cfg.FailOn != "" {
+		log.Debug("scan-fail-on flag overriding the ignite configuration")
+	}
+}
+
+// ResolveSBOM reads the ignite configuration to resolve the SBOM generated for the
+// imported images. The sbom flag overrides the configuration.
+func ResolveSBOM() {
+	if providers.ComponentConfig == nil {
+		return
+	}
+
+	cfg := providers.ComponentConfig.Spec.SBOM
+	if providers.SBOM.Format == "" {
+		providers.SBOM.Format = cfg.Format
+	} else if cfg.Format != "" {
+		log.Debug("sbom flag overriding the ignite configuration")
+	}
+
+	if providers.SBOM.Path == "" {
+		providers.SBOM.Path = cfg.Path
+	}
+}
+
+// ResolveImageImport reads the ignite configuration to resolve how the filesystems of the
+// imported images are built, how many are imported at once, and whether they're imported
+// lazily. The import-mode flag overrides the configuration, the lazy flag enables it.
+func ResolveImageImport() {
+	if providers.ComponentConfig == nil {
+		return
+	}
+
+	cfg := providers.ComponentConfig.Spec.ImageImport
+	if providers.ImageImport.Mode == "" {
+		providers.ImageImport.Mode = cfg.Mode
+	} else if cfg.Mode != "" {
+		log.Debug("import-mode flag overriding the ignite configuration")
+	}
+
+	if providers.ImageImport.Concurrency == 0 {
+		providers.ImageImport.Concurrency = cfg.Concurrency
+	}
+
+	if !providers.ImageImport.Lazy {
+		providers.ImageImport.Lazy = cfg.Lazy
+	}
+}
+
+// ResolveBootCache reads the ignite configuration to resolve whether the boot cache is
+// used, the boot-cache flag enables it
+func ResolveBootCache() {
+	if providers.ComponentConfig == nil {
+		return
+	}
+
+	if !providers.BootCache.Enabled {
+		providers.BootCache.Enabled = providers.ComponentConfig.Spec.BootCache.Enabled
+	}
+}
+
+// ResolveEmulation reads the ignite configuration to resolve whether the VMs are emulated
+// with QEMU, the emulation flag overrides it
+func ResolveEmulation() {
+	if providers.ComponentConfig == nil {
+		return
+	}
+
+	cfg := providers.ComponentConfig.Spec.Emulation
+	if providers.Emulation.Mode == "" {
+		providers.Emulation.Mode = cfg.Mode
+	} else if cfg.Mode != "" {
+		log.Debug("emulation flag overriding the ignite configuration")
+	}
+}
@@ -48,7 +48,8 @@ func (vf *VolumeFlag) Set(x string) error {
 		})
 	}
 
-	*vf.value = storage
+	// Keep the rest of the storage spec, e.g. a read-only root set by another flag
+	vf.value.Volumes, vf.value.VolumeMounts = storage.Volumes, storage.VolumeMounts
 	vf.s = x // String should return the input after a successful Set
 	return nil
 }
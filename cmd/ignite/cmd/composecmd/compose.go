@@ -0,0 +1,57 @@
+package composecmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdCompose groups the commands managing stacks of VMs
+func NewCmdCompose(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compose",
+		Short: "Manage stacks of VMs described by a stack file",
+		Long: dedent.Dedent(`
+			Create, start, stop and remove the VMs of a stack with one command. A stack
+			file describes the VMs of a stack, the networks they share, the volumes they
+			mount and the order they're started in:
+
+				name: devstack
+				networks:
+				  backend: {}
+				volumes:
+				  pgdata:
+				    blockDevice:
+				      path: /dev/vg0/pgdata
+				vms:
+				  db:
+				    image:
+				      oci: weaveworks/ignite-ubuntu:${UBUNTU_TAG:-latest}
+				    memory: 1GB
+				    networks: [backend]
+				    volumes: ["pgdata:/var/lib/postgresql"]
+				  web:
+				    image:
+				      oci: weaveworks/ignite-ubuntu:${UBUNTU_TAG:-latest}
+				    cpus: 2
+				    networks: [default, backend]
+				    dependsOn: [db]
+
+			The keys of a VM besides dependsOn, networks, volumes and labels are its spec,
+			as in a VM manifest. The VMs are named <stack>-<name>. Variables of the
+			environment and of the .env file next to the stack file are substituted.
+		`),
+	}
+
+	cmd.AddCommand(NewCmdUp(out))
+	cmd.AddCommand(NewCmdDown(out))
+	return cmd
+}
+
+func addComposeFlags(fs *pflag.FlagSet, cf *run.ComposeFlags) {
+	fs.StringVarP(&cf.Filename, "file", "f", "ignite-compose.yaml", "Stack file")
+	fs.StringVarP(&cf.ProjectName, "project-name", "p", "", "Name of the stack, instead of its name in the stack file or the name of its directory")
+}
@@ -0,0 +1,42 @@
+package composecmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdDown stops and removes the VMs of a stack
+func NewCmdDown(out io.Writer) *cobra.Command {
+	cf := &run.ComposeFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "Stop and remove the VMs of a stack",
+		Long: dedent.Dedent(`
+			Stop and remove the VMs of the stack, the VMs depending on others first. The
+			VMs that were removed from the stack file are removed too. The images, kernels
+			and volumes of the stack are kept.
+
+			Example usage:
+				$ ignite compose down -f stack.yaml
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				co, err := cf.NewComposeOptions()
+				if err != nil {
+					return err
+				}
+
+				return run.ComposeDown(co)
+			}())
+		},
+	}
+
+	addComposeFlags(cmd.Flags(), cf)
+	return cmd
+}
@@ -0,0 +1,53 @@
+package composecmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+	networkflag "github.com/weaveworks/ignite/pkg/network/flag"
+	"github.com/weaveworks/ignite/pkg/providers"
+	runtimeflag "github.com/weaveworks/ignite/pkg/runtime/flag"
+)
+
+// NewCmdUp creates and starts the VMs of a stack
+func NewCmdUp(out io.Writer) *cobra.Command {
+	cf := &run.ComposeFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Create and start the VMs of a stack",
+		Long: dedent.Dedent(`
+			Create the VMs of the stack file that don't exist, update the ones that
+			changed like "ignite apply", and start the ones that aren't running. A VM is
+			started after the VMs it depends on. Before a VM is started, the names and
+			IP addresses of the running VMs it shares a network with are written to its
+			/etc/hosts, so it reaches the VMs it depends on by name.
+
+			With the dry-run flag (--dry-run) the changes are only printed.
+
+			Example usage:
+				$ ignite compose up -f stack.yaml
+				$ ignite compose up -f stack.yaml --dry-run
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				co, err := cf.NewComposeOptions()
+				if err != nil {
+					return err
+				}
+
+				return run.ComposeUp(co, cmd.Flags())
+			}())
+		},
+	}
+
+	addComposeFlags(cmd.Flags(), cf)
+	cmd.Flags().BoolVar(&cf.DryRun, "dry-run", false, "Print the changes without making them")
+	runtimeflag.RuntimeVar(cmd.Flags(), &providers.RuntimeName)
+	networkflag.NetworkPluginVar(cmd.Flags(), &providers.NetworkPluginName)
+	return cmd
+}
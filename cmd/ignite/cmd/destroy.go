@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdDestroy stops and removes the VM of the project of the current directory
+func NewCmdDestroy(out io.Writer) *cobra.Command {
+	pf := &run.ProjectFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "destroy",
+		Short: "Stop and remove the VM of the project in the current directory",
+		Long: dedent.Dedent(`
+			Stop and remove the VM of the project of the current directory, created by
+			"ignite up". The files of the project on the host are kept, the next
+			"ignite up" creates and provisions a new VM.
+
+			Example usage:
+				$ ignite destroy
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				po, err := pf.NewProjectOptions()
+				if err != nil {
+					return err
+				}
+
+				return run.Destroy(po)
+			}())
+		},
+	}
+
+	return cmd
+}
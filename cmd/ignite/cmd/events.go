@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdEvents prints the events of the VMs, images and kernels
+func NewCmdEvents(out io.Writer) *cobra.Command {
+	ef := &run.EventsFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Show the events of the VMs, images and kernels",
+		Long: dedent.Dedent(`
+			Print the events recorded by ignited daemon about the VMs, images and kernels,
+			oldest first: when they were created, started, stopped, removed or imported.
+			The events of VMs that stopped tell why, e.g. OOMKilled if Firecracker ran out
+			of memory, Killed if it was killed by a signal or Error if it failed, and with
+			which exit code. The events persist after the VM is removed.
+
+			The events are read from /var/lib/firecracker/events.log, they're only recorded
+			while ignited daemon runs. With --watch, the events keep being printed as
+			they're recorded.
+
+			Example usage:
+				$ ignite events --vm my-vm
+				$ ignite events --watch -o json
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(run.Events(ef))
+		},
+	}
+
+	addEventsFlags(cmd.Flags(), ef)
+	return cmd
+}
+
+func addEventsFlags(fs *pflag.FlagSet, ef *run.EventsFlags) {
+	fs.StringVar(&ef.VM, "vm", ef.VM, "Only show the events of the VM with the given name or UID")
+	fs.BoolVarP(&ef.Watch, "watch", "w", ef.Watch, "Keep printing the events as they're recorded")
+	fs.StringVarP(&ef.Output, "output", "o", ef.Output, "Output format: table|json")
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdHalt stops the VM of the project of the current directory
+func NewCmdHalt(out io.Writer) *cobra.Command {
+	pf := &run.ProjectFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "halt",
+		Short: "Stop the VM of the project in the current directory",
+		Long: dedent.Dedent(`
+			Stop the VM of the project of the current directory, created by "ignite up".
+			The VM is kept, and "ignite up" starts it again.
+
+			Example usage:
+				$ ignite halt
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				po, err := pf.NewProjectOptions()
+				if err != nil {
+					return err
+				}
+
+				return run.Halt(po)
+			}())
+		},
+	}
+
+	return cmd
+}
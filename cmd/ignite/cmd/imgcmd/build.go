@@ -0,0 +1,71 @@
+package imgcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+	"github.com/weaveworks/ignite/pkg/build"
+	"github.com/weaveworks/ignite/pkg/providers"
+	runtimeflag "github.com/weaveworks/ignite/pkg/runtime/flag"
+)
+
+// NewCmdBuild builds a new VM image from a Dockerfile
+func NewCmdBuild(out io.Writer) *cobra.Command {
+	bf := &run.BuildFlags{
+		Builder: string(build.BuilderAuto),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "build <context directory>",
+		Short: "Build a base image for VMs from a Dockerfile",
+		Long: dedent.Dedent(`
+			Build an OCI image from the Dockerfile or Containerfile in the given context
+			directory, and import it as a base image for VMs named after the tag flag
+			(-t, --tag). Another file can be built with the file flag (-f, --file).
+
+			The builder flag (--builder) selects the tool building the image: "docker"
+			runs "docker build" and requires the docker runtime, "buildkit" runs
+			"buildctl build" against a BuildKit daemon, optionally at the address given
+			with --buildkit-addr, and loads the image into the configured runtime.
+			By default ("auto"), docker is used with the docker runtime, and BuildKit
+			otherwise.
+
+			Example usage:
+				$ ignite image build -t my-ubuntu:latest .
+				$ ignite image build -t my-app:v1 -f build/Containerfile --build-arg VERSION=1 .
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				bo, err := bf.NewBuildOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				_, err = run.Build(bo)
+				return err
+			}())
+		},
+	}
+
+	addBuildFlags(cmd.Flags(), bf)
+	return cmd
+}
+
+func addBuildFlags(fs *pflag.FlagSet, bf *run.BuildFlags) {
+	fs.StringVarP(&bf.Tag, "tag", "t", bf.Tag, "Name of the built image")
+	fs.StringVarP(&bf.File, "file", "f", bf.File, "Dockerfile to build (default <context directory>/Dockerfile or Containerfile)")
+	fs.StringArrayVar(&bf.BuildArgs, "build-arg", bf.BuildArgs, "Set a build-time variable (KEY=VALUE)")
+	fs.StringVar(&bf.Target, "target", bf.Target, "Build stage to build")
+	fs.BoolVar(&bf.NoCache, "no-cache", bf.NoCache, "Don't use the build cache")
+	fs.StringVar(&bf.Builder, "builder", bf.Builder, "Tool building the image: auto, docker or buildkit")
+	fs.StringVar(&bf.BuildKitAddr, "buildkit-addr", bf.BuildKitAddr, "Address of the BuildKit daemon (default buildctl's default)")
+	fs.BoolVar(&bf.WithAgent, "agent", bf.WithAgent, "Inject the ignite guest agent into the image")
+	runtimeflag.RuntimeVar(fs, &providers.RuntimeName)
+	cmdutil.AddRegistryConfigDirFlag(fs, &providers.RegistryConfigDir)
+}
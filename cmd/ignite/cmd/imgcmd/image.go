@@ -5,6 +5,7 @@ import (
 
 	"github.com/lithammer/dedent"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
 	"github.com/weaveworks/ignite/cmd/ignite/run"
 )
@@ -12,17 +13,24 @@ import (
 // NewCmdImage handles image-related functionality via its subcommands
 // This command by itself lists available images
 func NewCmdImage(out io.Writer) *cobra.Command {
+	imf := &run.ImagesFlags{}
+
 	cmd := &cobra.Command{
 		Use:   "image",
 		Short: "Manage base images for VMs",
 		Long: dedent.Dedent(`
 			Groups together functionality for managing VM base images.
-			Calling this command alone lists all available images.
+			Calling this command alone lists all available images, the selector
+			flag (-l, --selector) lists only the images with matching labels.
+
+			The output flag (-o, --output) selects the format of the list: "table"
+			(default), "json", "yaml", "go-template=<template>" or
+			"custom-columns=<header>:<field path>,...", see "ignite ps" for details.
 		`),
 		Aliases: []string{"images"},
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
-				i, err := run.NewImagesOptions()
+				i, err := imf.NewImagesOptions()
 				if err != nil {
 					return err
 				}
@@ -32,8 +40,18 @@ func NewCmdImage(out io.Writer) *cobra.Command {
 		},
 	}
 
+	cmd.AddCommand(NewCmdBuild(out))
 	cmd.AddCommand(NewCmdImport(out))
-	cmd.AddCommand(NewCmdLs(out))
+	cmd.AddCommand(NewCmdLs(out, imf))
 	cmd.AddCommand(NewCmdRm(out))
+	cmd.AddCommand(NewCmdSBOM(out))
+	cmd.AddCommand(NewCmdTag(out))
+
+	addImagesFlags(cmd.Flags(), imf)
 	return cmd
 }
+
+func addImagesFlags(fs *pflag.FlagSet, imf *run.ImagesFlags) {
+	cmdutil.AddOutputFlag(fs, &imf.Output)
+	cmdutil.AddSelectorFlag(fs, &imf.Selector)
+}
@@ -15,6 +15,9 @@ import (
 
 // NewCmdImport imports a new VM image
 func NewCmdImport(out io.Writer) *cobra.Command {
+	var withAgent bool
+	var output string
+
 	cmd := &cobra.Command{
 		Use:   "import <OCI image>",
 		Short: "Import a new base image for VMs",
@@ -22,21 +25,68 @@ func NewCmdImport(out io.Writer) *cobra.Command {
 			Import an OCI image as a base image for VMs, takes in a Docker image identifier.
 			This importing is done automatically when the "run" or "create" commands are run.
 			The import step is essentially a cache for images to be used later when running VMs.
+
+			Using the agent flag (--agent), the ignite guest agent is injected into the image,
+			and started at boot using systemd. It lets ignite execute commands, copy files,
+			read metrics and shut down VMs based on images without an SSH server, see the
+			agent flags of the "exec", "cp" and "stop" commands, and "ignite vm metrics".
+			The ignite-agent binary needs to be installed next to ignite or in $PATH.
+
+			With a vulnerability scanner set (--scanner, or the imageScan section of the
+			ignite configuration), the root filesystem of the image is scanned with trivy
+			or grype, and the vulnerabilities found are stored in the image status. With
+			a severity set (--scan-fail-on), the import fails if the image has
+			vulnerabilities of the severity or higher, and the image is removed.
+
+			With an SBOM format set (--sbom, or the sbom section of the ignite
+			configuration), the software bill of materials of the image is generated
+			by syft in the SPDX or CycloneDX format, see "ignite image sbom".
+
+			In the offline import mode (--import-mode offline, or the imageImport section
+			of the ignite configuration), the filesystem of the image is built with
+			mkfs.ext4 -d without mounting it, so images can be imported without root.
+
+			With lazy imports enabled (--lazy, or the imageImport section of the ignite
+			configuration), images with eStargz layers are imported with only the files
+			before their prefetch landmarks, read from the registry with range requests.
+			The guest agent is injected, and writes the rest of the files into the disk
+			of each VM when it's first started, after the VM has booted. Images with
+			other layers are imported in full.
+
+			Images prefixed with "docker-daemon:" are imported from the local docker
+			daemon instead of a registry, e.g. "docker-daemon:my-app:dev". With the
+			containerd runtime, the image is exported from docker like "docker save"
+			and loaded into containerd, so images built locally with docker don't need
+			to be pushed to a registry. These images are always imported in full.
 		`),
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
-				_, err := run.ImportImage(args[0])
-				return err
+				if err := run.SetResultOutput(output); err != nil {
+					return err
+				}
+
+				image, err := run.ImportImage(args[0], withAgent)
+				if err != nil {
+					return err
+				}
+
+				return run.PrintResult(output, image)
 			}())
 		},
 	}
 
-	addImportFlags(cmd.Flags())
+	addImportFlags(cmd.Flags(), &withAgent)
+	cmdutil.AddResultOutputFlag(cmd.Flags(), &output)
 	return cmd
 }
 
-func addImportFlags(fs *pflag.FlagSet) {
+func addImportFlags(fs *pflag.FlagSet, withAgent *bool) {
+	fs.BoolVar(withAgent, "agent", *withAgent, "Inject the ignite guest agent into the image")
 	runtimeflag.RuntimeVar(fs, &providers.RuntimeName)
 	cmdutil.AddRegistryConfigDirFlag(fs, &providers.RegistryConfigDir)
+	cmdutil.AddImageScanFlags(fs, &providers.ImageScan)
+	cmdutil.AddSBOMFlag(fs, &providers.SBOM.Format)
+	cmdutil.AddImportModeFlag(fs, &providers.ImageImport.Mode)
+	cmdutil.AddLazyImportFlag(fs, &providers.ImageImport.Lazy)
 }
@@ -0,0 +1,43 @@
+package imgcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdSBOM prints the SBOM of an image
+func NewCmdSBOM(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom <image>",
+		Short: "Print the software bill of materials of an image",
+		Long: dedent.Dedent(`
+			Print the software bill of materials (SBOM) of an image, which lists the
+			packages installed in it. The SBOM is generated by syft when the image is
+			imported with an SBOM format set, either with the sbom flag (--sbom) of
+			"ignite image import", or in the sbom section of the ignite configuration.
+			It's printed in the format it was generated in, SPDX or CycloneDX JSON.
+			The image is matched by prefix based on its ID and name.
+
+			Example usage:
+				$ ignite image import --sbom spdx weaveworks/ignite-ubuntu
+				$ ignite image sbom weaveworks/ignite-ubuntu > ubuntu.spdx.json
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				so, err := run.NewSBOMOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.SBOM(out, so)
+			}())
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+package imgcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdTag adds a name to an image
+func NewCmdTag(out io.Writer) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "tag <image> <name>",
+		Short: "Add a name to an imported image",
+		Long: dedent.Dedent(`
+			Add a name to an imported image, like "docker tag", so VMs can be created
+			from the image with either name, e.g. to promote an image from development
+			to staging without importing it again. The image is matched by prefix based
+			on its ID and name.
+
+			The name becomes an image of its own, with its own ID, sharing the files of
+			the image through hard links, so no disk space is used. Removing one of the
+			names with "ignite rmi" keeps the others. The OCI image is tagged in the
+			container runtime with the name as well. An image that already has the name
+			needs to be removed first, as the VMs using it would break if the name moved.
+
+			Example usage:
+				$ ignite image tag my-app:dev my-app:staging
+				$ ignite run my-app:staging --name staging-1
+		`),
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				if err := run.SetResultOutput(output); err != nil {
+					return err
+				}
+
+				ito, err := run.NewImageTagOptions(args[0], args[1])
+				if err != nil {
+					return err
+				}
+
+				image, err := run.ImageTag(ito)
+				if err != nil {
+					return err
+				}
+
+				return run.PrintResult(output, image)
+			}())
+		},
+	}
+
+	cmdutil.AddResultOutputFlag(cmd.Flags(), &output)
+	return cmd
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdJobs prints the jobs of ignited
+func NewCmdJobs(out io.Writer) *cobra.Command {
+	jf := &run.JobsFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Show the operations queued and running in ignited",
+		Long: dedent.Dedent(`
+			Print the jobs of ignited daemon or gitops: the imports, VM starts, stops and
+			removals, and reconciliations triggered by the management API and the manifests.
+			At most a limit of jobs of each class run at once, set with --job-concurrency,
+			the others are queued until a slot of their class frees up.
+
+			Only the queued and running jobs are printed by default, with --all the last
+			finished jobs are printed too, with the errors of the ones that failed. With
+			-o json, the concurrency limits are printed as well.
+
+			Example usage:
+				$ ignite jobs
+				$ ignite jobs --all -o json
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(run.Jobs(jf))
+		},
+	}
+
+	addJobsFlags(cmd.Flags(), jf)
+	return cmd
+}
+
+func addJobsFlags(fs *pflag.FlagSet, jf *run.JobsFlags) {
+	fs.BoolVarP(&jf.All, "all", "a", jf.All, "Also show the last finished jobs")
+	fs.StringVarP(&jf.Output, "output", "o", jf.Output, "Output format: table|json")
+}
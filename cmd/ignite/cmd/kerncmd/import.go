@@ -15,6 +15,8 @@ import (
 
 // NewCmdImport imports a new kernel image
 func NewCmdImport(out io.Writer) *cobra.Command {
+	var output string
+
 	cmd := &cobra.Command{
 		Use:   "import <OCI image>",
 		Short: "Import a kernel image from an OCI image",
@@ -22,17 +24,30 @@ func NewCmdImport(out io.Writer) *cobra.Command {
 			Import an OCI image as a kernel image for VMs, takes in a Docker image identifier.
 			This importing is done automatically when the "run" or "create" commands are run.
 			The import step is essentially a cache for images to be used later when running VMs.
+
+			Kernels prefixed with "docker-daemon:" are imported from the local docker daemon
+			instead of a registry, e.g. "docker-daemon:my-kernel:dev", also when the container
+			runtime is containerd.
 		`),
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
-				_, err := run.ImportKernel(args[0])
-				return err
+				if err := run.SetResultOutput(output); err != nil {
+					return err
+				}
+
+				kernel, err := run.ImportKernel(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.PrintResult(output, kernel)
 			}())
 		},
 	}
 
 	addImportFlags(cmd.Flags())
+	cmdutil.AddResultOutputFlag(cmd.Flags(), &output)
 	return cmd
 }
 
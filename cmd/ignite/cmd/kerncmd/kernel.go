@@ -5,6 +5,7 @@ import (
 
 	"github.com/lithammer/dedent"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
 	"github.com/weaveworks/ignite/cmd/ignite/run"
 )
@@ -12,17 +13,24 @@ import (
 // NewCmdKernel handles kernel-related functionality via its subcommands
 // This command by itself lists available kernels
 func NewCmdKernel(out io.Writer) *cobra.Command {
+	kf := &run.KernelsFlags{}
+
 	cmd := &cobra.Command{
 		Use:   "kernel",
 		Short: "Manage VM kernels",
 		Long: dedent.Dedent(`
 			Groups together functionality for managing VM kernels.
-			Calling this command alone lists all available kernels.
+			Calling this command alone lists all available kernels, the selector
+			flag (-l, --selector) lists only the kernels with matching labels.
+
+			The output flag (-o, --output) selects the format of the list: "table"
+			(default), "json", "yaml", "go-template=<template>" or
+			"custom-columns=<header>:<field path>,...", see "ignite ps" for details.
 		`),
 		Aliases: []string{"kernels"},
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
-				ko, err := run.NewKernelsOptions()
+				ko, err := kf.NewKernelsOptions()
 				if err != nil {
 					return err
 				}
@@ -33,7 +41,14 @@ func NewCmdKernel(out io.Writer) *cobra.Command {
 	}
 
 	cmd.AddCommand(NewCmdImport(out))
-	cmd.AddCommand(NewCmdLs(out))
+	cmd.AddCommand(NewCmdLs(out, kf))
 	cmd.AddCommand(NewCmdRm(out))
+
+	addKernelsFlags(cmd.Flags(), kf)
 	return cmd
 }
+
+func addKernelsFlags(fs *pflag.FlagSet, kf *run.KernelsFlags) {
+	cmdutil.AddOutputFlag(fs, &kf.Output)
+	cmdutil.AddSelectorFlag(fs, &kf.Selector)
+}
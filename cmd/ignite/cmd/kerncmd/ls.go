@@ -5,10 +5,11 @@ import (
 
 	"github.com/lithammer/dedent"
 	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
 )
 
 // NewCmdLs lists available kernels
-func NewCmdLs(out io.Writer) *cobra.Command {
+func NewCmdLs(out io.Writer, kf *run.KernelsFlags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "ls",
 		Short: "List available VM kernels",
@@ -21,5 +22,6 @@ func NewCmdLs(out io.Writer) *cobra.Command {
 		},
 	}
 
+	addKernelsFlags(cmd.Flags(), kf)
 	return cmd
 }
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdLabel sets and removes the labels of an Ignite Object
+func NewCmdLabel(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label <kind> <object> <key>=<value>|<key>-...",
+		Short: "Set or remove the labels of an Ignite Object",
+		Long: dedent.Dedent(`
+			Set or remove the labels of the given object of the given kind. The kind
+			can be "image", "kernel" or "vm". The object is matched by prefix based on
+			its ID and name. A "key=value" argument sets a label, overwriting its
+			current value, and a "key-" argument removes it.
+
+			Labels can be used to select the objects with the selector flag (-l, --selector)
+			of "ignite ps", "ignite images", "ignite kernels" and the commands operating
+			on multiple VMs, like "ignite stop" and "ignite rm".
+
+			Example usage:
+				$ ignite label vm my-vm app=ci tier=build
+
+				$ ignite label image weaveworks/ignite-ubuntu os=ubuntu
+
+				$ ignite label vm my-vm tier-
+		`),
+		Args: cobra.MinimumNArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				lo, err := run.NewLabelOptions(args[0], args[1], args[2:])
+				if err != nil {
+					return err
+				}
+
+				return run.Label(lo)
+			}())
+		},
+	}
+
+	return cmd
+}
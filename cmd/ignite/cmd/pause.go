@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/vmcmd"
+)
+
+// NewCmdPause is an alias for vmcmd.NewCmdPause
+func NewCmdPause(out io.Writer) *cobra.Command {
+	return vmcmd.NewCmdPause(out)
+}
@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/vmcmd"
+)
+
+// NewCmdResume is an alias for vmcmd.NewCmdResume
+func NewCmdResume(out io.Writer) *cobra.Command {
+	return vmcmd.NewCmdResume(out)
+}
@@ -9,9 +9,15 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/composecmd"
 	"github.com/weaveworks/ignite/cmd/ignite/cmd/imgcmd"
 	"github.com/weaveworks/ignite/cmd/ignite/cmd/kerncmd"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/schemacmd"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/systemcmd"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/tmplcmd"
 	"github.com/weaveworks/ignite/cmd/ignite/cmd/vmcmd"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/config"
 	"github.com/weaveworks/ignite/pkg/logs"
 	logflag "github.com/weaveworks/ignite/pkg/logs/flag"
@@ -23,6 +29,8 @@ import (
 
 var logLevel = log.InfoLevel
 
+var logFormat = logs.FormatText
+
 // Ignite config file path flag variable.
 var configPath string
 
@@ -30,14 +38,19 @@ var configPath string
 func NewIgniteCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 	imageCmd := imgcmd.NewCmdImage(os.Stdout)
 	kernelCmd := kerncmd.NewCmdKernel(os.Stdout)
+	systemCmd := systemcmd.NewCmdSystem(os.Stdout)
+	templateCmd := tmplcmd.NewCmdTemplate(os.Stdout)
 	vmCmd := vmcmd.NewCmdVM(os.Stdout)
 
 	root := &cobra.Command{
 		Use:   "ignite",
 		Short: "ignite: easily run Firecracker VMs",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			// Set the desired logging level, now that the flags are parsed
+			// Set the desired logging level and format, now that the flags are parsed
 			logs.Logger.SetLevel(logLevel)
+			if err := logs.SetFormat(logFormat); err != nil {
+				log.Fatal(err)
+			}
 
 			// TODO Some commands do not need to check root
 			// Currently it seems to be only ignite version that does not require root
@@ -45,30 +58,40 @@ func NewIgniteCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 				return
 			}
 
+			if err := config.ApplyConfiguration(configPath); err != nil {
+				log.Fatal(err)
+			}
+
 			// Ignite needs to run as root for now, see
 			// https://github.com/weaveworks/ignite/issues/46
 			// TODO: Remove this when ready
-			util.GenericCheckErr(util.TestRoot())
+			if !isUnprivilegedImport(cmd) {
+				util.GenericCheckErr(util.TestRoot())
+			}
 
 			// Create the directories needed for running
 			util.GenericCheckErr(util.CreateDirectories())
 
-			if err := config.ApplyConfiguration(configPath); err != nil {
-				log.Fatal(err)
-			}
-
 			// Populate the providers after flags have been parsed
 			if err := providers.Populate(ignite.Providers); err != nil {
 				log.Fatal(err)
 			}
+
+			// Record the commands changing VMs, images or kernels in the audit log
+			beginAudit(cmd)
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			finishAudit(nil)
 		},
 		Long: dedent.Dedent(fmt.Sprintf(`
 			Ignite is a containerized Firecracker microVM administration tool.
 			It can build VM images, spin VMs up/down and manage multiple VMs efficiently.
 
-			Administration is divided into three subcommands:
+			Administration is divided into five subcommands:
 			  image       %s
 			  kernel      %s
+			  system      %s
+			  template    %s
 			  vm          %s
 
 			Ignite also supports the same commands as the Docker CLI.
@@ -86,51 +109,83 @@ func NewIgniteCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 				$ ignite ps
 				$ ignite logs my-vm
 				$ ignite ssh my-vm
-		`, imageCmd.Short, kernelCmd.Short, vmCmd.Short)),
+		`, imageCmd.Short, kernelCmd.Short, systemCmd.Short, templateCmd.Short, vmCmd.Short)),
 	}
 
 	addGlobalFlags(root.PersistentFlags())
 
 	root.AddCommand(imageCmd)
 	root.AddCommand(kernelCmd)
+	root.AddCommand(systemCmd)
+	root.AddCommand(templateCmd)
 	root.AddCommand(vmCmd)
 
+	root.AddCommand(NewCmdApply(os.Stdout))
 	root.AddCommand(NewCmdAttach(os.Stdout))
+	root.AddCommand(NewCmdAudit(os.Stdout))
 	root.AddCommand(NewCmdCompletion(os.Stdout, root))
+	root.AddCommand(composecmd.NewCmdCompose(os.Stdout))
 	root.AddCommand(NewCmdCP(os.Stdout))
 	root.AddCommand(NewCmdCreate(os.Stdout))
+	root.AddCommand(NewCmdDestroy(os.Stdout))
+	root.AddCommand(NewCmdEvents(os.Stdout))
+	root.AddCommand(NewCmdHalt(os.Stdout))
+	root.AddCommand(NewCmdJobs(os.Stdout))
 	root.AddCommand(NewCmdKill(os.Stdout))
 	root.AddCommand(NewCmdLogs(os.Stdout))
 	root.AddCommand(NewCmdInspect(os.Stdout))
+	root.AddCommand(NewCmdLabel(os.Stdout))
+	root.AddCommand(NewCmdPause(os.Stdout))
 	root.AddCommand(NewCmdPs(os.Stdout))
+	root.AddCommand(NewCmdResume(os.Stdout))
 	root.AddCommand(NewCmdRm(os.Stdout))
 	root.AddCommand(NewCmdRmi(os.Stdout))
 	root.AddCommand(NewCmdRmk(os.Stdout))
 	root.AddCommand(NewCmdRun(os.Stdout))
+	root.AddCommand(schemacmd.NewCmdSchema(os.Stdout))
 	root.AddCommand(NewCmdSSH(os.Stdout))
 	root.AddCommand(NewCmdExec(os.Stdout, os.Stderr, os.Stdin))
 	root.AddCommand(NewCmdStart(os.Stdout))
 	root.AddCommand(NewCmdStop(os.Stdout))
+	root.AddCommand(NewCmdUp(os.Stdout))
 	root.AddCommand(versioncmd.NewCmdVersion(os.Stdout))
 	return root
 }
 
 func isNonRootCommand(cmd string, parentCmd string) bool {
+	// The schema commands only print the API schemas
+	if parentCmd == "schema" {
+		return true
+	}
+
 	if parentCmd != "ignite" {
 		return false
 	}
 
 	switch cmd {
-	case "version", "help", "image", "kernel", "completion", "inspect", "ps":
+	case "version", "help", "image", "kernel", "completion", "inspect", "ps", "schema":
 		return true
 	}
 
 	return false
 }
 
+// isUnprivilegedImport returns true for image imports in the offline import mode, which
+// doesn't mount the image, so they can run as a user with access to the container runtime
+// and the data directory of ignite
+func isUnprivilegedImport(cmd *cobra.Command) bool {
+	if cmd.Name() != "import" || cmd.Parent().Name() != "image" {
+		return false
+	}
+
+	cmdutil.ResolveImageImport()
+	return providers.ImageImport.Mode == api.ImageImportModeOffline
+}
+
 func addGlobalFlags(fs *pflag.FlagSet) {
 	AddQuietFlag(fs)
 	logflag.LogLevelFlagVar(fs, &logLevel)
+	logflag.LogFormatFlagVar(fs, &logFormat)
 	fs.StringVar(&configPath, "ignite-config", "", "Ignite configuration path; refer to the 'Ignite Configuration' docs for more details")
 }
 
@@ -0,0 +1,57 @@
+package schemacmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+	"github.com/weaveworks/ignite/pkg/schema"
+)
+
+// NewCmdExport exports the JSON schemas of the API kinds
+func NewCmdExport(out io.Writer) *cobra.Command {
+	sef := &run.SchemaExportFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "export [kind]...",
+		Short: "Export the JSON schemas of the API kinds",
+		Long: dedent.Dedent(fmt.Sprintf(`
+			Export the JSON schemas of the manifests of the given kinds, or of all
+			kinds if none are given. The schemas are built from the API types of
+			this ignite binary, so they match the manifests it accepts.
+			Kinds: %s
+
+			A single schema is written to stdout, which matches a manifest of any of
+			the kinds. With an output directory (--output-dir), a schema for every kind
+			is written to it instead, named after the kind, e.g. vm.json. The API
+			version of the schemas is set with the api-version flag (--api-version).
+
+			Example usage:
+				$ ignite schema export vm > vm.schema.json
+				$ ignite schema export --output-dir schemas/
+		`, strings.Join(schema.KindNames(), ", "))),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				seo, err := sef.NewSchemaExportOptions(args)
+				if err != nil {
+					return err
+				}
+
+				return run.SchemaExport(seo)
+			}())
+		},
+	}
+
+	addExportFlags(cmd.Flags(), sef)
+	return cmd
+}
+
+func addExportFlags(fs *pflag.FlagSet, sef *run.SchemaExportFlags) {
+	fs.StringVar(&sef.APIVersion, "api-version", schema.DefaultVersion, fmt.Sprintf("API version of the schemas, one of %v", schema.Versions))
+	fs.StringVar(&sef.OutputDir, "output-dir", "", "Write a schema for every kind to this directory instead of stdout")
+}
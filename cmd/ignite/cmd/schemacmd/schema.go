@@ -0,0 +1,27 @@
+package schemacmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdSchema handles the JSON schemas of the manifests via its subcommands
+func NewCmdSchema(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Export the JSON schemas of ignite manifests",
+		Long: dedent.Dedent(`
+			Groups together functionality for the JSON schemas of the ignite API kinds,
+			which editors and CI pipelines can use to validate manifests.
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewCmdExport(out))
+	return cmd
+}
@@ -0,0 +1,46 @@
+package systemcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdBootCache lists or clears the boot cache
+func NewCmdBootCache(out io.Writer) *cobra.Command {
+	bf := &run.BootCacheFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "boot-cache",
+		Short: "List or clear the snapshots of the boot cache",
+		Long: dedent.Dedent(`
+			List the entries of the boot cache. With the boot cache enabled (--boot-cache,
+			or the bootCache section of the ignite configuration), the first VM of an image,
+			kernel and size is snapshotted once it has booted, and the next VMs of the same
+			kind are restored from the snapshot instead of booting. An entry holds the memory
+			of the snapshotted VM, and the data it has written to its disk.
+
+			The entries of an image or kernel are removed with it. The clear flag (--clear)
+			removes all entries, the next VMs boot and are snapshotted again.
+
+			Example usage:
+				$ ignite system boot-cache
+				$ ignite system boot-cache --clear
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(run.BootCache(bf))
+		},
+	}
+
+	addBootCacheFlags(cmd.Flags(), bf)
+	return cmd
+}
+
+func addBootCacheFlags(fs *pflag.FlagSet, bf *run.BootCacheFlags) {
+	fs.BoolVar(&bf.Clear, "clear", false, "Remove all entries of the boot cache")
+}
@@ -0,0 +1,51 @@
+package systemcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdDf shows the disk usage of the images, kernels and VMs
+func NewCmdDf(out io.Writer) *cobra.Command {
+	df := &run.DfFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "df",
+		Short: "Show the disk usage of images, kernels and VMs",
+		Long: dedent.Dedent(`
+			Show the disk space used by the images, kernels and VMs of the host.
+			The disks of the VMs are copy-on-write snapshots of their images, so
+			the image data they share is only stored once. The disk usage of a
+			VM only counts the data written by the VM itself. The verbose flag
+			(-v, --verbose) shows the shared and unique usage of every VM.
+
+			The snapshotters that can't tell the data of a VM apart from its
+			image, dmthin and lvm with a thin pool, show its usage as n/a.
+
+			Example usage:
+				$ ignite system df -v
+		`),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				do, err := df.NewDfOptions()
+				if err != nil {
+					return err
+				}
+
+				return run.Df(do)
+			}())
+		},
+	}
+
+	addDfFlags(cmd.Flags(), df)
+	return cmd
+}
+
+func addDfFlags(fs *pflag.FlagSet, df *run.DfFlags) {
+	fs.BoolVarP(&df.Verbose, "verbose", "v", false, "Show the disk usage of every VM")
+}
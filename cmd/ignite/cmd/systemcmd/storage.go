@@ -0,0 +1,65 @@
+package systemcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+	"github.com/weaveworks/ignite/pkg/etcdstorage"
+)
+
+// NewCmdStorage prints or changes where the metadata is stored
+func NewCmdStorage(out io.Writer) *cobra.Command {
+	etcd := &etcdstorage.Config{}
+
+	cmd := &cobra.Command{
+		Use:   "storage [file|bolt|etcd]",
+		Short: "Show or change where the metadata of the VMs, images and kernels is stored",
+		Long: dedent.Dedent(`
+			Print where the metadata of the VMs, images, kernels and VM templates is stored,
+			or move it to the given storage:
+
+			  file   A metadata.json file in the directory of every object in /var/lib/firecracker,
+			         the default
+			  bolt   The bbolt database /var/lib/firecracker/metadata.db. Listing and updating
+			         hundreds of VMs is faster, and concurrent updates don't race.
+			  etcd   An etcd cluster, given with --etcd-endpoints, shared by the hosts using the
+			         same --etcd-prefix. The disks of the images and VMs stay on every host.
+
+			Moving the metadata copies all objects to the other storage, the VMs keep running
+			and nothing is imported again. The metadata is moved between the metadata files
+			and the other storages, move it back to the files before moving it to another
+			storage. ignite and ignited use the enabled storage, stop ignited before moving
+			the metadata.
+
+			Example usage:
+				$ ignite system storage
+				$ ignite system storage bolt
+				$ ignite system storage etcd --etcd-endpoints https://10.0.0.1:2379 \
+					--etcd-cacert /etc/etcd/ca.crt --etcd-cert client.crt --etcd-key client.key
+		`),
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var backend string
+			if len(args) > 0 {
+				backend = args[0]
+			}
+
+			cmdutil.CheckErr(run.Storage(backend, etcd))
+		},
+	}
+
+	addStorageFlags(cmd.Flags(), etcd)
+	return cmd
+}
+
+func addStorageFlags(fs *pflag.FlagSet, etcd *etcdstorage.Config) {
+	fs.StringSliceVar(&etcd.Endpoints, "etcd-endpoints", nil, "URLs of the etcd members to move the metadata to, comma-separated")
+	fs.StringVar(&etcd.Prefix, "etcd-prefix", etcdstorage.DefaultPrefix, "Prefix of the keys of the objects in etcd, shared by the hosts sharing their objects")
+	fs.StringVar(&etcd.CACert, "etcd-cacert", "", "CA certificate file verifying the etcd members")
+	fs.StringVar(&etcd.Cert, "etcd-cert", "", "Client certificate file authenticating to etcd")
+	fs.StringVar(&etcd.Key, "etcd-key", "", "Key file of the client certificate")
+}
@@ -0,0 +1,25 @@
+package systemcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdSystem handles host-wide functionality via its subcommands
+func NewCmdSystem(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "system",
+		Short: "Manage the ignite host",
+		Long: dedent.Dedent(`
+			Groups together functionality concerning all VMs, images and kernels
+			of the host.
+		`),
+	}
+
+	cmd.AddCommand(NewCmdBootCache(out))
+	cmd.AddCommand(NewCmdDf(out))
+	cmd.AddCommand(NewCmdStorage(out))
+	return cmd
+}
@@ -0,0 +1,54 @@
+package tmplcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdCreate creates a new VM template from a manifest
+func NewCmdCreate(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <file>",
+		Short: "Create a new VM template",
+		Long: dedent.Dedent(`
+			Create a new VM template from the given VMTemplate manifest. The spec
+			of a VM template has the same format as the spec of a VM, the image
+			is required. VMs are created from a template using the template flag
+			(--template) of the "create" and "run" commands, or by setting the
+			"ignite.weave.works/template" annotation in a GitOps VM manifest.
+
+			Example usage:
+				$ cat golden-dev.yaml
+				apiVersion: ignite.weave.works/v1alpha5
+				kind: VMTemplate
+				metadata:
+				  name: golden-dev
+				spec:
+				  image:
+				    oci: weaveworks/ignite-ubuntu
+				  cpus: 2
+				  memory: 2GB
+				  diskSize: 10GB
+				  ssh: true
+				$ ignite template create golden-dev.yaml
+				$ ignite run --template golden-dev --name my-vm
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				co, err := run.NewCreateTemplateOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.CreateTemplate(co)
+			}())
+		},
+	}
+
+	return cmd
+}
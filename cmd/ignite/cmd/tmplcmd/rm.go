@@ -0,0 +1,36 @@
+package tmplcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdRm removes VM templates
+func NewCmdRm(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm <template>...",
+		Short: "Remove VM templates",
+		Long: dedent.Dedent(`
+			Remove one or multiple VM templates. Templates are matched by prefix based on
+			their ID and name. To remove multiple templates, chain the matches separated by
+			spaces. VMs created from a template are not affected by its removal.
+		`),
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				ro, err := run.NewRmTemplateOptions(args)
+				if err != nil {
+					return err
+				}
+
+				return run.RmTemplate(ro)
+			}())
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+package tmplcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdTemplate handles VM template-related functionality via its subcommands
+// This command by itself lists available VM templates
+func NewCmdTemplate(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage reusable VM templates",
+		Long: dedent.Dedent(`
+			Groups together functionality for managing VM templates.
+			Calling this command alone lists all available VM templates.
+		`),
+		Aliases: []string{"templates"},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				to, err := run.NewTemplatesOptions()
+				if err != nil {
+					return err
+				}
+
+				return run.Templates(to)
+			}())
+		},
+	}
+
+	cmd.AddCommand(NewCmdCreate(out))
+	cmd.AddCommand(NewCmdLs(out))
+	cmd.AddCommand(NewCmdRm(out))
+	return cmd
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+	networkflag "github.com/weaveworks/ignite/pkg/network/flag"
+	"github.com/weaveworks/ignite/pkg/providers"
+	runtimeflag "github.com/weaveworks/ignite/pkg/runtime/flag"
+)
+
+// NewCmdUp creates and starts the VM of the project of the current directory
+func NewCmdUp(out io.Writer) *cobra.Command {
+	pf := &run.ProjectFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Create and start the VM of the project in the current directory",
+		Long: dedent.Dedent(`
+			Create and start the VM described by the project file of the current
+			directory, an Ignitefile or ignite.yaml looked up in the current directory
+			and then in its parents:
+
+				name: dev
+				image: weaveworks/ignite-ubuntu:latest
+				cpus: 2
+				memory: 2GB
+				diskSize: 10GB
+				ports: ["8080:80"]
+				shares:
+				- hostPath: ./src
+				  vmPath: /src
+				provision:
+				- shell: apt-get update && apt-get install -y nginx
+				- script: ./provision.sh
+
+			The VM is named after the directory of the project if no name is given, and
+			gets an SSH key to use "ignite ssh" without a VM. The shares are copied into
+			the VM when it's created, and again by every later "ignite up". The
+			provisioners are run in order when the VM is created, and again with the
+			provision flag (--provision).
+
+			If the project file changed, the VM is updated like "ignite apply", which
+			requires it to be stopped with "ignite halt" first.
+
+			Example usage:
+				$ ignite up
+				$ ignite up --provision
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				po, err := pf.NewProjectOptions()
+				if err != nil {
+					return err
+				}
+
+				return run.Up(po, cmd.Flags())
+			}())
+		},
+	}
+
+	cmd.Flags().BoolVar(&pf.Provision, "provision", false, "Run the provisioners even if the VM already exists")
+	runtimeflag.RuntimeVar(cmd.Flags(), &providers.RuntimeName)
+	networkflag.NetworkPluginVar(cmd.Flags(), &providers.NetworkPluginName)
+	return cmd
+}
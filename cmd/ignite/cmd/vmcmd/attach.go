@@ -5,12 +5,15 @@ import (
 
 	"github.com/lithammer/dedent"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
 	"github.com/weaveworks/ignite/cmd/ignite/run"
 )
 
 // NewCmdAttach attaches to a running VM
 func NewCmdAttach(out io.Writer) *cobra.Command {
+	af := &run.AttachFlags{}
+
 	cmd := &cobra.Command{
 		Use:   "attach <vm>",
 		Short: "Attach to a running VM",
@@ -18,11 +21,21 @@ func NewCmdAttach(out io.Writer) *cobra.Command {
 			Connect the current terminal to the running VM's TTY.
 			To detach from the VM's TTY, type ^P^Q (Ctrl + P + Q).
 			The given VM is matched by prefix based on its ID and name.
+
+			Multiple terminals can be attached to a VM at the same time. They all
+			see the console output, but only one of them can type into the console.
+			The others attach with the read-only flag (--read-only), which observes
+			the VM without sending any input to it. Read-only sessions also detach
+			with ^C (Ctrl + C).
+
+			Example usage:
+				$ ignite attach my-vm
+				$ ignite attach my-vm --read-only
 		`),
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
-				ao, err := run.NewAttachOptions(args[0])
+				ao, err := af.NewAttachOptions(args[0])
 				if err != nil {
 					return err
 				}
@@ -32,5 +45,10 @@ func NewCmdAttach(out io.Writer) *cobra.Command {
 		},
 	}
 
+	addAttachFlags(cmd.Flags(), af)
 	return cmd
 }
+
+func addAttachFlags(fs *pflag.FlagSet, af *run.AttachFlags) {
+	fs.BoolVar(&af.ReadOnly, "read-only", af.ReadOnly, "Observe the console without sending input to the VM")
+}
@@ -0,0 +1,56 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdAutostart starts the VMs marked for autostart
+func NewCmdAutostart(out io.Writer) *cobra.Command {
+	af := &run.AutostartFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "autostart",
+		Short: "Start the VMs marked for autostart",
+		Long: dedent.Dedent(`
+			Start the VMs created with the autostart flag (--autostart), or with
+			spec.autostart set in their manifest, that aren't running. This is run
+			by ignited when it starts up, hosts without ignited can run it from a
+			systemd unit at boot instead.
+
+			The VMs are started in dependency order: the VMs listed by a VM with the
+			autostart-after flag (--autostart-after), or in spec.autostartAfter, are
+			started before it, even if they aren't marked for autostart themselves.
+			VMs depending on a VM that failed to start aren't started. The order can
+			be checked with the list flag (-l, --list).
+
+			Example usage:
+				$ ignite create weaveworks/ignite-ubuntu --name db --autostart
+				$ ignite create weaveworks/ignite-ubuntu --name app --autostart --autostart-after db
+				$ ignite vm autostart --list
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				ao, err := af.NewAutostartOptions()
+				if err != nil {
+					return err
+				}
+
+				return run.Autostart(ao)
+			}())
+		},
+	}
+
+	addAutostartFlags(cmd.Flags(), af)
+	return cmd
+}
+
+func addAutostartFlags(fs *pflag.FlagSet, af *run.AutostartFlags) {
+	fs.BoolVarP(&af.List, "list", "l", false, "List the VMs to autostart in the order they're started in, without starting them")
+}
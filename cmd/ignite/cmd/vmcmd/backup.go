@@ -0,0 +1,82 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdBackup handles the backups of VMs via its subcommands
+func NewCmdBackup(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Manage the backups of VMs",
+		Long: dedent.Dedent(`
+			Groups together functionality for managing the backups of VM disks.
+			VMs are backed up by ignited according to the backup policy in their
+			spec (spec.backup), which defines the cron schedule of the backups,
+			how many backups to keep, and where to store them.
+		`),
+	}
+
+	cmd.AddCommand(newCmdBackupList(out))
+	cmd.AddCommand(newCmdBackupRestore(out))
+	return cmd
+}
+
+func newCmdBackupList(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <vm>",
+		Short: "List the backups of a VM",
+		Long: dedent.Dedent(`
+			List the backups of a VM stored in the destination of its backup policy,
+			oldest first. The VM is matched by prefix based on its ID and name.
+		`),
+		Aliases: []string{"ls"},
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				blo, err := run.NewBackupListOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.BackupList(blo)
+			}())
+		},
+	}
+
+	return cmd
+}
+
+func newCmdBackupRestore(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <vm> <backup>",
+		Short: "Restore the disk of a VM from a backup",
+		Long: dedent.Dedent(`
+			Restore the disk of a stopped VM from one of its backups, as listed by
+			"ignite vm backup list". The VM is matched by prefix based on its ID and
+			name. All changes made to the disk since the backup are lost.
+
+			Example usage:
+				$ ignite vm backup list my-vm
+				$ ignite vm backup restore my-vm 20210301T023000Z.tar.gz
+		`),
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				bro, err := run.NewBackupRestoreOptions(args[0], args[1])
+				if err != nil {
+					return err
+				}
+
+				return run.BackupRestore(bro)
+			}())
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,60 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdCheckpoint takes checkpoints of the disks of VMs
+func NewCmdCheckpoint(out io.Writer) *cobra.Command {
+	cf := &run.CheckpointFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "checkpoint <vm> [checkpoint]",
+		Short: "Take a checkpoint of the disk of a VM",
+		Long: dedent.Dedent(`
+			Save the current state of the disk of a stopped VM as a checkpoint, which
+			the VM can later be rolled back to with "ignite vm rollback". The VM is
+			matched by prefix based on its ID and name. The checkpoint is named by
+			the current time, unless a name is given.
+
+			Checkpoints are stored in the VM directory and removed together with the
+			VM. The list flag (-l, --list) lists the checkpoints of the VM, and the
+			remove flag (--rm) removes the given checkpoint instead.
+
+			Example usage:
+				$ ignite vm checkpoint my-vm clean-install
+				$ ignite vm checkpoint --list my-vm
+				$ ignite vm checkpoint --rm my-vm clean-install
+		`),
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				var checkpoint string
+				if len(args) == 2 {
+					checkpoint = args[1]
+				}
+
+				co, err := cf.NewCheckpointOptions(args[0], checkpoint)
+				if err != nil {
+					return err
+				}
+
+				return run.Checkpoint(co)
+			}())
+		},
+	}
+
+	addCheckpointFlags(cmd.Flags(), cf)
+	return cmd
+}
+
+func addCheckpointFlags(fs *pflag.FlagSet, cf *run.CheckpointFlags) {
+	fs.BoolVarP(&cf.List, "list", "l", false, "List the checkpoints of the VM")
+	fs.BoolVar(&cf.Remove, "rm", false, "Remove the given checkpoint of the VM")
+}
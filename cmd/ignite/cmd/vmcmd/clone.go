@@ -0,0 +1,55 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdClone clones VMs
+func NewCmdClone(out io.Writer) *cobra.Command {
+	cf := &run.CloneFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "clone <vm>",
+		Short: "Clone a VM into a new VM",
+		Long: dedent.Dedent(`
+			Create a new VM with the configuration and a copy of the disk of a stopped
+			VM, for example to use a prepared VM as a template for others. The VM is
+			matched by prefix based on its ID and name. The clone is named randomly,
+			unless a name is given with the name flag (-n, --name).
+
+			The clone gets its own identity: a new ID and hostname, new SSH host keys,
+			a new machine ID and, if the source VM generated one, a new SSH key pair.
+			Its MAC and IP addresses are assigned when it's started. The blocks of the
+			disk that the source VM didn't change stay shared with the image, if the
+			snapshotter supports it.
+
+			Example usage:
+				$ ignite vm clone my-template --name my-vm
+				$ ignite vm start my-vm
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				co, err := cf.NewCloneOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.Clone(co)
+			}())
+		},
+	}
+
+	addCloneFlags(cmd.Flags(), cf)
+	return cmd
+}
+
+func addCloneFlags(fs *pflag.FlagSet, cf *run.CloneFlags) {
+	cmdutil.AddNameFlag(fs, &cf.Name)
+}
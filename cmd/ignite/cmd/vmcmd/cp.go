@@ -18,15 +18,25 @@ func NewCmdCP(out io.Writer) *cobra.Command {
 		Use:   "cp <source> <dest>",
 		Short: "Copy files/folders between a running vm and the local filesystem",
 		Long: dedent.Dedent(`
-			Copy a file between host and a running VM.
+			Copy files and directories between host and a running VM.
 			Creates an SFTP connection to the running VM using the private key created for
 			it during generation, and transfers files between the host and VM. If no
 			private key was created or wanting to use a different identity file, use the
 			identity file flag (-i, --identity) to override the used identity file.
 
+			The VM side is given as <vm>:<path>, where the VM is matched by prefix based
+			on its ID and name. Directories are copied recursively. If the destination is
+			an existing directory, the source is copied into it.
+
+			Using the agent flag (--agent), files and directories are copied using the guest agent
+			instead of SFTP, for VMs based on images imported with the agent. The
+			path in the VM needs to be absolute.
+
 			Example usage:
 				$ ignite cp localfile.txt my-vm:remotefile.txt
 				$ ignite cp my-vm:remotefile.txt localfile.txt
+				$ ignite cp ./project my-vm:/root/
+				$ ignite cp my-vm:/var/log ./vm-logs
 		`),
 		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -47,4 +57,5 @@ func NewCmdCP(out io.Writer) *cobra.Command {
 
 func addCPFlags(fs *pflag.FlagSet, cf *run.CPFlags) {
 	cmdutil.AddSSHFlags(fs, &cf.IdentityFile, &cf.Timeout)
+	fs.BoolVar(&cf.Agent, "agent", false, "Copy the files using the guest agent instead of SFTP")
 }
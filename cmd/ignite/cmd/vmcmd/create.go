@@ -13,6 +13,7 @@ import (
 	networkflag "github.com/weaveworks/ignite/pkg/network/flag"
 	"github.com/weaveworks/ignite/pkg/providers"
 	runtimeflag "github.com/weaveworks/ignite/pkg/runtime/flag"
+	snapshotterflag "github.com/weaveworks/ignite/pkg/snapshotter/flag"
 	"github.com/weaveworks/ignite/pkg/version"
 )
 
@@ -37,6 +38,11 @@ func NewCmdCreate(out io.Writer) *cobra.Command {
 			can be added to the VM during creation with the syntax
 			/host/path:/vm/path.
 
+			Using the template flag (--template), the VM is based on
+			the given VM template instead of the default configuration.
+			The image argument, config file and flags override the
+			configuration of the template.
+
 			Example usage:
 				$ ignite create weaveworks/ignite-ubuntu \
 					--name my-vm \
@@ -44,6 +50,7 @@ func NewCmdCreate(out io.Writer) *cobra.Command {
 					--ssh \
 					--memory 2GB \
 					--size 6GB
+				$ ignite create --template golden-dev --name my-vm
 		`, version.GetIgnite().KernelImage.String())),
 		Args: cobra.RangeArgs(0, 1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -59,6 +66,7 @@ func NewCmdCreate(out io.Writer) *cobra.Command {
 	}
 
 	addCreateFlags(cmd.Flags(), cf)
+	cmdutil.AddResultOutputFlag(cmd.Flags(), &cf.Output)
 	return cmd
 }
 
@@ -66,27 +74,44 @@ func addCreateFlags(fs *pflag.FlagSet, cf *run.CreateFlags) {
 	// Register common flags
 	cmdutil.AddNameFlag(fs, &cf.VM.ObjectMeta.Name)
 	cmdutil.AddConfigFlag(fs, &cf.ConfigFile)
+	fs.StringVar(&cf.Template, "template", cf.Template, "Use the spec of the given VM template as the base configuration of the VM")
 
 	// Register flags bound to temporary holder values
 	fs.StringSliceVarP(&cf.PortMappings, "ports", "p", cf.PortMappings, "Map host ports to VM ports")
 	fs.StringSliceVarP(&cf.CopyFiles, "copy-files", "f", cf.CopyFiles, "Copy files/directories from the host to the created VM")
+	fs.StringSliceVar(&cf.Tmpfs, "tmpfs", cf.Tmpfs, "Mount a RAM-backed scratch volume inside the VM, in the /vm/path[:size] format")
 
 	// Register flags for simple types (int, string, etc.)
 	fs.Uint64Var(&cf.VM.Spec.CPUs, "cpus", cf.VM.Spec.CPUs, "VM vCPU count, 1 or even numbers between 1 and 32")
 	fs.StringVar(&cf.VM.Spec.Kernel.CmdLine, "kernel-args", cf.VM.Spec.Kernel.CmdLine, "Set the command line for the kernel")
 	fs.StringArrayVarP(&cf.Labels, "label", "l", cf.Labels, "Set a label (foo=bar)")
+	fs.StringArrayVarP(&cf.Env, "env", "e", cf.Env, "Set an environment variable in the VM (FOO=bar), FOO alone takes the value from the host")
+	fs.StringArrayVar(&cf.Secrets, "secret", cf.Secrets, "Deliver a secret from the host to /run/ignite/secrets in the VM at boot (name=file:/path, name=env:NAME or name=vault:path#field), needs the guest agent")
+	fs.StringArrayVar(&cf.Sysctls, "sysctl", cf.Sysctls, "Set a kernel parameter in the VM at boot (fs.inotify.max_user_watches=524288)")
 	fs.BoolVar(&cf.RequireName, "require-name", cf.RequireName, "Require VM name to be passed, no name generation")
+	fs.BoolVar(&cf.VM.Spec.Storage.ReadOnlyRoot, "read-only-root", cf.VM.Spec.Storage.ReadOnlyRoot, "Attach the root filesystem of the VM read-only")
+	fs.StringSliceVar(&cf.VM.Spec.Storage.WritablePaths, "writable-path", cf.VM.Spec.Storage.WritablePaths, "Mount a RAM-backed writable path in a VM with a read-only root (default /tmp, /var/tmp and /var/log)")
+	fs.BoolVar(&cf.VM.Spec.Autostart, "autostart", cf.VM.Spec.Autostart, "Start the VM automatically when the host boots")
+	fs.StringSliceVar(&cf.VM.Spec.AutostartAfter, "autostart-after", cf.VM.Spec.AutostartAfter, "VMs that need to be running before the VM is autostarted")
+	fs.StringVar((*string)(&cf.VM.Spec.MemoryHugepages), "memory-hugepages", string(cf.VM.Spec.MemoryHugepages), "Back the VM's memory with hugepages reserved on the host: 2Mi or 1Gi")
+	fs.StringVar((*string)(&cf.VM.Spec.RestartPolicy), "restart", string(cf.VM.Spec.RestartPolicy), "Restart policy enforced by ignited when the VM stops: always, on-failure or never")
+	fs.BoolVar(&cf.VM.Spec.NestedVirtualization, "nested-virtualization", cf.VM.Spec.NestedVirtualization, "Expose the virtualization extensions of the CPU to the VM to run a hypervisor in it, the VM runs with QEMU")
+	fs.StringArrayVar(&cf.Devices, "device", cf.Devices, "Pass a host PCI device through into the VM with VFIO, by its address (0000:01:00.0), the VM runs with QEMU")
+	fs.StringVar(&cf.VM.Spec.Firmware, "firmware", cf.VM.Spec.Firmware, "UEFI firmware on the host to boot a disk image with (default: OVMF or AAVMF of the sandbox)")
 
 	// Register more complex flags with their own flag types
 	cmdutil.SizeVar(fs, &cf.VM.Spec.Memory, "memory", "Amount of RAM to allocate for the VM")
 	cmdutil.SizeVarP(fs, &cf.VM.Spec.DiskSize, "size", "s", "VM filesystem size, for example 5GB or 2048MB")
+	cmdutil.SizeVar(fs, &cf.VM.Spec.OverlaySizeLimit, "overlay-size-limit", "Maximum host disk space the VM's writable overlay may use, for example 2GB")
 	cmdutil.OCIImageRefVarP(fs, &cf.VM.Spec.Kernel.OCI, "kernel-image", "k", "Specify an OCI image containing the kernel at /boot/vmlinux and optionally, modules")
+	cmdutil.OCIImageRefVar(fs, &cf.Initrd, "initrd", "Specify an OCI image containing an initramfs at /boot/initrd, booted instead of the initrd of the kernel image")
 	cmdutil.OCIImageRefVar(fs, &cf.VM.Spec.Sandbox.OCI, "sandbox-image", "Specify an OCI image for the VM sandbox")
 	cmdutil.SSHVar(fs, &cf.SSH)
 	cmdutil.VolumeVarP(fs, &cf.VM.Spec.Storage, "volumes", "v", "Expose block devices from the host inside the VM")
 
 	runtimeflag.RuntimeVar(fs, &providers.RuntimeName)
 	networkflag.NetworkPluginVar(fs, &providers.NetworkPluginName)
+	snapshotterflag.SnapshotterVar(fs, &providers.SnapshotterName)
 	cmdutil.AddIDPrefixFlag(fs, &providers.IDPrefix)
 	cmdutil.AddRegistryConfigDirFlag(fs, &providers.RegistryConfigDir)
 }
@@ -0,0 +1,44 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdEdit edits the manifests of stopped VMs
+func NewCmdEdit(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit <vm>",
+		Short: "Edit the configuration of a stopped VM",
+		Long: dedent.Dedent(`
+			Open the manifest of a stopped VM in the editor given by $EDITOR (vi by
+			default), and save the changes once the editor exits. The VM is matched
+			by prefix based on its ID and name. The changes are applied when the VM
+			is started the next time.
+
+			The image, kernel, name and ID of the VM can't be changed, and changes to
+			its status are discarded. If the disk size is increased, the disk is
+			grown together with the ext4 filesystem on it, it can't be shrunk.
+
+			Example usage:
+				$ EDITOR=nano ignite vm edit my-vm
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				eo, err := run.NewEditOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.Edit(eo)
+			}())
+		},
+	}
+
+	return cmd
+}
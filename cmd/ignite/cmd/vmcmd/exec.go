@@ -18,10 +18,23 @@ func NewCmdExec(out io.Writer, err io.Writer, in io.Reader) *cobra.Command {
 		Use:   "exec <vm> <command...>",
 		Short: "execute a command in a running VM",
 		Long: dedent.Dedent(`
-			Execute a command in a running VM using SSH and the private key created for it during generation.
-			If no private key was created or wanting to use a different identity file,
-			use the identity file flag (-i, --identity) to override the used identity file.
-			The given VM is matched by prefix based on its ID and name.
+			Execute a command in a running VM. The given VM is matched by prefix based on
+			its ID and name. The command is executed using the guest agent of the VM if it
+			has one (see "ignite image import --agent"), which doesn't need the VM to have
+			networking or an SSH server. Otherwise, SSH and the private key created for the
+			VM during generation are used. If no private key was created or wanting to use
+			a different identity file, use the identity file flag (-i, --identity) to
+			override the used identity file.
+
+			The agent flag (--agent) requires the guest agent to be used, and waits for it
+			to start for the given timeout. The exit code of ignite is the exit code of the
+			command. The command gets the environment variables of the VM (spec.env), and
+			more are set for it using the env flag (-e, --env).
+
+			Example usage:
+				$ ignite exec my-vm uname -a
+				$ ignite exec -e FOO=bar my-vm sh -c 'echo $FOO'
+				$ ignite exec --agent -t my-vm bash
 		`),
 		Args: cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -43,4 +56,6 @@ func NewCmdExec(out io.Writer, err io.Writer, in io.Reader) *cobra.Command {
 func addExecFlags(fs *pflag.FlagSet, ef *run.ExecFlags) {
 	cmdutil.AddSSHFlags(fs, &ef.IdentityFile, &ef.Timeout)
 	fs.BoolVarP(&ef.Tty, "tty", "t", false, "Allocate a pseudo-TTY")
+	fs.BoolVar(&ef.Agent, "agent", false, "Require the guest agent to execute the command, instead of falling back to SSH")
+	fs.StringArrayVarP(&ef.Env, "env", "e", nil, "Set an environment variable for the command (KEY=value), can be given multiple times")
 }
@@ -0,0 +1,54 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdExport exports a VM as a portable archive
+func NewCmdExport(out io.Writer) *cobra.Command {
+	ef := &run.ExportFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "export <vm>",
+		Short: "Export a VM as a portable archive",
+		Long: dedent.Dedent(`
+			Export a VM as a portable archive, from which it can be recreated on
+			another host with "ignite vm import". The VM is matched by prefix based
+			on its ID and name. The archive contains the VM specification and
+			metadata, which references its image and kernel, the SSH keys generated
+			for the VM, and the contents of its disk. The archive is written to stdout,
+			unless an output file is given with the output flag (-o, --output).
+
+			Stop the VM before exporting it, the disk of a running VM is exported
+			while it's in use.
+
+			Example usage:
+				$ ignite vm export my-vm -o my-vm.tgz
+				$ ignite vm export my-vm | ssh other-host ignite vm import -
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				eo, err := ef.NewExportOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.ExportVM(eo)
+			}())
+		},
+	}
+
+	addExportFlags(cmd.Flags(), ef)
+	return cmd
+}
+
+func addExportFlags(fs *pflag.FlagSet, ef *run.ExportFlags) {
+	fs.StringVarP(&ef.Output, "output", "o", "", "Write the archive to a file instead of stdout")
+}
@@ -0,0 +1,50 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdHibernate saves the state of running VMs to disk and stops them
+func NewCmdHibernate(out io.Writer) *cobra.Command {
+	hf := &run.HibernateFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "hibernate <vm>...",
+		Short: "Save the state of running VMs to disk and stop them",
+		Long: dedent.Dedent(`
+			Suspend one or multiple running VMs to disk. The VMs are matched by prefix
+			based on their ID and name. To hibernate multiple VMs, chain the matches
+			separated by spaces, or select them by label with the selector flag
+			(-l, --selector).
+
+			The VM is paused, and a Firecracker snapshot of its memory and devices is
+			written to the VM directory, taking up as much disk space as the memory of
+			the VM. The VM is then stopped, releasing its CPUs and memory. The next
+			"ignite start" restores the snapshot, resuming the VM where it left off
+			instead of booting it. Snapshots require Firecracker v0.24 or newer in the
+			sandbox image of the VM.
+
+			Example usage:
+				$ ignite vm hibernate my-vm
+				$ ignite start my-vm
+		`),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				ho, err := hf.NewHibernateOptions(args)
+				if err != nil {
+					return err
+				}
+
+				return run.Hibernate(ho)
+			}())
+		},
+	}
+
+	cmdutil.AddSelectorFlag(cmd.Flags(), &hf.Selector)
+	return cmd
+}
@@ -0,0 +1,51 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdImport imports a VM from a portable archive
+func NewCmdImport(out io.Writer) *cobra.Command {
+	ivf := &run.ImportVMFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "import <archive>",
+		Short: "Import a VM from a portable archive",
+		Long: dedent.Dedent(`
+			Recreate a VM exported with "ignite vm export" on this host. Use "-" to
+			read the archive from stdin. The image and kernel of the VM are imported
+			if they don't exist on this host, and its disk is restored from the
+			archive using the snapshotter of this host. The VM keeps its ID unless
+			it's already in use, its name can be changed with the name flag (-n, --name).
+
+			Example usage:
+				$ ignite vm import my-vm.tgz
+				$ ignite vm import --name my-vm-copy my-vm.tgz
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				ivo, err := ivf.NewImportVMOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.ImportVM(ivo)
+			}())
+		},
+	}
+
+	addImportFlags(cmd.Flags(), ivf)
+	return cmd
+}
+
+func addImportFlags(fs *pflag.FlagSet, ivf *run.ImportVMFlags) {
+	cmdutil.AddNameFlag(fs, &ivf.Name)
+	cmdutil.AddResultOutputFlag(fs, &ivf.Output)
+}
@@ -11,18 +11,20 @@ import (
 
 // NewCmdKill kills running VMs
 func NewCmdKill(out io.Writer) *cobra.Command {
+	sf := &run.StopFlags{Kill: true}
+
 	cmd := &cobra.Command{
 		Use:   "kill <vm>...",
 		Short: "Kill running VMs",
 		Long: dedent.Dedent(`
 			Kill (force stop) one or multiple VMs. The VMs are matched by prefix based
 			on their ID and name. To kill multiple VMs, chain the matches separated
-			by spaces.
+			by spaces, or select them by label with the selector flag (-l, --selector).
+			The parallel flag (--parallel) kills multiple VMs at once.
 		`),
-		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
-				so, err := (&run.StopFlags{Kill: true}).NewStopOptions(args)
+				so, err := sf.NewStopOptions(args)
 				if err != nil {
 					return err
 				}
@@ -32,5 +34,7 @@ func NewCmdKill(out io.Writer) *cobra.Command {
 		},
 	}
 
+	cmdutil.AddSelectorFlag(cmd.Flags(), &sf.Selector)
+	cmdutil.AddParallelFlag(cmd.Flags(), &sf.Parallel)
 	return cmd
 }
@@ -5,23 +5,37 @@ import (
 
 	"github.com/lithammer/dedent"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
 	"github.com/weaveworks/ignite/cmd/ignite/run"
 )
 
 // NewCmdLogs gets the logs for a VM
 func NewCmdLogs(out io.Writer) *cobra.Command {
+	lf := &run.LogsFlags{}
+
 	cmd := &cobra.Command{
 		Use:   "logs <vm>",
-		Short: "Get the logs for a running VM",
+		Short: "Get the console logs of a VM",
 		Long: dedent.Dedent(`
-			Show the logs for the given VM. The VM needs to be running (its backing
-			container needs to exist). The VM is matched by prefix based on its ID and name.
+			Show the console output of the given VM. The VM is matched by prefix based
+			on its ID and name. The output is recorded in the VM directory while the VM
+			runs, so the logs of stopped VMs, including their previous boots, are
+			available as well.
+
+			The output can be filtered to the lines written after a time with the since
+			flag (--since), which takes a duration like 10m or an RFC 3339 timestamp,
+			and to the last lines with the tail flag (-n, --tail). The follow flag
+			(-f, --follow) keeps streaming the output of a running VM until it stops.
+
+			Example usage:
+				$ ignite logs my-vm --since 10m
+				$ ignite logs my-vm --tail 20 --follow
 		`),
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
-				lo, err := run.NewLogsOptions(args[0])
+				lo, err := lf.NewLogsOptions(args[0])
 				if err != nil {
 					return err
 				}
@@ -31,5 +45,13 @@ func NewCmdLogs(out io.Writer) *cobra.Command {
 		},
 	}
 
+	addLogsFlags(cmd.Flags(), lf)
 	return cmd
 }
+
+func addLogsFlags(fs *pflag.FlagSet, lf *run.LogsFlags) {
+	fs.BoolVarP(&lf.Follow, "follow", "f", false, "Follow the output of a running VM until it stops")
+	fs.StringVar(&lf.Since, "since", "", "Only show the output written after the given time, a duration like 10m or an RFC 3339 timestamp")
+	fs.IntVarP(&lf.Tail, "tail", "n", -1, "Number of lines to show from the end of the output, all by default")
+	fs.BoolVarP(&lf.Timestamps, "timestamps", "t", false, "Show the time every line was written at")
+}
@@ -0,0 +1,40 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdMetrics shows the resource usage of VMs
+func NewCmdMetrics(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics <vm>...",
+		Short: "Show the resource usage inside of running VMs",
+		Long: dedent.Dedent(`
+			Show the uptime, load, memory and disk usage inside of one or multiple
+			running VMs, as reported by their guest agents. The images of the VMs
+			need to have been imported with "ignite image import --agent". The VMs
+			are matched by prefix based on their ID and name.
+
+			Example usage:
+				$ ignite vm metrics my-vm
+		`),
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				mo, err := run.NewMetricsOptions(args)
+				if err != nil {
+					return err
+				}
+
+				return run.Metrics(mo)
+			}())
+		},
+	}
+
+	return cmd
+}
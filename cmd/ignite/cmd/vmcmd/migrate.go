@@ -0,0 +1,62 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdMigrate migrates running VMs to other hosts
+func NewCmdMigrate(out io.Writer) *cobra.Command {
+	mf := &run.MigrateFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "migrate <vm> --to <host>",
+		Short: "Migrate a running VM to another host",
+		Long: dedent.Dedent(`
+			Move a running VM to another host without rebooting it. The VM is matched
+			by prefix based on its ID and name. The VM is paused and a Firecracker
+			snapshot of its memory and devices is taken. The snapshot is transferred
+			together with the VM and its disk to the destination host (--to), where
+			the VM is imported and restored with "ignite vm import" and
+			"ignite vm start" over SSH. The VM is then removed from this host. If the
+			migration fails, the VM is resumed on this host.
+
+			The destination host is given as an SSH destination ([user@]host), SSH
+			needs to authenticate without prompting, and ignite needs to be installed
+			on the destination host. Snapshots require Firecracker v0.24 or newer in
+			the sandbox image of the VM.
+
+			If the network of the destination host assigns the VM other addresses, the
+			guest agent changes the address of the guest to the assigned one after the
+			restore. Without the agent, the VM is stopped on the destination host and
+			the migration fails, so VMs without the agent can only be migrated between
+			hosts whose networks assign them the same addresses.
+
+			Example usage:
+				$ ignite vm migrate my-vm --to root@other-host
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				mo, err := mf.NewMigrateOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.Migrate(mo)
+			}())
+		},
+	}
+
+	addMigrateFlags(cmd.Flags(), mf)
+	return cmd
+}
+
+func addMigrateFlags(fs *pflag.FlagSet, mf *run.MigrateFlags) {
+	fs.StringVar(&mf.To, "to", "", "SSH destination ([user@]host) of the host to migrate the VM to")
+}
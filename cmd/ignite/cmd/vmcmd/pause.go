@@ -0,0 +1,40 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdPause pauses running VMs
+func NewCmdPause(out io.Writer) *cobra.Command {
+	pf := &run.PauseFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "pause <vm>...",
+		Short: "Pause running VMs",
+		Long: dedent.Dedent(`
+			Pause one or multiple running VMs. The VMs are matched by prefix based on
+			their ID and name. To pause multiple VMs, chain the matches separated by
+			spaces, or select them by label with the selector flag (-l, --selector).
+			The vCPUs of paused VMs are frozen, while their memory and device
+			state are kept, until they're resumed with "ignite vm resume".
+		`),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				po, err := pf.NewPauseOptions(args)
+				if err != nil {
+					return err
+				}
+
+				return run.Pause(po)
+			}())
+		},
+	}
+
+	cmdutil.AddSelectorFlag(cmd.Flags(), &pf.Selector)
+	return cmd
+}
@@ -23,7 +23,8 @@ func NewCmdPs(out io.Writer) *cobra.Command {
 			also list VMs that are not currently running.
 			Using the -f (--filter) flag, you can give conditions VMs should fullfilled to be displayed.
 			You can filter on all the underlying fields of the VM struct, see the documentation:
-			https://ignite.readthedocs.io/en/stable/api/ignite_v1alpha4#VM.
+			https://ignite.readthedocs.io/en/stable/api/ignite_v1alpha5#VM.
+			The selector flag (-l, --selector) selects the VMs by their labels.
 
 			Different operators can be used:
 			- "=" and "==" for the equal
@@ -39,10 +40,21 @@ func NewCmdPs(out io.Writer) *cobra.Command {
 			- Kernel
 			- Memory
 
+			The output flag (-o, --output) selects the format of the list: "table" (default),
+			"json", "yaml", "go-template=<template>" rendering the Go template for every VM,
+			or "custom-columns=<header>:<field path>,..." printing a table of the given
+			fields. The field paths reference the fields of the VM struct like the filters.
+
 			Example usage:
 				$ ignite ps -f "{{.ObjectMeta.Name}}=my-vm2,{{.Spec.CPUs}}!=3,{{.Spec.Image.OCI}}=~weaveworks/ignite-ubuntu"
 
 				$ ignite ps -f "{{.Spec.Memory}}=~1024,{{.Status.Running}}=true"
+
+				$ ignite ps -a -l app=ci
+
+				$ ignite ps -a -o json
+
+				$ ignite ps -o "custom-columns=NAME:.ObjectMeta.Name,IPS:.Status.Network.IPAddresses"
 		`),
 		Run: func(cmd *cobra.Command, args []string) {
 			// If `ps` is called via any of its aliases
@@ -69,5 +81,7 @@ func NewCmdPs(out io.Writer) *cobra.Command {
 func addPsFlags(fs *pflag.FlagSet, pf *run.PsFlags) {
 	fs.BoolVarP(&pf.All, "all", "a", false, "Show all VMs, not just running ones")
 	fs.StringVarP(&pf.Filter, "filter", "f", "", "Filter the VMs")
+	cmdutil.AddSelectorFlag(fs, &pf.Selector)
 	fs.StringVarP(&pf.TemplateFormat, "template", "t", "", "Format the output using the given Go template")
+	cmdutil.AddOutputFlag(fs, &pf.Output)
 }
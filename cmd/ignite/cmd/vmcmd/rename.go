@@ -0,0 +1,45 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdRename changes the name of a VM
+func NewCmdRename(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename <vm> <name>",
+		Short: "Change the name of a VM",
+		Long: dedent.Dedent(`
+			Change the name of a VM. The VM is matched by prefix based on its ID and
+			name, the new name needs to be unused by other VMs. The ID of the VM, which
+			is also its hostname, doesn't change.
+
+			The autostart dependencies and host annotations of other VMs referring to
+			the VM by name are updated. The container of a running VM keeps the old
+			name in its labels until the VM is restarted. The VMs of "ignite compose"
+			stacks and "ignite up" projects are named by their files, and can't be
+			renamed.
+
+			Example usage:
+				$ ignite vm rename my-vm web
+		`),
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				ro, err := run.NewRenameOptions(args[0], args[1])
+				if err != nil {
+					return err
+				}
+
+				return run.Rename(ro)
+			}())
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdResume resumes paused VMs
+func NewCmdResume(out io.Writer) *cobra.Command {
+	rf := &run.ResumeFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "resume <vm>...",
+		Short: "Resume paused VMs",
+		Long: dedent.Dedent(`
+			Resume one or multiple VMs paused with "ignite vm pause". The VMs are
+			matched by prefix based on their ID and name. To resume multiple VMs,
+			chain the matches separated by spaces, or select them by label with the
+			selector flag (-l, --selector).
+		`),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				ro, err := rf.NewResumeOptions(args)
+				if err != nil {
+					return err
+				}
+
+				return run.Resume(ro)
+			}())
+		},
+	}
+
+	cmdutil.AddSelectorFlag(cmd.Flags(), &rf.Selector)
+	return cmd
+}
@@ -20,8 +20,14 @@ func NewCmdRm(out io.Writer) *cobra.Command {
 		Long: dedent.Dedent(`
 			Remove one or multiple VMs. The VMs are matched by prefix based
 			on their ID and name. To remove multiple VMs, chain the matches
-			separated by spaces. The force flag (-f, --force) kills running
-			VMs before removal instead of throwing an error.
+			separated by spaces, or select them by label with the selector flag
+			(-l, --selector). The force flag (-f, --force) kills running VMs
+			before removal instead of throwing an error. The parallel flag
+			(--parallel) removes multiple VMs at once.
+
+			Example usage:
+				$ ignite rm my-vm
+				$ ignite rm -f -l app=ci
 		`),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
@@ -42,4 +48,6 @@ func NewCmdRm(out io.Writer) *cobra.Command {
 func addRmFlags(fs *pflag.FlagSet, rf *run.RmFlags) {
 	cmdutil.AddForceFlag(fs, &rf.Force)
 	cmdutil.AddConfigFlag(fs, &rf.ConfigFile)
+	cmdutil.AddSelectorFlag(fs, &rf.Selector)
+	cmdutil.AddParallelFlag(fs, &rf.Parallel)
 }
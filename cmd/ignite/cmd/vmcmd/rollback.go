@@ -0,0 +1,43 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdRollback rolls back the disks of VMs to checkpoints
+func NewCmdRollback(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback <vm> <checkpoint>",
+		Short: "Roll back the disk of a VM to a checkpoint",
+		Long: dedent.Dedent(`
+			Revert the disk of a stopped VM to a checkpoint taken with
+			"ignite vm checkpoint". The VM is matched by prefix based on its ID and
+			name. All changes made to the disk since the checkpoint are lost, the
+			checkpoint itself is kept.
+
+			Example usage:
+				$ ignite vm checkpoint my-vm before-upgrade
+				$ ignite vm start my-vm
+				$ ignite vm stop my-vm
+				$ ignite vm rollback my-vm before-upgrade
+		`),
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				ro, err := run.NewRollbackOptions(args[0], args[1])
+				if err != nil {
+					return err
+				}
+
+				return run.Rollback(ro)
+			}())
+		},
+	}
+
+	return cmd
+}
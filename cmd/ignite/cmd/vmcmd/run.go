@@ -34,6 +34,7 @@ func NewCmdRun(out io.Writer) *cobra.Command {
 					--ssh \
 					--memory 2GB \
 					--size 10G
+				$ ignite run --template golden-dev --interactive
 		`),
 		Args: cobra.RangeArgs(0, 1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -49,6 +50,7 @@ func NewCmdRun(out io.Writer) *cobra.Command {
 	}
 
 	addRunFlags(cmd.Flags(), rf)
+	cmdutil.AddResultOutputFlag(cmd.Flags(), &rf.StartFlags.Output)
 	return cmd
 }
 
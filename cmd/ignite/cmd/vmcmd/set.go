@@ -0,0 +1,53 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdSet changes the resources of stopped VMs
+func NewCmdSet(out io.Writer) *cobra.Command {
+	sf := &run.SetFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "set <vm>",
+		Short: "Change the resources of a stopped VM",
+		Long: dedent.Dedent(`
+			Change the vCPU count, memory or disk size of a stopped VM. The VM is
+			matched by prefix based on its ID and name. Only the given resources are
+			changed, and they're applied when the VM is started the next time.
+
+			The disk can only be grown. Its storage is extended by the snapshotter of
+			the VM, and the ext4 filesystem on it is resized to fill it before boot.
+
+			Example usage:
+				$ ignite vm set my-vm --cpus 4 --memory 4GB
+				$ ignite vm set my-vm --size 20GB
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				so, err := sf.NewSetOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.Set(so)
+			}())
+		},
+	}
+
+	addSetFlags(cmd.Flags(), sf)
+	return cmd
+}
+
+func addSetFlags(fs *pflag.FlagSet, sf *run.SetFlags) {
+	fs.Uint64Var(&sf.CPUs, "cpus", sf.CPUs, "VM vCPU count, 1 or even numbers between 1 and 32")
+	cmdutil.SizeVar(fs, &sf.Memory, "memory", "Amount of RAM to allocate for the VM")
+	cmdutil.SizeVarP(fs, &sf.DiskSize, "size", "s", "VM filesystem size, for example 5GB or 2048MB, it can only be grown")
+}
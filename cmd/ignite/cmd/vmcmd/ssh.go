@@ -15,18 +15,24 @@ func NewCmdSSH(out io.Writer) *cobra.Command {
 	sf := &run.SSHFlags{}
 
 	cmd := &cobra.Command{
-		Use:   "ssh <vm>",
+		Use:   "ssh [vm]",
 		Short: "SSH into a running vm",
 		Long: dedent.Dedent(`
 			SSH into the running VM using the private key created for it during generation.
 			If no private key was created or wanting to use a different identity file,
 			use the identity file flag (-i, --identity) to override the used identity file.
-			The given VM is matched by prefix based on its ID and name.
+			The given VM is matched by prefix based on its ID and name. Without a VM,
+			the VM of the project of the current directory created by "ignite up" is used.
 		`),
-		Args: cobra.ExactArgs(1),
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
-				so, err := sf.NewSSHOptions(args[0])
+				var vmMatch string
+				if len(args) == 1 {
+					vmMatch = args[0]
+				}
+
+				so, err := sf.NewSSHOptions(vmMatch)
 				if err != nil {
 					return err
 				}
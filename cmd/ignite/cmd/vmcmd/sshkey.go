@@ -0,0 +1,134 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdSSHKey manages the SSH keys of VMs via its subcommands
+func NewCmdSSHKey(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssh-key",
+		Short: "Manage the SSH keys authorized in VMs",
+		Long: dedent.Dedent(`
+			Groups together functionality for managing the public keys authorized for
+			root in VMs, and the SSH keypair ignite generates for "ignite ssh". The
+			authorized keys are listed in the spec of the VM (spec.ssh.authorizedKeys).
+
+			Running VMs are changed right away, using their guest agent if they have
+			one, and SSH otherwise. The disk of stopped VMs is changed directly.
+		`),
+	}
+
+	cmd.AddCommand(newCmdSSHKeyAdd(out))
+	cmd.AddCommand(newCmdSSHKeyRemove(out))
+	cmd.AddCommand(newCmdSSHKeyRotate(out))
+	return cmd
+}
+
+func newCmdSSHKeyAdd(out io.Writer) *cobra.Command {
+	sf := &run.SSHKeyFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "add <vm> <public key file|public key...>",
+		Short: "Authorize public keys in a VM",
+		Long: dedent.Dedent(`
+			Authorize the given public keys for root in a VM, and add them to the
+			authorized keys in its spec. A key is given either as a file with public
+			keys, or as a public key in the authorized_keys format. The VM is matched
+			by prefix based on its ID and name.
+
+			Example usage:
+				$ ignite vm ssh-key add my-vm ~/.ssh/id_ed25519.pub
+				$ ignite vm ssh-key add my-vm "ssh-ed25519 AAAAC3Nza... alice@laptop"
+		`),
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				so, err := sf.NewSSHKeyOptions(args[0], args[1:]...)
+				if err != nil {
+					return err
+				}
+
+				return run.SSHKeyAdd(so)
+			}())
+		},
+	}
+
+	addSSHKeyFlags(cmd.Flags(), sf)
+	return cmd
+}
+
+func newCmdSSHKeyRemove(out io.Writer) *cobra.Command {
+	sf := &run.SSHKeyFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "remove <vm> <public key file|public key...>",
+		Short: "Revoke public keys in a VM",
+		Long: dedent.Dedent(`
+			Revoke the given public keys for root in a VM, and remove them from the
+			authorized keys in its spec. Keys are matched regardless of their options
+			and comments, and are revoked even if they were authorized in the VM by
+			other means. The VM is matched by prefix based on its ID and name.
+
+			Example usage:
+				$ ignite vm ssh-key remove my-vm ~/.ssh/id_ed25519.pub
+		`),
+		Aliases: []string{"rm"},
+		Args:    cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				so, err := sf.NewSSHKeyOptions(args[0], args[1:]...)
+				if err != nil {
+					return err
+				}
+
+				return run.SSHKeyRemove(so)
+			}())
+		},
+	}
+
+	addSSHKeyFlags(cmd.Flags(), sf)
+	return cmd
+}
+
+func newCmdSSHKeyRotate(out io.Writer) *cobra.Command {
+	sf := &run.SSHKeyFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "rotate <vm>",
+		Short: "Replace the SSH keypair generated for a VM",
+		Long: dedent.Dedent(`
+			Replace the SSH keypair ignite generated for a VM (spec.ssh: true) with a
+			new one. The new public key is authorized in the VM and the old one is
+			revoked, before the private key used by "ignite ssh" is replaced. The VM
+			is matched by prefix based on its ID and name.
+
+			Example usage:
+				$ ignite vm ssh-key rotate my-vm
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				so, err := sf.NewSSHKeyOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.SSHKeyRotate(so)
+			}())
+		},
+	}
+
+	addSSHKeyFlags(cmd.Flags(), sf)
+	return cmd
+}
+
+func addSSHKeyFlags(fs *pflag.FlagSet, sf *run.SSHKeyFlags) {
+	cmdutil.AddSSHFlags(fs, &sf.IdentityFile, &sf.Timeout)
+}
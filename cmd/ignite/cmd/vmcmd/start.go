@@ -1,6 +1,7 @@
 package vmcmd
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/lithammer/dedent"
@@ -23,11 +24,30 @@ func NewCmdStart(out io.Writer) *cobra.Command {
 		Long: dedent.Dedent(`
 			Start the given VM. The VM is matched by prefix based on its ID and name.
 			If the interactive flag (-i, --interactive) is specified, attach to the
-			VM after starting.
+			VM after starting. Instead of a single VM, all the stopped VMs with
+			matching labels can be started with the selector flag (-l, --selector),
+			multiple of them at once with the parallel flag (--parallel).
+
+			Example usage:
+				$ ignite start my-vm
+				$ ignite start -l app=ci
+				$ ignite start --parallel 8 -l app=ci
 		`),
-		Args: cobra.ExactArgs(1),
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
+				if len(sf.Selector) > 0 {
+					if len(args) > 0 {
+						return fmt.Errorf("cannot use both a label selector and a vm argument")
+					}
+
+					return run.StartSelected(sf, cmd.Flags())
+				}
+
+				if len(args) != 1 {
+					return fmt.Errorf("need a vm identifier as argument, or a label selector")
+				}
+
 				so, err := sf.NewStartOptions(args[0])
 				if err != nil {
 					return err
@@ -39,6 +59,9 @@ func NewCmdStart(out io.Writer) *cobra.Command {
 	}
 
 	addStartFlags(cmd.Flags(), sf)
+	cmdutil.AddSelectorFlag(cmd.Flags(), &sf.Selector)
+	cmdutil.AddParallelFlag(cmd.Flags(), &sf.Parallel)
+	cmdutil.AddResultOutputFlag(cmd.Flags(), &sf.Output)
 
 	// NOTE: Since the run command combines the create and start command flags,
 	// to avoid redefining runtime, network, and id-prefix flags in the run command,
@@ -52,5 +75,7 @@ func NewCmdStart(out io.Writer) *cobra.Command {
 func addStartFlags(fs *pflag.FlagSet, sf *run.StartFlags) {
 	cmdutil.AddInteractiveFlag(fs, &sf.Interactive)
 	fs.BoolVarP(&sf.Debug, "debug", "d", false, "Debug mode, keep container after VM shutdown")
+	cmdutil.AddBootCacheFlag(fs, &providers.BootCache.Enabled)
+	cmdutil.AddEmulationFlag(fs, &providers.Emulation.Mode)
 	fs.StringSliceVar(&sf.IgnoredPreflightErrors, "ignore-preflight-checks", []string{}, "A list of checks whose errors will be shown as warnings. Example: 'BinaryInPath,Port,ExistingFile'. Value 'all' ignores errors from all checks.")
 }
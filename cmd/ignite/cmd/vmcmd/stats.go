@@ -0,0 +1,54 @@
+package vmcmd
+
+import (
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdStats shows the resource usage of running VMs
+func NewCmdStats(out io.Writer) *cobra.Command {
+	sf := &run.StatsFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "stats [vm]...",
+		Short: "Show the resource usage of running VMs",
+		Long: dedent.Dedent(`
+			Show the CPU, memory, disk and network usage of running VMs. The VMs are
+			matched by prefix based on their ID and name, if no VMs are given, all
+			running VMs are shown. The watch flag (-w, --watch) refreshes the usage
+			continuously.
+
+			The CPU and memory usage are those of the VM's container on the host. The
+			guest memory is the memory used inside of the VM, as reported by its balloon
+			device, which requires Firecracker v0.24 or newer, and balloon support in the
+			VM's kernel. The disk and network columns show the totals transferred by the
+			VM's drives and interfaces since it started.
+
+			Example usage:
+				$ ignite vm stats
+				$ ignite vm stats --watch my-vm
+		`),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				so, err := sf.NewStatsOptions(args)
+				if err != nil {
+					return err
+				}
+
+				return run.Stats(so)
+			}())
+		},
+	}
+
+	addStatsFlags(cmd.Flags(), sf)
+	return cmd
+}
+
+func addStatsFlags(fs *pflag.FlagSet, sf *run.StatsFlags) {
+	fs.BoolVarP(&sf.Watch, "watch", "w", false, "Refresh the usage continuously")
+}
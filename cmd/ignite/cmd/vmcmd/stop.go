@@ -21,14 +21,22 @@ func NewCmdStop(out io.Writer) *cobra.Command {
 		Short: "Stop running VMs",
 		Long: dedent.Dedent(fmt.Sprintf(`
 			Stop one or multiple VMs. The VMs are matched by prefix based on their
-			ID and name. To stop multiple VMs, chain the matches separated by spaces.
+			ID and name. To stop multiple VMs, chain the matches separated by spaces,
+			or select them by label with the selector flag (-l, --selector).
 			The force flag (-f, --force) kills VMs instead of trying to stop them
-			gracefully.
+			gracefully. The agent flag (--agent) lets the guest agent shut the VMs
+			down through their init system, the VMs' images need to have been
+			imported with "ignite image import --agent". The parallel flag
+			(--parallel) stops multiple VMs at once.
 
 			The VMs are given a %d second grace period to shut down before they
 			will be forcibly killed.
+
+			Example usage:
+				$ ignite stop my-vm
+				$ ignite stop -l app=ci
+				$ ignite stop --parallel 8 -l app=ci
 		`, constants.STOP_TIMEOUT)),
-		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(func() error {
 				so, err := sf.NewStopOptions(args)
@@ -47,4 +55,7 @@ func NewCmdStop(out io.Writer) *cobra.Command {
 
 func addStopFlags(fs *pflag.FlagSet, sf *run.StopFlags) {
 	fs.BoolVarP(&sf.Kill, "force-kill", "f", false, "Force kill the VM")
+	fs.BoolVar(&sf.Agent, "agent", false, "Shut the VM down using the guest agent")
+	cmdutil.AddSelectorFlag(fs, &sf.Selector)
+	cmdutil.AddParallelFlag(fs, &sf.Parallel)
 }
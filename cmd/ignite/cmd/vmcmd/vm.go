@@ -31,14 +31,33 @@ func NewCmdVM(out io.Writer) *cobra.Command {
 	}
 
 	cmd.AddCommand(NewCmdAttach(out))
+	cmd.AddCommand(NewCmdAutostart(out))
+	cmd.AddCommand(NewCmdBackup(out))
+	cmd.AddCommand(NewCmdCheckpoint(out))
+	cmd.AddCommand(NewCmdClone(out))
+	cmd.AddCommand(NewCmdCP(out))
 	cmd.AddCommand(NewCmdCreate(out))
+	cmd.AddCommand(NewCmdEdit(out))
+	cmd.AddCommand(NewCmdExport(out))
+	cmd.AddCommand(NewCmdHibernate(out))
+	cmd.AddCommand(NewCmdImport(out))
 	cmd.AddCommand(NewCmdKill(out))
 	cmd.AddCommand(NewCmdLogs(out))
+	cmd.AddCommand(NewCmdMetrics(out))
+	cmd.AddCommand(NewCmdMigrate(out))
+	cmd.AddCommand(NewCmdPause(out))
 	cmd.AddCommand(NewCmdPs(out))
+	cmd.AddCommand(NewCmdRename(out))
+	cmd.AddCommand(NewCmdResume(out))
 	cmd.AddCommand(NewCmdRm(out))
+	cmd.AddCommand(NewCmdRollback(out))
 	cmd.AddCommand(NewCmdRun(out))
+	cmd.AddCommand(NewCmdSet(out))
 	cmd.AddCommand(NewCmdSSH(out))
+	cmd.AddCommand(NewCmdSSHKey(out))
 	cmd.AddCommand(NewCmdStart(out))
+	cmd.AddCommand(NewCmdStats(out))
 	cmd.AddCommand(NewCmdStop(out))
+	cmd.AddCommand(NewCmdWait(out))
 	return cmd
 }
@@ -0,0 +1,59 @@
+package vmcmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/cmd/ignite/run"
+)
+
+// NewCmdWait blocks until a VM reaches the given state
+func NewCmdWait(out io.Writer) *cobra.Command {
+	wf := &run.WaitFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "wait <vm>",
+		Short: "Wait for a VM to reach a state",
+		Long: dedent.Dedent(`
+			Block until the given VM reaches a state, so scripts don't need to poll
+			"ignite ps". The VM is matched by prefix based on its ID and name.
+
+			The state to wait for is set with the for flag (--for):
+			  running: the VM is running
+			  stopped: the VM is stopped
+			  ip:      the VM is running and has an IP address
+			  ssh:     the SSH server within the VM accepts connections
+
+			By default wait blocks until the state is reached. With the timeout flag
+			(--timeout), wait fails with a non-zero exit code once the timeout passes.
+
+			Example usage:
+				$ ignite start my-vm
+				$ ignite vm wait my-vm --for=ssh --timeout=2m
+				$ ignite ssh my-vm
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				wo, err := wf.NewWaitOptions(args[0])
+				if err != nil {
+					return err
+				}
+
+				return run.Wait(wo)
+			}())
+		},
+	}
+
+	addWaitFlags(cmd.Flags(), wf)
+	return cmd
+}
+
+func addWaitFlags(fs *pflag.FlagSet, wf *run.WaitFlags) {
+	fs.StringVar(&wf.For, "for", string(run.WaitRunning), fmt.Sprintf("State to wait for, one of %v", run.ListWaitConditions()))
+	fs.DurationVar(&wf.Timeout, "timeout", 0, "Time to wait before failing, 0 waits forever")
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/weaveworks/ignite/cmd/ignite/cmd"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/providers/ignite"
+	"github.com/weaveworks/ignite/pkg/tracing"
 	"github.com/weaveworks/ignite/pkg/util"
 )
 
@@ -20,6 +21,11 @@ func Run() error {
 	// Preload necessary providers
 	util.GenericCheckErr(providers.Populate(ignite.Preload))
 
+	// Export the spans of the operations, if configured
+	flush, err := tracing.Setup("ignite")
+	util.GenericCheckErr(err)
+	defer flush()
+
 	c := cmd.NewIgniteCommand(os.Stdin, os.Stdout, os.Stderr)
 	return c.Execute()
 }
@@ -0,0 +1,717 @@
+package run
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/logs"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/sops"
+)
+
+// The actions apply takes for an object
+const (
+	applyCreate    = "create"
+	applyUpdate    = "update"
+	applyStart     = "start"
+	applyUnchanged = "unchanged"
+)
+
+// applyExtensions are the extensions of the files read from a directory
+var applyExtensions = []string{".yaml", ".yml", ".json"}
+
+type ApplyFlags struct {
+	Filenames []string
+	DryRun    bool
+}
+
+type ApplyOptions struct {
+	*ApplyFlags
+	manifests []manifest
+}
+
+// manifest is a single document of a manifest file
+type manifest struct {
+	source  string
+	content []byte
+}
+
+// applyAction is a change apply makes to an object to reach the state in its manifest
+type applyAction struct {
+	kind    runtime.Kind
+	name    string
+	action  string
+	changes []string
+	run     func() error
+}
+
+func (af *ApplyFlags) NewApplyOptions() (*ApplyOptions, error) {
+	if len(af.Filenames) == 0 {
+		return nil, fmt.Errorf("no manifests given, use -f to give a file, a directory or - for stdin")
+	}
+
+	manifests, err := readManifests(af.Filenames, os.Stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("no objects found in %s", strings.Join(af.Filenames, ", "))
+	}
+
+	return &ApplyOptions{ApplyFlags: af, manifests: manifests}, nil
+}
+
+// Apply brings the Images, Kernels and VMs in the manifests to the state they
+// describe. Objects that don't exist are created, and the spec, labels and
+// annotations of existing ones are updated. VMs whose manifest sets status.running
+// are started. All manifests are checked before any change is made, and applying
+// the same manifests again doesn't change anything.
+func Apply(ao *ApplyOptions, fs *flag.FlagSet) error {
+	if err := populateApplyProviders(); err != nil {
+		return err
+	}
+
+	actions, err := planApply(ao.manifests, fs)
+	if err != nil {
+		return err
+	}
+
+	return runApply(actions, ao.DryRun)
+}
+
+// populateApplyProviders populates the providers, and resolves the configuration
+// of imports, for applying manifests
+func populateApplyProviders() error {
+	// Populate the runtime and network-plugin providers.
+	if err := config.SetAndPopulateProviders(providers.RuntimeName, providers.NetworkPluginName); err != nil {
+		return err
+	}
+
+	// Resolve registry configuration used for pulling images if required.
+	cmdutil.ResolveRegistryConfigDir()
+	cmdutil.ResolveImageScan()
+	cmdutil.ResolveSBOM()
+	cmdutil.ResolveImageImport()
+	return nil
+}
+
+// runApply prints the planned actions and runs them in order, with dryRun they're only printed
+func runApply(actions []*applyAction, dryRun bool) error {
+	if dryRun || !logs.Quiet {
+		printApplyPlan(os.Stdout, actions)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	for _, a := range actions {
+		if a.run == nil {
+			continue
+		}
+
+		if err := a.run(); err != nil {
+			return fmt.Errorf("failed to %s %s %q: %v", a.action, a.kind, a.name, err)
+		}
+	}
+
+	return nil
+}
+
+// planApply decodes the manifests, and compares them to the current objects to
+// find the actions needed to apply them
+func planApply(manifests []manifest, fs *flag.FlagSet) ([]*applyAction, error) {
+	var actions []*applyAction
+	seen := map[string]string{}
+	for _, m := range manifests {
+		obj, err := scheme.Serializer.Decode(m.content, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", m.source, err)
+		}
+
+		var objActions []*applyAction
+		var kind runtime.Kind
+		var name string
+		switch o := obj.(type) {
+		case *api.Image:
+			kind, name = api.KindImage, o.Spec.OCI.String()
+			objActions, err = planImage(o)
+		case *api.Kernel:
+			kind, name = api.KindKernel, o.Spec.OCI.String()
+			objActions, err = planKernel(o)
+		case *api.VM:
+			kind, name = api.KindVM, o.GetName()
+			objActions, err = planVM(o, m.content, fs)
+		default:
+			err = fmt.Errorf("unsupported kind %s, only Image, Kernel and VM objects can be applied", obj.GetObjectKind().GroupVersionKind().Kind)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", m.source, err)
+		}
+
+		// Objects given twice would be created twice
+		key := fmt.Sprintf("%s/%s", kind, name)
+		if other, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%s: %s %q is also given in %s", m.source, kind, name, other)
+		}
+		seen[key] = m.source
+
+		actions = append(actions, objActions...)
+	}
+
+	return actions, nil
+}
+
+func planImage(image *api.Image) ([]*applyAction, error) {
+	if image.Spec.OCI.IsUnset() {
+		return nil, fmt.Errorf("the OCI reference of the image is mandatory")
+	}
+
+	if err := validation.ValidateLabels(image.GetObjectMeta().Labels, field.NewPath("metadata.labels")).ToAggregate(); err != nil {
+		return nil, err
+	}
+
+	current, err := providers.Client.Images().Find(filter.NewNameFilter(image.Spec.OCI.String()))
+	if _, ok := err.(*filterer.NonexistentError); ok {
+		return []*applyAction{{
+			kind:   api.KindImage,
+			name:   image.Spec.OCI.String(),
+			action: applyCreate,
+			run: func() error {
+				imported, err := ImportImage(image.Spec.OCI.String(), false)
+				if err != nil {
+					return err
+				}
+
+				return applyObjectMeta(imported, image)
+			},
+		}}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return planObjectMeta(current, image)
+}
+
+func planKernel(kernel *api.Kernel) ([]*applyAction, error) {
+	if kernel.Spec.OCI.IsUnset() {
+		return nil, fmt.Errorf("the OCI reference of the kernel is mandatory")
+	}
+
+	if err := validation.ValidateLabels(kernel.GetObjectMeta().Labels, field.NewPath("metadata.labels")).ToAggregate(); err != nil {
+		return nil, err
+	}
+
+	current, err := providers.Client.Kernels().Find(filter.NewNameFilter(kernel.Spec.OCI.String()))
+	if _, ok := err.(*filterer.NonexistentError); ok {
+		return []*applyAction{{
+			kind:   api.KindKernel,
+			name:   kernel.Spec.OCI.String(),
+			action: applyCreate,
+			run: func() error {
+				imported, err := ImportKernel(kernel.Spec.OCI.String())
+				if err != nil {
+					return err
+				}
+
+				return applyObjectMeta(imported, kernel)
+			},
+		}}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return planObjectMeta(current, kernel)
+}
+
+// planObjectMeta plans updating the labels and annotations of an existing Image or Kernel,
+// their spec is given by the OCI image they're imported from
+func planObjectMeta(current, desired runtime.Object) ([]*applyAction, error) {
+	updated := current.DeepCopyObject().(runtime.Object)
+	mergeObjectMeta(updated, desired)
+
+	a := &applyAction{
+		kind:   current.GetKind(),
+		name:   current.GetName(),
+		action: applyUnchanged,
+	}
+
+	var err error
+	if a.changes, err = objectMetaChanges(current, updated); err != nil {
+		return nil, err
+	}
+
+	if len(a.changes) > 0 {
+		a.action = applyUpdate
+		a.run = func() error {
+			return setApplied(updated)
+		}
+	}
+
+	return []*applyAction{a}, nil
+}
+
+// applyObjectMeta sets the labels and annotations of the manifest on a newly imported Image or Kernel
+func applyObjectMeta(obj, desired runtime.Object) error {
+	if len(desired.GetObjectMeta().Labels) == 0 && len(desired.GetObjectMeta().Annotations) == 0 {
+		return nil
+	}
+
+	mergeObjectMeta(obj, desired)
+	return providers.Client.Dynamic(obj.GetKind()).Set(obj)
+}
+
+// mergeObjectMeta sets the labels and annotations of desired on obj, keeping the ones
+// the manifest doesn't give
+func mergeObjectMeta(obj, desired runtime.Object) {
+	for k, v := range desired.GetObjectMeta().Labels {
+		obj.SetLabel(k, v)
+	}
+
+	for k, v := range desired.GetObjectMeta().Annotations {
+		obj.SetAnnotation(k, v)
+	}
+}
+
+func planVM(vm *api.VM, content []byte, fs *flag.FlagSet) ([]*applyAction, error) {
+	// Without a name, every apply would create a new VM
+	if len(vm.GetName()) == 0 {
+		return nil, fmt.Errorf("the VM needs a name to be applied")
+	}
+
+	current, err := findAppliedVM(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	var a *applyAction
+	var uid runtime.UID
+	if current == nil {
+		var created *api.VM
+		if a, created, err = planCreateVM(vm, content); err != nil {
+			return nil, err
+		}
+
+		uid = created.GetUID()
+	} else {
+		if a, err = planUpdateVM(current, content); err != nil {
+			return nil, err
+		}
+
+		uid = current.GetUID()
+	}
+
+	actions := []*applyAction{a}
+
+	// VMs are only started, apply never stops a VM
+	if vm.Status.Running && (current == nil || !current.Running()) {
+		actions = append(actions, &applyAction{
+			kind:   api.KindVM,
+			name:   vm.GetName(),
+			action: applyStart,
+			run: func() error {
+				so, err := (&StartFlags{}).NewStartOptions(uid.String())
+				if err != nil {
+					return err
+				}
+
+				return Start(so, fs)
+			},
+		})
+	}
+
+	return actions, nil
+}
+
+// findAppliedVM looks up the existing VM for the manifest, by its UID if given and
+// otherwise by its exact name. It returns nil if the VM doesn't exist yet.
+func findAppliedVM(vm *api.VM) (*api.VM, error) {
+	var current *api.VM
+	if len(vm.GetUID()) > 0 {
+		vms, err := getAllVMs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range vms {
+			if v.GetUID() == vm.GetUID() {
+				current = v
+				break
+			}
+		}
+	} else {
+		var err error
+		current, err = providers.Client.VMs().Find(filter.NewNameFilter(vm.GetName()))
+		if _, ok := err.(*filterer.NonexistentError); !ok && err != nil {
+			return nil, err
+		}
+	}
+
+	if current == nil {
+		return nil, nil
+	}
+
+	if current.GetName() != vm.GetName() {
+		return nil, fmt.Errorf("VM %q is named %q, rename it with \"ignite vm rename\" first", current.GetUID(), current.GetName())
+	}
+
+	return current, nil
+}
+
+// planCreateVM builds the new VM for the manifest, and plans creating it
+func planCreateVM(vm *api.VM, content []byte) (*applyAction, *api.VM, error) {
+	// Build the VM the same way as "ignite create --config"
+	baseVM := newBaseVM()
+	if templateName := vm.GetAnnotation(constants.IGNITE_TEMPLATE_ANNOTATION); len(templateName) > 0 {
+		if err := setTemplate(baseVM, templateName); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := applyVMConfig(baseVM, content); err != nil {
+		return nil, nil, err
+	}
+
+	// The applied status is only used to know whether to start the VM
+	baseVM.Status.Running = false
+	baseVM.Status.Conditions = nil
+
+	if err := metadata.SetNameAndUID(baseVM, providers.Client); err != nil {
+		return nil, nil, err
+	}
+
+	if err := validation.ValidateVM(baseVM).ToAggregate(); err != nil {
+		return nil, nil, err
+	}
+
+	return &applyAction{
+		kind:   api.KindVM,
+		name:   baseVM.GetName(),
+		action: applyCreate,
+		run: func() (err error) {
+			// The VM's spec may override the runtime and network-plugin
+			config.ResolveVMProviders(baseVM)
+			if err = config.SetAndPopulateProviders(baseVM.Status.Runtime.Name, baseVM.Status.Network.Plugin); err != nil {
+				return
+			}
+
+			co := &CreateOptions{CreateFlags: &CreateFlags{VM: baseVM}}
+			if co.image, co.kernel, err = operations.FindOrImportImageAndKernel(context.Background(), providers.Client, baseVM.Spec.Image.OCI, baseVM.Spec.Kernel.OCI); err != nil {
+				return
+			}
+			baseVM.SetImage(co.image)
+			baseVM.SetKernel(co.kernel)
+
+			return Create(co)
+		},
+	}, baseVM, nil
+}
+
+// planUpdateVM patches the manifest onto the existing VM, and plans saving the changes
+func planUpdateVM(current *api.VM, content []byte) (*applyAction, error) {
+	updated := current.DeepCopy()
+	if err := applyVMConfig(updated, content); err != nil {
+		return nil, err
+	}
+
+	// The status is managed by ignite, discard the applied one
+	updated.Status = current.Status
+
+	a := &applyAction{
+		kind:   api.KindVM,
+		name:   current.GetName(),
+		action: applyUnchanged,
+	}
+
+	specChanges, err := changedFields("spec", current.Spec, updated.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	metaChanges, err := objectMetaChanges(current, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	a.changes = append(metaChanges, specChanges...)
+	switch {
+	case len(specChanges) > 0:
+		if err := checkVMUpdate(current, updated); err != nil {
+			return nil, err
+		}
+
+		a.action = applyUpdate
+		a.run = func() error {
+			return updateVM(current, updated)
+		}
+	case len(metaChanges) > 0:
+		// Labels and annotations can be changed while the VM is running
+		if err := validation.ValidateVM(updated).ToAggregate(); err != nil {
+			return nil, err
+		}
+
+		a.action = applyUpdate
+		a.run = func() error {
+			return setApplied(updated)
+		}
+	}
+
+	return a, nil
+}
+
+// setApplied saves the object with its updated labels and annotations
+func setApplied(obj runtime.Object) error {
+	if err := providers.Client.Dynamic(obj.GetKind()).Set(obj); err != nil {
+		return err
+	}
+
+	if logs.Quiet {
+		fmt.Println(obj.GetUID())
+	} else {
+		log.Infof("Updated %s with name %q and ID %q", obj.GetKind(), obj.GetName(), obj.GetUID())
+	}
+
+	return nil
+}
+
+// objectMetaChanges lists the changed labels and annotations
+func objectMetaChanges(current, updated runtime.Object) ([]string, error) {
+	labelChanges, err := changedFields("metadata.labels", current.GetObjectMeta().Labels, updated.GetObjectMeta().Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	annotationChanges, err := changedFields("metadata.annotations", current.GetObjectMeta().Annotations, updated.GetObjectMeta().Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(labelChanges, annotationChanges...), nil
+}
+
+// changedFields compares the JSON encoding of the current and desired values, and
+// lists the fields that differ as "path: current -> desired", sorted by path
+func changedFields(path string, current, desired interface{}) ([]string, error) {
+	currentFields, err := jsonFields(path, current)
+	if err != nil {
+		return nil, err
+	}
+
+	desiredFields, err := jsonFields(path, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for p, v := range desiredFields {
+		if c, ok := currentFields[p]; !ok || c != v {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", p, orUnset(c), v))
+		}
+	}
+
+	for p, c := range currentFields {
+		if _, ok := desiredFields[p]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: %s -> <unset>", p, c))
+		}
+	}
+
+	sort.Strings(changes)
+	return changes, nil
+}
+
+// jsonFields flattens the JSON encoding of v into its fields by path, lists are
+// compared as a whole
+func jsonFields(path string, v interface{}) (map[string]string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	var flatten func(p string, v interface{})
+	flatten = func(p string, v interface{}) {
+		switch value := v.(type) {
+		case nil:
+		case map[string]interface{}:
+			for k, e := range value {
+				flatten(p+"."+k, e)
+			}
+		default:
+			if reflect.ValueOf(value).Kind() == reflect.Slice && reflect.ValueOf(value).Len() == 0 {
+				return
+			}
+
+			encoded, _ := json.Marshal(value)
+			fields[p] = string(encoded)
+		}
+	}
+
+	flatten(path, decoded)
+	return fields, nil
+}
+
+func orUnset(s string) string {
+	if len(s) == 0 {
+		return "<unset>"
+	}
+
+	return s
+}
+
+// printApplyPlan prints the action for every object, with the fields it changes
+func printApplyPlan(w io.Writer, actions []*applyAction) {
+	for _, a := range actions {
+		fmt.Fprintf(w, "%s %q: %s\n", a.kind, a.name, a.action)
+		for _, c := range a.changes {
+			fmt.Fprintf(w, "    %s\n", c)
+		}
+	}
+}
+
+// readManifests reads the YAML or JSON documents from the given files, from the
+// files in the given directories, and from stdin for "-"
+func readManifests(filenames []string, stdin io.Reader) ([]manifest, error) {
+	var manifests []manifest
+	for _, filename := range filenames {
+		if filename == "-" {
+			m, err := readManifestDocuments("stdin", stdin)
+			if err != nil {
+				return nil, err
+			}
+
+			manifests = append(manifests, m...)
+			continue
+		}
+
+		files, err := manifestFiles(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, err
+			}
+
+			m, err := readManifestDocuments(file, f)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			manifests = append(manifests, m...)
+		}
+	}
+
+	return manifests, nil
+}
+
+// manifestFiles returns the file, or the manifest files in the directory in lexical order
+func manifestFiles(filename string) ([]string, error) {
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		return []string{filename}, nil
+	}
+
+	entries, err := ioutil.ReadDir(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		for _, ext := range applyExtensions {
+			if filepath.Ext(entry.Name()) == ext {
+				files = append(files, filepath.Join(filename, entry.Name()))
+				break
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// readManifestDocuments splits the "---" separated documents, skipping empty ones
+func readManifestDocuments(source string, r io.Reader) ([]manifest, error) {
+	var manifests []manifest
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for i := 1; ; i++ {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", source, err)
+		}
+
+		if isEmptyDocument(doc) {
+			continue
+		}
+
+		// Documents encrypted with SOPS, e.g. to store secrets in git, are decrypted
+		if sops.IsEncrypted(doc) {
+			if doc, err = sops.Decrypt(doc, sops.Format(source)); err != nil {
+				return nil, fmt.Errorf("failed to decrypt %s (document %d): %v", source, i, err)
+			}
+		}
+
+		manifests = append(manifests, manifest{
+			source:  fmt.Sprintf("%s (document %d)", source, i),
+			content: doc,
+		})
+	}
+
+	return manifests, nil
+}
+
+// isEmptyDocument reports if the YAML document only contains whitespace and comments
+func isEmptyDocument(doc []byte) bool {
+	for _, line := range bytes.Split(doc, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 && line[0] != '#' && !bytes.Equal(line, []byte("---")) {
+			return false
+		}
+	}
+
+	return true
+}
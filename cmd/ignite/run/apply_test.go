@@ -0,0 +1,172 @@
+package run
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/sops"
+)
+
+const applyTestVMs = `# my VMs
+apiVersion: ignite.weave.works/v1alpha5
+kind: VM
+metadata:
+  name: vm1
+---
+# nothing here
+---
+apiVersion: ignite.weave.works/v1alpha5
+kind: VM
+metadata:
+  name: vm2
+`
+
+func TestReadManifests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-apply-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"b-vms.yaml":    applyTestVMs,
+		"a-image.yml":   "apiVersion: ignite.weave.works/v1alpha5\nkind: Image\nspec:\n  oci: foo/bar:latest\n",
+		"c-kernel.json": `{"apiVersion": "ignite.weave.works/v1alpha5", "kind": "Kernel"}`,
+		"README.md":     "not a manifest",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	cases := []struct {
+		name      string
+		filenames []string
+		stdin     string
+		want      []string
+		err       bool
+	}{
+		{
+			name:      "file with multiple documents",
+			filenames: []string{filepath.Join(dir, "b-vms.yaml")},
+			want: []string{
+				filepath.Join(dir, "b-vms.yaml") + " (document 1)",
+				filepath.Join(dir, "b-vms.yaml") + " (document 3)",
+			},
+		},
+		{
+			name:      "directory",
+			filenames: []string{dir},
+			want: []string{
+				filepath.Join(dir, "a-image.yml") + " (document 1)",
+				filepath.Join(dir, "b-vms.yaml") + " (document 1)",
+				filepath.Join(dir, "b-vms.yaml") + " (document 3)",
+				filepath.Join(dir, "c-kernel.json") + " (document 1)",
+			},
+		},
+		{
+			name:      "stdin",
+			filenames: []string{"-"},
+			stdin:     applyTestVMs,
+			want:      []string{"stdin (document 1)", "stdin (document 3)"},
+		},
+		{
+			name:      "nonexistent file",
+			filenames: []string{filepath.Join(dir, "nonexistent.yaml")},
+			err:       true,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			manifests, err := readManifests(rt.filenames, strings.NewReader(rt.stdin))
+			if (err != nil) != rt.err {
+				t.Fatalf("expected error %t, actual: %v", rt.err, err)
+			}
+
+			var sources []string
+			for _, m := range manifests {
+				sources = append(sources, m.source)
+			}
+			assert.DeepEqual(t, sources, rt.want)
+		})
+	}
+}
+
+func TestReadEncryptedManifests(t *testing.T) {
+	defer func(decrypt func([]byte, string) ([]byte, error)) { sops.Decrypt = decrypt }(sops.Decrypt)
+	sops.Decrypt = func(doc []byte, format string) ([]byte, error) {
+		assert.Equal(t, format, "yaml")
+		return []byte("apiVersion: ignite.weave.works/v1alpha5\nkind: VM\nmetadata:\n  name: vm1\n"), nil
+	}
+
+	encrypted := "apiVersion: ignite.weave.works/v1alpha5\nkind: VM\nmetadata:\n  name: ENC[AES256_GCM,data:dm0x]\nsops:\n  mac: ENC[AES256_GCM,data:bWFj]\n"
+	manifests, err := readManifests([]string{"-"}, strings.NewReader(encrypted))
+	assert.NilError(t, err)
+	assert.Equal(t, len(manifests), 1)
+	assert.Equal(t, string(manifests[0].content), "apiVersion: ignite.weave.works/v1alpha5\nkind: VM\nmetadata:\n  name: vm1\n")
+}
+
+func TestChangedFields(t *testing.T) {
+	ociRef, err := meta.NewOCIImageRef("foo/bar:latest")
+	if err != nil {
+		t.Fatalf("error parsing image: %v", err)
+	}
+
+	spec := api.VMSpec{
+		Image:          api.VMImageSpec{OCI: ociRef},
+		Sandbox:        api.VMSandboxSpec{OCI: ociRef},
+		Kernel:         api.VMKernelSpec{OCI: ociRef},
+		CPUs:           2,
+		Memory:         meta.NewSizeFromBytes(512 * 1024 * 1024),
+		AutostartAfter: []string{"db"},
+	}
+
+	cases := []struct {
+		name   string
+		modify func(spec *api.VMSpec)
+		want   []string
+	}{
+		{
+			name:   "unchanged",
+			modify: func(spec *api.VMSpec) {},
+		},
+		{
+			name: "changed fields",
+			modify: func(spec *api.VMSpec) {
+				spec.CPUs = 4
+				spec.Autostart = true
+			},
+			want: []string{
+				"spec.autostart: <unset> -> true",
+				"spec.cpus: 2 -> 4",
+			},
+		},
+		{
+			name: "unset list",
+			modify: func(spec *api.VMSpec) {
+				spec.AutostartAfter = nil
+			},
+			want: []string{`spec.autostartAfter: ["db"] -> <unset>`},
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			desired := *spec.DeepCopy()
+			rt.modify(&desired)
+
+			changes, err := changedFields("spec", spec, desired)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, changes, rt.want)
+		})
+	}
+}
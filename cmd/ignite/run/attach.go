@@ -1,22 +1,45 @@
 package run
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	terminal "golang.org/x/term"
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/container"
 	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+const (
+	// keyCtrlC, keyCtrlP and keyCtrlQ are the bytes sent by the Ctrl + C, P and Q keys.
+	// ^P^Q detaches from the VM, read-only sessions also detach on ^C.
+	keyCtrlC = 0x03
+	keyCtrlP = 0x10
+	keyCtrlQ = 0x11
 )
 
+// AttachFlags contains the flags supported by attach.
+type AttachFlags struct {
+	ReadOnly bool
+}
+
 // checkRunning can be used to skip the running check, this is used by Start and Run
 // as the in-container ignite takes some time to start up and update the state
 type AttachOptions struct {
+	*AttachFlags
 	checkRunning bool
 	vm           *api.VM
 }
 
-func NewAttachOptions(vmMatch string) (ao *AttachOptions, err error) {
-	ao = &AttachOptions{checkRunning: true}
+func (af *AttachFlags) NewAttachOptions(vmMatch string) (ao *AttachOptions, err error) {
+	ao = &AttachOptions{AttachFlags: af, checkRunning: true}
 	ao.vm, err = getVMForMatch(vmMatch)
 	return
 }
@@ -35,6 +58,26 @@ func Attach(ao *AttachOptions) error {
 	// Print the ID before attaching
 	fmt.Println(ao.vm.GetUID())
 
+	// Attach through the console socket of the VM, which is shared between sessions
+	conn, err := container.DialConsole(ao.vm, ao.ReadOnly)
+	if err == nil {
+		detached, err := attachConsole(conn, ao.ReadOnly)
+		if detached {
+			log.Info("Detached")
+		}
+		return err
+	}
+
+	// VMs started before the console socket was added, and VMs whose ignite-spawn is
+	// still starting up, are attached to through the runtime, which can't be read-only
+	if !errors.Is(err, container.ErrNoConsoleSocket) {
+		return err
+	}
+
+	if ao.ReadOnly {
+		return fmt.Errorf("VM %q doesn't support read-only attach sessions, restart it to enable them", ao.vm.GetUID())
+	}
+
 	// Attach to the VM in Docker
 	if err := providers.Runtime.AttachContainer(ao.vm.PrefixedID()); err != nil {
 		return fmt.Errorf("failed to attach to container for VM %s: %v", ao.vm.GetUID(), err)
@@ -42,3 +85,74 @@ func Attach(ao *AttachOptions) error {
 
 	return nil
 }
+
+// attachConsole connects the terminal to the attach session until the VM stops, or
+// the session is detached from, which is reported by detached
+func attachConsole(conn net.Conn, readOnly bool) (detached bool, err error) {
+	defer conn.Close()
+
+	if fd := int(os.Stdin.Fd()); terminal.IsTerminal(fd) {
+		state, rawErr := terminal.MakeRaw(fd)
+		if rawErr != nil {
+			return false, fmt.Errorf("failed to make terminal raw: %v", rawErr)
+		}
+		defer util.DeferErr(&err, func() error { return terminal.Restore(fd, state) })
+	}
+
+	done := make(chan bool, 2)
+	go func() {
+		// The VM has stopped once the console socket closes
+		_, _ = io.Copy(os.Stdout, conn)
+		done <- false
+	}()
+
+	go func() {
+		// Without input, keep printing the output until the VM stops
+		if copyInput(conn, os.Stdin, readOnly) {
+			done <- true
+		}
+	}()
+
+	return <-done, nil
+}
+
+// copyInput passes the input from src to dst until the detach sequence, read-only sessions only
+// look for it. It returns whether the session has been detached from, or false when src ends.
+func copyInput(dst io.Writer, src io.Reader, readOnly bool) bool {
+	buf := make([]byte, 1024)
+	pendingCtrlP := false
+	for {
+		n, err := src.Read(buf)
+		out := make([]byte, 0, n+1)
+		detached := false
+		for _, b := range buf[:n] {
+			if detached = (pendingCtrlP && b == keyCtrlQ) || (readOnly && b == keyCtrlC); detached {
+				break
+			}
+
+			if pendingCtrlP {
+				// Not a detach sequence, pass the held back ^P on
+				out = append(out, keyCtrlP)
+			}
+
+			if pendingCtrlP = b == keyCtrlP; !pendingCtrlP {
+				out = append(out, b)
+			}
+		}
+
+		// Pass on the input preceding the detach sequence
+		if !readOnly && len(out) > 0 {
+			if _, err := dst.Write(out); err != nil {
+				return false
+			}
+		}
+
+		if detached {
+			return true
+		}
+
+		if err != nil {
+			return false
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package run
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestCopyInput(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		readOnly bool
+		detached bool
+		want     string
+	}{
+		{"input ends", "ls -l\r", false, false, "ls -l\r"},
+		{"detach", "ls\r\x10\x11whoami\r", false, true, "ls\r"},
+		{"ctrl-p without ctrl-q", "a\x10b\x10\x10\x11", false, true, "a\x10b\x10"},
+		{"ctrl-c interrupts", "sleep 10\r\x03", false, false, "sleep 10\r\x03"},
+		{"read-only", "ls\r", true, false, ""},
+		{"read-only detach", "ls\r\x10\x11", true, true, ""},
+		{"read-only ctrl-c detaches", "\x03ls\r", true, true, ""},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			detached := copyInput(&out, bytes.NewBufferString(rt.input), rt.readOnly)
+			assert.Equal(t, detached, rt.detached)
+			assert.Equal(t, out.String(), rt.want)
+		})
+	}
+}
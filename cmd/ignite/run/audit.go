@@ -0,0 +1,90 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/weaveworks/ignite/pkg/audit"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+type AuditFlags struct {
+	File      string
+	Since     time.Duration
+	Actor     string
+	Operation string
+	Failed    bool
+	Output    string
+}
+
+// Audit prints the entries of the audit log that match the flags, oldest first
+func Audit(af *AuditFlags) error {
+	file := af.File
+	if len(file) == 0 {
+		file = audit.Path()
+	}
+
+	entries, err := audit.Read(file)
+	if err != nil {
+		return err
+	}
+
+	entries = filterAudit(entries, af, time.Now())
+	switch af.Output {
+	case "", outputFormatTable:
+		o := util.NewOutput()
+		defer o.Flush()
+
+		o.Write("TIME", "ACTOR", "SOURCE", "OPERATION", "RESULT", "DURATION", "ARGS")
+		for _, e := range entries {
+			result := e.Result
+			if len(e.Error) > 0 {
+				result = fmt.Sprintf("%s: %s", e.Result, e.Error)
+			}
+
+			o.Write(e.Time.Local().Format("2006-01-02 15:04:05"), e.Actor, e.Source, e.Operation, result,
+				e.EndTime.Sub(e.Time).Round(time.Millisecond), strings.Join(e.Args, " "))
+		}
+
+		return nil
+	case outputFormatJSON:
+		if entries == nil {
+			entries = []*audit.Entry{}
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	return fmt.Errorf("unrecognized output format: %q, supported formats: %s|%s", af.Output, outputFormatTable, outputFormatJSON)
+}
+
+// filterAudit returns the entries that match the flags
+func filterAudit(entries []*audit.Entry, af *AuditFlags, now time.Time) []*audit.Entry {
+	var matched []*audit.Entry
+	for _, e := range entries {
+		if af.Since > 0 && e.Time.Before(now.Add(-af.Since)) {
+			continue
+		}
+
+		if len(af.Actor) > 0 && e.Actor != af.Actor {
+			continue
+		}
+
+		if len(af.Operation) > 0 && !strings.Contains(e.Operation, af.Operation) {
+			continue
+		}
+
+		if af.Failed && e.Result != audit.ResultFailure {
+			continue
+		}
+
+		matched = append(matched, e)
+	}
+
+	return matched
+}
@@ -0,0 +1,45 @@
+package run
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/weaveworks/ignite/pkg/audit"
+)
+
+func TestFilterAudit(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []*audit.Entry{
+		{Time: now.Add(-48 * time.Hour), Actor: "alice", Operation: "vm create", Result: audit.ResultSuccess},
+		{Time: now.Add(-2 * time.Hour), Actor: "bob", Operation: "vm start", Result: audit.ResultFailure},
+		{Time: now.Add(-time.Hour), Actor: "alice", Operation: "POST /v1/vms/my-vm/start", Result: audit.ResultSuccess},
+		{Time: now.Add(-time.Minute), Actor: "ignited", Operation: "image import", Result: audit.ResultSuccess},
+	}
+
+	cases := []struct {
+		name string
+		af   *AuditFlags
+		want []int
+	}{
+		{"all", &AuditFlags{}, []int{0, 1, 2, 3}},
+		{"since", &AuditFlags{Since: 24 * time.Hour}, []int{1, 2, 3}},
+		{"actor", &AuditFlags{Actor: "alice"}, []int{0, 2}},
+		{"operation", &AuditFlags{Operation: "start"}, []int{1, 2}},
+		{"failed", &AuditFlags{Failed: true}, []int{1}},
+		{"combined", &AuditFlags{Since: 3 * time.Hour, Actor: "alice", Operation: "start"}, []int{2}},
+		{"none", &AuditFlags{Actor: "carol"}, nil},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			var want []*audit.Entry
+			for _, i := range rt.want {
+				want = append(want, entries[i])
+			}
+
+			assert.DeepEqual(t, filterAudit(entries, rt.af, now), want)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/autostart"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// AutostartFlags contains the flags supported by the autostart command.
+type AutostartFlags struct {
+	List bool
+}
+
+type AutostartOptions struct {
+	*AutostartFlags
+}
+
+func (af *AutostartFlags) NewAutostartOptions() (*AutostartOptions, error) {
+	return &AutostartOptions{af}, nil
+}
+
+// Autostart starts the VMs marked for autostart in dependency order,
+// or lists them in the order they would be started in
+func Autostart(ao *AutostartOptions) error {
+	if !ao.List {
+		return autostart.StartAll()
+	}
+
+	vms, err := getAllVMs()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	ordered, err := autostart.Order(vms)
+	if err != nil {
+		log.Warn(err)
+	}
+
+	o := util.NewOutput()
+	defer o.Flush()
+
+	o.Write("VM ID", "NAME", "STATE", "AUTOSTART AFTER")
+	for _, vm := range ordered {
+		state := "Stopped"
+		if vm.Running() {
+			state = "Running"
+		}
+
+		o.Write(vm.GetUID(), vm.GetName(), state, strings.Join(vm.Spec.AutostartAfter, ","))
+	}
+
+	return nil
+}
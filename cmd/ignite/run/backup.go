@@ -0,0 +1,64 @@
+package run
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/backup"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+type BackupListOptions struct {
+	vm *api.VM
+}
+
+func NewBackupListOptions(vmMatch string) (blo *BackupListOptions, err error) {
+	blo = &BackupListOptions{}
+	blo.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// BackupList lists the backups of a VM stored in the destination of its backup policy
+func BackupList(blo *BackupListOptions) error {
+	backups, err := backup.List(blo.vm)
+	if err != nil {
+		return err
+	}
+
+	o := util.NewOutput()
+	defer o.Flush()
+
+	o.Write("BACKUP", "CREATED")
+	for _, b := range backups {
+		o.Write(b.Name, b.Created.Local().Format("2006-01-02 15:04:05"))
+	}
+
+	return nil
+}
+
+type BackupRestoreOptions struct {
+	vm     *api.VM
+	backup string
+}
+
+func NewBackupRestoreOptions(vmMatch, backupName string) (bro *BackupRestoreOptions, err error) {
+	bro = &BackupRestoreOptions{backup: backupName}
+	bro.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// BackupRestore restores the disk of a stopped VM from one of its backups
+func BackupRestore(bro *BackupRestoreOptions) error {
+	if bro.vm.Running() {
+		return fmt.Errorf("VM %q is running, stop it before restoring a backup", bro.vm.GetUID())
+	}
+
+	if err := backup.Restore(bro.vm, bro.backup); err != nil {
+		return err
+	}
+
+	log.Infof("Restored backup %q of VM %q", bro.backup, bro.vm.GetUID())
+	return nil
+}
@@ -0,0 +1,97 @@
+package run
+
+import (
+	"sync"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+// forEachVM runs f for each of the VMs, after setProviders has set the runtime and network
+// plugin providers for it. Up to parallel VMs are processed at once, the errors of all of
+// them are returned. With parallel of one or less, the VMs are processed in order until one
+// fails. The providers are global, so the VMs run in groups sharing their runtime and network
+// plugin, and the providers are set for a whole group before it starts.
+func forEachVM(vms []*api.VM, parallel int, setProviders, f func(vm *api.VM) error) error {
+	if parallel <= 1 {
+		for _, vm := range vms {
+			if err := setProviders(vm); err != nil {
+				return err
+			}
+
+			if err := f(vm); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var errs []error
+	for _, group := range groupByProviders(vms) {
+		for _, vm := range group {
+			if err := setProviders(vm); err != nil {
+				return err
+			}
+		}
+
+		errs = append(errs, runParallel(group, parallel, f)...)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// groupByProviders groups the VMs by the runtime and network plugin of their status,
+// keeping the order of the VMs
+func groupByProviders(vms []*api.VM) [][]*api.VM {
+	var groups [][]*api.VM
+	index := make(map[string]int)
+	for _, vm := range vms {
+		var key string
+		if vm.Status.Runtime != nil {
+			key = vm.Status.Runtime.Name.String()
+		}
+		if vm.Status.Network != nil {
+			key += "/" + vm.Status.Network.Plugin.String()
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], vm)
+	}
+
+	return groups
+}
+
+// runParallel runs f for each of the VMs, up to parallel at once, and returns the errors
+func runParallel(vms []*api.VM, parallel int, f func(vm *api.VM) error) []error {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		errs  []error
+		slots = make(chan struct{}, parallel)
+	)
+
+	for _, vm := range vms {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(vm *api.VM) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := f(vm); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(vm)
+	}
+
+	wg.Wait()
+	return errs
+}
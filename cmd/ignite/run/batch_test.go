@@ -0,0 +1,89 @@
+package run
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/network"
+	"github.com/weaveworks/ignite/pkg/runtime"
+)
+
+func newBatchVM(name string, runtimeName runtime.Name, plugin network.PluginName) *api.VM {
+	vm := &api.VM{}
+	vm.SetName(name)
+	vm.Status.Runtime = &api.Runtime{Name: runtimeName}
+	vm.Status.Network = &api.Network{Plugin: plugin}
+	return vm
+}
+
+func TestForEachVM(t *testing.T) {
+	vms := []*api.VM{
+		newBatchVM("a", runtime.RuntimeContainerd, network.PluginCNI),
+		newBatchVM("b", runtime.RuntimeDocker, network.PluginDockerBridge),
+		newBatchVM("c", runtime.RuntimeContainerd, network.PluginCNI),
+		newBatchVM("d", runtime.RuntimeContainerd, network.PluginCNI),
+	}
+
+	var (
+		mu        sync.Mutex
+		providers runtime.Name
+		processed []string
+	)
+
+	setProviders := func(vm *api.VM) error {
+		providers = vm.Status.Runtime.Name
+		return nil
+	}
+
+	f := func(vm *api.VM) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// The providers are set for the whole group of the VM
+		if providers != vm.Status.Runtime.Name {
+			return fmt.Errorf("%s processed with runtime %s", vm.GetName(), providers)
+		}
+
+		processed = append(processed, vm.GetName())
+		if vm.GetName() == "c" {
+			return fmt.Errorf("%s failed", vm.GetName())
+		}
+		return nil
+	}
+
+	// Sequentially, the VMs are processed in order until one fails
+	err := forEachVM(vms, 1, setProviders, f)
+	assert.Error(t, err, "c failed")
+	assert.DeepEqual(t, processed, []string{"a", "b", "c"})
+
+	// In parallel, all the VMs are processed
+	processed = nil
+	err = forEachVM(vms, 2, setProviders, f)
+	assert.Error(t, err, "c failed")
+	assert.Equal(t, len(processed), 4)
+	assert.Equal(t, processed[3], "b")
+}
+
+func TestGroupByProviders(t *testing.T) {
+	vms := []*api.VM{
+		newBatchVM("a", runtime.RuntimeContainerd, network.PluginCNI),
+		newBatchVM("b", runtime.RuntimeDocker, network.PluginDockerBridge),
+		newBatchVM("c", runtime.RuntimeContainerd, network.PluginDockerBridge),
+		newBatchVM("d", runtime.RuntimeContainerd, network.PluginCNI),
+	}
+
+	var names [][]string
+	for _, group := range groupByProviders(vms) {
+		var groupNames []string
+		for _, vm := range group {
+			groupNames = append(groupNames, vm.GetName())
+		}
+		names = append(names, groupNames)
+	}
+
+	assert.DeepEqual(t, names, [][]string{{"a", "d"}, {"b"}, {"c"}})
+}
@@ -0,0 +1,74 @@
+package run
+
+import (
+	"fmt"
+
+	"github.com/weaveworks/ignite/pkg/bootcache"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// BootCacheFlags contains the flags supported by boot-cache.
+type BootCacheFlags struct {
+	Clear bool
+}
+
+// BootCache lists the entries of the boot cache, or removes them all
+func BootCache(bf *BootCacheFlags) error {
+	manifests, err := bootcache.List()
+	if err != nil {
+		return err
+	}
+
+	if bf.Clear {
+		for _, manifest := range manifests {
+			if err := bootcache.Remove(manifest.Key); err != nil {
+				return err
+			}
+
+			fmt.Println(manifest.Key)
+		}
+
+		return nil
+	}
+
+	o := util.NewOutput()
+	defer o.Flush()
+
+	o.Write("KEY", "IMAGE", "KERNEL", "CPUS", "MEMORY", "DISK SIZE", "SIZE", "CREATED", "STATUS")
+	for _, manifest := range manifests {
+		size, err := util.DiskUsage(bootcache.Dir(manifest.Key))
+		if err != nil {
+			return err
+		}
+
+		status := "Ready"
+		if len(manifest.Unsupported) > 0 {
+			status = fmt.Sprintf("Unsupported: %s", manifest.Unsupported)
+		}
+
+		o.Write(manifest.Key, imageName(manifest.Image), kernelName(manifest.Kernel), manifest.CPUs, manifest.Memory,
+			manifest.DiskSize, formatBytes(size), fmt.Sprint(manifest.Created, " ago"), status)
+	}
+
+	return nil
+}
+
+// imageName returns the name of the image with the UID, or the UID if it's not found
+func imageName(uid runtime.UID) string {
+	if image, err := providers.Client.Images().Get(uid); err == nil {
+		return image.GetName()
+	}
+
+	return uid.String()
+}
+
+// kernelName returns the name of the kernel with the UID, or the UID if it's not found
+func kernelName(uid runtime.UID) string {
+	if kernel, err := providers.Client.Kernels().Get(uid); err == nil {
+		return kernel.GetName()
+	}
+
+	return uid.String()
+}
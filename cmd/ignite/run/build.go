@@ -0,0 +1,95 @@
+package run
+
+import (
+	"fmt"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/build"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+)
+
+// BuildFlags contains the flags supported by image build.
+type BuildFlags struct {
+	Tag          string
+	File         string
+	BuildArgs    []string
+	Target       string
+	NoCache      bool
+	Builder      string
+	BuildKitAddr string
+	WithAgent    bool
+}
+
+type BuildOptions struct {
+	*BuildFlags
+	build *build.Options
+}
+
+// NewBuildOptions validates the flags, and constructs the build of the given context directory
+func (bf *BuildFlags) NewBuildOptions(contextDir string) (*BuildOptions, error) {
+	if len(bf.Tag) == 0 {
+		return nil, fmt.Errorf("the name of the image to build needs to be given with --tag")
+	}
+
+	ociRef, err := meta.NewOCIImageRef(bf.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := build.Builder(bf.Builder)
+	if !isValidBuilder(builder) {
+		return nil, fmt.Errorf("unknown builder %q, supported builders are %v", bf.Builder, build.ListBuilders())
+	}
+
+	return &BuildOptions{
+		BuildFlags: bf,
+		build: &build.Options{
+			ContextDir:   contextDir,
+			File:         bf.File,
+			Image:        ociRef,
+			BuildArgs:    bf.BuildArgs,
+			Target:       bf.Target,
+			NoCache:      bf.NoCache,
+			Builder:      builder,
+			BuildKitAddr: bf.BuildKitAddr,
+		},
+	}, nil
+}
+
+// Build builds the OCI image, and imports the result as an ignite image
+func Build(bo *BuildOptions) (*api.Image, error) {
+	// Populate the runtime provider, it stores the built image.
+	if err := config.SetAndPopulateProviders(providers.RuntimeName, providers.NetworkPluginName); err != nil {
+		return nil, err
+	}
+
+	// The import would return an image that's already imported under the name instead of the build
+	image, err := providers.Client.Images().Find(filter.NewIDNameFilter(bo.build.Image.String()))
+	switch err.(type) {
+	case nil:
+		return nil, fmt.Errorf("image %q is already imported with UID %q, remove it or build with another tag", bo.build.Image, image.GetUID())
+	case *filterer.NonexistentError:
+	default:
+		return nil, err
+	}
+
+	if err := build.Build(bo.build); err != nil {
+		return nil, err
+	}
+
+	return ImportImage(bo.build.Image.String(), bo.WithAgent)
+}
+
+func isValidBuilder(builder build.Builder) bool {
+	for _, b := range build.ListBuilders() {
+		if b == builder {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package run
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/backup"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// CheckpointFlags contains the flags supported by the checkpoint command.
+type CheckpointFlags struct {
+	List   bool
+	Remove bool
+}
+
+type CheckpointOptions struct {
+	*CheckpointFlags
+	vm         *api.VM
+	checkpoint string
+}
+
+func (cf *CheckpointFlags) NewCheckpointOptions(vmMatch, checkpoint string) (co *CheckpointOptions, err error) {
+	if cf.List && cf.Remove {
+		return nil, fmt.Errorf("the list and remove flags are mutually exclusive")
+	}
+
+	if cf.Remove && len(checkpoint) == 0 {
+		return nil, fmt.Errorf("the checkpoint to remove needs to be given")
+	}
+
+	co = &CheckpointOptions{CheckpointFlags: cf, checkpoint: checkpoint}
+	co.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// Checkpoint takes a checkpoint of the disk of a stopped VM, or lists or removes its checkpoints
+func Checkpoint(co *CheckpointOptions) error {
+	switch {
+	case co.List:
+		checkpoints, err := backup.ListCheckpoints(co.vm)
+		if err != nil {
+			return err
+		}
+
+		o := util.NewOutput()
+		defer o.Flush()
+
+		o.Write("CHECKPOINT", "CREATED", "SIZE")
+		for _, c := range checkpoints {
+			o.Write(c.Name, c.Created.Local().Format("2006-01-02 15:04:05"), c.Usage)
+		}
+	case co.Remove:
+		if err := backup.RemoveCheckpoint(co.vm, co.checkpoint); err != nil {
+			return err
+		}
+
+		log.Infof("Removed checkpoint %q of VM %q", co.checkpoint, co.vm.GetUID())
+	default:
+		checkpoint, err := backup.CreateCheckpoint(co.vm, co.checkpoint)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Took checkpoint %q of VM %q", checkpoint.Name, co.vm.GetUID())
+	}
+
+	return nil
+}
+
+type RollbackOptions struct {
+	vm         *api.VM
+	checkpoint string
+}
+
+func NewRollbackOptions(vmMatch, checkpoint string) (ro *RollbackOptions, err error) {
+	ro = &RollbackOptions{checkpoint: checkpoint}
+	ro.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// Rollback reverts the disk of a stopped VM to one of its checkpoints
+func Rollback(ro *RollbackOptions) error {
+	if err := backup.Rollback(ro.vm, ro.checkpoint); err != nil {
+		return err
+	}
+
+	log.Infof("Rolled back VM %q to checkpoint %q", ro.vm.GetUID(), ro.checkpoint)
+	return nil
+}
@@ -0,0 +1,97 @@
+package run
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	"github.com/weaveworks/ignite/pkg/backup"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/policy"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// CloneFlags contains the flags supported by the clone command.
+type CloneFlags struct {
+	Name string
+}
+
+type CloneOptions struct {
+	*CloneFlags
+	source *api.VM
+	vm     *api.VM
+}
+
+func (cf *CloneFlags) NewCloneOptions(vmMatch string) (co *CloneOptions, err error) {
+	co = &CloneOptions{CloneFlags: cf}
+	if co.source, err = getVMForMatch(vmMatch); err != nil {
+		return
+	}
+
+	if co.source.Running() {
+		return nil, fmt.Errorf("VM %q is running, stop it before cloning it", co.source.GetUID())
+	}
+
+	// Populate the runtime and network-plugin providers for the clone
+	if err = config.SetAndPopulateProviders(providers.RuntimeName, providers.NetworkPluginName); err != nil {
+		return
+	}
+
+	// The clone gets its own UID and name, and the status of a new VM
+	co.vm = providers.Client.VMs().New()
+	co.source.Spec.DeepCopyInto(&co.vm.Spec)
+	co.vm.SetName(cf.Name)
+	for key, value := range co.source.Labels {
+		co.vm.SetLabel(key, value)
+	}
+	for key, value := range co.source.Annotations {
+		co.vm.SetAnnotation(key, value)
+	}
+
+	co.vm.Status.IDPrefix = providers.IDPrefix
+	co.vm.Status.Runtime.Name = providers.RuntimeName
+	co.vm.Status.Network.Plugin = providers.NetworkPluginName
+	// The disk is copied between snapshots of the same kind
+	co.vm.Status.Snapshotter = co.source.Status.Snapshotter
+	co.vm.Status.Image = co.source.Status.Image
+	co.vm.Status.Kernel = co.source.Status.Kernel
+
+	err = validation.ValidateVM(co.vm).ToAggregate()
+	return
+}
+
+// Clone creates a new VM with the spec and a copy of the disk of the source VM.
+// The clone gets a fresh identity: a new UID, hostname, SSH host keys, machine
+// ID and generated SSH key pair. The MAC and IP addresses are assigned on start.
+func Clone(co *CloneOptions) (err error) {
+	if err = metadata.SetNameAndUID(co.vm, providers.Client); err != nil {
+		return
+	}
+	defer util.DeferErr(&err, func() error { return metadata.Cleanup(co.vm, false) })
+
+	if err = policy.CheckVM(context.Background(), policy.OperationVMCreate, co.vm); err != nil {
+		return
+	}
+
+	if err = providers.Client.VMs().Set(co.vm); err != nil {
+		return
+	}
+
+	if err = operations.AllocateOverlay(co.vm); err != nil {
+		return
+	}
+
+	if err = backup.CopyDisk(co.source, co.vm); err != nil {
+		return fmt.Errorf("failed to copy the disk of VM %q: %v", co.source.GetUID(), err)
+	}
+
+	if err = operations.PopulateClonedOverlay(co.vm, co.source); err != nil {
+		return
+	}
+
+	return metadata.Success(co.vm)
+}
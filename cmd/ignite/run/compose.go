@@ -0,0 +1,705 @@
+package run
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/network"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// defaultComposeNetwork is the network of the VMs of a stack that don't list any networks
+const defaultComposeNetwork = "default"
+
+// composeVMKeys are the keys of a VM of a stack that aren't fields of the VM spec
+var composeVMKeys = []string{"dependsOn", "networks", "volumes", "labels"}
+
+// composeVarRegexp matches the variables substituted in a stack file: $$, $NAME, ${NAME}
+// and ${NAME<op><word>} with the :-, -, :? and ? operators
+var composeVarRegexp = regexp.MustCompile(`\$(?:(\$)|([A-Za-z_][A-Za-z0-9_]*)|\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?])([^}]*))?\})`)
+
+type ComposeFlags struct {
+	Filename    string
+	ProjectName string
+	DryRun      bool
+}
+
+type ComposeOptions struct {
+	*ComposeFlags
+	stack *stack
+}
+
+// stackFile is the format of a stack file
+type stackFile struct {
+	// Name is the name of the stack, it defaults to the name of the directory of the file
+	Name     string                     `json:"name,omitempty"`
+	Networks map[string]*stackNetwork   `json:"networks,omitempty"`
+	Volumes  map[string]*api.Volume     `json:"volumes,omitempty"`
+	VMs      map[string]json.RawMessage `json:"vms"`
+}
+
+// stackNetwork is a network of a stack, the VMs on it resolve each other's names
+type stackNetwork struct {
+	// Plugin is the network plugin the VMs on the network run with
+	// Default: unset, the network plugin of the ignite configuration and flags
+	Plugin network.PluginName `json:"plugin,omitempty"`
+}
+
+// stackVMKeys are the keys of a VM of a stack that aren't fields of the VM spec
+type stackVMKeys struct {
+	// DependsOn are the VMs of the stack started before the VM
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Networks are the networks of the stack the VM is on
+	Networks []string `json:"networks,omitempty"`
+	// Volumes are the volumes of the stack mounted in the VM, as <volume>:<path>
+	Volumes []string          `json:"volumes,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// stack is a loaded stack file, with its VMs in the order they're started in
+type stack struct {
+	project string
+	source  string
+	vms     []*stackVM
+}
+
+// stackVM is a VM of a stack
+type stackVM struct {
+	// name is the name of the VM in the stack, the VM is named <project>-<name>
+	name      string
+	vmName    string
+	dependsOn []string
+	networks  []string
+	manifest  []byte
+}
+
+func (cf *ComposeFlags) NewComposeOptions() (*ComposeOptions, error) {
+	s, err := loadStack(cf.Filename, cf.ProjectName, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ComposeOptions{ComposeFlags: cf, stack: s}, nil
+}
+
+// ComposeUp creates the VMs of the stack that don't exist, updates the ones that
+// changed, and starts the ones that aren't running in dependency order. Before a VM
+// is started, the names of the running VMs it shares a network with are written to
+// its /etc/hosts.
+func ComposeUp(co *ComposeOptions, fs *flag.FlagSet) error {
+	if err := populateApplyProviders(); err != nil {
+		return err
+	}
+
+	manifests := make([]manifest, 0, len(co.stack.vms))
+	for _, vm := range co.stack.vms {
+		manifests = append(manifests, manifest{
+			source:  fmt.Sprintf("%s (VM %q)", co.stack.source, vm.name),
+			content: vm.manifest,
+		})
+	}
+
+	actions, err := planApply(manifests, fs)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range actions {
+		if a.kind != api.KindVM || a.action != applyStart {
+			continue
+		}
+
+		start, name := a.run, a.name
+		a.run = func() error {
+			if err := co.stack.setHosts(name); err != nil {
+				return err
+			}
+
+			return start()
+		}
+	}
+
+	if err := runApply(actions, co.DryRun); err != nil {
+		return err
+	}
+
+	vms, err := co.stack.projectVMs()
+	if err != nil {
+		return err
+	}
+
+	for _, vm := range vms {
+		if co.stack.vm(vm.GetLabel(constants.IGNITE_COMPOSE_VM_LABEL)) == nil {
+			log.Warnf("VM %q isn't in %s anymore, remove it with \"ignite rm\" or \"ignite compose down\"", vm.GetName(), co.stack.source)
+		}
+	}
+
+	return nil
+}
+
+// ComposeDown stops and removes the VMs of the stack in reverse dependency order,
+// including the VMs that were removed from the stack file
+func ComposeDown(co *ComposeOptions) error {
+	vms, err := co.stack.projectVMs()
+	if err != nil {
+		return err
+	}
+
+	// VMs that were removed from the stack file go first, then the VMs depending on others
+	position := make(map[string]int, len(co.stack.vms))
+	for i, vm := range co.stack.vms {
+		position[vm.name] = i + 1
+	}
+	sort.SliceStable(vms, func(i, j int) bool {
+		return position[vms[i].GetLabel(constants.IGNITE_COMPOSE_VM_LABEL)] > position[vms[j].GetLabel(constants.IGNITE_COMPOSE_VM_LABEL)]
+	})
+
+	for _, vm := range vms {
+		if vm.Running() {
+			if err := Stop(&StopOptions{StopFlags: &StopFlags{}, vms: []*api.VM{vm}}); err != nil {
+				return err
+			}
+		}
+
+		// The VM may still be recorded as running if it didn't stop in time
+		if err := Rm(&RmOptions{RmFlags: &RmFlags{Force: true}, vms: []*api.VM{vm}}); err != nil {
+			return err
+		}
+	}
+
+	if len(vms) == 0 {
+		log.Infof("No VMs of stack %q found", co.stack.project)
+	}
+
+	return nil
+}
+
+// projectVMs returns the VMs of the stack
+func (s *stack) projectVMs() ([]*api.VM, error) {
+	return getVMsForSelector(constants.IGNITE_COMPOSE_PROJECT_LABEL + "=" + s.project)
+}
+
+// vm returns the VM of the stack with the given name, or nil
+func (s *stack) vm(name string) *stackVM {
+	for _, vm := range s.vms {
+		if vm.name == name {
+			return vm
+		}
+	}
+
+	return nil
+}
+
+// setHosts sets the host annotations of the VM to the names and IP addresses of the
+// running VMs of the stack it shares a network with
+func (s *stack) setHosts(vmName string) error {
+	vm, err := providers.Client.VMs().Find(filter.NewNameFilter(vmName))
+	if err != nil {
+		return err
+	}
+
+	projectVMs, err := s.projectVMs()
+	if err != nil {
+		return err
+	}
+
+	hosts := s.hosts(s.vm(vm.GetLabel(constants.IGNITE_COMPOSE_VM_LABEL)), projectVMs)
+	if equalStringMaps(hosts, vm.Hosts()) {
+		return nil
+	}
+
+	for k := range vm.GetObjectMeta().Annotations {
+		if strings.HasPrefix(k, constants.IGNITE_HOST_ANNOTATION) {
+			delete(vm.GetObjectMeta().Annotations, k)
+		}
+	}
+
+	for name, ip := range hosts {
+		vm.SetAnnotation(constants.IGNITE_HOST_ANNOTATION+name, ip)
+	}
+
+	return providers.Client.VMs().Set(vm)
+}
+
+// hosts returns the IP addresses of the running VMs sharing a network with the VM, by
+// their name in the stack and their VM name
+func (s *stack) hosts(vm *stackVM, projectVMs []*api.VM) map[string]string {
+	hosts := map[string]string{}
+	if vm == nil {
+		return hosts
+	}
+
+	for _, peer := range projectVMs {
+		other := s.vm(peer.GetLabel(constants.IGNITE_COMPOSE_VM_LABEL))
+		if other == nil || other == vm || !peer.Running() || peer.Status.Network == nil || len(peer.Status.Network.IPAddresses) == 0 {
+			continue
+		}
+
+		if !sharesNetwork(vm, other) {
+			continue
+		}
+
+		ip := peer.Status.Network.IPAddresses[0].String()
+		hosts[other.name] = ip
+		hosts[other.vmName] = ip
+	}
+
+	return hosts
+}
+
+func sharesNetwork(a, b *stackVM) bool {
+	for _, n := range a.networks {
+		for _, m := range b.networks {
+			if n == m {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			return false
+		}
+	}
+
+	return true
+}
+
+// loadStack reads the stack file, substitutes the variables in it, and builds the
+// manifests of its VMs. The project name defaults to the name of the stack, and to
+// the name of the directory of the file.
+func loadStack(filename, project string, lookupEnv func(string) (string, bool)) (*stack, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	// The variables of the environment take precedence over the .env file next to the stack file
+	dotEnv, err := readDotEnv(filepath.Join(dir, ".env"))
+	if err != nil {
+		return nil, err
+	}
+
+	content, err = substituteVars(content, func(name string) (string, bool) {
+		if value, ok := lookupEnv(name); ok {
+			return value, true
+		}
+
+		value, ok := dotEnv[name]
+		return value, ok
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	jsonContent, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", filename, err)
+	}
+
+	sf := &stackFile{}
+	if err := strictUnmarshal(jsonContent, sf); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", filename, err)
+	}
+
+	if len(project) == 0 {
+		project = sf.Name
+	}
+	if len(project) == 0 {
+		project = projectName(filepath.Base(dir))
+	}
+
+	s, err := buildStack(sf, project)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	s.source = filename
+	return s, nil
+}
+
+// buildStack validates the stack file, and builds the manifests of its VMs in the order
+// they're started in
+func buildStack(sf *stackFile, project string) (*stack, error) {
+	if errs := validation.IsDNS1123Label(project); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid stack name %q: %s", project, strings.Join(errs, ", "))
+	}
+
+	if len(sf.VMs) == 0 {
+		return nil, fmt.Errorf("the stack has no VMs")
+	}
+
+	for name, n := range sf.Networks {
+		if n == nil {
+			sf.Networks[name] = &stackNetwork{}
+		} else if len(n.Plugin) > 0 && !knownNetworkPlugin(n.Plugin) {
+			return nil, fmt.Errorf("network %q has an unknown network plugin %q", name, n.Plugin)
+		}
+	}
+
+	for name, v := range sf.Volumes {
+		if v == nil {
+			return nil, fmt.Errorf("volume %q needs a blockDevice, tmpfs or nbd source", name)
+		}
+	}
+
+	vms := make(map[string]*stackVM, len(sf.VMs))
+	deps := make(map[string][]string, len(sf.VMs))
+	volumeUsers := map[string][]string{}
+	for name, raw := range sf.VMs {
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid VM name %q: %s", name, strings.Join(errs, ", "))
+		}
+
+		vm, err := buildStackVM(sf, project, name, raw)
+		if err != nil {
+			return nil, fmt.Errorf("VM %q: %v", name, err)
+		}
+
+		for _, v := range vm.volumes {
+			volumeUsers[v] = append(volumeUsers[v], name)
+		}
+
+		vms[name] = vm.stackVM
+		deps[name] = vm.dependsOn
+	}
+
+	// The guests would corrupt a filesystem on a device they mount at the same time
+	for name, users := range volumeUsers {
+		if v := sf.Volumes[name]; len(users) > 1 && (v.BlockDevice != nil || v.NBD != nil) {
+			sort.Strings(users)
+			return nil, fmt.Errorf("volume %q is backed by a device, it can only be mounted by one VM, not by %s", name, strings.Join(users, ", "))
+		}
+	}
+
+	order, err := orderStack(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &stack{project: project}
+	for _, name := range order {
+		s.vms = append(s.vms, vms[name])
+	}
+
+	return s, nil
+}
+
+// builtStackVM is a VM of a stack, with the volumes it mounts
+type builtStackVM struct {
+	*stackVM
+	volumes []string
+}
+
+// buildStackVM builds the VM manifest of a VM of the stack. The keys of the VM that
+// aren't in composeVMKeys are the spec of the VM.
+func buildStackVM(sf *stackFile, project, name string, raw json.RawMessage) (*builtStackVM, error) {
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]interface{}{}
+	for _, key := range composeVMKeys {
+		if value, ok := spec[key]; ok {
+			keys[key] = value
+			delete(spec, key)
+		}
+	}
+
+	b, err := json.Marshal(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	vmKeys := &stackVMKeys{}
+	if err := strictUnmarshal(b, vmKeys); err != nil {
+		return nil, err
+	}
+
+	vm := &builtStackVM{stackVM: &stackVM{
+		name:      name,
+		vmName:    project + "-" + name,
+		dependsOn: vmKeys.DependsOn,
+		networks:  vmKeys.Networks,
+	}}
+
+	// The dependencies are also started first when the VMs are autostarted
+	if len(vm.dependsOn) > 0 {
+		after, _ := spec["autostartAfter"].([]interface{})
+		for _, dep := range vm.dependsOn {
+			if _, ok := sf.VMs[dep]; !ok {
+				return nil, fmt.Errorf("depends on unknown VM %q", dep)
+			}
+
+			after = append(after, project+"-"+dep)
+		}
+		spec["autostartAfter"] = after
+	}
+
+	if len(vm.networks) == 0 {
+		vm.networks = []string{defaultComposeNetwork}
+	}
+
+	var plugin network.PluginName
+	for _, n := range vm.networks {
+		stackNet, ok := sf.Networks[n]
+		if !ok {
+			if n != defaultComposeNetwork {
+				return nil, fmt.Errorf("unknown network %q", n)
+			}
+
+			continue
+		}
+
+		if len(stackNet.Plugin) == 0 {
+			continue
+		}
+
+		if len(plugin) > 0 && plugin != stackNet.Plugin {
+			return nil, fmt.Errorf("the networks of the VM use different network plugins, %q and %q", plugin, stackNet.Plugin)
+		}
+		plugin = stackNet.Plugin
+	}
+
+	if len(plugin) > 0 {
+		if specPlugin, ok := spec["networkPlugin"]; ok && specPlugin != plugin.String() {
+			return nil, fmt.Errorf("networkPlugin %q differs from the network plugin %q of its networks", specPlugin, plugin)
+		}
+		spec["networkPlugin"] = plugin.String()
+	}
+
+	if len(vmKeys.Volumes) > 0 {
+		storage, _ := spec["storage"].(map[string]interface{})
+		if storage == nil {
+			storage = map[string]interface{}{}
+		}
+		volumes, _ := storage["volumes"].([]interface{})
+		mounts, _ := storage["volumeMounts"].([]interface{})
+
+		for _, v := range vmKeys.Volumes {
+			parts := strings.SplitN(v, ":", 2)
+			if len(parts) != 2 || len(parts[1]) == 0 {
+				return nil, fmt.Errorf("invalid volume %q, volumes are mounted as <volume>:<path>", v)
+			}
+
+			volume, ok := sf.Volumes[parts[0]]
+			if !ok {
+				return nil, fmt.Errorf("unknown volume %q", parts[0])
+			}
+
+			source := *volume
+			source.Name = parts[0]
+			volumes = append(volumes, source)
+			mounts = append(mounts, api.VolumeMount{Name: parts[0], MountPath: parts[1]})
+			vm.volumes = append(vm.volumes, parts[0])
+		}
+
+		storage["volumes"], storage["volumeMounts"] = volumes, mounts
+		spec["storage"] = storage
+	}
+
+	labels := map[string]string{}
+	for k, v := range vmKeys.Labels {
+		labels[k] = v
+	}
+	labels[constants.IGNITE_COMPOSE_PROJECT_LABEL] = project
+	labels[constants.IGNITE_COMPOSE_VM_LABEL] = name
+
+	vm.manifest, err = json.Marshal(map[string]interface{}{
+		"apiVersion": v1alpha5.SchemeGroupVersion.String(),
+		"kind":       api.KindVM,
+		"metadata": map[string]interface{}{
+			"name":   vm.vmName,
+			"labels": labels,
+		},
+		"spec":   spec,
+		"status": map[string]interface{}{"running": true},
+	})
+
+	return vm, err
+}
+
+// orderStack orders the VMs of a stack so that every VM comes after the VMs it depends
+// on, VMs without dependencies between them are ordered by name
+func orderStack(deps map[string][]string) ([]string, error) {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var order []string
+	visiting, ordered := map[string]bool{}, map[string]bool{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if visiting[name] {
+			return fmt.Errorf("VM %q depends on itself: %s", name, strings.Join(append(path, name), " -> "))
+		} else if ordered[name] {
+			return nil
+		}
+
+		visiting[name] = true
+		for _, dep := range deps[name] {
+			if _, ok := deps[dep]; !ok {
+				return fmt.Errorf("VM %q depends on unknown VM %q", name, dep)
+			}
+
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		visiting[name], ordered[name] = false, true
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// substituteVars replaces the variables in the content by their values: $NAME and
+// ${NAME} by the value of NAME, ${NAME:-word} by word if NAME is unset or empty,
+// ${NAME-word} by word if NAME is unset, ${NAME:?message} and ${NAME?message} fail
+// with the message if NAME is unset (or empty), and $$ by $. Unset variables without
+// a default are replaced by an empty string, with a warning.
+func substituteVars(content []byte, lookup func(string) (string, bool)) ([]byte, error) {
+	var errs []string
+	warned := map[string]bool{}
+	result := composeVarRegexp.ReplaceAllFunc(content, func(match []byte) []byte {
+		groups := composeVarRegexp.FindSubmatch(match)
+		if len(groups[1]) > 0 {
+			return []byte("$")
+		}
+
+		name, op, word := string(groups[2]), string(groups[4]), string(groups[5])
+		if len(name) == 0 {
+			name = string(groups[3])
+		}
+
+		value, ok := lookup(name)
+		set := ok && (len(value) > 0 || !strings.HasPrefix(op, ":"))
+		switch op {
+		case ":-", "-":
+			if !set {
+				value = word
+			}
+		case ":?", "?":
+			if !set {
+				if len(word) == 0 {
+					word = "is required"
+				}
+				errs = append(errs, fmt.Sprintf("variable %s %s", name, word))
+			}
+		default:
+			if !ok && !warned[name] {
+				log.Warnf("The variable %s isn't set, substituting an empty string", name)
+				warned[name] = true
+			}
+		}
+
+		return []byte(value)
+	})
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+
+	return result, nil
+}
+
+// readDotEnv reads the NAME=value lines of a .env file, it's fine for it not to exist
+func readDotEnv(path string) (map[string]string, error) {
+	env := map[string]string{}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return env, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for i := 1; scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected NAME=value", path, i)
+		}
+
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		env[strings.TrimSpace(parts[0])] = value
+	}
+
+	return env, scanner.Err()
+}
+
+// projectName turns the name of a directory into a stack name
+func projectName(dir string) string {
+	name := strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(dir), "-"), "-")
+	if len(name) > validation.DNS1123LabelMaxLength {
+		name = strings.Trim(name[:validation.DNS1123LabelMaxLength], "-")
+	}
+
+	return name
+}
+
+func knownNetworkPlugin(plugin network.PluginName) bool {
+	for _, p := range network.ListPlugins() {
+		if p == plugin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// strictUnmarshal decodes the JSON into obj, failing on unknown fields
+func strictUnmarshal(b []byte, obj interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	return d.Decode(obj)
+}
@@ -0,0 +1,235 @@
+package run
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+const composeTestStack = `name: devstack
+networks:
+  backend:
+    plugin: cni
+volumes:
+  pgdata:
+    blockDevice:
+      path: /dev/vg0/pgdata
+  scratch:
+    tmpfs:
+      size: 256MB
+vms:
+  web:
+    image:
+      oci: weaveworks/ignite-ubuntu:${TAG:-latest}
+    cpus: ${WEB_CPUS}
+    networks: [default, backend]
+    volumes: ["scratch:/tmp/scratch"]
+    dependsOn: [db]
+    labels:
+      tier: frontend
+  db:
+    image:
+      oci: weaveworks/ignite-ubuntu:${TAG:-latest}
+    memory: 1GB
+    networks: [backend]
+    volumes: ["pgdata:/var/lib/postgresql", "scratch:/tmp/scratch"]
+  cache:
+    image:
+      oci: weaveworks/ignite-ubuntu:latest
+`
+
+func TestLoadStack(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-compose-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "stack.yaml")
+	assert.NilError(t, ioutil.WriteFile(filename, []byte(composeTestStack), 0644))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, ".env"), []byte("# defaults\nTAG=20.04\nWEB_CPUS=\"1\"\n"), 0644))
+
+	// The environment takes precedence over the .env file
+	env := map[string]string{"WEB_CPUS": "2"}
+	s, err := loadStack(filename, "", func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, s.project, "devstack")
+
+	var names []string
+	for _, vm := range s.vms {
+		names = append(names, vm.name)
+	}
+	assert.DeepEqual(t, names, []string{"cache", "db", "web"})
+
+	var web map[string]interface{}
+	assert.NilError(t, json.Unmarshal(s.vm("web").manifest, &web))
+	assert.DeepEqual(t, web, map[string]interface{}{
+		"apiVersion": "ignite.weave.works/v1alpha5",
+		"kind":       "VM",
+		"metadata": map[string]interface{}{
+			"name": "devstack-web",
+			"labels": map[string]interface{}{
+				"tier":                               "frontend",
+				"ignite.weave.works/compose-project": "devstack",
+				"ignite.weave.works/compose-vm":      "web",
+			},
+		},
+		"spec": map[string]interface{}{
+			"image":          map[string]interface{}{"oci": "weaveworks/ignite-ubuntu:20.04"},
+			"cpus":           float64(2),
+			"autostartAfter": []interface{}{"devstack-db"},
+			"networkPlugin":  "cni",
+			"storage": map[string]interface{}{
+				"volumes":      []interface{}{map[string]interface{}{"name": "scratch", "tmpfs": map[string]interface{}{"size": "256MB"}}},
+				"volumeMounts": []interface{}{map[string]interface{}{"name": "scratch", "mountPath": "/tmp/scratch"}},
+			},
+		},
+		"status": map[string]interface{}{"running": true},
+	})
+
+	// The project name given on the command line takes precedence
+	s, err = loadStack(filename, "other", func(string) (string, bool) { return "", false })
+	assert.NilError(t, err)
+	assert.Equal(t, s.vm("db").vmName, "other-db")
+}
+
+func TestBuildStackErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		stack string
+		err   string
+	}{
+		{
+			name:  "no VMs",
+			stack: `{"vms": {}}`,
+			err:   "the stack has no VMs",
+		},
+		{
+			name:  "unknown dependency",
+			stack: `{"vms": {"web": {"dependsOn": ["db"]}}}`,
+			err:   `VM "web": depends on unknown VM "db"`,
+		},
+		{
+			name:  "dependency cycle",
+			stack: `{"vms": {"a": {"dependsOn": ["b"]}, "b": {"dependsOn": ["a"]}}}`,
+			err:   `VM "a" depends on itself: a -> b -> a`,
+		},
+		{
+			name:  "unknown network",
+			stack: `{"vms": {"web": {"networks": ["front"]}}}`,
+			err:   `VM "web": unknown network "front"`,
+		},
+		{
+			name:  "networks with different plugins",
+			stack: `{"networks": {"a": {"plugin": "cni"}, "b": {"plugin": "docker-bridge"}}, "vms": {"web": {"networks": ["a", "b"]}}}`,
+			err:   `VM "web": the networks of the VM use different network plugins, "cni" and "docker-bridge"`,
+		},
+		{
+			name:  "device volume mounted twice",
+			stack: `{"volumes": {"data": {"blockDevice": {"path": "/dev/sdb"}}}, "vms": {"a": {"volumes": ["data:/data"]}, "b": {"volumes": ["data:/data"]}}}`,
+			err:   `volume "data" is backed by a device, it can only be mounted by one VM, not by a, b`,
+		},
+		{
+			name:  "invalid volume mount",
+			stack: `{"volumes": {"data": {"tmpfs": {}}}, "vms": {"a": {"volumes": ["data"]}}}`,
+			err:   `VM "a": invalid volume "data", volumes are mounted as <volume>:<path>`,
+		},
+		{
+			name:  "invalid VM name",
+			stack: `{"vms": {"Web_1": {}}}`,
+			err:   `invalid VM name "Web_1": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			sf := &stackFile{}
+			assert.NilError(t, strictUnmarshal([]byte(rt.stack), sf))
+
+			_, err := buildStack(sf, "test")
+			assert.Error(t, err, rt.err)
+		})
+	}
+}
+
+func TestSubstituteVars(t *testing.T) {
+	env := map[string]string{"SET": "value", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	cases := []struct {
+		in  string
+		out string
+		err string
+	}{
+		{in: "a: $SET ${SET}", out: "a: value value"},
+		{in: "a: ${UNSET:-default} ${EMPTY:-default} ${EMPTY-default} ${UNSET-default}", out: "a: default default  default"},
+		{in: "a: $$SET $UNSET", out: "a: $SET "},
+		{in: "a: ${SET:?is needed} $5", out: "a: value $5"},
+		{in: "a: ${UNSET:?is needed} ${EMPTY:?}", err: "variable UNSET is needed, variable EMPTY is required"},
+		{in: "a: ${EMPTY?}", out: "a: "},
+	}
+
+	for _, rt := range cases {
+		out, err := substituteVars([]byte(rt.in), lookup)
+		if len(rt.err) > 0 {
+			assert.Error(t, err, rt.err)
+			continue
+		}
+
+		assert.NilError(t, err)
+		assert.Equal(t, string(out), rt.out)
+	}
+}
+
+func TestStackHosts(t *testing.T) {
+	s := &stack{project: "devstack", vms: []*stackVM{
+		{name: "db", vmName: "devstack-db", networks: []string{"backend"}},
+		{name: "cache", vmName: "devstack-cache", networks: []string{"default"}},
+		{name: "web", vmName: "devstack-web", networks: []string{"default", "backend"}},
+		{name: "worker", vmName: "devstack-worker", networks: []string{"backend"}},
+	}}
+
+	newVM := func(name string, ip string) *api.VM {
+		vm := &api.VM{}
+		vm.SetName("devstack-" + name)
+		vm.SetLabel(constants.IGNITE_COMPOSE_VM_LABEL, name)
+		if len(ip) > 0 {
+			vm.Status.Running = true
+			vm.Status.Network = &api.Network{IPAddresses: meta.IPAddresses{net.ParseIP(ip)}}
+		}
+		return vm
+	}
+
+	// The worker isn't running, and the cache isn't on the network of the db
+	vms := []*api.VM{newVM("db", "10.61.0.2"), newVM("cache", "10.61.0.3"), newVM("web", "10.61.0.4"), newVM("worker", "")}
+	assert.DeepEqual(t, s.hosts(s.vm("db"), vms), map[string]string{
+		"web":          "10.61.0.4",
+		"devstack-web": "10.61.0.4",
+	})
+	assert.DeepEqual(t, s.hosts(s.vm("web"), vms), map[string]string{
+		"db":             "10.61.0.2",
+		"devstack-db":    "10.61.0.2",
+		"cache":          "10.61.0.3",
+		"devstack-cache": "10.61.0.3",
+	})
+}
+
+func TestProjectName(t *testing.T) {
+	assert.Equal(t, projectName("My_Dev Stack"), "my-dev-stack")
+	assert.Equal(t, projectName("--x--"), "x")
+}
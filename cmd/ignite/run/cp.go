@@ -1,6 +1,7 @@
 package run
 
 import (
+	"archive/tar"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,10 +11,14 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/pkg/sftp"
+	log "github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 
+	"github.com/weaveworks/ignite/pkg/agent"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/util"
@@ -27,6 +32,7 @@ const VMFilePathSeparator = ":"
 type CPFlags struct {
 	Timeout      uint32
 	IdentityFile string
+	Agent        bool
 }
 
 type CpOptions struct {
@@ -101,57 +107,212 @@ func CP(co *CpOptions) error {
 		return fmt.Errorf("VM %q is not running", co.vm.GetUID())
 	}
 
-	ipAddrs := co.vm.Status.Network.IPAddresses
+	if co.Agent {
+		return cpAgent(co)
+	}
+
+	sftpClient, err := newSFTPClient(co.vm, co.IdentityFile, co.Timeout)
+	if err != nil {
+		return err
+	}
+	defer sftpClient.Close()
+
+	// Clean the source and destination paths.
+	co.source = filepath.Clean(co.source)
+	co.dest = filepath.Clean(co.dest)
+
+	// Copy files based on the copy direction.
+	switch co.copyDirection {
+	case CopyDirectionHostToVM:
+		if err := copyToVM(sftpClient, co.source, co.dest); err != nil {
+			return fmt.Errorf("failed to copy files from host to VM: %v", err)
+		}
+	case CopyDirectionVMToHost:
+		if err := copyFromVM(sftpClient, co.source, co.dest); err != nil {
+			return fmt.Errorf("failed to copy files from VM to host: %v", err)
+		}
+	}
+	return nil
+}
+
+// newSFTPClient connects to the SFTP server of the running VM over SSH
+func newSFTPClient(vm *api.VM, privKeyFile string, timeout uint32) (*sftp.Client, error) {
+	ipAddrs := vm.Status.Network.IPAddresses
 	if len(ipAddrs) == 0 {
-		return fmt.Errorf("VM %q has no usable IP addresses", co.vm.GetUID())
+		return nil, fmt.Errorf("VM %q has no usable IP addresses", vm.GetUID())
+	}
+
+	// Wait for the SSH server in the VM to come up, the VM may have just been started
+	if err := waitForSSH(vm, constants.SSH_DEFAULT_TIMEOUT_SECONDS, time.Duration(timeout)*time.Second); err != nil {
+		return nil, err
 	}
 
-	privKeyFile := co.IdentityFile
 	if len(privKeyFile) == 0 {
-		privKeyFile = path.Join(co.vm.ObjectPath(), fmt.Sprintf(constants.VM_SSH_KEY_TEMPLATE, co.vm.GetUID()))
+		privKeyFile = path.Join(vm.ObjectPath(), fmt.Sprintf(constants.VM_SSH_KEY_TEMPLATE, vm.GetUID()))
 		if !util.FileExists(privKeyFile) {
-			return fmt.Errorf("no private key found for VM %q", co.vm.GetUID())
+			return nil, fmt.Errorf("no private key found for VM %q", vm.GetUID())
 		}
 	}
 
 	// Create a ssh config using the private key.
 	signer, err := newSignerForKey(privKeyFile)
 	if err != nil {
-		return fmt.Errorf("unable to create singer for private key: %v", err)
+		return nil, fmt.Errorf("unable to create singer for private key: %v", err)
 	}
-	config := newSSHConfig(signer, co.Timeout)
+	config := newSSHConfig(signer, timeout)
 
 	// Obtain a ssh client.
 	client, err := ssh.Dial("tcp", net.JoinHostPort(ipAddrs[0].String(), "22"), config)
 	if err != nil {
-		return fmt.Errorf("failed to dial: %v", err)
+		return nil, fmt.Errorf("failed to dial: %v", err)
 	}
 
-	// Use sftp to copy file from source to destination.
+	// Use sftp to copy files between the host and the VM.
 	sftpClient, err := sftp.NewClient(client)
 	if err != nil {
-		return fmt.Errorf("failed to create new sftp client: %v", err)
+		return nil, fmt.Errorf("failed to create new sftp client: %v", err)
 	}
-	defer sftpClient.Close()
 
-	// Clean the source and destination paths.
+	return sftpClient, nil
+}
+
+// cpAgent copies files and directories between the host and the VM using the guest
+// agent of the VM. Directories are streamed as tar archives.
+func cpAgent(co *CpOptions) error {
 	co.source = filepath.Clean(co.source)
 	co.dest = filepath.Clean(co.dest)
 
-	// Copy files based on the copy direction.
 	switch co.copyDirection {
 	case CopyDirectionHostToVM:
-		if err := copyToVM(sftpClient, co.source, co.dest); err != nil {
-			return fmt.Errorf("failed to copy files from host to VM: %v", err)
+		fi, err := os.Stat(co.source)
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() {
+			pr, pw := io.Pipe()
+			go func() {
+				pw.CloseWithError(protocol.Archive(pw, co.source))
+			}()
+			defer pr.Close()
+
+			if err := agent.WriteArchive(co.vm, co.dest, pr); err != nil {
+				return fmt.Errorf("failed to copy files from host to VM: %v", err)
+			}
+			return nil
+		}
+
+		in, err := os.Open(co.source)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		if err := agent.WriteFile(co.vm, co.dest, in, fi.Mode()); err != nil {
+			return fmt.Errorf("failed to copy file from host to VM: %v", err)
 		}
 	case CopyDirectionVMToHost:
-		if err := copyFromVM(sftpClient, co.source, co.dest); err != nil {
+		rc, err := agent.ReadArchive(co.vm, co.source)
+		if err != nil {
+			return fmt.Errorf("failed to copy files from VM to host: %v", err)
+		}
+		defer rc.Close()
+
+		if err := extractFromVM(tar.NewReader(rc), co.source, co.dest); err != nil {
 			return fmt.Errorf("failed to copy files from VM to host: %v", err)
 		}
 	}
+
 	return nil
 }
 
+// extractFromVM writes the file or directory at remotePath in the VM, read from its tar
+// stream, to localPath. Like with SFTP, a file or a directory with a different name is
+// copied into an existing directory at localPath.
+func extractFromVM(tr *tar.Reader, remotePath, localPath string) error {
+	hdr, err := tr.Next()
+	if err == io.EOF {
+		return fmt.Errorf("no files received for %q(VM)", remotePath)
+	} else if err != nil {
+		return err
+	}
+
+	if isDir, err := isDirInHost(localPath); err == nil && isDir {
+		if hdr.Typeflag != tar.TypeDir || filepath.Base(remotePath) != filepath.Base(localPath) {
+			localPath = filepath.Join(localPath, filepath.Base(remotePath))
+		}
+	} else if err == nil && hdr.Typeflag == tar.TypeDir {
+		return fmt.Errorf("cannot overwrite non-directory %q(Host) with directory %q(VM)", localPath, remotePath)
+	}
+
+	for ; err == nil; hdr, err = tr.Next() {
+		// The archive comes from the VM, it can't write through the symlinks it created
+		lPath, err := protocol.ArchiveJoin(localPath, hdr.Name)
+		if err != nil {
+			return err
+		}
+		mode := hdr.FileInfo().Mode()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if fi, statErr := os.Lstat(lPath); statErr == nil && !fi.IsDir() {
+				return fmt.Errorf("cannot overwrite non-directory %q(Host) with directory %q(VM)", lPath, hdr.Name)
+			}
+			if err := os.MkdirAll(lPath, mode.Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := removeNonDir(lPath); err != nil {
+				return err
+			}
+			if err := os.Symlink(hdr.Linkname, lPath); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := removeNonDir(lPath); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(lPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL|syscall.O_NOFOLLOW, mode.Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, tr)
+			if err == nil {
+				err = out.Chmod(mode)
+			}
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			log.Warnf("Skipping %q(VM), only directories, regular files and symlinks are copied", hdr.Name)
+		}
+	}
+
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// removeNonDir removes the file or symlink at path if it exists, so it can be created anew
+// without following a symlink. Existing directories can't be replaced.
+func removeNonDir(path string) error {
+	fi, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if fi.IsDir() {
+		return fmt.Errorf("cannot overwrite directory %q(Host) with non-directory", path)
+	}
+	return os.Remove(path)
+}
+
 // copyToVM copies from host to VM.
 func copyToVM(client *sftp.Client, localPath, remotePath string) error {
 	// Check if the source exists.
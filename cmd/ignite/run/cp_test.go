@@ -1,10 +1,15 @@
 package run
 
 import (
+	"archive/tar"
+	"bytes"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
@@ -150,3 +155,130 @@ func TestNewCPOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractFromVM(t *testing.T) {
+	src, err := ioutil.TempDir("", "cp-src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	project := filepath.Join(src, "project")
+	if err := os.MkdirAll(filepath.Join(project, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(project, "src", "main.go"), []byte("package main"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		existing string // created in the destination directory before copying
+		dest     string
+		wantFile string
+		err      bool
+	}{
+		{
+			name:     "new directory",
+			dest:     "copy",
+			wantFile: "copy/src/main.go",
+		},
+		{
+			name:     "into existing directory",
+			existing: "vm-files/",
+			dest:     "vm-files",
+			wantFile: "vm-files/project/src/main.go",
+		},
+		{
+			name:     "existing directory with the same name",
+			existing: "project/",
+			dest:     "project",
+			wantFile: "project/src/main.go",
+		},
+		{
+			name:     "existing file",
+			existing: "project",
+			dest:     "project",
+			err:      true,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			dest, err := ioutil.TempDir("", "cp-dest")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dest)
+
+			if strings.HasSuffix(rt.existing, "/") {
+				err = os.Mkdir(filepath.Join(dest, rt.existing), 0755)
+			} else if len(rt.existing) > 0 {
+				err = ioutil.WriteFile(filepath.Join(dest, rt.existing), nil, 0644)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := protocol.Archive(&buf, project); err != nil {
+				t.Fatal(err)
+			}
+
+			err = extractFromVM(tar.NewReader(&buf), "/root/project", filepath.Join(dest, rt.dest))
+			if (err != nil) != rt.err {
+				t.Fatalf("expected error %t, actual: %v", rt.err, err)
+			}
+			if rt.err {
+				return
+			}
+
+			fi, err := os.Stat(filepath.Join(dest, rt.wantFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fi.Mode().Perm() != 0600 {
+				t.Errorf("expected mode 0600, actual: %o", fi.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestExtractFromVMSymlinkEscape(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cp-escape")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	outside := filepath.Join(dir, "outside")
+	if err := os.Mkdir(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The VM links a directory to the outside, and then writes a file through the link
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "project", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "project/etc", Typeflag: tar.TypeSymlink, Linkname: outside},
+		{Name: "project/etc/passwd", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+	} {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := tw.Write([]byte("root")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := extractFromVM(tar.NewReader(&buf), "/root/project", filepath.Join(dir, "copy")); err == nil {
+		t.Fatal("expected an error writing through the symlink")
+	}
+	if _, err := os.Lstat(filepath.Join(outside, "passwd")); !os.IsNotExist(err) {
+		t.Errorf("expected no file written outside of the destination, got %v", err)
+	}
+}
@@ -1,8 +1,10 @@
 package run
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path"
 	"strings"
 
@@ -12,13 +14,15 @@ import (
 	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	"github.com/weaveworks/ignite/pkg/config"
-	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/metadata"
 	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/policy"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/util"
 
 	flag "github.com/spf13/pflag"
+	"github.com/weaveworks/libgitops/pkg/filter"
 	patchutil "github.com/weaveworks/libgitops/pkg/util/patch"
 	"sigs.k8s.io/yaml"
 )
@@ -32,15 +36,23 @@ func NewCreateFlags() *CreateFlags {
 type CreateFlags struct {
 	PortMappings []string
 	CopyFiles    []string
+	Tmpfs        []string
 	// This is a placeholder value here for now.
 	// If it was set using flags, it will be copied over to
 	// the API type. TODO: When we later have internal types
 	// this can go away
 	SSH         api.SSH
 	ConfigFile  string
+	Template    string
 	VM          *api.VM
 	Labels      []string
+	Sysctls     []string
+	Env         []string
+	Secrets     []string
+	Devices     []string
+	Initrd      meta.OCIImageRef
 	RequireName bool
+	Output      string
 }
 
 type CreateOptions struct {
@@ -50,23 +62,27 @@ type CreateOptions struct {
 }
 
 func (cf *CreateFlags) NewCreateOptions(args []string, fs *flag.FlagSet) (*CreateOptions, error) {
+	if err := SetResultOutput(cf.Output); err != nil {
+		return nil, err
+	}
+
 	// Create a new base VM and configure it by combining the component config,
 	// VM config file and flags.
-	baseVM := providers.Client.VMs().New()
+	baseVM := newBaseVM()
 
-	// If component config is in use, set the VMDefaults on the base VM.
-	if providers.ComponentConfig != nil {
-		baseVM.Spec = providers.ComponentConfig.Spec.VMDefaults
+	// If a VM template is given, use its spec as the base instead.
+	if len(cf.Template) != 0 {
+		if err := setTemplate(baseVM, cf.Template); err != nil {
+			return nil, err
+		}
 	}
 
 	// Resolve registry configuration used for pulling image if required.
 	cmdutil.ResolveRegistryConfigDir()
+	cmdutil.ResolveImageScan()
+	cmdutil.ResolveSBOM()
+	cmdutil.ResolveImageImport()
 
-	// Initialize the VM's Prefixer
-	baseVM.Status.IDPrefix = providers.IDPrefix
-	// Set the runtime and network-plugin on the VM, then override the global config.
-	baseVM.Status.Runtime.Name = providers.RuntimeName
-	baseVM.Status.Network.Plugin = providers.NetworkPluginName
 	// Populate the runtime and network-plugin providers.
 	if err := config.SetAndPopulateProviders(providers.RuntimeName, providers.NetworkPluginName); err != nil {
 		return nil, err
@@ -104,6 +120,11 @@ func (cf *CreateFlags) NewCreateOptions(args []string, fs *flag.FlagSet) (*Creat
 		return nil, fmt.Errorf("must set VM name, flag --require-name set")
 	}
 
+	// The VM's spec may override the runtime and network-plugin providers.
+	if err := setVMProviders(baseVM, fs); err != nil {
+		return nil, err
+	}
+
 	// Assign the new VM to the configFlag.
 	cf.VM = baseVM
 
@@ -114,25 +135,50 @@ func (cf *CreateFlags) NewCreateOptions(args []string, fs *flag.FlagSet) (*Creat
 
 	co := &CreateOptions{CreateFlags: cf}
 
-	// Get the image, or import it if it doesn't exist.
+	// Get the image and kernel, or import the ones that don't exist in parallel.
 	var err error
-	co.image, err = operations.FindOrImportImage(providers.Client, cf.VM.Spec.Image.OCI)
+	co.image, co.kernel, err = operations.FindOrImportImageAndKernel(context.Background(), providers.Client, cf.VM.Spec.Image.OCI, cf.VM.Spec.Kernel.OCI)
 	if err != nil {
 		return nil, err
 	}
 
-	// Populate relevant data from the Image on the VM object.
+	// Populate relevant data from the Image and Kernel on the VM object.
 	cf.VM.SetImage(co.image)
+	cf.VM.SetKernel(co.kernel)
+	return co, nil
+}
+
+// newBaseVM returns a new VM with the VM defaults of the component config, if
+// in use, and the runtime, network-plugin and snapshotter to create it with.
+func newBaseVM() *api.VM {
+	baseVM := providers.Client.VMs().New()
+
+	// If component config is in use, set the VMDefaults on the base VM.
+	if providers.ComponentConfig != nil {
+		baseVM.Spec = providers.ComponentConfig.Spec.VMDefaults
+	}
+
+	// Initialize the VM's Prefixer
+	baseVM.Status.IDPrefix = providers.IDPrefix
+	// Set the runtime and network-plugin on the VM, then override the global config.
+	baseVM.Status.Runtime.Name = providers.RuntimeName
+	baseVM.Status.Network.Plugin = providers.NetworkPluginName
+	// The snapshotter is fixed for the lifetime of the VM, so record it right away.
+	baseVM.Status.Snapshotter = providers.SnapshotterName
+	return baseVM
+}
 
-	// Get the kernel, or import it if it doesn't exist.
-	co.kernel, err = operations.FindOrImportKernel(providers.Client, cf.VM.Spec.Kernel.OCI)
+// setTemplate uses the spec of the given VM template as the spec of the base VM,
+// and references the template from the VM.
+func setTemplate(baseVM *api.VM, templateMatch string) error {
+	template, err := providers.Client.VMTemplates().Find(filter.NewIDNameFilter(templateMatch))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Populate relevant data from the Kernel on the VM object.
-	cf.VM.SetKernel(co.kernel)
-	return co, nil
+	baseVM.Spec = *template.Spec.DeepCopy()
+	baseVM.SetAnnotation(constants.IGNITE_TEMPLATE_ANNOTATION, template.GetName())
+	return nil
 }
 
 // applyVMConfigFile patches a given base VM with the VM config in a given
@@ -143,6 +189,11 @@ func applyVMConfigFile(baseVM *api.VM, configFile string) error {
 		return err
 	}
 
+	return applyVMConfig(baseVM, vmConfigBytes)
+}
+
+// applyVMConfig patches a given base VM with the given VM config.
+func applyVMConfig(baseVM *api.VM, vmConfigBytes []byte) error {
 	// Marshal into a new object to extract VM image if any.
 	fileVM := &api.VM{}
 	if err := scheme.Serializer.DecodeInto(vmConfigBytes, fileVM); err != nil {
@@ -202,17 +253,49 @@ func applyVMFlagOverrides(baseVM *api.VM, cf *CreateFlags, fs *flag.FlagSet) err
 	if fs.Changed("memory") {
 		baseVM.Spec.Memory = cf.VM.Spec.Memory
 	}
+	if fs.Changed("memory-hugepages") {
+		baseVM.Spec.MemoryHugepages = cf.VM.Spec.MemoryHugepages
+	}
 	if fs.Changed("size") {
 		baseVM.Spec.DiskSize = cf.VM.Spec.DiskSize
 	}
+	if fs.Changed("overlay-size-limit") {
+		baseVM.Spec.OverlaySizeLimit = cf.VM.Spec.OverlaySizeLimit
+	}
 	if fs.Changed("kernel-image") {
 		baseVM.Spec.Kernel.OCI = cf.VM.Spec.Kernel.OCI
 	}
+	if fs.Changed("initrd") {
+		initrd := cf.Initrd
+		baseVM.Spec.Kernel.InitrdRef = &initrd
+	}
 	if fs.Changed("sandbox-image") {
 		baseVM.Spec.Sandbox.OCI = cf.VM.Spec.Sandbox.OCI
 	}
 	if fs.Changed("volumes") {
-		baseVM.Spec.Storage = cf.VM.Spec.Storage
+		baseVM.Spec.Storage.Volumes = cf.VM.Spec.Storage.Volumes
+		baseVM.Spec.Storage.VolumeMounts = cf.VM.Spec.Storage.VolumeMounts
+	}
+	if fs.Changed("read-only-root") {
+		baseVM.Spec.Storage.ReadOnlyRoot = cf.VM.Spec.Storage.ReadOnlyRoot
+	}
+	if fs.Changed("writable-path") {
+		baseVM.Spec.Storage.WritablePaths = cf.VM.Spec.Storage.WritablePaths
+	}
+	if fs.Changed("autostart") {
+		baseVM.Spec.Autostart = cf.VM.Spec.Autostart
+	}
+	if fs.Changed("autostart-after") {
+		baseVM.Spec.AutostartAfter = cf.VM.Spec.AutostartAfter
+	}
+	if fs.Changed("restart") {
+		baseVM.Spec.RestartPolicy = cf.VM.Spec.RestartPolicy
+	}
+	if fs.Changed("firmware") {
+		baseVM.Spec.Firmware = cf.VM.Spec.Firmware
+	}
+	if fs.Changed("nested-virtualization") {
+		baseVM.Spec.NestedVirtualization = cf.VM.Spec.NestedVirtualization
 	}
 
 	if len(cf.CopyFiles) > 0 {
@@ -223,6 +306,42 @@ func applyVMFlagOverrides(baseVM *api.VM, cf *CreateFlags, fs *flag.FlagSet) err
 		}
 	}
 
+	if len(cf.Tmpfs) > 0 {
+		// Parse the --tmpfs flag and add the volumes to the ones already set.
+		if err = addTmpfsVolumes(&baseVM.Spec.Storage, cf.Tmpfs); err != nil {
+			return err
+		}
+	}
+
+	if len(cf.Sysctls) > 0 {
+		// Parse the --sysctl flag and add the sysctls to the ones already set.
+		if err = addSysctls(&baseVM.Spec, cf.Sysctls); err != nil {
+			return err
+		}
+	}
+
+	if len(cf.Env) > 0 {
+		// Parse the --env flag and add the environment variables to the ones already set.
+		if err = addEnv(&baseVM.Spec, cf.Env); err != nil {
+			return err
+		}
+	}
+
+	if len(cf.Secrets) > 0 {
+		// Parse the --secret flag and add the secrets to the ones already set.
+		if err = addSecrets(&baseVM.Spec, cf.Secrets); err != nil {
+			return err
+		}
+	}
+
+	if len(cf.Devices) > 0 {
+		// The --device flag replaces the devices already set, a device can only be passed through once
+		baseVM.Spec.Devices = nil
+		for _, address := range cf.Devices {
+			baseVM.Spec.Devices = append(baseVM.Spec.Devices, api.PCIDevice{Address: address})
+		}
+	}
+
 	if len(cf.PortMappings) > 0 {
 		// Parse the given port mappings.
 		baseVM.Spec.Network.Ports, err = meta.ParsePortMappings(cf.PortMappings)
@@ -233,6 +352,10 @@ func applyVMFlagOverrides(baseVM *api.VM, cf *CreateFlags, fs *flag.FlagSet) err
 
 	// If the SSH flag was set, copy it over to the API type
 	if cf.SSH.Generate || cf.SSH.PublicKey != "" {
+		// Keep the additional authorized keys of the configuration
+		if baseVM.Spec.SSH != nil {
+			cf.SSH.AuthorizedKeys = baseVM.Spec.SSH.AuthorizedKeys
+		}
 		baseVM.Spec.SSH = &cf.SSH
 	}
 
@@ -250,17 +373,24 @@ func Create(co *CreateOptions) (err error) {
 	}
 	defer util.DeferErr(&err, func() error { return metadata.Cleanup(co.VM, false) })
 
+	if err = policy.CheckVM(context.Background(), policy.OperationVMCreate, co.VM); err != nil {
+		return
+	}
+
 	if err = providers.Client.VMs().Set(co.VM); err != nil {
 		return
 	}
 
 	// Allocate and populate the overlay file
-	if err = dmlegacy.AllocateAndPopulateOverlay(co.VM); err != nil {
+	if err = operations.AllocateAndPopulateOverlay(co.VM); err != nil {
 		return
 	}
 
-	err = metadata.Success(co.VM)
+	if err = metadata.Success(co.VM); err != nil {
+		return
+	}
 
+	err = PrintResult(co.Output, co.VM)
 	return
 }
 
@@ -287,3 +417,123 @@ func parseFileMappings(fileMappings []string) ([]api.FileMapping, error) {
 
 	return result, nil
 }
+
+// addTmpfsVolumes parses the given /vm/path[:size] tmpfs mounts and adds
+// them as named tmpfs volumes with their volume mounts to the storage spec.
+func addTmpfsVolumes(storage *api.VMStorageSpec, tmpfsMounts []string) error {
+	for i, tmpfsMount := range tmpfsMounts {
+		parts := strings.Split(tmpfsMount, ":")
+		if len(parts) > 2 {
+			return fmt.Errorf("--tmpfs requires the /vm/path[:size] form")
+		}
+
+		mountPath := parts[0]
+		if !path.IsAbs(mountPath) {
+			return fmt.Errorf("--tmpfs path arguments must be absolute")
+		}
+
+		tmpfs := &api.TmpfsVolume{}
+		if len(parts) == 2 {
+			size, err := meta.NewSizeFromString(parts[1])
+			if err != nil {
+				return fmt.Errorf("invalid --tmpfs size %q: %v", parts[1], err)
+			}
+			tmpfs.Size = size
+		}
+
+		volumeName := fmt.Sprintf("tmpfs%d", i)
+		storage.Volumes = append(storage.Volumes, api.Volume{
+			Name:  volumeName,
+			Tmpfs: tmpfs,
+		})
+		storage.VolumeMounts = append(storage.VolumeMounts, api.VolumeMount{
+			Name:      volumeName,
+			MountPath: mountPath,
+		})
+	}
+
+	return nil
+}
+
+// addSysctls parses the given name=value sysctls and sets them in the VM spec,
+// overriding the value of a sysctl that's already set.
+func addSysctls(spec *api.VMSpec, sysctls []string) error {
+	for _, sysctl := range sysctls {
+		kv := strings.SplitN(sysctl, "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return fmt.Errorf("--sysctl requires the name=value form, got %q", sysctl)
+		}
+
+		if spec.Sysctls == nil {
+			spec.Sysctls = make(map[string]string, len(sysctls))
+		}
+		spec.Sysctls[kv[0]] = kv[1]
+	}
+
+	return nil
+}
+
+// addEnv parses the given NAME=value environment variables and sets them in the VM
+// spec, overriding the value of a variable that's already set. A variable given
+// without a value takes its value from the environment of ignite, it's skipped if
+// it isn't set there.
+func addEnv(spec *api.VMSpec, env []string) error {
+	for _, e := range env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv[0]) == 0 {
+			return fmt.Errorf("--env requires the NAME=value or NAME form, got %q", e)
+		}
+
+		if len(kv) == 1 {
+			value, ok := os.LookupEnv(kv[0])
+			if !ok {
+				continue
+			}
+			kv = append(kv, value)
+		}
+
+		if spec.Env == nil {
+			spec.Env = make(map[string]string, len(env))
+		}
+		spec.Env[kv[0]] = kv[1]
+	}
+
+	return nil
+}
+
+// addSecrets parses the given name=file:/path, name=env:NAME and name=vault:path#field
+// secrets and adds them
+// to the VM spec, replacing a secret with the same name that's already set
+func addSecrets(spec *api.VMSpec, secrets []string) error {
+	for _, s := range secrets {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return fmt.Errorf("--secret requires the name=file:/path, name=env:NAME or name=vault:path#field form, got %q", s)
+		}
+
+		secret := api.VMSecret{Name: kv[0]}
+		switch {
+		case strings.HasPrefix(kv[1], "file:"):
+			secret.File = strings.TrimPrefix(kv[1], "file:")
+		case strings.HasPrefix(kv[1], "env:"):
+			secret.Env = strings.TrimPrefix(kv[1], "env:")
+		case strings.HasPrefix(kv[1], "vault:"):
+			secret.Vault = strings.TrimPrefix(kv[1], "vault:")
+		default:
+			return fmt.Errorf("--secret requires the name=file:/path, name=env:NAME or name=vault:path#field form, got %q", s)
+		}
+
+		replaced := false
+		for i := range spec.Secrets {
+			if spec.Secrets[i].Name == secret.Name {
+				spec.Secrets[i], replaced = secret, true
+			}
+		}
+
+		if !replaced {
+			spec.Secrets = append(spec.Secrets, secret)
+		}
+	}
+
+	return nil
+}
@@ -188,6 +188,9 @@ func TestApplyVMFlagOverrides(t *testing.T) {
 		wantCopyFiles   []api.FileMapping
 		wantPortMapping meta.PortMappings
 		wantSSH         *api.SSH
+		wantSysctls     map[string]string
+		wantEnv         map[string]string
+		wantSecrets     []api.VMSecret
 		err             bool
 	}{
 		{
@@ -304,8 +307,87 @@ func TestApplyVMFlagOverrides(t *testing.T) {
 				PublicKey: "some-pub-key",
 			},
 		},
+		{
+			name: "sysctls added to base VM",
+			createFlag: &CreateFlags{
+				VM: &api.VM{
+					Spec: api.VMSpec{
+						Sysctls: map[string]string{
+							"vm.swappiness":       "10",
+							"net.ipv4.ip_forward": "0",
+						},
+					},
+				},
+				Sysctls: []string{"net.ipv4.ip_forward=1", "net.ipv4.ping_group_range=0 2147483647"},
+			},
+			wantSysctls: map[string]string{
+				"vm.swappiness":             "10",
+				"net.ipv4.ip_forward":       "1",
+				"net.ipv4.ping_group_range": "0 2147483647",
+			},
+		},
+		{
+			name: "invalid sysctl syntax",
+			createFlag: &CreateFlags{
+				VM:      &api.VM{},
+				Sysctls: []string{"net.ipv4.ip_forward"},
+			},
+			err: true,
+		},
+		{
+			name: "env added to base VM",
+			createFlag: &CreateFlags{
+				VM: &api.VM{
+					Spec: api.VMSpec{
+						Env: map[string]string{"FOO": "foo", "BAR": "bar"},
+					},
+				},
+				Env: []string{"BAR=baz", "EMPTY=", "IGNITE_TEST_HOST_ENV", "IGNITE_TEST_UNSET_ENV"},
+			},
+			wantEnv: map[string]string{
+				"FOO":                  "foo",
+				"BAR":                  "baz",
+				"EMPTY":                "",
+				"IGNITE_TEST_HOST_ENV": "from-host",
+			},
+		},
+		{
+			name: "invalid env syntax",
+			createFlag: &CreateFlags{
+				VM:  &api.VM{},
+				Env: []string{"=foo"},
+			},
+			err: true,
+		},
+		{
+			name: "secrets added to base VM",
+			createFlag: &CreateFlags{
+				VM: &api.VM{
+					Spec: api.VMSpec{
+						Secrets: []api.VMSecret{{Name: "token", File: "/etc/token", Mode: 0440}, {Name: "key", Env: "KEY"}},
+					},
+				},
+				Secrets: []string{"token=env:API_TOKEN", "db-password=file:/etc/ignite/db-password", "key=vault:secret/data/app#key"},
+			},
+			wantSecrets: []api.VMSecret{
+				{Name: "token", Env: "API_TOKEN"},
+				{Name: "key", Vault: "secret/data/app#key"},
+				{Name: "db-password", File: "/etc/ignite/db-password"},
+			},
+		},
+		{
+			name: "invalid secret source",
+			createFlag: &CreateFlags{
+				VM:      &api.VM{},
+				Secrets: []string{"token=/etc/token"},
+			},
+			err: true,
+		},
 	}
 
+	os.Setenv("IGNITE_TEST_HOST_ENV", "from-host")
+	defer os.Unsetenv("IGNITE_TEST_HOST_ENV")
+
 	for _, rt := range tests {
 		t.Run(rt.name, func(t *testing.T) {
 			vm := rt.createFlag.VM
@@ -339,6 +421,21 @@ func TestApplyVMFlagOverrides(t *testing.T) {
 				if !reflect.DeepEqual(vm.Spec.SSH, rt.wantSSH) {
 					t.Errorf("expected VM.Spec.SSH to be %v, actual: %v", rt.wantSSH, vm.Spec.SSH)
 				}
+
+				// Check if the sysctls are set as expected.
+				if !reflect.DeepEqual(vm.Spec.Sysctls, rt.wantSysctls) {
+					t.Errorf("expected VM.Spec.Sysctls to be %v, actual: %v", rt.wantSysctls, vm.Spec.Sysctls)
+				}
+
+				// Check if the environment variables are set as expected.
+				if !reflect.DeepEqual(vm.Spec.Env, rt.wantEnv) {
+					t.Errorf("expected VM.Spec.Env to be %v, actual: %v", rt.wantEnv, vm.Spec.Env)
+				}
+
+				// Check if the secrets are set as expected.
+				if !reflect.DeepEqual(vm.Spec.Secrets, rt.wantSecrets) {
+					t.Errorf("expected VM.Spec.Secrets to be %v, actual: %v", rt.wantSecrets, vm.Spec.Secrets)
+				}
 			}
 		})
 	}
@@ -366,6 +463,14 @@ func TestNewCreateOptions(t *testing.T) {
 			},
 			err: true,
 		},
+		{
+			name: "nonexistent template",
+			createFlag: &CreateFlags{
+				VM:       &api.VM{},
+				Template: "golden-dev",
+			},
+			err: true,
+		},
 	}
 
 	for _, rt := range tests {
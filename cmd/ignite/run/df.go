@@ -0,0 +1,189 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/filter"
+)
+
+// DfFlags contains the flags supported by df.
+type DfFlags struct {
+	Verbose bool
+}
+
+type DfOptions struct {
+	*DfFlags
+	images  []*api.Image
+	kernels []*api.Kernel
+	vms     []*api.VM
+}
+
+// vmUsage is the disk usage of a VM, split into the image data its disk shares,
+// and the data only the VM uses. The unique usage of some snapshotters is unknown.
+type vmUsage struct {
+	vm      *api.VM
+	shared  uint64
+	unique  uint64
+	unknown bool
+}
+
+// dfSummary sums up the usage of the images, kernels and VMs. The in use sizes are
+// of the images and kernels used by VMs, which are stored once however many VMs use them.
+type dfSummary struct {
+	images, imagesInUse   int
+	imageSize, imageInUse uint64
+
+	kernels, kernelsInUse   int
+	kernelSize, kernelInUse uint64
+
+	vms, running   int
+	shared, unique uint64
+	unknown        int
+}
+
+// NewDfOptions constructs and returns DfOptions.
+func (df *DfFlags) NewDfOptions() (do *DfOptions, err error) {
+	do = &DfOptions{DfFlags: df}
+	if do.images, err = providers.Client.Images().FindAll(filter.NewAllFilter()); err != nil && !os.IsNotExist(err) {
+		return
+	}
+	if do.kernels, err = providers.Client.Kernels().FindAll(filter.NewAllFilter()); err != nil && !os.IsNotExist(err) {
+		return
+	}
+	if do.vms, err = providers.Client.VMs().FindAll(filter.NewAllFilter()); err != nil && !os.IsNotExist(err) {
+		return
+	}
+
+	return do, nil
+}
+
+// Df shows the disk space used by the images, kernels and VMs, and how much of the
+// image data is shared by the disks of the VMs instead of being copied for each of them
+func Df(do *DfOptions) error {
+	imageSizes := make(map[string]uint64, len(do.images))
+	for _, image := range do.images {
+		size, err := util.DiskUsage(path.Join(image.ObjectPath(), constants.IMAGE_FS))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		imageSizes[image.GetName()] = size
+	}
+
+	kernelSizes := make(map[string]uint64, len(do.kernels))
+	for _, kernel := range do.kernels {
+		size, err := util.DiskUsage(kernel.ObjectPath())
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		kernelSizes[kernel.GetName()] = size
+	}
+
+	usages := make([]vmUsage, 0, len(do.vms))
+	for _, vm := range do.vms {
+		u := vmUsage{vm: vm, shared: imageSizes[vm.Spec.Image.OCI.String()]}
+		unique, err := operations.OverlayUsage(vm)
+		switch {
+		case errors.Is(err, snapshotter.ErrUsageUnknown):
+			u.unknown = true
+		case err != nil:
+			log.Warnf("Failed to get the disk usage of VM %q: %v", vm.GetUID(), err)
+			u.unknown = true
+		default:
+			u.unique = unique.Bytes()
+		}
+		usages = append(usages, u)
+	}
+
+	s := summarize(imageSizes, kernelSizes, usages)
+
+	o := util.NewOutput()
+	o.Write("TYPE", "COUNT", "IN USE", "SIZE", "IN USE SIZE")
+	o.Write("Images", s.images, s.imagesInUse, formatBytes(s.imageSize), formatBytes(s.imageInUse))
+	o.Write("Kernels", s.kernels, s.kernelsInUse, formatBytes(s.kernelSize), formatBytes(s.kernelInUse))
+	o.Write("VMs", s.vms, s.running, formatBytes(s.unique), "-")
+	o.Flush()
+
+	if s.vms != 0 {
+		fmt.Printf("\nThe disks of the VMs share %s of image data stored once as %s, and use %s on their own.\n",
+			formatBytes(s.shared), formatBytes(s.imageInUse), formatBytes(s.unique))
+		if s.unknown != 0 {
+			fmt.Printf("The usage of %d VMs is unknown, their snapshotters can't tell it apart from the image.\n", s.unknown)
+		}
+	}
+
+	if !do.Verbose || len(usages) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	o = util.NewOutput()
+	defer o.Flush()
+
+	o.Write("VM ID", "NAME", "SNAPSHOTTER", "IMAGE", "SHARED", "UNIQUE")
+	for _, u := range usages {
+		unique := "n/a"
+		if !u.unknown {
+			unique = formatBytes(u.unique)
+		}
+
+		snapshotterName := u.vm.Status.Snapshotter
+		if len(snapshotterName) == 0 {
+			snapshotterName = snapshotter.SnapshotterDMLegacy
+		}
+
+		o.Write(u.vm.GetUID(), u.vm.GetName(), snapshotterName, u.vm.Spec.Image.OCI, formatBytes(u.shared), unique)
+	}
+
+	return nil
+}
+
+// summarize sums up the sizes of the images and kernels by name, and the VM usages
+func summarize(imageSizes, kernelSizes map[string]uint64, usages []vmUsage) (s dfSummary) {
+	imagesInUse := make(map[string]bool)
+	kernelsInUse := make(map[string]bool)
+	for _, u := range usages {
+		s.vms++
+		if u.vm.Running() {
+			s.running++
+		}
+
+		s.shared += u.shared
+		s.unique += u.unique
+		if u.unknown {
+			s.unknown++
+		}
+
+		imagesInUse[u.vm.Spec.Image.OCI.String()] = true
+		kernelsInUse[u.vm.Spec.Kernel.OCI.String()] = true
+	}
+
+	for name, size := range imageSizes {
+		s.images++
+		s.imageSize += size
+		if imagesInUse[name] {
+			s.imagesInUse++
+			s.imageInUse += size
+		}
+	}
+
+	for name, size := range kernelSizes {
+		s.kernels++
+		s.kernelSize += size
+		if kernelsInUse[name] {
+			s.kernelsInUse++
+			s.kernelInUse += size
+		}
+	}
+
+	return
+}
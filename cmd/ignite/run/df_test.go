@@ -0,0 +1,35 @@
+package run
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+func TestSummarize(t *testing.T) {
+	newVM := func(name string, running bool) *api.VM {
+		vm, err := createTestVM(name, "")
+		assert.NilError(t, err)
+		vm.Status.Running = running
+		return vm
+	}
+
+	image := "foo/bar:latest" // The image and kernel of the test VMs
+	s := summarize(
+		map[string]uint64{image: 1000, "foo/unused:latest": 300},
+		map[string]uint64{image: 50},
+		[]vmUsage{
+			{vm: newVM("vm1", true), shared: 1000, unique: 10},
+			{vm: newVM("vm2", false), shared: 1000, unique: 20},
+			{vm: newVM("vm3", true), shared: 1000, unknown: true},
+		},
+	)
+
+	assert.Equal(t, s, dfSummary{
+		images: 2, imagesInUse: 1, imageSize: 1300, imageInUse: 1000,
+		kernels: 1, kernelsInUse: 1, kernelSize: 50, kernelInUse: 50,
+		vms: 3, running: 2, shared: 3000, unique: 30, unknown: 1,
+	})
+}
@@ -0,0 +1,99 @@
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+)
+
+// defaultEditor is used when $EDITOR isn't set
+const defaultEditor = "vi"
+
+type EditOptions struct {
+	vm *api.VM
+}
+
+func NewEditOptions(vmMatch string) (eo *EditOptions, err error) {
+	eo = &EditOptions{}
+	eo.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// Edit opens the manifest of a stopped VM in $EDITOR, and saves the changes to its
+// spec, metadata labels and annotations. They're applied when it's started the next time.
+func Edit(eo *EditOptions) error {
+	if eo.vm.Running() {
+		return fmt.Errorf("VM %q is running, stop it before editing it", eo.vm.GetUID())
+	}
+
+	original, err := scheme.Serializer.EncodeYAML(eo.vm)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile("", fmt.Sprintf("ignite-edit-%s-*.yaml", eo.vm.GetUID()))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(original); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := runEditor(f.Name()); err != nil {
+		return err
+	}
+
+	edited, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(bytes.TrimSpace(edited), bytes.TrimSpace(original)) {
+		log.Infof("No changes made to VM %q", eo.vm.GetUID())
+		return nil
+	}
+
+	vm := &api.VM{}
+	if err := scheme.Serializer.DecodeInto(edited, vm); err != nil {
+		return fmt.Errorf("failed to decode the edited VM: %v", err)
+	}
+
+	if vm.GetUID() != eo.vm.GetUID() || vm.GetName() != eo.vm.GetName() {
+		return fmt.Errorf("the name and ID of VM %q can't be changed", eo.vm.GetUID())
+	}
+
+	// The status is managed by ignite, discard any edits to it
+	vm.Status = eo.vm.Status
+	return updateVM(eo.vm, vm)
+}
+
+// runEditor opens the file in the editor given by $EDITOR, which may contain arguments
+func runEditor(file string) error {
+	args := strings.Fields(os.Getenv("EDITOR"))
+	if len(args) == 0 {
+		args = []string{defaultEditor}
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], file)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %v", args[0], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/events"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+type EventsFlags struct {
+	VM     string
+	Watch  bool
+	Output string
+}
+
+// Events prints the events of the event log that match the flags, oldest first. With Watch
+// set, it keeps printing the events as they're recorded.
+func Events(ef *EventsFlags) error {
+	if ef.Output != "" && ef.Output != outputFormatTable && ef.Output != outputFormatJSON {
+		return fmt.Errorf("unrecognized output format: %q, supported formats: %s|%s", ef.Output, outputFormatTable, outputFormatJSON)
+	}
+
+	logPath := path.Join(constants.DATA_DIR, constants.EVENT_LOG)
+	list, offset, err := events.ReadLog(logPath, 0)
+	if err != nil {
+		return err
+	}
+
+	list = filterEvents(list, ef.VM)
+	if !ef.Watch {
+		if ef.Output == outputFormatJSON {
+			if list == nil {
+				list = []*events.Event{}
+			}
+
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(list)
+		}
+
+		printEvents(list, true)
+		return nil
+	}
+
+	// Print the events as they're appended to the log, a line of JSON each for the json format
+	header := true
+	for {
+		if ef.Output == outputFormatJSON {
+			enc := json.NewEncoder(os.Stdout)
+			for _, e := range list {
+				if err := enc.Encode(e); err != nil {
+					return err
+				}
+			}
+		} else if len(list) > 0 {
+			printEvents(list, header)
+			header = false
+		}
+
+		time.Sleep(constants.EVENT_CHECK_INTERVAL)
+		if list, offset, err = events.ReadLog(logPath, offset); err != nil {
+			return err
+		}
+
+		list = filterEvents(list, ef.VM)
+	}
+}
+
+func printEvents(list []*events.Event, header bool) {
+	o := util.NewOutput()
+	defer o.Flush()
+
+	if header {
+		o.Write("TIME", "TYPE", "REASON", "OBJECT", "MESSAGE")
+	}
+
+	for _, e := range list {
+		o.Write(e.Time.Local().Format("2006-01-02 15:04:05"), e.Type, e.Reason,
+			fmt.Sprintf("%s/%s", strings.ToLower(e.Kind), eventObject(e)), e.Message)
+	}
+}
+
+// eventObject returns the name of the object of the event, or its UID if it has no name
+func eventObject(e *events.Event) string {
+	if len(e.Name) > 0 {
+		return e.Name
+	}
+
+	return e.UID.String()
+}
+
+// filterEvents returns the events of the VM with the given name or UID, all events if it's empty
+func filterEvents(list []*events.Event, vm string) []*events.Event {
+	if len(vm) == 0 {
+		return list
+	}
+
+	var matched []*events.Event
+	for _, e := range list {
+		if e.Kind == "VM" && (e.Name == vm || e.UID.String() == vm) {
+			matched = append(matched, e)
+		}
+	}
+
+	return matched
+}
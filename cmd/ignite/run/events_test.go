@@ -0,0 +1,35 @@
+package run
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/weaveworks/ignite/pkg/events"
+)
+
+func TestFilterEvents(t *testing.T) {
+	list := []*events.Event{
+		{Type: events.VMCreated, Kind: "VM", UID: "599615df99804ae8", Name: "my-vm"},
+		{Type: events.VMCrashed, Kind: "VM", UID: "599615df99804ae8", Name: "my-vm", Reason: "OOMKilled"},
+		{Type: events.VMStarted, Kind: "VM", UID: "cc82b4424244b3e4", Name: "other-vm"},
+		{Type: events.ImageImported, Kind: "Image", UID: "a2e2d8fe2b3b9f1e", Name: "my-vm"},
+	}
+
+	cases := []struct {
+		name string
+		vm   string
+		want []*events.Event
+	}{
+		{"all", "", list},
+		{"by name", "my-vm", list[:2]},
+		{"by UID", "cc82b4424244b3e4", list[2:3]},
+		{"unknown VM", "foo", nil},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.DeepEqual(t, filterEvents(list, rt.vm), rt.want)
+		})
+	}
+}
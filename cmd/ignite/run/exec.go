@@ -1,8 +1,20 @@
 package run
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
 	"time"
 
+	log "github.com/sirupsen/logrus"
+	terminal "golang.org/x/term"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/constants"
 )
@@ -12,6 +24,8 @@ type ExecFlags struct {
 	Timeout      uint32
 	IdentityFile string
 	Tty          bool
+	Agent        bool
+	Env          []string
 }
 
 type ExecOptions struct {
@@ -27,14 +41,134 @@ func (ef *ExecFlags) NewExecOptions(vmMatch string, command ...string) (eo *Exec
 		command:   command,
 	}
 
+	for _, env := range ef.Env {
+		if len(env) == 0 || env[0] == '=' || !strings.Contains(env, "=") {
+			return nil, fmt.Errorf("invalid environment variable %q, expected KEY=value", env)
+		}
+	}
+
 	eo.vm, err = getVMForMatch(vmMatch)
 	return
 }
 
-// Exec executes command in a VM based on the provided ExecOptions.
+// Exec executes command in a VM based on the provided ExecOptions. The guest
+// agent of the VM is preferred, SSH is used if the VM has no agent listening.
 func Exec(eo *ExecOptions) error {
+	code, err := execAgent(eo)
+	if err == nil {
+		os.Exit(code)
+	}
+
+	if eo.Agent || !errors.Is(err, agent.ErrNoAgent) {
+		return err
+	}
+
+	log.Debugf("Falling back to SSH: %v", err)
 	if err := waitForSSH(eo.vm, constants.SSH_DEFAULT_TIMEOUT_SECONDS, time.Duration(eo.Timeout)*time.Second); err != nil {
 		return err
 	}
-	return runSSH(eo.vm, eo.IdentityFile, eo.command, eo.Tty, eo.Timeout)
+
+	// Set the environment using env(1), as SSH servers commonly don't accept it
+	command := eo.command
+	if env := execEnv(eo); len(env) > 0 {
+		command = append(append([]string{"env"}, env...), command...)
+	}
+
+	return runSSH(eo.vm, eo.IdentityFile, command, eo.Tty, eo.Timeout)
+}
+
+// execEnv returns the environment variables of the VM followed by the ones given
+// for the command, which take precedence
+func execEnv(eo *ExecOptions) []string {
+	names := make([]string, 0, len(eo.vm.Spec.Env))
+	for name := range eo.vm.Spec.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make([]string, 0, len(names)+len(eo.Env))
+	for _, name := range names {
+		env = append(env, name+"="+eo.vm.Spec.Env[name])
+	}
+
+	return append(env, eo.Env...)
+}
+
+// execAgent executes the command using the guest agent of the VM, and returns its exit
+// code. If the agent is required, it's given the timeout to start listening.
+func execAgent(eo *ExecOptions) (int, error) {
+	if eo.Agent {
+		if err := agent.Wait(eo.vm, time.Duration(eo.Timeout)*time.Second); err != nil {
+			return 0, err
+		}
+	}
+
+	req := &protocol.ExecRequest{
+		Command: eo.command,
+		Env:     execEnv(eo),
+		Tty:     eo.Tty,
+	}
+
+	streams := &agent.ExecStreams{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+
+	if eo.Tty {
+		fd := int(os.Stdin.Fd())
+
+		// Request the terminal with the size and TERM of the local one, the
+		// given environment variables take precedence over TERM
+		w, h, err := terminal.GetSize(fd)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get terminal size: %v", err)
+		}
+		req.Size = protocol.WindowSize{Width: uint16(w), Height: uint16(h)}
+
+		term := os.Getenv("TERM")
+		if term == "" {
+			term = defaultTerm
+		}
+		req.Env = append([]string{"TERM=" + term}, req.Env...)
+
+		state, err := terminal.MakeRaw(fd)
+		if err != nil {
+			return 0, fmt.Errorf("failed to make terminal raw: %v", err)
+		}
+		defer terminal.Restore(fd, state)
+
+		// Forward the size of the local terminal when it's resized
+		resize := make(chan protocol.WindowSize)
+		done := make(chan struct{})
+		defer close(done)
+
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGWINCH)
+		defer signal.Stop(sigs)
+
+		go func() {
+			for {
+				select {
+				case <-sigs:
+					w, h, err := terminal.GetSize(fd)
+					if err != nil {
+						continue
+					}
+
+					select {
+					case resize <- protocol.WindowSize{Width: uint16(w), Height: uint16(h)}:
+					case <-done:
+						return
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		streams.Resize = resize
+	}
+
+	return agent.Exec(eo.vm, req, streams)
 }
@@ -0,0 +1,160 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	"github.com/weaveworks/ignite/pkg/backup"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// ExportFlags contains the flags supported by the export command.
+type ExportFlags struct {
+	Output string
+}
+
+type ExportOptions struct {
+	*ExportFlags
+	vm *api.VM
+}
+
+func (ef *ExportFlags) NewExportOptions(vmMatch string) (eo *ExportOptions, err error) {
+	eo = &ExportOptions{ExportFlags: ef}
+	eo.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// ExportVM writes an archive of the VM to the output file, or to stdout if it's unset or "-"
+func ExportVM(eo *ExportOptions) (err error) {
+	var w io.Writer = os.Stdout
+	if len(eo.Output) > 0 && eo.Output != "-" {
+		var f *os.File
+		if f, err = os.Create(eo.Output); err != nil {
+			return
+		}
+		defer util.DeferErr(&err, f.Close)
+		w = f
+	}
+
+	if err = backup.Export(eo.vm, w); err != nil {
+		return fmt.Errorf("failed to export VM %q: %v", eo.vm.GetUID(), err)
+	}
+
+	log.Infof("Exported VM %q with name %q", eo.vm.GetUID(), eo.vm.GetName())
+	return
+}
+
+// ImportVMFlags contains the flags supported by the VM import command.
+type ImportVMFlags struct {
+	Name   string
+	Output string
+}
+
+type ImportVMOptions struct {
+	*ImportVMFlags
+	source string
+	vm     *api.VM
+}
+
+func (ivf *ImportVMFlags) NewImportVMOptions(source string) (*ImportVMOptions, error) {
+	if err := SetResultOutput(ivf.Output); err != nil {
+		return nil, err
+	}
+
+	// Populate the runtime provider, the image and kernel of the VM might need to be imported
+	if err := config.SetAndPopulateProviders(providers.RuntimeName, providers.NetworkPluginName); err != nil {
+		return nil, err
+	}
+
+	cmdutil.ResolveRegistryConfigDir()
+
+	return &ImportVMOptions{ImportVMFlags: ivf, source: source}, nil
+}
+
+// ImportVM recreates a VM from an archive written by ExportVM on this host. The archive
+// is read from the source file, or from stdin if the source is "-".
+func ImportVM(ivo *ImportVMOptions) (err error) {
+	var r io.Reader = os.Stdin
+	if ivo.source != "-" {
+		f, err := os.Open(ivo.source)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	// Remove the VM again if the import fails after it has been created
+	defer func() {
+		if ivo.vm != nil {
+			util.DeferErr(&err, func() error { return metadata.Cleanup(ivo.vm, false) })
+		}
+	}()
+
+	if _, err = backup.Import(r, ivo.createVM); err != nil {
+		return
+	}
+
+	if err = metadata.Success(ivo.vm); err != nil {
+		return
+	}
+
+	return PrintResult(ivo.Output, ivo.vm)
+}
+
+// createVM creates a new VM on this host with the configuration of the archived
+// VM, and allocates its disk. The status of the archived VM is specific to its old host.
+func (ivo *ImportVMOptions) createVM(archived *api.VM) (*api.VM, error) {
+	vm := providers.Client.VMs().New()
+	vm.ObjectMeta = archived.ObjectMeta
+	vm.Spec = archived.Spec
+	if len(ivo.Name) > 0 {
+		vm.SetName(ivo.Name)
+	}
+
+	// Keep the UID of the VM, unless it's already in use on this host
+	if _, err := providers.Client.VMs().Get(vm.GetUID()); err == nil {
+		log.Warnf("A VM with ID %q already exists, generating a new ID", vm.GetUID())
+		vm.SetUID("")
+	}
+
+	vm.Status.IDPrefix = providers.IDPrefix
+	vm.Status.Runtime.Name = providers.RuntimeName
+	vm.Status.Network.Plugin = providers.NetworkPluginName
+	vm.Status.Snapshotter = providers.SnapshotterName
+
+	if err := validation.ValidateVM(vm).ToAggregate(); err != nil {
+		return nil, err
+	}
+
+	// Get the image and kernel, or import them if they don't exist on this host
+	image, kernel, err := operations.FindOrImportImageAndKernel(context.Background(), providers.Client, vm.Spec.Image.OCI, vm.Spec.Kernel.OCI)
+	if err != nil {
+		return nil, err
+	}
+	vm.SetImage(image)
+	vm.SetKernel(kernel)
+
+	if err := metadata.SetNameAndUID(vm, providers.Client); err != nil {
+		return nil, err
+	}
+	ivo.vm = vm
+
+	if err := providers.Client.VMs().Set(vm); err != nil {
+		return nil, err
+	}
+
+	// The contents of the disk are restored from the archive, so it doesn't need to be populated
+	return vm, operations.AllocateOverlay(vm)
+}
@@ -1,9 +1,14 @@
 package run
 
 import (
+	"fmt"
+	"strings"
+
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	ignitefilter "github.com/weaveworks/ignite/pkg/filter"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
 )
 
 // TODO: This
@@ -24,6 +29,49 @@ func getVMsForMatches(vmMatches []string) ([]*api.VM, error) {
 	return allVMs, nil
 }
 
+// getVMsForSelector returns the VMs whose labels match the label selector
+func getVMsForSelector(selector string) ([]*api.VM, error) {
+	f, err := ignitefilter.NewLabelFilter(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return providers.Client.VMs().FindAll(f)
+}
+
+// getVMsForMatchesOrSelector returns the VMs matching either the given VM
+// matches or the label selector, only one of them may be given
+func getVMsForMatchesOrSelector(vmMatches []string, selector string) ([]*api.VM, error) {
+	if len(selector) == 0 {
+		if len(vmMatches) == 0 {
+			return nil, fmt.Errorf("need at least one vm identifier as argument, or a label selector")
+		}
+
+		return getVMsForMatches(vmMatches)
+	}
+
+	if len(vmMatches) > 0 {
+		return nil, fmt.Errorf("cannot use both a label selector and vm arguments")
+	}
+
+	return getVMsForSelector(selector)
+}
+
+// parseKind returns the kind of the Objects that can be addressed by their
+// kind on the command line, that's images, kernels and VMs
+func parseKind(k string) (runtime.Kind, error) {
+	switch strings.ToLower(k) {
+	case api.KindImage.Lower():
+		return api.KindImage, nil
+	case api.KindKernel.Lower():
+		return api.KindKernel, nil
+	case api.KindVM.Lower():
+		return api.KindVM, nil
+	}
+
+	return "", fmt.Errorf("unrecognized kind: %q", k)
+}
+
 func getAllVMs() ([]*api.VM, error) {
 	return providers.Client.VMs().FindAll(filter.NewAllFilter())
 }
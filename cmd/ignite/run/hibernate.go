@@ -0,0 +1,44 @@
+package run
+
+import (
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/operations"
+)
+
+// HibernateFlags contains the flags supported by hibernate.
+type HibernateFlags struct {
+	Selector string
+}
+
+type HibernateOptions struct {
+	*HibernateFlags
+	vms []*api.VM
+}
+
+func (hf *HibernateFlags) NewHibernateOptions(vmMatches []string) (ho *HibernateOptions, err error) {
+	ho = &HibernateOptions{HibernateFlags: hf}
+	ho.vms, err = getVMsForMatchesOrSelector(vmMatches, hf.Selector)
+	return
+}
+
+// Hibernate saves the state of the given running VMs to disk and stops them
+func Hibernate(ho *HibernateOptions) error {
+	for _, vm := range ho.vms {
+		// The VMs selected by label include the stopped ones, skip them
+		if len(ho.Selector) > 0 && !vm.Running() {
+			continue
+		}
+
+		// Set the runtime and network-plugin providers from the VM status.
+		if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+			return err
+		}
+
+		if err := operations.HibernateVM(vm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -4,18 +4,31 @@ import (
 	"os"
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/filter"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/util"
-	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
 )
 
+// ImagesFlags contains the flags supported by the image listing.
+type ImagesFlags struct {
+	Output   string
+	Selector string
+}
+
 type ImagesOptions struct {
+	*ImagesFlags
 	allImages []*api.Image
 }
 
-func NewImagesOptions() (io *ImagesOptions, err error) {
-	io = &ImagesOptions{}
-	io.allImages, err = providers.Client.Images().FindAll(filter.NewAllFilter())
+func (imf *ImagesFlags) NewImagesOptions() (io *ImagesOptions, err error) {
+	io = &ImagesOptions{ImagesFlags: imf}
+	f, err := filter.NewLabelFilter(imf.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	io.allImages, err = providers.Client.Images().FindAll(f)
 	// If the storage is uninitialized, avoid failure and continue with empty
 	// image list.
 	if err != nil && os.IsNotExist(err) {
@@ -25,13 +38,28 @@ func NewImagesOptions() (io *ImagesOptions, err error) {
 }
 
 func Images(io *ImagesOptions) error {
-	o := util.NewOutput()
-	defer o.Flush()
-
-	o.Write("IMAGE ID", "NAME", "CREATED", "SIZE")
+	objects := make([]runtime.Object, 0, len(io.allImages))
 	for _, image := range io.allImages {
-		o.Write(image.GetUID(), image.GetName(), image.GetCreated(), image.Status.OCISource.Size.String())
+		objects = append(objects, image)
+	}
+
+	return printList(io.Output, objects, func() {
+		o := util.NewOutput()
+		defer o.Flush()
+
+		o.Write("IMAGE ID", "NAME", "CREATED", "SIZE", "ARCH")
+		for _, image := range io.allImages {
+			o.Write(image.GetUID(), image.GetName(), image.GetCreated(), image.Status.OCISource.Size.String(), architecture(image.Status.OCISource))
+		}
+	})
+}
+
+// architecture returns the architecture of the source, unknown for the ones imported before
+// it was recorded
+func architecture(src api.OCIImageSource) string {
+	if len(src.Architecture) == 0 {
+		return "<unknown>"
 	}
 
-	return nil
+	return src.Architecture
 }
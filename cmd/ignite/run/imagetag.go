@@ -0,0 +1,49 @@
+package run
+
+import (
+	"context"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/filter"
+)
+
+type ImageTagOptions struct {
+	image  *api.Image
+	target meta.OCIImageRef
+}
+
+func NewImageTagOptions(imageMatch, target string) (ito *ImageTagOptions, err error) {
+	ito = &ImageTagOptions{}
+	if ito.target, err = meta.NewOCIImageRef(target); err != nil {
+		return nil, err
+	}
+
+	if ito.image, err = providers.Client.Images().Find(filter.NewIDNameFilter(imageMatch)); err != nil {
+		return nil, err
+	}
+
+	return ito, nil
+}
+
+// ImageTag adds the target name to the image, the tag is a new image sharing its files
+func ImageTag(ito *ImageTagOptions) (image *api.Image, err error) {
+	// Populate the runtime provider.
+	if err := config.SetAndPopulateProviders(providers.RuntimeName, providers.NetworkPluginName); err != nil {
+		return nil, err
+	}
+
+	if image, err = operations.TagImage(context.Background(), providers.Client, ito.image, ito.target); err != nil {
+		return
+	}
+	defer util.DeferErr(&err, func() error { return metadata.Cleanup(image, false) })
+
+	err = metadata.Success(image)
+
+	return
+}
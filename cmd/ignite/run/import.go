@@ -1,30 +1,63 @@
 package run
 
 import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
 	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/pkg/agent"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
-	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	"github.com/weaveworks/ignite/pkg/config"
 	"github.com/weaveworks/ignite/pkg/metadata"
 	"github.com/weaveworks/ignite/pkg/operations"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/filter"
 )
 
-func ImportImage(source string) (image *api.Image, err error) {
+// ImportImage imports the given OCI image. If withAgent is set, the guest agent
+// is injected into the image, which fails if the image is already imported.
+func ImportImage(source string, withAgent bool) (image *api.Image, err error) {
 	// Populate the runtime provider.
 	if err := config.SetAndPopulateProviders(providers.RuntimeName, providers.NetworkPluginName); err != nil {
 		return nil, err
 	}
 
 	cmdutil.ResolveRegistryConfigDir()
+	cmdutil.ResolveImageScan()
+	cmdutil.ResolveSBOM()
+	cmdutil.ResolveImageImport()
 
-	ociRef, err := meta.NewOCIImageRef(source)
+	ociRef, fromDaemon, err := operations.ParseImportSource(source)
 	if err != nil {
 		return
 	}
 
-	image, err = operations.FindOrImportImage(providers.Client, ociRef)
+	if fromDaemon {
+		// Lazy imports read the layers from the registry, which may not have the image
+		if providers.ImageImport.Lazy {
+			log.Infof("Images of the docker daemon are imported in full")
+			providers.ImageImport.Lazy = false
+		}
+
+		// Images that are already imported aren't copied from the docker daemon again
+		if _, findErr := providers.Client.Images().Find(filter.NewIDNameFilter(ociRef.String())); findErr != nil {
+			if err = operations.LoadFromDockerDaemon(ociRef); err != nil {
+				return
+			}
+		}
+	}
+
+	if withAgent {
+		var agentBinary string
+		if agentBinary, err = agent.Binary(); err != nil {
+			return
+		}
+
+		image, err = operations.ImportImageWithAgent(context.Background(), providers.Client, ociRef, agentBinary)
+	} else {
+		image, err = operations.FindOrImportImage(context.Background(), providers.Client, ociRef)
+	}
 	if err != nil {
 		return
 	}
@@ -43,12 +76,20 @@ func ImportKernel(source string) (kernel *api.Kernel, err error) {
 
 	cmdutil.ResolveRegistryConfigDir()
 
-	ociRef, err := meta.NewOCIImageRef(source)
+	ociRef, fromDaemon, err := operations.ParseImportSource(source)
 	if err != nil {
 		return
 	}
 
-	kernel, err = operations.FindOrImportKernel(providers.Client, ociRef)
+	if fromDaemon {
+		if _, findErr := providers.Client.Kernels().Find(filter.NewIDNameFilter(ociRef.String())); findErr != nil {
+			if err = operations.LoadFromDockerDaemon(ociRef); err != nil {
+				return
+			}
+		}
+	}
+
+	kernel, err = operations.FindOrImportKernel(context.Background(), providers.Client, ociRef)
 	if err != nil {
 		return
 	}
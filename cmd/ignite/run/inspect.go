@@ -3,10 +3,8 @@ package run
 import (
 	"bytes"
 	"fmt"
-	"strings"
 	"text/template"
 
-	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/libgitops/pkg/filter"
@@ -27,19 +25,11 @@ type InspectOptions struct {
 // NewInspectOptions constructs and returns InspectOptions with the given kind
 // and object ID.
 func (i *InspectFlags) NewInspectOptions(k, objectMatch string) (*InspectOptions, error) {
-	var err error
-	var kind runtime.Kind
 	io := &InspectOptions{InspectFlags: i}
 
-	switch strings.ToLower(k) {
-	case api.KindImage.Lower():
-		kind = api.KindImage
-	case api.KindKernel.Lower():
-		kind = api.KindKernel
-	case api.KindVM.Lower():
-		kind = api.KindVM
-	default:
-		return nil, fmt.Errorf("unrecognized kind: %q", k)
+	kind, err := parseKind(k)
+	if err != nil {
+		return nil, err
 	}
 
 	if io.object, err = providers.Client.Dynamic(kind).Find(filter.NewIDNameFilter(objectMatch)); err != nil {
@@ -0,0 +1,85 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/util"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+type JobsFlags struct {
+	All    bool
+	Output string
+}
+
+// Jobs prints the queued and running jobs of ignited, and the finished ones with All set
+func Jobs(jf *JobsFlags) error {
+	if jf.Output != "" && jf.Output != outputFormatTable && jf.Output != outputFormatJSON {
+		return fmt.Errorf("unrecognized output format: %q, supported formats: %s|%s", jf.Output, outputFormatTable, outputFormatJSON)
+	}
+
+	statePath := path.Join(constants.DATA_DIR, constants.JOBS_FILE)
+	status, err := jobs.ReadStatus(statePath)
+	if err != nil {
+		return err
+	}
+
+	if status == nil {
+		return fmt.Errorf("no jobs found in %q, ignited daemon or gitops isn't running", statePath)
+	}
+
+	status.Jobs = filterJobs(status.Jobs, jf.All)
+	if jf.Output == outputFormatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+
+	o := util.NewOutput()
+	defer o.Flush()
+
+	o.Write("ID", "CLASS", "TARGET", "SOURCE", "STATE", "QUEUED", "DURATION", "ERROR")
+	for _, job := range status.Jobs {
+		o.Write(strconv.FormatUint(job.ID, 10), job.Class, job.Target, job.Source, job.State,
+			duration.HumanDuration(time.Since(job.Queued))+" ago", jobDuration(job), job.Error)
+	}
+
+	return nil
+}
+
+// filterJobs returns the queued and running jobs, or all of them with all set
+func filterJobs(list []jobs.Job, all bool) []jobs.Job {
+	if all {
+		return list
+	}
+
+	matched := []jobs.Job{}
+	for _, job := range list {
+		if job.Finished == nil {
+			matched = append(matched, job)
+		}
+	}
+
+	return matched
+}
+
+// jobDuration returns how long the job has been running, or how long it ran
+func jobDuration(job jobs.Job) string {
+	if job.Started == nil {
+		return ""
+	}
+
+	end := time.Now()
+	if job.Finished != nil {
+		end = *job.Finished
+	}
+
+	return duration.HumanDuration(end.Sub(*job.Started))
+}
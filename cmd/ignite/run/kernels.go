@@ -4,18 +4,31 @@ import (
 	"os"
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/filter"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/util"
-	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
 )
 
+// KernelsFlags contains the flags supported by the kernel listing.
+type KernelsFlags struct {
+	Output   string
+	Selector string
+}
+
 type KernelsOptions struct {
+	*KernelsFlags
 	allKernels []*api.Kernel
 }
 
-func NewKernelsOptions() (ko *KernelsOptions, err error) {
-	ko = &KernelsOptions{}
-	ko.allKernels, err = providers.Client.Kernels().FindAll(filter.NewAllFilter())
+func (kf *KernelsFlags) NewKernelsOptions() (ko *KernelsOptions, err error) {
+	ko = &KernelsOptions{KernelsFlags: kf}
+	f, err := filter.NewLabelFilter(kf.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	ko.allKernels, err = providers.Client.Kernels().FindAll(f)
 	// If the storage is uninitialized, avoid failure and continue with empty
 	// kernel list.
 	if err != nil && os.IsNotExist(err) {
@@ -25,13 +38,18 @@ func NewKernelsOptions() (ko *KernelsOptions, err error) {
 }
 
 func Kernels(ko *KernelsOptions) error {
-	o := util.NewOutput()
-	defer o.Flush()
-
-	o.Write("KERNEL ID", "NAME", "CREATED", "SIZE", "VERSION")
+	objects := make([]runtime.Object, 0, len(ko.allKernels))
 	for _, kernel := range ko.allKernels {
-		o.Write(kernel.GetUID(), kernel.GetName(), kernel.GetCreated(), kernel.Status.OCISource.Size.String(), kernel.Status.Version)
+		objects = append(objects, kernel)
 	}
 
-	return nil
+	return printList(ko.Output, objects, func() {
+		o := util.NewOutput()
+		defer o.Flush()
+
+		o.Write("KERNEL ID", "NAME", "CREATED", "SIZE", "VERSION", "ARCH")
+		for _, kernel := range ko.allKernels {
+			o.Write(kernel.GetUID(), kernel.GetName(), kernel.GetCreated(), kernel.Status.OCISource.Size.String(), kernel.Status.Version, architecture(kernel.Status.OCISource))
+		}
+	})
 }
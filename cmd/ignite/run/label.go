@@ -0,0 +1,70 @@
+package run
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/logs"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+type LabelOptions struct {
+	object runtime.Object
+	set    []string
+	remove []string
+}
+
+// NewLabelOptions looks up the object of the given kind, and parses the label
+// changes, "key=value" sets a label and "key-" removes it
+func NewLabelOptions(k, objectMatch string, labels []string) (*LabelOptions, error) {
+	kind, err := parseKind(k)
+	if err != nil {
+		return nil, err
+	}
+
+	lo := &LabelOptions{}
+	for _, label := range labels {
+		if strings.HasSuffix(label, "-") && !strings.Contains(label, "=") {
+			if label = strings.TrimSuffix(label, "-"); label == "" {
+				return nil, fmt.Errorf("invalid label removal %q, name empty", label+"-")
+			}
+
+			lo.remove = append(lo.remove, label)
+		} else {
+			lo.set = append(lo.set, label)
+		}
+	}
+
+	if lo.object, err = providers.Client.Dynamic(kind).Find(filter.NewIDNameFilter(objectMatch)); err != nil {
+		return nil, err
+	}
+
+	return lo, nil
+}
+
+// Label sets and removes the labels of the object
+func Label(lo *LabelOptions) error {
+	if err := metadata.SetLabels(lo.object, lo.set); err != nil {
+		return err
+	}
+
+	for _, key := range lo.remove {
+		delete(lo.object.GetObjectMeta().Labels, key)
+	}
+
+	if err := providers.Client.Dynamic(lo.object.GetKind()).Set(lo.object); err != nil {
+		return err
+	}
+
+	if logs.Quiet {
+		fmt.Println(lo.object.GetUID())
+	} else {
+		log.Infof("Labeled %s with name %q and ID %q", lo.object.GetKind(), lo.object.GetName(), lo.object.GetUID())
+	}
+
+	return nil
+}
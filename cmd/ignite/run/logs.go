@@ -1,28 +1,124 @@
 package run
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"time"
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/container"
 	"github.com/weaveworks/ignite/pkg/providers"
 )
 
+// logsStatusInterval is how often the VM is checked for having stopped while following its logs
+const logsStatusInterval = time.Second
+
+// LogsFlags contains the flags supported by the logs command.
+type LogsFlags struct {
+	Follow     bool
+	Since      string
+	Tail       int
+	Timestamps bool
+}
+
 type LogsOptions struct {
-	vm *api.VM
+	*LogsFlags
+	vm    *api.VM
+	since time.Time
 }
 
-func NewLogsOptions(vmMatch string) (lo *LogsOptions, err error) {
-	lo = &LogsOptions{}
+func (lf *LogsFlags) NewLogsOptions(vmMatch string) (lo *LogsOptions, err error) {
+	lo = &LogsOptions{LogsFlags: lf}
+	if len(lf.Since) > 0 {
+		if lo.since, err = parseSince(lf.Since); err != nil {
+			return
+		}
+	}
+
 	lo.vm, err = getVMForMatch(vmMatch)
 	return
 }
 
+// parseSince parses a relative duration like "10m", or an RFC 3339 timestamp
+func parseSince(since string) (time.Time, error) {
+	if d, err := time.ParseDuration(since); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return t, fmt.Errorf("invalid since value %q, expected a duration like 10m or an RFC 3339 timestamp", since)
+	}
+
+	return t, nil
+}
+
+// Logs prints the recorded console output of the VM, and follows the
+// output of a running VM until it stops if requested
 func Logs(lo *LogsOptions) error {
+	lines, offset, err := container.ReadConsoleLog(lo.vm)
+	if errors.Is(err, container.ErrNoConsoleLog) {
+		return lo.containerLogs()
+	} else if err != nil {
+		return err
+	}
+
+	// Drop the lines before the since time, then all but the last lines to tail
+	for len(lines) > 0 && lines[0].Time.Before(lo.since) {
+		lines = lines[1:]
+	}
+
+	if lo.Tail >= 0 && len(lines) > lo.Tail {
+		lines = lines[len(lines)-lo.Tail:]
+	}
+
+	for _, line := range lines {
+		lo.printLine(line)
+	}
+
+	if !lo.Follow || !lo.vm.Running() {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			time.Sleep(logsStatusInterval)
+			if vm, err := providers.Client.VMs().Get(lo.vm.GetUID()); err != nil || !vm.Running() {
+				return
+			}
+		}
+	}()
+
+	return container.FollowConsoleLog(ctx, lo.vm, offset, func(line container.ConsoleLine) error {
+		lo.printLine(line)
+		return nil
+	})
+}
+
+func (lo *LogsOptions) printLine(line container.ConsoleLine) {
+	if lo.Timestamps {
+		fmt.Printf("%s %s\n", line.Time.Format(time.RFC3339Nano), line.Text)
+	} else {
+		fmt.Println(line.Text)
+	}
+}
+
+// containerLogs prints the logs of the container of a running VM started without
+// console log recording. They can't be filtered or followed.
+func (lo *LogsOptions) containerLogs() error {
 	// Check if the VM is running
 	if !lo.vm.Running() {
-		return fmt.Errorf("VM %q is not running", lo.vm.GetUID())
+		return fmt.Errorf("no logs have been recorded for VM %q", lo.vm.GetUID())
+	}
+
+	if lo.Follow || len(lo.Since) > 0 || lo.Tail >= 0 || lo.Timestamps {
+		return fmt.Errorf("VM %q was started without console log recording, restart it to filter or follow its logs", lo.vm.GetUID())
 	}
 
 	// Set the runtime and network-plugin providers from the VM status.
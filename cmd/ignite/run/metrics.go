@@ -0,0 +1,43 @@
+package run
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+type MetricsOptions struct {
+	vms []*api.VM
+}
+
+func NewMetricsOptions(vmMatches []string) (mo *MetricsOptions, err error) {
+	mo = &MetricsOptions{}
+	mo.vms, err = getVMsForMatches(vmMatches)
+	return
+}
+
+// Metrics prints the resource usage of the VMs, as reported by their guest agents
+func Metrics(mo *MetricsOptions) error {
+	o := util.NewOutput()
+	defer o.Flush()
+
+	o.Write("VM ID", "NAME", "UPTIME", "LOAD", "MEMORY", "DISK")
+	for _, vm := range mo.vms {
+		m, err := agent.GetMetrics(vm)
+		if err != nil {
+			return err
+		}
+
+		uptime := time.Duration(m.UptimeSeconds) * time.Second
+		load := fmt.Sprintf("%.2f %.2f %.2f", m.Load[0], m.Load[1], m.Load[2])
+		memory := fmt.Sprintf("%s / %s", meta.NewSizeFromBytes(m.MemoryTotal-m.MemoryAvailable), meta.NewSizeFromBytes(m.MemoryTotal))
+		disk := fmt.Sprintf("%s / %s", meta.NewSizeFromBytes(m.DiskTotal-m.DiskAvailable), meta.NewSizeFromBytes(m.DiskTotal))
+		o.Write(vm.GetUID(), vm.GetName(), uptime, load, memory, disk)
+	}
+
+	return nil
+}
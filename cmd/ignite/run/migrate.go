@@ -0,0 +1,122 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/backup"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// MigrateFlags contains the flags supported by the migrate command.
+type MigrateFlags struct {
+	To string
+}
+
+type MigrateOptions struct {
+	*MigrateFlags
+	vm *api.VM
+}
+
+func (mf *MigrateFlags) NewMigrateOptions(vmMatch string) (mo *MigrateOptions, err error) {
+	if len(mf.To) == 0 {
+		return nil, fmt.Errorf("the destination host needs to be given with --to")
+	}
+
+	mo = &MigrateOptions{MigrateFlags: mf}
+	if mo.vm, err = getVMForMatch(vmMatch); err != nil {
+		return
+	}
+
+	// Set the runtime and network-plugin providers from the VM status
+	err = config.SetAndPopulateProviders(mo.vm.Status.Runtime.Name, mo.vm.Status.Network.Plugin)
+	return
+}
+
+// Migrate moves a running VM to another host. The VM is paused and snapshotted, then
+// imported and restored on the destination host over SSH, and finally removed from
+// this host. If the migration fails, the VM continues running on this host.
+func Migrate(mo *MigrateOptions) error {
+	vm := mo.vm
+	if err := operations.PrepareMigration(vm); err != nil {
+		return err
+	}
+
+	log.Infof("Transferring VM %q to %q", vm.GetUID(), mo.To)
+	if err := transferVM(vm, mo.To); err != nil {
+		if abortErr := operations.AbortMigration(vm); abortErr != nil {
+			log.Errorf("Failed to resume VM %q: %v", vm.GetUID(), abortErr)
+		}
+
+		return fmt.Errorf("failed to migrate VM %q to %q: %v", vm.GetUID(), mo.To, err)
+	}
+
+	// The VM is running on the destination host now, remove it from this host
+	if err := operations.DeleteVM(providers.Client, vm); err != nil {
+		return err
+	}
+
+	log.Infof("Migrated VM %q with name %q to %q", vm.GetUID(), vm.GetName(), mo.To)
+	return nil
+}
+
+// transferVM streams the VM prepared for migration to "ignite vm import" on the
+// destination host, and starts the imported VM there, which restores its snapshot.
+// The imported VM is removed again if it fails to start.
+func transferVM(vm *api.VM, host string) error {
+	cmd := remoteIgnite(host, "vm", "import", "-")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// Closing stdin aborts the import on the destination host if the export fails
+	exportErr := backup.Export(vm, stdin)
+	stdin.Close()
+
+	// The import may succeed with a truncated export, which the VM can't be restored from
+	importErr := cmd.Wait()
+	if exportErr != nil {
+		if importErr == nil {
+			if rmErr := remoteIgnite(host, "vm", "rm", "-f", vm.GetName()).Run(); rmErr != nil {
+				log.Errorf("Failed to remove VM %q from %q: %v", vm.GetName(), host, rmErr)
+			}
+		}
+
+		return fmt.Errorf("failed to export VM %q: %v", vm.GetUID(), exportErr)
+	}
+
+	if importErr != nil {
+		return fmt.Errorf("failed to import VM on %q: %v", host, importErr)
+	}
+
+	// The name is kept by the import, while the UID might have been in use
+	if err := remoteIgnite(host, "vm", "start", vm.GetName()).Run(); err != nil {
+		if rmErr := remoteIgnite(host, "vm", "rm", "-f", vm.GetName()).Run(); rmErr != nil {
+			log.Errorf("Failed to remove VM %q from %q: %v", vm.GetName(), host, rmErr)
+		}
+
+		return fmt.Errorf("failed to start VM on %q: %v", host, err)
+	}
+
+	return nil
+}
+
+// remoteIgnite returns a command running ignite on the given host over SSH. Its output
+// is forwarded to stderr, SSH needs to be able to authenticate non-interactively.
+func remoteIgnite(host string, args ...string) *exec.Cmd {
+	cmd := exec.Command("ssh", append([]string{"-o", "BatchMode=yes", host, "ignite"}, args...)...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd
+}
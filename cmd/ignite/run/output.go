@@ -0,0 +1,194 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/logs"
+	"github.com/weaveworks/ignite/pkg/result"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// Output formats supported by the listing commands
+const (
+	outputFormatTable   = "table"
+	outputFormatJSON    = "json"
+	outputFormatYAML    = "yaml"
+	outputGoTemplate    = "go-template="
+	outputCustomColumns = "custom-columns="
+)
+
+// outputFormats describes the supported output formats
+var outputFormats = fmt.Sprintf("%s|%s|%s|%s<template>|%s<header>:<field path>,...",
+	outputFormatTable, outputFormatJSON, outputFormatYAML, outputGoTemplate, outputCustomColumns)
+
+// printList prints the listed objects in the given output format. The table format
+// is the default, and is printed by printTable.
+func printList(format string, objects []runtime.Object, printTable func()) error {
+	switch {
+	case format == "", format == outputFormatTable:
+		printTable()
+		return nil
+	case format == outputFormatJSON:
+		return printJSON(objects)
+	case format == outputFormatYAML:
+		return printYAML(objects)
+	case strings.HasPrefix(format, outputGoTemplate):
+		return printTemplate(strings.TrimPrefix(format, outputGoTemplate), objects)
+	case strings.HasPrefix(format, outputCustomColumns):
+		return printCustomColumns(strings.TrimPrefix(format, outputCustomColumns), objects)
+	}
+
+	return fmt.Errorf("unrecognized output format: %q, supported formats: %s", format, outputFormats)
+}
+
+// printJSON prints the objects as a JSON array
+func printJSON(objects []runtime.Object) error {
+	list := make([]json.RawMessage, 0, len(objects))
+	for _, obj := range objects {
+		b, err := scheme.Serializer.EncodeJSON(obj)
+		if err != nil {
+			return err
+		}
+
+		list = append(list, b)
+	}
+
+	b, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(b))
+	return nil
+}
+
+// printYAML prints the objects as a multi-document YAML stream
+func printYAML(objects []runtime.Object) error {
+	for i, obj := range objects {
+		b, err := scheme.Serializer.EncodeYAML(obj)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			fmt.Println("---")
+		}
+		fmt.Println(string(bytes.TrimSpace(b)))
+	}
+
+	return nil
+}
+
+// printTemplate renders the template for every object on a line of its own
+func printTemplate(format string, objects []runtime.Object) error {
+	tmpl, err := parseTemplate(format)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		o := &bytes.Buffer{}
+		if err := tmpl.Execute(o, obj); err != nil {
+			return fmt.Errorf("failed rendering template: %v", err)
+		}
+		fmt.Println(o.String())
+	}
+
+	return nil
+}
+
+// printCustomColumns prints a table of the given columns. The columns are given
+// as "HEADER:.Field.Path" pairs separated by commas, the field paths reference
+// the fields of the objects like in Go templates.
+func printCustomColumns(spec string, objects []runtime.Object) error {
+	headers, tmpls, err := parseCustomColumns(spec)
+	if err != nil {
+		return err
+	}
+
+	o := util.NewOutput()
+	defer o.Flush()
+
+	o.Write(headers...)
+	for _, obj := range objects {
+		row := make([]interface{}, 0, len(tmpls))
+		for _, tmpl := range tmpls {
+			b := &bytes.Buffer{}
+			if err := tmpl.Execute(b, obj); err != nil {
+				return fmt.Errorf("failed rendering column: %v", err)
+			}
+			row = append(row, b.String())
+		}
+		o.Write(row...)
+	}
+
+	return nil
+}
+
+func parseTemplate(format string) (*template.Template, error) {
+	tmpl, err := template.New("").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %v", err)
+	}
+
+	return tmpl, nil
+}
+
+func parseCustomColumns(spec string) (headers []interface{}, tmpls []*template.Template, err error) {
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || !strings.HasPrefix(parts[1], ".") {
+			return nil, nil, fmt.Errorf("invalid custom column %q, expected <header>:<field path>", column)
+		}
+
+		tmpl, err := parseTemplate("{{" + parts[1] + "}}")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		headers = append(headers, parts[0])
+		tmpls = append(tmpls, tmpl)
+	}
+
+	return
+}
+
+// SetResultOutput checks the output format of the result of a command. When the result
+// is printed, the log lines are written to stderr, so stdout only has the result.
+func SetResultOutput(format string) error {
+	switch format {
+	case "":
+		return nil
+	case outputFormatJSON:
+		logs.Logger.SetOutput(os.Stderr)
+		return nil
+	}
+
+	return fmt.Errorf("unrecognized output format: %q, supported formats: %s", format, outputFormatJSON)
+}
+
+// PrintResult prints the result for the VM, image or kernel in the output format, if any
+func PrintResult(format string, obj runtime.Object) error {
+	if len(format) == 0 {
+		return nil
+	}
+
+	switch o := obj.(type) {
+	case *api.VM:
+		return result.Print(os.Stdout, result.NewVM(o))
+	case *api.Image:
+		return result.Print(os.Stdout, result.NewImage(o))
+	case *api.Kernel:
+		return result.Print(os.Stdout, result.NewKernel(o))
+	}
+
+	return fmt.Errorf("no result for kind %s", obj.GetObjectKind().GroupVersionKind().Kind)
+}
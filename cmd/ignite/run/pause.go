@@ -0,0 +1,95 @@
+package run
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/logs"
+	"github.com/weaveworks/ignite/pkg/operations"
+)
+
+// PauseFlags contains the flags supported by pause.
+type PauseFlags struct {
+	Selector string
+}
+
+type PauseOptions struct {
+	*PauseFlags
+	vms []*api.VM
+}
+
+func (pf *PauseFlags) NewPauseOptions(vmMatches []string) (po *PauseOptions, err error) {
+	po = &PauseOptions{PauseFlags: pf}
+	po.vms, err = getVMsForMatchesOrSelector(vmMatches, pf.Selector)
+	return
+}
+
+// Pause freezes the given running VMs
+func Pause(po *PauseOptions) error {
+	for _, vm := range po.vms {
+		// The VMs selected by label include the ones that can't be paused, skip them
+		if len(po.Selector) > 0 && (!vm.Running() || vm.Paused()) {
+			continue
+		}
+
+		// Set the runtime provider from the VM status, it's used to find the VMM process
+		if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+			return err
+		}
+
+		if err := operations.PauseVM(vm); err != nil {
+			return err
+		}
+
+		if logs.Quiet {
+			fmt.Println(vm.GetUID())
+		} else {
+			log.Infof("Paused %s with name %q and ID %q", vm.GetKind(), vm.GetName(), vm.GetUID())
+		}
+	}
+
+	return nil
+}
+
+// ResumeFlags contains the flags supported by resume.
+type ResumeFlags struct {
+	Selector string
+}
+
+type ResumeOptions struct {
+	*ResumeFlags
+	vms []*api.VM
+}
+
+func (rf *ResumeFlags) NewResumeOptions(vmMatches []string) (ro *ResumeOptions, err error) {
+	ro = &ResumeOptions{ResumeFlags: rf}
+	ro.vms, err = getVMsForMatchesOrSelector(vmMatches, rf.Selector)
+	return
+}
+
+// Resume unfreezes the given paused VMs
+func Resume(ro *ResumeOptions) error {
+	for _, vm := range ro.vms {
+		if len(ro.Selector) > 0 && !vm.Paused() {
+			continue
+		}
+
+		if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+			return err
+		}
+
+		if err := operations.ResumeVM(vm); err != nil {
+			return err
+		}
+
+		if logs.Quiet {
+			fmt.Println(vm.GetUID())
+		} else {
+			log.Infof("Resumed %s with name %q and ID %q", vm.GetKind(), vm.GetName(), vm.GetUID())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,471 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/sftp"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+	"sigs.k8s.io/yaml"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// projectFiles are the names of the project file, looked up in the current
+// directory and then in its parents
+var projectFiles = []string{"Ignitefile", "ignite.yaml"}
+
+type ProjectFlags struct {
+	Provision bool
+}
+
+type ProjectOptions struct {
+	*ProjectFlags
+	project *project
+}
+
+// projectFile describes the VM of a project
+type projectFile struct {
+	Name      string                 `json:"name,omitempty"`
+	Image     string                 `json:"image"`
+	Kernel    string                 `json:"kernel,omitempty"`
+	CPUs      uint64                 `json:"cpus,omitempty"`
+	Memory    string                 `json:"memory,omitempty"`
+	DiskSize  string                 `json:"diskSize,omitempty"`
+	Ports     []string               `json:"ports,omitempty"`
+	Shares    []projectShare         `json:"shares,omitempty"`
+	Provision []projectProvisioner   `json:"provision,omitempty"`
+	Spec      map[string]interface{} `json:"spec,omitempty"`
+}
+
+// projectShare is a file or directory of the host copied into the VM
+type projectShare struct {
+	HostPath string `json:"hostPath"`
+	VMPath   string `json:"vmPath"`
+}
+
+// projectProvisioner is a shell command, or a shell script of the project, run in the VM
+type projectProvisioner struct {
+	Shell  string `json:"shell,omitempty"`
+	Script string `json:"script,omitempty"`
+}
+
+// project is a loaded project file
+type project struct {
+	dir          string
+	source       string
+	vmName       string
+	shares       []api.FileMapping
+	provisioners []projectProvisioner
+	manifest     []byte
+}
+
+// NewProjectOptions loads the project of the current directory
+func (pf *ProjectFlags) NewProjectOptions() (*ProjectOptions, error) {
+	p, err := loadCurrentProject()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectOptions{ProjectFlags: pf, project: p}, nil
+}
+
+// Up creates the VM of the project if it doesn't exist, updates it like "ignite apply"
+// if the project file changed, and starts it. A created VM is provisioned, an existing
+// one gets the shares copied again and is only provisioned again if asked to.
+func Up(po *ProjectOptions, fs *flag.FlagSet) error {
+	if err := populateApplyProviders(); err != nil {
+		return err
+	}
+
+	current, err := po.project.vm()
+	if err != nil {
+		return err
+	}
+
+	actions, err := planApply([]manifest{{source: po.project.source, content: po.project.manifest}}, fs)
+	if err != nil {
+		return err
+	}
+
+	if err := runApply(actions, false); err != nil {
+		return err
+	}
+
+	vm, err := po.project.vm()
+	if err != nil {
+		return err
+	}
+
+	// The shares are copied when the VM is created, and synced on the next runs
+	if current != nil {
+		if err := po.project.syncShares(vm); err != nil {
+			return err
+		}
+	}
+
+	if current == nil || po.Provision {
+		return po.project.provision(vm)
+	}
+
+	return nil
+}
+
+// Halt stops the VM of the project
+func Halt(po *ProjectOptions) error {
+	vm, err := po.project.vm()
+	if err != nil {
+		return err
+	}
+
+	if vm == nil || !vm.Running() {
+		log.Infof("The VM of the project in %s isn't running", po.project.dir)
+		return nil
+	}
+
+	return Stop(&StopOptions{StopFlags: &StopFlags{}, vms: []*api.VM{vm}})
+}
+
+// Destroy stops and removes the VM of the project
+func Destroy(po *ProjectOptions) error {
+	vm, err := po.project.vm()
+	if err != nil {
+		return err
+	}
+
+	if vm == nil {
+		log.Infof("The VM of the project in %s doesn't exist", po.project.dir)
+		return nil
+	}
+
+	if vm.Running() {
+		if err := Stop(&StopOptions{StopFlags: &StopFlags{}, vms: []*api.VM{vm}}); err != nil {
+			return err
+		}
+	}
+
+	// The VM may still be recorded as running if it didn't stop in time
+	return Rm(&RmOptions{RmFlags: &RmFlags{Force: true}, vms: []*api.VM{vm}})
+}
+
+// projectVM returns the VM of the project of the current directory, for the commands
+// taking an optional VM
+func projectVM() (*api.VM, error) {
+	p, err := loadCurrentProject()
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := p.vm()
+	if err != nil {
+		return nil, err
+	}
+
+	if vm == nil {
+		return nil, fmt.Errorf("the VM of the project in %s doesn't exist, create it with \"ignite up\"", p.dir)
+	}
+
+	return vm, nil
+}
+
+// vm returns the VM of the project, or nil if it doesn't exist
+func (p *project) vm() (*api.VM, error) {
+	vm, err := providers.Client.VMs().Find(filter.NewNameFilter(p.vmName))
+	if _, ok := err.(*filterer.NonexistentError); ok {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := checkProjectVM(vm, p.dir); err != nil {
+		return nil, err
+	}
+
+	return vm, nil
+}
+
+// checkProjectVM fails if the VM wasn't created for the project in the directory,
+// to not take over a VM that has the same name
+func checkProjectVM(vm *api.VM, dir string) error {
+	switch owner := vm.GetAnnotation(constants.IGNITE_PROJECT_ANNOTATION); owner {
+	case dir:
+		return nil
+	case "":
+		return fmt.Errorf("VM %q isn't the VM of a project, set another name in the project file", vm.GetName())
+	default:
+		return fmt.Errorf("VM %q is the VM of the project in %s, set another name in the project file", vm.GetName(), owner)
+	}
+}
+
+// syncShares copies the shares into the running VM again. The files removed on the
+// host aren't removed in the VM.
+func (p *project) syncShares(vm *api.VM) error {
+	if len(p.shares) == 0 || !vm.Running() {
+		return nil
+	}
+
+	client, err := newSFTPClient(vm, "", constants.SSH_DEFAULT_TIMEOUT_SECONDS)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for _, share := range p.shares {
+		log.Infof("Copying %s to %s in the VM", share.HostPath, share.VMPath)
+		if err := syncShare(client, share); err != nil {
+			return fmt.Errorf("failed to copy %s to the VM: %v", share.HostPath, err)
+		}
+	}
+
+	return nil
+}
+
+// syncShare copies the share into the VM. The entries of a directory are copied one
+// by one, as copying the directory to an existing directory copies it into it.
+func syncShare(client *sftp.Client, share api.FileMapping) error {
+	fi, err := os.Stat(share.HostPath)
+	if err != nil {
+		return err
+	}
+
+	if !fi.IsDir() {
+		return copyToVM(client, share.HostPath, share.VMPath)
+	}
+
+	if err := client.MkdirAll(share.VMPath); err != nil {
+		return err
+	}
+
+	entries, err := ioutil.ReadDir(share.HostPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := copyToVM(client, filepath.Join(share.HostPath, entry.Name()), path.Join(share.VMPath, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// provision runs the provisioners of the project in order in the VM, through its
+// guest agent if it has one, or else over SSH
+func (p *project) provision(vm *api.VM) error {
+	if vm == nil || !vm.Running() {
+		return fmt.Errorf("the VM of the project in %s isn't running", p.dir)
+	}
+
+	for i, provisioner := range p.provisioners {
+		script, description := provisioner.Shell, "shell command"
+		if len(provisioner.Script) > 0 {
+			b, err := ioutil.ReadFile(provisioner.Script)
+			if err != nil {
+				return err
+			}
+
+			script, description = string(b), provisioner.Script
+		}
+
+		log.Infof("Running provisioner %d (%s)", i+1, description)
+		req := &protocol.ExecRequest{Command: []string{"sh", "-c", script}}
+		code, err := agent.Exec(vm, req, &agent.ExecStreams{Stdout: os.Stdout, Stderr: os.Stderr})
+		if errors.Is(err, agent.ErrNoAgent) {
+			log.Debugf("Falling back to SSH: %v", err)
+			code, err = runSSHScript(vm, "", constants.SSH_DEFAULT_TIMEOUT_SECONDS, script, nil, os.Stdout, os.Stderr)
+		}
+
+		if err != nil {
+			return fmt.Errorf("provisioner %d (%s) failed: %v", i+1, description, err)
+		}
+
+		if code != 0 {
+			return fmt.Errorf("provisioner %d (%s) exited with code %d, run it again with \"ignite up --provision\"", i+1, description, code)
+		}
+	}
+
+	return nil
+}
+
+// loadCurrentProject loads the project file of the current directory or of its parents
+func loadCurrentProject() (*project, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	filename, err := findProjectFile(wd)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadProject(filename)
+}
+
+// findProjectFile looks up the project file in the directory and then in its parents
+func findProjectFile(dir string) (string, error) {
+	for d := dir; ; d = filepath.Dir(d) {
+		for _, name := range projectFiles {
+			filename := filepath.Join(d, name)
+			if fi, err := os.Stat(filename); err == nil && !fi.IsDir() {
+				return filename, nil
+			}
+		}
+
+		if d == filepath.Dir(d) {
+			return "", fmt.Errorf("no Ignitefile or ignite.yaml found in %s or its parent directories", dir)
+		}
+	}
+}
+
+// loadProject reads the project file, and builds the manifest of the VM of the project
+func loadProject(filename string) (*project, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonContent, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", filename, err)
+	}
+
+	pf := &projectFile{}
+	if err := strictUnmarshal(jsonContent, pf); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", filename, err)
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := buildProject(pf, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	p.source = filename
+	return p, nil
+}
+
+// buildProject validates the project file, and builds the manifest of the VM of the
+// project in the directory. The paths of the project file are relative to the directory.
+func buildProject(pf *projectFile, dir string) (*project, error) {
+	p := &project{dir: dir, vmName: pf.Name}
+	if len(p.vmName) == 0 {
+		p.vmName = projectName(filepath.Base(dir))
+	}
+	if len(p.vmName) == 0 {
+		return nil, fmt.Errorf("the project needs a name, the name of its directory can't be used")
+	}
+
+	if len(pf.Image) == 0 {
+		return nil, fmt.Errorf("the image of the VM is mandatory")
+	}
+
+	spec := map[string]interface{}{}
+	for k, v := range pf.Spec {
+		spec[k] = v
+	}
+
+	spec["image"] = map[string]interface{}{"oci": pf.Image}
+	if len(pf.Kernel) > 0 {
+		spec["kernel"] = map[string]interface{}{"oci": pf.Kernel}
+	}
+	if pf.CPUs > 0 {
+		spec["cpus"] = pf.CPUs
+	}
+	if len(pf.Memory) > 0 {
+		spec["memory"] = pf.Memory
+	}
+	if len(pf.DiskSize) > 0 {
+		spec["diskSize"] = pf.DiskSize
+	}
+
+	if len(pf.Ports) > 0 {
+		ports, err := meta.ParsePortMappings(pf.Ports)
+		if err != nil {
+			return nil, err
+		}
+
+		// The mappings are parsed in random order, which apply would see as a change
+		sort.Slice(ports, func(i, j int) bool {
+			return ports[i].String() < ports[j].String()
+		})
+
+		network, _ := spec["network"].(map[string]interface{})
+		if network == nil {
+			network = map[string]interface{}{}
+		}
+		network["ports"] = ports
+		spec["network"] = network
+	}
+
+	for _, share := range pf.Shares {
+		hostPath := share.HostPath
+		if !filepath.IsAbs(hostPath) {
+			hostPath = filepath.Join(dir, hostPath)
+		}
+
+		if _, err := os.Stat(hostPath); err != nil {
+			return nil, fmt.Errorf("invalid share: %v", err)
+		}
+
+		if !path.IsAbs(share.VMPath) {
+			return nil, fmt.Errorf("invalid share of %s: the path in the VM must be absolute", share.HostPath)
+		}
+
+		p.shares = append(p.shares, api.FileMapping{HostPath: hostPath, VMPath: share.VMPath})
+	}
+	if len(p.shares) > 0 {
+		spec["copyFiles"] = p.shares
+	}
+
+	for i, provisioner := range pf.Provision {
+		if (len(provisioner.Shell) > 0) == (len(provisioner.Script) > 0) {
+			return nil, fmt.Errorf("provisioner %d needs either a shell command or a script", i+1)
+		}
+
+		if len(provisioner.Script) > 0 && !filepath.IsAbs(provisioner.Script) {
+			provisioner.Script = filepath.Join(dir, provisioner.Script)
+		}
+
+		p.provisioners = append(p.provisioners, provisioner)
+	}
+
+	// The provisioners and "ignite ssh" need to log in to the VM
+	if _, ok := spec["ssh"]; !ok {
+		spec["ssh"] = map[string]interface{}{"generate": true}
+	}
+
+	var err error
+	p.manifest, err = json.Marshal(map[string]interface{}{
+		"apiVersion": v1alpha5.SchemeGroupVersion.String(),
+		"kind":       api.KindVM,
+		"metadata": map[string]interface{}{
+			"name":        p.vmName,
+			"annotations": map[string]string{constants.IGNITE_PROJECT_ANNOTATION: dir},
+		},
+		"spec":   spec,
+		"status": map[string]interface{}{"running": true},
+	})
+
+	return p, err
+}
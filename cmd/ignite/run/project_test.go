@@ -0,0 +1,143 @@
+package run
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+const projectTestFile = `image: weaveworks/ignite-ubuntu:latest
+cpus: 2
+memory: 2GB
+ports: ["8443:443", "8080:80"]
+shares:
+- hostPath: ./src
+  vmPath: /src
+provision:
+- shell: apt-get update
+- script: scripts/provision.sh
+spec:
+  kernel:
+    cmdLine: console=ttyS0
+`
+
+func TestLoadProject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-project-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	projectDir := filepath.Join(dir, "My_App")
+	assert.NilError(t, os.MkdirAll(filepath.Join(projectDir, "src", "cmd"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(projectDir, "Ignitefile"), []byte(projectTestFile), 0644))
+
+	// The project file is found from the subdirectories of the project
+	filename, err := findProjectFile(filepath.Join(projectDir, "src", "cmd"))
+	assert.NilError(t, err)
+	assert.Equal(t, filename, filepath.Join(projectDir, "Ignitefile"))
+
+	_, err = findProjectFile(dir)
+	assert.Error(t, err, "no Ignitefile or ignite.yaml found in "+dir+" or its parent directories")
+
+	p, err := loadProject(filename)
+	assert.NilError(t, err)
+	assert.Equal(t, p.vmName, "my-app")
+	assert.DeepEqual(t, p.provisioners, []projectProvisioner{
+		{Shell: "apt-get update"},
+		{Script: filepath.Join(projectDir, "scripts", "provision.sh")},
+	})
+
+	var vm map[string]interface{}
+	assert.NilError(t, json.Unmarshal(p.manifest, &vm))
+	assert.DeepEqual(t, vm, map[string]interface{}{
+		"apiVersion": "ignite.weave.works/v1alpha5",
+		"kind":       "VM",
+		"metadata": map[string]interface{}{
+			"name":        "my-app",
+			"annotations": map[string]interface{}{"ignite.weave.works/project": projectDir},
+		},
+		"spec": map[string]interface{}{
+			"image":  map[string]interface{}{"oci": "weaveworks/ignite-ubuntu:latest"},
+			"kernel": map[string]interface{}{"cmdLine": "console=ttyS0"},
+			"cpus":   float64(2),
+			"memory": "2GB",
+			"network": map[string]interface{}{"ports": []interface{}{
+				map[string]interface{}{"hostPort": float64(8080), "vmPort": float64(80), "protocol": "tcp"},
+				map[string]interface{}{"hostPort": float64(8443), "vmPort": float64(443), "protocol": "tcp"},
+			}},
+			"copyFiles": []interface{}{map[string]interface{}{"hostPath": filepath.Join(projectDir, "src"), "vmPath": "/src"}},
+			"ssh":       map[string]interface{}{"generate": true},
+		},
+		"status": map[string]interface{}{"running": true},
+	})
+}
+
+func TestBuildProjectErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-project-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := []struct {
+		name    string
+		project string
+		err     string
+	}{
+		{
+			name:    "no image",
+			project: `{"cpus": 1}`,
+			err:     "the image of the VM is mandatory",
+		},
+		{
+			name:    "missing share",
+			project: `{"image": "weaveworks/ignite-ubuntu", "shares": [{"hostPath": "src", "vmPath": "/src"}]}`,
+			err:     "invalid share: stat " + filepath.Join(dir, "src") + ": no such file or directory",
+		},
+		{
+			name:    "relative share path in the VM",
+			project: `{"image": "weaveworks/ignite-ubuntu", "shares": [{"hostPath": ".", "vmPath": "src"}]}`,
+			err:     "invalid share of .: the path in the VM must be absolute",
+		},
+		{
+			name:    "provisioner with a command and a script",
+			project: `{"image": "weaveworks/ignite-ubuntu", "provision": [{"shell": "true", "script": "provision.sh"}]}`,
+			err:     "provisioner 1 needs either a shell command or a script",
+		},
+		{
+			name:    "invalid port",
+			project: `{"image": "weaveworks/ignite-ubuntu", "ports": ["x:80"]}`,
+			err:     `Invalid hostPort: x`,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			pf := &projectFile{}
+			assert.NilError(t, strictUnmarshal([]byte(rt.project), pf))
+
+			_, err := buildProject(pf, dir)
+			assert.Error(t, err, rt.err)
+		})
+	}
+}
+
+func TestCheckProjectVM(t *testing.T) {
+	vm := &api.VM{}
+	vm.SetName("dev")
+	assert.Error(t, checkProjectVM(vm, "/src/app"), `VM "dev" isn't the VM of a project, set another name in the project file`)
+
+	vm.SetAnnotation(constants.IGNITE_PROJECT_ANNOTATION, "/src/other")
+	assert.Error(t, checkProjectVM(vm, "/src/app"), `VM "dev" is the VM of the project in /src/other, set another name in the project file`)
+
+	vm.SetAnnotation(constants.IGNITE_PROJECT_ANNOTATION, "/src/app")
+	assert.NilError(t, checkProjectVM(vm, "/src/app"))
+}
@@ -5,16 +5,20 @@ import (
 	"fmt"
 	"os"
 	"text/template"
+	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/filter"
+	"github.com/weaveworks/ignite/pkg/operations"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/runtime"
 	containerdruntime "github.com/weaveworks/ignite/pkg/runtime/containerd"
 	dockerruntime "github.com/weaveworks/ignite/pkg/runtime/docker"
 	"github.com/weaveworks/ignite/pkg/util"
+	apiruntime "github.com/weaveworks/libgitops/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
 )
 
 // runtimeRunningStatus is the status returned from the container runtimes when
@@ -22,11 +26,16 @@ import (
 const runtimeRunningStatus = "running"
 const oldManifestIndicator = "*"
 
+// now returns the time the age of the VMs is counted to, the tests replace it
+var now = time.Now
+
 // PsFlags contains the flags supported by ps.
 type PsFlags struct {
 	All            bool
 	Filter         string
+	Selector       string
 	TemplateFormat string
+	Output         string
 }
 
 type PsOptions struct {
@@ -37,11 +46,25 @@ type PsOptions struct {
 // NewPsOptions constructs and returns PsOptions.
 func (pf *PsFlags) NewPsOptions() (po *PsOptions, err error) {
 	po = &PsOptions{PsFlags: pf}
-	po.allVMs, err = providers.Client.VMs().FindAll(filter.NewVMFilterAll("", po.All))
+	labelFilter, err := filter.NewLabelFilter(pf.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	vms, err := providers.Client.VMs().FindAll(filter.NewVMFilterAll("", po.All))
 	// If the storage is uninitialized, avoid failure and continue with empty
 	// VM list.
-	if err != nil && os.IsNotExist(err) {
-		err = nil
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+
+	for _, vm := range vms {
+		if labelFilter.Matches(vm) {
+			po.allVMs = append(po.allVMs, vm)
+		}
 	}
 	return
 }
@@ -114,28 +137,40 @@ func Ps(po *PsOptions) error {
 		return nil
 	}
 
-	o := util.NewOutput()
-	defer o.Flush()
-
-	o.Write("VM ID", "IMAGE", "KERNEL", "SIZE", "CPUS", "MEMORY", "CREATED", "STATUS", "IPS", "PORTS", "NAME")
+	objects := make([]apiruntime.Object, 0, len(filteredVMs))
 	for _, vm := range filteredVMs {
-		o.Write(vm.GetUID(), vm.Spec.Image.OCI, vm.Spec.Kernel.OCI,
-			vm.Spec.DiskSize, vm.Spec.CPUs, vm.Spec.Memory, formatCreated(vm), formatStatus(vm, outdatedVMs), vm.Status.Network.IPAddresses,
-			vm.Spec.Network.Ports, vm.GetName())
+		objects = append(objects, vm)
 	}
 
-	return nil
+	return printList(po.Output, objects, func() {
+		o := util.NewOutput()
+		defer o.Flush()
+
+		o.Write("VM ID", "IMAGE", "KERNEL", "SIZE", "CPUS", "MEMORY", "CREATED", "STATUS", "IPS", "PORTS", "NAME")
+		for _, vm := range filteredVMs {
+			o.Write(vm.GetUID(), vm.Spec.Image.OCI, formatKernel(vm),
+				vm.Spec.DiskSize, vm.Spec.CPUs, vm.Spec.Memory, formatCreated(vm), formatStatus(vm, outdatedVMs), vm.Status.Network.IPAddresses,
+				vm.Spec.Network.Ports, vm.GetName())
+		}
+	})
 }
 
 func formatCreated(vm *api.VM) string {
 	created := vm.GetCreated()
+	if created.IsZero() {
+		return fmt.Sprint(created)
+	}
+
+	return duration.HumanDuration(now().Sub(created.Time.Time)) + " ago"
+}
 
-	var suffix string
-	if !created.IsZero() {
-		suffix = " ago"
+// formatKernel returns the kernel of the VM, VMs of disk images boot the kernel in their disk
+func formatKernel(vm *api.VM) interface{} {
+	if vm.DiskImage() {
+		return "<disk image>"
 	}
 
-	return fmt.Sprint(created, suffix)
+	return vm.Spec.Kernel.OCI
 }
 
 func formatStatus(vm *api.VM, outdatedVMs map[string]bool) string {
@@ -144,10 +179,26 @@ func formatStatus(vm *api.VM, outdatedVMs map[string]bool) string {
 		isOld = oldManifestIndicator
 	}
 
+	if vm.Paused() {
+		return fmt.Sprintf("%sUp %s (Paused)", isOld, vm.Status.StartTime)
+	}
+
+	if vm.Running() && vm.ConditionTrue(api.VMDegraded) {
+		return fmt.Sprintf("%sUp %s (Degraded)", isOld, vm.Status.StartTime)
+	}
+
+	if vm.Running() && vm.ConditionTrue(api.VMEmulated) {
+		return fmt.Sprintf("%sUp %s (Emulated)", isOld, vm.Status.StartTime)
+	}
+
 	if vm.Running() {
 		return fmt.Sprintf("%sUp %s", isOld, vm.Status.StartTime)
 	}
 
+	if operations.Hibernated(vm) {
+		return isOld + "Hibernated"
+	}
+
 	return isOld + "Stopped"
 }
 
@@ -84,6 +84,13 @@ func createTestVM(name, id string) (*api.VM, error) {
 // Update the golden files with:
 //   go test -v github.com/weaveworks/ignite/cmd/ignite/run -run TestPs -update
 func TestPs(t *testing.T) {
+	// Count the age of the VMs to a fixed time, so the golden files don't age
+	oldNow := now
+	defer func() { now = oldNow }()
+	now = func() time.Time {
+		return time.Date(2022, time.January, 1, 1, 0, 0, 0, time.UTC)
+	}
+
 	// Existing VMs with UID for deterministic results.
 	// A sorted list of VMs. The VM list returned by the VM filter is sorted by
 	// VM UID.
@@ -113,6 +120,16 @@ func TestPs(t *testing.T) {
 			psFlags: &PsFlags{Filter: "{{.ObjectMeta.Name}}!=vm2", TemplateFormat: "Name: {{.ObjectMeta.Name}} Image: {{.Spec.Image.OCI}}"},
 			golden:  "output/ps-formatted-table.txt",
 		},
+		{
+			name:    "list in custom columns",
+			psFlags: &PsFlags{Output: "custom-columns=NAME:.ObjectMeta.Name,CPUS:.Spec.CPUs"},
+			golden:  "output/ps-custom-columns.txt",
+		},
+		{
+			name:    "list in go template format",
+			psFlags: &PsFlags{Output: "go-template={{.ObjectMeta.UID}} {{.Spec.Image.OCI}}"},
+			golden:  "output/ps-go-template.txt",
+		},
 	}
 
 	for _, rt := range cases {
@@ -0,0 +1,120 @@
+package run
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/logs"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+type RenameOptions struct {
+	vm   *api.VM
+	name string
+}
+
+func NewRenameOptions(vmMatch, name string) (ro *RenameOptions, err error) {
+	if err := validation.ValidateVMName(name, field.NewPath("name")).ToAggregate(); err != nil {
+		return nil, err
+	}
+
+	ro = &RenameOptions{name: name}
+	ro.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// Rename changes the name of the VM, and updates the references to it by name
+func Rename(ro *RenameOptions) error {
+	oldName := ro.vm.GetName()
+	if oldName == ro.name {
+		return fmt.Errorf("VM %q is already named %q", ro.vm.GetUID(), ro.name)
+	}
+
+	if err := verifyRenamable(ro.vm); err != nil {
+		return err
+	}
+
+	if err := metadata.SetName(ro.vm, ro.name, nil); err != nil {
+		return err
+	}
+
+	if err := providers.Client.VMs().Set(ro.vm); err != nil {
+		return err
+	}
+
+	// Other VMs may refer to the VM by name to be autostarted after it, or in their hosts
+	vms, err := getAllVMs()
+	if err != nil {
+		return err
+	}
+
+	for _, vm := range renameReferences(vms, oldName, ro.name) {
+		log.Infof("Updating the references of VM %q with name %q", vm.GetUID(), vm.GetName())
+		if err := providers.Client.VMs().Set(vm); err != nil {
+			return err
+		}
+
+		if vm.Running() && len(vm.GetAnnotation(constants.IGNITE_HOST_ANNOTATION+ro.name)) > 0 {
+			log.Warnf("VM %q is running, its /etc/hosts keeps the name %q until it's restarted", vm.GetUID(), oldName)
+		}
+	}
+
+	// The container of a running VM is labeled with the name it was started with
+	if ro.vm.Running() {
+		log.Warnf("VM %q is running, its container keeps the name %q until it's restarted", ro.vm.GetUID(), oldName)
+	}
+
+	if logs.Quiet {
+		fmt.Println(ro.vm.GetUID())
+	} else {
+		log.Infof("Renamed %s with ID %q from %q to %q", ro.vm.GetKind(), ro.vm.GetUID(), oldName, ro.name)
+	}
+
+	return nil
+}
+
+// verifyRenamable returns an error if the name of the VM is set by the stack file of
+// "ignite compose" or the project file of "ignite up", which find their VMs by name
+func verifyRenamable(vm *api.VM) error {
+	if project := vm.GetLabel(constants.IGNITE_COMPOSE_PROJECT_LABEL); len(project) > 0 {
+		return fmt.Errorf("VM %q is named by stack %q of ignite compose, change its name in the stack file instead", vm.GetName(), project)
+	}
+
+	if dir := vm.GetAnnotation(constants.IGNITE_PROJECT_ANNOTATION); len(dir) > 0 {
+		return fmt.Errorf("VM %q is the VM of the project in %s, change its name in the project file instead", vm.GetName(), dir)
+	}
+
+	return nil
+}
+
+// renameReferences replaces oldName with newName in the autostart dependencies and
+// the host annotations of the given VMs, and returns the VMs that have been changed
+func renameReferences(vms []*api.VM, oldName, newName string) (changed []*api.VM) {
+	for _, vm := range vms {
+		found := false
+		for i, name := range vm.Spec.AutostartAfter {
+			if name == oldName {
+				vm.Spec.AutostartAfter[i] = newName
+				found = true
+			}
+		}
+
+		if ip := vm.GetAnnotation(constants.IGNITE_HOST_ANNOTATION + oldName); len(ip) > 0 {
+			delete(vm.GetObjectMeta().Annotations, constants.IGNITE_HOST_ANNOTATION+oldName)
+			vm.SetAnnotation(constants.IGNITE_HOST_ANNOTATION+newName, ip)
+			found = true
+		}
+
+		if found {
+			changed = append(changed, vm)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,54 @@
+package run
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+func TestRenameReferences(t *testing.T) {
+	newVM := func(name string, after ...string) *api.VM {
+		vm := &api.VM{}
+		vm.SetName(name)
+		vm.Spec.AutostartAfter = after
+		return vm
+	}
+
+	db := newVM("db")
+	app := newVM("app", "db", "cache")
+	web := newVM("web", "app")
+	worker := newVM("worker", "cache", "db")
+	monitor := newVM("monitor")
+	monitor.SetAnnotation(constants.IGNITE_HOST_ANNOTATION+"db", "10.61.0.2")
+	monitor.SetAnnotation(constants.IGNITE_HOST_ANNOTATION+"web", "10.61.0.3")
+
+	changed := renameReferences([]*api.VM{db, app, web, worker, monitor}, "db", "postgres")
+
+	assert.Equal(t, len(changed), 3)
+	assert.Equal(t, changed[0], app)
+	assert.Equal(t, changed[1], worker)
+	assert.Equal(t, changed[2], monitor)
+	assert.DeepEqual(t, app.Spec.AutostartAfter, []string{"postgres", "cache"})
+	assert.DeepEqual(t, web.Spec.AutostartAfter, []string{"app"})
+	assert.DeepEqual(t, worker.Spec.AutostartAfter, []string{"cache", "postgres"})
+	assert.DeepEqual(t, monitor.Hosts(), map[string]string{"postgres": "10.61.0.2", "web": "10.61.0.3"})
+}
+
+func TestVerifyRenamable(t *testing.T) {
+	standalone := &api.VM{}
+	standalone.SetName("db")
+	assert.NilError(t, verifyRenamable(standalone))
+
+	stackVM := &api.VM{}
+	stackVM.SetName("shop-db")
+	stackVM.SetLabel(constants.IGNITE_COMPOSE_PROJECT_LABEL, "shop")
+	assert.Error(t, verifyRenamable(stackVM), `VM "shop-db" is named by stack "shop" of ignite compose, change its name in the stack file instead`)
+
+	projectVM := &api.VM{}
+	projectVM.SetName("blog")
+	projectVM.SetAnnotation(constants.IGNITE_PROJECT_ANNOTATION, "/home/user/blog")
+	assert.Error(t, verifyRenamable(projectVM), `VM "blog" is the VM of the project in /home/user/blog, change its name in the project file instead`)
+}
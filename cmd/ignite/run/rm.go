@@ -14,6 +14,8 @@ import (
 type RmFlags struct {
 	Force      bool
 	ConfigFile string
+	Selector   string
+	Parallel   int
 }
 
 type RmOptions struct {
@@ -28,7 +30,7 @@ func (rf *RmFlags) NewRmOptions(vmMatches []string) (*RmOptions, error) {
 
 	// If config file is provided, use it to find the VM to be removed.
 	if len(rf.ConfigFile) != 0 {
-		if len(vmMatches) > 0 {
+		if len(vmMatches) > 0 || len(rf.Selector) > 0 {
 			return ro, fmt.Errorf("cannot use both config flag and vm argument")
 		}
 
@@ -44,36 +46,31 @@ func (rf *RmFlags) NewRmOptions(vmMatches []string) (*RmOptions, error) {
 		return ro, nil
 	}
 
-	// Use vm args to find the VMs to be removed.
-	if len(vmMatches) < 1 {
-		return ro, fmt.Errorf("need at least one vm identifier as argument")
-	}
+	// Use vm args or the label selector to find the VMs to be removed.
 	var err error
-	ro.vms, err = getVMsForMatches(vmMatches)
+	ro.vms, err = getVMsForMatchesOrSelector(vmMatches, rf.Selector)
 	return ro, err
 }
 
-// Rm removes VMs based on RmOptions.
+// Rm removes VMs based on RmOptions, up to the parallel count of the RmFlags at once.
 func Rm(ro *RmOptions) error {
-	for _, vm := range ro.vms {
+	// Runtime and network info are present only when the VM is running.
+	setProviders := func(vm *api.VM) error {
+		if !vm.Running() {
+			return nil
+		}
+
+		// Set the runtime and network-plugin providers from the VM status.
+		return config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin)
+	}
+
+	return forEachVM(ro.vms, ro.Parallel, setProviders, func(vm *api.VM) error {
 		// If the VM is running, but we haven't enabled force-mode, return an error
 		if vm.Running() && !ro.Force {
 			return fmt.Errorf("%s is running", vm.GetUID())
 		}
 
-		// Runtime and network info are present only when the VM is running.
-		if vm.Running() {
-			// Set the runtime and network-plugin providers from the VM status.
-			if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
-				return err
-			}
-		}
-
 		// This will first kill the VM container, and then remove it
-		if err := operations.DeleteVM(providers.Client, vm); err != nil {
-			return err
-		}
-	}
-
-	return nil
+		return operations.DeleteVM(providers.Client, vm)
+	})
 }
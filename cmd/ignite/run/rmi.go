@@ -6,6 +6,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/bootcache"
 	"github.com/weaveworks/ignite/pkg/operations/lookup"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/libgitops/pkg/filter"
@@ -70,6 +71,11 @@ func Rmi(ro *RmiOptions) error {
 			return fmt.Errorf("unable to remove directory for %s %q: %v", image.GetKind(), image.GetUID(), err)
 		}
 
+		// The boot cache entries of the image can't be restored without it
+		if err := bootcache.RemoveFor(image.GetUID()); err != nil {
+			return fmt.Errorf("unable to remove the boot cache entries of %s %q: %v", image.GetKind(), image.GetUID(), err)
+		}
+
 		fmt.Println(image.GetUID())
 	}
 
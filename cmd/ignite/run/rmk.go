@@ -6,6 +6,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/bootcache"
 	"github.com/weaveworks/ignite/pkg/operations/lookup"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/libgitops/pkg/filter"
@@ -70,6 +71,11 @@ func Rmk(ro *RmkOptions) error {
 			return fmt.Errorf("unable to remove directory for %s %q: %v", kernel.GetKind(), kernel.GetUID(), err)
 		}
 
+		// The boot cache entries of the kernel can't be restored without it
+		if err := bootcache.RemoveFor(kernel.GetUID()); err != nil {
+			return fmt.Errorf("unable to remove the boot cache entries of %s %q: %v", kernel.GetKind(), kernel.GetUID(), err)
+		}
+
 		fmt.Println(kernel.GetUID())
 	}
 
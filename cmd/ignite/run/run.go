@@ -15,6 +15,11 @@ type RunOptions struct {
 }
 
 func (rf *RunFlags) NewRunOptions(args []string, fs *flag.FlagSet) (*RunOptions, error) {
+	// The result is printed once the VM is started
+	if err := checkStartOutput(rf.StartFlags); err != nil {
+		return nil, err
+	}
+
 	co, err := rf.NewCreateOptions(args, fs)
 	if err != nil {
 		return nil, err
@@ -24,6 +29,7 @@ func (rf *RunFlags) NewRunOptions(args []string, fs *flag.FlagSet) (*RunOptions,
 	so := &StartOptions{
 		StartFlags: rf.StartFlags,
 		AttachOptions: &AttachOptions{
+			AttachFlags:  &AttachFlags{},
 			checkRunning: false,
 		},
 	}
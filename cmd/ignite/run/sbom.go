@@ -0,0 +1,42 @@
+package run
+
+import (
+	"io"
+	"os"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/sbom"
+	"github.com/weaveworks/libgitops/pkg/filter"
+)
+
+type SBOMOptions struct {
+	image *api.Image
+}
+
+func NewSBOMOptions(imageMatch string) (*SBOMOptions, error) {
+	image, err := providers.Client.Images().Find(filter.NewIDNameFilter(imageMatch))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SBOMOptions{image: image}, nil
+}
+
+// SBOM writes the software bill of materials of the image to out, as it was generated
+// when the image was imported
+func SBOM(out io.Writer, so *SBOMOptions) error {
+	p, err := sbom.Path(so.image)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(out, f)
+	return err
+}
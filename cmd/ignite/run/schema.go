@@ -0,0 +1,92 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/weaveworks/ignite/pkg/schema"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// SchemaExportFlags contains the flags supported by the schema export command.
+type SchemaExportFlags struct {
+	APIVersion string
+	OutputDir  string
+}
+
+type SchemaExportOptions struct {
+	*SchemaExportFlags
+	kinds []runtime.Kind
+}
+
+func (sef *SchemaExportFlags) NewSchemaExportOptions(kinds []string) (*SchemaExportOptions, error) {
+	seo := &SchemaExportOptions{SchemaExportFlags: sef}
+	for _, k := range kinds {
+		kind, err := schema.ParseKind(k)
+		if err != nil {
+			return nil, err
+		}
+
+		seo.kinds = append(seo.kinds, kind)
+	}
+
+	// Export all the kinds by default
+	if len(seo.kinds) == 0 {
+		seo.kinds = schema.Kinds
+	}
+
+	return seo, nil
+}
+
+// SchemaExport writes the JSON schema of the kinds to stdout. With an output directory,
+// a schema for every kind is written to it instead, named after the kind, e.g. vm.json.
+func SchemaExport(seo *SchemaExportOptions) error {
+	if len(seo.OutputDir) == 0 {
+		b, err := marshalSchema(seo.APIVersion, seo.kinds)
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+
+	if err := os.MkdirAll(seo.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	for _, kind := range seo.kinds {
+		b, err := marshalSchema(seo.APIVersion, []runtime.Kind{kind})
+		if err != nil {
+			return err
+		}
+
+		file := path.Join(seo.OutputDir, kind.Lower()+".json")
+		if err := ioutil.WriteFile(file, b, 0644); err != nil {
+			return err
+		}
+
+		log.Infof("Wrote the schema of %s to %s", kind.Title(), file)
+	}
+
+	return nil
+}
+
+func marshalSchema(apiVersion string, kinds []runtime.Kind) ([]byte, error) {
+	s, err := schema.ForKinds(apiVersion, kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the schema: %v", err)
+	}
+
+	return append(b, '\n'), nil
+}
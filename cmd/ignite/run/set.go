@@ -0,0 +1,124 @@
+package run
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/authorizedkeys"
+	"github.com/weaveworks/ignite/pkg/logs"
+	"github.com/weaveworks/ignite/pkg/migration"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// SetFlags contains the flags supported by the set command.
+// Zero values leave the resource of the VM unchanged.
+type SetFlags struct {
+	CPUs     uint64
+	Memory   meta.Size
+	DiskSize meta.Size
+}
+
+type SetOptions struct {
+	*SetFlags
+	vm *api.VM
+}
+
+func (sf *SetFlags) NewSetOptions(vmMatch string) (so *SetOptions, err error) {
+	if sf.CPUs == 0 && sf.Memory == meta.EmptySize && sf.DiskSize == meta.EmptySize {
+		return nil, fmt.Errorf("no resources to set given, use --cpus, --memory or --size")
+	}
+
+	so = &SetOptions{SetFlags: sf}
+	so.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// Set changes the resources of a stopped VM, they're applied when it's started the next time
+func Set(so *SetOptions) error {
+	vm := so.vm.DeepCopy()
+	if so.CPUs != 0 {
+		vm.Spec.CPUs = so.CPUs
+	}
+
+	if so.Memory != meta.EmptySize {
+		vm.Spec.Memory = so.Memory
+	}
+
+	if so.DiskSize != meta.EmptySize {
+		vm.Spec.DiskSize = so.DiskSize
+	}
+
+	return updateVM(so.vm, vm)
+}
+
+// updateVM validates and saves the changed spec of a stopped VM. The disk of the VM
+// is grown, together with its filesystem, if the disk size has been increased.
+func updateVM(current, updated *api.VM) error {
+	if err := checkVMUpdate(current, updated); err != nil {
+		return err
+	}
+
+	if updated.Spec.DiskSize.Bytes() > current.Spec.DiskSize.Bytes() {
+		log.Infof("Growing the disk of VM %q from %s to %s...", current.GetUID(), current.Spec.DiskSize, updated.Spec.DiskSize)
+		if err := operations.GrowDisk(updated); err != nil {
+			return fmt.Errorf("failed to grow the disk of VM %q: %v", current.GetUID(), err)
+		}
+	}
+
+	// Authorize the keys added to the spec in the VM, and revoke the removed ones
+	if added, removed := authorizedKeysDiff(current, updated); len(added) > 0 || len(removed) > 0 {
+		if err := operations.UpdateAuthorizedKeys(updated, func(content []byte) ([]byte, error) {
+			return authorizedkeys.Add(authorizedkeys.Remove(content, removed), added), nil
+		}); err != nil {
+			return fmt.Errorf("failed to update the authorized keys of VM %q: %v", current.GetUID(), err)
+		}
+	}
+
+	if err := providers.Client.VMs().Set(updated); err != nil {
+		return err
+	}
+
+	if logs.Quiet {
+		fmt.Println(updated.GetUID())
+	} else {
+		log.Infof("Updated %s with name %q and ID %q", updated.GetKind(), updated.GetName(), updated.GetUID())
+	}
+
+	return nil
+}
+
+// checkVMUpdate checks that the VM can be changed to the updated VM
+func checkVMUpdate(current, updated *api.VM) error {
+	if current.Running() {
+		return fmt.Errorf("VM %q is running, stop it before changing it", current.GetUID())
+	}
+
+	// The snapshot of a migrated or hibernated VM fixes its resources until it has been restored
+	if migration.Pending(current) {
+		return fmt.Errorf("VM %q has a saved state from a migration or hibernation, start it before changing it", current.GetUID())
+	}
+
+	if err := validation.ValidateVM(updated).ToAggregate(); err != nil {
+		return err
+	}
+
+	// The disk of the VM is based on its image and contains its kernel modules
+	if updated.Spec.Image.OCI != current.Spec.Image.OCI {
+		return fmt.Errorf("the image of VM %q can't be changed", current.GetUID())
+	}
+
+	if updated.Spec.Kernel.OCI != current.Spec.Kernel.OCI {
+		return fmt.Errorf("the kernel of VM %q can't be changed", current.GetUID())
+	}
+
+	if updated.Spec.DiskSize.Bytes() < current.Spec.DiskSize.Bytes() {
+		return fmt.Errorf("the disk of VM %q can't be shrunk below its current size of %s", current.GetUID(), current.Spec.DiskSize)
+	}
+
+	return nil
+}
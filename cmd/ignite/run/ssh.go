@@ -36,9 +36,15 @@ type SshOptions struct {
 	vm *api.VM
 }
 
-// NewSSHOptions returns ssh options for a given VM.
+// NewSSHOptions returns ssh options for a given VM, or for the VM of the
+// project of the current directory if no VM is given.
 func (sf *SSHFlags) NewSSHOptions(vmMatch string) (so *SshOptions, err error) {
 	so = &SshOptions{SSHFlags: sf}
+	if len(vmMatch) == 0 {
+		so.vm, err = projectVM()
+		return
+	}
+
 	so.vm, err = getVMForMatch(vmMatch)
 	return
 }
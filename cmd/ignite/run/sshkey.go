@@ -0,0 +1,350 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	"github.com/weaveworks/ignite/pkg/authorizedkeys"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/migration"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// guestAuthorizedKeys is the authorized_keys file of root in the VM
+const guestAuthorizedKeys = "/root/.ssh/authorized_keys"
+
+var (
+	// readAuthorizedKeysScript prints the authorized_keys file, which may not exist yet
+	readAuthorizedKeysScript = fmt.Sprintf("cat %s 2>/dev/null || true", guestAuthorizedKeys)
+	// writeAuthorizedKeysScript replaces the authorized_keys file with stdin. It's written
+	// to a temporary file first, so a failed write leaves the file intact.
+	writeAuthorizedKeysScript = fmt.Sprintf("set -e; umask 077; mkdir -p %[1]s; cat > %[2]s.ignite; mv %[2]s.ignite %[2]s",
+		path.Dir(guestAuthorizedKeys), guestAuthorizedKeys)
+)
+
+// SSHKeyFlags contains the flags supported by the ssh-key commands.
+type SSHKeyFlags struct {
+	Timeout      uint32
+	IdentityFile string
+}
+
+type SSHKeyOptions struct {
+	*SSHKeyFlags
+	vm   *api.VM
+	keys []string
+}
+
+// NewSSHKeyOptions returns the options for changing the SSH keys of a VM. A key is given
+// either as the path of a file with public keys, such as ~/.ssh/id_ed25519.pub, or as
+// a public key in the authorized_keys format.
+func (sf *SSHKeyFlags) NewSSHKeyOptions(vmMatch string, keys ...string) (so *SSHKeyOptions, err error) {
+	so = &SSHKeyOptions{SSHKeyFlags: sf}
+	for _, key := range keys {
+		parsed, err := parsePublicKeys(key)
+		if err != nil {
+			return nil, err
+		}
+
+		so.keys = append(so.keys, parsed...)
+	}
+
+	so.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// parsePublicKeys returns the public keys in the file at the given path, or the key
+// itself if there's no such file
+func parsePublicKeys(key string) ([]string, error) {
+	if !util.FileExists(key) {
+		if _, err := authorizedkeys.Parse(key); err != nil {
+			return nil, fmt.Errorf("%q is neither a public key file nor a public key: %v", key, err)
+		}
+
+		return []string{key}, nil
+	}
+
+	content, err := ioutil.ReadFile(key)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := authorizedkeys.ParseList(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the public keys in %q: %v", key, err)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys found in %q", key)
+	}
+
+	return keys, nil
+}
+
+// SSHKeyAdd authorizes the public keys for root in the VM, and adds them to the
+// authorized keys in its spec
+func SSHKeyAdd(so *SSHKeyOptions) error {
+	vm := so.vm.DeepCopy()
+	if vm.Spec.SSH == nil {
+		vm.Spec.SSH = &api.SSH{}
+	}
+
+	for _, key := range so.keys {
+		if !authorizedkeys.Contains(vm.Spec.SSH.AuthorizedKeys, key) {
+			vm.Spec.SSH.AuthorizedKeys = append(vm.Spec.SSH.AuthorizedKeys, key)
+		}
+	}
+
+	return updateSSHKeys(so, vm, func(content []byte) ([]byte, error) {
+		return authorizedkeys.Add(content, so.keys), nil
+	})
+}
+
+// SSHKeyRemove revokes the public keys for root in the VM, and removes them from the
+// authorized keys in its spec. Keys that aren't in the spec are revoked as well.
+func SSHKeyRemove(so *SSHKeyOptions) error {
+	vm := so.vm.DeepCopy()
+	if vm.Spec.SSH != nil {
+		var kept []string
+		for _, key := range vm.Spec.SSH.AuthorizedKeys {
+			if !authorizedkeys.Contains(so.keys, key) {
+				kept = append(kept, key)
+			}
+		}
+		vm.Spec.SSH.AuthorizedKeys = kept
+	}
+
+	return updateSSHKeys(so, vm, func(content []byte) ([]byte, error) {
+		return authorizedkeys.Remove(content, so.keys), nil
+	})
+}
+
+// updateSSHKeys updates the authorized_keys file of root in the VM, right away if the
+// VM is running, and saves the updated VM
+func updateSSHKeys(so *SSHKeyOptions, updated *api.VM, update func(content []byte) ([]byte, error)) (err error) {
+	if so.vm.Running() {
+		if err = validation.ValidateVM(updated).ToAggregate(); err != nil {
+			return
+		}
+
+		err = updateGuestAuthorizedKeys(so.vm, so.IdentityFile, so.Timeout, update)
+	} else {
+		if err = checkVMUpdate(so.vm, updated); err != nil {
+			return
+		}
+
+		if err = operations.UpdateAuthorizedKeys(so.vm, update); err != nil {
+			err = fmt.Errorf("failed to update the authorized keys of VM %q: %v", so.vm.GetUID(), err)
+		}
+	}
+
+	if err != nil {
+		return
+	}
+
+	if err = providers.Client.VMs().Set(updated); err != nil {
+		return
+	}
+
+	log.Infof("Updated %s with name %q and ID %q", updated.GetKind(), updated.GetName(), updated.GetUID())
+	return
+}
+
+// authorizedKeysDiff returns the authorized keys added to, and removed from, the spec
+// of the updated VM
+func authorizedKeysDiff(current, updated *api.VM) (added, removed []string) {
+	var currentKeys, updatedKeys []string
+	if current.Spec.SSH != nil {
+		currentKeys = current.Spec.SSH.AuthorizedKeys
+	}
+
+	if updated.Spec.SSH != nil {
+		updatedKeys = updated.Spec.SSH.AuthorizedKeys
+	}
+
+	for _, key := range updatedKeys {
+		if !authorizedkeys.Contains(currentKeys, key) {
+			added = append(added, key)
+		}
+	}
+
+	for _, key := range currentKeys {
+		if !authorizedkeys.Contains(updatedKeys, key) {
+			removed = append(removed, key)
+		}
+	}
+
+	return
+}
+
+// SSHKeyRotate replaces the SSH keypair ignite generated for the VM with a new one. The
+// new public key is authorized in the VM, and the old one is revoked, before the private
+// key used by "ignite ssh" is replaced.
+func SSHKeyRotate(so *SSHKeyOptions) error {
+	if so.vm.Spec.SSH == nil || !so.vm.Spec.SSH.Generate {
+		return fmt.Errorf("VM %q has no SSH keypair generated by ignite", so.vm.GetUID())
+	}
+
+	// The disk of a migrated or hibernated VM belongs to its saved state until it has been restored
+	if !so.vm.Running() && migration.Pending(so.vm) {
+		return fmt.Errorf("VM %q has a saved state from a migration or hibernation, start it before changing it", so.vm.GetUID())
+	}
+
+	privKeyPath := path.Join(so.vm.ObjectPath(), fmt.Sprintf(constants.VM_SSH_KEY_TEMPLATE, so.vm.GetUID()))
+	oldKey, err := ioutil.ReadFile(privKeyPath + ".pub")
+	if err != nil {
+		return fmt.Errorf("failed to read the public key of VM %q: %v", so.vm.GetUID(), err)
+	}
+
+	newPrivKeyPath := privKeyPath + ".new"
+	for _, file := range []string{newPrivKeyPath, newPrivKeyPath + ".pub"} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := dmlegacy.GenerateSSHKeypair(newPrivKeyPath); err != nil {
+		return fmt.Errorf("failed to generate a new SSH keypair: %v", err)
+	}
+
+	newKey, err := ioutil.ReadFile(newPrivKeyPath + ".pub")
+	if err != nil {
+		return err
+	}
+
+	update := func(content []byte) ([]byte, error) {
+		content = authorizedkeys.Remove(content, []string{string(oldKey)})
+		return authorizedkeys.Add(content, []string{string(bytes.TrimSpace(newKey))}), nil
+	}
+
+	if so.vm.Running() {
+		err = updateGuestAuthorizedKeys(so.vm, so.IdentityFile, so.Timeout, update)
+	} else {
+		err = operations.UpdateAuthorizedKeys(so.vm, update)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update the authorized keys of VM %q: %v", so.vm.GetUID(), err)
+	}
+
+	// Replace the old keypair, the private key last so it's never left without a public key
+	if err := os.Rename(newPrivKeyPath+".pub", privKeyPath+".pub"); err != nil {
+		return err
+	}
+
+	if err := os.Rename(newPrivKeyPath, privKeyPath); err != nil {
+		return err
+	}
+
+	log.Infof("Rotated the SSH keypair of VM %q", so.vm.GetUID())
+	return nil
+}
+
+// updateGuestAuthorizedKeys replaces the authorized_keys file of root in the running VM
+// with the result of update
+func updateGuestAuthorizedKeys(vm *api.VM, identityFile string, timeout uint32, update func(content []byte) ([]byte, error)) error {
+	content, err := runGuestScript(vm, identityFile, timeout, readAuthorizedKeysScript, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read the authorized keys of VM %q: %v", vm.GetUID(), err)
+	}
+
+	if content, err = update(content); err != nil {
+		return err
+	}
+
+	if _, err := runGuestScript(vm, identityFile, timeout, writeAuthorizedKeysScript, content); err != nil {
+		return fmt.Errorf("failed to write the authorized keys of VM %q: %v", vm.GetUID(), err)
+	}
+
+	return nil
+}
+
+// runGuestScript runs the shell script in the running VM with the given input, and
+// returns its output. The guest agent of the VM is preferred, SSH is used if the VM
+// has no agent listening.
+func runGuestScript(vm *api.VM, identityFile string, timeout uint32, script string, stdin []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	req := &protocol.ExecRequest{Command: []string{"sh", "-c", script}}
+	code, err := agent.Exec(vm, req, &agent.ExecStreams{
+		Stdin:  bytes.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+
+	if errors.Is(err, agent.ErrNoAgent) {
+		log.Debugf("Falling back to SSH: %v", err)
+		stdout.Reset()
+		stderr.Reset()
+		code, err = runSSHScript(vm, identityFile, timeout, script, bytes.NewReader(stdin), &stdout, &stderr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("command exited with code %d: %s", code, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// runSSHScript runs the shell script in the running VM over SSH, and returns its exit code
+func runSSHScript(vm *api.VM, privKeyFile string, timeout uint32, script string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	ipAddrs := vm.Status.Network.IPAddresses
+	if len(ipAddrs) == 0 {
+		return 0, fmt.Errorf("VM %q has no usable IP addresses", vm.GetUID())
+	}
+
+	if len(privKeyFile) == 0 {
+		privKeyFile = path.Join(vm.ObjectPath(), fmt.Sprintf(constants.VM_SSH_KEY_TEMPLATE, vm.GetUID()))
+		if !util.FileExists(privKeyFile) {
+			return 0, fmt.Errorf("VM %q has no guest agent, and no private key to use SSH", vm.GetUID())
+		}
+	}
+
+	signer, err := newSignerForKey(privKeyFile)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create signer for private key: %v", err)
+	}
+
+	client, err := ssh.Dial(defaultSSHNetwork, net.JoinHostPort(ipAddrs[0].String(), defaultSSHPort), newSSHConfig(signer, timeout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create session: %v", err)
+	}
+	defer session.Close()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+	if err := session.Run(script); err != nil {
+		if e, ok := err.(*ssh.ExitError); ok {
+			return e.ExitStatus(), nil
+		}
+
+		return 0, fmt.Errorf("failed to run shell command: %v", err)
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,63 @@
+package run
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+const (
+	testAliceKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJdbFdgbfD6sOsr5VGPWGbO1kgmnDfHU14rlcwNHBdDq alice@laptop"
+	testBobKey   = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFsYRIg18Qf7qrDhFyzoSC2q5C82gchbtIFIxm4+CQas bob@desk"
+)
+
+func TestAuthorizedKeysDiff(t *testing.T) {
+	cases := []struct {
+		name        string
+		current     *api.SSH
+		updated     *api.SSH
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{
+			name:      "keys added",
+			updated:   &api.SSH{AuthorizedKeys: []string{testAliceKey}},
+			wantAdded: []string{testAliceKey},
+		},
+		{
+			name:        "key replaced",
+			current:     &api.SSH{Generate: true, AuthorizedKeys: []string{testAliceKey}},
+			updated:     &api.SSH{Generate: true, AuthorizedKeys: []string{testBobKey}},
+			wantAdded:   []string{testBobKey},
+			wantRemoved: []string{testAliceKey},
+		},
+		{
+			name:    "comment changed",
+			current: &api.SSH{AuthorizedKeys: []string{testAliceKey}},
+			updated: &api.SSH{AuthorizedKeys: []string{testAliceKey + "-2"}},
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			current, updated := &api.VM{}, &api.VM{}
+			current.Spec.SSH = rt.current
+			updated.Spec.SSH = rt.updated
+
+			added, removed := authorizedKeysDiff(current, updated)
+			assert.DeepEqual(t, added, rt.wantAdded)
+			assert.DeepEqual(t, removed, rt.wantRemoved)
+		})
+	}
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	keys, err := parsePublicKeys(testAliceKey)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, keys, []string{testAliceKey})
+
+	_, err = parsePublicKeys("/nonexistent/id_ed25519.pub")
+	assert.ErrorContains(t, err, "neither a public key file nor a public key")
+}
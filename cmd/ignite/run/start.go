@@ -1,6 +1,7 @@
 package run
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"golang.org/x/crypto/ssh"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
 	"github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/config"
 	"github.com/weaveworks/ignite/pkg/constants"
@@ -24,6 +26,9 @@ type StartFlags struct {
 	Interactive            bool
 	Debug                  bool
 	IgnoredPreflightErrors []string
+	Selector               string
+	Parallel               int
+	Output                 string
 }
 
 type StartOptions struct {
@@ -32,7 +37,11 @@ type StartOptions struct {
 }
 
 func (sf *StartFlags) NewStartOptions(vmMatch string) (*StartOptions, error) {
-	ao, err := NewAttachOptions(vmMatch)
+	if err := checkStartOutput(sf); err != nil {
+		return nil, err
+	}
+
+	ao, err := (&AttachFlags{}).NewAttachOptions(vmMatch)
 	if err != nil {
 		return nil, err
 	}
@@ -43,42 +52,69 @@ func (sf *StartFlags) NewStartOptions(vmMatch string) (*StartOptions, error) {
 	return &StartOptions{sf, ao}, nil
 }
 
-func Start(so *StartOptions, fs *flag.FlagSet) error {
-	// Check if the given VM is already running
-	if so.vm.Running() {
-		return fmt.Errorf("VM %q is already running", so.vm.GetUID())
+// checkStartOutput checks the output format of the result of the started VM, which
+// isn't printed for a VM that's attached to
+func checkStartOutput(sf *StartFlags) error {
+	if sf.Interactive && len(sf.Output) > 0 {
+		return fmt.Errorf("cannot print the result of a VM that's attached to")
 	}
 
-	// Stopped VMs don't contain the runtime and network information. Set the
-	// default runtime and network from the providers if empty.
-	if so.vm.Status.Runtime.Name == "" {
-		so.vm.Status.Runtime.Name = providers.RuntimeName
-	}
-	if so.vm.Status.Network.Plugin == "" {
-		so.vm.Status.Network.Plugin = providers.NetworkPluginName
-	}
+	return SetResultOutput(sf.Output)
+}
+
+// setVMProviders sets the runtime and network-plugin of the VM in its status, and
+// populates the providers with them. The runtime and network-plugin of the VM's spec
+// are used if set, otherwise the ones it ran with. If the runtime and network-plugin
+// are specified explicitly as flags, they override the global config and the config
+// on the VM object.
+func setVMProviders(vm *ignite.VM, fs *flag.FlagSet) error {
+	resolveVMProviders(vm, fs)
+	return populateVMProviders(vm)
+}
 
-	// In case the runtime and network-plugin are specified explicitly at
-	// start, set the runtime and network-plugin on the VM. This overrides the
-	// global config and config on the VM object, if any.
+// resolveVMProviders sets the runtime and network-plugin of the VM in its status,
+// without populating the providers
+func resolveVMProviders(vm *ignite.VM, fs *flag.FlagSet) {
+	config.ResolveVMProviders(vm)
 	if fs.Changed("runtime") {
-		so.vm.Status.Runtime.Name = providers.RuntimeName
+		vm.Status.Runtime.Name = providers.RuntimeName
 	}
 	if fs.Changed("network-plugin") {
-		so.vm.Status.Network.Plugin = providers.NetworkPluginName
+		vm.Status.Network.Plugin = providers.NetworkPluginName
 	}
+}
+
+// populateVMProviders populates the providers with the runtime and network-plugin of the VM's status
+func populateVMProviders(vm *ignite.VM) error {
+	return config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin)
+}
 
-	// Set the runtime and network-plugin providers from the VM status.
-	if err := config.SetAndPopulateProviders(so.vm.Status.Runtime.Name, so.vm.Status.Network.Plugin); err != nil {
+func Start(so *StartOptions, fs *flag.FlagSet) error {
+	// Check if the given VM is already running
+	if so.vm.Running() {
+		return fmt.Errorf("VM %q is already running", so.vm.GetUID())
+	}
+
+	// Set the runtime and network-plugin providers for the VM
+	if err := setVMProviders(so.vm, fs); err != nil {
 		return err
 	}
 
+	// The boot-cache flag or the ignite configuration enable the boot cache
+	cmdutil.ResolveBootCache()
+	cmdutil.ResolveEmulation()
+
+	return start(so)
+}
+
+// start starts the VM of the StartOptions with the providers that are set
+func start(so *StartOptions) error {
 	ignoredPreflightErrors := sets.NewString(util.ToLower(so.StartFlags.IgnoredPreflightErrors)...)
 	if err := checkers.StartCmdChecks(so.vm, ignoredPreflightErrors); err != nil {
 		return err
 	}
 
-	if err := operations.StartVM(so.vm, so.Debug); err != nil {
+	if err := operations.StartVM(context.Background(), so.vm, so.Debug); err != nil {
 		return err
 	}
 
@@ -93,7 +129,49 @@ func Start(so *StartOptions, fs *flag.FlagSet) error {
 	if so.Interactive {
 		return Attach(so.AttachOptions)
 	}
-	return nil
+
+	return PrintResult(so.Output, so.vm)
+}
+
+// StartSelected starts the stopped VMs whose labels match the selector of the StartFlags,
+// up to the parallel count of the StartFlags at once
+func StartSelected(sf *StartFlags, fs *flag.FlagSet) error {
+	if sf.Interactive {
+		return fmt.Errorf("cannot attach to VMs selected by label")
+	}
+
+	if len(sf.Output) > 0 {
+		return fmt.Errorf("cannot print the result of VMs selected by label")
+	}
+
+	selected, err := getVMsForSelector(sf.Selector)
+	if err != nil {
+		return err
+	}
+
+	var vms []*ignite.VM
+	options := make(map[string]*StartOptions)
+	for _, vm := range selected {
+		if vm.Running() {
+			continue
+		}
+
+		so, err := sf.NewStartOptions(vm.GetUID().String())
+		if err != nil {
+			return err
+		}
+
+		// Resolve the providers of all the VMs first, they're started in groups sharing them
+		resolveVMProviders(so.vm, fs)
+		vms = append(vms, so.vm)
+		options[so.vm.GetUID().String()] = so
+	}
+
+	cmdutil.ResolveBootCache()
+	cmdutil.ResolveEmulation()
+	return forEachVM(vms, sf.Parallel, populateVMProviders, func(vm *ignite.VM) error {
+		return start(options[vm.GetUID().String()])
+	})
 }
 
 func dialSuccess(vm *ignite.VM, seconds int) error {
@@ -130,6 +208,12 @@ func waitForSSH(vm *ignite.VM, dialSeconds int, sshTimeout time.Duration) error
 		return err
 	}
 
+	return sshHandshake(vm, sshTimeout)
+}
+
+// sshHandshake connects to the SSH server of the VM without credentials, the server
+// answering with an authentication failure means it's up
+func sshHandshake(vm *ignite.VM, sshTimeout time.Duration) error {
 	certCheck := &ssh.CertChecker{
 		IsHostAuthority: func(auth ssh.PublicKey, address string) bool {
 			return true
@@ -0,0 +1,109 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/container"
+	"github.com/weaveworks/ignite/pkg/filter"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// clearScreen moves the cursor to the top left corner, and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// StatsFlags contains the flags supported by the stats command.
+type StatsFlags struct {
+	Watch bool
+}
+
+type StatsOptions struct {
+	*StatsFlags
+	vmMatches []string
+}
+
+// NewStatsOptions constructs and returns StatsOptions. Without VM matches,
+// the stats of all running VMs are shown.
+func (sf *StatsFlags) NewStatsOptions(vmMatches []string) (so *StatsOptions, err error) {
+	so = &StatsOptions{StatsFlags: sf, vmMatches: vmMatches}
+
+	// Resolve the matches once to fail early for unknown VMs
+	_, err = so.getVMs()
+	return
+}
+
+// getVMs returns the matched VMs, or all running VMs if none were matched
+func (so *StatsOptions) getVMs() ([]*api.VM, error) {
+	if len(so.vmMatches) > 0 {
+		return getVMsForMatches(so.vmMatches)
+	}
+
+	vms, err := providers.Client.VMs().FindAll(filter.NewVMFilterAll("", false))
+	if err != nil && os.IsNotExist(err) {
+		err = nil
+	}
+	return vms, err
+}
+
+// Stats shows the resource usage of the VMs, and refreshes it in watch mode
+func Stats(so *StatsOptions) error {
+	for {
+		vms, err := so.getVMs()
+		if err != nil {
+			return err
+		}
+
+		if so.Watch {
+			fmt.Print(clearScreen)
+		}
+
+		printStats(vms)
+		if !so.Watch {
+			return nil
+		}
+
+		time.Sleep(constants.VM_STATS_INTERVAL)
+	}
+}
+
+func printStats(vms []*api.VM) {
+	o := util.NewOutput()
+	defer o.Flush()
+
+	o.Write("VM ID", "NAME", "CPU %", "MEM USAGE", "GUEST MEM", "DISK READ / WRITE", "NET RX / TX")
+	for _, vm := range vms {
+		if !vm.Running() {
+			log.Warnf("VM %q is not running", vm.GetUID())
+			continue
+		}
+
+		stats, err := container.ReadStats(vm)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+
+		// The guest memory is only known if the VM has a balloon device
+		guestMemory := "-"
+		if stats.GuestMemoryTotal > 0 {
+			guestMemory = fmt.Sprintf("%s / %s", formatBytes(stats.GuestMemoryTotal-stats.GuestMemoryAvailable), formatBytes(stats.GuestMemoryTotal))
+		}
+
+		o.Write(vm.GetUID(), vm.GetName(),
+			fmt.Sprintf("%.2f%%", stats.CPUPercent),
+			fmt.Sprintf("%s / %s", formatBytes(stats.MemoryUsage), vm.Spec.Memory),
+			guestMemory,
+			fmt.Sprintf("%s / %s", formatBytes(stats.DiskReadBytes), formatBytes(stats.DiskWriteBytes)),
+			fmt.Sprintf("%s / %s", formatBytes(stats.NetRxBytes), formatBytes(stats.NetTxBytes)))
+	}
+}
+
+func formatBytes(bytes uint64) string {
+	return meta.NewSizeFromBytes(bytes).String()
+}
@@ -1,13 +1,18 @@
 package run
 
 import (
+	"context"
+	"github.com/weaveworks/ignite/pkg/agent"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/config"
 	"github.com/weaveworks/ignite/pkg/operations"
 )
 
 type StopFlags struct {
-	Kill bool
+	Kill     bool
+	Agent    bool
+	Selector string
+	Parallel int
 }
 
 type StopOptions struct {
@@ -17,22 +22,41 @@ type StopOptions struct {
 
 func (sf *StopFlags) NewStopOptions(vmMatches []string) (so *StopOptions, err error) {
 	so = &StopOptions{StopFlags: sf}
-	so.vms, err = getVMsForMatches(vmMatches)
+	so.vms, err = getVMsForMatchesOrSelector(vmMatches, sf.Selector)
 	return
 }
 
+// Stop stops the VMs of the StopOptions, up to the parallel count of the StopFlags at once
 func Stop(so *StopOptions) error {
+	var vms []*api.VM
 	for _, vm := range so.vms {
-		// Set the runtime and network-plugin providers from the VM status.
-		if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
-			return err
+		// The VMs selected by label include the stopped ones, skip them
+		if len(so.Selector) > 0 && !vm.Running() {
+			continue
 		}
 
-		// Stop the VM, and optionally kill it
-		if err := operations.StopVM(vm, so.Kill, false); err != nil {
-			return err
-		}
+		vms = append(vms, vm)
+	}
+
+	// Set the runtime and network-plugin providers from the VM status.
+	setProviders := func(vm *api.VM) error {
+		return config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin)
 	}
 
-	return nil
+	return forEachVM(vms, so.Parallel, setProviders, func(vm *api.VM) error {
+		// Let the guest agent shut the VM down, the container exits with it
+		if so.Agent && !so.Kill {
+			// The VM may stop before StopVM marks the stop as requested
+			if err := operations.MarkStopRequested(vm); err != nil {
+				return err
+			}
+
+			if err := agent.Shutdown(vm); err != nil {
+				return err
+			}
+		}
+
+		// Stop the VM, and optionally kill it
+		return operations.StopVM(context.Background(), vm, so.Kill, false)
+	})
 }
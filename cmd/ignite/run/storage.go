@@ -0,0 +1,75 @@
+package run
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/etcdstorage"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	storageprovider "github.com/weaveworks/ignite/pkg/providers/storage"
+	"github.com/weaveworks/ignite/pkg/storagebackend"
+)
+
+// Storage prints where the metadata is stored, or moves it to the given storage. The etcd
+// cluster is the one the metadata is moved to.
+func Storage(name string, etcd *etcdstorage.Config) error {
+	current := storagebackend.For(storageprovider.Backends, constants.DATA_DIR)
+	if len(name) == 0 {
+		fmt.Printf("%s (%s)\n", current.Name(), current.Location(constants.DATA_DIR))
+		return nil
+	}
+
+	backend, err := storagebackend.Get(storageprovider.Backends, name)
+	if err != nil {
+		return err
+	}
+
+	if backend.Name() == current.Name() {
+		return fmt.Errorf("the metadata is already stored in the %s storage", name)
+	}
+
+	// The metadata files are moved to a backend, and back
+	if backend != storagebackend.File && current != storagebackend.File {
+		return fmt.Errorf("the metadata is stored in the %s storage, move it to the %s storage first", current.Name(), storagebackend.File.Name())
+	}
+
+	if _, ok := backend.(*etcdstorage.Backend); ok {
+		if len(etcd.Endpoints) == 0 {
+			return fmt.Errorf("the etcd endpoints are required to move the metadata to etcd")
+		}
+
+		// The settings are stored, the files are read by every command
+		for _, file := range []*string{&etcd.CACert, &etcd.Cert, &etcd.Key} {
+			if len(*file) != 0 {
+				if *file, err = filepath.Abs(*file); err != nil {
+					return err
+				}
+			}
+		}
+		backend = &etcdstorage.Backend{Config: etcd}
+	}
+
+	// ignited would keep writing to the storage it started with
+	status, err := jobs.ReadStatus(path.Join(constants.DATA_DIR, constants.JOBS_FILE))
+	if err != nil {
+		return err
+	}
+	if status != nil {
+		return fmt.Errorf("ignited is running with PID %d, stop it before moving the metadata", status.PID)
+	}
+
+	var count int
+	if backend == storagebackend.File {
+		count, err = current.Export(constants.DATA_DIR)
+	} else {
+		count, err = backend.Import(constants.DATA_DIR)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Moved the metadata of %d objects to the %s storage\n", count, name)
+	return nil
+}
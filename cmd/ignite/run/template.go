@@ -0,0 +1,119 @@
+package run
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/filter"
+)
+
+type TemplatesOptions struct {
+	allTemplates []*api.VMTemplate
+}
+
+func NewTemplatesOptions() (to *TemplatesOptions, err error) {
+	to = &TemplatesOptions{}
+	to.allTemplates, err = providers.Client.VMTemplates().FindAll(filter.NewAllFilter())
+	// If the storage is uninitialized, avoid failure and continue with empty
+	// template list.
+	if err != nil && os.IsNotExist(err) {
+		err = nil
+	}
+	return
+}
+
+func Templates(to *TemplatesOptions) error {
+	o := util.NewOutput()
+	defer o.Flush()
+
+	o.Write("TEMPLATE ID", "NAME", "IMAGE", "KERNEL", "CPUS", "MEMORY", "SIZE", "CREATED")
+	for _, template := range to.allTemplates {
+		o.Write(template.GetUID(), template.GetName(), template.Spec.Image.OCI, template.Spec.Kernel.OCI,
+			template.Spec.CPUs, template.Spec.Memory, template.Spec.DiskSize, template.GetCreated())
+	}
+
+	return nil
+}
+
+type CreateTemplateOptions struct {
+	template *api.VMTemplate
+}
+
+// NewCreateTemplateOptions reads the VMTemplate to create from the given manifest file
+func NewCreateTemplateOptions(file string) (*CreateTemplateOptions, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	template := providers.Client.VMTemplates().New()
+	if err := scheme.Serializer.DecodeInto(b, template); err != nil {
+		return nil, err
+	}
+
+	// VMs reference templates by name, so it can't be generated
+	if len(template.GetName()) == 0 {
+		return nil, fmt.Errorf("the VM template in %q must set its name", file)
+	}
+
+	if err := validation.ValidateVMTemplate(template).ToAggregate(); err != nil {
+		return nil, err
+	}
+
+	return &CreateTemplateOptions{template}, nil
+}
+
+func CreateTemplate(co *CreateTemplateOptions) (err error) {
+	if err = metadata.SetNameAndUID(co.template, providers.Client); err != nil {
+		return
+	}
+	defer util.DeferErr(&err, func() error { return metadata.Cleanup(co.template, false) })
+
+	if err = providers.Client.VMTemplates().Set(co.template); err != nil {
+		return
+	}
+
+	err = metadata.Success(co.template)
+
+	return
+}
+
+type RmTemplateOptions struct {
+	templates []*api.VMTemplate
+}
+
+func NewRmTemplateOptions(templateMatches []string) (*RmTemplateOptions, error) {
+	ro := &RmTemplateOptions{}
+
+	for _, match := range templateMatches {
+		template, err := providers.Client.VMTemplates().Find(filter.NewIDNameFilter(match))
+		if err != nil {
+			return nil, err
+		}
+
+		ro.templates = append(ro.templates, template)
+	}
+
+	return ro, nil
+}
+
+// RmTemplate removes the given VM templates. VMs created from
+// a template have a copy of its spec, so they're not affected.
+func RmTemplate(ro *RmTemplateOptions) error {
+	for _, template := range ro.templates {
+		if err := providers.Client.VMTemplates().Delete(template.GetUID()); err != nil {
+			return fmt.Errorf("unable to remove %s %q: %v", template.GetKind(), template.GetUID(), err)
+		}
+
+		fmt.Println(template.GetUID())
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package run
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// WaitCondition is a state of a VM "ignite vm wait" can wait for
+type WaitCondition string
+
+const (
+	// WaitRunning waits for the VM to be running
+	WaitRunning WaitCondition = "running"
+	// WaitStopped waits for the VM to be stopped
+	WaitStopped WaitCondition = "stopped"
+	// WaitIP waits for the VM to be running with an IP address
+	WaitIP WaitCondition = "ip"
+	// WaitSSH waits for the SSH server within the VM to accept connections
+	WaitSSH WaitCondition = "ssh"
+)
+
+// ListWaitConditions gets the list of conditions wait supports
+func ListWaitConditions() []WaitCondition {
+	return []WaitCondition{
+		WaitRunning,
+		WaitStopped,
+		WaitIP,
+		WaitSSH,
+	}
+}
+
+// WaitFlags contains the flags supported by wait.
+type WaitFlags struct {
+	For     string
+	Timeout time.Duration
+}
+
+type WaitOptions struct {
+	*WaitFlags
+	vm *api.VM
+}
+
+func (wf *WaitFlags) NewWaitOptions(vmMatch string) (wo *WaitOptions, err error) {
+	if !validWaitCondition(WaitCondition(wf.For)) {
+		return nil, fmt.Errorf("unknown condition %q, supported conditions are %v", wf.For, ListWaitConditions())
+	}
+
+	wo = &WaitOptions{WaitFlags: wf}
+	wo.vm, err = getVMForMatch(vmMatch)
+	return
+}
+
+// Wait blocks until the VM reaches the condition, or fails once the timeout passes
+func Wait(wo *WaitOptions) error {
+	condition := WaitCondition(wo.For)
+	var deadline time.Time
+	if wo.Timeout > 0 {
+		deadline = time.Now().Add(wo.Timeout)
+	}
+
+	log.Debugf("Waiting for VM %q to be %s...", wo.vm.GetUID(), condition)
+	for {
+		// Reload the VM, its status is updated by ignite-spawn and other ignite processes
+		vm, err := providers.Client.VMs().Get(wo.vm.GetUID())
+		if err != nil {
+			return err
+		}
+
+		if conditionMet(vm, condition) {
+			return nil
+		}
+
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for VM %q to be %s", wo.Timeout, vm.GetUID(), condition)
+		}
+
+		time.Sleep(constants.VM_WAIT_INTERVAL)
+	}
+}
+
+func validWaitCondition(condition WaitCondition) bool {
+	for _, c := range ListWaitConditions() {
+		if c == condition {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasIPAddress reports if an IP address has been assigned to the VM, its network status is unset until it's started
+func hasIPAddress(vm *api.VM) bool {
+	return vm.Status.Network != nil && len(vm.Status.Network.IPAddresses) > 0
+}
+
+// conditionMet checks the condition against the current state of the VM
+func conditionMet(vm *api.VM, condition WaitCondition) bool {
+	switch condition {
+	case WaitRunning:
+		return vm.Running()
+	case WaitStopped:
+		return !vm.Running()
+	case WaitIP:
+		return vm.Running() && hasIPAddress(vm)
+	case WaitSSH:
+		if !vm.Running() || !hasIPAddress(vm) {
+			return false
+		}
+
+		conn, err := net.DialTimeout("tcp", vm.Status.Network.IPAddresses[0].String()+":22", constants.VM_WAIT_INTERVAL)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+
+		return sshHandshake(vm, constants.SSH_DEFAULT_TIMEOUT_SECONDS*time.Second) == nil
+	}
+
+	return false
+}
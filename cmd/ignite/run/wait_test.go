@@ -0,0 +1,49 @@
+package run
+
+import (
+	"net"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+func TestConditionMet(t *testing.T) {
+	stopped := &api.VM{}
+	running := &api.VM{}
+	running.Status.Running = true
+	withIP := running.DeepCopy()
+	withIP.Status.Network = &api.Network{
+		IPAddresses: meta.IPAddresses{net.ParseIP("10.61.0.2")},
+	}
+
+	cases := []struct {
+		name      string
+		vm        *api.VM
+		condition WaitCondition
+		want      bool
+	}{
+		{"running VM is running", running, WaitRunning, true},
+		{"stopped VM isn't running", stopped, WaitRunning, false},
+		{"stopped VM is stopped", stopped, WaitStopped, true},
+		{"running VM isn't stopped", running, WaitStopped, false},
+		{"running VM without IP", running, WaitIP, false},
+		{"running VM with IP", withIP, WaitIP, true},
+		{"stopped VM has no SSH", stopped, WaitSSH, false},
+		{"unknown condition", running, WaitCondition("paused"), false},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.Equal(t, conditionMet(rt.vm, rt.condition), rt.want)
+		})
+	}
+}
+
+func TestNewWaitOptionsCondition(t *testing.T) {
+	wf := &WaitFlags{For: "paused"}
+	_, err := wf.NewWaitOptions("my-vm")
+	assert.ErrorContains(t, err, "unknown condition")
+}
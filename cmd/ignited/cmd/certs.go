@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/pki"
+)
+
+// NewCmdCerts manages the certificates securing the management API on TCP
+func NewCmdCerts(out io.Writer) *cobra.Command {
+	pkiDir := constants.DAEMON_PKI_DIR
+
+	cmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Manage the certificates of the management API",
+		Long: dedent.Dedent(`
+			Manage the CA and certificates securing the management API of "ignited daemon"
+			when it's served on TCP with --api-address. The API requires mutual TLS: it's
+			served with a certificate issued by the CA, and the clients need to present a
+			certificate issued by it too. Create the CA with "ignited certs init", and
+			issue a certificate for every client with "ignited certs issue".
+		`),
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&pkiDir, "pki-dir", pkiDir, "Directory of the CA and server certificate")
+	cmd.AddCommand(newCmdCertsInit(out, &pkiDir))
+	cmd.AddCommand(newCmdCertsIssue(out, &pkiDir))
+	return cmd
+}
+
+func newCmdCertsInit(out io.Writer, pkiDir *string) *cobra.Command {
+	var hosts []string
+	force := false
+	keepCA := false
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create the CA and the server certificate of the management API",
+		Long: dedent.Dedent(`
+			Create the CA of the management API, and the certificate the API is served
+			with. The server certificate is valid for the host name of the machine,
+			localhost and the loopback addresses, add the names and addresses clients
+			reach the API on with --host. An existing CA is only replaced with --force,
+			which invalidates the client certificates issued by it. With --keep-ca, only
+			the server certificate is issued, with the CA in --pki-dir. Copy the CA to
+			several hosts this way to accept the same client certificates on all of them.
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				allHosts := append([]string{"localhost", "127.0.0.1", "::1"}, hosts...)
+				if hostname, err := os.Hostname(); err == nil {
+					allHosts = append(allHosts, hostname)
+				}
+
+				if keepCA {
+					if err := pki.IssueServer(*pkiDir, allHosts); err != nil {
+						return err
+					}
+
+					fmt.Fprintf(out, "Issued the server certificate in %s with the existing CA\n", *pkiDir)
+					return nil
+				}
+
+				if err := pki.Init(*pkiDir, allHosts, force); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(out, "Created the CA and server certificate in %s\n", *pkiDir)
+				return nil
+			}())
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&hosts, "host", hosts, "Additional host names and IP addresses the server certificate is valid for")
+	cmd.Flags().BoolVar(&force, "force", force, "Replace an existing CA")
+	cmd.Flags().BoolVar(&keepCA, "keep-ca", keepCA, "Only issue the server certificate, with the existing CA")
+	return cmd
+}
+
+func newCmdCertsIssue(out io.Writer, pkiDir *string) *cobra.Command {
+	outDir := "."
+
+	cmd := &cobra.Command{
+		Use:   "issue <name>",
+		Short: "Issue a client certificate for the management API",
+		Long: dedent.Dedent(`
+			Issue a client certificate for the management API, signed by the CA. The
+			certificate, its key and the CA certificate are written to <name>.crt,
+			<name>.key and ca.crt in the output directory. The name is recorded as the
+			actor of the requests of the client in the audit log.
+		`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() error {
+				if err := pki.Issue(*pkiDir, outDir, args[0]); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(out, "Issued the client certificate %s\n", pki.CertPath(outDir, args[0]))
+				return nil
+			}())
+		},
+	}
+
+	cmd.Flags().StringVarP(&outDir, "output-dir", "o", outDir, "Directory to write the client certificate and key to")
+	return cmd
+}
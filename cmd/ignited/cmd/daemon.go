@@ -5,19 +5,64 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"path"
 	"sync"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/apiserver"
+	"github.com/weaveworks/ignite/pkg/autostart"
+	"github.com/weaveworks/ignite/pkg/backup"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/cri"
+	"github.com/weaveworks/ignite/pkg/events"
+	"github.com/weaveworks/ignite/pkg/health"
+	"github.com/weaveworks/ignite/pkg/operations"
 	"github.com/weaveworks/ignite/pkg/operations/reconcile"
+	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/providers/manifeststorage"
+	"github.com/weaveworks/ignite/pkg/restart"
+	"github.com/weaveworks/ignite/pkg/vault"
 )
 
 func NewCmdDaemon(out io.Writer) *cobra.Command {
+	autostartVMs := true
+	apiSocket := path.Join(constants.DATA_DIR, constants.DAEMON_API_SOCKET)
+	apiAddress := ""
+	pkiDir := constants.DAEMON_PKI_DIR
+	metricsAddress := ""
+	criSocket := ""
+	criSandboxImage := "weaveworks/ignite-ubuntu:latest"
+	jobConcurrency := map[string]int{}
+
 	cmd := &cobra.Command{
 		Use:   "daemon",
 		Short: "Operates in daemon mode and watches /etc/firecracker/manifests for VM specifications to run.", // TODO: Parameterize
 		Run: func(cmd *cobra.Command, args []string) {
+			// Resolve the configuration used for importing images
+			cmdutil.ResolveRegistryConfigDir()
+			cmdutil.ResolveImageScan()
+			cmdutil.ResolveSBOM()
+			cmdutil.ResolveImageImport()
+
+			// Restore the VMs started by ignited from the boot cache if it's enabled
+			cmdutil.ResolveBootCache()
+
+			// Emulate the VMs started by ignited with QEMU as configured, e.g. on hosts without KVM
+			cmdutil.ResolveEmulation()
+
+			// Don't hold up the reconciliation and API requests while files of lazily imported images load
+			operations.LoadFilesInBackground = true
+
+			// Limit how many operations of the reconciliation and API requests run at once
+			closeJobs, err := startJobQueue(jobConcurrency)
+			if err != nil {
+				log.Fatalf("Failed to set up the job queue: %v", err)
+			}
+			defer closeJobs()
+
 			// Wait for Ctrl + C
 			var endWaiter sync.WaitGroup
 			endWaiter.Add(1)
@@ -27,11 +72,114 @@ func NewCmdDaemon(out io.Writer) *cobra.Command {
 
 			ms := manifeststorage.ManifestStorage
 
+			// Watch for events first, so the autostarted VMs are reported
+			watcher, err := events.NewWatcher(path.Join(constants.DATA_DIR, constants.EVENT_LOG))
+			if err != nil {
+				log.Fatalf("Failed to set up the events: %v", err)
+			}
+
+			go func() {
+				log.Infof("Starting event watcher...")
+				watcher.Run()
+			}()
+
+			// Bring the autostart VMs back up before handling any manifest changes
+			if autostartVMs {
+				log.Infof("Starting the VMs marked for autostart...")
+				if err := autostart.StartAll(); err != nil {
+					log.Errorf("Failed to autostart VMs: %v", err)
+				}
+			}
+
+			if len(metricsAddress) > 0 {
+				if err := reconcile.ServeMetrics(metricsAddress); err != nil {
+					log.Fatalf("Failed to listen on the metrics address: %v", err)
+				}
+			}
+
 			go func() {
 				log.Infof("Starting reconciliation loop...")
 				reconcile.ReconcileManifests(ms)
 			}()
 
+			go func() {
+				log.Infof("Starting backup scheduler...")
+				backup.NewScheduler().Run()
+			}()
+
+			go func() {
+				log.Infof("Starting restart policy watcher...")
+				restart.NewRestarter().Run()
+			}()
+
+			go func() {
+				log.Infof("Starting exit handler...")
+				operations.HandleExits()
+			}()
+
+			go func() {
+				log.Infof("Starting liveness prober...")
+				health.NewProber().Run()
+			}()
+
+			// Keep the Vault token and the leases of the secrets delivered to the VMs alive
+			if vaultClient, err := vault.Default(); err == nil {
+				go func() {
+					log.Infof("Starting Vault lease renewer...")
+					vault.NewRenewer(vaultClient).Run()
+				}()
+			} else if err != vault.ErrNotConfigured {
+				log.Errorf("Failed to set up Vault: %v", err)
+			}
+
+			server := apiserver.New(providers.Client)
+			if len(apiSocket) > 0 {
+				l, err := apiserver.ListenUnix(apiSocket)
+				if err != nil {
+					log.Fatalf("Failed to listen on the API socket: %v", err)
+				}
+				defer os.Remove(apiSocket)
+
+				go func() {
+					if err := server.Serve(l); err != nil {
+						log.Errorf("API server on %s failed: %v", apiSocket, err)
+					}
+				}()
+			}
+
+			if len(apiAddress) > 0 {
+				l, err := apiserver.ListenTLS(apiAddress, pkiDir)
+				if err != nil {
+					log.Fatalf("Failed to listen on the API address: %v", err)
+				}
+
+				go func() {
+					if err := server.Serve(l); err != nil {
+						log.Errorf("API server on %s failed: %v", apiAddress, err)
+					}
+				}()
+			}
+
+			if len(criSocket) > 0 {
+				sandboxImage, err := meta.NewOCIImageRef(criSandboxImage)
+				if err != nil {
+					log.Fatalf("Invalid CRI sandbox image: %v", err)
+				}
+
+				l, err := apiserver.ListenUnix(criSocket)
+				if err != nil {
+					log.Fatalf("Failed to listen on the CRI socket: %v", err)
+				}
+				defer os.Remove(criSocket)
+
+				criServer := cri.New(providers.Client, server.LifecycleLock(), cri.Options{SandboxImage: sandboxImage})
+				go func() {
+					if err := criServer.Serve(l); err != nil {
+						log.Errorf("CRI server on %s failed: %v", criSocket, err)
+					}
+				}()
+			}
+
 			go func() {
 				<-signalChannel
 				endWaiter.Done()
@@ -45,5 +193,13 @@ func NewCmdDaemon(out io.Writer) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&autostartVMs, "autostart", autostartVMs, "Start the VMs marked for autostart when the daemon starts")
+	cmd.Flags().StringVar(&apiSocket, "api-socket", apiSocket, "Unix socket to serve the management API on, set to an empty string to disable it")
+	cmd.Flags().StringVar(&apiAddress, "api-address", apiAddress, "TCP address (e.g. :7070) to also serve the management API on, with mutual TLS. See \"ignited certs\"")
+	cmd.Flags().StringVar(&pkiDir, "pki-dir", pkiDir, "Directory of the CA and server certificate of the management API on TCP")
+	cmd.Flags().StringVar(&metricsAddress, "metrics-address", metricsAddress, "TCP address (e.g. :9090) to serve the Prometheus metrics on at /metrics, besides the daemon socket")
+	cmd.Flags().StringVar(&criSocket, "cri-socket", criSocket, fmt.Sprintf("Unix socket to serve the Kubernetes CRI on for a kubelet to run pods in VMs (e.g. %s), disabled if empty", path.Join(constants.DATA_DIR, constants.DAEMON_CRI_SOCKET)))
+	cmd.Flags().StringVar(&criSandboxImage, "cri-sandbox-image", criSandboxImage, "Image of the VMs of the pod sandboxes created over the CRI, imported with the guest agent if needed")
+	addJobConcurrencyFlag(cmd.Flags(), &jobConcurrency)
 	return cmd
 }
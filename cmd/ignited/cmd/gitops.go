@@ -1,8 +1,9 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
-	"io/ioutil"
+	"os"
 	"time"
 
 	"github.com/lithammer/dedent"
@@ -10,7 +11,11 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/gitops"
+	"github.com/weaveworks/ignite/pkg/operations/reconcile"
+	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/util"
 	"github.com/weaveworks/libgitops/pkg/gitdir"
 )
@@ -18,9 +23,16 @@ import (
 const defaultKnownHostsPath = "~/.ssh/known_hosts"
 
 type gitOpsFlags struct {
-	branch   string
-	interval time.Duration
-	timeout  time.Duration
+	branch        string
+	interval      time.Duration
+	timeout       time.Duration
+	driftInterval time.Duration
+	writeStatus   bool
+	prune         string
+	pruneGrace    time.Duration
+	webhook       string
+	metrics       string
+	concurrency   map[string]int
 
 	identityFile string
 	hostsFile    string
@@ -31,9 +43,12 @@ type gitOpsFlags struct {
 // NewCmdGitOps runs the GitOps functionality of Ignite
 func NewCmdGitOps(out io.Writer) *cobra.Command {
 	f := &gitOpsFlags{
-		branch:   "master",
-		interval: 30 * time.Second,
-		timeout:  1 * time.Minute,
+		branch:        "master",
+		interval:      30 * time.Second,
+		timeout:       1 * time.Minute,
+		driftInterval: 5 * time.Minute,
+		prune:         string(api.PrunePolicyDelete),
+		concurrency:   map[string]int{},
 
 		identityFile: "",
 		hostsFile:    defaultKnownHostsPath,
@@ -46,49 +61,79 @@ func NewCmdGitOps(out io.Writer) *cobra.Command {
 		Use:   "gitops <repo-url>",
 		Short: "Run the GitOps feature of Ignite",
 		Long: dedent.Dedent(`
-			Run Ignite in GitOps mode watching the given repository. The repository needs
-			to be publicly cloneable. Ignite will watch for changes in the master branch
+			Run Ignite in GitOps mode watching the given repository. Private repositories
+			are cloned over SSH with a deploy key given by the identity file flag
+			(--identity-file), the host key of the remote is verified against the
+			known_hosts file (--hosts-file). An encrypted deploy key is decrypted with the
+			passphrase in the IGNITED_GITOPS_SSH_PASSPHRASE environment variable.
+			Without an identity file, the repository needs to be publicly cloneable.
+
+			Ignite will watch for changes in the master branch
 			by default, overridable with the branch flag (-b, --branch). If any new/changed
 			VM specification files are found in the repo (in JSON/YAML format), their
-			configuration will automatically be declaratively applied.
+			configuration will automatically be declaratively applied. The VMs are
+			also compared to their manifests at the drift interval (--drift-interval),
+			and corrected if they drifted from them, e.g. started again if they stopped.
+			With --write-status, the status of the VMs is written back to their manifests
+			and pushed, so the repository shows whether they run.
+
+			To sync right away when changes are pushed, serve a webhook for GitHub or
+			GitLab on the webhook address (--webhook-address), e.g. ":9292". Its requests
+			are validated with the secret in the IGNITED_GITOPS_WEBHOOK_SECRET environment
+			variable, and pull the repository without waiting for the sync interval.
+
+			Instead of a repository, the manifests can be pulled from an OCI artifact,
+			e.g. oci://registry.example.com/manifests:production as pushed by
+			"flux push artifact", with the credentials of the registry configuration
+			(--registry-config-dir). The artifact is pulled again at the sync interval.
+
+			VMs whose manifests are removed are pruned according to the prune policy
+			(--prune): "delete" removes them, "stop" stops them and "orphan" keeps them
+			running, the VMs kept are flagged with the ignite.weave.works/pruned
+			annotation. They're pruned after the grace period (--prune-grace-period),
+			unless their manifests come back meanwhile, e.g. when they're moved. VMs
+			annotated with ignite.weave.works/prune=disabled are never pruned.
+
+			Several branches or directories of the repository can be synced as separate
+			environments, e.g. staging and production, each with its own sync policy.
+			They're set in the gitops section of the ignite configuration (--ignite-config),
+			the branch, interval, drift interval and prune flags are the defaults of the environments.
 
 			To quit GitOps mode, use (Ctrl + C).
 		`),
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.ResolveRegistryConfigDir()
+			cmdutil.ResolveImageScan()
+			cmdutil.ResolveSBOM()
+			cmdutil.ResolveImageImport()
 			opts := gitdir.GitDirectoryOptions{
 				Branch:   f.branch,
 				Interval: f.interval,
 				Timeout:  f.timeout,
 			}
-			if f.identityFile != "" {
-				var err error
-				// support ~ prefixes in the path
-				f.identityFile, err = homedir.Expand(f.identityFile)
-				log.Tracef("Parsed identity file path: %s", f.identityFile)
-				util.GenericCheckErr(err)
-
-				opts.IdentityFileContent, err = ioutil.ReadFile(f.identityFile)
-				util.GenericCheckErr(err)
+			util.GenericCheckErr(f.setAuth(args[0], &opts))
+			if len(f.metrics) > 0 {
+				util.GenericCheckErr(reconcile.ServeMetrics(f.metrics))
 			}
-			if f.hostsFile != "" {
-				var err error
-				// support ~ prefixes in the path
-				f.hostsFile, err = homedir.Expand(f.hostsFile)
-				log.Tracef("Parsed_known hosts file path: %s", f.hostsFile)
-				util.GenericCheckErr(err)
-
-				opts.KnownHostsFileContent, err = ioutil.ReadFile(f.hostsFile)
-				util.GenericCheckErr(err)
-			}
-			if f.username != "" {
-				opts.Username = &f.username
-			}
-			if f.password != "" {
-				opts.Password = &f.password
+
+			closeJobs, err := startJobQueue(f.concurrency)
+			util.GenericCheckErr(err)
+			defer closeJobs()
+
+			var environments []api.GitOpsEnvironment
+			if providers.ComponentConfig != nil {
+				environments = providers.ComponentConfig.Spec.GitOps.Environments
 			}
 
-			util.GenericCheckErr(gitops.RunGitOps(args[0], opts))
+			util.GenericCheckErr(gitops.RunGitOps(args[0], opts, environments, gitops.ReconcileOptions{
+				DriftInterval:    f.driftInterval,
+				WriteStatus:      f.writeStatus,
+				Prune:            api.PrunePolicy(f.prune),
+				PruneGracePeriod: f.pruneGrace,
+				WebhookAddress:   f.webhook,
+				WebhookSecret:    []byte(os.Getenv(gitops.WebhookSecretEnv)),
+			}))
 		},
 	}
 
@@ -96,13 +141,70 @@ func NewCmdGitOps(out io.Writer) *cobra.Command {
 	return cmd
 }
 
+// setAuth sets the credentials for the repository. The SSH identity is only used for SSH URLs,
+// their host keys are verified against the known_hosts file.
+func (f *gitOpsFlags) setAuth(url string, opts *gitdir.GitDirectoryOptions) error {
+	if f.username != "" {
+		opts.Username = &f.username
+	}
+	if f.password != "" {
+		opts.Password = &f.password
+	}
+
+	if !gitops.IsSSHURL(url) {
+		if f.identityFile != "" {
+			return fmt.Errorf("the identity file is only used for SSH repository URLs, like git@github.com:user/repo.git")
+		}
+
+		return nil
+	}
+
+	if f.identityFile == "" {
+		log.Warnf("No identity file given, the repository is cloned without authentication and its VM status isn't pushed")
+		return nil
+	}
+
+	// support ~ prefixes in the paths
+	identityFile, err := homedir.Expand(f.identityFile)
+	if err != nil {
+		return err
+	}
+	log.Tracef("Parsed identity file path: %s", identityFile)
+
+	if opts.IdentityFileContent, err = gitops.ReadIdentity(identityFile, []byte(os.Getenv(gitops.IdentityPassphraseEnv))); err != nil {
+		return err
+	}
+
+	if f.hostsFile == "" {
+		return fmt.Errorf("a known_hosts file is needed to verify the host key of the repository")
+	}
+
+	hostsFile, err := homedir.Expand(f.hostsFile)
+	if err != nil {
+		return err
+	}
+	log.Tracef("Parsed known hosts file path: %s", hostsFile)
+
+	opts.KnownHostsFileContent, err = gitops.ReadKnownHosts(hostsFile)
+	return err
+}
+
 func addGitOpsFlags(fs *pflag.FlagSet, f *gitOpsFlags) {
 	fs.StringVarP(&f.branch, "branch", "b", f.branch, "What branch to sync")
 	fs.DurationVar(&f.interval, "interval", f.interval, "Sync interval for pushing to and pulling from the remote")
 	fs.DurationVar(&f.timeout, "timeout", f.timeout, "Git operation (clone, push, pull) timeout")
-
-	fs.StringVar(&f.identityFile, "identity-file", f.identityFile, "What SSH identity file to use for pushing")
-	fs.StringVar(&f.hostsFile, "hosts-file", f.hostsFile, "What known_hosts file to use for remote verification")
+	fs.DurationVar(&f.driftInterval, "drift-interval", f.driftInterval, "How often to correct the VMs that drifted from their manifests, 0 disables drift detection")
+	fs.BoolVar(&f.writeStatus, "write-status", f.writeStatus, "Write the status of the VMs back to their manifests at the sync interval, so the repository shows whether they run")
+
+	fs.StringVar(&f.prune, "prune", f.prune, "What to do with the VMs whose manifests are removed: delete, stop or orphan")
+	fs.DurationVar(&f.pruneGrace, "prune-grace-period", f.pruneGrace, "How long to keep the VMs whose manifests are removed before pruning them, in case the manifests come back")
+	fs.StringVar(&f.webhook, "webhook-address", f.webhook, fmt.Sprintf("What address to serve the webhook triggering an immediate sync on, e.g. :9292, its requests are validated with the secret in $%s", gitops.WebhookSecretEnv))
+	fs.StringVar(&f.metrics, "metrics-address", f.metrics, "TCP address (e.g. :9090) to serve the Prometheus metrics on at /metrics, besides the daemon socket")
+	addJobConcurrencyFlag(fs, &f.concurrency)
+	cmdutil.AddRegistryConfigDirFlag(fs, &providers.RegistryConfigDir)
+
+	fs.StringVar(&f.identityFile, "identity-file", f.identityFile, fmt.Sprintf("What SSH identity file (deploy key) to use for cloning and pushing, an encrypted key is decrypted with the passphrase in $%s", gitops.IdentityPassphraseEnv))
+	fs.StringVar(&f.hostsFile, "hosts-file", f.hostsFile, "What known_hosts file to verify the SSH host key of the remote with")
 	fs.StringVar(&f.username, "https-username", f.username, "What username to use when authenticating with Git over HTTPS")
 	fs.StringVar(&f.password, "https-password", f.password, "What password/access token to use when authenticating with Git over HTTPS")
 
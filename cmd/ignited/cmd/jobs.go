@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/jobs"
+)
+
+// startJobQueue schedules the operations of ignited on a job queue with the given
+// concurrency limits per class, and returns a function closing it
+func startJobQueue(concurrency map[string]int) (func(), error) {
+	limits, err := jobs.ParseLimits(concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs.Default = jobs.NewQueue(limits, path.Join(constants.DATA_DIR, constants.JOBS_FILE))
+	log.Infof("Running jobs with the concurrency limits %s", jobs.FormatLimits(jobs.Default.Status().Limits))
+
+	return func() {
+		if err := jobs.Default.Close(); err != nil {
+			log.Warnf("Failed to remove the jobs file: %v", err)
+		}
+	}, nil
+}
+
+func addJobConcurrencyFlag(fs *pflag.FlagSet, concurrency *map[string]int) {
+	fs.StringToIntVar(concurrency, "job-concurrency", *concurrency, "How many jobs of a class run at once, e.g. import=2,start=8. The classes are import, start, stop, remove and reconcile, see \"ignite jobs\"")
+}
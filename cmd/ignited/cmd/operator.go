@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lithammer/dedent"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/ignite/cmd/ignite/cmd/cmdutil"
+	"github.com/weaveworks/ignite/pkg/operator"
+	"github.com/weaveworks/ignite/pkg/pki"
+)
+
+// NewCmdOperator runs the Kubernetes operator managing VMs through custom resources
+func NewCmdOperator(out io.Writer) *cobra.Command {
+	opts := operator.Options{
+		NodeSelector: operator.DefaultNodeSelector,
+		APIPort:      operator.DefaultAPIPort,
+		Interval:     10 * time.Second,
+	}
+	certDir := "/etc/ignite/operator"
+	clientName := "ignite-operator"
+
+	cmd := &cobra.Command{
+		Use:   "operator",
+		Short: "Manage VMs, images and kernels on Kubernetes nodes through custom resources",
+		Long: dedent.Dedent(`
+			Run a Kubernetes operator that manages ignite VMs, images and kernels through the
+			VM, Image and Kernel custom resources of the cluster it runs in. VM resources are
+			assigned to the nodes matching --node-selector, whose "ignited daemon" creates,
+			starts and stops their VMs. The images and kernels are imported on all of them.
+
+			The operator reaches ignited through its API on TCP with mutual TLS, using the
+			client certificate issued for it by "ignited certs issue" in --cert-dir.
+		`),
+		Args: cobra.NoArgs,
+		// The operator runs in a pod, it doesn't need the providers of the host
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			applyLogFlags()
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(func() (err error) {
+				if opts.TLSConfig, err = pki.ClientConfig(certDir, clientName); err != nil {
+					return
+				}
+
+				o, err := operator.New(opts)
+				if err != nil {
+					return
+				}
+
+				ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer cancel()
+
+				log.Infof("Reconciling the ignite resources on the nodes matching %q every %s...", opts.NodeSelector, opts.Interval)
+				o.Run(ctx)
+				return nil
+			}())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.NodeSelector, "node-selector", opts.NodeSelector, "Label selector of the nodes running ignited daemon")
+	cmd.Flags().IntVar(&opts.APIPort, "api-port", opts.APIPort, "Port of the ignited API of the nodes, unless they're annotated with its address")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", opts.Interval, "How often the resources are reconciled")
+	cmd.Flags().StringVar(&certDir, "cert-dir", certDir, "Directory of the client certificate and the CA certificate of the ignited API")
+	cmd.Flags().StringVar(&clientName, "client-name", clientName, "Name of the client certificate in --cert-dir")
+	return cmd
+}
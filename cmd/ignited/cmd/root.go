@@ -17,11 +17,14 @@ import (
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/providers/ignite"
 	runtimeflag "github.com/weaveworks/ignite/pkg/runtime/flag"
+	snapshotterflag "github.com/weaveworks/ignite/pkg/snapshotter/flag"
 	versioncmd "github.com/weaveworks/ignite/pkg/version/cmd"
 )
 
 var logLevel = log.InfoLevel
 
+var logFormat = logs.FormatText
+
 // Ignite config file path flag variable.
 var configPath string
 
@@ -31,8 +34,7 @@ func NewIgnitedCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 		Use:   "ignited",
 		Short: "ignited: run Firecracker VMs declaratively through a manifest directory or Git",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			// Set the desired logging level, now that the flags are parsed
-			logs.Logger.SetLevel(logLevel)
+			applyLogFlags()
 
 			if err := config.ApplyConfiguration(configPath); err != nil {
 				log.Fatal(err)
@@ -56,14 +58,26 @@ func NewIgnitedCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 	root.AddCommand(NewCmdCompletion(os.Stdout, root))
 	root.AddCommand(NewCmdGitOps(os.Stdout))
 	root.AddCommand(NewCmdDaemon(os.Stdout))
+	root.AddCommand(NewCmdCerts(os.Stdout))
+	root.AddCommand(NewCmdOperator(os.Stdout))
 	root.AddCommand(versioncmd.NewCmdVersion(os.Stdout))
 	return root
 }
 
+// applyLogFlags sets the desired logging level and format, once the flags are parsed
+func applyLogFlags() {
+	logs.Logger.SetLevel(logLevel)
+	if err := logs.SetFormat(logFormat); err != nil {
+		log.Fatal(err)
+	}
+}
+
 func addGlobalFlags(fs *pflag.FlagSet) {
 	logflag.LogLevelFlagVar(fs, &logLevel)
+	logflag.LogFormatFlagVar(fs, &logFormat)
 	runtimeflag.RuntimeVar(fs, &providers.RuntimeName)
 	networkflag.NetworkPluginVar(fs, &providers.NetworkPluginName)
+	snapshotterflag.SnapshotterVar(fs, &providers.SnapshotterName)
 	cmdutil.AddIDPrefixFlag(fs, &providers.IDPrefix)
 	fs.StringVar(&configPath, "ignite-config", "", "Ignite configuration path; refer to the 'Ignite Configuration' docs for more details")
 }
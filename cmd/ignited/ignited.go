@@ -11,9 +11,13 @@ import (
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/providers/ignited"
+	"github.com/weaveworks/ignite/pkg/tracing"
 	"github.com/weaveworks/ignite/pkg/util"
 )
 
+// flushSpans exports the pending spans before exiting
+var flushSpans = func() {}
+
 func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -33,6 +37,7 @@ func main() {
 }
 
 func cleanup() {
+	flushSpans()
 
 	var daemonSocket = path.Join(constants.DATA_DIR, constants.DAEMON_SOCKET)
 
@@ -55,6 +60,12 @@ func Run() error {
 	// Preload necessary providers
 	util.GenericCheckErr(providers.Populate(ignited.Preload))
 
+	// Export the spans of the operations and reconciliation, if configured
+	flush, err := tracing.Setup("ignited")
+	util.GenericCheckErr(err)
+	flushSpans = flush
+	defer flush()
+
 	c := cmd.NewIgnitedCommand(os.Stdin, os.Stdout, os.Stderr)
 	return c.Execute()
 }
@@ -132,6 +132,37 @@ spec:
 			args:             []string{"--size=1GB", "--cpus=1"},
 			wantVMProperties: fmt.Sprintf("'1024.0 MB 1 1024.0 MB weaveworks/ignite-ubuntu:latest weaveworks/ignite:dev weaveworks/ignite-kernel:%s <nil>'", constants.DEFAULT_KERNEL_IMAGE_TAG),
 		},
+		{
+			name: "minimal valid v1alpha5 config",
+			config: []byte(`---
+apiVersion: ignite.weave.works/v1alpha5
+kind: Configuration
+`),
+			wantVMProperties: fmt.Sprintf("'512.0 MB 1 4.0 GB weaveworks/ignite-ubuntu:latest weaveworks/ignite:dev weaveworks/ignite-kernel:%s <nil>'", constants.DEFAULT_KERNEL_IMAGE_TAG),
+		},
+		{
+			name: "v1alpha5 vm config",
+			config: []byte(`---
+apiVersion: ignite.weave.works/v1alpha5
+kind: Configuration
+metadata:
+  name: test-config
+spec:
+  vmDefaults:
+    memory: "2GB"
+    diskSize: "3GB"
+    cpus: 2
+`),
+			vmConfig: []byte(`
+apiVersion: ignite.weave.works/v1alpha5
+kind: VM
+spec:
+  memory: "1GB"
+  diskSize: "2GB"
+  cpus: 1
+`),
+			wantVMProperties: fmt.Sprintf("'1024.0 MB 1 2.0 GB weaveworks/ignite-ubuntu:latest weaveworks/ignite:dev weaveworks/ignite-kernel:%s <nil>'", constants.DEFAULT_KERNEL_IMAGE_TAG),
+		},
 	}
 
 	for _, rt := range cases {
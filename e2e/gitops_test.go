@@ -19,7 +19,8 @@ var (
 	ignitedBin   = path.Join(e2eHome, "bin/ignited")
 )
 
-func TestRunGitops(t *testing.T) {
+// runGitops runs ignited in gitops mode on a repo holding a VM manifest of the given API version
+func runGitops(t *testing.T, apiVersion string) {
 	assert.Assert(t, e2eHome != "", "IGNITE_E2E_HOME should be set")
 
 	igniteCmd := util.NewCommand(t, igniteBin)
@@ -65,7 +66,7 @@ func TestRunGitops(t *testing.T) {
 
 	// Write a VM config file in the cloned repo, commit and push.
 	vmConfig := []byte(`---
-apiVersion: ignite.weave.works/v1alpha4
+apiVersion: ignite.weave.works/` + apiVersion + `
 kind: VM
 metadata:
   name: my-vm
@@ -132,3 +133,11 @@ status:
 	got := strings.TrimSpace(string(psOut))
 	assert.Equal(t, got, wantVMProperties, fmt.Sprintf("unexpected VM properties:\n\t(WNT): %q\n\t(GOT): %q", wantVMProperties, got))
 }
+
+func TestRunGitops(t *testing.T) {
+	runGitops(t, "v1alpha4")
+}
+
+func TestRunGitopsV1alpha5(t *testing.T) {
+	runGitops(t, "v1alpha5")
+}
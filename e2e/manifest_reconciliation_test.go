@@ -14,7 +14,8 @@ import (
 	"gotest.tools/assert"
 )
 
-func TestIgnitedDaemon(t *testing.T) {
+// runIgnitedDaemon runs ignited in daemon mode on a VM manifest of the given API version
+func runIgnitedDaemon(t *testing.T, apiVersion string) {
 	assert.Assert(t, e2eHome != "", "IGNITE_E2E_HOME should be set")
 
 	igniteCmd := util.NewCommand(t, igniteBin)
@@ -27,7 +28,7 @@ func TestIgnitedDaemon(t *testing.T) {
 
 	// Write the VM manifest in the manifest directory.
 	vmConfig := []byte(`---
-apiVersion: ignite.weave.works/v1alpha4
+apiVersion: ignite.weave.works/` + apiVersion + `
 kind: VM
 metadata:
   name: manifest-vm
@@ -86,3 +87,11 @@ status:
 	got = strings.TrimSpace(string(psOut))
 	assert.Equal(t, got, wantStatus)
 }
+
+func TestIgnitedDaemon(t *testing.T) {
+	runIgnitedDaemon(t, "v1alpha4")
+}
+
+func TestIgnitedDaemonV1alpha5(t *testing.T) {
+	runIgnitedDaemon(t, "v1alpha5")
+}
@@ -1,6 +1,7 @@
 package e2e
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -9,7 +10,6 @@ import (
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	igniteConstants "github.com/weaveworks/ignite/pkg/constants"
-	"github.com/weaveworks/ignite/pkg/dmlegacy"
 	"github.com/weaveworks/ignite/pkg/metadata"
 	"github.com/weaveworks/ignite/pkg/network"
 	"github.com/weaveworks/ignite/pkg/operations"
@@ -55,14 +55,14 @@ func startAsyncVM(t *testing.T, intfs []string) (*operations.VMChannels, string)
 		t.Fatalf("Failed to parse OCI image ref %s: %s", kernelImage, err)
 	}
 	vm.Spec.Kernel.OCI = ociRef
-	k, _ := operations.FindOrImportKernel(providers.Client, ociRef)
+	k, _ := operations.FindOrImportKernel(context.Background(), providers.Client, ociRef)
 	vm.SetKernel(k)
 
 	ociRef, err = meta.NewOCIImageRef(vmImage)
 	if err != nil {
 		t.Fatalf("Failed to parse OCI image ref %s: %s", vmImage, err)
 	}
-	img, err := operations.FindOrImportImage(providers.Client, ociRef)
+	img, err := operations.FindOrImportImage(context.Background(), providers.Client, ociRef)
 	if err != nil {
 		t.Fatalf("Failed to find OCI image ref %s: %s", ociRef, err)
 	}
@@ -79,12 +79,12 @@ func startAsyncVM(t *testing.T, intfs []string) (*operations.VMChannels, string)
 
 	_ = providers.Client.VMs().Set(vm)
 
-	err = dmlegacy.AllocateAndPopulateOverlay(vm)
+	err = operations.AllocateAndPopulateOverlay(vm)
 	if err != nil {
 		t.Fatalf("Error AllocateAndPopulateOverlay: %s", err)
 	}
 
-	vmChans, err := operations.StartVMNonBlocking(vm, false)
+	vmChans, err := operations.StartVMNonBlocking(context.Background(), vm, false)
 	if err != nil {
 		t.Fatalf("failed to start a VM: \n%q\n", err)
 	}
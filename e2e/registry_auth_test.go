@@ -83,14 +83,15 @@ func TestPullFromAuthRegistry(t *testing.T) {
 	defer os.Remove(configPath)
 
 	templateConfig := `---
-apiVersion: ignite.weave.works/v1alpha4
+apiVersion: ignite.weave.works/%s
 kind: Configuration
 metadata:
   name: test-config
 spec:
   registryConfigDir: %s
 `
-	igniteConfigContent := fmt.Sprintf(templateConfig, rcDir)
+	igniteConfigContent := fmt.Sprintf(templateConfig, "v1alpha4", rcDir)
+	igniteConfigContentV1alpha5 := fmt.Sprintf(templateConfig, "v1alpha5", rcDir)
 
 	type testCase struct {
 		name               string
@@ -130,6 +131,16 @@ spec:
 			runtime:      runtime.RuntimeDocker,
 			igniteConfig: igniteConfigContent,
 		},
+		{
+			name:         "registry config in v1alpha5 ignite config - containerd",
+			runtime:      runtime.RuntimeContainerd,
+			igniteConfig: igniteConfigContentV1alpha5,
+		},
+		{
+			name:         "registry config in v1alpha5 ignite config - docker",
+			runtime:      runtime.RuntimeDocker,
+			igniteConfig: igniteConfigContentV1alpha5,
+		},
 		// Following sets the registry config dir to a location without a valid
 		// registry config file, although the registry config dir in the ignite
 		// config is correct, the import fails due to bad configuration by the
@@ -0,0 +1,440 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+const (
+	// dialTimeout bounds connecting to the agent, including the vsock handshake
+	dialTimeout = 5 * time.Second
+	// requestTimeout bounds the requests that don't run user-given commands
+	requestTimeout = 10 * time.Second
+	// waitInterval is the time between connection attempts while waiting for the agent
+	waitInterval = 500 * time.Millisecond
+)
+
+// ErrNoAgent is returned when the VM has no guest agent listening, e.g. because
+// its image wasn't imported with the agent, or the agent hasn't started yet
+var ErrNoAgent = errors.New("no guest agent is listening")
+
+// ExecStreams connects a command run by Exec to the host
+type ExecStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Resize receives the new sizes of the terminal of the command, if it has one
+	Resize <-chan protocol.WindowSize
+	// Started is called once the command has started, if it's set
+	Started func()
+}
+
+// Exec runs the command in the VM using the guest agent, connected to the given
+// streams. It returns the exit code of the command once it has exited.
+func Exec(vm *api.VM, req *protocol.ExecRequest, streams *ExecStreams) (int, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return 0, err
+	}
+
+	httpReq, err := newRequest(http.MethodPost, protocol.ExecPath, nil, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Connection", "Upgrade")
+	httpReq.Header.Set("Upgrade", protocol.ExecUpgrade)
+
+	// The command may run for a long time, so the request isn't bounded
+	resp, err := do(vm, httpReq, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	conn, ok := resp.Body.(io.ReadWriter)
+	if resp.StatusCode != http.StatusSwitchingProtocols || !ok {
+		return 0, fmt.Errorf("guest agent of VM %q didn't upgrade the exec connection", vm.GetUID())
+	}
+
+	if streams.Started != nil {
+		streams.Started()
+	}
+
+	fw := protocol.NewFrameWriter(conn)
+	done := make(chan struct{})
+	defer close(done)
+
+	// Forward the input, an empty frame closes the stdin of the command
+	if streams.Stdin != nil {
+		go func() {
+			io.Copy(fw.Stream(protocol.StreamStdin), streams.Stdin)
+			fw.WriteFrame(protocol.StreamStdin, nil)
+		}()
+	}
+
+	if streams.Resize != nil {
+		go func() {
+			for {
+				select {
+				case size := <-streams.Resize:
+					fw.WriteFrame(protocol.StreamResize, protocol.EncodeWindowSize(size))
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		t, payload, err := protocol.ReadFrame(conn)
+		if err != nil {
+			return 0, fmt.Errorf("lost the exec connection to the guest agent of VM %q: %v", vm.GetUID(), err)
+		}
+
+		switch t {
+		case protocol.StreamStdout:
+			_, err = streams.Stdout.Write(payload)
+		case protocol.StreamStderr:
+			_, err = streams.Stderr.Write(payload)
+		case protocol.StreamExit:
+			return protocol.DecodeExitCode(payload)
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Wait waits for the guest agent of the VM to start listening, for at most timeout
+func Wait(vm *api.VM, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := dial(context.Background(), vm)
+		if err == nil {
+			return conn.Close()
+		}
+
+		if !errors.Is(err, ErrNoAgent) || time.Now().After(deadline) {
+			return err
+		}
+
+		time.Sleep(waitInterval)
+	}
+}
+
+// ReadFile copies the contents of the file at filePath in the VM to w
+func ReadFile(vm *api.VM, filePath string, w io.Writer) error {
+	req, err := newRequest(http.MethodGet, protocol.FilePath, url.Values{protocol.PathParam: {filePath}}, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := do(vm, req, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// WriteFile replaces the file at filePath in the VM with the contents of r
+func WriteFile(vm *api.VM, filePath string, r io.Reader, mode os.FileMode) error {
+	query := url.Values{
+		protocol.PathParam: {filePath},
+		protocol.ModeParam: {strconv.FormatUint(uint64(mode.Perm()), 8)},
+	}
+
+	req, err := newRequest(http.MethodPut, protocol.FilePath, query, r)
+	if err != nil {
+		return err
+	}
+
+	resp, err := do(vm, req, 0)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// ReadArchive returns the file or directory at filePath in the VM as a tar stream written by
+// protocol.Archive, which needs to be closed by the caller
+func ReadArchive(vm *api.VM, filePath string) (io.ReadCloser, error) {
+	req, err := newRequest(http.MethodGet, protocol.ArchivePath, url.Values{protocol.PathParam: {filePath}}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := do(vm, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// WriteArchive copies the directory in the tar stream written by protocol.Archive read from r
+// to dirPath in the VM, or into it if it's an existing directory with a different name
+func WriteArchive(vm *api.VM, dirPath string, r io.Reader) error {
+	req, err := newRequest(http.MethodPut, protocol.ArchivePath, url.Values{protocol.PathParam: {dirPath}}, r)
+	if err != nil {
+		return err
+	}
+
+	resp, err := do(vm, req, 0)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// WriteSecrets replaces the secrets of the VM, the agent writes them to a tmpfs
+func WriteSecrets(vm *api.VM, secrets []protocol.Secret) error {
+	body, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	req, err := newRequest(http.MethodPut, protocol.SecretsPath, nil, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := do(vm, req, requestTimeout)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// Extract extracts the tar stream read from r into the root filesystem of the VM, the
+// files that exist in the VM already are kept
+func Extract(vm *api.VM, r io.Reader) error {
+	req, err := newRequest(http.MethodPut, protocol.ExtractPath, nil, r)
+	if err != nil {
+		return err
+	}
+
+	// The stream may be large and read from a registry, so the request isn't bounded
+	resp, err := do(vm, req, 0)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// GetIdentity returns the hostname and the addresses of the VM
+func GetIdentity(vm *api.VM) (*protocol.Identity, error) {
+	req, err := newRequest(http.MethodGet, protocol.IdentityPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := do(vm, req, requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	identity := &protocol.Identity{}
+	if err := json.NewDecoder(resp.Body).Decode(identity); err != nil {
+		return nil, fmt.Errorf("failed to decode the response of the guest agent: %v", err)
+	}
+
+	return identity, nil
+}
+
+// SetIdentity changes the hostname and the address of the VM, the MAC address is kept
+func SetIdentity(vm *api.VM, identity *protocol.Identity) error {
+	body, err := json.Marshal(identity)
+	if err != nil {
+		return err
+	}
+
+	req, err := newRequest(http.MethodPut, protocol.IdentityPath, nil, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := do(vm, req, requestTimeout)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// GetMetrics returns the resource usage inside of the VM
+func GetMetrics(vm *api.VM) (*protocol.Metrics, error) {
+	req, err := newRequest(http.MethodGet, protocol.MetricsPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := do(vm, req, requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	metrics := &protocol.Metrics{}
+	if err := json.NewDecoder(resp.Body).Decode(metrics); err != nil {
+		return nil, fmt.Errorf("failed to decode the response of the guest agent: %v", err)
+	}
+
+	return metrics, nil
+}
+
+// Signal sends the signal to the command run by Exec with the given request ID
+func Signal(vm *api.VM, id string, sig syscall.Signal) error {
+	query := url.Values{
+		protocol.IDParam:     {id},
+		protocol.SignalParam: {strconv.Itoa(int(sig))},
+	}
+
+	req, err := newRequest(http.MethodPost, protocol.SignalPath, query, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := do(vm, req, requestTimeout)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// Shutdown requests the VM to shut down gracefully, it doesn't wait for the VM to stop
+func Shutdown(vm *api.VM) error {
+	req, err := newRequest(http.MethodPost, protocol.ShutdownPath, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := do(vm, req, requestTimeout)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// newRequest creates a request for the given endpoint of the agent API
+func newRequest(method, endpoint string, query url.Values, body io.Reader) (*http.Request, error) {
+	u := url.URL{Scheme: "http", Host: "localhost", Path: endpoint, RawQuery: query.Encode()}
+	return http.NewRequest(method, u.String(), body)
+}
+
+// do sends a request to the guest agent of the VM, and returns the response if it
+// succeeded or upgraded the connection
+func do(vm *api.VM, req *http.Request, timeout time.Duration) (*http.Response, error) {
+	if !vm.Running() {
+		return nil, fmt.Errorf("VM %q is not running", vm.GetUID())
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dial(ctx, vm)
+			},
+			DisableKeepAlives: true,
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the guest agent of VM %q: %w", vm.GetUID(), err)
+	}
+
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("guest agent of VM %q returned %s: %s", vm.GetUID(), resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return resp, nil
+}
+
+// dial connects to the agent port of the VM. Firecracker exposes the vsock device of the VM
+// as a unix socket in the VM directory, a connection to a guest port is requested by sending
+// "CONNECT <port>\n" over it, which Firecracker acknowledges with "OK <host port>\n".
+func dial(ctx context.Context, vm *api.VM) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	socketPath := path.Join(vm.ObjectPath(), constants.FIRECRACKER_VSOCK_SOCKET)
+	conn, err := (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+	if err != nil {
+		// VMs started without a vsock device have no socket to connect to
+		if errors.Is(err, syscall.ENOENT) || errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, fmt.Errorf("%w in VM %q: %v", ErrNoAgent, vm.GetUID(), err)
+		}
+
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := fmt.Fprintf(conn, "CONNECT %d\n", protocol.Port); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Read the acknowledgement byte by byte, the agent's response follows it directly
+	ack, err := readLine(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w in VM %q: %v", ErrNoAgent, vm.GetUID(), err)
+	}
+
+	if !strings.HasPrefix(ack, "OK ") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected vsock handshake response %q", strings.TrimSpace(ack))
+	}
+
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// readLine reads a single line from r without reading past its end
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for len(line) == 0 || line[len(line)-1] != '\n' {
+		if _, err := r.Read(b); err != nil {
+			return "", err
+		}
+
+		line = append(line, b[0])
+	}
+
+	return string(line), nil
+}
@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+const (
+	// BinaryName is the name of the agent binary on the host
+	BinaryName = "ignite-agent"
+
+	// Where the agent is installed in the image
+	guestBinaryPath  = "/usr/local/sbin/" + BinaryName
+	systemdUnitPath  = "/etc/systemd/system/" + BinaryName + ".service"
+	systemdWantsPath = "/etc/systemd/system/multi-user.target.wants/" + BinaryName + ".service"
+)
+
+// systemdUnit starts the agent at boot, and restarts it if it exits
+var systemdUnit = fmt.Sprintf(`[Unit]
+Description=Ignite guest agent
+
+[Service]
+ExecStart=%s
+Restart=always
+
+[Install]
+WantedBy=multi-user.target
+`, guestBinaryPath)
+
+// Binary returns the path of the agent binary on the host. It's looked up next to
+// the running ignite binary first, and in $PATH otherwise. The agent needs to be
+// built statically, as it's run with the libraries of the image.
+func Binary() (string, error) {
+	if self, err := os.Executable(); err == nil {
+		binary := filepath.Join(filepath.Dir(self), BinaryName)
+		if util.FileExists(binary) {
+			return binary, nil
+		}
+	}
+
+	binary, err := exec.LookPath(BinaryName)
+	if err != nil {
+		return "", fmt.Errorf("the %s binary wasn't found next to ignite or in $PATH: %v", BinaryName, err)
+	}
+
+	return binary, nil
+}
+
+// GuestFile is a file installed into the image with the agent. Symlinks have a Target.
+type GuestFile struct {
+	Path    string
+	Mode    os.FileMode
+	Content []byte
+	Target  string
+}
+
+// GuestFiles returns the files installing the agent binary into an image, and enabling
+// it to be started at boot by systemd, the way "systemctl enable" does
+func GuestFiles(binary string) ([]GuestFile, error) {
+	content, err := ioutil.ReadFile(binary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the guest agent: %v", err)
+	}
+
+	return []GuestFile{
+		{Path: guestBinaryPath, Mode: 0755, Content: content},
+		{Path: systemdUnitPath, Mode: 0644, Content: []byte(systemdUnit)},
+		{Path: systemdWantsPath, Mode: 0777, Target: systemdUnitPath},
+	}, nil
+}
+
+// Inject copies the agent binary into the root filesystem at rootfs, and enables
+// it to be started at boot by systemd. Images using another init system need to
+// start the agent themselves.
+func Inject(rootfs, binary string) error {
+	log.Debugf("Injecting the guest agent %q into the image", binary)
+	files, err := GuestFiles(binary)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		p := filepath.Join(rootfs, file.Path)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+
+		if len(file.Target) != 0 {
+			if err := os.Symlink(file.Target, p); err != nil && !os.IsExist(err) {
+				return err
+			}
+			continue
+		}
+
+		if err := ioutil.WriteFile(p, file.Content, file.Mode); err != nil {
+			return fmt.Errorf("failed to write %s into the image: %v", file.Path, err)
+		}
+
+		// WriteFile doesn't change the mode of existing files, and applies the umask
+		if err := os.Chmod(p, file.Mode); err != nil {
+			return err
+		}
+	}
+
+	CheckInit(func(p string) bool {
+		_, err := os.Lstat(filepath.Join(rootfs, p))
+		return err == nil
+	})
+
+	return nil
+}
+
+// CheckInit warns if the image doesn't use systemd, which starts the agent. exists
+// returns if the path exists in the image.
+func CheckInit(exists func(p string) bool) {
+	if !usesSystemd(exists) {
+		log.Warnf("The image doesn't seem to use systemd, its init system needs to start %s to use the guest agent", guestBinaryPath)
+	}
+}
+
+// usesSystemd returns true if systemd is installed in the image
+func usesSystemd(exists func(p string) bool) bool {
+	for _, p := range []string{"/lib/systemd/systemd", "/usr/lib/systemd/systemd"} {
+		if exists(p) {
+			return true
+		}
+	}
+
+	return false
+}
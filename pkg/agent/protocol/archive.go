@@ -0,0 +1,112 @@
+package protocol
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Archive writes the file or directory at root to w as a tar stream, directories are
+// walked recursively. The entries are named below the base name of root, and owned by
+// root, as the copies are owned by the user receiving them.
+func Archive(w io.Writer, root string) error {
+	tw := tar.NewWriter(w)
+	base := filepath.Base(root)
+
+	// A trailing separator makes the walk follow root if it's a symlink to a directory
+	walkRoot := root
+	if fi, err := os.Stat(root); err == nil && fi.IsDir() {
+		walkRoot += string(filepath.Separator)
+	}
+
+	err := filepath.Walk(walkRoot, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(base, filepath.ToSlash(rel))
+		hdr.Uid, hdr.Gid = 0, 0
+		hdr.Uname, hdr.Gname = "", ""
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// ArchiveRelPath returns the path of an entry written by Archive relative to its root
+func ArchiveRelPath(name string) string {
+	name = path.Clean("/" + name)[1:]
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		return name[i+1:]
+	}
+
+	return "."
+}
+
+// ArchiveJoin returns where the entry of an archive written by Archive is unpacked to
+// below root. It fails if a parent directory of the entry below root is a symlink, so
+// an archive can't write outside of root through a symlink it unpacked before.
+func ArchiveJoin(root, name string) (string, error) {
+	rel := ArchiveRelPath(name)
+	dir := root
+	for _, elem := range strings.Split(path.Dir(rel), "/") {
+		if elem == "." {
+			continue
+		}
+
+		dir = filepath.Join(dir, elem)
+		fi, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			break
+		} else if err != nil {
+			return "", err
+		}
+
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("cannot write %q through the symlink %q", name, dir)
+		} else if !fi.IsDir() {
+			return "", fmt.Errorf("cannot write %q, %q is not a directory", name, dir)
+		}
+	}
+
+	return filepath.Join(root, filepath.FromSlash(rel)), nil
+}
@@ -0,0 +1,114 @@
+package protocol
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, "project")
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("src/main.go", filepath.Join(root, "main.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The walk follows a symlink given as the root, the entries keep its name
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(root, link); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Archive(&buf, link); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		name, rel string
+		typeflag  byte
+	}{
+		{"link", ".", tar.TypeDir},
+		{"link/main.go", "main.go", tar.TypeSymlink},
+		{"link/src", "src", tar.TypeDir},
+		{"link/src/main.go", "src/main.go", tar.TypeReg},
+	}
+
+	tr := tar.NewReader(&buf)
+	for _, e := range expected {
+		hdr, err := tr.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if hdr.Name != e.name || hdr.Typeflag != e.typeflag {
+			t.Errorf("expected %q of type %q, got %q of type %q", e.name, e.typeflag, hdr.Name, hdr.Typeflag)
+		}
+		if rel := ArchiveRelPath(hdr.Name); rel != e.rel {
+			t.Errorf("expected %q to be at %q, got %q", hdr.Name, e.rel, rel)
+		}
+	}
+
+	if _, err := tr.Next(); err != io.EOF {
+		t.Errorf("expected the end of the archive, got %v", err)
+	}
+}
+
+func TestArchiveJoin(t *testing.T) {
+	root, err := ioutil.TempDir("", "archive-join")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	if err := os.Mkdir(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "file"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/etc", filepath.Join(root, "etc")); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		expected string
+		err      bool
+	}{
+		{name: "project", expected: root},
+		{name: "project/src/main.go", expected: filepath.Join(root, "src", "main.go")},
+		{name: "project/new/dir/main.go", expected: filepath.Join(root, "new", "dir", "main.go")},
+		{name: "project/../../etc/passwd", expected: filepath.Join(root, "passwd")},
+		{name: "project/etc", expected: filepath.Join(root, "etc")},
+		{name: "project/etc/passwd", err: true},
+		{name: "project/file/passwd", err: true},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			actual, err := ArchiveJoin(root, rt.name)
+			if (err != nil) != rt.err {
+				t.Fatalf("expected error %t, actual: %v", rt.err, err)
+			}
+			if !rt.err && actual != rt.expected {
+				t.Errorf("expected %q, actual %q", rt.expected, actual)
+			}
+		})
+	}
+}
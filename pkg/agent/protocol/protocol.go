@@ -0,0 +1,117 @@
+// Package protocol defines the API of the ignite guest agent, which is served over HTTP
+// on a virtio-vsock port inside of the VM. It's shared by the agent and its client on the
+// host, and only depends on the standard library to keep the agent binary small.
+package protocol
+
+const (
+	// Port is the vsock port the agent listens on inside of the VM
+	Port = 10240
+
+	// ExecPath runs a command given as ExecRequest. If the command was started, the
+	// connection is upgraded to ExecUpgrade, and carries the frames of an exec stream.
+	ExecPath = "/exec"
+	// ExecUpgrade is the protocol requested in the Upgrade header of exec requests
+	ExecUpgrade = "ignite-exec"
+	// FilePath reads (GET) or writes (PUT) the file given by the PathParam
+	// query parameter, a write sets the permissions given by ModeParam
+	FilePath = "/file"
+	// MetricsPath returns the Metrics of the VM
+	MetricsPath = "/metrics"
+	// ShutdownPath shuts the VM down gracefully
+	ShutdownPath = "/shutdown"
+	// SecretsPath replaces the secrets of the VM with the []Secret sent (PUT). They're
+	// written to a tmpfs mounted at SecretsDir, so they never reach the disk of the VM.
+	SecretsPath = "/secrets"
+	// ExtractPath extracts the tar stream sent (PUT) into the root filesystem of the VM.
+	// Files that exist already are kept, the VM may have written them.
+	ExtractPath = "/extract"
+	// IdentityPath returns (GET) or changes (PUT) the Identity of the VM. A VM restored from
+	// a snapshot of another VM takes its own identity this way.
+	IdentityPath = "/identity"
+	// SignalPath sends the signal given by SignalParam to the command started by the exec
+	// request with the ID given by IDParam (POST)
+	SignalPath = "/signal"
+	// ArchivePath returns (GET) the file or directory given by PathParam as a tar stream
+	// written by Archive, or copies the directory in the tar stream sent (PUT) to PathParam.
+	// A directory existing at PathParam receives a copy of the directory, unless their
+	// names match. Files that exist already are replaced.
+	ArchivePath = "/archive"
+
+	// SecretsDir is where the agent mounts the tmpfs holding the secrets
+	SecretsDir = "/run/ignite/secrets"
+
+	// PathParam is the query parameter holding the absolute path of a file in the VM
+	PathParam = "path"
+	// ModeParam is the query parameter holding the octal permissions of a written file
+	ModeParam = "mode"
+	// IDParam is the query parameter holding the ID of an exec request
+	IDParam = "id"
+	// SignalParam is the query parameter holding the number of a signal
+	SignalParam = "signal"
+)
+
+// ExecRequest describes a command to run in the VM
+type ExecRequest struct {
+	// Command is the executable followed by its arguments, the
+	// executable is looked up in $PATH if it's not a path
+	Command []string `json:"command"`
+	// Env holds KEY=value variables added to the environment of the command
+	Env []string `json:"env,omitempty"`
+	// Tty runs the command in a pseudo-terminal of the given size. Stdout and
+	// stderr are merged into the stdout stream of the terminal in that case.
+	Tty  bool       `json:"tty,omitempty"`
+	Size WindowSize `json:"size,omitempty"`
+	// ID identifies the command for signal requests while it runs, it needs to be unique
+	ID string `json:"id,omitempty"`
+	// Root runs the command chrooted into the directory, like a container. The executable
+	// is looked up in the $PATH of Env inside of it, and the environment only holds Env.
+	Root string `json:"root,omitempty"`
+	// Dir is the working directory of the command, inside of Root if it's set
+	Dir string `json:"dir,omitempty"`
+}
+
+// Secret is a secret delivered to the VM
+type Secret struct {
+	// Name is the name of the secret file in SecretsDir
+	Name string `json:"name"`
+	// Data is the value of the secret
+	Data []byte `json:"data"`
+	// Mode is the permissions of the secret file
+	Mode uint32 `json:"mode"`
+}
+
+// Identity is how the VM is known on its network. The address and MAC address are of
+// the interface of the default route.
+type Identity struct {
+	Hostname string `json:"hostname"`
+	// Address is the IPv4 address of the interface. A changed address keeps the prefix
+	// length of the previous one, and the default route is added back.
+	Address string `json:"address,omitempty"`
+	// Gateway replaces the gateway of the default route if set, for an address in
+	// another subnet
+	Gateway string `json:"gateway,omitempty"`
+	// MACAddress of the interface, it isn't changed
+	MACAddress string `json:"macAddress,omitempty"`
+}
+
+// WindowSize is the size of a terminal in characters
+type WindowSize struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+// Metrics describes the resource usage inside of the VM, sizes are in bytes
+type Metrics struct {
+	// UptimeSeconds is the time since the VM booted
+	UptimeSeconds float64 `json:"uptimeSeconds"`
+	// Load holds the 1, 5 and 15 minute load averages
+	Load [3]float64 `json:"load"`
+	// MemoryTotal is the memory usable by the guest kernel
+	MemoryTotal uint64 `json:"memoryTotal"`
+	// MemoryAvailable is the memory available for starting new processes
+	MemoryAvailable uint64 `json:"memoryAvailable"`
+	// DiskTotal is the size of the root filesystem
+	DiskTotal uint64 `json:"diskTotal"`
+	// DiskAvailable is the space available on the root filesystem
+	DiskAvailable uint64 `json:"diskAvailable"`
+}
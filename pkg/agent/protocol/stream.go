@@ -0,0 +1,144 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// StreamType identifies the contents of a frame in an exec stream. Every frame
+// starts with its StreamType byte, followed by the length of its payload as a
+// big endian uint32, followed by the payload.
+type StreamType byte
+
+const (
+	// StreamStdin carries input of the command from the host, an empty
+	// payload closes the stdin of the command
+	StreamStdin StreamType = iota
+	// StreamStdout carries the standard output of the command
+	StreamStdout
+	// StreamStderr carries the standard error of the command
+	StreamStderr
+	// StreamResize carries the new WindowSize of the terminal from the host,
+	// as its width and height in big endian uint16s
+	StreamResize
+	// StreamExit carries the exit code of the command as a big endian int32,
+	// it's the last frame sent by the agent
+	StreamExit
+)
+
+// MaxFrameSize limits the payload size of a frame
+const MaxFrameSize = 32 * 1024
+
+// FrameWriter writes the frames of an exec stream, it's safe for concurrent use
+type FrameWriter struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewFrameWriter returns a FrameWriter writing to w
+func NewFrameWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: w}
+}
+
+// WriteFrame writes payload as a single frame of type t
+func (fw *FrameWriter) WriteFrame(t StreamType, payload []byte) error {
+	if len(payload) > MaxFrameSize {
+		return fmt.Errorf("frame payload of %d bytes exceeds the maximum of %d bytes", len(payload), MaxFrameSize)
+	}
+
+	header := make([]byte, 5)
+	header[0] = byte(t)
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if _, err := fw.w.Write(header); err != nil {
+		return err
+	}
+
+	_, err := fw.w.Write(payload)
+	return err
+}
+
+// Stream returns a writer sending everything written to it as frames of type t
+func (fw *FrameWriter) Stream(t StreamType) io.Writer {
+	return &streamWriter{fw, t}
+}
+
+type streamWriter struct {
+	fw *FrameWriter
+	t  StreamType
+}
+
+func (sw *streamWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > MaxFrameSize {
+			chunk = chunk[:MaxFrameSize]
+		}
+
+		if err = sw.fw.WriteFrame(sw.t, chunk); err != nil {
+			return
+		}
+
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+
+	return
+}
+
+// ReadFrame reads the next frame from r
+func ReadFrame(r io.Reader) (StreamType, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > MaxFrameSize {
+		return 0, nil, fmt.Errorf("frame payload of %d bytes exceeds the maximum of %d bytes", size, MaxFrameSize)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return StreamType(header[0]), payload, nil
+}
+
+// EncodeWindowSize returns the payload of a StreamResize frame
+func EncodeWindowSize(size WindowSize) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint16(b, size.Width)
+	binary.BigEndian.PutUint16(b[2:], size.Height)
+	return b
+}
+
+// DecodeWindowSize parses the payload of a StreamResize frame
+func DecodeWindowSize(b []byte) (WindowSize, error) {
+	if len(b) != 4 {
+		return WindowSize{}, fmt.Errorf("invalid resize payload of %d bytes", len(b))
+	}
+
+	return WindowSize{binary.BigEndian.Uint16(b), binary.BigEndian.Uint16(b[2:])}, nil
+}
+
+// EncodeExitCode returns the payload of a StreamExit frame
+func EncodeExitCode(code int) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(int32(code)))
+	return b
+}
+
+// DecodeExitCode parses the payload of a StreamExit frame
+func DecodeExitCode(b []byte) (int, error) {
+	if len(b) != 4 {
+		return 0, fmt.Errorf("invalid exit payload of %d bytes", len(b))
+	}
+
+	return int(int32(binary.BigEndian.Uint32(b))), nil
+}
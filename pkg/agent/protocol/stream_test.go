@@ -0,0 +1,60 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	fw := NewFrameWriter(&buf)
+
+	// Writes larger than a frame are split into multiple frames
+	large := bytes.Repeat([]byte("a"), MaxFrameSize+10)
+	if _, err := fw.Stream(StreamStdout).Write(large); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fw.WriteFrame(StreamResize, EncodeWindowSize(WindowSize{Width: 80, Height: 24})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fw.WriteFrame(StreamExit, EncodeExitCode(-1)); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout []byte
+	for _, size := range []int{MaxFrameSize, 10} {
+		st, payload, err := ReadFrame(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if st != StreamStdout || len(payload) != size {
+			t.Fatalf("expected a stdout frame of %d bytes, got type %d with %d bytes", size, st, len(payload))
+		}
+		stdout = append(stdout, payload...)
+	}
+
+	if !bytes.Equal(stdout, large) {
+		t.Errorf("stdout doesn't match the written data")
+	}
+
+	st, payload, err := ReadFrame(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if size, err := DecodeWindowSize(payload); st != StreamResize || err != nil || size != (WindowSize{80, 24}) {
+		t.Errorf("unexpected resize frame: type %d, size %v, error %v", st, size, err)
+	}
+
+	st, payload, err = ReadFrame(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code, err := DecodeExitCode(payload); st != StreamExit || err != nil || code != -1 {
+		t.Errorf("unexpected exit frame: type %d, code %d, error %v", st, code, err)
+	}
+}
@@ -1,10 +1,16 @@
 package ignite
 
 import (
+	"fmt"
 	"path"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
 	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
 )
 
 // SetImage populates relevant fields to an Image on the VM object
@@ -13,13 +19,44 @@ func (vm *VM) SetImage(image *Image) {
 	vm.Status.Image = image.Status.OCISource
 }
 
-// SetKernel populates relevant fields to a Kernel on the VM object
+// SetKernel populates relevant fields to a Kernel on the VM object. VMs of disk
+// images have no kernel, a nil kernel clears its status.
 func (vm *VM) SetKernel(kernel *Kernel) {
+	if kernel == nil {
+		vm.Status.Kernel = OCIImageSource{}
+		return
+	}
+
 	vm.Spec.Kernel.OCI = kernel.Spec.OCI
 	vm.Spec.Kernel.HasInitrd = kernel.Spec.HasInitrd
 	vm.Status.Kernel = kernel.Status.OCISource
 }
 
+// HasInitrd returns true if the kernel of the VM boots with an initrd, of its kernel
+// image or of its own initrd image
+func (vm *VM) HasInitrd() bool {
+	return vm.Spec.Kernel.HasInitrd || vm.Spec.Kernel.InitrdRef != nil
+}
+
+// DiskImage returns true if the image of the VM is a disk image, booted with UEFI
+// firmware from its own bootloader instead of the kernel of the VM
+func (vm *VM) DiskImage() bool {
+	return vm.Status.Image.Format == ImageFormatDisk
+}
+
+// HasDevices returns true if host PCI devices are passed through into the VM, which
+// runs with QEMU then
+func (vm *VM) HasDevices() bool {
+	return len(vm.Spec.Devices) > 0
+}
+
+// QEMU returns true if the VM is run with QEMU instead of Firecracker, as it boots a disk
+// image, or has host PCI devices or nested virtualization, which Firecracker doesn't
+// support. Without KVM, the other VMs are emulated with QEMU too.
+func (vm *VM) QEMU() bool {
+	return vm.DiskImage() || vm.HasDevices() || vm.Spec.NestedVirtualization
+}
+
 // NewPrefixer returns a util.Prefixer specific to the VM
 func (vm *VM) NewPrefixer() *util.Prefixer {
 	if vm.Status.IDPrefix == "" {
@@ -46,9 +83,85 @@ func (vm *VM) Running() bool {
 	return vm.Status.Running
 }
 
-// OverlayFile returns the path to the overlay.dm file for the VM.
-// TODO: This will be removed once we have the new snapshotter in place.
+// Paused returns true if the VM is running, but frozen
+func (vm *VM) Paused() bool {
+	return vm.Status.Running && vm.Status.Paused
+}
+
+// ExitReason returns why the VM stopped. Exit statuses without a reason, e.g. recorded by
+// older versions of ignite, get the reason of their exit code.
+func (s *VMExitStatus) ExitReason() ExitReason {
+	switch {
+	case len(s.Reason) > 0:
+		return s.Reason
+	case s.Requested:
+		return ExitReasonStopped
+	case s.ExitCode == 0:
+		return ExitReasonCompleted
+	case s.ExitCode > 128:
+		// Killed by a signal, following the shell convention of 128 + the signal number
+		return ExitReasonKilled
+	}
+
+	return ExitReasonError
+}
+
+// Condition returns the VM's condition of the given type, or nil if it isn't set
+func (vm *VM) Condition(conditionType VMConditionType) *VMCondition {
+	for i := range vm.Status.Conditions {
+		if vm.Status.Conditions[i].Type == conditionType {
+			return &vm.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// ConditionTrue returns true if the VM's condition of the given type has status True
+func (vm *VM) ConditionTrue(conditionType VMConditionType) bool {
+	c := vm.Condition(conditionType)
+	return c != nil && c.Status == ConditionTrue
+}
+
+// SetCondition sets the VM's condition of the given type. Its transition time is only
+// updated if the status changes, the reason and message are always replaced.
+func (vm *VM) SetCondition(conditionType VMConditionType, status ConditionStatus, reason, message string) {
+	c := vm.Condition(conditionType)
+	if c == nil {
+		vm.Status.Conditions = append(vm.Status.Conditions, VMCondition{Type: conditionType})
+		c = &vm.Status.Conditions[len(vm.Status.Conditions)-1]
+	}
+
+	if c.Status != status {
+		c.Status = status
+		c.LastTransitionTime = runtime.Timestamp()
+	}
+
+	c.Reason = reason
+	c.Message = message
+}
+
+// OverlayFile returns the path to the file holding the writable overlay of the VM,
+// which is overlay.dm for the (legacy) DM snapshotter, overlay.qcow2 for qcow2 and
+// overlay.raw for reflink.
+// The dmthin, lvm, zfs and rbd snapshotters keep the overlay outside of the VM directory,
+// and only record a reference to it in overlay.thin, overlay.lv, overlay.zvol or overlay.rbd.
 func (vm *VM) OverlayFile() string {
+	switch vm.Status.Snapshotter {
+	case snapshotter.SnapshotterQcow2:
+		return path.Join(vm.ObjectPath(), constants.QCOW2_OVERLAY_FILE)
+	case snapshotter.SnapshotterReflink:
+		return path.Join(vm.ObjectPath(), constants.REFLINK_OVERLAY_FILE)
+	case snapshotter.SnapshotterDMThin:
+		return path.Join(vm.ObjectPath(), constants.DMTHIN_OVERLAY_FILE)
+	case snapshotter.SnapshotterLVM:
+		return path.Join(vm.ObjectPath(), constants.LVM_OVERLAY_FILE)
+	case snapshotter.SnapshotterZFS:
+		return path.Join(vm.ObjectPath(), constants.ZFS_OVERLAY_FILE)
+	case snapshotter.SnapshotterRBD:
+		return path.Join(vm.ObjectPath(), constants.RBD_OVERLAY_FILE)
+	}
+
 	return path.Join(vm.ObjectPath(), constants.OVERLAY_FILE)
 }
 
@@ -69,3 +182,89 @@ func (k *Kernel) ObjectPath() string {
 	// TODO: Move this into storage
 	return path.Join(constants.DATA_DIR, k.GetKind().Lower(), k.GetUID().String())
 }
+
+// Bytes returns the size of the hugepages in bytes, or 0 if the size isn't supported
+func (s HugepageSize) Bytes() uint64 {
+	switch s {
+	case HugepageSize2Mi:
+		return 2 << 20
+	case HugepageSize1Gi:
+		return 1 << 30
+	}
+
+	return 0
+}
+
+// KernelCmdLineBuiltinVars are the variables the kernel command line of every VM may
+// reference, ignite-spawn resolves them when the VM starts
+var KernelCmdLineBuiltinVars = []string{"VM_UID", "VM_NAME", "VM_HOSTNAME", "VM_IP", "VM_NETMASK", "VM_GATEWAY"}
+
+// KernelCmdLineReservedPrefix prefixes the names of the built-in variables, and of the ones
+// that may be built in by later versions of ignite
+const KernelCmdLineReservedPrefix = "VM_"
+
+// kernelCmdLineVarRegexp matches the ${NAME} variables of a kernel command line, and their
+// $${NAME} escapes
+var kernelCmdLineVarRegexp = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// KernelCmdLineVars returns the custom variables of the VM's kernel command line,
+// which are set by annotations prefixed with IGNITE_KERNEL_CMDLINE_VAR_ANNOTATION
+func (vm *VM) KernelCmdLineVars() map[string]string {
+	vars := map[string]string{}
+	for k, v := range vm.GetObjectMeta().Annotations {
+		if strings.HasPrefix(k, constants.IGNITE_KERNEL_CMDLINE_VAR_ANNOTATION) {
+			vars[strings.TrimPrefix(k, constants.IGNITE_KERNEL_CMDLINE_VAR_ANNOTATION)] = v
+		}
+	}
+
+	return vars
+}
+
+// Hosts returns the IP addresses of the host names written to /etc/hosts of the VM,
+// which are set by annotations prefixed with IGNITE_HOST_ANNOTATION
+func (vm *VM) Hosts() map[string]string {
+	hosts := map[string]string{}
+	for k, v := range vm.GetObjectMeta().Annotations {
+		if strings.HasPrefix(k, constants.IGNITE_HOST_ANNOTATION) {
+			hosts[strings.TrimPrefix(k, constants.IGNITE_HOST_ANNOTATION)] = v
+		}
+	}
+
+	return hosts
+}
+
+// ExpandKernelCmdLine replaces the ${NAME} variables of the kernel command line by their
+// values in vars. Variables that aren't in vars are left as they are, so a literal ${NAME}
+// can be passed to init, unless their name has the reserved prefix, which is an error.
+// $${NAME} is replaced by a literal ${NAME}.
+func ExpandKernelCmdLine(cmdLine string, vars map[string]string) (string, error) {
+	undefined := map[string]struct{}{}
+	expanded := kernelCmdLineVarRegexp.ReplaceAllStringFunc(cmdLine, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		name := kernelCmdLineVarRegexp.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			if strings.HasPrefix(name, KernelCmdLineReservedPrefix) {
+				undefined[name] = struct{}{}
+			}
+			return match
+		}
+
+		return value
+	})
+
+	if len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return "", fmt.Errorf("undefined kernel command line variables: %s", strings.Join(names, ", "))
+	}
+
+	return expanded, nil
+}
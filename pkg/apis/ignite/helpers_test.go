@@ -0,0 +1,58 @@
+package ignite
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+func TestSetCondition(t *testing.T) {
+	vm := &VM{}
+	assert.Assert(t, vm.Condition(VMBooted) == nil)
+
+	vm.SetCondition(VMBooted, ConditionUnknown, "Starting", "")
+	vm.SetCondition(VMDegraded, ConditionFalse, "AsExpected", "")
+	assert.Equal(t, len(vm.Status.Conditions), 2)
+	assert.Assert(t, !vm.ConditionTrue(VMBooted))
+
+	// Backdate the transition, to tell whether it's updated
+	before := runtime.Time{Time: metav1.Unix(1, 0)}
+	vm.Condition(VMBooted).LastTransitionTime = before
+
+	// The same status only replaces the reason and message
+	vm.SetCondition(VMBooted, ConditionUnknown, "StillStarting", "waiting")
+	booted := vm.Condition(VMBooted)
+	assert.Equal(t, booted.Reason, "StillStarting")
+	assert.Equal(t, booted.Message, "waiting")
+	assert.Assert(t, booted.LastTransitionTime.Equal(&before.Time))
+
+	vm.SetCondition(VMBooted, ConditionTrue, "FirecrackerStarted", "")
+	booted = vm.Condition(VMBooted)
+	assert.Assert(t, vm.ConditionTrue(VMBooted))
+	assert.Assert(t, booted.LastTransitionTime.After(before.Time.Time))
+	assert.Equal(t, booted.Message, "")
+	assert.Equal(t, len(vm.Status.Conditions), 2)
+}
+
+func TestExitReason(t *testing.T) {
+	cases := []struct {
+		name   string
+		status VMExitStatus
+		want   ExitReason
+	}{
+		{"recorded reason", VMExitStatus{ExitCode: 137, Reason: ExitReasonOOMKilled}, ExitReasonOOMKilled},
+		{"requested stop", VMExitStatus{ExitCode: 137, Requested: true}, ExitReasonStopped},
+		{"guest shutdown", VMExitStatus{ExitCode: 0}, ExitReasonCompleted},
+		{"killed", VMExitStatus{ExitCode: 137}, ExitReasonKilled},
+		{"failed", VMExitStatus{ExitCode: 1}, ExitReasonError},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.Equal(t, rt.status.ExitReason(), rt.want)
+		})
+	}
+}
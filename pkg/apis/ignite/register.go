@@ -30,6 +30,7 @@ var SchemeGroupVersion = schema.GroupVersion{
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&VM{},
+		&VMTemplate{},
 		&Kernel{},
 		&Pool{},
 		&Image{},
@@ -9,6 +9,7 @@ import (
 	"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2"
 	"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3"
 	"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5"
 	"github.com/weaveworks/libgitops/pkg/serializer"
 )
 
@@ -34,5 +35,6 @@ func AddToScheme(scheme *runtime.Scheme) {
 	utilruntime.Must(v1alpha2.AddToScheme(Scheme))
 	utilruntime.Must(v1alpha3.AddToScheme(Scheme))
 	utilruntime.Must(v1alpha4.AddToScheme(Scheme))
-	utilruntime.Must(scheme.SetVersionPriority(v1alpha4.SchemeGroupVersion))
+	utilruntime.Must(v1alpha5.AddToScheme(Scheme))
+	utilruntime.Must(scheme.SetVersionPriority(v1alpha5.SchemeGroupVersion))
 }
@@ -37,3 +37,57 @@ func Convert_ignite_VMStatus_To_v1alpha2_VMStatus(in *ignite.VMStatus, out *VMSt
 
 	return nil
 }
+
+// Convert_ignite_KernelSpec_To_v1alpha2_KernelSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_KernelSpec_To_v1alpha2_KernelSpec(in *ignite.KernelSpec, out *KernelSpec, s conversion.Scope) error {
+	// HasInitrd doesn't exist in v1alpha2, it's dropped
+	return autoConvert_ignite_KernelSpec_To_v1alpha2_KernelSpec(in, out, s)
+}
+
+// Convert_ignite_VMKernelSpec_To_v1alpha2_VMKernelSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMKernelSpec_To_v1alpha2_VMKernelSpec(in *ignite.VMKernelSpec, out *VMKernelSpec, s conversion.Scope) error {
+	// HasInitrd and InitrdRef don't exist in v1alpha2, they're dropped
+	return autoConvert_ignite_VMKernelSpec_To_v1alpha2_VMKernelSpec(in, out, s)
+}
+
+// Convert_ignite_Volume_To_v1alpha2_Volume calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_Volume_To_v1alpha2_Volume(in *ignite.Volume, out *Volume, s conversion.Scope) error {
+	// Tmpfs and NBD volumes don't exist in v1alpha2, they're dropped
+	return autoConvert_ignite_Volume_To_v1alpha2_Volume(in, out, s)
+}
+
+// Convert_ignite_VMSpec_To_v1alpha2_VMSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMSpec_To_v1alpha2_VMSpec(in *ignite.VMSpec, out *VMSpec, s conversion.Scope) error {
+	// OverlaySizeLimit, MemoryHugepages, Backup, RestartPolicy, Sysctls, Env, Users, Runtime, NetworkPlugin, Firmware, Devices, NestedVirtualization and the autostart fields don't exist in v1alpha2, they're dropped
+	return autoConvert_ignite_VMSpec_To_v1alpha2_VMSpec(in, out, s)
+}
+
+// Convert_ignite_SSH_To_v1alpha2_SSH calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_SSH_To_v1alpha2_SSH(in *ignite.SSH, out *SSH, s conversion.Scope) error {
+	// AuthorizedKeys don't exist in v1alpha2, they're dropped
+	return autoConvert_ignite_SSH_To_v1alpha2_SSH(in, out, s)
+}
+
+// Convert_ignite_KernelStatus_To_v1alpha2_KernelStatus calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_KernelStatus_To_v1alpha2_KernelStatus(in *ignite.KernelStatus, out *KernelStatus, s conversion.Scope) error {
+	// Compression doesn't exist in v1alpha2, it's dropped
+	return autoConvert_ignite_KernelStatus_To_v1alpha2_KernelStatus(in, out, s)
+}
+
+// Convert_ignite_ImageStatus_To_v1alpha2_ImageStatus calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_ImageStatus_To_v1alpha2_ImageStatus(in *ignite.ImageStatus, out *ImageStatus, s conversion.Scope) error {
+	// Scan, SBOM and LazyRef don't exist in v1alpha2, they're dropped
+	return autoConvert_ignite_ImageStatus_To_v1alpha2_ImageStatus(in, out, s)
+}
+
+// Convert_ignite_VMStorageSpec_To_v1alpha2_VMStorageSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMStorageSpec_To_v1alpha2_VMStorageSpec(in *ignite.VMStorageSpec, out *VMStorageSpec, s conversion.Scope) error {
+	// ReadOnlyRoot and WritablePaths don't exist in v1alpha2, they're dropped
+	return autoConvert_ignite_VMStorageSpec_To_v1alpha2_VMStorageSpec(in, out, s)
+}
+
+// Convert_ignite_OCIImageSource_To_v1alpha2_OCIImageSource calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_OCIImageSource_To_v1alpha2_OCIImageSource(in *ignite.OCIImageSource, out *OCIImageSource, s conversion.Scope) error {
+	// Architecture and Format don't exist in v1alpha2, they're dropped
+	return autoConvert_ignite_OCIImageSource_To_v1alpha2_OCIImageSource(in, out, s)
+}
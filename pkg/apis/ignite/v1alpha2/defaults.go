@@ -2,6 +2,7 @@ package v1alpha2
 
 import (
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/arch"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/version"
 
@@ -55,7 +56,7 @@ func SetDefaults_VMKernelSpec(obj *VMKernelSpec) {
 	}
 
 	if len(obj.CmdLine) == 0 {
-		obj.CmdLine = constants.VM_DEFAULT_KERNEL_ARGS
+		obj.CmdLine = arch.Host.KernelArgs
 	}
 }
 
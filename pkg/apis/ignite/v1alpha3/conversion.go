@@ -9,3 +9,63 @@ import (
 func Convert_ignite_ConfigurationSpec_To_v1alpha3_ConfigurationSpec(in *ignite.ConfigurationSpec, out *ConfigurationSpec, s conversion.Scope) error {
 	return autoConvert_ignite_ConfigurationSpec_To_v1alpha3_ConfigurationSpec(in, out, s)
 }
+
+// Convert_ignite_KernelSpec_To_v1alpha3_KernelSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_KernelSpec_To_v1alpha3_KernelSpec(in *ignite.KernelSpec, out *KernelSpec, s conversion.Scope) error {
+	// HasInitrd doesn't exist in v1alpha3, it's dropped
+	return autoConvert_ignite_KernelSpec_To_v1alpha3_KernelSpec(in, out, s)
+}
+
+// Convert_ignite_VMKernelSpec_To_v1alpha3_VMKernelSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMKernelSpec_To_v1alpha3_VMKernelSpec(in *ignite.VMKernelSpec, out *VMKernelSpec, s conversion.Scope) error {
+	// HasInitrd and InitrdRef don't exist in v1alpha3, they're dropped
+	return autoConvert_ignite_VMKernelSpec_To_v1alpha3_VMKernelSpec(in, out, s)
+}
+
+// Convert_ignite_Volume_To_v1alpha3_Volume calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_Volume_To_v1alpha3_Volume(in *ignite.Volume, out *Volume, s conversion.Scope) error {
+	// Tmpfs and NBD volumes don't exist in v1alpha3, they're dropped
+	return autoConvert_ignite_Volume_To_v1alpha3_Volume(in, out, s)
+}
+
+// Convert_ignite_VMSpec_To_v1alpha3_VMSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMSpec_To_v1alpha3_VMSpec(in *ignite.VMSpec, out *VMSpec, s conversion.Scope) error {
+	// OverlaySizeLimit, MemoryHugepages, Backup, RestartPolicy, Sysctls, Env, Users, Runtime, NetworkPlugin, Firmware, Devices, NestedVirtualization and the autostart fields don't exist in v1alpha3, they're dropped
+	return autoConvert_ignite_VMSpec_To_v1alpha3_VMSpec(in, out, s)
+}
+
+// Convert_ignite_VMStatus_To_v1alpha3_VMStatus calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMStatus_To_v1alpha3_VMStatus(in *ignite.VMStatus, out *VMStatus, s conversion.Scope) error {
+	// Paused, Overlay, Conditions and the restart fields don't exist in v1alpha3, they're dropped
+	return autoConvert_ignite_VMStatus_To_v1alpha3_VMStatus(in, out, s)
+}
+
+// Convert_ignite_SSH_To_v1alpha3_SSH calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_SSH_To_v1alpha3_SSH(in *ignite.SSH, out *SSH, s conversion.Scope) error {
+	// AuthorizedKeys don't exist in v1alpha3, they're dropped
+	return autoConvert_ignite_SSH_To_v1alpha3_SSH(in, out, s)
+}
+
+// Convert_ignite_KernelStatus_To_v1alpha3_KernelStatus calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_KernelStatus_To_v1alpha3_KernelStatus(in *ignite.KernelStatus, out *KernelStatus, s conversion.Scope) error {
+	// Compression doesn't exist in v1alpha3, it's dropped
+	return autoConvert_ignite_KernelStatus_To_v1alpha3_KernelStatus(in, out, s)
+}
+
+// Convert_ignite_ImageStatus_To_v1alpha3_ImageStatus calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_ImageStatus_To_v1alpha3_ImageStatus(in *ignite.ImageStatus, out *ImageStatus, s conversion.Scope) error {
+	// Scan, SBOM and LazyRef don't exist in v1alpha3, they're dropped
+	return autoConvert_ignite_ImageStatus_To_v1alpha3_ImageStatus(in, out, s)
+}
+
+// Convert_ignite_VMStorageSpec_To_v1alpha3_VMStorageSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMStorageSpec_To_v1alpha3_VMStorageSpec(in *ignite.VMStorageSpec, out *VMStorageSpec, s conversion.Scope) error {
+	// ReadOnlyRoot and WritablePaths don't exist in v1alpha3, they're dropped
+	return autoConvert_ignite_VMStorageSpec_To_v1alpha3_VMStorageSpec(in, out, s)
+}
+
+// Convert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource(in *ignite.OCIImageSource, out *OCIImageSource, s conversion.Scope) error {
+	// Architecture and Format don't exist in v1alpha3, they're dropped
+	return autoConvert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource(in, out, s)
+}
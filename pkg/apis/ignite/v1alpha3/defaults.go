@@ -2,6 +2,7 @@ package v1alpha3
 
 import (
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/arch"
 	"github.com/weaveworks/ignite/pkg/constants"
 	igniteNetwork "github.com/weaveworks/ignite/pkg/network"
 	igniteRuntime "github.com/weaveworks/ignite/pkg/runtime"
@@ -57,7 +58,7 @@ func SetDefaults_VMKernelSpec(obj *VMKernelSpec) {
 	}
 
 	if len(obj.CmdLine) == 0 {
-		obj.CmdLine = constants.VM_DEFAULT_KERNEL_ARGS
+		obj.CmdLine = arch.Host.KernelArgs
 	}
 }
 
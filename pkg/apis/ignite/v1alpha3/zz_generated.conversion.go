@@ -84,11 +84,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.ImageStatus)(nil), (*ImageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_ImageStatus_To_v1alpha3_ImageStatus(a.(*ignite.ImageStatus), b.(*ImageStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Kernel)(nil), (*ignite.Kernel)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Kernel_To_ignite_Kernel(a.(*Kernel), b.(*ignite.Kernel), scope)
 	}); err != nil {
@@ -104,21 +99,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.KernelSpec)(nil), (*KernelSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_KernelSpec_To_v1alpha3_KernelSpec(a.(*ignite.KernelSpec), b.(*KernelSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*KernelStatus)(nil), (*ignite.KernelStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_KernelStatus_To_ignite_KernelStatus(a.(*KernelStatus), b.(*ignite.KernelStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.KernelStatus)(nil), (*KernelStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_KernelStatus_To_v1alpha3_KernelStatus(a.(*ignite.KernelStatus), b.(*KernelStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Network)(nil), (*ignite.Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Network_To_ignite_Network(a.(*Network), b.(*ignite.Network), scope)
 	}); err != nil {
@@ -134,11 +119,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.OCIImageSource)(nil), (*OCIImageSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource(a.(*ignite.OCIImageSource), b.(*OCIImageSource), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Pool)(nil), (*ignite.Pool)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Pool_To_ignite_Pool(a.(*Pool), b.(*ignite.Pool), scope)
 	}); err != nil {
@@ -194,11 +174,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.SSH)(nil), (*SSH)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_SSH_To_v1alpha3_SSH(a.(*ignite.SSH), b.(*SSH), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VM)(nil), (*ignite.VM)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VM_To_ignite_VM(a.(*VM), b.(*ignite.VM), scope)
 	}); err != nil {
@@ -224,11 +199,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.VMKernelSpec)(nil), (*VMKernelSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_VMKernelSpec_To_v1alpha3_VMKernelSpec(a.(*ignite.VMKernelSpec), b.(*VMKernelSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VMNetworkSpec)(nil), (*ignite.VMNetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VMNetworkSpec_To_ignite_VMNetworkSpec(a.(*VMNetworkSpec), b.(*ignite.VMNetworkSpec), scope)
 	}); err != nil {
@@ -254,41 +224,21 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.VMSpec)(nil), (*VMSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_VMSpec_To_v1alpha3_VMSpec(a.(*ignite.VMSpec), b.(*VMSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VMStatus)(nil), (*ignite.VMStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VMStatus_To_ignite_VMStatus(a.(*VMStatus), b.(*ignite.VMStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.VMStatus)(nil), (*VMStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_VMStatus_To_v1alpha3_VMStatus(a.(*ignite.VMStatus), b.(*VMStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VMStorageSpec)(nil), (*ignite.VMStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VMStorageSpec_To_ignite_VMStorageSpec(a.(*VMStorageSpec), b.(*ignite.VMStorageSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.VMStorageSpec)(nil), (*VMStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_VMStorageSpec_To_v1alpha3_VMStorageSpec(a.(*ignite.VMStorageSpec), b.(*VMStorageSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Volume)(nil), (*ignite.Volume)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Volume_To_ignite_Volume(a.(*Volume), b.(*ignite.Volume), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.Volume)(nil), (*Volume)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_Volume_To_v1alpha3_Volume(a.(*ignite.Volume), b.(*Volume), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VolumeMount)(nil), (*ignite.VolumeMount)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VolumeMount_To_ignite_VolumeMount(a.(*VolumeMount), b.(*ignite.VolumeMount), scope)
 	}); err != nil {
@@ -304,6 +254,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*ignite.ImageStatus)(nil), (*ImageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_ImageStatus_To_v1alpha3_ImageStatus(a.(*ignite.ImageStatus), b.(*ImageStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.KernelSpec)(nil), (*KernelSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_KernelSpec_To_v1alpha3_KernelSpec(a.(*ignite.KernelSpec), b.(*KernelSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.KernelStatus)(nil), (*KernelStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_KernelStatus_To_v1alpha3_KernelStatus(a.(*ignite.KernelStatus), b.(*KernelStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.OCIImageSource)(nil), (*OCIImageSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource(a.(*ignite.OCIImageSource), b.(*OCIImageSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.SSH)(nil), (*SSH)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_SSH_To_v1alpha3_SSH(a.(*ignite.SSH), b.(*SSH), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.VMKernelSpec)(nil), (*VMKernelSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMKernelSpec_To_v1alpha3_VMKernelSpec(a.(*ignite.VMKernelSpec), b.(*VMKernelSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.VMSpec)(nil), (*VMSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMSpec_To_v1alpha3_VMSpec(a.(*ignite.VMSpec), b.(*VMSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.VMStatus)(nil), (*VMStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMStatus_To_v1alpha3_VMStatus(a.(*ignite.VMStatus), b.(*VMStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.VMStorageSpec)(nil), (*VMStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMStorageSpec_To_v1alpha3_VMStorageSpec(a.(*ignite.VMStorageSpec), b.(*VMStorageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.Volume)(nil), (*Volume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_Volume_To_v1alpha3_Volume(a.(*ignite.Volume), b.(*Volume), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -378,6 +378,22 @@ func autoConvert_ignite_ConfigurationSpec_To_v1alpha3_ConfigurationSpec(in *igni
 	}
 	out.IDPrefix = in.IDPrefix
 	// WARNING: in.RegistryConfigDir requires manual conversion: does not exist in peer-type
+	// WARNING: in.Snapshotter requires manual conversion: does not exist in peer-type
+	// WARNING: in.LVM requires manual conversion: does not exist in peer-type
+	// WARNING: in.ZFS requires manual conversion: does not exist in peer-type
+	// WARNING: in.RBD requires manual conversion: does not exist in peer-type
+	// WARNING: in.ConsoleLog requires manual conversion: does not exist in peer-type
+	// WARNING: in.Events requires manual conversion: does not exist in peer-type
+	// WARNING: in.Audit requires manual conversion: does not exist in peer-type
+	// WARNING: in.GitOps requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageScan requires manual conversion: does not exist in peer-type
+	// WARNING: in.SBOM requires manual conversion: does not exist in peer-type
+	// WARNING: in.Policy requires manual conversion: does not exist in peer-type
+	// WARNING: in.Confinement requires manual conversion: does not exist in peer-type
+	// WARNING: in.Vault requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageImport requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootCache requires manual conversion: does not exist in peer-type
+	// WARNING: in.Emulation requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -473,14 +489,12 @@ func autoConvert_ignite_ImageStatus_To_v1alpha3_ImageStatus(in *ignite.ImageStat
 	if err := Convert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource(&in.OCISource, &out.OCISource, s); err != nil {
 		return err
 	}
+	// WARNING: in.Scan requires manual conversion: does not exist in peer-type
+	// WARNING: in.SBOM requires manual conversion: does not exist in peer-type
+	// WARNING: in.LazyRef requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_ImageStatus_To_v1alpha3_ImageStatus is an autogenerated conversion function.
-func Convert_ignite_ImageStatus_To_v1alpha3_ImageStatus(in *ignite.ImageStatus, out *ImageStatus, s conversion.Scope) error {
-	return autoConvert_ignite_ImageStatus_To_v1alpha3_ImageStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_Kernel_To_ignite_Kernel(in *Kernel, out *ignite.Kernel, s conversion.Scope) error {
 	out.TypeMeta = in.TypeMeta
 	out.ObjectMeta = in.ObjectMeta
@@ -527,14 +541,10 @@ func Convert_v1alpha3_KernelSpec_To_ignite_KernelSpec(in *KernelSpec, out *ignit
 
 func autoConvert_ignite_KernelSpec_To_v1alpha3_KernelSpec(in *ignite.KernelSpec, out *KernelSpec, s conversion.Scope) error {
 	out.OCI = in.OCI
+	// WARNING: in.HasInitrd requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_KernelSpec_To_v1alpha3_KernelSpec is an autogenerated conversion function.
-func Convert_ignite_KernelSpec_To_v1alpha3_KernelSpec(in *ignite.KernelSpec, out *KernelSpec, s conversion.Scope) error {
-	return autoConvert_ignite_KernelSpec_To_v1alpha3_KernelSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_KernelStatus_To_ignite_KernelStatus(in *KernelStatus, out *ignite.KernelStatus, s conversion.Scope) error {
 	out.Version = in.Version
 	if err := Convert_v1alpha3_OCIImageSource_To_ignite_OCIImageSource(&in.OCISource, &out.OCISource, s); err != nil {
@@ -553,14 +563,10 @@ func autoConvert_ignite_KernelStatus_To_v1alpha3_KernelStatus(in *ignite.KernelS
 	if err := Convert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource(&in.OCISource, &out.OCISource, s); err != nil {
 		return err
 	}
+	// WARNING: in.Compression requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_KernelStatus_To_v1alpha3_KernelStatus is an autogenerated conversion function.
-func Convert_ignite_KernelStatus_To_v1alpha3_KernelStatus(in *ignite.KernelStatus, out *KernelStatus, s conversion.Scope) error {
-	return autoConvert_ignite_KernelStatus_To_v1alpha3_KernelStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_Network_To_ignite_Network(in *Network, out *ignite.Network, s conversion.Scope) error {
 	out.Plugin = network.PluginName(in.Plugin)
 	out.IPAddresses = *(*v1alpha1.IPAddresses)(unsafe.Pointer(&in.IPAddresses))
@@ -597,14 +603,11 @@ func Convert_v1alpha3_OCIImageSource_To_ignite_OCIImageSource(in *OCIImageSource
 func autoConvert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource(in *ignite.OCIImageSource, out *OCIImageSource, s conversion.Scope) error {
 	out.ID = (*v1alpha1.OCIContentID)(unsafe.Pointer(in.ID))
 	out.Size = in.Size
+	// WARNING: in.Architecture requires manual conversion: does not exist in peer-type
+	// WARNING: in.Format requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource is an autogenerated conversion function.
-func Convert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource(in *ignite.OCIImageSource, out *OCIImageSource, s conversion.Scope) error {
-	return autoConvert_ignite_OCIImageSource_To_v1alpha3_OCIImageSource(in, out, s)
-}
-
 func autoConvert_v1alpha3_Pool_To_ignite_Pool(in *Pool, out *ignite.Pool, s conversion.Scope) error {
 	out.TypeMeta = in.TypeMeta
 	if err := Convert_v1alpha3_PoolSpec_To_ignite_PoolSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -747,14 +750,10 @@ func Convert_v1alpha3_SSH_To_ignite_SSH(in *SSH, out *ignite.SSH, s conversion.S
 func autoConvert_ignite_SSH_To_v1alpha3_SSH(in *ignite.SSH, out *SSH, s conversion.Scope) error {
 	out.Generate = in.Generate
 	out.PublicKey = in.PublicKey
+	// WARNING: in.AuthorizedKeys requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_SSH_To_v1alpha3_SSH is an autogenerated conversion function.
-func Convert_ignite_SSH_To_v1alpha3_SSH(in *ignite.SSH, out *SSH, s conversion.Scope) error {
-	return autoConvert_ignite_SSH_To_v1alpha3_SSH(in, out, s)
-}
-
 func autoConvert_v1alpha3_VM_To_ignite_VM(in *VM, out *ignite.VM, s conversion.Scope) error {
 	out.TypeMeta = in.TypeMeta
 	out.ObjectMeta = in.ObjectMeta
@@ -822,15 +821,12 @@ func Convert_v1alpha3_VMKernelSpec_To_ignite_VMKernelSpec(in *VMKernelSpec, out
 
 func autoConvert_ignite_VMKernelSpec_To_v1alpha3_VMKernelSpec(in *ignite.VMKernelSpec, out *VMKernelSpec, s conversion.Scope) error {
 	out.OCI = in.OCI
+	// WARNING: in.HasInitrd requires manual conversion: does not exist in peer-type
 	out.CmdLine = in.CmdLine
+	// WARNING: in.InitrdRef requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_VMKernelSpec_To_v1alpha3_VMKernelSpec is an autogenerated conversion function.
-func Convert_ignite_VMKernelSpec_To_v1alpha3_VMKernelSpec(in *ignite.VMKernelSpec, out *VMKernelSpec, s conversion.Scope) error {
-	return autoConvert_ignite_VMKernelSpec_To_v1alpha3_VMKernelSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_VMNetworkSpec_To_ignite_VMNetworkSpec(in *VMNetworkSpec, out *ignite.VMNetworkSpec, s conversion.Scope) error {
 	out.Ports = *(*v1alpha1.PortMappings)(unsafe.Pointer(&in.Ports))
 	return nil
@@ -891,7 +887,15 @@ func autoConvert_v1alpha3_VMSpec_To_ignite_VMSpec(in *VMSpec, out *ignite.VMSpec
 		return err
 	}
 	out.CopyFiles = *(*[]ignite.FileMapping)(unsafe.Pointer(&in.CopyFiles))
-	out.SSH = (*ignite.SSH)(unsafe.Pointer(in.SSH))
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(ignite.SSH)
+		if err := Convert_v1alpha3_SSH_To_ignite_SSH(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSH = nil
+	}
 	return nil
 }
 
@@ -913,6 +917,8 @@ func autoConvert_ignite_VMSpec_To_v1alpha3_VMSpec(in *ignite.VMSpec, out *VMSpec
 	out.CPUs = in.CPUs
 	out.Memory = in.Memory
 	out.DiskSize = in.DiskSize
+	// WARNING: in.OverlaySizeLimit requires manual conversion: does not exist in peer-type
+	// WARNING: in.MemoryHugepages requires manual conversion: does not exist in peer-type
 	if err := Convert_ignite_VMNetworkSpec_To_v1alpha3_VMNetworkSpec(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
@@ -920,15 +926,33 @@ func autoConvert_ignite_VMSpec_To_v1alpha3_VMSpec(in *ignite.VMSpec, out *VMSpec
 		return err
 	}
 	out.CopyFiles = *(*[]FileMapping)(unsafe.Pointer(&in.CopyFiles))
-	out.SSH = (*SSH)(unsafe.Pointer(in.SSH))
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(SSH)
+		if err := Convert_ignite_SSH_To_v1alpha3_SSH(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSH = nil
+	}
+	// WARNING: in.Backup requires manual conversion: does not exist in peer-type
+	// WARNING: in.Autostart requires manual conversion: does not exist in peer-type
+	// WARNING: in.AutostartAfter requires manual conversion: does not exist in peer-type
+	// WARNING: in.RestartPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.LivenessProbe requires manual conversion: does not exist in peer-type
+	// WARNING: in.Seccomp requires manual conversion: does not exist in peer-type
+	// WARNING: in.Sysctls requires manual conversion: does not exist in peer-type
+	// WARNING: in.Env requires manual conversion: does not exist in peer-type
+	// WARNING: in.Users requires manual conversion: does not exist in peer-type
+	// WARNING: in.Secrets requires manual conversion: does not exist in peer-type
+	// WARNING: in.Runtime requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkPlugin requires manual conversion: does not exist in peer-type
+	// WARNING: in.Firmware requires manual conversion: does not exist in peer-type
+	// WARNING: in.Devices requires manual conversion: does not exist in peer-type
+	// WARNING: in.NestedVirtualization requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_VMSpec_To_v1alpha3_VMSpec is an autogenerated conversion function.
-func Convert_ignite_VMSpec_To_v1alpha3_VMSpec(in *ignite.VMSpec, out *VMSpec, s conversion.Scope) error {
-	return autoConvert_ignite_VMSpec_To_v1alpha3_VMSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_VMStatus_To_ignite_VMStatus(in *VMStatus, out *ignite.VMStatus, s conversion.Scope) error {
 	out.Running = in.Running
 	out.Runtime = (*ignite.Runtime)(unsafe.Pointer(in.Runtime))
@@ -951,6 +975,7 @@ func Convert_v1alpha3_VMStatus_To_ignite_VMStatus(in *VMStatus, out *ignite.VMSt
 
 func autoConvert_ignite_VMStatus_To_v1alpha3_VMStatus(in *ignite.VMStatus, out *VMStatus, s conversion.Scope) error {
 	out.Running = in.Running
+	// WARNING: in.Paused requires manual conversion: does not exist in peer-type
 	out.Runtime = (*Runtime)(unsafe.Pointer(in.Runtime))
 	out.StartTime = (*libgitopspkgruntime.Time)(unsafe.Pointer(in.StartTime))
 	out.Network = (*Network)(unsafe.Pointer(in.Network))
@@ -961,16 +986,28 @@ func autoConvert_ignite_VMStatus_To_v1alpha3_VMStatus(in *ignite.VMStatus, out *
 		return err
 	}
 	out.IDPrefix = in.IDPrefix
+	// WARNING: in.Overlay requires manual conversion: does not exist in peer-type
+	// WARNING: in.Snapshotter requires manual conversion: does not exist in peer-type
+	// WARNING: in.RestartCount requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastExit requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	// WARNING: in.Seccomp requires manual conversion: does not exist in peer-type
+	// WARNING: in.Confinement requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_VMStatus_To_v1alpha3_VMStatus is an autogenerated conversion function.
-func Convert_ignite_VMStatus_To_v1alpha3_VMStatus(in *ignite.VMStatus, out *VMStatus, s conversion.Scope) error {
-	return autoConvert_ignite_VMStatus_To_v1alpha3_VMStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_VMStorageSpec_To_ignite_VMStorageSpec(in *VMStorageSpec, out *ignite.VMStorageSpec, s conversion.Scope) error {
-	out.Volumes = *(*[]ignite.Volume)(unsafe.Pointer(&in.Volumes))
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]ignite.Volume, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_Volume_To_ignite_Volume(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Volumes = nil
+	}
 	out.VolumeMounts = *(*[]ignite.VolumeMount)(unsafe.Pointer(&in.VolumeMounts))
 	return nil
 }
@@ -981,16 +1018,23 @@ func Convert_v1alpha3_VMStorageSpec_To_ignite_VMStorageSpec(in *VMStorageSpec, o
 }
 
 func autoConvert_ignite_VMStorageSpec_To_v1alpha3_VMStorageSpec(in *ignite.VMStorageSpec, out *VMStorageSpec, s conversion.Scope) error {
-	out.Volumes = *(*[]Volume)(unsafe.Pointer(&in.Volumes))
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]Volume, len(*in))
+		for i := range *in {
+			if err := Convert_ignite_Volume_To_v1alpha3_Volume(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Volumes = nil
+	}
 	out.VolumeMounts = *(*[]VolumeMount)(unsafe.Pointer(&in.VolumeMounts))
+	// WARNING: in.ReadOnlyRoot requires manual conversion: does not exist in peer-type
+	// WARNING: in.WritablePaths requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_VMStorageSpec_To_v1alpha3_VMStorageSpec is an autogenerated conversion function.
-func Convert_ignite_VMStorageSpec_To_v1alpha3_VMStorageSpec(in *ignite.VMStorageSpec, out *VMStorageSpec, s conversion.Scope) error {
-	return autoConvert_ignite_VMStorageSpec_To_v1alpha3_VMStorageSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_Volume_To_ignite_Volume(in *Volume, out *ignite.Volume, s conversion.Scope) error {
 	out.Name = in.Name
 	out.BlockDevice = (*ignite.BlockDeviceVolume)(unsafe.Pointer(in.BlockDevice))
@@ -1005,14 +1049,11 @@ func Convert_v1alpha3_Volume_To_ignite_Volume(in *Volume, out *ignite.Volume, s
 func autoConvert_ignite_Volume_To_v1alpha3_Volume(in *ignite.Volume, out *Volume, s conversion.Scope) error {
 	out.Name = in.Name
 	out.BlockDevice = (*BlockDeviceVolume)(unsafe.Pointer(in.BlockDevice))
+	// WARNING: in.Tmpfs requires manual conversion: does not exist in peer-type
+	// WARNING: in.NBD requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_Volume_To_v1alpha3_Volume is an autogenerated conversion function.
-func Convert_ignite_Volume_To_v1alpha3_Volume(in *ignite.Volume, out *Volume, s conversion.Scope) error {
-	return autoConvert_ignite_Volume_To_v1alpha3_Volume(in, out, s)
-}
-
 func autoConvert_v1alpha3_VolumeMount_To_ignite_VolumeMount(in *VolumeMount, out *ignite.VolumeMount, s conversion.Scope) error {
 	out.Name = in.Name
 	out.MountPath = in.MountPath
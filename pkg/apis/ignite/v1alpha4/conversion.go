@@ -0,0 +1,66 @@
+package v1alpha4
+
+import (
+	"github.com/weaveworks/ignite/pkg/apis/ignite"
+	"k8s.io/apimachinery/pkg/conversion"
+)
+
+// Convert_ignite_VMSpec_To_v1alpha4_VMSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMSpec_To_v1alpha4_VMSpec(in *ignite.VMSpec, out *VMSpec, s conversion.Scope) error {
+	// MemoryHugepages, LivenessProbe, Seccomp, Sysctls, Env, Users, Secrets, Runtime, NetworkPlugin, Firmware, Devices and NestedVirtualization don't exist in v1alpha4, they're dropped
+	return autoConvert_ignite_VMSpec_To_v1alpha4_VMSpec(in, out, s)
+}
+
+// Convert_ignite_VMKernelSpec_To_v1alpha4_VMKernelSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMKernelSpec_To_v1alpha4_VMKernelSpec(in *ignite.VMKernelSpec, out *VMKernelSpec, s conversion.Scope) error {
+	// InitrdRef doesn't exist in v1alpha4, it's dropped
+	return autoConvert_ignite_VMKernelSpec_To_v1alpha4_VMKernelSpec(in, out, s)
+}
+
+// Convert_ignite_VMStatus_To_v1alpha4_VMStatus calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMStatus_To_v1alpha4_VMStatus(in *ignite.VMStatus, out *VMStatus, s conversion.Scope) error {
+	// Conditions, Seccomp and Confinement don't exist in v1alpha4, they're dropped
+	return autoConvert_ignite_VMStatus_To_v1alpha4_VMStatus(in, out, s)
+}
+
+// Convert_ignite_SSH_To_v1alpha4_SSH calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_SSH_To_v1alpha4_SSH(in *ignite.SSH, out *SSH, s conversion.Scope) error {
+	// AuthorizedKeys don't exist in v1alpha4, they're dropped
+	return autoConvert_ignite_SSH_To_v1alpha4_SSH(in, out, s)
+}
+
+// Convert_ignite_VMStorageSpec_To_v1alpha4_VMStorageSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMStorageSpec_To_v1alpha4_VMStorageSpec(in *ignite.VMStorageSpec, out *VMStorageSpec, s conversion.Scope) error {
+	// ReadOnlyRoot and WritablePaths don't exist in v1alpha4, they're dropped
+	return autoConvert_ignite_VMStorageSpec_To_v1alpha4_VMStorageSpec(in, out, s)
+}
+
+// Convert_ignite_KernelStatus_To_v1alpha4_KernelStatus calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_KernelStatus_To_v1alpha4_KernelStatus(in *ignite.KernelStatus, out *KernelStatus, s conversion.Scope) error {
+	// Compression doesn't exist in v1alpha4, it's dropped
+	return autoConvert_ignite_KernelStatus_To_v1alpha4_KernelStatus(in, out, s)
+}
+
+// Convert_ignite_ImageStatus_To_v1alpha4_ImageStatus calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_ImageStatus_To_v1alpha4_ImageStatus(in *ignite.ImageStatus, out *ImageStatus, s conversion.Scope) error {
+	// Scan, SBOM and LazyRef don't exist in v1alpha4, they're dropped
+	return autoConvert_ignite_ImageStatus_To_v1alpha4_ImageStatus(in, out, s)
+}
+
+// Convert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource(in *ignite.OCIImageSource, out *OCIImageSource, s conversion.Scope) error {
+	// Architecture and Format don't exist in v1alpha4, they're dropped
+	return autoConvert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource(in, out, s)
+}
+
+// Convert_ignite_VMExitStatus_To_v1alpha4_VMExitStatus calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMExitStatus_To_v1alpha4_VMExitStatus(in *ignite.VMExitStatus, out *VMExitStatus, s conversion.Scope) error {
+	// Reason doesn't exist in v1alpha4, it's dropped
+	return autoConvert_ignite_VMExitStatus_To_v1alpha4_VMExitStatus(in, out, s)
+}
+
+// Convert_ignite_ConfigurationSpec_To_v1alpha4_ConfigurationSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_ConfigurationSpec_To_v1alpha4_ConfigurationSpec(in *ignite.ConfigurationSpec, out *ConfigurationSpec, s conversion.Scope) error {
+	// Events, Audit, GitOps, ImageScan, SBOM, Policy, Confinement, Vault, ImageImport, BootCache and Emulation don't exist in v1alpha4, they're dropped
+	return autoConvert_ignite_ConfigurationSpec_To_v1alpha4_ConfigurationSpec(in, out, s)
+}
@@ -2,6 +2,7 @@ package v1alpha4
 
 import (
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/arch"
 	"github.com/weaveworks/ignite/pkg/constants"
 	igniteNetwork "github.com/weaveworks/ignite/pkg/network"
 	igniteRuntime "github.com/weaveworks/ignite/pkg/runtime"
@@ -20,11 +21,11 @@ func SetDefaults_PoolSpec(obj *PoolSpec) {
 	}
 
 	if obj.DataSize == meta.EmptySize {
-		obj.AllocationSize = meta.NewSizeFromBytes(constants.POOL_DATA_SIZE_BYTES)
+		obj.DataSize = meta.NewSizeFromBytes(constants.POOL_DATA_SIZE_BYTES)
 	}
 
 	if obj.MetadataSize == meta.EmptySize {
-		obj.AllocationSize = calcMetadataDevSize(obj)
+		obj.MetadataSize = calcMetadataDevSize(obj)
 	}
 
 	if len(obj.MetadataPath) == 0 {
@@ -57,7 +58,7 @@ func SetDefaults_VMKernelSpec(obj *VMKernelSpec) {
 	}
 
 	if len(obj.CmdLine) == 0 {
-		obj.CmdLine = constants.VM_DEFAULT_KERNEL_ARGS
+		obj.CmdLine = arch.Host.KernelArgs
 	}
 }
 
@@ -1,16 +1,21 @@
 package v1alpha4
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	igniteNetwork "github.com/weaveworks/ignite/pkg/network"
 	igniteRuntime "github.com/weaveworks/ignite/pkg/runtime"
+	igniteSnapshotter "github.com/weaveworks/ignite/pkg/snapshotter"
 	"github.com/weaveworks/libgitops/pkg/runtime"
 )
 
 const (
-	KindImage  runtime.Kind = "Image"
-	KindKernel runtime.Kind = "Kernel"
-	KindVM     runtime.Kind = "VM"
+	KindImage      runtime.Kind = "Image"
+	KindKernel     runtime.Kind = "Kernel"
+	KindVM         runtime.Kind = "VM"
+	KindVMTemplate runtime.Kind = "VMTemplate"
+	KindPool       runtime.Kind = "Pool"
 )
 
 // Image represents a cached OCI image ready to be used with Ignite
@@ -154,6 +159,10 @@ type VMSpec struct {
 	CPUs     uint64        `json:"cpus"`
 	Memory   meta.Size     `json:"memory"`
 	DiskSize meta.Size     `json:"diskSize"`
+	// OverlaySizeLimit caps how much host disk space the VM's writable overlay
+	// may allocate, independently of DiskSize. Writes beyond the limit fail
+	// inside the VM. Unset means the overlay may grow up to DiskSize.
+	OverlaySizeLimit meta.Size `json:"overlaySizeLimit,omitempty"`
 	// TODO: Implement working omitempty without pointers for the following entries
 	// Currently both will show in the JSON output as empty arrays. Making them
 	// pointers requires plenty of nil checks (as their contents are accessed directly)
@@ -170,6 +179,54 @@ type VMSpec struct {
 	// If SSH.PublicKey is set, this struct will marshal as a string using that path
 	// If SSH.Generate is set, this struct will marshal as a bool => true
 	SSH *SSH `json:"ssh,omitempty"`
+	// Backup defines a policy for periodic backups of the VM's disk, run by ignited
+	// nil here means the VM isn't backed up
+	Backup *VMBackupSpec `json:"backup,omitempty"`
+	// Autostart makes the VM start automatically when the host boots, either
+	// by ignited when it starts up, or by "ignite vm autostart"
+	Autostart bool `json:"autostart,omitempty"`
+	// AutostartAfter lists the names or IDs of the VMs that need to be running before
+	// the VM is autostarted. They're started first, even if they don't set autostart.
+	AutostartAfter []string `json:"autostartAfter,omitempty"`
+	// RestartPolicy determines whether ignited restarts the VM when it stops without
+	// being stopped by ignite, e.g. when it crashes or shuts itself down
+	// Default: unset, which means never
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+}
+
+// RestartPolicy determines when a stopped VM is restarted by ignited
+type RestartPolicy string
+
+const (
+	// RestartPolicyAlways restarts the VM whenever it stops
+	RestartPolicyAlways RestartPolicy = "always"
+	// RestartPolicyOnFailure restarts the VM if Firecracker exited with a non-zero exit code
+	RestartPolicyOnFailure RestartPolicy = "on-failure"
+	// RestartPolicyNever never restarts the VM
+	RestartPolicyNever RestartPolicy = "never"
+)
+
+// VMTemplate is a reusable VM configuration. VMs created from a template
+// use its spec as their base configuration, which can be overridden per VM.
+// These files are stored in /var/lib/firecracker/vmtemplate/{template-id}/metadata.json
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type VMTemplate struct {
+	runtime.TypeMeta   `json:",inline"`
+	runtime.ObjectMeta `json:"metadata"`
+
+	Spec VMSpec `json:"spec"`
+}
+
+// VMBackupSpec defines when the disk of a VM is backed up, where to, and how many backups to keep
+type VMBackupSpec struct {
+	// Schedule is a cron expression in the "minute hour day-of-month month day-of-week" format
+	Schedule string `json:"schedule"`
+	// Retention is the number of backups to keep, older backups are removed
+	// Default: unset, all backups are kept
+	Retention uint64 `json:"retention,omitempty"`
+	// Destination is a local directory or an s3://bucket[/prefix] URL,
+	// the backups of the VM are stored in a subdirectory named by its UID
+	Destination string `json:"destination"`
 }
 
 type VMImageSpec struct {
@@ -201,6 +258,8 @@ type VMStorageSpec struct {
 type Volume struct {
 	Name        string             `json:"name"`
 	BlockDevice *BlockDeviceVolume `json:"blockDevice,omitempty"`
+	Tmpfs       *TmpfsVolume       `json:"tmpfs,omitempty"`
+	NBD         *NBDVolume         `json:"nbd,omitempty"`
 }
 
 // BlockDeviceVolume defines a block device on the host
@@ -208,6 +267,22 @@ type BlockDeviceVolume struct {
 	Path string `json:"path"`
 }
 
+// TmpfsVolume defines a RAM-backed scratch volume inside the VM.
+// The volume is created empty when the VM boots and its contents
+// are discarded when the VM stops, nothing is written to the host disk.
+type TmpfsVolume struct {
+	// Size limits the size of the tmpfs, it counts against the VM's memory
+	// Default: unset, the guest kernel default (half of the VM's memory) is used
+	Size meta.Size `json:"size,omitempty"`
+}
+
+// NBDVolume defines a remote disk served over the network block device protocol.
+// It's connected on the host when the VM starts, and reconnected if the connection drops.
+type NBDVolume struct {
+	// URL of the export, in the nbd://host[:port]/export format
+	URL string `json:"url"`
+}
+
 // VolumeMount defines the mount point for a named volume inside a VM
 type VolumeMount struct {
 	Name      string `json:"name"`
@@ -243,13 +318,40 @@ type Network struct {
 
 // VMStatus defines the status of a VM
 type VMStatus struct {
-	Running   bool           `json:"running"`
-	Runtime   *Runtime       `json:"runtime,omitempty"`
-	StartTime *runtime.Time  `json:"startTime,omitempty"`
-	Network   *Network       `json:"network,omitempty"`
-	Image     OCIImageSource `json:"image"`
-	Kernel    OCIImageSource `json:"kernel"`
-	IDPrefix  string         `json:"idPrefix"`
+	Running     bool                   `json:"running"`
+	Paused      bool                   `json:"paused,omitempty"`
+	Runtime     *Runtime               `json:"runtime,omitempty"`
+	StartTime   *runtime.Time          `json:"startTime,omitempty"`
+	Network     *Network               `json:"network,omitempty"`
+	Image       OCIImageSource         `json:"image"`
+	Kernel      OCIImageSource         `json:"kernel"`
+	IDPrefix    string                 `json:"idPrefix"`
+	Overlay     *OverlayStatus         `json:"overlay,omitempty"`
+	Snapshotter igniteSnapshotter.Name `json:"snapshotter,omitempty"`
+	// RestartCount is the number of times the VM has been restarted by its restart policy
+	RestartCount uint64 `json:"restartCount,omitempty"`
+	// LastExit describes how the VM stopped the last time
+	LastExit *VMExitStatus `json:"lastExit,omitempty"`
+}
+
+// VMExitStatus describes how a VM stopped
+type VMExitStatus struct {
+	// Time is when the VM stopped
+	Time runtime.Time `json:"time"`
+	// ExitCode is the exit code of Firecracker, it's non-zero if the VM failed
+	ExitCode int `json:"exitCode"`
+	// Requested is set if the VM was stopped by ignite, e.g. by "ignite stop".
+	// The restart policy doesn't apply to requested stops.
+	Requested bool `json:"requested,omitempty"`
+}
+
+// OverlayStatus describes the host disk usage of the VM's writable overlay
+type OverlayStatus struct {
+	// Usage is the amount of host disk space allocated by the overlay
+	Usage meta.Size `json:"usage"`
+	// NearLimit is set when Usage has reached the warning threshold
+	// of the VM's overlay size limit
+	NearLimit bool `json:"nearLimit,omitempty"`
 }
 
 // Configuration represents the ignite runtime configuration.
@@ -268,4 +370,49 @@ type ConfigurationSpec struct {
 	VMDefaults        VMSpec                   `json:"vmDefaults,omitempty"`
 	IDPrefix          string                   `json:"idPrefix,omitempty"`
 	RegistryConfigDir string                   `json:"registryConfigDir,omitempty"`
+	Snapshotter       igniteSnapshotter.Name   `json:"snapshotter,omitempty"`
+	LVM               LVMConfiguration         `json:"lvm,omitempty"`
+	ZFS               ZFSConfiguration         `json:"zfs,omitempty"`
+	RBD               RBDConfiguration         `json:"rbd,omitempty"`
+	ConsoleLog        ConsoleLogConfiguration  `json:"consoleLog,omitempty"`
+}
+
+// LVMConfiguration configures where the lvm snapshotter allocates VM disks
+type LVMConfiguration struct {
+	// VolumeGroup is the LVM volume group the logical volumes are created in
+	VolumeGroup string `json:"volumeGroup,omitempty"`
+	// ThinPool optionally names a thin pool in the volume group. If set, images
+	// are imported as thin volumes, and VM disks are thin snapshots of them.
+	ThinPool string `json:"thinPool,omitempty"`
+}
+
+// ZFSConfiguration configures where the zfs snapshotter creates its volumes
+type ZFSConfiguration struct {
+	// Dataset is the parent dataset for the image zvols and the VM clones,
+	// for example "tank/ignite". Properties like compression are inherited from it.
+	Dataset string `json:"dataset,omitempty"`
+}
+
+// RBDConfiguration configures where the rbd snapshotter creates its Ceph RBD images
+type RBDConfiguration struct {
+	// Pool is the Ceph pool the base images and the VM images are created in
+	Pool string `json:"pool,omitempty"`
+	// User is the Ceph user to authenticate as, defaults to the Ceph default (admin)
+	User string `json:"user,omitempty"`
+}
+
+// ConsoleLogConfiguration configures how the console output of the VMs is recorded
+// to the console log in the VM directory, and how the console log is rotated
+type ConsoleLogConfiguration struct {
+	// MaxSize is the size at which the console log is rotated
+	// Default: 10 MB
+	MaxSize meta.Size `json:"maxSize,omitempty"`
+	// MaxAge is the age of the oldest line at which the console log is rotated
+	// Default: unset, the console log is only rotated by size
+	MaxAge metav1.Duration `json:"maxAge,omitempty"`
+	// MaxFiles is the number of rotated console logs kept, older ones are removed
+	// Default: 1
+	MaxFiles uint64 `json:"maxFiles,omitempty"`
+	// Compress gzips the rotated console logs, except the most recent one
+	Compress bool `json:"compress,omitempty"`
 }
@@ -12,6 +12,7 @@ import (
 	v1alpha1 "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	network "github.com/weaveworks/ignite/pkg/network"
 	pkgruntime "github.com/weaveworks/ignite/pkg/runtime"
+	snapshotter "github.com/weaveworks/ignite/pkg/snapshotter"
 	libgitopspkgruntime "github.com/weaveworks/libgitops/pkg/runtime"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -49,8 +50,13 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.ConfigurationSpec)(nil), (*ConfigurationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_ConfigurationSpec_To_v1alpha4_ConfigurationSpec(a.(*ignite.ConfigurationSpec), b.(*ConfigurationSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*ConsoleLogConfiguration)(nil), (*ignite.ConsoleLogConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ConsoleLogConfiguration_To_ignite_ConsoleLogConfiguration(a.(*ConsoleLogConfiguration), b.(*ignite.ConsoleLogConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ignite.ConsoleLogConfiguration)(nil), (*ConsoleLogConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_ConsoleLogConfiguration_To_v1alpha4_ConsoleLogConfiguration(a.(*ignite.ConsoleLogConfiguration), b.(*ConsoleLogConfiguration), scope)
 	}); err != nil {
 		return err
 	}
@@ -89,11 +95,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.ImageStatus)(nil), (*ImageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_ImageStatus_To_v1alpha4_ImageStatus(a.(*ignite.ImageStatus), b.(*ImageStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Kernel)(nil), (*ignite.Kernel)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_Kernel_To_ignite_Kernel(a.(*Kernel), b.(*ignite.Kernel), scope)
 	}); err != nil {
@@ -119,8 +120,23 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.KernelStatus)(nil), (*KernelStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_KernelStatus_To_v1alpha4_KernelStatus(a.(*ignite.KernelStatus), b.(*KernelStatus), scope)
+	if err := s.AddGeneratedConversionFunc((*LVMConfiguration)(nil), (*ignite.LVMConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_LVMConfiguration_To_ignite_LVMConfiguration(a.(*LVMConfiguration), b.(*ignite.LVMConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ignite.LVMConfiguration)(nil), (*LVMConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_LVMConfiguration_To_v1alpha4_LVMConfiguration(a.(*ignite.LVMConfiguration), b.(*LVMConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NBDVolume)(nil), (*ignite.NBDVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_NBDVolume_To_ignite_NBDVolume(a.(*NBDVolume), b.(*ignite.NBDVolume), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ignite.NBDVolume)(nil), (*NBDVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_NBDVolume_To_v1alpha4_NBDVolume(a.(*ignite.NBDVolume), b.(*NBDVolume), scope)
 	}); err != nil {
 		return err
 	}
@@ -139,8 +155,13 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.OCIImageSource)(nil), (*OCIImageSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource(a.(*ignite.OCIImageSource), b.(*OCIImageSource), scope)
+	if err := s.AddGeneratedConversionFunc((*OverlayStatus)(nil), (*ignite.OverlayStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_OverlayStatus_To_ignite_OverlayStatus(a.(*OverlayStatus), b.(*ignite.OverlayStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ignite.OverlayStatus)(nil), (*OverlayStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_OverlayStatus_To_v1alpha4_OverlayStatus(a.(*ignite.OverlayStatus), b.(*OverlayStatus), scope)
 	}); err != nil {
 		return err
 	}
@@ -184,6 +205,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RBDConfiguration)(nil), (*ignite.RBDConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_RBDConfiguration_To_ignite_RBDConfiguration(a.(*RBDConfiguration), b.(*ignite.RBDConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ignite.RBDConfiguration)(nil), (*RBDConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_RBDConfiguration_To_v1alpha4_RBDConfiguration(a.(*ignite.RBDConfiguration), b.(*RBDConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Runtime)(nil), (*ignite.Runtime)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_Runtime_To_ignite_Runtime(a.(*Runtime), b.(*ignite.Runtime), scope)
 	}); err != nil {
@@ -199,8 +230,13 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.SSH)(nil), (*SSH)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_SSH_To_v1alpha4_SSH(a.(*ignite.SSH), b.(*SSH), scope)
+	if err := s.AddGeneratedConversionFunc((*TmpfsVolume)(nil), (*ignite.TmpfsVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_TmpfsVolume_To_ignite_TmpfsVolume(a.(*TmpfsVolume), b.(*ignite.TmpfsVolume), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ignite.TmpfsVolume)(nil), (*TmpfsVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_TmpfsVolume_To_v1alpha4_TmpfsVolume(a.(*ignite.TmpfsVolume), b.(*TmpfsVolume), scope)
 	}); err != nil {
 		return err
 	}
@@ -214,6 +250,21 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*VMBackupSpec)(nil), (*ignite.VMBackupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_VMBackupSpec_To_ignite_VMBackupSpec(a.(*VMBackupSpec), b.(*ignite.VMBackupSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ignite.VMBackupSpec)(nil), (*VMBackupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMBackupSpec_To_v1alpha4_VMBackupSpec(a.(*ignite.VMBackupSpec), b.(*VMBackupSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VMExitStatus)(nil), (*ignite.VMExitStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_VMExitStatus_To_ignite_VMExitStatus(a.(*VMExitStatus), b.(*ignite.VMExitStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VMImageSpec)(nil), (*ignite.VMImageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_VMImageSpec_To_ignite_VMImageSpec(a.(*VMImageSpec), b.(*ignite.VMImageSpec), scope)
 	}); err != nil {
@@ -229,11 +280,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.VMKernelSpec)(nil), (*VMKernelSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_VMKernelSpec_To_v1alpha4_VMKernelSpec(a.(*ignite.VMKernelSpec), b.(*VMKernelSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VMNetworkSpec)(nil), (*ignite.VMNetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_VMNetworkSpec_To_ignite_VMNetworkSpec(a.(*VMNetworkSpec), b.(*ignite.VMNetworkSpec), scope)
 	}); err != nil {
@@ -259,28 +305,23 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.VMSpec)(nil), (*VMSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_VMSpec_To_v1alpha4_VMSpec(a.(*ignite.VMSpec), b.(*VMSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VMStatus)(nil), (*ignite.VMStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_VMStatus_To_ignite_VMStatus(a.(*VMStatus), b.(*ignite.VMStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.VMStatus)(nil), (*VMStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_VMStatus_To_v1alpha4_VMStatus(a.(*ignite.VMStatus), b.(*VMStatus), scope)
+	if err := s.AddGeneratedConversionFunc((*VMStorageSpec)(nil), (*ignite.VMStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_VMStorageSpec_To_ignite_VMStorageSpec(a.(*VMStorageSpec), b.(*ignite.VMStorageSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*VMStorageSpec)(nil), (*ignite.VMStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_VMStorageSpec_To_ignite_VMStorageSpec(a.(*VMStorageSpec), b.(*ignite.VMStorageSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*VMTemplate)(nil), (*ignite.VMTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_VMTemplate_To_ignite_VMTemplate(a.(*VMTemplate), b.(*ignite.VMTemplate), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*ignite.VMStorageSpec)(nil), (*VMStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_ignite_VMStorageSpec_To_v1alpha4_VMStorageSpec(a.(*ignite.VMStorageSpec), b.(*VMStorageSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*ignite.VMTemplate)(nil), (*VMTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMTemplate_To_v1alpha4_VMTemplate(a.(*ignite.VMTemplate), b.(*VMTemplate), scope)
 	}); err != nil {
 		return err
 	}
@@ -304,6 +345,66 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ZFSConfiguration)(nil), (*ignite.ZFSConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ZFSConfiguration_To_ignite_ZFSConfiguration(a.(*ZFSConfiguration), b.(*ignite.ZFSConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ignite.ZFSConfiguration)(nil), (*ZFSConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_ZFSConfiguration_To_v1alpha4_ZFSConfiguration(a.(*ignite.ZFSConfiguration), b.(*ZFSConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.ConfigurationSpec)(nil), (*ConfigurationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_ConfigurationSpec_To_v1alpha4_ConfigurationSpec(a.(*ignite.ConfigurationSpec), b.(*ConfigurationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.ImageStatus)(nil), (*ImageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_ImageStatus_To_v1alpha4_ImageStatus(a.(*ignite.ImageStatus), b.(*ImageStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.KernelStatus)(nil), (*KernelStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_KernelStatus_To_v1alpha4_KernelStatus(a.(*ignite.KernelStatus), b.(*KernelStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.OCIImageSource)(nil), (*OCIImageSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource(a.(*ignite.OCIImageSource), b.(*OCIImageSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.SSH)(nil), (*SSH)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_SSH_To_v1alpha4_SSH(a.(*ignite.SSH), b.(*SSH), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.VMExitStatus)(nil), (*VMExitStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMExitStatus_To_v1alpha4_VMExitStatus(a.(*ignite.VMExitStatus), b.(*VMExitStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.VMKernelSpec)(nil), (*VMKernelSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMKernelSpec_To_v1alpha4_VMKernelSpec(a.(*ignite.VMKernelSpec), b.(*VMKernelSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.VMSpec)(nil), (*VMSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMSpec_To_v1alpha4_VMSpec(a.(*ignite.VMSpec), b.(*VMSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.VMStatus)(nil), (*VMStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMStatus_To_v1alpha4_VMStatus(a.(*ignite.VMStatus), b.(*VMStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ignite.VMStorageSpec)(nil), (*VMStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ignite_VMStorageSpec_To_v1alpha4_VMStorageSpec(a.(*ignite.VMStorageSpec), b.(*VMStorageSpec), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -363,6 +464,19 @@ func autoConvert_v1alpha4_ConfigurationSpec_To_ignite_ConfigurationSpec(in *Conf
 	}
 	out.IDPrefix = in.IDPrefix
 	out.RegistryConfigDir = in.RegistryConfigDir
+	out.Snapshotter = snapshotter.Name(in.Snapshotter)
+	if err := Convert_v1alpha4_LVMConfiguration_To_ignite_LVMConfiguration(&in.LVM, &out.LVM, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_ZFSConfiguration_To_ignite_ZFSConfiguration(&in.ZFS, &out.ZFS, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_RBDConfiguration_To_ignite_RBDConfiguration(&in.RBD, &out.RBD, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_ConsoleLogConfiguration_To_ignite_ConsoleLogConfiguration(&in.ConsoleLog, &out.ConsoleLog, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -379,12 +493,57 @@ func autoConvert_ignite_ConfigurationSpec_To_v1alpha4_ConfigurationSpec(in *igni
 	}
 	out.IDPrefix = in.IDPrefix
 	out.RegistryConfigDir = in.RegistryConfigDir
+	out.Snapshotter = snapshotter.Name(in.Snapshotter)
+	if err := Convert_ignite_LVMConfiguration_To_v1alpha4_LVMConfiguration(&in.LVM, &out.LVM, s); err != nil {
+		return err
+	}
+	if err := Convert_ignite_ZFSConfiguration_To_v1alpha4_ZFSConfiguration(&in.ZFS, &out.ZFS, s); err != nil {
+		return err
+	}
+	if err := Convert_ignite_RBDConfiguration_To_v1alpha4_RBDConfiguration(&in.RBD, &out.RBD, s); err != nil {
+		return err
+	}
+	if err := Convert_ignite_ConsoleLogConfiguration_To_v1alpha4_ConsoleLogConfiguration(&in.ConsoleLog, &out.ConsoleLog, s); err != nil {
+		return err
+	}
+	// WARNING: in.Events requires manual conversion: does not exist in peer-type
+	// WARNING: in.Audit requires manual conversion: does not exist in peer-type
+	// WARNING: in.GitOps requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageScan requires manual conversion: does not exist in peer-type
+	// WARNING: in.SBOM requires manual conversion: does not exist in peer-type
+	// WARNING: in.Policy requires manual conversion: does not exist in peer-type
+	// WARNING: in.Confinement requires manual conversion: does not exist in peer-type
+	// WARNING: in.Vault requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageImport requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootCache requires manual conversion: does not exist in peer-type
+	// WARNING: in.Emulation requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1alpha4_ConsoleLogConfiguration_To_ignite_ConsoleLogConfiguration(in *ConsoleLogConfiguration, out *ignite.ConsoleLogConfiguration, s conversion.Scope) error {
+	out.MaxSize = in.MaxSize
+	out.MaxAge = in.MaxAge
+	out.MaxFiles = in.MaxFiles
+	out.Compress = in.Compress
+	return nil
+}
+
+// Convert_v1alpha4_ConsoleLogConfiguration_To_ignite_ConsoleLogConfiguration is an autogenerated conversion function.
+func Convert_v1alpha4_ConsoleLogConfiguration_To_ignite_ConsoleLogConfiguration(in *ConsoleLogConfiguration, out *ignite.ConsoleLogConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha4_ConsoleLogConfiguration_To_ignite_ConsoleLogConfiguration(in, out, s)
+}
+
+func autoConvert_ignite_ConsoleLogConfiguration_To_v1alpha4_ConsoleLogConfiguration(in *ignite.ConsoleLogConfiguration, out *ConsoleLogConfiguration, s conversion.Scope) error {
+	out.MaxSize = in.MaxSize
+	out.MaxAge = in.MaxAge
+	out.MaxFiles = in.MaxFiles
+	out.Compress = in.Compress
 	return nil
 }
 
-// Convert_ignite_ConfigurationSpec_To_v1alpha4_ConfigurationSpec is an autogenerated conversion function.
-func Convert_ignite_ConfigurationSpec_To_v1alpha4_ConfigurationSpec(in *ignite.ConfigurationSpec, out *ConfigurationSpec, s conversion.Scope) error {
-	return autoConvert_ignite_ConfigurationSpec_To_v1alpha4_ConfigurationSpec(in, out, s)
+// Convert_ignite_ConsoleLogConfiguration_To_v1alpha4_ConsoleLogConfiguration is an autogenerated conversion function.
+func Convert_ignite_ConsoleLogConfiguration_To_v1alpha4_ConsoleLogConfiguration(in *ignite.ConsoleLogConfiguration, out *ConsoleLogConfiguration, s conversion.Scope) error {
+	return autoConvert_ignite_ConsoleLogConfiguration_To_v1alpha4_ConsoleLogConfiguration(in, out, s)
 }
 
 func autoConvert_v1alpha4_FileMapping_To_ignite_FileMapping(in *FileMapping, out *ignite.FileMapping, s conversion.Scope) error {
@@ -479,14 +638,12 @@ func autoConvert_ignite_ImageStatus_To_v1alpha4_ImageStatus(in *ignite.ImageStat
 	if err := Convert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource(&in.OCISource, &out.OCISource, s); err != nil {
 		return err
 	}
+	// WARNING: in.Scan requires manual conversion: does not exist in peer-type
+	// WARNING: in.SBOM requires manual conversion: does not exist in peer-type
+	// WARNING: in.LazyRef requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_ImageStatus_To_v1alpha4_ImageStatus is an autogenerated conversion function.
-func Convert_ignite_ImageStatus_To_v1alpha4_ImageStatus(in *ignite.ImageStatus, out *ImageStatus, s conversion.Scope) error {
-	return autoConvert_ignite_ImageStatus_To_v1alpha4_ImageStatus(in, out, s)
-}
-
 func autoConvert_v1alpha4_Kernel_To_ignite_Kernel(in *Kernel, out *ignite.Kernel, s conversion.Scope) error {
 	out.TypeMeta = in.TypeMeta
 	out.ObjectMeta = in.ObjectMeta
@@ -561,12 +718,50 @@ func autoConvert_ignite_KernelStatus_To_v1alpha4_KernelStatus(in *ignite.KernelS
 	if err := Convert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource(&in.OCISource, &out.OCISource, s); err != nil {
 		return err
 	}
+	// WARNING: in.Compression requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1alpha4_LVMConfiguration_To_ignite_LVMConfiguration(in *LVMConfiguration, out *ignite.LVMConfiguration, s conversion.Scope) error {
+	out.VolumeGroup = in.VolumeGroup
+	out.ThinPool = in.ThinPool
+	return nil
+}
+
+// Convert_v1alpha4_LVMConfiguration_To_ignite_LVMConfiguration is an autogenerated conversion function.
+func Convert_v1alpha4_LVMConfiguration_To_ignite_LVMConfiguration(in *LVMConfiguration, out *ignite.LVMConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha4_LVMConfiguration_To_ignite_LVMConfiguration(in, out, s)
+}
+
+func autoConvert_ignite_LVMConfiguration_To_v1alpha4_LVMConfiguration(in *ignite.LVMConfiguration, out *LVMConfiguration, s conversion.Scope) error {
+	out.VolumeGroup = in.VolumeGroup
+	out.ThinPool = in.ThinPool
+	return nil
+}
+
+// Convert_ignite_LVMConfiguration_To_v1alpha4_LVMConfiguration is an autogenerated conversion function.
+func Convert_ignite_LVMConfiguration_To_v1alpha4_LVMConfiguration(in *ignite.LVMConfiguration, out *LVMConfiguration, s conversion.Scope) error {
+	return autoConvert_ignite_LVMConfiguration_To_v1alpha4_LVMConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha4_NBDVolume_To_ignite_NBDVolume(in *NBDVolume, out *ignite.NBDVolume, s conversion.Scope) error {
+	out.URL = in.URL
+	return nil
+}
+
+// Convert_v1alpha4_NBDVolume_To_ignite_NBDVolume is an autogenerated conversion function.
+func Convert_v1alpha4_NBDVolume_To_ignite_NBDVolume(in *NBDVolume, out *ignite.NBDVolume, s conversion.Scope) error {
+	return autoConvert_v1alpha4_NBDVolume_To_ignite_NBDVolume(in, out, s)
+}
+
+func autoConvert_ignite_NBDVolume_To_v1alpha4_NBDVolume(in *ignite.NBDVolume, out *NBDVolume, s conversion.Scope) error {
+	out.URL = in.URL
 	return nil
 }
 
-// Convert_ignite_KernelStatus_To_v1alpha4_KernelStatus is an autogenerated conversion function.
-func Convert_ignite_KernelStatus_To_v1alpha4_KernelStatus(in *ignite.KernelStatus, out *KernelStatus, s conversion.Scope) error {
-	return autoConvert_ignite_KernelStatus_To_v1alpha4_KernelStatus(in, out, s)
+// Convert_ignite_NBDVolume_To_v1alpha4_NBDVolume is an autogenerated conversion function.
+func Convert_ignite_NBDVolume_To_v1alpha4_NBDVolume(in *ignite.NBDVolume, out *NBDVolume, s conversion.Scope) error {
+	return autoConvert_ignite_NBDVolume_To_v1alpha4_NBDVolume(in, out, s)
 }
 
 func autoConvert_v1alpha4_Network_To_ignite_Network(in *Network, out *ignite.Network, s conversion.Scope) error {
@@ -605,12 +800,31 @@ func Convert_v1alpha4_OCIImageSource_To_ignite_OCIImageSource(in *OCIImageSource
 func autoConvert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource(in *ignite.OCIImageSource, out *OCIImageSource, s conversion.Scope) error {
 	out.ID = (*v1alpha1.OCIContentID)(unsafe.Pointer(in.ID))
 	out.Size = in.Size
+	// WARNING: in.Architecture requires manual conversion: does not exist in peer-type
+	// WARNING: in.Format requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1alpha4_OverlayStatus_To_ignite_OverlayStatus(in *OverlayStatus, out *ignite.OverlayStatus, s conversion.Scope) error {
+	out.Usage = in.Usage
+	out.NearLimit = in.NearLimit
+	return nil
+}
+
+// Convert_v1alpha4_OverlayStatus_To_ignite_OverlayStatus is an autogenerated conversion function.
+func Convert_v1alpha4_OverlayStatus_To_ignite_OverlayStatus(in *OverlayStatus, out *ignite.OverlayStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha4_OverlayStatus_To_ignite_OverlayStatus(in, out, s)
+}
+
+func autoConvert_ignite_OverlayStatus_To_v1alpha4_OverlayStatus(in *ignite.OverlayStatus, out *OverlayStatus, s conversion.Scope) error {
+	out.Usage = in.Usage
+	out.NearLimit = in.NearLimit
 	return nil
 }
 
-// Convert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource is an autogenerated conversion function.
-func Convert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource(in *ignite.OCIImageSource, out *OCIImageSource, s conversion.Scope) error {
-	return autoConvert_ignite_OCIImageSource_To_v1alpha4_OCIImageSource(in, out, s)
+// Convert_ignite_OverlayStatus_To_v1alpha4_OverlayStatus is an autogenerated conversion function.
+func Convert_ignite_OverlayStatus_To_v1alpha4_OverlayStatus(in *ignite.OverlayStatus, out *OverlayStatus, s conversion.Scope) error {
+	return autoConvert_ignite_OverlayStatus_To_v1alpha4_OverlayStatus(in, out, s)
 }
 
 func autoConvert_v1alpha4_Pool_To_ignite_Pool(in *Pool, out *ignite.Pool, s conversion.Scope) error {
@@ -719,6 +933,28 @@ func Convert_ignite_PoolStatus_To_v1alpha4_PoolStatus(in *ignite.PoolStatus, out
 	return autoConvert_ignite_PoolStatus_To_v1alpha4_PoolStatus(in, out, s)
 }
 
+func autoConvert_v1alpha4_RBDConfiguration_To_ignite_RBDConfiguration(in *RBDConfiguration, out *ignite.RBDConfiguration, s conversion.Scope) error {
+	out.Pool = in.Pool
+	out.User = in.User
+	return nil
+}
+
+// Convert_v1alpha4_RBDConfiguration_To_ignite_RBDConfiguration is an autogenerated conversion function.
+func Convert_v1alpha4_RBDConfiguration_To_ignite_RBDConfiguration(in *RBDConfiguration, out *ignite.RBDConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha4_RBDConfiguration_To_ignite_RBDConfiguration(in, out, s)
+}
+
+func autoConvert_ignite_RBDConfiguration_To_v1alpha4_RBDConfiguration(in *ignite.RBDConfiguration, out *RBDConfiguration, s conversion.Scope) error {
+	out.Pool = in.Pool
+	out.User = in.User
+	return nil
+}
+
+// Convert_ignite_RBDConfiguration_To_v1alpha4_RBDConfiguration is an autogenerated conversion function.
+func Convert_ignite_RBDConfiguration_To_v1alpha4_RBDConfiguration(in *ignite.RBDConfiguration, out *RBDConfiguration, s conversion.Scope) error {
+	return autoConvert_ignite_RBDConfiguration_To_v1alpha4_RBDConfiguration(in, out, s)
+}
+
 func autoConvert_v1alpha4_Runtime_To_ignite_Runtime(in *Runtime, out *ignite.Runtime, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Name = pkgruntime.Name(in.Name)
@@ -755,12 +991,28 @@ func Convert_v1alpha4_SSH_To_ignite_SSH(in *SSH, out *ignite.SSH, s conversion.S
 func autoConvert_ignite_SSH_To_v1alpha4_SSH(in *ignite.SSH, out *SSH, s conversion.Scope) error {
 	out.Generate = in.Generate
 	out.PublicKey = in.PublicKey
+	// WARNING: in.AuthorizedKeys requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1alpha4_TmpfsVolume_To_ignite_TmpfsVolume(in *TmpfsVolume, out *ignite.TmpfsVolume, s conversion.Scope) error {
+	out.Size = in.Size
+	return nil
+}
+
+// Convert_v1alpha4_TmpfsVolume_To_ignite_TmpfsVolume is an autogenerated conversion function.
+func Convert_v1alpha4_TmpfsVolume_To_ignite_TmpfsVolume(in *TmpfsVolume, out *ignite.TmpfsVolume, s conversion.Scope) error {
+	return autoConvert_v1alpha4_TmpfsVolume_To_ignite_TmpfsVolume(in, out, s)
+}
+
+func autoConvert_ignite_TmpfsVolume_To_v1alpha4_TmpfsVolume(in *ignite.TmpfsVolume, out *TmpfsVolume, s conversion.Scope) error {
+	out.Size = in.Size
 	return nil
 }
 
-// Convert_ignite_SSH_To_v1alpha4_SSH is an autogenerated conversion function.
-func Convert_ignite_SSH_To_v1alpha4_SSH(in *ignite.SSH, out *SSH, s conversion.Scope) error {
-	return autoConvert_ignite_SSH_To_v1alpha4_SSH(in, out, s)
+// Convert_ignite_TmpfsVolume_To_v1alpha4_TmpfsVolume is an autogenerated conversion function.
+func Convert_ignite_TmpfsVolume_To_v1alpha4_TmpfsVolume(in *ignite.TmpfsVolume, out *TmpfsVolume, s conversion.Scope) error {
+	return autoConvert_ignite_TmpfsVolume_To_v1alpha4_TmpfsVolume(in, out, s)
 }
 
 func autoConvert_v1alpha4_VM_To_ignite_VM(in *VM, out *ignite.VM, s conversion.Scope) error {
@@ -797,6 +1049,50 @@ func Convert_ignite_VM_To_v1alpha4_VM(in *ignite.VM, out *VM, s conversion.Scope
 	return autoConvert_ignite_VM_To_v1alpha4_VM(in, out, s)
 }
 
+func autoConvert_v1alpha4_VMBackupSpec_To_ignite_VMBackupSpec(in *VMBackupSpec, out *ignite.VMBackupSpec, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Retention = in.Retention
+	out.Destination = in.Destination
+	return nil
+}
+
+// Convert_v1alpha4_VMBackupSpec_To_ignite_VMBackupSpec is an autogenerated conversion function.
+func Convert_v1alpha4_VMBackupSpec_To_ignite_VMBackupSpec(in *VMBackupSpec, out *ignite.VMBackupSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha4_VMBackupSpec_To_ignite_VMBackupSpec(in, out, s)
+}
+
+func autoConvert_ignite_VMBackupSpec_To_v1alpha4_VMBackupSpec(in *ignite.VMBackupSpec, out *VMBackupSpec, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Retention = in.Retention
+	out.Destination = in.Destination
+	return nil
+}
+
+// Convert_ignite_VMBackupSpec_To_v1alpha4_VMBackupSpec is an autogenerated conversion function.
+func Convert_ignite_VMBackupSpec_To_v1alpha4_VMBackupSpec(in *ignite.VMBackupSpec, out *VMBackupSpec, s conversion.Scope) error {
+	return autoConvert_ignite_VMBackupSpec_To_v1alpha4_VMBackupSpec(in, out, s)
+}
+
+func autoConvert_v1alpha4_VMExitStatus_To_ignite_VMExitStatus(in *VMExitStatus, out *ignite.VMExitStatus, s conversion.Scope) error {
+	out.Time = in.Time
+	out.ExitCode = in.ExitCode
+	out.Requested = in.Requested
+	return nil
+}
+
+// Convert_v1alpha4_VMExitStatus_To_ignite_VMExitStatus is an autogenerated conversion function.
+func Convert_v1alpha4_VMExitStatus_To_ignite_VMExitStatus(in *VMExitStatus, out *ignite.VMExitStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha4_VMExitStatus_To_ignite_VMExitStatus(in, out, s)
+}
+
+func autoConvert_ignite_VMExitStatus_To_v1alpha4_VMExitStatus(in *ignite.VMExitStatus, out *VMExitStatus, s conversion.Scope) error {
+	out.Time = in.Time
+	out.ExitCode = in.ExitCode
+	out.Requested = in.Requested
+	// WARNING: in.Reason requires manual conversion: does not exist in peer-type
+	return nil
+}
+
 func autoConvert_v1alpha4_VMImageSpec_To_ignite_VMImageSpec(in *VMImageSpec, out *ignite.VMImageSpec, s conversion.Scope) error {
 	out.OCI = in.OCI
 	return nil
@@ -833,14 +1129,10 @@ func autoConvert_ignite_VMKernelSpec_To_v1alpha4_VMKernelSpec(in *ignite.VMKerne
 	out.OCI = in.OCI
 	out.HasInitrd = in.HasInitrd
 	out.CmdLine = in.CmdLine
+	// WARNING: in.InitrdRef requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_VMKernelSpec_To_v1alpha4_VMKernelSpec is an autogenerated conversion function.
-func Convert_ignite_VMKernelSpec_To_v1alpha4_VMKernelSpec(in *ignite.VMKernelSpec, out *VMKernelSpec, s conversion.Scope) error {
-	return autoConvert_ignite_VMKernelSpec_To_v1alpha4_VMKernelSpec(in, out, s)
-}
-
 func autoConvert_v1alpha4_VMNetworkSpec_To_ignite_VMNetworkSpec(in *VMNetworkSpec, out *ignite.VMNetworkSpec, s conversion.Scope) error {
 	out.Ports = *(*v1alpha1.PortMappings)(unsafe.Pointer(&in.Ports))
 	return nil
@@ -894,6 +1186,7 @@ func autoConvert_v1alpha4_VMSpec_To_ignite_VMSpec(in *VMSpec, out *ignite.VMSpec
 	out.CPUs = in.CPUs
 	out.Memory = in.Memory
 	out.DiskSize = in.DiskSize
+	out.OverlaySizeLimit = in.OverlaySizeLimit
 	if err := Convert_v1alpha4_VMNetworkSpec_To_ignite_VMNetworkSpec(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
@@ -901,7 +1194,19 @@ func autoConvert_v1alpha4_VMSpec_To_ignite_VMSpec(in *VMSpec, out *ignite.VMSpec
 		return err
 	}
 	out.CopyFiles = *(*[]ignite.FileMapping)(unsafe.Pointer(&in.CopyFiles))
-	out.SSH = (*ignite.SSH)(unsafe.Pointer(in.SSH))
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(ignite.SSH)
+		if err := Convert_v1alpha4_SSH_To_ignite_SSH(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSH = nil
+	}
+	out.Backup = (*ignite.VMBackupSpec)(unsafe.Pointer(in.Backup))
+	out.Autostart = in.Autostart
+	out.AutostartAfter = *(*[]string)(unsafe.Pointer(&in.AutostartAfter))
+	out.RestartPolicy = ignite.RestartPolicy(in.RestartPolicy)
 	return nil
 }
 
@@ -923,6 +1228,8 @@ func autoConvert_ignite_VMSpec_To_v1alpha4_VMSpec(in *ignite.VMSpec, out *VMSpec
 	out.CPUs = in.CPUs
 	out.Memory = in.Memory
 	out.DiskSize = in.DiskSize
+	out.OverlaySizeLimit = in.OverlaySizeLimit
+	// WARNING: in.MemoryHugepages requires manual conversion: does not exist in peer-type
 	if err := Convert_ignite_VMNetworkSpec_To_v1alpha4_VMNetworkSpec(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
@@ -930,17 +1237,36 @@ func autoConvert_ignite_VMSpec_To_v1alpha4_VMSpec(in *ignite.VMSpec, out *VMSpec
 		return err
 	}
 	out.CopyFiles = *(*[]FileMapping)(unsafe.Pointer(&in.CopyFiles))
-	out.SSH = (*SSH)(unsafe.Pointer(in.SSH))
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(SSH)
+		if err := Convert_ignite_SSH_To_v1alpha4_SSH(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSH = nil
+	}
+	out.Backup = (*VMBackupSpec)(unsafe.Pointer(in.Backup))
+	out.Autostart = in.Autostart
+	out.AutostartAfter = *(*[]string)(unsafe.Pointer(&in.AutostartAfter))
+	out.RestartPolicy = RestartPolicy(in.RestartPolicy)
+	// WARNING: in.LivenessProbe requires manual conversion: does not exist in peer-type
+	// WARNING: in.Seccomp requires manual conversion: does not exist in peer-type
+	// WARNING: in.Sysctls requires manual conversion: does not exist in peer-type
+	// WARNING: in.Env requires manual conversion: does not exist in peer-type
+	// WARNING: in.Users requires manual conversion: does not exist in peer-type
+	// WARNING: in.Secrets requires manual conversion: does not exist in peer-type
+	// WARNING: in.Runtime requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkPlugin requires manual conversion: does not exist in peer-type
+	// WARNING: in.Firmware requires manual conversion: does not exist in peer-type
+	// WARNING: in.Devices requires manual conversion: does not exist in peer-type
+	// WARNING: in.NestedVirtualization requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_VMSpec_To_v1alpha4_VMSpec is an autogenerated conversion function.
-func Convert_ignite_VMSpec_To_v1alpha4_VMSpec(in *ignite.VMSpec, out *VMSpec, s conversion.Scope) error {
-	return autoConvert_ignite_VMSpec_To_v1alpha4_VMSpec(in, out, s)
-}
-
 func autoConvert_v1alpha4_VMStatus_To_ignite_VMStatus(in *VMStatus, out *ignite.VMStatus, s conversion.Scope) error {
 	out.Running = in.Running
+	out.Paused = in.Paused
 	out.Runtime = (*ignite.Runtime)(unsafe.Pointer(in.Runtime))
 	out.StartTime = (*libgitopspkgruntime.Time)(unsafe.Pointer(in.StartTime))
 	out.Network = (*ignite.Network)(unsafe.Pointer(in.Network))
@@ -951,6 +1277,18 @@ func autoConvert_v1alpha4_VMStatus_To_ignite_VMStatus(in *VMStatus, out *ignite.
 		return err
 	}
 	out.IDPrefix = in.IDPrefix
+	out.Overlay = (*ignite.OverlayStatus)(unsafe.Pointer(in.Overlay))
+	out.Snapshotter = snapshotter.Name(in.Snapshotter)
+	out.RestartCount = in.RestartCount
+	if in.LastExit != nil {
+		in, out := &in.LastExit, &out.LastExit
+		*out = new(ignite.VMExitStatus)
+		if err := Convert_v1alpha4_VMExitStatus_To_ignite_VMExitStatus(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.LastExit = nil
+	}
 	return nil
 }
 
@@ -961,6 +1299,7 @@ func Convert_v1alpha4_VMStatus_To_ignite_VMStatus(in *VMStatus, out *ignite.VMSt
 
 func autoConvert_ignite_VMStatus_To_v1alpha4_VMStatus(in *ignite.VMStatus, out *VMStatus, s conversion.Scope) error {
 	out.Running = in.Running
+	out.Paused = in.Paused
 	out.Runtime = (*Runtime)(unsafe.Pointer(in.Runtime))
 	out.StartTime = (*libgitopspkgruntime.Time)(unsafe.Pointer(in.StartTime))
 	out.Network = (*Network)(unsafe.Pointer(in.Network))
@@ -971,14 +1310,24 @@ func autoConvert_ignite_VMStatus_To_v1alpha4_VMStatus(in *ignite.VMStatus, out *
 		return err
 	}
 	out.IDPrefix = in.IDPrefix
+	out.Overlay = (*OverlayStatus)(unsafe.Pointer(in.Overlay))
+	out.Snapshotter = snapshotter.Name(in.Snapshotter)
+	out.RestartCount = in.RestartCount
+	if in.LastExit != nil {
+		in, out := &in.LastExit, &out.LastExit
+		*out = new(VMExitStatus)
+		if err := Convert_ignite_VMExitStatus_To_v1alpha4_VMExitStatus(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.LastExit = nil
+	}
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	// WARNING: in.Seccomp requires manual conversion: does not exist in peer-type
+	// WARNING: in.Confinement requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_VMStatus_To_v1alpha4_VMStatus is an autogenerated conversion function.
-func Convert_ignite_VMStatus_To_v1alpha4_VMStatus(in *ignite.VMStatus, out *VMStatus, s conversion.Scope) error {
-	return autoConvert_ignite_VMStatus_To_v1alpha4_VMStatus(in, out, s)
-}
-
 func autoConvert_v1alpha4_VMStorageSpec_To_ignite_VMStorageSpec(in *VMStorageSpec, out *ignite.VMStorageSpec, s conversion.Scope) error {
 	out.Volumes = *(*[]ignite.Volume)(unsafe.Pointer(&in.Volumes))
 	out.VolumeMounts = *(*[]ignite.VolumeMount)(unsafe.Pointer(&in.VolumeMounts))
@@ -993,17 +1342,44 @@ func Convert_v1alpha4_VMStorageSpec_To_ignite_VMStorageSpec(in *VMStorageSpec, o
 func autoConvert_ignite_VMStorageSpec_To_v1alpha4_VMStorageSpec(in *ignite.VMStorageSpec, out *VMStorageSpec, s conversion.Scope) error {
 	out.Volumes = *(*[]Volume)(unsafe.Pointer(&in.Volumes))
 	out.VolumeMounts = *(*[]VolumeMount)(unsafe.Pointer(&in.VolumeMounts))
+	// WARNING: in.ReadOnlyRoot requires manual conversion: does not exist in peer-type
+	// WARNING: in.WritablePaths requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_ignite_VMStorageSpec_To_v1alpha4_VMStorageSpec is an autogenerated conversion function.
-func Convert_ignite_VMStorageSpec_To_v1alpha4_VMStorageSpec(in *ignite.VMStorageSpec, out *VMStorageSpec, s conversion.Scope) error {
-	return autoConvert_ignite_VMStorageSpec_To_v1alpha4_VMStorageSpec(in, out, s)
+func autoConvert_v1alpha4_VMTemplate_To_ignite_VMTemplate(in *VMTemplate, out *ignite.VMTemplate, s conversion.Scope) error {
+	out.TypeMeta = in.TypeMeta
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha4_VMSpec_To_ignite_VMSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha4_VMTemplate_To_ignite_VMTemplate is an autogenerated conversion function.
+func Convert_v1alpha4_VMTemplate_To_ignite_VMTemplate(in *VMTemplate, out *ignite.VMTemplate, s conversion.Scope) error {
+	return autoConvert_v1alpha4_VMTemplate_To_ignite_VMTemplate(in, out, s)
+}
+
+func autoConvert_ignite_VMTemplate_To_v1alpha4_VMTemplate(in *ignite.VMTemplate, out *VMTemplate, s conversion.Scope) error {
+	out.TypeMeta = in.TypeMeta
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_ignite_VMSpec_To_v1alpha4_VMSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_ignite_VMTemplate_To_v1alpha4_VMTemplate is an autogenerated conversion function.
+func Convert_ignite_VMTemplate_To_v1alpha4_VMTemplate(in *ignite.VMTemplate, out *VMTemplate, s conversion.Scope) error {
+	return autoConvert_ignite_VMTemplate_To_v1alpha4_VMTemplate(in, out, s)
 }
 
 func autoConvert_v1alpha4_Volume_To_ignite_Volume(in *Volume, out *ignite.Volume, s conversion.Scope) error {
 	out.Name = in.Name
 	out.BlockDevice = (*ignite.BlockDeviceVolume)(unsafe.Pointer(in.BlockDevice))
+	out.Tmpfs = (*ignite.TmpfsVolume)(unsafe.Pointer(in.Tmpfs))
+	out.NBD = (*ignite.NBDVolume)(unsafe.Pointer(in.NBD))
 	return nil
 }
 
@@ -1015,6 +1391,8 @@ func Convert_v1alpha4_Volume_To_ignite_Volume(in *Volume, out *ignite.Volume, s
 func autoConvert_ignite_Volume_To_v1alpha4_Volume(in *ignite.Volume, out *Volume, s conversion.Scope) error {
 	out.Name = in.Name
 	out.BlockDevice = (*BlockDeviceVolume)(unsafe.Pointer(in.BlockDevice))
+	out.Tmpfs = (*TmpfsVolume)(unsafe.Pointer(in.Tmpfs))
+	out.NBD = (*NBDVolume)(unsafe.Pointer(in.NBD))
 	return nil
 }
 
@@ -1044,3 +1422,23 @@ func autoConvert_ignite_VolumeMount_To_v1alpha4_VolumeMount(in *ignite.VolumeMou
 func Convert_ignite_VolumeMount_To_v1alpha4_VolumeMount(in *ignite.VolumeMount, out *VolumeMount, s conversion.Scope) error {
 	return autoConvert_ignite_VolumeMount_To_v1alpha4_VolumeMount(in, out, s)
 }
+
+func autoConvert_v1alpha4_ZFSConfiguration_To_ignite_ZFSConfiguration(in *ZFSConfiguration, out *ignite.ZFSConfiguration, s conversion.Scope) error {
+	out.Dataset = in.Dataset
+	return nil
+}
+
+// Convert_v1alpha4_ZFSConfiguration_To_ignite_ZFSConfiguration is an autogenerated conversion function.
+func Convert_v1alpha4_ZFSConfiguration_To_ignite_ZFSConfiguration(in *ZFSConfiguration, out *ignite.ZFSConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha4_ZFSConfiguration_To_ignite_ZFSConfiguration(in, out, s)
+}
+
+func autoConvert_ignite_ZFSConfiguration_To_v1alpha4_ZFSConfiguration(in *ignite.ZFSConfiguration, out *ZFSConfiguration, s conversion.Scope) error {
+	out.Dataset = in.Dataset
+	return nil
+}
+
+// Convert_ignite_ZFSConfiguration_To_v1alpha4_ZFSConfiguration is an autogenerated conversion function.
+func Convert_ignite_ZFSConfiguration_To_v1alpha4_ZFSConfiguration(in *ignite.ZFSConfiguration, out *ZFSConfiguration, s conversion.Scope) error {
+	return autoConvert_ignite_ZFSConfiguration_To_v1alpha4_ZFSConfiguration(in, out, s)
+}
@@ -60,6 +60,10 @@ func (in *Configuration) DeepCopyObject() runtime.Object {
 func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	*out = *in
 	in.VMDefaults.DeepCopyInto(&out.VMDefaults)
+	out.LVM = in.LVM
+	out.ZFS = in.ZFS
+	out.RBD = in.RBD
+	out.ConsoleLog = in.ConsoleLog
 	return
 }
 
@@ -73,6 +77,24 @@ func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleLogConfiguration) DeepCopyInto(out *ConsoleLogConfiguration) {
+	*out = *in
+	out.MaxSize = in.MaxSize
+	out.MaxAge = in.MaxAge
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleLogConfiguration.
+func (in *ConsoleLogConfiguration) DeepCopy() *ConsoleLogConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleLogConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FileMapping) DeepCopyInto(out *FileMapping) {
 	*out = *in
@@ -213,6 +235,38 @@ func (in *KernelStatus) DeepCopy() *KernelStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMConfiguration) DeepCopyInto(out *LVMConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMConfiguration.
+func (in *LVMConfiguration) DeepCopy() *LVMConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NBDVolume) DeepCopyInto(out *NBDVolume) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NBDVolume.
+func (in *NBDVolume) DeepCopy() *NBDVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(NBDVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Network) DeepCopyInto(out *Network) {
 	*out = *in
@@ -262,6 +316,23 @@ func (in *OCIImageSource) DeepCopy() *OCIImageSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverlayStatus) DeepCopyInto(out *OverlayStatus) {
+	*out = *in
+	out.Usage = in.Usage
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverlayStatus.
+func (in *OverlayStatus) DeepCopy() *OverlayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OverlayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pool) DeepCopyInto(out *Pool) {
 	*out = *in
@@ -353,6 +424,22 @@ func (in *PoolStatus) DeepCopy() *PoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBDConfiguration) DeepCopyInto(out *RBDConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBDConfiguration.
+func (in *RBDConfiguration) DeepCopy() *RBDConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RBDConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Runtime) DeepCopyInto(out *Runtime) {
 	*out = *in
@@ -385,6 +472,23 @@ func (in *SSH) DeepCopy() *SSH {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TmpfsVolume) DeepCopyInto(out *TmpfsVolume) {
+	*out = *in
+	out.Size = in.Size
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TmpfsVolume.
+func (in *TmpfsVolume) DeepCopy() *TmpfsVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(TmpfsVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VM) DeepCopyInto(out *VM) {
 	*out = *in
@@ -413,6 +517,39 @@ func (in *VM) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMBackupSpec) DeepCopyInto(out *VMBackupSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMBackupSpec.
+func (in *VMBackupSpec) DeepCopy() *VMBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMExitStatus) DeepCopyInto(out *VMExitStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMExitStatus.
+func (in *VMExitStatus) DeepCopy() *VMExitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMExitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMImageSpec) DeepCopyInto(out *VMImageSpec) {
 	*out = *in
@@ -495,6 +632,7 @@ func (in *VMSpec) DeepCopyInto(out *VMSpec) {
 	out.Kernel = in.Kernel
 	out.Memory = in.Memory
 	out.DiskSize = in.DiskSize
+	out.OverlaySizeLimit = in.OverlaySizeLimit
 	in.Network.DeepCopyInto(&out.Network)
 	in.Storage.DeepCopyInto(&out.Storage)
 	if in.CopyFiles != nil {
@@ -507,6 +645,16 @@ func (in *VMSpec) DeepCopyInto(out *VMSpec) {
 		*out = new(SSH)
 		**out = **in
 	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(VMBackupSpec)
+		**out = **in
+	}
+	if in.AutostartAfter != nil {
+		in, out := &in.AutostartAfter, &out.AutostartAfter
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -540,6 +688,16 @@ func (in *VMStatus) DeepCopyInto(out *VMStatus) {
 	}
 	in.Image.DeepCopyInto(&out.Image)
 	in.Kernel.DeepCopyInto(&out.Kernel)
+	if in.Overlay != nil {
+		in, out := &in.Overlay, &out.Overlay
+		*out = new(OverlayStatus)
+		**out = **in
+	}
+	if in.LastExit != nil {
+		in, out := &in.LastExit, &out.LastExit
+		*out = new(VMExitStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -581,6 +739,33 @@ func (in *VMStorageSpec) DeepCopy() *VMStorageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMTemplate) DeepCopyInto(out *VMTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMTemplate.
+func (in *VMTemplate) DeepCopy() *VMTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VMTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Volume) DeepCopyInto(out *Volume) {
 	*out = *in
@@ -589,6 +774,16 @@ func (in *Volume) DeepCopyInto(out *Volume) {
 		*out = new(BlockDeviceVolume)
 		**out = **in
 	}
+	if in.Tmpfs != nil {
+		in, out := &in.Tmpfs, &out.Tmpfs
+		*out = new(TmpfsVolume)
+		**out = **in
+	}
+	if in.NBD != nil {
+		in, out := &in.NBD, &out.NBD
+		*out = new(NBDVolume)
+		**out = **in
+	}
 	return
 }
 
@@ -617,3 +812,19 @@ func (in *VolumeMount) DeepCopy() *VolumeMount {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZFSConfiguration) DeepCopyInto(out *ZFSConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZFSConfiguration.
+func (in *ZFSConfiguration) DeepCopy() *ZFSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ZFSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -16,6 +16,7 @@ func RegisterDefaults(scheme *runtime.Scheme) error {
 	scheme.AddTypeDefaultingFunc(&Configuration{}, func(obj interface{}) { SetObjectDefaults_Configuration(obj.(*Configuration)) })
 	scheme.AddTypeDefaultingFunc(&Pool{}, func(obj interface{}) { SetObjectDefaults_Pool(obj.(*Pool)) })
 	scheme.AddTypeDefaultingFunc(&VM{}, func(obj interface{}) { SetObjectDefaults_VM(obj.(*VM)) })
+	scheme.AddTypeDefaultingFunc(&VMTemplate{}, func(obj interface{}) { SetObjectDefaults_VMTemplate(obj.(*VMTemplate)) })
 	return nil
 }
 
@@ -36,3 +37,9 @@ func SetObjectDefaults_VM(in *VM) {
 	SetDefaults_VMKernelSpec(&in.Spec.Kernel)
 	SetDefaults_VMStatus(&in.Status)
 }
+
+func SetObjectDefaults_VMTemplate(in *VMTemplate) {
+	SetDefaults_VMSpec(&in.Spec)
+	SetDefaults_VMSandboxSpec(&in.Spec.Sandbox)
+	SetDefaults_VMKernelSpec(&in.Spec.Kernel)
+}
@@ -0,0 +1,40 @@
+package v1alpha5
+
+import (
+	"github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"k8s.io/apimachinery/pkg/conversion"
+)
+
+// Convert_ignite_VMSpec_To_v1alpha5_VMSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_ignite_VMSpec_To_v1alpha5_VMSpec(in *ignite.VMSpec, out *VMSpec, s conversion.Scope) error {
+	if err := autoConvert_ignite_VMSpec_To_v1alpha5_VMSpec(in, out, s); err != nil {
+		return err
+	}
+
+	// MemoryHugepages is part of the memory spec in v1alpha5
+	out.Memory.Hugepages = HugepageSize(in.MemoryHugepages)
+	return nil
+}
+
+// Convert_v1alpha5_VMSpec_To_ignite_VMSpec calls the autogenerated conversion function along with custom conversion logic
+func Convert_v1alpha5_VMSpec_To_ignite_VMSpec(in *VMSpec, out *ignite.VMSpec, s conversion.Scope) error {
+	if err := autoConvert_v1alpha5_VMSpec_To_ignite_VMSpec(in, out, s); err != nil {
+		return err
+	}
+
+	out.MemoryHugepages = ignite.HugepageSize(in.Memory.Hugepages)
+	return nil
+}
+
+// Convert_v1alpha5_VMMemorySpec_To_v1alpha1_Size converts the memory size, the hugepages are converted with the VMSpec
+func Convert_v1alpha5_VMMemorySpec_To_v1alpha1_Size(in *VMMemorySpec, out *meta.Size, s conversion.Scope) error {
+	*out = in.Size
+	return nil
+}
+
+// Convert_v1alpha1_Size_To_v1alpha5_VMMemorySpec converts the memory size, the hugepages are converted with the VMSpec
+func Convert_v1alpha1_Size_To_v1alpha5_VMMemorySpec(in *meta.Size, out *VMMemorySpec, s conversion.Scope) error {
+	out.Size = *in
+	return nil
+}
@@ -0,0 +1,5 @@
+// +k8s:deepcopy-gen=package
+// +k8s:defaulter-gen=TypeMeta
+// +k8s:openapi-gen=true
+// +k8s:conversion-gen=github.com/weaveworks/ignite/pkg/apis/ignite
+package v1alpha5
@@ -0,0 +1,100 @@
+package v1alpha5
+
+import (
+	"encoding/json"
+
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+// In this package custom marshal/unmarshal functions are registered
+
+func (s *SSH) MarshalJSON() ([]byte, error) {
+	if len(s.AuthorizedKeys) != 0 {
+		// Marshal the object form, without calling this function again
+		type sshObject SSH
+		return json.Marshal((*sshObject)(s))
+	}
+
+	if len(s.PublicKey) != 0 {
+		return json.Marshal(s.PublicKey)
+	}
+
+	if s.Generate {
+		return json.Marshal(true)
+	}
+
+	return []byte("{}"), nil
+}
+
+func (s *SSH) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		if str == "true" {
+			*s = SSH{
+				Generate: true,
+			}
+		} else {
+			*s = SSH{
+				PublicKey: str,
+			}
+		}
+
+		return nil
+	}
+
+	var boolVar bool
+	if err := json.Unmarshal(b, &boolVar); err == nil {
+		if boolVar {
+			*s = SSH{
+				Generate: true,
+			}
+
+			return nil
+		}
+	}
+
+	type sshObject SSH
+	var obj sshObject
+	if err := json.Unmarshal(b, &obj); err == nil {
+		*s = SSH(obj)
+		return nil
+	}
+
+	// The user did not specify this field, just return
+	return nil
+}
+
+func (m *VMMemorySpec) MarshalJSON() ([]byte, error) {
+	if len(m.Hugepages) != 0 {
+		// Marshal the object form, without calling this function again
+		type memoryObject VMMemorySpec
+		return json.Marshal((*memoryObject)(m))
+	}
+
+	return json.Marshal(&m.Size)
+}
+
+func (m *VMMemorySpec) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		size, err := meta.NewSizeFromString(str)
+		if err != nil {
+			return err
+		}
+
+		*m = VMMemorySpec{
+			Size: size,
+		}
+
+		return nil
+	}
+
+	type memoryObject VMMemorySpec
+	var obj memoryObject
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+
+	*m = VMMemorySpec(obj)
+	return nil
+}
@@ -0,0 +1,44 @@
+package v1alpha5
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+func TestVMMemorySpecJSON(t *testing.T) {
+	cases := []struct {
+		name   string
+		memory VMMemorySpec
+		json   string
+	}{
+		{
+			name:   "size only",
+			memory: VMMemorySpec{Size: meta.NewSizeFromBytes(512 << 20)},
+			json:   `"512MB"`,
+		},
+		{
+			name:   "hugepages",
+			memory: VMMemorySpec{Size: meta.NewSizeFromBytes(1 << 30), Hugepages: HugepageSize2Mi},
+			json:   `{"size":"1GB","hugepages":"2Mi"}`,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			b, err := json.Marshal(&rt.memory)
+			assert.NilError(t, err)
+			assert.Equal(t, string(b), rt.json)
+
+			var memory VMMemorySpec
+			assert.NilError(t, json.Unmarshal([]byte(rt.json), &memory))
+			assert.DeepEqual(t, memory, rt.memory)
+		})
+	}
+
+	var memory VMMemorySpec
+	assert.ErrorContains(t, json.Unmarshal([]byte(`"lots"`), &memory), "")
+}
@@ -0,0 +1,1073 @@
+package v1alpha5
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	igniteNetwork "github.com/weaveworks/ignite/pkg/network"
+	igniteRuntime "github.com/weaveworks/ignite/pkg/runtime"
+	igniteSnapshotter "github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+const (
+	KindImage      runtime.Kind = "Image"
+	KindKernel     runtime.Kind = "Kernel"
+	KindVM         runtime.Kind = "VM"
+	KindVMTemplate runtime.Kind = "VMTemplate"
+	KindPool       runtime.Kind = "Pool"
+)
+
+// Image represents a cached OCI image ready to be used with Ignite
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Image struct {
+	runtime.TypeMeta `json:",inline"`
+	// runtime.ObjectMeta is also embedded into the struct, and defines the human-readable name, and the machine-readable ID
+	// Name is available at the .metadata.name JSON path
+	// ID is available at the .metadata.uid JSON path (the Go type is k8s.io/apimachinery/pkg/types.UID, which is only a typed string)
+	runtime.ObjectMeta `json:"metadata"`
+
+	Spec   ImageSpec   `json:"spec"`
+	Status ImageStatus `json:"status"`
+}
+
+// ImageSpec declares what the image contains
+type ImageSpec struct {
+	OCI meta.OCIImageRef `json:"oci"`
+}
+
+// OCIImageSource specifies how the OCI image was imported.
+// It is the status variant of OCIImageClaim
+type OCIImageSource struct {
+	// ID defines the source's content ID (e.g. the canonical OCI path or Docker image ID)
+	ID *meta.OCIContentID `json:"id"`
+	// Size defines the size of the source in bytes
+	Size meta.Size `json:"size"`
+	// Architecture is the architecture the image is built for, e.g. amd64 or arm64. For
+	// kernels, it's the architecture of the kernel file.
+	Architecture string `json:"architecture,omitempty"`
+	// Format is how the image is stored, an ext4 filesystem of its files, or the disk
+	// image it contains. Kernels don't set it.
+	// Default: filesystem
+	Format ImageFormat `json:"format,omitempty"`
+}
+
+// ImageFormat is how an image is stored
+type ImageFormat string
+
+const (
+	// ImageFormatFilesystem is an ext4 filesystem of the files of the OCI image, the VMs
+	// boot it with the kernel of the VM
+	ImageFormatFilesystem ImageFormat = "filesystem"
+	// ImageFormatDisk is the disk image an OCI image contains in /disk, like the container
+	// disks of KubeVirt, e.g. an unmodified cloud image. The VMs boot it with UEFI firmware
+	// from the bootloader and kernel in the disk.
+	ImageFormatDisk ImageFormat = "disk"
+)
+
+// ImageStatus defines the status of the image
+type ImageStatus struct {
+	// OCISource contains the information about how this OCI image was imported
+	OCISource OCIImageSource `json:"ociSource"`
+	// Scan contains the vulnerabilities found in the image when it was imported
+	Scan *ImageScanStatus `json:"scan,omitempty"`
+	// SBOM describes the software bill of materials generated when the image was imported
+	SBOM *ImageSBOM `json:"sbom,omitempty"`
+	// LazyRef is set for lazily imported images to the reference of the manifest, by
+	// digest, the files that aren't in the filesystem of the image are read from
+	LazyRef string `json:"lazyRef,omitempty"`
+}
+
+// ImageSBOM describes the software bill of materials of an image, which is stored
+// next to the image filesystem
+type ImageSBOM struct {
+	// Format is the format of the SBOM, spdx or cyclonedx
+	Format SBOMFormat `json:"format"`
+	// Time is when the SBOM was generated
+	Time runtime.Time `json:"time"`
+}
+
+// SBOMFormat is the format of a software bill of materials
+type SBOMFormat string
+
+const (
+	// SBOMFormatSPDX is the SPDX JSON format
+	SBOMFormatSPDX SBOMFormat = "spdx"
+	// SBOMFormatCycloneDX is the CycloneDX JSON format
+	SBOMFormatCycloneDX SBOMFormat = "cyclonedx"
+)
+
+// ImageScanStatus contains the result of the vulnerability scan of an image
+type ImageScanStatus struct {
+	// Scanner is the scanner that scanned the image, trivy or grype
+	Scanner string `json:"scanner"`
+	// Time is when the image was scanned
+	Time runtime.Time `json:"time"`
+	// Summary counts the vulnerabilities by severity
+	Summary VulnerabilitySummary `json:"summary"`
+	// Vulnerabilities are the vulnerabilities found in the packages of the image
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// VulnerabilitySummary counts the vulnerabilities of an image by severity
+type VulnerabilitySummary struct {
+	Critical uint32 `json:"critical,omitempty"`
+	High     uint32 `json:"high,omitempty"`
+	Medium   uint32 `json:"medium,omitempty"`
+	Low      uint32 `json:"low,omitempty"`
+	Unknown  uint32 `json:"unknown,omitempty"`
+}
+
+// Vulnerability is a vulnerability of a package installed in an image
+type Vulnerability struct {
+	// ID is the identifier of the vulnerability, e.g. CVE-2021-3711
+	ID string `json:"id"`
+	// Package is the name of the vulnerable package
+	Package string `json:"package"`
+	// Version is the installed version of the package
+	Version string `json:"version"`
+	// FixedVersion is the version of the package the vulnerability is fixed in, if any
+	FixedVersion string `json:"fixedVersion,omitempty"`
+	// Severity is the severity of the vulnerability
+	Severity VulnerabilitySeverity `json:"severity"`
+	// Title briefly describes the vulnerability
+	Title string `json:"title,omitempty"`
+}
+
+// VulnerabilitySeverity is the severity of a vulnerability
+type VulnerabilitySeverity string
+
+const (
+	SeverityUnknown  VulnerabilitySeverity = "Unknown"
+	SeverityLow      VulnerabilitySeverity = "Low"
+	SeverityMedium   VulnerabilitySeverity = "Medium"
+	SeverityHigh     VulnerabilitySeverity = "High"
+	SeverityCritical VulnerabilitySeverity = "Critical"
+)
+
+// Pool defines device mapper pool database
+// This file is managed by the snapshotter part of Ignite, and the file (existing as a singleton)
+// is present at /var/lib/firecracker/snapshotter/pool.json
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Pool struct {
+	runtime.TypeMeta `json:",inline"`
+	// Not needed (yet)
+	// runtime.ObjectMeta `json:"metadata"`
+
+	Spec   PoolSpec   `json:"spec"`
+	Status PoolStatus `json:"status"`
+}
+
+// PoolSpec defines the Pool's specification
+type PoolSpec struct {
+	// MetadataSize specifies the size of the pool's metadata
+	MetadataSize meta.Size `json:"metadataSize"`
+	// DataSize specifies the size of the pool's data
+	DataSize meta.Size `json:"dataSize"`
+	// AllocationSize specifies the smallest size that can be allocated at a time
+	AllocationSize meta.Size `json:"allocationSize"`
+	// MetadataPath points to the file where device mapper stores all metadata information
+	// Defaults to constants.SNAPSHOTTER_METADATA_PATH
+	MetadataPath string `json:"metadataPath"`
+	// DataPath points to the backing physical device or sparse file (to be loop mounted) for the pool
+	// Defaults to constants.SNAPSHOTTER_DATA_PATH
+	DataPath string `json:"dataPath"`
+}
+
+// PoolStatus defines the Pool's current status
+type PoolStatus struct {
+	// The Devices array needs to contain pointers to accommodate "holes" in the mapping
+	// Where devices have been deleted, the pointer is nil
+	Devices []*PoolDevice `json:"devices"`
+}
+
+type PoolDeviceType string
+
+const (
+	PoolDeviceTypeImage  PoolDeviceType = "Image"
+	PoolDeviceTypeResize PoolDeviceType = "Resize"
+	PoolDeviceTypeKernel PoolDeviceType = "Kernel"
+	PoolDeviceTypeVM     PoolDeviceType = "VM"
+)
+
+// PoolDevice defines one device in the pool
+type PoolDevice struct {
+	Size   meta.Size `json:"size"`
+	Parent meta.DMID `json:"parent"`
+	// Type specifies the type of the contents of the device
+	Type PoolDeviceType `json:"type"`
+	// MetadataPath points to the JSON/YAML file with metadata about this device
+	// This is most often of the format /var/lib/firecracker/{type}/{id}/metadata.json
+	MetadataPath string `json:"metadataPath"`
+}
+
+// Kernel is a serializable object that caches information about imported kernels
+// This file is stored in /var/lib/firecracker/kernels/{oci-image-digest}/metadata.json
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Kernel struct {
+	runtime.TypeMeta `json:",inline"`
+	// runtime.ObjectMeta is also embedded into the struct, and defines the human-readable name, and the machine-readable ID
+	// Name is available at the .metadata.name JSON path
+	// ID is available at the .metadata.uid JSON path (the Go type is k8s.io/apimachinery/pkg/types.UID, which is only a typed string)
+	runtime.ObjectMeta `json:"metadata"`
+
+	Spec   KernelSpec   `json:"spec"`
+	Status KernelStatus `json:"status"`
+}
+
+// KernelSpec describes the properties of a kernel
+type KernelSpec struct {
+	OCI meta.OCIImageRef `json:"oci"`
+	// Optional future feature, support per-kernel specific default command lines
+	// DefaultCmdLine string
+
+	// Describe if the OCIImageRef has a initrd
+	HasInitrd bool `json:"initrd"`
+}
+
+// KernelStatus describes the status of a kernel
+type KernelStatus struct {
+	Version   string         `json:"version"`
+	OCISource OCIImageSource `json:"ociSource"`
+	// Compression is the compression of the kernel of the kernel image, e.g. gzip for a
+	// bzImage. The uncompressed vmlinux Firecracker boots is extracted from it at import.
+	Compression string `json:"compression,omitempty"`
+}
+
+// VM represents a virtual machine run by Firecracker
+// These files are stored in /var/lib/firecracker/vm/{vm-id}/metadata.json
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type VM struct {
+	runtime.TypeMeta `json:",inline"`
+	// runtime.ObjectMeta is also embedded into the struct, and defines the human-readable name, and the machine-readable ID
+	// Name is available at the .metadata.name JSON path
+	// ID is available at the .metadata.uid JSON path (the Go type is k8s.io/apimachinery/pkg/types.UID, which is only a typed string)
+	runtime.ObjectMeta `json:"metadata"`
+
+	Spec   VMSpec   `json:"spec"`
+	Status VMStatus `json:"status"`
+}
+
+// VMSpec describes the configuration of a VM
+type VMSpec struct {
+	Image    VMImageSpec   `json:"image"`
+	Sandbox  VMSandboxSpec `json:"sandbox"`
+	Kernel   VMKernelSpec  `json:"kernel"`
+	CPUs     uint64        `json:"cpus"`
+	Memory   VMMemorySpec  `json:"memory"`
+	DiskSize meta.Size     `json:"diskSize"`
+	// OverlaySizeLimit caps how much host disk space the VM's writable overlay
+	// may allocate, independently of DiskSize. Writes beyond the limit fail
+	// inside the VM. Unset means the overlay may grow up to DiskSize.
+	OverlaySizeLimit meta.Size `json:"overlaySizeLimit,omitempty"`
+	// TODO: Implement working omitempty without pointers for the following entries
+	// Currently both will show in the JSON output as empty arrays. Making them
+	// pointers requires plenty of nil checks (as their contents are accessed directly)
+	// and is very risky for stability. APIMachinery potentially has a solution.
+	Network VMNetworkSpec `json:"network,omitempty"`
+	Storage VMStorageSpec `json:"storage,omitempty"`
+	// This will be done at either "ignite start" or "ignite create" time
+	// TODO: We might revisit this later
+	CopyFiles []FileMapping `json:"copyFiles,omitempty"`
+	// SSH specifies how the SSH setup should be done
+	// nil here means "don't do anything special"
+	// If SSH.Generate is set, Ignite will generate a new SSH key and copy it in to authorized_keys in the VM
+	// Specifying a path in SSH.Generate means "use this public key"
+	// If SSH.PublicKey is set, this struct will marshal as a string using that path
+	// If SSH.Generate is set, this struct will marshal as a bool => true
+	SSH *SSH `json:"ssh,omitempty"`
+	// Backup defines a policy for periodic backups of the VM's disk, run by ignited
+	// nil here means the VM isn't backed up
+	Backup *VMBackupSpec `json:"backup,omitempty"`
+	// Autostart makes the VM start automatically when the host boots, either
+	// by ignited when it starts up, or by "ignite vm autostart"
+	Autostart bool `json:"autostart,omitempty"`
+	// AutostartAfter lists the names or IDs of the VMs that need to be running before
+	// the VM is autostarted. They're started first, even if they don't set autostart.
+	AutostartAfter []string `json:"autostartAfter,omitempty"`
+	// RestartPolicy determines whether ignited restarts the VM when it stops without
+	// being stopped by ignite, e.g. when it crashes or shuts itself down
+	// Default: never
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+	// LivenessProbe is run by ignited while the VM is running, its result is recorded
+	// in the Healthy condition of the VM
+	// +optional
+	LivenessProbe *VMProbe `json:"livenessProbe,omitempty"`
+	// Seccomp configures the seccomp filter of the Firecracker process of the VM, the
+	// syscalls the VMM may make
+	// Default: the advanced level, the default of Firecracker
+	// +optional
+	Seccomp *VMSeccompSpec `json:"seccomp,omitempty"`
+	// Sysctls are the kernel parameters set in the guest at boot, by their sysctl name
+	// (e.g. fs.inotify.max_user_watches). They're passed on the kernel command line,
+	// which the guest kernel applies from Linux 5.8 on. Parameters of kernel modules
+	// that are loaded later, after the kernel command line has been applied, aren't set.
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+	// Env are the environment variables set in the guest. They're written to
+	// /etc/profile.d/ignite-env.sh for login shells, and to /etc/ignite/environment
+	// for systemd units (EnvironmentFile=) every time the VM is started.
+	Env map[string]string `json:"env,omitempty"`
+	// Users are created in the guest every time the VM is started, users that already
+	// exist in the image are updated. This allows logging in to stock images without
+	// baking credentials into them.
+	Users []VMUser `json:"users,omitempty"`
+	// Secrets are read on the host every time the VM is started, and delivered to its
+	// guest agent. The agent writes them to a tmpfs at /run/ignite/secrets, they're never
+	// written to the disk of the VM. The image needs to be imported with the agent.
+	Secrets []VMSecret `json:"secrets,omitempty"`
+	// Runtime is the container runtime the VM is run with. It overrides the runtime of
+	// the ignite configuration and flags, so that VMs on the same host can use different
+	// runtimes. Default: unset, the runtime the VM was created with is used
+	Runtime igniteRuntime.Name `json:"runtime,omitempty"`
+	// NetworkPlugin is the network plugin the VM is run with. It overrides the network
+	// plugin of the ignite configuration and flags, docker-bridge needs the docker runtime.
+	// Default: unset, the network plugin the VM was created with is used
+	NetworkPlugin igniteNetwork.PluginName `json:"networkPlugin,omitempty"`
+	// Firmware is the path of a UEFI firmware blob on the host the VM boots with if its
+	// image is a disk image, which boots from the bootloader and kernel in the disk.
+	// Default: the OVMF (amd64) or AAVMF (arm64) firmware of the sandbox image
+	Firmware string `json:"firmware,omitempty"`
+	// Devices are host PCI devices passed through into the VM with VFIO, e.g. GPUs. They're
+	// unbound from their host drivers while the VM runs, and need the IOMMU of the host to
+	// be enabled. VMs with devices run with QEMU, Firecracker doesn't support VFIO.
+	// +optional
+	Devices []PCIDevice `json:"devices,omitempty"`
+	// NestedVirtualization exposes the virtualization extensions of the host CPU (VMX or SVM)
+	// to the guest, so the VM can run its own hypervisor. It needs nested virtualization to be
+	// enabled in KVM on the host. VMs with nested virtualization run with QEMU, Firecracker
+	// doesn't expose the extensions. Only supported on amd64.
+	// +optional
+	NestedVirtualization bool `json:"nestedVirtualization,omitempty"`
+}
+
+// PCIDevice is a host PCI device passed through into a VM
+type PCIDevice struct {
+	// Address is the PCI address of the device on the host, in the
+	// domain:bus:device.function format, e.g. 0000:01:00.0
+	Address string `json:"address"`
+}
+
+// RestartPolicy determines when a stopped VM is restarted by ignited
+type RestartPolicy string
+
+const (
+	// RestartPolicyAlways restarts the VM whenever it stops
+	RestartPolicyAlways RestartPolicy = "always"
+	// RestartPolicyOnFailure restarts the VM if Firecracker exited with a non-zero exit code
+	RestartPolicyOnFailure RestartPolicy = "on-failure"
+	// RestartPolicyNever never restarts the VM
+	RestartPolicyNever RestartPolicy = "never"
+)
+
+// VMMemorySpec is the memory of the VM
+// VMMemorySpec uses a custom marshaller/unmarshaller. Without hugepages,
+// it marshals to the size string. Otherwise it marshals to an object
+// with the size and hugepages fields.
+type VMMemorySpec struct {
+	Size meta.Size `json:"size"`
+	// Hugepages backs the memory of the VM with hugepages of the given size,
+	// which need to be reserved on the host. Unset uses regular pages.
+	Hugepages HugepageSize `json:"hugepages,omitempty"`
+}
+
+// VMSeccompSpec configures the seccomp filter of the Firecracker process of a VM,
+// either by level or with a custom filter
+type VMSeccompSpec struct {
+	// Level is the seccomp level of Firecracker: advanced filters the syscalls by number
+	// and arguments, basic by number only, and disabled turns seccomp off
+	// Default: advanced
+	Level SeccompLevel `json:"level,omitempty"`
+	// Filter is the path of a custom seccomp filter on the host, compiled by the
+	// seccompiler of Firecracker, which is applied instead of the level. It needs
+	// Firecracker v0.25 or newer.
+	Filter string `json:"filter,omitempty"`
+}
+
+// SeccompLevel is a seccomp level of Firecracker
+type SeccompLevel string
+
+const (
+	// SeccompLevelAdvanced filters the syscalls by number and arguments, Firecracker's level 2
+	SeccompLevelAdvanced SeccompLevel = "advanced"
+	// SeccompLevelBasic filters the syscalls by number, Firecracker's level 1
+	SeccompLevelBasic SeccompLevel = "basic"
+	// SeccompLevelDisabled disables seccomp, Firecracker's level 0
+	SeccompLevelDisabled SeccompLevel = "disabled"
+)
+
+// VMSeccompStatus is the seccomp filter applied to the Firecracker process of a VM
+type VMSeccompStatus struct {
+	// Level is the seccomp level applied, if no custom filter is
+	Level SeccompLevel `json:"level,omitempty"`
+	// Filter is the path of the custom seccomp filter applied
+	Filter string `json:"filter,omitempty"`
+	// FilterDigest is the sha256 digest of the custom seccomp filter applied
+	FilterDigest string `json:"filterDigest,omitempty"`
+}
+
+// VMConfinementStatus is the confinement applied to the sandbox of a VM
+type VMConfinementStatus struct {
+	// SELinuxLabel is the SELinux label of the sandbox processes, its MCS categories are
+	// unique to the VM and kept across restarts
+	SELinuxLabel string `json:"selinuxLabel,omitempty"`
+	// AppArmorProfile is the name of the AppArmor profile generated for the VM
+	AppArmorProfile string `json:"appArmorProfile,omitempty"`
+}
+
+// VMProbe checks whether a running VM is healthy, by connecting to a TCP port, by
+// sending an HTTP GET request, or by running a command through the guest agent.
+// Exactly one of TCP, HTTP and Exec is set.
+type VMProbe struct {
+	TCP  *TCPProbe  `json:"tcp,omitempty"`
+	HTTP *HTTPProbe `json:"http,omitempty"`
+	Exec *ExecProbe `json:"exec,omitempty"`
+	// InitialDelay is how long after the VM started the probe is first run
+	// Default: 0, the probe is run right away
+	InitialDelay metav1.Duration `json:"initialDelay,omitempty"`
+	// Period is how often the probe is run
+	// Default: 10s
+	Period metav1.Duration `json:"period,omitempty"`
+	// Timeout is how long the probe may take before it fails
+	// Default: 1s
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// FailureThreshold is how many consecutive failures make the VM unhealthy
+	// Default: 3
+	FailureThreshold uint32 `json:"failureThreshold,omitempty"`
+	// Restart kills the VM once it's unhealthy, so its restart policy restarts it
+	Restart bool `json:"restart,omitempty"`
+}
+
+// TCPProbe succeeds if the port of the VM accepts the connection
+type TCPProbe struct {
+	Port uint16 `json:"port"`
+}
+
+// HTTPProbe succeeds if the GET request to the port and path of the VM returns a
+// status code from 200 to 399
+type HTTPProbe struct {
+	Port uint16 `json:"port"`
+	// Path is the path of the request, / by default
+	Path string `json:"path,omitempty"`
+	// HTTPS sends the request over TLS, without verifying the certificate of the VM
+	HTTPS bool `json:"https,omitempty"`
+}
+
+// ExecProbe succeeds if the command exits with code 0. It's run through the guest
+// agent, so the image needs to be imported with it.
+type ExecProbe struct {
+	Command []string `json:"command"`
+}
+
+// HugepageSize is the size of the hugepages that back the memory of a VM
+type HugepageSize string
+
+const (
+	// HugepageSize2Mi backs the memory of the VM with 2 MiB hugepages
+	HugepageSize2Mi HugepageSize = "2Mi"
+	// HugepageSize1Gi backs the memory of the VM with 1 GiB hugepages
+	HugepageSize1Gi HugepageSize = "1Gi"
+)
+
+// VMTemplate is a reusable VM configuration. VMs created from a template
+// use its spec as their base configuration, which can be overridden per VM.
+// These files are stored in /var/lib/firecracker/vmtemplate/{template-id}/metadata.json
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type VMTemplate struct {
+	runtime.TypeMeta   `json:",inline"`
+	runtime.ObjectMeta `json:"metadata"`
+
+	Spec VMSpec `json:"spec"`
+}
+
+// VMBackupSpec defines when the disk of a VM is backed up, where to, and how many backups to keep
+type VMBackupSpec struct {
+	// Schedule is a cron expression in the "minute hour day-of-month month day-of-week" format
+	Schedule string `json:"schedule"`
+	// Retention is the number of backups to keep, older backups are removed
+	// Default: unset, all backups are kept
+	Retention uint64 `json:"retention,omitempty"`
+	// Destination is a local directory or an s3://bucket[/prefix] URL,
+	// the backups of the VM are stored in a subdirectory named by its UID
+	Destination string `json:"destination"`
+}
+
+type VMImageSpec struct {
+	OCI meta.OCIImageRef `json:"oci"`
+}
+
+type VMKernelSpec struct {
+	OCI       meta.OCIImageRef `json:"oci"`
+	HasInitrd bool             `json:"initrd"`
+	CmdLine   string           `json:"cmdLine,omitempty"`
+	// InitrdRef is an OCI image with an initramfs at /boot/initrd, passed to the kernel
+	// instead of the initrd of the kernel image, e.g. for root filesystems that need the
+	// drivers or the LUKS setup of the initrd to be mounted
+	InitrdRef *meta.OCIImageRef `json:"initrdRef,omitempty"`
+}
+
+// VMSandboxSpec is the spec of the sandbox used for the VM.
+type VMSandboxSpec struct {
+	OCI meta.OCIImageRef `json:"oci"`
+}
+
+type VMNetworkSpec struct {
+	Ports meta.PortMappings `json:"ports,omitempty"`
+}
+
+// VMStorageSpec defines the VM's Volumes and VolumeMounts
+type VMStorageSpec struct {
+	Volumes      []Volume      `json:"volumes,omitempty"`
+	VolumeMounts []VolumeMount `json:"volumeMounts,omitempty"`
+	// ReadOnlyRoot attaches the root device of the VM read-only, the guest can't change
+	// its root filesystem. Only the WritablePaths and the volumes are writable.
+	ReadOnlyRoot bool `json:"readOnlyRoot,omitempty"`
+	// WritablePaths are mounted as empty RAM-backed tmpfs when the root device is
+	// read-only, their contents are discarded when the VM stops
+	// Default: /tmp, /var/tmp and /var/log
+	WritablePaths []string `json:"writablePaths,omitempty"`
+}
+
+// Volume defines named storage volume
+type Volume struct {
+	Name        string             `json:"name"`
+	BlockDevice *BlockDeviceVolume `json:"blockDevice,omitempty"`
+	Tmpfs       *TmpfsVolume       `json:"tmpfs,omitempty"`
+	NBD         *NBDVolume         `json:"nbd,omitempty"`
+}
+
+// BlockDeviceVolume defines a block device on the host
+type BlockDeviceVolume struct {
+	Path string `json:"path"`
+}
+
+// TmpfsVolume defines a RAM-backed scratch volume inside the VM.
+// The volume is created empty when the VM boots and its contents
+// are discarded when the VM stops, nothing is written to the host disk.
+type TmpfsVolume struct {
+	// Size limits the size of the tmpfs, it counts against the VM's memory
+	// Default: unset, the guest kernel default (half of the VM's memory) is used
+	Size meta.Size `json:"size,omitempty"`
+}
+
+// NBDVolume defines a remote disk served over the network block device protocol.
+// It's connected on the host when the VM starts, and reconnected if the connection drops.
+type NBDVolume struct {
+	// URL of the export, in the nbd://host[:port]/export format
+	URL string `json:"url"`
+}
+
+// VolumeMount defines the mount point for a named volume inside a VM
+type VolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+}
+
+// FileMapping defines mappings between files on the host and VM
+type FileMapping struct {
+	HostPath string `json:"hostPath"`
+	VMPath   string `json:"vmPath"`
+}
+
+// SSH specifies different ways to connect via SSH to the VM
+// SSH uses a custom marshaller/unmarshaller. If generate is true,
+// it marshals to true (a JSON bool). If PublicKey is set, it marshals
+// to that string. If AuthorizedKeys are set, it marshals to an object
+// with the generate, publicKey and authorizedKeys fields.
+type SSH struct {
+	Generate  bool   `json:"generate,omitempty"`
+	PublicKey string `json:"publicKey,omitempty"`
+	// AuthorizedKeys are public keys in the authorized_keys format that are
+	// authorized for root in the VM, in addition to the generated or given key
+	AuthorizedKeys []string `json:"authorizedKeys,omitempty"`
+}
+
+// VMUser is a user of the guest, see VMSpec.Users
+type VMUser struct {
+	Name string `json:"name"`
+	// UID of the user, the first free UID from 1000 on is used if unset.
+	// It can't be changed for users that already exist in the image.
+	UID *uint32 `json:"uid,omitempty"`
+	// Groups are the supplementary groups of the user, missing groups are created
+	Groups []string `json:"groups,omitempty"`
+	// Sudo allows the user to run any command as root with sudo, without a password.
+	// The image needs to have sudo installed.
+	Sudo bool `json:"sudo,omitempty"`
+	// PasswordHash is the password of the user in the crypt(3) format, e.g. the
+	// output of "mkpasswd -m sha-512". Password logins are disabled if unset.
+	PasswordHash string `json:"passwordHash,omitempty"`
+	// AuthorizedKeys are public keys in the authorized_keys format that are
+	// authorized for the user
+	AuthorizedKeys []string `json:"authorizedKeys,omitempty"`
+}
+
+// VMSecret is a secret of the guest, see VMSpec.Secrets. Its value is read from a file
+// or an environment variable of the host, or from Vault. Exactly one of File, Env and
+// Vault is set.
+type VMSecret struct {
+	// Name is the name of the secret file in /run/ignite/secrets in the guest
+	Name string `json:"name"`
+	// File is the absolute path of the file on the host holding the secret
+	File string `json:"file,omitempty"`
+	// Env is the environment variable holding the secret, of the ignite or ignited
+	// process starting the VM
+	Env string `json:"env,omitempty"`
+	// Vault is the field of a Vault secret holding the secret, as <path>#<field>, e.g.
+	// secret/data/db#password. Vault is configured in the ignite configuration.
+	Vault string `json:"vault,omitempty"`
+	// Mode is the permissions of the secret file in the guest, it's owned by root
+	// Default: 0400
+	Mode uint32 `json:"mode,omitempty"`
+}
+
+// Runtime specifies the VM's runtime information
+type Runtime struct {
+	ID   string             `json:"id"`
+	Name igniteRuntime.Name `json:"name"`
+}
+
+// Network specifies the VM's network information.
+type Network struct {
+	Plugin      igniteNetwork.PluginName `json:"plugin"`
+	IPAddresses meta.IPAddresses         `json:"ipAddresses"`
+}
+
+// VMStatus defines the status of a VM
+type VMStatus struct {
+	Running     bool                   `json:"running"`
+	Paused      bool                   `json:"paused,omitempty"`
+	Runtime     *Runtime               `json:"runtime,omitempty"`
+	StartTime   *runtime.Time          `json:"startTime,omitempty"`
+	Network     *Network               `json:"network,omitempty"`
+	Image       OCIImageSource         `json:"image"`
+	Kernel      OCIImageSource         `json:"kernel"`
+	IDPrefix    string                 `json:"idPrefix"`
+	Overlay     *OverlayStatus         `json:"overlay,omitempty"`
+	Snapshotter igniteSnapshotter.Name `json:"snapshotter,omitempty"`
+	// RestartCount is the number of times the VM has been restarted by its restart policy
+	RestartCount uint64 `json:"restartCount,omitempty"`
+	// LastExit describes how the VM stopped the last time
+	LastExit *VMExitStatus `json:"lastExit,omitempty"`
+	// Conditions describe whether the VM is usable, and why not
+	Conditions []VMCondition `json:"conditions,omitempty"`
+	// Seccomp is the seccomp filter applied to the Firecracker process of the VM when it was started
+	Seccomp *VMSeccompStatus `json:"seccomp,omitempty"`
+	// Confinement is the SELinux label and AppArmor profile the sandbox of the VM was started with
+	Confinement *VMConfinementStatus `json:"confinement,omitempty"`
+}
+
+// VMConditionType is the type of a VMCondition
+type VMConditionType string
+
+const (
+	// VMImageReady is set when the overlay of the VM on its image has been activated
+	VMImageReady VMConditionType = "ImageReady"
+	// VMNetworkReady is set when the networking of the VM has been set up and its
+	// addresses are served over DHCP
+	VMNetworkReady VMConditionType = "NetworkReady"
+	// VMBooted is set when Firecracker has started the VM
+	VMBooted VMConditionType = "Booted"
+	// VMSSHReachable is set when the SSH server within the VM accepts connections
+	VMSSHReachable VMConditionType = "SSHReachable"
+	// VMDegraded is set when the VM crashed, or its overlay is running out of space
+	VMDegraded VMConditionType = "Degraded"
+	// VMHealthy is set by ignited from the result of the liveness probe of the VM
+	VMHealthy VMConditionType = "Healthy"
+	// VMSynced is set by "ignited gitops" in the manifests it writes the status back to, when
+	// the manifest of the VM was applied, or failed to
+	VMSynced VMConditionType = "Synced"
+	// VMSecretsDelivered is set when the secrets of the VM have been delivered to its guest agent
+	VMSecretsDelivered VMConditionType = "SecretsDelivered"
+	// VMFilesLoaded is set for VMs of lazily imported images when the files that weren't
+	// needed at boot have been written into the disk of the VM by its guest agent
+	VMFilesLoaded VMConditionType = "FilesLoaded"
+	// VMEmulated is set when the VM is started, true if it's emulated with QEMU because
+	// KVM is unavailable, or emulation is always used
+	VMEmulated VMConditionType = "Emulated"
+)
+
+// ConditionStatus is the status of a condition, True, False or Unknown
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// VMCondition describes an aspect of the state of a VM
+type VMCondition struct {
+	Type   VMConditionType `json:"type"`
+	Status ConditionStatus `json:"status"`
+	// LastTransitionTime is when the status of the condition last changed
+	LastTransitionTime runtime.Time `json:"lastTransitionTime"`
+	// Reason is a CamelCase reason for the status of the condition
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable description of the reason
+	Message string `json:"message,omitempty"`
+}
+
+// VMExitStatus describes how a VM stopped
+type VMExitStatus struct {
+	// Time is when the VM stopped
+	Time runtime.Time `json:"time"`
+	// ExitCode is the exit code of Firecracker, it's non-zero if the VM failed
+	ExitCode int `json:"exitCode"`
+	// Requested is set if the VM was stopped by ignite, e.g. by "ignite stop".
+	// The restart policy doesn't apply to requested stops.
+	Requested bool `json:"requested,omitempty"`
+	// Reason is why the VM stopped, e.g. OOMKilled
+	Reason ExitReason `json:"reason,omitempty"`
+}
+
+// ExitReason describes why a VM stopped
+type ExitReason string
+
+const (
+	// ExitReasonStopped is set when the VM was stopped by ignite
+	ExitReasonStopped ExitReason = "Stopped"
+	// ExitReasonCompleted is set when Firecracker exited successfully, e.g. when the guest shut down
+	ExitReasonCompleted ExitReason = "Completed"
+	// ExitReasonOOMKilled is set when Firecracker was killed for running out of memory
+	ExitReasonOOMKilled ExitReason = "OOMKilled"
+	// ExitReasonKilled is set when Firecracker was killed by a signal
+	ExitReasonKilled ExitReason = "Killed"
+	// ExitReasonError is set when Firecracker exited unsuccessfully
+	ExitReasonError ExitReason = "Error"
+	// ExitReasonUnhealthy is set when ignited killed the VM for failing its liveness probe
+	ExitReasonUnhealthy ExitReason = "Unhealthy"
+)
+
+// OverlayStatus describes the host disk usage of the VM's writable overlay
+type OverlayStatus struct {
+	// Usage is the amount of host disk space allocated by the overlay
+	Usage meta.Size `json:"usage"`
+	// NearLimit is set when Usage has reached the warning threshold
+	// of the VM's overlay size limit
+	NearLimit bool `json:"nearLimit,omitempty"`
+}
+
+// Configuration represents the ignite runtime configuration.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Configuration struct {
+	runtime.TypeMeta   `json:",inline"`
+	runtime.ObjectMeta `json:"metadata"`
+
+	Spec ConfigurationSpec `json:"spec"`
+}
+
+// ConfigurationSpec defines the ignite configuration.
+type ConfigurationSpec struct {
+	Runtime           igniteRuntime.Name       `json:"runtime,omitempty"`
+	NetworkPlugin     igniteNetwork.PluginName `json:"networkPlugin,omitempty"`
+	VMDefaults        VMSpec                   `json:"vmDefaults,omitempty"`
+	IDPrefix          string                   `json:"idPrefix,omitempty"`
+	RegistryConfigDir string                   `json:"registryConfigDir,omitempty"`
+	Snapshotter       igniteSnapshotter.Name   `json:"snapshotter,omitempty"`
+	LVM               LVMConfiguration         `json:"lvm,omitempty"`
+	ZFS               ZFSConfiguration         `json:"zfs,omitempty"`
+	RBD               RBDConfiguration         `json:"rbd,omitempty"`
+	ConsoleLog        ConsoleLogConfiguration  `json:"consoleLog,omitempty"`
+	Events            EventsConfiguration      `json:"events,omitempty"`
+	Audit             AuditConfiguration       `json:"audit,omitempty"`
+	GitOps            GitOpsConfiguration      `json:"gitops,omitempty"`
+	ImageScan         ImageScanConfiguration   `json:"imageScan,omitempty"`
+	SBOM              SBOMConfiguration        `json:"sbom,omitempty"`
+	Policy            PolicyConfiguration      `json:"policy,omitempty"`
+	Confinement       ConfinementConfiguration `json:"confinement,omitempty"`
+	Vault             VaultConfiguration       `json:"vault,omitempty"`
+	ImageImport       ImageImportConfiguration `json:"imageImport,omitempty"`
+	BootCache         BootCacheConfiguration   `json:"bootCache,omitempty"`
+	Emulation         EmulationConfiguration   `json:"emulation,omitempty"`
+}
+
+// LVMConfiguration configures where the lvm snapshotter allocates VM disks
+type LVMConfiguration struct {
+	// VolumeGroup is the LVM volume group the logical volumes are created in
+	VolumeGroup string `json:"volumeGroup,omitempty"`
+	// ThinPool optionally names a thin pool in the volume group. If set, images
+	// are imported as thin volumes, and VM disks are thin snapshots of them.
+	ThinPool string `json:"thinPool,omitempty"`
+}
+
+// ZFSConfiguration configures where the zfs snapshotter creates its volumes
+type ZFSConfiguration struct {
+	// Dataset is the parent dataset for the image zvols and the VM clones,
+	// for example "tank/ignite". Properties like compression are inherited from it.
+	Dataset string `json:"dataset,omitempty"`
+}
+
+// RBDConfiguration configures where the rbd snapshotter creates its Ceph RBD images
+type RBDConfiguration struct {
+	// Pool is the Ceph pool the base images and the VM images are created in
+	Pool string `json:"pool,omitempty"`
+	// User is the Ceph user to authenticate as, defaults to the Ceph default (admin)
+	User string `json:"user,omitempty"`
+}
+
+// ConsoleLogConfiguration configures how the console output of the VMs is recorded
+// to the console log in the VM directory, and how the console log is rotated
+type ConsoleLogConfiguration struct {
+	// MaxSize is the size at which the console log is rotated
+	// Default: 10 MB
+	MaxSize meta.Size `json:"maxSize,omitempty"`
+	// MaxAge is the age of the oldest line at which the console log is rotated
+	// Default: unset, the console log is only rotated by size
+	MaxAge metav1.Duration `json:"maxAge,omitempty"`
+	// MaxFiles is the number of rotated console logs kept, older ones are removed
+	// Default: 1
+	MaxFiles uint64 `json:"maxFiles,omitempty"`
+	// Compress gzips the rotated console logs, except the most recent one
+	Compress bool `json:"compress,omitempty"`
+}
+
+// EventsConfiguration configures where ignited sends the events about VMs, images and kernels.
+// The events are always appended to the event log in the data directory.
+type EventsConfiguration struct {
+	// Webhooks are the HTTP endpoints every event is POSTed to as JSON
+	Webhooks []WebhookConfiguration `json:"webhooks,omitempty"`
+}
+
+// AuditConfiguration configures where the audit log of the create, start, stop, delete,
+// import and other mutating operations is recorded
+type AuditConfiguration struct {
+	// Path is the file the audit log is appended to
+	// Default: /var/lib/firecracker/audit.log
+	Path string `json:"path,omitempty"`
+	// Syslog sends the audit log to the local syslog daemon instead of the file
+	Syslog bool `json:"syslog,omitempty"`
+}
+
+// WebhookConfiguration is an HTTP endpoint ignited POSTs events to
+type WebhookConfiguration struct {
+	// URL is the http or https URL the events are POSTed to
+	URL string `json:"url"`
+	// Events are the types of the events sent to the webhook, e.g. VMCrashed
+	// Default: unset, all events are sent
+	Events []string `json:"events,omitempty"`
+	// Timeout is how long a request to the webhook may take
+	// Default: 10s
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// GitOpsConfiguration configures "ignited gitops"
+type GitOpsConfiguration struct {
+	// Environments map branches and directories of the repository to environments, which
+	// are synced separately, e.g. staging and production VM fleets
+	// Default: unset, the branch given to "ignited gitops" is synced as a whole
+	Environments []GitOpsEnvironment `json:"environments,omitempty"`
+}
+
+// ImageScanConfiguration configures the vulnerability scan of the images when they're imported
+type ImageScanConfiguration struct {
+	// Scanner is the scanner the images are scanned with, trivy or grype
+	// Default: unset, the images aren't scanned
+	Scanner string `json:"scanner,omitempty"`
+	// Path is the scanner binary
+	// Default: the scanner looked up in $PATH
+	Path string `json:"path,omitempty"`
+	// FailOn fails the import of the images with vulnerabilities of the severity
+	// or higher: Low, Medium, High or Critical
+	// Default: unset, the import never fails because of vulnerabilities
+	FailOn VulnerabilitySeverity `json:"failOn,omitempty"`
+}
+
+// SBOMConfiguration configures the software bill of materials generated for the images
+// when they're imported
+type SBOMConfiguration struct {
+	// Format is the format of the SBOM, spdx or cyclonedx
+	// Default: unset, no SBOM is generated
+	Format SBOMFormat `json:"format,omitempty"`
+	// Path is the syft binary the SBOM is generated with
+	// Default: syft looked up in $PATH
+	Path string `json:"path,omitempty"`
+}
+
+// ImageImportConfiguration configures how the filesystems of the images are built when
+// they're imported
+type ImageImportConfiguration struct {
+	// Mode is how the files of the image are written to its ext4 filesystem, mount or offline
+	// Default: mount
+	Mode ImageImportMode `json:"mode,omitempty"`
+	// Concurrency is how many images and kernels are imported at once by this process,
+	// e.g. the image and kernel of a VM on a host that has neither yet
+	// Default: 3
+	Concurrency int `json:"concurrency,omitempty"`
+	// Lazy imports images with eStargz layers with only the files they need to boot, read
+	// from the registry with range requests. The rest of the files are written into the
+	// disk of each VM when it's first started. Other images are imported in full.
+	Lazy bool `json:"lazy,omitempty"`
+}
+
+// BootCacheConfiguration configures the boot cache, which snapshots the first VM of an
+// image, kernel and size once it has booted, and restores the next VMs of the same kind
+// from the snapshot instead of booting them
+type BootCacheConfiguration struct {
+	// Enabled makes "ignite run" and "ignite start" use the boot cache for the VMs that
+	// haven't been started before. The images need to be imported with the guest agent.
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// EmulationConfiguration configures booting the VMs with the TCG emulation of QEMU instead
+// of Firecracker, for hosts without KVM like nested CI runners. Emulated VMs run a lot
+// slower, and without the features of the Firecracker API, e.g. migration, the boot cache,
+// pausing and the guest agent.
+type EmulationConfiguration struct {
+	// Mode is when the VMs are emulated, never, auto or always
+	// Default: never
+	Mode EmulationMode `json:"mode,omitempty"`
+}
+
+// EmulationMode is when the VMs are emulated with QEMU
+type EmulationMode string
+
+const (
+	// EmulationModeNever runs the VMs with Firecracker, which needs /dev/kvm
+	EmulationModeNever EmulationMode = "never"
+	// EmulationModeAuto emulates the VMs if /dev/kvm is unavailable
+	EmulationModeAuto EmulationMode = "auto"
+	// EmulationModeAlways emulates the VMs even if KVM is available, e.g. to test the emulation
+	EmulationModeAlways EmulationMode = "always"
+)
+
+// ImageImportMode is how the files of an image are written to its filesystem
+type ImageImportMode string
+
+const (
+	// ImageImportModeMount loop-mounts the filesystem and extracts the files into it,
+	// which requires root
+	ImageImportModeMount ImageImportMode = "mount"
+	// ImageImportModeOffline builds the filesystem from the tar stream of the image with
+	// mkfs.ext4 -d, without mounting it, so images can be imported without root. It
+	// requires e2fsprogs 1.47.1 or newer built with libarchive.
+	ImageImportModeOffline ImageImportMode = "offline"
+)
+
+// PolicyConfiguration configures the policy the image and kernel imports, and the VM
+// creations and starts are checked against. The operations the policy denies fail.
+type PolicyConfiguration struct {
+	// AllowedRegistries are the registries and repositories the images and kernels may
+	// come from, in their normalized form, e.g. docker.io/weaveworks or ghcr.io
+	// Default: unset, the images and kernels may come from anywhere
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+	// DeniedRegistries are the registries and repositories the images and kernels may not
+	// come from, even if they're allowed by AllowedRegistries, e.g. docker.io/library
+	DeniedRegistries []string `json:"deniedRegistries,omitempty"`
+	// RequireDigests requires the images and kernels of the VMs to be pinned to a digest,
+	// e.g. weaveworks/ignite-ubuntu@sha256:..., so a tag moved to another image isn't run
+	RequireDigests bool `json:"requireDigests,omitempty"`
+	// MaxCPUs is the most vCPUs a VM may have
+	// Default: unset, no limit
+	MaxCPUs uint64 `json:"maxCPUs,omitempty"`
+	// MaxMemory is the most memory a VM may have
+	// Default: unset, no limit
+	MaxMemory meta.Size `json:"maxMemory,omitempty"`
+	// DenyBlockDevices forbids the VMs to pass block devices of the host through
+	DenyBlockDevices bool `json:"denyBlockDevices,omitempty"`
+	// DenyDevices forbids the VMs to pass PCI devices of the host through with VFIO
+	DenyDevices bool `json:"denyDevices,omitempty"`
+	// Rego is an Open Policy Agent policy the operations are checked against too
+	Rego *RegoPolicy `json:"rego,omitempty"`
+}
+
+// ConfinementConfiguration confines the sandbox containers of the VMs, the ignite-spawn
+// and Firecracker processes, with a Linux security module. Every VM gets its own SELinux
+// categories or AppArmor profile, so a VM escaping Firecracker can't access the disks and
+// files of the other VMs.
+type ConfinementConfiguration struct {
+	// SELinux runs every sandbox with its own MCS categories, sVirt-style, and labels the
+	// directory and disks of the VM with them
+	SELinux bool `json:"selinux,omitempty"`
+	// SELinuxProcessType is the SELinux type of the sandbox processes
+	// Default: container_t
+	SELinuxProcessType string `json:"selinuxProcessType,omitempty"`
+	// SELinuxFileType is the SELinux type the directories and disks of the VMs are labeled with
+	// Default: container_file_t
+	SELinuxFileType string `json:"selinuxFileType,omitempty"`
+	// AppArmor confines every sandbox with an AppArmor profile generated for the VM, which
+	// only allows writing its own directory and disks
+	AppArmor bool `json:"appArmor,omitempty"`
+}
+
+// VaultConfiguration configures the HashiCorp Vault server the secrets of the VMs, and the
+// SSH keys authorized in them, are read from. The paths are the paths of the Vault HTTP API
+// without the /v1 prefix, e.g. secret/data/db for the KV version 2 engine mounted at secret.
+type VaultConfiguration struct {
+	// Address is the URL of the Vault server, e.g. https://vault.example.com:8200
+	// Default: $VAULT_ADDR
+	Address string `json:"address,omitempty"`
+	// TokenFile is the file holding the token ignite authenticates with
+	// Default: unset, the token is read from $VAULT_TOKEN
+	TokenFile string `json:"tokenFile,omitempty"`
+	// CAFile is the CA certificate the certificate of the Vault server is verified with
+	// Default: unset, the system CAs are used
+	CAFile string `json:"caFile,omitempty"`
+	// Namespace is the Vault Enterprise namespace of the paths
+	Namespace string `json:"namespace,omitempty"`
+	// SSHKeysPath is the path of a secret whose fields are public keys in the authorized_keys
+	// format. They're authorized for root in every VM created.
+	SSHKeysPath string `json:"sshKeysPath,omitempty"`
+}
+
+// RegoPolicy is an Open Policy Agent policy written in Rego, evaluated by opa. Its input
+// is the operation, "image.import", "kernel.import", "vm.create" or "vm.start", the OCI
+// image imported, and the VM created or started.
+type RegoPolicy struct {
+	// Path is the Rego file, or the directory of the Rego files, of the policy
+	Path string `json:"path"`
+	// Query evaluates to the reasons the operation is denied for, it's allowed if there are none
+	// Default: data.ignite.deny
+	Query string `json:"query,omitempty"`
+	// OPA is the opa binary the policy is evaluated with
+	// Default: opa looked up in $PATH
+	OPA string `json:"opa,omitempty"`
+}
+
+// GitOpsEnvironment is a branch and directory of the gitops repository that's synced
+// with its own sync policy
+type GitOpsEnvironment struct {
+	// Name identifies the environment, it's set as the ignite.weave.works/environment label of its VMs
+	Name string `json:"name"`
+	// Branch is the branch the manifests of the environment are on
+	// Default: the branch given to "ignited gitops"
+	Branch string `json:"branch,omitempty"`
+	// Path is the directory of the manifests in the repository, relative to its root
+	// Default: unset, the whole repository
+	Path string `json:"path,omitempty"`
+	// Labels are set on the VMs of the environment, in addition to the environment label
+	Labels map[string]string `json:"labels,omitempty"`
+	// SyncPolicy configures how the environment is synced
+	SyncPolicy GitOpsSyncPolicy `json:"syncPolicy,omitempty"`
+}
+
+// GitOpsSyncPolicy configures how a gitops environment is synced
+type GitOpsSyncPolicy struct {
+	// Interval is how often the branch is pulled, and the status of the VMs pushed
+	// Default: the interval given to "ignited gitops"
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// DryRun only logs the VMs that would be created, started, stopped or removed
+	// Default: false
+	DryRun bool `json:"dryRun,omitempty"`
+	// DriftInterval is how often the VMs are compared to their manifests, and corrected if
+	// they drifted from them, e.g. restarted if they stopped. Zero disables drift detection.
+	// Default: the drift interval given to "ignited gitops"
+	DriftInterval metav1.Duration `json:"driftInterval,omitempty"`
+	// Prune determines what happens to the VMs whose manifests are removed
+	// Default: the prune policy given to "ignited gitops"
+	Prune PrunePolicy `json:"prune,omitempty"`
+	// PruneGracePeriod is how long the VMs are kept after their manifests are removed, they
+	// aren't pruned if their manifests come back meanwhile, e.g. when they're moved.
+	// Default: the prune grace period given to "ignited gitops"
+	PruneGracePeriod metav1.Duration `json:"pruneGracePeriod,omitempty"`
+}
+
+// PrunePolicy determines what happens to a VM when its manifest is removed
+type PrunePolicy string
+
+const (
+	// PrunePolicyDelete removes the VM from the host
+	PrunePolicyDelete PrunePolicy = "delete"
+	// PrunePolicyStop stops the VM, and keeps it on the host
+	PrunePolicyStop PrunePolicy = "stop"
+	// PrunePolicyOrphan keeps the VM running on the host, it's only flagged as pruned
+	PrunePolicyOrphan PrunePolicy = "orphan"
+)
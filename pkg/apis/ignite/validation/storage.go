@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/nbd"
 	"github.com/weaveworks/ignite/pkg/util"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -28,6 +30,25 @@ func ValidateBlockDeviceVolume(b *api.BlockDeviceVolume, fldPath *field.Path, pa
 	return
 }
 
+// ValidateVolumeName validates that the volume name is a DNS-1123 label, as the name
+// is used as the file name of the volume in the VM container
+func ValidateVolumeName(name string, fldPath *field.Path) (allErrs field.ErrorList) {
+	for _, e := range validation.IsDNS1123Label(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, e))
+	}
+
+	return
+}
+
+// ValidateNBDVolume validates if the NBDVolume is valid
+func ValidateNBDVolume(n *api.NBDVolume, fldPath *field.Path) (allErrs field.ErrorList) {
+	if _, _, _, err := nbd.ParseURL(n.URL); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), n.URL, err.Error()))
+	}
+
+	return
+}
+
 // ValidateVMStorage validates if the VMStorageSpec is valid
 func ValidateVMStorage(s *api.VMStorageSpec, fldPath *field.Path) (allErrs field.ErrorList) {
 	// names keeps track of volume names and if they have a respective volumeMount
@@ -40,14 +61,19 @@ func ValidateVMStorage(s *api.VMStorageSpec, fldPath *field.Path) (allErrs field
 	// volume validation
 	for i, volume := range s.Volumes {
 		volumeFldPath := fldPath.Child(fmt.Sprintf("[%d]", i))
-		allErrs = append(allErrs, ValidateNonemptyName(volume.Name, volumeFldPath.Child("name"))...)
+		allErrs = append(allErrs, ValidateVolumeName(volume.Name, volumeFldPath.Child("name"))...)
 
-		// For now require and validate the BlockDevice entry
+		// Require exactly one of the BlockDevice, Tmpfs or NBD entries
 		blockDevFldPath := volumeFldPath.Child("blockDevice")
-		if volume.BlockDevice == nil {
-			allErrs = append(allErrs, field.Invalid(blockDevFldPath, nil, "blockDevice must be non-nil"))
-		} else {
+		switch sources := countVolumeSources(&volume); {
+		case sources > 1:
+			allErrs = append(allErrs, field.Invalid(volumeFldPath, volume.Name, "only one of blockDevice, tmpfs or nbd may be set"))
+		case volume.BlockDevice != nil:
 			allErrs = append(allErrs, ValidateBlockDeviceVolume(volume.BlockDevice, blockDevFldPath, blockDevPaths)...)
+		case volume.NBD != nil:
+			allErrs = append(allErrs, ValidateNBDVolume(volume.NBD, volumeFldPath.Child("nbd"))...)
+		case sources == 0:
+			allErrs = append(allErrs, field.Invalid(blockDevFldPath, nil, "blockDevice, tmpfs or nbd must be non-nil"))
 		}
 
 		// Validate volume name uniqueness
@@ -86,5 +112,41 @@ func ValidateVMStorage(s *api.VMStorageSpec, fldPath *field.Path) (allErrs field
 		}
 	}
 
+	// writablePaths validation
+	writablePathsFldPath := fldPath.Child("writablePaths")
+	if len(s.WritablePaths) > 0 && !s.ReadOnlyRoot {
+		allErrs = append(allErrs, field.Invalid(writablePathsFldPath, s.WritablePaths, "writablePaths require readOnlyRoot"))
+	}
+
+	writablePaths := make(map[string]struct{}, len(s.WritablePaths))
+	for i, p := range s.WritablePaths {
+		pathFldPath := writablePathsFldPath.Index(i)
+		allErrs = append(allErrs, ValidateAbsolutePath(p, pathFldPath)...)
+		if p == "/" {
+			allErrs = append(allErrs, field.Invalid(pathFldPath, p, "the root can't be a writable path"))
+		}
+
+		if _, ok := writablePaths[p]; ok {
+			allErrs = append(allErrs, field.Invalid(pathFldPath, p, "writable path must be unique"))
+		} else {
+			writablePaths[p] = struct{}{}
+		}
+	}
+
+	return
+}
+
+// countVolumeSources returns the number of sources set for the volume
+func countVolumeSources(volume *api.Volume) (count int) {
+	if volume.BlockDevice != nil {
+		count++
+	}
+	if volume.Tmpfs != nil {
+		count++
+	}
+	if volume.NBD != nil {
+		count++
+	}
+
 	return
 }
@@ -3,9 +3,17 @@ package validation
 import (
 	"fmt"
 	"path"
+	"regexp"
+	"strings"
+	"time"
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/authorizedkeys"
+	"github.com/weaveworks/ignite/pkg/backup"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/network"
+	"github.com/weaveworks/ignite/pkg/runtime"
 	"github.com/weaveworks/ignite/pkg/util"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -14,12 +22,382 @@ import (
 // ValidateVM validates a VM object and collects all encountered errors
 func ValidateVM(obj *api.VM) (allErrs field.ErrorList) {
 	allErrs = append(allErrs, ValidateVMName(obj.GetName(), field.NewPath("metadata.name"))...)
-	allErrs = append(allErrs, RequireOCIImageRef(&obj.Spec.Image.OCI, field.NewPath(".spec.image.oci"))...)
-	allErrs = append(allErrs, RequireOCIImageRef(&obj.Spec.Kernel.OCI, field.NewPath(".spec.kernel.oci"))...)
-	allErrs = append(allErrs, ValidateFileMappings(&obj.Spec.CopyFiles, field.NewPath(".spec.copyFiles"))...)
-	allErrs = append(allErrs, ValidateVMStorage(&obj.Spec.Storage, field.NewPath(".spec.storage"))...)
-	// TODO: Add vCPU, memory, disk max and min sizes
-	// TODO: Add port mapping validation
+	allErrs = append(allErrs, ValidateLabels(obj.GetObjectMeta().Labels, field.NewPath("metadata.labels"))...)
+	allErrs = append(allErrs, ValidateVMSpec(&obj.Spec, field.NewPath(".spec"))...)
+	for i, name := range obj.Spec.AutostartAfter {
+		if name == obj.GetName() || name == obj.GetUID().String() {
+			allErrs = append(allErrs, field.Invalid(field.NewPath(".spec").Child("autostartAfter").Index(i), name, "a VM can't be autostarted after itself"))
+		}
+	}
+	allErrs = append(allErrs, ValidateKernelCmdLine(obj, field.NewPath(".spec.kernel.cmdLine"))...)
+	return
+}
+
+// ValidateVMTemplate validates a VMTemplate object and collects all encountered errors
+func ValidateVMTemplate(obj *api.VMTemplate) (allErrs field.ErrorList) {
+	allErrs = append(allErrs, ValidateVMName(obj.GetName(), field.NewPath("metadata.name"))...)
+	allErrs = append(allErrs, ValidateLabels(obj.GetObjectMeta().Labels, field.NewPath("metadata.labels"))...)
+	allErrs = append(allErrs, ValidateVMSpec(&obj.Spec, field.NewPath(".spec"))...)
+	return
+}
+
+// ValidateImage validates an Image object and collects all encountered errors
+func ValidateImage(obj *api.Image) (allErrs field.ErrorList) {
+	allErrs = append(allErrs, ValidateLabels(obj.GetObjectMeta().Labels, field.NewPath("metadata.labels"))...)
+	allErrs = append(allErrs, RequireOCIImageRef(&obj.Spec.OCI, field.NewPath(".spec.oci"))...)
+	return
+}
+
+// ValidateKernel validates a Kernel object and collects all encountered errors
+func ValidateKernel(obj *api.Kernel) (allErrs field.ErrorList) {
+	allErrs = append(allErrs, ValidateLabels(obj.GetObjectMeta().Labels, field.NewPath("metadata.labels"))...)
+	allErrs = append(allErrs, RequireOCIImageRef(&obj.Spec.OCI, field.NewPath(".spec.oci"))...)
+	return
+}
+
+// ValidateVMSpec validates the spec of a VM or VMTemplate
+func ValidateVMSpec(spec *api.VMSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	allErrs = append(allErrs, RequireOCIImageRef(&spec.Image.OCI, fldPath.Child("image.oci"))...)
+	allErrs = append(allErrs, RequireOCIImageRef(&spec.Kernel.OCI, fldPath.Child("kernel.oci"))...)
+	if spec.Kernel.InitrdRef != nil {
+		allErrs = append(allErrs, RequireOCIImageRef(spec.Kernel.InitrdRef, fldPath.Child("kernel.initrdRef"))...)
+	}
+	allErrs = append(allErrs, ValidateVMCPUs(spec.CPUs, fldPath.Child("cpus"))...)
+	allErrs = append(allErrs, ValidateVMMemory(spec.Memory, fldPath.Child("memory"))...)
+	allErrs = append(allErrs, ValidateVMMemoryHugepages(spec.MemoryHugepages, spec.Memory, fldPath.Child("memory.hugepages"))...)
+	allErrs = append(allErrs, ValidatePortMappings(spec.Network.Ports, fldPath.Child("network.ports"))...)
+	allErrs = append(allErrs, ValidateSSH(spec.SSH, fldPath.Child("ssh"))...)
+	allErrs = append(allErrs, ValidateFileMappings(&spec.CopyFiles, fldPath.Child("copyFiles"))...)
+	allErrs = append(allErrs, ValidateVMStorage(&spec.Storage, fldPath.Child("storage"))...)
+	if spec.Backup != nil {
+		allErrs = append(allErrs, ValidateVMBackup(spec.Backup, fldPath.Child("backup"))...)
+	}
+	for i, name := range spec.AutostartAfter {
+		allErrs = append(allErrs, ValidateVMName(name, fldPath.Child("autostartAfter").Index(i))...)
+	}
+	allErrs = append(allErrs, ValidateRestartPolicy(spec.RestartPolicy, fldPath.Child("restartPolicy"))...)
+	if spec.LivenessProbe != nil {
+		allErrs = append(allErrs, ValidateProbe(spec.LivenessProbe, fldPath.Child("livenessProbe"))...)
+	}
+	if spec.Seccomp != nil {
+		allErrs = append(allErrs, ValidateSeccomp(spec.Seccomp, fldPath.Child("seccomp"))...)
+	}
+	allErrs = append(allErrs, ValidateSysctls(spec.Sysctls, fldPath.Child("sysctls"))...)
+	allErrs = append(allErrs, ValidateEnv(spec.Env, fldPath.Child("env"))...)
+	allErrs = append(allErrs, ValidateUsers(spec.Users, fldPath.Child("users"))...)
+	allErrs = append(allErrs, ValidateSecrets(spec.Secrets, fldPath.Child("secrets"))...)
+	allErrs = append(allErrs, ValidateVMProviders(spec.Runtime, spec.NetworkPlugin, fldPath)...)
+	if len(spec.Firmware) > 0 {
+		allErrs = append(allErrs, ValidateAbsolutePath(spec.Firmware, fldPath.Child("firmware"))...)
+	}
+	allErrs = append(allErrs, ValidateDevices(spec.Devices, fldPath.Child("devices"))...)
+	return
+}
+
+// ValidateVMCPUs validates the vCPU count, Firecracker supports 1 or an even number up to VM_MAX_CPUS
+func ValidateVMCPUs(cpus uint64, fldPath *field.Path) (allErrs field.ErrorList) {
+	if cpus == 0 || cpus > constants.VM_MAX_CPUS || (cpus > 1 && cpus%2 != 0) {
+		allErrs = append(allErrs, field.Invalid(fldPath, int64(cpus), fmt.Sprintf("must be 1 or an even number up to %d", constants.VM_MAX_CPUS)))
+	}
+
+	return
+}
+
+// ValidateVMMemory validates that the memory is large enough to boot a VM
+func ValidateVMMemory(memory meta.Size, fldPath *field.Path) (allErrs field.ErrorList) {
+	if memory.Bytes() < constants.VM_MIN_MEMORY {
+		allErrs = append(allErrs, field.Invalid(fldPath, memory.String(), fmt.Sprintf("must be at least %s", meta.NewSizeFromBytes(constants.VM_MIN_MEMORY))))
+	}
+
+	return
+}
+
+// ValidateVMMemoryHugepages validates the hugepage size, the memory needs to be a multiple of it
+func ValidateVMMemoryHugepages(hugepages api.HugepageSize, memory meta.Size, fldPath *field.Path) (allErrs field.ErrorList) {
+	if len(hugepages) == 0 {
+		return
+	}
+
+	if hugepages.Bytes() == 0 {
+		allErrs = append(allErrs, field.NotSupported(fldPath, hugepages, []string{
+			string(api.HugepageSize2Mi),
+			string(api.HugepageSize1Gi),
+		}))
+	} else if memory.Bytes()%hugepages.Bytes() != 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, hugepages, fmt.Sprintf("the memory (%s) must be a multiple of the hugepage size", memory)))
+	}
+
+	return
+}
+
+// ValidatePortMappings validates the port ranges and protocols of the port mappings,
+// and that no host port is mapped twice
+func ValidatePortMappings(ports meta.PortMappings, fldPath *field.Path) (allErrs field.ErrorList) {
+	hostPorts := make(map[string]struct{}, len(ports))
+	for i, port := range ports {
+		portFldPath := fldPath.Index(i)
+		allErrs = append(allErrs, ValidatePort(port.HostPort, portFldPath.Child("hostPort"))...)
+		allErrs = append(allErrs, ValidatePort(port.VMPort, portFldPath.Child("vmPort"))...)
+
+		switch port.Protocol {
+		case "", meta.ProtocolTCP, meta.ProtocolUDP:
+		default:
+			allErrs = append(allErrs, field.NotSupported(portFldPath.Child("protocol"), port.Protocol, []string{
+				string(meta.ProtocolTCP),
+				string(meta.ProtocolUDP),
+			}))
+		}
+
+		protocol := port.Protocol
+		if len(protocol) == 0 {
+			protocol = meta.ProtocolTCP
+		}
+
+		key := fmt.Sprintf("%s:%d/%s", port.BindAddress, port.HostPort, protocol)
+		if _, ok := hostPorts[key]; ok {
+			allErrs = append(allErrs, field.Duplicate(portFldPath.Child("hostPort"), int64(port.HostPort)))
+		}
+		hostPorts[key] = struct{}{}
+	}
+
+	return
+}
+
+// ValidatePort validates that the port is in the 1-65535 range
+func ValidatePort(port uint64, fldPath *field.Path) (allErrs field.ErrorList) {
+	if port == 0 || port > 65535 {
+		allErrs = append(allErrs, field.Invalid(fldPath, int64(port), "must be between 1 and 65535"))
+	}
+
+	return
+}
+
+// ValidateSSH validates that a public key isn't combined with generating one,
+// and that the authorized keys are public keys in the authorized_keys format
+func ValidateSSH(ssh *api.SSH, fldPath *field.Path) (allErrs field.ErrorList) {
+	if ssh == nil {
+		return
+	}
+
+	if ssh.Generate && len(ssh.PublicKey) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, ssh.PublicKey, "a public key can't be given when generating one"))
+	}
+
+	for i, key := range ssh.AuthorizedKeys {
+		if _, err := authorizedkeys.Parse(key); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("authorizedKeys").Index(i), key, fmt.Sprintf("must be a public key in the authorized_keys format: %v", err)))
+		}
+	}
+
+	return
+}
+
+// sysctlNameRegexp matches sysctl names separated by dots or slashes, e.g. net.ipv4.ip_forward
+var sysctlNameRegexp = regexp.MustCompile(`^([a-z0-9]([-_a-z0-9]*[a-z0-9])?[./])*[a-z0-9]([-_a-z0-9]*[a-z0-9])?$`)
+
+// ValidateSysctls validates the sysctl names and values, which are passed on the kernel command line
+func ValidateSysctls(sysctls map[string]string, fldPath *field.Path) (allErrs field.ErrorList) {
+	for name, value := range sysctls {
+		if !sysctlNameRegexp.MatchString(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath, name, "must be a sysctl name, e.g. fs.inotify.max_user_watches"))
+		}
+
+		if len(strings.TrimSpace(value)) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Key(name), "a value is required"))
+		} else if strings.ContainsAny(value, "\"\n") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), value, "must not contain quotes or newlines"))
+		}
+	}
+
+	return
+}
+
+// envNameRegexp matches environment variable names that can be used in shells
+var envNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateEnv validates the names and values of the environment variables of the guest
+func ValidateEnv(env map[string]string, fldPath *field.Path) (allErrs field.ErrorList) {
+	for name, value := range env {
+		if !envNameRegexp.MatchString(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath, name, "must consist of letters, digits and underscores, and not start with a digit"))
+		}
+
+		if strings.ContainsAny(value, "\n\x00") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), value, "must not contain newlines or NUL characters"))
+		}
+	}
+
+	return
+}
+
+// secretNameRegexp matches the names of secrets, which are file names in the guest
+var secretNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// ValidateSecrets validates the names, sources and modes of the secrets of the guest
+func ValidateSecrets(secrets []api.VMSecret, fldPath *field.Path) (allErrs field.ErrorList) {
+	names := make(map[string]struct{}, len(secrets))
+	for i, secret := range secrets {
+		secretFldPath := fldPath.Index(i)
+		if !secretNameRegexp.MatchString(secret.Name) {
+			allErrs = append(allErrs, field.Invalid(secretFldPath.Child("name"), secret.Name, "must be a file name of letters, digits, '_', '-' and '.'"))
+		}
+
+		if _, ok := names[secret.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(secretFldPath.Child("name"), secret.Name))
+		} else {
+			names[secret.Name] = struct{}{}
+		}
+
+		sources := 0
+		for _, source := range []string{secret.File, secret.Env, secret.Vault} {
+			if len(source) != 0 {
+				sources++
+			}
+		}
+
+		switch {
+		case sources > 1:
+			allErrs = append(allErrs, field.Invalid(secretFldPath, secret.Name, "only one of file, env or vault may be set"))
+		case len(secret.File) != 0:
+			allErrs = append(allErrs, ValidateAbsolutePath(secret.File, secretFldPath.Child("file"))...)
+		case len(secret.Env) != 0:
+			if !envNameRegexp.MatchString(secret.Env) {
+				allErrs = append(allErrs, field.Invalid(secretFldPath.Child("env"), secret.Env, "must consist of letters, digits and underscores, and not start with a digit"))
+			}
+		case len(secret.Vault) != 0:
+			if i := strings.LastIndex(secret.Vault, "#"); i <= 0 || i == len(secret.Vault)-1 {
+				allErrs = append(allErrs, field.Invalid(secretFldPath.Child("vault"), secret.Vault, "must be a field of a Vault secret, as <path>#<field>"))
+			}
+		default:
+			allErrs = append(allErrs, field.Required(secretFldPath, "file, env or vault must be set"))
+		}
+
+		if secret.Mode&^0777 != 0 {
+			allErrs = append(allErrs, field.Invalid(secretFldPath.Child("mode"), secret.Mode, "must be file permissions, at most 0777"))
+		}
+	}
+
+	return
+}
+
+// pciAddressRegexp matches PCI addresses in the domain:bus:device.function format of sysfs
+var pciAddressRegexp = regexp.MustCompile(`^[0-9a-f]{4}:[0-9a-f]{2}:[0-1][0-9a-f]\.[0-7]$`)
+
+// ValidateDevices validates the PCI addresses of the host devices passed through into the VM
+func ValidateDevices(devices []api.PCIDevice, fldPath *field.Path) (allErrs field.ErrorList) {
+	addresses := make(map[string]bool, len(devices))
+	for i, device := range devices {
+		addressPath := fldPath.Index(i).Child("address")
+		if !pciAddressRegexp.MatchString(device.Address) {
+			allErrs = append(allErrs, field.Invalid(addressPath, device.Address, "must be a lowercase PCI address, e.g. 0000:01:00.0"))
+		} else if addresses[device.Address] {
+			allErrs = append(allErrs, field.Duplicate(addressPath, device.Address))
+		}
+		addresses[device.Address] = true
+	}
+
+	return
+}
+
+// ValidateVMProviders validates the runtime and network plugin overrides of a VM
+func ValidateVMProviders(runtimeName runtime.Name, networkPlugin network.PluginName, fldPath *field.Path) (allErrs field.ErrorList) {
+	switch runtimeName {
+	case "", runtime.RuntimeDocker, runtime.RuntimeContainerd:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("runtime"), runtimeName, []string{
+			string(runtime.RuntimeDocker),
+			string(runtime.RuntimeContainerd),
+		}))
+	}
+
+	switch networkPlugin {
+	case "", network.PluginCNI, network.PluginDockerBridge:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("networkPlugin"), networkPlugin, []string{
+			string(network.PluginCNI),
+			string(network.PluginDockerBridge),
+		}))
+	}
+
+	// The docker-bridge network plugin only works with the docker runtime
+	if networkPlugin == network.PluginDockerBridge && len(runtimeName) > 0 && runtimeName != runtime.RuntimeDocker {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("networkPlugin"), networkPlugin, fmt.Sprintf("can only be used with the %q runtime", runtime.RuntimeDocker)))
+	}
+
+	return
+}
+
+// userNameRegexp matches the user and group names accepted by useradd and groupadd
+var userNameRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,30}$`)
+
+// ValidateUsers validates the users of the guest
+func ValidateUsers(users []api.VMUser, fldPath *field.Path) (allErrs field.ErrorList) {
+	names := map[string]bool{}
+	uids := map[uint32]bool{}
+	for i, user := range users {
+		userPath := fldPath.Index(i)
+		if !userNameRegexp.MatchString(user.Name) {
+			allErrs = append(allErrs, field.Invalid(userPath.Child("name"), user.Name, "must be a lowercase user name of at most 31 characters"))
+		} else if names[user.Name] {
+			allErrs = append(allErrs, field.Duplicate(userPath.Child("name"), user.Name))
+		}
+		names[user.Name] = true
+
+		if user.UID != nil {
+			if *user.UID == 0 && user.Name != "root" {
+				allErrs = append(allErrs, field.Invalid(userPath.Child("uid"), *user.UID, "UID 0 is reserved for root"))
+			} else if uids[*user.UID] {
+				allErrs = append(allErrs, field.Duplicate(userPath.Child("uid"), *user.UID))
+			}
+			uids[*user.UID] = true
+		}
+
+		for j, group := range user.Groups {
+			if !userNameRegexp.MatchString(group) {
+				allErrs = append(allErrs, field.Invalid(userPath.Child("groups").Index(j), group, "must be a lowercase group name of at most 31 characters"))
+			}
+		}
+
+		if len(user.PasswordHash) > 0 && (!strings.HasPrefix(user.PasswordHash, "$") || strings.ContainsAny(user.PasswordHash, ": \t\n")) {
+			allErrs = append(allErrs, field.Invalid(userPath.Child("passwordHash"), "<hidden>", "must be a password hash in the crypt(3) format, e.g. from mkpasswd -m sha-512"))
+		}
+
+		for j, key := range user.AuthorizedKeys {
+			if _, err := authorizedkeys.Parse(key); err != nil {
+				allErrs = append(allErrs, field.Invalid(userPath.Child("authorizedKeys").Index(j), key, fmt.Sprintf("must be a public key in the authorized_keys format: %v", err)))
+			}
+		}
+	}
+
+	return
+}
+
+// ValidateKernelCmdLine validates that the variables the kernel command line of the VM
+// references with the reserved prefix are built in. Other variables that aren't set for
+// the VM are passed on as they are.
+func ValidateKernelCmdLine(vm *api.VM, fldPath *field.Path) (allErrs field.ErrorList) {
+	vars := vm.KernelCmdLineVars()
+	for _, name := range api.KernelCmdLineBuiltinVars {
+		vars[name] = ""
+	}
+
+	if _, err := api.ExpandKernelCmdLine(vm.Spec.Kernel.CmdLine, vars); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, vm.Spec.Kernel.CmdLine, err.Error()))
+	}
+	return
+}
+
+// ValidateLabels validates the label keys and values, which follow the Kubernetes label format
+func ValidateLabels(labels map[string]string, fldPath *field.Path) (allErrs field.ErrorList) {
+	for k, v := range labels {
+		for _, e := range validation.IsQualifiedName(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath, k, e))
+		}
+		for _, e := range validation.IsValidLabelValue(v) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(k), v, e))
+		}
+	}
+
 	return
 }
 
@@ -53,6 +431,99 @@ func ValidateFileMappings(mappings *[]api.FileMapping, fldPath *field.Path) (all
 	return
 }
 
+// ValidateVMBackup validates if the VMBackupSpec is valid
+func ValidateVMBackup(b *api.VMBackupSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if _, err := backup.ParseSchedule(b.Schedule); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), b.Schedule, err.Error()))
+	}
+
+	// The destination is either an S3 URL or a local directory
+	destFldPath := fldPath.Child("destination")
+	if strings.HasPrefix(b.Destination, "s3://") {
+		if len(strings.Trim(strings.TrimPrefix(b.Destination, "s3://"), "/")) == 0 {
+			allErrs = append(allErrs, field.Invalid(destFldPath, b.Destination, "the S3 destination must contain a bucket"))
+		}
+	} else {
+		allErrs = append(allErrs, ValidateAbsolutePath(b.Destination, destFldPath)...)
+	}
+
+	return
+}
+
+// ValidateRestartPolicy validates that the restart policy is a known one, or unset
+func ValidateRestartPolicy(policy api.RestartPolicy, fldPath *field.Path) (allErrs field.ErrorList) {
+	switch policy {
+	case "", api.RestartPolicyAlways, api.RestartPolicyOnFailure, api.RestartPolicyNever:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath, policy, []string{
+			string(api.RestartPolicyAlways),
+			string(api.RestartPolicyOnFailure),
+			string(api.RestartPolicyNever),
+		}))
+	}
+
+	return
+}
+
+// ValidateProbe validates that exactly one way of probing is set, and that it has a port
+// or a command to probe
+func ValidateProbe(probe *api.VMProbe, fldPath *field.Path) (allErrs field.ErrorList) {
+	set := 0
+	if probe.TCP != nil {
+		set++
+		allErrs = append(allErrs, ValidatePort(uint64(probe.TCP.Port), fldPath.Child("tcp.port"))...)
+	}
+
+	if probe.HTTP != nil {
+		set++
+		allErrs = append(allErrs, ValidatePort(uint64(probe.HTTP.Port), fldPath.Child("http.port"))...)
+		if len(probe.HTTP.Path) > 0 && !strings.HasPrefix(probe.HTTP.Path, "/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("http.path"), probe.HTTP.Path, "must start with /"))
+		}
+	}
+
+	if probe.Exec != nil {
+		set++
+		if len(probe.Exec.Command) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("exec.command"), "the command to run is required"))
+		}
+	}
+
+	if set != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, set, "exactly one of tcp, http and exec must be set"))
+	}
+
+	for _, d := range []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"initialDelay", probe.InitialDelay.Duration},
+		{"period", probe.Period.Duration},
+		{"timeout", probe.Timeout.Duration},
+	} {
+		if d.duration < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(d.name), d.duration.String(), "must not be negative"))
+		}
+	}
+
+	return
+}
+
+// ValidatePrunePolicy validates that the prune policy is a known one, or unset
+func ValidatePrunePolicy(policy api.PrunePolicy, fldPath *field.Path) (allErrs field.ErrorList) {
+	switch policy {
+	case "", api.PrunePolicyDelete, api.PrunePolicyStop, api.PrunePolicyOrphan:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath, policy, []string{
+			string(api.PrunePolicyDelete),
+			string(api.PrunePolicyStop),
+			string(api.PrunePolicyOrphan),
+		}))
+	}
+
+	return
+}
+
 // ValidateNonemptyName validated that the given name is nonempty
 func ValidateNonemptyName(name string, fldPath *field.Path) (allErrs field.ErrorList) {
 	if util.IsEmptyString(name) {
@@ -70,3 +541,24 @@ func ValidateAbsolutePath(pathStr string, fldPath *field.Path) (allErrs field.Er
 
 	return
 }
+
+// ValidateSeccomp validates the seccomp level, or that the custom filter is an absolute path
+func ValidateSeccomp(seccomp *api.VMSeccompSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	switch seccomp.Level {
+	case "", api.SeccompLevelAdvanced, api.SeccompLevelBasic, api.SeccompLevelDisabled:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("level"), seccomp.Level,
+			[]string{string(api.SeccompLevelAdvanced), string(api.SeccompLevelBasic), string(api.SeccompLevelDisabled)}))
+	}
+
+	if len(seccomp.Filter) > 0 {
+		if !path.IsAbs(seccomp.Filter) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("filter"), seccomp.Filter, "must be an absolute path"))
+		}
+		if len(seccomp.Level) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, seccomp.Level, "only one of level and filter may be set"))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,465 @@
+package validation
+
+import (
+	"net"
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+func TestValidateVMSpec(t *testing.T) {
+	ociRef, err := meta.NewOCIImageRef("foo/bar:latest")
+	if err != nil {
+		t.Fatalf("error parsing image: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		modify  func(spec *api.VMSpec)
+		wantErr string
+	}{
+		{
+			name:   "valid spec",
+			modify: func(spec *api.VMSpec) {},
+		},
+		{
+			name:    "no vCPUs",
+			modify:  func(spec *api.VMSpec) { spec.CPUs = 0 },
+			wantErr: ".spec.cpus",
+		},
+		{
+			name:    "odd vCPU count",
+			modify:  func(spec *api.VMSpec) { spec.CPUs = 3 },
+			wantErr: ".spec.cpus",
+		},
+		{
+			name:    "too many vCPUs",
+			modify:  func(spec *api.VMSpec) { spec.CPUs = 64 },
+			wantErr: ".spec.cpus",
+		},
+		{
+			name:    "too little memory",
+			modify:  func(spec *api.VMSpec) { spec.Memory = meta.NewSizeFromBytes(1024) },
+			wantErr: ".spec.memory",
+		},
+		{
+			name:   "2Mi hugepages",
+			modify: func(spec *api.VMSpec) { spec.MemoryHugepages = api.HugepageSize2Mi },
+		},
+		{
+			name:    "unsupported hugepage size",
+			modify:  func(spec *api.VMSpec) { spec.MemoryHugepages = "4Ki" },
+			wantErr: ".spec.memory.hugepages",
+		},
+		{
+			name:    "memory not a multiple of the hugepage size",
+			modify:  func(spec *api.VMSpec) { spec.MemoryHugepages = api.HugepageSize1Gi },
+			wantErr: ".spec.memory.hugepages",
+		},
+		{
+			name: "port out of range",
+			modify: func(spec *api.VMSpec) {
+				spec.Network.Ports = meta.PortMappings{{HostPort: 70000, VMPort: 80}}
+			},
+			wantErr: ".spec.network.ports[0].hostPort",
+		},
+		{
+			name: "unsupported protocol",
+			modify: func(spec *api.VMSpec) {
+				spec.Network.Ports = meta.PortMappings{{HostPort: 8080, VMPort: 80, Protocol: "sctp"}}
+			},
+			wantErr: ".spec.network.ports[0].protocol",
+		},
+		{
+			name: "host port mapped twice",
+			modify: func(spec *api.VMSpec) {
+				spec.Network.Ports = meta.PortMappings{
+					{HostPort: 8080, VMPort: 80},
+					{HostPort: 8080, VMPort: 81, Protocol: meta.ProtocolTCP},
+				}
+			},
+			wantErr: ".spec.network.ports[1].hostPort",
+		},
+		{
+			name: "same host port on other addresses and protocols",
+			modify: func(spec *api.VMSpec) {
+				spec.Network.Ports = meta.PortMappings{
+					{HostPort: 8080, VMPort: 80},
+					{HostPort: 8080, VMPort: 80, Protocol: meta.ProtocolUDP},
+					{BindAddress: net.ParseIP("127.0.0.1"), HostPort: 8080, VMPort: 80},
+				}
+			},
+		},
+		{
+			name: "generated and given SSH key",
+			modify: func(spec *api.VMSpec) {
+				spec.SSH = &api.SSH{Generate: true, PublicKey: "ssh-rsa AAAA"}
+			},
+			wantErr: ".spec.ssh",
+		},
+		{
+			name: "invalid authorized key",
+			modify: func(spec *api.VMSpec) {
+				spec.SSH = &api.SSH{Generate: true, AuthorizedKeys: []string{"ssh-ed25519 not-base64"}}
+			},
+			wantErr: ".spec.ssh.authorizedKeys[0]",
+		},
+		{
+			name: "valid sysctls",
+			modify: func(spec *api.VMSpec) {
+				spec.Sysctls = map[string]string{
+					"fs.inotify.max_user_watches": "524288",
+					"net.ipv4.ping_group_range":   "0 2147483647",
+				}
+			},
+		},
+		{
+			name:    "invalid sysctl name",
+			modify:  func(spec *api.VMSpec) { spec.Sysctls = map[string]string{"fs inotify": "1"} },
+			wantErr: ".spec.sysctls",
+		},
+		{
+			name:    "empty sysctl value",
+			modify:  func(spec *api.VMSpec) { spec.Sysctls = map[string]string{"vm.swappiness": " "} },
+			wantErr: ".spec.sysctls[vm.swappiness]",
+		},
+		{
+			name: "valid env",
+			modify: func(spec *api.VMSpec) {
+				spec.Env = map[string]string{"HTTP_PROXY": "http://proxy:3128", "_x": ""}
+			},
+		},
+		{
+			name:    "invalid env name",
+			modify:  func(spec *api.VMSpec) { spec.Env = map[string]string{"1FOO": "bar"} },
+			wantErr: ".spec.env",
+		},
+		{
+			name:    "env value with newline",
+			modify:  func(spec *api.VMSpec) { spec.Env = map[string]string{"FOO": "bar\nbaz"} },
+			wantErr: ".spec.env[FOO]",
+		},
+		{
+			name: "valid users",
+			modify: func(spec *api.VMSpec) {
+				uid := uint32(1500)
+				spec.Users = []api.VMUser{
+					{Name: "root", PasswordHash: "$6$salt$hash"},
+					{Name: "dev", UID: &uid, Groups: []string{"docker"}, Sudo: true},
+				}
+			},
+		},
+		{
+			name:    "invalid user name",
+			modify:  func(spec *api.VMSpec) { spec.Users = []api.VMUser{{Name: "Dev"}} },
+			wantErr: ".spec.users[0].name",
+		},
+		{
+			name:    "duplicate user",
+			modify:  func(spec *api.VMSpec) { spec.Users = []api.VMUser{{Name: "dev"}, {Name: "dev"}} },
+			wantErr: ".spec.users[1].name",
+		},
+		{
+			name: "UID 0 for another user than root",
+			modify: func(spec *api.VMSpec) {
+				uid := uint32(0)
+				spec.Users = []api.VMUser{{Name: "dev", UID: &uid}}
+			},
+			wantErr: ".spec.users[0].uid",
+		},
+		{
+			name:    "plain text password",
+			modify:  func(spec *api.VMSpec) { spec.Users = []api.VMUser{{Name: "dev", PasswordHash: "hunter2"}} },
+			wantErr: ".spec.users[0].passwordHash",
+		},
+		{
+			name:    "invalid group name",
+			modify:  func(spec *api.VMSpec) { spec.Users = []api.VMUser{{Name: "dev", Groups: []string{"a:b"}}} },
+			wantErr: ".spec.users[0].groups[0]",
+		},
+		{
+			name: "docker runtime with docker-bridge",
+			modify: func(spec *api.VMSpec) {
+				spec.Runtime = "docker"
+				spec.NetworkPlugin = "docker-bridge"
+			},
+		},
+		{
+			name:    "unsupported runtime",
+			modify:  func(spec *api.VMSpec) { spec.Runtime = "podman" },
+			wantErr: ".spec.runtime",
+		},
+		{
+			name: "containerd runtime with docker-bridge",
+			modify: func(spec *api.VMSpec) {
+				spec.Runtime = "containerd"
+				spec.NetworkPlugin = "docker-bridge"
+			},
+			wantErr: ".spec.networkPlugin",
+		},
+		{
+			name:    "invalid autostartAfter name",
+			modify:  func(spec *api.VMSpec) { spec.AutostartAfter = []string{"My_VM"} },
+			wantErr: ".spec.autostartAfter[0]",
+		},
+		{
+			name: "HTTP liveness probe",
+			modify: func(spec *api.VMSpec) {
+				spec.LivenessProbe = &api.VMProbe{HTTP: &api.HTTPProbe{Port: 8080, Path: "/healthz"}, Restart: true}
+			},
+		},
+		{
+			name:    "liveness probe without a way of probing",
+			modify:  func(spec *api.VMSpec) { spec.LivenessProbe = &api.VMProbe{} },
+			wantErr: ".spec.livenessProbe",
+		},
+		{
+			name: "liveness probe with two ways of probing",
+			modify: func(spec *api.VMSpec) {
+				spec.LivenessProbe = &api.VMProbe{TCP: &api.TCPProbe{Port: 22}, Exec: &api.ExecProbe{Command: []string{"true"}}}
+			},
+			wantErr: ".spec.livenessProbe",
+		},
+		{
+			name:    "TCP liveness probe without a port",
+			modify:  func(spec *api.VMSpec) { spec.LivenessProbe = &api.VMProbe{TCP: &api.TCPProbe{}} },
+			wantErr: ".spec.livenessProbe.tcp.port",
+		},
+		{
+			name:    "exec liveness probe without a command",
+			modify:  func(spec *api.VMSpec) { spec.LivenessProbe = &api.VMProbe{Exec: &api.ExecProbe{}} },
+			wantErr: ".spec.livenessProbe.exec.command",
+		},
+		{
+			name:   "seccomp level",
+			modify: func(spec *api.VMSpec) { spec.Seccomp = &api.VMSeccompSpec{Level: api.SeccompLevelBasic} },
+		},
+		{
+			name:   "custom seccomp filter",
+			modify: func(spec *api.VMSpec) { spec.Seccomp = &api.VMSeccompSpec{Filter: "/etc/ignite/seccomp.bpf"} },
+		},
+		{
+			name:    "unknown seccomp level",
+			modify:  func(spec *api.VMSpec) { spec.Seccomp = &api.VMSeccompSpec{Level: "2"} },
+			wantErr: ".spec.seccomp.level",
+		},
+		{
+			name:    "relative seccomp filter",
+			modify:  func(spec *api.VMSpec) { spec.Seccomp = &api.VMSeccompSpec{Filter: "seccomp.bpf"} },
+			wantErr: ".spec.seccomp.filter",
+		},
+		{
+			name: "seccomp level and filter",
+			modify: func(spec *api.VMSpec) {
+				spec.Seccomp = &api.VMSeccompSpec{Level: api.SeccompLevelBasic, Filter: "/etc/ignite/seccomp.bpf"}
+			},
+			wantErr: ".spec.seccomp",
+		},
+		{
+			name:   "initrd image",
+			modify: func(spec *api.VMSpec) { spec.Kernel.InitrdRef = &ociRef },
+		},
+		{
+			name:    "empty initrd image",
+			modify:  func(spec *api.VMSpec) { spec.Kernel.InitrdRef = &meta.OCIImageRef{} },
+			wantErr: ".spec.kernel.initrdRef",
+		},
+		{
+			name:   "firmware",
+			modify: func(spec *api.VMSpec) { spec.Firmware = "/usr/share/OVMF/OVMF.fd" },
+		},
+		{
+			name:    "relative firmware path",
+			modify:  func(spec *api.VMSpec) { spec.Firmware = "OVMF.fd" },
+			wantErr: ".spec.firmware",
+		},
+		{
+			name: "devices",
+			modify: func(spec *api.VMSpec) {
+				spec.Devices = []api.PCIDevice{{Address: "0000:01:00.0"}, {Address: "0000:01:00.1"}}
+			},
+		},
+		{
+			name:    "short PCI address",
+			modify:  func(spec *api.VMSpec) { spec.Devices = []api.PCIDevice{{Address: "01:00.0"}} },
+			wantErr: ".spec.devices[0].address",
+		},
+		{
+			name: "duplicate device",
+			modify: func(spec *api.VMSpec) {
+				spec.Devices = []api.PCIDevice{{Address: "0000:01:00.0"}, {Address: "0000:01:00.0"}}
+			},
+			wantErr: ".spec.devices[1].address",
+		},
+		{
+			name: "secrets",
+			modify: func(spec *api.VMSpec) {
+				spec.Secrets = []api.VMSecret{{Name: "db-password", File: "/etc/ignite/db-password"}, {Name: "token", Env: "API_TOKEN", Mode: 0440}}
+			},
+		},
+		{
+			name:    "secret name with a slash",
+			modify:  func(spec *api.VMSpec) { spec.Secrets = []api.VMSecret{{Name: "../shadow", Env: "API_TOKEN"}} },
+			wantErr: ".spec.secrets[0].name",
+		},
+		{
+			name: "duplicate secret",
+			modify: func(spec *api.VMSpec) {
+				spec.Secrets = []api.VMSecret{{Name: "token", Env: "API_TOKEN"}, {Name: "token", Env: "OTHER_TOKEN"}}
+			},
+			wantErr: ".spec.secrets[1].name",
+		},
+		{
+			name:    "secret without a source",
+			modify:  func(spec *api.VMSpec) { spec.Secrets = []api.VMSecret{{Name: "token"}} },
+			wantErr: ".spec.secrets[0]",
+		},
+		{
+			name: "secret with two sources",
+			modify: func(spec *api.VMSpec) {
+				spec.Secrets = []api.VMSecret{{Name: "token", File: "/etc/token", Env: "API_TOKEN"}}
+			},
+			wantErr: ".spec.secrets[0]",
+		},
+		{
+			name:   "secret from Vault",
+			modify: func(spec *api.VMSpec) { spec.Secrets = []api.VMSecret{{Name: "token", Vault: "secret/data/app#token"}} },
+		},
+		{
+			name:    "Vault secret without a field",
+			modify:  func(spec *api.VMSpec) { spec.Secrets = []api.VMSecret{{Name: "token", Vault: "secret/data/app"}} },
+			wantErr: ".spec.secrets[0].vault",
+		},
+		{
+			name:    "relative secret file",
+			modify:  func(spec *api.VMSpec) { spec.Secrets = []api.VMSecret{{Name: "token", File: "token"}} },
+			wantErr: ".spec.secrets[0].file",
+		},
+		{
+			name: "read-only root with writable paths",
+			modify: func(spec *api.VMSpec) {
+				spec.Storage = api.VMStorageSpec{ReadOnlyRoot: true, WritablePaths: []string{"/tmp", "/var/lib/app"}}
+			},
+		},
+		{
+			name:    "writable paths without a read-only root",
+			modify:  func(spec *api.VMSpec) { spec.Storage.WritablePaths = []string{"/tmp"} },
+			wantErr: ".spec.storage.writablePaths",
+		},
+		{
+			name: "relative writable path",
+			modify: func(spec *api.VMSpec) {
+				spec.Storage = api.VMStorageSpec{ReadOnlyRoot: true, WritablePaths: []string{"tmp"}}
+			},
+			wantErr: ".spec.storage.writablePaths[0]",
+		},
+		{
+			name: "duplicate writable path",
+			modify: func(spec *api.VMSpec) {
+				spec.Storage = api.VMStorageSpec{ReadOnlyRoot: true, WritablePaths: []string{"/tmp", "/tmp"}}
+			},
+			wantErr: ".spec.storage.writablePaths[1]",
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			spec := &api.VMSpec{
+				Image:  api.VMImageSpec{OCI: ociRef},
+				Kernel: api.VMKernelSpec{OCI: ociRef},
+				CPUs:   2,
+				Memory: meta.NewSizeFromBytes(512 * 1024 * 1024),
+			}
+			rt.modify(spec)
+
+			errs := ValidateVMSpec(spec, field.NewPath(".spec"))
+			if len(rt.wantErr) == 0 {
+				assert.Equal(t, len(errs), 0, errs.ToAggregate())
+				return
+			}
+
+			assert.Equal(t, len(errs), 1, errs.ToAggregate())
+			assert.Equal(t, errs[0].Field, rt.wantErr)
+		})
+	}
+}
+
+func TestValidateVMAutostartAfterItself(t *testing.T) {
+	vm := &api.VM{}
+	vm.SetName("my-vm")
+	vm.Spec.AutostartAfter = []string{"my-vm"}
+
+	found := false
+	for _, err := range ValidateVM(vm) {
+		if err.Field == ".spec.autostartAfter[0]" {
+			found = true
+		}
+	}
+	assert.Assert(t, found, "expected autostartAfter to be rejected")
+}
+
+func TestValidateKernelCmdLine(t *testing.T) {
+	cases := []struct {
+		name        string
+		cmdLine     string
+		annotations map[string]string
+		err         bool
+	}{
+		{
+			name:    "no variables",
+			cmdLine: "console=ttyS0 ip=dhcp",
+		},
+		{
+			name:    "built-in variables",
+			cmdLine: "console=ttyS0 ip=${VM_IP}::${VM_GATEWAY}:${VM_NETMASK}:${VM_HOSTNAME}:eth0:off",
+		},
+		{
+			name:        "custom variable",
+			cmdLine:     "console=ttyS0 region=${REGION}",
+			annotations: map[string]string{"ignite.weave.works/cmdline-var/REGION": "eu-west-1"},
+		},
+		{
+			name:    "undefined variable passed on",
+			cmdLine: "console=ttyS0 -- --config=${CONFIG_DIR}/init.conf",
+		},
+		{
+			name:    "undefined variable with the reserved prefix",
+			cmdLine: "console=ttyS0 region=${VM_REGION}",
+			err:     true,
+		},
+		{
+			name:    "escaped variable with the reserved prefix",
+			cmdLine: "console=ttyS0 -- echo $${VM_REGION}",
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			vm := &api.VM{}
+			vm.Spec.Kernel.CmdLine = rt.cmdLine
+			vm.GetObjectMeta().Annotations = rt.annotations
+
+			errs := ValidateKernelCmdLine(vm, field.NewPath(".spec.kernel.cmdLine"))
+			assert.Equal(t, len(errs) > 0, rt.err, errs.ToAggregate())
+		})
+	}
+}
+
+func TestValidateImageAndKernel(t *testing.T) {
+	image := &api.Image{}
+	assert.Equal(t, len(ValidateImage(image)), 1, "the OCI reference is mandatory")
+
+	kernel := &api.Kernel{}
+	assert.Equal(t, len(ValidateKernel(kernel)), 1, "the OCI reference is mandatory")
+
+	ociRef, err := meta.NewOCIImageRef("weaveworks/ignite-kernel:5.10.51")
+	assert.NilError(t, err)
+	image.Spec.OCI = ociRef
+	kernel.Spec.OCI = ociRef
+	assert.Equal(t, len(ValidateImage(image)), 0)
+	assert.Equal(t, len(ValidateKernel(kernel)), 0)
+}
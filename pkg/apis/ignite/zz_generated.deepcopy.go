@@ -13,6 +13,22 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditConfiguration) DeepCopyInto(out *AuditConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditConfiguration.
+func (in *AuditConfiguration) DeepCopy() *AuditConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlockDeviceVolume) DeepCopyInto(out *BlockDeviceVolume) {
 	*out = *in
@@ -29,6 +45,22 @@ func (in *BlockDeviceVolume) DeepCopy() *BlockDeviceVolume {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootCacheConfiguration) DeepCopyInto(out *BootCacheConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootCacheConfiguration.
+func (in *BootCacheConfiguration) DeepCopy() *BootCacheConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BootCacheConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Configuration) DeepCopyInto(out *Configuration) {
 	*out = *in
@@ -60,6 +92,21 @@ func (in *Configuration) DeepCopyObject() runtime.Object {
 func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	*out = *in
 	in.VMDefaults.DeepCopyInto(&out.VMDefaults)
+	out.LVM = in.LVM
+	out.ZFS = in.ZFS
+	out.RBD = in.RBD
+	out.ConsoleLog = in.ConsoleLog
+	in.Events.DeepCopyInto(&out.Events)
+	out.Audit = in.Audit
+	in.GitOps.DeepCopyInto(&out.GitOps)
+	out.ImageScan = in.ImageScan
+	out.SBOM = in.SBOM
+	in.Policy.DeepCopyInto(&out.Policy)
+	out.Confinement = in.Confinement
+	out.Vault = in.Vault
+	out.ImageImport = in.ImageImport
+	out.BootCache = in.BootCache
+	out.Emulation = in.Emulation
 	return
 }
 
@@ -74,215 +121,219 @@ func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FileMapping) DeepCopyInto(out *FileMapping) {
+func (in *ConfinementConfiguration) DeepCopyInto(out *ConfinementConfiguration) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileMapping.
-func (in *FileMapping) DeepCopy() *FileMapping {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfinementConfiguration.
+func (in *ConfinementConfiguration) DeepCopy() *ConfinementConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(FileMapping)
+	out := new(ConfinementConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Image) DeepCopyInto(out *Image) {
+func (in *ConsoleLogConfiguration) DeepCopyInto(out *ConsoleLogConfiguration) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	out.MaxSize = in.MaxSize
+	out.MaxAge = in.MaxAge
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
-func (in *Image) DeepCopy() *Image {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleLogConfiguration.
+func (in *ConsoleLogConfiguration) DeepCopy() *ConsoleLogConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(Image)
+	out := new(ConsoleLogConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Image) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
+func (in *EmulationConfiguration) DeepCopyInto(out *EmulationConfiguration) {
 	*out = *in
-	out.OCI = in.OCI
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSpec.
-func (in *ImageSpec) DeepCopy() *ImageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmulationConfiguration.
+func (in *EmulationConfiguration) DeepCopy() *EmulationConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSpec)
+	out := new(EmulationConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+func (in *EventsConfiguration) DeepCopyInto(out *EventsConfiguration) {
 	*out = *in
-	in.OCISource.DeepCopyInto(&out.OCISource)
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]WebhookConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
-func (in *ImageStatus) DeepCopy() *ImageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventsConfiguration.
+func (in *EventsConfiguration) DeepCopy() *EventsConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageStatus)
+	out := new(EventsConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Kernel) DeepCopyInto(out *Kernel) {
+func (in *ExecProbe) DeepCopyInto(out *ExecProbe) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kernel.
-func (in *Kernel) DeepCopy() *Kernel {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecProbe.
+func (in *ExecProbe) DeepCopy() *ExecProbe {
 	if in == nil {
 		return nil
 	}
-	out := new(Kernel)
+	out := new(ExecProbe)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Kernel) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KernelSpec) DeepCopyInto(out *KernelSpec) {
+func (in *FileMapping) DeepCopyInto(out *FileMapping) {
 	*out = *in
-	out.OCI = in.OCI
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelSpec.
-func (in *KernelSpec) DeepCopy() *KernelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileMapping.
+func (in *FileMapping) DeepCopy() *FileMapping {
 	if in == nil {
 		return nil
 	}
-	out := new(KernelSpec)
+	out := new(FileMapping)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KernelStatus) DeepCopyInto(out *KernelStatus) {
+func (in *GitOpsConfiguration) DeepCopyInto(out *GitOpsConfiguration) {
 	*out = *in
-	in.OCISource.DeepCopyInto(&out.OCISource)
+	if in.Environments != nil {
+		in, out := &in.Environments, &out.Environments
+		*out = make([]GitOpsEnvironment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelStatus.
-func (in *KernelStatus) DeepCopy() *KernelStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsConfiguration.
+func (in *GitOpsConfiguration) DeepCopy() *GitOpsConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(KernelStatus)
+	out := new(GitOpsConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Network) DeepCopyInto(out *Network) {
+func (in *GitOpsEnvironment) DeepCopyInto(out *GitOpsEnvironment) {
 	*out = *in
-	if in.IPAddresses != nil {
-		in, out := &in.IPAddresses, &out.IPAddresses
-		*out = make(v1alpha1.IPAddresses, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = make(net.IP, len(*in))
-				copy(*out, *in)
-			}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
+	out.SyncPolicy = in.SyncPolicy
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
-func (in *Network) DeepCopy() *Network {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsEnvironment.
+func (in *GitOpsEnvironment) DeepCopy() *GitOpsEnvironment {
 	if in == nil {
 		return nil
 	}
-	out := new(Network)
+	out := new(GitOpsEnvironment)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OCIImageSource) DeepCopyInto(out *OCIImageSource) {
+func (in *GitOpsSyncPolicy) DeepCopyInto(out *GitOpsSyncPolicy) {
 	*out = *in
-	if in.ID != nil {
-		in, out := &in.ID, &out.ID
-		*out = new(v1alpha1.OCIContentID)
-		**out = **in
+	out.Interval = in.Interval
+	out.DriftInterval = in.DriftInterval
+	out.PruneGracePeriod = in.PruneGracePeriod
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsSyncPolicy.
+func (in *GitOpsSyncPolicy) DeepCopy() *GitOpsSyncPolicy {
+	if in == nil {
+		return nil
 	}
-	out.Size = in.Size
+	out := new(GitOpsSyncPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProbe) DeepCopyInto(out *HTTPProbe) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIImageSource.
-func (in *OCIImageSource) DeepCopy() *OCIImageSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProbe.
+func (in *HTTPProbe) DeepCopy() *HTTPProbe {
 	if in == nil {
 		return nil
 	}
-	out := new(OCIImageSource)
+	out := new(HTTPProbe)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Pool) DeepCopyInto(out *Pool) {
+func (in *Image) DeepCopyInto(out *Image) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pool.
-func (in *Pool) DeepCopy() *Pool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
+func (in *Image) DeepCopy() *Image {
 	if in == nil {
 		return nil
 	}
-	out := new(Pool)
+	out := new(Image)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Pool) DeepCopyObject() runtime.Object {
+func (in *Image) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -290,123 +341,143 @@ func (in *Pool) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolDevice) DeepCopyInto(out *PoolDevice) {
+func (in *ImageImportConfiguration) DeepCopyInto(out *ImageImportConfiguration) {
 	*out = *in
-	out.Size = in.Size
-	out.Parent = in.Parent
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolDevice.
-func (in *PoolDevice) DeepCopy() *PoolDevice {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageImportConfiguration.
+func (in *ImageImportConfiguration) DeepCopy() *ImageImportConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolDevice)
+	out := new(ImageImportConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
+func (in *ImageSBOM) DeepCopyInto(out *ImageSBOM) {
 	*out = *in
-	out.MetadataSize = in.MetadataSize
-	out.DataSize = in.DataSize
-	out.AllocationSize = in.AllocationSize
+	in.Time.DeepCopyInto(&out.Time)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolSpec.
-func (in *PoolSpec) DeepCopy() *PoolSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSBOM.
+func (in *ImageSBOM) DeepCopy() *ImageSBOM {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolSpec)
+	out := new(ImageSBOM)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolStatus) DeepCopyInto(out *PoolStatus) {
+func (in *ImageScanConfiguration) DeepCopyInto(out *ImageScanConfiguration) {
 	*out = *in
-	if in.Devices != nil {
-		in, out := &in.Devices, &out.Devices
-		*out = make([]*PoolDevice, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(PoolDevice)
-				**out = **in
-			}
-		}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageScanConfiguration.
+func (in *ImageScanConfiguration) DeepCopy() *ImageScanConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageScanConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageScanStatus) DeepCopyInto(out *ImageScanStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	out.Summary = in.Summary
+	if in.Vulnerabilities != nil {
+		in, out := &in.Vulnerabilities, &out.Vulnerabilities
+		*out = make([]Vulnerability, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolStatus.
-func (in *PoolStatus) DeepCopy() *PoolStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageScanStatus.
+func (in *ImageScanStatus) DeepCopy() *ImageScanStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolStatus)
+	out := new(ImageScanStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Runtime) DeepCopyInto(out *Runtime) {
+func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
 	*out = *in
+	out.OCI = in.OCI
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Runtime.
-func (in *Runtime) DeepCopy() *Runtime {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSpec.
+func (in *ImageSpec) DeepCopy() *ImageSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Runtime)
+	out := new(ImageSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SSH) DeepCopyInto(out *SSH) {
+func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
 	*out = *in
+	in.OCISource.DeepCopyInto(&out.OCISource)
+	if in.Scan != nil {
+		in, out := &in.Scan, &out.Scan
+		*out = new(ImageScanStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SBOM != nil {
+		in, out := &in.SBOM, &out.SBOM
+		*out = new(ImageSBOM)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSH.
-func (in *SSH) DeepCopy() *SSH {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
+func (in *ImageStatus) DeepCopy() *ImageStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SSH)
+	out := new(ImageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VM) DeepCopyInto(out *VM) {
+func (in *Kernel) DeepCopyInto(out *Kernel) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VM.
-func (in *VM) DeepCopy() *VM {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kernel.
+func (in *Kernel) DeepCopy() *Kernel {
 	if in == nil {
 		return nil
 	}
-	out := new(VM)
+	out := new(Kernel)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VM) DeepCopyObject() runtime.Object {
+func (in *Kernel) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -414,99 +485,721 @@ func (in *VM) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VMImageSpec) DeepCopyInto(out *VMImageSpec) {
+func (in *KernelSpec) DeepCopyInto(out *KernelSpec) {
 	*out = *in
 	out.OCI = in.OCI
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMImageSpec.
-func (in *VMImageSpec) DeepCopy() *VMImageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelSpec.
+func (in *KernelSpec) DeepCopy() *KernelSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VMImageSpec)
+	out := new(KernelSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VMKernelSpec) DeepCopyInto(out *VMKernelSpec) {
+func (in *KernelStatus) DeepCopyInto(out *KernelStatus) {
 	*out = *in
-	out.OCI = in.OCI
+	in.OCISource.DeepCopyInto(&out.OCISource)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMKernelSpec.
-func (in *VMKernelSpec) DeepCopy() *VMKernelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelStatus.
+func (in *KernelStatus) DeepCopy() *KernelStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VMKernelSpec)
+	out := new(KernelStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VMNetworkSpec) DeepCopyInto(out *VMNetworkSpec) {
+func (in *LVMConfiguration) DeepCopyInto(out *LVMConfiguration) {
 	*out = *in
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make(v1alpha1.PortMappings, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMNetworkSpec.
-func (in *VMNetworkSpec) DeepCopy() *VMNetworkSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMConfiguration.
+func (in *LVMConfiguration) DeepCopy() *LVMConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(VMNetworkSpec)
+	out := new(LVMConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VMSandboxSpec) DeepCopyInto(out *VMSandboxSpec) {
+func (in *NBDVolume) DeepCopyInto(out *NBDVolume) {
 	*out = *in
-	out.OCI = in.OCI
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSandboxSpec.
-func (in *VMSandboxSpec) DeepCopy() *VMSandboxSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NBDVolume.
+func (in *NBDVolume) DeepCopy() *NBDVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(VMSandboxSpec)
+	out := new(NBDVolume)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VMSpec) DeepCopyInto(out *VMSpec) {
+func (in *Network) DeepCopyInto(out *Network) {
 	*out = *in
-	out.Image = in.Image
-	out.Sandbox = in.Sandbox
-	out.Kernel = in.Kernel
-	out.Memory = in.Memory
-	out.DiskSize = in.DiskSize
-	in.Network.DeepCopyInto(&out.Network)
-	in.Storage.DeepCopyInto(&out.Storage)
-	if in.CopyFiles != nil {
-		in, out := &in.CopyFiles, &out.CopyFiles
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make(v1alpha1.IPAddresses, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(net.IP, len(*in))
+				copy(*out, *in)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
+func (in *Network) DeepCopy() *Network {
+	if in == nil {
+		return nil
+	}
+	out := new(Network)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIImageSource) DeepCopyInto(out *OCIImageSource) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(v1alpha1.OCIContentID)
+		**out = **in
+	}
+	out.Size = in.Size
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIImageSource.
+func (in *OCIImageSource) DeepCopy() *OCIImageSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIImageSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverlayStatus) DeepCopyInto(out *OverlayStatus) {
+	*out = *in
+	out.Usage = in.Usage
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverlayStatus.
+func (in *OverlayStatus) DeepCopy() *OverlayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OverlayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PCIDevice) DeepCopyInto(out *PCIDevice) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PCIDevice.
+func (in *PCIDevice) DeepCopy() *PCIDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(PCIDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyConfiguration) DeepCopyInto(out *PolicyConfiguration) {
+	*out = *in
+	if in.AllowedRegistries != nil {
+		in, out := &in.AllowedRegistries, &out.AllowedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedRegistries != nil {
+		in, out := &in.DeniedRegistries, &out.DeniedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.MaxMemory = in.MaxMemory
+	if in.Rego != nil {
+		in, out := &in.Rego, &out.Rego
+		*out = new(RegoPolicy)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyConfiguration.
+func (in *PolicyConfiguration) DeepCopy() *PolicyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pool) DeepCopyInto(out *Pool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pool.
+func (in *Pool) DeepCopy() *Pool {
+	if in == nil {
+		return nil
+	}
+	out := new(Pool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Pool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolDevice) DeepCopyInto(out *PoolDevice) {
+	*out = *in
+	out.Size = in.Size
+	out.Parent = in.Parent
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolDevice.
+func (in *PoolDevice) DeepCopy() *PoolDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
+	*out = *in
+	out.MetadataSize = in.MetadataSize
+	out.DataSize = in.DataSize
+	out.AllocationSize = in.AllocationSize
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolSpec.
+func (in *PoolSpec) DeepCopy() *PoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolStatus) DeepCopyInto(out *PoolStatus) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]*PoolDevice, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(PoolDevice)
+				**out = **in
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolStatus.
+func (in *PoolStatus) DeepCopy() *PoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBDConfiguration) DeepCopyInto(out *RBDConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBDConfiguration.
+func (in *RBDConfiguration) DeepCopy() *RBDConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RBDConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegoPolicy) DeepCopyInto(out *RegoPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegoPolicy.
+func (in *RegoPolicy) DeepCopy() *RegoPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RegoPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Runtime) DeepCopyInto(out *Runtime) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Runtime.
+func (in *Runtime) DeepCopy() *Runtime {
+	if in == nil {
+		return nil
+	}
+	out := new(Runtime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SBOMConfiguration) DeepCopyInto(out *SBOMConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SBOMConfiguration.
+func (in *SBOMConfiguration) DeepCopy() *SBOMConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SBOMConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSH) DeepCopyInto(out *SSH) {
+	*out = *in
+	if in.AuthorizedKeys != nil {
+		in, out := &in.AuthorizedKeys, &out.AuthorizedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSH.
+func (in *SSH) DeepCopy() *SSH {
+	if in == nil {
+		return nil
+	}
+	out := new(SSH)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPProbe) DeepCopyInto(out *TCPProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPProbe.
+func (in *TCPProbe) DeepCopy() *TCPProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TmpfsVolume) DeepCopyInto(out *TmpfsVolume) {
+	*out = *in
+	out.Size = in.Size
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TmpfsVolume.
+func (in *TmpfsVolume) DeepCopy() *TmpfsVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(TmpfsVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VM) DeepCopyInto(out *VM) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VM.
+func (in *VM) DeepCopy() *VM {
+	if in == nil {
+		return nil
+	}
+	out := new(VM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VM) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMBackupSpec) DeepCopyInto(out *VMBackupSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMBackupSpec.
+func (in *VMBackupSpec) DeepCopy() *VMBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMCondition) DeepCopyInto(out *VMCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMCondition.
+func (in *VMCondition) DeepCopy() *VMCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VMCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMConfinementStatus) DeepCopyInto(out *VMConfinementStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMConfinementStatus.
+func (in *VMConfinementStatus) DeepCopy() *VMConfinementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMConfinementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMExitStatus) DeepCopyInto(out *VMExitStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMExitStatus.
+func (in *VMExitStatus) DeepCopy() *VMExitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMExitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMImageSpec) DeepCopyInto(out *VMImageSpec) {
+	*out = *in
+	out.OCI = in.OCI
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMImageSpec.
+func (in *VMImageSpec) DeepCopy() *VMImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMKernelSpec) DeepCopyInto(out *VMKernelSpec) {
+	*out = *in
+	out.OCI = in.OCI
+	if in.InitrdRef != nil {
+		in, out := &in.InitrdRef, &out.InitrdRef
+		*out = new(v1alpha1.OCIImageRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMKernelSpec.
+func (in *VMKernelSpec) DeepCopy() *VMKernelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMKernelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMNetworkSpec) DeepCopyInto(out *VMNetworkSpec) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make(v1alpha1.PortMappings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMNetworkSpec.
+func (in *VMNetworkSpec) DeepCopy() *VMNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMProbe) DeepCopyInto(out *VMProbe) {
+	*out = *in
+	if in.TCP != nil {
+		in, out := &in.TCP, &out.TCP
+		*out = new(TCPProbe)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPProbe)
+		**out = **in
+	}
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	out.InitialDelay = in.InitialDelay
+	out.Period = in.Period
+	out.Timeout = in.Timeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMProbe.
+func (in *VMProbe) DeepCopy() *VMProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(VMProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSandboxSpec) DeepCopyInto(out *VMSandboxSpec) {
+	*out = *in
+	out.OCI = in.OCI
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSandboxSpec.
+func (in *VMSandboxSpec) DeepCopy() *VMSandboxSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSandboxSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSeccompSpec) DeepCopyInto(out *VMSeccompSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSeccompSpec.
+func (in *VMSeccompSpec) DeepCopy() *VMSeccompSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSeccompSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSeccompStatus) DeepCopyInto(out *VMSeccompStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSeccompStatus.
+func (in *VMSeccompStatus) DeepCopy() *VMSeccompStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSeccompStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSecret) DeepCopyInto(out *VMSecret) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSecret.
+func (in *VMSecret) DeepCopy() *VMSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSpec) DeepCopyInto(out *VMSpec) {
+	*out = *in
+	out.Image = in.Image
+	out.Sandbox = in.Sandbox
+	in.Kernel.DeepCopyInto(&out.Kernel)
+	out.Memory = in.Memory
+	out.DiskSize = in.DiskSize
+	out.OverlaySizeLimit = in.OverlaySizeLimit
+	in.Network.DeepCopyInto(&out.Network)
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.CopyFiles != nil {
+		in, out := &in.CopyFiles, &out.CopyFiles
 		*out = make([]FileMapping, len(*in))
 		copy(*out, *in)
 	}
 	if in.SSH != nil {
 		in, out := &in.SSH, &out.SSH
 		*out = new(SSH)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(VMBackupSpec)
+		**out = **in
+	}
+	if in.AutostartAfter != nil {
+		in, out := &in.AutostartAfter, &out.AutostartAfter
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(VMProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Seccomp != nil {
+		in, out := &in.Seccomp, &out.Seccomp
+		*out = new(VMSeccompSpec)
 		**out = **in
 	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]VMUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]VMSecret, len(*in))
+		copy(*out, *in)
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]PCIDevice, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -540,6 +1233,33 @@ func (in *VMStatus) DeepCopyInto(out *VMStatus) {
 	}
 	in.Image.DeepCopyInto(&out.Image)
 	in.Kernel.DeepCopyInto(&out.Kernel)
+	if in.Overlay != nil {
+		in, out := &in.Overlay, &out.Overlay
+		*out = new(OverlayStatus)
+		**out = **in
+	}
+	if in.LastExit != nil {
+		in, out := &in.LastExit, &out.LastExit
+		*out = new(VMExitStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VMCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Seccomp != nil {
+		in, out := &in.Seccomp, &out.Seccomp
+		*out = new(VMSeccompStatus)
+		**out = **in
+	}
+	if in.Confinement != nil {
+		in, out := &in.Confinement, &out.Confinement
+		*out = new(VMConfinementStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -568,6 +1288,11 @@ func (in *VMStorageSpec) DeepCopyInto(out *VMStorageSpec) {
 		*out = make([]VolumeMount, len(*in))
 		copy(*out, *in)
 	}
+	if in.WritablePaths != nil {
+		in, out := &in.WritablePaths, &out.WritablePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -581,6 +1306,80 @@ func (in *VMStorageSpec) DeepCopy() *VMStorageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMTemplate) DeepCopyInto(out *VMTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMTemplate.
+func (in *VMTemplate) DeepCopy() *VMTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VMTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMUser) DeepCopyInto(out *VMUser) {
+	*out = *in
+	if in.UID != nil {
+		in, out := &in.UID, &out.UID
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuthorizedKeys != nil {
+		in, out := &in.AuthorizedKeys, &out.AuthorizedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMUser.
+func (in *VMUser) DeepCopy() *VMUser {
+	if in == nil {
+		return nil
+	}
+	out := new(VMUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultConfiguration) DeepCopyInto(out *VaultConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultConfiguration.
+func (in *VaultConfiguration) DeepCopy() *VaultConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Volume) DeepCopyInto(out *Volume) {
 	*out = *in
@@ -589,6 +1388,16 @@ func (in *Volume) DeepCopyInto(out *Volume) {
 		*out = new(BlockDeviceVolume)
 		**out = **in
 	}
+	if in.Tmpfs != nil {
+		in, out := &in.Tmpfs, &out.Tmpfs
+		*out = new(TmpfsVolume)
+		**out = **in
+	}
+	if in.NBD != nil {
+		in, out := &in.NBD, &out.NBD
+		*out = new(NBDVolume)
+		**out = **in
+	}
 	return
 }
 
@@ -617,3 +1426,73 @@ func (in *VolumeMount) DeepCopy() *VolumeMount {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Vulnerability) DeepCopyInto(out *Vulnerability) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Vulnerability.
+func (in *Vulnerability) DeepCopy() *Vulnerability {
+	if in == nil {
+		return nil
+	}
+	out := new(Vulnerability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilitySummary) DeepCopyInto(out *VulnerabilitySummary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilitySummary.
+func (in *VulnerabilitySummary) DeepCopy() *VulnerabilitySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilitySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookConfiguration) DeepCopyInto(out *WebhookConfiguration) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Timeout = in.Timeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfiguration.
+func (in *WebhookConfiguration) DeepCopy() *WebhookConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZFSConfiguration) DeepCopyInto(out *ZFSConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZFSConfiguration.
+func (in *ZFSConfiguration) DeepCopy() *ZFSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ZFSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
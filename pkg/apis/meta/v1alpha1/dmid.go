@@ -1,6 +1,10 @@
 package v1alpha1
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
 
 // DMID specifies the format for device mapper IDs
 type DMID struct {
@@ -46,3 +50,35 @@ func (d DMID) String() string {
 
 	return "pool"
 }
+
+func (d DMID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *DMID) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	if s == "pool" {
+		*d = NewPoolDMID()
+		return nil
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid device mapper ID %q: %v", s, err)
+	}
+
+	if i < 0 || i >= 1<<24 {
+		return fmt.Errorf("device mapper ID out of range: %d", i)
+	}
+
+	*d = NewDMID(i)
+	return nil
+}
+
+// OpenAPISchemaType and OpenAPISchemaFormat make the OpenAPI schema of DMID a string, like its JSON form
+func (DMID) OpenAPISchemaType() []string { return []string{"string"} }
+func (DMID) OpenAPISchemaFormat() string { return "" }
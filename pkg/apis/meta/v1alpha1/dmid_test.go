@@ -0,0 +1,57 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDMIDJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		pool bool
+		err  bool
+	}{
+		{
+			in: `"0"`,
+		},
+		{
+			in: `"42"`,
+		},
+		{
+			in:   `"pool"`,
+			pool: true,
+		},
+		{
+			in:  `"-1"`,
+			err: true,
+		},
+		{
+			in:  `"foo"`,
+			err: true,
+		},
+	}
+
+	for _, rt := range tests {
+		var id DMID
+		err := json.Unmarshal([]byte(rt.in), &id)
+		if (err != nil) != rt.err {
+			t.Fatalf("expected error %t, actual: %v", rt.err, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		if id.Pool() != rt.pool {
+			t.Errorf("expected pool %t, actual: %t", rt.pool, id.Pool())
+		}
+
+		// Check that the ID survives a round trip
+		out, err := json.Marshal(id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(out) != rt.in {
+			t.Errorf("expected %s, actual: %s", rt.in, out)
+		}
+	}
+}
@@ -19,35 +19,51 @@ const (
 	ociSchemeLocal    = "docker://"
 )
 
-// NewOCIImageRef parses and normalizes a reference to an OCI (docker) image.
+// NewOCIImageRef parses and normalizes a reference to an OCI (docker) image. The reference
+// may be pinned to a digest, e.g. "weaveworks/ignite-ubuntu@sha256:...", optionally keeping
+// the tag for readability. References without a tag or digest get the latest tag.
 func NewOCIImageRef(imageStr string) (o OCIImageRef, err error) {
-	named, err := reference.ParseDockerRef(imageStr)
+	named, err := reference.ParseNormalizedNamed(imageStr)
 	if err != nil {
 		return
 	}
 
-	if namedTagged, ok := named.(reference.NamedTagged); ok {
-		o.name, o.tag = namedTagged.Name(), namedTagged.Tag()
-	} else {
-		err = fmt.Errorf("could not parse image %q with a tag", imageStr)
+	o.name = named.Name()
+	if tagged, ok := named.(reference.Tagged); ok {
+		o.tag = tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		o.digest = digested.Digest()
+	}
+
+	if len(o.tag) == 0 && len(o.digest) == 0 {
+		o.tag = "latest"
 	}
 
 	return
 }
 
-// OCIImageRef is a struct containing a names and tagged reference
+// OCIImageRef is a struct containing a name, and a tag and/or a digest
 // by which an OCI runtime can identify an image to retrieve.
 type OCIImageRef struct {
-	name string
-	tag  string
+	name   string
+	tag    string
+	digest digest.Digest
 }
 
 var _ fmt.Stringer = OCIImageRef{}
 
-// Ref parses the internal strings to a reference.NamedTagged
-func (i OCIImageRef) Ref() reference.NamedTagged {
-	r, _ := reference.ParseDockerRef(fmt.Sprintf("%s:%s", i.name, i.tag))
-	return r.(reference.NamedTagged)
+// Ref parses the internal strings to a reference.Named, which is tagged, digested or both
+func (i OCIImageRef) Ref() reference.Named {
+	r, _ := reference.WithName(i.name)
+	if len(i.tag) != 0 {
+		r, _ = reference.WithTag(r, i.tag)
+	}
+	if len(i.digest) != 0 {
+		r, _ = reference.WithDigest(r, i.digest)
+	}
+
+	return r
 }
 
 // String returns the familiar form of the reference, e.g. "weaveworks/ignite-ubuntu:latest"
@@ -55,16 +71,33 @@ func (i OCIImageRef) String() string {
 	return reference.FamiliarString(i.Ref())
 }
 
-// Normalized returns the normalized reference, e.g. "docker.io/weaveworks/ignite-ubuntu:latest"
+// Normalized returns the normalized reference, e.g. "docker.io/weaveworks/ignite-ubuntu:latest".
+// The tag of a reference pinned to a digest is dropped, as the digest identifies the image.
 func (i OCIImageRef) Normalized() string {
+	if len(i.digest) != 0 {
+		r, _ := reference.WithName(i.name)
+		r, _ = reference.WithDigest(r, i.digest)
+		return r.String()
+	}
+
 	return i.Ref().String()
 }
 
+// Digest returns the digest the reference is pinned to, if any
+func (i OCIImageRef) Digest() digest.Digest {
+	return i.digest
+}
+
 func (i OCIImageRef) IsUnset() bool {
 	return len(i.name) == 0
 }
 
+// MarshalJSON encodes the reference in its familiar form, an unset reference is empty
 func (i OCIImageRef) MarshalJSON() ([]byte, error) {
+	if i.IsUnset() {
+		return json.Marshal("")
+	}
+
 	return json.Marshal(i.String())
 }
 
@@ -74,10 +107,19 @@ func (i *OCIImageRef) UnmarshalJSON(b []byte) (err error) {
 		return err
 	}
 
+	if len(s) == 0 {
+		*i = OCIImageRef{}
+		return nil
+	}
+
 	*i, err = NewOCIImageRef(s)
 	return err
 }
 
+// OpenAPISchemaType and OpenAPISchemaFormat make the OpenAPI schema of OCIImageRef a string, like its JSON form
+func (OCIImageRef) OpenAPISchemaType() []string { return []string{"string"} }
+func (OCIImageRef) OpenAPISchemaFormat() string { return "" }
+
 // ParseOCIContentID takes in a string to parse into an *OCIContentID
 // If given a local Docker SHA like "sha256:3285f65b2651c68b5316e7a1fbabd30b5ae47914ac5791ac4bb9d59d029b924b",
 // it will be parsed into the local format, encoded as "docker://<SHA>". Given a full repo digest, such as
@@ -190,3 +232,7 @@ func (o *OCIContentID) UnmarshalJSON(b []byte) (err error) {
 
 	return
 }
+
+// OpenAPISchemaType and OpenAPISchemaFormat make the OpenAPI schema of OCIContentID a string, like its JSON form
+func (OCIContentID) OpenAPISchemaType() []string { return []string{"string"} }
+func (OCIContentID) OpenAPISchemaFormat() string { return "" }
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -17,6 +18,14 @@ func TestNewOCIImageRef(t *testing.T) {
 			in:  "centos",
 			out: "centos:latest",
 		},
+		{
+			in:  "weaveworks/ignite-ubuntu@sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e",
+			out: "weaveworks/ignite-ubuntu@sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e",
+		},
+		{
+			in:  "docker.io/weaveworks/ignite-ubuntu:20.04@sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e",
+			out: "weaveworks/ignite-ubuntu:20.04@sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e",
+		},
 		{
 			in:  "skjjnfnskj//bs::777",
 			err: true,
@@ -37,6 +46,47 @@ func TestNewOCIImageRef(t *testing.T) {
 	}
 }
 
+func TestOCIImageRefNormalized(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{"centos", "docker.io/library/centos:latest"},
+		// The tag of a pinned reference is dropped, the runtimes pull the image by its digest
+		{
+			"weaveworks/ignite-ubuntu:20.04@sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e",
+			"docker.io/weaveworks/ignite-ubuntu@sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e",
+		},
+	}
+
+	for _, rt := range tests {
+		ref, err := NewOCIImageRef(rt.in)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ref.Normalized() != rt.out {
+			t.Errorf("expected %q, actual: %q", rt.out, ref.Normalized())
+		}
+	}
+}
+
+func TestOCIImageRefJSON(t *testing.T) {
+	for _, in := range []string{`"weaveworks/ignite-ubuntu:latest"`, `""`} {
+		var ref OCIImageRef
+		if err := json.Unmarshal([]byte(in), &ref); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Check that the reference, unset or not, survives a round trip
+		out, err := json.Marshal(ref)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(out) != in {
+			t.Errorf("expected %s, actual: %s", in, out)
+		}
+	}
+}
+
 func TestParseOCIString(t *testing.T) {
 	tests := []struct {
 		name string
@@ -85,3 +85,7 @@ func (s *Size) UnmarshalJSON(b []byte) error {
 	*s, err = NewSizeFromString(str)
 	return err
 }
+
+// OpenAPISchemaType and OpenAPISchemaFormat make the OpenAPI schema of Size a string, like its JSON form
+func (Size) OpenAPISchemaType() []string { return []string{"string"} }
+func (Size) OpenAPISchemaFormat() string { return "" }
@@ -0,0 +1,83 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/weaveworks/ignite/pkg/audit"
+	"golang.org/x/sys/unix"
+)
+
+// actorKey is the context key of the actor of the requests of a connection
+type actorKey struct{}
+
+// connActor stores the actor of the connection in its context: the user of the peer of a
+// unix socket, from its credentials, or the remote address of a TCP connection. The actor
+// of a TLS connection is the common name of the client certificate, set by withAudit.
+func connActor(ctx context.Context, c net.Conn) context.Context {
+	actor := c.RemoteAddr().String()
+	if uc, ok := c.(*net.UnixConn); ok {
+		if raw, err := uc.SyscallConn(); err == nil {
+			var cred *unix.Ucred
+			_ = raw.Control(func(fd uintptr) {
+				cred, _ = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+			})
+			if cred != nil {
+				actor = audit.UserName(int(cred.Uid))
+			}
+		}
+	}
+
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// statusRecorder records the status code written by the handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// withAudit records the requests that change objects in the audit log
+func withAudit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		actor, ok := r.Context().Value(actorKey{}).(string)
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			// Clients on TCP are identified by their certificates
+			actor = r.TLS.PeerCertificates[0].Subject.CommonName
+		} else if !ok || len(actor) == 0 {
+			actor = r.RemoteAddr
+		}
+
+		var args []string
+		query := r.URL.Query()
+		for key := range query {
+			for _, value := range query[key] {
+				args = append(args, fmt.Sprintf("%s=%s", key, value))
+			}
+		}
+		sort.Strings(args)
+
+		entry := audit.Begin(audit.SourceAPI, actor, fmt.Sprintf("%s %s", r.Method, r.URL.Path), args...)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		var err error
+		if rec.status >= http.StatusBadRequest {
+			err = fmt.Errorf("%d %s", rec.status, http.StatusText(rec.status))
+		}
+		entry.Finish(err)
+	})
+}
@@ -0,0 +1,179 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/audit"
+	"github.com/weaveworks/ignite/pkg/bootcache"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/operations/lookup"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+)
+
+// handleImports lists the images or kernels, or imports the one of the posted manifest
+func (s *Server) handleImports(kind runtime.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.handleList(kind)(w, r)
+		case http.MethodPost:
+			obj, imported, err := s.importObject(r, kind)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+
+			status := http.StatusOK
+			if imported {
+				status = http.StatusCreated
+			}
+			writeObject(w, status, obj)
+		default:
+			writeError(w, errMethodNotAllowed(r))
+		}
+	}
+}
+
+// handleImported gets or removes the image or kernel
+func (s *Server) handleImported(kind runtime.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			s.handleGet(kind)(w, r)
+			return
+		}
+
+		if err := s.removeObject(r, kind, strings.TrimPrefix(r.URL.Path, kindPath(kind)+"/")); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// importObject imports the OCI image of the Image or Kernel manifest like "ignite image import"
+// and "ignite kernel import", unless it's imported already. The labels of the manifest are set
+// on the image or kernel. It returns true if the OCI image was imported.
+func (s *Server) importObject(r *http.Request, kind runtime.Kind) (runtime.Object, bool, error) {
+	manifest, err := readManifest(r)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var ref meta.OCIImageRef
+	var labels map[string]string
+	switch kind {
+	case api.KindImage:
+		image := &api.Image{}
+		if err := scheme.Serializer.DecodeInto(manifest, image); err != nil {
+			return nil, false, &statusError{http.StatusBadRequest, err}
+		}
+
+		if err := validation.ValidateImage(image).ToAggregate(); err != nil {
+			return nil, false, &statusError{http.StatusBadRequest, err}
+		}
+		ref, labels = image.Spec.OCI, image.Labels
+	case api.KindKernel:
+		kernel := &api.Kernel{}
+		if err := scheme.Serializer.DecodeInto(manifest, kernel); err != nil {
+			return nil, false, &statusError{http.StatusBadRequest, err}
+		}
+
+		if err := validation.ValidateKernel(kernel).ToAggregate(); err != nil {
+			return nil, false, &statusError{http.StatusBadRequest, err}
+		}
+		ref, labels = kernel.Spec.OCI, kernel.Labels
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.client.Dynamic(kind).Find(filter.NewIDNameFilter(ref.String()))
+	if err != nil && !filterer.IsNonexistentError(err) {
+		return nil, false, err
+	}
+	imported := err != nil
+
+	var obj runtime.Object
+	if err := jobs.Run(r.Context(), jobs.ClassImport, kind.Lower()+"/"+ref.String(), audit.SourceAPI, func() (err error) {
+		if kind == api.KindImage {
+			obj, err = operations.FindOrImportImage(context.Background(), s.client, ref)
+		} else {
+			obj, err = operations.FindOrImportKernel(context.Background(), s.client, ref)
+		}
+		return
+	}); err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	for k, v := range labels {
+		if obj.GetObjectMeta().GetLabel(k) != v {
+			obj.GetObjectMeta().SetLabel(k, v)
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := s.client.Dynamic(kind).Set(obj); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return obj, imported, nil
+}
+
+// removeObject removes the image or kernel like "ignite image rm" and "ignite kernel rm",
+// an image or kernel used by a VM isn't removed
+func (s *Server) removeObject(r *http.Request, kind runtime.Kind, ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, err := s.find(kind, ref)
+	if err != nil {
+		return err
+	}
+
+	vms, err := s.client.VMs().List()
+	if err != nil {
+		return err
+	}
+
+	for _, vm := range vms {
+		var uid runtime.UID
+		if kind == api.KindImage {
+			uid, err = lookup.ImageUIDForVM(vm, s.client)
+		} else {
+			uid, err = lookup.KernelUIDForVM(vm, s.client)
+		}
+
+		if err == nil && uid == obj.GetUID() {
+			return &statusError{http.StatusConflict, fmt.Errorf("%s %q is in use by VM %q", kind.Lower(), obj.GetUID(), vm.GetUID())}
+		}
+	}
+
+	// Images and kernels keep their files in their directory of the data directory
+	dir := obj.(interface{ ObjectPath() string }).ObjectPath()
+
+	log.Infof("Removing %s %q with name %q through the API...", kind.Lower(), obj.GetUID(), obj.GetName())
+	return jobs.Run(r.Context(), jobs.ClassRemove, kind.Lower()+"/"+obj.GetName(), audit.SourceAPI, func() error {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("unable to remove directory for %s %q: %v", kind, obj.GetUID(), err)
+		}
+
+		// The boot cache entries of the image or kernel can't be restored without it
+		return bootcache.RemoveFor(obj.GetUID())
+	})
+}
@@ -0,0 +1,268 @@
+package apiserver
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/client"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/pki"
+	"github.com/weaveworks/ignite/pkg/policy"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+)
+
+// maxManifestSize limits the size of the manifests that can be posted
+const maxManifestSize = 1 << 20
+
+// Server serves the management API of ignited over HTTP. Objects are encoded in the
+// current API version, the same way "ignite inspect" outputs them. The lifecycle
+// operations are run one at a time, as they share the process-global providers.
+type Server struct {
+	client *client.Client
+	// watchInterval is how often the storage is checked for changes for watches
+	watchInterval time.Duration
+	mu            sync.Mutex
+}
+
+// New returns a Server for the objects of the given client
+func New(c *client.Client) *Server {
+	return &Server{
+		client:        c,
+		watchInterval: time.Second,
+	}
+}
+
+// LifecycleLock returns the lock held while the API runs a lifecycle operation, for other
+// servers of ignited to run theirs one at a time with the API's
+func (s *Server) LifecycleLock() sync.Locker {
+	return &s.mu
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/vms", s.handleVMs)
+	mux.HandleFunc("/v1/vms/", s.handleVM)
+	for _, kind := range []runtime.Kind{api.KindImage, api.KindKernel} {
+		mux.HandleFunc(kindPath(kind), s.handleImports(kind))
+		mux.HandleFunc(kindPath(kind)+"/", s.handleImported(kind))
+	}
+	mux.HandleFunc("/v1/watch", s.handleWatch)
+	mux.HandleFunc("/v1/jobs", s.handleJobs)
+	return withAudit(mux)
+}
+
+// ListenUnix listens on the unix socket at socketPath, replacing a socket left over
+// from a previous run. Only root and the group of the daemon may connect to it.
+func ListenUnix(socketPath string) (net.Listener, error) {
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(socketPath, 0660); err != nil {
+		l.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// ListenTLS listens on the TCP address, and requires the clients to present a
+// certificate issued by the CA in pkiDir
+func ListenTLS(address, pkiDir string) (net.Listener, error) {
+	cfg, err := pki.ServerConfig(pkiDir)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return tls.NewListener(l, cfg), nil
+}
+
+// Serve serves the API on the listener until it's closed
+func (s *Server) Serve(l net.Listener) error {
+	log.Infof("Serving the API on %s", l.Addr())
+	return (&http.Server{Handler: s.Handler(), ConnContext: connActor}).Serve(l)
+}
+
+// kindPath returns the path of the objects of the given kind, e.g. /v1/vms
+func kindPath(kind runtime.Kind) string {
+	return "/v1/" + kind.Lower() + "s"
+}
+
+func (s *Server) handleList(kind runtime.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, errMethodNotAllowed(r))
+			return
+		}
+
+		objs, err := s.client.Dynamic(kind).List()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeList(w, kind, objs)
+	}
+}
+
+// handleJobs returns the limits and the jobs of the queue of the daemon
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, errMethodNotAllowed(r))
+		return
+	}
+
+	status := &jobs.Status{PID: os.Getpid()}
+	if jobs.Default != nil {
+		status = jobs.Default.Status()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+func (s *Server) handleGet(kind runtime.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, errMethodNotAllowed(r))
+			return
+		}
+
+		obj, err := s.find(kind, strings.TrimPrefix(r.URL.Path, kindPath(kind)+"/"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeObject(w, http.StatusOK, obj)
+	}
+}
+
+// find returns the object of the given kind by its name or UID prefix
+func (s *Server) find(kind runtime.Kind, ref string) (runtime.Object, error) {
+	if len(ref) == 0 || strings.Contains(ref, "/") {
+		return nil, &statusError{http.StatusNotFound, fmt.Errorf("no %s given", kind)}
+	}
+
+	obj, err := s.client.Dynamic(kind).Find(filter.NewIDNameFilter(ref))
+	if filterer.IsNonexistentError(err) {
+		return nil, &statusError{http.StatusNotFound, err}
+	} else if filterer.IsAmbiguousError(err) {
+		return nil, &statusError{http.StatusBadRequest, err}
+	}
+
+	return obj, err
+}
+
+// statusError is an error that's returned with the given HTTP status code
+type statusError struct {
+	status int
+	error
+}
+
+func errMethodNotAllowed(r *http.Request) error {
+	return &statusError{http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed for %s", r.Method, r.URL.Path)}
+}
+
+// writeError writes the error as {"error": "..."}, the operations denied by the policy
+// are forbidden, and errors that aren't a statusError are internal server errors
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var denied *policy.DeniedError
+	if se, ok := err.(*statusError); ok {
+		status = se.status
+	} else if errors.As(err, &denied) {
+		status = http.StatusForbidden
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+func writeObject(w http.ResponseWriter, status int, obj runtime.Object) {
+	b, err := scheme.Serializer.EncodeJSON(obj)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+// writeList writes the objects as {"kind": "VMList", "items": [...]}
+func writeList(w http.ResponseWriter, kind runtime.Kind, objs []runtime.Object) {
+	items := make([]json.RawMessage, 0, len(objs))
+	for _, obj := range objs {
+		b, err := scheme.Serializer.EncodeJSON(obj)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		items = append(items, b)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}{kind.Title() + "List", items})
+}
+
+// readManifest reads the manifest in the request body, in YAML or JSON
+func readManifest(r *http.Request) ([]byte, error) {
+	b, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxManifestSize))
+	if err != nil {
+		return nil, &statusError{http.StatusBadRequest, fmt.Errorf("failed to read the manifest: %v", err)}
+	}
+
+	if len(b) == 0 {
+		return nil, &statusError{http.StatusBadRequest, fmt.Errorf("a manifest is required")}
+	}
+
+	return b, nil
+}
+
+// queryBool returns the boolean value of the query parameter, false if it's unset
+func queryBool(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if len(value) == 0 {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &statusError{http.StatusBadRequest, fmt.Errorf("invalid value %q for %s", value, name)}
+	}
+
+	return b, nil
+}
@@ -0,0 +1,241 @@
+package apiserver
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage"
+	"github.com/weaveworks/libgitops/pkg/storage/cache"
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/audit"
+	"github.com/weaveworks/ignite/pkg/client"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, func()) {
+	dir, err := ioutil.TempDir("", "ignite-apiserver-test")
+	if err != nil {
+		t.Fatalf("failed to create storage for ignite: %v", err)
+	}
+
+	// The storage expects a directory per kind
+	for _, kind := range []string{"vm", "image", "kernel"} {
+		if err := os.Mkdir(path.Join(dir, kind), 0755); err != nil {
+			t.Fatalf("failed to create storage for ignite: %v", err)
+		}
+	}
+
+	s := cache.NewCache(
+		storage.NewGenericStorage(
+			storage.NewGenericRawStorage(dir), scheme.Serializer))
+
+	// Record the requests in an audit log next to the storage
+	providers.ComponentConfig = &api.Configuration{}
+	providers.ComponentConfig.Spec.Audit.Path = path.Join(dir, "audit.log")
+
+	ts := httptest.NewServer(New(client.NewClient(s)).Handler())
+	return ts, func() {
+		ts.Close()
+		providers.ComponentConfig = nil
+		os.RemoveAll(dir)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ts, cleanup := newTestServer(t)
+	defer cleanup()
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "empty VM list",
+			method:     http.MethodGet,
+			path:       "/v1/vms",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"kind":"VMList","items":[]}`,
+		},
+		{
+			name:       "empty image list",
+			method:     http.MethodGet,
+			path:       "/v1/images",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"kind":"ImageList","items":[]}`,
+		},
+		{
+			name:       "nonexistent VM",
+			method:     http.MethodGet,
+			path:       "/v1/vms/foo",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "nonexistent kernel",
+			method:     http.MethodGet,
+			path:       "/v1/kernels/foo",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "start nonexistent VM",
+			method:     http.MethodPost,
+			path:       "/v1/vms/foo/start",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "delete nonexistent VM",
+			method:     http.MethodDelete,
+			path:       "/v1/vms/foo",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "unknown VM operation",
+			method:     http.MethodPost,
+			path:       "/v1/vms/foo/pause",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "get start",
+			method:     http.MethodGet,
+			path:       "/v1/vms/foo/start",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "delete images",
+			method:     http.MethodDelete,
+			path:       "/v1/images",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "import image without manifest",
+			method:     http.MethodPost,
+			path:       "/v1/images",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"a manifest is required"}`,
+		},
+		{
+			name:       "put kernels",
+			method:     http.MethodPut,
+			path:       "/v1/kernels",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "delete nonexistent image",
+			method:     http.MethodDelete,
+			path:       "/v1/images/foo",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "create without manifest",
+			method:     http.MethodPost,
+			path:       "/v1/vms",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"a manifest is required"}`,
+		},
+		{
+			name:       "invalid kill value",
+			method:     http.MethodPost,
+			path:       "/v1/vms/foo/stop?kill=maybe",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "post jobs",
+			method:     http.MethodPost,
+			path:       "/v1/jobs",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "watch unknown kind",
+			method:     http.MethodGet,
+			path:       "/v1/watch?kind=Pod",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			req, err := http.NewRequest(rt.method, ts.URL+rt.path, nil)
+			assert.NilError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NilError(t, err)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NilError(t, err)
+			assert.Equal(t, resp.StatusCode, rt.wantStatus, string(body))
+			if len(rt.wantBody) > 0 {
+				assert.Equal(t, strings.TrimSpace(string(body)), rt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAudit(t *testing.T) {
+	ts, cleanup := newTestServer(t)
+	defer cleanup()
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		req, err := http.NewRequest(method, ts.URL+"/v1/vms/foo/stop?kill=maybe", nil)
+		assert.NilError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NilError(t, err)
+		resp.Body.Close()
+	}
+
+	// Only the POST changes anything, so only it is recorded
+	entries, err := audit.Read(audit.Path())
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1)
+
+	e := entries[0]
+	assert.Equal(t, e.Source, audit.SourceAPI)
+	assert.Equal(t, e.Operation, "POST /v1/vms/foo/stop")
+	assert.DeepEqual(t, e.Args, []string{"kill=maybe"})
+	assert.Equal(t, e.Result, audit.ResultFailure)
+	assert.Equal(t, e.Error, "400 Bad Request")
+	assert.Assert(t, len(e.Actor) > 0)
+}
+
+func TestDiffObjects(t *testing.T) {
+	newVM := func(uid runtime.UID, cpus uint64) *api.VM {
+		vm := &api.VM{}
+		vm.SetUID(uid)
+		vm.Spec.CPUs = cpus
+		return vm
+	}
+
+	old := map[runtime.UID]runtime.Object{
+		"kept":    newVM("kept", 1),
+		"changed": newVM("changed", 1),
+		"removed": newVM("removed", 1),
+	}
+	current := map[runtime.UID]runtime.Object{
+		"kept":    newVM("kept", 1),
+		"changed": newVM("changed", 2),
+		"added":   newVM("added", 1),
+	}
+
+	got := map[runtime.UID]EventType{}
+	for _, e := range diffObjects(old, current) {
+		got[e.obj.GetUID()] = e.eventType
+	}
+
+	assert.DeepEqual(t, got, map[runtime.UID]EventType{
+		"changed": EventModified,
+		"removed": EventDeleted,
+		"added":   EventAdded,
+	})
+}
@@ -0,0 +1,311 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	"github.com/weaveworks/ignite/pkg/audit"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/policy"
+	"github.com/weaveworks/ignite/pkg/preflight/checkers"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+	patchutil "github.com/weaveworks/libgitops/pkg/util/patch"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// handleVMs lists the VMs, or creates one from the posted manifest
+func (s *Server) handleVMs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.handleList(api.KindVM)(w, r)
+	case http.MethodPost:
+		vm, err := s.createVM(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeObject(w, http.StatusCreated, vm)
+	default:
+		writeError(w, errMethodNotAllowed(r))
+	}
+}
+
+// handleVM gets or deletes the VM, or starts or stops it for the /start and /stop paths
+func (s *Server) handleVM(w http.ResponseWriter, r *http.Request) {
+	ref := strings.TrimPrefix(r.URL.Path, kindPath(api.KindVM)+"/")
+	action := ""
+	if i := strings.Index(ref, "/"); i >= 0 {
+		ref, action = ref[:i], ref[i+1:]
+	}
+
+	var err error
+	var vm *api.VM
+	switch {
+	case len(action) == 0 && r.Method == http.MethodGet:
+		vm, err = s.findVM(ref)
+	case len(action) == 0 && r.Method == http.MethodDelete:
+		if err = s.deleteVM(r, ref); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	case action == "start" && r.Method == http.MethodPost:
+		vm, err = s.startVM(ref)
+	case action == "stop" && r.Method == http.MethodPost:
+		vm, err = s.stopVM(r, ref)
+	case len(action) == 0 || action == "start" || action == "stop":
+		err = errMethodNotAllowed(r)
+	default:
+		err = &statusError{http.StatusNotFound, fmt.Errorf("unknown VM operation %q", action)}
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeObject(w, http.StatusOK, vm)
+}
+
+func (s *Server) findVM(ref string) (*api.VM, error) {
+	obj, err := s.find(api.KindVM, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj.(*api.VM), nil
+}
+
+// createVM creates the VM in the manifest the same way as "ignite create --config",
+// and starts it if the manifest sets status.running or the start query parameter is set
+func (s *Server) createVM(r *http.Request) (vm *api.VM, err error) {
+	manifest, err := readManifest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := queryBool(r, "start")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if vm, err = s.newVM(manifest); err != nil {
+		return nil, &statusError{http.StatusBadRequest, err}
+	}
+
+	// The status of the manifest is only used to know whether to start the VM
+	start = start || vm.Status.Running
+	vm.Status.Running = false
+	vm.Status.Conditions = nil
+
+	if err = metadata.SetNameAndUID(vm, s.client); err != nil {
+		return nil, &statusError{http.StatusConflict, err}
+	}
+
+	if err = validation.ValidateVM(vm).ToAggregate(); err != nil {
+		return nil, &statusError{http.StatusBadRequest, err}
+	}
+
+	config.ResolveVMProviders(vm)
+	if err = config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+		return nil, err
+	}
+
+	var image *api.Image
+	var kernel *api.Kernel
+	err = jobs.Run(r.Context(), jobs.ClassImport, "image/"+vm.Spec.Image.OCI.String(), audit.SourceAPI, func() (err error) {
+		image, kernel, err = operations.FindOrImportImageAndKernel(context.Background(), s.client, vm.Spec.Image.OCI, vm.Spec.Kernel.OCI)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	vm.SetImage(image)
+	vm.SetKernel(kernel)
+
+	if err = jobs.Run(r.Context(), jobs.ClassStart, jobs.Target(vm), audit.SourceAPI, func() error {
+		return s.create(vm)
+	}); err != nil {
+		return nil, err
+	}
+
+	if start {
+		if err = s.start(vm); err != nil {
+			return nil, err
+		}
+	}
+
+	return vm, nil
+}
+
+// newVM decodes the VM in the manifest, on top of the VM defaults of the component config
+func (s *Server) newVM(manifest []byte) (*api.VM, error) {
+	vm := s.client.VMs().New()
+	if providers.ComponentConfig != nil {
+		vm.Spec = *providers.ComponentConfig.Spec.VMDefaults.DeepCopy()
+	}
+
+	manifestVM := &api.VM{}
+	if err := scheme.Serializer.DecodeInto(manifest, manifestVM); err != nil {
+		return nil, err
+	}
+
+	// The image is needed to encode the base VM
+	vm.Spec.Image.OCI = manifestVM.Spec.Image.OCI
+
+	manifestJSON, err := yaml.YAMLToJSON(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	baseVMJSON, err := scheme.Serializer.EncodeJSON(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := patchutil.NewPatcher(scheme.Serializer).Apply(baseVMJSON, manifestJSON, vm.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scheme.Serializer.DecodeInto(result, vm); err != nil {
+		return nil, err
+	}
+
+	vm.Status.IDPrefix = providers.IDPrefix
+	vm.Status.Runtime = &api.Runtime{Name: providers.RuntimeName}
+	vm.Status.Network = &api.Network{Plugin: providers.NetworkPluginName}
+	vm.Status.Snapshotter = providers.SnapshotterName
+	return vm, nil
+}
+
+// create saves the VM and allocates its overlay, like "ignite create"
+func (s *Server) create(vm *api.VM) (err error) {
+	defer util.DeferErr(&err, func() error { return metadata.Cleanup(vm, false) })
+
+	if err = policy.CheckVM(context.Background(), policy.OperationVMCreate, vm); err != nil {
+		return
+	}
+
+	if err = s.client.VMs().Set(vm); err != nil {
+		return
+	}
+
+	if err = operations.AllocateAndPopulateOverlay(vm); err != nil {
+		return
+	}
+
+	return metadata.Success(vm)
+}
+
+func (s *Server) startVM(ref string) (*api.VM, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vm, err := s.findVM(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if vm.Running() {
+		return nil, &statusError{http.StatusConflict, fmt.Errorf("VM %q is already running", vm.GetUID())}
+	}
+
+	return vm, s.start(vm)
+}
+
+// start starts the VM with the runtime and network plugin of its spec, or the ones it ran with
+func (s *Server) start(vm *api.VM) error {
+	config.ResolveVMProviders(vm)
+	if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+		return err
+	}
+
+	if err := checkers.StartCmdChecks(vm, sets.NewString()); err != nil {
+		return &statusError{http.StatusPreconditionFailed, err}
+	}
+
+	log.Infof("Starting VM %q with name %q through the API...", vm.GetUID(), vm.GetName())
+	return jobs.Run(context.Background(), jobs.ClassStart, jobs.Target(vm), audit.SourceAPI, func() error {
+		return operations.StartVM(context.Background(), vm, false)
+	})
+}
+
+// stopVM stops the VM, or kills it if the kill query parameter is set
+func (s *Server) stopVM(r *http.Request, ref string) (*api.VM, error) {
+	kill, err := queryBool(r, "kill")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vm, err := s.findVM(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if !vm.Running() {
+		return nil, &statusError{http.StatusConflict, fmt.Errorf("VM %q is not running", vm.GetUID())}
+	}
+
+	if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Stopping VM %q with name %q through the API...", vm.GetUID(), vm.GetName())
+	if err := jobs.Run(r.Context(), jobs.ClassStop, jobs.Target(vm), audit.SourceAPI, func() error {
+		return operations.StopVM(context.Background(), vm, kill, false)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Return the VM as it's saved after stopping
+	return s.findVM(string(vm.GetUID()))
+}
+
+// deleteVM removes the VM, a running VM is only removed if the force query parameter is set
+func (s *Server) deleteVM(r *http.Request, ref string) error {
+	force, err := queryBool(r, "force")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vm, err := s.findVM(ref)
+	if err != nil {
+		return err
+	}
+
+	if vm.Running() {
+		if !force {
+			return &statusError{http.StatusConflict, fmt.Errorf("VM %q is running, stop it or force its removal", vm.GetUID())}
+		}
+
+		if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Removing VM %q with name %q through the API...", vm.GetUID(), vm.GetName())
+	return jobs.Run(r.Context(), jobs.ClassRemove, jobs.Target(vm), audit.SourceAPI, func() error {
+		return operations.DeleteVM(s.client, vm)
+	})
+}
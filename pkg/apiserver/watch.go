@@ -0,0 +1,143 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// EventType is the type of a watch event
+type EventType string
+
+const (
+	// EventAdded is sent for the existing objects when the watch starts, and for new objects
+	EventAdded EventType = "ADDED"
+	// EventModified is sent when an object is changed
+	EventModified EventType = "MODIFIED"
+	// EventDeleted is sent when an object is removed
+	EventDeleted EventType = "DELETED"
+)
+
+// event is a line of the watch stream
+type event struct {
+	Type   EventType       `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// handleWatch streams the changes of the objects as newline-delimited JSON events. The
+// kind query parameter restricts the watch to VMs, images or kernels.
+func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, errMethodNotAllowed(r))
+		return
+	}
+
+	kinds := []runtime.Kind{api.KindVM, api.KindImage, api.KindKernel}
+	if kind := r.URL.Query().Get("kind"); len(kind) > 0 {
+		found := false
+		for _, k := range kinds {
+			if k.Title() == kind {
+				kinds, found = []runtime.Kind{k}, true
+				break
+			}
+		}
+
+		if !found {
+			writeError(w, &statusError{http.StatusBadRequest, fmt.Errorf("unknown kind %q, expected one of %v", kind, kinds)})
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	enc := json.NewEncoder(w)
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	objs := map[runtime.UID]runtime.Object{}
+	for {
+		current, err := s.listKinds(kinds)
+		if err != nil {
+			// The watch is already streaming, so the error can only end it
+			return
+		}
+
+		for _, e := range diffObjects(objs, current) {
+			if err := s.sendEvent(enc, e.eventType, e.obj); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+		objs = current
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) listKinds(kinds []runtime.Kind) (map[runtime.UID]runtime.Object, error) {
+	objs := map[runtime.UID]runtime.Object{}
+	for _, kind := range kinds {
+		list, err := s.client.Dynamic(kind).List()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range list {
+			objs[obj.GetUID()] = obj
+		}
+	}
+
+	return objs, nil
+}
+
+func (s *Server) sendEvent(enc *json.Encoder, eventType EventType, obj runtime.Object) error {
+	b, err := scheme.Serializer.EncodeJSON(obj)
+	if err != nil {
+		return err
+	}
+
+	return enc.Encode(event{eventType, b})
+}
+
+type objectEvent struct {
+	eventType EventType
+	obj       runtime.Object
+}
+
+// diffObjects returns the events that turn the old objects into the current ones
+func diffObjects(old, current map[runtime.UID]runtime.Object) []objectEvent {
+	var events []objectEvent
+	for uid, obj := range current {
+		if oldObj, ok := old[uid]; !ok {
+			events = append(events, objectEvent{EventAdded, obj})
+		} else if !reflect.DeepEqual(oldObj, obj) {
+			events = append(events, objectEvent{EventModified, obj})
+		}
+	}
+
+	for uid, obj := range old {
+		if _, ok := current[uid]; !ok {
+			events = append(events, objectEvent{EventDeleted, obj})
+		}
+	}
+
+	return events
+}
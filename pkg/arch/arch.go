@@ -0,0 +1,191 @@
+// Package arch describes the differences between the architectures ignite runs VMs on,
+// amd64 and arm64. The architectures are named like GOARCH, which is also how the OCI
+// image configs name them.
+package arch
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+// Arch describes how the VMs of an architecture are run
+type Arch struct {
+	// Name is the name of the architecture, e.g. arm64
+	Name string
+	// KernelArgs is the default kernel command line of the VMs
+	KernelArgs string
+	// KernelFiles are the names the kernel is looked up by in the /boot directory of the
+	// kernel images, in order. The names of distribution kernels are suffixed with their
+	// version, e.g. vmlinuz-5.10.0-8-amd64, and the compressed kernels are extracted.
+	KernelFiles []string
+	// KernelELF tells whether Firecracker boots an ELF vmlinux, or else a PE Image kernel
+	KernelELF bool
+	// SMT tells whether Firecracker can enable simultaneous multithreading for the vCPUs
+	SMT bool
+	// CtrlAltDel tells whether Firecracker can ask the guest to shut down by sending
+	// Ctrl+Alt+Del to its keyboard controller
+	CtrlAltDel bool
+	// QEMUBinary and QEMUMachine are the QEMU system emulator and machine type the VMs are
+	// emulated with on hosts without KVM. The machine has virtio-mmio devices like Firecracker,
+	// so the same kernels boot.
+	QEMUBinary  string
+	QEMUMachine string
+	// QEMUKernelArgs are added to the kernel command line of the emulated VMs
+	QEMUKernelArgs string
+	// QEMUHostCPU is the CPU model of the VMs QEMU runs with KVM, it hides the virtualization
+	// extensions of the host CPU from the VMs without nested virtualization
+	QEMUHostCPU string
+	// QEMUPCIMachine is the machine type of the VMs booting their kernel with host PCI
+	// devices passed through, it has a PCIe bus next to the virtio-mmio devices
+	QEMUPCIMachine string
+	// QEMUFirmwareMachine and QEMUFirmware are the machine type and the default UEFI firmware
+	// of the sandbox the VMs of disk images boot with. The machine has PCI devices, like the
+	// clouds the disk images are built for.
+	QEMUFirmwareMachine string
+	QEMUFirmware        string
+}
+
+var (
+	// AMD64 boots an ELF vmlinux, Firecracker describes the vCPUs in an MP table
+	AMD64 = &Arch{
+		Name:        "amd64",
+		KernelArgs:  "console=ttyS0 reboot=k panic=1 pci=off ip=dhcp",
+		KernelFiles: []string{"vmlinux", "vmlinuz", "bzImage"},
+		KernelELF:   true,
+		SMT:         true,
+		CtrlAltDel:  true,
+		QEMUBinary:  "qemu-system-x86_64",
+		QEMUMachine: "microvm",
+		QEMUHostCPU: "host,vmx=off,svm=off",
+		// The PCIe bus of microvm is described to the guest with ACPI
+		QEMUPCIMachine: "microvm,pcie=on",
+		// OVMF, the UEFI firmware of QEMU on amd64
+		QEMUFirmwareMachine: "q35",
+		QEMUFirmware:        "/usr/share/OVMF/OVMF.fd",
+	}
+
+	// ARM64 boots a PE Image kernel, Firecracker describes the machine in a device tree.
+	// The ignite kernel images keep the Image at /boot/vmlinux too. The console is the
+	// MMIO serial port, kept as the boot console to see the early boot messages.
+	ARM64 = &Arch{
+		Name:        "arm64",
+		KernelArgs:  "console=ttyS0 keep_bootcon reboot=k panic=1 pci=off ip=dhcp",
+		KernelFiles: []string{"vmlinux", "Image", "vmlinuz", "Image.gz"},
+		QEMUBinary:  "qemu-system-aarch64",
+		QEMUMachine: "virt",
+		// KVM only exposes EL2 to the guests of machines with virtualization=on
+		QEMUHostCPU: "host",
+		// The virt machine always has a PCIe bus
+		QEMUPCIMachine: "virt",
+		// The console of the virt machine is a PL011 UART
+		QEMUKernelArgs: "console=ttyAMA0",
+		// AAVMF, the UEFI firmware of QEMU on arm64
+		QEMUFirmwareMachine: "virt",
+		QEMUFirmware:        "/usr/share/AAVMF/QEMU_EFI.fd",
+	}
+)
+
+// Supported are the architectures ignite runs VMs on
+var Supported = []*Arch{AMD64, ARM64}
+
+// Host is the architecture of the host, the one ignite is built for
+var Host = get(runtime.GOARCH)
+
+// Get returns the supported architecture of the given name
+func Get(name string) (*Arch, error) {
+	for _, a := range Supported {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported architecture %q", name)
+}
+
+// get returns the architecture of the given name, unsupported ones are described like amd64
+// so ignite builds on them
+func get(name string) *Arch {
+	if a, err := Get(name); err == nil {
+		return a
+	}
+
+	a := *AMD64
+	a.Name = name
+	return &a
+}
+
+// Check returns an error if the thing built for the architecture name can't be run on the
+// host. Things of unknown architecture, e.g. from before it was recorded, are accepted.
+func Check(what, name string) error {
+	if len(name) == 0 || name == Host.Name {
+		return nil
+	}
+
+	return fmt.Errorf("%s is built for %s, but the host is %s", what, name, Host.Name)
+}
+
+// arm64ImageMagic is the magic number of the header of arm64 Image kernels, "ARM\x64" at
+// offset 56, see Documentation/arm64/booting.rst in the kernel
+const (
+	arm64ImageMagic       = 0x644d5241
+	arm64ImageMagicOffset = 56
+)
+
+// Kernel returns the architecture of the uncompressed kernel file, and whether it's an ELF
+// vmlinux. Other kernels are only recognized in the arm64 Image format.
+func Kernel(file string) (string, bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	if ef, err := elf.NewFile(f); err == nil {
+		switch ef.Machine {
+		case elf.EM_X86_64:
+			return AMD64.Name, true, nil
+		case elf.EM_AARCH64:
+			return ARM64.Name, true, nil
+		default:
+			return "", true, fmt.Errorf("unsupported kernel machine %s", ef.Machine)
+		}
+	}
+
+	header := make([]byte, arm64ImageMagicOffset+4)
+	if _, err := f.ReadAt(header, 0); err != nil && err != io.EOF {
+		return "", false, err
+	}
+
+	if binary.LittleEndian.Uint32(header[arm64ImageMagicOffset:]) == arm64ImageMagic {
+		return ARM64.Name, false, nil
+	}
+
+	return "", false, fmt.Errorf("unknown kernel format, the kernel should be an uncompressed ELF vmlinux or arm64 Image")
+}
+
+// CheckKernel returns an error if the uncompressed kernel file can't be booted on the host,
+// and otherwise the architecture of the kernel
+func CheckKernel(file string) (string, error) {
+	name, isELF, err := Kernel(file)
+	if err != nil {
+		return "", err
+	}
+
+	if err := Check("the kernel", name); err != nil {
+		return "", err
+	}
+
+	if isELF != Host.KernelELF {
+		format := "an ELF vmlinux"
+		if !Host.KernelELF {
+			format = "a PE Image"
+		}
+		return "", fmt.Errorf("firecracker boots %s kernel on %s", format, Host.Name)
+	}
+
+	return name, nil
+}
@@ -0,0 +1,146 @@
+package arch
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+// elfKernel returns the header of an ELF vmlinux for the machine
+func elfKernel(machine elf.Machine) []byte {
+	header := elf.Header64{
+		Type:    uint16(elf.ET_EXEC),
+		Machine: uint16(machine),
+		Version: uint32(elf.EV_CURRENT),
+		Ehsize:  64,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	buf := &bytes.Buffer{}
+	_ = binary.Write(buf, binary.LittleEndian, &header)
+	return buf.Bytes()
+}
+
+// imageKernel returns the header of an arm64 Image kernel
+func imageKernel() []byte {
+	header := make([]byte, 64)
+	binary.LittleEndian.PutUint32(header[arm64ImageMagicOffset:], arm64ImageMagic)
+	return header
+}
+
+func TestKernel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-arch-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content []byte
+		arch    string
+		elf     bool
+		err     string
+	}{
+		{
+			name:    "amd64 vmlinux",
+			content: elfKernel(elf.EM_X86_64),
+			arch:    "amd64",
+			elf:     true,
+		},
+		{
+			name:    "arm64 vmlinux",
+			content: elfKernel(elf.EM_AARCH64),
+			arch:    "arm64",
+			elf:     true,
+		},
+		{
+			name:    "arm64 Image",
+			content: imageKernel(),
+			arch:    "arm64",
+		},
+		{
+			name:    "riscv vmlinux",
+			content: elfKernel(elf.EM_RISCV),
+			elf:     true,
+			err:     "unsupported kernel machine EM_RISCV",
+		},
+		{
+			name:    "compressed",
+			content: []byte{0x1f, 0x8b, 0x08},
+			err:     "unknown kernel format",
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			file := path.Join(dir, "vmlinux")
+			assert.NilError(t, ioutil.WriteFile(file, rt.content, 0644))
+
+			arch, isELF, err := Kernel(file)
+			if len(rt.err) > 0 {
+				assert.ErrorContains(t, err, rt.err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, arch, rt.arch)
+			assert.Equal(t, isELF, rt.elf)
+		})
+	}
+}
+
+func TestCheckKernel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-arch-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	host := Host
+	defer func() { Host = host }()
+
+	file := path.Join(dir, "vmlinux")
+	write := func(content []byte) {
+		assert.NilError(t, ioutil.WriteFile(file, content, 0644))
+	}
+
+	Host = AMD64
+	write(elfKernel(elf.EM_X86_64))
+	arch, err := CheckKernel(file)
+	assert.NilError(t, err)
+	assert.Equal(t, arch, "amd64")
+
+	write(imageKernel())
+	_, err = CheckKernel(file)
+	assert.Error(t, err, "the kernel is built for arm64, but the host is amd64")
+
+	// Firecracker only boots the Image of arm64 kernels
+	Host = ARM64
+	arch, err = CheckKernel(file)
+	assert.NilError(t, err)
+	assert.Equal(t, arch, "arm64")
+
+	write(elfKernel(elf.EM_AARCH64))
+	_, err = CheckKernel(file)
+	assert.Error(t, err, "firecracker boots a PE Image kernel on arm64")
+}
+
+func TestCheck(t *testing.T) {
+	host := Host
+	defer func() { Host = host }()
+	Host = ARM64
+
+	assert.NilError(t, Check(`image "ubuntu"`, "arm64"))
+	assert.NilError(t, Check(`image "ubuntu"`, ""))
+	assert.Error(t, Check(`image "ubuntu"`, "amd64"), `image "ubuntu" is built for amd64, but the host is arm64`)
+
+	_, err := Get("s390x")
+	assert.Error(t, err, `unsupported architecture "s390x"`)
+	assert.Equal(t, get("s390x").KernelArgs, AMD64.KernelArgs)
+}
@@ -0,0 +1,175 @@
+// Package audit records the create, start, stop, delete, import and other mutating
+// operations with the actor that requested them, their arguments and their results, to
+// an append-only audit log.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"os"
+	"os/user"
+	"path"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// The sources of the operations
+const (
+	// SourceCLI is an ignite command
+	SourceCLI = "cli"
+	// SourceAPI is a request to the management API of ignited
+	SourceAPI = "api"
+	// SourceReconcile is the reconciliation of a manifest by ignited daemon or ignited gitops
+	SourceReconcile = "reconcile"
+	// SourceCRI is a request of a kubelet to the CRI server of ignited daemon
+	SourceCRI = "cri"
+)
+
+// The results of the operations
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Entry records an operation in the audit log
+type Entry struct {
+	// Time is when the operation started, EndTime when it finished
+	Time    time.Time `json:"time"`
+	EndTime time.Time `json:"endTime"`
+	// Actor is the user that requested the operation
+	Actor  string `json:"actor"`
+	Source string `json:"source"`
+	// Operation is e.g. the ignite command, or the method and path of the API request
+	Operation string   `json:"operation"`
+	Args      []string `json:"args,omitempty"`
+	Result    string   `json:"result"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Begin returns an entry for the operation the actor starts now, record it with Finish
+func Begin(source, actor, operation string, args ...string) *Entry {
+	return &Entry{
+		Time:      time.Now().UTC(),
+		Actor:     actor,
+		Source:    source,
+		Operation: operation,
+		Args:      args,
+	}
+}
+
+// Finish records the entry with the result of the operation. A failure to record it is
+// logged, it doesn't fail the operation.
+func (e *Entry) Finish(err error) {
+	e.EndTime = time.Now().UTC()
+	e.Result = ResultSuccess
+	if err != nil {
+		e.Result = ResultFailure
+		e.Error = err.Error()
+	}
+
+	if err := Write(e); err != nil {
+		log.Errorf("Failed to record %q in the audit log: %v", e.Operation, err)
+	}
+}
+
+// Path returns the path of the audit log file, from the ignite configuration or the default
+func Path() string {
+	if providers.ComponentConfig != nil && len(providers.ComponentConfig.Spec.Audit.Path) > 0 {
+		return providers.ComponentConfig.Spec.Audit.Path
+	}
+
+	return path.Join(constants.DATA_DIR, constants.AUDIT_LOG)
+}
+
+// toSyslog returns true if the ignite configuration sends the audit log to syslog
+func toSyslog() bool {
+	return providers.ComponentConfig != nil && providers.ComponentConfig.Spec.Audit.Syslog
+}
+
+// Write appends the entry to the audit log as a line of JSON, or sends it to syslog
+func Write(e *Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if toSyslog() {
+		w, err := syslog.New(syslog.LOG_NOTICE|syslog.LOG_AUTHPRIV, "ignite-audit")
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+
+		return w.Notice(string(b))
+	}
+
+	// The file is only ever appended to, and only root may read it. It's opened for every
+	// entry, as the CLI and ignited append to it concurrently.
+	f, err := os.OpenFile(Path(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Read reads the entries of the audit log file, oldest first
+func Read(file string) ([]*Entry, error) {
+	if toSyslog() {
+		return nil, fmt.Errorf("the audit log is sent to syslog, query it there")
+	}
+
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		e := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			return nil, fmt.Errorf("line %d of %s: %v", line, file, err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, scanner.Err()
+}
+
+// LocalActor returns the user running the process, the user that ran sudo if it runs with sudo
+func LocalActor() string {
+	if sudoUser := os.Getenv("SUDO_USER"); len(sudoUser) > 0 {
+		return sudoUser
+	}
+
+	return UserName(os.Getuid())
+}
+
+// UserName returns the name of the user with the given ID, or the ID if it has no name
+func UserName(uid int) string {
+	if u, err := user.LookupId(fmt.Sprint(uid)); err == nil {
+		return u.Username
+	}
+
+	return fmt.Sprintf("uid=%d", uid)
+}
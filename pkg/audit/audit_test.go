@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+func TestWriteRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-audit-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	providers.ComponentConfig = &api.Configuration{}
+	providers.ComponentConfig.Spec.Audit.Path = path.Join(dir, "audit.log")
+	defer func() { providers.ComponentConfig = nil }()
+
+	// A missing audit log has no entries
+	entries, err := Read(Path())
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 0)
+
+	Begin(SourceCLI, "alice", "vm start", "start", "my-vm").Finish(nil)
+	Begin(SourceAPI, "bob", "DELETE /v1/vms/my-vm").Finish(errors.New("404 Not Found"))
+
+	entries, err = Read(Path())
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+
+	assert.Equal(t, entries[0].Actor, "alice")
+	assert.Equal(t, entries[0].Source, SourceCLI)
+	assert.DeepEqual(t, entries[0].Args, []string{"start", "my-vm"})
+	assert.Equal(t, entries[0].Result, ResultSuccess)
+	assert.Assert(t, !entries[0].EndTime.Before(entries[0].Time))
+
+	assert.Equal(t, entries[1].Operation, "DELETE /v1/vms/my-vm")
+	assert.Equal(t, entries[1].Result, ResultFailure)
+	assert.Equal(t, entries[1].Error, "404 Not Found")
+
+	// Only root may read the audit log
+	fi, err := os.Stat(Path())
+	assert.NilError(t, err)
+	assert.Equal(t, fi.Mode().Perm(), os.FileMode(0600))
+}
+
+func TestReadSyslog(t *testing.T) {
+	providers.ComponentConfig = &api.Configuration{}
+	providers.ComponentConfig.Spec.Audit.Syslog = true
+	defer func() { providers.ComponentConfig = nil }()
+
+	_, err := Read(Path())
+	assert.ErrorContains(t, err, "sent to syslog")
+}
@@ -0,0 +1,102 @@
+package authorizedkeys
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Parse parses a single public key in the authorized_keys format
+func Parse(key string) (ssh.PublicKey, error) {
+	pubKey, _, _, rest, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, fmt.Errorf("expected a single public key")
+	}
+
+	return pubKey, nil
+}
+
+// ParseList parses the public keys in the authorized_keys format in content, one per
+// line. Empty lines and comments are skipped.
+func ParseList(content []byte) ([]string, error) {
+	var keys []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if _, err := Parse(line); err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %v", line, err)
+		}
+
+		keys = append(keys, line)
+	}
+
+	return keys, nil
+}
+
+// Contains returns true if one of the keys is the same public key as key. Their
+// options and comments are ignored, lines that aren't keys never match.
+func Contains(keys []string, key string) bool {
+	pubKey, err := Parse(key)
+	if err != nil {
+		return false
+	}
+
+	for _, k := range keys {
+		if other, err := Parse(k); err == nil && bytes.Equal(other.Marshal(), pubKey.Marshal()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Add adds the keys to the authorized_keys file content, unless they're in it already
+func Add(content []byte, keys []string) []byte {
+	lines := splitLines(content)
+	for _, key := range keys {
+		if !Contains(lines, key) {
+			lines = append(lines, key)
+		}
+	}
+
+	return joinLines(lines)
+}
+
+// Remove removes the keys from the authorized_keys file content, other lines are kept
+func Remove(content []byte, keys []string) []byte {
+	lines := splitLines(content)
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if !Contains(keys, line) {
+			kept = append(kept, line)
+		}
+	}
+
+	return joinLines(kept)
+}
+
+func splitLines(content []byte) []string {
+	trimmed := strings.TrimRight(string(content), "\n")
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	return strings.Split(trimmed, "\n")
+}
+
+func joinLines(lines []string) []byte {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
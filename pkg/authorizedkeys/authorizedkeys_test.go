@@ -0,0 +1,88 @@
+package authorizedkeys
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const (
+	aliceKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJdbFdgbfD6sOsr5VGPWGbO1kgmnDfHU14rlcwNHBdDq alice@laptop"
+	bobKey   = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFsYRIg18Qf7qrDhFyzoSC2q5C82gchbtIFIxm4+CQas bob@desk"
+)
+
+func TestParseList(t *testing.T) {
+	keys, err := ParseList([]byte("# team keys\n" + aliceKey + "\n\n" + bobKey + "\n"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, keys, []string{aliceKey, bobKey})
+
+	_, err = ParseList([]byte(aliceKey + "\nnot a key\n"))
+	assert.ErrorContains(t, err, "not a key")
+}
+
+func TestAdd(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		keys    []string
+		want    string
+	}{
+		{
+			name: "empty file",
+			keys: []string{aliceKey},
+			want: aliceKey + "\n",
+		},
+		{
+			name:    "appended",
+			content: "# keys\n" + aliceKey,
+			keys:    []string{bobKey},
+			want:    "# keys\n" + aliceKey + "\n" + bobKey + "\n",
+		},
+		{
+			name:    "already authorized with another comment",
+			content: `no-pty ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJdbFdgbfD6sOsr5VGPWGbO1kgmnDfHU14rlcwNHBdDq alice@desktop` + "\n",
+			keys:    []string{aliceKey},
+			want:    `no-pty ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJdbFdgbfD6sOsr5VGPWGbO1kgmnDfHU14rlcwNHBdDq alice@desktop` + "\n",
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.Equal(t, string(Add([]byte(rt.content), rt.keys)), rt.want)
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		keys    []string
+		want    string
+	}{
+		{
+			name:    "removed by key, not comment",
+			content: "# keys\n" + aliceKey + "\n" + bobKey + "\n",
+			keys:    []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJdbFdgbfD6sOsr5VGPWGbO1kgmnDfHU14rlcwNHBdDq"},
+			want:    "# keys\n" + bobKey + "\n",
+		},
+		{
+			name:    "not authorized",
+			content: aliceKey + "\n",
+			keys:    []string{bobKey},
+			want:    aliceKey + "\n",
+		},
+		{
+			name:    "last key",
+			content: aliceKey + "\n",
+			keys:    []string{aliceKey},
+			want:    "",
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.Equal(t, string(Remove([]byte(rt.content), rt.keys)), rt.want)
+		})
+	}
+}
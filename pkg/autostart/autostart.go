@@ -0,0 +1,155 @@
+// Package autostart starts the VMs that are marked to be started automatically when
+// the host boots. The VMs are started in dependency order: a VM is only started once
+// the VMs listed in its autostartAfter field are running.
+package autostart
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// visitState tracks the progress of ordering a VM
+type visitState int
+
+const (
+	unvisited visitState = iota
+	visiting
+	ordered
+	failed
+)
+
+// Order returns the VMs to autostart, ordered so that every VM comes after the VMs it
+// needs to be started after. The dependencies of autostarted VMs are included, even if
+// they don't set autostart themselves. VMs with unknown or cyclic dependencies are left
+// out together with the VMs depending on them, the returned error lists them.
+func Order(vms []*api.VM) ([]*api.VM, error) {
+	// Dependencies are referenced by name or ID
+	refs := make(map[string]*api.VM, 2*len(vms))
+	for _, vm := range vms {
+		refs[vm.GetName()] = vm
+		refs[vm.GetUID().String()] = vm
+	}
+
+	// Sort the VMs by name, so VMs without dependencies between them start in a stable order
+	sorted := append([]*api.VM(nil), vms...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+
+	var result []*api.VM
+	states := make(map[runtime.UID]visitState, len(vms))
+	var visit func(vm *api.VM) error
+	visit = func(vm *api.VM) error {
+		switch states[vm.GetUID()] {
+		case visiting:
+			return fmt.Errorf("VM %q depends on itself through autostartAfter", vm.GetName())
+		case ordered:
+			return nil
+		case failed:
+			return fmt.Errorf("VM %q can't be autostarted", vm.GetName())
+		}
+
+		states[vm.GetUID()] = visiting
+		for _, ref := range vm.Spec.AutostartAfter {
+			dep, ok := refs[ref]
+			if !ok {
+				states[vm.GetUID()] = failed
+				return fmt.Errorf("VM %q needs to be started after unknown VM %q", vm.GetName(), ref)
+			}
+
+			if err := visit(dep); err != nil {
+				states[vm.GetUID()] = failed
+				return err
+			}
+		}
+
+		states[vm.GetUID()] = ordered
+		result = append(result, vm)
+		return nil
+	}
+
+	var errs []error
+	for _, vm := range sorted {
+		if !vm.Spec.Autostart {
+			continue
+		}
+
+		if err := visit(vm); err != nil {
+			errs = append(errs, fmt.Errorf("not autostarting VM %q: %v", vm.GetName(), err))
+		}
+	}
+
+	return result, utilerrors.NewAggregate(errs)
+}
+
+// StartAll starts the VMs marked for autostart that aren't running, in dependency
+// order. A VM isn't started if one of the VMs it depends on failed to start.
+func StartAll() error {
+	vms, err := providers.Client.VMs().FindAll(filter.NewAllFilter())
+	if err != nil {
+		return err
+	}
+
+	toStart, err := Order(vms)
+	if err != nil {
+		log.Warn(err)
+	}
+
+	failedVMs := make(map[string]bool)
+	for _, vm := range toStart {
+		if dep := failedDependency(vm, failedVMs); len(dep) > 0 {
+			log.Warnf("Not autostarting VM %q, the VM %q it depends on failed to start", vm.GetName(), dep)
+		} else if err := start(vm); err != nil {
+			log.Errorf("Failed to autostart VM %q: %v", vm.GetName(), err)
+		} else {
+			continue
+		}
+
+		failedVMs[vm.GetName()] = true
+		failedVMs[vm.GetUID().String()] = true
+	}
+
+	return nil
+}
+
+// failedDependency returns the first dependency of the VM that failed to start
+func failedDependency(vm *api.VM, failedVMs map[string]bool) string {
+	for _, ref := range vm.Spec.AutostartAfter {
+		if failedVMs[ref] {
+			return ref
+		}
+	}
+
+	return ""
+}
+
+// start starts the VM unless its container is running already. After a reboot of
+// the host, the status of the VMs that were running when it went down is stale.
+func start(vm *api.VM) error {
+	// Use the runtime and network plugin of the VM's spec, otherwise the ones it ran with
+	config.ResolveVMProviders(vm)
+
+	if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+		return err
+	}
+
+	if result, err := providers.Runtime.InspectContainer(vm.PrefixedID()); err == nil && strings.EqualFold(result.Status, "running") {
+		log.Infof("VM %q with name %q is already running", vm.GetUID(), vm.GetName())
+		return nil
+	}
+
+	log.Infof("Autostarting VM %q with name %q...", vm.GetUID(), vm.GetName())
+	return operations.StartVM(context.Background(), vm, false)
+}
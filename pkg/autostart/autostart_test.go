@@ -0,0 +1,73 @@
+package autostart
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+func newVM(name string, autostart bool, after ...string) *api.VM {
+	vm := &api.VM{}
+	vm.SetName(name)
+	vm.SetUID(runtime.UID(name + "-uid"))
+	vm.Spec.Autostart = autostart
+	vm.Spec.AutostartAfter = after
+	return vm
+}
+
+func names(vms []*api.VM) []string {
+	result := make([]string, 0, len(vms))
+	for _, vm := range vms {
+		result = append(result, vm.GetName())
+	}
+
+	return result
+}
+
+func TestOrder(t *testing.T) {
+	cases := []struct {
+		name    string
+		vms     []*api.VM
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "no autostart",
+			vms:  []*api.VM{newVM("a", false), newVM("b", false)},
+			want: []string{},
+		},
+		{
+			name: "sorted by name without dependencies",
+			vms:  []*api.VM{newVM("c", true), newVM("a", true), newVM("b", false)},
+			want: []string{"a", "c"},
+		},
+		{
+			name: "dependencies first",
+			vms:  []*api.VM{newVM("app", true, "db", "cache"), newVM("db", true), newVM("cache", false, "db-uid")},
+			want: []string{"db", "cache", "app"},
+		},
+		{
+			name:    "unknown dependency",
+			vms:     []*api.VM{newVM("app", true, "db"), newVM("web", true, "app"), newVM("other", true)},
+			want:    []string{"other"},
+			wantErr: true,
+		},
+		{
+			name:    "dependency cycle",
+			vms:     []*api.VM{newVM("a", true, "b"), newVM("b", true, "a"), newVM("c", true)},
+			want:    []string{"c"},
+			wantErr: true,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			ordered, err := Order(rt.vms)
+			assert.Equal(t, err != nil, rt.wantErr, "unexpected error: %v", err)
+			assert.DeepEqual(t, names(ordered), rt.want)
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/migration"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// A VM archive is a gzipped tar archive holding the VM object (metadata.json),
+// optionally the SSH keys generated for the VM and the snapshot of a VM prepared
+// for migration, and the raw contents of its disk (disk.img) as the last entry.
+// Backups and exported VMs share this format.
+const (
+	// diskEntry is the archive entry holding the raw contents of the VM's disk
+	diskEntry = "disk.img"
+	// sshKeyEntry is the archive entry holding the private SSH key generated for the VM,
+	// the public key is stored in an entry with the .pub suffix
+	sshKeyEntry = "id"
+	// chunkSize is the unit in which restored disks are compared and written
+	chunkSize = constants.MB
+)
+
+// migrationFiles are the files of the migration directory of a VM prepared for migration
+var migrationFiles = []string{migration.ManifestFile, migration.StateFile, migration.MemoryFile}
+
+// Export writes an archive of the VM to w, from which the VM can be recreated on
+// another host with Import. The disk of a running VM is read while the VM is using it,
+// so it's only as consistent as the disk after a crash of the VM.
+func Export(vm *api.VM, w io.Writer) error {
+	// A VM prepared for migration is paused, so its disk doesn't change
+	pendingMigration := migration.Pending(vm)
+	if vm.Running() && !pendingMigration {
+		log.Warnf("VM %q is running, stop it for a consistent export of its disk", vm.GetUID())
+	}
+
+	// Bundle the generated SSH keys, so "ignite ssh" works for the imported VM
+	files := make(map[string]string, 2+len(migrationFiles))
+	keyPath := sshKeyPath(vm)
+	for entry, file := range map[string]string{sshKeyEntry: keyPath, sshKeyEntry + ".pub": keyPath + ".pub"} {
+		if util.FileExists(file) {
+			files[entry] = file
+		}
+	}
+
+	// Bundle the snapshot of a VM prepared for migration, it's restored on the next start of the imported VM
+	if pendingMigration {
+		for _, name := range migrationFiles {
+			files[path.Join(constants.MIGRATION_DIR, name)] = migration.File(vm, name)
+		}
+	}
+
+	return withDisk(vm, os.O_RDONLY, func(disk *os.File, size int64) error {
+		return writeArchive(w, vm, files, disk, size)
+	})
+}
+
+// Import reads an archive written by Export from r. The VM object of the archive is
+// passed to create, which needs to create the VM on this host with a writable disk,
+// and return it. The disk of the created VM is then overwritten with the archived disk.
+func Import(r io.Reader, create func(vm *api.VM) (*api.VM, error)) (*api.VM, error) {
+	ar, err := newArchiveReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read VM archive: %v", err)
+	}
+	defer ar.Close()
+
+	var vm *api.VM
+	for {
+		hdr, err := ar.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("the VM archive contains no disk")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read VM archive: %v", err)
+		}
+
+		if hdr.Name == constants.METADATA {
+			b, err := ioutil.ReadAll(ar)
+			if err != nil {
+				return nil, err
+			}
+
+			archived := &api.VM{}
+			if err := scheme.Serializer.DecodeInto(b, archived); err != nil {
+				return nil, fmt.Errorf("failed to decode the VM of the archive: %v", err)
+			}
+
+			if vm, err = create(archived); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// All other entries belong to the VM, which is the first entry
+		if vm == nil {
+			return nil, fmt.Errorf("the VM archive doesn't start with the VM object")
+		}
+
+		switch hdr.Name {
+		case sshKeyEntry, sshKeyEntry + ".pub":
+			// The keys are named by the UID of the VM, which might differ on this host
+			file := sshKeyPath(vm) + hdr.Name[len(sshKeyEntry):]
+			if err := writeFile(file, ar, os.FileMode(hdr.Mode)); err != nil {
+				return nil, err
+			}
+		case path.Join(constants.MIGRATION_DIR, migration.ManifestFile),
+			path.Join(constants.MIGRATION_DIR, migration.StateFile),
+			path.Join(constants.MIGRATION_DIR, migration.MemoryFile):
+			if err := os.MkdirAll(migration.Dir(vm), constants.DATA_DIR_PERM); err != nil {
+				return nil, err
+			}
+
+			if err := writeFile(migration.File(vm, path.Base(hdr.Name)), ar, os.FileMode(hdr.Mode)); err != nil {
+				return nil, err
+			}
+		case diskEntry:
+			return vm, restoreDisk(vm, ar, hdr.Size)
+		default:
+			log.Warnf("Ignoring unknown entry %q of VM archive", hdr.Name)
+		}
+	}
+}
+
+// writeArchive writes a VM archive of the VM, the given files (archive entry to path on
+// the host), and the given disk to w
+func writeArchive(w io.Writer, vm *api.VM, files map[string]string, disk io.Reader, size int64) error {
+	metadata, err := scheme.Serializer.EncodeJSON(vm)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	if err := writeEntry(tw, constants.METADATA, 0644, int64(len(metadata)), bytes.NewReader(metadata), now); err != nil {
+		return err
+	}
+
+	entries := make([]string, 0, len(files))
+	for entry := range files {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+
+	for _, entry := range entries {
+		if err := writeFileEntry(tw, entry, files[entry]); err != nil {
+			return err
+		}
+	}
+
+	if err := writeEntry(tw, diskEntry, 0644, size, disk, now); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// writeEntry writes an entry with the given contents to the tar archive
+func writeEntry(tw *tar.Writer, name string, mode os.FileMode, size int64, r io.Reader, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    int64(mode),
+		Size:    size,
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.CopyN(tw, r, size)
+	return err
+}
+
+// writeFileEntry writes the given file on the host as an entry to the tar archive
+func writeFileEntry(tw *tar.Writer, name, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	return writeEntry(tw, name, fi.Mode().Perm(), fi.Size(), f, fi.ModTime())
+}
+
+// writeFile writes the contents of r to the given file on the host
+func writeFile(file string, r io.Reader, mode os.FileMode) (err error) {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, f.Close)
+
+	_, err = io.Copy(f, r)
+	return
+}
+
+// archiveReader reads the entries of a VM archive
+type archiveReader struct {
+	*tar.Reader
+	gr *gzip.Reader
+}
+
+func newArchiveReader(r io.Reader) (*archiveReader, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &archiveReader{Reader: tar.NewReader(gr), gr: gr}, nil
+}
+
+func (a *archiveReader) Close() error {
+	return a.gr.Close()
+}
+
+// restoreDisk writes the archived disk read from r to the disk of the VM, skipping
+// the chunks that already match. This keeps the allocation of sparse and
+// copy-on-write disks low, as most of the disk is usually unchanged.
+func restoreDisk(vm *api.VM, r io.Reader, size int64) error {
+	return withDisk(vm, os.O_RDWR, func(disk *os.File, diskSize int64) error {
+		if size > diskSize {
+			return fmt.Errorf("the archived disk (%d bytes) doesn't fit the disk of VM %q (%d bytes)", size, vm.GetUID(), diskSize)
+		}
+
+		archivedChunk := make([]byte, chunkSize)
+		diskChunk := make([]byte, chunkSize)
+
+		for offset := int64(0); offset < size; {
+			n, err := io.ReadFull(r, archivedChunk)
+			if err != nil && err != io.ErrUnexpectedEOF {
+				return err
+			}
+
+			if _, err := disk.ReadAt(diskChunk[:n], offset); err != nil && err != io.EOF {
+				return err
+			}
+
+			if !bytes.Equal(archivedChunk[:n], diskChunk[:n]) {
+				if _, err := disk.WriteAt(archivedChunk[:n], offset); err != nil {
+					return err
+				}
+			}
+
+			offset += int64(n)
+		}
+
+		return disk.Sync()
+	})
+}
+
+// sshKeyPath returns the path of the private SSH key generated for the VM
+func sshKeyPath(vm *api.VM) string {
+	return path.Join(vm.ObjectPath(), fmt.Sprintf(constants.VM_SSH_KEY_TEMPLATE, vm.GetUID()))
+}
@@ -0,0 +1,222 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/migration"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+const (
+	// archiveSuffix is the file extension of backup archives
+	archiveSuffix = ".tar.gz"
+	// nameLayout formats the creation time of a backup into its name
+	nameLayout = "20060102T150405Z"
+)
+
+// Backup describes a stored backup of the disk of a VM
+type Backup struct {
+	// Name identifies the backup in its destination
+	Name string
+	// Created is the time the backup was started at
+	Created time.Time
+}
+
+// Create backs up the disk of the VM to the destination of its backup policy.
+// The backup archive contains the VM object and the raw contents of its disk.
+// The disk of a running VM is read while the VM is using it, so the backup
+// is only as consistent as the state after a crash of the VM.
+func Create(vm *api.VM) (*Backup, error) {
+	dest, err := destinationFor(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	created := time.Now().UTC()
+	backup := &Backup{
+		Name:    created.Format(nameLayout) + archiveSuffix,
+		Created: created,
+	}
+
+	if err := withDisk(vm, os.O_RDONLY, func(disk *os.File, size int64) error {
+		return dest.store(backup.Name, func(w io.Writer) error {
+			return writeArchive(w, vm, nil, disk, size)
+		})
+	}); err != nil {
+		return nil, fmt.Errorf("failed to back up VM %q: %v", vm.GetUID(), err)
+	}
+
+	return backup, nil
+}
+
+// List returns the backups of the VM stored in the destination of its backup policy, oldest first
+func List(vm *api.VM) ([]Backup, error) {
+	dest, err := destinationFor(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := dest.list()
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]Backup, 0, len(names))
+	for _, name := range names {
+		created, err := parseName(name)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Name: name, Created: created})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Created.Before(backups[j].Created)
+	})
+
+	return backups, nil
+}
+
+// Restore overwrites the disk of the VM with the contents of the given backup.
+// Only the blocks differing from the backup are written, the VM may not be running.
+func Restore(vm *api.VM, name string) error {
+	if vm.Running() {
+		return fmt.Errorf("VM %q is running, stop it before restoring a backup", vm.GetUID())
+	}
+
+	dest, err := destinationFor(vm)
+	if err != nil {
+		return err
+	}
+
+	r, err := dest.open(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	ar, err := newArchiveReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read backup %q: %v", name, err)
+	}
+	defer ar.Close()
+
+	for {
+		hdr, err := ar.Next()
+		if err == io.EOF {
+			return fmt.Errorf("backup %q contains no disk", name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read backup %q: %v", name, err)
+		}
+
+		if hdr.Name == diskEntry {
+			if err := discardSavedState(vm); err != nil {
+				return err
+			}
+
+			return restoreDisk(vm, ar, hdr.Size)
+		}
+	}
+}
+
+// discardSavedState removes the state saved by hibernating the VM, the saved
+// memory doesn't match a restored disk, so the VM is booted on its next start
+func discardSavedState(vm *api.VM) error {
+	if !migration.Pending(vm) {
+		return nil
+	}
+
+	log.Warnf("Discarding the saved state of VM %q, it boots on its next start", vm.GetUID())
+	return migration.Remove(vm)
+}
+
+// Prune removes the oldest backups of the VM exceeding the retention of its backup policy
+func Prune(vm *api.VM) error {
+	if vm.Spec.Backup == nil || vm.Spec.Backup.Retention == 0 {
+		return nil // All backups are kept
+	}
+
+	backups, err := List(vm)
+	if err != nil {
+		return err
+	}
+
+	dest := newDestination(vm, vm.Spec.Backup.Destination)
+	for i := 0; uint64(len(backups)-i) > vm.Spec.Backup.Retention; i++ {
+		log.Debugf("Removing backup %q of VM %q", backups[i].Name, vm.GetUID())
+		if err := dest.remove(backups[i].Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// destinationFor returns the destination of the backup policy of the VM
+func destinationFor(vm *api.VM) (destination, error) {
+	if vm.Spec.Backup == nil {
+		return nil, fmt.Errorf("VM %q has no backup policy", vm.GetUID())
+	}
+
+	return newDestination(vm, vm.Spec.Backup.Destination), nil
+}
+
+// withDisk opens the snapshot device of the VM with the given flags for the given function.
+// The snapshot of a stopped VM is activated for the duration of the function.
+func withDisk(vm *api.VM, flag int, f func(disk *os.File, size int64) error) (err error) {
+	var devicePath string
+	if vm.Running() {
+		devicePath, err = operations.SnapshotDevice(vm)
+	} else {
+		devicePath, err = operations.ActivateSnapshot(vm)
+		if err == nil {
+			defer util.DeferErr(&err, func() error { return operations.DeactivateSnapshot(vm) })
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	disk, err := os.OpenFile(devicePath, flag, 0)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, disk.Close)
+
+	// Block devices don't report their size through stat
+	size, err := disk.Seek(0, io.SeekEnd)
+	if err != nil {
+		return
+	}
+
+	if _, err = disk.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+
+	return f(disk, size)
+}
+
+// isBackupName reports whether the given file name is the name of a backup
+func isBackupName(name string) bool {
+	_, err := parseName(name)
+	return err == nil
+}
+
+// parseName parses the creation time from the name of a backup
+func parseName(name string) (time.Time, error) {
+	if !strings.HasSuffix(name, archiveSuffix) {
+		return time.Time{}, fmt.Errorf("%q is not a backup archive", name)
+	}
+
+	return time.Parse(nameLayout, strings.TrimSuffix(name, archiveSuffix))
+}
@@ -0,0 +1,231 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// checkpointSuffix is the file extension of checkpoint images
+const checkpointSuffix = ".img"
+
+var checkpointNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Checkpoint describes a saved state of the disk of a VM
+type Checkpoint struct {
+	// Name identifies the checkpoint of the VM
+	Name string
+	// Created is the time the checkpoint was taken at
+	Created time.Time
+	// Usage is the amount of host disk space allocated by the checkpoint
+	Usage meta.Size
+}
+
+// CreateCheckpoint saves the current state of the disk of the stopped VM under the
+// given name, or under its creation time if name is empty. Checkpoints are sparse raw
+// images stored in the VM directory, independent of the snapshotter of the VM.
+func CreateCheckpoint(vm *api.VM, name string) (*Checkpoint, error) {
+	if vm.Running() {
+		return nil, fmt.Errorf("VM %q is running, stop it before taking a checkpoint", vm.GetUID())
+	}
+
+	created := time.Now().UTC()
+	if len(name) == 0 {
+		name = created.Format(nameLayout)
+	}
+
+	if err := validateCheckpointName(name); err != nil {
+		return nil, err
+	}
+
+	file := checkpointFile(vm, name)
+	if util.FileExists(file) {
+		return nil, fmt.Errorf("checkpoint %q of VM %q already exists", name, vm.GetUID())
+	}
+
+	if err := os.MkdirAll(path.Dir(file), constants.DATA_DIR_PERM); err != nil {
+		return nil, err
+	}
+
+	if err := withDisk(vm, os.O_RDONLY, func(disk *os.File, size int64) error {
+		return writeSparse(file, disk, size)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to take checkpoint %q of VM %q: %v", name, vm.GetUID(), err)
+	}
+
+	return readCheckpoint(vm, name)
+}
+
+// ListCheckpoints returns the checkpoints of the VM, oldest first
+func ListCheckpoints(vm *api.VM) ([]Checkpoint, error) {
+	entries, err := ioutil.ReadDir(path.Join(vm.ObjectPath(), constants.CHECKPOINT_DIR))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]Checkpoint, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), checkpointSuffix) {
+			continue // Skip checkpoints that are still being written
+		}
+
+		checkpoints = append(checkpoints, newCheckpoint(strings.TrimSuffix(entry.Name(), checkpointSuffix), entry))
+	}
+
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Created.Before(checkpoints[j].Created)
+	})
+
+	return checkpoints, nil
+}
+
+// Rollback reverts the disk of the stopped VM to the given checkpoint. Only the
+// blocks that changed since the checkpoint was taken are written. The checkpoint
+// is kept, so the VM can be rolled back to it again.
+func Rollback(vm *api.VM, name string) error {
+	if vm.Running() {
+		return fmt.Errorf("VM %q is running, stop it before rolling it back", vm.GetUID())
+	}
+
+	if err := validateCheckpointName(name); err != nil {
+		return err
+	}
+
+	f, err := os.Open(checkpointFile(vm, name))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("VM %q has no checkpoint %q", vm.GetUID(), name)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := discardSavedState(vm); err != nil {
+		return err
+	}
+
+	return restoreDisk(vm, f, fi.Size())
+}
+
+// RemoveCheckpoint removes the given checkpoint of the VM
+func RemoveCheckpoint(vm *api.VM, name string) error {
+	if err := validateCheckpointName(name); err != nil {
+		return err
+	}
+
+	if err := os.Remove(checkpointFile(vm, name)); os.IsNotExist(err) {
+		return fmt.Errorf("VM %q has no checkpoint %q", vm.GetUID(), name)
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeSparse copies size bytes from r to the given file, leaving holes for the
+// chunks only containing zeroes. The file only appears once it has been written.
+func writeSparse(file string, r io.Reader, size int64) (err error) {
+	tmpFile := file + ".tmp"
+	f, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmpFile)
+		}
+	}()
+
+	chunk := make([]byte, chunkSize)
+	zeroes := make([]byte, chunkSize)
+
+	for offset := int64(0); offset < size; {
+		n, err := io.ReadFull(r, chunk)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+
+		if !bytes.Equal(chunk[:n], zeroes[:n]) {
+			if _, err := f.WriteAt(chunk[:n], offset); err != nil {
+				return err
+			}
+		}
+
+		offset += int64(n)
+	}
+
+	// Extend the file over the trailing holes
+	if err = f.Truncate(size); err != nil {
+		return
+	}
+
+	if err = f.Sync(); err != nil {
+		return
+	}
+
+	if err = f.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(tmpFile, file)
+}
+
+func readCheckpoint(vm *api.VM, name string) (*Checkpoint, error) {
+	fi, err := os.Stat(checkpointFile(vm, name))
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := newCheckpoint(name, fi)
+	return &checkpoint, nil
+}
+
+func newCheckpoint(name string, fi os.FileInfo) Checkpoint {
+	checkpoint := Checkpoint{
+		Name:    name,
+		Created: fi.ModTime(),
+	}
+
+	// The file is sparse, its allocation is reported in 512 byte blocks
+	if stat, ok := fi.Sys().(*syscall.Stat_t); ok {
+		checkpoint.Usage = meta.NewSizeFromBytes(uint64(stat.Blocks) * 512)
+	}
+
+	return checkpoint
+}
+
+// validateCheckpointName makes sure the name can't escape the checkpoint directory
+func validateCheckpointName(name string) error {
+	if !checkpointNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid checkpoint name %q, it may only contain alphanumerics, '_', '.' and '-'", name)
+	}
+
+	return nil
+}
+
+// checkpointFile returns the path of the image of the given checkpoint of the VM
+func checkpointFile(vm *api.VM, name string) string {
+	return path.Join(vm.ObjectPath(), constants.CHECKPOINT_DIR, name+checkpointSuffix)
+}
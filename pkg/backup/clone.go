@@ -0,0 +1,22 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+// CopyDisk copies the contents of the disk of the stopped source VM to the disk of the
+// given VM, which needs to be at least as large. Only the blocks differing from the disk
+// of the given VM are written, so a VM freshly created from the same image keeps sharing
+// the blocks the source VM didn't change with the image.
+func CopyDisk(source, vm *api.VM) error {
+	if source.Running() {
+		return fmt.Errorf("VM %q is running, stop it before copying its disk", source.GetUID())
+	}
+
+	return withDisk(source, os.O_RDONLY, func(disk *os.File, size int64) error {
+		return restoreDisk(vm, disk, size)
+	})
+}
@@ -0,0 +1,193 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// s3Scheme prefixes destinations stored in S3
+const s3Scheme = "s3://"
+
+// destination stores the backups of a single VM
+type destination interface {
+	// store writes a new backup with the given name
+	store(name string, write func(w io.Writer) error) error
+	// open reads the backup with the given name
+	open(name string) (io.ReadCloser, error)
+	// list returns the names of all stored backups
+	list() ([]string, error)
+	// remove deletes the backup with the given name
+	remove(name string) error
+}
+
+// newDestination returns the destination for the backups of the VM,
+// which is a subdirectory named by the UID of the VM in the configured destination
+func newDestination(vm *api.VM, dest string) destination {
+	if strings.HasPrefix(dest, s3Scheme) {
+		return &s3Destination{prefix: strings.TrimSuffix(dest, "/") + "/" + vm.GetUID().String() + "/"}
+	}
+
+	return &localDestination{dir: path.Join(dest, vm.GetUID().String())}
+}
+
+// localDestination stores backups in a directory on the host
+type localDestination struct {
+	dir string
+}
+
+var _ destination = &localDestination{}
+
+func (d *localDestination) store(name string, write func(w io.Writer) error) (err error) {
+	if err = os.MkdirAll(d.dir, constants.DATA_DIR_PERM); err != nil {
+		return
+	}
+
+	// Write to a temporary file first, so that failed backups are never listed
+	f, err := ioutil.TempFile(d.dir, "."+name)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if err = write(f); err != nil {
+		_ = f.Close()
+		return
+	}
+
+	if err = f.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(f.Name(), path.Join(d.dir, name))
+}
+
+func (d *localDestination) open(name string) (io.ReadCloser, error) {
+	return os.Open(path.Join(d.dir, name))
+}
+
+func (d *localDestination) list() ([]string, error) {
+	entries, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // No backups have been made yet
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Mode().IsRegular() && isBackupName(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+func (d *localDestination) remove(name string) error {
+	return os.Remove(path.Join(d.dir, name))
+}
+
+// s3Destination stores backups in an S3 bucket using the AWS CLI,
+// which picks up the credentials and region from its usual configuration
+type s3Destination struct {
+	prefix string
+}
+
+var _ destination = &s3Destination{}
+
+func (d *s3Destination) store(name string, write func(w io.Writer) error) error {
+	// Stream the backup to S3, the AWS CLI reads the object from stdin with "-"
+	cmd := exec.Command("aws", "s3", "cp", "-", d.prefix+name)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if err := write(stdin); err != nil {
+		// Kill the upload before it sees the end of the input, so no partial backup is stored
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	_ = stdin.Close()
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("command %q exited with %q: %w", cmd.Args, stderr.String(), err)
+	}
+
+	return nil
+}
+
+func (d *s3Destination) open(name string) (io.ReadCloser, error) {
+	cmd := exec.Command("aws", "s3", "cp", d.prefix+name, "-")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &commandReader{ReadCloser: stdout, cmd: cmd}, nil
+}
+
+func (d *s3Destination) list() ([]string, error) {
+	cmd := exec.Command("aws", "s3", "ls", d.prefix)
+	b, err := cmd.CombinedOutput()
+	out := string(b)
+	if err != nil {
+		// The AWS CLI fails without output if nothing has been stored under the prefix yet
+		if len(strings.TrimSpace(out)) == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("command %q exited with %q: %v", cmd.Args, out, err)
+	}
+
+	// Every object is listed as "<date> <time> <size> <name>"
+	var names []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 4 && isBackupName(fields[3]) {
+			names = append(names, fields[3])
+		}
+	}
+
+	return names, nil
+}
+
+func (d *s3Destination) remove(name string) error {
+	_, err := util.ExecuteCommand("aws", "s3", "rm", d.prefix+name)
+	return err
+}
+
+// commandReader reads the output of a command, and waits for it to exit when closed
+type commandReader struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (r *commandReader) Close() error {
+	_ = r.ReadCloser.Close()
+	return r.cmd.Wait()
+}
@@ -0,0 +1,147 @@
+package backup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shorthands maps the predefined cron schedules to their expressions
+var shorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// scheduleField describes the valid range of a field of a cron expression
+type scheduleField struct {
+	name     string
+	min, max int
+}
+
+var scheduleFields = [...]scheduleField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // Both 0 and 7 are Sunday
+}
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	minute, hour, dom, month, dow map[int]bool
+	// domAny and dowAny record if the day fields are unrestricted,
+	// if both are restricted, a time matches if either of them matches
+	domAny, dowAny bool
+}
+
+// ParseSchedule parses a cron expression in the "minute hour day-of-month month
+// day-of-week" format. Every field is a comma-separated list of values, ranges
+// (a-b) and wildcards (*), which can be stepped (*/15). The @yearly, @monthly,
+// @weekly, @daily and @hourly shorthands are supported as well.
+func ParseSchedule(expr string) (*Schedule, error) {
+	if shorthand, ok := shorthands[strings.TrimSpace(expr)]; ok {
+		expr = shorthand
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(scheduleFields) {
+		return nil, fmt.Errorf("invalid schedule %q, expected %d fields (minute hour day-of-month month day-of-week)", expr, len(scheduleFields))
+	}
+
+	sets := make([]map[int]bool, len(parts))
+	for i, part := range parts {
+		set, err := parseField(part, scheduleFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", expr, err)
+		}
+		sets[i] = set
+	}
+
+	// Sunday may be given as 7 as well
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &Schedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: strings.HasPrefix(parts[2], "*"),
+		dowAny: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// Matches reports whether the schedule fires in the minute of the given time
+func (s *Schedule) Matches(t time.Time) bool {
+	if !s.minute[t.Minute()] || !s.hour[t.Hour()] || !s.month[int(t.Month())] {
+		return false
+	}
+
+	domMatch, dowMatch := s.dom[t.Day()], s.dow[int(t.Weekday())]
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// parseField parses a single field of a cron expression into the set of values it matches
+func parseField(expr string, field scheduleField) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step %q in %s field", item[i+1:], field.name)
+			}
+			rangeExpr = item[:i]
+		}
+
+		start, end := field.min, field.max
+		if rangeExpr != "*" {
+			var err error
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			if start, err = parseValue(bounds[0], field); err != nil {
+				return nil, err
+			}
+
+			end = start
+			if len(bounds) == 2 {
+				if end, err = parseValue(bounds[1], field); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				end = field.max // "5/15" means from 5 onwards
+			}
+
+			if end < start {
+				return nil, fmt.Errorf("invalid range %q in %s field", rangeExpr, field.name)
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
+
+// parseValue parses a single value of a field and verifies it's within the valid range
+func parseValue(expr string, field scheduleField) (int, error) {
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < field.min || v > field.max {
+		return 0, fmt.Errorf("invalid value %q in %s field, expected %d-%d", expr, field.name, field.min, field.max)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,72 @@
+package backup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	utests := []struct {
+		name  string
+		expr  string
+		valid bool
+	}{
+		{"Wildcards", "* * * * *", true},
+		{"Values", "30 2 1 6 0", true},
+		{"Lists and ranges", "0,30 9-17 * * 1-5", true},
+		{"Steps", "*/15 0-12/2 * * *", true},
+		{"Sunday as 7", "0 0 * * 7", true},
+		{"Shorthand", "@daily", true},
+		{"Too few fields", "* * * *", false},
+		{"Out of range", "60 * * * *", false},
+		{"Inverted range", "* 17-9 * * *", false},
+		{"Zero step", "*/0 * * * *", false},
+		{"Garbage", "a b c d e", false},
+	}
+
+	for _, rt := range utests {
+		t.Run(rt.name, func(t *testing.T) {
+			_, err := ParseSchedule(rt.expr)
+			if (err == nil) != rt.valid {
+				t.Errorf("ParseSchedule(%q): expected valid=%t, got error %v", rt.expr, rt.valid, err)
+			}
+		})
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	// 2021-03-01 is a Monday
+	monday := time.Date(2021, time.March, 1, 2, 30, 0, 0, time.UTC)
+
+	utests := []struct {
+		name    string
+		expr    string
+		time    time.Time
+		matches bool
+	}{
+		{"Every minute", "* * * * *", monday, true},
+		{"Exact time", "30 2 * * *", monday, true},
+		{"Other minute", "31 2 * * *", monday, false},
+		{"Step", "*/15 * * * *", monday, true},
+		{"Step miss", "*/20 * * * *", monday, false},
+		{"Weekday range", "30 2 * * 1-5", monday, true},
+		{"Weekend", "30 2 * * 0,6", monday, false},
+		{"Day of month or week", "30 2 15 * 1", monday, true},
+		{"Day of month and any week day", "30 2 15 * *", monday, false},
+		{"Hourly", "@hourly", monday, false},
+		{"Hourly match", "@hourly", monday.Add(30 * time.Minute), true},
+	}
+
+	for _, rt := range utests {
+		t.Run(rt.name, func(t *testing.T) {
+			s, err := ParseSchedule(rt.expr)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) failed: %v", rt.expr, err)
+			}
+
+			if actual := s.Matches(rt.time); actual != rt.matches {
+				t.Errorf("%q matching %v: expected %t, got %t", rt.expr, rt.time, rt.matches, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package backup
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// Scheduler runs the backup policies of the VMs
+type Scheduler struct {
+	// running tracks the VMs currently being backed up, a backup
+	// that takes longer than the schedule interval is not started twice
+	running map[runtime.UID]bool
+	mu      sync.Mutex
+}
+
+// NewScheduler creates a new backup scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{running: make(map[runtime.UID]bool)}
+}
+
+// Run checks the backup schedules of all VMs at the start of every minute,
+// and backs up the VMs whose schedule matches. It never returns.
+func (s *Scheduler) Run() {
+	for {
+		now := time.Now()
+		time.Sleep(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		s.runScheduled(time.Now())
+	}
+}
+
+// runScheduled starts the backups of the VMs scheduled for the minute of the given time
+func (s *Scheduler) runScheduled(t time.Time) {
+	vms, err := providers.Client.VMs().FindAll(filter.NewAllFilter())
+	if err != nil {
+		log.Errorf("Failed to list VMs for backup: %v", err)
+		return
+	}
+
+	for _, vm := range vms {
+		if vm.Spec.Backup == nil {
+			continue
+		}
+
+		schedule, err := ParseSchedule(vm.Spec.Backup.Schedule)
+		if err != nil {
+			log.Warnf("Skipping backup of VM %q: %v", vm.GetUID(), err)
+			continue
+		}
+
+		if schedule.Matches(t) {
+			go s.backup(vm)
+		}
+	}
+}
+
+// backup backs up the VM and prunes its old backups
+func (s *Scheduler) backup(vm *api.VM) {
+	s.mu.Lock()
+	if s.running[vm.GetUID()] {
+		s.mu.Unlock()
+		log.Warnf("Skipping backup of VM %q, the previous backup is still running", vm.GetUID())
+		return
+	}
+	s.running[vm.GetUID()] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, vm.GetUID())
+		s.mu.Unlock()
+	}()
+
+	log.Infof("Backing up VM %q with name %q...", vm.GetUID(), vm.GetName())
+	b, err := Create(vm)
+	if err != nil {
+		log.Errorf("Backup of VM %q failed: %v", vm.GetUID(), err)
+		return
+	}
+	log.Infof("Created backup %q of VM %q", b.Name, vm.GetUID())
+
+	if err := Prune(vm); err != nil {
+		log.Errorf("Failed to remove old backups of VM %q: %v", vm.GetUID(), err)
+	}
+}
@@ -0,0 +1,268 @@
+// Package boltstorage stores the metadata of the VMs, images, kernels and VM templates in
+// a bbolt database in the data directory, instead of a metadata file per object. It's a
+// RawStorage, so it's used behind the same storage, cache and client as the metadata files.
+//
+// The database is opened for every operation and closed right after, so ignite and ignited
+// can use it at the same time: reads share its lock, writes take it exclusively.
+//
+// VMs keep their metadata file, see storagebackend.HasFile. Writes of a VM go to both the
+// database and the file, and changes of the file are written to the database when the VM
+// is read.
+package boltstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/storagebackend"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage"
+	bolt "go.etcd.io/bbolt"
+)
+
+// lockTimeout is how long to wait for another process writing to the database
+const lockTimeout = 30 * time.Second
+
+// RawStorage stores the objects in the database in the data directory
+type RawStorage struct {
+	dir    string
+	dbPath string
+}
+
+var _ storage.RawStorage = &RawStorage{}
+
+// NewRawStorage returns a RawStorage for the database in the data directory dir
+func NewRawStorage(dir string) *RawStorage {
+	return &RawStorage{
+		dir:    dir,
+		dbPath: path.Join(dir, constants.METADATA_DB),
+	}
+}
+
+// Enabled returns whether the metadata of the data directory dir is stored in the database
+func Enabled(dir string) bool {
+	return util.FileExists(path.Join(dir, constants.METADATA_DB))
+}
+
+// Backend stores the metadata in the database of the data directory
+var Backend storagebackend.Backend = backend{}
+
+type backend struct{}
+
+func (backend) Name() string {
+	return "bolt"
+}
+
+func (backend) Enabled(dir string) bool {
+	return Enabled(dir)
+}
+
+func (backend) Location(dir string) string {
+	return path.Join(dir, constants.METADATA_DB)
+}
+
+func (backend) NewRawStorage(dir string) (storage.RawStorage, error) {
+	return NewRawStorage(dir), nil
+}
+
+func (backend) Import(dir string) (int, error) {
+	return Import(dir)
+}
+
+func (backend) Export(dir string) (int, error) {
+	return Export(dir)
+}
+
+func (r *RawStorage) Read(key storage.Key) ([]byte, error) {
+	var rec *storagebackend.Record
+	if err := r.view(func(tx *bolt.Tx) (err error) {
+		rec, err = get(tx, key)
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	if rec == nil {
+		return nil, storagebackend.NotExist(r.dbPath, key)
+	}
+
+	content, modTime, changed, err := storagebackend.ReadChangedFile(r.dir, key, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		// ignite-spawn updated the VM, store its changes
+		if err := r.update(func(tx *bolt.Tx) error {
+			return put(tx, key, content, modTime)
+		}); err != nil {
+			return nil, err
+		}
+
+		return content, nil
+	}
+
+	return rec.Object, nil
+}
+
+func (r *RawStorage) Exists(key storage.Key) bool {
+	var exists bool
+	_ = r.view(func(tx *bolt.Tx) error {
+		if b := tx.Bucket(bucketName(key.KindKey)); b != nil {
+			exists = b.Get([]byte(key.UID)) != nil
+		}
+		return nil
+	})
+
+	return exists
+}
+
+func (r *RawStorage) Write(key storage.Key, content []byte) error {
+	if err := storagebackend.WriteFile(r.dir, key, content); err != nil {
+		return err
+	}
+
+	modTime, err := storagebackend.FileModTime(r.dir, key)
+	if err != nil {
+		return err
+	}
+
+	return r.update(func(tx *bolt.Tx) error {
+		return put(tx, key, content, modTime)
+	})
+}
+
+// Delete deletes the object, and its directory like the metadata files do
+func (r *RawStorage) Delete(key storage.Key) error {
+	if err := r.update(func(tx *bolt.Tx) error {
+		if b := tx.Bucket(bucketName(key.KindKey)); b != nil {
+			return b.Delete([]byte(key.UID))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(storagebackend.ObjectDir(r.dir, key))
+}
+
+func (r *RawStorage) List(kindKey storage.KindKey) ([]storage.Key, error) {
+	var keys []storage.Key
+	err := r.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName(kindKey))
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(uid, _ []byte) error {
+			keys = append(keys, storage.NewKey(kindKey.Kind, runtime.UID(uid)))
+			return nil
+		})
+	})
+
+	return keys, err
+}
+
+// Checksum returns the revision of the object, and the modification time of the metadata
+// file of a VM if it changed since it was written to the database. If the object doesn't
+// exist, it returns an empty string.
+func (r *RawStorage) Checksum(key storage.Key) (string, error) {
+	var rec *storagebackend.Record
+	if err := r.view(func(tx *bolt.Tx) (err error) {
+		rec, err = get(tx, key)
+		return
+	}); err != nil || rec == nil {
+		return "", err
+	}
+
+	checksum := strconv.FormatUint(rec.Revision, 10)
+	if modTime := storagebackend.FileChanged(r.dir, key, rec); modTime != 0 {
+		checksum += "-" + strconv.FormatInt(modTime, 10)
+	}
+
+	return checksum, nil
+}
+
+func (r *RawStorage) Format(key storage.Key) storage.Format {
+	return storage.FormatJSON
+}
+
+func (r *RawStorage) WatchDir() string {
+	return r.dir
+}
+
+func (r *RawStorage) GetKey(p string) (storage.Key, error) {
+	return storage.NewGenericRawStorage(r.dir).GetKey(p)
+}
+
+func (r *RawStorage) view(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(r.dbPath, 0600, &bolt.Options{Timeout: lockTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to open the metadata database %q: %v", r.dbPath, err)
+	}
+	defer db.Close()
+
+	return db.View(fn)
+}
+
+func (r *RawStorage) update(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(r.dbPath, 0600, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open the metadata database %q: %v", r.dbPath, err)
+	}
+	defer db.Close()
+
+	return db.Update(fn)
+}
+
+func bucketName(kindKey storage.KindKey) []byte {
+	return []byte(kindKey.String())
+}
+
+func get(tx *bolt.Tx, key storage.Key) (*storagebackend.Record, error) {
+	b := tx.Bucket(bucketName(key.KindKey))
+	if b == nil {
+		return nil, nil
+	}
+
+	value := b.Get([]byte(key.UID))
+	if value == nil {
+		return nil, nil
+	}
+
+	rec := &storagebackend.Record{}
+	if err := json.Unmarshal(value, rec); err != nil {
+		return nil, fmt.Errorf("failed to decode %s from the metadata database: %v", key, err)
+	}
+
+	return rec, nil
+}
+
+func put(tx *bolt.Tx, key storage.Key, content []byte, modTime int64) error {
+	b, err := tx.CreateBucketIfNotExists(bucketName(key.KindKey))
+	if err != nil {
+		return err
+	}
+
+	revision, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(&storagebackend.Record{
+		Revision: revision,
+		ModTime:  modTime,
+		Object:   content,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s for the metadata database: %v", key, err)
+	}
+
+	return b.Put([]byte(key.UID), value)
+}
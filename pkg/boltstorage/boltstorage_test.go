@@ -0,0 +1,147 @@
+package boltstorage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/weaveworks/libgitops/pkg/storage"
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+var (
+	vmKey    = storage.NewKey(api.KindVM, "1699b6ba255cde7f")
+	imageKey = storage.NewKey(api.KindImage, "ad5b7e8d1ef2d5a4")
+)
+
+func newTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ignite-boltstorage-test")
+	assert.NilError(t, err)
+	return dir
+}
+
+// manifest returns the JSON of an object, the status of VMs tells whether they run
+func manifest(key storage.Key, name string, running bool) []byte {
+	status := "{}"
+	if key.Kind == api.KindVM {
+		status = fmt.Sprintf(`{"running":%t}`, running)
+	}
+
+	return []byte(fmt.Sprintf(`{"kind":%q,"apiVersion":"ignite.weave.works/v1alpha5","metadata":{"name":%q,"uid":%q},"spec":{},"status":%s}`,
+		key.Kind.Title(), name, key.UID, status))
+}
+
+func getName(t *testing.T, raw storage.RawStorage, key storage.Key) string {
+	gvk := api.SchemeGroupVersion.WithKind(key.Kind.Title())
+	obj, err := storage.NewGenericStorage(raw, scheme.Serializer).Get(gvk, key.UID)
+	assert.NilError(t, err)
+	return obj.GetName()
+}
+
+func TestRawStorage(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	_, err := Import(dir)
+	assert.NilError(t, err)
+	assert.Assert(t, Enabled(dir))
+
+	r := NewRawStorage(dir)
+	assert.NilError(t, r.Write(vmKey, manifest(vmKey, "my-vm", false)))
+	assert.NilError(t, r.Write(imageKey, manifest(imageKey, "ubuntu", false)))
+
+	// Only VMs keep their metadata file, the directories are created for the files of the objects
+	vmFile := path.Join(dir, vmKey.String(), constants.METADATA)
+	assert.Assert(t, util.FileExists(vmFile))
+	assert.Assert(t, util.DirExists(path.Join(dir, imageKey.String())))
+	assert.Assert(t, !util.FileExists(path.Join(dir, imageKey.String(), constants.METADATA)))
+
+	keys, err := r.List(imageKey.KindKey)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, keys, []storage.Key{imageKey})
+	assert.Assert(t, r.Exists(vmKey))
+	assert.Assert(t, !r.Exists(storage.NewKey(api.KindVM, "0000000000000000")))
+	assert.Equal(t, getName(t, r, vmKey), "my-vm")
+
+	checksum, err := r.Checksum(vmKey)
+	assert.NilError(t, err)
+
+	// ignite-spawn updates the metadata file of the VM
+	time.Sleep(10 * time.Millisecond)
+	running := manifest(vmKey, "my-vm", true)
+	assert.NilError(t, ioutil.WriteFile(vmFile, running, 0644))
+
+	changed, err := r.Checksum(vmKey)
+	assert.NilError(t, err)
+	assert.Assert(t, changed != checksum)
+
+	content, err := r.Read(vmKey)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), string(running))
+
+	// A partially written file is ignored
+	assert.NilError(t, ioutil.WriteFile(vmFile, running[:10], 0644))
+	content, err = r.Read(vmKey)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), string(running))
+
+	assert.NilError(t, r.Delete(vmKey))
+	assert.Assert(t, !util.DirExists(path.Join(dir, vmKey.String())))
+	_, err = r.Read(vmKey)
+	assert.Assert(t, os.IsNotExist(err))
+
+	checksum, err = r.Checksum(vmKey)
+	assert.NilError(t, err)
+	assert.Equal(t, checksum, "")
+}
+
+func TestImportExport(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	files := storage.NewGenericRawStorage(dir)
+	assert.NilError(t, files.Write(vmKey, manifest(vmKey, "my-vm", true)))
+	assert.NilError(t, files.Write(imageKey, manifest(imageKey, "ubuntu", false)))
+
+	// A directory without metadata isn't imported
+	assert.NilError(t, os.MkdirAll(path.Join(dir, "kernel", "b5b0c3ab3b31e9a2"), 0755))
+
+	count, err := Import(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, count, 2)
+
+	_, err = Import(dir)
+	assert.ErrorContains(t, err, "already stored in a database")
+
+	assert.Assert(t, Backend.Enabled(dir))
+	r, err := Backend.NewRawStorage(dir)
+	assert.NilError(t, err)
+	_, ok := r.(*RawStorage)
+	assert.Assert(t, ok)
+	assert.Equal(t, getName(t, r, vmKey), "my-vm")
+	assert.Equal(t, getName(t, r, imageKey), "ubuntu")
+
+	kernels, err := r.List(storage.NewKindKey(api.KindKernel))
+	assert.NilError(t, err)
+	assert.Equal(t, len(kernels), 0)
+
+	// The image is renamed in the database, and written back to its metadata file
+	assert.NilError(t, r.Write(imageKey, manifest(imageKey, "ubuntu-20.04", false)))
+
+	count, err = Export(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, count, 2)
+	assert.Assert(t, !Enabled(dir))
+	assert.Equal(t, getName(t, files, imageKey), "ubuntu-20.04")
+
+	_, err = Export(dir)
+	assert.ErrorContains(t, err, "isn't stored in a database")
+}
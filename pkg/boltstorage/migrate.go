@@ -0,0 +1,57 @@
+package boltstorage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/weaveworks/ignite/pkg/storagebackend"
+	"github.com/weaveworks/libgitops/pkg/storage"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Import copies the objects of the metadata files of the data directory dir to a new
+// database, which is enabled once all of them are copied. The metadata files are kept,
+// the objects of the VMs, images and kernels aren't changed. It returns how many objects
+// were copied.
+func Import(dir string) (int, error) {
+	if Enabled(dir) {
+		return 0, fmt.Errorf("the metadata of %q is already stored in a database", dir)
+	}
+
+	r := NewRawStorage(dir)
+	tmp := &RawStorage{dir: dir, dbPath: r.dbPath + ".tmp"}
+	if err := os.Remove(tmp.dbPath); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	count := 0
+	err := tmp.update(func(tx *bolt.Tx) error {
+		return storagebackend.ReadFiles(dir, func(key storage.Key, content []byte, modTime int64) error {
+			count++
+			return put(tx, key, content, modTime)
+		})
+	})
+	if err != nil {
+		os.Remove(tmp.dbPath)
+		return 0, err
+	}
+
+	return count, os.Rename(tmp.dbPath, r.dbPath)
+}
+
+// Export writes the objects of the database of the data directory dir to metadata files,
+// and removes the database, so the metadata files are used again. It returns how many
+// objects were written.
+func Export(dir string) (int, error) {
+	if !Enabled(dir) {
+		return 0, fmt.Errorf("the metadata of %q isn't stored in a database", dir)
+	}
+
+	r := NewRawStorage(dir)
+	count, err := storagebackend.WriteFiles(r, dir)
+	if err != nil {
+		return count, err
+	}
+
+	return count, os.Remove(r.dbPath)
+}
@@ -0,0 +1,224 @@
+// Package bootcache keeps Firecracker snapshots of freshly booted VMs. The first VM of a
+// given image, kernel and size is snapshotted once its guest agent answers, and the next
+// VMs of the same kind are restored from the snapshot instead of booting. Every entry of
+// the cache is a directory named by its key, holding the snapshot, a copy of the disk
+// overlay of the snapshotted VM and a manifest describing it.
+package bootcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+const (
+	// ManifestFile is the manifest of the entry, it's written last
+	ManifestFile = "manifest.json"
+	// StateFile holds the device state of the Firecracker snapshot
+	StateFile = "vmstate"
+	// MemoryFile holds the guest memory of the Firecracker snapshot
+	MemoryFile = "memory"
+	// OverlayFile is the copy of the disk overlay the snapshotted VM had
+	OverlayFile = "overlay"
+)
+
+// Manifest describes the VM a boot cache entry was snapshotted from
+type Manifest struct {
+	Key    string      `json:"key"`
+	Image  runtime.UID `json:"image"`
+	Kernel runtime.UID `json:"kernel"`
+	CPUs   uint64      `json:"cpus"`
+	Memory meta.Size   `json:"memory"`
+	// DiskSize is the size of the disk of the VMs
+	DiskSize meta.Size `json:"diskSize"`
+	// DrivePath is the path of the root drive recorded in the snapshot
+	DrivePath string `json:"drivePath,omitempty"`
+	// MACAddress is the address of the network interface of the snapshotted guest, the
+	// restored VMs keep it
+	MACAddress string `json:"macAddress,omitempty"`
+	// Unsupported is set to the reason the VMs of the key can't be snapshotted, e.g. because
+	// their image has no guest agent. They boot normally, and aren't tried again.
+	Unsupported string       `json:"unsupported,omitempty"`
+	Created     runtime.Time `json:"created"`
+}
+
+// key holds everything a restored VM has in common with the snapshotted one
+type key struct {
+	Image         runtime.UID       `json:"image"`
+	Kernel        runtime.UID       `json:"kernel"`
+	Sandbox       meta.OCIImageRef  `json:"sandbox"`
+	CPUs          uint64            `json:"cpus"`
+	Memory        meta.Size         `json:"memory"`
+	DiskSize      meta.Size         `json:"diskSize"`
+	CmdLine       string            `json:"cmdLine"`
+	Initrd        string            `json:"initrd,omitempty"`
+	CmdLineVars   map[string]string `json:"cmdLineVars,omitempty"`
+	Sysctls       map[string]string `json:"sysctls,omitempty"`
+	NetworkPlugin string            `json:"networkPlugin"`
+	SSH           bool              `json:"ssh"`
+}
+
+// Key returns the key of the boot cache entry of the VM, with the given image and kernel
+func Key(vm *api.VM, imageUID, kernelUID runtime.UID) string {
+	b, _ := json.Marshal(&key{
+		Image:         imageUID,
+		Kernel:        kernelUID,
+		Sandbox:       vm.Spec.Sandbox.OCI,
+		CPUs:          vm.Spec.CPUs,
+		Memory:        vm.Spec.Memory,
+		DiskSize:      vm.Spec.DiskSize,
+		CmdLine:       vm.Spec.Kernel.CmdLine,
+		Initrd:        initrdRef(vm),
+		CmdLineVars:   vm.KernelCmdLineVars(),
+		Sysctls:       vm.Spec.Sysctls,
+		NetworkPlugin: providers.NetworkPluginName.String(),
+		SSH:           vm.Spec.SSH != nil,
+	})
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// initrdRef returns the initrd image of the VM, if it has its own
+func initrdRef(vm *api.VM) string {
+	if vm.Spec.Kernel.InitrdRef == nil {
+		return ""
+	}
+
+	return vm.Spec.Kernel.InitrdRef.String()
+}
+
+// Dir returns the directory of the boot cache entry
+func Dir(key string) string {
+	return path.Join(constants.BOOT_CACHE_DIR, key)
+}
+
+// File returns the path of the given file of the boot cache entry
+func File(key, name string) string {
+	return path.Join(Dir(key), name)
+}
+
+// Lookup returns the manifest of the boot cache entry, or nil if there's no entry
+func Lookup(key string) (*Manifest, error) {
+	b, err := ioutil.ReadFile(File(key, ManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(b, manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode the manifest of boot cache entry %q: %v", key, err)
+	}
+
+	return manifest, nil
+}
+
+// Store adds the entry described by the manifest to the boot cache. populate writes the
+// snapshot and overlay into the given directory, the entry is only added if it succeeds.
+// If another VM has added an entry with the same key in the meantime, it's kept.
+func Store(manifest *Manifest, populate func(dir string) error) (err error) {
+	if err = os.MkdirAll(constants.BOOT_CACHE_DIR, constants.DATA_DIR_PERM); err != nil {
+		return
+	}
+
+	// The entry is populated in a temporary directory, so an incomplete one is never restored
+	tempDir, err := ioutil.TempDir(constants.BOOT_CACHE_DIR, "."+manifest.Key)
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	if populate != nil {
+		if err = populate(tempDir); err != nil {
+			return
+		}
+	}
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return
+	}
+
+	if err = ioutil.WriteFile(path.Join(tempDir, ManifestFile), b, 0644); err != nil {
+		return
+	}
+
+	if err = os.Rename(tempDir, Dir(manifest.Key)); err != nil && util.DirExists(Dir(manifest.Key)) {
+		err = nil
+	}
+
+	return
+}
+
+// List returns the manifests of the entries of the boot cache, sorted by key
+func List() ([]*Manifest, error) {
+	entries, err := ioutil.ReadDir(constants.BOOT_CACHE_DIR)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var manifests []*Manifest
+	for _, entry := range entries {
+		// Skip the entries being populated
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		manifest, err := Lookup(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if manifest != nil {
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].Key < manifests[j].Key
+	})
+
+	return manifests, nil
+}
+
+// Remove removes the boot cache entry
+func Remove(key string) error {
+	return os.RemoveAll(Dir(key))
+}
+
+// RemoveFor removes the boot cache entries of the image or kernel with the given UID
+func RemoveFor(uid runtime.UID) error {
+	manifests, err := List()
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		if manifest.Image == uid || manifest.Kernel == uid {
+			if err := Remove(manifest.Key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
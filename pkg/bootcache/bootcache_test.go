@@ -0,0 +1,52 @@
+package bootcache
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/arch"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+func newVM(name string) *api.VM {
+	vm := &api.VM{}
+	vm.SetName(name)
+	vm.SetUID("0123456789abcdef")
+	vm.Spec.CPUs = 1
+	vm.Spec.Memory = meta.NewSizeFromBytes(512 * constants.MB)
+	vm.Spec.DiskSize = meta.NewSizeFromBytes(4 * constants.GB)
+	vm.Spec.Kernel.CmdLine = arch.Host.KernelArgs
+	return vm
+}
+
+func TestKey(t *testing.T) {
+	key := Key(newVM("a"), "image", "kernel")
+	assert.Equal(t, len(key), 16)
+
+	// The name and UID of the VM aren't part of the key
+	other := newVM("b")
+	other.SetUID("fedcba9876543210")
+	assert.Equal(t, Key(other, "image", "kernel"), key)
+
+	for name, change := range map[string]func(vm *api.VM){
+		"memory":  func(vm *api.VM) { vm.Spec.Memory = meta.NewSizeFromBytes(1 * constants.GB) },
+		"cpus":    func(vm *api.VM) { vm.Spec.CPUs = 2 },
+		"disk":    func(vm *api.VM) { vm.Spec.DiskSize = meta.NewSizeFromBytes(8 * constants.GB) },
+		"cmdline": func(vm *api.VM) { vm.Spec.Kernel.CmdLine += " quiet" },
+		"sysctls": func(vm *api.VM) { vm.Spec.Sysctls = map[string]string{"vm.swappiness": "10"} },
+		"ssh":     func(vm *api.VM) { vm.Spec.SSH = &api.SSH{Generate: true} },
+		"cmdline var": func(vm *api.VM) {
+			vm.SetAnnotation(constants.IGNITE_KERNEL_CMDLINE_VAR_ANNOTATION+"role", "worker")
+		},
+	} {
+		vm := newVM("a")
+		change(vm)
+		assert.Assert(t, Key(vm, "image", "kernel") != key, name)
+	}
+
+	assert.Assert(t, Key(newVM("a"), "other", "kernel") != key)
+	assert.Assert(t, Key(newVM("a"), "image", "other") != key)
+}
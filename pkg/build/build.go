@@ -0,0 +1,181 @@
+// Package build builds OCI images from a Dockerfile or Containerfile, and stores
+// them in the container runtime, where they can be imported as ignite images.
+package build
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/runtime"
+)
+
+// Builder defines the tool building the images
+type Builder string
+
+const (
+	// BuilderAuto builds with docker when it's the container runtime, and with BuildKit otherwise
+	BuilderAuto Builder = "auto"
+	// BuilderDocker builds with "docker build", the image is stored by docker
+	BuilderDocker Builder = "docker"
+	// BuilderBuildKit builds with "buildctl build" against a BuildKit daemon, the
+	// resulting image archive is loaded into the container runtime
+	BuilderBuildKit Builder = "buildkit"
+)
+
+// ListBuilders gets the list of available builders
+func ListBuilders() []Builder {
+	return []Builder{
+		BuilderAuto,
+		BuilderDocker,
+		BuilderBuildKit,
+	}
+}
+
+// Options configures a build
+type Options struct {
+	// ContextDir is the directory containing the build context
+	ContextDir string
+	// File is the path to the Dockerfile, by default the Dockerfile or Containerfile in ContextDir
+	File string
+	// Image is the name the built image is stored under
+	Image meta.OCIImageRef
+	// BuildArgs set the build-time variables, in the KEY=VALUE format
+	BuildArgs []string
+	// Target is the build stage to build, the last stage if empty
+	Target string
+	// NoCache disables the build cache
+	NoCache bool
+	// Builder selects the tool building the image
+	Builder Builder
+	// BuildKitAddr is the address of the BuildKit daemon, buildctl's default if empty
+	BuildKitAddr string
+}
+
+// Build builds the image, and stores it in the container runtime set in providers.Runtime.
+// The build output is written to stderr.
+func Build(opts *Options) error {
+	file, err := buildFile(opts)
+	if err != nil {
+		return err
+	}
+
+	builder := opts.Builder
+	if builder == BuilderAuto || len(builder) == 0 {
+		builder = BuilderBuildKit
+		if providers.Runtime.Name() == runtime.RuntimeDocker {
+			builder = BuilderDocker
+		}
+	}
+
+	log.Infof("Building image %q from %q with %s...", opts.Image, file, builder)
+	switch builder {
+	case BuilderDocker:
+		return buildDocker(opts, file)
+	case BuilderBuildKit:
+		return buildBuildKit(opts, file)
+	}
+
+	return fmt.Errorf("unknown builder %q, supported builders are %v", opts.Builder, ListBuilders())
+}
+
+// buildFile returns the path to the Dockerfile of the build
+func buildFile(opts *Options) (string, error) {
+	if len(opts.File) > 0 {
+		if _, err := os.Stat(opts.File); err != nil {
+			return "", err
+		}
+
+		return opts.File, nil
+	}
+
+	for _, name := range []string{"Dockerfile", "Containerfile"} {
+		file := filepath.Join(opts.ContextDir, name)
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		}
+	}
+
+	return "", fmt.Errorf("no Dockerfile or Containerfile found in %q, specify the file to build", opts.ContextDir)
+}
+
+// buildDocker builds the image with the docker CLI, which stores it in the docker daemon
+func buildDocker(opts *Options, file string) error {
+	if providers.Runtime.Name() != runtime.RuntimeDocker {
+		return fmt.Errorf("the docker builder requires the docker runtime, use the buildkit builder with %s", providers.Runtime.Name())
+	}
+
+	args := []string{"build", "--tag", opts.Image.String(), "--file", file}
+	for _, arg := range opts.BuildArgs {
+		args = append(args, "--build-arg", arg)
+	}
+	if len(opts.Target) > 0 {
+		args = append(args, "--target", opts.Target)
+	}
+	if opts.NoCache {
+		args = append(args, "--no-cache")
+	}
+
+	cmd := exec.Command("docker", append(args, opts.ContextDir)...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("command %q failed: %v", cmd.Args, err)
+	}
+
+	return nil
+}
+
+// buildBuildKit builds the image with buildctl, and loads the docker-archive it outputs into the runtime
+func buildBuildKit(opts *Options, file string) error {
+	var args []string
+	if len(opts.BuildKitAddr) > 0 {
+		args = append(args, "--addr", opts.BuildKitAddr)
+	}
+
+	args = append(args, "build",
+		"--frontend", "dockerfile.v0",
+		"--local", "context="+opts.ContextDir,
+		"--local", "dockerfile="+filepath.Dir(file),
+		"--opt", "filename="+filepath.Base(file),
+		"--output", "type=docker,name="+opts.Image.Normalized(),
+	)
+	for _, arg := range opts.BuildArgs {
+		args = append(args, "--opt", "build-arg:"+arg)
+	}
+	if len(opts.Target) > 0 {
+		args = append(args, "--opt", "target="+opts.Target)
+	}
+	if opts.NoCache {
+		args = append(args, "--no-cache")
+	}
+
+	cmd := exec.Command("buildctl", args...)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if err := providers.Runtime.LoadImage(stdout); err != nil {
+		// Stop the build if the runtime can't take its output
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("command %q failed: %v", cmd.Args, err)
+	}
+
+	return nil
+}
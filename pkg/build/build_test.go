@@ -0,0 +1,40 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestBuildFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-build-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Without a Dockerfile or Containerfile the file needs to be given
+	_, err = buildFile(&Options{ContextDir: dir})
+	assert.ErrorContains(t, err, "no Dockerfile or Containerfile")
+
+	containerfile := filepath.Join(dir, "Containerfile")
+	assert.NilError(t, ioutil.WriteFile(containerfile, []byte("FROM scratch\n"), 0644))
+	file, err := buildFile(&Options{ContextDir: dir})
+	assert.NilError(t, err)
+	assert.Equal(t, file, containerfile)
+
+	// The Dockerfile takes precedence over the Containerfile
+	dockerfile := filepath.Join(dir, "Dockerfile")
+	assert.NilError(t, ioutil.WriteFile(dockerfile, []byte("FROM scratch\n"), 0644))
+	file, err = buildFile(&Options{ContextDir: dir})
+	assert.NilError(t, err)
+	assert.Equal(t, file, dockerfile)
+
+	// An explicitly given file needs to exist
+	file, err = buildFile(&Options{ContextDir: dir, File: containerfile})
+	assert.NilError(t, err)
+	assert.Equal(t, file, containerfile)
+	_, err = buildFile(&Options{ContextDir: dir, File: filepath.Join(dir, "missing")})
+	assert.Assert(t, os.IsNotExist(err))
+}
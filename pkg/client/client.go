@@ -79,8 +79,9 @@ func NewIgniteInternalClient(s storage.Storage) *IgniteInternalClient {
 type IgniteInternalClient struct {
 	storage        storage.Storage
 	gv             schema.GroupVersion
-	vmClient       VMClient
-	kernelClient   KernelClient
-	imageClient    ImageClient
-	dynamicClients map[schema.GroupVersionKind]DynamicClient
+	vmClient         VMClient
+	kernelClient     KernelClient
+	imageClient      ImageClient
+	vmtemplateClient VMTemplateClient
+	dynamicClients   map[schema.GroupVersionKind]DynamicClient
 }
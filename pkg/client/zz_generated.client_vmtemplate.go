@@ -0,0 +1,149 @@
+/*
+	Note: This file is autogenerated! Do not edit it manually!
+	Edit client_vmtemplate_template.go instead, and run
+	hack/generate-client.sh afterwards.
+*/
+
+package client
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// VMTemplateClient is an interface for accessing VMTemplate-specific API objects
+type VMTemplateClient interface {
+	// New returns a new VMTemplate
+	New() *api.VMTemplate
+	// Get returns the VMTemplate matching given UID from the storage
+	Get(runtime.UID) (*api.VMTemplate, error)
+	// Set saves the given VMTemplate into persistent storage
+	Set(*api.VMTemplate) error
+	// Patch performs a strategic merge patch on the object with
+	// the given UID, using the byte-encoded patch given
+	Patch(runtime.UID, []byte) error
+	// Find returns the VMTemplate matching the given filter, filters can
+	// match e.g. the Object's Name, UID or a specific property
+	Find(filter filterer.BaseFilter) (*api.VMTemplate, error)
+	// FindAll returns multiple VMTemplates matching the given filter, filters can
+	// match e.g. the Object's Name, UID or a specific property
+	FindAll(filter filterer.BaseFilter) ([]*api.VMTemplate, error)
+	// Delete deletes the VMTemplate with the given UID from the storage
+	Delete(uid runtime.UID) error
+	// List returns a list of all VMTemplates available
+	List() ([]*api.VMTemplate, error)
+}
+
+// VMTemplates returns the VMTemplateClient for the IgniteInternalClient instance
+func (c *IgniteInternalClient) VMTemplates() VMTemplateClient {
+	if c.vmtemplateClient == nil {
+		c.vmtemplateClient = newVMTemplateClient(c.storage, c.gv)
+	}
+
+	return c.vmtemplateClient
+}
+
+// vmtemplateClient is a struct implementing the VMTemplateClient interface
+// It uses a shared storage instance passed from the Client together with its own Filterer
+type vmtemplateClient struct {
+	storage  storage.Storage
+	filterer *filterer.Filterer
+	gvk      schema.GroupVersionKind
+}
+
+// newVMTemplateClient builds the vmtemplateClient struct using the storage implementation and a new Filterer
+func newVMTemplateClient(s storage.Storage, gv schema.GroupVersion) VMTemplateClient {
+	return &vmtemplateClient{
+		storage:  s,
+		filterer: filterer.NewFilterer(s),
+		gvk:      gv.WithKind(api.KindVMTemplate.Title()),
+	}
+}
+
+// New returns a new Object of its kind
+func (c *vmtemplateClient) New() *api.VMTemplate {
+	log.Tracef("Client.New; GVK: %v", c.gvk)
+	obj, err := c.storage.New(c.gvk)
+	if err != nil {
+		panic(fmt.Sprintf("Client.New must not return an error: %v", err))
+	}
+	return obj.(*api.VMTemplate)
+}
+
+// Find returns a single VMTemplate based on the given Filter
+func (c *vmtemplateClient) Find(filter filterer.BaseFilter) (*api.VMTemplate, error) {
+	log.Tracef("Client.Find; GVK: %v", c.gvk)
+	object, err := c.filterer.Find(c.gvk, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.(*api.VMTemplate), nil
+}
+
+// FindAll returns multiple VMTemplates based on the given Filter
+func (c *vmtemplateClient) FindAll(filter filterer.BaseFilter) ([]*api.VMTemplate, error) {
+	log.Tracef("Client.FindAll; GVK: %v", c.gvk)
+	matches, err := c.filterer.FindAll(c.gvk, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*api.VMTemplate, 0, len(matches))
+	for _, item := range matches {
+		results = append(results, item.(*api.VMTemplate))
+	}
+
+	return results, nil
+}
+
+// Get returns the VMTemplate matching given UID from the storage
+func (c *vmtemplateClient) Get(uid runtime.UID) (*api.VMTemplate, error) {
+	log.Tracef("Client.Get; UID: %q, GVK: %v", uid, c.gvk)
+	object, err := c.storage.Get(c.gvk, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.(*api.VMTemplate), nil
+}
+
+// Set saves the given VMTemplate into the persistent storage
+func (c *vmtemplateClient) Set(vmtemplate *api.VMTemplate) error {
+	log.Tracef("Client.Set; UID: %q, GVK: %v", vmtemplate.GetUID(), c.gvk)
+	return c.storage.Set(c.gvk, vmtemplate)
+}
+
+// Patch performs a strategic merge patch on the object with
+// the given UID, using the byte-encoded patch given
+func (c *vmtemplateClient) Patch(uid runtime.UID, patch []byte) error {
+	return c.storage.Patch(c.gvk, uid, patch)
+}
+
+// Delete deletes the VMTemplate from the storage
+func (c *vmtemplateClient) Delete(uid runtime.UID) error {
+	log.Tracef("Client.Delete; UID: %q, GVK: %v", uid, c.gvk)
+	return c.storage.Delete(c.gvk, uid)
+}
+
+// List returns a list of all VMTemplates available
+func (c *vmtemplateClient) List() ([]*api.VMTemplate, error) {
+	log.Tracef("Client.List; GVK: %v", c.gvk)
+	list, err := c.storage.List(c.gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*api.VMTemplate, 0, len(list))
+	for _, item := range list {
+		results = append(results, item.(*api.VMTemplate))
+	}
+
+	return results, nil
+}
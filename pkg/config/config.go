@@ -13,6 +13,7 @@ import (
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/providers/ignite"
 	"github.com/weaveworks/ignite/pkg/runtime"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
 )
 
 // ApplyConfiguration merges the given configurations with the default ignite
@@ -49,6 +50,9 @@ func ApplyConfiguration(configPath string) error {
 		if providers.ComponentConfig.Spec.IDPrefix != "" && providers.IDPrefix == "" {
 			providers.IDPrefix = providers.ComponentConfig.Spec.IDPrefix
 		}
+		if providers.ComponentConfig.Spec.Snapshotter != "" && providers.SnapshotterName == "" {
+			providers.SnapshotterName = providers.ComponentConfig.Spec.Snapshotter
+		}
 	} else {
 		log.Debugln("Using ignite default configurations")
 	}
@@ -64,6 +68,9 @@ func ApplyConfiguration(configPath string) error {
 	if providers.IDPrefix == "" {
 		providers.IDPrefix = constants.IGNITE_PREFIX
 	}
+	if providers.SnapshotterName == "" {
+		providers.SnapshotterName = snapshotter.SnapshotterDMLegacy
+	}
 
 	return nil
 }
@@ -87,6 +94,23 @@ func getConfigFromFile(configPath string) (*api.Configuration, error) {
 	return componentConfig, nil
 }
 
+// ResolveVMProviders sets the runtime and network plugin to run the VM with in its
+// status. The ones set in the VM's spec override the ones it was created or last run
+// with, the current providers are used if neither is set.
+func ResolveVMProviders(vm *api.VM) {
+	if len(vm.Spec.Runtime) > 0 {
+		vm.Status.Runtime.Name = vm.Spec.Runtime
+	} else if len(vm.Status.Runtime.Name) == 0 {
+		vm.Status.Runtime.Name = providers.RuntimeName
+	}
+
+	if len(vm.Spec.NetworkPlugin) > 0 {
+		vm.Status.Network.Plugin = vm.Spec.NetworkPlugin
+	} else if len(vm.Status.Network.Plugin) == 0 {
+		vm.Status.Network.Plugin = providers.NetworkPluginName
+	}
+}
+
 // SetAndPopulateProviders sets and populates the providers.
 func SetAndPopulateProviders(runtimeName runtime.Name, networkPlugin network.PluginName) error {
 	providers.RuntimeName = runtimeName
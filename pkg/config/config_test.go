@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/network"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/runtime"
+)
+
+func TestResolveVMProviders(t *testing.T) {
+	providers.RuntimeName = runtime.RuntimeContainerd
+	providers.NetworkPluginName = network.PluginCNI
+
+	cases := []struct {
+		name          string
+		spec          api.VMSpec
+		runtime       runtime.Name
+		networkPlugin network.PluginName
+		wantRuntime   runtime.Name
+		wantPlugin    network.PluginName
+	}{
+		{
+			name:        "never run",
+			wantRuntime: runtime.RuntimeContainerd,
+			wantPlugin:  network.PluginCNI,
+		},
+		{
+			name:          "last run",
+			runtime:       runtime.RuntimeDocker,
+			networkPlugin: network.PluginDockerBridge,
+			wantRuntime:   runtime.RuntimeDocker,
+			wantPlugin:    network.PluginDockerBridge,
+		},
+		{
+			name:          "spec overrides",
+			spec:          api.VMSpec{Runtime: runtime.RuntimeDocker, NetworkPlugin: network.PluginDockerBridge},
+			runtime:       runtime.RuntimeContainerd,
+			networkPlugin: network.PluginCNI,
+			wantRuntime:   runtime.RuntimeDocker,
+			wantPlugin:    network.PluginDockerBridge,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			vm := &api.VM{Spec: rt.spec}
+			vm.Status.Runtime = &api.Runtime{Name: rt.runtime}
+			vm.Status.Network = &api.Network{Plugin: rt.networkPlugin}
+
+			ResolveVMProviders(vm)
+			assert.Equal(t, vm.Status.Runtime.Name, rt.wantRuntime)
+			assert.Equal(t, vm.Status.Network.Plugin, rt.wantPlugin)
+		})
+	}
+}
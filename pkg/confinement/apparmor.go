@@ -0,0 +1,93 @@
+package confinement
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+const (
+	apparmorParser  = "apparmor_parser"
+	apparmorEnabled = "/sys/module/apparmor/parameters/enabled"
+	apparmorRemove  = "/sys/kernel/security/apparmor/.remove"
+)
+
+// profileTemplate is the AppArmor profile of a sandbox. It may read and run everything
+// in the container, but only write the directory and disks of its own VM. The data of
+// ignite and the dangerous bits of /proc and /sys are off limits.
+var profileTemplate = template.Must(template.New("profile").Parse(`#include <tunables/global>
+
+profile {{ .Name }} flags=(attach_disconnected,mediate_deleted) {
+  #include <abstractions/base>
+
+  capability,
+  network,
+  unix,
+  signal (receive) peer=unconfined,
+  signal peer={{ .Name }},
+  ptrace (read, readby, trace, tracedby) peer={{ .Name }},
+
+  /** rmix,
+  {{ .VMDir }}/ rw,
+  {{ .VMDir }}/** rwk,
+  /dev/** rwk,
+  /tmp/** rwk,
+  /run/** rwk,
+  /var/run/** rwk,
+  @{PROC}/** rw,
+  /sys/** r,
+
+  deny {{ .DataDir }}/{image,kernel}/** wlk,
+  deny @{PROC}/sysrq-trigger rwklx,
+  deny @{PROC}/kcore rwklx,
+  deny /sys/firmware/** rwklx,
+  deny /sys/kernel/security/** rwklx,
+  deny mount,
+  deny pivot_root,
+}
+`))
+
+// profile returns the AppArmor profile of the sandbox of the VM
+func profile(vm *api.VM) string {
+	var b bytes.Buffer
+	_ = profileTemplate.Execute(&b, map[string]string{
+		"Name":    profileName(vm),
+		"VMDir":   filepath.Join(constants.VM_DIR, vm.GetUID().String()),
+		"DataDir": constants.DATA_DIR,
+	})
+	return b.String()
+}
+
+// applyAppArmor loads the profile of the VM, or replaces it, and returns its name
+func applyAppArmor(vm *api.VM) (string, error) {
+	if b, err := ioutil.ReadFile(apparmorEnabled); err != nil || strings.TrimSpace(string(b)) != "Y" {
+		return "", fmt.Errorf("AppArmor confinement is configured, but AppArmor isn't enabled on the host")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(apparmorParser, "--replace")
+	cmd.Stdin = strings.NewReader(profile(vm))
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("command %q exited with %q: %v", cmd.Args, bytes.TrimSpace(stderr.Bytes()), err)
+	}
+
+	return profileName(vm), nil
+}
+
+// unloadProfile removes the loaded profile from the kernel, if it's still loaded
+func unloadProfile(name string) error {
+	if err := ioutil.WriteFile(apparmorRemove, []byte(name), 0); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to unload the AppArmor profile %q: %v", name, err)
+	}
+
+	return nil
+}
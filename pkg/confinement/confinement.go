@@ -0,0 +1,99 @@
+package confinement
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/runtime"
+	"github.com/weaveworks/ignite/pkg/vfio"
+)
+
+// sharedDevices are bound into every sandbox, or every sandbox of VMs with host PCI devices,
+// they keep their labels
+var sharedDevices = map[string]bool{
+	"/dev/mapper/control": true,
+	"/dev/net/tun":        true,
+	"/dev/kvm":            true,
+	vfio.ContainerDevice:  true,
+}
+
+// Apply confines the sandbox container of the VM as configured. The SELinux label and
+// AppArmor profile are set in the container config, and the files and devices bound
+// into the container are labeled for it. usedLabels are the SELinux labels of the other
+// VMs, whose categories aren't given to this VM. The confinement is returned to be
+// recorded in the status of the VM, nil if none is configured.
+func Apply(cfg *api.ConfinementConfiguration, vm *api.VM, config *runtime.ContainerConfig, usedLabels []string) (*api.VMConfinementStatus, error) {
+	if !cfg.SELinux && !cfg.AppArmor {
+		return nil, nil
+	}
+
+	status := &api.VMConfinementStatus{}
+	if cfg.SELinux {
+		var previous string
+		if vm.Status.Confinement != nil {
+			previous = vm.Status.Confinement.SELinuxLabel
+		}
+
+		label, err := applySELinux(cfg, vm, config, previous, usedLabels)
+		if err != nil {
+			return nil, err
+		}
+
+		status.SELinuxLabel = label
+	}
+
+	if cfg.AppArmor {
+		profile, err := applyAppArmor(vm)
+		if err != nil {
+			return nil, err
+		}
+
+		status.AppArmorProfile = profile
+	}
+
+	config.SELinuxLabel = status.SELinuxLabel
+	config.AppArmorProfile = status.AppArmorProfile
+	return status, nil
+}
+
+// Release drops the confinement of the removed VM
+func Release(vm *api.VM) error {
+	if vm.Status.Confinement == nil || len(vm.Status.Confinement.AppArmorProfile) == 0 {
+		return nil
+	}
+
+	return unloadProfile(vm.Status.Confinement.AppArmorProfile)
+}
+
+// exclusivePaths returns the host paths of the binds and devices of the container owned
+// by the VM, the rest are shared with the other VMs
+func exclusivePaths(vm *api.VM, config *runtime.ContainerConfig) (exclusive, shared []string) {
+	vmDir := filepath.Join(constants.VM_DIR, vm.GetUID().String())
+	exclusive = append(exclusive, vmDir)
+
+	for _, bind := range config.Binds {
+		if !within(bind.HostPath, vmDir) {
+			shared = append(shared, bind.HostPath)
+		}
+	}
+
+	for _, device := range config.Devices {
+		if !sharedDevices[device.HostPath] {
+			exclusive = append(exclusive, device.HostPath)
+		}
+	}
+
+	return
+}
+
+// within returns if p is dir or in it
+func within(p, dir string) bool {
+	return p == dir || strings.HasPrefix(p, dir+"/")
+}
+
+func profileName(vm *api.VM) string {
+	return fmt.Sprintf("%s-%s", constants.IGNITE_PREFIX, vm.GetUID())
+}
@@ -0,0 +1,77 @@
+package confinement
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/runtime"
+)
+
+func TestAllocateLevel(t *testing.T) {
+	// intn returns the numbers in order
+	numbers := []int{7, 7, 9, 3, 12, 4}
+	intn := func(int) int {
+		n := numbers[0]
+		numbers = numbers[1:]
+		return n
+	}
+
+	// 7,7 repeats a category, 9,3 is used, and 12,4 is sorted
+	level, err := allocateLevel(map[string]bool{"s0:c3,c9": true}, intn)
+	assert.NilError(t, err)
+	assert.Equal(t, level, "s0:c4,c12")
+
+	_, err = allocateLevel(nil, func(int) int { return 1 })
+	assert.ErrorContains(t, err, "failed to allocate")
+}
+
+func TestLabelLevel(t *testing.T) {
+	assert.Equal(t, labelLevel("system_u:system_r:container_t:s0:c1,c2"), "s0:c1,c2")
+	assert.Equal(t, labelLevel("system_u:system_r:container_t:s0"), "s0")
+	assert.Equal(t, labelLevel(""), "")
+}
+
+func TestExclusivePaths(t *testing.T) {
+	vm := &api.VM{}
+	vm.SetUID("599a5bd0b7e3fd70")
+	vmDir := "/var/lib/firecracker/vm/599a5bd0b7e3fd70"
+
+	config := &runtime.ContainerConfig{
+		Binds: []*runtime.Bind{
+			runtime.BindBoth(vmDir),
+			{HostPath: vmDir + "/metadata.json", ContainerPath: "/vm.json"},
+			{HostPath: "/var/lib/firecracker/kernel/8ecc6c64d6a3ab87/vmlinux", ContainerPath: "/vmlinux"},
+		},
+		Devices: []*runtime.Bind{
+			runtime.BindBoth("/dev/mapper/control"),
+			runtime.BindBoth("/dev/kvm"),
+			runtime.BindBoth("/dev/mapper/ignite-599a5bd0b7e3fd70"),
+		},
+	}
+
+	exclusive, shared := exclusivePaths(vm, config)
+	assert.DeepEqual(t, exclusive, []string{vmDir, "/dev/mapper/ignite-599a5bd0b7e3fd70"})
+	assert.DeepEqual(t, shared, []string{"/var/lib/firecracker/kernel/8ecc6c64d6a3ab87/vmlinux"})
+}
+
+func TestProfile(t *testing.T) {
+	vm := &api.VM{}
+	vm.SetUID("599a5bd0b7e3fd70")
+
+	p := profile(vm)
+	assert.Assert(t, strings.Contains(p, "profile ignite-599a5bd0b7e3fd70 flags="))
+	assert.Assert(t, strings.Contains(p, "  /var/lib/firecracker/vm/599a5bd0b7e3fd70/** rwk,\n"))
+	assert.Assert(t, strings.Contains(p, "  deny /var/lib/firecracker/{image,kernel}/** wlk,\n"))
+}
+
+func TestApplyUnconfined(t *testing.T) {
+	config := &runtime.ContainerConfig{}
+	status, err := Apply(&api.ConfinementConfiguration{}, &api.VM{}, config, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, status == nil)
+	assert.Equal(t, config.SELinuxLabel, "")
+	assert.Equal(t, config.AppArmorProfile, "")
+}
@@ -0,0 +1,132 @@
+package confinement
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/runtime"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	selinuxFS           = "/sys/fs/selinux"
+	selinuxXattr        = "security.selinux"
+	defaultProcessType  = "container_t"
+	defaultFileType     = "container_file_t"
+	sharedLevel         = "s0"
+	categories          = 1024
+	maxLevelAllocations = 1000
+)
+
+var (
+	random   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	randomMu sync.Mutex
+)
+
+// applySELinux gives the VM its MCS level, kept from its previous label unless another
+// VM took it, and labels the files of the VM with it. The process label is returned.
+func applySELinux(cfg *api.ConfinementConfiguration, vm *api.VM, config *runtime.ContainerConfig, previous string, usedLabels []string) (string, error) {
+	if _, err := os.Stat(filepath.Join(selinuxFS, "enforce")); err != nil {
+		return "", fmt.Errorf("SELinux confinement is configured, but SELinux isn't enabled on the host")
+	}
+
+	used := make(map[string]bool, len(usedLabels))
+	for _, label := range usedLabels {
+		used[labelLevel(label)] = true
+	}
+
+	level := labelLevel(previous)
+	if len(level) == 0 || used[level] {
+		var err error
+		if level, err = allocateLevel(used, intn); err != nil {
+			return "", err
+		}
+	}
+
+	processType, fileType := cfg.SELinuxProcessType, cfg.SELinuxFileType
+	if len(processType) == 0 {
+		processType = defaultProcessType
+	}
+	if len(fileType) == 0 {
+		fileType = defaultFileType
+	}
+
+	// The files and devices of the VM get its level, only its sandbox can access them.
+	// The shared ones, like the kernel, get the level without categories, which every
+	// sandbox can read.
+	exclusive, shared := exclusivePaths(vm, config)
+	for _, p := range exclusive {
+		if err := relabel(p, fileLabel(fileType, level)); err != nil {
+			return "", err
+		}
+	}
+	for _, p := range shared {
+		if err := relabel(p, fileLabel(fileType, sharedLevel)); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("system_u:system_r:%s:%s", processType, level), nil
+}
+
+// allocateLevel picks an MCS level of two random categories, like sVirt does, which
+// isn't used. intn returns a random number in [0, n).
+func allocateLevel(used map[string]bool, intn func(n int) int) (string, error) {
+	for i := 0; i < maxLevelAllocations; i++ {
+		c1, c2 := intn(categories), intn(categories)
+		if c1 == c2 {
+			continue
+		}
+		if c1 > c2 {
+			c1, c2 = c2, c1
+		}
+
+		if level := fmt.Sprintf("%s:c%d,c%d", sharedLevel, c1, c2); !used[level] {
+			return level, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to allocate unused SELinux categories")
+}
+
+// intn is random.Intn, which isn't safe for concurrent use by itself
+func intn(n int) int {
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	return random.Intn(n)
+}
+
+// labelLevel returns the MCS level of the SELinux label, e.g. s0:c1,c2 of
+// system_u:system_r:container_t:s0:c1,c2
+func labelLevel(label string) string {
+	if parts := strings.SplitN(label, ":", 4); len(parts) == 4 {
+		return parts[3]
+	}
+
+	return ""
+}
+
+func fileLabel(fileType, level string) string {
+	return fmt.Sprintf("system_u:object_r:%s:%s", fileType, level)
+}
+
+// relabel sets the SELinux label of p, and of everything in it if it's a directory
+func relabel(p, label string) error {
+	return filepath.Walk(p, func(p string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := unix.Lsetxattr(p, selinuxXattr, []byte(label), 0); err != nil {
+			return fmt.Errorf("failed to label %q with %q: %v", p, label, err)
+		}
+
+		return nil
+	})
+}
@@ -17,6 +17,12 @@ const (
 	// Filename for metadata files
 	METADATA = "metadata.json"
 
+	// Database storing the metadata instead of the metadata files if it exists, in DATA_DIR
+	METADATA_DB = "metadata.db"
+
+	// Settings of the etcd cluster storing the metadata instead of the metadata files if it exists, in DATA_DIR
+	METADATA_ETCD = "metadata-etcd.json"
+
 	// DHCP infinite lease time
 	DHCP_INFINITE_LEASE = "4294967295s"
 
@@ -41,9 +47,43 @@ const (
 	// In-container file name for the firecracker metrics FIFO
 	METRICS_FIFO = "firecracker_metrics.fifo"
 
+	// In-container file name for the unix socket exposing the vsock device of the VM,
+	// the host connects to the guest agent through it
+	FIRECRACKER_VSOCK_SOCKET = "vsock.sock"
+
+	// Context ID of the VM on its vsock device, every VM has its own device
+	FIRECRACKER_VSOCK_CID = 3
+
+	// In-container file name for the QMP socket of QEMU, which emulates the VMs without KVM
+	QEMU_QMP_SOCKET = "qemu.sock"
+
 	// Socket with a web server (with metrics for now) for the daemon
 	DAEMON_SOCKET = "daemon.sock"
 
+	// Socket the management API of the daemon is served on
+	DAEMON_API_SOCKET = "ignited.sock"
+
+	// Socket the CRI runtime and image services of the daemon are served on, if enabled
+	DAEMON_CRI_SOCKET = "cri.sock"
+
+	// Directory of the CA and certificates securing the management API on TCP
+	DAEMON_PKI_DIR = "/etc/ignite/pki"
+
+	// Log the daemon appends the VM, image and kernel events to, in DATA_DIR
+	EVENT_LOG = "events.log"
+
+	// File the daemon writes its queued, running and recently finished jobs to, in DATA_DIR
+	JOBS_FILE = "jobs.json"
+
+	// Directory of the files locked to serialize operations between ignite processes
+	LOCK_DIR = "/run/ignite/locks"
+
+	// Directory recording the VM each PCI device passed through is held by
+	VFIO_OWNER_DIR = "/run/ignite/vfio"
+
+	// Log the mutating operations are audited to by default, in DATA_DIR
+	AUDIT_LOG = "audit.log"
+
 	// How many characters Ignite UIDs should have
 	IGNITE_UID_LENGTH = 16
 
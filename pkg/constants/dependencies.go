@@ -1,8 +1,6 @@
 package constants
 
 var BinaryDependencies = [...]string{
-	"mount",
-	"umount",
 	"tar",
 	"mkfs.ext4",
 	"e2fsck",
@@ -15,10 +13,37 @@ var BinaryDependencies = [...]string{
 
 var PathDependencies = [...]string{
 	"/dev/mapper/control",
+	"/dev/loop-control",
 	"/dev/net/tun",
 	"/dev/kvm",
 }
 
+var Qcow2Dependencies = [...]string{
+	"qemu-img",
+	"qemu-nbd",
+}
+
+var LVMDependencies = [...]string{
+	"lvcreate",
+	"lvchange",
+	"lvextend",
+	"lvremove",
+	"dd",
+}
+
+var ZFSDependencies = [...]string{
+	"zfs",
+	"dd",
+}
+
+var RBDDependencies = [...]string{
+	"rbd",
+}
+
+var NBDDependencies = [...]string{
+	"nbd-client",
+}
+
 var CNIDependencies = [...]string{
 	"/opt/cni/bin/loopback",
 	"/opt/cni/bin/bridge",
@@ -6,4 +6,12 @@ const (
 
 	// Filename for the image file containing the image filesystem
 	IMAGE_FS = "image.ext4"
+
+	// Filenames for the software bill of materials of the image, in the SPDX or CycloneDX format
+	IMAGE_SBOM_SPDX      = "sbom.spdx.json"
+	IMAGE_SBOM_CYCLONEDX = "sbom.cdx.json"
+
+	// IMAGE_IMPORT_CONCURRENCY is how many images and kernels are imported at once
+	// by default, see imageImport.concurrency in the ignite configuration
+	IMAGE_IMPORT_CONCURRENCY = 3
 )
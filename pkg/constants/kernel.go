@@ -7,6 +7,9 @@ const (
 	// Kernel filename
 	KERNEL_FILE = "vmlinux"
 
+	// Compressed kernel filename, kept next to the kernel file extracted from it
+	KERNEL_COMPRESSED_FILE = "vmlinuz"
+
 	// Initrd filename
 	INITRD_FILE = "initrd"
 
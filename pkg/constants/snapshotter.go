@@ -18,4 +18,7 @@ const (
 	// Paths to the default data and metadata backing files
 	SNAPSHOTTER_METADATA_PATH = SNAPSHOTTER_DIR + "/metadata.dm"
 	SNAPSHOTTER_DATA_PATH     = SNAPSHOTTER_DIR + "/data.dm"
+
+	// Path to the file holding the state of the pool
+	SNAPSHOTTER_POOL_PATH = SNAPSHOTTER_DIR + "/pool.json"
 )
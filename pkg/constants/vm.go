@@ -6,14 +6,24 @@ const (
 	// Path to directory containing a subdirectory for each VM
 	VM_DIR = DATA_DIR + "/vm"
 
+	// Path to directory containing a subdirectory for each VM template
+	VM_TEMPLATE_DIR = DATA_DIR + "/vmtemplate"
+
+	// Path to directory containing the snapshots of the boot cache
+	BOOT_CACHE_DIR = DATA_DIR + "/bootcache"
+
 	// Path where ignited stores its manifests
 	MANIFEST_DIR = "/etc/firecracker/manifests"
 
 	// Default values for VM options
-	VM_DEFAULT_CPUS        = 1
-	VM_DEFAULT_MEMORY      = 512 * MB
-	VM_DEFAULT_SIZE        = 4 * GB
-	VM_DEFAULT_KERNEL_ARGS = "console=ttyS0 reboot=k panic=1 pci=off ip=dhcp"
+	VM_DEFAULT_CPUS   = 1
+	VM_DEFAULT_MEMORY = 512 * MB
+	VM_DEFAULT_SIZE   = 4 * GB
+
+	// Bounds of the VM resources enforced by the validation, Firecracker supports
+	// up to 32 vCPUs, and the guests need some memory to boot
+	VM_MAX_CPUS   = 32
+	VM_MIN_MEMORY = 64 * MB
 
 	// SSH key template for VMs
 	VM_SSH_KEY_TEMPLATE = "id_%s"
@@ -21,6 +31,48 @@ const (
 	// TODO: remove this when the old dm code is removed
 	OVERLAY_FILE = "overlay.dm"
 
+	// Overlay file of VMs using the qcow2 snapshotter
+	QCOW2_OVERLAY_FILE = "overlay.qcow2"
+
+	// File recording the NBD device a qcow2 overlay is connected to
+	QCOW2_NBD_DEVICE_FILE = "overlay.nbd"
+
+	// Overlay file of VMs using the reflink snapshotter
+	REFLINK_OVERLAY_FILE = "overlay.raw"
+
+	// File recording the loop device a reflink overlay is attached to
+	REFLINK_LOOP_DEVICE_FILE = "overlay.loop"
+
+	// File recording the thin device ID of VMs using the dmthin snapshotter
+	DMTHIN_OVERLAY_FILE = "overlay.thin"
+
+	// File recording the logical volume of VMs using the lvm snapshotter
+	LVM_OVERLAY_FILE = "overlay.lv"
+
+	// File recording the zvol of VMs using the zfs snapshotter
+	ZFS_OVERLAY_FILE = "overlay.zvol"
+
+	// File recording the RBD image of VMs using the rbd snapshotter
+	RBD_OVERLAY_FILE = "overlay.rbd"
+
+	// Directory recording the NBD devices the NBD volumes of a VM are connected to
+	NBD_VOLUME_DIR = "nbd"
+
+	// Directory recording the host drivers of the PCI devices passed through into a VM
+	VFIO_DEVICE_DIR = "vfio"
+
+	// Directory recording the CRI containers of a VM that's the sandbox of a Kubernetes pod
+	CRI_CONTAINER_DIR = "cri"
+
+	// Directory holding the disk checkpoints of a VM
+	CHECKPOINT_DIR = "checkpoints"
+
+	// Directory holding the snapshot of a VM migrated from another host until it's restored
+	MIGRATION_DIR = "migration"
+
+	// Directory Firecracker writes the boot cache snapshot of a VM to, before it's moved to BOOT_CACHE_DIR
+	BOOT_CACHE_SNAPSHOT_DIR = "bootcache"
+
 	// Prometheus socket filename
 	PROMETHEUS_SOCKET = "prometheus.sock"
 
@@ -33,6 +85,12 @@ const (
 	// Where the initrd is located inside of the container
 	IGNITE_SPAWN_INITRD_FILE_PATH = "/initrd"
 
+	// Where the custom seccomp filter of Firecracker is located inside of the container
+	IGNITE_SPAWN_SECCOMP_FILTER_FILE_PATH = "/seccomp.bpf"
+
+	// Where the UEFI firmware of VMs of disk images is located inside of the container, if it's set
+	IGNITE_SPAWN_FIRMWARE_FILE_PATH = "/firmware.fd"
+
 	// Subdirectory for volumes to be forwarded into the VM
 	IGNITE_SPAWN_VOLUME_DIR = "/volumes"
 
@@ -47,9 +105,152 @@ const (
 	// IGNITE_INTERFACE_ANNOTATION is the annotation prefix to store a list of extra interfaces
 	IGNITE_INTERFACE_ANNOTATION = "ignite.weave.works/interface/"
 
+	// IGNITE_TEMPLATE_ANNOTATION is the annotation referencing the VMTemplate a VM is created from
+	IGNITE_TEMPLATE_ANNOTATION = "ignite.weave.works/template"
+
+	// IGNITE_ENVIRONMENT_LABEL is the label naming the gitops environment a VM is synced from
+	IGNITE_ENVIRONMENT_LABEL = "ignite.weave.works/environment"
+
+	// IGNITE_PRUNE_ANNOTATION set to "disabled" protects a VM from being pruned when its gitops manifest is removed
+	IGNITE_PRUNE_ANNOTATION = "ignite.weave.works/prune"
+
+	// IGNITE_PRUNED_ANNOTATION flags a VM kept on the host after its gitops manifest was removed, with the time of the removal
+	IGNITE_PRUNED_ANNOTATION = "ignite.weave.works/pruned"
+
+	// IGNITE_KERNEL_CMDLINE_VAR_ANNOTATION is the annotation prefix to store custom variables of the kernel command line
+	IGNITE_KERNEL_CMDLINE_VAR_ANNOTATION = "ignite.weave.works/cmdline-var/"
+
+	// IGNITE_HOST_ANNOTATION is the annotation prefix to store the IP addresses of host names written to /etc/hosts of the VM
+	IGNITE_HOST_ANNOTATION = "ignite.weave.works/host/"
+
 	// IGNITE_SANDBOX_ENV_VAR is the annotation prefix to store a list of env variables
 	IGNITE_SANDBOX_ENV_VAR = "ignite.weave.works/sandbox-env/"
 
+	// IGNITE_CRI_SANDBOX_ANNOTATION holds the pod sandbox a VM was created for by the CRI server of ignited
+	IGNITE_CRI_SANDBOX_ANNOTATION = "ignite.weave.works/cri-sandbox"
+
+	// IGNITE_OWNER_LABEL holds the UID of the Kubernetes resource the operator created a VM, image or kernel for
+	IGNITE_OWNER_LABEL = "ignite.weave.works/owner"
+
+	// IGNITE_GENERATION_LABEL holds the generation of the Kubernetes resource the operator created a VM from
+	IGNITE_GENERATION_LABEL = "ignite.weave.works/generation"
+
+	// IGNITE_NODE_ANNOTATION holds the Kubernetes node the operator assigned a VM resource to
+	IGNITE_NODE_ANNOTATION = "ignite.weave.works/node"
+
+	// IGNITE_RUNNING_ANNOTATION set to "false" keeps the VM of a VM resource of the operator stopped
+	IGNITE_RUNNING_ANNOTATION = "ignite.weave.works/running"
+
+	// IGNITE_API_ADDRESS_ANNOTATION holds the address of the ignited API of a Kubernetes node for the operator
+	IGNITE_API_ADDRESS_ANNOTATION = "ignite.weave.works/api-address"
+
+	// IGNITE_COMPOSE_PROJECT_LABEL holds the name of the stack of "ignite compose" a VM belongs to
+	IGNITE_COMPOSE_PROJECT_LABEL = "ignite.weave.works/compose-project"
+
+	// IGNITE_COMPOSE_VM_LABEL holds the name of a VM in the stack of "ignite compose" it belongs to
+	IGNITE_COMPOSE_VM_LABEL = "ignite.weave.works/compose-vm"
+
+	// IGNITE_PROJECT_ANNOTATION holds the directory of the project of "ignite up" a VM was created for
+	IGNITE_PROJECT_ANNOTATION = "ignite.weave.works/project"
+
 	// IGNITE_SPAWN_TIMEOUT determines how long to wait for spawn to start up
 	IGNITE_SPAWN_TIMEOUT = 2 * time.Minute
+
+	// SECRETS_TIMEOUT determines how long to wait for the guest agent to receive the secrets of a VM
+	SECRETS_TIMEOUT = 2 * time.Minute
+
+	// LOAD_FILES_TIMEOUT determines how long to wait for the guest agent to write the rest of
+	// the files of a lazily imported image into a VM
+	LOAD_FILES_TIMEOUT = 2 * time.Minute
+
+	// BOOT_CACHE_TIMEOUT determines how long to wait for the guest agent of a booted VM before
+	// it's snapshotted into the boot cache, and of a VM restored from the boot cache
+	BOOT_CACHE_TIMEOUT = 2 * time.Minute
+
+	// READDRESS_TIMEOUT determines how long to wait for the guest agent of a VM restored from
+	// a snapshot, to change the address the guest kept to the one assigned by the network
+	READDRESS_TIMEOUT = 30 * time.Second
+
+	// CRI_SANDBOX_TIMEOUT determines how long to wait for the guest agent of a started pod sandbox VM
+	CRI_SANDBOX_TIMEOUT = 2 * time.Minute
+
+	// SECRET_DEFAULT_MODE is the permissions of the secret files in the guest
+	SECRET_DEFAULT_MODE = 0400
+
+	// VAULT_TIMEOUT determines how long to wait for the responses of Vault
+	VAULT_TIMEOUT = 30 * time.Second
+
+	// VAULT_RENEW_INTERVAL is the longest ignited waits between renewals of its Vault token
+	// and leases, they're renewed earlier when they expire sooner
+	VAULT_RENEW_INTERVAL = time.Hour
+
+	// VAULT_RENEW_RETRY determines how long to wait before retrying a failed renewal
+	VAULT_RENEW_RETRY = 30 * time.Second
+
+	// VAULT_LEASES_FILE records the Vault leases of the secrets read for a VM, in its directory
+	VAULT_LEASES_FILE = "vault-leases.json"
+
+	// OVERLAY_USAGE_WARNING_PERCENT is the percentage of a VM's overlay size limit
+	// at which the VM is flagged as near its limit
+	OVERLAY_USAGE_WARNING_PERCENT = 90
+
+	// OVERLAY_USAGE_CHECK_INTERVAL determines how often ignite-spawn checks the overlay usage
+	OVERLAY_USAGE_CHECK_INTERVAL = 30 * time.Second
+
+	// SSH_CHECK_INTERVAL determines how often ignite-spawn checks if the SSH server within the VM accepts connections
+	SSH_CHECK_INTERVAL = 10 * time.Second
+
+	// VM_STATS_FILE is the file in the VM directory ignite-spawn writes the resource usage of the VM to
+	VM_STATS_FILE = "stats.json"
+
+	// VM_STATS_INTERVAL determines how often ignite-spawn collects the resource usage of the VM
+	VM_STATS_INTERVAL = 2 * time.Second
+
+	// VM_CONSOLE_LOG_FILE is the file in the VM directory ignite-spawn records the console output of the VM to
+	VM_CONSOLE_LOG_FILE = "console.log"
+
+	// VM_CONSOLE_SOCKET is the socket in the VM directory ignite-spawn shares the console of the VM on,
+	// it accepts a single writable and any number of read-only attach sessions
+	VM_CONSOLE_SOCKET = "console.sock"
+
+	// VM_CONSOLE_LOG_MAX_SIZE is the default size in bytes at which the console log is rotated
+	VM_CONSOLE_LOG_MAX_SIZE = 10 * 1024 * 1024
+
+	// VM_CONSOLE_LOG_MAX_FILES is the default number of rotated console logs kept,
+	// the most recent one has a ".1" suffix
+	VM_CONSOLE_LOG_MAX_FILES = 1
+
+	// VM_STOP_REQUESTED_FILE marks that a VM is being stopped by ignite, which exempts the stop from its restart policy
+	VM_STOP_REQUESTED_FILE = "stop-requested"
+
+	// VM_RESTART_BACKOFF_MIN and VM_RESTART_BACKOFF_MAX bound the delay before ignited restarts
+	// a stopped VM, the delay doubles with every restart of a VM that didn't stay up
+	VM_RESTART_BACKOFF_MIN = 1 * time.Second
+	VM_RESTART_BACKOFF_MAX = 5 * time.Minute
+
+	// VM_RESTART_CHECK_INTERVAL determines how often ignited checks for VMs to restart
+	VM_RESTART_CHECK_INTERVAL = 2 * time.Second
+
+	// VM_EXIT_CHECK_INTERVAL determines how often ignited checks for exited VMs still holding NBD devices or PCI devices
+	VM_EXIT_CHECK_INTERVAL = 2 * time.Second
+
+	// VM_UNHEALTHY_FILE marks that a VM is being killed by ignited for failing its liveness probe
+	VM_UNHEALTHY_FILE = "unhealthy"
+
+	// PROBE_CHECK_INTERVAL determines how often ignited checks for liveness probes that are due
+	PROBE_CHECK_INTERVAL = time.Second
+
+	// PROBE_PERIOD, PROBE_TIMEOUT and PROBE_FAILURE_THRESHOLD are the defaults of the liveness probes
+	PROBE_PERIOD            = 10 * time.Second
+	PROBE_TIMEOUT           = 1 * time.Second
+	PROBE_FAILURE_THRESHOLD = 3
+
+	// EVENT_CHECK_INTERVAL determines how often ignited checks the VMs, images and kernels for events
+	EVENT_CHECK_INTERVAL = time.Second
+
+	// EVENT_WEBHOOK_TIMEOUT is the default timeout of the requests to event webhooks
+	EVENT_WEBHOOK_TIMEOUT = 10 * time.Second
+
+	// VM_WAIT_INTERVAL determines how often "ignite vm wait" checks the state of the VM
+	VM_WAIT_INTERVAL = 500 * time.Millisecond
 )
@@ -0,0 +1,85 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path"
+	"time"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+const firecrackerAPITimeout = 5 * time.Second
+
+// FirecrackerRequest sends a request with the given JSON body to the API of the Firecracker
+// process of the VM. The API socket is created in the VM directory, which is shared between
+// the host and the VM container, so this works from both sides. The Go SDK only covers the
+// API of the Firecracker version it's built for, this is used for the newer endpoints.
+func FirecrackerRequest(vm *api.VM, method, endpoint string, body interface{}, timeout time.Duration) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := firecrackerDo(vm, method, endpoint, bytes.NewReader(b), timeout)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// FirecrackerGet decodes the JSON response of the given endpoint of the Firecracker API into result
+func FirecrackerGet(vm *api.VM, endpoint string, result interface{}) error {
+	resp, err := firecrackerDo(vm, http.MethodGet, endpoint, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// firecrackerDo sends a request to the API of the Firecracker process of the VM, and
+// returns the response if it succeeded
+func firecrackerDo(vm *api.VM, method, endpoint string, body io.Reader, timeout time.Duration) (*http.Response, error) {
+	if timeout == 0 {
+		timeout = firecrackerAPITimeout
+	}
+
+	socketPath := path.Join(vm.ObjectPath(), constants.FIRECRACKER_API_SOCKET)
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	req, err := http.NewRequest(method, "http://localhost"+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("firecracker API returned %s for %s %s: %s", resp.Status, method, endpoint, bytes.TrimSpace(msg))
+	}
+
+	return resp, nil
+}
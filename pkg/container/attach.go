@@ -0,0 +1,263 @@
+package container
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+const (
+	// attachWritable and attachReadOnly are the modes an attach session requests
+	attachWritable = "rw"
+	attachReadOnly = "ro"
+	// attachOK is the reply to an accepted attach session
+	attachOK = "ok"
+	// attachHandshakeTimeout bounds the time a client has to request its mode
+	attachHandshakeTimeout = 5 * time.Second
+	// attachSessionBuffer is the number of console writes queued for a session,
+	// sessions that fall further behind are disconnected
+	attachSessionBuffer = 256
+)
+
+// ErrNoConsoleSocket is returned for VMs that haven't been started with a console socket
+var ErrNoConsoleSocket = errors.New("the console of the VM isn't shared over a socket")
+
+// consoleMux shares the serial console of the VM between multiple attach sessions over
+// a unix socket in the VM directory. The console output is sent to every session, while
+// only the single writable session can send input to the VM.
+type consoleMux struct {
+	listener net.Listener
+	input    io.Writer
+
+	mu       sync.Mutex
+	sessions map[*attachSession]struct{}
+	writer   *attachSession
+}
+
+var _ io.WriteCloser = &consoleMux{}
+
+type attachSession struct {
+	conn   net.Conn
+	output chan []byte
+}
+
+// newConsoleMux starts serving the console on the socket at p, the input of the writable session is written to input
+func newConsoleMux(p string, input io.Writer) (*consoleMux, error) {
+	// The socket is left over if the VM wasn't stopped cleanly
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	l, err := net.Listen("unix", p)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &consoleMux{
+		listener: l,
+		input:    input,
+		sessions: make(map[*attachSession]struct{}),
+	}
+
+	go m.serve()
+	return m, nil
+}
+
+func (m *consoleMux) serve() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			// The listener has been closed
+			return
+		}
+
+		go m.handle(conn)
+	}
+}
+
+// handle reads the mode requested by the session, and passes its input to the VM if it's writable
+func (m *consoleMux) handle(conn net.Conn) {
+	_ = conn.SetReadDeadline(time.Now().Add(attachHandshakeTimeout))
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	mode := strings.TrimSpace(line)
+	s := &attachSession{conn: conn, output: make(chan []byte, attachSessionBuffer)}
+	if err := m.add(s, mode); err != nil {
+		fmt.Fprintf(conn, "%v\n", err)
+		conn.Close()
+		return
+	}
+	defer m.remove(s)
+
+	go func() {
+		for b := range s.output {
+			if _, err := conn.Write(b); err != nil {
+				break
+			}
+		}
+		conn.Close()
+	}()
+
+	input := ioutil.Discard
+	if mode == attachWritable {
+		input = m.input
+	}
+
+	// Returns once the session detaches or is disconnected
+	_, _ = io.Copy(input, r)
+}
+
+func (m *consoleMux) add(s *attachSession, mode string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch mode {
+	case attachWritable:
+		if m.writer != nil {
+			return fmt.Errorf("the VM is already attached to with input, attach read-only instead")
+		}
+		m.writer = s
+	case attachReadOnly:
+	default:
+		return fmt.Errorf("unknown attach mode %q", mode)
+	}
+
+	m.sessions[s] = struct{}{}
+	// Acknowledged through the output, so it's sent before any console output
+	s.output <- []byte(attachOK + "\n")
+	return nil
+}
+
+func (m *consoleMux) remove(s *attachSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeLocked(s)
+}
+
+func (m *consoleMux) removeLocked(s *attachSession) {
+	if _, ok := m.sessions[s]; !ok {
+		return
+	}
+
+	delete(m.sessions, s)
+	close(s.output)
+	if m.writer == s {
+		m.writer = nil
+	}
+}
+
+// Write sends the console output to every session. It never blocks the console,
+// sessions that don't keep up with the output are disconnected instead.
+func (m *consoleMux) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for s := range m.sessions {
+		b := make([]byte, len(p))
+		copy(b, p)
+
+		select {
+		case s.output <- b:
+		default:
+			log.Warnf("Disconnecting an attach session that doesn't keep up with the console output")
+			m.removeLocked(s)
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close stops serving the console, and disconnects all sessions
+func (m *consoleMux) Close() error {
+	err := m.listener.Close()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for s := range m.sessions {
+		m.removeLocked(s)
+	}
+
+	return err
+}
+
+// DialConsole opens an attach session to the console of the running VM. The session
+// receives the console output, and sends its input to the VM unless it's read-only.
+// Only one writable session is accepted at a time.
+func DialConsole(vm *api.VM, readOnly bool) (net.Conn, error) {
+	p := consoleSocketPath(vm)
+	if _, err := os.Stat(p); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w for VM %q", ErrNoConsoleSocket, vm.GetUID())
+	}
+
+	conn, err := dialConsole(p, readOnly)
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		// The socket is left over from a VM that didn't stop cleanly, or isn't served yet
+		return nil, fmt.Errorf("%w for VM %q", ErrNoConsoleSocket, vm.GetUID())
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to attach to VM %q: %v", vm.GetUID(), err)
+	}
+
+	return conn, nil
+}
+
+func dialConsole(p string, readOnly bool) (net.Conn, error) {
+	conn, err := net.Dial("unix", p)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := attachWritable
+	if readOnly {
+		mode = attachReadOnly
+	}
+
+	if _, err := fmt.Fprintf(conn, "%s\n", mode); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Read the reply byte by byte, the console output follows it
+	var reply []byte
+	b := make([]byte, 1)
+	for {
+		if _, err := conn.Read(b); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		if b[0] == '\n' {
+			break
+		}
+		reply = append(reply, b[0])
+	}
+
+	if string(reply) != attachOK {
+		conn.Close()
+		return nil, errors.New(string(reply))
+	}
+
+	return conn, nil
+}
+
+func consoleSocketPath(vm *api.VM) string {
+	return path.Join(vm.ObjectPath(), constants.VM_CONSOLE_SOCKET)
+}
@@ -0,0 +1,69 @@
+package container
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestConsoleMux(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-console-socket-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	input, inputWriter := io.Pipe()
+	p := filepath.Join(dir, "console.sock")
+	m, err := newConsoleMux(p, inputWriter)
+	assert.NilError(t, err)
+	defer m.Close()
+
+	writable, err := dialConsole(p, false)
+	assert.NilError(t, err)
+	defer writable.Close()
+
+	// Only a single writable session is accepted
+	_, err = dialConsole(p, false)
+	assert.ErrorContains(t, err, "attach read-only instead")
+
+	readOnly, err := dialConsole(p, true)
+	assert.NilError(t, err)
+	defer readOnly.Close()
+
+	// The console output is sent to all sessions
+	_, err = m.Write([]byte("login: "))
+	assert.NilError(t, err)
+	for _, conn := range []io.Reader{writable, readOnly} {
+		b := make([]byte, 7)
+		_, err := io.ReadFull(conn, b)
+		assert.NilError(t, err)
+		assert.Equal(t, string(b), "login: ")
+	}
+
+	// Only the input of the writable session reaches the VM
+	_, err = readOnly.Write([]byte("ignored"))
+	assert.NilError(t, err)
+	_, err = writable.Write([]byte("root\n"))
+	assert.NilError(t, err)
+	b := make([]byte, 5)
+	_, err = io.ReadFull(input, b)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "root\n")
+
+	// Another writable session is accepted once the first one detaches
+	assert.NilError(t, writable.Close())
+	for i := 0; ; i++ {
+		conn, err := dialConsole(p, false)
+		if err == nil {
+			conn.Close()
+			break
+		}
+
+		assert.Assert(t, i < 50, "writable session not released: %v", err)
+		time.Sleep(10 * time.Millisecond)
+	}
+}
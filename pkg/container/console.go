@@ -0,0 +1,399 @@
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+const (
+	// consoleLineLimit caps the length of a recorded line, so console
+	// output without newlines doesn't get buffered indefinitely
+	consoleLineLimit = 16 * 1024
+	// consolePollInterval is how often a followed console log is checked for new output
+	consolePollInterval = 500 * time.Millisecond
+	// compressedSuffix is appended to the name of the compressed rotated console logs
+	compressedSuffix = ".gz"
+)
+
+// ErrNoConsoleLog is returned for VMs that haven't been started with console log recording
+var ErrNoConsoleLog = errors.New("no console output has been recorded")
+
+// ConsoleLine is a line of the console output of a VM
+type ConsoleLine struct {
+	// Time is when the line was written to the console, it's zero if it couldn't be parsed
+	Time time.Time
+	Text string
+}
+
+// consoleLog records the console output of the VM to the console log file in the
+// VM directory, prefixing every line with the time it was written at. The log
+// outlives the VM container, so the output of stopped VMs stays available.
+// It's rotated by size or age to "console.log.1", "console.log.2" and so on.
+type consoleLog struct {
+	path    string
+	cfg     api.ConsoleLogConfiguration
+	file    *os.File
+	size    int64
+	oldest  time.Time
+	partial []byte
+}
+
+var _ io.WriteCloser = &consoleLog{}
+
+func newConsoleLog(vm *api.VM, cfg api.ConsoleLogConfiguration) (*consoleLog, error) {
+	if cfg.MaxSize.Bytes() == 0 {
+		cfg.MaxSize = meta.NewSizeFromBytes(constants.VM_CONSOLE_LOG_MAX_SIZE)
+	}
+	if cfg.MaxFiles == 0 {
+		cfg.MaxFiles = constants.VM_CONSOLE_LOG_MAX_FILES
+	}
+
+	c := &consoleLog{path: consoleLogPath(vm), cfg: cfg}
+	return c, c.open()
+}
+
+func (c *consoleLog) open() error {
+	f, err := os.OpenFile(c.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	c.file, c.size, c.oldest = f, fi.Size(), time.Time{}
+
+	// The age of an existing log is the time of its first line
+	if c.size > 0 {
+		_, _ = readConsoleLines(c.path, 0, func(line ConsoleLine) error {
+			c.oldest = line.Time
+			return io.EOF
+		})
+	}
+
+	return nil
+}
+
+// Write records the complete lines in p. Failing to record the output must not
+// disrupt the console, so errors are logged and disable the recording instead.
+func (c *consoleLog) Write(p []byte) (int, error) {
+	now := time.Now()
+	c.partial = append(c.partial, p...)
+	for {
+		i := bytes.IndexByte(c.partial, '\n')
+		if i < 0 && len(c.partial) < consoleLineLimit {
+			break
+		}
+
+		line := c.partial
+		if i >= 0 {
+			line, c.partial = c.partial[:i], c.partial[i+1:]
+		} else {
+			c.partial = nil
+		}
+
+		c.writeLine(now, line)
+	}
+
+	return len(p), nil
+}
+
+// Close records the incomplete last line, if any, and closes the console log
+func (c *consoleLog) Close() error {
+	if len(c.partial) > 0 {
+		c.writeLine(time.Now(), c.partial)
+		c.partial = nil
+	}
+
+	if c.file == nil {
+		return nil
+	}
+
+	return c.file.Close()
+}
+
+func (c *consoleLog) writeLine(t time.Time, line []byte) {
+	if c.file == nil {
+		return
+	}
+
+	n, err := fmt.Fprintf(c.file, "%s %s\n", t.UTC().Format(time.RFC3339Nano), bytes.TrimRight(line, "\r"))
+	c.size += int64(n)
+	if c.oldest.IsZero() {
+		c.oldest = t
+	}
+
+	if err == nil && c.rotationDue(t) {
+		err = c.rotate()
+	}
+
+	if err != nil {
+		log.Warnf("Failed to record the console output, disabling the console log: %v", err)
+		c.file.Close()
+		c.file = nil
+	}
+}
+
+// rotationDue returns whether the console log has reached its maximum size or age at time t
+func (c *consoleLog) rotationDue(t time.Time) bool {
+	if maxSize := int64(c.cfg.MaxSize.Bytes()); maxSize > 0 && c.size >= maxSize {
+		return true
+	}
+
+	return c.cfg.MaxAge.Duration > 0 && t.Sub(c.oldest) >= c.cfg.MaxAge.Duration
+}
+
+// rotate shifts the rotated console logs by one, removing the ones beyond MaxFiles, moves the
+// current console log to "console.log.1" and starts a new one. The most recent rotated log is
+// never compressed, as "ignite vm logs --follow" finishes reading it after a rotation.
+func (c *consoleLog) rotate() error {
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+
+	maxFiles := int(c.cfg.MaxFiles)
+	for i := maxFiles; ; i++ {
+		file, err := findRotated(c.path, i)
+		if err != nil {
+			break
+		}
+
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+	}
+
+	for i := maxFiles - 1; i > 0; i-- {
+		file, err := findRotated(c.path, i)
+		if err != nil {
+			continue
+		}
+
+		next := rotatedPath(c.path, i+1)
+		if strings.HasSuffix(file, compressedSuffix) {
+			next += compressedSuffix
+		}
+
+		if err := os.Rename(file, next); err != nil {
+			return err
+		}
+	}
+
+	if c.cfg.Compress && maxFiles > 1 {
+		if err := compressFile(rotatedPath(c.path, 2)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.Rename(c.path, rotatedPath(c.path, 1)); err != nil {
+		return err
+	}
+
+	return c.open()
+}
+
+// rotatedPath returns the path of the i-th most recent rotated console log
+func rotatedPath(p string, i int) string {
+	return fmt.Sprintf("%s.%d", p, i)
+}
+
+// findRotated returns the path of the i-th most recent rotated console log,
+// which may be compressed. It returns an error if the log doesn't exist.
+func findRotated(p string, i int) (string, error) {
+	file := rotatedPath(p, i)
+	_, err := os.Stat(file + compressedSuffix)
+	if err == nil {
+		return file + compressedSuffix, nil
+	}
+
+	_, err = os.Stat(file)
+	return file, err
+}
+
+// compressFile gzips the file to file + ".gz", and removes the uncompressed file
+func compressFile(file string) (err error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(file + compressedSuffix)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(file)
+}
+
+// ReadConsoleLog returns the recorded console output of the VM, oldest first, together
+// with the offset in the console log the output has been read up to. The offset can be
+// passed to FollowConsoleLog to continue reading the output from there.
+func ReadConsoleLog(vm *api.VM) ([]ConsoleLine, int64, error) {
+	p := consoleLogPath(vm)
+	if _, err := os.Stat(p); os.IsNotExist(err) {
+		return nil, 0, fmt.Errorf("%w for VM %q", ErrNoConsoleLog, vm.GetUID())
+	}
+
+	return readConsoleLog(p)
+}
+
+// readConsoleLog reads the console log at p together with its rotated console logs
+func readConsoleLog(p string) ([]ConsoleLine, int64, error) {
+	var lines []ConsoleLine
+	collect := func(line ConsoleLine) error {
+		lines = append(lines, line)
+		return nil
+	}
+
+	// Read the rotated console logs from the oldest one
+	var rotated []string
+	for i := 1; ; i++ {
+		file, err := findRotated(p, i)
+		if err != nil {
+			break
+		}
+		rotated = append([]string{file}, rotated...)
+	}
+
+	for _, file := range rotated {
+		if _, err := readConsoleLines(file, 0, collect); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	offset, err := readConsoleLines(p, 0, collect)
+	return lines, offset, err
+}
+
+// readConsoleLines calls f for every complete line in the console log file after offset,
+// and returns the offset after the last complete line. The offset of a compressed console
+// log is an offset in its uncompressed content.
+func readConsoleLines(file string, offset int64, f func(ConsoleLine) error) (int64, error) {
+	fd, err := os.Open(file)
+	if err != nil {
+		return offset, err
+	}
+	defer fd.Close()
+
+	var r *bufio.Reader
+	if strings.HasSuffix(file, compressedSuffix) {
+		gz, err := gzip.NewReader(fd)
+		if err != nil {
+			return offset, err
+		}
+		defer gz.Close()
+
+		if _, err := io.CopyN(ioutil.Discard, gz, offset); err != nil {
+			return offset, err
+		}
+		r = bufio.NewReader(gz)
+	} else {
+		if _, err := fd.Seek(offset, io.SeekStart); err != nil {
+			return offset, err
+		}
+		r = bufio.NewReader(fd)
+	}
+
+	for {
+		s, err := r.ReadString('\n')
+		if err == io.EOF {
+			// An incomplete line is still being written, it's read the next time
+			return offset, nil
+		} else if err != nil {
+			return offset, err
+		}
+
+		offset += int64(len(s))
+		if err := f(parseConsoleLine(s)); err != nil {
+			return offset, err
+		}
+	}
+}
+
+// FollowConsoleLog calls f for every line written to the console log of the VM after
+// offset, following the log across rotations. It returns once ctx is done and the
+// output written up to that point has been read.
+func FollowConsoleLog(ctx context.Context, vm *api.VM, offset int64, f func(ConsoleLine) error) error {
+	p := consoleLogPath(vm)
+	stopping := false
+	for {
+		fi, err := os.Stat(p)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		// A log smaller than the offset has been rotated, finish reading the previous one
+		if err == nil && fi.Size() < offset {
+			if _, err := readConsoleLines(rotatedPath(p, 1), offset, f); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			offset = 0
+		}
+
+		// The log is missing briefly while it's being rotated
+		if err == nil {
+			if offset, err = readConsoleLines(p, offset, f); err != nil {
+				return err
+			}
+		}
+
+		if stopping {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			stopping = true
+		case <-time.After(consolePollInterval):
+		}
+	}
+}
+
+// parseConsoleLine parses a line of the console log, which has the "<RFC 3339 time> <text>" format
+func parseConsoleLine(s string) ConsoleLine {
+	s = strings.TrimSuffix(s, "\n")
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		if t, err := time.Parse(time.RFC3339Nano, s[:i]); err == nil {
+			return ConsoleLine{Time: t, Text: s[i+1:]}
+		}
+	}
+
+	return ConsoleLine{Text: s}
+}
+
+func consoleLogPath(vm *api.VM) string {
+	return path.Join(vm.ObjectPath(), constants.VM_CONSOLE_LOG_FILE)
+}
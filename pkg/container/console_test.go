@@ -0,0 +1,133 @@
+package container
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+func TestConsoleLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-console-log-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := &consoleLog{path: filepath.Join(dir, "console.log")}
+	assert.NilError(t, c.open())
+
+	// Lines are split across writes, and the incomplete last line is recorded on close
+	for _, s := range []string{"Booting ", "the kernel\r\n", "login: root\nWelcome\n", "$ "} {
+		n, err := c.Write([]byte(s))
+		assert.NilError(t, err)
+		assert.Equal(t, n, len(s))
+	}
+
+	var texts []string
+	collect := func(line ConsoleLine) error {
+		assert.Assert(t, !line.Time.IsZero())
+		texts = append(texts, line.Text)
+		return nil
+	}
+
+	offset, err := readConsoleLines(c.path, 0, collect)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, texts, []string{"Booting the kernel", "login: root", "Welcome"})
+
+	assert.NilError(t, c.Close())
+	texts = nil
+	_, err = readConsoleLines(c.path, offset, collect)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, texts, []string{"$ "})
+}
+
+func TestParseConsoleLine(t *testing.T) {
+	line := parseConsoleLine("2020-01-02T03:04:05.5Z hello world\n")
+	assert.Equal(t, line.Time.Format("15:04:05.0"), "03:04:05.5")
+	assert.Equal(t, line.Text, "hello world")
+
+	line = parseConsoleLine("not a timestamp\n")
+	assert.Assert(t, line.Time.IsZero())
+	assert.Equal(t, line.Text, "not a timestamp")
+}
+
+func TestConsoleLogRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-console-log-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Every line is 28 to 38 bytes long with its timestamp, rotate after every second line
+	c := &consoleLog{
+		path: filepath.Join(dir, "console.log"),
+		cfg: api.ConsoleLogConfiguration{
+			MaxSize:  meta.NewSizeFromBytes(40),
+			MaxFiles: 3,
+			Compress: true,
+		},
+	}
+	assert.NilError(t, c.open())
+
+	for i := 0; i < 10; i++ {
+		_, err := fmt.Fprintf(c, "line %d\n", i)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, c.Close())
+
+	// The most recent rotated log stays uncompressed, the ones beyond MaxFiles are removed
+	for _, file := range []string{"console.log", "console.log.1", "console.log.2.gz", "console.log.3.gz"} {
+		_, err := os.Stat(filepath.Join(dir, file))
+		assert.NilError(t, err, file)
+	}
+	for _, file := range []string{"console.log.2", "console.log.3", "console.log.4", "console.log.4.gz"} {
+		_, err := os.Stat(filepath.Join(dir, file))
+		assert.Assert(t, os.IsNotExist(err), file)
+	}
+
+	lines, _, err := readConsoleLog(c.path)
+	assert.NilError(t, err)
+	var texts []string
+	for _, line := range lines {
+		texts = append(texts, line.Text)
+	}
+	assert.DeepEqual(t, texts, []string{"line 4", "line 5", "line 6", "line 7", "line 8", "line 9"})
+}
+
+func TestConsoleLogRotationByAge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-console-log-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := &consoleLog{
+		path: filepath.Join(dir, "console.log"),
+		cfg: api.ConsoleLogConfiguration{
+			MaxSize:  meta.NewSizeFromBytes(1024 * 1024),
+			MaxAge:   metav1.Duration{Duration: time.Hour},
+			MaxFiles: 1,
+		},
+	}
+	assert.NilError(t, c.open())
+	defer c.Close()
+
+	now := time.Now()
+	c.writeLine(now, []byte("first"))
+	assert.Assert(t, !c.rotationDue(now.Add(59*time.Minute)))
+	assert.Assert(t, c.rotationDue(now.Add(time.Hour)))
+
+	// The age of a reopened log is the time of its first line
+	c.writeLine(now.Add(time.Hour), []byte("second"))
+	assert.NilError(t, c.Close())
+	assert.NilError(t, c.open())
+	assert.Assert(t, c.oldest.IsZero())
+
+	c.writeLine(now.Add(2*time.Hour), []byte("third"))
+	assert.NilError(t, c.Close())
+	assert.NilError(t, c.open())
+	assert.Equal(t, c.oldest.Unix(), now.Add(2*time.Hour).Unix())
+}
@@ -3,35 +3,39 @@ package container
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path"
+	"sort"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/firecracker-microvm/firecracker-go-sdk"
 	models "github.com/firecracker-microvm/firecracker-go-sdk/client/models"
 	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/agent"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/arch"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/logs"
+	"github.com/weaveworks/ignite/pkg/migration"
 	"github.com/weaveworks/ignite/pkg/util"
 )
 
-// ExecuteFirecracker executes the firecracker process using the Go SDK
-func ExecuteFirecracker(vm *api.VM, fcIfaces firecracker.NetworkInterfaces) (err error) {
-	drivePath := vm.SnapshotDev()
+// snapshotLoadTimeout bounds loading the guest memory of a migrated VM
+const snapshotLoadTimeout = 5 * time.Minute
 
+// ExecuteFirecracker executes the firecracker process using the Go SDK, booting the VM
+// with the given kernel command line from the block device at drivePath and recording
+// its console output as configured
+func ExecuteFirecracker(vm *api.VM, fcIfaces firecracker.NetworkInterfaces, cmdLine, drivePath string, consoleCfg api.ConsoleLogConfiguration) (err error) {
 	vCPUCount := int64(vm.Spec.CPUs)
 	memSizeMib := int64(vm.Spec.Memory.MBytes())
 
-	cmdLine := vm.Spec.Kernel.CmdLine
-	if len(cmdLine) == 0 {
-		// if for some reason cmdline would be unpopulated, set it to the default
-		cmdLine = constants.VM_DEFAULT_KERNEL_ARGS
-	}
-
 	// Convert the logrus error level to a Firecracker compatible error level.
 	// Firecracker accepts "Error", "Warning", "Info", and "Debug", case-sensitive.
 	fcLogLevel := "Debug"
@@ -51,17 +55,28 @@ func ExecuteFirecracker(vm *api.VM, fcIfaces firecracker.NetworkInterfaces) (err
 		SocketPath:      firecrackerSocketPath,
 		KernelImagePath: constants.IGNITE_SPAWN_VMLINUX_FILE_PATH,
 		KernelArgs:      cmdLine,
+		// Firecracker's virtio-block devices don't support discard, so fstrim fails inside the
+		// VM and freed space stays allocated on the host until the snapshotters reclaim it on
+		// the next start of the VM. dmlegacy overlays don't shrink at all.
 		Drives: []models.Drive{{
 			DriveID:      firecracker.String("1"),
-			IsReadOnly:   firecracker.Bool(false),
+			IsReadOnly:   firecracker.Bool(vm.Spec.Storage.ReadOnlyRoot), // Firecracker adds "ro" to the kernel args
 			IsRootDevice: firecracker.Bool(true),
 			PathOnHost:   &drivePath,
 		}},
 		NetworkInterfaces: fcIfaces,
+		// The vsock device is used to reach the guest agent. Its socket path is relative to
+		// the VM directory, which Firecracker runs in, so it doesn't change with the VM
+		// directory when the VM is restored from a snapshot on another host.
+		VsockDevices: []firecracker.VsockDevice{{
+			ID:   "vsock0",
+			Path: constants.FIRECRACKER_VSOCK_SOCKET,
+			CID:  constants.FIRECRACKER_VSOCK_CID,
+		}},
 		MachineCfg: models.MachineConfiguration{
 			VcpuCount:  &vCPUCount,
 			MemSizeMib: &memSizeMib,
-			HtEnabled:  firecracker.Bool(true),
+			HtEnabled:  firecracker.Bool(arch.Host.SMT),
 		},
 		//JailerCfg: firecracker.JailerConfig{
 		//	GID:      firecracker.Int(0),
@@ -78,7 +93,7 @@ func ExecuteFirecracker(vm *api.VM, fcIfaces firecracker.NetworkInterfaces) (err
 	}
 
 	//cfg.InitrdPath = constants.IGNITE_SPAWN_INITRD_FILE_PATH
-	if vm.Spec.Kernel.HasInitrd {
+	if vm.HasInitrd() {
 		cfg.InitrdPath = constants.IGNITE_SPAWN_INITRD_FILE_PATH
 	}
 
@@ -102,45 +117,146 @@ func ExecuteFirecracker(vm *api.VM, fcIfaces firecracker.NetworkInterfaces) (err
 	defer os.Remove(logSocketPath)
 	defer os.Remove(metricsSocketPath)
 
+	// Firecracker fails to create the vsock socket if it's left over from a previous run
+	vsockSocketPath := path.Join(vm.ObjectPath(), constants.FIRECRACKER_VSOCK_SOCKET)
+	if err := os.Remove(vsockSocketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	defer os.Remove(vsockSocketPath)
+
+	// Connect the console of the VM to the container's stdio, the console log and the console socket
+	stdio, err := openStdio(vm, consoleCfg)
+	if err != nil {
+		return err
+	}
+	defer util.DeferErr(&err, stdio.Close)
+
 	ctx, vmmCancel := context.WithCancel(context.Background())
 	defer vmmCancel()
 
 	cmd := firecracker.VMCommandBuilder{}.
 		WithBin("firecracker").
 		WithSocketPath(firecrackerSocketPath).
-		WithStdin(os.Stdin).
-		WithStdout(os.Stdout).
+		WithArgs(seccompArgs(vm.Spec.Seccomp)).
+		WithStdin(stdio.Stdin).
+		WithStdout(stdio.Stdout).
 		WithStderr(os.Stderr).
 		Build(ctx)
+	cmd.Dir = vm.ObjectPath()
 
 	m, err := firecracker.NewMachine(ctx, cfg, firecracker.WithProcessRunner(cmd))
 	if err != nil {
 		return fmt.Errorf("failed to create machine: %s", err)
 	}
 
+	// Collect the resource usage of the VM for "ignite vm stats"
+	stats := &statsCollector{vm: vm}
+	m.Handlers.FcInit = m.Handlers.FcInit.AppendAfter(firecracker.CreateMachineHandlerName, firecracker.Handler{
+		Name: "ignite.ConfigureBalloon",
+		Fn: func(context.Context, *firecracker.Machine) error {
+			return stats.configureBalloon()
+		},
+	})
+	defer os.Remove(path.Join(vm.ObjectPath(), constants.VM_STATS_FILE))
+
+	// Back the memory of the VM with hugepages, the Go SDK doesn't support them yet
+	if len(vm.Spec.MemoryHugepages) > 0 {
+		m.Handlers.FcInit = m.Handlers.FcInit.AppendAfter(firecracker.CreateMachineHandlerName, firecracker.Handler{
+			Name: "ignite.ConfigureHugepages",
+			Fn: func(context.Context, *firecracker.Machine) error {
+				return configureHugepages(vm)
+			},
+		})
+	}
+
 	//defer os.Remove(cfg.SocketPath)
 
 	//if opts.validMetadata != nil {
 	//	m.EnableMetadata(opts.validMetadata)
 	//}
 
-	if err = m.Start(ctx); err != nil {
+	if migration.Pending(vm) {
+		// The VM was migrated from another host, only start the VMM and restore its snapshot
+		if err = restoreMachine(ctx, vm, m); err != nil {
+			return fmt.Errorf("failed to restore machine: %v", err)
+		}
+
+		// The balloon device is restored from the snapshot, if the VM had one
+		stats.balloon = true
+	} else if err = m.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start machine: %v", err)
 	}
 	defer util.DeferErr(&err, m.StopVMM)
 
-	installSignalHandlers(ctx, m)
+	go stats.run(ctx)
+
+	installSignalHandlers(ctx, vm, m)
 
 	// wait for the VMM to exit
 	if err = m.Wait(ctx); err != nil {
+		if cmd.ProcessState != nil && !cmd.ProcessState.Success() {
+			return &ExitError{ExitCode: exitCode(cmd.ProcessState), err: err}
+		}
+
 		return fmt.Errorf("wait returned an error %s", err)
 	}
 
 	return
 }
 
+// ExitError is returned by ExecuteFirecracker and ExecuteQEMU when the VMM process exits unsuccessfully
+type ExitError struct {
+	ExitCode int
+	err      error
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("the VMM exited with code %d: %v", e.ExitCode, e.err)
+}
+
+// exitCode returns the exit code of the process, following the shell
+// convention of 128 + the signal number for processes killed by a signal
+func exitCode(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+
+	return state.ExitCode()
+}
+
+// restoreMachine starts the VMM, and loads the snapshot of the migration of the VM into it.
+// The snapshot contains the configuration of the VM, so the configuration handlers are skipped.
+// The TAP devices recorded in the snapshot have been recreated by the network setup, and
+// ignite exposes the root drive at the path recorded in the snapshot.
+func restoreMachine(ctx context.Context, vm *api.VM, m *firecracker.Machine) error {
+	m.Handlers.FcInit = firecracker.HandlerList{}.Append(
+		firecracker.StartVMMHandler,
+		firecracker.CreateLogFilesHandler,
+		firecracker.BootstrapLoggingHandler,
+	)
+
+	if err := m.Handlers.Run(ctx, m); err != nil {
+		return err
+	}
+
+	if err := FirecrackerRequest(vm, http.MethodPut, "/snapshot/load", map[string]interface{}{
+		"snapshot_path": migration.File(vm, migration.StateFile),
+		"mem_file_path": migration.File(vm, migration.MemoryFile),
+		"resume_vm":     true,
+	}, snapshotLoadTimeout); err != nil {
+		if stopErr := m.StopVMM(); stopErr != nil {
+			log.Errorf("VMM stop failed with error: %v", stopErr)
+		}
+		return err
+	}
+
+	// Remove the snapshot, so the next start boots the VM again. Firecracker keeps
+	// the memory file mapped as long as it's running.
+	return migration.Remove(vm)
+}
+
 // Install custom signal handlers:
-func installSignalHandlers(ctx context.Context, m *firecracker.Machine) {
+func installSignalHandlers(ctx context.Context, vm *api.VM, m *firecracker.Machine) {
 	go func() {
 		// Clear some default handlers installed by the firecracker SDK:
 		signal.Reset(os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
@@ -151,14 +267,15 @@ func installSignalHandlers(ctx context.Context, m *firecracker.Machine) {
 			switch s := <-c; {
 			case s == syscall.SIGTERM || s == os.Interrupt:
 				fmt.Println("Caught SIGTERM, requesting clean shutdown")
-				if err := m.Shutdown(ctx); err != nil {
+				if err := shutdown(ctx, vm, m); err != nil {
 					log.Errorf("Machine shutdown failed with error: %v", err)
 				}
 				time.Sleep(constants.STOP_TIMEOUT * time.Second)
 
 				// There's no direct way of checking if a VM is running, so we test if we can send it another shutdown
-				// request. If that fails, the VM is still running and we need to kill it.
-				if err := m.Shutdown(ctx); err == nil {
+				// request. If that fails, the VM is still running and we need to kill it. Without Ctrl+Alt+Del, the
+				// VM is still running as this process didn't exit with it.
+				if !arch.Host.CtrlAltDel || m.Shutdown(ctx) == nil {
 					fmt.Println("Timeout exceeded, forcing shutdown") // TODO: Proper logging
 					if err := m.StopVMM(); err != nil {
 						log.Errorf("VMM stop failed with error: %v", err)
@@ -173,3 +290,119 @@ func installSignalHandlers(ctx context.Context, m *firecracker.Machine) {
 		}
 	}()
 }
+
+// shutdown requests the VM to shut down. Firecracker sends Ctrl+Alt+Del to the guest where
+// it has a keyboard controller, e.g. not on arm64, where the guest agent is asked instead.
+// VMs without the agent are stopped after the timeout there.
+func shutdown(ctx context.Context, vm *api.VM, m *firecracker.Machine) error {
+	if arch.Host.CtrlAltDel {
+		return m.Shutdown(ctx)
+	}
+
+	if err := agent.Shutdown(vm); err != nil {
+		return fmt.Errorf("the VM shuts down cleanly only through its guest agent on %s: %v", arch.Host.Name, err)
+	}
+
+	return nil
+}
+
+// KernelCmdLine returns the kernel command line to boot the VM with. Its variables are
+// resolved with the addresses served over DHCP, and the sysctls of the VM are added.
+func KernelCmdLine(vm *api.VM, dhcpIfaces []DHCPInterface) (string, error) {
+	cmdLine := vm.Spec.Kernel.CmdLine
+	if len(cmdLine) == 0 {
+		// if for some reason cmdline would be unpopulated, set it to the default
+		cmdLine = arch.Host.KernelArgs
+	}
+
+	cmdLine, err := api.ExpandKernelCmdLine(cmdLine, kernelCmdLineVars(vm, dhcpIfaces))
+	if err != nil {
+		return "", err
+	}
+
+	return addSysctlKernelArgs(cmdLine, vm.Spec.Sysctls), nil
+}
+
+// kernelCmdLineVars returns the values of the kernel command line variables of the VM.
+// The address variables are those of the first interface served over DHCP, normally
+// eth0, and are left unset if there's none. The built-in variables take precedence
+// over the custom ones.
+func kernelCmdLineVars(vm *api.VM, dhcpIfaces []DHCPInterface) map[string]string {
+	vars := vm.KernelCmdLineVars()
+	vars["VM_UID"] = vm.GetUID().String()
+	vars["VM_NAME"] = vm.GetName()
+	// The DHCP server sets the hostname of the VM to its UID
+	vars["VM_HOSTNAME"] = vm.GetUID().String()
+
+	if len(dhcpIfaces) > 0 && dhcpIfaces[0].VMIPNet != nil {
+		ipNet := dhcpIfaces[0].VMIPNet
+		mask := ipNet.Mask
+		if ipNet.IP.To4() != nil && len(mask) == net.IPv6len {
+			mask = mask[12:]
+		}
+
+		vars["VM_IP"] = ipNet.IP.String()
+		vars["VM_NETMASK"] = net.IP(mask).String()
+		if gw := dhcpIfaces[0].GatewayIP; gw != nil {
+			vars["VM_GATEWAY"] = gw.String()
+		}
+	}
+
+	return vars
+}
+
+// addSysctlKernelArgs adds the sysctls to the kernel command line as sysctl.<name>=<value>
+// arguments, which the guest kernel applies at boot. They're added before the arguments
+// for init, which follow "--".
+func addSysctlKernelArgs(cmdLine string, sysctls map[string]string) string {
+	if len(sysctls) == 0 {
+		return cmdLine
+	}
+
+	names := make([]string, 0, len(sysctls))
+	for name := range sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		value := sysctls[name]
+		// Values with spaces, like net.ipv4.ping_group_range, need to be quoted
+		if strings.ContainsAny(value, " \t") {
+			value = `"` + value + `"`
+		}
+
+		args = append(args, fmt.Sprintf("sysctl.%s=%s", name, value))
+	}
+
+	return insertKernelArgs(cmdLine, args...)
+}
+
+// insertKernelArgs adds the arguments to the kernel command line, before the arguments for
+// init, which follow "--"
+func insertKernelArgs(cmdLine string, args ...string) string {
+	kernelArgs, initArgs := cmdLine, ""
+	if i := strings.Index(cmdLine, " -- "); i >= 0 {
+		kernelArgs, initArgs = cmdLine[:i], cmdLine[i:]
+	}
+
+	return fmt.Sprintf("%s %s%s", kernelArgs, strings.Join(args, " "), initArgs)
+}
+
+// firecrackerHugepages maps the hugepage sizes to their names in the Firecracker API
+var firecrackerHugepages = map[api.HugepageSize]string{
+	api.HugepageSize2Mi: "2M",
+	api.HugepageSize1Gi: "1G",
+}
+
+// configureHugepages makes Firecracker back the memory of the VM with hugepages. It's
+// applied on top of the machine configuration set by the Go SDK.
+func configureHugepages(vm *api.VM) error {
+	hugepages := map[string]string{"huge_pages": firecrackerHugepages[vm.Spec.MemoryHugepages]}
+	if err := FirecrackerRequest(vm, http.MethodPatch, "/machine-config", hugepages, 0); err != nil {
+		return fmt.Errorf("failed to configure %s hugepages: %v", vm.Spec.MemoryHugepages, err)
+	}
+
+	return nil
+}
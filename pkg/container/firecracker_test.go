@@ -0,0 +1,177 @@
+package container
+
+import (
+	"net"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/arch"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+func TestAddSysctlKernelArgs(t *testing.T) {
+	cases := []struct {
+		name    string
+		cmdLine string
+		sysctls map[string]string
+		want    string
+	}{
+		{
+			name:    "no sysctls",
+			cmdLine: "console=ttyS0 reboot=k",
+			want:    "console=ttyS0 reboot=k",
+		},
+		{
+			name:    "sorted by name",
+			cmdLine: "console=ttyS0 reboot=k",
+			sysctls: map[string]string{
+				"vm.max_map_count":            "262144",
+				"fs.inotify.max_user_watches": "524288",
+			},
+			want: "console=ttyS0 reboot=k sysctl.fs.inotify.max_user_watches=524288 sysctl.vm.max_map_count=262144",
+		},
+		{
+			name:    "value with spaces",
+			cmdLine: "console=ttyS0",
+			sysctls: map[string]string{
+				"net.ipv4.ping_group_range": "0 2147483647",
+			},
+			want: `console=ttyS0 sysctl.net.ipv4.ping_group_range="0 2147483647"`,
+		},
+		{
+			name:    "before the init arguments",
+			cmdLine: "console=ttyS0 -- single",
+			sysctls: map[string]string{
+				"net.ipv4.ip_forward": "1",
+			},
+			want: "console=ttyS0 sysctl.net.ipv4.ip_forward=1 -- single",
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.Equal(t, addSysctlKernelArgs(rt.cmdLine, rt.sysctls), rt.want)
+		})
+	}
+}
+
+func TestKernelCmdLine(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("10.61.0.2/16")
+	if err != nil {
+		t.Fatalf("error parsing CIDR: %v", err)
+	}
+	ipNet.IP = net.ParseIP("10.61.0.2")
+	gw := net.ParseIP("10.61.0.1")
+	dhcpIfaces := []DHCPInterface{{VMIPNet: ipNet, GatewayIP: &gw}}
+
+	cases := []struct {
+		name        string
+		cmdLine     string
+		annotations map[string]string
+		dhcpIfaces  []DHCPInterface
+		want        string
+		err         bool
+	}{
+		{
+			name: "default",
+			want: arch.Host.KernelArgs,
+		},
+		{
+			name:       "built-in variables",
+			cmdLine:    "console=ttyS0 ip=${VM_IP}::${VM_GATEWAY}:${VM_NETMASK}:${VM_HOSTNAME}:eth0:off vm=${VM_NAME}",
+			dhcpIfaces: dhcpIfaces,
+			want:       "console=ttyS0 ip=10.61.0.2::10.61.0.1:255.255.0.0:0123456789abcdef:eth0:off vm=my-vm",
+		},
+		{
+			name:    "custom variables",
+			cmdLine: "console=ttyS0 -- --region ${REGION}",
+			annotations: map[string]string{
+				constants.IGNITE_KERNEL_CMDLINE_VAR_ANNOTATION + "REGION": "eu-west-1",
+			},
+			want: "console=ttyS0 -- --region eu-west-1",
+		},
+		{
+			name:    "built-in variables take precedence",
+			cmdLine: "console=ttyS0 uid=${VM_UID}",
+			annotations: map[string]string{
+				constants.IGNITE_KERNEL_CMDLINE_VAR_ANNOTATION + "VM_UID": "overridden",
+			},
+			want: "console=ttyS0 uid=0123456789abcdef",
+		},
+		{
+			name:    "no DHCP interface",
+			cmdLine: "console=ttyS0 ip=${VM_IP}",
+			err:     true,
+		},
+		{
+			name:    "undefined variables are passed on",
+			cmdLine: "console=ttyS0 -- sh -c 'echo ${HOME} ${PATH}'",
+			want:    "console=ttyS0 -- sh -c 'echo ${HOME} ${PATH}'",
+		},
+		{
+			name:    "escaped variables",
+			cmdLine: "console=ttyS0 -- echo $${VM_UID} ${VM_UID}",
+			want:    "console=ttyS0 -- echo ${VM_UID} 0123456789abcdef",
+		},
+		{
+			name:    "undefined variable with the reserved prefix",
+			cmdLine: "console=ttyS0 ${VM_FOO}",
+			err:     true,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			vm := &api.VM{}
+			vm.SetName("my-vm")
+			vm.SetUID("0123456789abcdef")
+			vm.Spec.Kernel.CmdLine = rt.cmdLine
+			vm.GetObjectMeta().Annotations = rt.annotations
+
+			cmdLine, err := KernelCmdLine(vm, rt.dhcpIfaces)
+			if (err != nil) != rt.err {
+				t.Fatalf("expected error %t, actual: %v", rt.err, err)
+			}
+			assert.Equal(t, cmdLine, rt.want)
+		})
+	}
+}
+
+func TestSeccompArgs(t *testing.T) {
+	cases := []struct {
+		name    string
+		seccomp *api.VMSeccompSpec
+		want    []string
+	}{
+		{
+			name: "Firecracker's default",
+		},
+		{
+			name:    "empty",
+			seccomp: &api.VMSeccompSpec{},
+		},
+		{
+			name:    "basic level",
+			seccomp: &api.VMSeccompSpec{Level: api.SeccompLevelBasic},
+			want:    []string{"--seccomp-level", "1"},
+		},
+		{
+			name:    "disabled",
+			seccomp: &api.VMSeccompSpec{Level: api.SeccompLevelDisabled},
+			want:    []string{"--seccomp-level", "0"},
+		},
+		{
+			name:    "custom filter",
+			seccomp: &api.VMSeccompSpec{Filter: "/etc/ignite/seccomp.bpf"},
+			want:    []string{"--seccomp-filter", constants.IGNITE_SPAWN_SECCOMP_FILTER_FILE_PATH},
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.DeepEqual(t, seccompArgs(rt.seccomp), rt.want)
+		})
+	}
+}
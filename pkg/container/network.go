@@ -14,6 +14,7 @@ import (
 	"github.com/vishvananda/netlink"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/migration"
 	"github.com/weaveworks/ignite/pkg/util"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
@@ -90,9 +91,33 @@ func SetupContainerNetworking(vm *api.VM) (firecracker.NetworkInterfaces, []DHCP
 		return nil, nil, err
 	}
 
+	if err := keepRestoredMAC(vm, dhcpIntfs); err != nil {
+		return nil, nil, err
+	}
+
 	return fcIntfs, dhcpIntfs, nil
 }
 
+// keepRestoredMAC serves the addresses of the main interface to the MAC address of the
+// guest in the snapshot the VM is restored from, if it's recorded. The guest keeps the
+// address it had when it was snapshotted, instead of the one generated for this run.
+func keepRestoredMAC(vm *api.VM, dhcpIntfs []DHCPInterface) error {
+	if !migration.Pending(vm) || len(dhcpIntfs) == 0 {
+		return nil
+	}
+
+	manifest, err := migration.ReadManifest(vm)
+	if err != nil {
+		return err
+	}
+
+	if len(manifest.MACAddress) > 0 {
+		dhcpIntfs[0].MACFilter = manifest.MACAddress
+	}
+
+	return nil
+}
+
 func collectInterfaces(vmIntfs map[string]string) (bool, error) {
 	allIntfs, err := net.Interfaces()
 	if err != nil || allIntfs == nil || len(allIntfs) == 0 {
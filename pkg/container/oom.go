@@ -0,0 +1,51 @@
+package container
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The files of the memory cgroup of the container counting its OOM kills, for cgroup v2
+// and v1 respectively
+var oomFiles = []string{
+	"/sys/fs/cgroup/memory.events",
+	"/sys/fs/cgroup/memory/memory.oom_control",
+}
+
+// OOMKills returns how many processes of the container the kernel killed for running out
+// of memory, zero if the memory cgroup doesn't count them
+func OOMKills() uint64 {
+	for _, file := range oomFiles {
+		f, err := os.Open(file)
+		if err != nil {
+			continue
+		}
+
+		kills, ok := parseOOMKills(f)
+		f.Close()
+		if ok {
+			return kills
+		}
+	}
+
+	return 0
+}
+
+// parseOOMKills returns the oom_kill counter of memory.events or memory.oom_control
+func parseOOMKills(r io.Reader) (uint64, bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[0] != "oom_kill" {
+			continue
+		}
+
+		kills, err := strconv.ParseUint(fields[1], 10, 64)
+		return kills, err == nil
+	}
+
+	return 0, false
+}
@@ -0,0 +1,30 @@
+package container
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseOOMKills(t *testing.T) {
+	cases := []struct {
+		name  string
+		file  string
+		kills uint64
+		ok    bool
+	}{
+		{"cgroup v2", "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n", 1, true},
+		{"cgroup v1", "oom_kill_disable 0\nunder_oom 0\noom_kill 2\n", 2, true},
+		{"no counter", "oom_kill_disable 0\nunder_oom 0\n", 0, false},
+		{"invalid counter", "oom_kill many\n", 0, false},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			kills, ok := parseOOMKills(strings.NewReader(rt.file))
+			assert.Equal(t, kills, rt.kills)
+			assert.Equal(t, ok, rt.ok)
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/firecracker-microvm/firecracker-go-sdk"
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/arch"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// qmpTimeout bounds the commands sent to QEMU over its QMP socket
+const qmpTimeout = 5 * time.Second
+
+// ExecuteQEMU runs the VM with QEMU. If emulate is set, the VM is emulated with QEMU's TCG
+// emulation for hosts without KVM, it boots like with ExecuteFirecracker, but a lot slower.
+// VMs of disk images are booted with UEFI firmware from the bootloader in their disk, with
+// KVM unless they're emulated, and VMs with host PCI devices pass them through with VFIO.
+// The features of the Firecracker API, e.g. the guest agent, the balloon statistics and
+// hugepages, aren't available.
+func ExecuteQEMU(vm *api.VM, fcIfaces firecracker.NetworkInterfaces, cmdLine, drivePath string, consoleCfg api.ConsoleLogConfiguration, emulate bool) (err error) {
+	if len(vm.Spec.MemoryHugepages) > 0 {
+		log.Warnf("The memory of QEMU VM %q isn't backed by hugepages", vm.GetUID())
+	}
+
+	qmpSocketPath := path.Join(vm.ObjectPath(), constants.QEMU_QMP_SOCKET)
+	if err := os.Remove(qmpSocketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	defer os.Remove(qmpSocketPath)
+
+	// Connect the console of the VM to the container's stdio, the console log and the console socket
+	stdio, err := openStdio(vm, consoleCfg)
+	if err != nil {
+		return err
+	}
+	defer util.DeferErr(&err, stdio.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if emulate {
+		log.Warnf("Emulating VM %q with %s, it runs a lot slower than with KVM", vm.GetUID(), arch.Host.QEMUBinary)
+	}
+	cmd := exec.CommandContext(ctx, arch.Host.QEMUBinary, qemuArgs(vm, fcIfaces, cmdLine, drivePath, qmpSocketPath, emulate)...)
+	cmd.Dir = vm.ObjectPath()
+	cmd.Stdin = stdio.Stdin
+	cmd.Stdout = stdio.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start QEMU: %v", err)
+	}
+
+	installQEMUSignalHandlers(ctx, cmd, qmpSocketPath)
+
+	if err = cmd.Wait(); err != nil {
+		if cmd.ProcessState != nil && !cmd.ProcessState.Success() {
+			return &ExitError{ExitCode: exitCode(cmd.ProcessState), err: err}
+		}
+
+		return fmt.Errorf("wait returned an error %s", err)
+	}
+
+	return
+}
+
+// qemuArgs returns the arguments of QEMU running the VM, emulated if emulate is set. The
+// machine has virtio-mmio devices like Firecracker, so the same kernel command line and
+// devices are used. QEMU plugs the devices into the virtio-mmio transports from the
+// highest address down, and the guest enumerates them from the lowest, so they're added
+// in reverse for the root drive to be /dev/vda and the first interface eth0. VMs of disk
+// images boot with UEFI firmware on a machine with PCI devices instead, enumerated in order.
+// The host PCI devices of the VM are passed through with vfio-pci.
+func qemuArgs(vm *api.VM, fcIfaces firecracker.NetworkInterfaces, cmdLine, drivePath, qmpSocketPath string, emulate bool) []string {
+	// Discards of the guest, e.g. by fstrim, are passed through to free the space on the host,
+	// and writes of zeroes are turned into discards
+	const discard = ",discard=unmap,detect-zeroes=unmap"
+	rootOptions := discard
+	if vm.Spec.Storage.ReadOnlyRoot {
+		rootOptions = ",readonly=on"
+	}
+
+	machine, accel, cpu := arch.Host.QEMUMachine, "kvm", arch.Host.QEMUHostCPU
+	if vm.Spec.NestedVirtualization {
+		// The host CPU model exposes VMX or SVM if KVM on the host supports nested virtualization
+		cpu = "host"
+	}
+	if vm.DiskImage() {
+		machine = arch.Host.QEMUFirmwareMachine
+	} else if vm.HasDevices() {
+		machine = arch.Host.QEMUPCIMachine
+		// The guest needs to scan the PCI bus for the devices
+		cmdLine = removeKernelArg(cmdLine, "pci=off")
+	}
+	if emulate {
+		accel, cpu = "tcg", "max"
+	}
+
+	args := []string{
+		"-machine", machine,
+		"-accel", accel,
+		"-cpu", cpu,
+		"-smp", strconv.FormatUint(vm.Spec.CPUs, 10),
+		"-m", fmt.Sprintf("%dM", int64(vm.Spec.Memory.MBytes())),
+		"-nodefaults",
+		"-no-user-config",
+		"-no-reboot",
+		"-display", "none",
+		"-serial", "stdio",
+		"-qmp", fmt.Sprintf("unix:%s,server=on,wait=off", qmpSocketPath),
+	}
+
+	transport := "device"
+	if vm.DiskImage() {
+		// The bootloader in the disk boots its own kernel
+		transport = "pci"
+		args = append(args, "-bios", qemuFirmware())
+	} else {
+		rootMode := "rw"
+		if vm.Spec.Storage.ReadOnlyRoot {
+			rootMode = "ro"
+		}
+
+		kernelArgs := []string{"root=/dev/vda", rootMode}
+		if len(arch.Host.QEMUKernelArgs) > 0 {
+			kernelArgs = append(kernelArgs, arch.Host.QEMUKernelArgs)
+		}
+
+		args = append(args,
+			"-kernel", constants.IGNITE_SPAWN_VMLINUX_FILE_PATH,
+			"-append", insertKernelArgs(cmdLine, kernelArgs...),
+		)
+
+		if vm.HasInitrd() {
+			args = append(args, "-initrd", constants.IGNITE_SPAWN_INITRD_FILE_PATH)
+		}
+	}
+
+	// The devices in the order the guest enumerates them
+	type device struct {
+		option, backend, frontend string
+	}
+	devices := []device{{
+		option:   "-drive",
+		backend:  fmt.Sprintf("file=%s,format=raw,if=none,id=drive1%s", drivePath, rootOptions),
+		frontend: fmt.Sprintf("virtio-blk-%s,drive=drive1", transport),
+	}}
+
+	for i, volume := range vm.Spec.Storage.Volumes {
+		volumePath := path.Join(constants.IGNITE_SPAWN_VOLUME_DIR, volume.Name)
+		if !util.FileExists(volumePath) {
+			log.Warnf("Skipping nonexistent volume: %q", volume.Name)
+			continue // Skip all nonexistent volumes
+		}
+
+		id := fmt.Sprintf("drive%d", i+2)
+		devices = append(devices, device{
+			option:   "-drive",
+			backend:  fmt.Sprintf("file=%s,format=raw,if=none,id=%s%s", volumePath, id, discard),
+			frontend: fmt.Sprintf("virtio-blk-%s,drive=%s", transport, id),
+		})
+	}
+
+	for i, iface := range fcIfaces {
+		if iface.StaticConfiguration == nil {
+			continue
+		}
+
+		id := fmt.Sprintf("net%d", i)
+		devices = append(devices, device{
+			option:   "-netdev",
+			backend:  fmt.Sprintf("tap,id=%s,ifname=%s,script=no,downscript=no", id, iface.StaticConfiguration.HostDevName),
+			frontend: fmt.Sprintf("virtio-net-%s,netdev=%s,mac=%s", transport, id, iface.StaticConfiguration.MacAddress),
+		})
+	}
+
+	// The backends are defined first, the devices refer to them
+	for _, d := range devices {
+		args = append(args, d.option, d.backend)
+	}
+	if vm.DiskImage() {
+		for _, d := range devices {
+			args = append(args, "-device", d.frontend)
+		}
+	} else {
+		for i := len(devices) - 1; i >= 0; i-- {
+			args = append(args, "-device", devices[i].frontend)
+		}
+	}
+
+	// The host PCI devices are plugged into the PCI bus of the machine
+	for _, d := range vm.Spec.Devices {
+		args = append(args, "-device", fmt.Sprintf("vfio-pci,host=%s", d.Address))
+	}
+
+	return args
+}
+
+// removeKernelArg removes the argument from the kernel arguments of the command line,
+// the init arguments after -- are kept
+func removeKernelArg(cmdLine, arg string) string {
+	kernelArgs, initArgs := cmdLine, ""
+	if i := strings.Index(cmdLine, " -- "); i >= 0 {
+		kernelArgs, initArgs = cmdLine[:i], cmdLine[i:]
+	}
+
+	fields := strings.Fields(kernelArgs)
+	kept := fields[:0]
+	for _, field := range fields {
+		if field != arg {
+			kept = append(kept, field)
+		}
+	}
+
+	return strings.Join(kept, " ") + initArgs
+}
+
+// qemuFirmware returns the UEFI firmware of the VM mounted into the container, or the
+// default firmware of the sandbox
+func qemuFirmware() string {
+	if util.FileExists(constants.IGNITE_SPAWN_FIRMWARE_FILE_PATH) {
+		return constants.IGNITE_SPAWN_FIRMWARE_FILE_PATH
+	}
+
+	return arch.Host.QEMUFirmware
+}
+
+// installQEMUSignalHandlers shuts the VM down when the container is stopped, like
+// installSignalHandlers does for Firecracker. QEMU presses the power button of the VM.
+func installQEMUSignalHandlers(ctx context.Context, cmd *exec.Cmd, qmpSocketPath string) {
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+
+		kill := func() {
+			if err := cmd.Process.Kill(); err != nil {
+				log.Errorf("QEMU kill failed with error: %v", err)
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s := <-c:
+				switch s {
+				case syscall.SIGTERM, os.Interrupt:
+					fmt.Println("Caught SIGTERM, requesting clean shutdown")
+					if err := qmpCommand(qmpSocketPath, "system_powerdown"); err != nil {
+						log.Errorf("Machine shutdown failed with error: %v", err)
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(constants.STOP_TIMEOUT * time.Second):
+						fmt.Println("Timeout exceeded, forcing shutdown")
+						kill()
+					}
+				case syscall.SIGQUIT:
+					fmt.Println("Caught SIGQUIT, forcing shutdown")
+					kill()
+				}
+			}
+		}
+	}()
+}
+
+// qmpResponse is a reply or an event of the QEMU Machine Protocol
+type qmpResponse struct {
+	Return json.RawMessage `json:"return"`
+	Error  *struct {
+		Class string `json:"class"`
+		Desc  string `json:"desc"`
+	} `json:"error"`
+	Event string `json:"event"`
+}
+
+// qmpCommand executes the command without arguments over the QMP socket of QEMU, after
+// negotiating the capabilities of the connection
+func qmpCommand(socketPath, command string) error {
+	conn, err := net.DialTimeout("unix", socketPath, qmpTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(qmpTimeout)); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+
+	// QEMU greets the client first
+	greeting := map[string]interface{}{}
+	if err := dec.Decode(&greeting); err != nil {
+		return fmt.Errorf("failed to read the QMP greeting: %v", err)
+	}
+
+	for _, execute := range []string{"qmp_capabilities", command} {
+		if err := enc.Encode(map[string]string{"execute": execute}); err != nil {
+			return err
+		}
+
+		for {
+			resp := &qmpResponse{}
+			if err := dec.Decode(resp); err != nil {
+				return fmt.Errorf("failed to read the QMP reply to %s: %v", execute, err)
+			}
+
+			// Skip the events QEMU sends in between
+			if len(resp.Event) > 0 {
+				continue
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("QMP command %s failed: %s: %s", execute, resp.Error.Class, resp.Error.Desc)
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package container
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/firecracker-microvm/firecracker-go-sdk"
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/arch"
+)
+
+func TestQEMUArgs(t *testing.T) {
+	host := arch.Host
+	defer func() { arch.Host = host }()
+	arch.Host = arch.AMD64
+
+	vm := &api.VM{}
+	vm.Spec.CPUs = 2
+	vm.Spec.Memory = meta.NewSizeFromBytes(512 * 1024 * 1024)
+	vm.Spec.Storage.ReadOnlyRoot = true
+	ifaces := firecracker.NetworkInterfaces{{
+		StaticConfiguration: &firecracker.StaticNetworkConfiguration{
+			MacAddress:  "02:00:00:00:00:01",
+			HostDevName: "vm_eth0",
+		},
+	}}
+
+	args := strings.Join(qemuArgs(vm, ifaces, "console=ttyS0 -- single", "/dev/ignite-1", "/vm/qemu.sock", true), " ")
+	for _, arg := range []string{
+		"-machine microvm -accel tcg -cpu max",
+		"-smp 2 -m 512M",
+		"-qmp unix:/vm/qemu.sock,server=on,wait=off",
+		"-append console=ttyS0 root=/dev/vda ro -- single",
+		"-drive file=/dev/ignite-1,format=raw,if=none,id=drive1,readonly=on",
+		"-netdev tap,id=net0,ifname=vm_eth0,script=no,downscript=no",
+		// The devices are added in reverse, the guest enumerates them from the last one
+		"-device virtio-net-device,netdev=net0,mac=02:00:00:00:00:01 -device virtio-blk-device,drive=drive1",
+	} {
+		assert.Assert(t, strings.Contains(args, arg), "%q not in %q", arg, args)
+	}
+
+	// The console of the arm64 virt machine is another UART
+	arch.Host = arch.ARM64
+	args = strings.Join(qemuArgs(vm, nil, "console=ttyS0", "/dev/ignite-1", "/vm/qemu.sock", true), " ")
+	assert.Assert(t, strings.Contains(args, "-machine virt"))
+	assert.Assert(t, strings.Contains(args, "-append console=ttyS0 root=/dev/vda ro console=ttyAMA0"))
+}
+
+func TestQEMUFirmwareArgs(t *testing.T) {
+	host := arch.Host
+	defer func() { arch.Host = host }()
+	arch.Host = arch.AMD64
+
+	vm := &api.VM{}
+	vm.Spec.CPUs = 1
+	vm.Spec.Memory = meta.NewSizeFromBytes(1024 * 1024 * 1024)
+	vm.Status.Image.Format = api.ImageFormatDisk
+	ifaces := firecracker.NetworkInterfaces{{
+		StaticConfiguration: &firecracker.StaticNetworkConfiguration{
+			MacAddress:  "02:00:00:00:00:01",
+			HostDevName: "vm_eth0",
+		},
+	}}
+
+	args := strings.Join(qemuArgs(vm, ifaces, "console=ttyS0", "/dev/ignite-1", "/vm/qemu.sock", false), " ")
+	for _, arg := range []string{
+		"-machine q35 -accel kvm -cpu host",
+		"-bios /usr/share/OVMF/OVMF.fd",
+		"-drive file=/dev/ignite-1,format=raw,if=none,id=drive1,discard=unmap,detect-zeroes=unmap ",
+		// PCI devices are enumerated in order
+		"-device virtio-blk-pci,drive=drive1 -device virtio-net-pci,netdev=net0,mac=02:00:00:00:00:01",
+	} {
+		assert.Assert(t, strings.Contains(args, arg), "%q not in %q", arg, args)
+	}
+
+	// The bootloader in the disk boots its own kernel
+	for _, arg := range []string{"-kernel", "-append", "-initrd"} {
+		assert.Assert(t, !strings.Contains(args, arg), "%q in %q", arg, args)
+	}
+}
+
+func TestQEMUDeviceArgs(t *testing.T) {
+	host := arch.Host
+	defer func() { arch.Host = host }()
+	arch.Host = arch.AMD64
+
+	vm := &api.VM{}
+	vm.Spec.CPUs = 1
+	vm.Spec.Memory = meta.NewSizeFromBytes(1024 * 1024 * 1024)
+	vm.Spec.Devices = []api.PCIDevice{{Address: "0000:01:00.0"}, {Address: "0000:01:00.1"}}
+
+	args := strings.Join(qemuArgs(vm, nil, "console=ttyS0 pci=off ip=dhcp -- pci=off", "/dev/ignite-1", "/vm/qemu.sock", false), " ")
+	for _, arg := range []string{
+		"-machine microvm,pcie=on -accel kvm -cpu host",
+		// The guest scans the PCI bus, the init arguments are kept as they are
+		"-append console=ttyS0 ip=dhcp root=/dev/vda rw -- pci=off",
+		"-device virtio-blk-device,drive=drive1 -device vfio-pci,host=0000:01:00.0 -device vfio-pci,host=0000:01:00.1",
+	} {
+		assert.Assert(t, strings.Contains(args, arg), "%q not in %q", arg, args)
+	}
+}
+
+func TestQEMUNestedArgs(t *testing.T) {
+	host := arch.Host
+	defer func() { arch.Host = host }()
+	arch.Host = arch.AMD64
+
+	vm := &api.VM{}
+	vm.Spec.CPUs = 2
+	vm.Spec.Memory = meta.NewSizeFromBytes(4 * 1024 * 1024 * 1024)
+	vm.Status.Image.Format = api.ImageFormatDisk
+
+	// The virtualization extensions are hidden from the VMs without nested virtualization
+	args := strings.Join(qemuArgs(vm, nil, "console=ttyS0", "/dev/ignite-1", "/vm/qemu.sock", false), " ")
+	assert.Assert(t, strings.Contains(args, "-cpu host,vmx=off,svm=off -smp 2"), args)
+
+	vm.Spec.NestedVirtualization = true
+	args = strings.Join(qemuArgs(vm, nil, "console=ttyS0", "/dev/ignite-1", "/vm/qemu.sock", false), " ")
+	assert.Assert(t, strings.Contains(args, "-accel kvm -cpu host -smp 2"), args)
+}
+
+func TestQMPCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-qemu-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := path.Join(dir, "qemu.sock")
+	l, err := net.Listen("unix", socket)
+	assert.NilError(t, err)
+	defer l.Close()
+
+	executed := make(chan []string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var commands []string
+		r := bufio.NewReader(conn)
+		_, _ = conn.Write([]byte(`{"QMP": {"version": {}, "capabilities": []}}` + "\n"))
+		for i := 0; i < 2; i++ {
+			req := map[string]string{}
+			line, err := r.ReadBytes('\n')
+			if err != nil || json.Unmarshal(line, &req) != nil {
+				break
+			}
+			commands = append(commands, req["execute"])
+
+			// An event may arrive before the reply
+			if req["execute"] == "system_powerdown" {
+				_, _ = conn.Write([]byte(`{"event": "POWERDOWN", "timestamp": {}}` + "\n"))
+				_, _ = conn.Write([]byte(`{"error": {"class": "GenericError", "desc": "no power button"}}` + "\n"))
+				continue
+			}
+			_, _ = conn.Write([]byte(`{"return": {}}` + "\n"))
+		}
+		executed <- commands
+	}()
+
+	err = qmpCommand(socket, "system_powerdown")
+	assert.Error(t, err, "QMP command system_powerdown failed: GenericError: no power button")
+	assert.DeepEqual(t, <-executed, []string{"qmp_capabilities", "system_powerdown"})
+}
@@ -0,0 +1,29 @@
+package container
+
+import (
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+// seccompLevels are the Firecracker seccomp levels of the ignite ones
+var seccompLevels = map[api.SeccompLevel]string{
+	api.SeccompLevelAdvanced: "2",
+	api.SeccompLevelBasic:    "1",
+	api.SeccompLevelDisabled: "0",
+}
+
+// seccompArgs returns the Firecracker flags applying the seccomp configuration. The custom
+// filter is bound into the container by ignite. Without any configuration, Firecracker
+// applies its default, the advanced level.
+func seccompArgs(seccomp *api.VMSeccompSpec) []string {
+	switch {
+	case seccomp == nil:
+		return nil
+	case len(seccomp.Filter) > 0:
+		return []string{"--seccomp-filter", constants.IGNITE_SPAWN_SECCOMP_FILTER_FILE_PATH}
+	case len(seccomp.Level) > 0:
+		return []string{"--seccomp-level", seccompLevels[seccomp.Level]}
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+// Stats is the resource usage of a VM. It's collected by ignite-spawn, which
+// writes it to the stats file in the VM directory every VM_STATS_INTERVAL.
+type Stats struct {
+	// Timestamp is the time the stats were collected at
+	Timestamp time.Time `json:"timestamp"`
+	// CPUPercent is the CPU usage of the VM container since the previous
+	// collection, a fully used CPU counts as 100%
+	CPUPercent float64 `json:"cpuPercent"`
+	// MemoryUsage is the memory used by the VM container on the host
+	MemoryUsage uint64 `json:"memoryUsage"`
+	// GuestMemoryTotal and GuestMemoryAvailable are reported by the balloon device
+	// of the VM, they're zero if the VM or Firecracker doesn't support it
+	GuestMemoryTotal     uint64 `json:"guestMemoryTotal,omitempty"`
+	GuestMemoryAvailable uint64 `json:"guestMemoryAvailable,omitempty"`
+	// DiskReadBytes and DiskWriteBytes count the bytes transferred by the drives of the VM
+	DiskReadBytes  uint64 `json:"diskReadBytes"`
+	DiskWriteBytes uint64 `json:"diskWriteBytes"`
+	// NetRxBytes and NetTxBytes count the bytes transferred by the network interfaces of the VM
+	NetRxBytes uint64 `json:"netRxBytes"`
+	NetTxBytes uint64 `json:"netTxBytes"`
+}
+
+// ReadStats reads the latest resource usage of the running VM
+func ReadStats(vm *api.VM) (*Stats, error) {
+	b, err := ioutil.ReadFile(path.Join(vm.ObjectPath(), constants.VM_STATS_FILE))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no stats have been collected for VM %q", vm.GetUID())
+		}
+
+		return nil, err
+	}
+
+	stats := &Stats{}
+	if err := json.Unmarshal(b, stats); err != nil {
+		return nil, fmt.Errorf("failed to parse the stats of VM %q: %v", vm.GetUID(), err)
+	}
+
+	return stats, nil
+}
+
+// firecrackerMetrics holds the counters read from the metrics Firecracker flushes,
+// the counters are reset on every flush, so they're summed up by statsCollector
+type firecrackerMetrics struct {
+	Block struct {
+		ReadBytes  uint64 `json:"read_bytes"`
+		WriteBytes uint64 `json:"write_bytes"`
+	} `json:"block"`
+	Net struct {
+		RxBytes uint64 `json:"rx_bytes_count"`
+		TxBytes uint64 `json:"tx_bytes_count"`
+	} `json:"net"`
+}
+
+// balloonStatistics holds the guest memory statistics of the balloon device
+type balloonStatistics struct {
+	TotalMemory     uint64 `json:"total_memory"`
+	AvailableMemory uint64 `json:"available_memory"`
+}
+
+// statsCollector collects the resource usage of a running VM
+type statsCollector struct {
+	vm      *api.VM
+	balloon bool
+
+	mu     sync.Mutex
+	totals firecrackerMetrics
+
+	lastCPU  uint64
+	lastTime time.Time
+}
+
+// configureBalloon adds a balloon device to the VM, which is only used to report the
+// memory statistics of the guest. Firecracker supports balloon devices since v0.24,
+// with older versions the VM is started without one.
+func (c *statsCollector) configureBalloon() error {
+	if err := FirecrackerRequest(c.vm, http.MethodPut, "/balloon", map[string]interface{}{
+		"amount_mib":               0,
+		"deflate_on_oom":           true,
+		"stats_polling_interval_s": int(constants.VM_STATS_INTERVAL / time.Second),
+	}, 0); err != nil {
+		log.Debugf("Starting VM %q without a balloon device: %v", c.vm.GetUID(), err)
+		return nil
+	}
+
+	c.balloon = true
+	return nil
+}
+
+// run collects the stats of the VM until ctx is done
+func (c *statsCollector) run(ctx context.Context) {
+	statsFile := path.Join(c.vm.ObjectPath(), constants.VM_STATS_FILE)
+
+	go c.readMetrics(ctx, path.Join(c.vm.ObjectPath(), constants.METRICS_FIFO))
+
+	ticker := time.NewTicker(constants.VM_STATS_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Let Firecracker write its metrics for the next collection
+		if err := FirecrackerRequest(c.vm, http.MethodPut, "/actions", map[string]string{"action_type": "FlushMetrics"}, 0); err != nil {
+			log.Debugf("Failed to flush the Firecracker metrics of VM %q: %v", c.vm.GetUID(), err)
+		}
+
+		stats := c.collect()
+		b, err := json.Marshal(stats)
+		if err != nil {
+			log.Errorf("Failed to encode the stats of VM %q: %v", c.vm.GetUID(), err)
+			continue
+		}
+
+		// Replace the file atomically, so readers never see a partial write
+		if err := ioutil.WriteFile(statsFile+".tmp", b, 0644); err != nil {
+			log.Errorf("Failed to write the stats of VM %q: %v", c.vm.GetUID(), err)
+			continue
+		}
+
+		if err := os.Rename(statsFile+".tmp", statsFile); err != nil {
+			log.Errorf("Failed to write the stats of VM %q: %v", c.vm.GetUID(), err)
+		}
+	}
+}
+
+// collect returns the current resource usage of the VM
+func (c *statsCollector) collect() *Stats {
+	stats := &Stats{Timestamp: time.Now()}
+
+	cpu, memory, err := readCgroupUsage()
+	if err != nil {
+		log.Debugf("Failed to read the cgroup usage of VM %q: %v", c.vm.GetUID(), err)
+	} else {
+		if !c.lastTime.IsZero() && cpu >= c.lastCPU {
+			elapsed := stats.Timestamp.Sub(c.lastTime)
+			stats.CPUPercent = float64(cpu-c.lastCPU) / float64(elapsed.Nanoseconds()) * 100
+		}
+
+		c.lastCPU, c.lastTime = cpu, stats.Timestamp
+		stats.MemoryUsage = memory
+	}
+
+	if c.balloon {
+		balloon := &balloonStatistics{}
+		if err := FirecrackerGet(c.vm, "/balloon/statistics", balloon); err != nil {
+			log.Debugf("Failed to get the balloon statistics of VM %q: %v", c.vm.GetUID(), err)
+		} else {
+			stats.GuestMemoryTotal, stats.GuestMemoryAvailable = balloon.TotalMemory, balloon.AvailableMemory
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats.DiskReadBytes, stats.DiskWriteBytes = c.totals.Block.ReadBytes, c.totals.Block.WriteBytes
+	stats.NetRxBytes, stats.NetTxBytes = c.totals.Net.RxBytes, c.totals.Net.TxBytes
+
+	return stats
+}
+
+// readMetrics sums up the counters of the metrics Firecracker writes to the
+// metrics FIFO, which holds a JSON object per line
+func (c *statsCollector) readMetrics(ctx context.Context, fifo string) {
+	f, err := os.Open(fifo)
+	if err != nil {
+		log.Errorf("Failed to open the Firecracker metrics of VM %q: %v", c.vm.GetUID(), err)
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		metrics := &firecrackerMetrics{}
+		if err := json.Unmarshal(scanner.Bytes(), metrics); err != nil {
+			log.Debugf("Skipping invalid Firecracker metrics of VM %q: %v", c.vm.GetUID(), err)
+			continue
+		}
+
+		c.mu.Lock()
+		c.totals.Block.ReadBytes += metrics.Block.ReadBytes
+		c.totals.Block.WriteBytes += metrics.Block.WriteBytes
+		c.totals.Net.RxBytes += metrics.Net.RxBytes
+		c.totals.Net.TxBytes += metrics.Net.TxBytes
+		c.mu.Unlock()
+	}
+}
+
+// readCgroupUsage returns the CPU time in nanoseconds and the memory used by the
+// cgroup of the container, which is mounted at /sys/fs/cgroup inside of it
+func readCgroupUsage() (cpu, memory uint64, err error) {
+	// cgroup v2 reports the CPU time in microseconds in cpu.stat
+	if cpuStat, err := ioutil.ReadFile("/sys/fs/cgroup/cpu.stat"); err == nil {
+		for _, line := range strings.Split(string(cpuStat), "\n") {
+			if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "usage_usec" {
+				if cpu, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+					return 0, 0, err
+				}
+				cpu *= 1000
+			}
+		}
+
+		memory, err = readUintFile("/sys/fs/cgroup/memory.current")
+		return cpu, memory, err
+	}
+
+	if cpu, err = readUintFile("/sys/fs/cgroup/cpuacct/cpuacct.usage"); err != nil {
+		return
+	}
+
+	memory, err = readUintFile("/sys/fs/cgroup/memory/memory.usage_in_bytes")
+	return
+}
+
+func readUintFile(file string) (uint64, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+}
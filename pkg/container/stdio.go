@@ -0,0 +1,84 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	terminal "golang.org/x/term"
+)
+
+// vmStdio connects the serial console of the VM, the stdin and stdout of the VMM process.
+// The console input is passed to the VMM through a pipe, fed by the container's stdin for
+// the runtime's attach, and by the writable session of the console socket. The console
+// output is written to the container's stdout, the console log and the console socket.
+type vmStdio struct {
+	Stdin  *os.File
+	Stdout io.Writer
+
+	socket  string
+	closers []io.Closer
+	restore func() error
+}
+
+// openStdio records the console output of the VM for "ignite vm logs", it's kept after
+// the VM stops, and shares the console for multiple "ignite attach" sessions
+func openStdio(vm *api.VM, consoleCfg api.ConsoleLogConfiguration) (s *vmStdio, err error) {
+	s = &vmStdio{}
+	defer func() {
+		if err != nil {
+			_ = s.Close()
+		}
+	}()
+
+	console, err := newConsoleLog(vm, consoleCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the console log: %v", err)
+	}
+	s.closers = append(s.closers, console)
+
+	stdin, input, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	s.closers = append(s.closers, stdin, input)
+
+	// The VMMs put their stdin into raw mode when it's a terminal, which the pipe isn't
+	if fd := int(os.Stdin.Fd()); terminal.IsTerminal(fd) {
+		state, rawErr := terminal.MakeRaw(fd)
+		if rawErr != nil {
+			return nil, fmt.Errorf("failed to make the terminal raw: %v", rawErr)
+		}
+		s.restore = func() error { return terminal.Restore(fd, state) }
+	}
+	go func() { _, _ = io.Copy(input, os.Stdin) }()
+
+	s.socket = consoleSocketPath(vm)
+	mux, err := newConsoleMux(s.socket, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to share the console: %v", err)
+	}
+	s.closers = append(s.closers, mux)
+
+	s.Stdin = stdin
+	s.Stdout = io.MultiWriter(os.Stdout, console, mux)
+	return s, nil
+}
+
+// Close closes the console in reverse order of opening, and returns the error of restoring
+// the terminal
+func (s *vmStdio) Close() error {
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		_ = s.closers[i].Close()
+	}
+	if len(s.socket) > 0 {
+		_ = os.Remove(s.socket)
+	}
+
+	if s.restore != nil {
+		return s.restore()
+	}
+
+	return nil
+}
@@ -0,0 +1,826 @@
+package cri
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/agent"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/runtime"
+	"github.com/weaveworks/ignite/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	// guestContainerDir is the directory in the sandbox VMs holding the containers
+	guestContainerDir = "/var/lib/ignite-cri"
+	// killTimeout bounds waiting for a killed container to exit
+	killTimeout = 10 * time.Second
+	// lostExitCode is the exit code of the containers lost when ignited stopped
+	lostExitCode = 255
+)
+
+// container is the record of a container of a pod sandbox, stored in the
+// CRI_CONTAINER_DIR of the sandbox VM
+type container struct {
+	ID        string                        `json:"id"`
+	SandboxID string                        `json:"sandboxID"`
+	Metadata  *runtimeapi.ContainerMetadata `json:"metadata"`
+	Image     *runtimeapi.ImageSpec         `json:"image"`
+	ImageRef  string                        `json:"imageRef"`
+	// Command is the executable and its arguments, from the config or the image
+	Command    []string            `json:"command"`
+	Env        []string            `json:"env,omitempty"`
+	WorkingDir string              `json:"workingDir,omitempty"`
+	Tty        bool                `json:"tty,omitempty"`
+	Mounts     []*runtimeapi.Mount `json:"mounts,omitempty"`
+	// LogPath is the absolute path of the log of the container on the host
+	LogPath     string                    `json:"logPath,omitempty"`
+	Labels      map[string]string         `json:"labels,omitempty"`
+	Annotations map[string]string         `json:"annotations,omitempty"`
+	State       runtimeapi.ContainerState `json:"state"`
+	CreatedAt   int64                     `json:"createdAt"`
+	StartedAt   int64                     `json:"startedAt,omitempty"`
+	FinishedAt  int64                     `json:"finishedAt,omitempty"`
+	ExitCode    int32                     `json:"exitCode,omitempty"`
+	Reason      string                    `json:"reason,omitempty"`
+	Message     string                    `json:"message,omitempty"`
+}
+
+// guestDir returns the directory of the container in the sandbox VM
+func (c *container) guestDir() string {
+	return path.Join(guestContainerDir, c.ID)
+}
+
+// rootfs returns the root filesystem of the container in the sandbox VM
+func (c *container) rootfs() string {
+	return path.Join(c.guestDir(), "rootfs")
+}
+
+// GetID returns the ID of the container, or an empty string if it's nil
+func (c *container) GetID() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.ID
+}
+
+// exited records that the container exited with the given code
+func (c *container) exited(code int32, reason, message string) {
+	c.State = runtimeapi.ContainerState_CONTAINER_EXITED
+	c.FinishedAt = time.Now().UnixNano()
+	c.ExitCode = code
+	c.Reason = reason
+	c.Message = message
+}
+
+// process is a running container, its exec connection to the guest agent is held until it exits
+type process struct {
+	log *logFile
+	// done is closed once the container has exited
+	done chan struct{}
+}
+
+// containerDir returns the directory of the container records of the sandbox VM
+func containerDir(vm *api.VM) string {
+	return path.Join(vm.ObjectPath(), constants.CRI_CONTAINER_DIR)
+}
+
+// containers returns the container records of the sandbox VM, the caller holds s.mu
+func (s *Server) containers(vm *api.VM) ([]*container, error) {
+	files, err := ioutil.ReadDir(containerDir(vm))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var containers []*container
+	for _, fi := range files {
+		if path.Ext(fi.Name()) != ".json" {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(path.Join(containerDir(vm), fi.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		c := &container{}
+		if err := json.Unmarshal(b, c); err != nil {
+			return nil, fmt.Errorf("invalid record of container %q: %v", fi.Name(), err)
+		}
+
+		containers = append(containers, c)
+	}
+
+	return containers, nil
+}
+
+// saveContainer writes the record of the container, the caller holds s.mu
+func saveContainer(vm *api.VM, c *container) error {
+	if err := os.MkdirAll(containerDir(vm), constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path.Join(containerDir(vm), c.ID+".json"), b, 0644)
+}
+
+// findContainer returns the container with the given ID, or a prefix of it, and its sandbox VM.
+// The caller holds s.mu.
+func (s *Server) findContainer(id string) (*api.VM, *container, error) {
+	if len(id) == 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "no container ID given")
+	}
+
+	vms, err := s.sandboxVMs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var foundVM *api.VM
+	var found *container
+	for _, vm := range vms {
+		containers, err := s.containers(vm)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, c := range containers {
+			if !strings.HasPrefix(c.ID, id) {
+				continue
+			}
+
+			if found != nil {
+				return nil, nil, status.Errorf(codes.InvalidArgument, "container ID %q is ambiguous", id)
+			}
+			foundVM, found = vm, c
+		}
+	}
+
+	if found == nil {
+		return nil, nil, status.Errorf(codes.NotFound, "container %q not found", id)
+	}
+
+	return foundVM, found, nil
+}
+
+// recordLostContainers records the containers that ran when ignited stopped as exited
+func (s *Server) recordLostContainers() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vms, err := s.sandboxVMs()
+	if err != nil {
+		return err
+	}
+
+	for _, vm := range vms {
+		containers, err := s.containers(vm)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range containers {
+			if c.State != runtimeapi.ContainerState_CONTAINER_RUNNING || s.running[c.ID] != nil {
+				continue
+			}
+
+			c.exited(lostExitCode, "Error", "the container was killed when its exec connection to ignited was lost")
+			if err := saveContainer(vm, c); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CreateContainer extracts the root filesystem of the image of the container into the sandbox
+// VM, with the files of its mounts copied into it, and records the container
+func (s *Server) CreateContainer(ctx context.Context, req *runtimeapi.CreateContainerRequest) (*runtimeapi.CreateContainerResponse, error) {
+	cfg := req.GetConfig()
+	if cfg.GetMetadata() == nil || len(cfg.GetImage().GetImage()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no container metadata or image given")
+	}
+
+	vm, sb, err := s.findSandbox(req.PodSandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !vm.Running() {
+		return nil, status.Errorf(codes.FailedPrecondition, "pod sandbox %q is not ready", vm.GetUID())
+	}
+
+	ref, err := meta.NewOCIImageRef(cfg.Image.Image)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	img, err := providers.Runtime.InspectImage(ref)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "image %q not found: %v", ref, err)
+	}
+
+	command := containerCommand(cfg, img)
+	if len(command) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no command given for container %q, and image %q has none", cfg.Metadata.Name, ref)
+	}
+
+	id, err := util.NewUID()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &container{
+		ID:          id,
+		SandboxID:   vm.GetUID().String(),
+		Metadata:    cfg.Metadata,
+		Image:       cfg.Image,
+		ImageRef:    ref.Normalized(),
+		Command:     command,
+		Env:         containerEnv(cfg, img),
+		WorkingDir:  cfg.WorkingDir,
+		Tty:         cfg.Tty,
+		Mounts:      cfg.Mounts,
+		Labels:      cfg.Labels,
+		Annotations: cfg.Annotations,
+		State:       runtimeapi.ContainerState_CONTAINER_CREATED,
+		CreatedAt:   time.Now().UnixNano(),
+	}
+
+	if len(c.WorkingDir) == 0 {
+		c.WorkingDir = img.WorkingDir
+	}
+
+	if len(cfg.LogPath) > 0 && len(sb.LogDirectory) > 0 {
+		c.LogPath = path.Join(sb.LogDirectory, cfg.LogPath)
+	}
+
+	log.Infof("Creating container %q of pod sandbox %q from image %q...", cfg.Metadata.Name, vm.GetUID(), ref)
+	if err := runScript(vm, prepareScript, c.guestDir()); err != nil {
+		return nil, fmt.Errorf("failed to prepare the root filesystem of container %q: %v", cfg.Metadata.Name, err)
+	}
+
+	if err := extractRootfs(vm, sb, c, ref); err != nil {
+		if cleanupErr := runScript(vm, cleanupScript, c.guestDir()); cleanupErr != nil {
+			log.Warnf("Failed to clean up container %q in VM %q: %v", c.ID, vm.GetUID(), cleanupErr)
+		}
+
+		return nil, fmt.Errorf("failed to extract the root filesystem of container %q: %v", cfg.Metadata.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := saveContainer(vm, c); err != nil {
+		return nil, err
+	}
+
+	return &runtimeapi.CreateContainerResponse{ContainerId: c.ID}, nil
+}
+
+// containerCommand returns the command of the container like Docker does, the command of the
+// config replaces the entrypoint of the image, and its arguments replace the cmd of the image
+func containerCommand(cfg *runtimeapi.ContainerConfig, img *runtime.ImageInspectResult) []string {
+	entrypoint, args := cfg.Command, cfg.Args
+	if len(entrypoint) == 0 {
+		entrypoint = img.Entrypoint
+		if len(args) == 0 {
+			args = img.Cmd
+		}
+	}
+
+	return append(append([]string{}, entrypoint...), args...)
+}
+
+// containerEnv returns the environment of the image, with the variables of the config added
+// to it, or replacing the ones of the image with the same name
+func containerEnv(cfg *runtimeapi.ContainerConfig, img *runtime.ImageInspectResult) []string {
+	env := append([]string{}, img.Env...)
+	for _, kv := range cfg.Envs {
+		replaced := false
+		for i, variable := range env {
+			if strings.SplitN(variable, "=", 2)[0] == kv.Key {
+				env[i] = kv.Key + "=" + kv.Value
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			env = append(env, kv.Key+"="+kv.Value)
+		}
+	}
+
+	return env
+}
+
+// StartContainer runs the command of the container chrooted into its root filesystem using the
+// guest agent, and writes its output to its log in the background until it exits
+func (s *Server) StartContainer(ctx context.Context, req *runtimeapi.StartContainerRequest) (*runtimeapi.StartContainerResponse, error) {
+	s.mu.Lock()
+	vm, c, err := s.findContainer(req.ContainerId)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.State != runtimeapi.ContainerState_CONTAINER_CREATED {
+		return nil, status.Errorf(codes.FailedPrecondition, "container %q is not in the created state", c.ID)
+	}
+
+	p := &process{done: make(chan struct{})}
+	if len(c.LogPath) > 0 {
+		if p.log, err = openLogFile(c.LogPath); err != nil {
+			return nil, err
+		}
+	}
+
+	execReq := &protocol.ExecRequest{
+		Command: c.Command,
+		Env:     c.Env,
+		Tty:     c.Tty,
+		ID:      c.ID,
+		Root:    c.rootfs(),
+		Dir:     c.WorkingDir,
+	}
+
+	stdout, stderr := newLogWriter(p.log, "stdout"), newLogWriter(p.log, "stderr")
+	startErr := make(chan error, 1)
+
+	s.mu.Lock()
+	s.running[c.ID] = p
+	s.mu.Unlock()
+
+	go func() {
+		started := false
+		code, err := agent.Exec(vm, execReq, &agent.ExecStreams{
+			Stdout: stdout,
+			Stderr: stderr,
+			Started: func() {
+				started = true
+				startErr <- s.markStarted(vm, c.ID)
+			},
+		})
+		stdout.Flush()
+		stderr.Flush()
+		if !started {
+			startErr <- err
+		}
+
+		s.finishContainer(vm, c.ID, code, err)
+	}()
+
+	// A command that failed to start is recorded as exited by finishContainer
+	if err := <-startErr; err != nil {
+		return nil, fmt.Errorf("failed to start container %q: %v", c.ID, err)
+	}
+
+	return &runtimeapi.StartContainerResponse{}, nil
+}
+
+// markStarted records that the container is running
+func (s *Server) markStarted(vm *api.VM, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, c, err := s.findContainer(id)
+	if err != nil {
+		return err
+	}
+
+	c.State = runtimeapi.ContainerState_CONTAINER_RUNNING
+	c.StartedAt = time.Now().UnixNano()
+	return saveContainer(vm, c)
+}
+
+// finishContainer records that the container exited with the code, or that its exec connection
+// to the guest agent failed with err
+func (s *Server) finishContainer(vm *api.VM, id string, code int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.running[id]
+	delete(s.running, id)
+	if p != nil {
+		if p.log != nil {
+			p.log.Close()
+		}
+		defer close(p.done)
+	}
+
+	_, c, findErr := s.findContainer(id)
+	if findErr != nil {
+		// The container was removed in the meantime
+		return
+	}
+
+	switch {
+	case err != nil:
+		c.exited(lostExitCode, "Error", err.Error())
+	case code == 0:
+		c.exited(0, "Completed", "")
+	default:
+		c.exited(int32(code), "Error", "")
+	}
+
+	if err := saveContainer(vm, c); err != nil {
+		log.Errorf("Failed to record the exit of container %q: %v", id, err)
+	}
+}
+
+// StopContainer sends SIGTERM to the container, and SIGKILL if it hasn't exited after the timeout
+func (s *Server) StopContainer(ctx context.Context, req *runtimeapi.StopContainerRequest) (*runtimeapi.StopContainerResponse, error) {
+	s.mu.Lock()
+	vm, c, err := s.findContainer(req.ContainerId)
+	p := s.running[c.GetID()]
+	s.mu.Unlock()
+	if status.Code(err) == codes.NotFound {
+		return &runtimeapi.StopContainerResponse{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return &runtimeapi.StopContainerResponse{}, nil
+	}
+
+	if err := agent.Signal(vm, c.ID, syscall.SIGTERM); err != nil {
+		log.Warnf("Failed to send SIGTERM to container %q: %v", c.ID, err)
+	}
+
+	select {
+	case <-p.done:
+		return &runtimeapi.StopContainerResponse{}, nil
+	case <-time.After(time.Duration(req.Timeout) * time.Second):
+	}
+
+	if err := kill(vm, c.ID, p); err != nil {
+		return nil, err
+	}
+
+	return &runtimeapi.StopContainerResponse{}, nil
+}
+
+// kill sends SIGKILL to the running container, and waits for it to exit
+func kill(vm *api.VM, id string, p *process) error {
+	if err := agent.Signal(vm, id, syscall.SIGKILL); err != nil {
+		log.Warnf("Failed to send SIGKILL to container %q: %v", id, err)
+	}
+
+	select {
+	case <-p.done:
+		return nil
+	case <-time.After(killTimeout):
+		return fmt.Errorf("container %q didn't exit after SIGKILL", id)
+	}
+}
+
+// killContainers kills the running containers of the sandbox VM
+func (s *Server) killContainers(vm *api.VM) {
+	s.mu.Lock()
+	containers, err := s.containers(vm)
+	running := map[string]*process{}
+	for _, c := range containers {
+		if p := s.running[c.ID]; p != nil {
+			running[c.ID] = p
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		log.Warnf("Failed to list the containers of VM %q: %v", vm.GetUID(), err)
+	}
+
+	for id, p := range running {
+		if err := kill(vm, id, p); err != nil {
+			log.Warn(err)
+		}
+	}
+}
+
+// RemoveContainer kills the container if it's running, and removes it from the sandbox VM
+func (s *Server) RemoveContainer(ctx context.Context, req *runtimeapi.RemoveContainerRequest) (*runtimeapi.RemoveContainerResponse, error) {
+	s.mu.Lock()
+	vm, c, err := s.findContainer(req.ContainerId)
+	p := s.running[c.GetID()]
+	s.mu.Unlock()
+	if status.Code(err) == codes.NotFound {
+		return &runtimeapi.RemoveContainerResponse{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if p != nil {
+		if err := kill(vm, c.ID, p); err != nil {
+			return nil, err
+		}
+	}
+
+	// The files of the container go away with the VM if it isn't running
+	if vm.Running() {
+		if err := runScript(vm, cleanupScript, c.guestDir()); err != nil {
+			return nil, fmt.Errorf("failed to remove container %q from VM %q: %v", c.ID, vm.GetUID(), err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(path.Join(containerDir(vm), c.ID+".json")); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return &runtimeapi.RemoveContainerResponse{}, nil
+}
+
+// ListContainers lists the containers of all pod sandboxes matching the filter
+func (s *Server) ListContainers(ctx context.Context, req *runtimeapi.ListContainersRequest) (*runtimeapi.ListContainersResponse, error) {
+	f := req.GetFilter()
+	containers, err := s.listContainers(f.GetId(), f.GetPodSandboxId(), f.GetLabelSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &runtimeapi.ListContainersResponse{}
+	for _, c := range containers {
+		if f.GetState() != nil && f.GetState().State != c.State {
+			continue
+		}
+
+		resp.Containers = append(resp.Containers, &runtimeapi.Container{
+			Id:           c.ID,
+			PodSandboxId: c.SandboxID,
+			Metadata:     c.Metadata,
+			Image:        c.Image,
+			ImageRef:     c.ImageRef,
+			State:        c.State,
+			CreatedAt:    c.CreatedAt,
+			Labels:       c.Labels,
+			Annotations:  c.Annotations,
+		})
+	}
+
+	return resp, nil
+}
+
+// listContainers returns the containers matching the ID and sandbox ID prefixes and the label
+// selector, oldest first
+func (s *Server) listContainers(id, sandboxID string, selector map[string]string) ([]*container, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vms, err := s.sandboxVMs()
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []*container
+	for _, vm := range vms {
+		if !strings.HasPrefix(vm.GetUID().String(), sandboxID) {
+			continue
+		}
+
+		containers, err := s.containers(vm)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range containers {
+			if strings.HasPrefix(c.ID, id) && matchLabels(selector, c.Labels) {
+				matching = append(matching, c)
+			}
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool { return matching[i].CreatedAt < matching[j].CreatedAt })
+	return matching, nil
+}
+
+// ContainerStatus returns the state of the container
+func (s *Server) ContainerStatus(ctx context.Context, req *runtimeapi.ContainerStatusRequest) (*runtimeapi.ContainerStatusResponse, error) {
+	s.mu.Lock()
+	_, c, err := s.findContainer(req.ContainerId)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return &runtimeapi.ContainerStatusResponse{
+		Status: &runtimeapi.ContainerStatus{
+			Id:          c.ID,
+			Metadata:    c.Metadata,
+			State:       c.State,
+			CreatedAt:   c.CreatedAt,
+			StartedAt:   c.StartedAt,
+			FinishedAt:  c.FinishedAt,
+			ExitCode:    c.ExitCode,
+			Image:       c.Image,
+			ImageRef:    c.ImageRef,
+			Reason:      c.Reason,
+			Message:     c.Message,
+			Labels:      c.Labels,
+			Annotations: c.Annotations,
+			Mounts:      c.Mounts,
+			LogPath:     c.LogPath,
+		},
+	}, nil
+}
+
+// ContainerStats returns the attributes of the container, the resource usage of the processes
+// of a container isn't available from the guest agent
+func (s *Server) ContainerStats(ctx context.Context, req *runtimeapi.ContainerStatsRequest) (*runtimeapi.ContainerStatsResponse, error) {
+	s.mu.Lock()
+	_, c, err := s.findContainer(req.ContainerId)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return &runtimeapi.ContainerStatsResponse{Stats: containerStats(c)}, nil
+}
+
+// ListContainerStats returns the attributes of the running containers matching the filter
+func (s *Server) ListContainerStats(ctx context.Context, req *runtimeapi.ListContainerStatsRequest) (*runtimeapi.ListContainerStatsResponse, error) {
+	f := req.GetFilter()
+	containers, err := s.listContainers(f.GetId(), f.GetPodSandboxId(), f.GetLabelSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &runtimeapi.ListContainerStatsResponse{}
+	for _, c := range containers {
+		if c.State == runtimeapi.ContainerState_CONTAINER_RUNNING {
+			resp.Stats = append(resp.Stats, containerStats(c))
+		}
+	}
+
+	return resp, nil
+}
+
+func containerStats(c *container) *runtimeapi.ContainerStats {
+	return &runtimeapi.ContainerStats{
+		Attributes: &runtimeapi.ContainerAttributes{
+			Id:          c.ID,
+			Metadata:    c.Metadata,
+			Labels:      c.Labels,
+			Annotations: c.Annotations,
+		},
+	}
+}
+
+// ReopenContainerLog reopens the log of the running container, after the kubelet rotated it
+func (s *Server) ReopenContainerLog(ctx context.Context, req *runtimeapi.ReopenContainerLogRequest) (*runtimeapi.ReopenContainerLogResponse, error) {
+	s.mu.Lock()
+	_, c, err := s.findContainer(req.ContainerId)
+	p := s.running[c.GetID()]
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "container %q is not running", c.ID)
+	}
+
+	if p.log != nil {
+		if err := p.log.Reopen(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &runtimeapi.ReopenContainerLogResponse{}, nil
+}
+
+// ExecSync runs the command chrooted into the root filesystem of the running container, with
+// its environment, and returns its output. The command is killed after the timeout.
+func (s *Server) ExecSync(ctx context.Context, req *runtimeapi.ExecSyncRequest) (*runtimeapi.ExecSyncResponse, error) {
+	s.mu.Lock()
+	vm, c, err := s.findContainer(req.ContainerId)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.State != runtimeapi.ContainerState_CONTAINER_RUNNING {
+		return nil, status.Errorf(codes.FailedPrecondition, "container %q is not running", c.ID)
+	}
+
+	uid, err := util.NewUID()
+	if err != nil {
+		return nil, err
+	}
+
+	execReq := &protocol.ExecRequest{
+		Command: req.Cmd,
+		Env:     c.Env,
+		ID:      c.ID + "-exec-" + uid,
+		Root:    c.rootfs(),
+		Dir:     c.WorkingDir,
+	}
+
+	type result struct {
+		code int
+		err  error
+	}
+
+	var stdout, stderr bytes.Buffer
+	done := make(chan result, 1)
+	go func() {
+		code, err := agent.Exec(vm, execReq, &agent.ExecStreams{Stdout: &stdout, Stderr: &stderr})
+		done <- result{code, err}
+	}()
+
+	var timeout <-chan time.Time
+	if req.Timeout > 0 {
+		timeout = time.After(time.Duration(req.Timeout) * time.Second)
+	}
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		return &runtimeapi.ExecSyncResponse{
+			Stdout:   stdout.Bytes(),
+			Stderr:   stderr.Bytes(),
+			ExitCode: int32(r.code),
+		}, nil
+	case <-timeout:
+		if err := agent.Signal(vm, execReq.ID, syscall.SIGKILL); err != nil {
+			log.Warnf("Failed to kill the timed out command in container %q: %v", c.ID, err)
+		}
+
+		return nil, status.Errorf(codes.DeadlineExceeded, "command %q timed out after %ds", strings.Join(req.Cmd, " "), req.Timeout)
+	}
+}
+
+// prepareScript mounts the pseudo filesystems into the root filesystem of the container at
+// the directory given as the argument, with a /dev holding only the basic devices
+const prepareScript = `set -e
+root="$1/rootfs"
+mkdir -p "$root/proc" "$root/sys" "$root/dev"
+mount -t proc proc "$root/proc"
+mount -t sysfs -o ro sysfs "$root/sys"
+mount -t tmpfs -o nosuid,mode=755 tmpfs "$root/dev"
+for dev in null zero full random urandom tty; do
+	touch "$root/dev/$dev"
+	mount --bind "/dev/$dev" "$root/dev/$dev"
+done
+mkdir -p "$root/dev/pts" "$root/dev/shm"
+mount -t devpts -o newinstance,ptmxmode=0666 devpts "$root/dev/pts"
+ln -s pts/ptmx "$root/dev/ptmx"
+mount -t tmpfs -o nosuid,nodev shm "$root/dev/shm"
+`
+
+// cleanupScript unmounts the pseudo filesystems of the container at the directory given as the
+// argument, and removes it. It's only removed once nothing is mounted below it anymore.
+const cleanupScript = `root="$1/rootfs"
+for mnt in dev/shm dev/pts dev/null dev/zero dev/full dev/random dev/urandom dev/tty dev sys proc; do
+	umount "$root/$mnt" 2>/dev/null
+done
+if grep -q " $root/" /proc/mounts; then
+	echo "filesystems are still mounted below $root" >&2
+	exit 1
+fi
+rm -rf "$1"
+`
+
+// runScript runs the shell script in the sandbox VM with the given argument
+func runScript(vm *api.VM, script, arg string) error {
+	var stderr bytes.Buffer
+	code, err := agent.Exec(vm, &protocol.ExecRequest{Command: []string{"/bin/sh", "-c", script, "sh", arg}}, &agent.ExecStreams{
+		Stdout: ioutil.Discard,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("exit code %d: %s", code, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package cri
+
+import (
+	"testing"
+
+	"github.com/weaveworks/ignite/pkg/runtime"
+	"gotest.tools/assert"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestContainerCommand(t *testing.T) {
+	img := &runtime.ImageInspectResult{
+		Entrypoint: []string{"/entrypoint.sh"},
+		Cmd:        []string{"serve"},
+	}
+
+	tests := []struct {
+		command, args []string
+		expected      []string
+	}{
+		{nil, nil, []string{"/entrypoint.sh", "serve"}},
+		{nil, []string{"migrate"}, []string{"/entrypoint.sh", "migrate"}},
+		// A command overrides both the entrypoint and the cmd of the image
+		{[]string{"/bin/sh"}, nil, []string{"/bin/sh"}},
+		{[]string{"/bin/sh"}, []string{"-c", "true"}, []string{"/bin/sh", "-c", "true"}},
+	}
+
+	for _, rt := range tests {
+		cfg := &runtimeapi.ContainerConfig{Command: rt.command, Args: rt.args}
+		assert.DeepEqual(t, containerCommand(cfg, img), rt.expected)
+	}
+}
+
+func TestContainerEnv(t *testing.T) {
+	img := &runtime.ImageInspectResult{
+		Env: []string{"PATH=/usr/bin:/bin", "LANG=C.UTF-8"},
+	}
+
+	cfg := &runtimeapi.ContainerConfig{
+		Envs: []*runtimeapi.KeyValue{
+			{Key: "LANG", Value: "en_US.UTF-8"},
+			{Key: "KUBERNETES_SERVICE_HOST", Value: "10.96.0.1"},
+		},
+	}
+
+	assert.DeepEqual(t, containerEnv(cfg, img), []string{
+		"PATH=/usr/bin:/bin",
+		"LANG=en_US.UTF-8",
+		"KUBERNETES_SERVICE_HOST=10.96.0.1",
+	})
+	// The environment of the image is left alone
+	assert.Equal(t, img.Env[1], "LANG=C.UTF-8")
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"app": "web", "tier": "frontend"}
+
+	tests := []struct {
+		selector map[string]string
+		expected bool
+	}{
+		{nil, true},
+		{map[string]string{"app": "web"}, true},
+		{map[string]string{"app": "web", "tier": "frontend"}, true},
+		{map[string]string{"app": "db"}, false},
+		{map[string]string{"zone": "a"}, false},
+	}
+
+	for _, rt := range tests {
+		assert.Equal(t, matchLabels(rt.selector, labels), rt.expected)
+	}
+}
@@ -0,0 +1,177 @@
+package cri
+
+import (
+	"context"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// ListImages lists the images pulled over the CRI and the images of the containers. The images
+// of the container runtime pulled otherwise aren't listed, so the image garbage collection of
+// the kubelet leaves them alone.
+func (s *Server) ListImages(ctx context.Context, req *runtimeapi.ListImagesRequest) (*runtimeapi.ListImagesResponse, error) {
+	refs, err := s.knownImages()
+	if err != nil {
+		return nil, err
+	}
+
+	filter := ""
+	if name := req.GetFilter().GetImage().GetImage(); len(name) > 0 {
+		ref, err := meta.NewOCIImageRef(name)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		filter = ref.Normalized()
+	}
+
+	images := make([]*runtimeapi.Image, 0, len(refs))
+	for _, ref := range refs {
+		if len(filter) > 0 && filter != ref.Normalized() {
+			continue
+		}
+
+		if image := inspectImage(ref); image != nil {
+			images = append(images, image)
+		}
+	}
+
+	return &runtimeapi.ListImagesResponse{Images: images}, nil
+}
+
+// knownImages returns the images pulled over the CRI and the images of the containers
+func (s *Server) knownImages() ([]meta.OCIImageRef, error) {
+	containers, err := s.listContainers("", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refs := make([]meta.OCIImageRef, 0, len(s.pulled))
+	seen := map[string]bool{}
+	for name, ref := range s.pulled {
+		seen[name] = true
+		refs = append(refs, ref)
+	}
+
+	for _, c := range containers {
+		if seen[c.ImageRef] {
+			continue
+		}
+
+		ref, err := meta.NewOCIImageRef(c.ImageRef)
+		if err != nil {
+			continue
+		}
+
+		seen[c.ImageRef] = true
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+// ImageStatus returns the status of the image, or no image if the runtime doesn't have it
+func (s *Server) ImageStatus(ctx context.Context, req *runtimeapi.ImageStatusRequest) (*runtimeapi.ImageStatusResponse, error) {
+	ref, err := meta.NewOCIImageRef(req.GetImage().GetImage())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &runtimeapi.ImageStatusResponse{Image: inspectImage(ref)}, nil
+}
+
+// PullImage pulls the image with the container runtime. The credentials given by the kubelet
+// are ignored, the runtime pulls with its own registry configuration.
+func (s *Server) PullImage(ctx context.Context, req *runtimeapi.PullImageRequest) (*runtimeapi.PullImageResponse, error) {
+	ref, err := meta.NewOCIImageRef(req.GetImage().GetImage())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := auditedTarget(ctx, jobs.ClassImport, "PullImage", "image/"+ref.String(), func() error {
+		log.Infof("Pulling image %q for the kubelet...", ref)
+		return providers.Runtime.PullImage(ref)
+	}, "image="+ref.String()); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.pulled[ref.Normalized()] = ref
+	s.mu.Unlock()
+
+	return &runtimeapi.PullImageResponse{ImageRef: ref.Normalized()}, nil
+}
+
+// RemoveImage removes the image from the container runtime, removing an image that doesn't
+// exist succeeds
+func (s *Server) RemoveImage(ctx context.Context, req *runtimeapi.RemoveImageRequest) (*runtimeapi.RemoveImageResponse, error) {
+	ref, err := meta.NewOCIImageRef(req.GetImage().GetImage())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := providers.Runtime.InspectImage(ref); err == nil {
+		if err := auditedTarget(ctx, jobs.ClassRemove, "RemoveImage", "image/"+ref.String(), func() error {
+			return providers.Runtime.RemoveImage(ref)
+		}, "image="+ref.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.pulled, ref.Normalized())
+	s.mu.Unlock()
+
+	return &runtimeapi.RemoveImageResponse{}, nil
+}
+
+// ImageFsInfo reports the usage of the filesystem of the ignite data directory
+func (s *Server) ImageFsInfo(context.Context, *runtimeapi.ImageFsInfoRequest) (*runtimeapi.ImageFsInfoResponse, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(constants.DATA_DIR, &st); err != nil {
+		return nil, err
+	}
+
+	return &runtimeapi.ImageFsInfoResponse{
+		ImageFilesystems: []*runtimeapi.FilesystemUsage{
+			{
+				Timestamp:  time.Now().UnixNano(),
+				FsId:       &runtimeapi.FilesystemIdentifier{Mountpoint: constants.DATA_DIR},
+				UsedBytes:  &runtimeapi.UInt64Value{Value: (st.Blocks - st.Bfree) * uint64(st.Bsize)},
+				InodesUsed: &runtimeapi.UInt64Value{Value: st.Files - st.Ffree},
+			},
+		},
+	}, nil
+}
+
+// inspectImage returns the CRI image of the image in the container runtime, or nil if the
+// runtime doesn't have it
+func inspectImage(ref meta.OCIImageRef) *runtimeapi.Image {
+	res, err := providers.Runtime.InspectImage(ref)
+	if err != nil {
+		return nil
+	}
+
+	image := &runtimeapi.Image{
+		Id:       ref.Normalized(),
+		RepoTags: []string{ref.Normalized()},
+		Size_:    uint64(res.Size),
+	}
+
+	if res.ID != nil && !res.ID.Local() {
+		image.RepoDigests = []string{res.ID.RepoDigest().String()}
+	}
+
+	return image
+}
@@ -0,0 +1,130 @@
+package cri
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+// maxLogLine is the size after which a line without a newline is written as partial
+const maxLogLine = 16 * 1024
+
+// logFile is the log of a container on the host, which the kubelet may rotate
+type logFile struct {
+	path string
+	mu   sync.Mutex
+	f    *os.File
+}
+
+// openLogFile opens the log at logPath for appending, creating its directory if needed
+func openLogFile(logPath string) (*logFile, error) {
+	l := &logFile{path: logPath}
+	return l, l.Reopen()
+}
+
+// Reopen closes the log, and opens the file at its path again
+func (l *logFile) Reopen() error {
+	if err := os.MkdirAll(path.Dir(l.path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f != nil {
+		l.f.Close()
+	}
+	l.f = f
+	return nil
+}
+
+func (l *logFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Write(p)
+}
+
+func (l *logFile) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
+
+// logWriter writes the output of a stream of a container to its log in the CRI log format.
+// Every line is prefixed by the time it was written and the stream, and tagged F if it's
+// full, or P if it's the partial start of a longer line.
+type logWriter struct {
+	w      io.Writer
+	stream string
+	buf    []byte
+	now    func() time.Time
+}
+
+// newLogWriter returns a logWriter for the stream writing to the log, or discarding the output
+// if there's no log
+func newLogWriter(l *logFile, stream string) *logWriter {
+	lw := &logWriter{stream: stream, now: time.Now}
+	if l != nil {
+		lw.w = l
+	}
+
+	return lw
+}
+
+func (lw *logWriter) Write(p []byte) (int, error) {
+	if lw.w == nil {
+		return len(p), nil
+	}
+
+	lw.buf = append(lw.buf, p...)
+	for {
+		i := bytes.IndexByte(lw.buf, '\n')
+		if i < 0 {
+			break
+		} else if i > maxLogLine {
+			if err := lw.writeLine("P", lw.buf[:maxLogLine]); err != nil {
+				return 0, err
+			}
+			lw.buf = lw.buf[maxLogLine:]
+			continue
+		}
+
+		if err := lw.writeLine("F", lw.buf[:i]); err != nil {
+			return 0, err
+		}
+		lw.buf = lw.buf[i+1:]
+	}
+
+	for len(lw.buf) >= maxLogLine {
+		if err := lw.writeLine("P", lw.buf[:maxLogLine]); err != nil {
+			return 0, err
+		}
+		lw.buf = lw.buf[maxLogLine:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the rest of the output without a newline as a full line
+func (lw *logWriter) Flush() error {
+	if lw.w == nil || len(lw.buf) == 0 {
+		return nil
+	}
+
+	err := lw.writeLine("F", lw.buf)
+	lw.buf = nil
+	return err
+}
+
+func (lw *logWriter) writeLine(tag string, line []byte) error {
+	_, err := lw.w.Write([]byte(fmt.Sprintf("%s %s %s %s\n", lw.now().Format(time.RFC3339Nano), lw.stream, tag, line)))
+	return err
+}
@@ -0,0 +1,53 @@
+package cri
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2021, 5, 4, 12, 0, 0, 0, time.UTC)
+	lw := &logWriter{w: &buf, stream: "stdout", now: func() time.Time { return now }}
+
+	_, err := lw.Write([]byte("hello\nwor"))
+	assert.NilError(t, err)
+	_, err = lw.Write([]byte("ld\n\nunterminated"))
+	assert.NilError(t, err)
+	assert.NilError(t, lw.Flush())
+
+	assert.Equal(t, buf.String(), strings.Join([]string{
+		"2021-05-04T12:00:00Z stdout F hello",
+		"2021-05-04T12:00:00Z stdout F world",
+		"2021-05-04T12:00:00Z stdout F ",
+		"2021-05-04T12:00:00Z stdout F unterminated",
+		"",
+	}, "\n"))
+}
+
+func TestLogWriterPartial(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2021, 5, 4, 12, 0, 0, 0, time.UTC)
+	lw := &logWriter{w: &buf, stream: "stderr", now: func() time.Time { return now }}
+
+	long := strings.Repeat("a", maxLogLine+3)
+	_, err := lw.Write([]byte(long + "\n"))
+	assert.NilError(t, err)
+	assert.NilError(t, lw.Flush())
+
+	assert.Equal(t, buf.String(),
+		"2021-05-04T12:00:00Z stderr P "+long[:maxLogLine]+"\n"+
+			"2021-05-04T12:00:00Z stderr F aaa\n")
+}
+
+func TestLogWriterDiscard(t *testing.T) {
+	lw := newLogWriter(nil, "stdout")
+	n, err := lw.Write([]byte("dropped\n"))
+	assert.NilError(t, err)
+	assert.Equal(t, n, 8)
+	assert.NilError(t, lw.Flush())
+}
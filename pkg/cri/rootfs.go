@@ -0,0 +1,195 @@
+package cri
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/source"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// extractRootfs extracts the root filesystem of the container into the sandbox VM using the
+// guest agent. The agent keeps the files that exist already, so the files of the sandbox and
+// of the mounts are sent before the files of the image to take their place.
+func extractRootfs(vm *api.VM, sb *sandbox, c *container, ref meta.OCIImageRef) error {
+	src := source.NewDockerSource()
+	if _, err := src.Parse(ref); err != nil {
+		return err
+	}
+
+	image, err := src.Reader()
+	if err != nil {
+		return err
+	}
+	defer src.Cleanup()
+	defer image.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeRootfs(pw, c.rootfs(), sandboxFiles(sb), c.Mounts, image))
+	}()
+
+	err = agent.Extract(vm, pr)
+	// Unblock the writer if the agent stopped reading
+	pr.Close()
+	return err
+}
+
+// writeRootfs writes the tar stream of the root filesystem at root: the given files, the files
+// of the host paths of the mounts, and the files of the image tar stream
+func writeRootfs(w io.Writer, root string, files map[string]string, mounts []*runtimeapi.Mount, image io.Reader) error {
+	tw := tar.NewWriter(w)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     entryName(root, name),
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+			ModTime:  time.Now(),
+		}); err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(tw, files[name]); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range mounts {
+		if err := addHostPath(tw, m.HostPath, path.Join(root, m.ContainerPath)); err != nil {
+			return fmt.Errorf("failed to copy mount %q: %v", m.HostPath, err)
+		}
+	}
+
+	if err := prefixTar(tw, image, root); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// sandboxFiles returns the files of the pod sandbox in the root filesystems of its containers
+func sandboxFiles(sb *sandbox) map[string]string {
+	files := map[string]string{}
+	if len(sb.Hostname) > 0 {
+		files["etc/hostname"] = sb.Hostname + "\n"
+	}
+
+	if conf := resolvConf(sb.DNSConfig); len(conf) > 0 {
+		files["etc/resolv.conf"] = conf
+	}
+
+	return files
+}
+
+// resolvConf returns the resolv.conf of the DNS config, or an empty string if it has no settings
+func resolvConf(dns *runtimeapi.DNSConfig) string {
+	var sb strings.Builder
+	if len(dns.GetSearches()) > 0 {
+		fmt.Fprintf(&sb, "search %s\n", strings.Join(dns.Searches, " "))
+	}
+
+	for _, server := range dns.GetServers() {
+		fmt.Fprintf(&sb, "nameserver %s\n", server)
+	}
+
+	if len(dns.GetOptions()) > 0 {
+		fmt.Fprintf(&sb, "options %s\n", strings.Join(dns.Options, " "))
+	}
+
+	return sb.String()
+}
+
+// addHostPath writes the file or the directory tree at hostPath to the tar stream at target.
+// Symlinks are written as they are.
+func addHostPath(tw *tar.Writer, hostPath, target string) error {
+	return filepath.Walk(hostPath, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(hostPath, file)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = entryName(target, filepath.ToSlash(rel))
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// prefixTar copies the entries of the tar stream read from r to tw, moved below root
+func prefixTar(tw *tar.Writer, r io.Reader, root string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		hdr.Name = entryName(root, hdr.Name)
+		if hdr.Typeflag == tar.TypeLink {
+			// Hardlinks refer to their target by its name in the stream
+			hdr.Linkname = entryName(root, hdr.Linkname)
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// entryName returns the name of the tar entry of the file at name below root
+func entryName(root, name string) string {
+	return strings.TrimPrefix(path.Join(root, path.Clean("/"+name)), "/")
+}
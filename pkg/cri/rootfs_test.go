@@ -0,0 +1,114 @@
+package cri
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+type tarEntry struct {
+	Name     string
+	Typeflag byte
+	Linkname string
+	Content  string
+}
+
+func readTar(t *testing.T, r io.Reader) []tarEntry {
+	var entries []tarEntry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		assert.NilError(t, err)
+
+		content, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		entries = append(entries, tarEntry{hdr.Name, hdr.Typeflag, hdr.Linkname, string(content)})
+	}
+}
+
+func TestPrefixTar(t *testing.T) {
+	var image bytes.Buffer
+	tw := tar.NewWriter(&image)
+	for _, hdr := range []*tar.Header{
+		{Typeflag: tar.TypeDir, Name: "bin/", Mode: 0755},
+		{Typeflag: tar.TypeReg, Name: "bin/busybox", Mode: 0755, Size: 2},
+		{Typeflag: tar.TypeLink, Name: "bin/sh", Linkname: "bin/busybox"},
+		{Typeflag: tar.TypeSymlink, Name: "bin/ls", Linkname: "/bin/busybox"},
+		{Typeflag: tar.TypeReg, Name: "../escape", Mode: 0644},
+	} {
+		assert.NilError(t, tw.WriteHeader(hdr))
+		if hdr.Size > 0 {
+			_, err := tw.Write([]byte("bb"))
+			assert.NilError(t, err)
+		}
+	}
+	assert.NilError(t, tw.Close())
+
+	var out bytes.Buffer
+	tw = tar.NewWriter(&out)
+	assert.NilError(t, prefixTar(tw, &image, "/var/lib/ignite-cri/abc/rootfs"))
+	assert.NilError(t, tw.Close())
+
+	assert.DeepEqual(t, readTar(t, &out), []tarEntry{
+		{"var/lib/ignite-cri/abc/rootfs/bin", tar.TypeDir, "", ""},
+		{"var/lib/ignite-cri/abc/rootfs/bin/busybox", tar.TypeReg, "", "bb"},
+		// Hardlinks are moved along, symlinks resolve in the chroot
+		{"var/lib/ignite-cri/abc/rootfs/bin/sh", tar.TypeLink, "var/lib/ignite-cri/abc/rootfs/bin/busybox", ""},
+		{"var/lib/ignite-cri/abc/rootfs/bin/ls", tar.TypeSymlink, "/bin/busybox", ""},
+		{"var/lib/ignite-cri/abc/rootfs/escape", tar.TypeReg, "", ""},
+	})
+}
+
+func TestAddHostPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cri-mount-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NilError(t, os.MkdirAll(filepath.Join(dir, "..data"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "..data", "key"), []byte("value"), 0644))
+	assert.NilError(t, os.Symlink("..data/key", filepath.Join(dir, "key")))
+
+	var out bytes.Buffer
+	tw := tar.NewWriter(&out)
+	assert.NilError(t, addHostPath(tw, dir, "/rootfs/etc/config"))
+	assert.NilError(t, tw.Close())
+
+	assert.DeepEqual(t, readTar(t, &out), []tarEntry{
+		{"rootfs/etc/config", tar.TypeDir, "", ""},
+		{"rootfs/etc/config/..data", tar.TypeDir, "", ""},
+		{"rootfs/etc/config/..data/key", tar.TypeReg, "", "value"},
+		{"rootfs/etc/config/key", tar.TypeSymlink, "..data/key", ""},
+	})
+}
+
+func TestResolvConf(t *testing.T) {
+	tests := []struct {
+		dns      *runtimeapi.DNSConfig
+		expected string
+	}{
+		{nil, ""},
+		{&runtimeapi.DNSConfig{}, ""},
+		{
+			&runtimeapi.DNSConfig{
+				Servers:  []string{"10.96.0.10"},
+				Searches: []string{"default.svc.cluster.local", "svc.cluster.local"},
+				Options:  []string{"ndots:5"},
+			},
+			"search default.svc.cluster.local svc.cluster.local\nnameserver 10.96.0.10\noptions ndots:5\n",
+		},
+	}
+
+	for _, rt := range tests {
+		assert.Equal(t, resolvConf(rt.dns), rt.expected)
+	}
+}
@@ -0,0 +1,433 @@
+package cri
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/agent"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/audit"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/policy"
+	"github.com/weaveworks/ignite/pkg/preflight/checkers"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/sets"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// sandbox is the pod sandbox a VM is created for, stored in its IGNITE_CRI_SANDBOX_ANNOTATION
+type sandbox struct {
+	Metadata    *runtimeapi.PodSandboxMetadata `json:"metadata"`
+	Labels      map[string]string              `json:"labels,omitempty"`
+	Annotations map[string]string              `json:"annotations,omitempty"`
+	// LogDirectory is the directory on the host the logs of the containers are written to
+	LogDirectory string                `json:"logDirectory,omitempty"`
+	Hostname     string                `json:"hostname,omitempty"`
+	DNSConfig    *runtimeapi.DNSConfig `json:"dnsConfig,omitempty"`
+	CreatedAt    int64                 `json:"createdAt"`
+}
+
+// sandboxOf returns the pod sandbox of the VM, or nil if it isn't a sandbox VM
+func sandboxOf(vm *api.VM) *sandbox {
+	value := vm.GetAnnotation(constants.IGNITE_CRI_SANDBOX_ANNOTATION)
+	if len(value) == 0 {
+		return nil
+	}
+
+	sb := &sandbox{}
+	if err := json.Unmarshal([]byte(value), sb); err != nil {
+		log.Warnf("Ignoring the invalid pod sandbox of VM %q: %v", vm.GetUID(), err)
+		return nil
+	}
+
+	return sb
+}
+
+// sandboxName returns the name of the VM of the pod sandbox. Namespaces can't contain dots,
+// and the attempt is a number, so names of different pods don't collide.
+func sandboxName(md *runtimeapi.PodSandboxMetadata) string {
+	return fmt.Sprintf("%s.%s.%d", md.Namespace, md.Name, md.Attempt)
+}
+
+// sandboxVMs returns the VMs that are pod sandboxes
+func (s *Server) sandboxVMs() ([]*api.VM, error) {
+	vms, err := s.client.VMs().List()
+	if err != nil {
+		return nil, err
+	}
+
+	var sandboxVMs []*api.VM
+	for _, vm := range vms {
+		if sandboxOf(vm) != nil {
+			sandboxVMs = append(sandboxVMs, vm)
+		}
+	}
+
+	return sandboxVMs, nil
+}
+
+// findSandbox returns the VM of the pod sandbox with the given ID, or a prefix of it
+func (s *Server) findSandbox(id string) (*api.VM, *sandbox, error) {
+	if len(id) == 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "no pod sandbox ID given")
+	}
+
+	vms, err := s.sandboxVMs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var found *api.VM
+	for _, vm := range vms {
+		if !strings.HasPrefix(vm.GetUID().String(), id) {
+			continue
+		}
+
+		if found != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "pod sandbox ID %q is ambiguous", id)
+		}
+		found = vm
+	}
+
+	if found == nil {
+		return nil, nil, status.Errorf(codes.NotFound, "pod sandbox %q not found", id)
+	}
+
+	return found, sandboxOf(found), nil
+}
+
+// RunPodSandbox creates and starts a VM for the pod sandbox, and waits for its guest agent
+func (s *Server) RunPodSandbox(ctx context.Context, req *runtimeapi.RunPodSandboxRequest) (*runtimeapi.RunPodSandboxResponse, error) {
+	cfg := req.GetConfig()
+	if cfg.GetMetadata() == nil {
+		return nil, status.Error(codes.InvalidArgument, "no pod sandbox metadata given")
+	}
+
+	if len(req.RuntimeHandler) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "runtime handler %q isn't supported", req.RuntimeHandler)
+	}
+
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	vm, err := s.newSandboxVM(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := audited(ctx, jobs.ClassStart, "RunPodSandbox", vm, func() error {
+		return s.runSandbox(ctx, vm)
+	}); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Started VM %q with name %q for pod sandbox %s/%s", vm.GetUID(), vm.GetName(), cfg.Metadata.Namespace, cfg.Metadata.Name)
+	return &runtimeapi.RunPodSandboxResponse{PodSandboxId: vm.GetUID().String()}, nil
+}
+
+// newSandboxVM returns the VM of the pod sandbox, with the VM defaults of the component config
+func (s *Server) newSandboxVM(cfg *runtimeapi.PodSandboxConfig) (*api.VM, error) {
+	vm := s.client.VMs().New()
+	if providers.ComponentConfig != nil {
+		vm.Spec = *providers.ComponentConfig.Spec.VMDefaults.DeepCopy()
+	}
+
+	vm.Name = sandboxName(cfg.Metadata)
+	vm.Spec.Image.OCI = s.opts.SandboxImage
+
+	for _, pm := range cfg.PortMappings {
+		if pm.HostPort == 0 {
+			continue
+		}
+
+		protocol := meta.ProtocolTCP
+		switch pm.Protocol {
+		case runtimeapi.Protocol_TCP:
+		case runtimeapi.Protocol_UDP:
+			protocol = meta.ProtocolUDP
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "port mapping protocol %s isn't supported", pm.Protocol)
+		}
+
+		vm.Spec.Network.Ports = append(vm.Spec.Network.Ports, meta.PortMapping{
+			BindAddress: net.ParseIP(pm.HostIp),
+			HostPort:    uint64(pm.HostPort),
+			VMPort:      uint64(pm.ContainerPort),
+			Protocol:    protocol,
+		})
+	}
+
+	sb, err := json.Marshal(&sandbox{
+		Metadata:     cfg.Metadata,
+		Labels:       cfg.Labels,
+		Annotations:  cfg.Annotations,
+		LogDirectory: cfg.LogDirectory,
+		Hostname:     cfg.Hostname,
+		DNSConfig:    cfg.DnsConfig,
+		CreatedAt:    time.Now().UnixNano(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	vm.SetAnnotation(constants.IGNITE_CRI_SANDBOX_ANNOTATION, string(sb))
+
+	vm.Status.IDPrefix = providers.IDPrefix
+	vm.Status.Runtime = &api.Runtime{Name: providers.RuntimeName}
+	vm.Status.Network = &api.Network{Plugin: providers.NetworkPluginName}
+	vm.Status.Snapshotter = providers.SnapshotterName
+
+	if err := metadata.SetNameAndUID(vm, s.client); err != nil {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	if err := validation.ValidateVM(vm).ToAggregate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return vm, nil
+}
+
+// runSandbox creates and starts the sandbox VM like "ignite run", and waits for its guest
+// agent. A VM that failed to start is removed, the kubelet only knows about sandboxes that ran.
+func (s *Server) runSandbox(ctx context.Context, vm *api.VM) (err error) {
+	config.ResolveVMProviders(vm)
+	if err = config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+		return
+	}
+
+	image, kernel, err := s.sandboxImageAndKernel(ctx, vm)
+	if err != nil {
+		return
+	}
+	vm.SetImage(image)
+	vm.SetKernel(kernel)
+
+	if err = policy.CheckVM(ctx, policy.OperationVMCreate, vm); err != nil {
+		return
+	}
+
+	func() {
+		defer util.DeferErr(&err, func() error { return metadata.Cleanup(vm, false) })
+		if err = s.client.VMs().Set(vm); err != nil {
+			return
+		}
+
+		if err = operations.AllocateAndPopulateOverlay(vm); err != nil {
+			return
+		}
+
+		err = metadata.Success(vm)
+	}()
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			if rmErr := operations.DeleteVM(s.client, vm); rmErr != nil {
+				log.Errorf("Failed to remove VM %q of the pod sandbox that failed to start: %v", vm.GetUID(), rmErr)
+			}
+		}
+	}()
+
+	if err = checkers.StartCmdChecks(vm, sets.NewString()); err != nil {
+		return
+	}
+
+	if err = operations.StartVM(ctx, vm, false); err != nil {
+		return
+	}
+
+	if err = agent.Wait(vm, constants.CRI_SANDBOX_TIMEOUT); err != nil {
+		return fmt.Errorf("the guest agent of the sandbox image %q is needed: %w", s.opts.SandboxImage, err)
+	}
+
+	return
+}
+
+// sandboxImageAndKernel returns the sandbox image and the kernel of the VM. The sandbox image
+// is imported with the guest agent if it isn't imported yet.
+func (s *Server) sandboxImageAndKernel(ctx context.Context, vm *api.VM) (image *api.Image, kernel *api.Kernel, err error) {
+	err = jobs.Run(ctx, jobs.ClassImport, "image/"+vm.Spec.Image.OCI.String(), audit.SourceCRI, func() error {
+		image, err = s.client.Images().Find(filter.NewIDNameFilter(vm.Spec.Image.OCI.String()))
+		if filterer.IsNonexistentError(err) {
+			var agentBinary string
+			if agentBinary, err = agent.Binary(); err != nil {
+				return err
+			}
+
+			image, err = operations.ImportImageWithAgent(ctx, s.client, vm.Spec.Image.OCI, agentBinary)
+		}
+		if err != nil {
+			return err
+		}
+
+		kernel, err = operations.FindOrImportKernel(ctx, s.client, vm.Spec.Kernel.OCI)
+		return err
+	})
+
+	return
+}
+
+// StopPodSandbox kills the containers of the pod sandbox, and stops its VM
+func (s *Server) StopPodSandbox(ctx context.Context, req *runtimeapi.StopPodSandboxRequest) (*runtimeapi.StopPodSandboxResponse, error) {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	vm, _, err := s.findSandbox(req.PodSandboxId)
+	if status.Code(err) == codes.NotFound {
+		return &runtimeapi.StopPodSandboxResponse{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.stopSandbox(ctx, vm); err != nil {
+		return nil, err
+	}
+
+	return &runtimeapi.StopPodSandboxResponse{}, nil
+}
+
+// stopSandbox kills the running containers of the VM, and stops it if it's running
+func (s *Server) stopSandbox(ctx context.Context, vm *api.VM) error {
+	if !vm.Running() {
+		return nil
+	}
+
+	s.killContainers(vm)
+
+	if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+		return err
+	}
+
+	return audited(ctx, jobs.ClassStop, "StopPodSandbox", vm, func() error {
+		return operations.StopVM(ctx, vm, false, false)
+	})
+}
+
+// RemovePodSandbox kills the containers of the pod sandbox, and removes its VM
+func (s *Server) RemovePodSandbox(ctx context.Context, req *runtimeapi.RemovePodSandboxRequest) (*runtimeapi.RemovePodSandboxResponse, error) {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	vm, _, err := s.findSandbox(req.PodSandboxId)
+	if status.Code(err) == codes.NotFound {
+		return &runtimeapi.RemovePodSandboxResponse{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if vm.Running() {
+		s.killContainers(vm)
+
+		if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+			return nil, err
+		}
+	}
+
+	// The records of the containers are removed with the directory of the VM
+	if err := audited(ctx, jobs.ClassRemove, "RemovePodSandbox", vm, func() error {
+		return operations.DeleteVM(s.client, vm)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &runtimeapi.RemovePodSandboxResponse{}, nil
+}
+
+// PodSandboxStatus returns the state and the address of the sandbox VM
+func (s *Server) PodSandboxStatus(ctx context.Context, req *runtimeapi.PodSandboxStatusRequest) (*runtimeapi.PodSandboxStatusResponse, error) {
+	vm, sb, err := s.findSandbox(req.PodSandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxStatus := &runtimeapi.PodSandboxStatus{
+		Id:          vm.GetUID().String(),
+		Metadata:    sb.Metadata,
+		State:       sandboxState(vm),
+		CreatedAt:   sb.CreatedAt,
+		Network:     &runtimeapi.PodSandboxNetworkStatus{},
+		Labels:      sb.Labels,
+		Annotations: sb.Annotations,
+	}
+
+	if vm.Running() && vm.Status.Network != nil {
+		for i, ip := range vm.Status.Network.IPAddresses {
+			if i == 0 {
+				sandboxStatus.Network.Ip = ip.String()
+			} else {
+				sandboxStatus.Network.AdditionalIps = append(sandboxStatus.Network.AdditionalIps, &runtimeapi.PodIP{Ip: ip.String()})
+			}
+		}
+	}
+
+	return &runtimeapi.PodSandboxStatusResponse{Status: sandboxStatus}, nil
+}
+
+// ListPodSandbox lists the sandbox VMs matching the filter
+func (s *Server) ListPodSandbox(ctx context.Context, req *runtimeapi.ListPodSandboxRequest) (*runtimeapi.ListPodSandboxResponse, error) {
+	vms, err := s.sandboxVMs()
+	if err != nil {
+		return nil, err
+	}
+
+	f := req.GetFilter()
+	resp := &runtimeapi.ListPodSandboxResponse{}
+	for _, vm := range vms {
+		sb := sandboxOf(vm)
+		state := sandboxState(vm)
+		if !strings.HasPrefix(vm.GetUID().String(), f.GetId()) ||
+			(f.GetState() != nil && f.GetState().State != state) ||
+			!matchLabels(f.GetLabelSelector(), sb.Labels) {
+			continue
+		}
+
+		resp.Items = append(resp.Items, &runtimeapi.PodSandbox{
+			Id:          vm.GetUID().String(),
+			Metadata:    sb.Metadata,
+			State:       state,
+			CreatedAt:   sb.CreatedAt,
+			Labels:      sb.Labels,
+			Annotations: sb.Annotations,
+		})
+	}
+
+	return resp, nil
+}
+
+// sandboxState returns whether the pod sandbox is ready, i.e. its VM is running
+func sandboxState(vm *api.VM) runtimeapi.PodSandboxState {
+	if vm.Running() {
+		return runtimeapi.PodSandboxState_SANDBOX_READY
+	}
+
+	return runtimeapi.PodSandboxState_SANDBOX_NOTREADY
+}
+
+// matchLabels returns true if the labels have all the keys and values of the selector
+func matchLabels(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+
+	return true
+}
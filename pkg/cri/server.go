@@ -0,0 +1,132 @@
+// Package cri implements the Container Runtime Interface of Kubernetes on top of the VMs of
+// ignite, so a kubelet can run pods in them. The sandbox of a pod is a VM based on the sandbox
+// image, which needs the guest agent. The containers of the pod are processes the agent runs
+// chrooted into the root filesystems of their images, which are extracted into the VM.
+package cri
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/audit"
+	"github.com/weaveworks/ignite/pkg/client"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/version"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"google.golang.org/grpc"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	// runtimeName is the name of the runtime reported to the kubelet
+	runtimeName = "ignite"
+	// runtimeAPIVersion is the version of the CRI served
+	runtimeAPIVersion = "v1"
+	// auditActor is the actor of the operations requested over the CRI
+	auditActor = "kubelet"
+)
+
+// Options configures the CRI server
+type Options struct {
+	// SandboxImage is the image of the pod sandbox VMs. It's imported with the guest
+	// agent if it isn't imported yet, an imported image needs to have the agent.
+	SandboxImage meta.OCIImageRef
+}
+
+// Server serves the CRI runtime and image services. The lifecycle operations of the sandbox
+// VMs are serialized with the ones of the management API, as they share the process-global
+// providers.
+type Server struct {
+	runtimeapi.UnimplementedRuntimeServiceServer
+	runtimeapi.UnimplementedImageServiceServer
+
+	client *client.Client
+	opts   Options
+	// lifecycle is held while the sandbox VMs are created, started, stopped and removed
+	lifecycle sync.Locker
+
+	// mu guards the container records and the running containers
+	mu      sync.Mutex
+	running map[string]*process
+	// pulled holds the images pulled by the kubelet since ignited started
+	pulled map[string]meta.OCIImageRef
+}
+
+// New returns a Server for the VMs of the given client, lifecycle serializes the lifecycle
+// operations with the other servers of ignited
+func New(c *client.Client, lifecycle sync.Locker, opts Options) *Server {
+	return &Server{
+		client:    c,
+		opts:      opts,
+		lifecycle: lifecycle,
+		running:   map[string]*process{},
+		pulled:    map[string]meta.OCIImageRef{},
+	}
+}
+
+// Serve serves the CRI on the listener until it's closed. The containers that were running
+// when ignited stopped are recorded as exited first, the guest agent kills them when their
+// exec connection is lost.
+func (s *Server) Serve(l net.Listener) error {
+	if err := s.recordLostContainers(); err != nil {
+		log.Errorf("Failed to record the containers lost since ignited stopped: %v", err)
+	}
+
+	gs := grpc.NewServer()
+	runtimeapi.RegisterRuntimeServiceServer(gs, s)
+	runtimeapi.RegisterImageServiceServer(gs, s)
+
+	log.Infof("Serving the CRI on %s", l.Addr())
+	return gs.Serve(l)
+}
+
+// Version returns the name and version of the runtime
+func (s *Server) Version(context.Context, *runtimeapi.VersionRequest) (*runtimeapi.VersionResponse, error) {
+	return &runtimeapi.VersionResponse{
+		Version:           runtimeAPIVersion,
+		RuntimeName:       runtimeName,
+		RuntimeVersion:    version.GetIgnite().String(),
+		RuntimeApiVersion: runtimeAPIVersion,
+	}, nil
+}
+
+// Status reports the runtime and the network as ready, the network of the sandbox VMs is set
+// up by the network plugin of ignite when they start
+func (s *Server) Status(context.Context, *runtimeapi.StatusRequest) (*runtimeapi.StatusResponse, error) {
+	return &runtimeapi.StatusResponse{
+		Status: &runtimeapi.RuntimeStatus{
+			Conditions: []*runtimeapi.RuntimeCondition{
+				{Type: runtimeapi.RuntimeReady, Status: true},
+				{Type: runtimeapi.NetworkReady, Status: true},
+			},
+		},
+	}, nil
+}
+
+// UpdateRuntimeConfig ignores the pod CIDR of the node, the addresses of the sandbox VMs are
+// allocated by the network plugin of ignite
+func (s *Server) UpdateRuntimeConfig(context.Context, *runtimeapi.UpdateRuntimeConfigRequest) (*runtimeapi.UpdateRuntimeConfigResponse, error) {
+	return &runtimeapi.UpdateRuntimeConfigResponse{}, nil
+}
+
+// audited runs the operation on the object as a job of the class, and records it in the
+// audit log as requested by the kubelet
+func audited(ctx context.Context, class jobs.Class, operation string, obj runtime.Object, fn func() error) error {
+	return auditedTarget(ctx, class, operation, jobs.Target(obj), fn,
+		fmt.Sprintf("uid=%s", obj.GetUID()), fmt.Sprintf("name=%s", obj.GetName()))
+}
+
+// auditedTarget runs the operation on the target as a job of the class, and records it in
+// the audit log with the given arguments
+func auditedTarget(ctx context.Context, class jobs.Class, operation, target string, fn func() error, args ...string) error {
+	return jobs.Run(ctx, class, target, audit.SourceCRI, func() error {
+		entry := audit.Begin(audit.SourceCRI, auditActor, operation, args...)
+		err := fn()
+		entry.Finish(err)
+		return err
+	})
+}
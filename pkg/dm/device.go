@@ -2,6 +2,7 @@ package dm
 
 import (
 	"fmt"
+	"os"
 	"path"
 
 	log "github.com/sirupsen/logrus"
@@ -14,8 +15,7 @@ import (
 
 type Device struct {
 	*api.PoolDevice
-	prefixer *util.Prefixer
-	pool     *Pool
+	pool *Pool
 
 	// These flags are for filesystem and snapshot creation
 	mkfs   bool
@@ -27,7 +27,35 @@ var _ blockDevice = &Device{}
 // Additional space to add to volumes to compensate for the ext4 partition
 var extraSize = meta.NewSizeFromBytes(constants.POOL_VOLUME_EXTRA_SIZE)
 
+// CreateVolume creates a new thin volume in the pool with an empty filesystem
 func (p *Pool) CreateVolume(size meta.Size, metadataPath string) (*Device, error) {
+	// This is a new volume, create a new filesystem for it on activation
+	return p.createVolume(size.Add(extraSize), metadataPath, true)
+}
+
+// ImportVolume creates a new thin volume in the pool holding a copy of the given filesystem image
+func (p *Pool) ImportVolume(file string, metadataPath string) (*Device, error) {
+	fi, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, err := p.createVolume(meta.NewSizeFromBytes(uint64(fi.Size())), metadataPath, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// A new thin volume reads as zeros, so only the non-zero blocks need to be
+	// written. This keeps the volume as sparse as the image file.
+	log.Infof("Importing %q into device: %s\n", file, volume.ID())
+	if _, err := util.ExecuteCommand("dd", "if="+file, "of="+volume.Path(), "bs=1M", "conv=sparse"); err != nil {
+		return nil, err
+	}
+
+	return volume, nil
+}
+
+func (p *Pool) createVolume(size meta.Size, metadataPath string, mkfs bool) (*Device, error) {
 	// The pool needs to be active for this
 	if err := p.activate(); err != nil {
 		return nil, err
@@ -40,12 +68,12 @@ func (p *Pool) CreateVolume(size meta.Size, metadataPath string) (*Device, error
 
 		return &Device{
 			PoolDevice: &api.PoolDevice{
-				Size: size.Add(extraSize),
-				// Parent:       nil,
+				Size:         size,
+				Parent:       meta.NewPoolDMID(), // Volumes are based on the pool itself
 				MetadataPath: metadataPath,
 			},
 			pool: p,
-			mkfs: true, // This is a new volume, create a new filesystem for it on activation
+			mkfs: mkfs,
 		}, nil
 	}); err != nil {
 		return nil, err
@@ -91,6 +119,20 @@ func (d *Device) CreateSnapshot(size meta.Size, metadataPath string) (*Device, e
 	}
 }
 
+// Activate activates the device and all its parents, so that it can be used
+func (d *Device) Activate() error {
+	return d.activate()
+}
+
+// Deactivate removes the device from device mapper, its data stays in the pool
+func (d *Device) Deactivate() error {
+	if !d.active() {
+		return nil
+	}
+
+	return dmsetup("remove", "--verifyudev", d.name(d.ID()))
+}
+
 func (d *Device) activate() error {
 	id := d.pool.getID(d)
 	parent := d.pool.GetDevice(d.Parent)
@@ -154,11 +196,28 @@ func (d *Device) Import(src source.Source) (*util.MountPoint, error) {
 }
 
 func (d *Device) name(id meta.DMID) string {
-	return d.prefixer.Prefix(id.String())
+	return deviceName(id)
+}
+
+// ID returns the ID of the device in the pool
+func (d *Device) ID() meta.DMID {
+	return d.pool.getID(d)
 }
 
 func (d *Device) Path() string {
-	return path.Join("/dev/mapper", d.name(d.pool.getID(d)))
+	return DevicePath(d.ID())
+}
+
+// DevicePath returns the path of the active device with the given ID.
+// This doesn't need the pool, so it can be used to reference devices
+// from places where the pool isn't available, like the VM container.
+func DevicePath(id meta.DMID) string {
+	return path.Join("/dev/mapper", deviceName(id))
+}
+
+func deviceName(id meta.DMID) string {
+	// The prefixer is stateful, so use a new one for every name
+	return util.NewPrefixer(constants.IGNITE_PREFIX).Prefix(id.String())
 }
 
 // If /dev/mapper/<name> exists the device is active
@@ -203,13 +203,38 @@ func (p *Pool) newDevice(genFunc func(meta.DMID) (*Device, error)) (*Device, err
 	device, err := genFunc(id)
 	if err != nil {
 		p.free = free
-	} else {
-		p.Status.Devices[id.Index()] = device.PoolDevice
+		return nil, err
 	}
 
+	p.Status.Devices[id.Index()] = device.PoolDevice
 	return device, nil
 }
 
+// DeleteDevice deactivates the device with the given ID and
+// deletes it from the pool, releasing its allocated space
+func (p *Pool) DeleteDevice(id meta.DMID) error {
+	device := p.GetDevice(id)
+	if device == nil {
+		return nil
+	}
+
+	if err := device.Deactivate(); err != nil {
+		return err
+	}
+
+	// The pool needs to be active for this
+	if err := p.activate(); err != nil {
+		return err
+	}
+
+	if err := dmsetup("message", p.Path(), "0", fmt.Sprintf("delete %s", id)); err != nil {
+		return err
+	}
+
+	p.Remove(id)
+	return nil
+}
+
 func (p *Pool) Remove(id meta.DMID) {
 	if p.GetDevice(id) != nil {
 		p.Status.Devices[id.Index()] = nil
@@ -1,14 +1,10 @@
 package dmlegacy
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
-	"github.com/nightlyone/lockfile"
-
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/flock"
 	"github.com/weaveworks/ignite/pkg/util"
 )
 
@@ -17,20 +13,11 @@ const dmsetupNotFound = "No such device or address"
 
 // DeactivateSnapshot deactivates the snapshot by removing it with dmsetup
 func DeactivateSnapshot(vm *api.VM) error {
-	// Global lock path.
-	glpath := filepath.Join(os.TempDir(), snapshotLockFileName)
-
-	// Create a lockfile and obtain a lock.
-	lock, err := lockfile.New(glpath)
+	unlock, err := flock.Lock(snapshotLock)
 	if err != nil {
-		err = fmt.Errorf("failed to create lockfile: %w", err)
-		return err
-	}
-	if err = obtainLock(lock); err != nil {
 		return err
 	}
-	// Release the lock at the end.
-	defer util.DeferErr(&err, lock.Unlock)
+	defer unlock()
 
 	dmArgs := []string{
 		"remove",
@@ -0,0 +1,81 @@
+package dmlegacy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+const (
+	// vmProfileEnvFile is sourced by login shells
+	vmProfileEnvFile = "/etc/profile.d/ignite-env.sh"
+	// vmEnvironmentFile can be used by systemd units with EnvironmentFile=
+	vmEnvironmentFile = "/etc/ignite/environment"
+)
+
+// envEscaper escapes the characters that are special in double quotes, both for
+// shells and for the environment files of systemd
+var envEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+
+// WriteEnvironment mounts the activated snapshot device of the VM and writes the
+// environment variables of the VM into it. The files are removed if the VM doesn't
+// set any environment variables.
+func WriteEnvironment(vm *api.VM, devicePath string) (err error) {
+	mp, err := util.Mount(devicePath)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, mp.Umount)
+
+	return writeEnvFiles(mp.Path, vm.Spec.Env)
+}
+
+func writeEnvFiles(mountPoint string, env map[string]string) error {
+	profileFile := path.Join(mountPoint, vmProfileEnvFile)
+	environmentFile := path.Join(mountPoint, vmEnvironmentFile)
+
+	if len(env) == 0 {
+		for _, file := range []string{profileFile, environmentFile} {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var profile, environment strings.Builder
+	for _, b := range []*strings.Builder{&profile, &environment} {
+		b.WriteString("# Written by ignite when the VM starts, set spec.env to change these variables\n")
+	}
+
+	for _, name := range names {
+		value := envEscaper.Replace(env[name])
+		fmt.Fprintf(&profile, "export %s=\"%s\"\n", name, value)
+		fmt.Fprintf(&environment, "%s=\"%s\"\n", name, value)
+	}
+
+	for file, content := range map[string]string{profileFile: profile.String(), environmentFile: environment.String()} {
+		if err := os.MkdirAll(path.Dir(file), 0755); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
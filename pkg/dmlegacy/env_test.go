@@ -0,0 +1,43 @@
+package dmlegacy
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestWriteEnvFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-env-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	env := map[string]string{
+		"HTTP_PROXY": "http://proxy:3128",
+		"GREETING":   `say "hi" to $USER`,
+	}
+	assert.NilError(t, writeEnvFiles(dir, env))
+
+	profile, err := ioutil.ReadFile(path.Join(dir, vmProfileEnvFile))
+	assert.NilError(t, err)
+	assert.Equal(t, string(profile), "# Written by ignite when the VM starts, set spec.env to change these variables\n"+
+		"export GREETING=\"say \\\"hi\\\" to \\$USER\"\n"+
+		"export HTTP_PROXY=\"http://proxy:3128\"\n")
+
+	environment, err := ioutil.ReadFile(path.Join(dir, vmEnvironmentFile))
+	assert.NilError(t, err)
+	assert.Equal(t, string(environment), "# Written by ignite when the VM starts, set spec.env to change these variables\n"+
+		"GREETING=\"say \\\"hi\\\" to \\$USER\"\n"+
+		"HTTP_PROXY=\"http://proxy:3128\"\n")
+
+	// Without environment variables, the files are removed
+	assert.NilError(t, writeEnvFiles(dir, nil))
+	for _, file := range []string{vmProfileEnvFile, vmEnvironmentFile} {
+		_, err := os.Stat(path.Join(dir, file))
+		assert.Assert(t, os.IsNotExist(err), "expected %s to be removed", file)
+	}
+}
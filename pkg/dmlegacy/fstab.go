@@ -10,30 +10,55 @@ import (
 	"text/tabwriter"
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/nbd"
 	"github.com/weaveworks/ignite/pkg/util"
 )
 
 const (
-	mountOptions = "rw,relatime"
+	mountOptions      = "rw,relatime"
+	tmpfsMountOptions = "rw,nosuid,nodev"
 )
 
 var (
 	blkidUUIDRegex = regexp.MustCompile("UUID=\"([^ ]*)\"")
+
+	// defaultWritablePaths are the writable paths of VMs with a read-only root that don't set any
+	defaultWritablePaths = []string{"/tmp", "/var/tmp", "/var/log"}
 )
 
 type fstabEntry struct {
 	uuid       string
 	mountPoint string
+	// tmpfs marks a RAM-backed volume, which has no UUID
+	tmpfs     bool
+	tmpfsSize meta.Size
 }
 
 var _ fmt.Stringer = &fstabEntry{}
 
 func (f *fstabEntry) isValid() bool {
-	// An entry is valid if both the UUID and mount point are set
-	return len(f.uuid) > 0 && len(f.mountPoint) > 0
+	// An entry is valid if both the UUID (or tmpfs) and mount point are set
+	return (len(f.uuid) > 0 || f.tmpfs) && len(f.mountPoint) > 0
 }
 
 func (f *fstabEntry) String() string {
+	if f.tmpfs {
+		options := tmpfsMountOptions
+		if f.tmpfsSize != meta.EmptySize {
+			options = fmt.Sprintf("%s,size=%d", options, f.tmpfsSize.Bytes())
+		}
+
+		return strings.Join([]string{
+			"tmpfs",      // tmpfs has no backing device
+			f.mountPoint, // The mount point for the volume
+			"tmpfs",      // The volume is RAM-backed
+			options,      // Use the tmpfs mount options, limited to the requested size
+			"0",          // Don't dump the filesystem
+			"0",          // There's nothing to fsck
+		}, "\t")
+	}
+
 	return strings.Join([]string{
 		fmt.Sprintf("UUID=%s", f.uuid), // Mount by UUID
 		f.mountPoint,                   // The mount point for the volume
@@ -57,12 +82,24 @@ func populateFstab(vm *api.VM, mountPoint string) error {
 
 	// Discover all volumes
 	for _, volume := range vm.Spec.Storage.Volumes {
-		if volume.BlockDevice == nil {
-			continue // Skip all non block device volumes for now
+		// tmpfs volumes are mounted by the guest on boot and need no device
+		if volume.Tmpfs != nil {
+			entries[volume.Name] = &fstabEntry{tmpfs: true, tmpfsSize: volume.Tmpfs.Size}
+			continue
 		}
 
-		// Retrieve the UUID for the block device
-		uuid, err := getUUID(volume.BlockDevice.Path)
+		var uuid string
+		var err error
+		switch {
+		case volume.BlockDevice != nil:
+			// Retrieve the UUID for the block device
+			uuid, err = getUUID(volume.BlockDevice.Path)
+		case volume.NBD != nil:
+			// Retrieve the UUID from the remote disk, it's only connected while the VM runs
+			uuid, err = getNBDUUID(vm, &volume)
+		default:
+			continue // Skip all volumes not backed by a device
+		}
 		if err != nil {
 			return err
 		}
@@ -80,6 +117,11 @@ func populateFstab(vm *api.VM, mountPoint string) error {
 		}
 	}
 
+	// The writable paths of a read-only root are RAM-backed, unless a volume is mounted there
+	for _, p := range writablePaths(&vm.Spec.Storage) {
+		entries[p] = &fstabEntry{tmpfs: true, mountPoint: p}
+	}
+
 	for _, entry := range entries {
 		if entry.isValid() {
 			// Write the entry to /etc/fstab
@@ -95,6 +137,43 @@ func populateFstab(vm *api.VM, mountPoint string) error {
 	return writer.Flush()
 }
 
+// writablePaths returns the writable paths of the storage with a read-only root,
+// which no volume is mounted at
+func writablePaths(storage *api.VMStorageSpec) (paths []string) {
+	if !storage.ReadOnlyRoot {
+		return nil
+	}
+
+	mounted := make(map[string]bool, len(storage.VolumeMounts))
+	for _, volumeMount := range storage.VolumeMounts {
+		mounted[volumeMount.MountPath] = true
+	}
+
+	candidates := storage.WritablePaths
+	if len(candidates) == 0 {
+		candidates = defaultWritablePaths
+	}
+
+	for _, p := range candidates {
+		if !mounted[p] {
+			paths = append(paths, p)
+		}
+	}
+
+	return
+}
+
+// getNBDUUID temporarily connects the given NBD volume to retrieve its UUID
+func getNBDUUID(vm *api.VM, volume *api.Volume) (uuid string, err error) {
+	devicePath, err := nbd.ConnectVolume(vm, volume)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, func() error { return nbd.DisconnectVolume(vm, volume.Name) })
+
+	return getUUID(devicePath)
+}
+
 func getUUID(devPath string) (string, error) {
 	// running blkid requires root
 	// we parse the output with regex because the `-o value -s UUID` format flags are not portable (ex: Alpine Linux)
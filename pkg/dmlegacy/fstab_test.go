@@ -0,0 +1,79 @@
+package dmlegacy
+
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+func TestFstabEntryString(t *testing.T) {
+	cases := []struct {
+		name     string
+		entry    *fstabEntry
+		expected string
+	}{
+		{
+			name:     "block device",
+			entry:    &fstabEntry{uuid: "1234-abcd", mountPoint: "/mnt"},
+			expected: "UUID=1234-abcd\t/mnt\tauto\trw,relatime\t0\t2",
+		},
+		{
+			name:     "tmpfs without size",
+			entry:    &fstabEntry{tmpfs: true, mountPoint: "/scratch"},
+			expected: "tmpfs\t/scratch\ttmpfs\trw,nosuid,nodev\t0\t0",
+		},
+		{
+			name:     "tmpfs with size",
+			entry:    &fstabEntry{tmpfs: true, tmpfsSize: meta.NewSizeFromBytes(64 * 1024 * 1024), mountPoint: "/cache"},
+			expected: "tmpfs\t/cache\ttmpfs\trw,nosuid,nodev,size=67108864\t0\t0",
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			if !rt.entry.isValid() {
+				t.Errorf("expected entry to be valid")
+			}
+			if actual := rt.entry.String(); actual != rt.expected {
+				t.Errorf("expected %q, got %q", rt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWritablePaths(t *testing.T) {
+	cases := []struct {
+		name     string
+		storage  api.VMStorageSpec
+		expected []string
+	}{
+		{
+			name:    "writable root",
+			storage: api.VMStorageSpec{WritablePaths: []string{"/tmp"}},
+		},
+		{
+			name:     "default writable paths",
+			storage:  api.VMStorageSpec{ReadOnlyRoot: true},
+			expected: []string{"/tmp", "/var/tmp", "/var/log"},
+		},
+		{
+			name: "volume mounted at a writable path",
+			storage: api.VMStorageSpec{
+				ReadOnlyRoot:  true,
+				WritablePaths: []string{"/tmp", "/var/lib/app"},
+				VolumeMounts:  []api.VolumeMount{{Name: "data", MountPath: "/var/lib/app"}},
+			},
+			expected: []string{"/tmp"},
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			if actual := writablePaths(&rt.storage); !reflect.DeepEqual(actual, rt.expected) {
+				t.Errorf("expected %v, got %v", rt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package dmlegacy
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+const (
+	vmHostsFile = "/etc/hosts"
+
+	// The host names of the VM's annotations are written between these lines, the
+	// rest of /etc/hosts is left as is
+	hostsBeginLine = "# Begin hosts written by ignite when the VM starts, set the ignite.weave.works/host/ annotations to change them"
+	hostsEndLine   = "# End hosts written by ignite"
+)
+
+// WriteHosts mounts the activated snapshot device of the VM and writes the host names
+// of the VM's host annotations into its /etc/hosts, replacing the ones written when it
+// was last started.
+func WriteHosts(vm *api.VM, devicePath string) (err error) {
+	mp, err := util.Mount(devicePath)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, mp.Umount)
+
+	return writeHostsFile(mp.Path, vm.Hosts())
+}
+
+func writeHostsFile(mountPoint string, hosts map[string]string) error {
+	hostsFile := path.Join(mountPoint, vmHostsFile)
+	content, err := ioutil.ReadFile(hostsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	updated := removeHostsBlock(content)
+	if len(hosts) > 0 {
+		names := make([]string, 0, len(hosts))
+		for name := range hosts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var b bytes.Buffer
+		b.Write(updated)
+		if len(updated) > 0 && !bytes.HasSuffix(updated, []byte("\n")) {
+			b.WriteByte('\n')
+		}
+
+		b.WriteString(hostsBeginLine + "\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s\t%s\n", hosts[name], name)
+		}
+		b.WriteString(hostsEndLine + "\n")
+		updated = b.Bytes()
+	}
+
+	if bytes.Equal(updated, content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(path.Dir(hostsFile), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(hostsFile, updated, 0644)
+}
+
+// removeHostsBlock removes the lines written by writeHostsFile from the content of /etc/hosts
+func removeHostsBlock(content []byte) []byte {
+	var out bytes.Buffer
+	inBlock := false
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		switch trimmed := string(bytes.TrimSpace(line)); {
+		case trimmed == hostsBeginLine:
+			inBlock = true
+		case trimmed == hostsEndLine:
+			inBlock = false
+		case !inBlock:
+			out.Write(line)
+		}
+	}
+
+	return out.Bytes()
+}
@@ -0,0 +1,45 @@
+package dmlegacy
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestWriteHostsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-hosts-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	hostsFile := path.Join(dir, vmHostsFile)
+	assert.NilError(t, os.MkdirAll(path.Dir(hostsFile), 0755))
+	original := "127.0.0.1\tlocalhost\n10.61.0.2\t4462576f8bf5b689\n"
+	assert.NilError(t, ioutil.WriteFile(hostsFile, []byte(original), 0644))
+
+	assert.NilError(t, writeHostsFile(dir, map[string]string{"web": "10.61.0.3", "db": "10.61.0.4"}))
+	content, err := ioutil.ReadFile(hostsFile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), original+hostsBeginLine+"\n"+
+		"10.61.0.4\tdb\n"+
+		"10.61.0.3\tweb\n"+
+		hostsEndLine+"\n")
+
+	// The hosts of the last start are replaced
+	assert.NilError(t, writeHostsFile(dir, map[string]string{"db": "10.61.0.5"}))
+	content, err = ioutil.ReadFile(hostsFile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), original+hostsBeginLine+"\n"+
+		"10.61.0.5\tdb\n"+
+		hostsEndLine+"\n")
+
+	// Without hosts, the file is restored
+	assert.NilError(t, writeHostsFile(dir, nil))
+	content, err = ioutil.ReadFile(hostsFile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), original)
+}
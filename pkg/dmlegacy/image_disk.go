@@ -0,0 +1,48 @@
+package dmlegacy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/source"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// CreateDiskImage converts the disk image file of a container disk, e.g. a qcow2 cloud image,
+// to the raw disk image of the image the VMs boot from. qemu-img detects the format of the file.
+func CreateDiskImage(ctx context.Context, img *api.Image, disk *source.Disk) (err error) {
+	// qemu-img needs to seek in the file, so it's written out of the tar stream first
+	diskPath := path.Join(img.ObjectPath(), disk.Name)
+	diskFile, err := os.Create(diskPath)
+	if err != nil {
+		return fmt.Errorf("failed to create disk file for %s: %v", img.GetUID(), err)
+	}
+	defer os.Remove(diskPath)
+
+	_, span := tracing.Start(ctx, "image.extract")
+	_, err = io.Copy(diskFile, disk)
+	if closeErr := diskFile.Close(); err == nil {
+		err = closeErr
+	}
+	tracing.End(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to extract disk %q: %v", disk.Name, err)
+	}
+
+	log.Debugf("Converting disk %q to a raw disk image...", disk.Name)
+	_, span = tracing.Start(ctx, "image.convert")
+	_, err = util.ExecuteCommand("qemu-img", "convert", "-O", "raw", diskPath, path.Join(img.ObjectPath(), constants.IMAGE_FS))
+	tracing.End(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to convert disk %q: %v", disk.Name, err)
+	}
+
+	return nil
+}
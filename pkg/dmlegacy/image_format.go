@@ -1,6 +1,7 @@
 package dmlegacy
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -12,9 +13,11 @@ import (
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/agent"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/source"
+	"github.com/weaveworks/ignite/pkg/tracing"
 	"github.com/weaveworks/ignite/pkg/util"
 )
 
@@ -38,8 +41,16 @@ func getMinimumBaseSizeBytes() int64 {
 	return int64(gb) * 1024 * 1024 * 1024
 }
 
-// CreateImageFilesystem creates an ext4 filesystem in a file, containing the files from the source
-func CreateImageFilesystem(img *api.Image, src source.Source) error {
+// CreateImageFilesystem creates an ext4 filesystem in a file, containing the files from the source.
+// If agentBinary is set, the guest agent binary at that path is injected into the filesystem.
+// In the offline mode, the filesystem is built without mounting it, which doesn't require root.
+func CreateImageFilesystem(ctx context.Context, img *api.Image, src source.Source, agentBinary string, mode api.ImageImportMode) error {
+	switch mode {
+	case "", api.ImageImportModeMount, api.ImageImportModeOffline:
+	default:
+		return fmt.Errorf("invalid image import mode %q, supported modes: %s, %s", mode, api.ImageImportModeMount, api.ImageImportModeOffline)
+	}
+
 	log.Debugf("Allocating image file and formatting it with ext4...")
 	p := path.Join(img.ObjectPath(), constants.IMAGE_FS)
 	imageFile, err := os.Create(p)
@@ -69,31 +80,57 @@ func CreateImageFilesystem(img *api.Image, src source.Source) error {
 		return errMsg
 	}
 
+	offline := mode == api.ImageImportModeOffline
+	if offline {
+		_, span := tracing.Start(ctx, "image.mkfs")
+		err = formatOffline(img, src, agentBinary, p)
+		tracing.End(span, err)
+		if err != nil {
+			log.Errorf("image import offline mkfs.ext4 failed: %v", err)
+			return err
+		}
+	} else if err := formatAndAddFiles(ctx, img, src, agentBinary, p); err != nil {
+		return err
+	}
+
+	// Resize the image to its minimum size
+	_, span := tracing.Start(ctx, "image.resize")
+	err = resizeToMinimum(img, !offline)
+	tracing.End(span, err)
+	if err != nil {
+		log.Errorf("image import resizeToMinimum failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// formatAndAddFiles formats the image file at p with ext4, and extracts the files from
+// the source into it
+func formatAndAddFiles(ctx context.Context, img *api.Image, src source.Source, agentBinary, p string) error {
 	// Use mkfs.ext4 to create the new image with an inode size of 256
 	// (gexto doesn't support anything but 128, but as long as we're not using that it's fine)
-	if _, err := util.ExecuteCommand("mkfs.ext4", "-b", strconv.Itoa(blockSize),
-		"-I", "256", "-F", "-E", "lazy_itable_init=0,lazy_journal_init=0", p); err != nil {
+	_, span := tracing.Start(ctx, "image.mkfs")
+	_, err := util.ExecuteCommand("mkfs.ext4", "-b", strconv.Itoa(blockSize),
+		"-I", "256", "-F", "-E", "lazy_itable_init=0,lazy_journal_init=0", p)
+	tracing.End(span, err)
+	if err != nil {
 		errMsg := errors.Wrapf(err, "failed to format image %s", img.GetUID())
 		log.Errorf("image import mkfs.ext4 failed: %v", errMsg)
 		return errMsg
 	}
 
 	// Proceed with populating the image with files
-	if err := addFiles(img, src); err != nil {
+	_, span = tracing.Start(ctx, "image.extract")
+	err = addFiles(img, src, agentBinary)
+	tracing.End(span, err)
+	if err != nil {
 		log.Errorf("image import addFiles failed: %v", err)
-		return err
 	}
-
-	// Resize the image to its minimum size
-	if err := resizeToMinimum(img); err != nil {
-		log.Errorf("image import resizeToMinimum failed: %v", err)
-		return err
-	}
-	return nil
+	return err
 }
 
 // addFiles copies the contents of the tar file into the ext4 filesystem
-func addFiles(img *api.Image, src source.Source) (err error) {
+func addFiles(img *api.Image, src source.Source, agentBinary string) (err error) {
 	log.Debugf("Copying in files to the image file from a source...")
 	p := path.Join(img.ObjectPath(), constants.IMAGE_FS)
 	tempDir, err := ioutil.TempDir("", "")
@@ -102,15 +139,13 @@ func addFiles(img *api.Image, src source.Source) (err error) {
 	}
 	defer os.RemoveAll(tempDir)
 
-	if _, err := util.ExecuteCommand("mount", "-o", "loop", p, tempDir); err != nil {
+	unmount, err := mountLoop(p, tempDir, false)
+	if err != nil {
 		errMsg := fmt.Errorf("failed to mount image %q: %v", p, err)
 		log.Errorf("image import mount failed: %v", errMsg)
 		return errMsg
 	}
-	defer util.DeferErr(&err, func() error {
-		_, execErr := util.ExecuteCommand("umount", tempDir)
-		return execErr
-	})
+	defer util.DeferErr(&err, unmount)
 
 	err = source.TarExtract(src, tempDir)
 	if err != nil {
@@ -121,6 +156,14 @@ func addFiles(img *api.Image, src source.Source) (err error) {
 	err = setupResolvConf(tempDir)
 	if err != nil {
 		log.Errorf("image import setupResolvConf failed: %v", err)
+		return
+	}
+
+	if len(agentBinary) != 0 {
+		err = agent.Inject(tempDir, agentBinary)
+		if err != nil {
+			log.Errorf("image import agent.Inject failed: %v", err)
+		}
 	}
 
 	return
@@ -150,13 +193,14 @@ func setupResolvConf(tempDir string) error {
 	return os.Symlink("../proc/net/pnp", resolvConf)
 }
 
-// resizeToMinimum resizes the given image to the smallest size possible
-func resizeToMinimum(img *api.Image) (err error) {
+// resizeToMinimum resizes the given image to the smallest size possible. The filesystem is
+// checked and resized through a loop device if loop is set, or in the file directly.
+func resizeToMinimum(img *api.Image, loop bool) (err error) {
 	p := path.Join(img.ObjectPath(), constants.IMAGE_FS)
 	var minSize int64
 	var imageFile *os.File
 
-	if minSize, err = getMinSize(p); err != nil {
+	if minSize, err = getMinSize(p, loop); err != nil {
 		log.Errorf("image import getMinSize failed: %v", err)
 		return
 	}
@@ -180,24 +224,29 @@ func resizeToMinimum(img *api.Image) (err error) {
 
 // getMinSize retrieves the minimum size for a block device file
 // containing a filesystem and shrinks the filesystem to that size
-func getMinSize(p string) (minSize int64, err error) {
-	// Loop mount the image for resize2fs
-	imageLoop, err := newLoopDev(p, false)
-	if err != nil {
-		log.Errorf("image import newLoopDev failed: %v", err)
-		return
-	}
+func getMinSize(p string, loop bool) (minSize int64, err error) {
+	device := p
+	if loop {
+		// Loop mount the image for resize2fs
+		var imageLoop *loopDevice
+		imageLoop, err = newLoopDev(p, false)
+		if err != nil {
+			log.Errorf("image import newLoopDev failed: %v", err)
+			return
+		}
 
-	// Defer the detach
-	defer util.DeferErr(&err, imageLoop.Detach)
+		// Defer the detach
+		defer util.DeferErr(&err, imageLoop.Detach)
+		device = imageLoop.Path()
+	}
 
 	// Call e2fsck for resize2fs, it sometimes requires this
 	// e2fsck throws an error if the filesystem gets repaired, so just ignore it
-	_, _ = util.ExecuteCommand("e2fsck", "-p", "-f", imageLoop.Path())
+	_, _ = util.ExecuteCommand("e2fsck", "-p", "-f", device)
 
 	// Retrieve the minimum size for the filesystem
-	log.Debugf("Retrieving minimum size for %q", imageLoop.Path())
-	out, err := util.ExecuteCommand("resize2fs", "-P", imageLoop.Path())
+	log.Debugf("Retrieving minimum size for %q", device)
+	out, err := util.ExecuteCommand("resize2fs", "-P", device)
 	if err != nil {
 		log.Errorf("image import resize2fs -P failed: %v", err)
 		return
@@ -211,7 +260,7 @@ func getMinSize(p string) (minSize int64, err error) {
 	log.Debugf("Minimum size: %d blocks", minSize)
 
 	// Perform the filesystem resize
-	_, err = util.ExecuteCommand("resize2fs", imageLoop.Path(), strconv.FormatInt(minSize, 10))
+	_, err = util.ExecuteCommand("resize2fs", device, strconv.FormatInt(minSize, 10))
 	if err != nil {
 		log.Errorf("image import resize2fs shrink failed: %v", err)
 	}
@@ -0,0 +1,31 @@
+package dmlegacy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// WithImageMounted mounts the filesystem of the imported image read-only, and calls fn
+// with the directory it's mounted at. The image is unmounted when fn returns.
+func WithImageMounted(img *api.Image, fn func(rootfs string) error) (err error) {
+	p := path.Join(img.ObjectPath(), constants.IMAGE_FS)
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	unmount, err := mountLoop(p, tempDir, true)
+	if err != nil {
+		return fmt.Errorf("failed to mount image %q: %v", p, err)
+	}
+	defer util.DeferErr(&err, unmount)
+
+	return fn(tempDir)
+}
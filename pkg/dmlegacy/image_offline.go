@@ -0,0 +1,177 @@
+package dmlegacy
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	containerderr "github.com/containerd/containerd/errdefs"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/source"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// resolvConfPath is the resolv.conf of the image, without the leading slash of the tar entries
+const resolvConfPath = "etc/resolv.conf"
+
+// formatOffline formats the image file at p with ext4, populated from the tar stream of
+// the source by mkfs.ext4 -d. Nothing is mounted, so this doesn't require root. The tar
+// stream is rewritten to a temporary file first, to set up resolv.conf and inject the agent.
+func formatOffline(img *api.Image, src source.Source, agentBinary, p string) (err error) {
+	var agentFiles []agent.GuestFile
+	if len(agentBinary) != 0 {
+		if agentFiles, err = agent.GuestFiles(agentBinary); err != nil {
+			return
+		}
+	}
+
+	tarFile, err := ioutil.TempFile(img.ObjectPath(), "rootfs-*.tar")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tarFile.Name())
+	defer tarFile.Close()
+
+	reader, err := src.Reader()
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+
+	seen, err := rewriteRootfsTar(reader, tarFile, agentFiles)
+	if err != nil {
+		return fmt.Errorf("failed to write the files of image %s: %v", img.GetUID(), err)
+	}
+
+	if len(agentFiles) != 0 {
+		agent.CheckInit(func(p string) bool { return seen[strings.TrimPrefix(p, "/")] })
+	}
+
+	if err = src.Cleanup(); err != nil && !containerderr.IsNotFound(err) {
+		return
+	}
+
+	if _, err = util.ExecuteCommand("mkfs.ext4", "-b", strconv.Itoa(blockSize),
+		"-I", "256", "-F", "-E", "lazy_itable_init=0,lazy_journal_init=0", "-d", tarFile.Name(), p); err != nil {
+		return fmt.Errorf("failed to format image %s from its files, the offline import mode requires e2fsprogs 1.47.1 or newer built with libarchive: %v", img.GetUID(), err)
+	}
+
+	return
+}
+
+// rewriteRootfsTar copies the tar stream of a root filesystem to w, and adds what addFiles
+// adds to a mounted filesystem: a resolv.conf, unless the image has one, and the files of
+// the agent. The missing parent directories of the added files are added too. It returns
+// the paths in the written stream, without leading slashes.
+func rewriteRootfsTar(r io.Reader, w io.Writer, agentFiles []agent.GuestFile) (map[string]bool, error) {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	seen := make(map[string]bool)
+
+	replaced := make(map[string]bool, len(agentFiles))
+	for _, file := range agentFiles {
+		replaced[strings.TrimPrefix(file.Path, "/")] = true
+	}
+
+	hasResolvConf := false
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := tarEntryName(hdr.Name)
+		if replaced[name] {
+			continue
+		}
+
+		// An empty resolv.conf is replaced like setupResolvConf does
+		if name == resolvConfPath {
+			if hdr.Typeflag == tar.TypeReg && hdr.Size == 0 {
+				continue
+			}
+			hasResolvConf = true
+		}
+
+		seen[name] = true
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return nil, err
+		}
+	}
+
+	added := agentFiles
+	if !hasResolvConf {
+		added = append([]agent.GuestFile{{Path: resolvConfPath, Mode: 0777, Target: "../proc/net/pnp"}}, added...)
+	}
+
+	now := time.Now()
+	for _, file := range added {
+		name := strings.TrimPrefix(file.Path, "/")
+		if err := addTarParents(tw, seen, name, now); err != nil {
+			return nil, err
+		}
+
+		hdr := &tar.Header{
+			Name:     name,
+			Mode:     int64(file.Mode),
+			Size:     int64(len(file.Content)),
+			ModTime:  now,
+			Typeflag: tar.TypeReg,
+		}
+		if len(file.Target) != 0 {
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, file.Target, 0
+		}
+
+		seen[name] = true
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(file.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	return seen, tw.Close()
+}
+
+// addTarParents adds the parent directories of the entry that aren't in the stream yet
+func addTarParents(tw *tar.Writer, seen map[string]bool, name string, modTime time.Time) error {
+	dir := path.Dir(name)
+	if dir == "." || seen[dir] {
+		return nil
+	}
+
+	if err := addTarParents(tw, seen, dir, modTime); err != nil {
+		return err
+	}
+
+	log.Tracef("Adding the missing directory %q to the image", dir)
+	seen[dir] = true
+	return tw.WriteHeader(&tar.Header{
+		Name:     dir + "/",
+		Mode:     0755,
+		ModTime:  modTime,
+		Typeflag: tar.TypeDir,
+	})
+}
+
+// tarEntryName returns the path of a tar entry, without leading "./" or "/", and trailing slashes
+func tarEntryName(name string) string {
+	name = path.Clean("/" + name)
+	return strings.TrimPrefix(name, "/")
+}
@@ -0,0 +1,101 @@
+package dmlegacy
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+)
+
+type tarEntry struct {
+	Name, Content, Link string
+	Typeflag            byte
+}
+
+func writeTar(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{
+			Name:     e.Name,
+			Linkname: e.Link,
+			Typeflag: e.Typeflag,
+			Mode:     0644,
+			Size:     int64(len(e.Content)),
+		}))
+		_, err := tw.Write([]byte(e.Content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return &buf
+}
+
+func readTar(t *testing.T, r io.Reader) (entries []tarEntry) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return
+		}
+		assert.NilError(t, err)
+
+		content, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		entries = append(entries, tarEntry{hdr.Name, string(content), hdr.Linkname, hdr.Typeflag})
+	}
+}
+
+func TestRewriteRootfsTar(t *testing.T) {
+	src := writeTar(t, []tarEntry{
+		{Name: "./", Typeflag: tar.TypeDir},
+		{Name: "./etc/", Typeflag: tar.TypeDir},
+		{Name: "./etc/hostname", Content: "image\n", Typeflag: tar.TypeReg},
+		{Name: "./etc/resolv.conf", Typeflag: tar.TypeReg},
+		{Name: "./usr/local/sbin/ignite-agent", Content: "old agent", Typeflag: tar.TypeReg},
+	})
+
+	agentFiles := []agent.GuestFile{
+		{Path: "/usr/local/sbin/ignite-agent", Mode: 0755, Content: []byte("agent")},
+		{Path: "/etc/systemd/system/multi-user.target.wants/ignite-agent.service", Mode: 0777, Target: "/etc/systemd/system/ignite-agent.service"},
+	}
+
+	var out bytes.Buffer
+	seen, err := rewriteRootfsTar(src, &out, agentFiles)
+	assert.NilError(t, err)
+
+	// The empty resolv.conf and the old agent are replaced, and the missing
+	// parents of the agent files are added
+	assert.DeepEqual(t, readTar(t, &out), []tarEntry{
+		{Name: "./", Typeflag: tar.TypeDir},
+		{Name: "./etc/", Typeflag: tar.TypeDir},
+		{Name: "./etc/hostname", Content: "image\n", Typeflag: tar.TypeReg},
+		{Name: "etc/resolv.conf", Link: "../proc/net/pnp", Typeflag: tar.TypeSymlink},
+		{Name: "usr/", Typeflag: tar.TypeDir},
+		{Name: "usr/local/", Typeflag: tar.TypeDir},
+		{Name: "usr/local/sbin/", Typeflag: tar.TypeDir},
+		{Name: "usr/local/sbin/ignite-agent", Content: "agent", Typeflag: tar.TypeReg},
+		{Name: "etc/systemd/", Typeflag: tar.TypeDir},
+		{Name: "etc/systemd/system/", Typeflag: tar.TypeDir},
+		{Name: "etc/systemd/system/multi-user.target.wants/", Typeflag: tar.TypeDir},
+		{Name: "etc/systemd/system/multi-user.target.wants/ignite-agent.service", Link: "/etc/systemd/system/ignite-agent.service", Typeflag: tar.TypeSymlink},
+	})
+	assert.Assert(t, seen["etc/hostname"])
+}
+
+func TestRewriteRootfsTarKeepsResolvConf(t *testing.T) {
+	src := writeTar(t, []tarEntry{
+		{Name: "etc/resolv.conf", Link: "../run/systemd/resolve/stub-resolv.conf", Typeflag: tar.TypeSymlink},
+	})
+
+	var out bytes.Buffer
+	_, err := rewriteRootfsTar(src, &out, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, readTar(t, &out), []tarEntry{
+		{Name: "etc/resolv.conf", Link: "../run/systemd/resolve/stub-resolv.conf", Typeflag: tar.TypeSymlink},
+	})
+}
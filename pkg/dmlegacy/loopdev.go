@@ -3,29 +3,151 @@ package dmlegacy
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"os/exec"
 	"path"
 	"strconv"
+	"sync"
+	"time"
+	"unsafe"
 
-	losetup "github.com/freddierice/go-losetup"
+	"golang.org/x/sys/unix"
+
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+const (
+	loopControlPath = "/dev/loop-control"
+	// loopAttachRetries is how many free loop devices are tried when other processes
+	// take the free ones between looking them up and attaching them
+	loopAttachRetries = 20
+	loopAttachBackoff = 10 * time.Millisecond
 )
 
-// loopDevice is a helper struct for handling loopback devices for devicemapper
+// loopDevice is a loop device attached with the LOOP_CTL_GET_FREE and LOOP_SET_FD
+// ioctls, it's used as a backing device for devicemapper or to mount files
 type loopDevice struct {
-	losetup.Device
+	number int
 }
 
+// loopLock serializes the loop device setups of this process. Attaching a file looks up
+// a free loop device first, concurrent setups would get the same device.
+var loopLock sync.Mutex
+
+// newLoopDev attaches the file to a free loop device. Other processes may attach the free
+// device first, LOOP_SET_FD fails with EBUSY then and the next free device is tried.
 func newLoopDev(file string, readOnly bool) (*loopDevice, error) {
-	dev, err := losetup.Attach(file, 0, readOnly)
+	loopLock.Lock()
+	defer loopLock.Unlock()
+
+	ld, err := attachLoopDev(file, readOnly)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup loop device for %q: %v", file, err)
 	}
 
-	return &loopDevice{dev}, nil
+	return ld, nil
+}
+
+func attachLoopDev(file string, readOnly bool) (*loopDevice, error) {
+	flags := os.O_RDWR
+	if readOnly {
+		flags = os.O_RDONLY
+	}
+
+	backing, err := os.OpenFile(file, flags, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer backing.Close()
+
+	control, err := os.OpenFile(loopControlPath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer control.Close()
+
+	for i := 0; i < loopAttachRetries; i++ {
+		number, err := unix.IoctlRetInt(int(control.Fd()), unix.LOOP_CTL_GET_FREE)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get a free loop device: %v", err)
+		}
+
+		ld := &loopDevice{number: number}
+		busy, err := ld.attach(backing, flags, newLoopInfo(file, readOnly))
+		if err != nil {
+			return nil, err
+		}
+
+		if !busy {
+			return ld, nil
+		}
+
+		time.Sleep(loopAttachBackoff)
+	}
+
+	return nil, fmt.Errorf("no free loop device after %d attempts, the free ones were taken by other processes", loopAttachRetries)
+}
+
+// attach sets the backing file of the loop device, and reports whether the device
+// has been taken by another process in the meantime
+func (ld *loopDevice) attach(backing *os.File, flags int, info *unix.LoopInfo64) (bool, error) {
+	dev, err := os.OpenFile(ld.Path(), flags, 0)
+	if err != nil {
+		return false, err
+	}
+	defer dev.Close()
+
+	if err := unix.IoctlSetInt(int(dev.Fd()), unix.LOOP_SET_FD, int(backing.Fd())); err != nil {
+		if err == unix.EBUSY {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("LOOP_SET_FD on %s failed: %v", ld.Path(), err)
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, dev.Fd(), unix.LOOP_SET_STATUS64, uintptr(unsafe.Pointer(info))); errno != 0 {
+		_ = unix.IoctlSetInt(int(dev.Fd()), unix.LOOP_CLR_FD, 0)
+		return false, fmt.Errorf("LOOP_SET_STATUS64 on %s failed: %v", ld.Path(), errno)
+	}
+
+	return false, nil
+}
+
+// newLoopInfo returns the status of a loop device backed by the file, the kernel
+// keeps the name of the file truncated to LO_NAME_SIZE
+func newLoopInfo(file string, readOnly bool) *unix.LoopInfo64 {
+	info := &unix.LoopInfo64{}
+	copy(info.File_name[:unix.LO_NAME_SIZE-1], file)
+	if readOnly {
+		info.Flags |= unix.LO_FLAGS_READ_ONLY
+	}
+
+	return info
+}
+
+// Path returns the path of the device node of the loop device
+func (ld *loopDevice) Path() string {
+	return fmt.Sprintf("/dev/loop%d", ld.number)
+}
+
+// Detach detaches the backing file from the loop device. If the device is still in use,
+// e.g. by devicemapper or a mount, the kernel detaches it when it's released.
+func (ld *loopDevice) Detach() error {
+	dev, err := os.OpenFile(ld.Path(), os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	if err := unix.IoctlSetInt(int(dev.Fd()), unix.LOOP_CLR_FD, 0); err != nil {
+		return fmt.Errorf("LOOP_CLR_FD on %s failed: %v", ld.Path(), err)
+	}
+
+	return nil
 }
 
 func (ld *loopDevice) Size512K() (uint64, error) {
-	data, err := ioutil.ReadFile(path.Join("/sys/class/block", path.Base(ld.Device.Path()), "size"))
+	data, err := ioutil.ReadFile(path.Join("/sys/class/block", path.Base(ld.Path()), "size"))
 	if err != nil {
 		return 0, err
 	}
@@ -34,6 +156,38 @@ func (ld *loopDevice) Size512K() (uint64, error) {
 	return strconv.ParseUint(string(data[:len(data)-1]), 10, 64)
 }
 
+// mountLoop mounts the ext4 filesystem in the file at dir through a loop device, and
+// returns the function unmounting it. The loop device is detached while it's mounted,
+// so it's released with the mount.
+func mountLoop(file, dir string, readOnly bool) (func() error, error) {
+	ld, err := newLoopDev(file, readOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags uintptr
+	if readOnly {
+		flags = unix.MS_RDONLY
+	}
+
+	if err := unix.Mount(ld.Path(), dir, util.MountFSType, flags, ""); err != nil {
+		_ = ld.Detach()
+		return nil, fmt.Errorf("failed to mount %s at %q: %v", ld.Path(), dir, err)
+	}
+
+	if err := ld.Detach(); err != nil {
+		_ = unix.Unmount(dir, 0)
+		return nil, err
+	}
+
+	return func() error {
+		if err := unix.Unmount(dir, 0); err != nil {
+			return fmt.Errorf("failed to unmount %q: %v", dir, err)
+		}
+		return nil
+	}, nil
+}
+
 // dmsetup uses stdin to read multiline tables, this is a helper function for that
 func runDMSetup(name string, table []byte) error {
 	cmd := exec.Command(
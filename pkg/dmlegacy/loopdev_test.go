@@ -0,0 +1,54 @@
+package dmlegacy
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+	"gotest.tools/assert"
+)
+
+func TestNewLoopInfo(t *testing.T) {
+	info := newLoopInfo("/var/lib/firecracker/image/1234/image.ext4", true)
+	assert.Equal(t, string(info.File_name[:strings.IndexByte(string(info.File_name[:]), 0)]), "/var/lib/firecracker/image/1234/image.ext4")
+	assert.Equal(t, info.Flags, uint32(unix.LO_FLAGS_READ_ONLY))
+
+	// The name is truncated, keeping the terminating NUL
+	long := "/" + strings.Repeat("a", 100)
+	info = newLoopInfo(long, false)
+	assert.Equal(t, string(info.File_name[:unix.LO_NAME_SIZE-1]), long[:unix.LO_NAME_SIZE-1])
+	assert.Equal(t, info.File_name[unix.LO_NAME_SIZE-1], uint8(0))
+	assert.Equal(t, info.Flags, uint32(0))
+}
+
+func TestNewLoopDev(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("attaching loop devices requires root")
+	}
+	if _, err := os.Stat(loopControlPath); err != nil {
+		t.Skipf("no loop device support: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "ignite-loopdev-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "disk")
+	assert.NilError(t, ioutil.WriteFile(file, make([]byte, 1024*1024), 0644))
+
+	ld, err := newLoopDev(file, true)
+	assert.NilError(t, err)
+
+	backing, err := ioutil.ReadFile(path.Join("/sys/class/block", path.Base(ld.Path()), "loop", "backing_file"))
+	assert.NilError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(backing)), file)
+
+	size, err := ld.Size512K()
+	assert.NilError(t, err)
+	assert.Equal(t, size, uint64(2048))
+
+	assert.NilError(t, ld.Detach())
+}
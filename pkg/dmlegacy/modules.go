@@ -0,0 +1,93 @@
+package dmlegacy
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// modulesDir is where the kernel images keep the modules of their kernels, in a
+// directory named after the kernel version
+const modulesDir = "lib/modules"
+
+// kernelTarMembers returns the members of the kernel tar to extract into the overlay of a
+// VM booting the given kernel version: the /boot directory, and the modules directory of
+// the version. Kernel images may ship the modules of other versions too, which the booted
+// kernel can't load. If the version is unknown, or no modules directory is named after it,
+// the modules of a single version are still extracted, and all of them otherwise.
+func kernelTarMembers(kernelTarPath, version string) ([]string, error) {
+	f, err := os.Open(kernelTarPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// The members are named like the tar entries, e.g. with a leading ./
+	var prefix string
+	var members []string
+	seen := map[string]bool{}
+	var versions []string
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(hdr.Name, "./") {
+			prefix = "./"
+		}
+
+		name := strings.Trim(path.Clean(hdr.Name), "/")
+		if name == "." {
+			continue
+		}
+
+		parts := strings.Split(name, "/")
+		switch {
+		case parts[0] != "lib":
+			// Other top-level directories than /lib, i.e. /boot, are extracted as they are
+			if !seen[parts[0]] {
+				seen[parts[0]] = true
+				members = append(members, parts[0])
+			}
+		case len(parts) >= 3 && path.Join(parts[:2]...) == modulesDir:
+			if dir := parts[2]; !seen[path.Join(modulesDir, dir)] {
+				seen[path.Join(modulesDir, dir)] = true
+				versions = append(versions, dir)
+			}
+		}
+	}
+
+	modules := versions
+	for _, v := range versions {
+		if v == version {
+			modules = []string{v}
+			break
+		}
+	}
+
+	if len(modules) > 1 {
+		log.Warnf("None of the kernel modules %v are named after kernel version %q, copying all of them into the VM", versions, version)
+	}
+
+	sort.Strings(modules)
+	for _, v := range modules {
+		members = append(members, path.Join(modulesDir, v))
+	}
+
+	for i := range members {
+		members[i] = prefix + members[i]
+	}
+
+	return members, nil
+}
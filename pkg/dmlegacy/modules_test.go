@@ -0,0 +1,74 @@
+package dmlegacy
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestKernelTarMembers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-modules-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	kernelTar := func(names ...string) string {
+		var entries []tarEntry
+		for _, name := range names {
+			typeflag := byte(tar.TypeReg)
+			if name[len(name)-1] == '/' {
+				typeflag = tar.TypeDir
+			}
+			entries = append(entries, tarEntry{Name: name, Typeflag: typeflag})
+		}
+
+		p := path.Join(dir, "kernel.tar")
+		assert.NilError(t, ioutil.WriteFile(p, writeTar(t, entries).Bytes(), 0644))
+		return p
+	}
+
+	tests := []struct {
+		name    string
+		names   []string
+		version string
+		members []string
+	}{
+		{
+			name: "modules of the kernel version",
+			names: []string{"./", "./boot/", "./boot/vmlinux", "./lib/", "./lib/modules/",
+				"./lib/modules/4.19.125/", "./lib/modules/4.19.125/kernel/a.ko",
+				"./lib/modules/5.10.51/", "./lib/modules/5.10.51/kernel/a.ko", "./lib/modules/5.10.51/modules.dep"},
+			version: "5.10.51",
+			members: []string{"./boot", "./lib/modules/5.10.51"},
+		},
+		{
+			name:    "single modules directory of another name",
+			names:   []string{"boot/vmlinux", "lib/modules/5.10.51-ignite/kernel/a.ko"},
+			version: "5.10.51",
+			members: []string{"boot", "lib/modules/5.10.51-ignite"},
+		},
+		{
+			name:    "unknown kernel version",
+			names:   []string{"./boot/vmlinux", "./lib/modules/5.10.51/", "./lib/modules/4.19.125/"},
+			version: "<unknown>",
+			members: []string{"./boot", "./lib/modules/4.19.125", "./lib/modules/5.10.51"},
+		},
+		{
+			name:    "no modules",
+			names:   []string{"./", "./boot/", "./boot/vmlinux", "./boot/initrd"},
+			version: "5.10.51",
+			members: []string{"./boot"},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			members, err := kernelTarMembers(kernelTar(rt.names...), rt.version)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, members, rt.members)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package dmlegacy
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+// The snapshots store their data in chunks of 4KiB, the 8 sectors of the snapshot table.
+// The persistent copy-on-write store in the overlay file takes a header chunk, and a
+// metadata chunk for every 256 chunks of data, as every chunk takes a 16 byte exception.
+const (
+	snapshotChunkSize          = 8 * 512
+	snapshotExceptionsPerChunk = snapshotChunkSize / 16
+)
+
+// overlaySizeFor returns how large the overlay file needs to be to store every chunk of a
+// snapshot device of the given size. dm-snapshot invalidates the whole snapshot when its
+// overlay overflows, so capped overlays need to fit all of the device.
+func overlaySizeFor(deviceSize int64) int64 {
+	chunks := (deviceSize + snapshotChunkSize - 1) / snapshotChunkSize
+	metadata := (chunks + snapshotExceptionsPerChunk - 1) / snapshotExceptionsPerChunk
+	return (1 + metadata + chunks) * snapshotChunkSize
+}
+
+// snapshotSizeFor returns how large a snapshot device can be for every chunk of it to fit
+// into an overlay file of the given size, the inverse of overlaySizeFor.
+func snapshotSizeFor(overlaySize int64) int64 {
+	chunks := overlaySize/snapshotChunkSize - 1
+	if chunks <= 0 {
+		return 0
+	}
+
+	return chunks * snapshotExceptionsPerChunk / (snapshotExceptionsPerChunk + 1) * snapshotChunkSize
+}
+
+// OverlayUsage returns the amount of host disk space allocated by the VM's
+// overlay file. The overlay is a sparse file, so this is usually a lot less
+// than the file's apparent size.
+func OverlayUsage(vm *api.VM) (meta.Size, error) {
+	fi, err := os.Stat(vm.OverlayFile())
+	if err != nil {
+		return meta.EmptySize, err
+	}
+
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return meta.EmptySize, fmt.Errorf("failed to get raw syscall.Stat_t data for %q", vm.OverlayFile())
+	}
+
+	// st_blocks is always counted in 512-byte units
+	return meta.NewSizeFromSectors(uint64(stat.Blocks)), nil
+}
+
+// OverlayNearLimit reports whether the given overlay usage has reached the
+// warning threshold of the VM's overlay size limit. VMs without a limit are
+// never near it.
+func OverlayNearLimit(vm *api.VM, usage meta.Size) bool {
+	limit := vm.Spec.OverlaySizeLimit
+	if limit == meta.EmptySize {
+		return false
+	}
+
+	return usage.Bytes()*100 >= limit.Bytes()*constants.OVERLAY_USAGE_WARNING_PERCENT
+}
@@ -0,0 +1,95 @@
+package dmlegacy
+
+import (
+	"testing"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+func TestOverlaySizeFor(t *testing.T) {
+	const gb = 1024 * 1024 * 1024
+	cases := []struct {
+		name       string
+		deviceSize int64
+		expected   int64
+	}{
+		{
+			name:       "single chunk",
+			deviceSize: 1,
+			expected:   3 * snapshotChunkSize, // header, metadata and data
+		},
+		{
+			name:       "full metadata chunk",
+			deviceSize: 256 * snapshotChunkSize,
+			expected:   258 * snapshotChunkSize,
+		},
+		{
+			name:       "second metadata chunk",
+			deviceSize: 257 * snapshotChunkSize,
+			expected:   260 * snapshotChunkSize,
+		},
+		{
+			name:       "4GB disk",
+			deviceSize: 4 * gb,
+			expected:   4*gb + (1+4096)*snapshotChunkSize,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			if actual := overlaySizeFor(rt.deviceSize); actual != rt.expected {
+				t.Errorf("expected %d, actual %d", rt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSnapshotSizeFor(t *testing.T) {
+	// The device of an overlay must always fit into it, and be as large as possible
+	for _, overlaySize := range []int64{0, snapshotChunkSize, 2 * snapshotChunkSize, 3 * snapshotChunkSize,
+		258 * snapshotChunkSize, 259 * snapshotChunkSize, 260 * snapshotChunkSize, 1000000000, 4 << 30} {
+		deviceSize := snapshotSizeFor(overlaySize)
+		if deviceSize > 0 && overlaySizeFor(deviceSize) > overlaySize {
+			t.Errorf("device of %d for overlay of %d doesn't fit", deviceSize, overlaySize)
+		}
+		if next := deviceSize + snapshotChunkSize; overlaySizeFor(next) <= overlaySize {
+			t.Errorf("device of %d for overlay of %d could be %d", deviceSize, overlaySize, next)
+		}
+	}
+}
+
+func TestOverlayNearLimit(t *testing.T) {
+	cases := []struct {
+		name     string
+		limit    meta.Size
+		usage    meta.Size
+		expected bool
+	}{
+		{
+			name:  "no limit",
+			usage: meta.NewSizeFromBytes(1 << 40),
+		},
+		{
+			name:  "below threshold",
+			limit: meta.NewSizeFromBytes(1000),
+			usage: meta.NewSizeFromBytes(899),
+		},
+		{
+			name:     "at threshold",
+			limit:    meta.NewSizeFromBytes(1000),
+			usage:    meta.NewSizeFromBytes(900),
+			expected: true,
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			vm := &api.VM{}
+			vm.Spec.OverlaySizeLimit = rt.limit
+			if actual := OverlayNearLimit(vm, rt.usage); actual != rt.expected {
+				t.Errorf("expected %t, actual %t", rt.expected, actual)
+			}
+		})
+	}
+}
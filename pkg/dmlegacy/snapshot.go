@@ -4,18 +4,18 @@ import (
 	"fmt"
 	"os"
 	"path"
-	"path/filepath"
-
-	"github.com/nightlyone/lockfile"
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/flock"
 	"github.com/weaveworks/ignite/pkg/operations/lookup"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/util"
 )
 
-const snapshotLockFileName = "ignite-snapshot.lock"
+// snapshotLock is the lock serializing the snapshot operations of all ignite processes
+const snapshotLock = "snapshot"
 
 // ActivateSnapshot sets up the snapshot with devicemapper so that it is active and can be used.
 // It returns the path of the bootable snapshot device.
@@ -45,21 +45,11 @@ func ActivateSnapshot(vm *api.VM) (devicePath string, err error) {
 	//   Device or resource busy
 	// Serializing the interaction with device mapper helps avoid issues due to
 	// race conditions.
-
-	// Global lock path.
-	glpath := filepath.Join(os.TempDir(), snapshotLockFileName)
-
-	// Create a lockfile and obtain a lock.
-	lock, err := lockfile.New(glpath)
+	unlock, err := flock.Lock(snapshotLock)
 	if err != nil {
-		err = fmt.Errorf("failed to create lockfile: %w", err)
-		return
-	}
-	if err = obtainLock(lock); err != nil {
 		return
 	}
-	// Release the lock at the end.
-	defer util.DeferErr(&err, lock.Unlock)
+	defer unlock()
 
 	// Setup loop device for the image
 	imageLoop, err := newLoopDev(path.Join(constants.IMAGE_DIR, imageUID.String(), constants.IMAGE_FS), true)
@@ -88,17 +78,29 @@ func ActivateSnapshot(vm *api.VM) (devicePath string, err error) {
 		return
 	}
 
-	// If the overlay is larger than the base image, we need to set up an additional dm device
+	// The snapshot device is as large as the overlay. If the overlay is capped by the VM's
+	// overlay size limit, the device is as large as what fits into the capped overlay. The
+	// VM runs out of disk space then, instead of overflowing the overlay, which would make
+	// dm-snapshot invalidate the snapshot with all the data of the VM.
+	deviceSize := overlayLoopSize
+	if limit := vm.Spec.OverlaySizeLimit; limit != meta.EmptySize {
+		if deviceSize = uint64(snapshotSizeFor(int64(overlayLoopSize)*512) / 512); deviceSize < imageLoopSize {
+			err = fmt.Errorf("overlay size limit %s of VM %q is too small for its image", limit, vm.GetUID())
+			return
+		}
+	}
+
+	// If the device is larger than the base image, we need to set up an additional dm device
 	// which will contain the image and additional zero space (which reads zeros and discards writes).
 	// This is fine, because all writes will target the overlay snapshot and not the read-only image.
 	// The newly generated larger device will then be used for creating the snapshot (which is always
 	// as large as the device backing it).
 
 	basePath := imageLoop.Path()
-	if overlayLoopSize > imageLoopSize {
+	if deviceSize > imageLoopSize {
 		// "0 8388608 linear /dev/loop0 0"
 		// "8388608 12582912 zero"
-		dmBaseTable := []byte(fmt.Sprintf("0 %d linear %s 0\n%d %d zero", imageLoopSize, imageLoop.Path(), imageLoopSize, overlayLoopSize))
+		dmBaseTable := []byte(fmt.Sprintf("0 %d linear %s 0\n%d %d zero", imageLoopSize, imageLoop.Path(), imageLoopSize, deviceSize))
 
 		baseDevice := fmt.Sprintf("%s-base", device)
 		if err = runDMSetup(baseDevice, dmBaseTable); err != nil {
@@ -109,19 +111,21 @@ func ActivateSnapshot(vm *api.VM) (devicePath string, err error) {
 	}
 
 	// "0 8388608 snapshot /dev/{loop0,mapper/ignite-<uid>-base} /dev/loop1 P 8"
-	dmTable := []byte(fmt.Sprintf("0 %d snapshot %s %s P 8", overlayLoopSize, basePath, overlayLoop.Path()))
+	dmTable := []byte(fmt.Sprintf("0 %d snapshot %s %s P 8", deviceSize, basePath, overlayLoop.Path()))
 
 	// setup the main boot device
 	if err = runDMSetup(device, dmTable); err != nil {
 		return
 	}
 
-	// Repair the filesystem in case it has errors
+	// Repair the filesystem in case it has errors, the filesystems of disk images are left to the guest
 	// e2fsck throws an error if the filesystem gets repaired, so just ignore it
-	_, _ = util.ExecuteCommand("e2fsck", "-p", "-f", devicePath)
+	if !vm.DiskImage() {
+		_, _ = util.ExecuteCommand("e2fsck", "-p", "-f", devicePath)
+	}
 
-	// If the overlay is larger than the image, call resize2fs to make the filesystem fill the overlay
-	if overlayLoopSize > imageLoopSize {
+	// If the device is larger than the image, call resize2fs to make the filesystem fill the device
+	if deviceSize > imageLoopSize && !vm.DiskImage() {
 		if _, err = util.ExecuteCommand("resize2fs", devicePath); err != nil {
 			return
 		}
@@ -137,33 +141,3 @@ func ActivateSnapshot(vm *api.VM) (devicePath string, err error) {
 
 	return
 }
-
-// obtainLock tries to obtain a lock and retries if the lock is owned by
-// another process, until a lock is obtained.
-func obtainLock(lock lockfile.Lockfile) error {
-	// Check if the lock has any owner.
-	process, err := lock.GetOwner()
-	if err == nil {
-		// A lock already exists. Check if the lock owner is the current process
-		// itself.
-		if process.Pid == os.Getpid() {
-			return fmt.Errorf("lockfile %q already locked by this process", lock)
-		}
-
-		// A lock already exists, but it's owned by some other process. Continue
-		// to obtain lock, in case the lock owner no longer exists.
-	}
-
-	// Obtain a lock. Retry if the lock can't be obtained.
-	err = lock.TryLock()
-	for err != nil {
-		// Check if it's a lock temporary error that can be mitigated with a
-		// retry. Fail if any other error.
-		if _, ok := err.(interface{ Temporary() bool }); !ok {
-			return fmt.Errorf("unable to lock %q: %v", lock, err)
-		}
-		err = lock.TryLock()
-	}
-
-	return nil
-}
@@ -0,0 +1,381 @@
+package dmlegacy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/authorizedkeys"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+const (
+	vmPasswdFile  = "/etc/passwd"
+	vmShadowFile  = "/etc/shadow"
+	vmGroupFile   = "/etc/group"
+	vmGShadowFile = "/etc/gshadow"
+	vmSudoersFile = "/etc/sudoers.d/ignite-users"
+
+	// firstUserID is the first UID and GID given to users and groups that are created without one
+	firstUserID = 1000
+	// lastUserID is the last UID and GID given to users and groups that are created without one
+	lastUserID = 59999
+)
+
+// WriteUsers mounts the activated snapshot device of the VM and creates or updates
+// the users of the VM in it. Users and groups that are removed from the VM's spec
+// are kept in the guest, but they lose their sudo rights.
+func WriteUsers(vm *api.VM, devicePath string) (err error) {
+	mp, err := util.Mount(devicePath)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, mp.Umount)
+
+	return writeUsers(mp.Path, vm.Spec.Users)
+}
+
+func writeUsers(mountPoint string, users []api.VMUser) error {
+	sudoers := make([]string, 0, len(users))
+	if len(users) > 0 {
+		dbs, err := readUserDBs(mountPoint)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			if err := dbs.apply(mountPoint, &user); err != nil {
+				return fmt.Errorf("failed to write user %q: %v", user.Name, err)
+			}
+
+			if user.Sudo {
+				sudoers = append(sudoers, user.Name)
+			}
+		}
+
+		if err := dbs.write(); err != nil {
+			return err
+		}
+	}
+
+	return writeSudoers(mountPoint, sudoers)
+}
+
+// writeSudoers allows the given users to run any command as root, the file is
+// removed if there are no such users
+func writeSudoers(mountPoint string, users []string) error {
+	sudoersFile := path.Join(mountPoint, vmSudoersFile)
+	if len(users) == 0 {
+		if err := os.Remove(sudoersFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("# Written by ignite when the VM starts, set spec.users to change these users\n")
+	for _, user := range users {
+		fmt.Fprintf(&b, "%s ALL=(ALL) NOPASSWD:ALL\n", user)
+	}
+
+	if err := os.MkdirAll(path.Dir(sudoersFile), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(sudoersFile, []byte(b.String()), 0440)
+}
+
+// userDB is a file in the colon-separated format of /etc/passwd, /etc/shadow,
+// /etc/group and /etc/gshadow. Each line is kept as its fields.
+type userDB struct {
+	path    string
+	exists  bool
+	entries [][]string
+}
+
+func readUserDB(file string) (*userDB, error) {
+	db := &userDB{path: file}
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return db, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	db.exists = true
+	for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		if len(line) > 0 {
+			db.entries = append(db.entries, strings.Split(line, ":"))
+		}
+	}
+
+	return db, nil
+}
+
+// find returns the entry with the given name, or nil if there's none
+func (db *userDB) find(name string) []string {
+	for _, entry := range db.entries {
+		if entry[0] == name {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// findID returns the entry with the given ID in the third field, or nil if there's none
+func (db *userDB) findID(id uint32) []string {
+	for _, entry := range db.entries {
+		if len(entry) > 2 && entry[2] == strconv.FormatUint(uint64(id), 10) {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// nextFreeID returns the first ID from firstUserID on that isn't in use
+func (db *userDB) nextFreeID() (uint32, error) {
+	for id := uint32(firstUserID); id <= lastUserID; id++ {
+		if db.findID(id) == nil {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free ID left in %s", path.Base(db.path))
+}
+
+func (db *userDB) add(entry ...string) {
+	db.entries = append(db.entries, entry)
+}
+
+func (db *userDB) write() error {
+	if !db.exists {
+		return nil
+	}
+
+	var b strings.Builder
+	for _, entry := range db.entries {
+		b.WriteString(strings.Join(entry, ":") + "\n")
+	}
+
+	// WriteFile keeps the permissions of the existing file
+	return ioutil.WriteFile(db.path, []byte(b.String()), 0644)
+}
+
+// userDBs are the user and group databases of the guest. The shadow databases
+// are optional, they're only updated if the image has them.
+type userDBs struct {
+	passwd, shadow, group, gshadow *userDB
+}
+
+func readUserDBs(mountPoint string) (*userDBs, error) {
+	dbs := &userDBs{}
+	for file, db := range map[string]**userDB{
+		vmPasswdFile:  &dbs.passwd,
+		vmShadowFile:  &dbs.shadow,
+		vmGroupFile:   &dbs.group,
+		vmGShadowFile: &dbs.gshadow,
+	} {
+		var err error
+		if *db, err = readUserDB(path.Join(mountPoint, file)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, db := range []*userDB{dbs.passwd, dbs.group} {
+		if !db.exists {
+			return nil, fmt.Errorf("the image has no %s", path.Base(db.path))
+		}
+	}
+
+	return dbs, nil
+}
+
+func (dbs *userDBs) write() error {
+	for _, db := range []*userDB{dbs.passwd, dbs.shadow, dbs.group, dbs.gshadow} {
+		if err := db.write(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply creates or updates the user, its groups, password and authorized keys
+func (dbs *userDBs) apply(mountPoint string, user *api.VMUser) error {
+	entry := dbs.passwd.find(user.Name)
+	if entry == nil {
+		var err error
+		if entry, err = dbs.addUser(mountPoint, user); err != nil {
+			return err
+		}
+	} else if len(entry) < 7 {
+		return fmt.Errorf("invalid %s entry for the user", path.Base(dbs.passwd.path))
+	} else if user.UID != nil && entry[2] != strconv.FormatUint(uint64(*user.UID), 10) {
+		return fmt.Errorf("the user exists in the image with UID %s, which can't be changed", entry[2])
+	}
+
+	// Password logins are disabled if no password hash is given
+	password := user.PasswordHash
+	if len(password) == 0 {
+		password = "!"
+	}
+
+	if shadowEntry := dbs.shadow.find(user.Name); shadowEntry != nil && len(shadowEntry) > 1 {
+		shadowEntry[1] = password
+	} else {
+		entry[1] = password
+	}
+
+	for _, group := range user.Groups {
+		if err := dbs.addGroupMember(group, user.Name); err != nil {
+			return err
+		}
+	}
+
+	uid, err := strconv.Atoi(entry[2])
+	if err != nil {
+		return err
+	}
+
+	gid, err := strconv.Atoi(entry[3])
+	if err != nil {
+		return err
+	}
+
+	if len(user.AuthorizedKeys) > 0 {
+		return writeUserAuthorizedKeys(path.Join(mountPoint, entry[5]), user.AuthorizedKeys, uid, gid)
+	}
+
+	return nil
+}
+
+// addUser adds a user with a primary group of the same name and a home directory
+func (dbs *userDBs) addUser(mountPoint string, user *api.VMUser) ([]string, error) {
+	var uid uint32
+	if user.UID != nil {
+		uid = *user.UID
+		if other := dbs.passwd.findID(uid); other != nil {
+			return nil, fmt.Errorf("UID %d is taken by user %q", uid, other[0])
+		}
+	} else {
+		var err error
+		if uid, err = dbs.passwd.nextFreeID(); err != nil {
+			return nil, err
+		}
+	}
+
+	gid, err := dbs.addGroup(user.Name, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	shell := "/bin/sh"
+	if _, err := os.Stat(path.Join(mountPoint, "/bin/bash")); err == nil {
+		shell = "/bin/bash"
+	}
+
+	home := path.Join("/home", user.Name)
+	entry := []string{user.Name, "x", fmt.Sprint(uid), fmt.Sprint(gid), "", home, shell}
+	dbs.passwd.add(entry...)
+	if dbs.shadow.exists {
+		dbs.shadow.add(user.Name, "!", "", "", "", "", "", "", "")
+	}
+
+	homeDir := path.Join(mountPoint, home)
+	if err := os.MkdirAll(homeDir, 0755); err != nil {
+		return nil, err
+	}
+
+	return entry, os.Chown(homeDir, int(uid), int(gid))
+}
+
+// addGroup returns the GID of the group, it's created if it doesn't exist. The
+// preferred GID is used for new groups if it's free.
+func (dbs *userDBs) addGroup(name string, preferredGID uint32) (uint32, error) {
+	if entry := dbs.group.find(name); entry != nil {
+		if len(entry) < 4 {
+			return 0, fmt.Errorf("invalid %s entry for group %q", path.Base(dbs.group.path), name)
+		}
+
+		gid, err := strconv.ParseUint(entry[2], 10, 32)
+		return uint32(gid), err
+	}
+
+	gid := preferredGID
+	if dbs.group.findID(gid) != nil {
+		var err error
+		if gid, err = dbs.group.nextFreeID(); err != nil {
+			return 0, err
+		}
+	}
+
+	dbs.group.add(name, "x", fmt.Sprint(gid), "")
+	if dbs.gshadow.exists {
+		dbs.gshadow.add(name, "!", "", "")
+	}
+
+	return gid, nil
+}
+
+// addGroupMember adds the user to the group, the group is created if it doesn't exist
+func (dbs *userDBs) addGroupMember(group, user string) error {
+	if dbs.group.find(group) == nil {
+		gid, err := dbs.group.nextFreeID()
+		if err != nil {
+			return err
+		}
+
+		if _, err := dbs.addGroup(group, gid); err != nil {
+			return err
+		}
+	}
+
+	for _, db := range []*userDB{dbs.group, dbs.gshadow} {
+		entry := db.find(group)
+		if entry == nil || len(entry) < 4 {
+			continue
+		}
+
+		if len(entry[3]) == 0 {
+			entry[3] = user
+		} else if !strings.Contains(","+entry[3]+",", ","+user+",") {
+			entry[3] += "," + user
+		}
+	}
+
+	return nil
+}
+
+// writeUserAuthorizedKeys adds the keys to the authorized_keys file in the home
+// directory of a user, the file and its directory are owned by the user
+func writeUserAuthorizedKeys(homeDir string, keys []string, uid, gid int) error {
+	sshDir := path.Join(homeDir, ".ssh")
+	authorizedKeysPath := path.Join(sshDir, "authorized_keys")
+	content, err := ioutil.ReadFile(authorizedKeysPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(authorizedKeysPath, authorizedkeys.Add(content, keys), 0600); err != nil {
+		return err
+	}
+
+	for _, p := range []string{sshDir, authorizedKeysPath} {
+		if err := os.Chown(p, uid, gid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
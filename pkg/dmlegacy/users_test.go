@@ -0,0 +1,82 @@
+package dmlegacy
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+const aliceKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJdbFdgbfD6sOsr5VGPWGbO1kgmnDfHU14rlcwNHBdDq alice@laptop"
+
+func TestWriteUsers(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("writing users requires root to chown their home directories")
+	}
+
+	dir, err := ioutil.TempDir("", "ignite-users-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		vmPasswdFile: "root:x:0:0:root:/root:/bin/sh\n" +
+			"app:x:1000:1000::/home/app:/bin/sh\n",
+		vmShadowFile:  "root:*:18000:0:99999:7:::\napp:*:18000:0:99999:7:::\n",
+		vmGroupFile:   "root:x:0:\ndocker:x:999:app\napp:x:1000:\n",
+		vmGShadowFile: "root:*::\ndocker:!::app\napp:!::\n",
+	}
+	for file, content := range files {
+		assert.NilError(t, os.MkdirAll(path.Dir(path.Join(dir, file)), 0755))
+		assert.NilError(t, ioutil.WriteFile(path.Join(dir, file), []byte(content), 0644))
+	}
+
+	uid := uint32(1500)
+	users := []api.VMUser{
+		{Name: "root", PasswordHash: "$6$salt$hash"},
+		{Name: "dev", Groups: []string{"docker", "wheel"}, Sudo: true},
+		{Name: "ops", UID: &uid, AuthorizedKeys: []string{aliceKey}},
+	}
+	assert.NilError(t, writeUsers(dir, users))
+
+	want := map[string]string{
+		vmPasswdFile: "root:x:0:0:root:/root:/bin/sh\n" +
+			"app:x:1000:1000::/home/app:/bin/sh\n" +
+			"dev:x:1001:1001::/home/dev:/bin/sh\n" +
+			"ops:x:1500:1500::/home/ops:/bin/sh\n",
+		vmShadowFile: "root:$6$salt$hash:18000:0:99999:7:::\n" +
+			"app:*:18000:0:99999:7:::\n" +
+			"dev:!:::::::\n" +
+			"ops:!:::::::\n",
+		vmGroupFile:   "root:x:0:\ndocker:x:999:app,dev\napp:x:1000:\ndev:x:1001:\nwheel:x:1002:dev\nops:x:1500:\n",
+		vmGShadowFile: "root:*::\ndocker:!::app,dev\napp:!::\ndev:!::\nwheel:!::dev\nops:!::\n",
+		vmSudoersFile: "# Written by ignite when the VM starts, set spec.users to change these users\n" +
+			"dev ALL=(ALL) NOPASSWD:ALL\n",
+		"/home/ops/.ssh/authorized_keys": aliceKey + "\n",
+	}
+	for file, content := range want {
+		got, err := ioutil.ReadFile(path.Join(dir, file))
+		assert.NilError(t, err)
+		assert.Equal(t, string(got), content, file)
+	}
+
+	// Writing the users again doesn't change them
+	assert.NilError(t, writeUsers(dir, users))
+	got, err := ioutil.ReadFile(path.Join(dir, vmGroupFile))
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), want[vmGroupFile])
+
+	// The UID of existing users can't be changed
+	uid = 2000
+	assert.ErrorContains(t, writeUsers(dir, users), "can't be changed")
+
+	// Without sudo users, the sudoers file is removed
+	assert.NilError(t, writeUsers(dir, nil))
+	_, err = os.Stat(path.Join(dir, vmSudoersFile))
+	assert.Assert(t, os.IsNotExist(err))
+}
@@ -1,6 +1,7 @@
 package dmlegacy
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -13,10 +14,12 @@ import (
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/authorizedkeys"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/operations/lookup"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/ignite/pkg/vault"
 )
 
 const (
@@ -32,10 +35,9 @@ ff02::2 ip6-allrouters
 	vmAuthorizedKeys = "/root/.ssh/authorized_keys"
 )
 
-// AllocateAndPopulateOverlay creates the overlay.dm file on top of an image, and
-// configures the snapshot in all ways needed. It also copies in contents from the
-// host as needed, and configures networking.
-func AllocateAndPopulateOverlay(vm *api.VM) error {
+// AllocateOverlay creates the sparse overlay.dm file holding the writes
+// of the VM's snapshot on top of its image.
+func AllocateOverlay(vm *api.VM) error {
 	requestedSize := vm.Spec.DiskSize.Bytes()
 	// Truncate only accepts an int64
 	if requestedSize > math.MaxInt64 {
@@ -63,6 +65,21 @@ func AllocateAndPopulateOverlay(vm *api.VM) error {
 		size = imageSize
 	}
 
+	// If the overlay size is limited, cap the space the overlay can allocate. The snapshot
+	// device is shrunk to fit into the capped overlay when it's activated, so the VM runs
+	// out of disk space before the overlay overflows.
+	if limit := vm.Spec.OverlaySizeLimit; limit != meta.EmptySize {
+		if overlaySizeFor(imageSize) > int64(limit.Bytes()) {
+			return fmt.Errorf("overlay size limit %s of VM %q is too small for its image of %s",
+				limit, vm.GetUID(), meta.NewSizeFromBytes(uint64(imageSize)))
+		}
+
+		if size = overlaySizeFor(size); int64(limit.Bytes()) < size {
+			log.Debugf("Limiting overlay of VM %q to %s", vm.GetUID(), limit)
+			size = int64(limit.Bytes())
+		}
+	}
+
 	// Make sure the all directories above the snapshot directory exists
 	if err := os.MkdirAll(path.Dir(vm.OverlayFile()), constants.DATA_DIR_PERM); err != nil {
 		return err
@@ -78,18 +95,46 @@ func AllocateAndPopulateOverlay(vm *api.VM) error {
 		return fmt.Errorf("failed to allocate overlay file for VM %q: %v", vm.GetUID(), err)
 	}
 
-	// populate the filesystem
-	return copyToOverlay(vm)
+	return nil
 }
 
-func copyToOverlay(vm *api.VM) (err error) {
-	_, err = ActivateSnapshot(vm)
+// ResizeOverlay grows the overlay file of the VM to its requested disk size. The
+// snapshot device takes the new size when it's activated the next time.
+func ResizeOverlay(vm *api.VM) error {
+	requestedSize := vm.Spec.DiskSize.Bytes()
+	if requestedSize > math.MaxInt64 {
+		return fmt.Errorf("requested size %d too large, cannot truncate", requestedSize)
+	}
+	size := int64(requestedSize)
+
+	// A capped overlay keeps its cap, the snapshot device grows up to what fits into it
+	if limit := vm.Spec.OverlaySizeLimit; limit != meta.EmptySize {
+		if size = overlaySizeFor(size); int64(limit.Bytes()) < size {
+			size = int64(limit.Bytes())
+		}
+	}
+
+	fi, err := os.Stat(vm.OverlayFile())
 	if err != nil {
-		return
+		return err
+	}
+
+	// Never shrink the overlay, that would discard the written blocks
+	if fi.Size() >= size {
+		return nil
+	}
+
+	if err := os.Truncate(vm.OverlayFile(), size); err != nil {
+		return fmt.Errorf("failed to resize overlay file for VM %q: %v", vm.GetUID(), err)
 	}
-	defer util.DeferErr(&err, func() error { return DeactivateSnapshot(vm) })
 
-	mp, err := util.Mount(vm.SnapshotDev())
+	return nil
+}
+
+// PopulateOverlay mounts the activated snapshot device of the VM and copies in
+// contents from the host as needed, and configures networking.
+func PopulateOverlay(vm *api.VM, devicePath string) (err error) {
+	mp, err := util.Mount(devicePath)
 	if err != nil {
 		return
 	}
@@ -133,6 +178,27 @@ func copyToOverlay(vm *api.VM) (err error) {
 		}
 	}
 
+	// Authorize the additional public keys next to the generated or given one,
+	// and the keys kept in Vault for all VMs
+	var authorizedKeys []string
+	if vm.Spec.SSH != nil {
+		authorizedKeys = append(authorizedKeys, vm.Spec.SSH.AuthorizedKeys...)
+	}
+
+	vaultKeys, err := vault.AuthorizedKeys()
+	if err != nil {
+		return
+	}
+	authorizedKeys = append(authorizedKeys, vaultKeys...)
+
+	if len(authorizedKeys) > 0 {
+		if err = updateAuthorizedKeys(mp.Path, func(content []byte) ([]byte, error) {
+			return authorizedkeys.Add(content, authorizedKeys), nil
+		}); err != nil {
+			return
+		}
+	}
+
 	ip := net.IP{127, 0, 0, 1}
 	if len(vm.Status.Network.IPAddresses) > 0 {
 		ip = vm.Status.Network.IPAddresses[0]
@@ -159,22 +225,84 @@ func copyToOverlay(vm *api.VM) (err error) {
 	return
 }
 
+// ReidentifyOverlay mounts the activated snapshot device of a VM cloned from the source
+// VM, and resets the identity the clone inherited from the disk of the source VM.
+// The SSH host keys are regenerated, the machine ID is cleared so the guest generates
+// a new one on boot, and the hostname of the source VM is replaced in /etc/hosts.
+// The remaining VM specific files are rewritten by PopulateOverlay.
+func ReidentifyOverlay(vm, source *api.VM, devicePath string) (err error) {
+	mp, err := util.Mount(devicePath)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, mp.Umount)
+
+	hostKeys, err := filepath.Glob(path.Join(mp.Path, "/etc/ssh/ssh_host_*"))
+	if err != nil {
+		return
+	}
+
+	if len(hostKeys) > 0 {
+		for _, hostKey := range hostKeys {
+			if err = os.Remove(hostKey); err != nil {
+				return
+			}
+		}
+
+		// Generate all default host key types under the mount point
+		if _, err = util.ExecuteCommand("ssh-keygen", "-A", "-f", mp.Path); err != nil {
+			return
+		}
+	}
+
+	// An empty machine ID makes systemd generate a new one on boot
+	for _, machineIDPath := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		machineIDFile := path.Join(mp.Path, machineIDPath)
+		if fi, statErr := os.Lstat(machineIDFile); statErr != nil || !fi.Mode().IsRegular() {
+			continue // Leave missing files and symlinks to the other ID alone
+		}
+
+		if err = ioutil.WriteFile(machineIDFile, []byte("\n"), 0444); err != nil {
+			return
+		}
+	}
+
+	hostsFilePath := path.Join(mp.Path, "/etc/hosts")
+	hosts, err := ioutil.ReadFile(hostsFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+
+	return ioutil.WriteFile(hostsFilePath, bytes.ReplaceAll(hosts, []byte(source.GetUID()), []byte(vm.GetUID())), 0644)
+}
+
+// copyKernelToOverlay copies the /boot directory of the kernel image of the VM into its
+// overlay, and the modules of the booted kernel version, so the guest can load them
 func copyKernelToOverlay(vm *api.VM, mountPoint string) error {
-	kernelUID, err := lookup.KernelUIDForVM(vm, providers.Client)
+	kernel, err := lookup.KernelForVM(vm, providers.Client)
 	if err != nil {
 		return err
 	}
-	kernelTarPath := path.Join(constants.KERNEL_DIR, kernelUID.String(), constants.KERNEL_TAR)
+	kernelTarPath := path.Join(constants.KERNEL_DIR, kernel.GetUID().String(), constants.KERNEL_TAR)
 
 	if !util.FileExists(kernelTarPath) {
 		log.Warnf("Could not find kernel overlay files, not copying into the VM.")
 		return nil
 	}
+
+	members, err := kernelTarMembers(kernelTarPath, kernel.Status.Version)
+	if err != nil {
+		return err
+	}
+
 	// It is important to not replace existing symlinks to directories when extracting because
 	// /lib might be a symlink (usually to usr/lib)
 	// WARNING: `-h` is only available on GNU and Busybox tar.  It is not present on BSD's bsdtar
 	//          It means "Follow symlinks"
-	_, err = util.ExecuteCommand("tar", "-h", "-xf", kernelTarPath, "-C", mountPoint)
+	_, err = util.ExecuteCommand("tar", append([]string{"-h", "-xf", kernelTarPath, "-C", mountPoint}, members...)...)
 	return err
 }
 
@@ -203,6 +331,16 @@ func writeEtcHostname(tmpDir, hostname string) error {
 // Generate a new SSH keypair for the vm
 func newSSHKeypair(vm *api.VM) (string, error) {
 	privKeyPath := path.Join(vm.ObjectPath(), fmt.Sprintf(constants.VM_SSH_KEY_TEMPLATE, vm.GetUID()))
+	if err := GenerateSSHKeypair(privKeyPath); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s.pub", privKeyPath), nil
+}
+
+// GenerateSSHKeypair generates a new SSH keypair without a passphrase, the private key
+// is written to privKeyPath and the public key next to it, with the .pub extension
+func GenerateSSHKeypair(privKeyPath string) error {
 	// TODO: In future versions, let the user specify what key algorithm to use through the API types
 	sshKeyAlgorithm := "ed25519"
 	if util.FIPSEnabled() {
@@ -210,9 +348,35 @@ func newSSHKeypair(vm *api.VM) (string, error) {
 		sshKeyAlgorithm = "rsa"
 	}
 	_, err := util.ExecuteCommand("ssh-keygen", "-q", "-t", sshKeyAlgorithm, "-N", "", "-f", privKeyPath)
+	return err
+}
+
+// UpdateAuthorizedKeys mounts the activated snapshot device of a stopped VM, and
+// replaces the authorized_keys file of root with the result of update
+func UpdateAuthorizedKeys(devicePath string, update func(content []byte) ([]byte, error)) (err error) {
+	mp, err := util.Mount(devicePath)
 	if err != nil {
-		return "", err
+		return
 	}
+	defer util.DeferErr(&err, mp.Umount)
 
-	return fmt.Sprintf("%s.pub", privKeyPath), nil
+	return updateAuthorizedKeys(mp.Path, update)
+}
+
+func updateAuthorizedKeys(mountPoint string, update func(content []byte) ([]byte, error)) error {
+	authorizedKeysPath := path.Join(mountPoint, vmAuthorizedKeys)
+	content, err := ioutil.ReadFile(authorizedKeysPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if content, err = update(content); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(authorizedKeysPath), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(authorizedKeysPath, content, 0600)
 }
@@ -0,0 +1,74 @@
+// Package emulation decides whether the VMs are emulated with the TCG emulation of QEMU
+// instead of run with Firecracker, which needs KVM. Emulation lets the same manifests run
+// on hosts without /dev/kvm, e.g. nested CI runners and some cloud VMs, for testing.
+package emulation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/arch"
+)
+
+// KVMDevice is the device Firecracker runs the VMs with
+var KVMDevice = "/dev/kvm"
+
+// moduleDir holds the parameters of the loaded kernel modules, the tests replace it
+var moduleDir = "/sys/module"
+
+// KVMAvailable returns whether the VMs can be run with KVM on the host
+func KVMAvailable() bool {
+	f, err := os.OpenFile(KVMDevice, os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+
+	return f.Close() == nil
+}
+
+// Enabled returns whether the VMs are emulated in the given mode
+func Enabled(mode api.EmulationMode) (bool, error) {
+	switch mode {
+	case "", api.EmulationModeNever:
+		return false, nil
+	case api.EmulationModeAuto:
+		return !KVMAvailable(), nil
+	case api.EmulationModeAlways:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown emulation mode %q, supported modes: never|auto|always", mode)
+	}
+}
+
+// NestedAvailable returns an error if KVM on the host can't expose the virtualization
+// extensions of the CPU to the guests, i.e. if the nested parameter of the KVM module
+// of the CPU vendor isn't enabled
+func NestedAvailable() error {
+	if arch.Host != arch.AMD64 {
+		return fmt.Errorf("nested virtualization is only supported on %s", arch.AMD64.Name)
+	}
+
+	for _, module := range []string{"kvm_intel", "kvm_amd"} {
+		nested, err := ioutil.ReadFile(path.Join(moduleDir, module, "parameters", "nested"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// kvm_intel reports Y or N, and kvm_amd 1 or 0
+		switch strings.TrimSpace(string(nested)) {
+		case "Y", "1":
+			return nil
+		}
+
+		return fmt.Errorf("nested virtualization isn't enabled on the host, reload %s with nested=1", module)
+	}
+
+	return fmt.Errorf("nested virtualization needs the kvm_intel or kvm_amd module to be loaded")
+}
@@ -0,0 +1,94 @@
+package emulation
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/arch"
+)
+
+func TestEnabled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-emulation-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	device := KVMDevice
+	defer func() { KVMDevice = device }()
+	KVMDevice = path.Join(dir, "kvm")
+
+	tests := []struct {
+		mode     api.EmulationMode
+		kvm      bool
+		expected bool
+	}{
+		{mode: "", kvm: false, expected: false},
+		{mode: api.EmulationModeNever, kvm: false, expected: false},
+		{mode: api.EmulationModeAuto, kvm: false, expected: true},
+		{mode: api.EmulationModeAuto, kvm: true, expected: false},
+		{mode: api.EmulationModeAlways, kvm: true, expected: true},
+	}
+
+	for _, rt := range tests {
+		os.Remove(KVMDevice)
+		if rt.kvm {
+			assert.NilError(t, ioutil.WriteFile(KVMDevice, nil, 0600))
+		}
+
+		enabled, err := Enabled(rt.mode)
+		assert.NilError(t, err)
+		assert.Equal(t, enabled, rt.expected, "mode %q, kvm %t", rt.mode, rt.kvm)
+	}
+
+	_, err = Enabled("sometimes")
+	assert.Error(t, err, `unknown emulation mode "sometimes", supported modes: never|auto|always`)
+}
+
+func TestNestedAvailable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-emulation-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	host, modules := arch.Host, moduleDir
+	defer func() { arch.Host, moduleDir = host, modules }()
+	arch.Host, moduleDir = arch.AMD64, dir
+
+	// module loads the KVM module with the nested parameter, if it's set
+	module := func(name, nested string) {
+		assert.NilError(t, os.RemoveAll(dir))
+		if len(name) == 0 {
+			return
+		}
+
+		assert.NilError(t, os.MkdirAll(path.Join(dir, name, "parameters"), 0755))
+		assert.NilError(t, ioutil.WriteFile(path.Join(dir, name, "parameters", "nested"), []byte(nested+"\n"), 0644))
+	}
+
+	tests := []struct {
+		module string
+		nested string
+		err    string
+	}{
+		{module: "kvm_intel", nested: "Y"},
+		{module: "kvm_amd", nested: "1"},
+		{module: "kvm_intel", nested: "N", err: "nested virtualization isn't enabled on the host, reload kvm_intel with nested=1"},
+		{err: "nested virtualization needs the kvm_intel or kvm_amd module to be loaded"},
+	}
+
+	for _, rt := range tests {
+		module(rt.module, rt.nested)
+		err := NestedAvailable()
+		if len(rt.err) > 0 {
+			assert.Error(t, err, rt.err)
+		} else {
+			assert.NilError(t, err, "module %q, nested %q", rt.module, rt.nested)
+		}
+	}
+
+	arch.Host = arch.ARM64
+	assert.Error(t, NestedAvailable(), "nested virtualization is only supported on amd64")
+}
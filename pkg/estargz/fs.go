@@ -0,0 +1,337 @@
+package estargz
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// Layer is an eStargz layer, read through the ReaderAt
+type Layer struct {
+	r    io.ReaderAt
+	size int64
+	toc  *TOC
+	// ends are where the gzip streams starting at the offsets of the entries end
+	ends map[int64]int64
+}
+
+// NewLayer reads the TOC of the eStargz layer of the given size. If tocDigest is set,
+// the TOC is verified against it. ErrNotEStargz is returned for other layers.
+func NewLayer(r io.ReaderAt, size int64, tocDigest digest.Digest) (*Layer, error) {
+	toc, tocOffset, err := readTOC(r, size, tocDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := []int64{tocOffset}
+	for _, entry := range toc.Entries {
+		if entry.Type == "chunk" || entry.Type == "reg" && entry.Size > 0 {
+			offsets = append(offsets, entry.Offset)
+		}
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	ends := make(map[int64]int64, len(offsets))
+	for i := 0; i < len(offsets)-1; i++ {
+		ends[offsets[i]] = offsets[i+1]
+	}
+
+	return &Layer{r: r, size: size, toc: toc, ends: ends}, nil
+}
+
+// file is a file in the root filesystem of the layers
+type file struct {
+	layer *Layer
+	entry *TOCEntry
+	// chunks are the entries holding the content of regular files, starting with the file itself
+	chunks []*TOCEntry
+	// prefetch is set for the files before the prefetch landmark of their layer
+	prefetch bool
+}
+
+// FS is the root filesystem of an image, assembled from the TOCs of its eStargz layers
+// like a container runtime stacks the layers. It's split into the files needed at boot,
+// and the rest of the files that can be loaded later.
+type FS struct {
+	files map[string]*file
+}
+
+// NewFS stacks the layers, the first layer is the lowest one. Files removed by whiteouts
+// in upper layers are dropped. Layers without a prefetch landmark are needed at boot.
+func NewFS(layers []*Layer) *FS {
+	fs := &FS{files: make(map[string]*file)}
+	for _, layer := range layers {
+		prefetch := true
+		for _, entry := range layer.toc.Entries {
+			if entry.Name == noPrefetchLandmark {
+				prefetch = false
+			}
+		}
+
+		var last *file
+		for _, entry := range layer.toc.Entries {
+			dir, base := path.Split(entry.Name)
+			dir = strings.TrimSuffix(dir, "/")
+
+			switch {
+			case len(entry.Name) == 0:
+				// The root directory exists in every filesystem
+			case entry.Name == prefetchLandmark || entry.Name == noPrefetchLandmark:
+				prefetch = false
+			case entry.Type == "chunk":
+				if last != nil && last.entry.Name == entry.Name {
+					last.chunks = append(last.chunks, entry)
+				}
+			case base == ".wh..wh..opq":
+				fs.removeChildren(dir, layer)
+			case strings.HasPrefix(base, ".wh."):
+				fs.remove(path.Join(dir, strings.TrimPrefix(base, ".wh.")))
+			default:
+				// A directory replaces the metadata of the lower one, anything else replaces it
+				if existing, ok := fs.files[entry.Name]; ok && (existing.entry.Type != "dir" || entry.Type != "dir") {
+					fs.remove(entry.Name)
+				}
+
+				f := &file{layer: layer, entry: entry, prefetch: prefetch}
+				if entry.Type == "reg" {
+					f.chunks = []*TOCEntry{entry}
+					last = f
+				}
+				fs.files[entry.Name] = f
+			}
+		}
+	}
+
+	return fs
+}
+
+// remove removes the file, and everything below it if it's a directory
+func (fs *FS) remove(name string) {
+	delete(fs.files, name)
+	fs.removeChildren(name, nil)
+}
+
+// removeChildren removes everything below the directory that isn't from the given layer
+func (fs *FS) removeChildren(dir string, layer *Layer) {
+	prefix := dir + "/"
+	if len(dir) == 0 {
+		prefix = ""
+	}
+
+	for name, f := range fs.files {
+		if strings.HasPrefix(name, prefix) && name != dir && f.layer != layer {
+			delete(fs.files, name)
+		}
+	}
+}
+
+// boot reports whether the file is needed at boot. Everything but regular files, and
+// hardlinks to regular files that aren't needed at boot, is.
+func (fs *FS) boot(f *file) bool {
+	switch f.entry.Type {
+	case "reg":
+		return f.prefetch
+	case "hardlink":
+		target, ok := fs.files[cleanName(f.entry.LinkName)]
+		return ok && fs.boot(target)
+	}
+
+	return true
+}
+
+// Sizes returns the size of the files needed at boot, and of the rest of the files
+func (fs *FS) Sizes() (boot, rest int64) {
+	for _, f := range fs.files {
+		if f.entry.Type != "reg" {
+			continue
+		}
+
+		if f.prefetch {
+			boot += f.entry.Size
+		} else {
+			rest += f.entry.Size
+		}
+	}
+
+	return
+}
+
+// WriteBoot writes the files needed at boot to w as a tar stream
+func (fs *FS) WriteBoot(w io.Writer) error {
+	return fs.write(w, func(f *file) bool { return fs.boot(f) })
+}
+
+// WriteRest writes the files that aren't needed at boot to w as a tar stream
+func (fs *FS) WriteRest(w io.Writer) error {
+	return fs.write(w, func(f *file) bool { return !fs.boot(f) })
+}
+
+// write writes the selected files as a tar stream. The directories come before their
+// contents, and the hardlinks after all other files, so their targets exist.
+func (fs *FS) write(w io.Writer, selected func(f *file) bool) error {
+	var files, links []*file
+	for _, f := range fs.files {
+		if !selected(f) {
+			continue
+		}
+
+		if f.entry.Type == "hardlink" {
+			links = append(links, f)
+		} else {
+			files = append(files, f)
+		}
+	}
+	sortFiles(files)
+	sortFiles(links)
+
+	tw := tar.NewWriter(w)
+	for _, f := range append(files, links...) {
+		if err := writeFile(tw, f); err != nil {
+			return fmt.Errorf("failed to write %q: %v", f.entry.Name, err)
+		}
+	}
+
+	return tw.Close()
+}
+
+// writeFile writes the tar header of the file, and reads its content from its layer
+func writeFile(tw *tar.Writer, f *file) error {
+	hdr, err := header(f.entry)
+	if err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	for _, chunk := range f.chunks {
+		size := chunk.ChunkSize
+		if size == 0 {
+			size = f.entry.Size - chunk.ChunkOffset
+		}
+		if size == 0 {
+			continue
+		}
+
+		if err := f.layer.readChunk(tw, chunk, size); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readChunk copies the uncompressed content of the chunk to w, and verifies its digest
+func (l *Layer) readChunk(w io.Writer, chunk *TOCEntry, size int64) error {
+	end, ok := l.ends[chunk.Offset]
+	if !ok {
+		return fmt.Errorf("no content at offset %d", chunk.Offset)
+	}
+
+	gz, err := gzip.NewReader(io.NewSectionReader(l.r, chunk.Offset, end-chunk.Offset))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	var verifier digest.Verifier
+	if len(chunk.ChunkDigest) != 0 {
+		d, err := digest.Parse(chunk.ChunkDigest)
+		if err != nil {
+			return err
+		}
+		verifier = d.Verifier()
+		w = io.MultiWriter(w, verifier)
+	}
+
+	if _, err := io.CopyN(w, gz, size); err != nil {
+		return err
+	}
+
+	if verifier != nil && !verifier.Verified() {
+		return fmt.Errorf("the chunk at offset %d doesn't match its digest %s", chunk.ChunkOffset, chunk.ChunkDigest)
+	}
+
+	return nil
+}
+
+// header returns the tar header of the TOC entry
+func header(entry *TOCEntry) (*tar.Header, error) {
+	hdr := &tar.Header{
+		Name:     entry.Name,
+		Mode:     entry.Mode,
+		Uid:      entry.UID,
+		Gid:      entry.GID,
+		Uname:    entry.Uname,
+		Gname:    entry.Gname,
+		Linkname: entry.LinkName,
+		Devmajor: int64(entry.DevMajor),
+		Devminor: int64(entry.DevMinor),
+	}
+
+	if len(entry.ModTime3339) != 0 {
+		modTime, err := time.Parse(time.RFC3339, entry.ModTime3339)
+		if err != nil {
+			return nil, err
+		}
+		hdr.ModTime = modTime
+	}
+
+	switch entry.Type {
+	case "dir":
+		hdr.Typeflag = tar.TypeDir
+		hdr.Name += "/"
+	case "reg":
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = entry.Size
+	case "symlink":
+		hdr.Typeflag = tar.TypeSymlink
+	case "hardlink":
+		hdr.Typeflag = tar.TypeLink
+		hdr.Linkname = cleanName(entry.LinkName)
+	case "char":
+		hdr.Typeflag = tar.TypeChar
+	case "block":
+		hdr.Typeflag = tar.TypeBlock
+	case "fifo":
+		hdr.Typeflag = tar.TypeFifo
+	default:
+		return nil, fmt.Errorf("unknown entry type %q", entry.Type)
+	}
+
+	for name, value := range entry.Xattrs {
+		if hdr.PAXRecords == nil {
+			hdr.PAXRecords = make(map[string]string, len(entry.Xattrs))
+		}
+		hdr.PAXRecords["SCHILY.xattr."+name] = string(value)
+	}
+
+	return hdr, nil
+}
+
+// sortFiles sorts the files by their path, so directories come before their contents
+func sortFiles(files []*file) {
+	sort.Slice(files, func(i, j int) bool {
+		return lessPath(files[i].entry.Name, files[j].entry.Name)
+	})
+}
+
+// lessPath compares paths by their elements, so a directory sorts right before its contents
+func lessPath(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] != bs[i] {
+			return as[i] < bs[i]
+		}
+	}
+
+	return len(as) < len(bs)
+}
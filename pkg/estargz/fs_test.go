@@ -0,0 +1,187 @@
+package estargz
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/assert"
+)
+
+// testEntry is a file of a test layer. Regular files are split into chunks of chunkSize.
+type testEntry struct {
+	name, typ, content, linkName string
+	chunkSize                    int
+}
+
+// buildLayer writes an eStargz layer with the entries, and returns it with the TOC digest
+func buildLayer(t *testing.T, entries []testEntry) ([]byte, digest.Digest) {
+	var buf bytes.Buffer
+	gzipMember := func(write func(w io.Writer)) int64 {
+		offset := int64(buf.Len())
+		gz := gzip.NewWriter(&buf)
+		write(gz)
+		assert.NilError(t, gz.Close())
+		return offset
+	}
+
+	toc := &TOC{Version: 1}
+	for _, e := range entries {
+		entry := &TOCEntry{Name: e.name, Type: e.typ, LinkName: e.linkName, Mode: 0644}
+		toc.Entries = append(toc.Entries, entry)
+		if e.typ != "reg" || len(e.content) == 0 {
+			continue
+		}
+
+		entry.Size = int64(len(e.content))
+		for chunkOffset := 0; chunkOffset < len(e.content); chunkOffset += e.chunkSize {
+			chunk := e.content[chunkOffset:]
+			if e.chunkSize != 0 && len(chunk) > e.chunkSize {
+				chunk = chunk[:e.chunkSize]
+			}
+
+			if chunkOffset != 0 {
+				entry = &TOCEntry{Name: e.name, Type: "chunk"}
+				toc.Entries = append(toc.Entries, entry)
+			}
+			entry.ChunkOffset = int64(chunkOffset)
+			if e.chunkSize != 0 {
+				entry.ChunkSize = int64(len(chunk))
+			}
+			entry.ChunkDigest = digest.FromString(chunk).String()
+			entry.Offset = gzipMember(func(w io.Writer) { io.WriteString(w, chunk) })
+
+			if e.chunkSize == 0 {
+				break
+			}
+		}
+	}
+
+	data, err := json.Marshal(toc)
+	assert.NilError(t, err)
+
+	tocOffset := gzipMember(func(w io.Writer) {
+		tw := tar.NewWriter(w)
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: tocName, Typeflag: tar.TypeReg, Size: int64(len(data))}))
+		_, err := tw.Write(data)
+		assert.NilError(t, err)
+		assert.NilError(t, tw.Close())
+	})
+
+	// The footer is an empty gzip stream with the TOC offset in its extra field, and a
+	// stored deflate block, written by hand as the size of the block differs by Go version
+	buf.Write([]byte{0x1f, 0x8b, 8, 4, 0, 0, 0, 0, 0, 0xff, 26, 0})
+	fmt.Fprintf(&buf, "SG\x16\x00%016xSTARGZ", tocOffset)
+	buf.Write([]byte{1, 0, 0, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 0})
+
+	return buf.Bytes(), digest.FromBytes(data)
+}
+
+func newTestLayer(t *testing.T, entries []testEntry) *Layer {
+	data, tocDigest := buildLayer(t, entries)
+	layer, err := NewLayer(bytes.NewReader(data), int64(len(data)), tocDigest)
+	assert.NilError(t, err)
+	return layer
+}
+
+// readTar returns the contents of the entries of the tar stream by name, links point to their target
+func readTar(t *testing.T, data []byte) map[string]string {
+	files := make(map[string]string)
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.NilError(t, err)
+
+		content, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		if len(hdr.Linkname) != 0 {
+			content = []byte("-> " + hdr.Linkname)
+		}
+		files[hdr.Name] = string(content)
+	}
+}
+
+func TestNewLayer(t *testing.T) {
+	data, tocDigest := buildLayer(t, []testEntry{{name: "etc", typ: "dir"}})
+
+	layer, err := NewLayer(bytes.NewReader(data), int64(len(data)), tocDigest)
+	assert.NilError(t, err)
+	assert.Equal(t, len(layer.toc.Entries), 1)
+	assert.Equal(t, layer.toc.Entries[0].Name, "etc")
+
+	_, err = NewLayer(bytes.NewReader(data), int64(len(data)), digest.FromString("other"))
+	assert.ErrorContains(t, err, "doesn't match its digest")
+
+	var plain bytes.Buffer
+	gz := gzip.NewWriter(&plain)
+	io.WriteString(gz, "a regular gzipped layer, without a TOC and footer")
+	assert.NilError(t, gz.Close())
+	_, err = NewLayer(bytes.NewReader(plain.Bytes()), int64(plain.Len()), "")
+	assert.Equal(t, err, ErrNotEStargz)
+}
+
+func TestFS(t *testing.T) {
+	lower := newTestLayer(t, []testEntry{
+		{name: "bin", typ: "dir"},
+		{name: "bin/sh", typ: "reg", content: "shell"},
+		{name: "etc", typ: "dir"},
+		{name: "etc/hostname", typ: "reg", content: "old"},
+		{name: prefetchLandmark, typ: "reg", content: "\x00"},
+		{name: "usr", typ: "dir"},
+		{name: "usr/big", typ: "reg", content: "0123456789", chunkSize: 4},
+		{name: "usr/big-link", typ: "hardlink", linkName: "usr/big"},
+		{name: "usr/removed", typ: "reg", content: "removed"},
+		{name: "var", typ: "dir"},
+		{name: "var/cache", typ: "reg", content: "cache"},
+	})
+	upper := newTestLayer(t, []testEntry{
+		{name: noPrefetchLandmark, typ: "reg", content: "\x00"},
+		{name: "etc", typ: "dir"},
+		{name: "etc/hostname", typ: "reg", content: "new"},
+		{name: "usr", typ: "dir"},
+		{name: "usr/.wh.removed", typ: "reg"},
+		{name: "var", typ: "dir"},
+		{name: "var/.wh..wh..opq", typ: "reg"},
+		{name: "var/log", typ: "symlink", linkName: "/tmp"},
+	})
+
+	fs := NewFS([]*Layer{lower, upper})
+
+	var boot, rest bytes.Buffer
+	assert.NilError(t, fs.WriteBoot(&boot))
+	assert.NilError(t, fs.WriteRest(&rest))
+
+	assert.DeepEqual(t, readTar(t, boot.Bytes()), map[string]string{
+		"bin/":    "",
+		"bin/sh":  "shell",
+		"etc/":    "",
+		"usr/":    "",
+		"var/":    "",
+		"var/log": "-> /tmp",
+	})
+	assert.DeepEqual(t, readTar(t, rest.Bytes()), map[string]string{
+		"etc/hostname": "new",
+		"usr/big":      "0123456789",
+		"usr/big-link": "-> usr/big",
+	})
+
+	bootSize, restSize := fs.Sizes()
+	assert.Equal(t, bootSize, int64(5))
+	assert.Equal(t, restSize, int64(13))
+}
+
+func TestLessPath(t *testing.T) {
+	// A directory sorts before its contents, even if a sibling sorts between them by string
+	assert.Assert(t, lessPath("usr", "usr/bin"))
+	assert.Assert(t, lessPath("usr/bin", "usr-local"))
+	assert.Assert(t, !lessPath("usr-local", "usr/bin"))
+}
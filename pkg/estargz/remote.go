@@ -0,0 +1,218 @@
+package estargz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	refdocker "github.com/containerd/containerd/reference/docker"
+	"github.com/containerd/containerd/remotes"
+	"github.com/opencontainers/go-digest"
+	imagespec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/runtime/containerd"
+)
+
+// Image is an image in a registry with eStargz layers. Its files are read from the
+// registry with range requests when they're written.
+type Image struct {
+	// Name is the normalized name of the image
+	Name string
+	// Manifest is the digest of the manifest of the image for the platform of the host
+	Manifest digest.Digest
+	// Config is the digest of the config of the image, its ID
+	Config digest.Digest
+	// Architecture is the architecture the image is built for, from its config
+	Architecture string
+	// FS is the root filesystem of the image
+	FS *FS
+
+	blobs []io.Closer
+}
+
+// Open resolves the image reference in its registry, and reads the TOCs of its layers.
+// ErrNotEStargz is returned if one of the layers isn't in the eStargz format.
+func Open(ctx context.Context, ref string) (*Image, error) {
+	named, err := refdocker.ParseDockerRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := containerd.NewRemoteResolver(refdocker.Domain(named), providers.RegistryConfigDir)
+	if err != nil {
+		return nil, err
+	}
+
+	name, desc, err := resolver.Resolve(ctx, named.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve image %q: %v", ref, err)
+	}
+
+	fetcher, err := resolver.Fetcher(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if desc, err = platformManifest(ctx, fetcher, desc); err != nil {
+		return nil, fmt.Errorf("failed to read the manifest of image %q: %v", ref, err)
+	}
+
+	content, err := fetch(ctx, fetcher, desc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest of image %q: %v", ref, err)
+	}
+
+	var manifest imagespec.Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest of image %q: %v", ref, err)
+	}
+
+	content, err = fetch(ctx, fetcher, manifest.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config of image %q: %v", ref, err)
+	}
+
+	var config imagespec.Image
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("invalid config of image %q: %v", ref, err)
+	}
+
+	img := &Image{
+		Name:         refdocker.TrimNamed(named).String(),
+		Manifest:     desc.Digest,
+		Config:       manifest.Config.Digest,
+		Architecture: config.Architecture,
+	}
+
+	layers := make([]*Layer, 0, len(manifest.Layers))
+	for _, desc := range manifest.Layers {
+		blob, err := openBlob(ctx, fetcher, desc)
+		if err != nil {
+			img.Close()
+			return nil, err
+		}
+		img.blobs = append(img.blobs, blob)
+
+		layer, err := NewLayer(blob, desc.Size, digest.Digest(desc.Annotations[TOCDigestAnnotation]))
+		if err != nil {
+			img.Close()
+			if err == ErrNotEStargz {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("failed to read layer %s of image %q: %v", desc.Digest, ref, err)
+		}
+		layers = append(layers, layer)
+	}
+
+	img.FS = NewFS(layers)
+	return img, nil
+}
+
+// Close closes the connections to the registry
+func (i *Image) Close() (err error) {
+	for _, blob := range i.blobs {
+		if closeErr := blob.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return
+}
+
+// platformManifest returns the manifest for the platform of the host if desc is an index
+func platformManifest(ctx context.Context, fetcher remotes.Fetcher, desc imagespec.Descriptor) (imagespec.Descriptor, error) {
+	switch desc.MediaType {
+	case imagespec.MediaTypeImageIndex, images.MediaTypeDockerSchema2ManifestList:
+	default:
+		return desc, nil
+	}
+
+	content, err := fetch(ctx, fetcher, desc)
+	if err != nil {
+		return desc, err
+	}
+
+	var index imagespec.Index
+	if err := json.Unmarshal(content, &index); err != nil {
+		return desc, err
+	}
+
+	matcher := platforms.Default()
+	for _, manifest := range index.Manifests {
+		if manifest.Platform == nil || matcher.Match(*manifest.Platform) {
+			return manifest, nil
+		}
+	}
+
+	return desc, fmt.Errorf("no manifest for platform %s", platforms.DefaultString())
+}
+
+// fetch reads the content of the descriptor and verifies its digest
+func fetch(ctx context.Context, fetcher remotes.Fetcher, desc imagespec.Descriptor) ([]byte, error) {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	if d := desc.Digest.Algorithm().FromBytes(content); d != desc.Digest {
+		return nil, fmt.Errorf("digest mismatch, expected %s but got %s", desc.Digest, d)
+	}
+
+	return content, nil
+}
+
+// blob reads a layer from the registry at any offset. The registry is only requested
+// again when the offset changes, so sequential reads share one request.
+type blob struct {
+	rs io.ReadSeeker
+	c  io.Closer
+	mu sync.Mutex
+}
+
+// openBlob opens the layer of the descriptor, without reading it yet
+func openBlob(ctx context.Context, fetcher remotes.Fetcher, desc imagespec.Descriptor) (*blob, error) {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch layer %s: %v", desc.Digest, err)
+	}
+
+	rs, ok := rc.(io.ReadSeeker)
+	if !ok {
+		rc.Close()
+		return nil, fmt.Errorf("the registry doesn't support reading layer %s at an offset", desc.Digest)
+	}
+
+	return &blob{rs: rs, c: rc}, nil
+}
+
+func (b *blob) ReadAt(p []byte, off int64) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n, err := io.ReadFull(b.rs, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+
+	return n, err
+}
+
+func (b *blob) Close() error {
+	return b.c.Close()
+}
@@ -0,0 +1,159 @@
+// Package estargz reads eStargz image layers lazily. An eStargz layer is a gzipped tar
+// where every file is compressed separately, and which ends with a table of contents (TOC)
+// of the files and their offsets, so single files can be read with range requests without
+// downloading the whole layer. Optimized eStargz images mark the files needed at boot with
+// a prefetch landmark, which lets the VMs boot before the rest of the files are loaded.
+package estargz
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	// footerSize is the size of the gzip stream ending an eStargz layer, which holds
+	// the offset of the TOC in its extra field
+	footerSize = 51
+	// tocName is the name of the TOC in the tar stream at the TOC offset
+	tocName = "stargz.index.json"
+
+	// prefetchLandmark separates the files needed at boot from the rest in a layer
+	prefetchLandmark = ".prefetch.landmark"
+	// noPrefetchLandmark marks layers without files needed at boot
+	noPrefetchLandmark = ".no.prefetch.landmark"
+
+	// TOCDigestAnnotation is the annotation of an eStargz layer holding the digest of its TOC
+	TOCDigestAnnotation = "containerd.io/snapshot/stargz/toc.digest"
+)
+
+// ErrNotEStargz is returned for layers that aren't in the eStargz format
+var ErrNotEStargz = errors.New("the layer isn't in the eStargz format")
+
+// TOC is the table of contents of an eStargz layer
+type TOC struct {
+	Version int         `json:"version"`
+	Entries []*TOCEntry `json:"entries"`
+}
+
+// TOCEntry is an entry of a TOC. Regular files larger than the chunk size of the layer
+// are split into chunks, the chunks after the first have their own entries.
+type TOCEntry struct {
+	// Name is the path of the entry in the layer, without a leading "./"
+	Name string `json:"name"`
+	// Type is dir, reg, symlink, hardlink, char, block, fifo or chunk
+	Type        string            `json:"type"`
+	Size        int64             `json:"size,omitempty"`
+	ModTime3339 string            `json:"modtime,omitempty"`
+	LinkName    string            `json:"linkName,omitempty"`
+	Mode        int64             `json:"mode,omitempty"`
+	UID         int               `json:"uid,omitempty"`
+	GID         int               `json:"gid,omitempty"`
+	Uname       string            `json:"userName,omitempty"`
+	Gname       string            `json:"groupName,omitempty"`
+	DevMajor    int               `json:"devMajor,omitempty"`
+	DevMinor    int               `json:"devMinor,omitempty"`
+	Xattrs      map[string][]byte `json:"xattrs,omitempty"`
+	// Offset is where the gzip stream holding the content of the file or chunk starts
+	Offset      int64  `json:"offset,omitempty"`
+	ChunkOffset int64  `json:"chunkOffset,omitempty"`
+	ChunkSize   int64  `json:"chunkSize,omitempty"`
+	ChunkDigest string `json:"chunkDigest,omitempty"`
+}
+
+// readTOC reads the TOC of the eStargz layer of the given size. If tocDigest is set, the
+// TOC is verified against it.
+func readTOC(r io.ReaderAt, size int64, tocDigest digest.Digest) (*TOC, int64, error) {
+	if size < footerSize {
+		return nil, 0, ErrNotEStargz
+	}
+
+	footer := make([]byte, footerSize)
+	if _, err := r.ReadAt(footer, size-footerSize); err != nil {
+		return nil, 0, err
+	}
+
+	tocOffset, err := parseFooter(footer)
+	if err != nil {
+		return nil, 0, err
+	}
+	if tocOffset >= size-footerSize {
+		return nil, 0, fmt.Errorf("invalid TOC offset %d in a layer of %d bytes", tocOffset, size)
+	}
+
+	gz, err := gzip.NewReader(io.NewSectionReader(r, tocOffset, size-footerSize-tocOffset))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read the TOC: %v", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, 0, fmt.Errorf("no %s in the TOC", tocName)
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read the TOC: %v", err)
+		}
+		if hdr.Name != tocName {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read the TOC: %v", err)
+		}
+
+		if len(tocDigest) != 0 && digest.FromBytes(data) != tocDigest {
+			return nil, 0, fmt.Errorf("the TOC doesn't match its digest %s", tocDigest)
+		}
+
+		toc := &TOC{}
+		if err := json.Unmarshal(data, toc); err != nil {
+			return nil, 0, fmt.Errorf("failed to parse the TOC: %v", err)
+		}
+
+		for _, entry := range toc.Entries {
+			entry.Name = cleanName(entry.Name)
+		}
+
+		return toc, tocOffset, nil
+	}
+}
+
+// parseFooter returns the TOC offset in the extra field of the footer, which is
+// "SG", its length of 22 bytes, and the offset as 16 hex digits followed by "STARGZ"
+func parseFooter(footer []byte) (int64, error) {
+	gz, err := gzip.NewReader(strings.NewReader(string(footer)))
+	if err != nil {
+		return 0, ErrNotEStargz
+	}
+	defer gz.Close()
+
+	extra := string(gz.Header.Extra)
+	if len(extra) != 26 || !strings.HasPrefix(extra, "SG") || !strings.HasSuffix(extra, "STARGZ") {
+		return 0, ErrNotEStargz
+	}
+
+	tocOffset, err := strconv.ParseInt(extra[4:20], 16, 64)
+	if err != nil {
+		return 0, ErrNotEStargz
+	}
+
+	return tocOffset, nil
+}
+
+// cleanName returns the path of a TOC entry without leading "./" or "/", and trailing slashes
+func cleanName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,193 @@
+package etcdstorage
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// requestTimeout is how long to wait for a response of an etcd member
+const requestTimeout = 10 * time.Second
+
+// client calls the JSON gateway of the etcd v3 API, trying the endpoints in order
+type client struct {
+	endpoints []string
+	http      *http.Client
+}
+
+// keyValue is a key of etcd and its value
+type keyValue struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+	// ModRevision is the revision of the cluster the key was last modified in, the 64-bit
+	// integers are encoded as strings
+	ModRevision string `json:"mod_revision"`
+}
+
+type rangeRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end,omitempty"`
+	KeysOnly bool   `json:"keys_only,omitempty"`
+}
+
+type rangeResponse struct {
+	Kvs []keyValue `json:"kvs"`
+}
+
+type putRequest struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type deleteRangeRequest struct {
+	Key []byte `json:"key"`
+}
+
+// errorResponse is the body of the responses of failed requests
+type errorResponse struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+func newClient(cfg *Config) (*client, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, fmt.Errorf("no etcd endpoints given")
+	}
+
+	tlsConfig := &tls.Config{}
+	if len(cfg.CACert) != 0 {
+		ca, err := ioutil.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %q", cfg.CACert)
+		}
+	}
+
+	if len(cfg.Cert) != 0 || len(cfg.Key) != 0 {
+		cert, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the etcd client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	endpoints := make([]string, 0, len(cfg.Endpoints))
+	for _, e := range cfg.Endpoints {
+		endpoints = append(endpoints, strings.TrimSuffix(e, "/"))
+	}
+
+	return &client{
+		endpoints: endpoints,
+		http: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+	}, nil
+}
+
+// get returns the value of the key, or nil if it doesn't exist
+func (c *client) get(key string) (*keyValue, error) {
+	resp := &rangeResponse{}
+	if err := c.call("/v3/kv/range", &rangeRequest{Key: []byte(key)}, resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+
+	return &resp.Kvs[0], nil
+}
+
+// keys returns the keys starting with prefix
+func (c *client) keys(prefix string) ([]string, error) {
+	resp := &rangeResponse{}
+	if err := c.call("/v3/kv/range", &rangeRequest{Key: []byte(prefix), RangeEnd: prefixEnd(prefix), KeysOnly: true}, resp); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		keys = append(keys, string(kv.Key))
+	}
+
+	return keys, nil
+}
+
+func (c *client) put(key string, value []byte) error {
+	return c.call("/v3/kv/put", &putRequest{Key: []byte(key), Value: value}, nil)
+}
+
+func (c *client) delete(key string) error {
+	return c.call("/v3/kv/deleterange", &deleteRangeRequest{Key: []byte(key)}, nil)
+}
+
+// call posts the request to the first endpoint that responds, and decodes its response. The
+// []byte fields are encoded in base64 like the gateway expects.
+func (c *client) call(method string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, endpoint := range c.endpoints {
+		r, err := c.http.Post(endpoint+method, "application/json", bytes.NewReader(body))
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		content, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		if r.StatusCode != http.StatusOK {
+			e := &errorResponse{}
+			if json.Unmarshal(content, e) != nil {
+				e.Message = strings.TrimSpace(string(content))
+			} else if len(e.Message) == 0 {
+				e.Message = e.Error
+			}
+			return fmt.Errorf("etcd %s failed with status %d: %s", method, r.StatusCode, e.Message)
+		}
+
+		if resp == nil {
+			return nil
+		}
+
+		if err := json.Unmarshal(content, resp); err != nil {
+			return fmt.Errorf("failed to decode the response of etcd %s: %v", method, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no etcd endpoint reachable: %s", strings.Join(errs, "; "))
+}
+
+// prefixEnd returns the end of the range of the keys starting with prefix
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+
+	// The prefix is all 0xff, the range ends at the last key
+	return []byte{0}
+}
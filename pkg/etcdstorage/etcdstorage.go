@@ -0,0 +1,308 @@
+// Package etcdstorage stores the metadata of the VMs, images, kernels and VM templates in
+// etcd, so multiple ignite hosts can share their objects. The objects are read and written
+// through the JSON gateway of the etcd v3 API, a key per object under a prefix.
+//
+// Only the metadata is shared: the files of the objects, e.g. the disks of the images and
+// VMs, are kept in the data directory of the host. VMs keep their metadata file like in the
+// bolt storage, see storagebackend.HasFile.
+package etcdstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/storagebackend"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage"
+)
+
+// DefaultPrefix is the prefix of the keys of the objects if none is given
+const DefaultPrefix = "/ignite"
+
+// Config is how to reach the etcd cluster, it's stored in the data directory while the
+// metadata is stored in etcd
+type Config struct {
+	// Endpoints are the URLs of the etcd members, e.g. https://10.0.0.1:2379
+	Endpoints []string `json:"endpoints"`
+	// Prefix is the prefix of the keys of the objects, the hosts sharing their objects use
+	// the same prefix
+	Prefix string `json:"prefix,omitempty"`
+	// CACert is the CA certificate file verifying the etcd members
+	CACert string `json:"caCert,omitempty"`
+	// Cert and Key are the client certificate and key files authenticating to etcd
+	Cert string `json:"cert,omitempty"`
+	Key  string `json:"key,omitempty"`
+}
+
+// Backend stores the metadata in etcd. Config is the cluster the metadata files are
+// imported to, the cluster of the enabled storage is read from the data directory.
+type Backend struct {
+	Config *Config
+}
+
+var _ storagebackend.Backend = &Backend{}
+
+func (b *Backend) Name() string {
+	return "etcd"
+}
+
+// Enabled returns whether the data directory dir has the settings of an etcd cluster
+func (b *Backend) Enabled(dir string) bool {
+	return util.FileExists(configPath(dir))
+}
+
+func (b *Backend) Location(dir string) string {
+	cfg, err := readConfig(dir)
+	if err != nil {
+		return configPath(dir)
+	}
+
+	return fmt.Sprintf("%s, prefix %s", strings.Join(cfg.Endpoints, ","), cfg.Prefix)
+}
+
+func (b *Backend) NewRawStorage(dir string) (storage.RawStorage, error) {
+	cfg, err := readConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRawStorage(dir, cfg)
+}
+
+// Import copies the objects of the metadata files to etcd, next to the objects other hosts
+// stored there, and enables the storage
+func (b *Backend) Import(dir string) (int, error) {
+	if b.Enabled(dir) {
+		return 0, fmt.Errorf("the metadata of %q is already stored in etcd", dir)
+	}
+
+	if b.Config == nil {
+		return 0, fmt.Errorf("no etcd cluster given")
+	}
+
+	r, err := NewRawStorage(dir, b.Config)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	if err := storagebackend.ReadFiles(dir, func(key storage.Key, content []byte, modTime int64) error {
+		count++
+		return r.put(key, content, modTime)
+	}); err != nil {
+		return 0, err
+	}
+
+	return count, writeConfig(dir, r.cfg)
+}
+
+// Export writes the objects of etcd to the metadata files, including the ones of the other
+// hosts, and disables the storage. The objects are kept in etcd.
+func (b *Backend) Export(dir string) (int, error) {
+	if !b.Enabled(dir) {
+		return 0, fmt.Errorf("the metadata of %q isn't stored in etcd", dir)
+	}
+
+	r, err := b.NewRawStorage(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := storagebackend.WriteFiles(r, dir)
+	if err != nil {
+		return count, err
+	}
+
+	return count, os.Remove(configPath(dir))
+}
+
+// RawStorage stores the objects of the data directory in etcd
+type RawStorage struct {
+	dir    string
+	cfg    Config
+	client *client
+}
+
+var _ storage.RawStorage = &RawStorage{}
+
+// NewRawStorage returns a RawStorage for the data directory dir storing the objects in the
+// given etcd cluster
+func NewRawStorage(dir string, cfg *Config) (*RawStorage, error) {
+	c, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &RawStorage{dir: dir, cfg: *cfg, client: c}
+	if len(r.cfg.Prefix) == 0 {
+		r.cfg.Prefix = DefaultPrefix
+	}
+	r.cfg.Prefix = strings.TrimSuffix(r.cfg.Prefix, "/")
+
+	return r, nil
+}
+
+func (r *RawStorage) Read(key storage.Key) ([]byte, error) {
+	rec, _, err := r.get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if rec == nil {
+		return nil, storagebackend.NotExist("etcd", key)
+	}
+
+	content, modTime, changed, err := storagebackend.ReadChangedFile(r.dir, key, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		// ignite-spawn updated the VM, store its changes
+		if err := r.put(key, content, modTime); err != nil {
+			return nil, err
+		}
+
+		return content, nil
+	}
+
+	return rec.Object, nil
+}
+
+func (r *RawStorage) Exists(key storage.Key) bool {
+	kv, err := r.client.get(r.etcdKey(key))
+	return err == nil && kv != nil
+}
+
+func (r *RawStorage) Write(key storage.Key, content []byte) error {
+	if err := storagebackend.WriteFile(r.dir, key, content); err != nil {
+		return err
+	}
+
+	modTime, err := storagebackend.FileModTime(r.dir, key)
+	if err != nil {
+		return err
+	}
+
+	return r.put(key, content, modTime)
+}
+
+// Delete deletes the object, and its directory like the metadata files do
+func (r *RawStorage) Delete(key storage.Key) error {
+	if err := r.client.delete(r.etcdKey(key)); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(storagebackend.ObjectDir(r.dir, key))
+}
+
+func (r *RawStorage) List(kindKey storage.KindKey) ([]storage.Key, error) {
+	prefix := r.cfg.Prefix + "/" + kindKey.String() + "/"
+	etcdKeys, err := r.client.keys(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]storage.Key, 0, len(etcdKeys))
+	for _, k := range etcdKeys {
+		keys = append(keys, storage.NewKey(kindKey.Kind, runtime.UID(strings.TrimPrefix(k, prefix))))
+	}
+
+	return keys, nil
+}
+
+// Checksum returns the revision of etcd the object was last modified in, and the
+// modification time of the metadata file of a VM if it changed since it was written to
+// etcd. If the object doesn't exist, it returns an empty string.
+func (r *RawStorage) Checksum(key storage.Key) (string, error) {
+	rec, revision, err := r.get(key)
+	if err != nil || rec == nil {
+		return "", err
+	}
+
+	checksum := revision
+	if modTime := storagebackend.FileChanged(r.dir, key, rec); modTime != 0 {
+		checksum += "-" + strconv.FormatInt(modTime, 10)
+	}
+
+	return checksum, nil
+}
+
+func (r *RawStorage) Format(key storage.Key) storage.Format {
+	return storage.FormatJSON
+}
+
+func (r *RawStorage) WatchDir() string {
+	return r.dir
+}
+
+func (r *RawStorage) GetKey(p string) (storage.Key, error) {
+	return storage.NewGenericRawStorage(r.dir).GetKey(p)
+}
+
+// etcdKey returns the key of the object in etcd, e.g. /ignite/vm/1699b6ba255cde7f
+func (r *RawStorage) etcdKey(key storage.Key) string {
+	return r.cfg.Prefix + "/" + key.String()
+}
+
+// get returns the record of the object and the revision it was last modified in, or nil if
+// the object doesn't exist
+func (r *RawStorage) get(key storage.Key) (*storagebackend.Record, string, error) {
+	kv, err := r.client.get(r.etcdKey(key))
+	if err != nil || kv == nil {
+		return nil, "", err
+	}
+
+	rec := &storagebackend.Record{}
+	if err := json.Unmarshal(kv.Value, rec); err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s from etcd: %v", key, err)
+	}
+
+	return rec, kv.ModRevision, nil
+}
+
+func (r *RawStorage) put(key storage.Key, content []byte, modTime int64) error {
+	value, err := json.Marshal(&storagebackend.Record{
+		ModTime: modTime,
+		Object:  content,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s for etcd: %v", key, err)
+	}
+
+	return r.client.put(r.etcdKey(key), value)
+}
+
+func configPath(dir string) string {
+	return path.Join(dir, constants.METADATA_ETCD)
+}
+
+func readConfig(dir string) (*Config, error) {
+	content, err := ioutil.ReadFile(configPath(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if err := json.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode the etcd settings %q: %v", configPath(dir), err)
+	}
+
+	return cfg, nil
+}
+
+func writeConfig(dir string, cfg Config) error {
+	content, err := json.MarshalIndent(&cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(configPath(dir), append(content, '\n'), 0600)
+}
@@ -0,0 +1,233 @@
+package etcdstorage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/weaveworks/libgitops/pkg/storage"
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+var (
+	vmKey    = storage.NewKey(api.KindVM, "1699b6ba255cde7f")
+	imageKey = storage.NewKey(api.KindImage, "ad5b7e8d1ef2d5a4")
+)
+
+// fakeEtcd serves the requests of the JSON gateway of etcd used by the storage
+type fakeEtcd struct {
+	mu       sync.Mutex
+	revision int64
+	kvs      map[string]keyValue
+}
+
+func newFakeEtcd() *httptest.Server {
+	e := &fakeEtcd{kvs: map[string]keyValue{}}
+	return httptest.NewServer(e)
+}
+
+func (e *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var resp interface{} = struct{}{}
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		req := &rangeRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, `{"message":"invalid request"}`, http.StatusBadRequest)
+			return
+		}
+
+		rr := &rangeResponse{}
+		for k, kv := range e.kvs {
+			if k == string(req.Key) || (req.RangeEnd != nil && k >= string(req.Key) && k < string(req.RangeEnd)) {
+				if req.KeysOnly {
+					kv.Value = nil
+				}
+				rr.Kvs = append(rr.Kvs, kv)
+			}
+		}
+		sort.Slice(rr.Kvs, func(i, j int) bool { return bytes.Compare(rr.Kvs[i].Key, rr.Kvs[j].Key) < 0 })
+		resp = rr
+	case "/v3/kv/put":
+		req := &putRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, `{"message":"invalid request"}`, http.StatusBadRequest)
+			return
+		}
+
+		e.revision++
+		e.kvs[string(req.Key)] = keyValue{Key: req.Key, Value: req.Value, ModRevision: strconv.FormatInt(e.revision, 10)}
+	case "/v3/kv/deleterange":
+		req := &deleteRangeRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, `{"message":"invalid request"}`, http.StatusBadRequest)
+			return
+		}
+
+		e.revision++
+		delete(e.kvs, string(req.Key))
+	default:
+		http.Error(w, `{"error":"Not Found","message":"Not Found"}`, http.StatusNotFound)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ignite-etcdstorage-test")
+	assert.NilError(t, err)
+	return dir
+}
+
+func manifest(key storage.Key, name string) []byte {
+	return []byte(fmt.Sprintf(`{"kind":%q,"apiVersion":"ignite.weave.works/v1alpha5","metadata":{"name":%q,"uid":%q}}`,
+		key.Kind.Title(), name, key.UID))
+}
+
+func TestRawStorage(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	etcd := newFakeEtcd()
+	defer etcd.Close()
+
+	// The unreachable endpoint is skipped
+	r, err := NewRawStorage(dir, &Config{Endpoints: []string{"http://127.0.0.1:1", etcd.URL}})
+	assert.NilError(t, err)
+	assert.NilError(t, r.Write(vmKey, manifest(vmKey, "my-vm")))
+	assert.NilError(t, r.Write(imageKey, manifest(imageKey, "ubuntu")))
+
+	// Only VMs keep their metadata file, the directories are created for the files of the objects
+	vmFile := path.Join(dir, vmKey.String(), constants.METADATA)
+	assert.Assert(t, util.FileExists(vmFile))
+	assert.Assert(t, !util.FileExists(path.Join(dir, imageKey.String(), constants.METADATA)))
+
+	keys, err := r.List(imageKey.KindKey)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, keys, []storage.Key{imageKey})
+	assert.Assert(t, r.Exists(vmKey))
+	assert.Assert(t, !r.Exists(storage.NewKey(api.KindVM, "0000000000000000")))
+
+	checksum, err := r.Checksum(vmKey)
+	assert.NilError(t, err)
+	assert.Equal(t, checksum, "1")
+
+	// ignite-spawn updates the metadata file of the VM
+	time.Sleep(10 * time.Millisecond)
+	renamed := manifest(vmKey, "renamed-vm")
+	assert.NilError(t, ioutil.WriteFile(vmFile, renamed, 0644))
+
+	changed, err := r.Checksum(vmKey)
+	assert.NilError(t, err)
+	assert.Assert(t, changed != checksum)
+
+	content, err := r.Read(vmKey)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), string(renamed))
+
+	// The change was stored
+	checksum, err = r.Checksum(vmKey)
+	assert.NilError(t, err)
+	assert.Equal(t, checksum, "3")
+
+	assert.NilError(t, r.Delete(vmKey))
+	assert.Assert(t, !util.DirExists(path.Join(dir, vmKey.String())))
+	_, err = r.Read(vmKey)
+	assert.Assert(t, os.IsNotExist(err))
+
+	checksum, err = r.Checksum(vmKey)
+	assert.NilError(t, err)
+	assert.Equal(t, checksum, "")
+}
+
+func TestImportExport(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	etcd := newFakeEtcd()
+	defer etcd.Close()
+
+	files := storage.NewGenericRawStorage(dir)
+	assert.NilError(t, files.Write(vmKey, manifest(vmKey, "my-vm")))
+	assert.NilError(t, files.Write(imageKey, manifest(imageKey, "ubuntu")))
+
+	b := &Backend{}
+	_, err := b.Import(dir)
+	assert.ErrorContains(t, err, "no etcd cluster given")
+
+	b = &Backend{Config: &Config{Endpoints: []string{etcd.URL}, Prefix: "/hosts/"}}
+	count, err := b.Import(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, count, 2)
+	assert.Assert(t, b.Enabled(dir))
+	assert.Equal(t, b.Location(dir), etcd.URL+", prefix /hosts")
+
+	_, err = b.Import(dir)
+	assert.ErrorContains(t, err, "already stored in etcd")
+
+	// Another host with the same prefix sees the objects, and adds its own
+	otherDir := newTestDir(t)
+	defer os.RemoveAll(otherDir)
+
+	other, err := NewRawStorage(otherDir, &Config{Endpoints: []string{etcd.URL}, Prefix: "/hosts"})
+	assert.NilError(t, err)
+	otherKey := storage.NewKey(api.KindImage, "b5b0c3ab3b31e9a2")
+	assert.NilError(t, other.Write(otherKey, manifest(otherKey, "alpine")))
+
+	r, err := (&Backend{}).NewRawStorage(dir)
+	assert.NilError(t, err)
+	images, err := r.List(imageKey.KindKey)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, images, []storage.Key{imageKey, otherKey})
+
+	count, err = b.Export(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, count, 3)
+	assert.Assert(t, !b.Enabled(dir))
+
+	content, err := files.Read(otherKey)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), string(manifest(otherKey, "alpine")))
+
+	_, err = b.Export(dir)
+	assert.ErrorContains(t, err, "isn't stored in etcd")
+}
+
+func TestErrors(t *testing.T) {
+	etcd := newFakeEtcd()
+	defer etcd.Close()
+
+	c, err := newClient(&Config{Endpoints: []string{etcd.URL + "/"}})
+	assert.NilError(t, err)
+	assert.ErrorContains(t, c.call("/v3/kv/unknown", struct{}{}, nil), "failed with status 404: Not Found")
+
+	c, err = newClient(&Config{Endpoints: []string{"http://127.0.0.1:1"}})
+	assert.NilError(t, err)
+	assert.ErrorContains(t, c.put("key", nil), "no etcd endpoint reachable")
+
+	_, err = newClient(&Config{})
+	assert.ErrorContains(t, err, "no etcd endpoints given")
+}
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Equal(t, string(prefixEnd("/ignite/vm/")), "/ignite/vm0")
+	assert.DeepEqual(t, prefixEnd("a\xff"), []byte("b"))
+	assert.DeepEqual(t, prefixEnd("\xff"), []byte{0})
+}
@@ -0,0 +1,275 @@
+// Package events emits events about the VMs, images and kernels, so other programs
+// can react to them without polling. The events are found by comparing the objects in
+// the storage over time, which includes the changes made by the ignite CLI.
+package events
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// Type is the type of an event
+type Type string
+
+const (
+	// VMCreated is emitted when a VM is created
+	VMCreated Type = "VMCreated"
+	// VMStarted is emitted when a VM is started, also when it's restarted by its restart policy
+	VMStarted Type = "VMStarted"
+	// VMStopped is emitted when a VM is stopped by ignite, e.g. by "ignite stop"
+	VMStopped Type = "VMStopped"
+	// VMCrashed is emitted when a VM stops without ignite stopping it
+	VMCrashed Type = "VMCrashed"
+	// VMRemoved is emitted when a VM is removed
+	VMRemoved Type = "VMRemoved"
+	// VMUnhealthy is emitted when the liveness probe of a VM keeps failing
+	VMUnhealthy Type = "VMUnhealthy"
+	// ImageImported is emitted when an image is imported
+	ImageImported Type = "ImageImported"
+	// ImageRemoved is emitted when an image is removed
+	ImageRemoved Type = "ImageRemoved"
+	// KernelImported is emitted when a kernel is imported
+	KernelImported Type = "KernelImported"
+	// KernelRemoved is emitted when a kernel is removed
+	KernelRemoved Type = "KernelRemoved"
+	// VMDriftCorrected is emitted by "ignited gitops" when it corrects a VM that drifted from its manifest
+	VMDriftCorrected Type = "VMDriftCorrected"
+)
+
+// Types are all the event types
+var Types = []Type{VMCreated, VMStarted, VMStopped, VMCrashed, VMRemoved, VMUnhealthy, ImageImported, ImageRemoved, KernelImported, KernelRemoved, VMDriftCorrected}
+
+// Event describes a change of a VM, image or kernel
+type Event struct {
+	Time time.Time   `json:"time"`
+	Type Type        `json:"type"`
+	Kind string      `json:"kind"`
+	UID  runtime.UID `json:"uid"`
+	Name string      `json:"name"`
+	// ExitCode is the exit code of Firecracker for VMStopped and VMCrashed events
+	ExitCode *int `json:"exitCode,omitempty"`
+	// Reason is why the VM stopped for VMStopped and VMCrashed events, e.g. OOMKilled, and
+	// the reason of the Healthy condition for VMUnhealthy events
+	Reason string `json:"reason,omitempty"`
+	// Message describes how the VM exited, why it's unhealthy, or the correction of VMDriftCorrected events
+	Message string `json:"message,omitempty"`
+}
+
+// Sink receives the events, e.g. the event log or a webhook
+type Sink interface {
+	Send(e *Event)
+}
+
+// Watcher compares the VMs, images and kernels over time, and sends the events for
+// their changes to its sinks
+type Watcher struct {
+	sinks []Sink
+	// vms are the VMs of the previous check, nil before the first check
+	vms     map[runtime.UID]*api.VM
+	images  map[runtime.UID]*api.Image
+	kernels map[runtime.UID]*api.Kernel
+}
+
+// NewWatcher creates a Watcher that sends the events to the event log, and to the
+// webhooks of the ignite configuration. The objects that exist when it's created
+// don't get events.
+func NewWatcher(logPath string) (*Watcher, error) {
+	sinks, err := NewSinks(logPath)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{sinks: sinks}
+	vms, images, kernels, err := list()
+	if err != nil {
+		return nil, err
+	}
+
+	w.check(vms, images, kernels, time.Now())
+	return w, nil
+}
+
+// NewSinks creates the event log at the given path, and the webhooks of the ignite configuration
+func NewSinks(logPath string) ([]Sink, error) {
+	sinks := []Sink{NewLog(logPath)}
+	if providers.ComponentConfig != nil {
+		for _, webhook := range providers.ComponentConfig.Spec.Events.Webhooks {
+			sink, err := NewWebhook(webhook)
+			if err != nil {
+				return nil, err
+			}
+
+			sinks = append(sinks, sink)
+		}
+	}
+
+	return sinks, nil
+}
+
+// Run checks for events every EVENT_CHECK_INTERVAL. It never returns.
+func (w *Watcher) Run() {
+	for {
+		time.Sleep(constants.EVENT_CHECK_INTERVAL)
+
+		// Skip the check if listing fails, rather than reporting every object as removed
+		if vms, images, kernels, err := list(); err != nil {
+			log.Errorf("Failed to list objects for events: %v", err)
+		} else {
+			for _, e := range w.check(vms, images, kernels, time.Now()) {
+				for _, sink := range w.sinks {
+					sink.Send(e)
+				}
+			}
+		}
+	}
+}
+
+func list() (vms []*api.VM, images []*api.Image, kernels []*api.Kernel, err error) {
+	if vms, err = providers.Client.VMs().FindAll(filter.NewAllFilter()); err != nil {
+		return
+	}
+
+	if images, err = providers.Client.Images().FindAll(filter.NewAllFilter()); err != nil {
+		return
+	}
+
+	kernels, err = providers.Client.Kernels().FindAll(filter.NewAllFilter())
+	return
+}
+
+// check returns the events for the changes since the previous check. The first
+// check only records the objects, they existed before the watcher started.
+func (w *Watcher) check(vms []*api.VM, images []*api.Image, kernels []*api.Kernel, now time.Time) []*Event {
+	first := w.vms == nil
+
+	var events []*Event
+	newVMs := make(map[runtime.UID]*api.VM, len(vms))
+	for _, vm := range vms {
+		newVMs[vm.GetUID()] = vm
+		if first {
+			continue
+		}
+
+		old, ok := w.vms[vm.GetUID()]
+		if !ok {
+			events = append(events, newEvent(now, VMCreated, api.KindVM, vm))
+			// Compare the new VM to an empty one, it may have been started right away
+			old = &api.VM{}
+		}
+
+		events = append(events, vmEvents(now, old, vm)...)
+	}
+
+	for uid, vm := range w.vms {
+		if _, ok := newVMs[uid]; !ok {
+			events = append(events, newEvent(now, VMRemoved, api.KindVM, vm))
+		}
+	}
+	w.vms = newVMs
+
+	newImages := make(map[runtime.UID]*api.Image, len(images))
+	for _, image := range images {
+		newImages[image.GetUID()] = image
+		if _, ok := w.images[image.GetUID()]; !first && !ok {
+			events = append(events, newEvent(now, ImageImported, api.KindImage, image))
+		}
+	}
+
+	for uid, image := range w.images {
+		if _, ok := newImages[uid]; !ok {
+			events = append(events, newEvent(now, ImageRemoved, api.KindImage, image))
+		}
+	}
+	w.images = newImages
+
+	newKernels := make(map[runtime.UID]*api.Kernel, len(kernels))
+	for _, kernel := range kernels {
+		newKernels[kernel.GetUID()] = kernel
+		if _, ok := w.kernels[kernel.GetUID()]; !first && !ok {
+			events = append(events, newEvent(now, KernelImported, api.KindKernel, kernel))
+		}
+	}
+
+	for uid, kernel := range w.kernels {
+		if _, ok := newKernels[uid]; !ok {
+			events = append(events, newEvent(now, KernelRemoved, api.KindKernel, kernel))
+		}
+	}
+	w.kernels = newKernels
+
+	return events
+}
+
+// vmEvents returns the events for the changes of the state of the VM. A VM that was
+// restarted between two checks gets both an exit and a VMStarted event.
+func vmEvents(now time.Time, old, vm *api.VM) []*Event {
+	var events []*Event
+	exit := vm.Status.LastExit
+	if exit != nil && (old.Status.LastExit == nil || !old.Status.LastExit.Time.Equal(&exit.Time.Time)) {
+		e := newEvent(now, VMCrashed, api.KindVM, vm)
+		if exit.Requested {
+			e.Type = VMStopped
+		}
+
+		exitCode := exit.ExitCode
+		e.ExitCode = &exitCode
+		e.Reason = string(exit.ExitReason())
+		e.Message = fmt.Sprintf("Firecracker exited with code %d", exitCode)
+		if exit.Reason == api.ExitReasonOOMKilled {
+			e.Message = "Firecracker ran out of memory"
+		}
+
+		events = append(events, e)
+	} else if old.Running() && !vm.Running() {
+		// VMs stopped by older versions of ignite have no exit status
+		e := newEvent(now, VMStopped, api.KindVM, vm)
+		e.Reason = string(api.ExitReasonStopped)
+		events = append(events, e)
+	}
+
+	if vm.Running() && (!old.Running() || !startTime(old).Equal(startTime(vm))) {
+		events = append(events, newEvent(now, VMStarted, api.KindVM, vm))
+	}
+
+	if healthy := vm.Condition(api.VMHealthy); healthy != nil && healthy.Status == api.ConditionFalse {
+		if was := old.Condition(api.VMHealthy); was == nil || was.Status != api.ConditionFalse {
+			e := newEvent(now, VMUnhealthy, api.KindVM, vm)
+			e.Reason = healthy.Reason
+			e.Message = healthy.Message
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+func startTime(vm *api.VM) time.Time {
+	if vm.Status.StartTime == nil {
+		return time.Time{}
+	}
+
+	return vm.Status.StartTime.Time.Time
+}
+
+// New returns an event of the given type for the object, which happened now
+func New(eventType Type, kind runtime.Kind, obj runtime.Object) *Event {
+	return newEvent(time.Now(), eventType, kind, obj)
+}
+
+func newEvent(now time.Time, eventType Type, kind runtime.Kind, obj runtime.Object) *Event {
+	return &Event{
+		Time: now,
+		Type: eventType,
+		Kind: kind.Title(),
+		UID:  obj.GetUID(),
+		Name: obj.GetName(),
+	}
+}
@@ -0,0 +1,240 @@
+package events
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+func newVM(uid string, running bool, started time.Time, exit *api.VMExitStatus) *api.VM {
+	vm := &api.VM{}
+	vm.SetName("vm-" + uid)
+	vm.SetUID(runtime.UID(uid))
+	vm.Status.Running = running
+	if !started.IsZero() {
+		vm.Status.StartTime = &runtime.Time{Time: metav1.Time{Time: started}}
+	}
+	vm.Status.LastExit = exit
+	return vm
+}
+
+func exitAt(t time.Time, code int, requested bool) *api.VMExitStatus {
+	return &api.VMExitStatus{Time: runtime.Time{Time: metav1.Time{Time: t}}, ExitCode: code, Requested: requested}
+}
+
+func newImage(uid string) *api.Image {
+	image := &api.Image{}
+	image.SetName("image-" + uid)
+	image.SetUID(runtime.UID(uid))
+	return image
+}
+
+// eventTypes returns the events as "<uid> <type>" for comparing
+func eventTypes(events []*Event) []string {
+	types := make([]string, 0, len(events))
+	for _, e := range events {
+		types = append(types, string(e.UID)+" "+string(e.Type))
+	}
+
+	return types
+}
+
+func TestCheck(t *testing.T) {
+	t0 := time.Now().Add(-time.Hour)
+	t1 := t0.Add(time.Minute)
+	t2 := t1.Add(time.Minute)
+	w := &Watcher{}
+
+	// The objects of the first check exist before the watcher starts
+	events := w.check([]*api.VM{
+		newVM("running", true, t0, nil),
+		newVM("stopped", false, time.Time{}, nil),
+		newVM("crashing", true, t0, nil),
+		newVM("restarting", true, t0, nil),
+		newVM("removed", false, time.Time{}, nil),
+	}, []*api.Image{newImage("base")}, nil, t0)
+	assert.Equal(t, len(events), 0)
+
+	events = w.check([]*api.VM{
+		newVM("running", true, t0, nil),
+		newVM("stopped", true, t1, nil),
+		newVM("crashing", false, t0, exitAt(t1, 137, false)),
+		newVM("restarting", true, t2, exitAt(t1, 0, true)),
+		newVM("created", false, time.Time{}, nil),
+		newVM("created-running", true, t1, nil),
+	}, []*api.Image{newImage("base"), newImage("new")}, []*api.Kernel{{}}, t2)
+
+	got := eventTypes(events)
+	assert.DeepEqual(t, got, []string{
+		"stopped VMStarted",
+		"crashing VMCrashed",
+		"restarting VMStopped",
+		"restarting VMStarted",
+		"created VMCreated",
+		"created-running VMCreated",
+		"created-running VMStarted",
+		"removed VMRemoved",
+		"new ImageImported",
+		" KernelImported",
+	})
+	assert.Equal(t, *events[1].ExitCode, 137)
+	assert.Equal(t, events[1].Name, "vm-crashing")
+	assert.Equal(t, events[1].Reason, string(api.ExitReasonKilled))
+	assert.Equal(t, events[2].Reason, string(api.ExitReasonStopped))
+	assert.Equal(t, events[8].Kind, "Image")
+
+	// The VMs killed for running out of memory are told apart
+	oomKilled := exitAt(t2, 137, false)
+	oomKilled.Reason = api.ExitReasonOOMKilled
+	events = vmEvents(t2, newVM("crashing", false, t0, exitAt(t1, 137, false)), newVM("crashing", false, t0, oomKilled))
+	assert.DeepEqual(t, eventTypes(events), []string{"crashing VMCrashed"})
+	assert.Equal(t, events[0].Reason, "OOMKilled")
+	assert.Equal(t, events[0].Message, "Firecracker ran out of memory")
+
+	// The VMs whose liveness probe keeps failing are reported once
+	unhealthy := newVM("probed", true, t0, nil)
+	unhealthy.SetCondition(api.VMHealthy, api.ConditionFalse, "ProbeFailed", "connection refused")
+	events = vmEvents(t2, newVM("probed", true, t0, nil), unhealthy)
+	assert.DeepEqual(t, eventTypes(events), []string{"probed VMUnhealthy"})
+	assert.Equal(t, events[0].Message, "connection refused")
+	assert.Equal(t, len(vmEvents(t2, unhealthy, unhealthy)), 0)
+
+	// Unchanged VMs get no events
+	events = w.check([]*api.VM{
+		newVM("crashing", false, t0, exitAt(t1, 137, false)),
+	}, nil, []*api.Kernel{{}}, t2)
+	got = eventTypes(events)
+	sort.Strings(got)
+	assert.DeepEqual(t, got, []string{
+		"base ImageRemoved",
+		"created VMRemoved",
+		"created-running VMRemoved",
+		"new ImageRemoved",
+		"restarting VMRemoved",
+		"running VMRemoved",
+		"stopped VMRemoved",
+	})
+}
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-events-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	logPath := path.Join(dir, "events.log")
+	l := NewLog(logPath)
+	l.Send(&Event{Type: VMCreated, Kind: "VM", UID: "a"})
+	l.Send(&Event{Type: VMRemoved, Kind: "VM", UID: "a"})
+
+	b, err := ioutil.ReadFile(logPath)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Equal(t, len(lines), 2)
+
+	e := &Event{}
+	assert.NilError(t, json.Unmarshal([]byte(lines[1]), e))
+	assert.Equal(t, e.Type, VMRemoved)
+}
+
+func TestReadLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-events-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	// A missing log has no events
+	logPath := path.Join(dir, "events.log")
+	events, offset, err := ReadLog(logPath, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 0)
+
+	l := NewLog(logPath)
+	l.Send(&Event{Type: VMCreated, Kind: "VM", UID: "a"})
+	l.Send(&Event{Type: VMStarted, Kind: "VM", UID: "a"})
+
+	// The incomplete last line is left for the next read
+	f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NilError(t, err)
+	_, err = f.WriteString(`{"type":"VMCrashed",`)
+	assert.NilError(t, err)
+
+	events, offset, err = ReadLog(logPath, offset)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, eventTypes(events), []string{"a VMCreated", "a VMStarted"})
+
+	_, err = f.WriteString(`"kind":"VM","uid":"a","reason":"OOMKilled"}` + "\n")
+	assert.NilError(t, err)
+	assert.NilError(t, f.Close())
+
+	events, offset, err = ReadLog(logPath, offset)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, eventTypes(events), []string{"a VMCrashed"})
+	assert.Equal(t, events[0].Reason, "OOMKilled")
+
+	// A rotated log is read from the start
+	assert.NilError(t, os.Remove(logPath))
+	l.Send(&Event{Type: VMRemoved, Kind: "VM", UID: "a"})
+	events, _, err = ReadLog(logPath, offset)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, eventTypes(events), []string{"a VMRemoved"})
+}
+
+func TestWebhook(t *testing.T) {
+	received := make(chan *Event, 2)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := &Event{}
+		if err := json.NewDecoder(r.Body).Decode(e); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		received <- e
+	}))
+	defer ts.Close()
+
+	webhook, err := NewWebhook(api.WebhookConfiguration{URL: ts.URL, Events: []string{"VMCrashed"}})
+	assert.NilError(t, err)
+
+	exitCode := 1
+	webhook.Send(&Event{Type: VMStarted, UID: "a"})
+	webhook.Send(&Event{Type: VMCrashed, UID: "a", ExitCode: &exitCode})
+
+	select {
+	case e := <-received:
+		assert.Equal(t, e.Type, VMCrashed)
+		assert.Equal(t, *e.ExitCode, 1)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the webhook didn't receive the event")
+	}
+}
+
+func TestNewWebhookErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  api.WebhookConfiguration
+		err  string
+	}{
+		{"no scheme", api.WebhookConfiguration{URL: "example.com/hook"}, "scheme must be http or https"},
+		{"unsupported scheme", api.WebhookConfiguration{URL: "ftp://example.com/hook"}, "scheme must be http or https"},
+		{"unknown type", api.WebhookConfiguration{URL: "https://example.com/hook", Events: []string{"VMPaused"}}, "unknown event type"},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			_, err := NewWebhook(rt.cfg)
+			assert.ErrorContains(t, err, rt.err)
+		})
+	}
+}
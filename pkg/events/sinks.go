@@ -0,0 +1,214 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+// webhookQueueSize is how many events are queued for a webhook that's slow to respond,
+// further events are dropped
+const webhookQueueSize = 100
+
+// Log appends the events to a file as newline-delimited JSON
+type Log struct {
+	path string
+}
+
+var _ Sink = &Log{}
+
+// NewLog creates a Log that appends to the file at the given path
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Send appends the event to the log. The file is opened for every event, so it can be
+// rotated by e.g. logrotate.
+func (l *Log) Send(e *Event) {
+	if err := l.write(e); err != nil {
+		log.Errorf("Failed to write %s event to %s: %v", e.Type, l.path, err)
+	}
+}
+
+func (l *Log) write(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReadLog reads the events appended to the event log at the given path since the offset,
+// and returns the offset after the last complete event to read the next events from. The
+// log is read from the start if it's shorter than the offset, as it has been rotated. A
+// missing log has no events.
+func ReadLog(path string, offset int64) ([]*Event, int64, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	} else if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, offset, err
+	}
+
+	if fi.Size() < offset {
+		offset = 0
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, offset, err
+	}
+
+	var events []*Event
+	for {
+		// An incomplete last line is still being written, it's read with the next events
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			break
+		}
+
+		line := b[:i]
+		b = b[i+1:]
+		offset += int64(i + 1)
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		e := &Event{}
+		if err := json.Unmarshal(line, e); err != nil {
+			return nil, offset, fmt.Errorf("invalid event in %s: %v", path, err)
+		}
+
+		events = append(events, e)
+	}
+
+	return events, offset, nil
+}
+
+// Webhook POSTs the events to an HTTP endpoint. The events are sent in order from a
+// queue, so a slow endpoint doesn't hold up the other sinks.
+type Webhook struct {
+	url    string
+	types  map[Type]bool
+	client *http.Client
+	queue  chan *Event
+}
+
+var _ Sink = &Webhook{}
+
+// NewWebhook creates a Webhook for the given configuration and starts sending to it
+func NewWebhook(cfg api.WebhookConfiguration) (*Webhook, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL %q: %v", cfg.URL, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook URL %q: the scheme must be http or https", cfg.URL)
+	}
+
+	types := make(map[Type]bool, len(cfg.Events))
+	for _, t := range cfg.Events {
+		if !isType(Type(t)) {
+			return nil, fmt.Errorf("unknown event type %q for webhook %q, expected one of %v", t, cfg.URL, Types)
+		}
+
+		types[Type(t)] = true
+	}
+
+	timeout := cfg.Timeout.Duration
+	if timeout == 0 {
+		timeout = constants.EVENT_WEBHOOK_TIMEOUT
+	}
+
+	w := &Webhook{
+		url:    cfg.URL,
+		types:  types,
+		client: &http.Client{Timeout: timeout},
+		queue:  make(chan *Event, webhookQueueSize),
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Send queues the event for the webhook, if the webhook is subscribed to its type
+func (w *Webhook) Send(e *Event) {
+	if len(w.types) > 0 && !w.types[e.Type] {
+		return
+	}
+
+	select {
+	case w.queue <- e:
+	default:
+		log.Warnf("Dropping %s event for webhook %s, too many events are queued", e.Type, w.url)
+	}
+}
+
+func (w *Webhook) run() {
+	for e := range w.queue {
+		if err := w.post(e); err != nil {
+			log.Warnf("Failed to send %s event to webhook %s: %v", e.Type, w.url, err)
+		}
+	}
+}
+
+func (w *Webhook) post(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
+
+func isType(t Type) bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+package filter
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+)
+
+// The LabelFilter matches Objects whose labels match a label selector, like
+// "app=ci,tier!=db" or "env in (dev, test)". Labels are a part of the ObjectMeta,
+// so this is a MetaFilter, which doesn't need to load the full Objects.
+type LabelFilter struct {
+	selector labels.Selector
+	kind     runtime.Kind
+}
+
+var _ filterer.MetaFilter = &LabelFilter{}
+
+// NewLabelFilter parses the given label selector into a LabelFilter
+func NewLabelFilter(selector string) (*LabelFilter, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %v", selector, err)
+	}
+
+	return &LabelFilter{selector: s}, nil
+}
+
+// Matches returns whether the labels of the given Object match the selector
+func (f *LabelFilter) Matches(object runtime.Object) bool {
+	return f.selector.Matches(labels.Set(object.GetObjectMeta().Labels))
+}
+
+func (f *LabelFilter) FilterMeta(object runtime.Object) (filterer.Match, error) {
+	if !f.Matches(object) {
+		return nil, nil
+	}
+
+	return filterer.NewMatch(object, false), nil
+}
+
+func (f *LabelFilter) AmbiguousError(_ []filterer.Match) *filterer.AmbiguousError {
+	return filterer.NewAmbiguousError("ambiguous query: label selector %q matched multiple %ss", f.selector, f.kind.Lower())
+}
+
+func (f *LabelFilter) NonexistentError() *filterer.NonexistentError {
+	return filterer.NewNonexistentError("no %s matches the label selector %q", f.kind.Lower(), f.selector)
+}
+
+func (f *LabelFilter) SetKind(k runtime.Kind) {
+	f.kind = k
+}
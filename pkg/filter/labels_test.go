@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+func TestLabelFilter(t *testing.T) {
+	vm := &api.VM{
+		ObjectMeta: runtime.ObjectMeta{
+			Labels: map[string]string{
+				"app":  "ci",
+				"tier": "build",
+			},
+		},
+	}
+
+	cases := []struct {
+		selector string
+		expected bool
+	}{
+		{"", true},
+		{"app=ci", true},
+		{"app==ci,tier=build", true},
+		{"app=ci,tier!=build", false},
+		{"app in (ci, cd)", true},
+		{"tier notin (build)", false},
+		{"app", true},
+		{"!app", false},
+		{"env=dev", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.selector, func(t *testing.T) {
+			f, err := NewLabelFilter(c.selector)
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, f.Matches(vm))
+
+			match, err := f.FilterMeta(vm)
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, match != nil)
+		})
+	}
+
+	_, err := NewLabelFilter("app=(ci")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,76 @@
+// Package flock serializes operations between the goroutines and processes of ignite and
+// ignited with flock(2) locks on files in the lock directory. The locks are released when
+// the process exits, so a crashed process never leaves a lock behind. A lock isn't
+// reentrant, the same goroutine taking it twice waits forever.
+package flock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// Dir is the directory of the lock files, it's on a tmpfs cleared on boot
+var Dir = constants.LOCK_DIR
+
+// Lock takes the exclusive lock of the given name, waiting for the goroutine or process
+// holding it, and returns the function releasing it
+func Lock(name string) (func(), error) {
+	return lock(name, unix.LOCK_EX)
+}
+
+// RLock takes the lock of the given name shared with the other readers, waiting for the
+// goroutine or process holding it exclusively, and returns the function releasing it
+func RLock(name string) (func(), error) {
+	return lock(name, unix.LOCK_SH)
+}
+
+// TryLock takes the exclusive lock of the given name if it's free. It returns the function
+// releasing it, or nil if the lock is held by another goroutine or process.
+func TryLock(name string) (func(), error) {
+	unlock, err := lock(name, unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return nil, nil
+	}
+
+	return unlock, err
+}
+
+// ObjectName returns the name of the lock of the object, its kind and UID
+func ObjectName(obj runtime.Object) string {
+	return obj.GetKind().Lower() + "-" + obj.GetUID().String()
+}
+
+func lock(name string, how int) (func(), error) {
+	if err := os.MkdirAll(Dir, 0700); err != nil {
+		return nil, err
+	}
+
+	// The names of imports hold the OCI references
+	file := path.Join(Dir, strings.ReplaceAll(name, "/", "_")+".lock")
+	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		err = unix.Flock(int(f.Fd()), how)
+		if err != unix.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %q: %w", file, err)
+	}
+
+	// Closing the file releases the lock
+	return func() { f.Close() }, nil
+}
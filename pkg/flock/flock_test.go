@@ -0,0 +1,99 @@
+package flock
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func setDir(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "ignite-flock-test")
+	assert.NilError(t, err)
+
+	Dir = dir
+	return func() { os.RemoveAll(dir) }
+}
+
+// waits returns whether f waits until the lock is released
+func waits(t *testing.T, unlock func(), f func() (func(), error)) bool {
+	locked := make(chan func())
+	go func() {
+		unlock, err := f()
+		assert.NilError(t, err)
+		locked <- unlock
+	}()
+
+	select {
+	case u := <-locked:
+		u()
+		unlock()
+		return false
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	(<-locked)()
+	return true
+}
+
+func TestLock(t *testing.T) {
+	defer setDir(t)()
+
+	unlock, err := Lock("vm-1699b6ba255cde7f")
+	assert.NilError(t, err)
+	assert.Assert(t, waits(t, unlock, func() (func(), error) { return Lock("vm-1699b6ba255cde7f") }))
+
+	// Other names are locked independently
+	unlock, err = Lock("vm-1699b6ba255cde7f")
+	assert.NilError(t, err)
+	assert.Assert(t, !waits(t, unlock, func() (func(), error) { return Lock("import-image-weaveworks/ignite-ubuntu:latest") }))
+
+	// Readers share the lock, but wait for writers
+	unlock, err = RLock("storage-vm")
+	assert.NilError(t, err)
+	assert.Assert(t, !waits(t, unlock, func() (func(), error) { return RLock("storage-vm") }))
+
+	unlock, err = RLock("storage-vm")
+	assert.NilError(t, err)
+	assert.Assert(t, waits(t, unlock, func() (func(), error) { return Lock("storage-vm") }))
+}
+
+func TestTryLock(t *testing.T) {
+	defer setDir(t)()
+
+	unlock, err := TryLock("vm-1699b6ba255cde7f")
+	assert.NilError(t, err)
+	assert.Assert(t, unlock != nil)
+
+	// The lock isn't taken while it's held
+	held, err := TryLock("vm-1699b6ba255cde7f")
+	assert.NilError(t, err)
+	assert.Assert(t, held == nil)
+
+	unlock()
+	unlock, err = TryLock("vm-1699b6ba255cde7f")
+	assert.NilError(t, err)
+	assert.Assert(t, unlock != nil)
+	unlock()
+}
+
+func TestLockProcesses(t *testing.T) {
+	if _, err := exec.LookPath("flock"); err != nil {
+		t.Skip("the flock command isn't installed")
+	}
+	defer setDir(t)()
+
+	unlock, err := Lock("pool")
+	assert.NilError(t, err)
+
+	// Another process can't take the lock while it's held
+	err = exec.Command("flock", "--nonblock", Dir+"/pool.lock", "true").Run()
+	assert.ErrorContains(t, err, "exit status 1")
+
+	unlock()
+	assert.NilError(t, exec.Command("flock", "--nonblock", Dir+"/pool.lock", "true").Run())
+}
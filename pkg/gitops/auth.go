@@ -0,0 +1,101 @@
+package gitops
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// IdentityPassphraseEnv is the environment variable holding the passphrase of an
+// encrypted SSH identity, so it doesn't show up in the process list
+const IdentityPassphraseEnv = "IGNITED_GITOPS_SSH_PASSPHRASE"
+
+// scpLikeURLRegexp matches the scp-like SSH URLs of Git, e.g. git@github.com:user/repo.git
+var scpLikeURLRegexp = regexp.MustCompile(`^(?:[^@/\s]+@)?[^:/\s]+:`)
+
+// IsSSHURL returns true if Git accesses the repository at the URL over SSH
+func IsSSHURL(url string) bool {
+	if strings.Contains(url, "://") {
+		return strings.HasPrefix(url, "ssh://")
+	}
+
+	return scpLikeURLRegexp.MatchString(url)
+}
+
+// ReadIdentity reads the SSH private key at the given path. An encrypted key is decrypted
+// with the passphrase, as the key is used unattended.
+func ReadIdentity(path string, passphrase []byte) ([]byte, error) {
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptIdentity(key, passphrase)
+}
+
+// decryptIdentity returns the private key unencrypted in PEM format
+func decryptIdentity(key, passphrase []byte) ([]byte, error) {
+	_, err := ssh.ParseRawPrivateKey(key)
+	if err == nil {
+		return key, nil // Not encrypted
+	}
+
+	if _, ok := err.(*ssh.PassphraseMissingError); !ok {
+		return nil, fmt.Errorf("invalid SSH identity: %v", err)
+	}
+
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("the SSH identity is encrypted, set its passphrase in the %s environment variable", IdentityPassphraseEnv)
+	}
+
+	rawKey, err := ssh.ParseRawPrivateKeyWithPassphrase(key, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the SSH identity: %v", err)
+	}
+
+	// OpenSSH keys are parsed to a pointer, which x509 doesn't handle
+	if k, ok := rawKey.(*ed25519.PrivateKey); ok {
+		rawKey = *k
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(rawKey)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported SSH identity: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// ReadKnownHosts reads the known_hosts file at the given path, the host keys of SSH
+// repositories are verified against it
+func ReadKnownHosts(path string) ([]byte, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := 0
+	for rest := content; ; {
+		var err error
+		if _, _, _, _, rest, err = ssh.ParseKnownHosts(rest); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid known_hosts file %q: %v", path, err)
+		}
+
+		hosts++
+	}
+
+	if hosts == 0 {
+		return nil, fmt.Errorf("the known_hosts file %q has no host keys, add the host key of the repository with e.g. ssh-keyscan", path)
+	}
+
+	return content, nil
+}
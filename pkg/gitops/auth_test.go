@@ -0,0 +1,90 @@
+package gitops
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"gotest.tools/assert"
+)
+
+func TestIsSSHURL(t *testing.T) {
+	cases := map[string]bool{
+		"git@github.com:user/repo.git":        true,
+		"github.com:user/repo.git":            true,
+		"ssh://git@github.com/user/repo.git":  true,
+		"https://github.com/user/repo.git":    false,
+		"file:///srv/git/repo.git":            false,
+		"/srv/git/repo.git":                   false,
+		"ssh://git@example.com:2222/repo.git": true,
+	}
+
+	for url, expected := range cases {
+		assert.Equal(t, IsSSHURL(url), expected, url)
+	}
+}
+
+func TestDecryptIdentity(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NilError(t, err)
+	plain := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	// Unencrypted keys are used as they are
+	key, err := decryptIdentity(plain, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, key, plain)
+
+	//nolint:staticcheck // Legacy PEM encryption is what ssh-keygen -m PEM writes
+	block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey), []byte("secret"), x509.PEMCipherAES256)
+	assert.NilError(t, err)
+	encrypted := pem.EncodeToMemory(block)
+
+	_, err = decryptIdentity(encrypted, nil)
+	assert.ErrorContains(t, err, IdentityPassphraseEnv)
+
+	_, err = decryptIdentity(encrypted, []byte("wrong"))
+	assert.ErrorContains(t, err, "failed to decrypt")
+
+	key, err = decryptIdentity(encrypted, []byte("secret"))
+	assert.NilError(t, err)
+	signer, err := ssh.ParsePrivateKey(key)
+	assert.NilError(t, err)
+	assert.Equal(t, signer.PublicKey().Type(), ssh.KeyAlgoRSA)
+
+	_, err = decryptIdentity([]byte("not a key"), nil)
+	assert.ErrorContains(t, err, "invalid SSH identity")
+}
+
+func TestReadKnownHosts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-gitops-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	assert.NilError(t, err)
+
+	file := path.Join(dir, "known_hosts")
+	content := []byte("# GitHub\ngithub.com " + string(ssh.MarshalAuthorizedKey(sshPub)))
+	assert.NilError(t, ioutil.WriteFile(file, content, 0644))
+
+	read, err := ReadKnownHosts(file)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, read, content)
+
+	assert.NilError(t, ioutil.WriteFile(file, []byte("# nothing\n"), 0644))
+	_, err = ReadKnownHosts(file)
+	assert.ErrorContains(t, err, "has no host keys")
+
+	assert.NilError(t, ioutil.WriteFile(file, []byte("github.com ssh-ed25519 !!!\n"), 0644))
+	_, err = ReadKnownHosts(file)
+	assert.ErrorContains(t, err, "invalid known_hosts file")
+}
@@ -0,0 +1,224 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fluxcd/toolkit/pkg/ssh/knownhosts"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/libgitops/pkg/gitdir"
+)
+
+// The author of the commits of the status written back to the repository
+const (
+	commitUserName  = "Weave libgitops"
+	commitUserEmail = "support@weave.works"
+)
+
+// gitDirectory keeps a clone of a branch up to date, and pushes the changes made to it
+// if it has the credentials to. It works like the Git directory of libgitops, which only
+// pulls at its interval. The webhook pulls right away through the same pull, which is
+// serialized with the ones at the interval and the pushes.
+type gitDirectory struct {
+	url  string
+	opts gitdir.GitDirectoryOptions
+	auth transport.AuthMethod
+	dir  string
+	// readwrite is set if the changes can be pushed to the repository
+	readwrite bool
+
+	repo *git.Repository
+	wt   *git.Worktree
+
+	// mu serializes the pulls, commits and pushes
+	mu sync.Mutex
+}
+
+// newGitDirectory clones the branch of the options into a new temporary directory, and
+// keeps it up to date in the background. The SSH identity and HTTPS credentials are
+// used in memory, nothing is written to disk for them.
+func newGitDirectory(url string, opts gitdir.GitDirectoryOptions) (*gitDirectory, error) {
+	opts.Default()
+
+	d := &gitDirectory{
+		url:  url,
+		opts: opts,
+	}
+
+	ep, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without credentials, the directory is only pulled
+	switch ep.Protocol {
+	case "ssh":
+		if len(opts.IdentityFileContent) == 0 || len(opts.KnownHostsFileContent) == 0 {
+			break
+		}
+
+		pk, err := ssh.NewPublicKeys("git", opts.IdentityFileContent, "")
+		if err != nil {
+			return nil, err
+		}
+		if pk.HostKeyCallback, err = knownhosts.New(opts.KnownHostsFileContent); err != nil {
+			return nil, err
+		}
+		d.auth, d.readwrite = pk, true
+	case "https":
+		if opts.Username == nil || opts.Password == nil {
+			break
+		}
+
+		d.auth, d.readwrite = &http.BasicAuth{Username: *opts.Username, Password: *opts.Password}, true
+	case "file":
+		d.readwrite = true // Assuming enough file privileges to access the repository
+	default:
+		return nil, fmt.Errorf("unsupported endpoint scheme %q of URL %q", ep.Protocol, url)
+	}
+
+	if d.dir, err = ioutil.TempDir("", "ignite-gitops-"); err != nil {
+		return nil, err
+	}
+
+	if err := d.clone(); err != nil {
+		_ = os.RemoveAll(d.dir)
+		return nil, err
+	}
+
+	if d.readwrite {
+		log.Infof("Running in read-write mode, the status of the VMs is pushed to branch %q", opts.Branch)
+	} else {
+		log.Infof("Running in read-only mode, the status of the VMs isn't pushed to branch %q", opts.Branch)
+	}
+
+	go d.run()
+	return d, nil
+}
+
+// clone clones the branch into the directory
+func (d *gitDirectory) clone() (err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	log.Infof("Cloning branch %q of %s...", d.opts.Branch, d.url)
+	ctx, cancel := context.WithTimeout(context.Background(), d.opts.Timeout)
+	defer cancel()
+
+	if d.repo, err = git.PlainCloneContext(ctx, d.dir, false, &git.CloneOptions{
+		URL:           d.url,
+		Auth:          d.auth,
+		ReferenceName: plumbing.NewBranchReferenceName(d.opts.Branch),
+		SingleBranch:  true,
+		Tags:          git.NoTags,
+	}); err != nil {
+		return fmt.Errorf("failed to clone branch %q: %v", d.opts.Branch, err)
+	}
+
+	if d.wt, err = d.repo.Worktree(); err != nil {
+		return
+	}
+
+	ref, err := d.repo.Head()
+	if err != nil {
+		return
+	}
+
+	log.Infof("Cloned branch %q at %s", d.opts.Branch, ref.Hash())
+	return
+}
+
+// run pulls the branch at the interval, and pushes the changes made to the directory
+func (d *gitDirectory) run() {
+	for range time.Tick(d.opts.Interval) {
+		if err := d.pull(); err != nil {
+			log.Errorf("Failed to pull branch %q: %v", d.opts.Branch, err)
+		}
+
+		if d.readwrite {
+			if err := d.push(); err != nil {
+				log.Errorf("Failed to push the changes to branch %q: %v", d.opts.Branch, err)
+			}
+		}
+	}
+}
+
+// pull fast-forwards the directory to the branch of the remote
+func (d *gitDirectory) pull() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.opts.Timeout)
+	defer cancel()
+
+	old, err := d.repo.Head()
+	if err != nil {
+		return err
+	}
+
+	if err := d.wt.PullContext(ctx, &git.PullOptions{
+		Auth:          d.auth,
+		ReferenceName: plumbing.NewBranchReferenceName(d.opts.Branch),
+		SingleBranch:  true,
+	}); err != nil && err != git.NoErrAlreadyUpToDate {
+		return err
+	}
+
+	ref, err := d.repo.Head()
+	if err != nil {
+		return err
+	}
+
+	if ref.Hash() != old.Hash() {
+		log.Infof("Pulled branch %q at %s", d.opts.Branch, ref.Hash())
+	}
+
+	return nil
+}
+
+// push commits the changes made to the directory, and pushes them to the branch
+func (d *gitDirectory) push() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status, err := d.wt.Status()
+	if err != nil {
+		return err
+	}
+
+	if status.IsClean() {
+		return nil
+	}
+
+	hash, err := d.wt.Commit("Update files changed by libgitops", &git.CommitOptions{
+		All: true,
+		Author: &object.Signature{
+			Name:  commitUserName,
+			Email: commitUserEmail,
+			When:  time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.opts.Timeout)
+	defer cancel()
+
+	if err := d.repo.PushContext(ctx, &git.PushOptions{Auth: d.auth}); err != nil && err != git.NoErrAlreadyUpToDate {
+		return err
+	}
+
+	log.Infof("Pushed the status of the VMs to branch %q at %s", d.opts.Branch, hash)
+	return nil
+}
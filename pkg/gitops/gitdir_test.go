@@ -0,0 +1,81 @@
+package gitops
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/weaveworks/libgitops/pkg/gitdir"
+	"gotest.tools/assert"
+)
+
+// commitFile commits the file with the content in the worktree, and pushes it to the origin
+func commitFile(t *testing.T, repo *git.Repository, name, content string) plumbing.Hash {
+	wt, err := repo.Worktree()
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(wt.Filesystem.Root(), name), []byte(content), 0644))
+	_, err = wt.Add(name)
+	assert.NilError(t, err)
+
+	hash, err := wt.Commit("update "+name, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	assert.NilError(t, err)
+	assert.NilError(t, repo.Push(&git.PushOptions{
+		RefSpecs: []config.RefSpec{"refs/heads/master:refs/heads/master"},
+	}))
+
+	return hash
+}
+
+func TestGitDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gitdir-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	originDir, workDir := filepath.Join(dir, "origin"), filepath.Join(dir, "work")
+	origin, err := git.PlainInit(originDir, true)
+	assert.NilError(t, err)
+	work, err := git.PlainInit(workDir, false)
+	assert.NilError(t, err)
+	_, err = work.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{originDir}})
+	assert.NilError(t, err)
+	commitFile(t, work, "vm.yaml", "kind: VM\n")
+
+	// The interval is never reached, the test pulls and pushes right away
+	d, err := newGitDirectory(originDir, gitdir.GitDirectoryOptions{Branch: "master", Interval: time.Hour})
+	assert.NilError(t, err)
+	defer os.RemoveAll(d.dir)
+	assert.Assert(t, d.readwrite)
+
+	content, err := ioutil.ReadFile(filepath.Join(d.dir, "vm.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "kind: VM\n")
+
+	// A pull brings in the new commits of the branch, and is a no-op if there are none
+	pushed := commitFile(t, work, "vm.yaml", "kind: VM\nspec: {}\n")
+	assert.NilError(t, d.pull())
+	assert.NilError(t, d.pull())
+	head, err := d.repo.Head()
+	assert.NilError(t, err)
+	assert.Equal(t, head.Hash(), pushed)
+	content, err = ioutil.ReadFile(filepath.Join(d.dir, "vm.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "kind: VM\nspec: {}\n")
+
+	// The changes written to the directory are pushed to the branch
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(d.dir, "vm.yaml"), []byte("kind: VM\nstatus: {}\n"), 0644))
+	assert.NilError(t, d.push())
+	ref, err := origin.Reference(plumbing.NewBranchReferenceName("master"), true)
+	assert.NilError(t, err)
+	assert.Assert(t, ref.Hash() != pushed)
+	head, err = d.repo.Head()
+	assert.NilError(t, err)
+	assert.Equal(t, ref.Hash(), head.Hash())
+}
@@ -1,37 +1,372 @@
 package gitops
 
 import (
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
 	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/operations/reconcile"
+	storageprovider "github.com/weaveworks/ignite/pkg/providers/storage"
+	"github.com/weaveworks/ignite/pkg/util"
 	"github.com/weaveworks/libgitops/pkg/gitdir"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage"
 	"github.com/weaveworks/libgitops/pkg/storage/manifest"
+	syncstorage "github.com/weaveworks/libgitops/pkg/storage/sync"
+	"github.com/weaveworks/libgitops/pkg/storage/watch"
+	"github.com/weaveworks/libgitops/pkg/storage/watch/update"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-func RunGitOps(url string, opts gitdir.GitDirectoryOptions) error {
-	log.Infof("Starting GitOps loop for repo at %q\n", url)
-	log.Info("Whenever changes are pushed to the target branch, Ignite will apply the desired state locally\n")
+// ReconcileOptions configure the sync and reconciliation of the manifests besides applying their changes
+type ReconcileOptions struct {
+	// DriftInterval is how often the VMs are corrected if they drifted from their manifests,
+	// the environments can override it. Zero disables drift detection.
+	DriftInterval time.Duration
+	// WriteStatus writes the status of the VMs back to their manifests at the sync interval
+	WriteStatus bool
+	// Prune determines what happens to the VMs whose manifests are removed, after the
+	// PruneGracePeriod. The environments can override them.
+	Prune            api.PrunePolicy
+	PruneGracePeriod time.Duration
+	// WebhookAddress is the address to serve the webhook triggering an immediate sync on,
+	// the webhook is disabled if empty
+	WebhookAddress string
+	// WebhookSecret validates the payloads of the webhook
+	WebhookSecret []byte
+}
+
+// serveWebhook serves the webhook on the address in the background, if any
+func serveWebhook(address string, secret []byte) (*webhook, error) {
+	if len(address) == 0 {
+		return nil, nil
+	}
 
-	// Construct the GitDirectory implementation which backs the storage
-	gitDir, err := gitdir.NewGitDirectory(url, opts)
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("the webhook requires a secret to validate its requests, set it in the %s environment variable", WebhookSecretEnv)
+	}
+
+	l, err := net.Listen("tcp", address)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// TODO: Run gitDir.Cleanup() on SIGINT
 
-	// Wait for the repo to be cloned
-	if err := gitDir.WaitForClone(); err != nil {
+	hook := newWebhook(secret)
+	mux := http.NewServeMux()
+	mux.Handle(WebhookPath, hook)
+	go hook.run()
+	go func() {
+		log.Infof("Serving the webhook on %s%s", l.Addr(), WebhookPath)
+		if err := (&http.Server{Handler: mux}).Serve(l); err != nil {
+			log.Errorf("Failed to serve the webhook: %v", err)
+		}
+	}()
+
+	return hook, nil
+}
+
+// RunGitOps syncs the VMs of the repository at the given URL. Without environments, the
+// branch of the options is synced as a whole. Otherwise every environment is synced from
+// its own branch and directory, with its own sync policy. A URL prefixed with oci:// is an
+// OCI artifact pulled from a registry instead of a repository.
+func RunGitOps(url string, opts gitdir.GitDirectoryOptions, environments []api.GitOpsEnvironment, ro ReconcileOptions) error {
+	if err := validateEnvironments(environments).ToAggregate(); err != nil {
 		return err
 	}
+	if err := validation.ValidatePrunePolicy(ro.Prune, field.NewPath("prune")).ToAggregate(); err != nil {
+		return err
+	}
+	opts.Default()
+
+	// An OCI artifact is only pulled, the status of the VMs can't be pushed to it
+	if IsOCIURL(url) && ro.WriteStatus {
+		return fmt.Errorf("the status of the VMs can't be written back to the OCI artifact %q", url)
+	}
 
-	// Construct a manifest storage for the path backed by git
-	s, err := manifest.NewTwoWayManifestStorage(gitDir.Dir(), constants.DATA_DIR, scheme.Serializer)
+	hook, err := serveWebhook(ro.WebhookAddress, ro.WebhookSecret)
 	if err != nil {
 		return err
 	}
 
-	// TODO: Make the reconcile function signal-aware
-	reconcile.ReconcileManifests(s)
+	log.Infof("Starting GitOps loop for repo at %q\n", url)
+	log.Info("Whenever changes are pushed to the target branch, Ignite will apply the desired state locally\n")
+
+	if len(environments) == 0 {
+		s, ws, err := newStorage(url, opts, "", hook)
+		if err != nil {
+			return err
+		}
+
+		// TODO: Make the reconcile function signal-aware
+		reconcile.ReconcileEnvironment(s, s.GetUpdateStream(), nil, reconcileOptions(ro, api.GitOpsSyncPolicy{}, opts.Interval, ws))
+		return nil
+	}
+
+	// Clone all the environments before syncing any of them
+	type environment struct {
+		*api.GitOpsEnvironment
+		storage storage.Storage
+		updates <-chan update.Update
+		opts    reconcile.Options
+	}
+
+	envs := make([]environment, 0, len(environments))
+	for i := range environments {
+		env := &environments[i]
+		if IsOCIURL(url) && len(env.Branch) > 0 {
+			return fmt.Errorf("environment %q: an OCI artifact has no branches, sync its directories instead", env.Name)
+		}
+
+		envOpts := opts
+		if len(env.Branch) > 0 {
+			envOpts.Branch = env.Branch
+		}
+		if env.SyncPolicy.Interval.Duration > 0 {
+			envOpts.Interval = env.SyncPolicy.Interval.Duration
+		}
+		log.Infof("Syncing environment %q from directory %q of branch %q", env.Name, "/"+env.Path, envOpts.Branch)
+		if env.SyncPolicy.DryRun {
+			s, updates, err := newDryRunStorage(url, envOpts, env.Path, hook)
+			if err != nil {
+				return fmt.Errorf("environment %q: %v", env.Name, err)
+			}
+
+			// Nothing is written to the repository in a dry run
+			envs = append(envs, environment{env, s, updates, reconcileOptions(ro, env.SyncPolicy, envOpts.Interval, nil)})
+			continue
+		}
+
+		s, ws, err := newStorage(url, envOpts, env.Path, hook)
+		if err != nil {
+			return fmt.Errorf("environment %q: %v", env.Name, err)
+		}
+
+		envs = append(envs, environment{env, s, s.GetUpdateStream(), reconcileOptions(ro, env.SyncPolicy, envOpts.Interval, ws)})
+	}
+
+	var wg sync.WaitGroup
+	for _, env := range envs {
+		wg.Add(1)
+		go func(env environment) {
+			defer wg.Done()
+			reconcile.ReconcileEnvironment(env.storage, env.updates, env.GitOpsEnvironment, env.opts)
+		}(env)
+	}
+
+	wg.Wait()
 	return nil
 }
+
+// clone clones the branch of the options, or pulls the OCI artifact of the URL, and returns
+// the directory of the manifests at the given path in it. The pull and render of the
+// manifests are added to the webhook, if any.
+func clone(url string, opts gitdir.GitDirectoryOptions, dir string, hook *webhook) (string, error) {
+	opts.Default()
+
+	var root, source string
+	if IsOCIURL(url) {
+		a, err := newArtifact(url, opts.Timeout)
+		if err != nil {
+			return "", err
+		}
+
+		go a.run(opts.Interval)
+		root, source = a.dir, fmt.Sprintf("OCI artifact %q", a.ref)
+		if hook != nil {
+			hook.add(a.pull)
+		}
+	} else {
+		// Clone the branch into the directory which backs the storage
+		d, err := newGitDirectory(url, opts)
+		if err != nil {
+			return "", err
+		}
+
+		root, source = d.dir, fmt.Sprintf("branch %q", opts.Branch)
+		if hook != nil {
+			hook.add(d.pull)
+		}
+	}
+
+	manifestDir := path.Join(root, dir)
+	if !util.DirExists(manifestDir) {
+		return "", fmt.Errorf("directory %q doesn't exist on %s", dir, source)
+	}
+
+	// A kustomization is rendered, and the rendered manifests are synced instead
+	if isKustomization(manifestDir) {
+		r, err := newRenderer(manifestDir)
+		if err != nil {
+			return "", err
+		}
+
+		go r.run(opts.Interval)
+		if hook != nil {
+			hook.add(r.render)
+		}
+
+		log.Infof("Rendering the kustomization in directory %q of %s", "/"+dir, source)
+		manifestDir = r.dir
+	}
+
+	// Manifests encrypted with SOPS are decrypted, and the decrypted manifests synced instead
+	if hasEncrypted(manifestDir) {
+		d, err := newDecrypter(manifestDir)
+		if err != nil {
+			return "", err
+		}
+
+		go d.run(opts.Interval)
+		if hook != nil {
+			hook.add(d.decrypt)
+		}
+
+		log.Infof("Decrypting the manifests encrypted with SOPS in directory %q of %s", "/"+dir, source)
+		manifestDir = d.dir
+	}
+
+	return manifestDir, nil
+}
+
+// newStorage constructs a manifest storage for the path of the branch, its changes are
+// propagated to the data directory, and the changes of the VMs back to the branch. The
+// storage of the manifests alone is returned too.
+func newStorage(url string, opts gitdir.GitDirectoryOptions, dir string, hook *webhook) (*manifest.ManifestStorage, storage.Storage, error) {
+	manifestDir, err := clone(url, opts, dir, hook)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Construct a manifest storage for the path backed by git, like manifest.NewTwoWayManifestStorage
+	ws, err := watch.NewGenericWatchStorage(storage.NewGenericStorage(storage.NewGenericMappedRawStorage(manifestDir), scheme.Serializer))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := storageprovider.NewRawStorage(constants.DATA_DIR)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ss := syncstorage.NewSyncStorage(
+		pruningStorage{storage.NewGenericStorage(raw, scheme.Serializer)},
+		ws)
+
+	return &manifest.ManifestStorage{Storage: ss}, ws, nil
+}
+
+// reconcileOptions returns the options of reconciling the manifests of the storage, the sync
+// policy of the environment overrides the ones given to "ignited gitops"
+func reconcileOptions(ro ReconcileOptions, policy api.GitOpsSyncPolicy, interval time.Duration, manifests storage.Storage) reconcile.Options {
+	opts := reconcile.Options{
+		DriftInterval:    ro.DriftInterval,
+		Prune:            ro.Prune,
+		PruneGracePeriod: ro.PruneGracePeriod,
+	}
+	if policy.DriftInterval.Duration > 0 {
+		opts.DriftInterval = policy.DriftInterval.Duration
+	}
+	if len(policy.Prune) > 0 {
+		opts.Prune = policy.Prune
+	}
+	if policy.PruneGracePeriod.Duration > 0 {
+		opts.PruneGracePeriod = policy.PruneGracePeriod.Duration
+	}
+	if ro.WriteStatus && manifests != nil {
+		opts.StatusStorage = manifests
+		opts.StatusInterval = interval
+	}
+
+	return opts
+}
+
+// pruningStorage is the storage of the data directory, which leaves the removal of the VMs
+// whose manifests were removed to the reconciler. It prunes them according to the prune policy.
+type pruningStorage struct {
+	storage.Storage
+}
+
+func (s pruningStorage) Delete(gvk schema.GroupVersionKind, uid runtime.UID) error {
+	if gvk.Kind == api.KindVM.Title() {
+		return nil
+	}
+
+	return s.Storage.Delete(gvk, uid)
+}
+
+// newDryRunStorage constructs a storage for the path of the branch that only reports the
+// changes of the manifests, without writing them anywhere
+func newDryRunStorage(url string, opts gitdir.GitDirectoryOptions, dir string, hook *webhook) (storage.Storage, <-chan update.Update, error) {
+	manifestDir, err := clone(url, opts, dir, hook)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ws, err := watch.NewGenericWatchStorage(storage.NewGenericStorage(storage.NewGenericMappedRawStorage(manifestDir), scheme.Serializer))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := make(watch.AssociatedEventStream)
+	ws.SetEventStream(events)
+
+	updates := make(chan update.Update)
+	go func() {
+		for upd := range events {
+			updates <- upd.Update
+		}
+	}()
+
+	return ws, updates, nil
+}
+
+// validateEnvironments validates the gitops environments of the ignite configuration
+func validateEnvironments(environments []api.GitOpsEnvironment) (allErrs field.ErrorList) {
+	fldPath := field.NewPath("spec", "gitops", "environments")
+	names := map[string]bool{}
+	for i, env := range environments {
+		envPath := fldPath.Index(i)
+		// The name is the value of the environment label
+		if len(env.Name) == 0 {
+			allErrs = append(allErrs, field.Required(envPath.Child("name"), "environments must be named"))
+		}
+		for _, e := range utilvalidation.IsValidLabelValue(env.Name) {
+			allErrs = append(allErrs, field.Invalid(envPath.Child("name"), env.Name, e))
+		}
+		if names[env.Name] {
+			allErrs = append(allErrs, field.Duplicate(envPath.Child("name"), env.Name))
+		}
+		names[env.Name] = true
+
+		// The path is relative to the root of the repository, and can't leave it
+		if clean := path.Clean(env.Path); filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			allErrs = append(allErrs, field.Invalid(envPath.Child("path"), env.Path, "must be a directory within the repository"))
+		}
+
+		allErrs = append(allErrs, validation.ValidateLabels(env.Labels, envPath.Child("labels"))...)
+		if env.SyncPolicy.Interval.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(envPath.Child("syncPolicy", "interval"), env.SyncPolicy.Interval.Duration.String(), "must not be negative"))
+		}
+		if env.SyncPolicy.DriftInterval.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(envPath.Child("syncPolicy", "driftInterval"), env.SyncPolicy.DriftInterval.Duration.String(), "must not be negative"))
+		}
+		allErrs = append(allErrs, validation.ValidatePrunePolicy(env.SyncPolicy.Prune, envPath.Child("syncPolicy", "prune"))...)
+		if env.SyncPolicy.PruneGracePeriod.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(envPath.Child("syncPolicy", "pruneGracePeriod"), env.SyncPolicy.PruneGracePeriod.Duration.String(), "must not be negative"))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,103 @@
+package gitops
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/operations/reconcile"
+)
+
+func TestValidateEnvironments(t *testing.T) {
+	cases := []struct {
+		name string
+		envs []api.GitOpsEnvironment
+		err  string
+	}{
+		{
+			name: "valid",
+			envs: []api.GitOpsEnvironment{
+				{Name: "staging", Branch: "staging", Path: "vms/staging", Labels: map[string]string{"team": "web"}},
+				{Name: "production", Path: "./vms/production/", SyncPolicy: api.GitOpsSyncPolicy{DryRun: true}},
+			},
+		},
+		{
+			name: "unnamed",
+			envs: []api.GitOpsEnvironment{{Path: "vms"}},
+			err:  "spec.gitops.environments[0].name: Required value",
+		},
+		{
+			name: "invalid name",
+			envs: []api.GitOpsEnvironment{{Name: "staging/eu"}},
+			err:  "spec.gitops.environments[0].name: Invalid value",
+		},
+		{
+			name: "duplicate name",
+			envs: []api.GitOpsEnvironment{{Name: "staging", Branch: "a"}, {Name: "staging", Branch: "b"}},
+			err:  "spec.gitops.environments[1].name: Duplicate value",
+		},
+		{
+			name: "absolute path",
+			envs: []api.GitOpsEnvironment{{Name: "staging", Path: "/etc"}},
+			err:  "must be a directory within the repository",
+		},
+		{
+			name: "path outside the repository",
+			envs: []api.GitOpsEnvironment{{Name: "staging", Path: "vms/../../etc"}},
+			err:  "must be a directory within the repository",
+		},
+		{
+			name: "invalid label",
+			envs: []api.GitOpsEnvironment{{Name: "staging", Labels: map[string]string{"team!": "web"}}},
+			err:  "spec.gitops.environments[0].labels: Invalid value",
+		},
+		{
+			name: "negative interval",
+			envs: []api.GitOpsEnvironment{{Name: "staging", SyncPolicy: api.GitOpsSyncPolicy{Interval: metav1.Duration{Duration: -time.Second}}}},
+			err:  "must not be negative",
+		},
+		{
+			name: "negative drift interval",
+			envs: []api.GitOpsEnvironment{{Name: "staging", SyncPolicy: api.GitOpsSyncPolicy{DriftInterval: metav1.Duration{Duration: -time.Second}}}},
+			err:  "spec.gitops.environments[0].syncPolicy.driftInterval: Invalid value",
+		},
+		{
+			name: "unknown prune policy",
+			envs: []api.GitOpsEnvironment{{Name: "staging", SyncPolicy: api.GitOpsSyncPolicy{Prune: "remove"}}},
+			err:  "spec.gitops.environments[0].syncPolicy.prune: Unsupported value",
+		},
+		{
+			name: "negative prune grace period",
+			envs: []api.GitOpsEnvironment{{Name: "staging", SyncPolicy: api.GitOpsSyncPolicy{PruneGracePeriod: metav1.Duration{Duration: -time.Second}}}},
+			err:  "spec.gitops.environments[0].syncPolicy.pruneGracePeriod: Invalid value",
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			err := validateEnvironments(rt.envs).ToAggregate()
+			if len(rt.err) == 0 {
+				assert.NilError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, rt.err)
+		})
+	}
+}
+
+func TestReconcileOptions(t *testing.T) {
+	ro := ReconcileOptions{DriftInterval: time.Minute, Prune: api.PrunePolicyStop, WriteStatus: true}
+	opts := reconcileOptions(ro, api.GitOpsSyncPolicy{}, time.Second, nil)
+	assert.DeepEqual(t, opts, reconcile.Options{DriftInterval: time.Minute, Prune: api.PrunePolicyStop})
+
+	// The sync policy of the environment overrides the flags
+	opts = reconcileOptions(ro, api.GitOpsSyncPolicy{
+		Prune:            api.PrunePolicyOrphan,
+		PruneGracePeriod: metav1.Duration{Duration: time.Hour},
+	}, time.Second, nil)
+	assert.DeepEqual(t, opts, reconcile.Options{DriftInterval: time.Minute, Prune: api.PrunePolicyOrphan, PruneGracePeriod: time.Hour})
+}
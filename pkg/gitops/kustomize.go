@@ -0,0 +1,193 @@
+package gitops
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/util"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// kustomizationFiles are the file names kustomize recognizes as kustomizations
+var kustomizationFiles = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// unsafeFileChars are replaced in the names of the rendered manifests
+var unsafeFileChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// isKustomization returns true if the directory has a kustomization
+func isKustomization(dir string) bool {
+	for _, file := range kustomizationFiles {
+		if util.FileExists(path.Join(dir, file)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kustomizeBuild renders the kustomization in the directory with the kustomize binary
+var kustomizeBuild = func(dir string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("kustomize", "build", dir)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("kustomize build of %q failed: %v: %s", dir, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return out, nil
+}
+
+// renderer renders a kustomization into a directory of manifests, which the storage
+// watches instead of the directory of the repository
+type renderer struct {
+	kustomization string
+	dir           string
+	// rendered are the manifests of the last render by file name
+	rendered map[string][]byte
+	// mu serializes the renders at the interval and the ones of webhook requests
+	mu sync.Mutex
+}
+
+// newRenderer renders the kustomization into a new temporary directory
+func newRenderer(kustomization string) (*renderer, error) {
+	if _, err := exec.LookPath("kustomize"); err != nil {
+		return nil, fmt.Errorf("%q is a kustomization, rendering it requires kustomize: %v", kustomization, err)
+	}
+
+	dir, err := ioutil.TempDir("", "ignite-kustomize-")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &renderer{
+		kustomization: kustomization,
+		dir:           dir,
+	}
+
+	if err := r.render(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// render renders the kustomization, and writes the manifests that changed since the last
+// render. The manifests that are no longer rendered are removed. The storage writes the
+// status of the VMs to the manifests, it's kept until the rendered manifest changes.
+func (r *renderer) render() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out, err := kustomizeBuild(r.kustomization)
+	if err != nil {
+		return err
+	}
+
+	manifests, err := splitManifests(out)
+	if err != nil {
+		return fmt.Errorf("invalid output of kustomize build of %q: %v", r.kustomization, err)
+	}
+
+	if err := writeFiles(r.dir, r.rendered, manifests); err != nil {
+		return err
+	}
+
+	r.rendered = manifests
+	return nil
+}
+
+// writeFiles writes the files that changed from the previous ones to the directory, and
+// removes the previous files that are gone. The files are keyed by their relative path.
+func writeFiles(dir string, previous, files map[string][]byte) error {
+	for name, content := range files {
+		if bytes.Equal(previous[name], content) {
+			continue
+		}
+
+		file := path.Join(dir, name)
+		if err := os.MkdirAll(path.Dir(file), 0755); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(file, content, 0644); err != nil {
+			return err
+		}
+	}
+
+	for name := range previous {
+		if _, ok := files[name]; !ok {
+			if err := os.Remove(path.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// run renders the kustomization at the interval, the repository is pulled at the same
+// interval. A failed render keeps the manifests of the last one.
+func (r *renderer) run(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := r.render(); err != nil {
+			log.Errorf("Failed to render the kustomization %q: %v", r.kustomization, err)
+		}
+	}
+}
+
+// splitManifests splits the documents of the output of kustomize into manifests, named
+// after their kind and UID, or name if they have no UID
+func splitManifests(out []byte) (map[string][]byte, error) {
+	manifests := map[string][]byte{}
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(out)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		var obj struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name string `json:"name"`
+				UID  string `json:"uid"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal(doc, &obj); err != nil {
+			return nil, err
+		}
+
+		id := obj.Metadata.UID
+		if len(id) == 0 {
+			id = obj.Metadata.Name
+		}
+
+		name := unsafeFileChars.ReplaceAllString(strings.ToLower(obj.Kind+"-"+id), "-") + ".yaml"
+		if _, ok := manifests[name]; ok {
+			return nil, fmt.Errorf("several manifests of %s %q", obj.Kind, id)
+		}
+
+		manifests[name] = doc
+	}
+
+	return manifests, nil
+}
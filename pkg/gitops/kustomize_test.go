@@ -0,0 +1,65 @@
+package gitops
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const renderedVM = `apiVersion: ignite.weave.works/v1alpha5
+kind: VM
+metadata:
+  name: my-vm
+  uid: 599615df99804ae8
+spec:
+  image:
+    oci: weaveworks/ignite-ubuntu
+`
+
+const renderedImage = `apiVersion: ignite.weave.works/v1alpha5
+kind: Image
+metadata:
+  name: weaveworks/ignite-ubuntu:latest
+spec:
+  oci: weaveworks/ignite-ubuntu:latest
+`
+
+func TestSplitManifests(t *testing.T) {
+	manifests, err := splitManifests([]byte(renderedVM + "---\n" + renderedImage))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, manifests, map[string][]byte{
+		"vm-599615df99804ae8.yaml":                   []byte(renderedVM),
+		"image-weaveworks-ignite-ubuntu-latest.yaml": []byte(renderedImage),
+	})
+
+	_, err = splitManifests([]byte(renderedVM + "---\n" + renderedVM))
+	assert.ErrorContains(t, err, "several manifests")
+}
+
+func TestRender(t *testing.T) {
+	output := renderedVM + "---\n" + renderedImage
+	defer func(build func(string) ([]byte, error)) { kustomizeBuild = build }(kustomizeBuild)
+	kustomizeBuild = func(dir string) ([]byte, error) {
+		return []byte(output), nil
+	}
+
+	r := &renderer{kustomization: "kustomize", dir: t.TempDir()}
+	assert.NilError(t, r.render())
+	files, err := ioutil.ReadDir(r.dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 2)
+
+	// Manifests that didn't change aren't written, to keep the status written to them
+	vmFile := path.Join(r.dir, "vm-599615df99804ae8.yaml")
+	assert.NilError(t, ioutil.WriteFile(vmFile, []byte(renderedVM+"status:\n  running: true\n"), 0644))
+	output = renderedVM
+	assert.NilError(t, r.render())
+	files, err = ioutil.ReadDir(r.dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 1)
+	content, err := ioutil.ReadFile(vmFile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), renderedVM+"status:\n  running: true\n")
+}
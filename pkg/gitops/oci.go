@@ -0,0 +1,214 @@
+package gitops
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/images"
+	refdocker "github.com/containerd/containerd/reference/docker"
+	"github.com/containerd/containerd/remotes"
+	"github.com/opencontainers/go-digest"
+	imagespec "github.com/opencontainers/image-spec/specs-go/v1"
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/runtime/containerd"
+)
+
+// ociURLPrefix prefixes the references of OCI artifacts synced instead of a repository
+const ociURLPrefix = "oci://"
+
+// IsOCIURL returns true if the URL references an OCI artifact, e.g. oci://registry/manifests:tag
+func IsOCIURL(url string) bool {
+	return strings.HasPrefix(url, ociURLPrefix)
+}
+
+// artifact pulls the manifests of an OCI artifact into a directory, which the storage
+// watches instead of the directory of a repository. The artifact is pulled with the
+// credentials of the registry configuration, like OCI images.
+type artifact struct {
+	ref     string
+	dir     string
+	timeout time.Duration
+	// digest is the digest of the manifest of the last pull
+	digest digest.Digest
+	// files are the files of the last pull by path
+	files map[string][]byte
+	// mu serializes the pulls at the interval and the ones of webhook requests
+	mu sync.Mutex
+}
+
+// newArtifact pulls the artifact at the URL into a new temporary directory
+func newArtifact(url string, timeout time.Duration) (*artifact, error) {
+	named, err := refdocker.ParseDockerRef(strings.TrimPrefix(url, ociURLPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI artifact reference %q: %v", url, err)
+	}
+
+	dir, err := ioutil.TempDir("", "ignite-oci-")
+	if err != nil {
+		return nil, err
+	}
+
+	a := &artifact{
+		ref:     named.String(),
+		dir:     dir,
+		timeout: timeout,
+	}
+
+	if err := a.pull(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// pull pulls the artifact if its manifest changed since the last pull, and writes the files
+// that changed. The files that are no longer in the artifact are removed.
+func (a *artifact) pull() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	named, err := refdocker.ParseDockerRef(a.ref)
+	if err != nil {
+		return err
+	}
+
+	resolver, err := containerd.NewRemoteResolver(refdocker.Domain(named), providers.RegistryConfigDir)
+	if err != nil {
+		return err
+	}
+
+	name, desc, err := resolver.Resolve(ctx, a.ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve OCI artifact %q: %v", a.ref, err)
+	}
+
+	if desc.Digest == a.digest {
+		return nil
+	}
+
+	switch desc.MediaType {
+	case imagespec.MediaTypeImageIndex, images.MediaTypeDockerSchema2ManifestList:
+		return fmt.Errorf("%q is an image index, not an OCI artifact", a.ref)
+	}
+
+	fetcher, err := resolver.Fetcher(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	content, err := fetch(ctx, fetcher, desc)
+	if err != nil {
+		return err
+	}
+
+	var manifest imagespec.Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return fmt.Errorf("invalid manifest of OCI artifact %q: %v", a.ref, err)
+	}
+
+	files := map[string][]byte{}
+	for _, layer := range manifest.Layers {
+		content, err := fetch(ctx, fetcher, layer)
+		if err != nil {
+			return err
+		}
+
+		if err := extractLayer(layer.MediaType, content, files); err != nil {
+			return fmt.Errorf("invalid layer %s of OCI artifact %q: %v", layer.Digest, a.ref, err)
+		}
+	}
+
+	if err := writeFiles(a.dir, a.files, files); err != nil {
+		return err
+	}
+
+	log.Infof("Pulled OCI artifact %q at %s", a.ref, desc.Digest)
+	a.digest = desc.Digest
+	a.files = files
+	return nil
+}
+
+// run pulls the artifact at the interval. A failed pull keeps the files of the last one.
+func (a *artifact) run(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := a.pull(); err != nil {
+			log.Errorf("Failed to pull OCI artifact %q: %v", a.ref, err)
+		}
+	}
+}
+
+// fetch fetches the content of the descriptor, and verifies its digest
+func fetch(ctx context.Context, fetcher remotes.Fetcher, desc imagespec.Descriptor) ([]byte, error) {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	if d := desc.Digest.Algorithm().FromBytes(content); d != desc.Digest {
+		return nil, fmt.Errorf("digest mismatch, expected %s but got %s", desc.Digest, d)
+	}
+
+	return content, nil
+}
+
+// extractLayer extracts the regular files of the layer, a tar archive that may be gzipped
+// like the layers of flux artifacts, into the files by their path
+func extractLayer(mediaType string, content []byte, files map[string][]byte) error {
+	var r io.Reader = bytes.NewReader(content)
+	if strings.HasSuffix(mediaType, "gzip") {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	} else if !strings.HasSuffix(mediaType, "tar") {
+		return fmt.Errorf("unsupported media type %q, the manifests need to be in a tar archive", mediaType)
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// The files can't leave the directory of the artifact
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		if name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("file %q is outside of the artifact", hdr.Name)
+		}
+
+		if files[name], err = ioutil.ReadAll(tr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
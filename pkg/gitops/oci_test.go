@@ -0,0 +1,51 @@
+package gitops
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestIsOCIURL(t *testing.T) {
+	assert.Assert(t, IsOCIURL("oci://ghcr.io/user/manifests:latest"))
+	assert.Assert(t, !IsOCIURL("https://github.com/user/repo"))
+	assert.Assert(t, !IsOCIURL("git@github.com:user/repo.git"))
+}
+
+func archive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestExtractLayer(t *testing.T) {
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, err := gw.Write(archive(t, map[string]string{"./vms/my-vm.yaml": renderedVM}))
+	assert.NilError(t, err)
+	assert.NilError(t, gw.Close())
+
+	// Flux artifacts are gzipped tar archives
+	files := map[string][]byte{}
+	assert.NilError(t, extractLayer("application/vnd.cncf.flux.content.v1.tar+gzip", gzipped.Bytes(), files))
+	assert.NilError(t, extractLayer("application/vnd.oci.image.layer.v1.tar", archive(t, map[string]string{"/image.yaml": renderedImage}), files))
+	assert.DeepEqual(t, files, map[string][]byte{
+		"vms/my-vm.yaml": []byte(renderedVM),
+		"image.yaml":     []byte(renderedImage),
+	})
+
+	err = extractLayer("application/vnd.oci.image.layer.v1.tar", archive(t, map[string]string{"../vm.yaml": renderedVM}), files)
+	assert.ErrorContains(t, err, "outside of the artifact")
+
+	err = extractLayer("application/yaml", []byte(renderedVM), files)
+	assert.ErrorContains(t, err, "unsupported media type")
+}
@@ -0,0 +1,154 @@
+package gitops
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/sops"
+)
+
+// manifestExtensions are the extensions of the manifest files that are decrypted
+var manifestExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// decrypter mirrors a directory of manifests into a directory the storage watches instead,
+// with the manifests encrypted with SOPS decrypted
+type decrypter struct {
+	source string
+	dir    string
+	// sources are the manifests of the source directory of the last sync by relative path,
+	// files are the manifests written for them. Manifests that didn't change aren't decrypted again.
+	sources map[string][]byte
+	files   map[string][]byte
+	// mu serializes the syncs at the interval and the ones of webhook requests
+	mu sync.Mutex
+}
+
+// hasEncrypted returns true if a manifest in the directory is encrypted with SOPS
+func hasEncrypted(dir string) bool {
+	found := false
+	_ = walkManifests(dir, func(_ string, content []byte) error {
+		found = found || sops.IsEncrypted(content)
+		return nil
+	})
+
+	return found
+}
+
+// newDecrypter decrypts the manifests of the source directory into a new temporary directory,
+// only root may read it
+func newDecrypter(source string) (*decrypter, error) {
+	dir, err := ioutil.TempDir("", "ignite-sops-")
+	if err != nil {
+		return nil, err
+	}
+
+	d := &decrypter{
+		source: source,
+		dir:    dir,
+	}
+
+	if err := d.decrypt(); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// decrypt writes the manifests that changed since the last sync, decrypting the encrypted
+// ones. A manifest that fails to decrypt keeps its last decrypted version.
+func (d *decrypter) decrypt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sources := map[string][]byte{}
+	files := map[string][]byte{}
+	var errs []string
+	err := walkManifests(d.source, func(name string, content []byte) error {
+		if bytes.Equal(d.sources[name], content) {
+			sources[name], files[name] = content, d.files[name]
+			return nil
+		}
+
+		if !sops.IsEncrypted(content) {
+			sources[name], files[name] = content, content
+			return nil
+		}
+
+		decrypted, err := sops.Decrypt(content, sops.Format(name))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			if previous, ok := d.files[name]; ok {
+				files[name] = previous
+			}
+			return nil
+		}
+
+		sources[name], files[name] = content, decrypted
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writeFiles(d.dir, d.files, files); err != nil {
+		return err
+	}
+
+	d.sources, d.files = sources, files
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to decrypt %s", strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// run decrypts the manifests at the interval, the repository is pulled at the same interval
+func (d *decrypter) run(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := d.decrypt(); err != nil {
+			log.Errorf("Failed to decrypt the manifests of %q: %v", d.source, err)
+		}
+	}
+}
+
+// walkManifests calls fn with the path relative to the directory and the content of every
+// manifest file in it, the .git directory is skipped
+func walkManifests(dir string, fn func(name string, content []byte) error) error {
+	return filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !info.Mode().IsRegular() || !manifestExtensions[filepath.Ext(file)] {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+
+		return fn(name, content)
+	})
+}
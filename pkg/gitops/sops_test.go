@@ -0,0 +1,54 @@
+package gitops
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/weaveworks/ignite/pkg/sops"
+)
+
+const encryptedVM = `apiVersion: ignite.weave.works/v1alpha5
+kind: VM
+metadata:
+  name: my-vm
+  uid: 599615df99804ae8
+spec:
+  image:
+    oci: ENC[AES256_GCM,data:d2VhdmV3b3Jrcw==,type:str]
+sops:
+  mac: ENC[AES256_GCM,data:bWFj,type:str]
+`
+
+func TestDecrypt(t *testing.T) {
+	decrypted := 0
+	defer func(decrypt func([]byte, string) ([]byte, error)) { sops.Decrypt = decrypt }(sops.Decrypt)
+	sops.Decrypt = func(doc []byte, format string) ([]byte, error) {
+		decrypted++
+		return []byte(renderedVM), nil
+	}
+
+	source := t.TempDir()
+	assert.NilError(t, ioutil.WriteFile(path.Join(source, "vm.yaml"), []byte(encryptedVM), 0644))
+	assert.NilError(t, ioutil.WriteFile(path.Join(source, "image.yaml"), []byte(renderedImage), 0644))
+	assert.NilError(t, ioutil.WriteFile(path.Join(source, "README.md"), []byte("not a manifest"), 0644))
+	assert.Assert(t, hasEncrypted(source))
+
+	d := &decrypter{source: source, dir: t.TempDir()}
+	assert.NilError(t, d.decrypt())
+	files, err := ioutil.ReadDir(d.dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 2)
+	content, err := ioutil.ReadFile(path.Join(d.dir, "vm.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), renderedVM)
+
+	// Manifests that didn't change aren't decrypted again
+	assert.NilError(t, d.decrypt())
+	assert.Equal(t, decrypted, 1)
+
+	assert.NilError(t, ioutil.WriteFile(path.Join(source, "vm.yaml"), []byte(renderedVM), 0644))
+	assert.Assert(t, !hasEncrypted(source))
+}
@@ -0,0 +1,128 @@
+package gitops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// WebhookSecretEnv is the environment variable holding the secret of the webhook, so it
+// doesn't show up in the process list
+const WebhookSecretEnv = "IGNITED_GITOPS_WEBHOOK_SECRET"
+
+// WebhookPath is the path of the webhook triggering an immediate sync
+const WebhookPath = "/hook"
+
+// maxPayloadSize limits the size of the webhook payloads, GitHub caps them at 25 MB
+const maxPayloadSize = 25 << 20
+
+// webhook syncs the manifests right away when the repository notifies it of a push,
+// instead of waiting for the next sync interval
+type webhook struct {
+	secret []byte
+	// syncs pull the repositories and artifacts, and render the kustomizations
+	syncs   []func() error
+	mu      sync.Mutex
+	trigger chan struct{}
+}
+
+// newWebhook returns a webhook validating the payloads with the secret
+func newWebhook(secret []byte) *webhook {
+	return &webhook{
+		secret: secret,
+		// Pushes arriving during a sync are coalesced into the next one
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// add adds a sync to run on requests, in order after the ones added before
+func (h *webhook) add(sync func() error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.syncs = append(h.syncs, sync)
+}
+
+// run runs the syncs when triggered
+func (h *webhook) run() {
+	for range h.trigger {
+		h.mu.Lock()
+		syncs := h.syncs
+		h.mu.Unlock()
+
+		log.Info("Syncing on webhook request...")
+		for _, sync := range syncs {
+			if err := sync(); err != nil {
+				log.Errorf("Failed to sync on webhook request: %v", err)
+			}
+		}
+	}
+}
+
+func (h *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err := validatePayload(r.Header, payload, h.secret); err != nil {
+		log.Warnf("Rejected webhook request from %s: %v", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// GitHub pings the webhook when it's added
+	if r.Header.Get("X-GitHub-Event") == "ping" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	select {
+	case h.trigger <- struct{}{}:
+	default: // A sync is pending already
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// validatePayload validates the payload of a GitHub webhook by its HMAC signature, or the
+// secret token of a GitLab webhook
+func validatePayload(header http.Header, payload, secret []byte) error {
+	if signature := header.Get("X-Hub-Signature-256"); len(signature) > 0 {
+		sum, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+		if err != nil || !strings.HasPrefix(signature, "sha256=") {
+			return fmt.Errorf("malformed signature %q", signature)
+		}
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(payload)
+		if !hmac.Equal(sum, mac.Sum(nil)) {
+			return fmt.Errorf("invalid signature")
+		}
+
+		return nil
+	}
+
+	if token := header.Get("X-Gitlab-Token"); len(token) > 0 {
+		if subtle.ConstantTimeCompare([]byte(token), secret) != 1 {
+			return fmt.Errorf("invalid token")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("the request is neither signed by GitHub nor has the token of GitLab")
+}
@@ -0,0 +1,69 @@
+package gitops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const payload = `{"ref":"refs/heads/master"}`
+
+func sign(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidatePayload(t *testing.T) {
+	secret := []byte("secret")
+	tests := []struct {
+		name   string
+		header http.Header
+		err    string
+	}{
+		{"github", http.Header{"X-Hub-Signature-256": {sign("secret", payload)}}, ""},
+		{"github wrong secret", http.Header{"X-Hub-Signature-256": {sign("other", payload)}}, "invalid signature"},
+		{"github malformed", http.Header{"X-Hub-Signature-256": {"sha1=abc"}}, "malformed signature"},
+		{"gitlab", http.Header{"X-Gitlab-Token": {"secret"}}, ""},
+		{"gitlab wrong token", http.Header{"X-Gitlab-Token": {"other"}}, "invalid token"},
+		{"unsigned", http.Header{}, "neither signed"},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			err := validatePayload(rt.header, []byte(payload), secret)
+			if len(rt.err) == 0 {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, rt.err)
+			}
+		})
+	}
+}
+
+func TestWebhookTrigger(t *testing.T) {
+	hook := newWebhook([]byte("secret"))
+	request := func(signature, event string) int {
+		r := httptest.NewRequest(http.MethodPost, WebhookPath, strings.NewReader(payload))
+		r.Header.Set("X-Hub-Signature-256", signature)
+		r.Header.Set("X-GitHub-Event", event)
+		w := httptest.NewRecorder()
+		hook.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, request(sign("other", payload), "push"), http.StatusUnauthorized)
+	assert.Equal(t, request(sign("secret", payload), "ping"), http.StatusOK)
+	assert.Equal(t, len(hook.trigger), 0)
+
+	// Pushes are coalesced while a sync is pending
+	assert.Equal(t, request(sign("secret", payload), "push"), http.StatusAccepted)
+	assert.Equal(t, request(sign("secret", payload), "push"), http.StatusAccepted)
+	assert.Equal(t, len(hook.trigger), 1)
+}
@@ -0,0 +1,229 @@
+// Package health runs the liveness probes of the running VMs, and records their result in
+// the Healthy condition of the VMs. A VM whose probe keeps failing can be killed, so its
+// restart policy restarts it.
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// state tracks the probes of a run of a VM
+type state struct {
+	// started is when the run of the VM the state is for started
+	started time.Time
+	// nextProbe is when the probe is run next
+	nextProbe time.Time
+	// failures counts the consecutive failures of the probe
+	failures uint32
+	// healthy is the recorded result, nil until the probe succeeds or reaches the failure threshold
+	healthy *bool
+	// killed is set once the VM is killed for being unhealthy
+	killed bool
+}
+
+// Prober runs the liveness probes of the running VMs when they're due
+type Prober struct {
+	states map[runtime.UID]*state
+	probe  func(vm *api.VM, probe *api.VMProbe, timeout time.Duration) error
+	// setHealthy records the result in the Healthy condition of the VM
+	setHealthy func(vm *api.VM, healthy bool, message string) error
+	kill       func(vm *api.VM) error
+}
+
+// NewProber creates a new Prober
+func NewProber() *Prober {
+	return &Prober{
+		states:     make(map[runtime.UID]*state),
+		probe:      Probe,
+		setHealthy: setHealthy,
+		kill:       kill,
+	}
+}
+
+// Run checks for probes that are due every PROBE_CHECK_INTERVAL. It never returns.
+func (p *Prober) Run() {
+	for {
+		time.Sleep(constants.PROBE_CHECK_INTERVAL)
+
+		vms, err := providers.Client.VMs().FindAll(filter.NewAllFilter())
+		if err != nil {
+			log.Errorf("Failed to list VMs for probing: %v", err)
+			continue
+		}
+
+		p.check(vms, time.Now())
+	}
+}
+
+// check runs the probes of the VMs that are due at the given time, concurrently, and
+// records their results
+func (p *Prober) check(vms []*api.VM, now time.Time) {
+	var due []*api.VM
+	running := make(map[runtime.UID]bool, len(vms))
+	for _, vm := range vms {
+		probe := vm.Spec.LivenessProbe
+		if probe == nil || !vm.Running() || vm.Paused() {
+			continue
+		}
+		running[vm.GetUID()] = true
+
+		// A restarted VM starts over with its initial delay
+		started := startTime(vm)
+		s, ok := p.states[vm.GetUID()]
+		if !ok || !s.started.Equal(started) {
+			s = &state{started: started, nextProbe: started.Add(probe.InitialDelay.Duration)}
+			p.states[vm.GetUID()] = s
+		}
+
+		if s.killed || now.Before(s.nextProbe) {
+			continue
+		}
+
+		s.nextProbe = now.Add(period(probe))
+		due = append(due, vm)
+	}
+
+	// Forget the VMs that were removed, stopped or paused, or whose probe was removed
+	for uid := range p.states {
+		if !running[uid] {
+			delete(p.states, uid)
+		}
+	}
+
+	errs := make([]error, len(due))
+	var wg sync.WaitGroup
+	for i, vm := range due {
+		wg.Add(1)
+		go func(i int, vm *api.VM) {
+			defer wg.Done()
+			errs[i] = p.probe(vm, vm.Spec.LivenessProbe, timeout(vm.Spec.LivenessProbe))
+		}(i, vm)
+	}
+	wg.Wait()
+
+	for i, vm := range due {
+		p.record(vm, p.states[vm.GetUID()], errs[i])
+	}
+}
+
+// record records the result of the probe of the VM, and kills it once it's unhealthy if
+// its probe asks for it
+func (p *Prober) record(vm *api.VM, s *state, err error) {
+	probe := vm.Spec.LivenessProbe
+	healthy := err == nil
+	message := ""
+	if healthy {
+		s.failures = 0
+	} else {
+		s.failures++
+		log.Debugf("Liveness probe of VM %q failed (%d/%d): %v", vm.GetUID(), s.failures, failureThreshold(probe), err)
+
+		// The VM is still considered healthy until the probe fails often enough in a row
+		if s.failures < failureThreshold(probe) {
+			return
+		}
+
+		message = fmt.Sprintf("the liveness probe failed %d times in a row: %v", s.failures, err)
+	}
+
+	if s.healthy == nil || *s.healthy != healthy {
+		s.healthy = &healthy
+		if healthy {
+			log.Infof("VM %q with name %q is healthy", vm.GetUID(), vm.GetName())
+		} else {
+			log.Warnf("VM %q with name %q is unhealthy, %s", vm.GetUID(), vm.GetName(), message)
+		}
+
+		if err := p.setHealthy(vm, healthy, message); err != nil {
+			log.Errorf("Failed to update the Healthy condition of VM %q: %v", vm.GetUID(), err)
+		}
+	}
+
+	if !healthy && probe.Restart {
+		log.Infof("Killing unhealthy VM %q with name %q, so its restart policy applies...", vm.GetUID(), vm.GetName())
+		s.killed = true
+		if err := p.kill(vm); err != nil {
+			log.Errorf("Failed to kill unhealthy VM %q: %v", vm.GetUID(), err)
+		}
+	}
+}
+
+// setHealthy patches the Healthy condition of the stored VM, its other conditions are
+// updated by ignite-spawn meanwhile
+func setHealthy(vm *api.VM, healthy bool, message string) error {
+	current, err := providers.Client.VMs().Get(vm.GetUID())
+	if err != nil {
+		return err
+	}
+
+	if healthy {
+		current.SetCondition(api.VMHealthy, api.ConditionTrue, "ProbeSucceeded", "")
+	} else {
+		current.SetCondition(api.VMHealthy, api.ConditionFalse, "ProbeFailed", message)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": current.Status.Conditions,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return providers.Client.VMs().Patch(vm.GetUID(), patch)
+}
+
+// kill kills the VM without marking the stop as requested, so its restart policy applies.
+// ignite-spawn records Unhealthy as the reason the VM stopped.
+func kill(vm *api.VM) error {
+	if err := operations.MarkUnhealthy(vm); err != nil {
+		return err
+	}
+
+	return providers.Runtime.KillContainer(vm.PrefixedID(), "SIGQUIT")
+}
+
+func startTime(vm *api.VM) time.Time {
+	if vm.Status.StartTime == nil {
+		return time.Time{}
+	}
+
+	return vm.Status.StartTime.Time.Time
+}
+
+func period(probe *api.VMProbe) time.Duration {
+	if probe.Period.Duration > 0 {
+		return probe.Period.Duration
+	}
+
+	return constants.PROBE_PERIOD
+}
+
+func timeout(probe *api.VMProbe) time.Duration {
+	if probe.Timeout.Duration > 0 {
+		return probe.Timeout.Duration
+	}
+
+	return constants.PROBE_TIMEOUT
+}
+
+func failureThreshold(probe *api.VMProbe) uint32 {
+	if probe.FailureThreshold > 0 {
+		return probe.FailureThreshold
+	}
+
+	return constants.PROBE_FAILURE_THRESHOLD
+}
@@ -0,0 +1,123 @@
+package health
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+func newVM(started time.Time, probe *api.VMProbe) *api.VM {
+	vm := &api.VM{}
+	vm.SetUID("599615df99804ae8")
+	vm.Spec.LivenessProbe = probe
+	vm.Status.Running = true
+	vm.Status.StartTime = &runtime.Time{Time: metav1.Time{Time: started}}
+	return vm
+}
+
+func TestCheck(t *testing.T) {
+	var probed int
+	var probeErr error
+	var results []bool
+	var killed int
+	p := &Prober{
+		states: make(map[runtime.UID]*state),
+		probe: func(*api.VM, *api.VMProbe, time.Duration) error {
+			probed++
+			return probeErr
+		},
+		setHealthy: func(vm *api.VM, healthy bool, message string) error {
+			results = append(results, healthy)
+			return nil
+		},
+		kill: func(*api.VM) error {
+			killed++
+			return nil
+		},
+	}
+
+	t0 := time.Now()
+	probe := &api.VMProbe{
+		TCP:              &api.TCPProbe{Port: 22},
+		InitialDelay:     metav1.Duration{Duration: 5 * time.Second},
+		FailureThreshold: 2,
+		Restart:          true,
+	}
+	vm := newVM(t0, probe)
+
+	// The probe isn't run before its initial delay, then every period
+	probeErr = errors.New("connection refused")
+	p.check([]*api.VM{vm}, t0.Add(time.Second))
+	assert.Equal(t, probed, 0)
+	p.check([]*api.VM{vm}, t0.Add(5*time.Second))
+	p.check([]*api.VM{vm}, t0.Add(6*time.Second))
+	assert.Equal(t, probed, 1)
+	assert.Equal(t, len(results), 0)
+
+	// The VM is unhealthy once the probe reaches the failure threshold, and killed
+	p.check([]*api.VM{vm}, t0.Add(15*time.Second))
+	assert.Equal(t, probed, 2)
+	assert.DeepEqual(t, results, []bool{false})
+	assert.Equal(t, killed, 1)
+
+	// A killed VM isn't probed until it's restarted
+	p.check([]*api.VM{vm}, t0.Add(25*time.Second))
+	assert.Equal(t, probed, 2)
+
+	// The restarted VM starts over with its initial delay
+	t1 := t0.Add(30 * time.Second)
+	probeErr = nil
+	vm = newVM(t1, probe)
+	p.check([]*api.VM{vm}, t1.Add(time.Second))
+	assert.Equal(t, probed, 2)
+	p.check([]*api.VM{vm}, t1.Add(5*time.Second))
+	assert.Equal(t, probed, 3)
+	assert.DeepEqual(t, results, []bool{false, true})
+
+	// Only transitions are recorded
+	p.check([]*api.VM{vm}, t1.Add(15*time.Second))
+	assert.Equal(t, probed, 4)
+	assert.DeepEqual(t, results, []bool{false, true})
+
+	// Stopped VMs aren't probed, and are forgotten
+	vm.Status.Running = false
+	p.check([]*api.VM{vm}, t1.Add(25*time.Second))
+	assert.Equal(t, probed, 4)
+	assert.Equal(t, len(p.states), 0)
+}
+
+func TestProbe(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	_, portStr, err := net.SplitHostPort(ts.Listener.Addr().String())
+	assert.NilError(t, err)
+	port, err := strconv.Atoi(portStr)
+	assert.NilError(t, err)
+
+	vm := newVM(time.Now(), nil)
+	vm.Status.Network = &api.Network{IPAddresses: meta.IPAddresses{net.ParseIP("127.0.0.1")}}
+
+	assert.NilError(t, Probe(vm, &api.VMProbe{TCP: &api.TCPProbe{Port: uint16(port)}}, time.Second))
+	assert.NilError(t, Probe(vm, &api.VMProbe{HTTP: &api.HTTPProbe{Port: uint16(port), Path: "/healthz"}}, time.Second))
+	assert.ErrorContains(t, Probe(vm, &api.VMProbe{HTTP: &api.HTTPProbe{Port: uint16(port)}}, time.Second), "503")
+
+	// A VM without an address can't be probed over the network
+	vm.Status.Network = nil
+	assert.ErrorContains(t, Probe(vm, &api.VMProbe{TCP: &api.TCPProbe{Port: uint16(port)}}, time.Second), "no IP address")
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+// maxProbeOutput is how much of the output of a failed exec probe is reported
+const maxProbeOutput = 256
+
+// Probe runs the probe against the VM once, and returns why it failed
+func Probe(vm *api.VM, probe *api.VMProbe, timeout time.Duration) error {
+	switch {
+	case probe.TCP != nil:
+		return probeTCP(vm, probe.TCP, timeout)
+	case probe.HTTP != nil:
+		return probeHTTP(vm, probe.HTTP, timeout)
+	case probe.Exec != nil:
+		return probeExec(vm, probe.Exec, timeout)
+	}
+
+	return fmt.Errorf("the probe has no tcp, http or exec check")
+}
+
+// address returns the address of the port of the VM, on the first IP address of the VM
+func address(vm *api.VM, port uint16) (string, error) {
+	if vm.Status.Network == nil || len(vm.Status.Network.IPAddresses) == 0 {
+		return "", fmt.Errorf("VM %q has no IP address", vm.GetUID())
+	}
+
+	return net.JoinHostPort(vm.Status.Network.IPAddresses[0].String(), strconv.Itoa(int(port))), nil
+}
+
+func probeTCP(vm *api.VM, probe *api.TCPProbe, timeout time.Duration) error {
+	addr, err := address(vm, probe.Port)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+func probeHTTP(vm *api.VM, probe *api.HTTPProbe, timeout time.Duration) error {
+	addr, err := address(vm, probe.Port)
+	if err != nil {
+		return err
+	}
+
+	scheme := "http"
+	if probe.HTTPS {
+		scheme = "https"
+	}
+
+	urlPath := probe.Path
+	if len(urlPath) == 0 {
+		urlPath = "/"
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// The VMs serve self-signed certificates for their IP addresses
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+		// Redirects are a success, like the status codes of 300 to 399
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(fmt.Sprintf("%s://%s%s", scheme, addr, urlPath))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("GET %s returned %s", urlPath, resp.Status)
+	}
+
+	return nil
+}
+
+// probeExec runs the command through the guest agent. A command that times out is left
+// running in the VM, the probe only stops waiting for it.
+func probeExec(vm *api.VM, probe *api.ExecProbe, timeout time.Duration) error {
+	type result struct {
+		exitCode int
+		output   string
+		err      error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		var out bytes.Buffer
+		exitCode, err := agent.Exec(vm, &protocol.ExecRequest{Command: probe.Command}, &agent.ExecStreams{
+			Stdout: &out,
+			Stderr: &out,
+		})
+		done <- result{exitCode, out.String(), err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return r.err
+		}
+
+		if r.exitCode != 0 {
+			out := strings.TrimSpace(r.output)
+			if len(out) > maxProbeOutput {
+				out = out[:maxProbeOutput] + "..."
+			}
+
+			return fmt.Errorf("%q exited with code %d: %s", strings.Join(probe.Command, " "), r.exitCode, out)
+		}
+
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("%q timed out after %s", strings.Join(probe.Command, " "), timeout)
+	}
+}
@@ -0,0 +1,394 @@
+// Package jobs schedules the operations ignited runs on behalf of the management API and
+// the reconciliation of manifests. Every operation is a job of a class, e.g. an import or a
+// VM start, and at most the limit of its class run at once, the others wait in the queue.
+// The queued, running and recently finished jobs are written to a state file, which is
+// read by "ignite jobs".
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// Class is the kind of operation of a job, the concurrency is limited per class
+type Class string
+
+const (
+	// ClassImport imports images and kernels
+	ClassImport Class = "import"
+	// ClassStart creates and starts VMs
+	ClassStart Class = "start"
+	// ClassStop stops VMs
+	ClassStop Class = "stop"
+	// ClassRemove removes VMs
+	ClassRemove Class = "remove"
+	// ClassReconcile applies the change of a manifest, or corrects the drift of a VM
+	ClassReconcile Class = "reconcile"
+)
+
+// Classes are all the classes of jobs
+var Classes = []Class{ClassImport, ClassStart, ClassStop, ClassRemove, ClassReconcile}
+
+// DefaultLimits are how many jobs of each class run at once by default
+var DefaultLimits = map[Class]int{
+	ClassImport:    3,
+	ClassStart:     4,
+	ClassStop:      8,
+	ClassRemove:    8,
+	ClassReconcile: 4,
+}
+
+// State is the state of a job
+type State string
+
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+)
+
+// historySize is how many finished jobs are kept
+const historySize = 100
+
+// Job is an operation scheduled by the queue
+type Job struct {
+	ID    uint64 `json:"id"`
+	Class Class  `json:"class"`
+	// Target is the object of the operation, e.g. "vm/my-vm"
+	Target string `json:"target"`
+	// Source is what triggered the operation, see the sources of the audit package
+	Source   string     `json:"source"`
+	State    State      `json:"state"`
+	Error    string     `json:"error,omitempty"`
+	Queued   time.Time  `json:"queued"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+// Status is the content of the state file
+type Status struct {
+	// PID is the process ID of the ignited daemon running the jobs
+	PID    int           `json:"pid"`
+	Limits map[Class]int `json:"limits"`
+	Jobs   []Job         `json:"jobs"`
+}
+
+// Queue runs jobs with per-class concurrency limits
+type Queue struct {
+	mu     sync.Mutex
+	limits map[Class]int
+	slots  map[Class]chan struct{}
+	nextID uint64
+	// jobs are the queued and running jobs, and the last finished ones
+	jobs []*Job
+	// statePath is the file the jobs are written to, no file is written if it's empty
+	statePath string
+}
+
+// Default is the queue of ignited, operations run right away without it
+var Default *Queue
+
+// NewQueue returns a queue running at most the given number of jobs of each class at once,
+// the classes without a limit use the default one. The jobs are written to statePath.
+func NewQueue(limits map[Class]int, statePath string) *Queue {
+	q := &Queue{
+		limits:    make(map[Class]int),
+		slots:     make(map[Class]chan struct{}),
+		statePath: statePath,
+	}
+
+	for _, class := range Classes {
+		limit := DefaultLimits[class]
+		if l, ok := limits[class]; ok && l > 0 {
+			limit = l
+		}
+
+		q.limits[class] = limit
+		q.slots[class] = make(chan struct{}, limit)
+	}
+
+	return q
+}
+
+// ParseLimits parses the class=limit pairs of the concurrency limits
+func ParseLimits(values map[string]int) (map[Class]int, error) {
+	limits := make(map[Class]int, len(values))
+	for name, limit := range values {
+		class := Class(name)
+		if _, ok := DefaultLimits[class]; !ok {
+			return nil, fmt.Errorf("unknown job class %q, supported classes: %s", name, classNames())
+		}
+		if limit < 1 {
+			return nil, fmt.Errorf("the limit of job class %q needs to be at least 1, got %d", name, limit)
+		}
+
+		limits[class] = limit
+	}
+
+	return limits, nil
+}
+
+func classNames() string {
+	names := make([]string, 0, len(Classes))
+	for _, class := range Classes {
+		names = append(names, string(class))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// Target returns the target of a job on the object, its kind and name, or UID if it has no name
+func Target(obj runtime.Object) string {
+	name := obj.GetName()
+	if len(name) == 0 {
+		name = obj.GetUID().String()
+	}
+
+	return strings.ToLower(obj.GetKind().String()) + "/" + name
+}
+
+// Run runs fn as a job of the class on the default queue, or right away if there's none
+func Run(ctx context.Context, class Class, target, source string, fn func() error) error {
+	if Default == nil {
+		return fn()
+	}
+
+	return Default.Run(ctx, class, target, source, fn)
+}
+
+// Run queues fn as a job of the class, and runs it when fewer jobs of the class than its
+// limit are running. It returns the error of fn, or the error of the context if it's done
+// before the job starts.
+func (q *Queue) Run(ctx context.Context, class Class, target, source string, fn func() error) error {
+	slots, ok := q.slots[class]
+	if !ok {
+		return fmt.Errorf("unknown job class %q", class)
+	}
+
+	job := q.add(class, target, source)
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		q.finish(job, ctx.Err())
+		return ctx.Err()
+	}
+	defer func() { <-slots }()
+
+	q.start(job)
+	err := fn()
+	q.finish(job, err)
+	return err
+}
+
+func (q *Queue) add(class Class, target, source string) *Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.nextID++
+	job := &Job{
+		ID:     q.nextID,
+		Class:  class,
+		Target: target,
+		Source: source,
+		State:  StateQueued,
+		Queued: time.Now().UTC(),
+	}
+
+	q.jobs = append(q.jobs, job)
+	q.save()
+	return job
+}
+
+func (q *Queue) start(job *Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now().UTC()
+	job.State = StateRunning
+	job.Started = &now
+	log.Debugf("Running %s job %d of %s", job.Class, job.ID, job.Target)
+	q.save()
+}
+
+func (q *Queue) finish(job *Job, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now().UTC()
+	job.State = StateSucceeded
+	job.Finished = &now
+	if err != nil {
+		job.State = StateFailed
+		job.Error = err.Error()
+	}
+
+	q.prune()
+	q.save()
+}
+
+// prune drops the oldest finished jobs beyond the history size
+func (q *Queue) prune() {
+	finished := 0
+	for _, job := range q.jobs {
+		if job.Finished != nil {
+			finished++
+		}
+	}
+
+	kept := q.jobs[:0]
+	for _, job := range q.jobs {
+		if job.Finished != nil && finished > historySize {
+			finished--
+			continue
+		}
+
+		kept = append(kept, job)
+	}
+
+	q.jobs = kept
+}
+
+// Status returns the limits and the jobs of the queue, by ID
+func (q *Queue) Status() *Status {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.status()
+}
+
+func (q *Queue) status() *Status {
+	status := &Status{
+		PID:    os.Getpid(),
+		Limits: make(map[Class]int, len(q.limits)),
+		Jobs:   make([]Job, 0, len(q.jobs)),
+	}
+
+	for class, limit := range q.limits {
+		status.Limits[class] = limit
+	}
+
+	for _, job := range q.jobs {
+		status.Jobs = append(status.Jobs, *job)
+	}
+
+	return status
+}
+
+// save writes the jobs to the state file, replacing it atomically. A failure to write
+// it is logged, it doesn't fail the jobs.
+func (q *Queue) save() {
+	if len(q.statePath) == 0 {
+		return
+	}
+
+	if err := writeStatus(q.statePath, q.status()); err != nil {
+		log.Warnf("Failed to write the jobs to %q: %v", q.statePath, err)
+	}
+}
+
+// Close removes the state file, the jobs of a stopped daemon aren't shown
+func (q *Queue) Close() error {
+	if len(q.statePath) == 0 {
+		return nil
+	}
+
+	if err := os.Remove(q.statePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func writeStatus(statePath string, status *Status) error {
+	b, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(path.Dir(statePath), "."+path.Base(statePath))
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), statePath)
+}
+
+// ReadStatus reads the jobs of the running ignited daemon from the state file. It returns
+// nil if the daemon isn't running.
+func ReadStatus(statePath string) (*Status, error) {
+	b, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	status := &Status{}
+	if err := json.Unmarshal(b, status); err != nil {
+		return nil, fmt.Errorf("failed to decode %q: %v", statePath, err)
+	}
+
+	// The file of a daemon that didn't exit cleanly is left behind
+	if !processExists(status.PID) {
+		return nil, nil
+	}
+
+	sort.Slice(status.Jobs, func(i, j int) bool {
+		return status.Jobs[i].ID < status.Jobs[j].ID
+	})
+
+	return status, nil
+}
+
+// processExists returns whether a process with the PID exists
+func processExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// FormatLimits formats the limits as class=limit pairs, in the order of the classes
+func FormatLimits(limits map[Class]int) string {
+	pairs := make([]string, 0, len(limits))
+	for _, class := range Classes {
+		if limit, ok := limits[class]; ok {
+			pairs = append(pairs, string(class)+"="+strconv.Itoa(limit))
+		}
+	}
+
+	return strings.Join(pairs, ",")
+}
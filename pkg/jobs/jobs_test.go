@@ -0,0 +1,123 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestRunLimit(t *testing.T) {
+	q := NewQueue(map[Class]int{ClassStart: 1}, "")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- q.Run(context.Background(), ClassStart, "vm/a", "api", func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	// The second start waits for the first one, until its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := q.Run(ctx, ClassStart, "vm/b", "api", func() error {
+		t.Error("the second start ran while the first one was running")
+		return nil
+	})
+	assert.Equal(t, err, context.DeadlineExceeded)
+
+	// Other classes aren't held up
+	assert.NilError(t, q.Run(context.Background(), ClassStop, "vm/c", "api", func() error { return nil }))
+
+	close(release)
+	assert.NilError(t, <-done)
+
+	err = q.Run(context.Background(), ClassStart, "vm/d", "api", func() error { return fmt.Errorf("no space left") })
+	assert.Error(t, err, "no space left")
+
+	states := map[string]State{}
+	for _, job := range q.Status().Jobs {
+		states[job.Target] = job.State
+	}
+	assert.DeepEqual(t, states, map[string]State{
+		"vm/a": StateSucceeded,
+		"vm/b": StateFailed,
+		"vm/c": StateSucceeded,
+		"vm/d": StateFailed,
+	})
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits(map[string]int{"import": 2, "start": 8})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, limits, map[Class]int{ClassImport: 2, ClassStart: 8})
+
+	_, err = ParseLimits(map[string]int{"pause": 2})
+	assert.Error(t, err, `unknown job class "pause", supported classes: import, start, stop, remove, reconcile`)
+
+	_, err = ParseLimits(map[string]int{"stop": 0})
+	assert.Error(t, err, `the limit of job class "stop" needs to be at least 1, got 0`)
+
+	q := NewQueue(limits, "")
+	assert.Equal(t, FormatLimits(q.Status().Limits), "import=2,start=8,stop=8,remove=8,reconcile=4")
+}
+
+func TestHistory(t *testing.T) {
+	q := NewQueue(nil, "")
+	for i := 0; i < historySize+10; i++ {
+		assert.NilError(t, q.Run(context.Background(), ClassReconcile, fmt.Sprintf("vm/%d", i), "reconcile", func() error { return nil }))
+	}
+
+	list := q.Status().Jobs
+	assert.Equal(t, len(list), historySize)
+	assert.Equal(t, list[0].Target, "vm/10")
+	assert.Equal(t, list[0].ID, uint64(11))
+}
+
+func TestStatusFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-jobs-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	statePath := path.Join(dir, "jobs.json")
+	status, err := ReadStatus(statePath)
+	assert.NilError(t, err)
+	assert.Assert(t, status == nil)
+
+	q := NewQueue(map[Class]int{ClassImport: 1}, statePath)
+	assert.NilError(t, q.Run(context.Background(), ClassImport, "image/weaveworks/ignite-ubuntu", "api", func() error {
+		status, err := ReadStatus(statePath)
+		assert.NilError(t, err)
+		assert.Equal(t, len(status.Jobs), 1)
+		assert.Equal(t, status.Jobs[0].State, StateRunning)
+		return nil
+	}))
+
+	status, err = ReadStatus(statePath)
+	assert.NilError(t, err)
+	assert.Equal(t, status.PID, os.Getpid())
+	assert.Equal(t, status.Limits[ClassImport], 1)
+	assert.Equal(t, status.Jobs[0].State, StateSucceeded)
+	assert.Equal(t, status.Jobs[0].Target, "image/weaveworks/ignite-ubuntu")
+
+	// The file of a daemon that exited isn't read
+	status.PID = 1 << 30
+	assert.NilError(t, writeStatus(statePath, status))
+	status, err = ReadStatus(statePath)
+	assert.NilError(t, err)
+	assert.Assert(t, status == nil)
+
+	assert.NilError(t, q.Close())
+	_, err = os.Stat(statePath)
+	assert.Assert(t, os.IsNotExist(err))
+}
@@ -0,0 +1,140 @@
+// Package kernelconfig reads the build configuration of kernels, to verify they have the
+// options the VMs need when they're imported, instead of the VMs hanging at boot
+package kernelconfig
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// The config embedded by CONFIG_IKCONFIG is gzipped between these markers
+var ikconfigStart = []byte("IKCFG_ST")
+
+// ErrNoConfig is returned for kernels without an embedded or bundled config
+var ErrNoConfig = errors.New("no kernel config found")
+
+// Config maps the options set in a kernel config to their values, without their CONFIG_
+// prefix, e.g. VIRTIO_BLK to y
+type Config map[string]string
+
+// Parse parses a kernel config file. Options that aren't set are left out.
+func Parse(r io.Reader) (Config, error) {
+	cfg := Config{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if !strings.HasPrefix(line, "CONFIG_") {
+			continue // Comments, including the "is not set" options
+		}
+
+		kv := strings.SplitN(strings.TrimPrefix(line, "CONFIG_"), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		cfg[kv[0]] = strings.Trim(kv[1], `"`)
+	}
+
+	return cfg, sc.Err()
+}
+
+// Embedded reads the config embedded in the kernel file, the kernel needs to be built
+// with CONFIG_IKCONFIG
+func Embedded(kernelFile string) (Config, error) {
+	b, err := ioutil.ReadFile(kernelFile)
+	if err != nil {
+		return nil, err
+	}
+
+	i := bytes.Index(b, ikconfigStart)
+	if i < 0 {
+		return nil, ErrNoConfig
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(b[i+len(ikconfigStart):]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the embedded kernel config: %v", err)
+	}
+	gz.Multistream(false)
+
+	return Parse(gz)
+}
+
+// Bundled reads the config file the kernel image ships in /boot, named config or
+// config-<version>, from the kernel tar holding the files of the kernel image
+func Bundled(kernelTar string) (Config, error) {
+	f, err := os.Open(kernelTar)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, ErrNoConfig
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		dir, name := path.Split(strings.TrimPrefix(path.Clean(hdr.Name), "/"))
+		if hdr.Typeflag != tar.TypeReg || path.Clean(dir) != "boot" || (name != "config" && !strings.HasPrefix(name, "config-")) {
+			continue
+		}
+
+		return Parse(tr)
+	}
+}
+
+// Requirement is a kernel option the VMs need
+type Requirement struct {
+	// Option is the name of the option without the CONFIG_ prefix
+	Option string
+	// Purpose is what the VMs need the option for
+	Purpose string
+	// Boot tells whether the VMs can't boot without the option. The option needs to be
+	// built in, unless the kernel has an initrd loading it as a module.
+	Boot bool
+}
+
+// Requirements are the options the VMs need, Firecracker attaches the drives and the
+// network interfaces as virtio devices over MMIO
+var Requirements = []Requirement{
+	{Option: "VIRTIO_MMIO", Purpose: "the virtio devices of Firecracker", Boot: true},
+	{Option: "VIRTIO_BLK", Purpose: "the root drive", Boot: true},
+	{Option: "EXT4_FS", Purpose: "the root filesystem", Boot: true},
+	{Option: "VIRTIO_NET", Purpose: "the network interfaces"},
+	{Option: "IP_PNP", Purpose: "the ip kernel argument configuring the network"},
+	{Option: "SERIAL_8250_CONSOLE", Purpose: "the serial console"},
+	{Option: "VIRTIO_VSOCKETS", Purpose: "the guest agent"},
+}
+
+// Missing returns the requirements the config doesn't meet. The boot requirements are
+// returned separately from the others.
+func Missing(cfg Config, hasInitrd bool) (boot, other []Requirement) {
+	for _, r := range Requirements {
+		switch v := cfg[r.Option]; {
+		case v == "y":
+			continue
+		case v == "m" && (!r.Boot || hasInitrd):
+			continue
+		case r.Boot:
+			boot = append(boot, r)
+		default:
+			other = append(other, r)
+		}
+	}
+
+	return
+}
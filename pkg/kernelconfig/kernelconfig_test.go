@@ -0,0 +1,138 @@
+package kernelconfig
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const testConfig = `#
+# Automatically generated file; DO NOT EDIT.
+#
+CONFIG_VIRTIO_MMIO=y
+CONFIG_VIRTIO_BLK=m
+# CONFIG_VIRTIO_NET is not set
+CONFIG_CMDLINE="console=ttyS0"
+`
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse(strings.NewReader(testConfig))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cfg, Config{
+		"VIRTIO_MMIO": "y",
+		"VIRTIO_BLK":  "m",
+		"CMDLINE":     "console=ttyS0",
+	})
+}
+
+func TestEmbedded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-kernelconfig-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The gzipped config is followed by the end marker and the rest of the kernel
+	var kernel bytes.Buffer
+	kernel.WriteString("\x7fELF...IKCFG_ST")
+	gz := gzip.NewWriter(&kernel)
+	_, err = gz.Write([]byte(testConfig))
+	assert.NilError(t, err)
+	assert.NilError(t, gz.Close())
+	kernel.WriteString("IKCFG_ED...")
+
+	kernelFile := path.Join(dir, "vmlinux")
+	assert.NilError(t, ioutil.WriteFile(kernelFile, kernel.Bytes(), 0644))
+
+	cfg, err := Embedded(kernelFile)
+	assert.NilError(t, err)
+	assert.Equal(t, cfg["VIRTIO_BLK"], "m")
+
+	assert.NilError(t, ioutil.WriteFile(kernelFile, []byte("\x7fELF..."), 0644))
+	_, err = Embedded(kernelFile)
+	assert.Equal(t, err, ErrNoConfig)
+}
+
+func TestBundled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-kernelconfig-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	kernelTar := func(files map[string]string) string {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for name, content := range files {
+			assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}))
+			_, err := tw.Write([]byte(content))
+			assert.NilError(t, err)
+		}
+		assert.NilError(t, tw.Close())
+
+		p := path.Join(dir, "kernel.tar")
+		assert.NilError(t, ioutil.WriteFile(p, buf.Bytes(), 0644))
+		return p
+	}
+
+	cfg, err := Bundled(kernelTar(map[string]string{"./boot/vmlinux": "", "./boot/config-5.10.51": testConfig}))
+	assert.NilError(t, err)
+	assert.Equal(t, cfg["VIRTIO_MMIO"], "y")
+
+	_, err = Bundled(kernelTar(map[string]string{"./boot/vmlinux": "", "./etc/config": testConfig}))
+	assert.Equal(t, err, ErrNoConfig)
+}
+
+func TestMissing(t *testing.T) {
+	options := func(rs []Requirement) (names []string) {
+		for _, r := range rs {
+			names = append(names, r.Option)
+		}
+		return
+	}
+
+	all := Config{}
+	for _, r := range Requirements {
+		all[r.Option] = "y"
+	}
+
+	tests := []struct {
+		name      string
+		config    Config
+		hasInitrd bool
+		boot      []string
+		other     []string
+	}{
+		{
+			name:   "all built in",
+			config: all,
+		},
+		{
+			name:   "nothing set",
+			config: Config{},
+			boot:   []string{"VIRTIO_MMIO", "VIRTIO_BLK", "EXT4_FS"},
+			other:  []string{"VIRTIO_NET", "IP_PNP", "SERIAL_8250_CONSOLE", "VIRTIO_VSOCKETS"},
+		},
+		{
+			name:   "boot options as modules",
+			config: Config{"VIRTIO_MMIO": "y", "VIRTIO_BLK": "m", "EXT4_FS": "m", "VIRTIO_NET": "m", "IP_PNP": "y", "SERIAL_8250_CONSOLE": "y", "VIRTIO_VSOCKETS": "m"},
+			boot:   []string{"VIRTIO_BLK", "EXT4_FS"},
+		},
+		{
+			name:      "boot options as modules of the initrd",
+			config:    Config{"VIRTIO_MMIO": "y", "VIRTIO_BLK": "m", "EXT4_FS": "m", "VIRTIO_NET": "m", "IP_PNP": "y", "SERIAL_8250_CONSOLE": "y", "VIRTIO_VSOCKETS": "m"},
+			hasInitrd: true,
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			boot, other := Missing(rt.config, rt.hasInitrd)
+			assert.DeepEqual(t, options(boot), rt.boot)
+			assert.DeepEqual(t, options(other), rt.other)
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package flag
 
 import (
+	"fmt"
+
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/pkg/logs"
 )
 
 type LogLevelFlag struct {
@@ -31,3 +34,33 @@ var _ pflag.Value = &LogLevelFlag{}
 func LogLevelFlagVar(fs *pflag.FlagSet, ptr *logrus.Level) {
 	fs.Var(&LogLevelFlag{value: ptr}, "log-level", "Specify the loglevel for the program")
 }
+
+type LogFormatFlag struct {
+	value *string
+}
+
+func (lf *LogFormatFlag) Set(val string) error {
+	if val != logs.FormatText && val != logs.FormatJSON {
+		return fmt.Errorf("expected %s or %s", logs.FormatText, logs.FormatJSON)
+	}
+
+	*lf.value = val
+	return nil
+}
+
+func (lf *LogFormatFlag) String() string {
+	if lf.value == nil {
+		return ""
+	}
+	return *lf.value
+}
+
+func (lf *LogFormatFlag) Type() string {
+	return "logformat"
+}
+
+var _ pflag.Value = &LogFormatFlag{}
+
+func LogFormatFlagVar(fs *pflag.FlagSet, ptr *string) {
+	fs.Var(&LogFormatFlag{value: ptr}, "log-format", "Specify the format of the log lines, text or json for structured logs")
+}
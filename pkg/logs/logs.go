@@ -1,9 +1,11 @@
 package logs
 
 import (
+	"fmt"
 	"io/ioutil"
 	golog "log"
 	"os"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -11,6 +13,24 @@ import (
 // Quiet specifies whether to only print machine-readable IDs
 var Quiet bool
 
+// The formats of the log lines
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Format is the format of the log lines, set it with SetFormat
+var Format = FormatText
+
+// The fields of the structured log lines
+const (
+	FieldVMUID     = "vm_uid"
+	FieldVMName    = "vm_name"
+	FieldOperation = "operation"
+	FieldPhase     = "phase"
+	FieldDuration  = "duration"
+)
+
 // Wrap the logrus logger together with the exit code
 // so we can control what log.Fatal returns
 type logger struct {
@@ -57,3 +77,30 @@ func init() {
 	golog.SetFlags(0)
 	golog.SetOutput(Logger.Writer())
 }
+
+// SetFormat sets the format of the log lines, text for humans or JSON with one object per
+// line for log pipelines
+func SetFormat(format string) error {
+	switch format {
+	case FormatText:
+		Logger.SetFormatter(&log.TextFormatter{
+			DisableTimestamp: false,
+			FullTimestamp:    false,
+		})
+	case FormatJSON:
+		Logger.SetFormatter(&log.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+	default:
+		return fmt.Errorf("invalid log format %q, expected %s or %s", format, FormatText, FormatJSON)
+	}
+
+	Format = format
+	return nil
+}
+
+// WithVM returns a log entry with the fields identifying the VM
+func WithVM(uid, name string) *log.Entry {
+	return Logger.WithFields(log.Fields{
+		FieldVMUID:  uid,
+		FieldVMName: name,
+	})
+}
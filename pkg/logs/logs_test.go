@@ -0,0 +1,32 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestSetFormat(t *testing.T) {
+	var out bytes.Buffer
+	Logger.SetOutput(&out)
+	defer Logger.SetOutput(os.Stdout)
+	defer SetFormat(FormatText)
+
+	assert.NilError(t, SetFormat(FormatJSON))
+	assert.Equal(t, Format, FormatJSON)
+	WithVM("599615df99804ae8", "my-vm").WithField(FieldOperation, "start").Info("Started VM")
+
+	var line map[string]interface{}
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, line["msg"], "Started VM")
+	assert.Equal(t, line["level"], "info")
+	assert.Equal(t, line[FieldVMUID], "599615df99804ae8")
+	assert.Equal(t, line[FieldVMName], "my-vm")
+	assert.Equal(t, line[FieldOperation], "start")
+
+	assert.ErrorContains(t, SetFormat("xml"), "invalid log format")
+	assert.Equal(t, Format, FormatJSON)
+}
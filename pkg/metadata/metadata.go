@@ -49,6 +49,32 @@ func SetNameAndUID(obj runtime.Object, c *client.Client) error {
 	return processName(obj, c)
 }
 
+// SetName renames an object, the new name needs to be valid and unique for its kind
+func SetName(obj runtime.Object, name string, c *client.Client) error {
+	if obj == nil {
+		return ErrNilObject
+	}
+
+	if c == nil {
+		c = providers.Client
+	}
+
+	if len(name) == 0 {
+		return fmt.Errorf("%s name must not be empty", obj.GetKind())
+	}
+
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid name %q: does not match required format %s", name, nameRegex.String())
+	}
+
+	if err := verifyUIDOrName(c, name, obj.GetKind()); err != nil {
+		return err
+	}
+
+	obj.SetName(name)
+	return nil
+}
+
 // SetLabels metadata labels for a given object.
 func SetLabels(obj runtime.Object, labels []string) error {
 	if obj == nil {
@@ -0,0 +1,90 @@
+package migration
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// The migration directory of a VM holds a Firecracker snapshot of the VM and a manifest
+// describing the VM on its source host. It's transferred to the destination host together
+// with the VM, where the next start of the VM restores the snapshot instead of booting it.
+const (
+	// ManifestFile is the manifest of the migration
+	ManifestFile = "manifest.json"
+	// StateFile holds the device state of the Firecracker snapshot
+	StateFile = "vmstate"
+	// MemoryFile holds the guest memory of the Firecracker snapshot
+	MemoryFile = "memory"
+)
+
+// Manifest describes a VM on the source host of its migration
+type Manifest struct {
+	// DrivePath is the path of the root drive recorded in the snapshot, the
+	// device of the root drive on the destination host is exposed at this path
+	DrivePath string `json:"drivePath"`
+	// IPAddresses are the addresses the guest has configured. It keeps them across
+	// the restore, as its DHCP lease doesn't expire, until they're changed by the agent.
+	IPAddresses meta.IPAddresses `json:"ipAddresses,omitempty"`
+	// MACAddress is the address of the network interface of the guest in the snapshot.
+	// The DHCP server of the VM serves it, as the guest keeps it after the restore.
+	MACAddress string `json:"macAddress,omitempty"`
+	// BootCache is set if the snapshot is from the boot cache, i.e. of another VM. The
+	// hostname and addresses of the guest are set after the restore.
+	BootCache bool `json:"bootCache,omitempty"`
+}
+
+// Dir returns the migration directory of the VM
+func Dir(vm *api.VM) string {
+	return path.Join(vm.ObjectPath(), constants.MIGRATION_DIR)
+}
+
+// File returns the path of the given file in the migration directory of the VM
+func File(vm *api.VM, name string) string {
+	return path.Join(Dir(vm), name)
+}
+
+// Pending returns true if the VM has a snapshot to be restored on its next start
+func Pending(vm *api.VM) bool {
+	return util.FileExists(File(vm, ManifestFile))
+}
+
+// ReadManifest reads the manifest of the migration of the VM
+func ReadManifest(vm *api.VM) (*Manifest, error) {
+	b, err := ioutil.ReadFile(File(vm, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(b, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// WriteManifest creates the migration directory of the VM, and writes the given manifest to it
+func WriteManifest(vm *api.VM, manifest *Manifest) error {
+	if err := os.MkdirAll(Dir(vm), constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(File(vm, ManifestFile), b, 0644)
+}
+
+// Remove removes the migration directory of the VM, the snapshot can be large
+func Remove(vm *api.VM) error {
+	return os.RemoveAll(Dir(vm))
+}
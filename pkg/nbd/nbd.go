@@ -0,0 +1,87 @@
+package nbd
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// DefaultPort is the IANA assigned port of the NBD protocol
+const DefaultPort = "10809"
+
+// ParseURL splits an nbd://host[:port]/export URL into its parts
+func ParseURL(rawURL string) (host, port, export string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return
+	}
+
+	if u.Scheme != "nbd" {
+		err = fmt.Errorf("unsupported scheme %q, expected nbd://host[:port]/export", u.Scheme)
+		return
+	}
+
+	host, port = u.Hostname(), u.Port()
+	if len(host) == 0 {
+		err = fmt.Errorf("no host given, expected nbd://host[:port]/export")
+		return
+	}
+
+	if len(port) == 0 {
+		port = DefaultPort
+	}
+
+	export = strings.TrimPrefix(u.Path, "/")
+	if len(export) == 0 {
+		err = fmt.Errorf("no export given, expected nbd://host[:port]/export")
+	}
+
+	return
+}
+
+// Connected reports whether the given NBD device is in use, the kernel
+// exposes the pid of the serving process for connected devices only
+func Connected(devicePath string) bool {
+	return util.FileExists(path.Join("/sys/block", path.Base(devicePath), "pid"))
+}
+
+// ConnectFree calls connect with the free NBD devices of the host in order,
+// until one of them connects successfully, and returns the path of that device.
+// The NBD tools refuse to connect to a device that is in use, so if another
+// process grabbed a device in between the check and the connect, the next
+// device is tried.
+func ConnectFree(connect func(devicePath string) error) (string, error) {
+	devices, err := filepath.Glob("/sys/block/nbd*")
+	if err != nil {
+		return "", err
+	}
+	if len(devices) == 0 {
+		return "", fmt.Errorf("no NBD devices found, is the nbd kernel module loaded?")
+	}
+	sort.Strings(devices)
+
+	for _, device := range devices {
+		candidate := path.Join("/dev", path.Base(device))
+		if Connected(candidate) {
+			continue
+		}
+
+		if err = connect(candidate); err == nil {
+			return candidate, nil
+		}
+		log.Debugf("Failed to connect NBD device %q: %v", candidate, err)
+	}
+
+	if err == nil {
+		err = fmt.Errorf("all NBD devices are in use")
+	}
+
+	return "", err
+}
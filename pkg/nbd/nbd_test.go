@@ -0,0 +1,70 @@
+package nbd
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		host   string
+		port   string
+		export string
+		err    string
+	}{
+		{
+			name:   "default port",
+			url:    "nbd://storage.example.com/data",
+			host:   "storage.example.com",
+			port:   DefaultPort,
+			export: "data",
+		},
+		{
+			name:   "custom port",
+			url:    "nbd://10.0.0.5:10900/disks/data",
+			host:   "10.0.0.5",
+			port:   "10900",
+			export: "disks/data",
+		},
+		{
+			name:   "IPv6 host",
+			url:    "nbd://[fd00::5]:10900/data",
+			host:   "fd00::5",
+			port:   "10900",
+			export: "data",
+		},
+		{
+			name: "other scheme",
+			url:  "iscsi://storage.example.com/data",
+			err:  `unsupported scheme "iscsi", expected nbd://host[:port]/export`,
+		},
+		{
+			name: "no host",
+			url:  "nbd:///data",
+			err:  "no host given, expected nbd://host[:port]/export",
+		},
+		{
+			name: "no export",
+			url:  "nbd://storage.example.com/",
+			err:  "no export given, expected nbd://host[:port]/export",
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			host, port, export, err := ParseURL(rt.url)
+			if len(rt.err) > 0 {
+				assert.Error(t, err, rt.err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, host, rt.host)
+			assert.Equal(t, port, rt.port)
+			assert.Equal(t, export, rt.export)
+		})
+	}
+}
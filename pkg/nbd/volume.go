@@ -0,0 +1,118 @@
+package nbd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// ConnectVolume connects the remote disk of the given NBD volume of the VM to a free
+// NBD device on the host, and returns the path of that device. If the volume is
+// still connected from an earlier start of the VM, that connection is reused.
+func ConnectVolume(vm *api.VM, volume *api.Volume) (string, error) {
+	if volume.NBD == nil {
+		return "", fmt.Errorf("volume %q of VM %q is not an NBD volume", volume.Name, vm.GetUID())
+	}
+
+	// Return if the volume is already connected
+	if devicePath, err := VolumeDevice(vm, volume.Name); err == nil && Connected(devicePath) {
+		return devicePath, nil
+	}
+
+	host, port, export, err := ParseURL(volume.NBD.URL)
+	if err != nil {
+		return "", err
+	}
+
+	devicePath, err := ConnectFree(func(devicePath string) error {
+		_, err := util.ExecuteCommand("nbd-client", connectArgs(host, port, export, devicePath)...)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to connect NBD volume %q of VM %q: %v", volume.Name, vm.GetUID(), err)
+	}
+
+	// Record the device, ignite-spawn and later invocations of ignite need to find it
+	if err := os.MkdirAll(volumeDir(vm), constants.DATA_DIR_PERM); err != nil {
+		return "", err
+	}
+
+	if err := ioutil.WriteFile(path.Join(volumeDir(vm), volume.Name), []byte(devicePath), 0644); err != nil {
+		return "", err
+	}
+
+	log.Debugf("Connected NBD volume %q of VM %q to %q", volume.Name, vm.GetUID(), devicePath)
+	return devicePath, nil
+}
+
+// connectArgs returns the arguments of nbd-client connecting the export to the NBD device.
+// The client keeps running in the background, with persist (-p) it reconnects to the
+// server if the connection drops, the I/O of the VM blocks until then. Persist is only
+// supported by the ioctl interface, so disable netlink (-L).
+func connectArgs(host, port, export, devicePath string) []string {
+	return []string{
+		"-N", export,
+		"-p",
+		"-L",
+		host, port, devicePath,
+	}
+}
+
+// DisconnectVolume disconnects the given NBD volume of the VM from the host.
+// The client stops reconnecting to the server after this.
+func DisconnectVolume(vm *api.VM, name string) error {
+	devicePath, err := VolumeDevice(vm, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // The volume isn't connected
+		}
+		return err
+	}
+
+	if Connected(devicePath) {
+		if _, err := util.ExecuteCommand("nbd-client", "-d", devicePath); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(path.Join(volumeDir(vm), name))
+}
+
+// DisconnectVolumes disconnects all NBD volumes of the VM from the host
+func DisconnectVolumes(vm *api.VM) error {
+	for _, volume := range vm.Spec.Storage.Volumes {
+		if volume.NBD == nil {
+			continue
+		}
+
+		if err := DisconnectVolume(vm, volume.Name); err != nil {
+			return fmt.Errorf("failed to disconnect NBD volume %q of VM %q: %v", volume.Name, vm.GetUID(), err)
+		}
+	}
+
+	return nil
+}
+
+// VolumeDevice returns the path of the NBD device the given
+// volume of the VM has been connected to by ConnectVolume
+func VolumeDevice(vm *api.VM, name string) (string, error) {
+	data, err := ioutil.ReadFile(path.Join(volumeDir(vm), name))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// volumeDir returns the directory recording the NBD devices of the VM's volumes
+func volumeDir(vm *api.VM) string {
+	return path.Join(vm.ObjectPath(), constants.NBD_VOLUME_DIR)
+}
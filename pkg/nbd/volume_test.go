@@ -0,0 +1,41 @@
+package nbd
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestConnectArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		host       string
+		port       string
+		export     string
+		devicePath string
+		expected   []string
+	}{
+		{
+			name:       "named export",
+			host:       "storage.example.com",
+			port:       DefaultPort,
+			export:     "data",
+			devicePath: "/dev/nbd0",
+			expected:   []string{"-N", "data", "-p", "-L", "storage.example.com", "10809", "/dev/nbd0"},
+		},
+		{
+			name:       "nested export on a custom port",
+			host:       "fd00::5",
+			port:       "10900",
+			export:     "disks/data",
+			devicePath: "/dev/nbd12",
+			expected:   []string{"-N", "disks/data", "-p", "-L", "fd00::5", "10900", "/dev/nbd12"},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.DeepEqual(t, connectArgs(rt.host, rt.port, rt.export, rt.devicePath), rt.expected)
+		})
+	}
+}
@@ -9,97 +9,177 @@ package openapi
 
 import (
 	spec "github.com/go-openapi/spec"
+	v1alpha1 "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	common "k8s.io/kube-openapi/pkg/common"
 )
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.BlockDeviceVolume": schema_pkg_apis_ignite_v1alpha2_BlockDeviceVolume(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.FileMapping":       schema_pkg_apis_ignite_v1alpha2_FileMapping(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Image":             schema_pkg_apis_ignite_v1alpha2_Image(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.ImageSpec":         schema_pkg_apis_ignite_v1alpha2_ImageSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.ImageStatus":       schema_pkg_apis_ignite_v1alpha2_ImageStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Kernel":            schema_pkg_apis_ignite_v1alpha2_Kernel(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.KernelSpec":        schema_pkg_apis_ignite_v1alpha2_KernelSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.KernelStatus":      schema_pkg_apis_ignite_v1alpha2_KernelStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.OCIImageSource":    schema_pkg_apis_ignite_v1alpha2_OCIImageSource(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Pool":              schema_pkg_apis_ignite_v1alpha2_Pool(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.PoolDevice":        schema_pkg_apis_ignite_v1alpha2_PoolDevice(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.PoolSpec":          schema_pkg_apis_ignite_v1alpha2_PoolSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.PoolStatus":        schema_pkg_apis_ignite_v1alpha2_PoolStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Runtime":           schema_pkg_apis_ignite_v1alpha2_Runtime(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.SSH":               schema_pkg_apis_ignite_v1alpha2_SSH(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VM":                schema_pkg_apis_ignite_v1alpha2_VM(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMImageSpec":       schema_pkg_apis_ignite_v1alpha2_VMImageSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMKernelSpec":      schema_pkg_apis_ignite_v1alpha2_VMKernelSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMNetworkSpec":     schema_pkg_apis_ignite_v1alpha2_VMNetworkSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMSandboxSpec":     schema_pkg_apis_ignite_v1alpha2_VMSandboxSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMSpec":            schema_pkg_apis_ignite_v1alpha2_VMSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMStatus":          schema_pkg_apis_ignite_v1alpha2_VMStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMStorageSpec":     schema_pkg_apis_ignite_v1alpha2_VMStorageSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Volume":            schema_pkg_apis_ignite_v1alpha2_Volume(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VolumeMount":       schema_pkg_apis_ignite_v1alpha2_VolumeMount(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.BlockDeviceVolume": schema_pkg_apis_ignite_v1alpha3_BlockDeviceVolume(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Configuration":     schema_pkg_apis_ignite_v1alpha3_Configuration(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.ConfigurationSpec": schema_pkg_apis_ignite_v1alpha3_ConfigurationSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.FileMapping":       schema_pkg_apis_ignite_v1alpha3_FileMapping(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Image":             schema_pkg_apis_ignite_v1alpha3_Image(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.ImageSpec":         schema_pkg_apis_ignite_v1alpha3_ImageSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.ImageStatus":       schema_pkg_apis_ignite_v1alpha3_ImageStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Kernel":            schema_pkg_apis_ignite_v1alpha3_Kernel(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.KernelSpec":        schema_pkg_apis_ignite_v1alpha3_KernelSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.KernelStatus":      schema_pkg_apis_ignite_v1alpha3_KernelStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Network":           schema_pkg_apis_ignite_v1alpha3_Network(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.OCIImageSource":    schema_pkg_apis_ignite_v1alpha3_OCIImageSource(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Pool":              schema_pkg_apis_ignite_v1alpha3_Pool(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.PoolDevice":        schema_pkg_apis_ignite_v1alpha3_PoolDevice(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.PoolSpec":          schema_pkg_apis_ignite_v1alpha3_PoolSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.PoolStatus":        schema_pkg_apis_ignite_v1alpha3_PoolStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Runtime":           schema_pkg_apis_ignite_v1alpha3_Runtime(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.SSH":               schema_pkg_apis_ignite_v1alpha3_SSH(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VM":                schema_pkg_apis_ignite_v1alpha3_VM(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMImageSpec":       schema_pkg_apis_ignite_v1alpha3_VMImageSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMKernelSpec":      schema_pkg_apis_ignite_v1alpha3_VMKernelSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMNetworkSpec":     schema_pkg_apis_ignite_v1alpha3_VMNetworkSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMSandboxSpec":     schema_pkg_apis_ignite_v1alpha3_VMSandboxSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMSpec":            schema_pkg_apis_ignite_v1alpha3_VMSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMStatus":          schema_pkg_apis_ignite_v1alpha3_VMStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMStorageSpec":     schema_pkg_apis_ignite_v1alpha3_VMStorageSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Volume":            schema_pkg_apis_ignite_v1alpha3_Volume(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VolumeMount":       schema_pkg_apis_ignite_v1alpha3_VolumeMount(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.BlockDeviceVolume": schema_pkg_apis_ignite_v1alpha4_BlockDeviceVolume(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Configuration":     schema_pkg_apis_ignite_v1alpha4_Configuration(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ConfigurationSpec": schema_pkg_apis_ignite_v1alpha4_ConfigurationSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.FileMapping":       schema_pkg_apis_ignite_v1alpha4_FileMapping(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Image":             schema_pkg_apis_ignite_v1alpha4_Image(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ImageSpec":         schema_pkg_apis_ignite_v1alpha4_ImageSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ImageStatus":       schema_pkg_apis_ignite_v1alpha4_ImageStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Kernel":            schema_pkg_apis_ignite_v1alpha4_Kernel(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.KernelSpec":        schema_pkg_apis_ignite_v1alpha4_KernelSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.KernelStatus":      schema_pkg_apis_ignite_v1alpha4_KernelStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Network":           schema_pkg_apis_ignite_v1alpha4_Network(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.OCIImageSource":    schema_pkg_apis_ignite_v1alpha4_OCIImageSource(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Pool":              schema_pkg_apis_ignite_v1alpha4_Pool(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.PoolDevice":        schema_pkg_apis_ignite_v1alpha4_PoolDevice(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.PoolSpec":          schema_pkg_apis_ignite_v1alpha4_PoolSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.PoolStatus":        schema_pkg_apis_ignite_v1alpha4_PoolStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Runtime":           schema_pkg_apis_ignite_v1alpha4_Runtime(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.SSH":               schema_pkg_apis_ignite_v1alpha4_SSH(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VM":                schema_pkg_apis_ignite_v1alpha4_VM(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMImageSpec":       schema_pkg_apis_ignite_v1alpha4_VMImageSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMKernelSpec":      schema_pkg_apis_ignite_v1alpha4_VMKernelSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMNetworkSpec":     schema_pkg_apis_ignite_v1alpha4_VMNetworkSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSandboxSpec":     schema_pkg_apis_ignite_v1alpha4_VMSandboxSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSpec":            schema_pkg_apis_ignite_v1alpha4_VMSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMStatus":          schema_pkg_apis_ignite_v1alpha4_VMStatus(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMStorageSpec":     schema_pkg_apis_ignite_v1alpha4_VMStorageSpec(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Volume":            schema_pkg_apis_ignite_v1alpha4_Volume(ref),
-		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VolumeMount":       schema_pkg_apis_ignite_v1alpha4_VolumeMount(ref),
-		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.DMID":                schema_pkg_apis_meta_v1alpha1_DMID(ref),
-		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIContentID":        schema_pkg_apis_meta_v1alpha1_OCIContentID(ref),
-		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef":         schema_pkg_apis_meta_v1alpha1_OCIImageRef(ref),
-		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.PortMapping":         schema_pkg_apis_meta_v1alpha1_PortMapping(ref),
-		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size":                schema_pkg_apis_meta_v1alpha1_Size(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.BlockDeviceVolume":        schema_pkg_apis_ignite_v1alpha2_BlockDeviceVolume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.FileMapping":              schema_pkg_apis_ignite_v1alpha2_FileMapping(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Image":                    schema_pkg_apis_ignite_v1alpha2_Image(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.ImageSpec":                schema_pkg_apis_ignite_v1alpha2_ImageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.ImageStatus":              schema_pkg_apis_ignite_v1alpha2_ImageStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Kernel":                   schema_pkg_apis_ignite_v1alpha2_Kernel(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.KernelSpec":               schema_pkg_apis_ignite_v1alpha2_KernelSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.KernelStatus":             schema_pkg_apis_ignite_v1alpha2_KernelStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.OCIImageSource":           schema_pkg_apis_ignite_v1alpha2_OCIImageSource(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Pool":                     schema_pkg_apis_ignite_v1alpha2_Pool(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.PoolDevice":               schema_pkg_apis_ignite_v1alpha2_PoolDevice(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.PoolSpec":                 schema_pkg_apis_ignite_v1alpha2_PoolSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.PoolStatus":               schema_pkg_apis_ignite_v1alpha2_PoolStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Runtime":                  schema_pkg_apis_ignite_v1alpha2_Runtime(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.SSH":                      schema_pkg_apis_ignite_v1alpha2_SSH(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VM":                       schema_pkg_apis_ignite_v1alpha2_VM(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMImageSpec":              schema_pkg_apis_ignite_v1alpha2_VMImageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMKernelSpec":             schema_pkg_apis_ignite_v1alpha2_VMKernelSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMNetworkSpec":            schema_pkg_apis_ignite_v1alpha2_VMNetworkSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMSandboxSpec":            schema_pkg_apis_ignite_v1alpha2_VMSandboxSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMSpec":                   schema_pkg_apis_ignite_v1alpha2_VMSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMStatus":                 schema_pkg_apis_ignite_v1alpha2_VMStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VMStorageSpec":            schema_pkg_apis_ignite_v1alpha2_VMStorageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.Volume":                   schema_pkg_apis_ignite_v1alpha2_Volume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha2.VolumeMount":              schema_pkg_apis_ignite_v1alpha2_VolumeMount(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.BlockDeviceVolume":        schema_pkg_apis_ignite_v1alpha3_BlockDeviceVolume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Configuration":            schema_pkg_apis_ignite_v1alpha3_Configuration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.ConfigurationSpec":        schema_pkg_apis_ignite_v1alpha3_ConfigurationSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.FileMapping":              schema_pkg_apis_ignite_v1alpha3_FileMapping(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Image":                    schema_pkg_apis_ignite_v1alpha3_Image(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.ImageSpec":                schema_pkg_apis_ignite_v1alpha3_ImageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.ImageStatus":              schema_pkg_apis_ignite_v1alpha3_ImageStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Kernel":                   schema_pkg_apis_ignite_v1alpha3_Kernel(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.KernelSpec":               schema_pkg_apis_ignite_v1alpha3_KernelSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.KernelStatus":             schema_pkg_apis_ignite_v1alpha3_KernelStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Network":                  schema_pkg_apis_ignite_v1alpha3_Network(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.OCIImageSource":           schema_pkg_apis_ignite_v1alpha3_OCIImageSource(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Pool":                     schema_pkg_apis_ignite_v1alpha3_Pool(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.PoolDevice":               schema_pkg_apis_ignite_v1alpha3_PoolDevice(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.PoolSpec":                 schema_pkg_apis_ignite_v1alpha3_PoolSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.PoolStatus":               schema_pkg_apis_ignite_v1alpha3_PoolStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Runtime":                  schema_pkg_apis_ignite_v1alpha3_Runtime(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.SSH":                      schema_pkg_apis_ignite_v1alpha3_SSH(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VM":                       schema_pkg_apis_ignite_v1alpha3_VM(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMImageSpec":              schema_pkg_apis_ignite_v1alpha3_VMImageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMKernelSpec":             schema_pkg_apis_ignite_v1alpha3_VMKernelSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMNetworkSpec":            schema_pkg_apis_ignite_v1alpha3_VMNetworkSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMSandboxSpec":            schema_pkg_apis_ignite_v1alpha3_VMSandboxSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMSpec":                   schema_pkg_apis_ignite_v1alpha3_VMSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMStatus":                 schema_pkg_apis_ignite_v1alpha3_VMStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VMStorageSpec":            schema_pkg_apis_ignite_v1alpha3_VMStorageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.Volume":                   schema_pkg_apis_ignite_v1alpha3_Volume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha3.VolumeMount":              schema_pkg_apis_ignite_v1alpha3_VolumeMount(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.BlockDeviceVolume":        schema_pkg_apis_ignite_v1alpha4_BlockDeviceVolume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Configuration":            schema_pkg_apis_ignite_v1alpha4_Configuration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ConfigurationSpec":        schema_pkg_apis_ignite_v1alpha4_ConfigurationSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ConsoleLogConfiguration":  schema_pkg_apis_ignite_v1alpha4_ConsoleLogConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.FileMapping":              schema_pkg_apis_ignite_v1alpha4_FileMapping(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Image":                    schema_pkg_apis_ignite_v1alpha4_Image(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ImageSpec":                schema_pkg_apis_ignite_v1alpha4_ImageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ImageStatus":              schema_pkg_apis_ignite_v1alpha4_ImageStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Kernel":                   schema_pkg_apis_ignite_v1alpha4_Kernel(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.KernelSpec":               schema_pkg_apis_ignite_v1alpha4_KernelSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.KernelStatus":             schema_pkg_apis_ignite_v1alpha4_KernelStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.LVMConfiguration":         schema_pkg_apis_ignite_v1alpha4_LVMConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.NBDVolume":                schema_pkg_apis_ignite_v1alpha4_NBDVolume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Network":                  schema_pkg_apis_ignite_v1alpha4_Network(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.OCIImageSource":           schema_pkg_apis_ignite_v1alpha4_OCIImageSource(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.OverlayStatus":            schema_pkg_apis_ignite_v1alpha4_OverlayStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Pool":                     schema_pkg_apis_ignite_v1alpha4_Pool(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.PoolDevice":               schema_pkg_apis_ignite_v1alpha4_PoolDevice(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.PoolSpec":                 schema_pkg_apis_ignite_v1alpha4_PoolSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.PoolStatus":               schema_pkg_apis_ignite_v1alpha4_PoolStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.RBDConfiguration":         schema_pkg_apis_ignite_v1alpha4_RBDConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Runtime":                  schema_pkg_apis_ignite_v1alpha4_Runtime(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.SSH":                      schema_pkg_apis_ignite_v1alpha4_SSH(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.TmpfsVolume":              schema_pkg_apis_ignite_v1alpha4_TmpfsVolume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VM":                       schema_pkg_apis_ignite_v1alpha4_VM(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMBackupSpec":             schema_pkg_apis_ignite_v1alpha4_VMBackupSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMExitStatus":             schema_pkg_apis_ignite_v1alpha4_VMExitStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMImageSpec":              schema_pkg_apis_ignite_v1alpha4_VMImageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMKernelSpec":             schema_pkg_apis_ignite_v1alpha4_VMKernelSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMNetworkSpec":            schema_pkg_apis_ignite_v1alpha4_VMNetworkSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSandboxSpec":            schema_pkg_apis_ignite_v1alpha4_VMSandboxSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSpec":                   schema_pkg_apis_ignite_v1alpha4_VMSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMStatus":                 schema_pkg_apis_ignite_v1alpha4_VMStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMStorageSpec":            schema_pkg_apis_ignite_v1alpha4_VMStorageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMTemplate":               schema_pkg_apis_ignite_v1alpha4_VMTemplate(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Volume":                   schema_pkg_apis_ignite_v1alpha4_Volume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VolumeMount":              schema_pkg_apis_ignite_v1alpha4_VolumeMount(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ZFSConfiguration":         schema_pkg_apis_ignite_v1alpha4_ZFSConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.AuditConfiguration":       schema_pkg_apis_ignite_v1alpha5_AuditConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.BlockDeviceVolume":        schema_pkg_apis_ignite_v1alpha5_BlockDeviceVolume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.BootCacheConfiguration":   schema_pkg_apis_ignite_v1alpha5_BootCacheConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Configuration":            schema_pkg_apis_ignite_v1alpha5_Configuration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ConfigurationSpec":        schema_pkg_apis_ignite_v1alpha5_ConfigurationSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ConfinementConfiguration": schema_pkg_apis_ignite_v1alpha5_ConfinementConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ConsoleLogConfiguration":  schema_pkg_apis_ignite_v1alpha5_ConsoleLogConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.EmulationConfiguration":   schema_pkg_apis_ignite_v1alpha5_EmulationConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.EventsConfiguration":      schema_pkg_apis_ignite_v1alpha5_EventsConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ExecProbe":                schema_pkg_apis_ignite_v1alpha5_ExecProbe(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.FileMapping":              schema_pkg_apis_ignite_v1alpha5_FileMapping(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.GitOpsConfiguration":      schema_pkg_apis_ignite_v1alpha5_GitOpsConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.GitOpsEnvironment":        schema_pkg_apis_ignite_v1alpha5_GitOpsEnvironment(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.GitOpsSyncPolicy":         schema_pkg_apis_ignite_v1alpha5_GitOpsSyncPolicy(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.HTTPProbe":                schema_pkg_apis_ignite_v1alpha5_HTTPProbe(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Image":                    schema_pkg_apis_ignite_v1alpha5_Image(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageImportConfiguration": schema_pkg_apis_ignite_v1alpha5_ImageImportConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageSBOM":                schema_pkg_apis_ignite_v1alpha5_ImageSBOM(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageScanConfiguration":   schema_pkg_apis_ignite_v1alpha5_ImageScanConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageScanStatus":          schema_pkg_apis_ignite_v1alpha5_ImageScanStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageSpec":                schema_pkg_apis_ignite_v1alpha5_ImageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageStatus":              schema_pkg_apis_ignite_v1alpha5_ImageStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Kernel":                   schema_pkg_apis_ignite_v1alpha5_Kernel(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.KernelSpec":               schema_pkg_apis_ignite_v1alpha5_KernelSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.KernelStatus":             schema_pkg_apis_ignite_v1alpha5_KernelStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.LVMConfiguration":         schema_pkg_apis_ignite_v1alpha5_LVMConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.NBDVolume":                schema_pkg_apis_ignite_v1alpha5_NBDVolume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Network":                  schema_pkg_apis_ignite_v1alpha5_Network(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OCIImageSource":           schema_pkg_apis_ignite_v1alpha5_OCIImageSource(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OverlayStatus":            schema_pkg_apis_ignite_v1alpha5_OverlayStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PCIDevice":                schema_pkg_apis_ignite_v1alpha5_PCIDevice(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PolicyConfiguration":      schema_pkg_apis_ignite_v1alpha5_PolicyConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Pool":                     schema_pkg_apis_ignite_v1alpha5_Pool(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PoolDevice":               schema_pkg_apis_ignite_v1alpha5_PoolDevice(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PoolSpec":                 schema_pkg_apis_ignite_v1alpha5_PoolSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PoolStatus":               schema_pkg_apis_ignite_v1alpha5_PoolStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.RBDConfiguration":         schema_pkg_apis_ignite_v1alpha5_RBDConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.RegoPolicy":               schema_pkg_apis_ignite_v1alpha5_RegoPolicy(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Runtime":                  schema_pkg_apis_ignite_v1alpha5_Runtime(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.SBOMConfiguration":        schema_pkg_apis_ignite_v1alpha5_SBOMConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.SSH":                      schema_pkg_apis_ignite_v1alpha5_SSH(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.TCPProbe":                 schema_pkg_apis_ignite_v1alpha5_TCPProbe(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.TmpfsVolume":              schema_pkg_apis_ignite_v1alpha5_TmpfsVolume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VM":                       schema_pkg_apis_ignite_v1alpha5_VM(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMBackupSpec":             schema_pkg_apis_ignite_v1alpha5_VMBackupSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMCondition":              schema_pkg_apis_ignite_v1alpha5_VMCondition(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMConfinementStatus":      schema_pkg_apis_ignite_v1alpha5_VMConfinementStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMExitStatus":             schema_pkg_apis_ignite_v1alpha5_VMExitStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMImageSpec":              schema_pkg_apis_ignite_v1alpha5_VMImageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMKernelSpec":             schema_pkg_apis_ignite_v1alpha5_VMKernelSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMMemorySpec":             schema_pkg_apis_ignite_v1alpha5_VMMemorySpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMNetworkSpec":            schema_pkg_apis_ignite_v1alpha5_VMNetworkSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMProbe":                  schema_pkg_apis_ignite_v1alpha5_VMProbe(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSandboxSpec":            schema_pkg_apis_ignite_v1alpha5_VMSandboxSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSeccompSpec":            schema_pkg_apis_ignite_v1alpha5_VMSeccompSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSeccompStatus":          schema_pkg_apis_ignite_v1alpha5_VMSeccompStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSecret":                 schema_pkg_apis_ignite_v1alpha5_VMSecret(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSpec":                   schema_pkg_apis_ignite_v1alpha5_VMSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMStatus":                 schema_pkg_apis_ignite_v1alpha5_VMStatus(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMStorageSpec":            schema_pkg_apis_ignite_v1alpha5_VMStorageSpec(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMTemplate":               schema_pkg_apis_ignite_v1alpha5_VMTemplate(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMUser":                   schema_pkg_apis_ignite_v1alpha5_VMUser(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VaultConfiguration":       schema_pkg_apis_ignite_v1alpha5_VaultConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Volume":                   schema_pkg_apis_ignite_v1alpha5_Volume(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VolumeMount":              schema_pkg_apis_ignite_v1alpha5_VolumeMount(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Vulnerability":            schema_pkg_apis_ignite_v1alpha5_Vulnerability(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VulnerabilitySummary":     schema_pkg_apis_ignite_v1alpha5_VulnerabilitySummary(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.WebhookConfiguration":     schema_pkg_apis_ignite_v1alpha5_WebhookConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ZFSConfiguration":         schema_pkg_apis_ignite_v1alpha5_ZFSConfiguration(ref),
+		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.DMID":                       schema_pkg_apis_meta_v1alpha1_DMID(ref),
+		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIContentID":               schema_pkg_apis_meta_v1alpha1_OCIContentID(ref),
+		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef":                schema_pkg_apis_meta_v1alpha1_OCIImageRef(ref),
+		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.PortMapping":                schema_pkg_apis_meta_v1alpha1_PortMapping(ref),
+		"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size":                       schema_pkg_apis_meta_v1alpha1_Size(ref),
 	}
 }
 
@@ -1962,11 +2042,84 @@ func schema_pkg_apis_ignite_v1alpha4_ConfigurationSpec(ref common.ReferenceCallb
 							Format: "",
 						},
 					},
+					"snapshotter": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lvm": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.LVMConfiguration"),
+						},
+					},
+					"zfs": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ZFSConfiguration"),
+						},
+					},
+					"rbd": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.RBDConfiguration"),
+						},
+					},
+					"consoleLog": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ConsoleLogConfiguration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ConsoleLogConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.LVMConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.RBDConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.ZFSConfiguration"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha4_ConsoleLogConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConsoleLogConfiguration configures how the console output of the VMs is recorded to the console log in the VM directory, and how the console log is rotated",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxSize is the size at which the console log is rotated Default: 10 MB",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"maxAge": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxAge is the age of the oldest line at which the console log is rotated Default: unset, the console log is only rotated by size",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"maxFiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxFiles is the number of rotated console logs kept, older ones are removed Default: 1",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"compress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Compress gzips the rotated console logs, except the most recent one",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSpec"},
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
@@ -2184,6 +2337,55 @@ func schema_pkg_apis_ignite_v1alpha4_KernelStatus(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_ignite_v1alpha4_LVMConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LVMConfiguration configures where the lvm snapshotter allocates VM disks",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"volumeGroup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeGroup is the LVM volume group the logical volumes are created in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"thinPool": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThinPool optionally names a thin pool in the volume group. If set, images are imported as thin volumes, and VM disks are thin snapshots of them.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha4_NBDVolume(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NBDVolume defines a remote disk served over the network block device protocol. It's connected on the host when the VM starts, and reconnected if the connection drops.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL of the export, in the nbd://host[:port]/export format",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_ignite_v1alpha4_Network(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2247,6 +2449,36 @@ func schema_pkg_apis_ignite_v1alpha4_OCIImageSource(ref common.ReferenceCallback
 	}
 }
 
+func schema_pkg_apis_ignite_v1alpha4_OverlayStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OverlayStatus describes the host disk usage of the VM's writable overlay",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"usage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Usage is the amount of host disk space allocated by the overlay",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"nearLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NearLimit is set when Usage has reached the warning threshold of the VM's overlay size limit",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"usage"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
 func schema_pkg_apis_ignite_v1alpha4_Pool(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2407,6 +2639,33 @@ func schema_pkg_apis_ignite_v1alpha4_PoolStatus(ref common.ReferenceCallback) co
 	}
 }
 
+func schema_pkg_apis_ignite_v1alpha4_RBDConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RBDConfiguration configures where the rbd snapshotter creates its Ceph RBD images",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pool": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pool is the Ceph pool the base images and the VM images are created in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"user": {
+						SchemaProps: spec.SchemaProps{
+							Description: "User is the Ceph user to authenticate as, defaults to the Ceph default (admin)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_ignite_v1alpha4_Runtime(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2446,6 +2705,28 @@ func schema_pkg_apis_ignite_v1alpha4_SSH(ref common.ReferenceCallback) common.Op
 	}
 }
 
+func schema_pkg_apis_ignite_v1alpha4_TmpfsVolume(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TmpfsVolume defines a RAM-backed scratch volume inside the VM. The volume is created empty when the VM boots and its contents are discarded when the VM stops, nothing is written to the host disk.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size limits the size of the tmpfs, it counts against the VM's memory Default: unset, the guest kernel default (half of the VM's memory) is used",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
 func schema_pkg_apis_ignite_v1alpha4_VM(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2487,6 +2768,81 @@ func schema_pkg_apis_ignite_v1alpha4_VM(ref common.ReferenceCallback) common.Ope
 	}
 }
 
+func schema_pkg_apis_ignite_v1alpha4_VMBackupSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMBackupSpec defines when the disk of a VM is backed up, where to, and how many backups to keep",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"schedule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Schedule is a cron expression in the \"minute hour day-of-month month day-of-week\" format",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"retention": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Retention is the number of backups to keep, older backups are removed Default: unset, all backups are kept",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"destination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destination is a local directory or an s3://bucket[/prefix] URL, the backups of the VM are stored in a subdirectory named by its UID",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"schedule", "destination"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha4_VMExitStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMExitStatus describes how a VM stopped",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"time": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time is when the VM stopped",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/libgitops/pkg/runtime.Time"),
+						},
+					},
+					"exitCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExitCode is the exit code of Firecracker, it's non-zero if the VM failed",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"requested": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Requested is set if the VM was stopped by ignite, e.g. by \"ignite stop\". The restart policy doesn't apply to requested stops.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"time", "exitCode"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/libgitops/pkg/runtime.Time"},
+	}
+}
+
 func schema_pkg_apis_ignite_v1alpha4_VMImageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2635,6 +2991,13 @@ func schema_pkg_apis_ignite_v1alpha4_VMSpec(ref common.ReferenceCallback) common
 							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
 						},
 					},
+					"overlaySizeLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OverlaySizeLimit caps how much host disk space the VM's writable overlay may allocate, independently of DiskSize. Writes beyond the limit fail inside the VM. Unset means the overlay may grow up to DiskSize.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
 					"network": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Currently both will show in the JSON output as empty arrays. Making them pointers requires plenty of nil checks (as their contents are accessed directly) and is very risky for stability. APIMachinery potentially has a solution.",
@@ -2668,12 +3031,47 @@ func schema_pkg_apis_ignite_v1alpha4_VMSpec(ref common.ReferenceCallback) common
 							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.SSH"),
 						},
 					},
+					"backup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backup defines a policy for periodic backups of the VM's disk, run by ignited nil here means the VM isn't backed up",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMBackupSpec"),
+						},
+					},
+					"autostart": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Autostart makes the VM start automatically when the host boots, either by ignited when it starts up, or by \"ignite vm autostart\"",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"autostartAfter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AutostartAfter lists the names or IDs of the VMs that need to be running before the VM is autostarted. They're started first, even if they don't set autostart.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"restartPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RestartPolicy determines whether ignited restarts the VM when it stops without being stopped by ignite, e.g. when it crashes or shuts itself down Default: unset, which means never",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"image", "sandbox", "kernel", "cpus", "memory", "diskSize"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.FileMapping", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.SSH", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMImageSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMKernelSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMNetworkSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSandboxSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMStorageSpec", "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.FileMapping", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.SSH", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMBackupSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMImageSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMKernelSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMNetworkSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSandboxSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMStorageSpec", "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
 	}
 }
 
@@ -2691,6 +3089,12 @@ func schema_pkg_apis_ignite_v1alpha4_VMStatus(ref common.ReferenceCallback) comm
 							Format:  "",
 						},
 					},
+					"paused": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
 					"runtime": {
 						SchemaProps: spec.SchemaProps{
 							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Runtime"),
@@ -2725,12 +3129,36 @@ func schema_pkg_apis_ignite_v1alpha4_VMStatus(ref common.ReferenceCallback) comm
 							Format:  "",
 						},
 					},
+					"overlay": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.OverlayStatus"),
+						},
+					},
+					"snapshotter": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"restartCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RestartCount is the number of times the VM has been restarted by its restart policy",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"lastExit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastExit describes how the VM stopped the last time",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMExitStatus"),
+						},
+					},
 				},
 				Required: []string{"running", "image", "kernel", "idPrefix"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Network", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.OCIImageSource", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Runtime", "github.com/weaveworks/libgitops/pkg/runtime.Time"},
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Network", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.OCIImageSource", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.OverlayStatus", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.Runtime", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMExitStatus", "github.com/weaveworks/libgitops/pkg/runtime.Time"},
 	}
 }
 
@@ -2775,39 +3203,2694 @@ func schema_pkg_apis_ignite_v1alpha4_VMStorageSpec(ref common.ReferenceCallback)
 	}
 }
 
-func schema_pkg_apis_ignite_v1alpha4_Volume(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_ignite_v1alpha4_VMTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Volume defines named storage volume",
+				Description: "VMTemplate is a reusable VM configuration. VMs created from a template use its spec as their base configuration, which can be overridden per VM. These files are stored in /var/lib/firecracker/vmtemplate/{template-id}/metadata.json",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"TypeMeta": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"),
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSpec"),
+						},
+					},
+				},
+				Required: []string{"TypeMeta", "metadata", "spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.VMSpec", "github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta", "k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha4_Volume(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Volume defines named storage volume",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
 						},
 					},
 					"blockDevice": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.BlockDeviceVolume"),
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.BlockDeviceVolume"),
+						},
+					},
+					"tmpfs": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.TmpfsVolume"),
+						},
+					},
+					"nbd": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.NBDVolume"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.BlockDeviceVolume", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.NBDVolume", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.TmpfsVolume"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha4_VolumeMount(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VolumeMount defines the mount point for a named volume inside a VM",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"mountPath": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+				},
+				Required: []string{"name", "mountPath"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha4_ZFSConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ZFSConfiguration configures where the zfs snapshotter creates its volumes",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"dataset": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Dataset is the parent dataset for the image zvols and the VM clones, for example \"tank/ignite\". Properties like compression are inherited from it.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_AuditConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AuditConfiguration configures where the audit log of the create, start, stop, delete, import and other mutating operations is recorded",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the file the audit log is appended to Default: /var/lib/firecracker/audit.log",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"syslog": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Syslog sends the audit log to the local syslog daemon instead of the file",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_BlockDeviceVolume(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BlockDeviceVolume defines a block device on the host",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+				},
+				Required: []string{"path"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_BootCacheConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BootCacheConfiguration configures the boot cache, which snapshots the first VM of an image, kernel and size once it has booted, and restores the next VMs of the same kind from the snapshot instead of booting them",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled makes \"ignite run\" and \"ignite start\" use the boot cache for the VMs that haven't been started before. The images need to be imported with the guest agent.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_Configuration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Configuration represents the ignite runtime configuration.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"TypeMeta": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"),
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ConfigurationSpec"),
+						},
+					},
+				},
+				Required: []string{"TypeMeta", "metadata", "spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ConfigurationSpec", "github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta", "k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ConfigurationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConfigurationSpec defines the ignite configuration.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"runtime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"networkPlugin": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"vmDefaults": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSpec"),
+						},
+					},
+					"idPrefix": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"registryConfigDir": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"snapshotter": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lvm": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.LVMConfiguration"),
+						},
+					},
+					"zfs": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ZFSConfiguration"),
+						},
+					},
+					"rbd": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.RBDConfiguration"),
+						},
+					},
+					"consoleLog": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ConsoleLogConfiguration"),
+						},
+					},
+					"events": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.EventsConfiguration"),
+						},
+					},
+					"audit": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.AuditConfiguration"),
+						},
+					},
+					"gitops": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.GitOpsConfiguration"),
+						},
+					},
+					"imageScan": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageScanConfiguration"),
+						},
+					},
+					"sbom": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.SBOMConfiguration"),
+						},
+					},
+					"policy": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PolicyConfiguration"),
+						},
+					},
+					"confinement": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ConfinementConfiguration"),
+						},
+					},
+					"vault": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VaultConfiguration"),
+						},
+					},
+					"imageImport": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageImportConfiguration"),
+						},
+					},
+					"bootCache": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.BootCacheConfiguration"),
+						},
+					},
+					"emulation": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.EmulationConfiguration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.AuditConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.BootCacheConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ConfinementConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ConsoleLogConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.EmulationConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.EventsConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.GitOpsConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageImportConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageScanConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.LVMConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PolicyConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.RBDConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.SBOMConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VaultConfiguration", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ZFSConfiguration"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ConfinementConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConfinementConfiguration confines the sandbox containers of the VMs, the ignite-spawn and Firecracker processes, with a Linux security module. Every VM gets its own SELinux categories or AppArmor profile, so a VM escaping Firecracker can't access the disks and files of the other VMs.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"selinux": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SELinux runs every sandbox with its own MCS categories, sVirt-style, and labels the directory and disks of the VM with them",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"selinuxProcessType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SELinuxProcessType is the SELinux type of the sandbox processes Default: container_t",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"selinuxFileType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SELinuxFileType is the SELinux type the directories and disks of the VMs are labeled with Default: container_file_t",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"appArmor": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AppArmor confines every sandbox with an AppArmor profile generated for the VM, which only allows writing its own directory and disks",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ConsoleLogConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConsoleLogConfiguration configures how the console output of the VMs is recorded to the console log in the VM directory, and how the console log is rotated",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxSize is the size at which the console log is rotated Default: 10 MB",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"maxAge": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxAge is the age of the oldest line at which the console log is rotated Default: unset, the console log is only rotated by size",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"maxFiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxFiles is the number of rotated console logs kept, older ones are removed Default: 1",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"compress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Compress gzips the rotated console logs, except the most recent one",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_EmulationConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "EmulationConfiguration configures booting the VMs with the TCG emulation of QEMU instead of Firecracker, for hosts without KVM like nested CI runners. Emulated VMs run a lot slower, and without the features of the Firecracker API, e.g. migration, the boot cache, pausing and the guest agent.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"mode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mode is when the VMs are emulated, never, auto or always Default: never",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_EventsConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "EventsConfiguration configures where ignited sends the events about VMs, images and kernels. The events are always appended to the event log in the data directory.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"webhooks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Webhooks are the HTTP endpoints every event is POSTed to as JSON",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.WebhookConfiguration"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.WebhookConfiguration"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ExecProbe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ExecProbe succeeds if the command exits with code 0. It's run through the guest agent, so the image needs to be imported with it.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"command": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"command"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_FileMapping(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FileMapping defines mappings between files on the host and VM",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"hostPath": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"vmPath": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+				},
+				Required: []string{"hostPath", "vmPath"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_GitOpsConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GitOpsConfiguration configures \"ignited gitops\"",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"environments": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Environments map branches and directories of the repository to environments, which are synced separately, e.g. staging and production VM fleets Default: unset, the branch given to \"ignited gitops\" is synced as a whole",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.GitOpsEnvironment"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.GitOpsEnvironment"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_GitOpsEnvironment(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GitOpsEnvironment is a branch and directory of the gitops repository that's synced with its own sync policy",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name identifies the environment, it's set as the ignite.weave.works/environment label of its VMs",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"branch": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Branch is the branch the manifests of the environment are on Default: the branch given to \"ignited gitops\"",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the directory of the manifests in the repository, relative to its root Default: unset, the whole repository",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"labels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels are set on the VMs of the environment, in addition to the environment label",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"syncPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyncPolicy configures how the environment is synced",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.GitOpsSyncPolicy"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.GitOpsSyncPolicy"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_GitOpsSyncPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GitOpsSyncPolicy configures how a gitops environment is synced",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"interval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interval is how often the branch is pulled, and the status of the VMs pushed Default: the interval given to \"ignited gitops\"",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"dryRun": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DryRun only logs the VMs that would be created, started, stopped or removed Default: false",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"driftInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DriftInterval is how often the VMs are compared to their manifests, and corrected if they drifted from them, e.g. restarted if they stopped. Zero disables drift detection. Default: the drift interval given to \"ignited gitops\"",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"prune": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Prune determines what happens to the VMs whose manifests are removed Default: the prune policy given to \"ignited gitops\"",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pruneGracePeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PruneGracePeriod is how long the VMs are kept after their manifests are removed, they aren't pruned if their manifests come back meanwhile, e.g. when they're moved. Default: the prune grace period given to \"ignited gitops\"",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_HTTPProbe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "HTTPProbe succeeds if the GET request to the port and path of the VM returns a status code from 200 to 399",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the path of the request, / by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"https": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HTTPS sends the request over TLS, without verifying the certificate of the VM",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"port"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_Image(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Image represents a cached OCI image ready to be used with Ignite",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"TypeMeta": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"),
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "runtime.ObjectMeta is also embedded into the struct, and defines the human-readable name, and the machine-readable ID Name is available at the .metadata.name JSON path ID is available at the .metadata.uid JSON path (the Go type is k8s.io/apimachinery/pkg/types.UID, which is only a typed string)",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageStatus"),
+						},
+					},
+				},
+				Required: []string{"TypeMeta", "metadata", "spec", "status"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageStatus", "github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta", "k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ImageImportConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageImportConfiguration configures how the filesystems of the images are built when they're imported",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"mode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mode is how the files of the image are written to its ext4 filesystem, mount or offline Default: mount",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"concurrency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Concurrency is how many images and kernels are imported at once by this process, e.g. the image and kernel of a VM on a host that has neither yet Default: 3",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"lazy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Lazy imports images with eStargz layers with only the files they need to boot, read from the registry with range requests. The rest of the files are written into the disk of each VM when it's first started. Other images are imported in full.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ImageSBOM(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageSBOM describes the software bill of materials of an image, which is stored next to the image filesystem",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"format": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Format is the format of the SBOM, spdx or cyclonedx",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"time": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time is when the SBOM was generated",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/libgitops/pkg/runtime.Time"),
+						},
+					},
+				},
+				Required: []string{"format", "time"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/libgitops/pkg/runtime.Time"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ImageScanConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageScanConfiguration configures the vulnerability scan of the images when they're imported",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"scanner": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scanner is the scanner the images are scanned with, trivy or grype Default: unset, the images aren't scanned",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the scanner binary Default: the scanner looked up in $PATH",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"failOn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailOn fails the import of the images with vulnerabilities of the severity or higher: Low, Medium, High or Critical Default: unset, the import never fails because of vulnerabilities",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ImageScanStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageScanStatus contains the result of the vulnerability scan of an image",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"scanner": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scanner is the scanner that scanned the image, trivy or grype",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"time": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time is when the image was scanned",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/libgitops/pkg/runtime.Time"),
+						},
+					},
+					"summary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary counts the vulnerabilities by severity",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VulnerabilitySummary"),
+						},
+					},
+					"vulnerabilities": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Vulnerabilities are the vulnerabilities found in the packages of the image",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Vulnerability"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"scanner", "time", "summary"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Vulnerability", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VulnerabilitySummary", "github.com/weaveworks/libgitops/pkg/runtime.Time"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ImageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageSpec declares what the image contains",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"oci": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"),
+						},
+					},
+				},
+				Required: []string{"oci"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ImageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageStatus defines the status of the image",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ociSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OCISource contains the information about how this OCI image was imported",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OCIImageSource"),
+						},
+					},
+					"scan": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scan contains the vulnerabilities found in the image when it was imported",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageScanStatus"),
+						},
+					},
+					"sbom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SBOM describes the software bill of materials generated when the image was imported",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageSBOM"),
+						},
+					},
+					"lazyRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LazyRef is set for lazily imported images to the reference of the manifest, by digest, the files that aren't in the filesystem of the image are read from",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"ociSource"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageSBOM", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ImageScanStatus", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OCIImageSource"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_Kernel(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Kernel is a serializable object that caches information about imported kernels This file is stored in /var/lib/firecracker/kernels/{oci-image-digest}/metadata.json",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"TypeMeta": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"),
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "runtime.ObjectMeta is also embedded into the struct, and defines the human-readable name, and the machine-readable ID Name is available at the .metadata.name JSON path ID is available at the .metadata.uid JSON path (the Go type is k8s.io/apimachinery/pkg/types.UID, which is only a typed string)",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.KernelSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.KernelStatus"),
+						},
+					},
+				},
+				Required: []string{"TypeMeta", "metadata", "spec", "status"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.KernelSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.KernelStatus", "github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta", "k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_KernelSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KernelSpec describes the properties of a kernel",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"oci": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"),
+						},
+					},
+					"initrd": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Describe if the OCIImageRef has a initrd",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"oci", "initrd"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_KernelStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KernelStatus describes the status of a kernel",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"ociSource": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OCIImageSource"),
+						},
+					},
+					"compression": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Compression is the compression of the kernel of the kernel image, e.g. gzip for a bzImage. The uncompressed vmlinux Firecracker boots is extracted from it at import.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"version", "ociSource"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OCIImageSource"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_LVMConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LVMConfiguration configures where the lvm snapshotter allocates VM disks",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"volumeGroup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeGroup is the LVM volume group the logical volumes are created in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"thinPool": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThinPool optionally names a thin pool in the volume group. If set, images are imported as thin volumes, and VM disks are thin snapshots of them.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_NBDVolume(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NBDVolume defines a remote disk served over the network block device protocol. It's connected on the host when the VM starts, and reconnected if the connection drops.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL of the export, in the nbd://host[:port]/export format",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_Network(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Network specifies the VM's network information.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"plugin": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"ipAddresses": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "byte",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"plugin", "ipAddresses"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_OCIImageSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OCIImageSource specifies how the OCI image was imported. It is the status variant of OCIImageClaim",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID defines the source's content ID (e.g. the canonical OCI path or Docker image ID)",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIContentID"),
+						},
+					},
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size defines the size of the source in bytes",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"architecture": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Architecture is the architecture the image is built for, e.g. amd64 or arm64. For kernels, it's the architecture of the kernel file.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"format": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Format is how the image is stored, an ext4 filesystem of its files, or the disk image it contains. Kernels don't set it. Default: filesystem",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"id", "size"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIContentID", "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_OverlayStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OverlayStatus describes the host disk usage of the VM's writable overlay",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"usage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Usage is the amount of host disk space allocated by the overlay",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"nearLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NearLimit is set when Usage has reached the warning threshold of the VM's overlay size limit",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"usage"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_PCIDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PCIDevice is a host PCI device passed through into a VM",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"address": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Address is the PCI address of the device on the host, in the domain:bus:device.function format, e.g. 0000:01:00.0",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"address"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_PolicyConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PolicyConfiguration configures the policy the image and kernel imports, and the VM creations and starts are checked against. The operations the policy denies fail.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"allowedRegistries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AllowedRegistries are the registries and repositories the images and kernels may come from, in their normalized form, e.g. docker.io/weaveworks or ghcr.io Default: unset, the images and kernels may come from anywhere",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"deniedRegistries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeniedRegistries are the registries and repositories the images and kernels may not come from, even if they're allowed by AllowedRegistries, e.g. docker.io/library",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"requireDigests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequireDigests requires the images and kernels of the VMs to be pinned to a digest, e.g. weaveworks/ignite-ubuntu@sha256:..., so a tag moved to another image isn't run",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"maxCPUs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxCPUs is the most vCPUs a VM may have Default: unset, no limit",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"maxMemory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxMemory is the most memory a VM may have Default: unset, no limit",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"denyBlockDevices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DenyBlockDevices forbids the VMs to pass block devices of the host through",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"denyDevices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DenyDevices forbids the VMs to pass PCI devices of the host through with VFIO",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"rego": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rego is an Open Policy Agent policy the operations are checked against too",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.RegoPolicy"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.RegoPolicy", "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_Pool(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Pool defines device mapper pool database This file is managed by the snapshotter part of Ignite, and the file (existing as a singleton) is present at /var/lib/firecracker/snapshotter/pool.json",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"TypeMeta": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PoolSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PoolStatus"),
+						},
+					},
+				},
+				Required: []string{"TypeMeta", "spec", "status"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PoolSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PoolStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_PoolDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PoolDevice defines one device in the pool",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"parent": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.DMID"),
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type specifies the type of the contents of the device",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadataPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MetadataPath points to the JSON/YAML file with metadata about this device This is most often of the format /var/lib/firecracker/{type}/{id}/metadata.json",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"size", "parent", "type", "metadataPath"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.DMID", "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_PoolSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PoolSpec defines the Pool's specification",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"metadataSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MetadataSize specifies the size of the pool's metadata",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"dataSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DataSize specifies the size of the pool's data",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"allocationSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AllocationSize specifies the smallest size that can be allocated at a time",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"metadataPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MetadataPath points to the file where device mapper stores all metadata information Defaults to constants.SNAPSHOTTER_METADATA_PATH",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dataPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DataPath points to the backing physical device or sparse file (to be loop mounted) for the pool Defaults to constants.SNAPSHOTTER_DATA_PATH",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"metadataSize", "dataSize", "allocationSize", "metadataPath", "dataPath"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_PoolStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PoolStatus defines the Pool's current status",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"devices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The Devices array needs to contain pointers to accommodate \"holes\" in the mapping Where devices have been deleted, the pointer is nil",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PoolDevice"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"devices"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PoolDevice"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_RBDConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RBDConfiguration configures where the rbd snapshotter creates its Ceph RBD images",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pool": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pool is the Ceph pool the base images and the VM images are created in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"user": {
+						SchemaProps: spec.SchemaProps{
+							Description: "User is the Ceph user to authenticate as, defaults to the Ceph default (admin)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_RegoPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RegoPolicy is an Open Policy Agent policy written in Rego, evaluated by opa. Its input is the operation, \"image.import\", \"kernel.import\", \"vm.create\" or \"vm.start\", the OCI image imported, and the VM created or started.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the Rego file, or the directory of the Rego files, of the policy",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"query": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Query evaluates to the reasons the operation is denied for, it's allowed if there are none Default: data.ignite.deny",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"opa": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OPA is the opa binary the policy is evaluated with Default: opa looked up in $PATH",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"path"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_Runtime(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Runtime specifies the VM's runtime information",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+				},
+				Required: []string{"id", "name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_SBOMConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SBOMConfiguration configures the software bill of materials generated for the images when they're imported",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"format": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Format is the format of the SBOM, spdx or cyclonedx Default: unset, no SBOM is generated",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the syft binary the SBOM is generated with Default: syft looked up in $PATH",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_SSH(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SSH specifies different ways to connect via SSH to the VM SSH uses a custom marshaller/unmarshaller. If generate is true, it marshals to true (a JSON bool). If PublicKey is set, it marshals to that string. If AuthorizedKeys are set, it marshals to an object with the generate, publicKey and authorizedKeys fields.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"generate": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"publicKey": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"authorizedKeys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AuthorizedKeys are public keys in the authorized_keys format that are authorized for root in the VM, in addition to the generated or given key",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_TCPProbe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TCPProbe succeeds if the port of the VM accepts the connection",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+				},
+				Required: []string{"port"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_TmpfsVolume(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TmpfsVolume defines a RAM-backed scratch volume inside the VM. The volume is created empty when the VM boots and its contents are discarded when the VM stops, nothing is written to the host disk.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size limits the size of the tmpfs, it counts against the VM's memory Default: unset, the guest kernel default (half of the VM's memory) is used",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VM(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VM represents a virtual machine run by Firecracker These files are stored in /var/lib/firecracker/vm/{vm-id}/metadata.json",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"TypeMeta": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"),
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "runtime.ObjectMeta is also embedded into the struct, and defines the human-readable name, and the machine-readable ID Name is available at the .metadata.name JSON path ID is available at the .metadata.uid JSON path (the Go type is k8s.io/apimachinery/pkg/types.UID, which is only a typed string)",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMStatus"),
+						},
+					},
+				},
+				Required: []string{"TypeMeta", "metadata", "spec", "status"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMStatus", "github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta", "k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMBackupSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMBackupSpec defines when the disk of a VM is backed up, where to, and how many backups to keep",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"schedule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Schedule is a cron expression in the \"minute hour day-of-month month day-of-week\" format",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"retention": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Retention is the number of backups to keep, older backups are removed Default: unset, all backups are kept",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"destination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destination is a local directory or an s3://bucket[/prefix] URL, the backups of the VM are stored in a subdirectory named by its UID",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"schedule", "destination"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMCondition describes an aspect of the state of a VM",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastTransitionTime is when the status of the condition last changed",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/libgitops/pkg/runtime.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is a CamelCase reason for the status of the condition",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a human readable description of the reason",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "status", "lastTransitionTime"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/libgitops/pkg/runtime.Time"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMConfinementStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMConfinementStatus is the confinement applied to the sandbox of a VM",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"selinuxLabel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SELinuxLabel is the SELinux label of the sandbox processes, its MCS categories are unique to the VM and kept across restarts",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"appArmorProfile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AppArmorProfile is the name of the AppArmor profile generated for the VM",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMExitStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMExitStatus describes how a VM stopped",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"time": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time is when the VM stopped",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/libgitops/pkg/runtime.Time"),
+						},
+					},
+					"exitCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExitCode is the exit code of Firecracker, it's non-zero if the VM failed",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"requested": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Requested is set if the VM was stopped by ignite, e.g. by \"ignite stop\". The restart policy doesn't apply to requested stops.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is why the VM stopped, e.g. OOMKilled",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"time", "exitCode"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/libgitops/pkg/runtime.Time"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMImageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"oci": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"),
+						},
+					},
+				},
+				Required: []string{"oci"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMKernelSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"oci": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"),
+						},
+					},
+					"initrd": {
+						SchemaProps: spec.SchemaProps{
+							Default: false,
+							Type:    []string{"boolean"},
+							Format:  "",
+						},
+					},
+					"cmdLine": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"initrdRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InitrdRef is an OCI image with an initramfs at /boot/initrd, passed to the kernel instead of the initrd of the kernel image, e.g. for root filesystems that need the drivers or the LUKS setup of the initrd to be mounted",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"),
+						},
+					},
+				},
+				Required: []string{"oci", "initrd"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMMemorySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMMemorySpec is the memory of the VM VMMemorySpec uses a custom marshaller/unmarshaller. Without hugepages, it marshals to the size string. Otherwise it marshals to an object with the size and hugepages fields.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"hugepages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hugepages backs the memory of the VM with hugepages of the given size, which need to be reserved on the host. Unset uses regular pages.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"size"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMNetworkSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ports": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.PortMapping"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.PortMapping"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMProbe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMProbe checks whether a running VM is healthy, by connecting to a TCP port, by sending an HTTP GET request, or by running a command through the guest agent. Exactly one of TCP, HTTP and Exec is set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"tcp": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.TCPProbe"),
+						},
+					},
+					"http": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.HTTPProbe"),
+						},
+					},
+					"exec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ExecProbe"),
+						},
+					},
+					"initialDelay": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InitialDelay is how long after the VM started the probe is first run Default: 0, the probe is run right away",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"period": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Period is how often the probe is run Default: 10s",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeout is how long the probe may take before it fails Default: 1s",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"failureThreshold": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailureThreshold is how many consecutive failures make the VM unhealthy Default: 3",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"restart": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Restart kills the VM once it's unhealthy, so its restart policy restarts it",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.ExecProbe", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.HTTPProbe", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.TCPProbe", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMSandboxSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMSandboxSpec is the spec of the sandbox used for the VM.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"oci": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"),
+						},
+					},
+				},
+				Required: []string{"oci"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.OCIImageRef"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMSeccompSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMSeccompSpec configures the seccomp filter of the Firecracker process of a VM, either by level or with a custom filter",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"level": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Level is the seccomp level of Firecracker: advanced filters the syscalls by number and arguments, basic by number only, and disabled turns seccomp off Default: advanced",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"filter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Filter is the path of a custom seccomp filter on the host, compiled by the seccompiler of Firecracker, which is applied instead of the level. It needs Firecracker v0.25 or newer.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMSeccompStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMSeccompStatus is the seccomp filter applied to the Firecracker process of a VM",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"level": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Level is the seccomp level applied, if no custom filter is",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"filter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Filter is the path of the custom seccomp filter applied",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"filterDigest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FilterDigest is the sha256 digest of the custom seccomp filter applied",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMSecret(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMSecret is a secret of the guest, see VMSpec.Secrets. Its value is read from a file or an environment variable of the host, or from Vault. Exactly one of File, Env and Vault is set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the secret file in /run/ignite/secrets in the guest",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"file": {
+						SchemaProps: spec.SchemaProps{
+							Description: "File is the absolute path of the file on the host holding the secret",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"env": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Env is the environment variable holding the secret, of the ignite or ignited process starting the VM",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"vault": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Vault is the field of a Vault secret holding the secret, as <path>#<field>, e.g. secret/data/db#password. Vault is configured in the ignite configuration.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"mode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mode is the permissions of the secret file in the guest, it's owned by root Default: 0400",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMSpec describes the configuration of a VM",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMImageSpec"),
+						},
+					},
+					"sandbox": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSandboxSpec"),
+						},
+					},
+					"kernel": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMKernelSpec"),
+						},
+					},
+					"cpus": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int64",
+						},
+					},
+					"memory": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMMemorySpec"),
+						},
+					},
+					"diskSize": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"overlaySizeLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OverlaySizeLimit caps how much host disk space the VM's writable overlay may allocate, independently of DiskSize. Writes beyond the limit fail inside the VM. Unset means the overlay may grow up to DiskSize.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"),
+						},
+					},
+					"network": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Currently both will show in the JSON output as empty arrays. Making them pointers requires plenty of nil checks (as their contents are accessed directly) and is very risky for stability. APIMachinery potentially has a solution.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMNetworkSpec"),
+						},
+					},
+					"storage": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMStorageSpec"),
+						},
+					},
+					"copyFiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "This will be done at either \"ignite start\" or \"ignite create\" time",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.FileMapping"),
+									},
+								},
+							},
+						},
+					},
+					"ssh": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SSH specifies how the SSH setup should be done nil here means \"don't do anything special\" If SSH.Generate is set, Ignite will generate a new SSH key and copy it in to authorized_keys in the VM Specifying a path in SSH.Generate means \"use this public key\" If SSH.PublicKey is set, this struct will marshal as a string using that path If SSH.Generate is set, this struct will marshal as a bool => true",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.SSH"),
+						},
+					},
+					"backup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backup defines a policy for periodic backups of the VM's disk, run by ignited nil here means the VM isn't backed up",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMBackupSpec"),
+						},
+					},
+					"autostart": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Autostart makes the VM start automatically when the host boots, either by ignited when it starts up, or by \"ignite vm autostart\"",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"autostartAfter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AutostartAfter lists the names or IDs of the VMs that need to be running before the VM is autostarted. They're started first, even if they don't set autostart.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"restartPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RestartPolicy determines whether ignited restarts the VM when it stops without being stopped by ignite, e.g. when it crashes or shuts itself down Default: never",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"livenessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LivenessProbe is run by ignited while the VM is running, its result is recorded in the Healthy condition of the VM",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMProbe"),
+						},
+					},
+					"seccomp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Seccomp configures the seccomp filter of the Firecracker process of the VM, the syscalls the VMM may make Default: the advanced level, the default of Firecracker",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSeccompSpec"),
+						},
+					},
+					"sysctls": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sysctls are the kernel parameters set in the guest at boot, by their sysctl name (e.g. fs.inotify.max_user_watches). They're passed on the kernel command line, which the guest kernel applies from Linux 5.8 on. Parameters of kernel modules that are loaded later, after the kernel command line has been applied, aren't set.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"env": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Env are the environment variables set in the guest. They're written to /etc/profile.d/ignite-env.sh for login shells, and to /etc/ignite/environment for systemd units (EnvironmentFile=) every time the VM is started.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"users": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Users are created in the guest every time the VM is started, users that already exist in the image are updated. This allows logging in to stock images without baking credentials into them.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMUser"),
+									},
+								},
+							},
+						},
+					},
+					"secrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secrets are read on the host every time the VM is started, and delivered to its guest agent. The agent writes them to a tmpfs at /run/ignite/secrets, they're never written to the disk of the VM. The image needs to be imported with the agent.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSecret"),
+									},
+								},
+							},
+						},
+					},
+					"runtime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Runtime is the container runtime the VM is run with. It overrides the runtime of the ignite configuration and flags, so that VMs on the same host can use different runtimes. Default: unset, the runtime the VM was created with is used",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"networkPlugin": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NetworkPlugin is the network plugin the VM is run with. It overrides the network plugin of the ignite configuration and flags, docker-bridge needs the docker runtime. Default: unset, the network plugin the VM was created with is used",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"firmware": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Firmware is the path of a UEFI firmware blob on the host the VM boots with if its image is a disk image, which boots from the bootloader and kernel in the disk. Default: the OVMF (amd64) or AAVMF (arm64) firmware of the sandbox image",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"devices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Devices are host PCI devices passed through into the VM with VFIO, e.g. GPUs. They're unbound from their host drivers while the VM runs, and need the IOMMU of the host to be enabled. VMs with devices run with QEMU, Firecracker doesn't support VFIO.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PCIDevice"),
+									},
+								},
+							},
+						},
+					},
+					"nestedVirtualization": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NestedVirtualization exposes the virtualization extensions of the host CPU (VMX or SVM) to the guest, so the VM can run its own hypervisor. It needs nested virtualization to be enabled in KVM on the host. VMs with nested virtualization run with QEMU, Firecracker doesn't expose the extensions. Only supported on amd64.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"image", "sandbox", "kernel", "cpus", "memory", "diskSize"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.FileMapping", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.PCIDevice", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.SSH", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMBackupSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMImageSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMKernelSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMMemorySpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMNetworkSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMProbe", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSandboxSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSeccompSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSecret", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMStorageSpec", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMUser", "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1.Size"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMStatus defines the status of a VM",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"running": {
+						SchemaProps: spec.SchemaProps{
+							Default: false,
+							Type:    []string{"boolean"},
+							Format:  "",
+						},
+					},
+					"paused": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"runtime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Runtime"),
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/libgitops/pkg/runtime.Time"),
+						},
+					},
+					"network": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Network"),
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OCIImageSource"),
+						},
+					},
+					"kernel": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OCIImageSource"),
+						},
+					},
+					"idPrefix": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"overlay": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OverlayStatus"),
+						},
+					},
+					"snapshotter": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"restartCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RestartCount is the number of times the VM has been restarted by its restart policy",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"lastExit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastExit describes how the VM stopped the last time",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMExitStatus"),
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions describe whether the VM is usable, and why not",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMCondition"),
+									},
+								},
+							},
+						},
+					},
+					"seccomp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Seccomp is the seccomp filter applied to the Firecracker process of the VM when it was started",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSeccompStatus"),
+						},
+					},
+					"confinement": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Confinement is the SELinux label and AppArmor profile the sandbox of the VM was started with",
+							Ref:         ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMConfinementStatus"),
+						},
+					},
+				},
+				Required: []string{"running", "image", "kernel", "idPrefix"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Network", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OCIImageSource", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.OverlayStatus", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Runtime", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMCondition", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMConfinementStatus", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMExitStatus", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSeccompStatus", "github.com/weaveworks/libgitops/pkg/runtime.Time"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMStorageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMStorageSpec defines the VM's Volumes and VolumeMounts",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"volumes": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Volume"),
+									},
+								},
+							},
+						},
+					},
+					"volumeMounts": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VolumeMount"),
+									},
+								},
+							},
+						},
+					},
+					"readOnlyRoot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadOnlyRoot attaches the root device of the VM read-only, the guest can't change its root filesystem. Only the WritablePaths and the volumes are writable.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"writablePaths": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WritablePaths are mounted as empty RAM-backed tmpfs when the root device is read-only, their contents are discarded when the VM stops Default: /tmp, /var/tmp and /var/log",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.Volume", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VolumeMount"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VMTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VMTemplate is a reusable VM configuration. VMs created from a template use its spec as their base configuration, which can be overridden per VM. These files are stored in /var/lib/firecracker/vmtemplate/{template-id}/metadata.json",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"TypeMeta": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"),
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSpec"),
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"TypeMeta", "metadata", "spec"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha4.BlockDeviceVolume"},
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSpec", "github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta", "k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"},
 	}
 }
 
-func schema_pkg_apis_ignite_v1alpha4_VolumeMount(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_ignite_v1alpha5_VMUser(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VolumeMount defines the mount point for a named volume inside a VM",
+				Description: "VMUser is a user of the guest, see VMSpec.Users",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"name": {
@@ -2817,74 +5900,155 @@ func schema_pkg_apis_ignite_v1alpha4_VolumeMount(ref common.ReferenceCallback) c
 							Format:  "",
 						},
 					},
-					"mountPath": {
+					"uid": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Description: "UID of the user, the first free UID from 1000 on is used if unset. It can't be changed for users that already exist in the image.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"groups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Groups are the supplementary groups of the user, missing groups are created",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"sudo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sudo allows the user to run any command as root with sudo, without a password. The image needs to have sudo installed.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"passwordHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PasswordHash is the password of the user in the crypt(3) format, e.g. the output of \"mkpasswd -m sha-512\". Password logins are disabled if unset.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"authorizedKeys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AuthorizedKeys are public keys in the authorized_keys format that are authorized for the user",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"name", "mountPath"},
+				Required: []string{"name"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_meta_v1alpha1_DMID(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_ignite_v1alpha5_VaultConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DMID specifies the format for device mapper IDs",
+				Description: "VaultConfiguration configures the HashiCorp Vault server the secrets of the VMs, and the SSH keys authorized in them, are read from. The paths are the paths of the Vault HTTP API without the /v1 prefix, e.g. secret/data/db for the KV version 2 engine mounted at secret.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"index": {
+					"address": {
 						SchemaProps: spec.SchemaProps{
-							Default: 0,
-							Type:    []string{"integer"},
-							Format:  "int32",
+							Description: "Address is the URL of the Vault server, e.g. https://vault.example.com:8200 Default: $VAULT_ADDR",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tokenFile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TokenFile is the file holding the token ignite authenticates with Default: unset, the token is read from $VAULT_TOKEN",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"caFile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CAFile is the CA certificate the certificate of the Vault server is verified with Default: unset, the system CAs are used",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace is the Vault Enterprise namespace of the paths",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sshKeysPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SSHKeysPath is the path of a secret whose fields are public keys in the authorized_keys format. They're authorized for root in every VM created.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"index"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_meta_v1alpha1_OCIContentID(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_ignite_v1alpha5_Volume(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Volume defines named storage volume",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"repoName": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
 							Default: "",
 							Type:    []string{"string"},
 							Format:  "",
 						},
 					},
-					"digest": {
+					"blockDevice": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Fully qualified image name, e.g. \"docker.io/library/node\" or blank if the image is local",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.BlockDeviceVolume"),
+						},
+					},
+					"tmpfs": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.TmpfsVolume"),
+						},
+					},
+					"nbd": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.NBDVolume"),
 						},
 					},
 				},
-				Required: []string{"repoName", "digest"},
+				Required: []string{"name"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.BlockDeviceVolume", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.NBDVolume", "github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.TmpfsVolume"},
 	}
 }
 
-func schema_pkg_apis_meta_v1alpha1_OCIImageRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_ignite_v1alpha5_VolumeMount(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "OCIImageRef is a struct containing a names and tagged reference by which an OCI runtime can identify an image to retrieve.",
+				Description: "VolumeMount defines the mount point for a named volume inside a VM",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"name": {
@@ -2894,7 +6058,7 @@ func schema_pkg_apis_meta_v1alpha1_OCIImageRef(ref common.ReferenceCallback) com
 							Format:  "",
 						},
 					},
-					"tag": {
+					"mountPath": {
 						SchemaProps: spec.SchemaProps{
 							Default: "",
 							Type:    []string{"string"},
@@ -2902,7 +6066,211 @@ func schema_pkg_apis_meta_v1alpha1_OCIImageRef(ref common.ReferenceCallback) com
 						},
 					},
 				},
-				Required: []string{"name", "tag"},
+				Required: []string{"name", "mountPath"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_Vulnerability(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Vulnerability is a vulnerability of a package installed in an image",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID is the identifier of the vulnerability, e.g. CVE-2021-3711",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"package": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Package is the name of the vulnerable package",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version is the installed version of the package",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"fixedVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FixedVersion is the version of the package the vulnerability is fixed in, if any",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity is the severity of the vulnerability",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"title": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Title briefly describes the vulnerability",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"id", "package", "version", "severity"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_VulnerabilitySummary(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilitySummary counts the vulnerabilities of an image by severity",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"critical": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"high": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"medium": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"low": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"unknown": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_WebhookConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WebhookConfiguration is an HTTP endpoint ignited POSTs events to",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the http or https URL the events are POSTed to",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"events": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Events are the types of the events sent to the webhook, e.g. VMCrashed Default: unset, all events are sent",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeout is how long a request to the webhook may take Default: 10s",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_ignite_v1alpha5_ZFSConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ZFSConfiguration configures where the zfs snapshotter creates its volumes",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"dataset": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Dataset is the parent dataset for the image zvols and the VM clones, for example \"tank/ignite\". Properties like compression are inherited from it.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_meta_v1alpha1_DMID(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DMID specifies the format for device mapper IDs",
+				Type:        v1alpha1.DMID{}.OpenAPISchemaType(),
+				Format:      v1alpha1.DMID{}.OpenAPISchemaFormat(),
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_meta_v1alpha1_OCIContentID(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:   v1alpha1.OCIContentID{}.OpenAPISchemaType(),
+				Format: v1alpha1.OCIContentID{}.OpenAPISchemaFormat(),
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_meta_v1alpha1_OCIImageRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OCIImageRef is a struct containing a name, and a tag and/or a digest by which an OCI runtime can identify an image to retrieve.",
+				Type:        v1alpha1.OCIImageRef{}.OpenAPISchemaType(),
+				Format:      v1alpha1.OCIImageRef{}.OpenAPISchemaFormat(),
 			},
 		},
 	}
@@ -2953,17 +6321,8 @@ func schema_pkg_apis_meta_v1alpha1_Size(ref common.ReferenceCallback) common.Ope
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Description: "Size specifies a common unit for data sizes",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"ByteSize": {
-						SchemaProps: spec.SchemaProps{
-							Default: 0,
-							Type:    []string{"integer"},
-							Format:  "int64",
-						},
-					},
-				},
-				Required: []string{"ByteSize"},
+				Type:        v1alpha1.Size{}.OpenAPISchemaType(),
+				Format:      v1alpha1.Size{}.OpenAPISchemaFormat(),
 			},
 		},
 	}
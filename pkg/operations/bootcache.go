@@ -0,0 +1,346 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/agent"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/authorizedkeys"
+	"github.com/weaveworks/ignite/pkg/bootcache"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/container"
+	"github.com/weaveworks/ignite/pkg/migration"
+	"github.com/weaveworks/ignite/pkg/operations/lookup"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/ignite/pkg/vault"
+	apiruntime "github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// vmAuthorizedKeys is the authorized_keys file of root in the guest
+const vmAuthorizedKeys = "/root/.ssh/authorized_keys"
+
+// bootCache is the boot cache entry of a started VM
+type bootCache struct {
+	key string
+	// restored is set if the VM is restored from the entry, it's snapshotted into it otherwise
+	restored bool
+}
+
+// prepareBootCache looks up the boot cache entry of the VM if the boot cache is enabled, and
+// the VM hasn't been started before. If the entry exists, its disk and snapshot are restored
+// into the VM. nil is returned for the VMs that don't use the boot cache.
+func prepareBootCache(vm *api.VM) (*bootCache, error) {
+	if !providers.BootCache.Enabled || vm.Condition(api.VMBooted) != nil || migration.Pending(vm) {
+		return nil, nil
+	}
+
+	if reason := bootCacheUnsupported(vm); len(reason) > 0 {
+		log.Debugf("VM %q doesn't use the boot cache: %s", vm.GetUID(), reason)
+		return nil, nil
+	}
+
+	imageUID, err := lookup.ImageUIDForVM(vm, providers.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := providers.Client.Images().Get(imageUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The files a lazily imported image didn't need at boot are loaded after the snapshot
+	if len(image.Status.LazyRef) > 0 {
+		log.Debugf("VM %q doesn't use the boot cache: its image is imported lazily", vm.GetUID())
+		return nil, nil
+	}
+
+	kernelUID, err := lookup.KernelUIDForVM(vm, providers.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	key := bootcache.Key(vm, imageUID, kernelUID)
+	manifest, err := bootcache.Lookup(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest == nil {
+		return &bootCache{key: key}, nil
+	}
+
+	if len(manifest.Unsupported) > 0 {
+		log.Debugf("VM %q doesn't use the boot cache: %s", vm.GetUID(), manifest.Unsupported)
+		return nil, nil
+	}
+
+	if err := restoreBootCache(vm, manifest); err != nil {
+		if removeErr := migration.Remove(vm); removeErr != nil {
+			log.Warnf("Failed to remove the snapshot of VM %q: %v", vm.GetUID(), removeErr)
+		}
+		return nil, fmt.Errorf("failed to restore VM %q from the boot cache: %v", vm.GetUID(), err)
+	}
+
+	return &bootCache{key: key, restored: true}, nil
+}
+
+// bootCacheUnsupported returns why the VM can't be snapshotted into the boot cache, or
+// restored from it. The VMs of an entry share everything that's in the disk and memory
+// of the snapshotted VM, so VMs with files of their own can't use the boot cache.
+func bootCacheUnsupported(vm *api.VM) string {
+	switch {
+	case len(vm.Status.Snapshotter) > 0 && vm.Status.Snapshotter != snapshotter.SnapshotterDMLegacy:
+		return fmt.Sprintf("it uses the %s snapshotter", vm.Status.Snapshotter)
+	case len(vm.Spec.Storage.Volumes) > 0:
+		return "it has volumes"
+	case vm.Spec.Storage.ReadOnlyRoot:
+		return "its root device is read-only"
+	case len(vm.Spec.CopyFiles) > 0:
+		return "it copies files into its disk"
+	case len(vm.Spec.Env) > 0 || len(vm.Spec.Users) > 0:
+		return "it writes environment variables or users into its disk"
+	case len(vm.Spec.MemoryHugepages) > 0:
+		return "its memory is backed by hugepages"
+	case isEmulated():
+		return "it's emulated with QEMU"
+	case vm.DiskImage():
+		return "it boots a disk image with QEMU"
+	case vm.HasDevices():
+		return "it passes host PCI devices through with QEMU"
+	case vm.Spec.NestedVirtualization:
+		return "it has nested virtualization with QEMU"
+	case vm.Spec.OverlaySizeLimit != meta.EmptySize:
+		return "its overlay size is limited"
+	case providers.ComponentConfig != nil && providers.ComponentConfig.Spec.Confinement.SELinux:
+		return "its files are labeled for SELinux"
+	}
+
+	for k := range vm.GetObjectMeta().Annotations {
+		if strings.HasPrefix(k, constants.IGNITE_INTERFACE_ANNOTATION) {
+			return "it has extra network interfaces"
+		}
+	}
+
+	return ""
+}
+
+// restoreBootCache replaces the disk of the VM by the one of the snapshotted VM, and links
+// the snapshot into the migration directory of the VM, so its start restores it. The guest
+// keeps the MAC address it had, the DHCP server of the VM serves it instead of a new one.
+func restoreBootCache(vm *api.VM, manifest *bootcache.Manifest) error {
+	if err := copySparse(bootcache.File(manifest.Key, bootcache.OverlayFile), vm.OverlayFile()); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(migration.Dir(vm), constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	// Firecracker maps the memory file copy-on-write, so the VMs can share it
+	for src, dst := range map[string]string{
+		bootcache.StateFile:  migration.StateFile,
+		bootcache.MemoryFile: migration.MemoryFile,
+	} {
+		if err := linkOrCopy(bootcache.File(manifest.Key, src), migration.File(vm, dst)); err != nil {
+			return err
+		}
+	}
+
+	// The manifest is written last, the VM is only restored from a complete snapshot
+	return migration.WriteManifest(vm, &migration.Manifest{
+		DrivePath:  manifest.DrivePath,
+		MACAddress: manifest.MACAddress,
+		BootCache:  true,
+	})
+}
+
+// storeBootCache snapshots the booted VM into the boot cache entry with the given key, once
+// its guest agent answers. The VM is paused while its memory and disk are copied. If the
+// agent doesn't answer, the entry records that the VMs of the key can't be snapshotted.
+func storeBootCache(ctx context.Context, vm *api.VM, key string) (err error) {
+	_, span := tracing.Start(ctx, "vm.bootcache.store")
+	defer func() { tracing.End(span, err) }()
+
+	// The VM is running now, which the agent client requires
+	if vm, err = providers.Client.VMs().Get(vm.GetUID()); err != nil {
+		return
+	}
+
+	imageUID, err := lookup.ImageUIDForVM(vm, providers.Client)
+	if err != nil {
+		return
+	}
+
+	kernelUID, err := lookup.KernelUIDForVM(vm, providers.Client)
+	if err != nil {
+		return
+	}
+
+	manifest := &bootcache.Manifest{
+		Key:      key,
+		Image:    imageUID,
+		Kernel:   kernelUID,
+		CPUs:     vm.Spec.CPUs,
+		Memory:   vm.Spec.Memory,
+		DiskSize: vm.Spec.DiskSize,
+		Created:  apiruntime.Timestamp(),
+	}
+
+	if err = agent.Wait(vm, constants.BOOT_CACHE_TIMEOUT); err != nil {
+		if errors.Is(err, agent.ErrNoAgent) {
+			manifest.Unsupported = "the image has no guest agent"
+			return bootcache.Store(manifest, nil)
+		}
+		return
+	}
+
+	identity, err := agent.GetIdentity(vm)
+	if err != nil {
+		return
+	}
+	manifest.MACAddress = identity.MACAddress
+
+	if manifest.DrivePath, err = SnapshotDevice(vm); err != nil {
+		return
+	}
+
+	// The VM directory is mounted at the same path in the container, so Firecracker can write there
+	snapshotDir := path.Join(vm.ObjectPath(), constants.BOOT_CACHE_SNAPSHOT_DIR)
+	if err = os.MkdirAll(snapshotDir, constants.DATA_DIR_PERM); err != nil {
+		return
+	}
+	defer os.RemoveAll(snapshotDir)
+
+	if err = setFirecrackerState(vm, firecrackerStatePaused); err != nil {
+		return fmt.Errorf("failed to pause VM %q, the boot cache requires Firecracker v0.24 or newer: %v", vm.GetUID(), err)
+	}
+	defer util.DeferErr(&err, func() error { return setFirecrackerState(vm, firecrackerStateResumed) })
+
+	if err = container.FirecrackerRequest(vm, http.MethodPut, "/snapshot/create", map[string]string{
+		"snapshot_type": "Full",
+		"snapshot_path": path.Join(snapshotDir, bootcache.StateFile),
+		"mem_file_path": path.Join(snapshotDir, bootcache.MemoryFile),
+	}, snapshotTimeout); err != nil {
+		return fmt.Errorf("failed to snapshot VM %q: %v", vm.GetUID(), err)
+	}
+
+	if err = bootcache.Store(manifest, func(dir string) error {
+		for _, name := range []string{bootcache.StateFile, bootcache.MemoryFile} {
+			if err := os.Rename(path.Join(snapshotDir, name), path.Join(dir, name)); err != nil {
+				return err
+			}
+		}
+
+		// The VM is paused, so its disk matches the snapshot of its memory
+		return copySparse(vm.OverlayFile(), path.Join(dir, bootcache.OverlayFile))
+	}); err != nil {
+		return
+	}
+
+	VMLog(vm, "start").Infof("Stored the snapshot of VM %q in the boot cache", vm.GetUID())
+	return nil
+}
+
+// reidentifyRestoredVM gives the VM restored from the boot cache its own hostname, address
+// and authorized SSH keys, in place of the ones of the snapshotted VM
+func reidentifyRestoredVM(ctx context.Context, vm *api.VM) (err error) {
+	_, span := tracing.Start(ctx, "vm.bootcache.restore")
+	defer func() { tracing.End(span, err) }()
+
+	// The VM is running now, which the agent client requires
+	if vm, err = providers.Client.VMs().Get(vm.GetUID()); err != nil {
+		return
+	}
+
+	if err = agent.Wait(vm, constants.BOOT_CACHE_TIMEOUT); err != nil {
+		return
+	}
+
+	identity := &protocol.Identity{Hostname: vm.GetUID().String()}
+	if len(vm.Status.Network.IPAddresses) > 0 {
+		identity.Address = vm.Status.Network.IPAddresses[0].String()
+	}
+
+	if err = agent.SetIdentity(vm, identity); err != nil {
+		return fmt.Errorf("failed to set the identity of VM %q: %v", vm.GetUID(), err)
+	}
+
+	authorizedKeys, err := restoredAuthorizedKeys(vm)
+	if err != nil {
+		return
+	}
+
+	if len(authorizedKeys) > 0 {
+		if err = agent.WriteFile(vm, vmAuthorizedKeys, bytes.NewReader(authorizedKeys), 0600); err != nil {
+			return fmt.Errorf("failed to write the authorized SSH keys of VM %q: %v", vm.GetUID(), err)
+		}
+	}
+
+	VMLog(vm, "start").Infof("Restored VM %q from the boot cache", vm.GetUID())
+	return nil
+}
+
+// restoredAuthorizedKeys returns the authorized_keys file of root for the VM: its public
+// key, its authorized keys and the keys kept in Vault for all VMs. The keys the image has
+// aren't kept, the file of the snapshotted VM has the keys of that VM.
+func restoredAuthorizedKeys(vm *api.VM) ([]byte, error) {
+	var content []byte
+	var keys []string
+	if vm.Spec.SSH != nil {
+		pubKeyPath := vm.Spec.SSH.PublicKey
+		if vm.Spec.SSH.Generate {
+			pubKeyPath = path.Join(vm.ObjectPath(), fmt.Sprintf(constants.VM_SSH_KEY_TEMPLATE, vm.GetUID())) + ".pub"
+		}
+
+		if len(pubKeyPath) > 0 {
+			var err error
+			if content, err = ioutil.ReadFile(pubKeyPath); err != nil {
+				return nil, err
+			}
+		}
+
+		keys = append(keys, vm.Spec.SSH.AuthorizedKeys...)
+	}
+
+	vaultKeys, err := vault.AuthorizedKeys()
+	if err != nil {
+		return nil, err
+	}
+	keys = append(keys, vaultKeys...)
+
+	return authorizedkeys.Add(content, keys), nil
+}
+
+// copySparse copies src to dst keeping its holes, or as a reflink if the filesystem supports it
+func copySparse(src, dst string) error {
+	_, err := util.ExecuteCommand("cp", "--sparse=always", "--reflink=auto", src, dst)
+	return err
+}
+
+// linkOrCopy hardlinks src at dst, or copies it if it can't be linked
+func linkOrCopy(src, dst string) error {
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	return copySparse(src, dst)
+}
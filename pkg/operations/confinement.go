@@ -0,0 +1,33 @@
+package operations
+
+import (
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/confinement"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/runtime"
+)
+
+// confine applies the confinement of the ignite configuration to the sandbox of the VM,
+// with SELinux categories the other VMs don't use
+func confine(vm *api.VM, config *runtime.ContainerConfig) (*api.VMConfinementStatus, error) {
+	if providers.ComponentConfig == nil {
+		return nil, nil
+	}
+
+	cfg := &providers.ComponentConfig.Spec.Confinement
+	var usedLabels []string
+	if cfg.SELinux {
+		vms, err := providers.Client.VMs().List()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, other := range vms {
+			if other.GetUID() != vm.GetUID() && other.Status.Confinement != nil {
+				usedLabels = append(usedLabels, other.Status.Confinement.SELinuxLabel)
+			}
+		}
+	}
+
+	return confinement.Apply(cfg, vm, config, usedLabels)
+}
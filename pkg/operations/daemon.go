@@ -0,0 +1,55 @@
+package operations
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/runtime"
+	dockerruntime "github.com/weaveworks/ignite/pkg/runtime/docker"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// DockerDaemonPrefix prefixes the images and kernels imported from the local docker
+// daemon instead of a registry, e.g. docker-daemon:my-image:latest
+const DockerDaemonPrefix = "docker-daemon:"
+
+// ParseImportSource parses the OCI image reference of an image or kernel to import, and
+// returns whether it's prefixed to come from the local docker daemon
+func ParseImportSource(source string) (meta.OCIImageRef, bool, error) {
+	fromDaemon := strings.HasPrefix(source, DockerDaemonPrefix)
+	ociRef, err := meta.NewOCIImageRef(strings.TrimPrefix(source, DockerDaemonPrefix))
+	return ociRef, fromDaemon, err
+}
+
+// LoadFromDockerDaemon copies the OCI image from the local docker daemon into the
+// container runtime, like "docker save" piped into the runtime, so images built
+// locally with docker are imported without pushing them to a registry. With the
+// docker runtime, the image only needs to exist in the daemon.
+func LoadFromDockerDaemon(ociRef meta.OCIImageRef) (err error) {
+	dc, err := dockerruntime.GetDockerClient()
+	if err != nil {
+		return err
+	}
+
+	// The image must not be pulled from a registry instead
+	if _, err := dc.InspectImage(ociRef); err != nil {
+		return fmt.Errorf("image %q not found in the docker daemon: %v", ociRef, err)
+	}
+
+	if providers.Runtime.Name() == runtime.RuntimeDocker {
+		return nil
+	}
+
+	log.Infof("Loading image %q from the docker daemon into %s...", ociRef, providers.Runtime.Name())
+	rc, err := dc.SaveImage(ociRef)
+	if err != nil {
+		return err
+	}
+	defer util.DeferErr(&err, rc.Close)
+
+	return providers.Runtime.LoadImage(rc)
+}
@@ -0,0 +1,35 @@
+package operations
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseImportSource(t *testing.T) {
+	cases := []struct {
+		source     string
+		ref        string
+		fromDaemon bool
+		err        bool
+	}{
+		{source: "weaveworks/ignite-ubuntu", ref: "weaveworks/ignite-ubuntu:latest"},
+		{source: "docker-daemon:my-app:dev", ref: "my-app:dev", fromDaemon: true},
+		{source: "docker-daemon:my-app", ref: "my-app:latest", fromDaemon: true},
+		{source: "docker-daemon:", fromDaemon: true, err: true},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.source, func(t *testing.T) {
+			ref, fromDaemon, err := ParseImportSource(rt.source)
+			assert.Equal(t, fromDaemon, rt.fromDaemon)
+			if rt.err {
+				assert.Assert(t, err != nil)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, ref.String(), rt.ref)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package operations
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/emulation"
+	"github.com/weaveworks/ignite/pkg/migration"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// emulated returns whether the started VMs are emulated with QEMU, as configured
+func emulated() (bool, error) {
+	return emulation.Enabled(providers.Emulation.Mode)
+}
+
+// isEmulated returns whether the started VMs are emulated, false if the mode is invalid
+func isEmulated() bool {
+	emulate, _ := emulated()
+	return emulate
+}
+
+// emulateVM returns whether the VM is started with QEMU's emulation instead of Firecracker,
+// and records it in the Emulated condition of the VM, so it's clear why the VM is slow
+func emulateVM(vm *api.VM) (bool, error) {
+	emulate, err := emulated()
+	if err != nil {
+		return false, err
+	}
+
+	if !emulate {
+		if vm.Condition(api.VMEmulated) != nil {
+			vm.SetCondition(api.VMEmulated, api.ConditionFalse, "KVM", "")
+		}
+		return false, nil
+	}
+
+	// The snapshot of a migrated VM can only be loaded by Firecracker
+	if migration.Pending(vm) {
+		return false, fmt.Errorf("VM %q can't be restored from its migration snapshot with QEMU's emulation, start it on a host with KVM", vm.GetUID())
+	}
+
+	reason, because := "KVMUnavailable", "KVM is unavailable"
+	if emulation.KVMAvailable() {
+		reason, because = "EmulationAlways", "the emulation mode is always"
+	}
+
+	log.Warnf("VM %q is emulated with QEMU because %s, it runs a lot slower than with KVM", vm.GetUID(), because)
+	vm.SetCondition(api.VMEmulated, api.ConditionTrue, reason, "The VM is emulated with QEMU TCG, it runs a lot slower than with KVM")
+	return true, nil
+}
+
+// requireFirecracker returns an error if the VM is run with QEMU, as the operation needs
+// the Firecracker API
+func requireFirecracker(vm *api.VM, operation string) error {
+	if vm.ConditionTrue(api.VMEmulated) {
+		return fmt.Errorf("VM %q is emulated with QEMU, it can't be %s without Firecracker", vm.GetUID(), operation)
+	}
+
+	if vm.DiskImage() {
+		return fmt.Errorf("VM %q boots a disk image with QEMU, it can't be %s without Firecracker", vm.GetUID(), operation)
+	}
+
+	if vm.HasDevices() {
+		return fmt.Errorf("VM %q passes host PCI devices through with QEMU, it can't be %s without Firecracker", vm.GetUID(), operation)
+	}
+
+	if vm.Spec.NestedVirtualization {
+		return fmt.Errorf("VM %q has nested virtualization with QEMU, it can't be %s without Firecracker", vm.GetUID(), operation)
+	}
+
+	return nil
+}
+
+// emulationArgs returns the ignite-spawn flag emulating the VM with QEMU
+func emulationArgs(emulate bool) []string {
+	if !emulate {
+		return nil
+	}
+
+	return []string{"--emulate"}
+}
@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/logs"
+	"github.com/weaveworks/ignite/pkg/migration"
+)
+
+// HibernateVM saves the memory and device state of the running VM to disk, and stops
+// it, releasing its CPUs and memory on the host. The snapshot is taken the same way as
+// for a migration, so the next start of the VM restores it instead of booting the VM.
+func HibernateVM(vm *api.VM) error {
+	if vm.Paused() {
+		return fmt.Errorf("VM %q is paused, resume it before hibernating it", vm.GetUID())
+	}
+
+	if err := PrepareMigration(vm); err != nil {
+		return err
+	}
+
+	// The VM stays paused while it's killed, it must not write to its disk after the snapshot
+	if err := StopVM(context.Background(), vm, true, true); err != nil {
+		if abortErr := AbortMigration(vm); abortErr != nil {
+			log.Errorf("Failed to resume VM %q: %v", vm.GetUID(), abortErr)
+		}
+
+		return err
+	}
+
+	if logs.Quiet {
+		fmt.Println(vm.GetUID())
+	} else {
+		log.Infof("Hibernated %s with name %q and ID %q", vm.GetKind(), vm.GetName(), vm.GetUID())
+	}
+
+	return nil
+}
+
+// Hibernated returns true if the VM is stopped with a saved state, which is restored on its next start
+func Hibernated(vm *api.VM) bool {
+	return !vm.Running() && migration.Pending(vm)
+}
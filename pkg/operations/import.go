@@ -1,29 +1,47 @@
 package operations
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/agent"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/arch"
 	"github.com/weaveworks/ignite/pkg/client"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/estargz"
 	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/policy"
+	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/source"
+	"github.com/weaveworks/ignite/pkg/tracing"
 	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/ignite/pkg/vmlinux"
 	"github.com/weaveworks/libgitops/pkg/filter"
 	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+	"go.opencensus.io/trace"
 )
 
 // FindOrImportImage returns an image based on the source string.
 // If the image already exists, it is returned. If the image doesn't
 // exist, it is imported
-func FindOrImportImage(c *client.Client, ociRef meta.OCIImageRef) (*api.Image, error) {
+func FindOrImportImage(ctx context.Context, c *client.Client, ociRef meta.OCIImageRef) (*api.Image, error) {
 	log.Debugf("Ensuring image %s exists, or importing it...", ociRef)
+	unlock, err := lockImport("image", ociRef)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	image, err := c.Images().Find(filter.NewIDNameFilter(ociRef.String()))
 	if err == nil {
 		// Return the image found
@@ -33,46 +51,61 @@ func FindOrImportImage(c *client.Client, ociRef meta.OCIImageRef) (*api.Image, e
 
 	switch err.(type) {
 	case *filterer.NonexistentError:
-		return importImage(c, ociRef)
+		return importImage(ctx, c, ociRef, "")
 	default:
 		return nil, err
 	}
 }
 
-// importKernel imports an image from an OCI image
-func importImage(c *client.Client, ociRef meta.OCIImageRef) (*api.Image, error) {
+// ImportImageWithAgent imports an image from an OCI image, and injects the guest agent
+// binary at agentBinary into it. The agent can't be added to an image that's already
+// imported, as the VMs based on the image would be corrupted by modifying it.
+func ImportImageWithAgent(ctx context.Context, c *client.Client, ociRef meta.OCIImageRef, agentBinary string) (*api.Image, error) {
+	unlock, err := lockImport("image", ociRef)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	image, err := c.Images().Find(filter.NewIDNameFilter(ociRef.String()))
+	switch err.(type) {
+	case nil:
+		return nil, fmt.Errorf("image %q is already imported with UID %q, remove it to import it with the guest agent", ociRef, image.GetUID())
+	case *filterer.NonexistentError:
+		return importImage(ctx, c, ociRef, agentBinary)
+	default:
+		return nil, err
+	}
+}
+
+// importImage imports an image from an OCI image, injecting the guest agent if agentBinary is set
+func importImage(ctx context.Context, c *client.Client, ociRef meta.OCIImageRef, agentBinary string) (*api.Image, error) {
 	log.Debugf("Importing image with ociRef %q", ociRef)
-	// Parse the source
-	dockerSource := source.NewDockerSource()
-	src, err := dockerSource.Parse(ociRef)
+	release, err := acquireImportSlot(ctx)
 	if err != nil {
-		log.Errorf("image import: parse OCI ref failed: %v", err)
 		return nil, err
 	}
+	defer release()
 
 	image := c.Images().New()
 	// Set the image name
 	image.Name = ociRef.String()
 	// Set the image's ociRef
 	image.Spec.OCI = ociRef
-	// Set the image's ociSource
-	image.Status.OCISource = *src
 
 	// Generate UID automatically
-	if err := metadata.SetNameAndUID(image, c); err != nil {
+	err = metadata.SetNameAndUID(image, c)
+	if err != nil {
 		log.Errorf("image import: SetNameAndUID failed: %v", err)
 		return nil, err
 	}
 
-	log.Infoln("Starting image import...")
-
-	// Truncate a file for the filesystem, format it with ext4, and copy in the files from the source
-	if err := dmlegacy.CreateImageFilesystem(image, dockerSource); err != nil {
-		log.Errorf("image import: CreateImageFilesystem failed: %v", err)
+	if err := populateImage(ctx, image, agentBinary); err != nil {
 		return nil, err
 	}
 
-	if err := c.Images().Set(image); err != nil {
+	err = c.Images().Set(image)
+	if err != nil {
 		log.Errorf("image import: Images().Set failed: %v", err)
 		return nil, err
 	}
@@ -81,11 +114,149 @@ func importImage(c *client.Client, ociRef meta.OCIImageRef) (*api.Image, error)
 	return image, nil
 }
 
+// PopulateImage creates the filesystem of an image object from its OCI image, and sets
+// the OCI source in its status. The caller stores the image.
+func PopulateImage(ctx context.Context, image *api.Image) error {
+	return populateImage(ctx, image, "")
+}
+
+func populateImage(ctx context.Context, image *api.Image, agentBinary string) (err error) {
+	defer observeImport("image", time.Now())
+	ctx, span := tracing.Start(ctx, "image.import", trace.StringAttribute("oci", image.Spec.OCI.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if err := policy.CheckImport(ctx, policy.OperationImageImport, image.Spec.OCI); err != nil {
+		return err
+	}
+
+	// Parse the source, images with eStargz layers are imported with only their boot files if enabled
+	var imageSource source.Source
+	var src *api.OCIImageSource
+	if providers.ImageImport.Lazy {
+		lazySource := source.NewEStargzSource()
+		src, err = parseSource(ctx, lazySource, image.Spec.OCI)
+		switch {
+		case err == nil:
+			imageSource = lazySource
+			image.Status.LazyRef = lazySource.LazyRef()
+		case errors.Is(err, estargz.ErrNotEStargz):
+			log.Infof("Image %q doesn't have eStargz layers, importing it in full", image.Spec.OCI)
+		default:
+			log.Errorf("image import: parse OCI ref failed: %v", err)
+			return err
+		}
+	}
+
+	if imageSource == nil {
+		dockerSource := source.NewDockerSource()
+		if src, err = parseSource(ctx, dockerSource, image.Spec.OCI); err != nil {
+			log.Errorf("image import: parse OCI ref failed: %v", err)
+			return err
+		}
+		imageSource = dockerSource
+	}
+
+	// Only images built for the architecture of the host run on it
+	if err := arch.Check(fmt.Sprintf("image %q", image.Spec.OCI), src.Architecture); err != nil {
+		imageSource.Cleanup()
+		return err
+	}
+
+	// Set the image's ociSource
+	image.Status.OCISource = *src
+
+	// Container disks are booted from their own disk, the tar stream of images imported
+	// in full is peeked at to find them
+	if len(image.Status.LazyRef) == 0 {
+		disk, replay, err := source.PeekDisk(imageSource)
+		if err != nil {
+			imageSource.Cleanup()
+			return fmt.Errorf("failed to read image %q: %v", image.Spec.OCI, err)
+		}
+
+		if disk != nil {
+			return importDisk(ctx, image, imageSource, disk, agentBinary)
+		}
+		imageSource = replay
+	}
+
+	// The guest agent writes the rest of the files of lazily imported images into the VMs
+	if len(image.Status.LazyRef) != 0 && len(agentBinary) == 0 {
+		if agentBinary, err = agent.Binary(); err != nil {
+			imageSource.Cleanup()
+			return fmt.Errorf("lazily imported images need the guest agent: %v", err)
+		}
+	}
+
+	log.Infoln("Starting image import...")
+
+	// Truncate a file for the filesystem, format it with ext4, and copy in the files from the source
+	if err := dmlegacy.CreateImageFilesystem(ctx, image, imageSource, agentBinary, providers.ImageImport.Mode); err != nil {
+		log.Errorf("image import: CreateImageFilesystem failed: %v", err)
+		return err
+	}
+
+	// Scan the filesystem for vulnerabilities and generate its SBOM. If either fails,
+	// the image filesystem is removed.
+	for _, step := range []struct {
+		name string
+		fn   func(context.Context, *api.Image) error
+	}{
+		{"scan", scanImage},
+		{"SBOM generation", generateSBOM},
+	} {
+		if err := step.fn(ctx, image); err != nil {
+			log.Errorf("image import: %s failed: %v", step.name, err)
+			if rmErr := os.RemoveAll(image.ObjectPath()); rmErr != nil {
+				log.Warnf("image import: failed to remove the image filesystem: %v", rmErr)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importDisk creates the disk image of an image of a container disk. The guest agent
+// can't be injected into the disk, and the scanners only read filesystems.
+func importDisk(ctx context.Context, image *api.Image, src source.Source, disk *source.Disk, agentBinary string) error {
+	defer func() {
+		if err := src.Cleanup(); err != nil {
+			log.Warnf("image import: cleanup failed: %v", err)
+		}
+	}()
+	defer disk.Close()
+
+	if len(agentBinary) != 0 {
+		return fmt.Errorf("image %q is a container disk, the guest agent can't be injected into it", image.Spec.OCI)
+	}
+
+	image.Status.OCISource.Format = api.ImageFormatDisk
+	log.Infof("Image %q is a container disk, starting import of disk %q...", image.Spec.OCI, disk.Name)
+
+	if err := dmlegacy.CreateDiskImage(ctx, image, disk); err != nil {
+		log.Errorf("image import: CreateDiskImage failed: %v", err)
+		return err
+	}
+
+	if len(providers.ImageScan.Scanner) != 0 || len(providers.SBOM.Format) != 0 {
+		log.Warnf("Image %q is a container disk, it isn't scanned and has no SBOM", image.Spec.OCI)
+	}
+
+	return nil
+}
+
 // FindOrImportKernel returns an kernel based on the source string.
 // If the image already exists, it is returned. If the image doesn't
 // exist, it is imported
-func FindOrImportKernel(c *client.Client, ociRef meta.OCIImageRef) (*api.Kernel, error) {
+func FindOrImportKernel(ctx context.Context, c *client.Client, ociRef meta.OCIImageRef) (*api.Kernel, error) {
 	log.Debugf("Ensuring kernel %s exists, or importing it...", ociRef)
+	unlock, err := lockImport("kernel", ociRef)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	kernel, err := c.Kernels().Find(filter.NewIDNameFilter(ociRef.String()))
 	if err == nil {
 		// Return the kernel found
@@ -95,37 +266,71 @@ func FindOrImportKernel(c *client.Client, ociRef meta.OCIImageRef) (*api.Kernel,
 
 	switch err.(type) {
 	case *filterer.NonexistentError:
-		return importKernel(c, ociRef)
+		return importKernel(ctx, c, ociRef)
 	default:
 		return nil, err
 	}
 }
 
 // importKernel imports a kernel from an OCI image
-func importKernel(c *client.Client, ociRef meta.OCIImageRef) (*api.Kernel, error) {
+func importKernel(ctx context.Context, c *client.Client, ociRef meta.OCIImageRef) (*api.Kernel, error) {
 	log.Debugf("Importing kernel with ociRef %q", ociRef)
-	// Parse the source
-	dockerSource := source.NewDockerSource()
-	src, err := dockerSource.Parse(ociRef)
+	release, err := acquireImportSlot(ctx)
 	if err != nil {
-		log.Errorf("kernel import: parse OCI ref failed: %v", err)
 		return nil, err
 	}
+	defer release()
 
 	kernel := c.Kernels().New()
 	// Set the kernel name
 	kernel.Name = ociRef.String()
 	// Set the kernel's ociRef
 	kernel.Spec.OCI = ociRef
-	// Set the kernel's ociSource
-	kernel.Status.OCISource = *src
 
 	// Generate UID automatically
-	if err := metadata.SetNameAndUID(kernel, c); err != nil {
+	err = metadata.SetNameAndUID(kernel, c)
+	if err != nil {
 		log.Errorf("kernel import: SetNameAndUID failed: %v", err)
 		return nil, err
 	}
 
+	if err := PopulateKernel(ctx, kernel); err != nil {
+		return nil, err
+	}
+
+	err = c.Kernels().Set(kernel)
+	if err != nil {
+		log.Errorf("kernel import: Kernels().Set failed: %v", err)
+		return nil, err
+	}
+
+	log.Infof("Imported OCI image %q (%s) to kernel image with UID %q", ociRef, kernel.Status.OCISource.Size, kernel.GetUID())
+	return kernel, nil
+}
+
+// PopulateKernel extracts the kernel, initrd and modules of a kernel object from its OCI
+// image, and sets the OCI source and version in its status. The files that already exist
+// are kept. The caller stores the kernel.
+func PopulateKernel(ctx context.Context, kernel *api.Kernel) (err error) {
+	defer observeImport("kernel", time.Now())
+	ctx, span := tracing.Start(ctx, "kernel.import", trace.StringAttribute("oci", kernel.Spec.OCI.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if err := policy.CheckImport(ctx, policy.OperationKernelImport, kernel.Spec.OCI); err != nil {
+		return err
+	}
+
+	// Parse the source
+	dockerSource := source.NewDockerSource()
+	src, err := parseSource(ctx, dockerSource, kernel.Spec.OCI)
+	if err != nil {
+		log.Errorf("kernel import: parse OCI ref failed: %v", err)
+		return err
+	}
+
+	// Set the kernel's ociSource
+	kernel.Status.OCISource = *src
+
 	// Cache the kernel contents in the kernel tar file
 	kernelTarFile := path.Join(kernel.ObjectPath(), constants.KERNEL_TAR)
 
@@ -142,28 +347,29 @@ func importKernel(c *client.Client, ociRef meta.OCIImageRef) (*api.Kernel, error
 		tempDir, err := ioutil.TempDir("", "")
 		if err != nil {
 			log.Errorf("kernel import: TempDir failed: %v", err)
-			return nil, err
+			return err
 		}
 
 		// Extract only the /boot and /lib directories of the tar stream into the tempDir
+		_, extractSpan := tracing.Start(ctx, "kernel.extract")
 		err = source.TarExtract(dockerSource, tempDir, "boot", "lib/modules")
+		tracing.End(extractSpan, err)
 		if err != nil {
 			log.Errorf("kernel import: TarExtract failed: %v", err)
-			return nil, err
+			return err
 		}
 
 		// Locate the kernel file in the temporary directory
 		kernelTmpFile, err := findKernel(tempDir)
 		if err != nil {
 			log.Errorf("kernel import: findKernel failed: %v", err)
-			return nil, err
+			return err
 		}
 
-		// Copy the vmlinux file
-		if err := util.CopyFile(kernelTmpFile, vmlinuxFile); err != nil {
-			errMsg := fmt.Errorf("failed to copy kernel file %q to kernel %q: %v", kernelTmpFile, kernel.GetUID(), err)
-			log.Errorf("kernel import: %v", errMsg)
-			return nil, errMsg
+		// Copy the vmlinux file, or extract it from the compressed kernel
+		if err := importKernelFile(kernel, kernelTmpFile, vmlinuxFile); err != nil {
+			log.Errorf("kernel import: %v", err)
+			return err
 		}
 
 		// Locate the initrd file in the temporary directory
@@ -175,7 +381,7 @@ func importKernel(c *client.Client, ociRef meta.OCIImageRef) (*api.Kernel, error
 			if err := util.CopyFile(initrdTmpFile, initrdFile); err != nil {
 				errMsg := fmt.Errorf("failed to copy initrd file %q to initrd %q: %v", initrdTmpFile, kernel.GetUID(), err)
 				log.Errorf("kernel import: %v", errMsg)
-				return nil, errMsg
+				return errMsg
 			}
 		} else {
 			kernel.Spec.HasInitrd = false
@@ -184,16 +390,27 @@ func importKernel(c *client.Client, ociRef meta.OCIImageRef) (*api.Kernel, error
 		// Pack the kernel tar with unnecessary data removed
 		if _, err := util.ExecuteCommand("tar", "-cf", kernelTarFile, "-C", tempDir, "."); err != nil {
 			log.Errorf("kernel import: tar pack failed: %v", err)
-			return nil, err
+			return err
 		}
 
 		// Cleanup
 		if err := os.RemoveAll(tempDir); err != nil {
 			log.Errorf("kernel import: RemoveAll tempDir failed: %v", err)
-			return nil, err
+			return err
 		}
 	}
 
+	// Firecracker only boots kernels of the architecture of the host, in its format. The
+	// architecture of the kernel is recorded instead of the one of its image.
+	if kernel.Status.OCISource.Architecture, err = arch.CheckKernel(vmlinuxFile); err != nil {
+		return fmt.Errorf("kernel %q can't be booted: %v", kernel.Spec.OCI, err)
+	}
+
+	// Verify the kernel has the options the VMs need, they'd hang at boot otherwise
+	if err := checkKernelConfig(kernel, vmlinuxFile, kernelTarFile); err != nil {
+		return err
+	}
+
 	// Populate the kernel version field if possible
 	if len(kernel.Status.Version) == 0 {
 		cmd := fmt.Sprintf("strings %s | grep 'Linux version' | awk '{print $3}'", vmlinuxFile)
@@ -206,23 +423,70 @@ func importKernel(c *client.Client, ociRef meta.OCIImageRef) (*api.Kernel, error
 		}
 	}
 
-	if err := c.Kernels().Set(kernel); err != nil {
-		log.Errorf("kernel import: Kernels().Set failed: %v", err)
-		return nil, err
+	return nil
+}
+
+// parseSource pulls the OCI image of the source if it isn't present, and inspects it
+func parseSource(ctx context.Context, src source.Source, ociRef meta.OCIImageRef) (*api.OCIImageSource, error) {
+	_, span := tracing.Start(ctx, "oci.pull")
+	res, err := src.Parse(ociRef)
+	tracing.End(span, err)
+	return res, err
+}
+
+// importKernelFile copies the kernel file of the kernel image to the vmlinux file. Firecracker
+// only boots uncompressed kernels, so compressed kernels like a bzImage are kept next to
+// the vmlinux file extracted from them.
+func importKernelFile(kernel *api.Kernel, kernelTmpFile, vmlinuxFile string) error {
+	compressed, err := vmlinux.Compressed(kernelTmpFile)
+	if err != nil {
+		return err
 	}
 
-	log.Infof("Imported OCI image %q (%s) to kernel image with UID %q", ociRef, kernel.Status.OCISource.Size, kernel.GetUID())
-	return kernel, nil
+	if !compressed {
+		kernel.Status.Compression = ""
+		if err := util.CopyFile(kernelTmpFile, vmlinuxFile); err != nil {
+			return fmt.Errorf("failed to copy kernel file %q to kernel %q: %v", kernelTmpFile, kernel.GetUID(), err)
+		}
+
+		return nil
+	}
+
+	vmlinuzFile := path.Join(path.Dir(vmlinuxFile), constants.KERNEL_COMPRESSED_FILE)
+	if err := util.CopyFile(kernelTmpFile, vmlinuzFile); err != nil {
+		return fmt.Errorf("failed to copy compressed kernel file %q to kernel %q: %v", kernelTmpFile, kernel.GetUID(), err)
+	}
+
+	if kernel.Status.Compression, err = vmlinux.Extract(vmlinuzFile, vmlinuxFile); err != nil {
+		return fmt.Errorf("failed to extract the vmlinux of compressed kernel file %q: %v", path.Base(kernelTmpFile), err)
+	}
+
+	log.Infof("Extracted the vmlinux of %s compressed kernel %q", kernel.Status.Compression, kernel.Spec.OCI)
+	return nil
 }
 
 func findKernel(tmpDir string) (string, error) {
 	// find the path to the kernel, resolve symlinks if necessary
 	bootDir := path.Join(tmpDir, "boot")
-	kernel := path.Join(bootDir, constants.KERNEL_FILE)
 
-	fi, err := os.Lstat(kernel)
+	// The kernel is named differently on some architectures
+	var kernel string
+	var fi os.FileInfo
+	var err error
+	for _, name := range arch.Host.KernelFiles {
+		kernel = path.Join(bootDir, name)
+		if fi, err = os.Lstat(kernel); err == nil {
+			break
+		}
+	}
 	if err != nil {
-		return "", err
+		// Distribution kernels are named after their version
+		if kernel, err = findVersionedKernel(bootDir); err != nil {
+			return "", err
+		}
+		if fi, err = os.Lstat(kernel); err != nil {
+			return "", err
+		}
 	}
 
 	if fi.Mode()&os.ModeSymlink == 0 {
@@ -247,6 +511,29 @@ func findKernel(tmpDir string) (string, error) {
 	// Return the path relative to the boot directory
 	return path.Join(bootDir, kernel), nil
 }
+
+// findVersionedKernel looks up the kernel by its names suffixed with a version, like the
+// vmlinuz-<version> of distribution kernel packages. Only one kernel may match.
+func findVersionedKernel(bootDir string) (string, error) {
+	for _, name := range arch.Host.KernelFiles {
+		matches, err := filepath.Glob(path.Join(bootDir, name+"-*"))
+		if err != nil {
+			return "", err
+		}
+
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			return "", fmt.Errorf("found multiple kernels in /boot: %v", matches)
+		}
+	}
+
+	return "", fmt.Errorf("no kernel found in /boot, looked for %v", arch.Host.KernelFiles)
+}
+
 func findInitrd(tmpDir string) (string, error) {
 	// find the path to the kernel, resolve symlinks if necessary
 	bootDir := path.Join(tmpDir, "boot")
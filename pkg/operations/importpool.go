@@ -0,0 +1,171 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/arch"
+	"github.com/weaveworks/ignite/pkg/client"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/version"
+	"github.com/weaveworks/libgitops/pkg/filter"
+)
+
+// importSlots limits how many images and kernels this process imports at once. It's
+// sized from the ignite configuration on first use.
+var importSlots struct {
+	once  sync.Once
+	slots chan struct{}
+}
+
+// importConcurrency returns how many images and kernels are imported at once
+func importConcurrency() int {
+	if providers.ImageImport.Concurrency > 0 {
+		return providers.ImageImport.Concurrency
+	}
+
+	return constants.IMAGE_IMPORT_CONCURRENCY
+}
+
+// acquireImportSlot waits until an import may start, and returns the function
+// releasing the slot when the import is done
+func acquireImportSlot(ctx context.Context) (func(), error) {
+	importSlots.once.Do(func() {
+		importSlots.slots = make(chan struct{}, importConcurrency())
+	})
+
+	select {
+	case importSlots.slots <- struct{}{}:
+	default:
+		log.Infof("Waiting for one of the %d running imports to finish...", cap(importSlots.slots))
+		select {
+		case importSlots.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() { <-importSlots.slots }, nil
+}
+
+// FindOrImportImageAndKernel finds or imports the image and the kernel of a VM at the
+// same time, as far as the import concurrency allows. If the kernel is built for another
+// architecture than the image, the default kernel is replaced by its build for the image's
+// architecture, other kernels fail the VM before it's booted. Disk images boot their own
+// kernel, no kernel is returned for them.
+func FindOrImportImageAndKernel(ctx context.Context, c *client.Client, imageRef, kernelRef meta.OCIImageRef) (*api.Image, *api.Kernel, error) {
+	// The kernel isn't imported for disk images found already
+	if image, err := c.Images().Find(filter.NewIDNameFilter(imageRef.String())); err == nil && image.Status.OCISource.Format == api.ImageFormatDisk {
+		return diskImage(image, imageRef)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		kernel    *api.Kernel
+		kernelErr error
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		kernel, kernelErr = FindOrImportKernel(ctx, c, kernelRef)
+	}()
+
+	image, err := FindOrImportImage(ctx, c, imageRef)
+	wg.Wait()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if image.Status.OCISource.Format == api.ImageFormatDisk {
+		if kernelErr != nil {
+			log.Debugf("Ignoring the kernel of disk image %q: %v", imageRef, kernelErr)
+		}
+		return diskImage(image, imageRef)
+	}
+
+	if kernelErr != nil {
+		return nil, nil, kernelErr
+	}
+
+	// An image found in a storage shared with other hosts may be built for another architecture
+	if err := arch.Check(fmt.Sprintf("image %q", imageRef), image.Status.OCISource.Architecture); err != nil {
+		return nil, nil, err
+	}
+
+	if kernel, err = matchKernel(ctx, c, image, kernel); err != nil {
+		return nil, nil, err
+	}
+
+	return image, kernel, nil
+}
+
+// diskImage returns the disk image if it's built for the host, without a kernel
+func diskImage(image *api.Image, imageRef meta.OCIImageRef) (*api.Image, *api.Kernel, error) {
+	if err := arch.Check(fmt.Sprintf("image %q", imageRef), image.Status.OCISource.Architecture); err != nil {
+		return nil, nil, err
+	}
+
+	return image, nil, nil
+}
+
+// matchKernel returns a kernel built for the architecture of the image, using the
+// architectures recorded when they were imported. Images of unknown architecture are run
+// on the host's architecture, kernels of unknown architecture are returned as they are.
+func matchKernel(ctx context.Context, c *client.Client, image *api.Image, kernel *api.Kernel) (*api.Kernel, error) {
+	imageArch := image.Status.OCISource.Architecture
+	if len(imageArch) == 0 {
+		imageArch = arch.Host.Name
+	}
+
+	kernelArch := kernel.Status.OCISource.Architecture
+	if len(kernelArch) == 0 || kernelArch == imageArch {
+		return kernel, nil
+	}
+
+	mismatch := fmt.Errorf("kernel %q is built for %s, but image %q is built for %s, select a kernel for %s",
+		kernel.Spec.OCI, kernelArch, image.Spec.OCI, imageArch, imageArch)
+
+	archRef, ok := archKernelRef(kernel.Spec.OCI, imageArch)
+	if !ok {
+		return nil, mismatch
+	}
+
+	log.Infof("Kernel %q is built for %s, using %q for image %q", kernel.Spec.OCI, kernelArch, archRef, image.Spec.OCI)
+	archKernel, err := FindOrImportKernel(ctx, c, archRef)
+	if err != nil {
+		return nil, err
+	}
+
+	// The kernel is checked to be built for the host when it's imported, but may be found
+	// in a shared storage
+	if a := archKernel.Status.OCISource.Architecture; len(a) != 0 && a != imageArch {
+		return nil, mismatch
+	}
+
+	return archKernel, nil
+}
+
+// archKernelRef returns the build of the default kernel for the architecture. The default
+// kernel is published for each architecture with the architecture suffixed to its tag, e.g.
+// weaveworks/ignite-kernel:5.10.51-arm64. Other kernels have no known builds.
+func archKernelRef(kernelRef meta.OCIImageRef, archName string) (meta.OCIImageRef, bool) {
+	defaultKernel := version.GetIgnite().KernelImage
+	defaultRef, err := meta.NewOCIImageRef(defaultKernel.String())
+	if err != nil || kernelRef.String() != defaultRef.String() || defaultKernel.Delimeter != ":" {
+		return meta.OCIImageRef{}, false
+	}
+
+	defaultKernel.Tag += "-" + archName
+	archRef, err := meta.NewOCIImageRef(defaultKernel.String())
+	if err != nil {
+		return meta.OCIImageRef{}, false
+	}
+
+	return archRef, true
+}
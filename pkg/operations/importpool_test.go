@@ -0,0 +1,89 @@
+package operations
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/flock"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/version"
+)
+
+func TestAcquireImportSlot(t *testing.T) {
+	providers.ImageImport.Concurrency = 2
+	defer func() { providers.ImageImport.Concurrency = 0 }()
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := acquireImportSlot(context.Background())
+		assert.NilError(t, err)
+		releases = append(releases, release)
+	}
+
+	// The third import waits until one of the others is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := acquireImportSlot(ctx)
+	assert.Equal(t, err, context.DeadlineExceeded)
+
+	releases[0]()
+	release, err := acquireImportSlot(context.Background())
+	assert.NilError(t, err)
+	release()
+	releases[1]()
+}
+
+func TestLockImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-operations-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	flock.Dir = dir
+
+	ref, err := meta.NewOCIImageRef("weaveworks/ignite-ubuntu:latest")
+	assert.NilError(t, err)
+
+	unlock, err := lockImport("image", ref)
+	assert.NilError(t, err)
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := lockImport("image", ref)
+		assert.NilError(t, err)
+		defer unlock()
+		close(locked)
+	}()
+
+	// A kernel with the same reference is imported independently
+	unlockKernel, err := lockImport("kernel", ref)
+	assert.NilError(t, err)
+	unlockKernel()
+
+	select {
+	case <-locked:
+		t.Fatal("the image was locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	<-locked
+}
+
+func TestArchKernelRef(t *testing.T) {
+	defaultRef, err := meta.NewOCIImageRef(version.GetIgnite().KernelImage.String())
+	assert.NilError(t, err)
+
+	archRef, ok := archKernelRef(defaultRef, "arm64")
+	assert.Assert(t, ok)
+	assert.Equal(t, archRef.String(), defaultRef.String()+"-arm64")
+
+	// Other kernels have no known builds for the other architectures
+	otherRef, err := meta.NewOCIImageRef("example.com/my-kernel:5.10")
+	assert.NilError(t, err)
+	_, ok = archKernelRef(otherRef, "arm64")
+	assert.Assert(t, !ok)
+}
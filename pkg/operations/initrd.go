@@ -0,0 +1,76 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/policy"
+	"github.com/weaveworks/ignite/pkg/source"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"github.com/weaveworks/ignite/pkg/util"
+	"go.opencensus.io/trace"
+)
+
+// initrdFile returns the path of the initramfs of the initrd image of the VM, in the VM's
+// own directory, so it's removed with the VM
+func initrdFile(vm *api.VM) string {
+	return path.Join(vm.ObjectPath(), constants.INITRD_FILE)
+}
+
+// importInitrd extracts the initramfs of the initrd image of the VM into its directory,
+// unless it's been extracted by an earlier start. The initramfs is at /boot/initrd in the
+// image, like in the kernel images.
+func importInitrd(ctx context.Context, vm *api.VM) (err error) {
+	ref := vm.Spec.Kernel.InitrdRef
+	if ref == nil || util.FileExists(initrdFile(vm)) {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "initrd.import", trace.StringAttribute("oci", ref.String()))
+	defer func() { tracing.End(span, err) }()
+
+	// The initramfs is booted like a kernel, it's allowed like one
+	if err := policy.CheckImport(ctx, policy.OperationKernelImport, *ref); err != nil {
+		return err
+	}
+
+	dockerSource := source.NewDockerSource()
+	if _, err := parseSource(ctx, dockerSource, *ref); err != nil {
+		return err
+	}
+
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := source.TarExtract(dockerSource, tempDir, "boot"); err != nil {
+		return fmt.Errorf("failed to extract initrd image %q: %v", ref, err)
+	}
+
+	initrdTmpFile, err := findInitrd(tempDir)
+	if err != nil {
+		return fmt.Errorf("initrd image %q has no initramfs at /boot/%s: %v", ref, constants.INITRD_FILE, err)
+	}
+
+	// Copy the initramfs under another name first, so a failed copy isn't booted by the next start
+	partialFile := initrdFile(vm) + ".partial"
+	if err := util.CopyFile(initrdTmpFile, partialFile); err != nil {
+		os.Remove(partialFile)
+		return fmt.Errorf("failed to copy the initramfs of initrd image %q: %v", ref, err)
+	}
+
+	if err := os.Rename(partialFile, initrdFile(vm)); err != nil {
+		return err
+	}
+
+	log.Infof("Imported initrd image %q for VM %q", ref, vm.GetUID())
+	return nil
+}
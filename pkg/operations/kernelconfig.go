@@ -0,0 +1,44 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/kernelconfig"
+)
+
+// checkKernelConfig verifies the config of the kernel, embedded in the kernel or bundled
+// in /boot of the kernel image. The kernel is rejected if the VMs can't boot without the
+// options it's missing, other missing options are warned about.
+func checkKernelConfig(kernel *api.Kernel, vmlinuxFile, kernelTarFile string) error {
+	cfg, err := kernelconfig.Embedded(vmlinuxFile)
+	if errors.Is(err, kernelconfig.ErrNoConfig) {
+		cfg, err = kernelconfig.Bundled(kernelTarFile)
+	}
+	if errors.Is(err, kernelconfig.ErrNoConfig) {
+		log.Warnf("Kernel %q has no embedded config (CONFIG_IKCONFIG) or config file in /boot, its options can't be verified", kernel.Spec.OCI)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the config of kernel %q: %v", kernel.Spec.OCI, err)
+	}
+
+	boot, other := kernelconfig.Missing(cfg, kernel.Spec.HasInitrd)
+	for _, r := range other {
+		log.Warnf("Kernel %q doesn't have CONFIG_%s, the VMs need it for %s", kernel.Spec.OCI, r.Option, r.Purpose)
+	}
+
+	if len(boot) > 0 {
+		missing := make([]string, 0, len(boot))
+		for _, r := range boot {
+			missing = append(missing, fmt.Sprintf("CONFIG_%s (%s)", r.Option, r.Purpose))
+		}
+
+		return fmt.Errorf("kernel %q can't boot the VMs, it doesn't have %s built in", kernel.Spec.OCI, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/estargz"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"go.opencensus.io/trace"
+)
+
+// LoadFilesInBackground makes StartVM return once a VM of a lazily imported image has
+// booted, while the rest of the files of the image are loaded. It's set by ignited.
+var LoadFilesInBackground bool
+
+// startLoadingFiles loads the files of the lazily imported image of the started VM that
+// weren't needed at boot, unless they have been loaded into the VM already
+func startLoadingFiles(ctx context.Context, vm *api.VM) error {
+	if vm.ConditionTrue(api.VMFilesLoaded) {
+		return nil
+	}
+
+	image, err := providers.Client.Images().Find(filter.NewNameFilter(vm.Spec.Image.OCI.String()))
+	if err != nil {
+		return err
+	}
+
+	if len(image.Status.LazyRef) == 0 {
+		return nil
+	}
+
+	if LoadFilesInBackground {
+		// The files are loaded past the request that started the VM, only its trace is kept
+		ctx := trace.NewContext(context.Background(), trace.FromContext(ctx))
+		go func() {
+			if err := loadFiles(ctx, vm, image.Status.LazyRef); err != nil {
+				log.Errorf("Failed to load the files of VM %q: %v", vm.GetUID(), err)
+			}
+		}()
+		return nil
+	}
+
+	log.Infof("VM %q has booted, loading the rest of the files of image %q...", vm.GetUID(), vm.Spec.Image.OCI)
+	return loadFiles(ctx, vm, image.Status.LazyRef)
+}
+
+// loadFiles reads the files that aren't in the filesystem of the lazily imported image
+// from the registry, and streams them to the guest agent of the VM, which writes them
+// into its disk. The files the VM has written since it booted are kept.
+func loadFiles(ctx context.Context, vm *api.VM, lazyRef string) (err error) {
+	ctx, span := tracing.Start(ctx, "vm.files")
+	defer func() { tracing.End(span, err) }()
+
+	// The VM is running now, which the agent client requires
+	vm, err = providers.Client.VMs().Get(vm.GetUID())
+	if err != nil {
+		return err
+	}
+
+	image, err := estargz.Open(ctx, lazyRef)
+	if err != nil {
+		return failFilesLoaded(vm, "ReadFailed", err)
+	}
+	defer image.Close()
+
+	if err := agent.Wait(vm, constants.LOAD_FILES_TIMEOUT); err != nil {
+		return failFilesLoaded(vm, "AgentUnreachable", err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(image.FS.WriteRest(pw))
+	}()
+
+	err = agent.Extract(vm, pr)
+	pr.Close()
+	if err != nil {
+		return failFilesLoaded(vm, "LoadFailed", err)
+	}
+
+	_, rest := image.FS.Sizes()
+	log.Infof("Loaded the rest of the files of image %q into VM %q", vm.Spec.Image.OCI, vm.GetUID())
+
+	vm.SetCondition(api.VMFilesLoaded, api.ConditionTrue, "Loaded", fmt.Sprintf("loaded %d bytes of files", rest))
+	return providers.Client.VMs().Set(vm)
+}
+
+// failFilesLoaded records the failure to load the files of the VM in its condition
+func failFilesLoaded(vm *api.VM, reason string, err error) error {
+	return failCondition(vm, api.VMFilesLoaded, reason, err)
+}
@@ -0,0 +1,20 @@
+package operations
+
+import (
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/flock"
+)
+
+// lockVM locks the lifecycle operations of the VM, and returns the function unlocking them.
+// VMs are started, stopped and removed in parallel, but a VM is never started and stopped at
+// the same time, also not by different ignite processes.
+func lockVM(vm *api.VM) (func(), error) {
+	return flock.Lock(flock.ObjectName(vm))
+}
+
+// lockImport locks the imports of the object of the given kind with the OCI reference, so
+// it's imported once, and returns the function unlocking them
+func lockImport(kind string, ociRef meta.OCIImageRef) (func(), error) {
+	return flock.Lock("import-" + kind + "-" + ociRef.String())
+}
@@ -17,10 +17,14 @@ func ImageUIDForVM(vm *api.VM, c *client.Client) (runtime.UID, error) {
 }
 
 func KernelUIDForVM(vm *api.VM, c *client.Client) (runtime.UID, error) {
-	kernel, err := c.Kernels().Find(filter.NewNameFilter(vm.Spec.Kernel.OCI.String()))
+	kernel, err := KernelForVM(vm, c)
 	if err != nil {
 		return "", err
 	}
 
 	return kernel.GetUID(), nil
 }
+
+func KernelForVM(vm *api.VM, c *client.Client) (*api.Kernel, error) {
+	return c.Kernels().Find(filter.NewNameFilter(vm.Spec.Kernel.OCI.String()))
+}
@@ -0,0 +1,20 @@
+package operations
+
+import (
+	"time"
+
+	go_prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// ImportDuration observes how long importing the OCI images of images and kernels takes,
+// ignited registers it with its metrics
+var ImportDuration = go_prom.NewHistogramVec(go_prom.HistogramOpts{
+	Name:    "oci_import_duration_seconds",
+	Help:    "How long importing the OCI images of images and kernels takes",
+	Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600},
+}, []string{"kind"})
+
+// observeImport observes the duration of the import of the given kind started at start
+func observeImport(kind string, start time.Time) {
+	ImportDuration.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+}
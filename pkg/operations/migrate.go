@@ -0,0 +1,133 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/agent"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/container"
+	"github.com/weaveworks/ignite/pkg/migration"
+	"github.com/weaveworks/ignite/pkg/network"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/tracing"
+)
+
+// snapshotTimeout bounds writing the guest memory to the snapshot
+const snapshotTimeout = 5 * time.Minute
+
+// PrepareMigration pauses the running VM and takes a full Firecracker snapshot of it into
+// its migration directory. This requires a Firecracker version supporting snapshots
+// (v0.24 or newer) in the sandbox image of the VM. The VM stays paused until it's either
+// removed after a successful migration, or resumed by AbortMigration.
+func PrepareMigration(vm *api.VM) (err error) {
+	if !vm.Running() {
+		return fmt.Errorf("VM %q is not running", vm.GetUID())
+	}
+
+	if vm.Paused() {
+		return fmt.Errorf("VM %q is paused, resume it before migrating it", vm.GetUID())
+	}
+
+	if err := requireFirecracker(vm, "snapshotted"); err != nil {
+		return err
+	}
+
+	devicePath, err := SnapshotDevice(vm)
+	if err != nil {
+		return
+	}
+
+	if err = setFirecrackerState(vm, firecrackerStatePaused); err != nil {
+		return fmt.Errorf("failed to pause VM %q, migration requires Firecracker v0.24 or newer: %v", vm.GetUID(), err)
+	}
+
+	defer func() {
+		if err != nil {
+			if abortErr := AbortMigration(vm); abortErr != nil {
+				log.Errorf("Failed to resume VM %q: %v", vm.GetUID(), abortErr)
+			}
+		}
+	}()
+
+	if err = migration.WriteManifest(vm, &migration.Manifest{
+		DrivePath:   devicePath,
+		IPAddresses: vm.Status.Network.IPAddresses,
+	}); err != nil {
+		return
+	}
+
+	// The VM directory is mounted at the same path in the container, so Firecracker can write there
+	if err = container.FirecrackerRequest(vm, http.MethodPut, "/snapshot/create", map[string]string{
+		"snapshot_type": "Full",
+		"snapshot_path": migration.File(vm, migration.StateFile),
+		"mem_file_path": migration.File(vm, migration.MemoryFile),
+	}, snapshotTimeout); err != nil {
+		return fmt.Errorf("failed to snapshot VM %q: %v", vm.GetUID(), err)
+	}
+
+	return nil
+}
+
+// AbortMigration resumes a VM paused by PrepareMigration, and removes its snapshot
+func AbortMigration(vm *api.VM) error {
+	if err := migration.Remove(vm); err != nil {
+		return err
+	}
+
+	return setFirecrackerState(vm, firecrackerStateResumed)
+}
+
+// readdressIdentity returns the identity giving the guest the first IPv4 address assigned
+// by the network, and its gateway, or nil if no IPv4 address was assigned
+func readdressIdentity(result *network.Result) *protocol.Identity {
+	for _, addr := range result.Addresses {
+		if addr.IP.IsLoopback() || addr.IP.To4() == nil {
+			continue
+		}
+
+		identity := &protocol.Identity{Address: addr.IP.String()}
+		if addr.Gateway != nil {
+			identity.Gateway = addr.Gateway.String()
+		}
+
+		return identity
+	}
+
+	return nil
+}
+
+// readdressRestoredVM changes the address of the guest of a VM restored from a snapshot to
+// the one assigned by the network of this host, through the guest agent. Without the agent,
+// the VM is stopped, as neither the IPAM of the network nor the port mappings would match
+// the addresses the guest kept.
+func readdressRestoredVM(ctx context.Context, vm *api.VM, identity *protocol.Identity) (err error) {
+	_, span := tracing.Start(ctx, "vm.readdress")
+	defer func() { tracing.End(span, err) }()
+
+	// The VM is running now, which the agent client requires
+	if vm, err = providers.Client.VMs().Get(vm.GetUID()); err != nil {
+		return
+	}
+
+	if err = agent.Wait(vm, constants.READDRESS_TIMEOUT); err == nil {
+		err = agent.SetIdentity(vm, identity)
+	}
+
+	if err != nil {
+		if stopErr := stopVM(ctx, vm, true, true); stopErr != nil {
+			log.Errorf("Failed to stop VM %q: %v", vm.GetUID(), stopErr)
+		}
+
+		return fmt.Errorf("VM %q was restored with other addresses than the network assigned, "+
+			"and the guest agent failed to change them to %s: %v", vm.GetUID(), identity.Address, err)
+	}
+
+	VMLog(vm, "start").Infof("Changed the address of restored VM %q to %s", vm.GetUID(), identity.Address)
+	return nil
+}
@@ -0,0 +1,45 @@
+package operations
+
+import (
+	"net"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+	"github.com/weaveworks/ignite/pkg/network"
+)
+
+func TestReaddressIdentity(t *testing.T) {
+	cases := []struct {
+		name     string
+		result   *network.Result
+		expected *protocol.Identity
+	}{
+		{
+			name: "first IPv4 address with its gateway",
+			result: &network.Result{Addresses: []network.Address{
+				{IP: net.ParseIP("127.0.0.1")},
+				{IP: net.ParseIP("fd00::2"), Gateway: net.ParseIP("fd00::1")},
+				{IP: net.ParseIP("10.61.0.7"), Gateway: net.ParseIP("10.61.0.1")},
+				{IP: net.ParseIP("10.62.0.7"), Gateway: net.ParseIP("10.62.0.1")},
+			}},
+			expected: &protocol.Identity{Address: "10.61.0.7", Gateway: "10.61.0.1"},
+		},
+		{
+			name:     "address without a gateway",
+			result:   &network.Result{Addresses: []network.Address{{IP: net.ParseIP("172.17.0.3")}}},
+			expected: &protocol.Identity{Address: "172.17.0.3"},
+		},
+		{
+			name:   "no IPv4 address",
+			result: &network.Result{Addresses: []network.Address{{IP: net.ParseIP("fd00::2")}}},
+		},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.DeepEqual(t, readdressIdentity(rt.result), rt.expected)
+		})
+	}
+}
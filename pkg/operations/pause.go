@@ -0,0 +1,145 @@
+package operations
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/container"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+const (
+	// The states of the Firecracker VM API, see PATCH /vm
+	firecrackerStatePaused  = "Paused"
+	firecrackerStateResumed = "Resumed"
+)
+
+// PauseVM freezes the vCPUs of the running VM, keeping its memory and device state.
+// The VM is paused using the Firecracker API, if the Firecracker version doesn't
+// support pausing, the Firecracker process is stopped with SIGSTOP instead.
+func PauseVM(vm *api.VM) error {
+	if !vm.Running() {
+		return fmt.Errorf("VM %q is not running", vm.GetUID())
+	}
+
+	if vm.Paused() {
+		return fmt.Errorf("VM %q is already paused", vm.GetUID())
+	}
+
+	if err := requireFirecracker(vm, "paused"); err != nil {
+		return err
+	}
+
+	if err := setFirecrackerState(vm, firecrackerStatePaused); err != nil {
+		log.Debugf("Failed to pause VM %q using the Firecracker API, stopping its process instead: %v", vm.GetUID(), err)
+
+		pid, err := firecrackerPID(vm)
+		if err != nil {
+			return fmt.Errorf("failed to pause VM %q: %v", vm.GetUID(), err)
+		}
+
+		if err := syscall.Kill(pid, syscall.SIGSTOP); err != nil {
+			return fmt.Errorf("failed to pause VM %q: %v", vm.GetUID(), err)
+		}
+	}
+
+	vm.Status.Paused = true
+	return providers.Client.VMs().Set(vm)
+}
+
+// ResumeVM unfreezes the vCPUs of a VM paused by PauseVM
+func ResumeVM(vm *api.VM) error {
+	if !vm.Paused() {
+		return fmt.Errorf("VM %q is not paused", vm.GetUID())
+	}
+
+	pid, err := firecrackerPID(vm)
+	if err != nil {
+		return fmt.Errorf("failed to resume VM %q: %v", vm.GetUID(), err)
+	}
+
+	// A Firecracker process stopped by SIGSTOP can't serve API requests,
+	// it's resumed by continuing the process instead
+	stopped, err := processStopped(pid)
+	if err != nil {
+		return fmt.Errorf("failed to resume VM %q: %v", vm.GetUID(), err)
+	}
+
+	if stopped {
+		err = syscall.Kill(pid, syscall.SIGCONT)
+	} else {
+		err = setFirecrackerState(vm, firecrackerStateResumed)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to resume VM %q: %v", vm.GetUID(), err)
+	}
+
+	vm.Status.Paused = false
+	return providers.Client.VMs().Set(vm)
+}
+
+// setFirecrackerState requests the Firecracker process of the VM to change the state of the VM
+func setFirecrackerState(vm *api.VM, state string) error {
+	return container.FirecrackerRequest(vm, http.MethodPatch, "/vm", map[string]string{"state": state}, 0)
+}
+
+// firecrackerPID returns the host PID of the Firecracker process of the VM,
+// which is a descendant of the init process of the VM container
+func firecrackerPID(vm *api.VM) (int, error) {
+	result, err := providers.Runtime.InspectContainer(vm.PrefixedID())
+	if err != nil {
+		return 0, err
+	}
+
+	queue := []int{int(result.PID)}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+
+		if comm, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/comm", pid)); err == nil && strings.TrimSpace(string(comm)) == "firecracker" {
+			return pid, nil
+		}
+
+		// Children are listed per thread that forked them
+		childFiles, _ := filepath.Glob(fmt.Sprintf("/proc/%d/task/*/children", pid))
+		for _, childFile := range childFiles {
+			children, err := ioutil.ReadFile(childFile)
+			if err != nil {
+				continue
+			}
+
+			for _, child := range strings.Fields(string(children)) {
+				if childPID, err := strconv.Atoi(child); err == nil {
+					queue = append(queue, childPID)
+				}
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("no Firecracker process found in container %q", vm.PrefixedID())
+}
+
+// processStopped returns true if the process is stopped by a signal
+func processStopped(pid int) (bool, error) {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false, err
+	}
+
+	// The state follows the command name in parentheses, which may contain spaces
+	fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+	if len(fields) == 0 {
+		return false, fmt.Errorf("failed to parse the state of process %d", pid)
+	}
+
+	return fields[0] == "T", nil
+}
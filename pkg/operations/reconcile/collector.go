@@ -0,0 +1,91 @@
+package reconcile
+
+import (
+	go_prom "github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/container"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// The states the VMs are counted by
+const (
+	vmStateRunning = "running"
+	vmStatePaused  = "paused"
+	vmStateStopped = "stopped"
+)
+
+var (
+	vmLabels = []string{"uid", "name"}
+
+	vmCountDesc       = go_prom.NewDesc("vm_count", "The count of VMs on the host by state", []string{"state"}, nil)
+	vmVCPUsDesc       = go_prom.NewDesc("vm_vcpus", "The vCPUs of the VM", vmLabels, nil)
+	vmMemoryDesc      = go_prom.NewDesc("vm_memory_bytes", "The memory of the VM", vmLabels, nil)
+	vmCPUDesc         = go_prom.NewDesc("vm_cpu_percent", "The CPU usage of the running VM, in percent of a host CPU", vmLabels, nil)
+	vmMemoryUsageDesc = go_prom.NewDesc("vm_memory_usage_bytes", "The memory used by the Firecracker process of the running VM", vmLabels, nil)
+	vmDiskReadDesc    = go_prom.NewDesc("vm_disk_read_bytes_total", "The bytes read from the disks of the running VM", vmLabels, nil)
+	vmDiskWriteDesc   = go_prom.NewDesc("vm_disk_write_bytes_total", "The bytes written to the disks of the running VM", vmLabels, nil)
+	vmNetRxDesc       = go_prom.NewDesc("vm_network_receive_bytes_total", "The bytes received by the network interfaces of the running VM", vmLabels, nil)
+	vmNetTxDesc       = go_prom.NewDesc("vm_network_transmit_bytes_total", "The bytes transmitted by the network interfaces of the running VM", vmLabels, nil)
+)
+
+// vmCollector collects the count of the VMs of the host by state, and the resource usage
+// of the running ones, when the metrics are scraped
+type vmCollector struct{}
+
+var _ go_prom.Collector = vmCollector{}
+
+func (vmCollector) Describe(ch chan<- *go_prom.Desc) {
+	for _, desc := range []*go_prom.Desc{vmCountDesc, vmVCPUsDesc, vmMemoryDesc, vmCPUDesc, vmMemoryUsageDesc, vmDiskReadDesc, vmDiskWriteDesc, vmNetRxDesc, vmNetTxDesc} {
+		ch <- desc
+	}
+}
+
+func (vmCollector) Collect(ch chan<- go_prom.Metric) {
+	vms, err := providers.Client.VMs().List()
+	if err != nil {
+		log.Errorf("Failed to list the VMs for their metrics: %v", err)
+		return
+	}
+
+	counts := map[string]int{vmStateRunning: 0, vmStatePaused: 0, vmStateStopped: 0}
+	for _, vm := range vms {
+		counts[vmState(vm)]++
+
+		labels := []string{vm.GetUID().String(), vm.GetName()}
+		ch <- go_prom.MustNewConstMetric(vmVCPUsDesc, go_prom.GaugeValue, float64(vm.Spec.CPUs), labels...)
+		ch <- go_prom.MustNewConstMetric(vmMemoryDesc, go_prom.GaugeValue, float64(vm.Spec.Memory.Bytes()), labels...)
+		if !vm.Running() {
+			continue
+		}
+
+		// The stats are collected by ignite-spawn while the VM runs
+		stats, err := container.ReadStats(vm)
+		if err != nil {
+			log.Debugf("Failed to read the stats of VM %q: %v", vm.GetUID(), err)
+			continue
+		}
+
+		ch <- go_prom.MustNewConstMetric(vmCPUDesc, go_prom.GaugeValue, stats.CPUPercent, labels...)
+		ch <- go_prom.MustNewConstMetric(vmMemoryUsageDesc, go_prom.GaugeValue, float64(stats.MemoryUsage), labels...)
+		ch <- go_prom.MustNewConstMetric(vmDiskReadDesc, go_prom.CounterValue, float64(stats.DiskReadBytes), labels...)
+		ch <- go_prom.MustNewConstMetric(vmDiskWriteDesc, go_prom.CounterValue, float64(stats.DiskWriteBytes), labels...)
+		ch <- go_prom.MustNewConstMetric(vmNetRxDesc, go_prom.CounterValue, float64(stats.NetRxBytes), labels...)
+		ch <- go_prom.MustNewConstMetric(vmNetTxDesc, go_prom.CounterValue, float64(stats.NetTxBytes), labels...)
+	}
+
+	for state, count := range counts {
+		ch <- go_prom.MustNewConstMetric(vmCountDesc, go_prom.GaugeValue, float64(count), state)
+	}
+}
+
+// vmState returns the state the VM is counted by
+func vmState(vm *api.VM) string {
+	if vm.Paused() {
+		return vmStatePaused
+	} else if vm.Running() {
+		return vmStateRunning
+	}
+
+	return vmStateStopped
+}
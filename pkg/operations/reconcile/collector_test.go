@@ -0,0 +1,33 @@
+package reconcile
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+func TestVMState(t *testing.T) {
+	tests := []struct {
+		name    string
+		running bool
+		paused  bool
+		state   string
+	}{
+		{"stopped", false, false, vmStateStopped},
+		{"running", true, false, vmStateRunning},
+		{"paused", true, true, vmStatePaused},
+		// A stopped VM isn't paused, even if it was when it stopped
+		{"stopped while paused", false, true, vmStateStopped},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			vm := &api.VM{}
+			vm.Status.Running = rt.running
+			vm.Status.Paused = rt.paused
+			assert.Equal(t, vmState(vm), rt.state)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/audit"
+	"github.com/weaveworks/ignite/pkg/events"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+	"go.opencensus.io/trace"
+)
+
+// drift describes how a VM drifted from its manifest
+type drift struct {
+	// removed is set if the VM was removed from the host
+	removed bool
+	// spec is set if the spec of the VM differs from the one of its manifest
+	spec bool
+	// running is set if the VM runs while its manifest has it stopped, or the other way around
+	running bool
+}
+
+func (d drift) String() string {
+	var reasons []string
+	if d.removed {
+		reasons = append(reasons, "it was removed")
+	}
+	if d.spec {
+		reasons = append(reasons, "its spec changed")
+	}
+	if d.running {
+		reasons = append(reasons, "its running state changed")
+	}
+
+	return strings.Join(reasons, " and ")
+}
+
+func (d drift) drifted() bool {
+	return d.removed || d.spec || d.running
+}
+
+// detectDrift compares the VM to its manifest. The VM is nil if it was removed, running is
+// whether its container runs. The spec only drifts once the VM is created, as the spec in
+// the manifest is written to the VM when it's created.
+func detectDrift(manifest, vm *api.VM, running, created bool) (d drift) {
+	if vm == nil {
+		d.removed = true
+		d.running = manifest.Status.Running
+		return
+	}
+
+	if created {
+		spec := manifest.Spec.DeepCopy()
+		// Whether the kernel has an initrd is set from the imported kernel
+		spec.Kernel.HasInitrd = vm.Spec.Kernel.HasInitrd
+		d.spec = !reflect.DeepEqual(*spec, vm.Spec)
+	}
+
+	d.running = manifest.Status.Running != running
+	return
+}
+
+// correctDrift compares the VMs to their manifests, and corrects the VMs that drifted from
+// them. Removed VMs are created again, VMs with a changed spec get the spec of their manifest
+// and are restarted if they run, and VMs are started or stopped like their manifests have them.
+func (r *reconciler) correctDrift() {
+	for uid, manifest := range r.manifests {
+		vm, err := providers.Client.VMs().Get(uid)
+		if _, ok := err.(*filterer.NonexistentError); ok {
+			vm = nil
+		} else if err != nil {
+			log.Errorf("Failed to get VM %q to detect its drift: %v", uid, err)
+			continue
+		}
+
+		running := vm != nil && currentState(vm)
+		created := vm != nil && util.FileExists(vm.OverlayFile())
+		d := detectDrift(manifest, vm, running, created)
+		if !d.drifted() {
+			continue
+		}
+
+		if r.dryRun() {
+			log.Infof("Dry run: would correct VM %q with name %q%s, %s", uid, manifest.GetName(), r.inEnv(), d)
+			continue
+		}
+
+		operations.VMLog(manifest, "correct-drift").Infof("Correcting VM %q with name %q%s, %s", uid, manifest.GetName(), r.inEnv(), d)
+		ctx, span := r.startSpan("gitops.correct-drift", trace.StringAttribute("uid", uid.String()),
+			trace.StringAttribute("name", manifest.GetName()), trace.StringAttribute("drift", d.String()))
+		var correction string
+		err = jobs.Run(ctx, jobs.ClassReconcile, jobs.Target(manifest), audit.SourceReconcile, func() (err error) {
+			correction, err = r.correct(ctx, manifest, vm, d, running)
+			return
+		})
+		tracing.End(span, err)
+		r.recordSync(uid, err)
+		if err != nil {
+			reconcileErrors.Inc()
+			log.Errorf("Failed to correct the drift of VM %q%s: %v", uid, r.inEnv(), err)
+			continue
+		}
+
+		vmDriftCorrected.Inc()
+		e := events.New(events.VMDriftCorrected, api.KindVM, manifest)
+		e.Message = fmt.Sprintf("%s, as %s", correction, d)
+		for _, sink := range r.sinks {
+			sink.Send(e)
+		}
+	}
+}
+
+// correct corrects the drift of the VM, and describes the correction
+func (r *reconciler) correct(ctx context.Context, manifest, vm *api.VM, d drift, running bool) (string, error) {
+	var actions []string
+	if d.removed {
+		vm = manifest.DeepCopy()
+		if err := r.c.VMs().Set(vm); err != nil {
+			return "", err
+		}
+
+		actions = append(actions, "created")
+	} else if d.spec {
+		hasInitrd := vm.Spec.Kernel.HasInitrd
+		vm.Spec = *manifest.Spec.DeepCopy()
+		vm.Spec.Kernel.HasInitrd = hasInitrd
+		if err := r.c.VMs().Set(vm); err != nil {
+			return "", err
+		}
+
+		actions = append(actions, "reset the spec")
+		if running {
+			if err := stop(ctx, vm); err != nil {
+				return "", err
+			}
+
+			running = false
+			actions = append(actions, "stopped")
+		}
+	}
+
+	if manifest.Status.Running && !running {
+		if err := r.start(ctx, vm); err != nil {
+			return "", err
+		}
+
+		actions = append(actions, "started")
+	} else if !manifest.Status.Running && running {
+		if err := stop(ctx, vm); err != nil {
+			return "", err
+		}
+
+		actions = append(actions, "stopped")
+	}
+
+	return strings.Join(actions, ", "), nil
+}
@@ -0,0 +1,35 @@
+package reconcile
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+func TestDetectDrift(t *testing.T) {
+	manifest := &api.VM{}
+	manifest.Spec.CPUs = 2
+	manifest.Status.Running = true
+
+	vm := manifest.DeepCopy()
+	vm.Spec.Kernel.HasInitrd = true
+	assert.Equal(t, detectDrift(manifest, vm, true, true), drift{}, "the initrd is set from the kernel")
+
+	assert.Equal(t, detectDrift(manifest, nil, false, false), drift{removed: true, running: true})
+	assert.Equal(t, detectDrift(manifest, vm, false, true), drift{running: true})
+
+	vm.Spec.CPUs = 4
+	d := detectDrift(manifest, vm, true, true)
+	assert.Equal(t, d, drift{spec: true})
+	assert.Equal(t, d.String(), "its spec changed")
+
+	// The spec of the manifest is written to the VM when it's created
+	assert.Equal(t, detectDrift(manifest, vm, false, false), drift{running: true})
+
+	manifest.Status.Running = false
+	d = detectDrift(manifest, vm, true, true)
+	assert.Assert(t, d.drifted())
+	assert.Equal(t, d.String(), "its spec changed and its running state changed")
+}
@@ -0,0 +1,275 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage/watch/update"
+)
+
+// reconcileImage imports the OCI image of an Image manifest into the data directory, and
+// imports it again when the OCI image changes. The storage removes the objects of deleted
+// manifests, along with their files.
+func (r *reconciler) reconcileImage(ctx context.Context, upd update.Update) {
+	if upd.Event == update.ObjectEventDelete {
+		image := &api.Image{
+			TypeMeta:   *upd.APIType.GetTypeMeta(),
+			ObjectMeta: *upd.APIType.GetObjectMeta(),
+		}
+
+		if r.dryRun() {
+			log.Infof("Dry run: would remove image %q with name %q%s", image.GetUID(), image.GetName(), r.inEnv())
+			return
+		}
+
+		runHandle(func() error {
+			return removeImage(image)
+		})
+		return
+	}
+
+	image, err := r.c.Images().Get(upd.APIType.GetUID())
+	if err != nil {
+		log.Errorf("Getting %s %q returned an error: %v", upd.APIType.GetKind(), upd.APIType.GetUID(), err)
+		return
+	}
+
+	if err := validation.ValidateImage(image).ToAggregate(); err != nil {
+		log.Warnf("Skipping %s of %s %q, not valid: %v.", upd.Event, upd.APIType.GetKind(), upd.APIType.GetUID(), err)
+		return
+	}
+
+	// VMs find their image by the name of its OCI image
+	if len(image.GetName()) == 0 {
+		image.SetName(image.Spec.OCI.String())
+	}
+
+	imported := path.Join(image.ObjectPath(), constants.IMAGE_FS)
+	if r.dryRun() {
+		r.planImport("image", image.GetUID(), image.Spec.OCI, imported, importedImage(image.GetUID()))
+		return
+	}
+
+	runHandle(func() error {
+		if !needsImport(image.Spec.OCI, imported, importedImage(image.GetUID())) {
+			return nil
+		}
+
+		if util.FileExists(imported) {
+			if vm, err := imageUser(image.GetName()); err != nil || vm != nil {
+				return inUse("image", image.GetUID(), vm, err)
+			}
+		}
+
+		log.Infof("Importing image %q with name %q from %q...", image.GetUID(), image.GetName(), image.Spec.OCI)
+		return audited(ctx, jobs.ClassImport, "image import", image, func() error {
+			if err := operations.PopulateImage(ctx, image); err != nil {
+				return err
+			}
+
+			imageImported.Inc()
+			return r.c.Images().Set(image)
+		})
+	})
+}
+
+// reconcileKernel imports the OCI image of a Kernel manifest into the data directory like
+// reconcileImage does for images
+func (r *reconciler) reconcileKernel(ctx context.Context, upd update.Update) {
+	if upd.Event == update.ObjectEventDelete {
+		kernel := &api.Kernel{
+			TypeMeta:   *upd.APIType.GetTypeMeta(),
+			ObjectMeta: *upd.APIType.GetObjectMeta(),
+		}
+
+		if r.dryRun() {
+			log.Infof("Dry run: would remove kernel %q with name %q%s", kernel.GetUID(), kernel.GetName(), r.inEnv())
+			return
+		}
+
+		runHandle(func() error {
+			return removeKernel(kernel)
+		})
+		return
+	}
+
+	kernel, err := r.c.Kernels().Get(upd.APIType.GetUID())
+	if err != nil {
+		log.Errorf("Getting %s %q returned an error: %v", upd.APIType.GetKind(), upd.APIType.GetUID(), err)
+		return
+	}
+
+	if err := validation.ValidateKernel(kernel).ToAggregate(); err != nil {
+		log.Warnf("Skipping %s of %s %q, not valid: %v.", upd.Event, upd.APIType.GetKind(), upd.APIType.GetUID(), err)
+		return
+	}
+
+	// VMs find their kernel by the name of its OCI image
+	if len(kernel.GetName()) == 0 {
+		kernel.SetName(kernel.Spec.OCI.String())
+	}
+
+	imported := path.Join(kernel.ObjectPath(), constants.KERNEL_FILE)
+	if r.dryRun() {
+		r.planImport("kernel", kernel.GetUID(), kernel.Spec.OCI, imported, importedKernel(kernel.GetUID()))
+		return
+	}
+
+	runHandle(func() error {
+		if !needsImport(kernel.Spec.OCI, imported, importedKernel(kernel.GetUID())) {
+			return nil
+		}
+
+		if util.FileExists(imported) {
+			if vm, err := kernelUser(kernel.GetName()); err != nil || vm != nil {
+				return inUse("kernel", kernel.GetUID(), vm, err)
+			}
+
+			// PopulateKernel keeps the files that exist, remove the ones of the previous OCI image
+			for _, file := range []string{constants.KERNEL_FILE, constants.KERNEL_COMPRESSED_FILE, constants.KERNEL_TAR, constants.INITRD_FILE} {
+				if err := os.RemoveAll(path.Join(kernel.ObjectPath(), file)); err != nil {
+					return err
+				}
+			}
+			kernel.Status.Version = ""
+		}
+
+		log.Infof("Importing kernel %q with name %q from %q...", kernel.GetUID(), kernel.GetName(), kernel.Spec.OCI)
+		return audited(ctx, jobs.ClassImport, "kernel import", kernel, func() error {
+			if err := operations.PopulateKernel(ctx, kernel); err != nil {
+				return err
+			}
+
+			kernelImported.Inc()
+			return r.c.Kernels().Set(kernel)
+		})
+	})
+}
+
+// needsImport returns true if the file of the imported OCI image doesn't exist, or if the OCI
+// image of the ref isn't the one imported. OCI images that aren't pulled yet are imported,
+// they're pulled by the import.
+func needsImport(ref meta.OCIImageRef, imported string, source *api.OCIImageSource) bool {
+	if !util.FileExists(imported) || source == nil || source.ID == nil {
+		return true
+	}
+
+	res, err := providers.Runtime.InspectImage(ref)
+	if err != nil {
+		return true
+	}
+
+	return res.ID.String() != source.ID.String()
+}
+
+// importedImage returns the OCI source of the last import of the image, from its status in the data directory
+func importedImage(uid runtime.UID) *api.OCIImageSource {
+	image, err := providers.Client.Images().Get(uid)
+	if err != nil {
+		return nil
+	}
+
+	return &image.Status.OCISource
+}
+
+// importedKernel returns the OCI source of the last import of the kernel, from its status in the data directory
+func importedKernel(uid runtime.UID) *api.OCIImageSource {
+	kernel, err := providers.Client.Kernels().Get(uid)
+	if err != nil {
+		return nil
+	}
+
+	return &kernel.Status.OCISource
+}
+
+// planImport logs whether the image or kernel would be imported
+func (r *reconciler) planImport(kind string, uid runtime.UID, ref meta.OCIImageRef, imported string, source *api.OCIImageSource) {
+	if !needsImport(ref, imported, source) {
+		log.Infof("Dry run: %s %q from %q%s is up to date", kind, uid, ref, r.inEnv())
+		return
+	}
+
+	action := "import"
+	if util.FileExists(imported) {
+		action = "import again"
+	}
+
+	log.Infof("Dry run: would %s %s %q from %q%s", action, kind, uid, ref, r.inEnv())
+}
+
+// removeImage removes what's left of the image, and warns about the VMs still using it
+func removeImage(image *api.Image) error {
+	log.Infof("Removing image %q with name %q...", image.GetUID(), image.GetName())
+	if vm, err := imageUser(image.GetName()); err == nil && vm != nil {
+		log.Warnf("Image %q is still used by VM %q, it's imported again when the VM is created", image.GetName(), vm.GetUID())
+	}
+
+	imageDeleted.Inc()
+	return audited(context.Background(), jobs.ClassRemove, "image rm", image, func() error {
+		return os.RemoveAll(image.ObjectPath())
+	})
+}
+
+// removeKernel removes what's left of the kernel, and warns about the VMs still using it
+func removeKernel(kernel *api.Kernel) error {
+	log.Infof("Removing kernel %q with name %q...", kernel.GetUID(), kernel.GetName())
+	if vm, err := kernelUser(kernel.GetName()); err == nil && vm != nil {
+		log.Warnf("Kernel %q is still used by VM %q, it's imported again when the VM is created", kernel.GetName(), vm.GetUID())
+	}
+
+	kernelDeleted.Inc()
+	return audited(context.Background(), jobs.ClassRemove, "kernel rm", kernel, func() error {
+		return os.RemoveAll(kernel.ObjectPath())
+	})
+}
+
+// imageUser returns a VM of the host using the image with the given name, if any
+func imageUser(name string) (*api.VM, error) {
+	return findVM(func(vm *api.VM) bool {
+		return vm.Spec.Image.OCI.String() == name
+	})
+}
+
+// kernelUser returns a VM of the host using the kernel with the given name, if any
+func kernelUser(name string) (*api.VM, error) {
+	return findVM(func(vm *api.VM) bool {
+		return vm.Spec.Kernel.OCI.String() == name
+	})
+}
+
+func findVM(match func(vm *api.VM) bool) (*api.VM, error) {
+	vms, err := providers.Client.VMs().List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vm := range vms {
+		if match(vm) {
+			return vm, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// inUse returns the error of an image or kernel that can't be imported again, as the VMs
+// using it would be corrupted
+func inUse(kind string, uid runtime.UID, vm *api.VM, err error) error {
+	if err != nil {
+		return fmt.Errorf("unable to check whether %s %q is in use: %v", kind, uid, err)
+	}
+
+	return fmt.Errorf("unable to import %s %q again, it's in use by VM %q", kind, uid, vm.GetUID())
+}
@@ -1,12 +1,19 @@
 package reconcile
 
 import (
+	"net"
+	"net/http"
 	"path"
+	"strings"
+	"time"
 
 	go_prom "github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/operations"
 	"github.com/weaveworks/ignite/pkg/prometheus"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage/watch/update"
 )
 
 var (
@@ -26,15 +33,53 @@ var (
 		Name: "vm_stop_counter",
 		Help: "The count of VMs stopped",
 	})
+	vmDriftCorrected = go_prom.NewCounter(go_prom.CounterOpts{
+		Name: "vm_drift_correct_counter",
+		Help: "The count of VMs corrected after drifting from their manifests",
+	})
+	vmOrphaned = go_prom.NewCounter(go_prom.CounterOpts{
+		Name: "vm_orphan_counter",
+		Help: "The count of VMs kept on the host after their manifests were removed",
+	})
+	imageImported = go_prom.NewCounter(go_prom.CounterOpts{
+		Name: "image_import_counter",
+		Help: "The count of images imported",
+	})
+	imageDeleted = go_prom.NewCounter(go_prom.CounterOpts{
+		Name: "image_delete_counter",
+		Help: "The count of images deleted",
+	})
+	kernelImported = go_prom.NewCounter(go_prom.CounterOpts{
+		Name: "kernel_import_counter",
+		Help: "The count of kernels imported",
+	})
+	kernelDeleted = go_prom.NewCounter(go_prom.CounterOpts{
+		Name: "kernel_delete_counter",
+		Help: "The count of kernels deleted",
+	})
 	kindIgnored = go_prom.NewCounter(go_prom.CounterOpts{
 		Name: "kind_ignored_counter",
-		Help: "A counter of non-vm manifests ignored",
+		Help: "A counter of manifests ignored, as they aren't VMs, images or kernels",
+	})
+	reconcileErrors = go_prom.NewCounter(go_prom.CounterOpts{
+		Name: "reconcile_error_counter",
+		Help: "The count of manifest changes and drift corrections that failed to apply",
 	})
+	reconcileDuration = go_prom.NewHistogramVec(go_prom.HistogramOpts{
+		Name:    "reconcile_duration_seconds",
+		Help:    "How long applying a change of a manifest takes",
+		Buckets: []float64{0.1, 0.5, 1, 5, 15, 30, 60, 120, 300},
+	}, []string{"kind", "event"})
 )
 
+// metricsServer serves the metrics on the metrics socket, and the metrics address if given
+var metricsServer *http.Server
+
 func startMetricsThread() {
 	reg, server := prometheus.New()
-	reg.MustRegister(vmCreated, vmDeleted, vmStarted, vmStopped, kindIgnored)
+	reg.MustRegister(vmCreated, vmDeleted, vmStarted, vmStopped, vmDriftCorrected, vmOrphaned, imageImported, imageDeleted, kernelImported, kernelDeleted, kindIgnored)
+	reg.MustRegister(reconcileErrors, reconcileDuration, operations.ImportDuration, vmCollector{})
+	metricsServer = server
 
 	go func() {
 		// create a new registry and http.Server. don't register custom metrics to the registry quite yet
@@ -44,3 +89,27 @@ func startMetricsThread() {
 		}
 	}()
 }
+
+// ServeMetrics serves the metrics on the TCP address too, for Prometheus to scrape them
+// from other hosts at /metrics
+func ServeMetrics(address string) error {
+	metricsOnce.Do(startMetricsThread)
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		log.Infof("Serving the metrics on %s/metrics", l.Addr())
+		if err := metricsServer.Serve(l); err != nil {
+			log.Errorf("Metrics server on %s failed: %v", address, err)
+		}
+	}()
+
+	return nil
+}
+
+// observeReconcile observes the duration of applying the change of a manifest started at start
+func observeReconcile(kind runtime.Kind, event update.ObjectEvent, start time.Time) {
+	reconcileDuration.WithLabelValues(kind.Lower(), strings.ToLower(event.String())).Observe(time.Since(start).Seconds())
+}
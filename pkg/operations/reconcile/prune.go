@@ -0,0 +1,163 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/audit"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/jobs"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"github.com/weaveworks/libgitops/pkg/storage/filterer"
+	"go.opencensus.io/trace"
+)
+
+// pendingPrune is a VM whose manifest was removed, it's pruned once its grace period is over
+type pendingPrune struct {
+	vm    *api.VM
+	timer *time.Timer
+}
+
+// schedulePrune prunes the VM whose manifest was removed once the grace period is over, or
+// right away without a grace period
+func (r *reconciler) schedulePrune(vm *api.VM) {
+	if r.pruneGracePeriod <= 0 {
+		runHandle(func() error {
+			return r.prune(vm)
+		})
+		return
+	}
+
+	if _, ok := r.pending[vm.GetUID()]; ok {
+		return
+	}
+
+	operations.VMLog(vm, "prune").Infof("Pruning VM %q with name %q%s in %s, unless its manifest comes back", vm.GetUID(), vm.GetName(), r.inEnv(), r.pruneGracePeriod)
+	p := &pendingPrune{vm: vm}
+	p.timer = time.AfterFunc(r.pruneGracePeriod, func() {
+		r.pruneDue <- p
+	})
+	r.pending[vm.GetUID()] = p
+}
+
+// cancelPrune keeps the VM if it was to be pruned, as its manifest is back
+func (r *reconciler) cancelPrune(vm *api.VM) {
+	p, ok := r.pending[vm.GetUID()]
+	if !ok {
+		return
+	}
+
+	p.timer.Stop()
+	delete(r.pending, vm.GetUID())
+	operations.VMLog(vm, "prune").Infof("The manifest of VM %q with name %q%s is back, it's no longer pruned", vm.GetUID(), vm.GetName(), r.inEnv())
+}
+
+// prunePending prunes the VM once its grace period is over, unless the prune was cancelled meanwhile
+func (r *reconciler) prunePending(p *pendingPrune) {
+	if r.pending[p.vm.GetUID()] != p {
+		return
+	}
+
+	delete(r.pending, p.vm.GetUID())
+	runHandle(func() error {
+		return jobs.Run(context.Background(), jobs.ClassReconcile, jobs.Target(p.vm), audit.SourceReconcile, func() error {
+			return r.prune(p.vm)
+		})
+	})
+}
+
+// prune prunes the VM whose manifest was removed according to the prune policy. VMs
+// protected by the prune annotation are only flagged as pruned.
+func (r *reconciler) prune(removed *api.VM) (err error) {
+	ctx, span := r.startSpan("gitops.prune", trace.StringAttribute("uid", removed.GetUID().String()),
+		trace.StringAttribute("name", removed.GetName()), trace.StringAttribute("policy", string(r.prunePolicy)))
+	defer func() { tracing.End(span, err) }()
+
+	// The storage of gitops keeps the VM in the data directory to be pruned here, the one of
+	// the manifest directory removes the VM along with its manifest
+	vm, err := providers.Client.VMs().Get(removed.GetUID())
+	if _, ok := err.(*filterer.NonexistentError); ok {
+		return remove(removed)
+	} else if err != nil {
+		return err
+	}
+
+	if protected(vm) {
+		log.Warnf("Keeping VM %q with name %q%s, it's protected by the %s=disabled annotation", vm.GetUID(), vm.GetName(), r.inEnv(), constants.IGNITE_PRUNE_ANNOTATION)
+		return flagPruned(vm)
+	}
+
+	switch r.prunePolicy {
+	case api.PrunePolicyStop:
+		if currentState(vm) {
+			if err := stop(ctx, vm); err != nil {
+				return err
+			}
+		}
+
+		return flagPruned(vm)
+	case api.PrunePolicyOrphan:
+		log.Warnf("Keeping VM %q with name %q%s, as the prune policy orphans the VMs", vm.GetUID(), vm.GetName(), r.inEnv())
+		return flagPruned(vm)
+	}
+
+	// The VM may not have run since it was created
+	if vm.Status.Runtime == nil {
+		vm.Status.Runtime = &api.Runtime{}
+	}
+	if vm.Status.Network == nil {
+		vm.Status.Network = &api.Network{}
+	}
+
+	if err := remove(vm); err != nil {
+		return err
+	}
+
+	return providers.Client.VMs().Delete(vm.GetUID())
+}
+
+// pruneAction describes what pruning the VM would do, for dry runs
+func (r *reconciler) pruneAction(removed *api.VM) string {
+	vm, err := providers.Client.VMs().Get(removed.GetUID())
+	if err != nil {
+		vm = removed
+	}
+
+	action := "remove"
+	if protected(vm) {
+		action = "flag the protected"
+	} else if r.prunePolicy == api.PrunePolicyStop {
+		action = "stop and flag"
+	} else if r.prunePolicy == api.PrunePolicyOrphan {
+		action = "flag"
+	}
+
+	return action
+}
+
+// protected returns true if the prune annotation of the VM disables pruning it
+func protected(vm *api.VM) bool {
+	return vm.GetAnnotation(constants.IGNITE_PRUNE_ANNOTATION) == "disabled"
+}
+
+// flagPruned annotates the VM kept on the host with the time its manifest was removed
+func flagPruned(vm *api.VM) error {
+	vmOrphaned.Inc()
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				constants.IGNITE_PRUNED_ANNOTATION: time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return providers.Client.VMs().Patch(vm.GetUID(), patch)
+}
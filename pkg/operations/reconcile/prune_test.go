@@ -0,0 +1,51 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+func TestPendingPrune(t *testing.T) {
+	r := &reconciler{
+		pruneGracePeriod: time.Hour,
+		pending:          map[runtime.UID]*pendingPrune{},
+		pruneDue:         make(chan *pendingPrune),
+	}
+
+	vm := &api.VM{}
+	vm.SetUID("599615df99804ae8")
+	vm.SetName("my-vm")
+
+	r.schedulePrune(vm)
+	p := r.pending[vm.GetUID()]
+	assert.Assert(t, p != nil)
+
+	// Removing the manifest again keeps the first grace period
+	r.schedulePrune(vm)
+	assert.Equal(t, r.pending[vm.GetUID()], p)
+
+	// The VM is kept if its manifest comes back, e.g. when its directory is moved
+	r.cancelPrune(vm)
+	assert.Equal(t, len(r.pending), 0)
+
+	// A grace period that was over before the cancel doesn't prune the VM, nor the next one
+	r.schedulePrune(vm)
+	r.prunePending(p)
+	assert.Assert(t, r.pending[vm.GetUID()] != p)
+	assert.Equal(t, len(r.pending), 1)
+	r.cancelPrune(vm)
+}
+
+func TestProtected(t *testing.T) {
+	vm := &api.VM{}
+	assert.Assert(t, !protected(vm))
+
+	vm.SetAnnotation(constants.IGNITE_PRUNE_ANNOTATION, "disabled")
+	assert.Assert(t, protected(vm))
+}
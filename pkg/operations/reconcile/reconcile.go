@@ -1,172 +1,523 @@
 package reconcile
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/weaveworks/ignite/pkg/apis/ignite"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
 	"github.com/weaveworks/ignite/pkg/apis/ignite/validation"
+	"github.com/weaveworks/ignite/pkg/audit"
 	"github.com/weaveworks/ignite/pkg/client"
-	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/events"
+	"github.com/weaveworks/ignite/pkg/jobs"
 	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/policy"
 	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/tracing"
 	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage"
 	"github.com/weaveworks/libgitops/pkg/storage/cache"
 	"github.com/weaveworks/libgitops/pkg/storage/manifest"
 	"github.com/weaveworks/libgitops/pkg/storage/watch/update"
+	patchutil "github.com/weaveworks/libgitops/pkg/util/patch"
+	"go.opencensus.io/trace"
+	"sigs.k8s.io/yaml"
 )
 
-var c *client.Client
+// reconciler applies the VM, image and kernel manifests of a storage to the host
+type reconciler struct {
+	c *client.Client
+	s storage.Storage
+	// env is the gitops environment the manifests are synced from, nil for the manifest directory
+	env *api.GitOpsEnvironment
+	// manifests are the VMs as their manifests declare them, the drift of the VMs is detected against them
+	manifests map[runtime.UID]*api.VM
+	// sinks receive the events of drift corrections
+	sinks []events.Sink
+	// status is the client of the manifests the status of the VMs is written back to, nil if it isn't
+	status *client.Client
+	// synced are the Synced conditions of the VMs, written back with their status
+	synced map[runtime.UID]*api.VMCondition
+	// written are the statuses written back to the manifests
+	written map[runtime.UID]*api.VMStatus
+	// prunePolicy and pruneGracePeriod determine what happens to the VMs whose manifests are removed
+	prunePolicy      api.PrunePolicy
+	pruneGracePeriod time.Duration
+	// pending are the VMs to prune once their grace period is over, pruneDue receives them then
+	pending  map[runtime.UID]*pendingPrune
+	pruneDue chan *pendingPrune
+}
+
+var metricsOnce sync.Once
+
+// auditActor is the actor of the changes applied by the reconciliation in the audit log
+const auditActor = "ignited"
 
+// Options configure the reconciliation of the manifests besides applying their changes
+type Options struct {
+	// DriftInterval is how often the VMs are compared to their manifests, and corrected if
+	// they drifted from them. Zero disables drift detection.
+	DriftInterval time.Duration
+	// StatusStorage is the storage of the manifests only, the status of the VMs is written
+	// back to it every StatusInterval. Nil disables status write-back.
+	StatusStorage  storage.Storage
+	StatusInterval time.Duration
+	// Prune determines what happens to the VMs whose manifests are removed, once the
+	// PruneGracePeriod is over. The VMs are deleted right away by default.
+	Prune            api.PrunePolicy
+	PruneGracePeriod time.Duration
+}
+
+// ReconcileManifests applies the manifests of the storage as they change
 func ReconcileManifests(s *manifest.ManifestStorage) {
-	startMetricsThread()
+	ReconcileEnvironment(s, s.GetUpdateStream(), nil, Options{})
+}
+
+// ReconcileEnvironment applies the manifests of the gitops environment as they change. The
+// storage holds the manifests, the updates are sent when they change. With the DryRun sync
+// policy of the environment, the changes are only logged.
+func ReconcileEnvironment(s storage.Storage, updates <-chan update.Update, env *api.GitOpsEnvironment, opts Options) {
+	metricsOnce.Do(startMetricsThread)
 
 	// Wrap the Manifest Storage with a cache for better performance, and create a client
-	c = client.NewClient(cache.NewCache(s))
+	r := &reconciler{
+		c:         client.NewClient(cache.NewCache(s)),
+		s:         s,
+		env:       env,
+		manifests: map[runtime.UID]*api.VM{},
+		synced:    map[runtime.UID]*api.VMCondition{},
+		written:   map[runtime.UID]*api.VMStatus{},
 
-	// These updates are coming from the SyncStorage
-	for upd := range s.GetUpdateStream() {
+		prunePolicy:      opts.Prune,
+		pruneGracePeriod: opts.PruneGracePeriod,
+		pending:          map[runtime.UID]*pendingPrune{},
+		pruneDue:         make(chan *pendingPrune),
+	}
 
-		// Only care about VMs
-		if upd.APIType.GetKind() != api.KindVM {
-			log.Tracef("GitOps: Ignoring kind %s", upd.APIType.GetKind())
-			kindIgnored.Inc()
-			continue
+	var drift <-chan time.Time
+	if opts.DriftInterval > 0 {
+		sinks, err := events.NewSinks(path.Join(constants.DATA_DIR, constants.EVENT_LOG))
+		if err != nil {
+			log.Errorf("Failed to set up the events of drift corrections%s: %v", r.inEnv(), err)
 		}
 
-		var vm *api.VM
-		var err error
-		if upd.Event == update.ObjectEventDelete {
-			// As we know this VM was deleted, it wouldn't show up in a Get() call
-			// Construct a temporary VM object for passing to the delete function
-			vm = &api.VM{
-				TypeMeta:   *upd.APIType.GetTypeMeta(),
-				ObjectMeta: *upd.APIType.GetObjectMeta(),
-				Status: api.VMStatus{
-					Running: true, // TODO: Fix this in StopVM
-					Runtime: &ignite.Runtime{},
-					Network: &ignite.Network{},
-				},
-			}
-		} else {
-			// Get the real API object
-			vm, err = c.VMs().Get(upd.APIType.GetUID())
-			if err != nil {
-				log.Errorf("Getting %s %q returned an error: %v", upd.APIType.GetKind(), upd.APIType.GetUID(), err)
-				continue
+		r.sinks = sinks
+		drift = time.Tick(opts.DriftInterval)
+	}
+
+	var writeStatus <-chan time.Time
+	if opts.StatusStorage != nil && !r.dryRun() {
+		r.status = client.NewClient(opts.StatusStorage)
+		writeStatus = time.Tick(opts.StatusInterval)
+	}
+
+	for {
+		select {
+		// These updates are coming from the SyncStorage
+		case upd, ok := <-updates:
+			if !ok {
+				return
 			}
 
-			// If the object was existent in the storage; validate it
-			// Validate the VM object
-			// TODO: Validate name uniqueness
-			if err := validation.ValidateVM(vm).ToAggregate(); err != nil {
-				log.Warnf("Skipping %s of %s %q, not valid: %v.", upd.Event, upd.APIType.GetKind(), upd.APIType.GetUID(), err)
-				continue
+			r.handleUpdate(upd)
+		case <-drift:
+			r.correctDrift()
+		case <-writeStatus:
+			r.writeStatus()
+		case p := <-r.pruneDue:
+			r.prunePending(p)
+		}
+	}
+}
+
+// handleUpdate applies the change of a manifest
+func (r *reconciler) handleUpdate(upd update.Update) {
+	// Only care about VMs, and the images and kernels they're created from
+	kind := upd.APIType.GetKind()
+	if kind != api.KindVM && kind != api.KindImage && kind != api.KindKernel {
+		log.Tracef("GitOps: Ignoring kind %s", kind)
+		kindIgnored.Inc()
+		return
+	}
+	defer observeReconcile(kind, upd.Event, time.Now())
+	ctx, span := r.startSpan("gitops.reconcile", trace.StringAttribute("kind", kind.String()),
+		trace.StringAttribute("event", upd.Event.String()),
+		trace.StringAttribute("uid", upd.APIType.GetUID().String()),
+		trace.StringAttribute("name", upd.APIType.GetName()))
+	// The error of applying the change of a VM, the images and kernels record their own
+	var handleErr error
+	defer func() { tracing.End(span, handleErr) }()
+
+	// An object is only synced from the environment that had it first
+	if owner, ok := claim(upd.APIType.GetUID(), r.envName()); !ok {
+		log.Warnf("Skipping %s of %s %q%s, it's synced from environment %q.", upd.Event, kind, upd.APIType.GetUID(), r.inEnv(), owner)
+		return
+	}
+
+	switch kind {
+	case api.KindImage:
+		r.reconcileImage(ctx, upd)
+		return
+	case api.KindKernel:
+		r.reconcileKernel(ctx, upd)
+		return
+	}
+
+	var vm *api.VM
+	var err error
+	if upd.Event == update.ObjectEventDelete {
+		// As we know this VM was deleted, it wouldn't show up in a Get() call
+		// Construct a temporary VM object for passing to the delete function
+		vm = &api.VM{
+			TypeMeta:   *upd.APIType.GetTypeMeta(),
+			ObjectMeta: *upd.APIType.GetObjectMeta(),
+			Status: api.VMStatus{
+				Running: true, // TODO: Fix this in StopVM
+				Runtime: &ignite.Runtime{},
+				Network: &ignite.Network{},
+			},
+		}
+	} else {
+		// Get the real API object
+		vm, err = r.c.VMs().Get(upd.APIType.GetUID())
+		if err != nil {
+			log.Errorf("Getting %s %q returned an error: %v", upd.APIType.GetKind(), upd.APIType.GetUID(), err)
+			return
+		}
+
+		// If the VM references a template, use the template as the base of the VM
+		if err := r.applyTemplate(vm); err != nil {
+			log.Warnf("Skipping %s of %s %q, failed to apply its template: %v.", upd.Event, upd.APIType.GetKind(), upd.APIType.GetUID(), err)
+			return
+		}
+
+		// Label the VM with the environment it's synced from
+		labelsChanged := r.applyLabels(vm)
+
+		// If the object was existent in the storage; validate it
+		// Validate the VM object
+		// TODO: Validate name uniqueness
+		if err := validation.ValidateVM(vm).ToAggregate(); err != nil {
+			log.Warnf("Skipping %s of %s %q, not valid: %v.", upd.Event, upd.APIType.GetKind(), upd.APIType.GetUID(), err)
+			return
+		}
+
+		if labelsChanged && !r.dryRun() {
+			if err := storeLabels(vm); err != nil {
+				log.Errorf("Failed to label %s %q%s: %v", upd.APIType.GetKind(), upd.APIType.GetUID(), r.inEnv(), err)
 			}
 		}
+	}
 
-		// TODO: Parallelization
-		switch upd.Event {
-		case update.ObjectEventCreate, update.ObjectEventModify:
-			runHandle(func() error {
-				return handleChange(vm)
-			})
+	// Record the VM as its manifest declares it
+	if upd.Event == update.ObjectEventDelete {
+		delete(r.manifests, vm.GetUID())
+		delete(r.synced, vm.GetUID())
+		delete(r.written, vm.GetUID())
+	} else {
+		r.manifests[vm.GetUID()] = vm.DeepCopy()
+		r.cancelPrune(vm)
+	}
+
+	if r.dryRun() {
+		r.plan(upd.Event, vm)
+		return
+	}
 
-		case update.ObjectEventDelete:
-			runHandle(func() error {
-				// TODO: Temporary VM Object for removal
-				return handleDelete(vm)
+	switch upd.Event {
+	case update.ObjectEventCreate, update.ObjectEventModify:
+		runHandle(func() error {
+			err := jobs.Run(ctx, jobs.ClassReconcile, jobs.Target(vm), audit.SourceReconcile, func() error {
+				return r.handleChange(ctx, vm)
 			})
-		default:
-			log.Infof("Unrecognized Git update type %s\n", upd.Event)
-			continue
+			r.recordSync(vm.GetUID(), err)
+			handleErr = err
+			return err
+		})
+
+	case update.ObjectEventDelete:
+		r.schedulePrune(vm)
+	default:
+		log.Infof("Unrecognized Git update type %s\n", upd.Event)
+		return
+	}
+}
+
+func (r *reconciler) envName() string {
+	if r.env == nil {
+		return ""
+	}
+
+	return r.env.Name
+}
+
+// inEnv describes the environment for log messages
+func (r *reconciler) inEnv() string {
+	if len(r.envName()) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" in environment %q", r.envName())
+}
+
+// startSpan starts a span of the reconciliation, attributed with the environment
+func (r *reconciler) startSpan(name string, attributes ...trace.Attribute) (context.Context, *tracing.Span) {
+	if len(r.envName()) > 0 {
+		attributes = append(attributes, trace.StringAttribute("environment", r.envName()))
+	}
+
+	return tracing.Start(context.Background(), name, attributes...)
+}
+
+func (r *reconciler) dryRun() bool {
+	return r.env != nil && r.env.SyncPolicy.DryRun
+}
+
+// applyLabels sets the environment label and the labels of the environment on the VM,
+// it returns true if any of them weren't set yet
+func (r *reconciler) applyLabels(vm *api.VM) (changed bool) {
+	if len(r.envName()) == 0 {
+		return false
+	}
+
+	labels := map[string]string{constants.IGNITE_ENVIRONMENT_LABEL: r.env.Name}
+	for k, v := range r.env.Labels {
+		labels[k] = v
+	}
+
+	for k, v := range labels {
+		if vm.GetLabel(k) != v {
+			vm.SetLabel(k, v)
+			changed = true
 		}
 	}
+
+	return
+}
+
+// storeLabels patches the labels of the VM into its object in the data directory, the
+// manifests of the repository are only written along with the status of the VM
+func storeLabels(vm *api.VM) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": vm.Labels,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return providers.Client.VMs().Patch(vm.GetUID(), patch)
+}
+
+// plan logs what applying the update would do
+func (r *reconciler) plan(event update.ObjectEvent, vm *api.VM) {
+	var actions []string
+	switch event {
+	case update.ObjectEventCreate, update.ObjectEventModify:
+		running := currentState(vm)
+		if _, err := providers.Client.VMs().Get(vm.GetUID()); err != nil {
+			actions = append(actions, "create")
+		}
+
+		if vm.Status.Running && !running {
+			actions = append(actions, "start")
+		} else if !vm.Status.Running && running {
+			actions = append(actions, "stop")
+		}
+	case update.ObjectEventDelete:
+		actions = append(actions, r.pruneAction(vm))
+	}
+
+	if len(actions) == 0 {
+		log.Infof("Dry run: VM %q with name %q%s is up to date", vm.GetUID(), vm.GetName(), r.inEnv())
+		return
+	}
+
+	var after string
+	if event == update.ObjectEventDelete && r.pruneGracePeriod > 0 {
+		after = fmt.Sprintf(" after a grace period of %s", r.pruneGracePeriod)
+	}
+
+	log.Infof("Dry run: would %s VM %q with name %q%s%s", strings.Join(actions, " and "), vm.GetUID(), vm.GetName(), r.inEnv(), after)
+}
+
+var (
+	owners   = map[runtime.UID]string{}
+	ownersMu sync.Mutex
+)
+
+// claim records that the object is synced from the environment, unless another environment
+// already syncs it. The owning environment is returned.
+func claim(uid runtime.UID, env string) (string, bool) {
+	ownersMu.Lock()
+	defer ownersMu.Unlock()
+
+	owner, ok := owners[uid]
+	if !ok {
+		owners[uid] = env
+		return env, true
+	}
+
+	return owner, owner == env
+}
+
+// applyTemplate rebuilds the VM from the VMTemplate referenced by its template annotation.
+// The manifest of the VM is patched on top of the spec of the template, so the fields
+// set in the manifest take precedence over the ones of the template.
+func (r *reconciler) applyTemplate(vm *api.VM) error {
+	templateName := vm.GetAnnotation(constants.IGNITE_TEMPLATE_ANNOTATION)
+	if len(templateName) == 0 {
+		return nil
+	}
+
+	template, err := r.c.VMTemplates().Find(filter.NewIDNameFilter(templateName))
+	if err != nil {
+		return err
+	}
+
+	// The decoded VM has its defaults set, read the manifest as it was written instead
+	content, err := r.s.RawStorage().Read(storage.KeyForUID(vm.GroupVersionKind(), vm.GetUID()))
+	if err != nil {
+		return err
+	}
+
+	manifestJSON, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return err
+	}
+
+	baseVM := vm.DeepCopy()
+	baseVM.Spec = *template.Spec.DeepCopy()
+	baseVMJSON, err := scheme.Serializer.EncodeJSON(baseVM)
+	if err != nil {
+		return err
+	}
+
+	result, err := patchutil.NewPatcher(scheme.Serializer).Apply(baseVMJSON, manifestJSON, vm.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+
+	return scheme.Serializer.DecodeInto(result, vm)
 }
 
 // TODO: Maybe parallelize these commands?
 func runHandle(fn func() error) {
 	if err := fn(); err != nil {
-		log.Errorf("An error occurred when processing a VM update: %v\n", err)
+		reconcileErrors.Inc()
+		log.Errorf("An error occurred when processing an update: %v\n", err)
 	}
 }
 
-func handleChange(vm *api.VM) (err error) {
+func (r *reconciler) handleChange(ctx context.Context, vm *api.VM) (err error) {
 	// Only apply the new state if it
 	// differs from the current state
 	running := currentState(vm)
 	if vm.Status.Running && !running {
-		err = start(vm)
+		err = r.start(ctx, vm)
 	} else if !vm.Status.Running && running {
-		err = stop(vm)
+		err = stop(ctx, vm)
 	}
 
 	return
 }
 
-func handleDelete(vm *api.VM) error {
-	return remove(vm)
-}
-
 // TODO: Unify this with the "real" Create() method currently in cmd/
-func create(vm *api.VM) error {
-	log.Infof("Creating VM %q with name %q...", vm.GetUID(), vm.GetName())
-	if err := ensureOCIImages(vm); err != nil {
-		return err
+func (r *reconciler) create(ctx context.Context, vm *api.VM) error {
+	operations.VMLog(vm, "create").Infof("Creating VM %q with name %q...", vm.GetUID(), vm.GetName())
+	// Record the snapshotter before ensureOCIImages persists the VM
+	if vm.Status.Snapshotter == "" {
+		vm.Status.Snapshotter = providers.SnapshotterName
 	}
-	vmCreated.Inc()
-	// Allocate and populate the overlay file
-	return dmlegacy.AllocateAndPopulateOverlay(vm)
+	return audited(ctx, jobs.ClassStart, "vm create", vm, func() error {
+		if err := policy.CheckVM(ctx, policy.OperationVMCreate, vm); err != nil {
+			return err
+		}
+		if err := r.ensureOCIImages(ctx, vm); err != nil {
+			return err
+		}
+		vmCreated.Inc()
+		// Allocate and populate the overlay file
+		return operations.AllocateAndPopulateOverlay(vm)
+	})
 }
 
 // ensureOCIImages imports the base/kernel OCI images if needed
-func ensureOCIImages(vm *api.VM) error {
-	// Check if a image with this name already exists, or import it
-	image, err := operations.FindOrImportImage(c, vm.Spec.Image.OCI)
+func (r *reconciler) ensureOCIImages(ctx context.Context, vm *api.VM) error {
+	// Check if the image and kernel already exist, or import them in parallel
+	var image *api.Image
+	var kernel *api.Kernel
+	err := jobs.Run(ctx, jobs.ClassImport, "image/"+vm.Spec.Image.OCI.String(), audit.SourceReconcile, func() (err error) {
+		image, kernel, err = operations.FindOrImportImageAndKernel(ctx, r.c, vm.Spec.Image.OCI, vm.Spec.Kernel.OCI)
+		return
+	})
 	if err != nil {
 		return err
 	}
 
-	// Populate relevant data from the Image on the VM object
+	// Populate relevant data from the Image and Kernel on the VM object
 	vm.SetImage(image)
-
-	// Check if a kernel with this name already exists, or import it
-	kernel, err := operations.FindOrImportKernel(c, vm.Spec.Kernel.OCI)
-	if err != nil {
-		return err
-	}
-
-	// Populate relevant data from the Kernel on the VM object
 	vm.SetKernel(kernel)
 
 	// Save the file to disk. This will also write the file to /var/lib/firecracker for compatibility.
-	return c.VMs().Set(vm)
+	return r.c.VMs().Set(vm)
 }
 
-func start(vm *api.VM) error {
+func (r *reconciler) start(ctx context.Context, vm *api.VM) error {
 	// create the overlay if it doesn't exist
 	if !util.FileExists(vm.OverlayFile()) {
-		if err := create(vm); err != nil {
+		if err := r.create(ctx, vm); err != nil {
 			return err
 		}
 	}
 
-	log.Infof("Starting VM %q with name %q...", vm.GetUID(), vm.GetName())
+	operations.VMLog(vm, "start").Infof("Starting VM %q with name %q...", vm.GetUID(), vm.GetName())
 	vmStarted.Inc()
-	return operations.StartVM(vm, true)
+	return audited(ctx, jobs.ClassStart, "vm start", vm, func() error {
+		return operations.StartVM(ctx, vm, true)
+	})
 }
 
-func stop(vm *api.VM) error {
-	log.Infof("Stopping VM %q with name %q...", vm.GetUID(), vm.GetName())
+func stop(ctx context.Context, vm *api.VM) error {
+	operations.VMLog(vm, "stop").Infof("Stopping VM %q with name %q...", vm.GetUID(), vm.GetName())
 	vmStopped.Inc()
-	return operations.StopVM(vm, true, false)
+	return audited(ctx, jobs.ClassStop, "vm stop", vm, func() error {
+		return operations.StopVM(ctx, vm, true, false)
+	})
 }
 
 func remove(vm *api.VM) error {
-	log.Infof("Removing VM %q with name %q...", vm.GetUID(), vm.GetName())
+	operations.VMLog(vm, "remove").Infof("Removing VM %q with name %q...", vm.GetUID(), vm.GetName())
 	vmDeleted.Inc()
 	// Object deletion is performed by the SyncStorage, so we just
 	// need to clean up any remaining resources of the VM here
-	return operations.CleanupVM(vm)
+	return audited(context.Background(), jobs.ClassRemove, "vm rm", vm, func() error {
+		return operations.CleanupVM(vm)
+	})
+}
+
+// audited runs the operation on the object as a job of the class, and records it in the
+// audit log as applied by ignited
+func audited(ctx context.Context, class jobs.Class, operation string, obj runtime.Object, fn func() error) error {
+	return jobs.Run(ctx, class, jobs.Target(obj), audit.SourceReconcile, func() error {
+		entry := audit.Begin(audit.SourceReconcile, auditActor, operation,
+			fmt.Sprintf("uid=%s", obj.GetUID()), fmt.Sprintf("name=%s", obj.GetName()))
+		err := fn()
+		entry.Finish(err)
+		return err
+	})
 }
 
 // TODO: Quick hack to get the current state of the VM,
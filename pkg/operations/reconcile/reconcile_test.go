@@ -0,0 +1,59 @@
+package reconcile
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+func TestApplyLabels(t *testing.T) {
+	vm := &api.VM{}
+	vm.SetLabel("app", "web")
+
+	// VMs of the manifest directory aren't labelled
+	r := &reconciler{}
+	assert.Assert(t, !r.applyLabels(vm))
+	assert.DeepEqual(t, vm.Labels, map[string]string{"app": "web"})
+
+	r = &reconciler{env: &api.GitOpsEnvironment{Name: "staging", Labels: map[string]string{"team": "platform"}}}
+	assert.Assert(t, r.applyLabels(vm))
+	assert.DeepEqual(t, vm.Labels, map[string]string{
+		"app":                              "web",
+		"team":                             "platform",
+		constants.IGNITE_ENVIRONMENT_LABEL: "staging",
+	})
+
+	// Labelling again changes nothing
+	assert.Assert(t, !r.applyLabels(vm))
+}
+
+func TestClaim(t *testing.T) {
+	owner, ok := claim("0123456789abcdef", "staging")
+	assert.Assert(t, ok)
+	assert.Equal(t, owner, "staging")
+
+	_, ok = claim("0123456789abcdef", "staging")
+	assert.Assert(t, ok)
+
+	owner, ok = claim("0123456789abcdef", "production")
+	assert.Assert(t, !ok)
+	assert.Equal(t, owner, "staging")
+}
+
+func TestNeedsImport(t *testing.T) {
+	ref, err := meta.NewOCIImageRef("weaveworks/ignite-ubuntu:latest")
+	assert.NilError(t, err)
+
+	imported := path.Join(t.TempDir(), constants.IMAGE_FS)
+	assert.Assert(t, needsImport(ref, imported, &api.OCIImageSource{}), "not imported yet")
+
+	assert.NilError(t, ioutil.WriteFile(imported, nil, 0644))
+	assert.Assert(t, needsImport(ref, imported, nil), "the object of the import is gone")
+	assert.Assert(t, needsImport(ref, imported, &api.OCIImageSource{}), "the import has no OCI source")
+}
@@ -0,0 +1,74 @@
+package reconcile
+
+import (
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// recordSync records whether applying the manifest of the VM succeeded, as its Synced condition
+func (r *reconciler) recordSync(uid runtime.UID, err error) {
+	vm := &api.VM{}
+	if c := r.synced[uid]; c != nil {
+		vm.Status.Conditions = []api.VMCondition{*c}
+	}
+
+	if err != nil {
+		vm.SetCondition(api.VMSynced, api.ConditionFalse, "ApplyFailed", err.Error())
+	} else {
+		vm.SetCondition(api.VMSynced, api.ConditionTrue, "Applied", "")
+	}
+
+	r.synced[uid] = vm.Condition(api.VMSynced)
+}
+
+// writeStatus writes the status of the VMs back to their manifests, along with their Synced
+// condition. The manifests keep their running field, as it's the state the VMs should be in.
+func (r *reconciler) writeStatus() {
+	for uid, manifest := range r.manifests {
+		vm, err := providers.Client.VMs().Get(uid)
+		if err != nil {
+			// The VM isn't created yet, or was removed
+			continue
+		}
+
+		status := manifestStatus(manifest, vm, r.synced[uid])
+		if reflect.DeepEqual(status, r.written[uid]) {
+			continue
+		}
+
+		if err := r.writeManifestStatus(uid, status); err != nil {
+			log.Errorf("Failed to write the status of VM %q%s to its manifest: %v", uid, r.inEnv(), err)
+			continue
+		}
+
+		r.written[uid] = status
+	}
+}
+
+// manifestStatus returns the status of the VM to write to its manifest
+func manifestStatus(manifest, vm *api.VM, synced *api.VMCondition) *api.VMStatus {
+	status := vm.Status.DeepCopy()
+	status.Running = manifest.Status.Running
+	if synced != nil {
+		statusVM := &api.VM{Status: *status}
+		statusVM.SetCondition(synced.Type, synced.Status, synced.Reason, synced.Message)
+		statusVM.Condition(api.VMSynced).LastTransitionTime = synced.LastTransitionTime
+		status = &statusVM.Status
+	}
+
+	return status
+}
+
+func (r *reconciler) writeManifestStatus(uid runtime.UID, status *api.VMStatus) error {
+	vm, err := r.status.VMs().Get(uid)
+	if err != nil {
+		return err
+	}
+
+	vm.Status = *status
+	return r.status.VMs().Set(vm)
+}
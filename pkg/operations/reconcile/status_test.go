@@ -0,0 +1,47 @@
+package reconcile
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+func TestRecordSync(t *testing.T) {
+	r := &reconciler{synced: map[runtime.UID]*api.VMCondition{}}
+	r.recordSync("0123456789abcdef", errors.New("image not found"))
+	synced := r.synced["0123456789abcdef"]
+	assert.Equal(t, synced.Status, api.ConditionFalse)
+	assert.Equal(t, synced.Reason, "ApplyFailed")
+	assert.Equal(t, synced.Message, "image not found")
+
+	r.recordSync("0123456789abcdef", nil)
+	synced = r.synced["0123456789abcdef"]
+	assert.Equal(t, synced.Status, api.ConditionTrue)
+	assert.Equal(t, synced.Reason, "Applied")
+	assert.Equal(t, synced.Message, "")
+}
+
+func TestManifestStatus(t *testing.T) {
+	manifest := &api.VM{}
+	manifest.Status.Running = true
+
+	// The VM crashed, the manifest still has it running
+	vm := &api.VM{}
+	vm.SetCondition(api.VMDegraded, api.ConditionTrue, "Crashed", "")
+	synced := &api.VMCondition{Type: api.VMSynced, Status: api.ConditionTrue, Reason: "Applied"}
+
+	status := manifestStatus(manifest, vm, synced)
+	assert.Assert(t, status.Running)
+	assert.Equal(t, len(status.Conditions), 2)
+	assert.Equal(t, status.Conditions[0].Type, api.VMDegraded)
+	assert.DeepEqual(t, status.Conditions[1], *synced)
+	assert.Equal(t, len(vm.Status.Conditions), 1, "the status of the VM is copied")
+
+	// VMs that weren't applied yet have no Synced condition
+	status = manifestStatus(manifest, vm, nil)
+	assert.Equal(t, len(status.Conditions), 1)
+}
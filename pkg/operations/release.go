@@ -0,0 +1,136 @@
+package operations
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/flock"
+	"github.com/weaveworks/ignite/pkg/nbd"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/vfio"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// releaseHostResources disconnects the NBD volumes of the VM from the host, and gives its PCI
+// devices back to their host drivers. The VMM of the VM needs to have exited.
+func releaseHostResources(vm *api.VM) error {
+	if err := nbd.DisconnectVolumes(vm); err != nil {
+		return err
+	}
+
+	return vfio.Release(vm)
+}
+
+// holdsHostResources returns true if the VM may hold NBD devices or PCI devices of the host
+func holdsHostResources(vm *api.VM) bool {
+	if vm.HasDevices() {
+		return true
+	}
+
+	for _, volume := range vm.Spec.Storage.Volumes {
+		if volume.NBD != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// releaseStopped releases the host resources of the VM once it has been stopped or killed. A
+// killed VMM exits after KillContainer returns, ignite-spawn marks the VM as stopped then.
+func releaseStopped(vm *api.VM, kill bool) error {
+	if kill {
+		if err := waitForExit(vm); err != nil {
+			return err
+		}
+	}
+
+	return releaseHostResources(vm)
+}
+
+// waitForExit waits for ignite-spawn to mark the VM as stopped
+func waitForExit(vm *api.VM) error {
+	const checkInterval = 100 * time.Millisecond
+
+	timer := time.Now()
+	for time.Since(timer) < constants.IGNITE_TIMEOUT*time.Second {
+		current, err := providers.Client.VMs().Get(vm.GetUID())
+		if err != nil {
+			return err
+		}
+
+		if !current.Running() {
+			return nil
+		}
+
+		time.Sleep(checkInterval)
+	}
+
+	return fmt.Errorf("timeout waiting for the VMM of VM %q to exit", vm.GetUID())
+}
+
+// ReleaseExited releases the host resources of the VM if its VMM has exited without ignite
+// stopping it, e.g. when the guest powered off or the VMM crashed. A VM being started,
+// stopped or removed by another goroutine or process is left to that operation.
+func ReleaseExited(vm *api.VM) error {
+	unlock, err := flock.TryLock(flock.ObjectName(vm))
+	if err != nil || unlock == nil {
+		return err
+	}
+	defer unlock()
+
+	// ignite-spawn marks the VM as stopped once the VMM has exited
+	current, err := providers.Client.VMs().Get(vm.GetUID())
+	if err != nil || current.Running() {
+		return err
+	}
+
+	return releaseHostResources(current)
+}
+
+// releaseExitedOwners releases the PCI devices of the VM that are still held by other VMs
+// whose VMM has exited, so the VM can pass them through
+func releaseExitedOwners(vm *api.VM) {
+	for _, address := range vfio.Addresses(vm) {
+		owner, err := vfio.Owner(address)
+		if err != nil || len(owner) == 0 || owner == vm.GetUID().String() {
+			continue
+		}
+
+		ownerVM, err := providers.Client.VMs().Get(runtime.UID(owner))
+		if err == nil {
+			err = ReleaseExited(ownerVM)
+		}
+		if err != nil {
+			log.Warnf("Failed to release PCI device %s held by VM %q: %v", address, owner, err)
+		}
+	}
+}
+
+// HandleExits releases the host resources of the VMs that exited without ignite stopping
+// them every VM_EXIT_CHECK_INTERVAL, so other VMs can use their devices. It never returns.
+func HandleExits() {
+	for {
+		time.Sleep(constants.VM_EXIT_CHECK_INTERVAL)
+
+		vms, err := providers.Client.VMs().FindAll(filter.NewAllFilter())
+		if err != nil {
+			log.Errorf("Failed to list VMs for releasing their devices: %v", err)
+			continue
+		}
+
+		for _, vm := range vms {
+			if vm.Running() || !holdsHostResources(vm) {
+				continue
+			}
+
+			if err := ReleaseExited(vm); err != nil {
+				log.Warnf("Failed to release the volumes and PCI devices of exited VM %q: %v", vm.GetUID(), err)
+			}
+		}
+	}
+}
@@ -1,17 +1,25 @@
 package operations
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path"
 
 	log "github.com/sirupsen/logrus"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	"github.com/weaveworks/ignite/pkg/client"
-	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/confinement"
+	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/logs"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/runtime"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/ignite/pkg/vault"
+	"go.opencensus.io/trace"
 )
 
 const (
@@ -29,13 +37,19 @@ func DeleteVM(c *client.Client, vm *api.VM) error {
 
 // CleanupVM removes the resources of the given VM
 func CleanupVM(vm *api.VM) error {
+	unlock, err := lockVM(vm)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Runtime information is available only when the VM is running.
 	if vm.Running() {
 		// Inspect the container before trying to stop it and it gets auto-removed
 		inspectResult, _ := providers.Runtime.InspectContainer(vm.PrefixedID())
 
 		// If the VM is running, try to kill it first so we don't leave dangling containers. Otherwise, try to cleanup VM networking.
-		if err := StopVM(vm, true, true); err != nil {
+		if err := stopVM(context.Background(), vm, true, true); err != nil {
 			if vm.Running() {
 				return err
 			}
@@ -47,17 +61,37 @@ func CleanupVM(vm *api.VM) error {
 	}
 
 	// After removing the VM container, if the Snapshot Device is still there, clean up
-	if _, err := os.Stat(vm.SnapshotDev()); err == nil {
+	if devicePath, err := SnapshotDevice(vm); err == nil && util.FileExists(devicePath) {
 		// try remove it again with DeactivateSnapshot
-		if err := dmlegacy.DeactivateSnapshot(vm); err != nil {
+		if err := DeactivateSnapshot(vm); err != nil {
 			return err
 		}
 	}
 
+	// Disconnect the remote disks of the VM from the host, and give its PCI devices back to their host drivers
+	if err := releaseHostResources(vm); err != nil {
+		return err
+	}
+
+	// Release the storage of the snapshot kept outside of the VM directory
+	if err := RemoveSnapshot(vm); err != nil {
+		return err
+	}
+
+	// Unload the AppArmor profile generated for the sandbox
+	if err := confinement.Release(vm); err != nil {
+		return err
+	}
+
+	// Revoke the Vault leases of the secrets of the VM
+	if err := vault.Release(vm.GetUID().String()); err != nil {
+		return err
+	}
+
 	if logs.Quiet {
 		fmt.Println(vm.GetUID())
 	} else {
-		log.Infof("Removed %s with name %q and ID %q", vm.GetKind(), vm.GetName(), vm.GetUID())
+		VMLog(vm, "remove").Infof("Removed %s with name %q and ID %q", vm.GetKind(), vm.GetName(), vm.GetUID())
 	}
 
 	return nil
@@ -76,8 +110,20 @@ func RemoveVMContainer(result *runtime.ContainerInspectResult) {
 }
 
 // StopVM removes networking of the given VM and stops or kills it
-func StopVM(vm *api.VM, kill, silent bool) error {
-	var err error
+func StopVM(ctx context.Context, vm *api.VM, kill, silent bool) error {
+	unlock, err := lockVM(vm)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return stopVM(ctx, vm, kill, silent)
+}
+
+func stopVM(ctx context.Context, vm *api.VM, kill, silent bool) (err error) {
+	ctx, span := tracing.Start(ctx, "vm.stop", append(vmAttributes(vm), trace.BoolAttribute("kill", kill))...)
+	defer func() { tracing.End(span, err) }()
+
 	container := vm.PrefixedID()
 	action := "stop"
 
@@ -86,25 +132,56 @@ func StopVM(vm *api.VM, kill, silent bool) error {
 	}
 
 	// Remove VM networking
-	if err = removeNetworking(vm.Status.Runtime.ID, vm.Spec.Network.Ports...); err != nil {
+	_, networkSpan := tracing.Start(ctx, "vm.network.remove")
+	err = removeNetworking(vm.Status.Runtime.ID, vm.Spec.Network.Ports...)
+	tracing.End(networkSpan, err)
+	if err != nil {
 		log.Warnf("Failed to cleanup networking for stopped container %s %q: %v", vm.GetKind(), vm.GetUID(), err)
 
 		return err
 	}
 
 	if vm.Running() {
+		// Keep the restart policy of the VM from restarting it
+		if err := MarkStopRequested(vm); err != nil {
+			log.Warnf("Failed to mark the stop of %s %q as requested: %v", vm.GetKind(), vm.GetUID(), err)
+		}
+
+		// A paused VM can't shut down gracefully, and a stopped Firecracker process
+		// can't handle the signal to exit, so resume it first
+		if vm.Paused() {
+			if err := ResumeVM(vm); err != nil {
+				log.Warnf("Failed to resume paused %s %q: %v", vm.GetKind(), vm.GetUID(), err)
+			}
+		}
+
 		// Stop or kill the VM container
+		_, containerSpan := tracing.Start(ctx, "vm.container.stop")
 		if kill {
 			action = "kill"
 			err = providers.Runtime.KillContainer(container, signalSIGQUIT) // TODO: common constant for SIGQUIT
 		} else {
 			err = providers.Runtime.StopContainer(container, nil)
 		}
+		tracing.End(containerSpan, err)
 
 		if err != nil {
 			return fmt.Errorf("failed to %s container for %s %q: %v", action, vm.GetKind(), vm.GetUID(), err)
 		}
 
+		// The container has exited now, release the parts of the snapshot
+		// that can't be released from inside of it, like mapped RBD images
+		if devicePath, err := SnapshotDevice(vm); !kill && err == nil && util.FileExists(devicePath) {
+			if err := DeactivateSnapshot(vm); err != nil {
+				log.Warnf("Failed to deactivate the snapshot of %s %q: %v", vm.GetKind(), vm.GetUID(), err)
+			}
+		}
+
+		// Disconnect the remote disks of the VM, and give its PCI devices back to their host drivers
+		if err := releaseStopped(vm, kill); err != nil {
+			log.Warnf("Failed to release the volumes and PCI devices of %s %q: %v", vm.GetKind(), vm.GetUID(), err)
+		}
+
 		if silent {
 			return nil
 		}
@@ -112,13 +189,37 @@ func StopVM(vm *api.VM, kill, silent bool) error {
 		if logs.Quiet {
 			fmt.Println(vm.GetUID())
 		} else {
-			log.Infof("Stopped %s with name %q and ID %q", vm.GetKind(), vm.GetName(), vm.GetUID())
+			VMLog(vm, "stop").Infof("Stopped %s with name %q and ID %q", vm.GetKind(), vm.GetName(), vm.GetUID())
 		}
 	}
 
 	return nil
 }
 
+// MarkStopRequested records that the VM is being stopped by ignite, so its restart
+// policy doesn't apply. ignite-spawn consumes the marker when the VM has stopped.
+func MarkStopRequested(vm *api.VM) error {
+	return ioutil.WriteFile(path.Join(vm.ObjectPath(), constants.VM_STOP_REQUESTED_FILE), nil, 0644)
+}
+
+// MarkUnhealthy records that the VM is being killed for failing its liveness probe, so
+// ignite-spawn records it as the reason the VM stopped
+func MarkUnhealthy(vm *api.VM) error {
+	return ioutil.WriteFile(path.Join(vm.ObjectPath(), constants.VM_UNHEALTHY_FILE), nil, 0644)
+}
+
+// ConsumeUnhealthy returns true if the VM has been killed for failing its liveness probe,
+// and removes the marker so it doesn't apply to the next stop
+func ConsumeUnhealthy(vm *api.VM) bool {
+	return os.Remove(path.Join(vm.ObjectPath(), constants.VM_UNHEALTHY_FILE)) == nil
+}
+
+// ConsumeStopRequested returns true if the stop of the VM has been requested
+// by ignite, and removes the marker so it doesn't apply to the next stop
+func ConsumeStopRequested(vm *api.VM) bool {
+	return os.Remove(path.Join(vm.ObjectPath(), constants.VM_STOP_REQUESTED_FILE)) == nil
+}
+
 func removeNetworking(containerID string, portmappings ...meta.PortMapping) error {
 	log.Infof("Removing the container with ID %q from the %q network", containerID, providers.NetworkPlugin.Name())
 	return providers.NetworkPlugin.RemoveContainerNetwork(containerID, portmappings...)
@@ -0,0 +1,44 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/sbom"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"go.opencensus.io/trace"
+)
+
+// generateSBOM generates the software bill of materials of the filesystem of the image
+// in the format of providers.SBOM, stores it next to the filesystem and describes it in
+// the image status. Nothing is done if no format is set.
+func generateSBOM(ctx context.Context, image *api.Image) (err error) {
+	cfg := providers.SBOM
+	if len(cfg.Format) == 0 {
+		return nil
+	}
+
+	if err := sbom.ValidateFormat(cfg.Format); err != nil {
+		return err
+	}
+
+	ctx, span := tracing.Start(ctx, "image.sbom", trace.StringAttribute("format", string(cfg.Format)))
+	defer func() { tracing.End(span, err) }()
+
+	log.Infof("Generating the %s SBOM of the image...", cfg.Format)
+	p := path.Join(image.ObjectPath(), sbom.FileName(cfg.Format))
+	if err := dmlegacy.WithImageMounted(image, func(rootfs string) error {
+		return sbom.GenerateFile(ctx, cfg.Path, cfg.Format, rootfs, p)
+	}); err != nil {
+		return fmt.Errorf("failed to generate the SBOM of image %q: %v", image.Spec.OCI, err)
+	}
+
+	image.Status.SBOM = &api.ImageSBOM{Format: cfg.Format, Time: runtime.Timestamp()}
+	return nil
+}
@@ -0,0 +1,61 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/scan"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"go.opencensus.io/trace"
+)
+
+// scanImage scans the filesystem of the image for vulnerabilities with the scanner of
+// providers.ImageScan, and stores them in the image status. Nothing is done if no
+// scanner is set. The import fails if the image has vulnerabilities of the severity
+// the imports fail on, or higher.
+func scanImage(ctx context.Context, image *api.Image) (err error) {
+	cfg := providers.ImageScan
+	if len(cfg.Scanner) == 0 {
+		return nil
+	}
+
+	if err := scan.ValidateThreshold(cfg.FailOn); err != nil {
+		return err
+	}
+
+	scanner, err := scan.New(cfg.Scanner, cfg.Path)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracing.Start(ctx, "image.scan", trace.StringAttribute("scanner", scanner.Name()))
+	defer func() { tracing.End(span, err) }()
+
+	log.Infof("Scanning the image for vulnerabilities with %s...", scanner.Name())
+	var vulns []api.Vulnerability
+	if err := dmlegacy.WithImageMounted(image, func(rootfs string) (scanErr error) {
+		vulns, scanErr = scanner.Scan(ctx, rootfs)
+		return
+	}); err != nil {
+		return fmt.Errorf("failed to scan image %q: %v", image.Spec.OCI, err)
+	}
+
+	image.Status.Scan = scan.Status(scanner.Name(), vulns)
+	s := image.Status.Scan.Summary
+	log.Infof("Found %d critical, %d high, %d medium, %d low and %d unknown severity vulnerabilities",
+		s.Critical, s.High, s.Medium, s.Low, s.Unknown)
+
+	if len(cfg.FailOn) != 0 {
+		if found := scan.AtLeast(image.Status.Scan.Vulnerabilities, cfg.FailOn); len(found) != 0 {
+			v := found[0]
+			return fmt.Errorf("image %q has %d vulnerabilities of severity %s or higher, e.g. %s (%s) in %s %s",
+				image.Spec.OCI, len(found), cfg.FailOn, v.ID, v.Severity, v.Package, v.Version)
+		}
+	}
+
+	return nil
+}
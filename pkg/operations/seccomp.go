@@ -0,0 +1,39 @@
+package operations
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+// seccompStatus returns the seccomp filter Firecracker applies with the configuration.
+// The digest of a custom filter records which version of the file was applied.
+func seccompStatus(seccomp *api.VMSeccompSpec) (*api.VMSeccompStatus, error) {
+	if seccomp == nil || (len(seccomp.Level) == 0 && len(seccomp.Filter) == 0) {
+		// Firecracker's default
+		return &api.VMSeccompStatus{Level: api.SeccompLevelAdvanced}, nil
+	}
+
+	if len(seccomp.Filter) == 0 {
+		return &api.VMSeccompStatus{Level: seccomp.Level}, nil
+	}
+
+	f, err := os.Open(seccomp.Filter)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+
+	return &api.VMSeccompStatus{
+		Filter:       seccomp.Filter,
+		FilterDigest: fmt.Sprintf("sha256:%x", h.Sum(nil)),
+	}, nil
+}
@@ -0,0 +1,96 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/weaveworks/ignite/pkg/agent"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/tracing"
+	"github.com/weaveworks/ignite/pkg/vault"
+)
+
+// deliverSecrets reads the secrets of the started VM on the host, and delivers them to
+// its guest agent once it's listening. They're only held in memory on the host.
+func deliverSecrets(ctx context.Context, vm *api.VM) (err error) {
+	_, span := tracing.Start(ctx, "vm.secrets")
+	defer func() { tracing.End(span, err) }()
+
+	// The VM is running now, which the agent client requires
+	if vm, err = providers.Client.VMs().Get(vm.GetUID()); err != nil {
+		return err
+	}
+
+	// Revoke the leases of the secrets the VM got when it was last started
+	if err := vault.Release(vm.GetUID().String()); err != nil {
+		log.Warnf("Failed to revoke the Vault leases of VM %q: %v", vm.GetUID(), err)
+	}
+
+	secrets, err := readSecrets(vm.GetUID().String(), vm.Spec.Secrets)
+	if err != nil {
+		return failCondition(vm, api.VMSecretsDelivered, "ReadFailed", err)
+	}
+
+	if err := agent.Wait(vm, constants.SECRETS_TIMEOUT); err != nil {
+		return failCondition(vm, api.VMSecretsDelivered, "AgentUnreachable", err)
+	}
+
+	if err := agent.WriteSecrets(vm, secrets); err != nil {
+		return failCondition(vm, api.VMSecretsDelivered, "DeliveryFailed", err)
+	}
+
+	vm.SetCondition(api.VMSecretsDelivered, api.ConditionTrue, "Delivered", fmt.Sprintf("delivered %d secrets", len(secrets)))
+	return providers.Client.VMs().Set(vm)
+}
+
+// readSecrets reads the values of the secrets of the VM with the given UID from the files
+// and environment of the host, and from Vault
+func readSecrets(vmUID string, vmSecrets []api.VMSecret) ([]protocol.Secret, error) {
+	var vaultClient *vault.Client
+	secrets := make([]protocol.Secret, 0, len(vmSecrets))
+	for _, vmSecret := range vmSecrets {
+		secret := protocol.Secret{Name: vmSecret.Name, Mode: vmSecret.Mode}
+		if secret.Mode == 0 {
+			secret.Mode = constants.SECRET_DEFAULT_MODE
+		}
+
+		if len(vmSecret.File) != 0 {
+			data, err := ioutil.ReadFile(vmSecret.File)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read secret %q: %v", vmSecret.Name, err)
+			}
+			secret.Data = data
+		} else if len(vmSecret.Vault) != 0 {
+			if vaultClient == nil {
+				c, err := vault.Default()
+				if err != nil {
+					return nil, fmt.Errorf("failed to read secret %q: %v", vmSecret.Name, err)
+				}
+				vaultClient = c
+			}
+
+			data, err := vaultClient.ReadField(vmUID, vmSecret.Vault)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read secret %q: %v", vmSecret.Name, err)
+			}
+			secret.Data = data
+		} else {
+			value, ok := os.LookupEnv(vmSecret.Env)
+			if !ok {
+				return nil, fmt.Errorf("failed to read secret %q: the environment variable %s isn't set", vmSecret.Name, vmSecret.Env)
+			}
+			secret.Data = []byte(value)
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
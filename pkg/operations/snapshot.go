@@ -0,0 +1,277 @@
+package operations
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/snapshotter/dmthin"
+	"github.com/weaveworks/ignite/pkg/snapshotter/lvm"
+	"github.com/weaveworks/ignite/pkg/snapshotter/qcow2"
+	"github.com/weaveworks/ignite/pkg/snapshotter/rbd"
+	"github.com/weaveworks/ignite/pkg/snapshotter/reflink"
+	"github.com/weaveworks/ignite/pkg/snapshotter/zfs"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// AllocateAndPopulateOverlay creates the writable overlay of the VM on top of its image
+// using the VM's snapshotter. It also copies in contents from the host as needed, and
+// configures networking. The overlay of a disk image is only allocated, the guest configures
+// itself from its disk.
+func AllocateAndPopulateOverlay(vm *api.VM) (err error) {
+	if vm.DiskImage() {
+		if err = verifyDiskVM(vm); err != nil {
+			return
+		}
+
+		return AllocateOverlay(vm)
+	}
+
+	if err = AllocateOverlay(vm); err != nil {
+		return
+	}
+
+	devicePath, err := ActivateSnapshot(vm)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, func() error { return DeactivateSnapshot(vm) })
+
+	return dmlegacy.PopulateOverlay(vm, devicePath)
+}
+
+// PopulateClonedOverlay gives the overlay of a VM cloned from the source VM its own identity.
+// The overlay has to contain a copy of the disk of the source VM.
+func PopulateClonedOverlay(vm, source *api.VM) (err error) {
+	if vm.DiskImage() {
+		log.Warnf("VM %q boots a disk image, it keeps the identity of VM %q, e.g. its SSH host keys and machine ID", vm.GetUID(), source.GetUID())
+		return nil
+	}
+
+	devicePath, err := ActivateSnapshot(vm)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, func() error { return DeactivateSnapshot(vm) })
+
+	if err = dmlegacy.ReidentifyOverlay(vm, source, devicePath); err != nil {
+		return
+	}
+
+	return dmlegacy.PopulateOverlay(vm, devicePath)
+}
+
+// UpdateAuthorizedKeys replaces the authorized_keys file of root in the disk of a
+// stopped VM with the result of update
+func UpdateAuthorizedKeys(vm *api.VM, update func(content []byte) ([]byte, error)) (err error) {
+	if vm.DiskImage() {
+		return fmt.Errorf("VM %q boots a disk image, its authorized keys can't be updated", vm.GetUID())
+	}
+
+	devicePath, err := ActivateSnapshot(vm)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, func() error { return DeactivateSnapshot(vm) })
+
+	return dmlegacy.UpdateAuthorizedKeys(devicePath, update)
+}
+
+// GrowDisk grows the disk of a stopped VM to its requested disk size, and resizes
+// the filesystem on it to fill the disk, so the VM boots with the new size.
+func GrowDisk(vm *api.VM) (err error) {
+	if err = ResizeOverlay(vm); err != nil {
+		return
+	}
+
+	// The partitions of disk images are grown by the guest, e.g. by cloud-init
+	if vm.DiskImage() {
+		return
+	}
+
+	devicePath, err := ActivateSnapshot(vm)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, func() error { return DeactivateSnapshot(vm) })
+
+	// resize2fs refuses to grow filesystems that haven't been checked.
+	// e2fsck throws an error if the filesystem gets repaired, so just ignore it
+	_, _ = util.ExecuteCommand("e2fsck", "-p", "-f", devicePath)
+	_, err = util.ExecuteCommand("resize2fs", devicePath)
+	return
+}
+
+// verifyDiskVM returns an error if the VM of a disk image needs files written into its disk,
+// the filesystems in the disk aren't known
+func verifyDiskVM(vm *api.VM) error {
+	if len(vm.Status.Snapshotter) > 0 && vm.Status.Snapshotter != snapshotter.SnapshotterDMLegacy {
+		return fmt.Errorf("VM %q boots a disk image, which is only supported by the %s snapshotter", vm.GetUID(), snapshotter.SnapshotterDMLegacy)
+	}
+
+	if len(vm.Spec.CopyFiles) > 0 || vm.Spec.SSH != nil || len(vm.Spec.Env) > 0 || len(vm.Spec.Users) > 0 {
+		return fmt.Errorf("VM %q boots a disk image, files, SSH keys, environment variables and users can't be written into it, configure them in the image, e.g. with cloud-init", vm.GetUID())
+	}
+
+	return nil
+}
+
+// AllocateOverlay creates the writable overlay of the VM on top of its image
+// using the VM's snapshotter, without modifying its contents
+func AllocateOverlay(vm *api.VM) error {
+	switch vm.Status.Snapshotter {
+	case "", snapshotter.SnapshotterDMLegacy:
+		return dmlegacy.AllocateOverlay(vm)
+	case snapshotter.SnapshotterDMThin:
+		return dmthin.AllocateOverlay(vm)
+	case snapshotter.SnapshotterLVM:
+		return lvm.AllocateOverlay(vm)
+	case snapshotter.SnapshotterQcow2:
+		return qcow2.AllocateOverlay(vm)
+	case snapshotter.SnapshotterRBD:
+		return rbd.AllocateOverlay(vm)
+	case snapshotter.SnapshotterReflink:
+		return reflink.AllocateOverlay(vm)
+	case snapshotter.SnapshotterZFS:
+		return zfs.AllocateOverlay(vm)
+	}
+
+	return unknownSnapshotter(vm)
+}
+
+// ResizeOverlay grows the storage of the VM's snapshot to the requested disk size
+func ResizeOverlay(vm *api.VM) error {
+	switch vm.Status.Snapshotter {
+	case "", snapshotter.SnapshotterDMLegacy:
+		return dmlegacy.ResizeOverlay(vm)
+	case snapshotter.SnapshotterDMThin:
+		return dmthin.ResizeOverlay(vm)
+	case snapshotter.SnapshotterLVM:
+		return lvm.ResizeOverlay(vm)
+	case snapshotter.SnapshotterQcow2:
+		return qcow2.ResizeOverlay(vm)
+	case snapshotter.SnapshotterRBD:
+		return rbd.ResizeOverlay(vm)
+	case snapshotter.SnapshotterReflink:
+		return reflink.ResizeOverlay(vm)
+	case snapshotter.SnapshotterZFS:
+		return zfs.ResizeOverlay(vm)
+	}
+
+	return unknownSnapshotter(vm)
+}
+
+// ActivateSnapshot sets up the snapshot of the VM so that it is active and can be used.
+// It returns the path of the bootable snapshot device.
+func ActivateSnapshot(vm *api.VM) (string, error) {
+	switch vm.Status.Snapshotter {
+	case "", snapshotter.SnapshotterDMLegacy:
+		return dmlegacy.ActivateSnapshot(vm)
+	case snapshotter.SnapshotterDMThin:
+		return dmthin.ActivateSnapshot(vm)
+	case snapshotter.SnapshotterLVM:
+		return lvm.ActivateSnapshot(vm)
+	case snapshotter.SnapshotterQcow2:
+		return qcow2.ActivateSnapshot(vm)
+	case snapshotter.SnapshotterRBD:
+		return rbd.ActivateSnapshot(vm)
+	case snapshotter.SnapshotterReflink:
+		return reflink.ActivateSnapshot(vm)
+	case snapshotter.SnapshotterZFS:
+		return zfs.ActivateSnapshot(vm)
+	}
+
+	return "", unknownSnapshotter(vm)
+}
+
+// DeactivateSnapshot tears down the active snapshot of the VM
+func DeactivateSnapshot(vm *api.VM) error {
+	switch vm.Status.Snapshotter {
+	case "", snapshotter.SnapshotterDMLegacy:
+		return dmlegacy.DeactivateSnapshot(vm)
+	case snapshotter.SnapshotterDMThin:
+		return dmthin.DeactivateSnapshot(vm)
+	case snapshotter.SnapshotterLVM:
+		return lvm.DeactivateSnapshot(vm)
+	case snapshotter.SnapshotterQcow2:
+		return qcow2.DeactivateSnapshot(vm)
+	case snapshotter.SnapshotterRBD:
+		return rbd.DeactivateSnapshot(vm)
+	case snapshotter.SnapshotterReflink:
+		return reflink.DeactivateSnapshot(vm)
+	case snapshotter.SnapshotterZFS:
+		return zfs.DeactivateSnapshot(vm)
+	}
+
+	return unknownSnapshotter(vm)
+}
+
+// RemoveSnapshot releases the storage of the VM's snapshot that isn't part of the VM directory
+func RemoveSnapshot(vm *api.VM) error {
+	switch vm.Status.Snapshotter {
+	case "", snapshotter.SnapshotterDMLegacy, snapshotter.SnapshotterQcow2, snapshotter.SnapshotterReflink:
+		return nil // The overlay file is removed together with the VM directory
+	case snapshotter.SnapshotterDMThin:
+		return dmthin.RemoveSnapshot(vm)
+	case snapshotter.SnapshotterLVM:
+		return lvm.RemoveSnapshot(vm)
+	case snapshotter.SnapshotterRBD:
+		return rbd.RemoveSnapshot(vm)
+	case snapshotter.SnapshotterZFS:
+		return zfs.RemoveSnapshot(vm)
+	}
+
+	return unknownSnapshotter(vm)
+}
+
+// SnapshotDevice returns the path of the bootable snapshot device of the VM.
+// Only for active snapshots it's guaranteed to exist.
+func SnapshotDevice(vm *api.VM) (string, error) {
+	switch vm.Status.Snapshotter {
+	case "", snapshotter.SnapshotterDMLegacy:
+		return vm.SnapshotDev(), nil
+	case snapshotter.SnapshotterDMThin:
+		return dmthin.SnapshotDevice(vm)
+	case snapshotter.SnapshotterLVM:
+		return lvm.SnapshotDevice(vm)
+	case snapshotter.SnapshotterQcow2:
+		return qcow2.SnapshotDevice(vm)
+	case snapshotter.SnapshotterRBD:
+		return rbd.SnapshotDevice(vm)
+	case snapshotter.SnapshotterReflink:
+		return reflink.SnapshotDevice(vm)
+	case snapshotter.SnapshotterZFS:
+		return zfs.SnapshotDevice(vm)
+	}
+
+	return "", unknownSnapshotter(vm)
+}
+
+// OverlayUsage returns the host disk space used by the disk of the VM on its own, i.e.
+// not counting the blocks it shares with its image. It returns snapshotter.ErrUsageUnknown
+// for the snapshotters that can't tell them apart.
+func OverlayUsage(vm *api.VM) (meta.Size, error) {
+	switch vm.Status.Snapshotter {
+	case "", snapshotter.SnapshotterDMLegacy, snapshotter.SnapshotterQcow2:
+		return dmlegacy.OverlayUsage(vm) // The overlay file only holds the writes of the VM
+	case snapshotter.SnapshotterDMThin:
+		return meta.EmptySize, snapshotter.ErrUsageUnknown
+	case snapshotter.SnapshotterLVM:
+		return lvm.OverlayUsage(vm)
+	case snapshotter.SnapshotterRBD:
+		return rbd.OverlayUsage(vm)
+	case snapshotter.SnapshotterReflink:
+		return reflink.OverlayUsage(vm)
+	case snapshotter.SnapshotterZFS:
+		return zfs.OverlayUsage(vm)
+	}
+
+	return meta.EmptySize, unknownSnapshotter(vm)
+}
+
+func unknownSnapshotter(vm *api.VM) error {
+	return fmt.Errorf("unknown snapshotter %q for VM %q", vm.Status.Snapshotter, vm.GetUID())
+}
@@ -0,0 +1,90 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+)
+
+func TestSnapshotterSelection(t *testing.T) {
+	tests := []struct {
+		name        string
+		snapshotter snapshotter.Name
+		device      string // the device path of the VM, if it doesn't need to be read
+		file        string // the file in the VM directory the device is read from
+		err         string
+	}{
+		{
+			name:   "default",
+			device: "/dev/mapper/ignite-0123456789abcdef",
+		},
+		{
+			name:        "dmlegacy",
+			snapshotter: snapshotter.SnapshotterDMLegacy,
+			device:      "/dev/mapper/ignite-0123456789abcdef",
+		},
+		{
+			name:        "dmthin",
+			snapshotter: snapshotter.SnapshotterDMThin,
+			file:        constants.DMTHIN_OVERLAY_FILE,
+		},
+		{
+			name:        "lvm",
+			snapshotter: snapshotter.SnapshotterLVM,
+			file:        constants.LVM_OVERLAY_FILE,
+		},
+		{
+			name:        "qcow2",
+			snapshotter: snapshotter.SnapshotterQcow2,
+			file:        constants.QCOW2_NBD_DEVICE_FILE,
+		},
+		{
+			name:        "rbd",
+			snapshotter: snapshotter.SnapshotterRBD,
+			file:        constants.RBD_OVERLAY_FILE,
+		},
+		{
+			name:        "reflink",
+			snapshotter: snapshotter.SnapshotterReflink,
+			file:        constants.REFLINK_LOOP_DEVICE_FILE,
+		},
+		{
+			name:        "zfs",
+			snapshotter: snapshotter.SnapshotterZFS,
+			file:        constants.ZFS_OVERLAY_FILE,
+		},
+		{
+			name:        "unknown",
+			snapshotter: "btrfs",
+			err:         `unknown snapshotter "btrfs" for VM "0123456789abcdef"`,
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			vm := &api.VM{}
+			vm.SetUID("0123456789abcdef")
+			vm.Status.Snapshotter = rt.snapshotter
+
+			// The VM doesn't exist, so the backends fail reading the files recording their devices
+			devicePath, err := SnapshotDevice(vm)
+			switch {
+			case len(rt.err) > 0:
+				assert.Error(t, err, rt.err)
+			case len(rt.file) > 0:
+				pathErr, ok := err.(*os.PathError)
+				assert.Assert(t, ok && os.IsNotExist(err), "expected a missing file, got %v", err)
+				assert.Equal(t, filepath.Base(pathErr.Path), rt.file)
+			default:
+				assert.NilError(t, err)
+				assert.Equal(t, devicePath, rt.device)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"path/filepath"
@@ -8,26 +9,52 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/agent/protocol"
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/arch"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/dmlegacy"
+	"github.com/weaveworks/ignite/pkg/emulation"
 	"github.com/weaveworks/ignite/pkg/logs"
+	"github.com/weaveworks/ignite/pkg/migration"
+	"github.com/weaveworks/ignite/pkg/nbd"
 	"github.com/weaveworks/ignite/pkg/operations/lookup"
+	"github.com/weaveworks/ignite/pkg/policy"
 	"github.com/weaveworks/ignite/pkg/providers"
 	"github.com/weaveworks/ignite/pkg/runtime"
+	"github.com/weaveworks/ignite/pkg/tracing"
 	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/ignite/pkg/vfio"
 	apiruntime "github.com/weaveworks/libgitops/pkg/runtime"
+	"go.opencensus.io/trace"
 )
 
 // VMChannels can be used to get signals for different stages of VM lifecycle
 type VMChannels struct {
 	SpawnFinished chan error
+	// Readdress is set for a VM restored from a snapshot, whose guest kept other addresses
+	// than the ones assigned by the network, to the address the guest needs to change to
+	Readdress *protocol.Identity
 }
 
-func StartVM(vm *api.VM, debug bool) error {
+func StartVM(ctx context.Context, vm *api.VM, debug bool) (err error) {
+	unlock, err := lockVM(vm)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	vmChans, err := StartVMNonBlocking(vm, debug)
+	ctx, span := tracing.Start(ctx, "vm.start", vmAttributes(vm)...)
+	defer func() { tracing.End(span, err) }()
+
+	// Restore a VM that hasn't been started before from the boot cache if it's enabled
+	cache, err := prepareBootCache(vm)
+	if err != nil {
+		return err
+	}
+
+	vmChans, err := StartVMNonBlocking(ctx, vm, debug)
 	if err != nil {
 		return err
 	}
@@ -36,32 +63,104 @@ func StartVM(vm *api.VM, debug bool) error {
 		return err
 	}
 
-	return nil
+	if vmChans.Readdress != nil {
+		if err := readdressRestoredVM(ctx, vm, vmChans.Readdress); err != nil {
+			return err
+		}
+	}
+
+	// The snapshot is taken before the secrets are delivered, so their tmpfs isn't in it
+	if cache != nil {
+		if cache.restored {
+			if err := reidentifyRestoredVM(ctx, vm); err != nil {
+				return err
+			}
+		} else if err := storeBootCache(ctx, vm, cache.key); err != nil {
+			log.Warnf("Failed to store the snapshot of VM %q in the boot cache: %v", vm.GetUID(), err)
+		}
+	}
+
+	// Deliver the secrets to the guest agent once the VM has booted
+	if len(vm.Spec.Secrets) > 0 {
+		if err := deliverSecrets(ctx, vm); err != nil {
+			return err
+		}
+	}
+
+	// Write the files a lazily imported image didn't need at boot into the disk of the VM
+	return startLoadingFiles(ctx, vm)
 }
 
-func StartVMNonBlocking(vm *api.VM, debug bool) (*VMChannels, error) {
+func StartVMNonBlocking(ctx context.Context, vm *api.VM, debug bool) (vmChans *VMChannels, err error) {
+	if err := policy.CheckVM(ctx, policy.OperationVMStart, vm); err != nil {
+		return nil, err
+	}
+
+	if err := verifyArch(vm); err != nil {
+		return nil, err
+	}
+
+	// Without KVM the VM may be emulated with QEMU, as configured
+	emulate, err := emulateVM(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	// VMs of disk images are booted by QEMU with UEFI firmware, and VMs with devices or nested
+	// virtualization are run by QEMU as Firecracker doesn't support VFIO or expose VMX/SVM
+	qemu := emulate || vm.QEMU()
+	if emulate && vm.HasDevices() {
+		return nil, fmt.Errorf("VM %q passes host PCI devices through, which needs KVM", vm.GetUID())
+	}
+	if vm.Spec.NestedVirtualization {
+		if emulate {
+			return nil, fmt.Errorf("VM %q has nested virtualization, which needs KVM", vm.GetUID())
+		}
+
+		if err := emulation.NestedAvailable(); err != nil {
+			return nil, fmt.Errorf("VM %q can't be started: %v", vm.GetUID(), err)
+		}
+	}
+
 	// Inspect the VM container and remove it if it exists
 	inspectResult, _ := providers.Runtime.InspectContainer(vm.PrefixedID())
 	RemoveVMContainer(inspectResult)
 
+	// Drop the markers of a stop or kill that didn't go through, they would apply to this run otherwise
+	ConsumeStopRequested(vm)
+	ConsumeUnhealthy(vm)
+
 	// Make sure we always initialize all channels
-	vmChans := &VMChannels{
+	vmChans = &VMChannels{
 		SpawnFinished: make(chan error),
 	}
 
 	// Setup the snapshot overlay filesystem
-	snapshotDevPath, err := dmlegacy.ActivateSnapshot(vm)
+	_, span := tracing.Start(ctx, "vm.snapshot")
+	snapshotDevPath, err := ActivateSnapshot(vm)
+	tracing.End(span, err)
 	if err != nil {
-		return vmChans, err
+		return vmChans, failCondition(vm, api.VMImageReady, "SnapshotFailed", err)
 	}
+	vm.SetCondition(api.VMImageReady, api.ConditionTrue, "SnapshotActivated", "")
 
-	kernelUID, err := lookup.KernelUIDForVM(vm, providers.Client)
-	if err != nil {
-		return vmChans, err
+	// Write the environment variables, users and hosts into the VM, a migrated VM resumes with its own.
+	// The filesystems in disk images aren't known, they're configured by e.g. cloud-init.
+	if !migration.Pending(vm) && !vm.DiskImage() {
+		if err := dmlegacy.WriteEnvironment(vm, snapshotDevPath); err != nil {
+			return vmChans, fmt.Errorf("failed to write the environment of VM %q: %v", vm.GetUID(), err)
+		}
+
+		if err := dmlegacy.WriteUsers(vm, snapshotDevPath); err != nil {
+			return vmChans, fmt.Errorf("failed to write the users of VM %q: %v", vm.GetUID(), err)
+		}
+
+		if err := dmlegacy.WriteHosts(vm, snapshotDevPath); err != nil {
+			return vmChans, fmt.Errorf("failed to write the hosts of VM %q: %v", vm.GetUID(), err)
+		}
 	}
 
 	vmDir := filepath.Join(constants.VM_DIR, vm.GetUID().String())
-	kernelDir := filepath.Join(constants.KERNEL_DIR, kernelUID.String())
 
 	// Verify that the image containing ignite-spawn is pulled
 	// TODO: Integrate automatic pulling into pkg/runtime
@@ -70,10 +169,9 @@ func StartVMNonBlocking(vm *api.VM, debug bool) (*VMChannels, error) {
 	}
 
 	config := &runtime.ContainerConfig{
-		Cmd: []string{
+		Cmd: append(append(append([]string{
 			fmt.Sprintf("--log-level=%s", logs.Logger.Level.String()),
-			vm.GetUID().String(),
-		},
+		}, logFormatArgs()...), append(consoleLogArgs(), emulationArgs(emulate)...)...), vm.GetUID().String()),
 		Labels: map[string]string{"ignite.name": vm.GetName()},
 		Binds: []*runtime.Bind{
 			{
@@ -85,17 +183,6 @@ func StartVMNonBlocking(vm *api.VM, debug bool) (*VMChannels, error) {
 				HostPath:      path.Join(vmDir, constants.METADATA),
 				ContainerPath: constants.IGNITE_SPAWN_VM_FILE_PATH,
 			},
-			{
-				// Mount the vmlinux file specifically into the container, to a well-known place for ignite-spawn to access
-				HostPath:      path.Join(kernelDir, constants.KERNEL_FILE),
-				ContainerPath: constants.IGNITE_SPAWN_VMLINUX_FILE_PATH,
-			},
-			{
-				// TODO(therealbobo): check if this has to be removed if not present
-				// Mount the initrd file specifically into the container, to a well-known place for ignite-spawn to access
-				HostPath:      path.Join(kernelDir, constants.INITRD_FILE),
-				ContainerPath: constants.IGNITE_SPAWN_INITRD_FILE_PATH,
-			},
 		},
 		CapAdds: []string{
 			"SYS_ADMIN", // Needed to run "dmsetup remove" inside the container
@@ -104,13 +191,88 @@ func StartVMNonBlocking(vm *api.VM, debug bool) (*VMChannels, error) {
 		Devices: []*runtime.Bind{
 			runtime.BindBoth("/dev/mapper/control"), // This enables containerized Ignite to remove its own dm snapshot
 			runtime.BindBoth("/dev/net/tun"),        // Needed for creating TAP adapters
-			runtime.BindBoth("/dev/kvm"),            // Pass through virtualization support
 			runtime.BindBoth(snapshotDevPath),       // The block device to boot from
 		},
 		StopTimeout:  constants.STOP_TIMEOUT + constants.IGNITE_TIMEOUT,
 		PortBindings: vm.Spec.Network.Ports, // Add the port mappings to Docker
 	}
 
+	if vm.DiskImage() {
+		// Mount the UEFI firmware booting the disk, the sandbox has a default one
+		if len(vm.Spec.Firmware) > 0 {
+			config.Binds = append(config.Binds, &runtime.Bind{
+				HostPath:      vm.Spec.Firmware,
+				ContainerPath: constants.IGNITE_SPAWN_FIRMWARE_FILE_PATH,
+			})
+		}
+	} else {
+		kernelUID, err := lookup.KernelUIDForVM(vm, providers.Client)
+		if err != nil {
+			return vmChans, err
+		}
+
+		kernelDir := filepath.Join(constants.KERNEL_DIR, kernelUID.String())
+		initrdPath := path.Join(kernelDir, constants.INITRD_FILE)
+
+		// The initrd image of the VM replaces the initrd of the kernel image
+		if vm.Spec.Kernel.InitrdRef != nil {
+			if err := importInitrd(ctx, vm); err != nil {
+				return vmChans, fmt.Errorf("failed to import the initrd of VM %q: %v", vm.GetUID(), err)
+			}
+			initrdPath = initrdFile(vm)
+		}
+
+		config.Binds = append(config.Binds,
+			&runtime.Bind{
+				// Mount the vmlinux file specifically into the container, to a well-known place for ignite-spawn to access
+				HostPath:      path.Join(kernelDir, constants.KERNEL_FILE),
+				ContainerPath: constants.IGNITE_SPAWN_VMLINUX_FILE_PATH,
+			},
+			&runtime.Bind{
+				// TODO(therealbobo): check if this has to be removed if not present
+				// Mount the initrd file specifically into the container, to a well-known place for ignite-spawn to access
+				HostPath:      initrdPath,
+				ContainerPath: constants.IGNITE_SPAWN_INITRD_FILE_PATH,
+			},
+		)
+	}
+
+	// Pass through virtualization support, emulated VMs run without it
+	if !emulate {
+		config.Devices = append(config.Devices, runtime.BindBoth(emulation.KVMDevice))
+	}
+
+	// Give back the NBD devices and PCI devices taken for the VM if it fails to start before
+	// its container runs, the container holds them afterwards
+	containerRunning := false
+	defer func() {
+		if err == nil || containerRunning {
+			return
+		}
+
+		if releaseErr := releaseHostResources(vm); releaseErr != nil {
+			log.Warnf("Failed to release the volumes and PCI devices of VM %q: %v", vm.GetUID(), releaseErr)
+		}
+	}()
+
+	// Bind the PCI devices of the VM to vfio-pci, and pass their IOMMU groups through
+	if vm.HasDevices() {
+		// Take the devices back from VMs that exited while holding them
+		releaseExitedOwners(vm)
+
+		deviceNodes, err := vfio.Bind(vm)
+		if err != nil {
+			return vmChans, fmt.Errorf("failed to pass the PCI devices of VM %q through: %v", vm.GetUID(), err)
+		}
+
+		for _, deviceNode := range deviceNodes {
+			config.Devices = append(config.Devices, runtime.BindBoth(deviceNode))
+		}
+
+		// VFIO pins the memory of the VM, which is only allowed beyond the memlock limit with IPC_LOCK
+		config.CapAdds = append(config.CapAdds, "IPC_LOCK")
+	}
+
 	var envVars []string
 	for k, v := range vm.GetObjectMeta().Annotations {
 		if strings.HasPrefix(k, constants.IGNITE_SANDBOX_ENV_VAR) {
@@ -120,43 +282,99 @@ func StartVMNonBlocking(vm *api.VM, debug bool) (*VMChannels, error) {
 	}
 	config.EnvVars = envVars
 
+	// A VM migrated from another host is restored from its snapshot, which
+	// refers to the root drive by its path on the source host
+	var manifest *migration.Manifest
+	if migration.Pending(vm) {
+		if manifest, err = migration.ReadManifest(vm); err != nil {
+			return vmChans, fmt.Errorf("failed to read the migration manifest of VM %q: %v", vm.GetUID(), err)
+		}
+
+		if manifest.DrivePath != snapshotDevPath {
+			config.Devices = append(config.Devices, &runtime.Bind{
+				HostPath:      snapshotDevPath,
+				ContainerPath: manifest.DrivePath,
+			})
+		}
+	}
+
 	// Add the volumes to the container devices
-	for _, volume := range vm.Spec.Storage.Volumes {
-		if volume.BlockDevice == nil {
-			continue // Skip all non block device volumes for now
+	for i, volume := range vm.Spec.Storage.Volumes {
+		var hostPath string
+		switch {
+		case volume.BlockDevice != nil:
+			hostPath = volume.BlockDevice.Path
+		case volume.NBD != nil:
+			// Connect the remote disk on the host, the container only sees the NBD device
+			if hostPath, err = nbd.ConnectVolume(vm, &vm.Spec.Storage.Volumes[i]); err != nil {
+				return vmChans, err
+			}
+		default:
+			continue // Skip all volumes not backed by a device
 		}
 
 		config.Devices = append(config.Devices, &runtime.Bind{
-			HostPath:      volume.BlockDevice.Path,
+			HostPath:      hostPath,
 			ContainerPath: path.Join(constants.IGNITE_SPAWN_VOLUME_DIR, volume.Name),
 		})
 	}
 
+	// Bind the custom seccomp filter of Firecracker into the container, and record the applied one.
+	// QEMU doesn't take the filters of Firecracker.
+	vm.Status.Seccomp = nil
+	if !qemu {
+		if vm.Status.Seccomp, err = seccompStatus(vm.Spec.Seccomp); err != nil {
+			return vmChans, fmt.Errorf("failed to apply the seccomp filter of VM %q: %v", vm.GetUID(), err)
+		}
+	}
+	if vm.Status.Seccomp != nil && len(vm.Status.Seccomp.Filter) > 0 {
+		config.Binds = append(config.Binds, &runtime.Bind{
+			HostPath:      vm.Status.Seccomp.Filter,
+			ContainerPath: constants.IGNITE_SPAWN_SECCOMP_FILTER_FILE_PATH,
+		})
+	}
+
 	// Prepare the networking for the container, for the given network plugin
 	if err := providers.NetworkPlugin.PrepareContainerSpec(config); err != nil {
 		return vmChans, err
 	}
 
+	// Confine the sandbox, so a VM escaping Firecracker can't access the resources of the other VMs
+	if vm.Status.Confinement, err = confine(vm, config); err != nil {
+		return vmChans, fmt.Errorf("failed to confine the sandbox of VM %q: %v", vm.GetUID(), err)
+	}
+
 	// If we're not debugging, remove the container post-run
 	if !debug {
 		config.AutoRemove = true
 	}
 
 	// Run the VM container in Docker
+	_, span = tracing.Start(ctx, "vm.container")
 	containerID, err := providers.Runtime.RunContainer(vm.Spec.Sandbox.OCI, config, vm.PrefixedID(), vm.GetUID().String())
+	tracing.End(span, err)
 	if err != nil {
 		return vmChans, fmt.Errorf("failed to start container for VM %q: %v", vm.GetUID(), err)
 	}
+	containerRunning = true
 
 	// Set up the networking
+	_, span = tracing.Start(ctx, "vm.network", trace.StringAttribute("plugin", providers.NetworkPlugin.Name().String()))
 	result, err := providers.NetworkPlugin.SetupContainerNetwork(containerID, vm.Spec.Network.Ports...)
+	tracing.End(span, err)
 	if err != nil {
-		return vmChans, err
+		return vmChans, failCondition(vm, api.VMNetworkReady, "NetworkSetupFailed", err)
 	}
 
 	if !logs.Quiet {
 		log.Infof("Networking is handled by %q", providers.NetworkPlugin.Name())
-		log.Infof("Started Firecracker VM %q in a container with ID %q", vm.GetUID(), containerID)
+		vmm := "Firecracker"
+		if emulate {
+			vmm = "emulated QEMU"
+		} else if qemu {
+			vmm = "QEMU"
+		}
+		VMLog(vm, "start").Infof("Started %s VM %q in a container with ID %q", vmm, vm.GetUID(), containerID)
 	}
 
 	// Set the container ID for the VM
@@ -171,6 +389,15 @@ func StartVMNonBlocking(vm *api.VM, debug bool) (*VMChannels, error) {
 	}
 	vm.Status.Network.Plugin = providers.NetworkPluginName
 
+	// The guest of a migrated VM keeps the addresses it had on the source host, as its DHCP
+	// lease doesn't expire. StartVM changes them to the ones assigned by the network here,
+	// which the IPAM of the network and the port mappings use.
+	if manifest != nil && len(manifest.IPAddresses) > 0 && !sameIPs(vm.Status.Network.IPAddresses, manifest.IPAddresses) {
+		vmChans.Readdress = readdressIdentity(result)
+	}
+
+	vm.SetCondition(api.VMBooted, api.ConditionUnknown, "Starting", "")
+
 	// write the API object in a non-running state before we wait for spawn's network logic and firecracker
 	if err := providers.Client.VMs().Set(vm); err != nil {
 		return vmChans, err
@@ -183,21 +410,48 @@ func StartVMNonBlocking(vm *api.VM, debug bool) (*VMChannels, error) {
 	return vmChans, nil
 }
 
+// sameIPs returns true if both lists contain the same addresses in the same order
+func sameIPs(a, b meta.IPAddresses) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // verifyPulled pulls the ignite-spawn image if it's not present
 func verifyPulled(image meta.OCIImageRef) error {
-	if _, err := providers.Runtime.InspectImage(image); err != nil {
+	res, err := providers.Runtime.InspectImage(image)
+	if err != nil {
 		log.Infof("Pulling image %q...", image)
 		if err = providers.Runtime.PullImage(image); err != nil {
 			return err
 		}
 
 		// Verify the image was pulled
-		if _, err = providers.Runtime.InspectImage(image); err != nil {
+		if res, err = providers.Runtime.InspectImage(image); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	// A sandbox image of another architecture may be present, e.g. built for a release
+	return arch.Check(fmt.Sprintf("image %q", image), res.Architecture)
+}
+
+// verifyArch verifies the image and kernel of the VM were built for the architecture of
+// the host, VMs of hosts sharing their metadata may have been created on another one
+func verifyArch(vm *api.VM) error {
+	if err := arch.Check(fmt.Sprintf("the image of VM %q", vm.GetUID()), vm.Status.Image.Architecture); err != nil {
+		return err
+	}
+
+	return arch.Check(fmt.Sprintf("the kernel of VM %q", vm.GetUID()), vm.Status.Kernel.Architecture)
 }
 
 // TODO: This check for the Prometheus socket file is temporary
@@ -214,6 +468,7 @@ func waitForSpawn(vm *api.VM, vmChans *VMChannels) {
 			vm, err := providers.Client.VMs().Get(vm.GetUID())
 			if err != nil {
 				vmChans.SpawnFinished <- err
+				return
 			}
 
 			// Set the VM's status to running
@@ -223,11 +478,78 @@ func waitForSpawn(vm *api.VM, vmChans *VMChannels) {
 			startTime := apiruntime.Timestamp()
 			vm.Status.StartTime = &startTime
 
+			// ignite-spawn serves the metrics once the network is set up, right before executing Firecracker
+			vm.SetCondition(api.VMNetworkReady, api.ConditionTrue, "AddressesServed", fmt.Sprintf("serving %v over DHCP", vm.Status.Network.IPAddresses))
+			vm.SetCondition(api.VMBooted, api.ConditionTrue, "FirecrackerStarted", "")
+			vm.SetCondition(api.VMDegraded, api.ConditionFalse, "AsExpected", "")
+
 			// Write the state changes, send any errors through the channel
 			vmChans.SpawnFinished <- providers.Client.VMs().Set(vm)
 			return
 		}
 	}
 
-	vmChans.SpawnFinished <- fmt.Errorf("timeout waiting for ignite-spawn startup")
+	err := fmt.Errorf("timeout waiting for ignite-spawn startup")
+	if vm, getErr := providers.Client.VMs().Get(vm.GetUID()); getErr == nil {
+		err = failCondition(vm, api.VMBooted, "SpawnTimeout", err)
+	}
+
+	vmChans.SpawnFinished <- err
+}
+
+// failCondition records a failed step of starting the VM as a False condition, and returns the error
+func failCondition(vm *api.VM, conditionType api.VMConditionType, reason string, err error) error {
+	vm.SetCondition(conditionType, api.ConditionFalse, reason, err.Error())
+	if setErr := providers.Client.VMs().Set(vm); setErr != nil {
+		log.Warnf("Failed to record the %s condition of VM %q: %v", conditionType, vm.GetUID(), setErr)
+	}
+
+	return err
+}
+
+// logFormatArgs returns the ignite-spawn flag for the log format, so the logs of the VM
+// container are structured like the ones of ignite. The text format is the default.
+func logFormatArgs() []string {
+	if logs.Format == logs.FormatText {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("--log-format=%s", logs.Format)}
+}
+
+// consoleLogArgs returns the ignite-spawn flags configuring the console log, as set in
+// the ignite configuration. ignite-spawn uses its defaults for the unset options.
+func consoleLogArgs() (args []string) {
+	if providers.ComponentConfig == nil {
+		return
+	}
+
+	cfg := providers.ComponentConfig.Spec.ConsoleLog
+	if cfg.MaxSize.Bytes() > 0 {
+		args = append(args, fmt.Sprintf("--console-log-max-size=%d", cfg.MaxSize.Bytes()))
+	}
+	if cfg.MaxAge.Duration > 0 {
+		args = append(args, fmt.Sprintf("--console-log-max-age=%s", cfg.MaxAge.Duration))
+	}
+	if cfg.MaxFiles > 0 {
+		args = append(args, fmt.Sprintf("--console-log-max-files=%d", cfg.MaxFiles))
+	}
+	if cfg.Compress {
+		args = append(args, "--console-log-compress")
+	}
+
+	return
+}
+
+// VMLog returns a log entry with the fields of the VM and the operation on it
+func VMLog(vm *api.VM, operation string) *log.Entry {
+	return logs.WithVM(vm.GetUID().String(), vm.GetName()).WithField(logs.FieldOperation, operation)
+}
+
+// vmAttributes are the span attributes identifying the VM
+func vmAttributes(vm *api.VM) []trace.Attribute {
+	return []trace.Attribute{
+		trace.StringAttribute("vm.uid", vm.GetUID().String()),
+		trace.StringAttribute("vm.name", vm.GetName()),
+	}
 }
@@ -0,0 +1,95 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/client"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/metadata"
+	"github.com/weaveworks/ignite/pkg/policy"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// TagImage adds the target name to an imported image, like "docker tag", so VMs can use
+// the image by either name without importing it again. The tag is a new image object
+// named after the target, its files are hard links to the files of the image, so they
+// aren't copied, and each name can be removed on its own. The OCI image is tagged in the
+// container runtime as well, so ignited sees the tag as up to date.
+func TagImage(ctx context.Context, c *client.Client, image *api.Image, target meta.OCIImageRef) (tagged *api.Image, err error) {
+	// The name is checked against the policy as if the image was imported with it
+	if err := policy.CheckImport(ctx, policy.OperationImageImport, target); err != nil {
+		return nil, err
+	}
+
+	unlock, err := lockImport("image", target)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tagged = c.Images().New()
+	tagged.Name = target.String()
+	tagged.Spec.OCI = target
+	tagged.Labels = image.Labels
+	tagged.Status = *image.Status.DeepCopy()
+
+	// Fails if an image with the name already exists, VMs using it would be corrupted
+	// by moving the name to other files
+	if err := metadata.SetNameAndUID(tagged, c); err != nil {
+		return nil, err
+	}
+
+	// Images that aren't in the container runtime anymore, like lazily imported images,
+	// keep the OCI source of the image
+	if err := providers.Runtime.TagImage(image.Spec.OCI, target); err != nil {
+		log.Warnf("Failed to tag OCI image %q as %q in %s: %v", image.Spec.OCI, target, providers.Runtime.Name(), err)
+	} else if res, err := providers.Runtime.InspectImage(target); err == nil {
+		tagged.Status.OCISource.ID = res.ID
+	}
+
+	if err := linkImageFiles(image.ObjectPath(), tagged.ObjectPath()); err != nil {
+		if rmErr := os.RemoveAll(tagged.ObjectPath()); rmErr != nil {
+			log.Warnf("image tag: failed to remove the files of the tag: %v", rmErr)
+		}
+		return nil, err
+	}
+
+	if err := c.Images().Set(tagged); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Tagged image %q as %q with UID %q", image.GetUID(), target, tagged.GetUID())
+	return tagged, nil
+}
+
+// linkImageFiles hard links the files of an image into the directory of a tag of it.
+// The metadata of the image isn't linked, as the tag has its own.
+func linkImageFiles(imageDir, tagDir string) error {
+	files, err := ioutil.ReadDir(imageDir)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(tagDir, constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	for _, fi := range files {
+		if !fi.Mode().IsRegular() || fi.Name() == constants.METADATA {
+			continue
+		}
+
+		if err := os.Link(path.Join(imageDir, fi.Name()), path.Join(tagDir, fi.Name())); err != nil {
+			return fmt.Errorf("failed to link %q of the image: %v", fi.Name(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package operations
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+func TestLinkImageFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-tag-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	imageDir := filepath.Join(dir, "image")
+	tagDir := filepath.Join(dir, "tag")
+	assert.NilError(t, os.MkdirAll(imageDir, 0755))
+	for _, name := range []string{constants.IMAGE_FS, constants.IMAGE_SBOM_SPDX, constants.METADATA} {
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(imageDir, name), []byte(name), 0644))
+	}
+
+	assert.NilError(t, linkImageFiles(imageDir, tagDir))
+
+	// The files are shared, but the tag has its own metadata
+	for _, name := range []string{constants.IMAGE_FS, constants.IMAGE_SBOM_SPDX} {
+		imageFile, err := os.Stat(filepath.Join(imageDir, name))
+		assert.NilError(t, err)
+		tagFile, err := os.Stat(filepath.Join(tagDir, name))
+		assert.NilError(t, err)
+		assert.Assert(t, os.SameFile(imageFile, tagFile), "%s isn't linked", name)
+	}
+
+	_, err = os.Stat(filepath.Join(tagDir, constants.METADATA))
+	assert.Assert(t, os.IsNotExist(err))
+
+	// Removing the image keeps the files of the tag
+	assert.NilError(t, os.RemoveAll(imageDir))
+	b, err := ioutil.ReadFile(filepath.Join(tagDir, constants.IMAGE_FS))
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), constants.IMAGE_FS)
+}
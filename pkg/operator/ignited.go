@@ -0,0 +1,163 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// ignitedClient is a client of the ignited API of a node
+type ignitedClient struct {
+	base   string
+	client *http.Client
+}
+
+// ignitedError is an error returned by the ignited API
+type ignitedError struct {
+	status  int
+	message string
+}
+
+func (e *ignitedError) Error() string {
+	return fmt.Sprintf("%d %s", e.status, e.message)
+}
+
+// isConflict returns true if the error is a conflict returned by the ignited API, e.g. when
+// removing an image that's in use
+func isConflict(err error) bool {
+	ie, ok := err.(*ignitedError)
+	return ok && ie.status == http.StatusConflict
+}
+
+// list returns the VMs, images or kernels of the node
+func (c *ignitedClient) list(ctx context.Context, kind runtime.Kind) ([]runtime.Object, error) {
+	b, err := c.do(ctx, http.MethodGet, "/v1/"+kind.Lower()+"s", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, err
+	}
+
+	objs := make([]runtime.Object, 0, len(list.Items))
+	for _, item := range list.Items {
+		obj, err := decode(kind, item)
+		if err != nil {
+			return nil, err
+		}
+
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+// create creates the VM, or imports the image or kernel, of the manifest
+func (c *ignitedClient) create(ctx context.Context, kind runtime.Kind, manifest []byte, query string) (runtime.Object, error) {
+	apiPath := "/v1/" + kind.Lower() + "s"
+	if len(query) > 0 {
+		apiPath += "?" + query
+	}
+
+	b, err := c.do(ctx, http.MethodPost, apiPath, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(kind, b)
+}
+
+// remove removes the VM, image or kernel with the UID
+func (c *ignitedClient) remove(ctx context.Context, kind runtime.Kind, uid runtime.UID, query string) error {
+	apiPath := "/v1/" + kind.Lower() + "s/" + uid.String()
+	if len(query) > 0 {
+		apiPath += "?" + query
+	}
+
+	_, err := c.do(ctx, http.MethodDelete, apiPath, nil)
+	return err
+}
+
+// setRunning starts or stops the VM with the UID
+func (c *ignitedClient) setRunning(ctx context.Context, uid runtime.UID, running bool) (*api.VM, error) {
+	action := "stop"
+	if running {
+		action = "start"
+	}
+
+	b, err := c.do(ctx, http.MethodPost, "/v1/vms/"+uid.String()+"/"+action, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := decode(api.KindVM, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj.(*api.VM), nil
+}
+
+func (c *ignitedClient) do(ctx context.Context, method, apiPath string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.base+apiPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(b, &apiErr); err != nil || len(apiErr.Error) == 0 {
+			apiErr.Error = strings.TrimSpace(string(b))
+		}
+
+		return nil, &ignitedError{resp.StatusCode, apiErr.Error}
+	}
+
+	return b, nil
+}
+
+// decode decodes the VM, image or kernel returned by the ignited API
+func decode(kind runtime.Kind, b []byte) (runtime.Object, error) {
+	var obj runtime.Object
+	switch kind {
+	case api.KindVM:
+		obj = &api.VM{}
+	case api.KindImage:
+		obj = &api.Image{}
+	case api.KindKernel:
+		obj = &api.Kernel{}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", kind)
+	}
+
+	if err := scheme.Serializer.DecodeInto(b, obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,120 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// serviceAccountDir holds the token and CA certificate of the service account of a pod
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// kubeClient is a minimal client of the Kubernetes API, which lists and patches resources
+type kubeClient struct {
+	server string
+	// tokenFile is read for every request, as the kubelet rotates the token
+	tokenFile string
+	client    *http.Client
+}
+
+// inClusterClient returns a kubeClient authenticated as the service account of the pod it runs in
+func inClusterClient() (*kubeClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if len(host) == 0 || len(port) == 0 {
+		return nil, fmt.Errorf("not running in a Kubernetes cluster, $KUBERNETES_SERVICE_HOST and $KUBERNETES_SERVICE_PORT are unset")
+	}
+
+	ca, err := ioutil.ReadFile(path.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CA certificate of the cluster: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificate found in %q", path.Join(serviceAccountDir, "ca.crt"))
+	}
+
+	return &kubeClient{
+		server:    "https://" + net.JoinHostPort(host, port),
+		tokenFile: path.Join(serviceAccountDir, "token"),
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+			},
+		},
+	}, nil
+}
+
+// get decodes the resource or list of resources at the path of the API into obj
+func (k *kubeClient) get(ctx context.Context, apiPath string, obj interface{}) error {
+	return k.do(ctx, http.MethodGet, apiPath, "", nil, obj)
+}
+
+// patch applies the JSON merge patch to the resource at the path of the API
+func (k *kubeClient) patch(ctx context.Context, apiPath string, patch interface{}) error {
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	return k.do(ctx, http.MethodPatch, apiPath, "application/merge-patch+json", b, nil)
+}
+
+func (k *kubeClient) do(ctx context.Context, method, apiPath, contentType string, body []byte, obj interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, k.server+apiPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if len(k.tokenFile) > 0 {
+		token, err := ioutil.ReadFile(k.tokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the service account token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		status := &metav1.Status{}
+		if err := json.Unmarshal(b, status); err != nil || len(status.Message) == 0 {
+			status.Message = strings.TrimSpace(string(b))
+		}
+
+		return fmt.Errorf("%s %s: %d %s", method, apiPath, resp.StatusCode, status.Message)
+	}
+
+	if obj == nil {
+		return nil
+	}
+
+	return json.Unmarshal(b, obj)
+}
@@ -0,0 +1,570 @@
+// Package operator implements a Kubernetes operator managing ignite VMs, images and kernels
+// through custom resources. The VM resources are assigned to the nodes running ignited, which
+// create, start and stop their VMs through their API. The images and kernels of the Image and
+// Kernel resources are imported on all of them.
+package operator
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// apiVersion is the API group and version of the resources, the ones of the ignite API
+	apiVersion = api.GroupName + "/v1alpha5"
+	// DefaultNodeSelector selects the nodes running ignited
+	DefaultNodeSelector = "ignite.weave.works/ignited=true"
+	// DefaultAPIPort is the port the ignited API is served on by the nodes
+	DefaultAPIPort = 7070
+	// ignitedTimeout limits the requests to the ignited API, which import images when creating VMs
+	ignitedTimeout = 10 * time.Minute
+)
+
+// Options configures the operator
+type Options struct {
+	// NodeSelector is the label selector of the nodes running ignited
+	NodeSelector string
+	// APIPort is the port of the ignited API of the nodes not annotated with its address
+	APIPort int
+	// Interval is how often the resources are reconciled
+	Interval time.Duration
+	// TLSConfig authenticates the operator to the ignited API, see pki.ClientConfig
+	TLSConfig *tls.Config
+}
+
+// Operator reconciles the VM, Image and Kernel resources of a cluster with the nodes running ignited
+type Operator struct {
+	kube    *kubeClient
+	ignited *http.Client
+	opts    Options
+}
+
+// New returns an Operator for the cluster it runs in
+func New(opts Options) (*Operator, error) {
+	kube, err := inClusterClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Operator{
+		kube: kube,
+		ignited: &http.Client{
+			Timeout:   ignitedTimeout,
+			Transport: &http.Transport{TLSClientConfig: opts.TLSConfig},
+		},
+		opts: opts,
+	}, nil
+}
+
+// resource is a VM, Image or Kernel resource, its spec is the spec of the ignite object
+type resource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              json.RawMessage `json:"spec,omitempty"`
+	Status            *vmStatus       `json:"status,omitempty"`
+}
+
+type resourceList struct {
+	Items []resource `json:"items"`
+}
+
+// vmStatus is the status of a VM resource, as its node reported it. The fields are always
+// set, so patching the status clears the ones that no longer apply.
+type vmStatus struct {
+	// Node is the node the VM is assigned to
+	Node string `json:"node"`
+	// UID is the UID of the VM on the node
+	UID string `json:"uid"`
+	// ObservedGeneration is the generation of the resource the VM was created from
+	ObservedGeneration int64    `json:"observedGeneration"`
+	Running            bool     `json:"running"`
+	IPAddresses        []string `json:"ipAddresses"`
+	// Message tells why the VM isn't as the resource asks, if it isn't
+	Message string `json:"message"`
+}
+
+// node is the part of a Kubernetes node the operator uses
+type node struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		Unschedulable bool `json:"unschedulable"`
+	} `json:"spec"`
+	Status struct {
+		Addresses []struct {
+			Type    string `json:"type"`
+			Address string `json:"address"`
+		} `json:"addresses"`
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+type nodeList struct {
+	Items []node `json:"items"`
+}
+
+func (n *node) ready() bool {
+	for _, c := range n.Status.Conditions {
+		if c.Type == "Ready" {
+			return c.Status == "True"
+		}
+	}
+
+	return false
+}
+
+// apiAddress returns the address of the ignited API of the node, from its annotation or
+// its internal IP address
+func (n *node) apiAddress(port int) string {
+	if addr := n.Annotations[constants.IGNITE_API_ADDRESS_ANNOTATION]; len(addr) > 0 {
+		return addr
+	}
+
+	for _, a := range n.Status.Addresses {
+		if a.Type == "InternalIP" {
+			return net.JoinHostPort(a.Address, strconv.Itoa(port))
+		}
+	}
+
+	return ""
+}
+
+// host is a node running ignited, with its objects
+type host struct {
+	node        string
+	schedulable bool
+	client      *ignitedClient
+	vms         []runtime.Object
+	images      []runtime.Object
+	kernels     []runtime.Object
+}
+
+func (h *host) load(ctx context.Context) (err error) {
+	if h.vms, err = h.client.list(ctx, api.KindVM); err != nil {
+		return
+	}
+
+	if h.images, err = h.client.list(ctx, api.KindImage); err != nil {
+		return
+	}
+
+	h.kernels, err = h.client.list(ctx, api.KindKernel)
+	return
+}
+
+// Run reconciles the resources every interval until the context is done
+func (o *Operator) Run(ctx context.Context) {
+	ticker := time.NewTicker(o.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := o.Sync(ctx); err != nil {
+			log.Errorf("Failed to reconcile the ignite resources: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync reconciles the resources with the nodes running ignited once. The VM resources without
+// a node are assigned to one, then the nodes are reconciled in parallel.
+func (o *Operator) Sync(ctx context.Context) error {
+	var nodes nodeList
+	if err := o.kube.get(ctx, "/api/v1/nodes?labelSelector="+url.QueryEscape(o.opts.NodeSelector), &nodes); err != nil {
+		return err
+	}
+
+	var vms, images, kernels resourceList
+	for resourceName, list := range map[string]*resourceList{"vms": &vms, "images": &images, "kernels": &kernels} {
+		if err := o.kube.get(ctx, resourcePath(resourceName, "", ""), list); err != nil {
+			return err
+		}
+	}
+
+	hosts := o.connect(ctx, nodes.Items)
+	o.schedule(ctx, vms.Items, hosts)
+
+	var wg sync.WaitGroup
+	for _, h := range hosts {
+		wg.Add(1)
+		go func(h *host) {
+			defer wg.Done()
+			o.syncHost(ctx, h, vms.Items, images.Items, kernels.Items)
+		}(h)
+	}
+	wg.Wait()
+
+	// The VMs of the nodes that can't be reached keep their last known status
+	for i := range vms.Items {
+		r := &vms.Items[i]
+		nodeName := r.Annotations[constants.IGNITE_NODE_ANNOTATION]
+		if _, ok := hosts[nodeName]; ok {
+			continue
+		}
+
+		var st vmStatus
+		if r.Status != nil {
+			st = *r.Status
+		}
+
+		st.Node = nodeName
+		st.Message = fmt.Sprintf("node %q running ignited isn't available", nodeName)
+		if len(nodeName) == 0 {
+			st.Message = "no node running ignited is available"
+		}
+		o.updateStatus(ctx, r, st)
+	}
+
+	return nil
+}
+
+// connect lists the objects of ignited on the ready nodes, the nodes that can't be reached
+// are left out
+func (o *Operator) connect(ctx context.Context, nodes []node) map[string]*host {
+	hosts := map[string]*host{}
+	for i := range nodes {
+		n := &nodes[i]
+		if !n.ready() {
+			continue
+		}
+
+		addr := n.apiAddress(o.opts.APIPort)
+		if len(addr) == 0 {
+			log.Warnf("Node %q has no internal IP address, annotate it with %s", n.Name, constants.IGNITE_API_ADDRESS_ANNOTATION)
+			continue
+		}
+
+		h := &host{
+			node:        n.Name,
+			schedulable: !n.Spec.Unschedulable,
+			client:      &ignitedClient{base: "https://" + addr, client: o.ignited},
+		}
+
+		if err := h.load(ctx); err != nil {
+			log.Warnf("Failed to reach ignited on node %q at %s: %v", n.Name, addr, err)
+			continue
+		}
+
+		hosts[n.Name] = h
+	}
+
+	return hosts
+}
+
+// schedule assigns the VM resources without a node to the schedulable node with the fewest
+// VM resources. The assignment is kept, as the VM and its overlay live on the node.
+func (o *Operator) schedule(ctx context.Context, vms []resource, hosts map[string]*host) {
+	load := map[string]int{}
+	for name, h := range hosts {
+		if h.schedulable {
+			load[name] = 0
+		}
+	}
+
+	for _, r := range vms {
+		if n, ok := load[r.Annotations[constants.IGNITE_NODE_ANNOTATION]]; ok {
+			load[r.Annotations[constants.IGNITE_NODE_ANNOTATION]] = n + 1
+		}
+	}
+
+	for i := range vms {
+		r := &vms[i]
+		if len(r.Annotations[constants.IGNITE_NODE_ANNOTATION]) > 0 {
+			continue
+		}
+
+		target := leastLoaded(load)
+		if len(target) == 0 {
+			return
+		}
+
+		patch := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{constants.IGNITE_NODE_ANNOTATION: target},
+			},
+		}
+		if err := o.kube.patch(ctx, resourcePath("vms", r.Namespace, r.Name), patch); err != nil {
+			log.Errorf("Failed to assign VM %s/%s to node %q: %v", r.Namespace, r.Name, target, err)
+			continue
+		}
+
+		log.Infof("Assigned VM %s/%s to node %q", r.Namespace, r.Name, target)
+		if r.Annotations == nil {
+			r.Annotations = map[string]string{}
+		}
+		r.Annotations[constants.IGNITE_NODE_ANNOTATION] = target
+		load[target]++
+	}
+}
+
+// leastLoaded returns the node with the fewest VMs, the first one by name if there are several
+func leastLoaded(load map[string]int) string {
+	best := ""
+	for name, n := range load {
+		if len(best) == 0 || n < load[best] || (n == load[best] && name < best) {
+			best = name
+		}
+	}
+
+	return best
+}
+
+// syncHost imports the images and kernels on the node, reconciles the VMs assigned to it,
+// and removes the images and kernels no resource refers to anymore
+func (o *Operator) syncHost(ctx context.Context, h *host, vms, images, kernels []resource) {
+	o.importAll(ctx, h, api.KindImage, images, h.images)
+	o.importAll(ctx, h, api.KindKernel, kernels, h.kernels)
+	o.syncVMs(ctx, h, vms)
+	o.prune(ctx, h, api.KindImage, images, h.images)
+	o.prune(ctx, h, api.KindKernel, kernels, h.kernels)
+}
+
+// importAll imports the images or kernels of the resources that the node doesn't have yet
+func (o *Operator) importAll(ctx context.Context, h *host, kind runtime.Kind, resources []resource, existing []runtime.Object) {
+	have := map[string]bool{}
+	for _, obj := range existing {
+		have[obj.GetName()] = true
+	}
+
+	for i := range resources {
+		r := &resources[i]
+		ref, err := resourceOCI(r)
+		if err != nil {
+			log.Warnf("Skipping %s %q: %v", kind, r.Name, err)
+			continue
+		}
+
+		if have[ref.String()] {
+			continue
+		}
+
+		b, err := manifest(kind, r, map[string]string{constants.IGNITE_OWNER_LABEL: string(r.UID)})
+		if err != nil {
+			log.Errorf("Failed to encode %s %q: %v", kind, r.Name, err)
+			continue
+		}
+
+		log.Infof("Importing %s %q on node %q...", kind.Lower(), ref, h.node)
+		if _, err := h.client.create(ctx, kind, b, ""); err != nil {
+			log.Errorf("Failed to import %s %q on node %q: %v", kind.Lower(), ref, h.node, err)
+		}
+	}
+}
+
+// prune removes the images or kernels the operator imported on the node that no resource
+// refers to anymore. The ones still used by VMs are kept until they aren't.
+func (o *Operator) prune(ctx context.Context, h *host, kind runtime.Kind, resources []resource, existing []runtime.Object) {
+	wanted := map[string]bool{}
+	for i := range resources {
+		if ref, err := resourceOCI(&resources[i]); err == nil {
+			wanted[ref.String()] = true
+		}
+	}
+
+	for _, obj := range existing {
+		if len(obj.GetObjectMeta().GetLabel(constants.IGNITE_OWNER_LABEL)) == 0 || wanted[obj.GetName()] {
+			continue
+		}
+
+		err := h.client.remove(ctx, kind, obj.GetUID(), "")
+		if isConflict(err) {
+			log.Debugf("Keeping %s %q on node %q: %v", kind.Lower(), obj.GetName(), h.node, err)
+		} else if err != nil {
+			log.Errorf("Failed to remove %s %q from node %q: %v", kind.Lower(), obj.GetName(), h.node, err)
+		} else {
+			log.Infof("Removed %s %q from node %q", kind.Lower(), obj.GetName(), h.node)
+		}
+	}
+}
+
+// syncVMs removes the VMs of the operator on the node whose resources were removed or assigned
+// to another node, and reconciles the VMs of the resources assigned to it
+func (o *Operator) syncVMs(ctx context.Context, h *host, resources []resource) {
+	wanted := map[string]bool{}
+	for _, r := range resources {
+		if r.Annotations[constants.IGNITE_NODE_ANNOTATION] == h.node {
+			wanted[string(r.UID)] = true
+		}
+	}
+
+	owned := map[string]*api.VM{}
+	for _, obj := range h.vms {
+		vm := obj.(*api.VM)
+		owner := vm.GetLabel(constants.IGNITE_OWNER_LABEL)
+		if len(owner) == 0 {
+			continue
+		}
+
+		if wanted[owner] {
+			owned[owner] = vm
+			continue
+		}
+
+		log.Infof("Removing VM %q with name %q from node %q, its resource is gone", vm.GetUID(), vm.GetName(), h.node)
+		if err := h.client.remove(ctx, api.KindVM, vm.GetUID(), "force=true"); err != nil {
+			log.Errorf("Failed to remove VM %q from node %q: %v", vm.GetUID(), h.node, err)
+		}
+	}
+
+	for i := range resources {
+		r := &resources[i]
+		if r.Annotations[constants.IGNITE_NODE_ANNOTATION] != h.node {
+			continue
+		}
+
+		vm, err := o.syncVM(ctx, h, r, owned[string(r.UID)])
+		st := vmStatus{Node: h.node}
+		if vm != nil {
+			st.UID = string(vm.GetUID())
+			st.ObservedGeneration, _ = strconv.ParseInt(vm.GetLabel(constants.IGNITE_GENERATION_LABEL), 10, 64)
+			st.Running = vm.Status.Running
+			if vm.Status.Network != nil {
+				for _, ip := range vm.Status.Network.IPAddresses {
+					st.IPAddresses = append(st.IPAddresses, ip.String())
+				}
+			}
+		}
+
+		if err != nil {
+			st.Message = err.Error()
+			log.Errorf("Failed to reconcile VM %s/%s on node %q: %v", r.Namespace, r.Name, h.node, err)
+		}
+		o.updateStatus(ctx, r, st)
+	}
+}
+
+// syncVM creates the VM of the resource on the node, replacing the VM of an earlier generation
+// of the resource, and starts or stops it as the resource asks
+func (o *Operator) syncVM(ctx context.Context, h *host, r *resource, vm *api.VM) (*api.VM, error) {
+	generation := strconv.FormatInt(r.Generation, 10)
+	running := r.Annotations[constants.IGNITE_RUNNING_ANNOTATION] != "false"
+
+	if vm != nil && vm.GetLabel(constants.IGNITE_GENERATION_LABEL) != generation {
+		log.Infof("Replacing VM %q of %s/%s on node %q with generation %s of its resource...", vm.GetUID(), r.Namespace, r.Name, h.node, generation)
+		if err := h.client.remove(ctx, api.KindVM, vm.GetUID(), "force=true"); err != nil {
+			return vm, err
+		}
+		vm = nil
+	}
+
+	if vm == nil {
+		b, err := manifest(api.KindVM, r, map[string]string{
+			constants.IGNITE_OWNER_LABEL:      string(r.UID),
+			constants.IGNITE_GENERATION_LABEL: generation,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		log.Infof("Creating VM for %s/%s on node %q...", r.Namespace, r.Name, h.node)
+		obj, err := h.client.create(ctx, api.KindVM, b, "start="+strconv.FormatBool(running))
+		if err != nil {
+			return nil, err
+		}
+
+		return obj.(*api.VM), nil
+	}
+
+	if vm.Status.Running != running {
+		log.Infof("Setting VM %q of %s/%s on node %q running: %t", vm.GetUID(), r.Namespace, r.Name, h.node, running)
+		updated, err := h.client.setRunning(ctx, vm.GetUID(), running)
+		if err != nil {
+			return vm, err
+		}
+		vm = updated
+	}
+
+	return vm, nil
+}
+
+// updateStatus patches the status of the VM resource, if it changed
+func (o *Operator) updateStatus(ctx context.Context, r *resource, st vmStatus) {
+	if r.Status != nil && reflect.DeepEqual(*r.Status, st) {
+		return
+	}
+
+	patch := map[string]interface{}{"status": st}
+	if err := o.kube.patch(ctx, resourcePath("vms", r.Namespace, r.Name)+"/status", patch); err != nil {
+		log.Errorf("Failed to update the status of VM %s/%s: %v", r.Namespace, r.Name, err)
+	}
+}
+
+// manifest returns the ignite manifest of the resource, with its labels and the given ones.
+// VMs are named <namespace>.<name> after their resource.
+func manifest(kind runtime.Kind, r *resource, labels map[string]string) ([]byte, error) {
+	for k, v := range r.Labels {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+
+	name := r.Name
+	if len(r.Namespace) > 0 {
+		name = r.Namespace + "." + r.Name
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":   name,
+			"labels": labels,
+		},
+		"spec": r.Spec,
+	})
+}
+
+// resourceOCI returns the OCI image of an Image or Kernel resource
+func resourceOCI(r *resource) (meta.OCIImageRef, error) {
+	var spec struct {
+		OCI meta.OCIImageRef `json:"oci"`
+	}
+
+	if err := json.Unmarshal(r.Spec, &spec); err != nil {
+		return spec.OCI, err
+	}
+
+	if spec.OCI.IsUnset() {
+		return spec.OCI, fmt.Errorf("spec.oci is required")
+	}
+
+	return spec.OCI, nil
+}
+
+// resourcePath returns the API path of the resources, or of the one with the given name.
+// The resources of all namespaces are listed without a namespace.
+func resourcePath(resourceName, namespace, name string) string {
+	p := "/apis/" + apiVersion
+	if len(namespace) > 0 {
+		p += "/namespaces/" + namespace
+	}
+
+	p += "/" + resourceName
+	if len(name) > 0 {
+		p += "/" + name
+	}
+
+	return p
+}
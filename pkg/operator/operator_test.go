@@ -0,0 +1,144 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+// recorder records the requests changing something, and serves the GET requests from its responses
+type recorder struct {
+	mu        sync.Mutex
+	requests  []string
+	bodies    map[string]string
+	responses map[string]string
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, _ := ioutil.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodGet {
+		_, _ = w.Write([]byte(rec.responses[r.URL.Path]))
+		return
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	request := r.Method + " " + r.URL.RequestURI()
+	rec.requests = append(rec.requests, request)
+	rec.bodies[request] = string(b)
+
+	if resp, ok := rec.responses[request]; ok {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(resp))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestSync(t *testing.T) {
+	ignited := &recorder{
+		bodies: map[string]string{},
+		responses: map[string]string{
+			// A VM of a removed resource, and a VM the operator doesn't manage
+			"/v1/vms": `{"kind":"VMList","items":[
+				{"kind":"VM","apiVersion":"ignite.weave.works/v1alpha5","metadata":{"name":"gone","uid":"orphan","labels":{"ignite.weave.works/owner":"uid-gone"}},"spec":{"image":{"oci":"weaveworks/ignite-ubuntu:latest"}},"status":{"running":true}},
+				{"kind":"VM","apiVersion":"ignite.weave.works/v1alpha5","metadata":{"name":"manual","uid":"manual"},"spec":{"image":{"oci":"weaveworks/ignite-ubuntu:latest"}},"status":{"running":true}}]}`,
+			"/v1/images": `{"kind":"ImageList","items":[
+				{"kind":"Image","apiVersion":"ignite.weave.works/v1alpha5","metadata":{"name":"old/image:1","uid":"oldimg","labels":{"ignite.weave.works/owner":"uid-old"}},"spec":{"oci":"old/image:1"}}]}`,
+			"/v1/kernels":     `{"kind":"KernelList","items":[]}`,
+			"POST /v1/images": `{"kind":"Image","apiVersion":"ignite.weave.works/v1alpha5","metadata":{"name":"weaveworks/ignite-ubuntu:latest","uid":"img"},"spec":{"oci":"weaveworks/ignite-ubuntu:latest"}}`,
+			"POST /v1/vms?start=true": `{"kind":"VM","apiVersion":"ignite.weave.works/v1alpha5",
+				"metadata":{"name":"default.web","uid":"vm1","labels":{"ignite.weave.works/generation":"2","ignite.weave.works/owner":"uid-web"}},
+				"spec":{"image":{"oci":"weaveworks/ignite-ubuntu:latest"}},
+				"status":{"running":true,"network":{"plugin":"cni","ipAddresses":["10.61.0.2"]}}}`,
+		},
+	}
+	ignitedServer := httptest.NewTLSServer(ignited)
+	defer ignitedServer.Close()
+
+	kube := &recorder{
+		bodies: map[string]string{},
+		responses: map[string]string{
+			"/api/v1/nodes": `{"items":[
+				{"metadata":{"name":"node-a","annotations":{"ignite.weave.works/api-address":"` + ignitedServer.Listener.Addr().String() + `"}},
+				 "status":{"conditions":[{"type":"Ready","status":"True"}]}},
+				{"metadata":{"name":"node-b"},"status":{"conditions":[{"type":"Ready","status":"False"}]}}]}`,
+			"/apis/ignite.weave.works/v1alpha5/vms": `{"items":[
+				{"metadata":{"name":"web","namespace":"default","uid":"uid-web","generation":2,"labels":{"app":"web"}},
+				 "spec":{"image":{"oci":"weaveworks/ignite-ubuntu:latest"}}}]}`,
+			"/apis/ignite.weave.works/v1alpha5/images": `{"items":[
+				{"metadata":{"name":"ubuntu","uid":"uid-img"},"spec":{"oci":"weaveworks/ignite-ubuntu:latest"}}]}`,
+			"/apis/ignite.weave.works/v1alpha5/kernels": `{"items":[]}`,
+		},
+	}
+	kubeServer := httptest.NewServer(kube)
+	defer kubeServer.Close()
+
+	o := &Operator{
+		kube:    &kubeClient{server: kubeServer.URL, client: kubeServer.Client()},
+		ignited: ignitedServer.Client(),
+		opts:    Options{NodeSelector: DefaultNodeSelector, APIPort: DefaultAPIPort},
+	}
+	assert.NilError(t, o.Sync(context.Background()))
+
+	// The image is imported before the VM is created, and the unused image is removed last
+	assert.DeepEqual(t, ignited.requests, []string{
+		"POST /v1/images",
+		"DELETE /v1/vms/orphan?force=true",
+		"POST /v1/vms?start=true",
+		"DELETE /v1/images/oldimg",
+	})
+
+	var vm map[string]interface{}
+	assert.NilError(t, json.Unmarshal([]byte(ignited.bodies["POST /v1/vms?start=true"]), &vm))
+	assert.DeepEqual(t, vm["metadata"], map[string]interface{}{
+		"name": "default.web",
+		"labels": map[string]interface{}{
+			"app":                           "web",
+			"ignite.weave.works/owner":      "uid-web",
+			"ignite.weave.works/generation": "2",
+		},
+	})
+
+	// The VM is assigned to the ready node, and its status is reported
+	assert.DeepEqual(t, kube.requests, []string{
+		"PATCH /apis/ignite.weave.works/v1alpha5/namespaces/default/vms/web",
+		"PATCH /apis/ignite.weave.works/v1alpha5/namespaces/default/vms/web/status",
+	})
+	assert.Equal(t, kube.bodies[kube.requests[0]], `{"metadata":{"annotations":{"ignite.weave.works/node":"node-a"}}}`)
+	assert.Equal(t, strings.TrimSpace(kube.bodies[kube.requests[1]]),
+		`{"status":{"node":"node-a","uid":"vm1","observedGeneration":2,"running":true,"ipAddresses":["10.61.0.2"],"message":""}}`)
+}
+
+func TestLeastLoaded(t *testing.T) {
+	tests := []struct {
+		load     map[string]int
+		expected string
+	}{
+		{map[string]int{}, ""},
+		{map[string]int{"b": 1, "a": 2}, "b"},
+		{map[string]int{"b": 0, "a": 0, "c": 0}, "a"},
+	}
+
+	for _, rt := range tests {
+		assert.Equal(t, leastLoaded(rt.load), rt.expected)
+	}
+}
+
+func TestResourceOCI(t *testing.T) {
+	ref, err := resourceOCI(&resource{Spec: json.RawMessage(`{"oci":"weaveworks/ignite-kernel:5.10.51"}`)})
+	assert.NilError(t, err)
+	assert.Equal(t, ref.String(), "weaveworks/ignite-kernel:5.10.51")
+
+	_, err = resourceOCI(&resource{Spec: json.RawMessage(`{}`)})
+	assert.Error(t, err, "spec.oci is required")
+}
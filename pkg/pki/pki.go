@@ -0,0 +1,260 @@
+// Package pki manages the certificate authority of the ignited API. The CA issues the
+// certificate the API is served with, and the certificates of its clients, which the
+// API requires when it's served on TCP.
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// CAName is the common name of the CA
+	CAName = "ignited-ca"
+	// ServerName is the common name of the certificate the API is served with
+	ServerName = "ignited"
+
+	caFile     = "ca"
+	serverFile = "server"
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	certValidity = 365 * 24 * time.Hour
+)
+
+// CertPath returns the path of the certificate with the given file name in dir
+func CertPath(dir, name string) string {
+	return filepath.Join(dir, name+".crt")
+}
+
+// KeyPath returns the path of the private key with the given file name in dir
+func KeyPath(dir, name string) string {
+	return filepath.Join(dir, name+".key")
+}
+
+// Init creates the CA in dir, and issues the server certificate for the given host names
+// and IP addresses with it. An existing CA is kept unless force is set, as replacing it
+// invalidates the issued client certificates.
+func Init(dir string, hosts []string, force bool) error {
+	if _, err := os.Stat(CertPath(dir, caFile)); err == nil && !force {
+		return fmt.Errorf("a CA already exists in %q", dir)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: CAName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	der, err := sign(template, template, key.Public(), key)
+	if err != nil {
+		return err
+	}
+
+	if err := write(dir, caFile, der, key); err != nil {
+		return err
+	}
+
+	return issue(dir, dir, serverFile, ServerName, hosts, x509.ExtKeyUsageServerAuth)
+}
+
+// IssueServer issues the server certificate for the given host names and IP addresses with
+// the existing CA in dir, e.g. a CA copied from another host. The clients are accepted by the
+// APIs of all the hosts sharing the CA.
+func IssueServer(dir string, hosts []string) error {
+	return issue(dir, dir, serverFile, ServerName, hosts, x509.ExtKeyUsageServerAuth)
+}
+
+// Issue issues a client certificate for name with the CA in dir, and writes it, its key
+// and the CA certificate to outDir. The name is recorded as the actor of the API requests
+// of the client.
+func Issue(dir, outDir, name string) error {
+	if len(name) == 0 || strings.ContainsAny(name, "/\x00") || name == caFile || name == serverFile {
+		return fmt.Errorf("invalid client name %q", name)
+	}
+
+	if err := os.MkdirAll(outDir, 0700); err != nil {
+		return err
+	}
+
+	if err := issue(dir, outDir, name, name, nil, x509.ExtKeyUsageClientAuth); err != nil {
+		return err
+	}
+
+	// The client verifies the API with the CA certificate
+	if filepath.Clean(outDir) == filepath.Clean(dir) {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(CertPath(dir, caFile))
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(CertPath(outDir, caFile), b, 0644)
+}
+
+// ServerConfig returns the TLS configuration of the API, with the server certificate in
+// dir. Clients need to present a certificate issued by the CA in dir.
+func ServerConfig(dir string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(CertPath(dir, serverFile), KeyPath(dir, serverFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the server certificate, create it with \"ignited certs init\": %v", err)
+	}
+
+	pool, err := caPool(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientConfig returns the TLS configuration of a client of the API, with the certificate
+// issued for name in dir by "ignited certs issue". The API is verified with the CA
+// certificate in dir.
+func ClientConfig(dir, name string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(CertPath(dir, name), KeyPath(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the client certificate, issue it with \"ignited certs issue\": %v", err)
+	}
+
+	pool, err := caPool(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func caPool(dir string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(CertPath(dir, caFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the CA certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificate found in %q", CertPath(dir, caFile))
+	}
+
+	return pool, nil
+}
+
+// issue signs a certificate for commonName and the hosts with the CA in dir, and writes
+// it to outDir as file
+func issue(dir, outDir, file, commonName string, hosts []string, usage x509.ExtKeyUsage) error {
+	ca, caKey, err := loadCA(dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{usage},
+	}
+
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	der, err := sign(template, ca, key.Public(), caKey)
+	if err != nil {
+		return err
+	}
+
+	return write(outDir, file, der, key)
+}
+
+func loadCA(dir string) (*x509.Certificate, crypto.Signer, error) {
+	pair, err := tls.LoadX509KeyPair(CertPath(dir, caFile), KeyPath(dir, caFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load the CA, create it with \"ignited certs init\": %v", err)
+	}
+
+	ca, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signer, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("the CA key can't sign certificates")
+	}
+
+	return ca, signer, nil
+}
+
+func sign(template, parent *x509.Certificate, pub crypto.PublicKey, signer crypto.Signer) ([]byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serial
+
+	return x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+}
+
+// write writes the certificate and its private key, which only the owner can read
+func write(dir, file string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := ioutil.WriteFile(KeyPath(dir, file), keyPEM, 0600); err != nil {
+		return err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return ioutil.WriteFile(CertPath(dir, file), certPEM, 0644)
+}
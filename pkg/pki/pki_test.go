@@ -0,0 +1,123 @@
+package pki
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestInit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-pki")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NilError(t, Init(dir, []string{"localhost", "127.0.0.1"}, false))
+	assert.ErrorContains(t, Init(dir, nil, false), "already exists")
+	assert.NilError(t, Init(dir, []string{"localhost", "127.0.0.1"}, true))
+
+	pair, err := tls.LoadX509KeyPair(CertPath(dir, serverFile), KeyPath(dir, serverFile))
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	assert.NilError(t, err)
+	assert.Equal(t, cert.Subject.CommonName, ServerName)
+	assert.DeepEqual(t, cert.DNSNames, []string{"localhost"})
+	assert.Equal(t, cert.IPAddresses[0].String(), "127.0.0.1")
+
+	info, err := os.Stat(KeyPath(dir, caFile))
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0600))
+
+	// The server certificate is issued again with the same CA
+	ca, err := ioutil.ReadFile(CertPath(dir, caFile))
+	assert.NilError(t, err)
+	assert.NilError(t, IssueServer(dir, []string{"node-b"}))
+	caAfter, err := ioutil.ReadFile(CertPath(dir, caFile))
+	assert.NilError(t, err)
+	assert.Equal(t, string(caAfter), string(ca))
+
+	pair, err = tls.LoadX509KeyPair(CertPath(dir, serverFile), KeyPath(dir, serverFile))
+	assert.NilError(t, err)
+	cert, err = x509.ParseCertificate(pair.Certificate[0])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cert.DNSNames, []string{"node-b"})
+}
+
+func TestIssue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-pki")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.ErrorContains(t, Issue(dir, dir, "client"), "create it with")
+	assert.NilError(t, Init(dir, nil, false))
+
+	for _, name := range []string{"", "ca", "server", "../client"} {
+		assert.ErrorContains(t, Issue(dir, dir, name), "invalid client name")
+	}
+
+	outDir := filepath.Join(dir, "out")
+	assert.NilError(t, Issue(dir, outDir, "client"))
+	_, err = os.Stat(CertPath(outDir, caFile))
+	assert.NilError(t, err)
+}
+
+func TestServerConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-pki")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NilError(t, Init(dir, []string{"127.0.0.1"}, false))
+	assert.NilError(t, Issue(dir, dir, "client"))
+
+	cfg, err := ServerConfig(dir)
+	assert.NilError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = cfg
+	server.StartTLS()
+	defer server.Close()
+
+	pool, err := caPool(dir)
+	assert.NilError(t, err)
+	get := func(certs ...tls.Certificate) (string, error) {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, Certificates: certs},
+		}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		return string(b), err
+	}
+
+	// Clients without a certificate are rejected
+	_, err = get()
+	assert.Assert(t, err != nil)
+
+	clientCert, err := tls.LoadX509KeyPair(CertPath(dir, "client"), KeyPath(dir, "client"))
+	assert.NilError(t, err)
+	actor, err := get(clientCert)
+	assert.NilError(t, err)
+	assert.Equal(t, actor, "client")
+
+	// The client configuration presents the certificate, and verifies the server
+	clientCfg, err := ClientConfig(dir, "client")
+	assert.NilError(t, err)
+	resp, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: clientCfg}}).Get(server.URL)
+	assert.NilError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+
+	_, err = ClientConfig(dir, "nonexistent")
+	assert.Assert(t, err != nil)
+}
@@ -0,0 +1,153 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// Operation is an operation checked against the policy
+type Operation string
+
+const (
+	OperationImageImport  Operation = "image.import"
+	OperationKernelImport Operation = "kernel.import"
+	OperationVMCreate     Operation = "vm.create"
+	OperationVMStart      Operation = "vm.start"
+)
+
+// Input describes the operation checked against the policy, it's the input of the Rego policies
+type Input struct {
+	Operation Operation `json:"operation"`
+	// Image is the normalized OCI image imported, set for the image and kernel imports
+	Image string `json:"image,omitempty"`
+	// VM is the VM created or started
+	VM *api.VM `json:"vm,omitempty"`
+}
+
+// DeniedError is returned for the operations the policy denies
+type DeniedError struct {
+	Operation Operation
+	Reasons   []string
+}
+
+var _ error = &DeniedError{}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("%s denied by policy: %s", e.Operation, strings.Join(e.Reasons, "; "))
+}
+
+// CheckImport checks the import of the OCI image against the policy of the ignite configuration
+func CheckImport(ctx context.Context, op Operation, oci meta.OCIImageRef) error {
+	return check(ctx, &Input{Operation: op, Image: oci.Normalized()})
+}
+
+// CheckVM checks the creation or start of the VM against the policy of the ignite configuration
+func CheckVM(ctx context.Context, op Operation, vm *api.VM) error {
+	return check(ctx, &Input{Operation: op, VM: vm})
+}
+
+func check(ctx context.Context, input *Input) error {
+	if providers.ComponentConfig == nil {
+		return nil
+	}
+
+	cfg := &providers.ComponentConfig.Spec.Policy
+	reasons := Evaluate(cfg, input)
+	if cfg.Rego != nil {
+		regoReasons, err := evaluateRego(ctx, cfg.Rego, input)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate the policy %q: %v", cfg.Rego.Path, err)
+		}
+		reasons = append(reasons, regoReasons...)
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	log.Debugf("Policy denied %s: %v", input.Operation, reasons)
+	return &DeniedError{input.Operation, reasons}
+}
+
+// Evaluate returns the reasons the rules of the policy, besides the Rego policy, deny the operation for
+func Evaluate(cfg *api.PolicyConfiguration, input *Input) (reasons []string) {
+	if len(input.Image) != 0 {
+		reasons = append(reasons, imageReasons(cfg, input.Image)...)
+	}
+
+	vm := input.VM
+	if vm == nil {
+		return
+	}
+
+	refs := []meta.OCIImageRef{vm.Spec.Image.OCI, vm.Spec.Kernel.OCI}
+	if vm.Spec.Kernel.InitrdRef != nil {
+		refs = append(refs, *vm.Spec.Kernel.InitrdRef)
+	}
+
+	for _, oci := range refs {
+		if oci.IsUnset() {
+			continue
+		}
+
+		reasons = append(reasons, imageReasons(cfg, oci.Normalized())...)
+		if cfg.RequireDigests && len(oci.Digest()) == 0 {
+			reasons = append(reasons, fmt.Sprintf("image %q isn't pinned to a digest", oci.Normalized()))
+		}
+	}
+
+	if cfg.MaxCPUs != 0 && vm.Spec.CPUs > cfg.MaxCPUs {
+		reasons = append(reasons, fmt.Sprintf("the VM has %d vCPUs, more than the %d allowed", vm.Spec.CPUs, cfg.MaxCPUs))
+	}
+
+	if cfg.MaxMemory.Bytes() != 0 && vm.Spec.Memory.Bytes() > cfg.MaxMemory.Bytes() {
+		reasons = append(reasons, fmt.Sprintf("the VM has %s of memory, more than the %s allowed", vm.Spec.Memory, cfg.MaxMemory))
+	}
+
+	if cfg.DenyBlockDevices {
+		for _, volume := range vm.Spec.Storage.Volumes {
+			if volume.BlockDevice != nil {
+				reasons = append(reasons, fmt.Sprintf("volume %q passes the host block device %q through", volume.Name, volume.BlockDevice.Path))
+			}
+		}
+	}
+
+	if cfg.DenyDevices {
+		for _, device := range vm.Spec.Devices {
+			reasons = append(reasons, fmt.Sprintf("the VM passes the host PCI device %q through", device.Address))
+		}
+	}
+
+	return
+}
+
+// imageReasons returns the reasons the registry rules of the policy deny the normalized image for
+func imageReasons(cfg *api.PolicyConfiguration, image string) (reasons []string) {
+	if len(cfg.AllowedRegistries) != 0 && !fromRegistry(cfg.AllowedRegistries, image) {
+		reasons = append(reasons, fmt.Sprintf("image %q isn't from an allowed registry", image))
+	}
+
+	if fromRegistry(cfg.DeniedRegistries, image) {
+		reasons = append(reasons, fmt.Sprintf("image %q is from a denied registry", image))
+	}
+
+	return
+}
+
+// fromRegistry returns if the normalized image is from one of the registries or repositories
+func fromRegistry(registries []string, image string) bool {
+	for _, registry := range registries {
+		registry = strings.TrimSuffix(registry, "/")
+		if strings.HasPrefix(image, registry+"/") || strings.HasPrefix(image, registry+":") || strings.HasPrefix(image, registry+"@") {
+			return true
+		}
+	}
+
+	return false
+}
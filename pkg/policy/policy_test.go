@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+func newVM(t *testing.T, image string, cpus uint64, memory string) *api.VM {
+	vm := &api.VM{}
+	vm.SetName("test-vm")
+
+	var err error
+	vm.Spec.Image.OCI, err = meta.NewOCIImageRef(image)
+	assert.NilError(t, err)
+	vm.Spec.Kernel.OCI, err = meta.NewOCIImageRef("weaveworks/ignite-kernel:5.10.51")
+	assert.NilError(t, err)
+	vm.Spec.CPUs = cpus
+	vm.Spec.Memory, err = meta.NewSizeFromString(memory)
+	assert.NilError(t, err)
+	return vm
+}
+
+func TestEvaluate(t *testing.T) {
+	maxMemory, err := meta.NewSizeFromString("4GB")
+	assert.NilError(t, err)
+	cfg := &api.PolicyConfiguration{
+		AllowedRegistries: []string{"docker.io/weaveworks", "ghcr.io/"},
+		DeniedRegistries:  []string{"docker.io/weaveworks/ignite-untrusted"},
+		MaxCPUs:           4,
+		MaxMemory:         maxMemory,
+		DenyBlockDevices:  true,
+		DenyDevices:       true,
+	}
+
+	tests := []struct {
+		name    string
+		input   *Input
+		reasons []string
+	}{
+		{
+			name:  "allowed image import",
+			input: &Input{Operation: OperationImageImport, Image: "docker.io/weaveworks/ignite-ubuntu:latest"},
+		},
+		{
+			name:  "allowed registry",
+			input: &Input{Operation: OperationKernelImport, Image: "ghcr.io/example/kernel:5.10"},
+		},
+		{
+			name:    "denied image import",
+			input:   &Input{Operation: OperationImageImport, Image: "docker.io/library/ubuntu:20.04"},
+			reasons: []string{`image "docker.io/library/ubuntu:20.04" isn't from an allowed registry`},
+		},
+		{
+			name:    "repository prefix isn't a registry",
+			input:   &Input{Operation: OperationImageImport, Image: "docker.io/weaveworksx/ubuntu:latest"},
+			reasons: []string{`image "docker.io/weaveworksx/ubuntu:latest" isn't from an allowed registry`},
+		},
+		{
+			name:    "denied repository",
+			input:   &Input{Operation: OperationImageImport, Image: "docker.io/weaveworks/ignite-untrusted:latest"},
+			reasons: []string{`image "docker.io/weaveworks/ignite-untrusted:latest" is from a denied registry`},
+		},
+		{
+			name:  "allowed VM",
+			input: &Input{Operation: OperationVMCreate, VM: newVM(t, "weaveworks/ignite-ubuntu", 4, "4GB")},
+		},
+		{
+			name:  "denied VM",
+			input: &Input{Operation: OperationVMStart, VM: newVM(t, "ubuntu:20.04", 8, "8GB")},
+			reasons: []string{
+				`image "docker.io/library/ubuntu:20.04" isn't from an allowed registry`,
+				"the VM has 8 vCPUs, more than the 4 allowed",
+				"the VM has 8.0 GB of memory, more than the 4.0 GB allowed",
+			},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.DeepEqual(t, Evaluate(cfg, rt.input), rt.reasons)
+		})
+	}
+
+	vm := newVM(t, "weaveworks/ignite-ubuntu", 1, "512MB")
+	vm.Spec.Storage.Volumes = []api.Volume{
+		{Name: "scratch", Tmpfs: &api.TmpfsVolume{}},
+		{Name: "data", BlockDevice: &api.BlockDeviceVolume{Path: "/dev/sdb"}},
+	}
+	assert.DeepEqual(t, Evaluate(cfg, &Input{Operation: OperationVMCreate, VM: vm}),
+		[]string{`volume "data" passes the host block device "/dev/sdb" through`})
+
+	vm = newVM(t, "weaveworks/ignite-ubuntu", 1, "512MB")
+	vm.Spec.Devices = []api.PCIDevice{{Address: "0000:01:00.0"}}
+	assert.DeepEqual(t, Evaluate(cfg, &Input{Operation: OperationVMStart, VM: vm}),
+		[]string{`the VM passes the host PCI device "0000:01:00.0" through`})
+
+	// Only VMs with images and kernels pinned to digests are allowed
+	digestCfg := &api.PolicyConfiguration{RequireDigests: true}
+	vm = newVM(t, "weaveworks/ignite-ubuntu@sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e", 1, "512MB")
+	assert.DeepEqual(t, Evaluate(digestCfg, &Input{Operation: OperationVMCreate, VM: vm}),
+		[]string{`image "docker.io/weaveworks/ignite-kernel:5.10.51" isn't pinned to a digest`})
+
+	// Nothing is denied without rules
+	assert.Assert(t, Evaluate(&api.PolicyConfiguration{}, &Input{Operation: OperationVMStart, VM: vm}) == nil)
+}
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		reasons []string
+	}{
+		{"undefined", `{}`, nil},
+		{"empty set", `{"result": [{"expressions": [{"value": [], "text": "data.ignite.deny"}]}]}`, nil},
+		{"false", `{"result": [{"expressions": [{"value": false}]}]}`, nil},
+		{"true", `{"result": [{"expressions": [{"value": true}]}]}`, []string{"denied by the rego policy"}},
+		{
+			"set of reasons",
+			`{"result": [{"expressions": [{"value": ["no root VMs", {"code": 3}]}]}]}`,
+			[]string{"no root VMs", `{"code":3}`},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			reasons, err := parseResult([]byte(rt.out))
+			assert.NilError(t, err)
+			assert.DeepEqual(t, reasons, rt.reasons)
+		})
+	}
+}
+
+func TestCheckRego(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The fake opa denies the VMs, and checks it's given the policy and the input
+	opa := filepath.Join(dir, "opa")
+	script := `#!/bin/sh
+[ "$*" = "eval --format json --stdin-input --data /etc/ignite/policy.rego data.ignite.deny" ] || exit 1
+if grep -q '"operation":"vm.start"'; then
+	echo '{"result": [{"expressions": [{"value": ["VMs may not be started"]}]}]}'
+else
+	echo '{}'
+fi
+`
+	assert.NilError(t, ioutil.WriteFile(opa, []byte(script), 0755))
+
+	defer func(c *api.Configuration) { providers.ComponentConfig = c }(providers.ComponentConfig)
+	providers.ComponentConfig = &api.Configuration{}
+	providers.ComponentConfig.Spec.Policy.Rego = &api.RegoPolicy{Path: "/etc/ignite/policy.rego", OPA: opa}
+
+	vm := newVM(t, "weaveworks/ignite-ubuntu", 1, "512MB")
+	assert.NilError(t, CheckVM(context.Background(), OperationVMCreate, vm))
+
+	err = CheckVM(context.Background(), OperationVMStart, vm)
+	assert.Error(t, err, "vm.start denied by policy: VMs may not be started")
+	_, denied := err.(*DeniedError)
+	assert.Assert(t, denied)
+
+	providers.ComponentConfig.Spec.Policy.Rego.Query = "data.other.deny"
+	assert.ErrorContains(t, CheckVM(context.Background(), OperationVMStart, vm), "failed to evaluate the policy")
+}
@@ -0,0 +1,93 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+const (
+	opa          = "opa"
+	defaultQuery = "data.ignite.deny"
+)
+
+// evaluateRego evaluates the Rego policy with opa, and returns the reasons it denies the operation for
+func evaluateRego(ctx context.Context, policy *api.RegoPolicy, input *Input) ([]string, error) {
+	in, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	binary, query := policy.OPA, policy.Query
+	if len(binary) == 0 {
+		binary = opa
+	}
+	if len(query) == 0 {
+		query = defaultQuery
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, "eval", "--format", "json", "--stdin-input", "--data", policy.Path, query)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("command %q exited with %q: %v", cmd.Args, bytes.TrimSpace(stderr.Bytes()), err)
+	}
+
+	return parseResult(stdout.Bytes())
+}
+
+// evalResult is the output of "opa eval --format json"
+type evalResult struct {
+	Result []struct {
+		Expressions []struct {
+			Value interface{} `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// parseResult returns the reasons in the value of the query. The value is a set of
+// reasons, like the deny rules usually are, or a single reason. Undefined, false and
+// empty values allow the operation.
+func parseResult(out []byte) (reasons []string, err error) {
+	var r evalResult
+	if err := json.Unmarshal(out, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse the opa output: %v", err)
+	}
+
+	for _, result := range r.Result {
+		for _, expr := range result.Expressions {
+			reasons = append(reasons, valueReasons(expr.Value)...)
+		}
+	}
+
+	return
+}
+
+func valueReasons(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case bool:
+		if v {
+			return []string{"denied by the rego policy"}
+		}
+		return nil
+	case string:
+		return []string{v}
+	case []interface{}:
+		var reasons []string
+		for _, item := range v {
+			reasons = append(reasons, valueReasons(item)...)
+		}
+		return reasons
+	}
+
+	b, _ := json.Marshal(value)
+	return []string{string(b)}
+}
@@ -3,16 +3,21 @@ package checkers
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"os/exec"
+	"path"
+	"strconv"
 	"strings"
 
 	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/emulation"
 	"github.com/weaveworks/ignite/pkg/network"
 	"github.com/weaveworks/ignite/pkg/preflight"
 	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -95,9 +100,70 @@ func (bipc BinInPathChecker) Type() string {
 	return "BinaryInPath"
 }
 
+// hugepagesDir is where the kernel reports the hugepages of every size
+const hugepagesDir = "/sys/kernel/mm/hugepages"
+
+// HugepagesChecker checks that enough free hugepages of the given size are reserved on the
+// host, see https://www.kernel.org/doc/html/latest/admin-guide/mm/hugetlbpage.html
+type HugepagesChecker struct {
+	size  api.HugepageSize
+	pages uint64
+	dir   string
+}
+
+func NewHugepagesChecker(size api.HugepageSize, memory uint64) HugepagesChecker {
+	hc := HugepagesChecker{
+		size: size,
+		dir:  hugepagesDir,
+	}
+
+	if size.Bytes() > 0 {
+		hc.pages = (memory + size.Bytes() - 1) / size.Bytes()
+	}
+
+	return hc
+}
+
+func (hc HugepagesChecker) Check() error {
+	freeFile := path.Join(hc.dir, fmt.Sprintf("hugepages-%dkB", hc.size.Bytes()>>10), "free_hugepages")
+	b, err := ioutil.ReadFile(freeFile)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("The host doesn't support %s hugepages", hc.size)
+	} else if err != nil {
+		return err
+	}
+
+	free, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Failed to parse %s: %v", freeFile, err)
+	}
+
+	if free < hc.pages {
+		return fmt.Errorf("%d free %s hugepages are needed, but only %d are available. Reserve more with e.g. \"sysctl vm.nr_hugepages\" for 2Mi hugepages", hc.pages, hc.size, free)
+	}
+	return nil
+}
+
+func (hc HugepagesChecker) Name() string {
+	return fmt.Sprintf("Hugepages-%s", hc.size)
+}
+
+func (hc HugepagesChecker) Type() string {
+	return "Hugepages"
+}
+
 func StartCmdChecks(vm *api.VM, ignoredPreflightErrors sets.String) error {
+	// Emulated VMs run without KVM
+	emulate, err := emulation.Enabled(providers.Emulation.Mode)
+	if err != nil {
+		return err
+	}
+
 	checks := []preflight.Checker{}
 	for _, dependency := range constants.PathDependencies {
+		if emulate && dependency == emulation.KVMDevice {
+			continue
+		}
 		checks = append(checks, ExistingFileChecker{filePath: dependency})
 	}
 	if providers.NetworkPluginName == network.PluginCNI {
@@ -125,6 +191,34 @@ func StartCmdChecks(vm *api.VM, ignoredPreflightErrors sets.String) error {
 	for _, dependency := range constants.BinaryDependencies {
 		checks = append(checks, BinInPathChecker{binaryNames: []string{dependency}})
 	}
+	// Check the binaries of the VM's snapshotter
+	var snapshotterDependencies []string
+	switch vm.Status.Snapshotter {
+	case snapshotter.SnapshotterQcow2:
+		snapshotterDependencies = constants.Qcow2Dependencies[:]
+	case snapshotter.SnapshotterLVM:
+		snapshotterDependencies = constants.LVMDependencies[:]
+	case snapshotter.SnapshotterZFS:
+		snapshotterDependencies = constants.ZFSDependencies[:]
+	case snapshotter.SnapshotterRBD:
+		snapshotterDependencies = constants.RBDDependencies[:]
+	}
+	for _, dependency := range snapshotterDependencies {
+		checks = append(checks, BinInPathChecker{binaryNames: []string{dependency}})
+	}
+	// Check that the hugepages backing the VM's memory are available
+	if len(vm.Spec.MemoryHugepages) > 0 {
+		checks = append(checks, NewHugepagesChecker(vm.Spec.MemoryHugepages, vm.Spec.Memory.Bytes()))
+	}
+	// Check the NBD client if the VM has remote disks
+	for _, volume := range vm.Spec.Storage.Volumes {
+		if volume.NBD != nil {
+			for _, dependency := range constants.NBDDependencies {
+				checks = append(checks, BinInPathChecker{binaryNames: []string{dependency}})
+			}
+			break
+		}
+	}
 	return runChecks(checks, ignoredPreflightErrors)
 }
 
@@ -2,11 +2,15 @@ package checkers
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
 	"github.com/weaveworks/ignite/pkg/preflight"
 )
 
@@ -116,3 +120,30 @@ func TestIsIgnoredPreflightError(t *testing.T) {
 		})
 	}
 }
+
+func TestHugepagesChecker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-hugepages-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pagesDir := path.Join(dir, "hugepages-2048kB")
+	assert.NoError(t, os.Mkdir(pagesDir, 0755))
+	assert.NoError(t, ioutil.WriteFile(path.Join(pagesDir, "free_hugepages"), []byte("256\n"), 0644))
+
+	utests := []struct {
+		size          api.HugepageSize
+		memory        uint64
+		expectedError bool
+	}{
+		{size: api.HugepageSize2Mi, memory: 512 << 20, expectedError: false},
+		{size: api.HugepageSize2Mi, memory: 1 << 30, expectedError: true},
+		{size: api.HugepageSize1Gi, memory: 1 << 30, expectedError: true},
+	}
+	for _, utest := range utests {
+		hc := NewHugepagesChecker(utest.size, utest.memory)
+		hc.dir = dir
+		assert.Equal(t, utest.expectedError, hc.Check() != nil)
+	}
+}
@@ -5,19 +5,35 @@ import (
 	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
 	"github.com/weaveworks/ignite/pkg/constants"
 	"github.com/weaveworks/ignite/pkg/providers"
+	storageprovider "github.com/weaveworks/ignite/pkg/providers/storage"
+	"github.com/weaveworks/libgitops/pkg/storage"
 	"github.com/weaveworks/libgitops/pkg/storage/cache"
 	"github.com/weaveworks/libgitops/pkg/storage/manifest"
+	"github.com/weaveworks/libgitops/pkg/storage/sync"
+	"github.com/weaveworks/libgitops/pkg/storage/watch"
 )
 
 var ManifestStorage *manifest.ManifestStorage
 
 func SetManifestStorage() (err error) {
 	log.Trace("Initializing the ManifestStorage provider...")
-	ManifestStorage, err = manifest.NewTwoWayManifestStorage(constants.MANIFEST_DIR, constants.DATA_DIR, scheme.Serializer)
+	// Like manifest.NewTwoWayManifestStorage, with the metadata in the enabled storage backend
+	ws, err := watch.NewGenericWatchStorage(storage.NewGenericStorage(storage.NewGenericMappedRawStorage(constants.MANIFEST_DIR), scheme.Serializer))
 	if err != nil {
 		return
 	}
 
-	providers.Storage = cache.NewCache(ManifestStorage)
+	raw, err := storageprovider.NewRawStorage(constants.DATA_DIR)
+	if err != nil {
+		return
+	}
+
+	ManifestStorage = &manifest.ManifestStorage{
+		Storage: sync.NewSyncStorage(
+			storage.NewGenericStorage(raw, scheme.Serializer),
+			ws),
+	}
+
+	providers.Storage = storageprovider.NewLockedStorage(cache.NewCache(ManifestStorage))
 	return
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/weaveworks/ignite/pkg/client"
 	"github.com/weaveworks/ignite/pkg/network"
 	"github.com/weaveworks/ignite/pkg/runtime"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
 	"github.com/weaveworks/libgitops/pkg/storage"
 )
 
@@ -29,6 +30,10 @@ var RuntimeName runtime.Name
 // This should be set after parsing user input on what runtime to use
 var Runtime runtime.Interface
 
+// SnapshotterName binds to the global flag to select the storage backend for new VMs
+// The default snapshotter is "dmlegacy"
+var SnapshotterName snapshotter.Name
+
 // Client is the default client that can be easily used
 var Client *client.Client
 
@@ -42,6 +47,26 @@ var ComponentConfig *api.Configuration
 // configurations.
 var RegistryConfigDir string
 
+// ImageScan configures the vulnerability scan of the images as they're imported. It's
+// set from the ignite configuration, and can be overridden with flags.
+var ImageScan api.ImageScanConfiguration
+
+// SBOM configures the software bill of materials generated for the images as they're
+// imported. It's set from the ignite configuration, and can be overridden with flags.
+var SBOM api.SBOMConfiguration
+
+// ImageImport configures how the filesystems of the images are built as they're imported.
+// It's set from the ignite configuration, and can be overridden with flags.
+var ImageImport api.ImageImportConfiguration
+
+// BootCache configures whether started VMs are restored from and snapshotted into the
+// boot cache. It's set from the ignite configuration, and can be enabled with a flag.
+var BootCache api.BootCacheConfiguration
+
+// Emulation configures whether started VMs are emulated with QEMU instead of run with
+// Firecracker. It's set from the ignite configuration, and can be overridden with a flag.
+var Emulation api.EmulationConfiguration
+
 type ProviderInitFunc func() error
 
 // Populate initializes all given providers
@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/weaveworks/ignite/pkg/flock"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/serializer"
+	"github.com/weaveworks/libgitops/pkg/storage"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// lockedStorage serializes the calls to the wrapped storage, so VM operations
+// can run in parallel. The cache of the storage isn't safe for concurrent use.
+// The objects of a kind are also locked between the ignite processes, shared
+// by the reads and exclusively by the writes, so concurrent ignite and ignited
+// invocations don't read partially written objects or overwrite each other.
+type lockedStorage struct {
+	mu      sync.Mutex
+	storage storage.Storage
+}
+
+var _ storage.Storage = &lockedStorage{}
+
+// NewLockedStorage returns a storage that can be used by multiple goroutines and
+// processes at once, calling the given storage one at a time
+func NewLockedStorage(s storage.Storage) storage.Storage {
+	return &lockedStorage{storage: s}
+}
+
+// lock locks the storage, and the objects of the kind for the other processes
+func (s *lockedStorage) lock(gvk schema.GroupVersionKind, write bool) (func(), error) {
+	s.mu.Lock()
+
+	lock := flock.RLock
+	if write {
+		lock = flock.Lock
+	}
+
+	unlock, err := lock("storage-" + strings.ToLower(gvk.Kind))
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	return func() {
+		unlock()
+		s.mu.Unlock()
+	}, nil
+}
+
+func (s *lockedStorage) New(gvk schema.GroupVersionKind) (runtime.Object, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.storage.New(gvk)
+}
+
+func (s *lockedStorage) Get(gvk schema.GroupVersionKind, uid runtime.UID) (runtime.Object, error) {
+	unlock, err := s.lock(gvk, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.storage.Get(gvk, uid)
+}
+
+func (s *lockedStorage) GetMeta(gvk schema.GroupVersionKind, uid runtime.UID) (runtime.Object, error) {
+	unlock, err := s.lock(gvk, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.storage.GetMeta(gvk, uid)
+}
+
+// Set stores the object. A new object can't take the name of another object of its kind,
+// which another process may have created since the name was checked.
+func (s *lockedStorage) Set(gvk schema.GroupVersionKind, obj runtime.Object) error {
+	unlock, err := s.lock(gvk, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := s.verifyName(gvk, obj); err != nil {
+		return err
+	}
+	return s.storage.Set(gvk, obj)
+}
+
+func (s *lockedStorage) Patch(gvk schema.GroupVersionKind, uid runtime.UID, patch []byte) error {
+	unlock, err := s.lock(gvk, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.storage.Patch(gvk, uid, patch)
+}
+
+func (s *lockedStorage) Delete(gvk schema.GroupVersionKind, uid runtime.UID) error {
+	unlock, err := s.lock(gvk, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.storage.Delete(gvk, uid)
+}
+
+func (s *lockedStorage) List(gvk schema.GroupVersionKind) ([]runtime.Object, error) {
+	unlock, err := s.lock(gvk, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.storage.List(gvk)
+}
+
+func (s *lockedStorage) ListMeta(gvk schema.GroupVersionKind) ([]runtime.Object, error) {
+	unlock, err := s.lock(gvk, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.storage.ListMeta(gvk)
+}
+
+func (s *lockedStorage) Count(gvk schema.GroupVersionKind) (uint64, error) {
+	unlock, err := s.lock(gvk, false)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	return s.storage.Count(gvk)
+}
+
+func (s *lockedStorage) Checksum(gvk schema.GroupVersionKind, uid runtime.UID) (string, error) {
+	unlock, err := s.lock(gvk, false)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	return s.storage.Checksum(gvk, uid)
+}
+
+func (s *lockedStorage) RawStorage() storage.RawStorage {
+	return s.storage.RawStorage()
+}
+
+func (s *lockedStorage) Serializer() serializer.Serializer {
+	return s.storage.Serializer()
+}
+
+func (s *lockedStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.storage.Close()
+}
+
+// verifyName returns an error if the object is new, and another object of its kind has its name
+func (s *lockedStorage) verifyName(gvk schema.GroupVersionKind, obj runtime.Object) error {
+	if len(obj.GetName()) == 0 {
+		return nil
+	}
+
+	if _, err := s.storage.GetMeta(gvk, obj.GetUID()); err == nil {
+		return nil
+	}
+
+	objs, err := s.storage.ListMeta(gvk)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range objs {
+		if o.GetName() == obj.GetName() && o.GetUID() != obj.GetUID() {
+			return fmt.Errorf("invalid %s name %q: already taken by %s %q", gvk.Kind, obj.GetName(), gvk.Kind, o.GetUID())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/flock"
+)
+
+// fakeStorage keeps the objects in memory, only the methods used by Set are implemented
+type fakeStorage struct {
+	storage.Storage
+	objs []runtime.Object
+}
+
+func (s *fakeStorage) GetMeta(_ schema.GroupVersionKind, uid runtime.UID) (runtime.Object, error) {
+	for _, obj := range s.objs {
+		if obj.GetUID() == uid {
+			return obj, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s not found", uid)
+}
+
+func (s *fakeStorage) ListMeta(_ schema.GroupVersionKind) ([]runtime.Object, error) {
+	return s.objs, nil
+}
+
+func (s *fakeStorage) Set(_ schema.GroupVersionKind, obj runtime.Object) error {
+	for i, o := range s.objs {
+		if o.GetUID() == obj.GetUID() {
+			s.objs[i] = obj
+			return nil
+		}
+	}
+
+	s.objs = append(s.objs, obj)
+	return nil
+}
+
+func newVM(name string, uid runtime.UID) *api.VM {
+	vm := &api.VM{}
+	vm.SetName(name)
+	vm.SetUID(uid)
+	return vm
+}
+
+func TestLockedStorageSet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-storage-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	flock.Dir = dir
+
+	gvk := api.SchemeGroupVersion.WithKind(api.KindVM.Title())
+	s := NewLockedStorage(&fakeStorage{})
+	assert.NilError(t, s.Set(gvk, newVM("my-vm", "1699b6ba255cde7f")))
+
+	// An existing object can be updated, a new one can't take its name
+	assert.NilError(t, s.Set(gvk, newVM("my-vm", "1699b6ba255cde7f")))
+	assert.ErrorContains(t, s.Set(gvk, newVM("my-vm", "ad5b7e8d1ef2d5a4")), `name "my-vm": already taken`)
+	assert.NilError(t, s.Set(gvk, newVM("other-vm", "ad5b7e8d1ef2d5a4")))
+
+	// The writes of the kind are locked for the other processes
+	_, err = os.Stat(path.Join(dir, "storage-vm.lock"))
+	assert.NilError(t, err)
+}
@@ -3,16 +3,33 @@ package storage
 import (
 	log "github.com/sirupsen/logrus"
 	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	"github.com/weaveworks/ignite/pkg/boltstorage"
 	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/etcdstorage"
 	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/storagebackend"
 	"github.com/weaveworks/libgitops/pkg/storage"
 	"github.com/weaveworks/libgitops/pkg/storage/cache"
 )
 
+// Backends are the backends the metadata can be stored in besides the metadata files,
+// the one enabled for the data directory is used
+var Backends = []storagebackend.Backend{boltstorage.Backend, &etcdstorage.Backend{}}
+
+// NewRawStorage returns the RawStorage of the metadata of the data directory dir, in the
+// enabled backend
+func NewRawStorage(dir string) (storage.RawStorage, error) {
+	return storagebackend.For(Backends, dir).NewRawStorage(dir)
+}
+
 func SetGenericStorage() error {
 	log.Trace("Initializing the GenericStorage provider...")
-	providers.Storage = cache.NewCache(
-		storage.NewGenericStorage(
-			storage.NewGenericRawStorage(constants.DATA_DIR), scheme.Serializer))
+	raw, err := NewRawStorage(constants.DATA_DIR)
+	if err != nil {
+		return err
+	}
+
+	providers.Storage = NewLockedStorage(cache.NewCache(
+		storage.NewGenericStorage(raw, scheme.Serializer)))
 	return nil
 }
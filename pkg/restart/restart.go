@@ -0,0 +1,164 @@
+// Package restart enforces the restart policies of the VMs. When the Firecracker process
+// of a VM exits without ignite stopping the VM, the VM is started again if its policy
+// asks for it. VMs that keep exiting are restarted with an exponentially growing delay.
+package restart
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/config"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/operations"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/libgitops/pkg/filter"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// state tracks the restarts of a VM
+type state struct {
+	// handled is the time of the last exit the VM was restarted after
+	handled time.Time
+	// started is when the VM was last restarted
+	started time.Time
+	// retryAt is when the VM is restarted next, zero if the current exit isn't scheduled yet
+	retryAt time.Time
+	// delay is the backoff applied to the next exit
+	delay time.Duration
+}
+
+// Restarter restarts the VMs that exited according to their restart policy
+type Restarter struct {
+	states map[runtime.UID]*state
+	start  func(vm *api.VM) error
+}
+
+// NewRestarter creates a new Restarter
+func NewRestarter() *Restarter {
+	return &Restarter{
+		states: make(map[runtime.UID]*state),
+		start:  start,
+	}
+}
+
+// Run checks for VMs to restart every VM_RESTART_CHECK_INTERVAL. It never returns.
+func (r *Restarter) Run() {
+	for {
+		time.Sleep(constants.VM_RESTART_CHECK_INTERVAL)
+
+		vms, err := providers.Client.VMs().FindAll(filter.NewAllFilter())
+		if err != nil {
+			log.Errorf("Failed to list VMs for restarting: %v", err)
+			continue
+		}
+
+		r.check(vms, time.Now())
+	}
+}
+
+// check restarts the VMs that are due to be restarted at the given time
+func (r *Restarter) check(vms []*api.VM, now time.Time) {
+	for _, vm := range vms {
+		if !ShouldRestart(vm) {
+			continue
+		}
+
+		s, ok := r.states[vm.GetUID()]
+		if !ok {
+			s = &state{delay: constants.VM_RESTART_BACKOFF_MIN}
+			r.states[vm.GetUID()] = s
+		}
+
+		exited := vm.Status.LastExit.Time.Time.Time
+		if !exited.After(s.handled) {
+			continue
+		}
+
+		if s.retryAt.IsZero() {
+			// A VM that stayed up longer than the maximum backoff starts over with the minimum
+			if !s.started.IsZero() && exited.Sub(s.started) > constants.VM_RESTART_BACKOFF_MAX {
+				s.delay = constants.VM_RESTART_BACKOFF_MIN
+			}
+
+			s.retryAt = exited.Add(s.delay)
+		}
+
+		if now.Before(s.retryAt) {
+			continue
+		}
+
+		vm.Status.RestartCount++
+		log.Infof("Restarting VM %q with name %q, it exited with code %d (restart %d)...",
+			vm.GetUID(), vm.GetName(), vm.Status.LastExit.ExitCode, vm.Status.RestartCount)
+
+		err := r.start(vm)
+		s.delay = nextDelay(s.delay)
+		if err != nil {
+			log.Errorf("Failed to restart VM %q: %v", vm.GetUID(), err)
+			s.retryAt = now.Add(s.delay)
+			continue
+		}
+
+		s.handled = exited
+		s.started = now
+		s.retryAt = time.Time{}
+	}
+
+	// Forget the VMs that were removed
+	for uid := range r.states {
+		if !containsVM(vms, uid) {
+			delete(r.states, uid)
+		}
+	}
+}
+
+// ShouldRestart returns whether the restart policy of the VM asks for it to be
+// restarted after its last exit. Stops requested through ignite never do.
+func ShouldRestart(vm *api.VM) bool {
+	if vm.Running() || vm.Status.LastExit == nil || vm.Status.LastExit.Requested {
+		return false
+	}
+
+	switch vm.Spec.RestartPolicy {
+	case api.RestartPolicyAlways:
+		return true
+	case api.RestartPolicyOnFailure:
+		return vm.Status.LastExit.ExitCode != 0
+	}
+
+	return false
+}
+
+// nextDelay doubles the delay, up to VM_RESTART_BACKOFF_MAX
+func nextDelay(delay time.Duration) time.Duration {
+	if delay *= 2; delay > constants.VM_RESTART_BACKOFF_MAX {
+		return constants.VM_RESTART_BACKOFF_MAX
+	}
+
+	return delay
+}
+
+func containsVM(vms []*api.VM, uid runtime.UID) bool {
+	for _, vm := range vms {
+		if vm.GetUID() == uid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// start starts the stopped VM, StartVM persists its restart count
+func start(vm *api.VM) error {
+	// Use the runtime and network plugin of the VM's spec, otherwise the ones it ran with
+	config.ResolveVMProviders(vm)
+
+	if err := config.SetAndPopulateProviders(vm.Status.Runtime.Name, vm.Status.Network.Plugin); err != nil {
+		return err
+	}
+
+	return operations.StartVM(context.Background(), vm, false)
+}
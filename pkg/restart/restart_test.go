@@ -0,0 +1,104 @@
+package restart
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+func newVM(policy api.RestartPolicy, exit *api.VMExitStatus) *api.VM {
+	vm := &api.VM{}
+	vm.SetName("test")
+	vm.SetUID("test-uid")
+	vm.Spec.RestartPolicy = policy
+	vm.Status.LastExit = exit
+	return vm
+}
+
+func exitAt(t time.Time, code int, requested bool) *api.VMExitStatus {
+	return &api.VMExitStatus{Time: runtime.Time{Time: metav1.Time{Time: t}}, ExitCode: code, Requested: requested}
+}
+
+func TestShouldRestart(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name string
+		vm   *api.VM
+		want bool
+	}{
+		{"no policy", newVM("", exitAt(now, 1, false)), false},
+		{"never", newVM(api.RestartPolicyNever, exitAt(now, 1, false)), false},
+		{"never exited", newVM(api.RestartPolicyAlways, nil), false},
+		{"always", newVM(api.RestartPolicyAlways, exitAt(now, 0, false)), true},
+		{"always but requested", newVM(api.RestartPolicyAlways, exitAt(now, 0, true)), false},
+		{"on-failure with success", newVM(api.RestartPolicyOnFailure, exitAt(now, 0, false)), false},
+		{"on-failure with failure", newVM(api.RestartPolicyOnFailure, exitAt(now, 137, false)), true},
+	}
+
+	for _, rt := range cases {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.Equal(t, ShouldRestart(rt.vm), rt.want)
+		})
+	}
+}
+
+func TestCheckBackoff(t *testing.T) {
+	var starts int
+	var failStart bool
+	r := NewRestarter()
+	r.start = func(vm *api.VM) error {
+		starts++
+		if failStart {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	}
+
+	exited := time.Now()
+	vm := newVM(api.RestartPolicyAlways, exitAt(exited, 0, false))
+
+	// The first restart waits for the minimum backoff
+	r.check([]*api.VM{vm}, exited)
+	assert.Equal(t, starts, 0)
+	r.check([]*api.VM{vm}, exited.Add(constants.VM_RESTART_BACKOFF_MIN))
+	assert.Equal(t, starts, 1)
+	assert.Equal(t, vm.Status.RestartCount, uint64(1))
+
+	// The same exit isn't handled twice
+	r.check([]*api.VM{vm}, exited.Add(time.Minute))
+	assert.Equal(t, starts, 1)
+
+	// The VM exits again right away, the delay has doubled
+	exited = exited.Add(2 * time.Second)
+	vm.Status.LastExit = exitAt(exited, 0, false)
+	r.check([]*api.VM{vm}, exited.Add(constants.VM_RESTART_BACKOFF_MIN))
+	assert.Equal(t, starts, 1)
+	r.check([]*api.VM{vm}, exited.Add(2*constants.VM_RESTART_BACKOFF_MIN))
+	assert.Equal(t, starts, 2)
+
+	// A failed restart is retried after the backoff
+	exited = exited.Add(3 * time.Second)
+	vm.Status.LastExit = exitAt(exited, 0, false)
+	failStart = true
+	now := exited.Add(4 * constants.VM_RESTART_BACKOFF_MIN)
+	r.check([]*api.VM{vm}, now)
+	assert.Equal(t, starts, 3)
+	failStart = false
+	r.check([]*api.VM{vm}, now.Add(4*constants.VM_RESTART_BACKOFF_MIN))
+	assert.Equal(t, starts, 3)
+	r.check([]*api.VM{vm}, now.Add(8*constants.VM_RESTART_BACKOFF_MIN))
+	assert.Equal(t, starts, 4)
+
+	// A VM that stayed up long enough is restarted after the minimum backoff again
+	exited = now.Add(time.Hour)
+	vm.Status.LastExit = exitAt(exited, 0, false)
+	r.check([]*api.VM{vm}, exited.Add(constants.VM_RESTART_BACKOFF_MIN))
+	assert.Equal(t, starts, 5)
+}
@@ -0,0 +1,204 @@
+// Package result defines the machine-readable results printed by the create, start, run
+// and import commands with the JSON output format (--output json), for the tools wrapping
+// ignite, like Packer builders and Terraform provisioners. The results are stable: fields
+// are only added to a version, so results can be decoded with Decode, or with any JSON
+// decoder, by tools built against older versions of this package.
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// APIVersion is the version of the results. It only changes when a field is removed or
+// changes meaning.
+const APIVersion = "result.ignite.weave.works/v1"
+
+// The kinds of results
+const (
+	KindVM     = "VM"
+	KindImage  = "Image"
+	KindKernel = "Kernel"
+)
+
+// sshUser is the user the SSH key generated for a VM is authorized for
+const sshUser = "root"
+
+// TypeMeta identifies the kind of a result
+type TypeMeta struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+// Source is the OCI image an image, a kernel or the disk of a VM comes from
+type Source struct {
+	// Ref is the OCI image reference, e.g. weaveworks/ignite-ubuntu:latest
+	Ref string `json:"ref"`
+	// Digest is the digest of the OCI image, or the ID of a local image of the container runtime
+	Digest string `json:"digest,omitempty"`
+	// RepoDigest is the reference of the OCI image by digest, empty for local images
+	RepoDigest string `json:"repoDigest,omitempty"`
+}
+
+// SSHEndpoint is where to log in to a VM over SSH
+type SSHEndpoint struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	User string `json:"user"`
+	// IdentityFile is the private key generated for the VM, empty if the VM uses a given public key
+	IdentityFile string `json:"identityFile,omitempty"`
+}
+
+// VM is the result of creating or starting a VM
+type VM struct {
+	TypeMeta
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Running bool              `json:"running"`
+	Image   Source            `json:"image"`
+	Kernel  Source            `json:"kernel"`
+	// IPAddresses are the IP addresses of the running VM
+	IPAddresses []string `json:"ipAddresses"`
+	// SSH is set for running VMs with an SSH key
+	SSH *SSHEndpoint `json:"ssh,omitempty"`
+}
+
+// Image is the result of importing an image
+type Image struct {
+	TypeMeta
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Source       Source `json:"source"`
+	Size         uint64 `json:"size"`
+	Architecture string `json:"architecture,omitempty"`
+}
+
+// Kernel is the result of importing a kernel
+type Kernel struct {
+	TypeMeta
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Source  Source `json:"source"`
+	Size    uint64 `json:"size"`
+	Version string `json:"version,omitempty"`
+}
+
+// NewVM returns the result for the VM
+func NewVM(vm *api.VM) *VM {
+	r := &VM{
+		TypeMeta:    TypeMeta{APIVersion: APIVersion, Kind: KindVM},
+		ID:          vm.GetUID().String(),
+		Name:        vm.GetName(),
+		Labels:      vm.GetObjectMeta().Labels,
+		Running:     vm.Running(),
+		Image:       newSource(vm.Spec.Image.OCI, vm.Status.Image),
+		Kernel:      newSource(vm.Spec.Kernel.OCI, vm.Status.Kernel),
+		IPAddresses: []string{},
+	}
+
+	if !r.Running || vm.Status.Network == nil {
+		return r
+	}
+
+	for _, ip := range vm.Status.Network.IPAddresses {
+		r.IPAddresses = append(r.IPAddresses, ip.String())
+	}
+
+	if vm.Spec.SSH != nil && len(r.IPAddresses) > 0 {
+		r.SSH = &SSHEndpoint{Host: r.IPAddresses[0], Port: 22, User: sshUser}
+		if keyFile := path.Join(vm.ObjectPath(), fmt.Sprintf(constants.VM_SSH_KEY_TEMPLATE, vm.GetUID())); util.FileExists(keyFile) {
+			r.SSH.IdentityFile = keyFile
+		}
+	}
+
+	return r
+}
+
+// NewImage returns the result for the image
+func NewImage(image *api.Image) *Image {
+	return &Image{
+		TypeMeta:     TypeMeta{APIVersion: APIVersion, Kind: KindImage},
+		ID:           image.GetUID().String(),
+		Name:         image.GetName(),
+		Source:       newSource(image.Spec.OCI, image.Status.OCISource),
+		Size:         image.Status.OCISource.Size.Bytes(),
+		Architecture: image.Status.OCISource.Architecture,
+	}
+}
+
+// NewKernel returns the result for the kernel
+func NewKernel(kernel *api.Kernel) *Kernel {
+	return &Kernel{
+		TypeMeta: TypeMeta{APIVersion: APIVersion, Kind: KindKernel},
+		ID:       kernel.GetUID().String(),
+		Name:     kernel.GetName(),
+		Source:   newSource(kernel.Spec.OCI, kernel.Status.OCISource),
+		Size:     kernel.Status.OCISource.Size.Bytes(),
+		Version:  kernel.Status.Version,
+	}
+}
+
+func newSource(ref meta.OCIImageRef, source api.OCIImageSource) Source {
+	var s Source
+	if !ref.IsUnset() {
+		s.Ref = ref.String()
+	}
+
+	if source.ID != nil {
+		s.Digest = source.ID.Digest().String()
+		if repoDigest := source.ID.RepoDigest(); repoDigest != nil {
+			s.RepoDigest = repoDigest.String()
+		}
+	}
+
+	return s
+}
+
+// Print writes the result as indented JSON
+func Print(w io.Writer, result interface{}) error {
+	b, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// Decode decodes a result into a *VM, *Image or *Kernel based on its kind
+func Decode(b []byte) (interface{}, error) {
+	var tm TypeMeta
+	if err := json.Unmarshal(b, &tm); err != nil {
+		return nil, err
+	}
+
+	if tm.APIVersion != APIVersion {
+		return nil, fmt.Errorf("unsupported result version %q, expected %q", tm.APIVersion, APIVersion)
+	}
+
+	var r interface{}
+	switch tm.Kind {
+	case KindVM:
+		r = &VM{}
+	case KindImage:
+		r = &Image{}
+	case KindKernel:
+		r = &Kernel{}
+	default:
+		return nil, fmt.Errorf("unsupported result kind %q", tm.Kind)
+	}
+
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
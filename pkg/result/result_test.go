@@ -0,0 +1,72 @@
+package result
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+const testDigest = "sha256:3285f65b2651c68b5316e7a1fbabd30b5ae47914ac5791ac4bb9d59d029b924b"
+
+func TestNewVM(t *testing.T) {
+	imageRef, err := meta.NewOCIImageRef("weaveworks/ignite-ubuntu:latest")
+	assert.NilError(t, err)
+	imageID, err := meta.ParseOCIContentID("weaveworks/ignite-ubuntu@" + testDigest)
+	assert.NilError(t, err)
+	kernelID, err := meta.ParseOCIContentID(testDigest)
+	assert.NilError(t, err)
+
+	vm := &api.VM{}
+	vm.SetName("my-vm")
+	vm.SetUID("599ed7c5b3b2f6f2")
+	vm.Spec.Image.OCI = imageRef
+	vm.Spec.SSH = &api.SSH{Generate: true}
+	vm.Status.Image.ID = imageID
+	vm.Status.Kernel.ID = kernelID
+
+	// A stopped VM has no IP addresses and SSH endpoint
+	assert.DeepEqual(t, NewVM(vm), &VM{
+		TypeMeta:    TypeMeta{APIVersion: APIVersion, Kind: KindVM},
+		ID:          "599ed7c5b3b2f6f2",
+		Name:        "my-vm",
+		Image:       Source{Ref: "weaveworks/ignite-ubuntu:latest", Digest: testDigest, RepoDigest: "docker.io/weaveworks/ignite-ubuntu@" + testDigest},
+		Kernel:      Source{Digest: testDigest},
+		IPAddresses: []string{},
+	})
+
+	vm.Status.Running = true
+	vm.Status.Network = &api.Network{IPAddresses: meta.IPAddresses{net.ParseIP("10.61.0.2")}}
+	r := NewVM(vm)
+	assert.DeepEqual(t, r.IPAddresses, []string{"10.61.0.2"})
+	assert.DeepEqual(t, r.SSH, &SSHEndpoint{Host: "10.61.0.2", Port: 22, User: "root"})
+}
+
+func TestDecode(t *testing.T) {
+	image := &api.Image{}
+	image.SetName("weaveworks/ignite-ubuntu:latest")
+	image.SetUID("e2c3fd0e3c4f5a2b")
+	image.Status.OCISource.Size = meta.NewSizeFromBytes(1024)
+
+	var b bytes.Buffer
+	assert.NilError(t, Print(&b, NewImage(image)))
+
+	r, err := Decode(b.Bytes())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, r, &Image{
+		TypeMeta: TypeMeta{APIVersion: APIVersion, Kind: KindImage},
+		ID:       "e2c3fd0e3c4f5a2b",
+		Name:     "weaveworks/ignite-ubuntu:latest",
+		Size:     1024,
+	})
+
+	_, err = Decode([]byte(`{"apiVersion": "result.ignite.weave.works/v2", "kind": "VM"}`))
+	assert.Error(t, err, `unsupported result version "result.ignite.weave.works/v2", expected "result.ignite.weave.works/v1"`)
+
+	_, err = Decode([]byte(`{"apiVersion": "result.ignite.weave.works/v1", "kind": "Pod"}`))
+	assert.Error(t, err, `unsupported result kind "Pod"`)
+}
@@ -3,6 +3,7 @@ package containerd
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -28,6 +29,7 @@ import (
 	"github.com/containerd/containerd"
 	"github.com/containerd/containerd/cio"
 	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/defaults"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/mount"
@@ -150,9 +152,9 @@ func GetContainerdClient() (*ctdClient, error) {
 	}, nil
 }
 
-// newRemoteResolver returns a remote resolver with auth info for a given
+// NewRemoteResolver returns a remote resolver with auth info for a given
 // host name.
-func newRemoteResolver(refHostname string, configPath string) (remotes.Resolver, error) {
+func NewRemoteResolver(refHostname string, configPath string) (remotes.Resolver, error) {
 	var authzOpts []docker.AuthorizerOpt
 	regOpts := []docker.RegistryOpt{}
 	insecureAllowed := false
@@ -213,7 +215,7 @@ func (cc *ctdClient) PullImage(image meta.OCIImageRef) error {
 	refDomain := refdocker.Domain(named)
 
 	// Create a remote resolver for the domain.
-	resolver, err := newRemoteResolver(refDomain, providers.RegistryConfigDir)
+	resolver, err := NewRemoteResolver(refDomain, providers.RegistryConfigDir)
 	if err != nil {
 		return err
 	}
@@ -246,6 +248,17 @@ func (cc *ctdClient) InspectImage(image meta.OCIImageRef) (result *runtime.Image
 		return
 	}
 
+	// The image config tells the architecture the image is built for
+	var blob []byte
+	if blob, err = content.ReadBlob(cc.ctx, img.ContentStore(), config); err != nil {
+		return
+	}
+
+	var spec imagespec.Image
+	if err = json.Unmarshal(blob, &spec); err != nil {
+		return
+	}
+
 	// img.Name() -> "docker.io/weaveworks/ignite-ubuntu:latest"
 	// config.Digest.String() -> "sha256:9552fe790974f7232205bf8219934d49af38fd4b47aaeb61f539ea735e93f26e"
 	if id, err = meta.ParseOCIContentID(fmt.Sprintf("%s@%s", img.Name(), config.Digest.String())); err != nil {
@@ -253,8 +266,13 @@ func (cc *ctdClient) InspectImage(image meta.OCIImageRef) (result *runtime.Image
 	}
 
 	result = &runtime.ImageInspectResult{
-		ID:   id,
-		Size: usage.Size,
+		ID:           id,
+		Size:         usage.Size,
+		Architecture: spec.Architecture,
+		Entrypoint:   spec.Config.Entrypoint,
+		Cmd:          spec.Config.Cmd,
+		Env:          spec.Config.Env,
+		WorkingDir:   spec.Config.WorkingDir,
 	}
 
 	return
@@ -351,6 +369,49 @@ func (cc *ctdClient) ExportImage(image meta.OCIImageRef) (r io.ReadCloser, clean
 	return
 }
 
+// LoadImage imports the images in the given OCI or docker-archive tarball, named
+// after the image name annotations in the archive, and unpacks them for ExportImage
+func (cc *ctdClient) LoadImage(r io.Reader) error {
+	log.Debugf("containerd: Loading image archive")
+	imgs, err := cc.client.Import(cc.ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if len(imgs) == 0 {
+		return fmt.Errorf("the image archive doesn't contain any named images")
+	}
+
+	for _, img := range imgs {
+		if err := containerd.NewImage(cc.client, img).Unpack(cc.ctx, containerd.DefaultSnapshotter); err != nil {
+			return fmt.Errorf("failed to unpack image %q: %v", img.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (cc *ctdClient) TagImage(image, target meta.OCIImageRef) error {
+	log.Debugf("containerd: Tagging image %q as %q", image, target)
+	img, err := cc.client.ImageService().Get(cc.ctx, image.Normalized())
+	if err != nil {
+		return err
+	}
+
+	// Like "ctr images tag --force", an existing target is moved to the image
+	img.Name = target.Normalized()
+	if _, err = cc.client.ImageService().Create(cc.ctx, img); errdefs.IsAlreadyExists(err) {
+		_, err = cc.client.ImageService().Update(cc.ctx, img)
+	}
+
+	return err
+}
+
+func (cc *ctdClient) RemoveImage(image meta.OCIImageRef) error {
+	log.Debugf("containerd: Removing image %q", image)
+	return cc.client.ImageService().Delete(cc.ctx, image.Normalized())
+}
+
 func (cc *ctdClient) InspectContainer(container string) (*runtime.ContainerInspectResult, error) {
 	var cont containerd.Container
 
@@ -512,6 +573,13 @@ func (cc *ctdClient) RunContainer(image meta.OCIImageRef, config *runtime.Contai
 		withDevices(config.Devices),
 	}
 
+	if len(config.SELinuxLabel) != 0 {
+		opts = append(opts, oci.WithSelinuxLabel(config.SELinuxLabel))
+	}
+	if len(config.AppArmorProfile) != 0 {
+		opts = append(opts, oci.WithApparmorProfile(config.AppArmorProfile))
+	}
+
 	// Known limitations, containerd doesn't support the following config fields:
 	// - StopTimeout
 	// - AutoRemove
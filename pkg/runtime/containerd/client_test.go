@@ -242,7 +242,7 @@ func TestNewRemoteResolver(t *testing.T) {
 				defer os.Unsetenv(InsecureRegistriesEnvVar)
 			}
 
-			_, rrErr := newRemoteResolver(domainRef, dir)
+			_, rrErr := NewRemoteResolver(domainRef, dir)
 			if (rrErr != nil) != rt.wantErr {
 				t.Errorf("expected error %t, actual: %v", rt.wantErr, rrErr)
 			}
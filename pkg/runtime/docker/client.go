@@ -8,6 +8,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net"
+	"strings"
 	"time"
 
 	refdocker "github.com/containerd/containerd/reference/docker"
@@ -120,8 +121,16 @@ func (dc *dockerClient) InspectImage(image meta.OCIImageRef) (*runtime.ImageInsp
 	}
 
 	r := &runtime.ImageInspectResult{
-		ID:   id,
-		Size: res.Size,
+		ID:           id,
+		Size:         res.Size,
+		Architecture: res.Architecture,
+	}
+
+	if res.Config != nil {
+		r.Entrypoint = res.Config.Entrypoint
+		r.Cmd = res.Config.Cmd
+		r.Env = res.Config.Env
+		r.WorkingDir = res.Config.WorkingDir
 	}
 
 	return r, nil
@@ -143,6 +152,50 @@ func (dc *dockerClient) ExportImage(image meta.OCIImageRef) (r io.ReadCloser, cl
 	return
 }
 
+// LoadImage loads the images in the given docker-archive tarball, they're named
+// after the tags recorded in the archive
+func (dc *dockerClient) LoadImage(r io.Reader) (err error) {
+	res, err := dc.client.ImageLoad(context.Background(), r, true)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, res.Body.Close)
+
+	// The load errors are reported in the stream of progress messages
+	dec := json.NewDecoder(res.Body)
+	for {
+		var msg struct {
+			Error string `json:"error"`
+		}
+
+		if err = dec.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return
+		}
+
+		if len(msg.Error) > 0 {
+			return fmt.Errorf("failed to load image: %s", msg.Error)
+		}
+	}
+}
+
+// SaveImage returns the docker-archive tarball of the image, like "docker save". It's
+// not part of runtime.Interface, it's used to copy images of the docker daemon into
+// other runtimes.
+func (dc *dockerClient) SaveImage(image meta.OCIImageRef) (io.ReadCloser, error) {
+	return dc.client.ImageSave(context.Background(), []string{image.Normalized()})
+}
+
+func (dc *dockerClient) TagImage(image, target meta.OCIImageRef) error {
+	return dc.client.ImageTag(context.Background(), image.Normalized(), target.Normalized())
+}
+
+func (dc *dockerClient) RemoveImage(image meta.OCIImageRef) error {
+	_, err := dc.client.ImageRemove(context.Background(), image.Normalized(), types.ImageRemoveOptions{})
+	return err
+}
+
 func (dc *dockerClient) InspectContainer(container string) (*runtime.ContainerInspectResult, error) {
 	res, _, err := dc.client.ContainerInspectWithRaw(context.Background(), container, false)
 	if err != nil {
@@ -207,6 +260,7 @@ func (dc *dockerClient) RunContainer(image meta.OCIImageRef, config *runtime.Con
 		PortBindings: bindings,
 		AutoRemove:   config.AutoRemove,
 		CapAdd:       config.CapAdds,
+		SecurityOpt:  securityOpts(config),
 		Resources: container.Resources{
 			Devices: devices,
 		},
@@ -218,6 +272,26 @@ func (dc *dockerClient) RunContainer(image meta.OCIImageRef, config *runtime.Con
 	return c.ID, dc.client.ContainerStart(context.Background(), c.ID, types.ContainerStartOptions{})
 }
 
+// securityOpts returns the Docker security options applying the SELinux label and
+// AppArmor profile of the container
+func securityOpts(config *runtime.ContainerConfig) (opts []string) {
+	if len(config.SELinuxLabel) != 0 {
+		// Docker takes the user, role, type and level of the label separately
+		parts := strings.SplitN(config.SELinuxLabel, ":", 4)
+		for i, key := range []string{"user", "role", "type", "level"} {
+			if i < len(parts) {
+				opts = append(opts, fmt.Sprintf("label=%s:%s", key, parts[i]))
+			}
+		}
+	}
+
+	if len(config.AppArmorProfile) != 0 {
+		opts = append(opts, "apparmor="+config.AppArmorProfile)
+	}
+
+	return
+}
+
 func (dc *dockerClient) StopContainer(container string, timeout *time.Duration) error {
 	// Start waiting before we do the stop, to avoid race
 	errC, readyC := make(chan error), make(chan struct{})
@@ -13,6 +13,13 @@ import (
 type ImageInspectResult struct {
 	ID   *meta.OCIContentID
 	Size int64
+	// Architecture is the architecture the image is built for, e.g. amd64
+	Architecture string
+	// Entrypoint, Cmd, Env and WorkingDir are the defaults of the processes run from the image
+	Entrypoint []string
+	Cmd        []string
+	Env        []string
+	WorkingDir string
 }
 
 type ContainerInspectResult struct {
@@ -48,12 +55,19 @@ type ContainerConfig struct {
 	AutoRemove   bool
 	NetworkMode  string
 	PortBindings meta.PortMappings
+	// SELinuxLabel is the SELinux label the container processes run with, if set
+	SELinuxLabel string
+	// AppArmorProfile is the loaded AppArmor profile confining the container, if set
+	AppArmorProfile string
 }
 
 type Interface interface {
 	PullImage(image meta.OCIImageRef) error
 	InspectImage(image meta.OCIImageRef) (*ImageInspectResult, error)
 	ExportImage(image meta.OCIImageRef) (io.ReadCloser, func() error, error)
+	LoadImage(r io.Reader) error
+	TagImage(image, target meta.OCIImageRef) error
+	RemoveImage(image meta.OCIImageRef) error
 
 	InspectContainer(container string) (*ContainerInspectResult, error)
 	AttachContainer(container string) error
@@ -0,0 +1,97 @@
+package sbom
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+)
+
+// syft is the generator of the SBOMs
+const syft = "syft"
+
+// syftOutputs are the syft output formats of the SBOM formats
+var syftOutputs = map[api.SBOMFormat]string{
+	api.SBOMFormatSPDX:      "spdx-json",
+	api.SBOMFormatCycloneDX: "cyclonedx-json",
+}
+
+// ValidateFormat checks that SBOMs can be generated in the format
+func ValidateFormat(format api.SBOMFormat) error {
+	if _, ok := syftOutputs[format]; !ok {
+		return fmt.Errorf("invalid SBOM format %q, supported formats: %s, %s", format, api.SBOMFormatSPDX, api.SBOMFormatCycloneDX)
+	}
+
+	return nil
+}
+
+// FileName returns the name of the SBOM file of the format in the image directory
+func FileName(format api.SBOMFormat) string {
+	if format == api.SBOMFormatCycloneDX {
+		return constants.IMAGE_SBOM_CYCLONEDX
+	}
+
+	return constants.IMAGE_SBOM_SPDX
+}
+
+// Path returns the path of the SBOM of the image
+func Path(image *api.Image) (string, error) {
+	if image.Status.SBOM == nil {
+		return "", fmt.Errorf("image %q has no SBOM, import it with an SBOM format set to generate one", image.GetName())
+	}
+
+	return path.Join(image.ObjectPath(), FileName(image.Status.SBOM.Format)), nil
+}
+
+// Generate writes the SBOM of the root filesystem in the format to w. It's generated by
+// the syft binary at binary, or the one in $PATH if binary is empty.
+func Generate(ctx context.Context, binary string, format api.SBOMFormat, rootfs string, w io.Writer) error {
+	if err := ValidateFormat(format); err != nil {
+		return err
+	}
+
+	if len(binary) == 0 {
+		binary = syft
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args(format, rootfs)...)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("command %q exited with %q: %v", cmd.Args, bytes.TrimSpace(stderr.Bytes()), err)
+	}
+
+	return nil
+}
+
+func args(format api.SBOMFormat, rootfs string) []string {
+	return []string{"dir:" + rootfs, "--quiet", "--output", syftOutputs[format]}
+}
+
+// GenerateFile generates the SBOM of the root filesystem to the file at p. The file
+// is removed if the generation fails.
+func GenerateFile(ctx context.Context, binary string, format api.SBOMFormat, rootfs, p string) (err error) {
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err = Generate(ctx, binary, format, rootfs, f); err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+
+	if err != nil {
+		os.Remove(p)
+	}
+
+	return
+}
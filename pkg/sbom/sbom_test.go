@@ -0,0 +1,63 @@
+package sbom
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+// fakeSyft writes a script printing its arguments, or failing, in place of syft
+func fakeSyft(t *testing.T, script string) string {
+	dir, err := ioutil.TempDir("", "")
+	assert.NilError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	p := filepath.Join(dir, "syft")
+	assert.NilError(t, ioutil.WriteFile(p, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	return p
+}
+
+func TestValidateFormat(t *testing.T) {
+	assert.NilError(t, ValidateFormat(api.SBOMFormatSPDX))
+	assert.NilError(t, ValidateFormat(api.SBOMFormatCycloneDX))
+	assert.ErrorContains(t, ValidateFormat("swid"), "invalid SBOM format")
+}
+
+func TestPath(t *testing.T) {
+	image := &api.Image{}
+	image.SetUID("cae0ac317cca74ba")
+	image.SetName("weaveworks/ignite-ubuntu:latest")
+
+	_, err := Path(image)
+	assert.ErrorContains(t, err, "has no SBOM")
+
+	image.Status.SBOM = &api.ImageSBOM{Format: api.SBOMFormatCycloneDX}
+	p, err := Path(image)
+	assert.NilError(t, err)
+	assert.Equal(t, p, filepath.Join(image.ObjectPath(), "sbom.cdx.json"))
+}
+
+func TestGenerateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, FileName(api.SBOMFormatSPDX))
+
+	syft := fakeSyft(t, `echo "$@"`)
+	assert.NilError(t, GenerateFile(context.Background(), syft, api.SBOMFormatSPDX, "/tmp/rootfs", p))
+	b, err := ioutil.ReadFile(p)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "dir:/tmp/rootfs --quiet --output spdx-json\n")
+
+	syft = fakeSyft(t, `echo "no such directory" >&2; exit 1`)
+	err = GenerateFile(context.Background(), syft, api.SBOMFormatSPDX, "/tmp/rootfs", p)
+	assert.ErrorContains(t, err, "no such directory")
+	_, err = os.Stat(p)
+	assert.Assert(t, os.IsNotExist(err))
+}
@@ -0,0 +1,71 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+const grypeName = "grype"
+
+// grype scans with "grype dir:"
+type grype struct {
+	binary string
+}
+
+var _ Scanner = &grype{}
+
+func (g *grype) Name() string {
+	return grypeName
+}
+
+func (g *grype) Scan(ctx context.Context, rootfs string) ([]api.Vulnerability, error) {
+	out, err := run(ctx, g.binary, "dir:"+rootfs, "--quiet", "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseGrype(out)
+}
+
+// grypeReport is the part of the JSON report of grype the vulnerabilities are read from
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			ID          string `json:"id"`
+			Severity    string `json:"severity"`
+			Description string `json:"description"`
+			Fix         struct {
+				Versions []string `json:"versions"`
+			} `json:"fix"`
+		} `json:"vulnerability"`
+		Artifact struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"artifact"`
+	} `json:"matches"`
+}
+
+func parseGrype(report []byte) ([]api.Vulnerability, error) {
+	var r grypeReport
+	if err := json.Unmarshal(report, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse the grype report: %v", err)
+	}
+
+	vulns := make([]api.Vulnerability, 0, len(r.Matches))
+	for _, m := range r.Matches {
+		vulns = append(vulns, api.Vulnerability{
+			ID:           m.Vulnerability.ID,
+			Package:      m.Artifact.Name,
+			Version:      m.Artifact.Version,
+			FixedVersion: strings.Join(m.Vulnerability.Fix.Versions, ", "),
+			Severity:     ParseSeverity(m.Vulnerability.Severity),
+			Title:        m.Vulnerability.Description,
+		})
+	}
+
+	return vulns, nil
+}
@@ -0,0 +1,164 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// Scanner scans a root filesystem for vulnerable packages
+type Scanner interface {
+	// Name is the name of the scanner, as set in the ignite configuration
+	Name() string
+	// Scan returns the vulnerabilities of the packages installed in the root filesystem
+	Scan(ctx context.Context, rootfs string) ([]api.Vulnerability, error)
+}
+
+// New returns the scanner of the name, which runs the binary at path, or the one in
+// $PATH if path is empty
+func New(name, path string) (Scanner, error) {
+	switch name {
+	case trivyName:
+		return &trivy{binary(path, trivyName)}, nil
+	case grypeName:
+		return &grype{binary(path, grypeName)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown vulnerability scanner %q, supported scanners: %s, %s", name, trivyName, grypeName)
+}
+
+func binary(path, name string) string {
+	if path != "" {
+		return path
+	}
+
+	return name
+}
+
+// severities are ordered from the least to the most severe
+var severities = []api.VulnerabilitySeverity{
+	api.SeverityUnknown,
+	api.SeverityLow,
+	api.SeverityMedium,
+	api.SeverityHigh,
+	api.SeverityCritical,
+}
+
+func rank(s api.VulnerabilitySeverity) int {
+	for i, severity := range severities {
+		if s == severity {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// ParseSeverity parses the severity reported by a scanner, case-insensitively. The
+// negligible severity of grype is low, and unknown severities are Unknown.
+func ParseSeverity(s string) api.VulnerabilitySeverity {
+	s = strings.ToLower(s)
+	if s == "negligible" {
+		return api.SeverityLow
+	}
+
+	for _, severity := range severities {
+		if s == strings.ToLower(string(severity)) {
+			return severity
+		}
+	}
+
+	return api.SeverityUnknown
+}
+
+// ValidateThreshold checks that the severity imports can be failed on is known
+func ValidateThreshold(s api.VulnerabilitySeverity) error {
+	if s == "" || (s != api.SeverityUnknown && ParseSeverity(string(s)) == s) {
+		return nil
+	}
+
+	return fmt.Errorf("invalid severity %q, supported severities: %s, %s, %s, %s", s,
+		api.SeverityLow, api.SeverityMedium, api.SeverityHigh, api.SeverityCritical)
+}
+
+// AtLeast returns the vulnerabilities of the severity or higher
+func AtLeast(vulns []api.Vulnerability, threshold api.VulnerabilitySeverity) (found []api.Vulnerability) {
+	for _, v := range vulns {
+		if rank(v.Severity) >= rank(threshold) {
+			found = append(found, v)
+		}
+	}
+
+	return
+}
+
+// Summarize counts the vulnerabilities by severity
+func Summarize(vulns []api.Vulnerability) (s api.VulnerabilitySummary) {
+	for _, v := range vulns {
+		switch v.Severity {
+		case api.SeverityCritical:
+			s.Critical++
+		case api.SeverityHigh:
+			s.High++
+		case api.SeverityMedium:
+			s.Medium++
+		case api.SeverityLow:
+			s.Low++
+		default:
+			s.Unknown++
+		}
+	}
+
+	return
+}
+
+// Status returns the scan status of an image the scanner found the vulnerabilities in.
+// The vulnerabilities are deduplicated and sorted from the most severe.
+func Status(scanner string, vulns []api.Vulnerability) *api.ImageScanStatus {
+	type key struct{ id, pkg, version string }
+	seen := make(map[key]bool, len(vulns))
+	unique := make([]api.Vulnerability, 0, len(vulns))
+	for _, v := range vulns {
+		k := key{v.ID, v.Package, v.Version}
+		if !seen[k] {
+			seen[k] = true
+			unique = append(unique, v)
+		}
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		if ri, rj := rank(unique[i].Severity), rank(unique[j].Severity); ri != rj {
+			return ri > rj
+		}
+		if unique[i].ID != unique[j].ID {
+			return unique[i].ID < unique[j].ID
+		}
+		return unique[i].Package < unique[j].Package
+	})
+
+	return &api.ImageScanStatus{
+		Scanner:         scanner,
+		Time:            runtime.Timestamp(),
+		Summary:         Summarize(unique),
+		Vulnerabilities: unique,
+	}
+}
+
+// run runs the scanner and returns its standard output, the report
+func run(ctx context.Context, binary string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("command %q exited with %q: %v", cmd.Args, bytes.TrimSpace(stderr.Bytes()), err)
+	}
+
+	return stdout.Bytes(), nil
+}
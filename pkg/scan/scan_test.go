@@ -0,0 +1,166 @@
+package scan
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+const trivyReportJSON = `{
+  "SchemaVersion": 2,
+  "ArtifactName": "/tmp/rootfs",
+  "ArtifactType": "filesystem",
+  "Results": [
+    {
+      "Target": "rootfs (ubuntu 20.04)",
+      "Class": "os-pkgs",
+      "Vulnerabilities": [
+        {
+          "VulnerabilityID": "CVE-2021-3711",
+          "PkgName": "libssl1.1",
+          "InstalledVersion": "1.1.1f-1ubuntu2.4",
+          "FixedVersion": "1.1.1f-1ubuntu2.8",
+          "Severity": "CRITICAL",
+          "Title": "openssl: SM2 Decryption Buffer Overflow"
+        },
+        {
+          "VulnerabilityID": "CVE-2016-2781",
+          "PkgName": "coreutils",
+          "InstalledVersion": "8.30-3ubuntu2",
+          "Severity": "LOW"
+        }
+      ]
+    },
+    {
+      "Target": "usr/lib/python3/dist-packages",
+      "Class": "lang-pkgs"
+    }
+  ]
+}`
+
+const grypeReportJSON = `{
+  "matches": [
+    {
+      "vulnerability": {
+        "id": "CVE-2022-1271",
+        "severity": "High",
+        "description": "An arbitrary file write vulnerability was found in GNU gzip's zgrep utility.",
+        "fix": {"versions": ["1.10-0ubuntu4.1"], "state": "fixed"}
+      },
+      "artifact": {"name": "gzip", "version": "1.10-0ubuntu4", "type": "deb"}
+    },
+    {
+      "vulnerability": {
+        "id": "CVE-2013-4235",
+        "severity": "Negligible",
+        "fix": {"versions": [], "state": "not-fixed"}
+      },
+      "artifact": {"name": "login", "version": "1:4.8.1-1ubuntu5.20.04", "type": "deb"}
+    }
+  ],
+  "source": {"type": "directory", "target": "/tmp/rootfs"}
+}`
+
+func TestParseTrivy(t *testing.T) {
+	vulns, err := parseTrivy([]byte(trivyReportJSON))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vulns, []api.Vulnerability{
+		{
+			ID:           "CVE-2021-3711",
+			Package:      "libssl1.1",
+			Version:      "1.1.1f-1ubuntu2.4",
+			FixedVersion: "1.1.1f-1ubuntu2.8",
+			Severity:     api.SeverityCritical,
+			Title:        "openssl: SM2 Decryption Buffer Overflow",
+		},
+		{
+			ID:       "CVE-2016-2781",
+			Package:  "coreutils",
+			Version:  "8.30-3ubuntu2",
+			Severity: api.SeverityLow,
+		},
+	})
+
+	_, err = parseTrivy([]byte("not json"))
+	assert.ErrorContains(t, err, "trivy report")
+}
+
+func TestParseGrype(t *testing.T) {
+	vulns, err := parseGrype([]byte(grypeReportJSON))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vulns, []api.Vulnerability{
+		{
+			ID:           "CVE-2022-1271",
+			Package:      "gzip",
+			Version:      "1.10-0ubuntu4",
+			FixedVersion: "1.10-0ubuntu4.1",
+			Severity:     api.SeverityHigh,
+			Title:        "An arbitrary file write vulnerability was found in GNU gzip's zgrep utility.",
+		},
+		{
+			ID:       "CVE-2013-4235",
+			Package:  "login",
+			Version:  "1:4.8.1-1ubuntu5.20.04",
+			Severity: api.SeverityLow,
+		},
+	})
+}
+
+func TestStatus(t *testing.T) {
+	vulns := []api.Vulnerability{
+		{ID: "CVE-3", Package: "a", Severity: api.SeverityLow},
+		{ID: "CVE-2", Package: "b", Severity: "whatever"},
+		{ID: "CVE-1", Package: "c", Severity: api.SeverityCritical},
+		{ID: "CVE-3", Package: "a", Severity: api.SeverityLow},
+		{ID: "CVE-0", Package: "d", Severity: api.SeverityLow},
+	}
+
+	s := Status("trivy", vulns)
+	assert.Equal(t, s.Scanner, "trivy")
+	assert.DeepEqual(t, s.Summary, api.VulnerabilitySummary{Critical: 1, Low: 2, Unknown: 1})
+
+	var ids []string
+	for _, v := range s.Vulnerabilities {
+		ids = append(ids, v.ID)
+	}
+	assert.DeepEqual(t, ids, []string{"CVE-1", "CVE-0", "CVE-3", "CVE-2"})
+}
+
+func TestAtLeast(t *testing.T) {
+	vulns := []api.Vulnerability{
+		{ID: "CVE-1", Severity: api.SeverityCritical},
+		{ID: "CVE-2", Severity: api.SeverityHigh},
+		{ID: "CVE-3", Severity: api.SeverityMedium},
+		{ID: "CVE-4", Severity: api.SeverityUnknown},
+	}
+
+	assert.Equal(t, len(AtLeast(vulns, api.SeverityHigh)), 2)
+	assert.Equal(t, len(AtLeast(vulns, api.SeverityCritical)), 1)
+	assert.Equal(t, len(AtLeast(vulns, api.SeverityLow)), 3)
+}
+
+func TestValidateThreshold(t *testing.T) {
+	for _, s := range []api.VulnerabilitySeverity{"", api.SeverityLow, api.SeverityMedium, api.SeverityHigh, api.SeverityCritical} {
+		assert.NilError(t, ValidateThreshold(s))
+	}
+
+	for _, s := range []api.VulnerabilitySeverity{api.SeverityUnknown, "high", "Severe"} {
+		assert.ErrorContains(t, ValidateThreshold(s), "invalid severity")
+	}
+}
+
+func TestNew(t *testing.T) {
+	s, err := New("grype", "")
+	assert.NilError(t, err)
+	assert.Equal(t, s.Name(), "grype")
+	assert.Equal(t, s.(*grype).binary, "grype")
+
+	s, err = New("trivy", "/opt/trivy/trivy")
+	assert.NilError(t, err)
+	assert.Equal(t, s.(*trivy).binary, "/opt/trivy/trivy")
+
+	_, err = New("clair", "")
+	assert.ErrorContains(t, err, "unknown vulnerability scanner")
+}
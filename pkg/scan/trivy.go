@@ -0,0 +1,68 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+const trivyName = "trivy"
+
+// trivy scans with "trivy rootfs"
+type trivy struct {
+	binary string
+}
+
+var _ Scanner = &trivy{}
+
+func (t *trivy) Name() string {
+	return trivyName
+}
+
+func (t *trivy) Scan(ctx context.Context, rootfs string) ([]api.Vulnerability, error) {
+	out, err := run(ctx, t.binary, "rootfs", "--quiet", "--format", "json", rootfs)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTrivy(out)
+}
+
+// trivyReport is the part of the JSON report of trivy the vulnerabilities are read from
+type trivyReport struct {
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string
+			PkgName          string
+			InstalledVersion string
+			FixedVersion     string
+			Severity         string
+			Title            string
+		}
+	}
+}
+
+func parseTrivy(report []byte) ([]api.Vulnerability, error) {
+	var r trivyReport
+	if err := json.Unmarshal(report, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse the trivy report: %v", err)
+	}
+
+	var vulns []api.Vulnerability
+	for _, result := range r.Results {
+		for _, v := range result.Vulnerabilities {
+			vulns = append(vulns, api.Vulnerability{
+				ID:           v.VulnerabilityID,
+				Package:      v.PkgName,
+				Version:      v.InstalledVersion,
+				FixedVersion: v.FixedVersion,
+				Severity:     ParseSeverity(v.Severity),
+				Title:        v.Title,
+			})
+		}
+	}
+
+	return vulns, nil
+}
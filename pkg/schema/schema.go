@@ -0,0 +1,246 @@
+// Package schema builds JSON schemas for the manifests of the ignite API kinds. The
+// schemas are built from the OpenAPI definitions generated from the Go types, which
+// are compiled into ignite, so they always match the API of the running binary.
+package schema
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5"
+	"github.com/weaveworks/ignite/pkg/openapi"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"k8s.io/kube-openapi/pkg/common"
+)
+
+const (
+	// jsonSchemaDraft is the JSON schema version of the generated schemas
+	jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+	apisPackage = "github.com/weaveworks/ignite/pkg/apis/ignite/"
+	typeMeta    = "k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta"
+)
+
+// Kinds are the kinds manifests can be written for
+var Kinds = []runtime.Kind{api.KindVM, api.KindVMTemplate, api.KindImage, api.KindKernel, "Configuration"}
+
+// Versions are the API versions schemas can be built for
+var Versions = []string{"v1alpha2", "v1alpha3", "v1alpha4", "v1alpha5"}
+
+// DefaultVersion is the API version schemas are built for by default
+var DefaultVersion = v1alpha5.SchemeGroupVersion.Version
+
+// overrides replace the generated definitions of types with custom JSON encodings, and
+// of the types whose definitions aren't generated
+var overrides = map[string]spec.Schema{
+	"github.com/weaveworks/libgitops/pkg/runtime.ObjectMeta": {
+		SchemaProps: spec.SchemaProps{
+			Description: "ObjectMeta holds the name, UID, creation time, labels and annotations of an object",
+			Type:        []string{"object"},
+			Properties: map[string]spec.Schema{
+				"name":        *spec.StringProperty(),
+				"uid":         *spec.StringProperty(),
+				"created":     *spec.DateTimeProperty(),
+				"labels":      *spec.MapProperty(spec.StringProperty()),
+				"annotations": *spec.MapProperty(spec.StringProperty()),
+			},
+			AdditionalProperties: &spec.SchemaOrBool{Allows: false},
+		},
+	},
+	"github.com/weaveworks/libgitops/pkg/runtime.Time": *spec.DateTimeProperty(),
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":    *spec.StringProperty().WithDescription("A duration like 30s, 5m or 1h30m"),
+}
+
+// sshForms are the JSON forms of SSH besides the object: true to generate a key, or a public key file
+var sshForms = []spec.Schema{*spec.BooleanProperty(), *spec.StringProperty()}
+
+// otherForms are the JSON forms of types with custom JSON encodings besides the generated object
+var otherForms = map[string][]spec.Schema{
+	apisPackage + "v1alpha2.SSH": sshForms,
+	apisPackage + "v1alpha3.SSH": sshForms,
+	apisPackage + "v1alpha4.SSH": sshForms,
+	apisPackage + "v1alpha5.SSH": sshForms,
+	// The memory size alone
+	apisPackage + "v1alpha5.VMMemorySpec": {*spec.StringProperty()},
+}
+
+// ForKinds returns the JSON schema of the manifests of the given kinds in the given
+// API version. For several kinds, the schema matches a manifest of any of them.
+func ForKinds(version string, kinds []runtime.Kind) (*spec.Schema, error) {
+	if !isVersion(version) {
+		return nil, fmt.Errorf("unknown API version %q, expected one of %v", version, Versions)
+	}
+
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("no kinds given")
+	}
+
+	definitions := openapi.GetOpenAPIDefinitions(func(name string) spec.Ref {
+		return spec.MustCreateRef("#/definitions/" + definitionName(name))
+	})
+
+	b := &builder{
+		definitions: definitions,
+		used:        spec.Definitions{},
+	}
+
+	kindRefs := make([]spec.Schema, 0, len(kinds))
+	for _, kind := range kinds {
+		name := apisPackage + version + "." + kind.Title()
+		if _, ok := definitions[name]; !ok || !isKind(kind.Title()) {
+			return nil, fmt.Errorf("unknown kind %q for API version %s, expected one of %v", kind.Title(), version, KindNames())
+		}
+
+		if err := b.add(name); err != nil {
+			return nil, err
+		}
+
+		// The kind is identified by its TypeMeta, which the generated definition has as a property
+		kindSchema := b.used[definitionName(name)]
+		delete(kindSchema.Properties, "TypeMeta")
+		kindSchema.Properties["apiVersion"] = *spec.StringProperty().WithEnum(api.GroupName + "/" + version)
+		kindSchema.Properties["kind"] = *spec.StringProperty().WithEnum(kind.Title())
+		kindSchema.Required = []string{"apiVersion", "kind"}
+		b.used[definitionName(name)] = kindSchema
+
+		kindRefs = append(kindRefs, *spec.RefSchema("#/definitions/" + definitionName(name)))
+	}
+
+	schema := &spec.Schema{}
+	if len(kindRefs) == 1 {
+		// A $ref next to the definitions would make validators ignore them
+		schema.AllOf = kindRefs
+	} else {
+		schema.OneOf = kindRefs
+	}
+
+	schema.Schema = jsonSchemaDraft
+	schema.Title = fmt.Sprintf("ignite %s manifest", version)
+	schema.Definitions = b.used
+	return schema, nil
+}
+
+// builder collects the definitions a schema refers to
+type builder struct {
+	definitions map[string]common.OpenAPIDefinition
+	used        spec.Definitions
+}
+
+// add adds the definition with the given name and the definitions it depends on
+func (b *builder) add(name string) error {
+	if _, ok := b.used[definitionName(name)]; ok {
+		return nil
+	}
+
+	if s, ok := overrides[name]; ok {
+		b.used[definitionName(name)] = s
+		return nil
+	}
+
+	definition, ok := b.definitions[name]
+	if !ok {
+		return fmt.Errorf("no schema for type %s", name)
+	}
+
+	s := manifestSchema(definition.Schema)
+	if forms, ok := otherForms[name]; ok {
+		object := s
+		object.Description = ""
+		s = spec.Schema{}
+		s.Description = definition.Schema.Description
+		s.OneOf = append(append([]spec.Schema{}, forms...), object)
+	}
+	b.used[definitionName(name)] = s
+
+	for _, dependency := range definition.Dependencies {
+		// The TypeMeta of the kinds is replaced by apiVersion and kind
+		if dependency == typeMeta {
+			continue
+		}
+
+		if err := b.add(dependency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// manifestSchema adapts a generated schema to manifests. The generated schemas mark
+// the fields without omitempty as required and have the zero values of the Go types
+// as defaults, but manifests may leave out any field and get defaults from ignite.
+// Unknown fields are rejected like the strict decoding of manifests does.
+func manifestSchema(s spec.Schema) spec.Schema {
+	s.Required = nil
+	s.Default = nil
+
+	// net.IP is generated as bytes, but its JSON form is the IP address as text
+	if s.Format == "byte" {
+		s.Format = ""
+	}
+
+	if len(s.Properties) > 0 {
+		properties := make(map[string]spec.Schema, len(s.Properties))
+		for name, property := range s.Properties {
+			properties[name] = manifestSchema(property)
+		}
+
+		s.Properties = properties
+		s.AdditionalProperties = &spec.SchemaOrBool{Allows: false}
+	} else if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		additional := manifestSchema(*s.AdditionalProperties.Schema)
+		s.AdditionalProperties = &spec.SchemaOrBool{Allows: true, Schema: &additional}
+	}
+
+	if s.Items != nil && s.Items.Schema != nil {
+		items := manifestSchema(*s.Items.Schema)
+		s.Items = &spec.SchemaOrArray{Schema: &items}
+	}
+
+	return s
+}
+
+// definitionName returns the name of the definition of a Go type, its package name and
+// type name, e.g. v1alpha5.VMSpec for github.com/weaveworks/ignite/pkg/apis/ignite/v1alpha5.VMSpec
+func definitionName(name string) string {
+	return path.Base(name)
+}
+
+func isVersion(version string) bool {
+	for _, v := range Versions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isKind(kind string) bool {
+	_, err := ParseKind(kind)
+	return err == nil
+}
+
+// KindNames returns the names of the kinds, runtime.Kind's String lowercases the first letter
+func KindNames() []string {
+	names := make([]string, 0, len(Kinds))
+	for _, k := range Kinds {
+		names = append(names, k.Title())
+	}
+
+	return names
+}
+
+// ParseKind returns the kind with the given name, case-insensitively
+func ParseKind(kind string) (runtime.Kind, error) {
+	for _, k := range Kinds {
+		if strings.EqualFold(k.Title(), kind) {
+			return k, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown kind %q, expected one of %v", kind, KindNames())
+}
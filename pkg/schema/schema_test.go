@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+var refRegexp = regexp.MustCompile(`"\$ref":"#/definitions/([^"]+)"`)
+
+func TestForKinds(t *testing.T) {
+	schema, err := ForKinds("v1alpha5", []runtime.Kind{api.KindVM})
+	assert.NilError(t, err)
+	assert.Equal(t, string(schema.Schema), jsonSchemaDraft)
+	assert.Equal(t, len(schema.AllOf), 1)
+	assert.Equal(t, schema.AllOf[0].Ref.String(), "#/definitions/v1alpha5.VM")
+
+	// The kind is identified by apiVersion and kind, instead of the generated TypeMeta
+	vm := schema.Definitions["v1alpha5.VM"]
+	assert.DeepEqual(t, vm.Required, []string{"apiVersion", "kind"})
+	assert.DeepEqual(t, vm.Properties["apiVersion"].Enum, []interface{}{"ignite.weave.works/v1alpha5"})
+	assert.DeepEqual(t, vm.Properties["kind"].Enum, []interface{}{"VM"})
+	_, ok := vm.Properties["TypeMeta"]
+	assert.Assert(t, !ok)
+
+	// Manifests can leave out any field, but unknown fields are rejected
+	vmSpec := schema.Definitions["v1alpha5.VMSpec"]
+	assert.Equal(t, len(vmSpec.Required), 0)
+	assert.Assert(t, vmSpec.AdditionalProperties != nil && !vmSpec.AdditionalProperties.Allows)
+
+	// Types with custom JSON encodings have the schema of their JSON form
+	assert.DeepEqual(t, []string(schema.Definitions["v1alpha1.Size"].Type), []string{"string"})
+	assert.DeepEqual(t, []string(schema.Definitions["v1alpha1.OCIImageRef"].Type), []string{"string"})
+	assert.DeepEqual(t, []string(schema.Definitions["runtime.Time"].Type), []string{"string"})
+	assert.Equal(t, len(schema.Definitions["v1alpha5.SSH"].OneOf), 3)
+	assert.Equal(t, len(schema.Definitions["v1alpha5.VMMemorySpec"].OneOf), 2)
+
+	b, err := json.Marshal(schema)
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(b), `"default"`), "the zero values of the Go types aren't defaults")
+	assert.Assert(t, !strings.Contains(string(b), `"format":"byte"`), "IP addresses are strings")
+
+	// Every reference has a definition
+	for _, ref := range refRegexp.FindAllStringSubmatch(string(b), -1) {
+		_, ok := schema.Definitions[ref[1]]
+		assert.Assert(t, ok, "no definition for %s", ref[1])
+	}
+}
+
+func TestForKindsSeveral(t *testing.T) {
+	for _, version := range []string{"v1alpha4", "v1alpha5"} {
+		kinds := []runtime.Kind{api.KindVM, api.KindImage, api.KindKernel, "Configuration"}
+		schema, err := ForKinds(version, kinds)
+		assert.NilError(t, err)
+		assert.Equal(t, len(schema.OneOf), len(kinds))
+		assert.DeepEqual(t, schema.Definitions[version+".Image"].Properties["apiVersion"].Enum,
+			[]interface{}{"ignite.weave.works/" + version})
+	}
+}
+
+func TestForKindsErrors(t *testing.T) {
+	_, err := ForKinds("v1alpha9", []runtime.Kind{api.KindVM})
+	assert.ErrorContains(t, err, "unknown API version")
+
+	_, err = ForKinds("v1alpha5", []runtime.Kind{api.KindPool})
+	assert.ErrorContains(t, err, "unknown kind")
+
+	// The Configuration kind was added in v1alpha3
+	_, err = ForKinds("v1alpha2", []runtime.Kind{"Configuration"})
+	assert.ErrorContains(t, err, "unknown kind")
+}
+
+func TestParseKind(t *testing.T) {
+	kind, err := ParseKind("vmtemplate")
+	assert.NilError(t, err)
+	assert.Equal(t, kind, api.KindVMTemplate)
+
+	_, err = ParseKind("pod")
+	assert.ErrorContains(t, err, "unknown kind")
+}
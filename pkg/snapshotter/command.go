@@ -0,0 +1,20 @@
+package snapshotter
+
+import (
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// ExecuteCommand runs the storage and filesystem tools of the snapshotters, the tests replace it
+var ExecuteCommand = util.ExecuteCommand
+
+// RepairFilesystem repairs the filesystem of a VM disk in case it has errors, discards
+// its free blocks and makes the filesystem fill the disk. Whether the discards free up
+// space on the host depends on the snapshotter backing the disk.
+func RepairFilesystem(devicePath string) error {
+	// e2fsck throws an error if the filesystem gets repaired, so just ignore it
+	_, _ = ExecuteCommand("e2fsck", "-p", "-f", "-E", "discard", devicePath)
+
+	// This is a no-op if the filesystem already fills the disk
+	_, err := ExecuteCommand("resize2fs", devicePath)
+	return err
+}
@@ -0,0 +1,49 @@
+package snapshotter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/snapshotter/snapshottertest"
+)
+
+func TestRepairFilesystem(t *testing.T) {
+	tests := []struct {
+		name   string
+		failed string // command that fails
+		err    bool
+	}{
+		{
+			name: "clean filesystem",
+		},
+		{
+			// e2fsck exits non-zero after repairing the filesystem
+			name:   "repaired filesystem",
+			failed: "e2fsck",
+		},
+		{
+			name:   "filesystem that can't be grown",
+			failed: "resize2fs",
+			err:    true,
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			if len(rt.failed) > 0 {
+				commands.Fail(rt.failed, fmt.Errorf("exit status 1"))
+			}
+
+			err := snapshotter.RepairFilesystem("/dev/vg0/ignite-0123456789abcdef")
+			assert.Equal(t, err != nil, rt.err, "unexpected error: %v", err)
+			assert.DeepEqual(t, commands.Run, []string{
+				"e2fsck -p -f -E discard /dev/vg0/ignite-0123456789abcdef",
+				"resize2fs /dev/vg0/ignite-0123456789abcdef",
+			})
+		})
+	}
+}
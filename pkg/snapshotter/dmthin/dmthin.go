@@ -0,0 +1,235 @@
+package dmthin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/apis/ignite/scheme"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/dm"
+	"github.com/weaveworks/ignite/pkg/flock"
+	"github.com/weaveworks/ignite/pkg/operations/lookup"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// poolLock is the lock serializing the pool operations of all ignite processes
+const poolLock = "pool"
+
+// dmsetupNotFound is the error message when dmsetup can't find a device.
+const dmsetupNotFound = "No such device or address"
+
+// AllocateOverlay creates a thin snapshot of the image's base volume for the VM.
+// The image is imported into the pool as a base volume by the first VM using it.
+func AllocateOverlay(vm *api.VM) error {
+	// Get the image UID from the VM
+	imageUID, err := lookup.ImageUIDForVM(vm, providers.Client)
+	if err != nil {
+		return err
+	}
+	imageDir := path.Join(constants.IMAGE_DIR, imageUID.String())
+
+	return withPool(func(pool *dm.Pool) error {
+		imageMetadataPath := path.Join(imageDir, constants.METADATA)
+		base := findDevice(pool, imageMetadataPath)
+		if base == nil {
+			log.Infof("Importing image %q into the thin pool...", imageUID)
+			if base, err = pool.ImportVolume(path.Join(imageDir, constants.IMAGE_FS), imageMetadataPath); err != nil {
+				return err
+			}
+			base.Type = api.PoolDeviceTypeImage
+		}
+
+		size := snapshotter.DiskSize(vm.Spec.DiskSize, base.Size)
+
+		// Thin devices allocate from the shared pool, there's no per-VM file to cap
+		if vm.Spec.OverlaySizeLimit != meta.EmptySize {
+			log.Warnf("The dmthin snapshotter doesn't cap the overlay of VM %q", vm.GetUID())
+		}
+
+		snapshot, err := base.CreateSnapshot(size, path.Join(vm.ObjectPath(), constants.METADATA))
+		if err != nil {
+			return err
+		}
+		snapshot.Type = api.PoolDeviceTypeVM
+
+		// Make sure the all directories above the overlay file exist
+		if err := os.MkdirAll(path.Dir(vm.OverlayFile()), constants.DATA_DIR_PERM); err != nil {
+			return err
+		}
+
+		// Record the ID of the thin device for the VM, this is used
+		// to reference the device without needing the pool state
+		return ioutil.WriteFile(vm.OverlayFile(), []byte(snapshot.ID().String()), 0644)
+	})
+}
+
+// ResizeOverlay grows the thin device of the VM to its disk size. Thin devices
+// are sized by their table, so the new size applies on the next activation.
+func ResizeOverlay(vm *api.VM) error {
+	return withPool(func(pool *dm.Pool) error {
+		device := findDevice(pool, path.Join(vm.ObjectPath(), constants.METADATA))
+		if device == nil {
+			return fmt.Errorf("no thin device found for VM %q", vm.GetUID())
+		}
+
+		if err := device.Deactivate(); err != nil {
+			return err
+		}
+
+		device.Size = vm.Spec.DiskSize
+		return nil
+	})
+}
+
+// ActivateSnapshot activates the thin device of the VM, and returns its path
+func ActivateSnapshot(vm *api.VM) (devicePath string, err error) {
+	err = withPool(func(pool *dm.Pool) error {
+		device := findDevice(pool, path.Join(vm.ObjectPath(), constants.METADATA))
+		if device == nil {
+			return fmt.Errorf("no thin device found for VM %q", vm.GetUID())
+		}
+
+		if err := device.Activate(); err != nil {
+			return err
+		}
+		devicePath = device.Path()
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	// The discards return the space of files deleted by the VM to the pool, and
+	// the filesystem grows to the size set by ResizeOverlay
+	err = snapshotter.RepairFilesystem(devicePath)
+	return
+}
+
+// DeactivateSnapshot deactivates the thin device of the VM. The pool is not
+// needed for this, so it can also be called from inside the VM container.
+func DeactivateSnapshot(vm *api.VM) error {
+	devicePath, err := SnapshotDevice(vm)
+	if err != nil {
+		return err
+	}
+
+	return removeDevice(devicePath)
+}
+
+// removeDevice removes the device-mapper device at devicePath, if it still exists
+func removeDevice(devicePath string) error {
+	if _, err := snapshotter.ExecuteCommand("dmsetup", "remove", "--verifyudev", path.Base(devicePath)); err != nil {
+		// If the device is not found, it's been deactivated already, return nil.
+		if strings.Contains(err.Error(), dmsetupNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RemoveSnapshot deletes the thin device of the VM from the pool
+func RemoveSnapshot(vm *api.VM) error {
+	return withPool(func(pool *dm.Pool) error {
+		if device := findDevice(pool, path.Join(vm.ObjectPath(), constants.METADATA)); device != nil {
+			return pool.DeleteDevice(device.ID())
+		}
+
+		return nil
+	})
+}
+
+// SnapshotDevice returns the path of the thin device of the VM
+func SnapshotDevice(vm *api.VM) (string, error) {
+	data, err := ioutil.ReadFile(vm.OverlayFile())
+	if err != nil {
+		return "", err
+	}
+
+	i, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return "", fmt.Errorf("invalid thin device ID for VM %q: %v", vm.GetUID(), err)
+	}
+
+	return dm.DevicePath(meta.NewDMID(i)), nil
+}
+
+// findDevice returns the device in the pool belonging to the object with the given metadata file
+func findDevice(pool *dm.Pool, metadataPath string) (device *dm.Device) {
+	_ = pool.ForDevices(func(_ meta.DMID, d *dm.Device) error {
+		if d.MetadataPath == metadataPath {
+			device = d
+		}
+		return nil
+	})
+
+	return
+}
+
+// withPool runs the given function on the pool while holding the pool lock,
+// and persists the pool state afterwards
+func withPool(f func(pool *dm.Pool) error) (err error) {
+	// Serialize all pool operations, the state is shared between all ignite processes
+	unlock, err := flock.Lock(poolLock)
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	poolMeta, err := loadPool()
+	if err != nil {
+		return
+	}
+
+	// NewPool copies the pool state, so save the state of the returned pool
+	pool := dm.NewPool(poolMeta)
+	if err = f(pool); err != nil {
+		return
+	}
+
+	return savePool(&pool.Pool)
+}
+
+// loadPool reads the pool state, or creates it with the default
+// configuration if this is the first time the pool is used
+func loadPool() (*api.Pool, error) {
+	poolMeta := &api.Pool{}
+	if util.FileExists(constants.SNAPSHOTTER_POOL_PATH) {
+		if err := scheme.Serializer.DecodeFileInto(constants.SNAPSHOTTER_POOL_PATH, poolMeta); err != nil {
+			return nil, err
+		}
+
+		return poolMeta, nil
+	}
+
+	poolMeta.SetGroupVersionKind(api.SchemeGroupVersion.WithKind(api.KindPool.Title()))
+	if err := scheme.Serializer.DefaultInternal(poolMeta); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(constants.SNAPSHOTTER_DIR, constants.DATA_DIR_PERM); err != nil {
+		return nil, err
+	}
+
+	return poolMeta, nil
+}
+
+func savePool(poolMeta *api.Pool) error {
+	b, err := scheme.Serializer.EncodeJSON(poolMeta)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(constants.SNAPSHOTTER_POOL_PATH, b, 0644)
+}
@@ -0,0 +1,47 @@
+package dmthin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/dm"
+	"github.com/weaveworks/ignite/pkg/snapshotter/snapshottertest"
+)
+
+func TestRemoveDevice(t *testing.T) {
+	devicePath := dm.DevicePath(meta.NewDMID(5))
+
+	tests := []struct {
+		name   string
+		result error // returned by dmsetup
+		err    bool
+	}{
+		{
+			name: "active device",
+		},
+		{
+			name:   "deactivated device",
+			result: fmt.Errorf("device-mapper: remove ioctl failed: %s", dmsetupNotFound),
+		},
+		{
+			name:   "busy device",
+			result: fmt.Errorf("device-mapper: remove ioctl failed: Device or resource busy"),
+			err:    true,
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			commands.Fail("dmsetup", rt.result)
+
+			err := removeDevice(devicePath)
+			assert.Equal(t, err != nil, rt.err, "unexpected error: %v", err)
+			assert.DeepEqual(t, commands.Run, []string{"dmsetup remove --verifyudev " + strings.TrimPrefix(devicePath, "/dev/mapper/")})
+		})
+	}
+}
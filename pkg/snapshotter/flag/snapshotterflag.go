@@ -0,0 +1,43 @@
+package flag
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+)
+
+var snapshotters = snapshotter.ListSnapshotters()
+
+type SnapshotterFlag struct {
+	value *snapshotter.Name
+}
+
+func (sf *SnapshotterFlag) Set(val string) error {
+	for _, s := range snapshotters {
+		if s.String() == val {
+			*sf.value = s
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid snapshotter %q, must be one of %v", val, snapshotters)
+}
+
+func (sf *SnapshotterFlag) String() string {
+	if sf.value == nil {
+		return ""
+	}
+
+	return sf.value.String()
+}
+
+func (sf *SnapshotterFlag) Type() string {
+	return "snapshotter"
+}
+
+var _ pflag.Value = &SnapshotterFlag{}
+
+func SnapshotterVar(fs *pflag.FlagSet, ptr *snapshotter.Name) {
+	fs.Var(&SnapshotterFlag{value: ptr}, "snapshotter", fmt.Sprintf("Storage backend for the VM's writable disk. Available options are: %v (default %v)", snapshotters, snapshotter.SnapshotterDMLegacy))
+}
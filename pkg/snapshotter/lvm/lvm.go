@@ -0,0 +1,254 @@
+package lvm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/operations/lookup"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// AllocateOverlay creates the logical volume holding the disk of the VM in the configured
+// volume group. Without a thin pool the image is copied into a new volume for every VM,
+// with a thin pool the image is imported once and the VM gets a thin snapshot of it.
+func AllocateOverlay(vm *api.VM) error {
+	cfg, err := configuration()
+	if err != nil {
+		return err
+	}
+
+	// Get the image UID from the VM
+	imageUID, err := lookup.ImageUIDForVM(vm, providers.Client)
+	if err != nil {
+		return err
+	}
+
+	// Get the size of the image ext4 file
+	imagePath := path.Join(constants.IMAGE_DIR, imageUID.String(), constants.IMAGE_FS)
+	fi, err := os.Stat(imagePath)
+	if err != nil {
+		return err
+	}
+	imageSize := meta.NewSizeFromBytes(uint64(fi.Size()))
+
+	size := snapshotter.DiskSize(vm.Spec.DiskSize, imageSize)
+	if vm.Spec.OverlaySizeLimit != meta.EmptySize {
+		log.Warnf("The lvm snapshotter doesn't cap the overlay of VM %q", vm.GetUID())
+	}
+
+	name := vm.PrefixedID()
+	if err := createVolume(cfg, name, imageUID, imagePath, imageSize, size); err != nil {
+		return err
+	}
+
+	// Make sure the all directories above the overlay file exist
+	if err := os.MkdirAll(path.Dir(vm.OverlayFile()), constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	// Record the path of the volume, the configuration of the host might change over the VM's lifetime
+	return ioutil.WriteFile(vm.OverlayFile(), []byte(lvPath(cfg.VolumeGroup, name)), 0644)
+}
+
+// ResizeOverlay extends the logical volume of the VM to its disk size
+func ResizeOverlay(vm *api.VM) error {
+	devicePath, err := SnapshotDevice(vm)
+	if err != nil {
+		return err
+	}
+
+	_, err = snapshotter.ExecuteCommand("lvextend", "--size", sizeArg(vm.Spec.DiskSize), devicePath)
+	return err
+}
+
+// ActivateSnapshot activates the logical volume of the VM, and returns its path
+func ActivateSnapshot(vm *api.VM) (string, error) {
+	devicePath, err := SnapshotDevice(vm)
+	if err != nil {
+		return "", err
+	}
+
+	return devicePath, activateVolume(devicePath)
+}
+
+// DeactivateSnapshot is a no-op, the logical volumes stay active like all other volumes
+// managed by LVM. The VM container doesn't have the LVM tooling to deactivate them anyways.
+func DeactivateSnapshot(_ *api.VM) error {
+	return nil
+}
+
+// RemoveSnapshot removes the logical volume of the VM
+func RemoveSnapshot(vm *api.VM) error {
+	devicePath, err := SnapshotDevice(vm)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // The volume has never been created
+		}
+		return err
+	}
+
+	_, err = snapshotter.ExecuteCommand("lvremove", "--yes", devicePath)
+	return err
+}
+
+// SnapshotDevice returns the path of the logical volume of the VM
+func SnapshotDevice(vm *api.VM) (string, error) {
+	data, err := ioutil.ReadFile(vm.OverlayFile())
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// createVolume creates the logical volume of the given name and size holding a copy of the
+// image, or a thin snapshot of the image if a thin pool is configured. The volume is removed
+// again if it can't be filled.
+func createVolume(cfg api.LVMConfiguration, name string, imageUID runtime.UID, imagePath string, imageSize, size meta.Size) (err error) {
+	if len(cfg.ThinPool) == 0 {
+		if _, err = snapshotter.ExecuteCommand("lvcreate", "--yes",
+			"--size", sizeArg(size),
+			"--name", name,
+			cfg.VolumeGroup); err != nil {
+			return
+		}
+		defer removeOnError(&err, path.Join(cfg.VolumeGroup, name))
+
+		// The volume isn't zeroed, so the whole image needs to be copied
+		_, err = snapshotter.ExecuteCommand("dd", "if="+imagePath, "of="+lvPath(cfg.VolumeGroup, name), "bs=1M")
+		return
+	}
+
+	base, err := ensureImageVolume(cfg, imageUID, imagePath, imageSize)
+	if err != nil {
+		return
+	}
+
+	// Thin snapshots are flagged to be skipped on activation, ActivateSnapshot overrides that
+	if _, err = snapshotter.ExecuteCommand("lvcreate", "--yes",
+		"--snapshot", path.Join(cfg.VolumeGroup, base),
+		"--name", name); err != nil {
+		return
+	}
+	defer removeOnError(&err, path.Join(cfg.VolumeGroup, name))
+
+	if size.Bytes() > imageSize.Bytes() {
+		_, err = snapshotter.ExecuteCommand("lvextend", "--size", sizeArg(size), path.Join(cfg.VolumeGroup, name))
+	}
+
+	return
+}
+
+// ensureImageVolume imports the image into a thin volume if that hasn't been done yet,
+// and returns the name of the volume. A partially imported volume is removed again, as
+// it would otherwise be taken for the image by the next VM.
+func ensureImageVolume(cfg api.LVMConfiguration, imageUID runtime.UID, imagePath string, imageSize meta.Size) (name string, err error) {
+	name = util.NewPrefixer(providers.IDPrefix).Prefix("image", imageUID)
+	if util.FileExists(lvPath(cfg.VolumeGroup, name)) {
+		return
+	}
+
+	log.Infof("Importing image %q into the LVM thin pool...", imageUID)
+	if _, err = snapshotter.ExecuteCommand("lvcreate", "--yes",
+		"--virtualsize", sizeArg(imageSize),
+		"--thinpool", path.Join(cfg.VolumeGroup, cfg.ThinPool),
+		"--name", name); err != nil {
+		return
+	}
+	defer removeOnError(&err, path.Join(cfg.VolumeGroup, name))
+
+	// A new thin volume reads as zeros, so only the non-zero blocks need to be written
+	_, err = snapshotter.ExecuteCommand("dd", "if="+imagePath, "of="+lvPath(cfg.VolumeGroup, name), "bs=1M", "conv=sparse")
+	return
+}
+
+// removeOnError removes the logical volume if *err is set
+func removeOnError(err *error, volume string) {
+	if *err == nil {
+		return
+	}
+
+	if _, rmErr := snapshotter.ExecuteCommand("lvremove", "--yes", volume); rmErr != nil {
+		log.Warnf("Failed to remove logical volume %q: %v", volume, rmErr)
+	}
+}
+
+// activateVolume activates the logical volume at devicePath and prepares its filesystem.
+// Activating an active volume is a no-op, so this is safe to run on every VM start.
+func activateVolume(devicePath string) error {
+	if _, err := snapshotter.ExecuteCommand("lvchange", "--activate", "y", "--ignoreactivationskip", devicePath); err != nil {
+		return err
+	}
+
+	// For thin volumes, the discards return the space of files deleted by the VM to the pool
+	return snapshotter.RepairFilesystem(devicePath)
+}
+
+// configuration returns the LVM configuration of the host
+func configuration() (api.LVMConfiguration, error) {
+	var cfg api.LVMConfiguration
+	if providers.ComponentConfig != nil {
+		cfg = providers.ComponentConfig.Spec.LVM
+	}
+
+	if len(cfg.VolumeGroup) == 0 {
+		return cfg, fmt.Errorf("no LVM volume group configured, set spec.lvm.volumeGroup in the ignite configuration")
+	}
+
+	return cfg, nil
+}
+
+func lvPath(vg, name string) string {
+	return path.Join("/dev", vg, name)
+}
+
+// sizeArg formats the size for LVM, in bytes
+func sizeArg(size meta.Size) string {
+	return fmt.Sprintf("%db", size.Bytes())
+}
+
+// OverlayUsage returns the size of the logical volume of the VM if it's a copy of the
+// image. The blocks of thin snapshots can't be told apart from the ones they share with
+// the image, so snapshotter.ErrUsageUnknown is returned for them.
+func OverlayUsage(vm *api.VM) (meta.Size, error) {
+	devicePath, err := SnapshotDevice(vm)
+	if err != nil {
+		return meta.EmptySize, err
+	}
+
+	return volumeUsage(devicePath)
+}
+
+// volumeUsage returns the size of the logical volume at devicePath, or
+// snapshotter.ErrUsageUnknown if it's a thin snapshot
+func volumeUsage(devicePath string) (meta.Size, error) {
+	out, err := snapshotter.ExecuteCommand("lvs", "--noheadings", "--nosuffix", "--units", "b",
+		"--separator", ",", "-o", "lv_size,pool_lv", devicePath)
+	if err != nil {
+		return meta.EmptySize, err
+	}
+
+	fields := strings.Split(strings.TrimSpace(out), ",")
+	if len(fields) == 2 && len(strings.TrimSpace(fields[1])) != 0 {
+		return meta.EmptySize, snapshotter.ErrUsageUnknown
+	}
+
+	size, err := strconv.ParseUint(strings.TrimSpace(fields[0]), 10, 64)
+	if err != nil {
+		return meta.EmptySize, fmt.Errorf("failed to parse the size of logical volume %q: %v", devicePath, err)
+	}
+
+	return meta.NewSizeFromBytes(size), nil
+}
@@ -0,0 +1,175 @@
+package lvm
+
+import (
+	"fmt"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/snapshotter/snapshottertest"
+)
+
+func TestCreateVolume(t *testing.T) {
+	oldPrefix := providers.IDPrefix
+	providers.IDPrefix = "ignite"
+	defer func() { providers.IDPrefix = oldPrefix }()
+
+	imagePath := "/var/lib/firecracker/image/fedcba9876543210/image.ext4"
+	imageSize := meta.NewSizeFromBytes(1024 * 1024 * 1024)
+
+	tests := []struct {
+		name     string
+		cfg      api.LVMConfiguration
+		size     meta.Size
+		failed   string // command that fails
+		expected []string
+	}{
+		{
+			name: "copy of the image",
+			cfg:  api.LVMConfiguration{VolumeGroup: "vg0"},
+			size: meta.NewSizeFromBytes(2 * 1024 * 1024 * 1024),
+			expected: []string{
+				"lvcreate --yes --size 2147483648b --name ignite-0123456789abcdef vg0",
+				"dd if=" + imagePath + " of=/dev/vg0/ignite-0123456789abcdef bs=1M",
+			},
+		},
+		{
+			name:   "failed copy of the image",
+			cfg:    api.LVMConfiguration{VolumeGroup: "vg0"},
+			size:   imageSize,
+			failed: "dd",
+			expected: []string{
+				"lvcreate --yes --size 1073741824b --name ignite-0123456789abcdef vg0",
+				"dd if=" + imagePath + " of=/dev/vg0/ignite-0123456789abcdef bs=1M",
+				"lvremove --yes vg0/ignite-0123456789abcdef",
+			},
+		},
+		{
+			name:   "no space for the copy",
+			cfg:    api.LVMConfiguration{VolumeGroup: "vg0"},
+			size:   imageSize,
+			failed: "lvcreate",
+			expected: []string{
+				"lvcreate --yes --size 1073741824b --name ignite-0123456789abcdef vg0",
+			},
+		},
+		{
+			name: "thin snapshot of the image",
+			cfg:  api.LVMConfiguration{VolumeGroup: "vg0", ThinPool: "pool"},
+			size: imageSize,
+			expected: []string{
+				"lvcreate --yes --virtualsize 1073741824b --thinpool vg0/pool --name ignite-image-fedcba9876543210",
+				"dd if=" + imagePath + " of=/dev/vg0/ignite-image-fedcba9876543210 bs=1M conv=sparse",
+				"lvcreate --yes --snapshot vg0/ignite-image-fedcba9876543210 --name ignite-0123456789abcdef",
+			},
+		},
+		{
+			name: "thin snapshot larger than the image",
+			cfg:  api.LVMConfiguration{VolumeGroup: "vg0", ThinPool: "pool"},
+			size: meta.NewSizeFromBytes(2 * 1024 * 1024 * 1024),
+			expected: []string{
+				"lvcreate --yes --virtualsize 1073741824b --thinpool vg0/pool --name ignite-image-fedcba9876543210",
+				"dd if=" + imagePath + " of=/dev/vg0/ignite-image-fedcba9876543210 bs=1M conv=sparse",
+				"lvcreate --yes --snapshot vg0/ignite-image-fedcba9876543210 --name ignite-0123456789abcdef",
+				"lvextend --size 2147483648b vg0/ignite-0123456789abcdef",
+			},
+		},
+		{
+			// The partial import would be taken for the image by the next VM
+			name:   "failed import of the image",
+			cfg:    api.LVMConfiguration{VolumeGroup: "vg0", ThinPool: "pool"},
+			size:   imageSize,
+			failed: "dd",
+			expected: []string{
+				"lvcreate --yes --virtualsize 1073741824b --thinpool vg0/pool --name ignite-image-fedcba9876543210",
+				"dd if=" + imagePath + " of=/dev/vg0/ignite-image-fedcba9876543210 bs=1M conv=sparse",
+				"lvremove --yes vg0/ignite-image-fedcba9876543210",
+			},
+		},
+		{
+			name:   "failed extension of the thin snapshot",
+			cfg:    api.LVMConfiguration{VolumeGroup: "vg0", ThinPool: "pool"},
+			size:   meta.NewSizeFromBytes(2 * 1024 * 1024 * 1024),
+			failed: "lvextend",
+			expected: []string{
+				"lvcreate --yes --virtualsize 1073741824b --thinpool vg0/pool --name ignite-image-fedcba9876543210",
+				"dd if=" + imagePath + " of=/dev/vg0/ignite-image-fedcba9876543210 bs=1M conv=sparse",
+				"lvcreate --yes --snapshot vg0/ignite-image-fedcba9876543210 --name ignite-0123456789abcdef",
+				"lvextend --size 2147483648b vg0/ignite-0123456789abcdef",
+				"lvremove --yes vg0/ignite-0123456789abcdef",
+			},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			if len(rt.failed) > 0 {
+				commands.Fail(rt.failed, fmt.Errorf("exit status 5"))
+			}
+
+			err := createVolume(rt.cfg, "ignite-0123456789abcdef", "fedcba9876543210", imagePath, imageSize, rt.size)
+			assert.Equal(t, err != nil, len(rt.failed) > 0, "unexpected error: %v", err)
+			assert.DeepEqual(t, commands.Run, rt.expected)
+		})
+	}
+}
+
+func TestActivateVolume(t *testing.T) {
+	devicePath := "/dev/vg0/ignite-0123456789abcdef"
+	activation := []string{
+		"lvchange --activate y --ignoreactivationskip " + devicePath,
+		"e2fsck -p -f -E discard " + devicePath,
+		"resize2fs " + devicePath,
+	}
+
+	// Every VM start activates the volume again, which needs to succeed
+	commands := snapshottertest.Fake(t)
+	assert.NilError(t, activateVolume(devicePath))
+	assert.NilError(t, activateVolume(devicePath))
+	assert.DeepEqual(t, commands.Run, append(activation, activation...))
+
+	// The filesystem isn't touched if the volume can't be activated
+	commands.Reset()
+	commands.Fail("lvchange", fmt.Errorf("exit status 5"))
+	assert.ErrorContains(t, activateVolume(devicePath), "exit status 5")
+	assert.DeepEqual(t, commands.Run, activation[:1])
+}
+
+func TestVolumeUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string // of lvs
+		expected meta.Size
+		err      error
+	}{
+		{
+			name:     "copy of the image",
+			output:   "  2147483648,",
+			expected: meta.NewSizeFromBytes(2 * 1024 * 1024 * 1024),
+		},
+		{
+			name:   "thin snapshot",
+			output: "  2147483648,pool",
+			err:    snapshotter.ErrUsageUnknown,
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			commands.Output("lvs", rt.output)
+
+			usage, err := volumeUsage("/dev/vg0/ignite-0123456789abcdef")
+			assert.Equal(t, err, rt.err)
+			assert.Equal(t, usage, rt.expected)
+			assert.DeepEqual(t, commands.Run, []string{
+				"lvs --noheadings --nosuffix --units b --separator , -o lv_size,pool_lv /dev/vg0/ignite-0123456789abcdef",
+			})
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package qcow2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/nbd"
+	"github.com/weaveworks/ignite/pkg/operations/lookup"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+)
+
+var (
+	// connected and connectFree find the NBD devices of the host, the tests replace them
+	connected   = nbd.Connected
+	connectFree = nbd.ConnectFree
+)
+
+// AllocateOverlay creates the overlay.qcow2 file of the VM. The overlay is backed by
+// the raw image filesystem, so it only contains the blocks the VM has written.
+func AllocateOverlay(vm *api.VM) error {
+	// Get the image UID from the VM
+	imageUID, err := lookup.ImageUIDForVM(vm, providers.Client)
+	if err != nil {
+		return err
+	}
+
+	// Get the size of the image ext4 file
+	imagePath := path.Join(constants.IMAGE_DIR, imageUID.String(), constants.IMAGE_FS)
+	fi, err := os.Stat(imagePath)
+	if err != nil {
+		return err
+	}
+	imageSize := meta.NewSizeFromBytes(uint64(fi.Size()))
+
+	size := snapshotter.DiskSize(vm.Spec.DiskSize, imageSize)
+
+	// qcow2 files grow on demand, so the overlay size limit can't be applied up front
+	if vm.Spec.OverlaySizeLimit != meta.EmptySize {
+		log.Warnf("The qcow2 snapshotter doesn't cap the overlay of VM %q, its usage is only monitored", vm.GetUID())
+	}
+
+	// Make sure the all directories above the overlay file exist
+	if err := os.MkdirAll(path.Dir(vm.OverlayFile()), constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	if err := createOverlay(imagePath, vm.OverlayFile(), size); err != nil {
+		return fmt.Errorf("failed to create qcow2 overlay for VM %q: %v", vm.GetUID(), err)
+	}
+
+	return nil
+}
+
+// ResizeOverlay grows the virtual size of the overlay.qcow2 file to the disk size of the VM
+func ResizeOverlay(vm *api.VM) error {
+	if _, err := snapshotter.ExecuteCommand("qemu-img", "resize",
+		"-f", "qcow2",
+		vm.OverlayFile(),
+		fmt.Sprintf("%d", vm.Spec.DiskSize.Bytes())); err != nil {
+		return fmt.Errorf("failed to resize qcow2 overlay for VM %q: %v", vm.GetUID(), err)
+	}
+
+	return nil
+}
+
+// ActivateSnapshot connects the qcow2 overlay of the VM to a free NBD device,
+// and returns the path of that bootable device.
+func ActivateSnapshot(vm *api.VM) (string, error) {
+	return activateOverlay(vm.ObjectPath(), vm.OverlayFile())
+}
+
+// activateOverlay connects the qcow2 file at overlayFile to a free NBD device, and records
+// the device in objectPath. An overlay that is still connected isn't connected again.
+func activateOverlay(objectPath, overlayFile string) (devicePath string, err error) {
+	// Return if the overlay is already connected
+	if devicePath, err = readDevice(objectPath); err == nil && connected(devicePath) {
+		return
+	}
+
+	if devicePath, err = connectFree(func(devicePath string) error {
+		return connectOverlay(devicePath, overlayFile)
+	}); err != nil {
+		err = fmt.Errorf("failed to connect the qcow2 overlay %q to an NBD device: %v", overlayFile, err)
+		return
+	}
+
+	if err = ioutil.WriteFile(path.Join(objectPath, constants.QCOW2_NBD_DEVICE_FILE), []byte(devicePath), 0644); err != nil {
+		return
+	}
+
+	// The discards are passed through to the overlay, which reclaims the space of files deleted by the VM
+	err = snapshotter.RepairFilesystem(devicePath)
+	return
+}
+
+// DeactivateSnapshot disconnects the qcow2 overlay of the VM from its NBD device
+func DeactivateSnapshot(vm *api.VM) error {
+	devicePath, err := SnapshotDevice(vm)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // The overlay isn't connected
+		}
+		return err
+	}
+
+	if _, err := snapshotter.ExecuteCommand("qemu-nbd", "--disconnect", devicePath); err != nil {
+		return err
+	}
+
+	return os.Remove(path.Join(vm.ObjectPath(), constants.QCOW2_NBD_DEVICE_FILE))
+}
+
+// createOverlay creates the qcow2 file at overlayFile backed by the raw image at imagePath.
+// The backing file is recorded relative to the overlay, so the overlay keeps working when the
+// data directory is moved or mounted elsewhere, e.g. together with the image on another host.
+func createOverlay(imagePath, overlayFile string, size meta.Size) error {
+	backingFile, err := filepath.Rel(filepath.Dir(overlayFile), imagePath)
+	if err != nil {
+		return err
+	}
+
+	// qemu-img resolves the relative backing file against the directory of the overlay
+	_, err = snapshotter.ExecuteCommand("qemu-img", "create",
+		"-f", "qcow2",
+		"-F", "raw",
+		"-b", backingFile,
+		overlayFile,
+		fmt.Sprintf("%d", size.Bytes()))
+	return err
+}
+
+// connectOverlay connects the qcow2 file at overlayFile to the NBD device at devicePath
+func connectOverlay(devicePath, overlayFile string) error {
+	_, err := snapshotter.ExecuteCommand("qemu-nbd",
+		"--connect="+devicePath,
+		"--format=qcow2",
+		"--discard=unmap", // Punch holes in the overlay for discarded blocks
+		"--detect-zeroes=unmap",
+		overlayFile)
+	return err
+}
+
+// SnapshotDevice returns the path of the NBD device the qcow2 overlay
+// of the VM has been connected to by ActivateSnapshot
+func SnapshotDevice(vm *api.VM) (string, error) {
+	return readDevice(vm.ObjectPath())
+}
+
+// readDevice returns the path of the NBD device recorded in objectPath
+func readDevice(objectPath string) (string, error) {
+	data, err := ioutil.ReadFile(path.Join(objectPath, constants.QCOW2_NBD_DEVICE_FILE))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
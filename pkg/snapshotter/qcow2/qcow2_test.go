@@ -0,0 +1,138 @@
+package qcow2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/snapshotter/snapshottertest"
+)
+
+func TestCommands(t *testing.T) {
+	vm := &api.VM{}
+	vm.SetUID("0123456789abcdef")
+	vm.Spec.DiskSize = meta.NewSizeFromBytes(4 * 1024 * 1024 * 1024)
+	vm.Status.Snapshotter = snapshotter.SnapshotterQcow2
+	overlay := vm.OverlayFile()
+
+	tests := []struct {
+		name     string
+		run      func() error
+		expected []string
+	}{
+		{
+			name: "create the overlay backed by the image relative to it",
+			run: func() error {
+				return createOverlay("/var/lib/firecracker/image/fedcba9876543210/image.ext4", vm.OverlayFile(), meta.NewSizeFromBytes(1024))
+			},
+			expected: []string{
+				"qemu-img create -f qcow2 -F raw -b ../image/fedcba9876543210/image.ext4 " + overlay + " 1024",
+			},
+		},
+		{
+			name: "connect the overlay passing discards through",
+			run: func() error {
+				return connectOverlay("/dev/nbd3", vm.OverlayFile())
+			},
+			expected: []string{
+				"qemu-nbd --connect=/dev/nbd3 --format=qcow2 --discard=unmap --detect-zeroes=unmap " + overlay,
+			},
+		},
+		{
+			name: "grow the overlay to the disk size",
+			run: func() error {
+				return ResizeOverlay(vm)
+			},
+			expected: []string{
+				"qemu-img resize -f qcow2 " + overlay + " 4294967296",
+			},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			assert.NilError(t, rt.run())
+			assert.DeepEqual(t, commands.Run, rt.expected)
+		})
+	}
+}
+
+func TestActivateOverlay(t *testing.T) {
+	objectPath, err := ioutil.TempDir("", "qcow2-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(objectPath)
+
+	overlay := filepath.Join(objectPath, "overlay.qcow2")
+	deviceFile := filepath.Join(objectPath, constants.QCOW2_NBD_DEVICE_FILE)
+
+	// Emulate the NBD devices of the host, nbd0 is used by another process
+	devices := map[string]bool{"/dev/nbd0": true, "/dev/nbd1": false, "/dev/nbd2": false}
+	oldConnected, oldConnectFree := connected, connectFree
+	connected = func(devicePath string) bool { return devices[devicePath] }
+	connectFree = func(connect func(devicePath string) error) (string, error) {
+		for _, devicePath := range []string{"/dev/nbd0", "/dev/nbd1", "/dev/nbd2"} {
+			if !devices[devicePath] {
+				if err := connect(devicePath); err != nil {
+					return "", err
+				}
+				devices[devicePath] = true
+				return devicePath, nil
+			}
+		}
+		return "", fmt.Errorf("all NBD devices are in use")
+	}
+	defer func() { connected, connectFree = oldConnected, oldConnectFree }()
+
+	activation := func(devicePath string) []string {
+		return []string{
+			"qemu-nbd --connect=" + devicePath + " --format=qcow2 --discard=unmap --detect-zeroes=unmap " + overlay,
+			"e2fsck -p -f -E discard " + devicePath,
+			"resize2fs " + devicePath,
+		}
+	}
+
+	commands := snapshottertest.Fake(t)
+	devicePath, err := activateOverlay(objectPath, overlay)
+	assert.NilError(t, err)
+	assert.Equal(t, devicePath, "/dev/nbd1")
+	assert.DeepEqual(t, commands.Run, activation("/dev/nbd1"))
+	data, err := ioutil.ReadFile(deviceFile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "/dev/nbd1")
+
+	// Activating the overlay again keeps its connection
+	commands.Reset()
+	devicePath, err = activateOverlay(objectPath, overlay)
+	assert.NilError(t, err)
+	assert.Equal(t, devicePath, "/dev/nbd1")
+	assert.Equal(t, len(commands.Run), 0)
+
+	// After a host reboot the recorded device isn't connected anymore
+	devices["/dev/nbd1"] = false
+	devices["/dev/nbd0"] = false
+	commands.Reset()
+	devicePath, err = activateOverlay(objectPath, overlay)
+	assert.NilError(t, err)
+	assert.Equal(t, devicePath, "/dev/nbd0")
+	assert.DeepEqual(t, commands.Run, activation("/dev/nbd0"))
+
+	// The recorded device is kept if the overlay can't be connected
+	devices["/dev/nbd0"] = false
+	commands.Reset()
+	commands.Fail("qemu-nbd", fmt.Errorf("exit status 1"))
+	_, err = activateOverlay(objectPath, overlay)
+	assert.ErrorContains(t, err, "exit status 1")
+	assert.DeepEqual(t, commands.Run, activation("/dev/nbd0")[:1])
+	data, err = ioutil.ReadFile(deviceFile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "/dev/nbd0")
+}
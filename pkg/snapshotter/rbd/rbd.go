@@ -0,0 +1,293 @@
+package rbd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/operations/lookup"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+// baseSnapshot is the name of the snapshot of the base RBD images the VMs are cloned from
+const baseSnapshot = "base"
+
+// rbdDir holds the udev managed symlinks to the mapped RBD images, the tests replace it
+var rbdDir = "/dev/rbd"
+
+// AllocateOverlay clones the base RBD image of the VM's image in the configured pool.
+// The image is imported into Ceph as a base RBD image by the first VM using it.
+func AllocateOverlay(vm *api.VM) error {
+	cfg, err := configuration()
+	if err != nil {
+		return err
+	}
+
+	// Get the image UID from the VM
+	imageUID, err := lookup.ImageUIDForVM(vm, providers.Client)
+	if err != nil {
+		return err
+	}
+
+	// Get the size of the image ext4 file
+	imagePath := path.Join(constants.IMAGE_DIR, imageUID.String(), constants.IMAGE_FS)
+	fi, err := os.Stat(imagePath)
+	if err != nil {
+		return err
+	}
+	imageSize := meta.NewSizeFromBytes(uint64(fi.Size()))
+
+	base, err := ensureBaseImage(cfg, imageUID, imagePath)
+	if err != nil {
+		return err
+	}
+
+	size := snapshotter.DiskSize(vm.Spec.DiskSize, imageSize)
+	if vm.Spec.OverlaySizeLimit != meta.EmptySize {
+		log.Warnf("The rbd snapshotter doesn't cap the overlay of VM %q", vm.GetUID())
+	}
+
+	spec := path.Join(cfg.Pool, vm.PrefixedID())
+	if err := createClone(cfg, base, spec, imageSize, size); err != nil {
+		return err
+	}
+
+	// Make sure the all directories above the overlay file exist
+	if err := os.MkdirAll(path.Dir(vm.OverlayFile()), constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	// Record the RBD image of the VM, the configuration of the host might change over
+	// the VM's lifetime. The user is needed as well to map the image on another host.
+	return ioutil.WriteFile(vm.OverlayFile(), []byte(fmt.Sprintf("%s\n%s", spec, cfg.User)), 0644)
+}
+
+// ResizeOverlay grows the RBD image of the VM to its disk size
+func ResizeOverlay(vm *api.VM) error {
+	spec, cfg, err := snapshotImage(vm)
+	if err != nil {
+		return err
+	}
+
+	_, err = rbd(cfg, "resize", "--size", sizeArg(vm.Spec.DiskSize), spec)
+	return err
+}
+
+// ActivateSnapshot maps the RBD image of the VM on the host, and returns its path
+func ActivateSnapshot(vm *api.VM) (string, error) {
+	spec, cfg, err := snapshotImage(vm)
+	if err != nil {
+		return "", err
+	}
+
+	return mapImage(cfg, spec)
+}
+
+// DeactivateSnapshot unmaps the RBD image of the VM from the host, so that
+// the VM can be started on another host. The mapping can only be seen on the
+// host, inside of the VM container this is a no-op.
+func DeactivateSnapshot(vm *api.VM) error {
+	spec, cfg, err := snapshotImage(vm)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // The image has never been created
+		}
+		return err
+	}
+
+	if !util.FileExists(rbdPath(spec)) {
+		return nil // The image isn't mapped
+	}
+
+	_, err = rbd(cfg, "unmap", spec)
+	return err
+}
+
+// RemoveSnapshot removes the RBD image of the VM from Ceph
+func RemoveSnapshot(vm *api.VM) error {
+	spec, cfg, err := snapshotImage(vm)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // The image has never been created
+		}
+		return err
+	}
+
+	_, err = rbd(cfg, "rm", spec)
+	return err
+}
+
+// SnapshotDevice returns the path of the mapped RBD image of the VM
+func SnapshotDevice(vm *api.VM) (string, error) {
+	spec, _, err := snapshotImage(vm)
+	if err != nil {
+		return "", err
+	}
+
+	return rbdPath(spec), nil
+}
+
+// snapshotImage returns the pool/image spec of the RBD image of the VM,
+// together with the configuration it has been created with
+func snapshotImage(vm *api.VM) (string, api.RBDConfiguration, error) {
+	var cfg api.RBDConfiguration
+	data, err := ioutil.ReadFile(vm.OverlayFile())
+	if err != nil {
+		return "", cfg, err
+	}
+
+	lines := strings.SplitN(strings.TrimSpace(string(data)), "\n", 2)
+	spec := lines[0]
+	cfg.Pool = path.Dir(spec)
+	if len(lines) > 1 {
+		cfg.User = strings.TrimSpace(lines[1])
+	}
+
+	return spec, cfg, nil
+}
+
+// createClone clones the base snapshot of the base RBD image into the RBD image spec of the given size
+func createClone(cfg api.RBDConfiguration, base, spec string, imageSize, size meta.Size) (err error) {
+	if _, err = rbd(cfg, "clone", base+"@"+baseSnapshot, spec); err != nil {
+		return
+	}
+	defer removeOnError(&err, cfg, spec)
+
+	if size.Bytes() > imageSize.Bytes() {
+		_, err = rbd(cfg, "resize", "--size", sizeArg(size), spec)
+	}
+
+	return
+}
+
+// ensureBaseImage imports the image into Ceph and creates the protected snapshot
+// the VMs are cloned from if that hasn't been done yet, and returns the image spec.
+// A partially imported image is removed again, as it would otherwise block the
+// import for the next VM.
+func ensureBaseImage(cfg api.RBDConfiguration, imageUID runtime.UID, imagePath string) (spec string, err error) {
+	spec = path.Join(cfg.Pool, util.NewPrefixer(providers.IDPrefix).Prefix("image", imageUID))
+	if _, err = rbd(cfg, "info", spec+"@"+baseSnapshot); err == nil {
+		return
+	}
+
+	log.Infof("Importing image %q into Ceph pool %q...", imageUID, cfg.Pool)
+	if _, err = rbd(cfg, "import", "--image-feature", "layering", imagePath, spec); err != nil {
+		return
+	}
+	defer removeOnError(&err, cfg, spec)
+
+	if _, err = rbd(cfg, "snap", "create", spec+"@"+baseSnapshot); err != nil {
+		return
+	}
+
+	// Clones can only be created from protected snapshots
+	_, err = rbd(cfg, "snap", "protect", spec+"@"+baseSnapshot)
+	return
+}
+
+// removeOnError removes the RBD image spec together with its snapshots if *err is set.
+// The snapshots of a failed import aren't protected yet, so they can be purged.
+func removeOnError(err *error, cfg api.RBDConfiguration, spec string) {
+	if *err == nil {
+		return
+	}
+
+	if _, rmErr := rbd(cfg, "snap", "purge", spec); rmErr != nil {
+		log.Warnf("Failed to remove the snapshots of RBD image %q: %v", spec, rmErr)
+	} else if _, rmErr := rbd(cfg, "rm", spec); rmErr != nil {
+		log.Warnf("Failed to remove RBD image %q: %v", spec, rmErr)
+	}
+}
+
+// mapImage maps the RBD image spec on the host unless that has been done already,
+// as mapping it again would add a second device for it, and prepares its filesystem.
+// The discards free the space of files deleted by the VM in the Ceph cluster.
+func mapImage(cfg api.RBDConfiguration, spec string) (string, error) {
+	devicePath := rbdPath(spec)
+	if !util.FileExists(devicePath) {
+		if _, err := rbd(cfg, "map", spec); err != nil {
+			return "", err
+		}
+	}
+
+	return devicePath, snapshotter.RepairFilesystem(devicePath)
+}
+
+// rbd runs the rbd command as the configured Ceph user
+func rbd(cfg api.RBDConfiguration, args ...string) (string, error) {
+	if len(cfg.User) > 0 {
+		args = append([]string{"--id", cfg.User}, args...)
+	}
+
+	return snapshotter.ExecuteCommand("rbd", args...)
+}
+
+// configuration returns the RBD configuration of the host
+func configuration() (api.RBDConfiguration, error) {
+	var cfg api.RBDConfiguration
+	if providers.ComponentConfig != nil {
+		cfg = providers.ComponentConfig.Spec.RBD
+	}
+
+	if len(cfg.Pool) == 0 {
+		return cfg, fmt.Errorf("no Ceph pool configured, set spec.rbd.pool in the ignite configuration")
+	}
+
+	return cfg, nil
+}
+
+// rbdPath returns the path of the udev managed symlink to a mapped RBD image
+func rbdPath(spec string) string {
+	return path.Join(rbdDir, spec)
+}
+
+// sizeArg formats the size for rbd, which defaults to megabytes
+func sizeArg(size meta.Size) string {
+	return fmt.Sprintf("%d", (size.Bytes()+constants.MB-1)/constants.MB)
+}
+
+// OverlayUsage returns the space used by the RBD image of the VM. The image is a clone
+// of the imported image, so this is only the space of the objects the VM has written.
+func OverlayUsage(vm *api.VM) (meta.Size, error) {
+	spec, cfg, err := snapshotImage(vm)
+	if err != nil {
+		return meta.EmptySize, err
+	}
+
+	return imageUsage(cfg, spec)
+}
+
+// imageUsage returns the space used by the RBD image spec
+func imageUsage(cfg api.RBDConfiguration, spec string) (meta.Size, error) {
+	out, err := rbd(cfg, "du", "--format", "json", spec)
+	if err != nil {
+		return meta.EmptySize, err
+	}
+
+	var du struct {
+		Images []struct {
+			UsedSize uint64 `json:"used_size"`
+		} `json:"images"`
+	}
+	if err := json.Unmarshal([]byte(out), &du); err != nil {
+		return meta.EmptySize, fmt.Errorf("failed to parse the space used by RBD image %q: %v", spec, err)
+	}
+
+	var used uint64
+	for _, image := range du.Images {
+		used += image.UsedSize
+	}
+
+	return meta.NewSizeFromBytes(used), nil
+}
@@ -0,0 +1,214 @@
+package rbd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter/snapshottertest"
+)
+
+func TestEnsureBaseImage(t *testing.T) {
+	oldPrefix := providers.IDPrefix
+	providers.IDPrefix = "ignite"
+	defer func() { providers.IDPrefix = oldPrefix }()
+
+	imagePath := "/var/lib/firecracker/image/fedcba9876543210/image.ext4"
+	spec := "vms/ignite-image-fedcba9876543210"
+
+	tests := []struct {
+		name     string
+		cfg      api.RBDConfiguration
+		imported bool
+		failed   string // command that fails
+		expected []string
+	}{
+		{
+			name: "import the image",
+			cfg:  api.RBDConfiguration{Pool: "vms"},
+			expected: []string{
+				"rbd info " + spec + "@base",
+				"rbd import --image-feature layering " + imagePath + " " + spec,
+				"rbd snap create " + spec + "@base",
+				"rbd snap protect " + spec + "@base",
+			},
+		},
+		{
+			name: "import the image as a Ceph user",
+			cfg:  api.RBDConfiguration{Pool: "vms", User: "ignite"},
+			expected: []string{
+				"rbd --id ignite info " + spec + "@base",
+				"rbd --id ignite import --image-feature layering " + imagePath + " " + spec,
+				"rbd --id ignite snap create " + spec + "@base",
+				"rbd --id ignite snap protect " + spec + "@base",
+			},
+		},
+		{
+			name:     "imported image",
+			cfg:      api.RBDConfiguration{Pool: "vms"},
+			imported: true,
+			expected: []string{
+				"rbd info " + spec + "@base",
+			},
+		},
+		{
+			name:   "failed import of the image",
+			cfg:    api.RBDConfiguration{Pool: "vms"},
+			failed: "rbd import",
+			expected: []string{
+				"rbd info " + spec + "@base",
+				"rbd import --image-feature layering " + imagePath + " " + spec,
+			},
+		},
+		{
+			// The imported image would make the import fail for the next VM
+			name:   "failed protection of the snapshot",
+			cfg:    api.RBDConfiguration{Pool: "vms"},
+			failed: "rbd snap protect",
+			expected: []string{
+				"rbd info " + spec + "@base",
+				"rbd import --image-feature layering " + imagePath + " " + spec,
+				"rbd snap create " + spec + "@base",
+				"rbd snap protect " + spec + "@base",
+				"rbd snap purge " + spec,
+				"rbd rm " + spec,
+			},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			if !rt.imported {
+				commands.Handle("rbd", func(args []string) (string, error) {
+					if args[len(args)-2] == "info" {
+						return "", fmt.Errorf("error opening image: (2) No such file or directory")
+					}
+					return "", nil
+				})
+			}
+			if len(rt.failed) > 0 {
+				commands.Fail(rt.failed, fmt.Errorf("exit status 1"))
+			}
+
+			base, err := ensureBaseImage(rt.cfg, "fedcba9876543210", imagePath)
+			assert.DeepEqual(t, commands.Run, rt.expected)
+			if len(rt.failed) > 0 {
+				assert.ErrorContains(t, err, "exit status 1")
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, base, spec)
+		})
+	}
+}
+
+func TestCreateClone(t *testing.T) {
+	imageSize := meta.NewSizeFromBytes(1024 * 1024 * 1024)
+	cfg := api.RBDConfiguration{Pool: "vms", User: "ignite"}
+
+	tests := []struct {
+		name     string
+		size     meta.Size
+		failed   string // command that fails
+		expected []string
+	}{
+		{
+			name: "size of the image",
+			size: imageSize,
+			expected: []string{
+				"rbd --id ignite clone vms/ignite-image-fedcba9876543210@base vms/ignite-0123456789abcdef",
+			},
+		},
+		{
+			name: "larger than the image",
+			size: meta.NewSizeFromBytes(2 * 1024 * 1024 * 1024),
+			expected: []string{
+				"rbd --id ignite clone vms/ignite-image-fedcba9876543210@base vms/ignite-0123456789abcdef",
+				"rbd --id ignite resize --size 2048 vms/ignite-0123456789abcdef",
+			},
+		},
+		{
+			name:   "failed resize of the clone",
+			size:   meta.NewSizeFromBytes(2 * 1024 * 1024 * 1024),
+			failed: "rbd --id ignite resize",
+			expected: []string{
+				"rbd --id ignite clone vms/ignite-image-fedcba9876543210@base vms/ignite-0123456789abcdef",
+				"rbd --id ignite resize --size 2048 vms/ignite-0123456789abcdef",
+				"rbd --id ignite snap purge vms/ignite-0123456789abcdef",
+				"rbd --id ignite rm vms/ignite-0123456789abcdef",
+			},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			if len(rt.failed) > 0 {
+				commands.Fail(rt.failed, fmt.Errorf("exit status 1"))
+			}
+
+			err := createClone(cfg, "vms/ignite-image-fedcba9876543210", "vms/ignite-0123456789abcdef", imageSize, rt.size)
+			assert.Equal(t, err != nil, len(rt.failed) > 0, "unexpected error: %v", err)
+			assert.DeepEqual(t, commands.Run, rt.expected)
+		})
+	}
+}
+
+func TestMapImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbd-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	oldRBDDir := rbdDir
+	rbdDir = dir
+	defer func() { rbdDir = oldRBDDir }()
+
+	cfg := api.RBDConfiguration{Pool: "vms"}
+	spec := "vms/ignite-0123456789abcdef"
+	devicePath := filepath.Join(dir, spec)
+
+	commands := snapshottertest.Fake(t)
+	commands.Handle("rbd map", func(args []string) (string, error) {
+		// Emulate udev creating the symlink to the mapped image
+		assert.NilError(t, os.MkdirAll(filepath.Dir(devicePath), 0755))
+		return "/dev/rbd0\n", ioutil.WriteFile(devicePath, nil, 0644)
+	})
+
+	mapped, err := mapImage(cfg, spec)
+	assert.NilError(t, err)
+	assert.Equal(t, mapped, devicePath)
+	assert.DeepEqual(t, commands.Run, []string{
+		"rbd map " + spec,
+		"e2fsck -p -f -E discard " + devicePath,
+		"resize2fs " + devicePath,
+	})
+
+	// Activating the image again doesn't map it a second time
+	commands.Reset()
+	mapped, err = mapImage(cfg, spec)
+	assert.NilError(t, err)
+	assert.Equal(t, mapped, devicePath)
+	assert.DeepEqual(t, commands.Run, []string{
+		"e2fsck -p -f -E discard " + devicePath,
+		"resize2fs " + devicePath,
+	})
+}
+
+func TestImageUsage(t *testing.T) {
+	commands := snapshottertest.Fake(t)
+	commands.Output("rbd du", `{"images":[{"name":"ignite-0123456789abcdef","used_size":4194304},{"name":"ignite-0123456789abcdef","snapshot":"s1","used_size":1048576}]}`)
+
+	usage, err := imageUsage(api.RBDConfiguration{Pool: "vms"}, "vms/ignite-0123456789abcdef")
+	assert.NilError(t, err)
+	assert.Equal(t, usage, meta.NewSizeFromBytes(5*1024*1024))
+	assert.DeepEqual(t, commands.Run, []string{"rbd du --format json vms/ignite-0123456789abcdef"})
+}
@@ -0,0 +1,180 @@
+package reflink
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	losetup "github.com/freddierice/go-losetup"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/operations/lookup"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// sysBlockDir holds the sysfs directories of the block devices, the tests replace it
+var sysBlockDir = "/sys/block"
+
+// AllocateOverlay clones the image filesystem into the overlay.raw file of the VM.
+// The data directory needs to be on a reflink-capable filesystem (like XFS and btrfs),
+// where the clone shares all blocks with the image, so it's created instantly and only
+// the blocks written by the VM take up space. The image isn't copied on other filesystems,
+// as every VM would take up the space of the whole image.
+func AllocateOverlay(vm *api.VM) error {
+	// Get the image UID from the VM
+	imageUID, err := lookup.ImageUIDForVM(vm, providers.Client)
+	if err != nil {
+		return err
+	}
+
+	imagePath := path.Join(constants.IMAGE_DIR, imageUID.String(), constants.IMAGE_FS)
+	fi, err := os.Stat(imagePath)
+	if err != nil {
+		return err
+	}
+	imageSize := meta.NewSizeFromBytes(uint64(fi.Size()))
+
+	size := snapshotter.DiskSize(vm.Spec.DiskSize, imageSize)
+
+	// The clone is a regular sparse file, so its allocation can't be capped up front
+	if vm.Spec.OverlaySizeLimit != meta.EmptySize {
+		log.Warnf("The reflink snapshotter doesn't cap the overlay of VM %q", vm.GetUID())
+	}
+
+	// Make sure the all directories above the overlay file exist
+	if err := os.MkdirAll(path.Dir(vm.OverlayFile()), constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	if err := cloneFile(imagePath, vm.OverlayFile()); err != nil {
+		if errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.EXDEV) || errors.Is(err, unix.EINVAL) {
+			return fmt.Errorf("the data directory doesn't support reflinks, use the dmlegacy or qcow2 snapshotter to share the image between VMs: %v", err)
+		}
+
+		return fmt.Errorf("failed to clone the image for VM %q: %v", vm.GetUID(), err)
+	}
+
+	// Grow the clone to the requested disk size, ActivateSnapshot resizes the filesystem
+	if err := os.Truncate(vm.OverlayFile(), int64(size.Bytes())); err != nil {
+		return fmt.Errorf("failed to resize overlay file for VM %q: %v", vm.GetUID(), err)
+	}
+
+	return nil
+}
+
+// ResizeOverlay grows the overlay.raw file of the VM to its disk size
+func ResizeOverlay(vm *api.VM) error {
+	if err := os.Truncate(vm.OverlayFile(), int64(vm.Spec.DiskSize.Bytes())); err != nil {
+		return fmt.Errorf("failed to resize overlay file for VM %q: %v", vm.GetUID(), err)
+	}
+
+	return nil
+}
+
+// ActivateSnapshot attaches the overlay.raw file of the VM to a loop device,
+// and returns the path of that bootable device.
+func ActivateSnapshot(vm *api.VM) (string, error) {
+	// Return if the overlay is already attached
+	if devicePath, err := SnapshotDevice(vm); err == nil && attached(devicePath, vm.OverlayFile()) {
+		return devicePath, nil
+	}
+
+	device, err := losetup.Attach(vm.OverlayFile(), 0, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to setup loop device for %q: %v", vm.OverlayFile(), err)
+	}
+	devicePath := device.Path()
+
+	if err := ioutil.WriteFile(path.Join(vm.ObjectPath(), constants.REFLINK_LOOP_DEVICE_FILE), []byte(devicePath), 0644); err != nil {
+		return "", err
+	}
+
+	// The loop device punches holes in the overlay for the discards,
+	// which reclaims the space of files deleted by the VM
+	return devicePath, snapshotter.RepairFilesystem(devicePath)
+}
+
+// DeactivateSnapshot detaches the overlay.raw file of the VM from its loop device.
+// This only needs the loop device itself, so it can also be called from inside the
+// VM container.
+func DeactivateSnapshot(vm *api.VM) error {
+	devicePath, err := SnapshotDevice(vm)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // The overlay isn't attached
+		}
+		return err
+	}
+
+	if attached(devicePath, vm.OverlayFile()) {
+		if err := detach(devicePath); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(path.Join(vm.ObjectPath(), constants.REFLINK_LOOP_DEVICE_FILE))
+}
+
+// SnapshotDevice returns the path of the loop device the overlay.raw
+// file of the VM has been attached to by ActivateSnapshot
+func SnapshotDevice(vm *api.VM) (string, error) {
+	data, err := ioutil.ReadFile(path.Join(vm.ObjectPath(), constants.REFLINK_LOOP_DEVICE_FILE))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// cloneFile creates dst as a reflink of src using the FICLONE ioctl
+func cloneFile(src, dst string) (err error) {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return
+	}
+	defer util.DeferErr(&err, dstFile.Close)
+
+	if err = unix.IoctlFileClone(int(dstFile.Fd()), int(srcFile.Fd())); err != nil {
+		// Don't leave an empty overlay behind
+		_ = os.Remove(dst)
+	}
+
+	return
+}
+
+// attached reports whether the given loop device is backed by the given file.
+// The loop device might have been reused for another file after a host reboot.
+func attached(devicePath, file string) bool {
+	data, err := ioutil.ReadFile(path.Join(sysBlockDir, path.Base(devicePath), "loop", "backing_file"))
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(string(data)) == file
+}
+
+// detach clears the backing file of the given loop device
+func detach(devicePath string) error {
+	f, err := os.OpenFile(devicePath, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return unix.IoctlSetInt(int(f.Fd()), unix.LOOP_CLR_FD, 0)
+}
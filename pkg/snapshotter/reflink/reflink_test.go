@@ -0,0 +1,60 @@
+package reflink
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestAttached(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reflink-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	oldSysBlockDir := sysBlockDir
+	sysBlockDir = dir
+	defer func() { sysBlockDir = oldSysBlockDir }()
+
+	// loop0 is backed by the overlay, loop1 has been reused for another file after a reboot
+	overlay := "/var/lib/firecracker/vm/0123456789abcdef/overlay.raw"
+	for device, file := range map[string]string{"loop0": overlay, "loop1": "/var/lib/images/disk.raw"} {
+		assert.NilError(t, os.MkdirAll(filepath.Join(dir, device, "loop"), 0755))
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, device, "loop", "backing_file"), []byte(file+"\n"), 0644))
+	}
+
+	tests := []struct {
+		devicePath string
+		expected   bool
+	}{
+		{"/dev/loop0", true},
+		{"/dev/loop1", false},
+		{"/dev/loop2", false}, // detached
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.devicePath, func(t *testing.T) {
+			assert.Equal(t, attached(rt.devicePath, overlay), rt.expected)
+		})
+	}
+}
+
+func TestCloneFileCleanup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reflink-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	assert.NilError(t, ioutil.WriteFile(src, []byte("image"), 0644))
+
+	// Without reflink support the clone fails, and doesn't leave an empty overlay behind
+	dst := filepath.Join(dir, "dst")
+	if err := cloneFile(src, dst); err == nil {
+		t.Skip("the temporary directory supports reflinks")
+	}
+
+	_, err = os.Stat(dst)
+	assert.Assert(t, os.IsNotExist(err), "the overlay exists: %v", err)
+}
@@ -0,0 +1,87 @@
+package reflink
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+// The FIEMAP ioctl, see linux/fiemap.h
+const (
+	fsIocFiemap        = 0xc020660b
+	fiemapExtentLast   = 0x1
+	fiemapExtentShared = 0x2000
+	fiemapFlagSync     = 0x1
+	fiemapBatch        = 256
+)
+
+type fiemap struct {
+	start         uint64
+	length        uint64
+	flags         uint32
+	mappedExtents uint32
+	extentCount   uint32
+	reserved      uint32
+}
+
+type fiemapExtent struct {
+	logical    uint64
+	physical   uint64
+	length     uint64
+	reserved64 [2]uint64
+	flags      uint32
+	reserved   [3]uint32
+}
+
+// fiemapRequest is a FIEMAP request with room for a batch of extents
+type fiemapRequest struct {
+	fiemap
+	extents [fiemapBatch]fiemapExtent
+}
+
+// OverlayUsage returns the amount of host disk space only used by the overlay.raw file
+// of the VM, i.e. its extents that aren't shared with the image anymore
+func OverlayUsage(vm *api.VM) (meta.Size, error) {
+	unique, err := uniqueBytes(vm.OverlayFile())
+	if err != nil {
+		return meta.EmptySize, err
+	}
+
+	return meta.NewSizeFromBytes(unique), nil
+}
+
+// uniqueBytes sums up the extents of the file that aren't shared with other files
+func uniqueBytes(file string) (unique uint64, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var req fiemapRequest
+	for start := uint64(0); ; {
+		req.fiemap = fiemap{start: start, length: ^uint64(0), flags: fiemapFlagSync, extentCount: fiemapBatch}
+		if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), fsIocFiemap, uintptr(unsafe.Pointer(&req))); errno != 0 {
+			return 0, &os.PathError{Op: "fiemap", Path: file, Err: errno}
+		}
+
+		if req.mappedExtents == 0 {
+			return
+		}
+
+		for _, extent := range req.extents[:req.mappedExtents] {
+			if extent.flags&fiemapExtentShared == 0 {
+				unique += extent.length
+			}
+
+			if extent.flags&fiemapExtentLast != 0 {
+				return
+			}
+			start = extent.logical + extent.length
+		}
+	}
+}
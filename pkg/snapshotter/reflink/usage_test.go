@@ -0,0 +1,45 @@
+package reflink
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/sys/unix"
+	"gotest.tools/assert"
+)
+
+func TestUniqueBytes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reflink-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	// A file of 64 KiB followed by a 1 MiB hole, none of its extents are shared
+	src := filepath.Join(dir, "src")
+	data := make([]byte, 64*1024)
+	for i := range data {
+		data[i] = 1
+	}
+	assert.NilError(t, ioutil.WriteFile(src, data, 0644))
+	assert.NilError(t, os.Truncate(src, 64*1024+1024*1024))
+
+	unique, err := uniqueBytes(src)
+	if errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.ENOTTY) {
+		t.Skip("the temporary directory doesn't support FIEMAP")
+	}
+	assert.NilError(t, err)
+	assert.Equal(t, unique, uint64(len(data)))
+
+	// All extents of a clone are shared until they're written
+	dst := filepath.Join(dir, "dst")
+	if err := cloneFile(src, dst); err != nil {
+		t.Logf("the temporary directory doesn't support reflinks: %v", err)
+		return
+	}
+
+	unique, err = uniqueBytes(dst)
+	assert.NilError(t, err)
+	assert.Equal(t, unique, uint64(0))
+}
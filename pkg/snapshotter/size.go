@@ -0,0 +1,19 @@
+package snapshotter
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+// DiskSize returns the size of the disk created for a VM requesting the given size.
+// The disk needs to hold the image, so it's at least as large as the image.
+func DiskSize(requested, imageSize meta.Size) meta.Size {
+	if requested.Bytes() < imageSize.Bytes() {
+		log.Warnf("warning: requested overlay size (%s) < image size (%s), using image size for overlay\n",
+			requested.String(), imageSize.String())
+		return imageSize
+	}
+
+	return requested
+}
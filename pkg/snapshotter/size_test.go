@@ -0,0 +1,45 @@
+package snapshotter
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+func TestDiskSize(t *testing.T) {
+	imageSize := meta.NewSizeFromBytes(1024 * 1024 * 1024)
+
+	tests := []struct {
+		name      string
+		requested meta.Size
+		expected  meta.Size
+	}{
+		{
+			name:      "larger than the image",
+			requested: meta.NewSizeFromBytes(4 * 1024 * 1024 * 1024),
+			expected:  meta.NewSizeFromBytes(4 * 1024 * 1024 * 1024),
+		},
+		{
+			name:      "as large as the image",
+			requested: imageSize,
+			expected:  imageSize,
+		},
+		{
+			name:      "smaller than the image",
+			requested: meta.NewSizeFromBytes(512 * 1024 * 1024),
+			expected:  imageSize,
+		},
+		{
+			name:     "unset",
+			expected: imageSize,
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			assert.Equal(t, DiskSize(rt.requested, imageSize), rt.expected)
+		})
+	}
+}
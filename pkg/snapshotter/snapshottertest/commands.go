@@ -0,0 +1,69 @@
+// Package snapshottertest fakes the storage tools run by the snapshotters in their tests
+package snapshottertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// Commands replaces snapshotter.ExecuteCommand in a test. It records the commands
+// run by the snapshotters, and answers them with the handlers registered for them.
+// Commands without a handler succeed without output.
+type Commands struct {
+	// Run holds the commands run so far, as command lines joined by spaces
+	Run []string
+
+	handlers []handler
+}
+
+type handler struct {
+	prefix string
+	run    func(args []string) (string, error)
+}
+
+// Fake replaces snapshotter.ExecuteCommand until the end of the test
+func Fake(t *testing.T) *Commands {
+	c := &Commands{}
+	snapshotter.ExecuteCommand = c.execute
+	t.Cleanup(func() { snapshotter.ExecuteCommand = util.ExecuteCommand })
+
+	return c
+}
+
+// Handle answers the command lines starting with prefix with run, which gets the
+// command and its arguments. Handlers registered later take precedence.
+func (c *Commands) Handle(prefix string, run func(args []string) (string, error)) {
+	c.handlers = append(c.handlers, handler{prefix, run})
+}
+
+// Output makes the command lines starting with prefix print output
+func (c *Commands) Output(prefix, output string) {
+	c.Handle(prefix, func([]string) (string, error) { return output, nil })
+}
+
+// Fail makes the command lines starting with prefix fail with err
+func (c *Commands) Fail(prefix string, err error) {
+	c.Handle(prefix, func([]string) (string, error) { return "", err })
+}
+
+// Reset forgets the commands run so far, the handlers are kept
+func (c *Commands) Reset() {
+	c.Run = nil
+}
+
+func (c *Commands) execute(command string, args ...string) (string, error) {
+	args = append([]string{command}, args...)
+	line := strings.Join(args, " ")
+	c.Run = append(c.Run, line)
+
+	for i := len(c.handlers) - 1; i >= 0; i-- {
+		if strings.HasPrefix(line, c.handlers[i].prefix) {
+			return c.handlers[i].run(args)
+		}
+	}
+
+	return "", nil
+}
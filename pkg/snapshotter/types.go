@@ -0,0 +1,57 @@
+package snapshotter
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUsageUnknown is returned by the snapshotters that can't tell how much space
+// the disk of a VM uses on its own, apart from the image it shares
+var ErrUsageUnknown = errors.New("the snapshotter can't tell the usage of the disk apart from its image")
+
+// Name defines a name for a snapshotter, the backend providing
+// the writable disk of a VM on top of its base image
+type Name string
+
+var _ fmt.Stringer = Name("")
+
+func (n Name) String() string {
+	return string(n)
+}
+
+const (
+	// SnapshotterDMLegacy specifies the device-mapper snapshot backend,
+	// where the overlay.dm file holds the writes of the VM
+	SnapshotterDMLegacy Name = "dmlegacy"
+	// SnapshotterDMThin specifies the device-mapper thin provisioning backend, where
+	// images and VMs are thin volumes and snapshots in a pool shared by all VMs
+	SnapshotterDMThin Name = "dmthin"
+	// SnapshotterLVM specifies the LVM backend, where VM disks are logical volumes
+	// (or thin snapshots) in the volume group configured for the host
+	SnapshotterLVM Name = "lvm"
+	// SnapshotterReflink specifies the reflink backend, where the disk of the VM is a
+	// reflinked copy of the image, which is instant on XFS and btrfs data directories
+	SnapshotterReflink Name = "reflink"
+	// SnapshotterRBD specifies the Ceph RBD backend, where VM disks are clones of
+	// the imported images in a Ceph pool, which makes them available to all hosts
+	SnapshotterRBD Name = "rbd"
+	// SnapshotterZFS specifies the ZFS backend, where images are imported into zvols
+	// and VM disks are clones of them, in the dataset configured for the host
+	SnapshotterZFS Name = "zfs"
+	// SnapshotterQcow2 specifies the qcow2 backend, where the writes of the VM
+	// are stored in a qcow2 file backed by the image, exposed over NBD
+	SnapshotterQcow2 Name = "qcow2"
+)
+
+// ListSnapshotters gets the list of available snapshotters
+func ListSnapshotters() []Name {
+	return []Name{
+		SnapshotterDMLegacy,
+		SnapshotterDMThin,
+		SnapshotterLVM,
+		SnapshotterQcow2,
+		SnapshotterRBD,
+		SnapshotterReflink,
+		SnapshotterZFS,
+	}
+}
@@ -0,0 +1,269 @@
+package zfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/operations/lookup"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter"
+	"github.com/weaveworks/ignite/pkg/util"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+)
+
+const (
+	// baseSnapshot is the name of the snapshot of the image zvols the VMs are cloned from
+	baseSnapshot = "base"
+
+	// zvolTimeout determines how long to wait for udev to create the device node of a zvol
+	zvolTimeout = 10 * time.Second
+)
+
+// zvolDir holds the device nodes udev creates for zvols, the tests replace it
+var zvolDir = "/dev/zvol"
+
+// AllocateOverlay clones the zvol of the VM's image in the configured dataset.
+// The image is imported into a sparse zvol by the first VM using it.
+func AllocateOverlay(vm *api.VM) error {
+	cfg, err := configuration()
+	if err != nil {
+		return err
+	}
+
+	// Get the image UID from the VM
+	imageUID, err := lookup.ImageUIDForVM(vm, providers.Client)
+	if err != nil {
+		return err
+	}
+
+	// Get the size of the image ext4 file
+	imagePath := path.Join(constants.IMAGE_DIR, imageUID.String(), constants.IMAGE_FS)
+	fi, err := os.Stat(imagePath)
+	if err != nil {
+		return err
+	}
+	imageSize := meta.NewSizeFromBytes(uint64(fi.Size()))
+
+	base, err := ensureImageVolume(cfg, imageUID, imagePath, imageSize)
+	if err != nil {
+		return err
+	}
+
+	size := snapshotter.DiskSize(vm.Spec.DiskSize, imageSize)
+	if vm.Spec.OverlaySizeLimit != meta.EmptySize {
+		log.Warnf("The zfs snapshotter doesn't cap the overlay of VM %q", vm.GetUID())
+	}
+
+	volume := path.Join(cfg.Dataset, vm.PrefixedID())
+	if err := createClone(base, volume, imageSize, size); err != nil {
+		return err
+	}
+
+	// Make sure the all directories above the overlay file exist
+	if err := os.MkdirAll(path.Dir(vm.OverlayFile()), constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	// Record the zvol of the VM, the configuration of the host might change over the VM's lifetime
+	return ioutil.WriteFile(vm.OverlayFile(), []byte(volume), 0644)
+}
+
+// ResizeOverlay grows the zvol of the VM to its disk size
+func ResizeOverlay(vm *api.VM) error {
+	volume, err := snapshotVolume(vm)
+	if err != nil {
+		return err
+	}
+
+	_, err = snapshotter.ExecuteCommand("zfs", "set", "volsize="+sizeArg(vm.Spec.DiskSize), volume)
+	return err
+}
+
+// ActivateSnapshot waits for the zvol of the VM to be available, and returns its path
+func ActivateSnapshot(vm *api.VM) (string, error) {
+	volume, err := snapshotVolume(vm)
+	if err != nil {
+		return "", err
+	}
+
+	devicePath := zvolPath(volume)
+	return devicePath, activateVolume(devicePath)
+}
+
+// DeactivateSnapshot is a no-op, zvols are always available as block devices
+func DeactivateSnapshot(_ *api.VM) error {
+	return nil
+}
+
+// RemoveSnapshot destroys the zvol of the VM
+func RemoveSnapshot(vm *api.VM) error {
+	volume, err := snapshotVolume(vm)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // The zvol has never been created
+		}
+		return err
+	}
+
+	_, err = snapshotter.ExecuteCommand("zfs", "destroy", volume)
+	return err
+}
+
+// SnapshotDevice returns the path of the zvol of the VM
+func SnapshotDevice(vm *api.VM) (string, error) {
+	volume, err := snapshotVolume(vm)
+	if err != nil {
+		return "", err
+	}
+
+	return zvolPath(volume), nil
+}
+
+// snapshotVolume returns the name of the zvol of the VM
+func snapshotVolume(vm *api.VM) (string, error) {
+	data, err := ioutil.ReadFile(vm.OverlayFile())
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// createClone clones the base snapshot of the image zvol base into the zvol volume of the given size
+func createClone(base, volume string, imageSize, size meta.Size) (err error) {
+	if _, err = snapshotter.ExecuteCommand("zfs", "clone", base+"@"+baseSnapshot, volume); err != nil {
+		return
+	}
+	defer destroyOnError(&err, volume)
+
+	if size.Bytes() > imageSize.Bytes() {
+		_, err = snapshotter.ExecuteCommand("zfs", "set", "volsize="+sizeArg(size), volume)
+	}
+
+	return
+}
+
+// ensureImageVolume imports the image into a sparse zvol and snapshots it if that
+// hasn't been done yet, and returns the name of the zvol. A partially imported zvol
+// is destroyed again, as it would otherwise block the import for the next VM.
+func ensureImageVolume(cfg api.ZFSConfiguration, imageUID runtime.UID, imagePath string, imageSize meta.Size) (volume string, err error) {
+	volume = path.Join(cfg.Dataset, util.NewPrefixer(providers.IDPrefix).Prefix("image", imageUID))
+	if _, err = snapshotter.ExecuteCommand("zfs", "list", "-t", "snapshot", volume+"@"+baseSnapshot); err == nil {
+		return
+	}
+
+	log.Infof("Importing image %q into ZFS dataset %q...", imageUID, cfg.Dataset)
+	if _, err = snapshotter.ExecuteCommand("zfs", "create", "-s", "-V", sizeArg(imageSize), volume); err != nil {
+		return
+	}
+	defer destroyOnError(&err, volume)
+
+	devicePath := zvolPath(volume)
+	if err = waitForZvol(devicePath); err != nil {
+		return
+	}
+
+	// A new sparse zvol reads as zeros, so only the non-zero blocks need to be written
+	if _, err = snapshotter.ExecuteCommand("dd", "if="+imagePath, "of="+devicePath, "bs=1M", "conv=sparse"); err != nil {
+		return
+	}
+
+	_, err = snapshotter.ExecuteCommand("zfs", "snapshot", volume+"@"+baseSnapshot)
+	return
+}
+
+// destroyOnError destroys the zvol if *err is set
+func destroyOnError(err *error, volume string) {
+	if *err == nil {
+		return
+	}
+
+	if _, destroyErr := snapshotter.ExecuteCommand("zfs", "destroy", volume); destroyErr != nil {
+		log.Warnf("Failed to destroy zvol %q: %v", volume, destroyErr)
+	}
+}
+
+// activateVolume waits for the zvol at devicePath to be available and prepares its filesystem.
+// The discards free the space of files deleted by the VM in the zvol.
+func activateVolume(devicePath string) error {
+	if err := waitForZvol(devicePath); err != nil {
+		return err
+	}
+
+	return snapshotter.RepairFilesystem(devicePath)
+}
+
+// waitForZvol waits for udev to create the device node of a zvol
+func waitForZvol(devicePath string) error {
+	const checkInterval = 100 * time.Millisecond
+
+	timer := time.Now()
+	for !util.FileExists(devicePath) {
+		if time.Since(timer) > zvolTimeout {
+			return fmt.Errorf("timeout waiting for zvol %q", devicePath)
+		}
+		time.Sleep(checkInterval)
+	}
+
+	return nil
+}
+
+// configuration returns the ZFS configuration of the host
+func configuration() (api.ZFSConfiguration, error) {
+	var cfg api.ZFSConfiguration
+	if providers.ComponentConfig != nil {
+		cfg = providers.ComponentConfig.Spec.ZFS
+	}
+
+	if len(cfg.Dataset) == 0 {
+		return cfg, fmt.Errorf("no ZFS dataset configured, set spec.zfs.dataset in the ignite configuration")
+	}
+
+	return cfg, nil
+}
+
+func zvolPath(volume string) string {
+	return path.Join(zvolDir, volume)
+}
+
+// sizeArg formats the size for ZFS. The volume size needs to be a multiple
+// of the volume block size, so round up to whole megabytes.
+func sizeArg(size meta.Size) string {
+	return fmt.Sprintf("%dM", (size.Bytes()+constants.MB-1)/constants.MB)
+}
+
+// OverlayUsage returns the space used by the zvol of the VM. The zvol is a clone of
+// the image, so this is only the space of the blocks the VM has written.
+func OverlayUsage(vm *api.VM) (meta.Size, error) {
+	volume, err := snapshotVolume(vm)
+	if err != nil {
+		return meta.EmptySize, err
+	}
+
+	return volumeUsage(volume)
+}
+
+// volumeUsage returns the space used by the zvol volume
+func volumeUsage(volume string) (meta.Size, error) {
+	out, err := snapshotter.ExecuteCommand("zfs", "get", "-Hp", "-o", "value", "used", volume)
+	if err != nil {
+		return meta.EmptySize, err
+	}
+
+	used, err := strconv.ParseUint(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return meta.EmptySize, fmt.Errorf("failed to parse the space used by zvol %q: %v", volume, err)
+	}
+
+	return meta.NewSizeFromBytes(used), nil
+}
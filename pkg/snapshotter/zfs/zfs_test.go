@@ -0,0 +1,229 @@
+package zfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/providers"
+	"github.com/weaveworks/ignite/pkg/snapshotter/snapshottertest"
+)
+
+// fakeZvolDir points zvolDir to a temporary directory for the test
+func fakeZvolDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "zfs-")
+	assert.NilError(t, err)
+
+	oldZvolDir := zvolDir
+	zvolDir = dir
+	t.Cleanup(func() {
+		zvolDir = oldZvolDir
+		_ = os.RemoveAll(dir)
+	})
+
+	return dir
+}
+
+// createZvol emulates udev creating the device node of a zvol
+func createZvol(t *testing.T, volume string) {
+	devicePath := zvolPath(volume)
+	assert.NilError(t, os.MkdirAll(filepath.Dir(devicePath), 0755))
+	assert.NilError(t, ioutil.WriteFile(devicePath, nil, 0644))
+}
+
+func TestEnsureImageVolume(t *testing.T) {
+	oldPrefix := providers.IDPrefix
+	providers.IDPrefix = "ignite"
+	defer func() { providers.IDPrefix = oldPrefix }()
+
+	dir := fakeZvolDir(t)
+	cfg := api.ZFSConfiguration{Dataset: "tank/ignite"}
+	imagePath := "/var/lib/firecracker/image/fedcba9876543210/image.ext4"
+	volume := "tank/ignite/ignite-image-fedcba9876543210"
+
+	tests := []struct {
+		name     string
+		imported bool
+		failed   string // command that fails
+		expected []string
+	}{
+		{
+			name: "import the image",
+			expected: []string{
+				"zfs list -t snapshot " + volume + "@base",
+				"zfs create -s -V 1024M " + volume,
+				"dd if=" + imagePath + " of=" + filepath.Join(dir, volume) + " bs=1M conv=sparse",
+				"zfs snapshot " + volume + "@base",
+			},
+		},
+		{
+			name:     "imported image",
+			imported: true,
+			expected: []string{
+				"zfs list -t snapshot " + volume + "@base",
+			},
+		},
+		{
+			// The partial zvol would make the import fail for the next VM
+			name:   "failed import of the image",
+			failed: "dd",
+			expected: []string{
+				"zfs list -t snapshot " + volume + "@base",
+				"zfs create -s -V 1024M " + volume,
+				"dd if=" + imagePath + " of=" + filepath.Join(dir, volume) + " bs=1M conv=sparse",
+				"zfs destroy " + volume,
+			},
+		},
+		{
+			name:   "failed snapshot of the image",
+			failed: "zfs snapshot",
+			expected: []string{
+				"zfs list -t snapshot " + volume + "@base",
+				"zfs create -s -V 1024M " + volume,
+				"dd if=" + imagePath + " of=" + filepath.Join(dir, volume) + " bs=1M conv=sparse",
+				"zfs snapshot " + volume + "@base",
+				"zfs destroy " + volume,
+			},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			if !rt.imported {
+				commands.Fail("zfs list", fmt.Errorf("dataset does not exist"))
+			}
+			commands.Handle("zfs create", func(args []string) (string, error) {
+				createZvol(t, args[len(args)-1])
+				return "", nil
+			})
+			if len(rt.failed) > 0 {
+				commands.Fail(rt.failed, fmt.Errorf("exit status 1"))
+			}
+
+			base, err := ensureImageVolume(cfg, "fedcba9876543210", imagePath, meta.NewSizeFromBytes(1024*1024*1024))
+			assert.DeepEqual(t, commands.Run, rt.expected)
+			if len(rt.failed) > 0 {
+				assert.ErrorContains(t, err, "exit status 1")
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, base, volume)
+		})
+	}
+}
+
+func TestCreateClone(t *testing.T) {
+	imageSize := meta.NewSizeFromBytes(1024 * 1024 * 1024)
+
+	tests := []struct {
+		name     string
+		size     meta.Size
+		failed   string // command that fails
+		expected []string
+	}{
+		{
+			name: "size of the image",
+			size: imageSize,
+			expected: []string{
+				"zfs clone tank/ignite/ignite-image-fedcba9876543210@base tank/ignite/ignite-0123456789abcdef",
+			},
+		},
+		{
+			name: "larger than the image",
+			size: meta.NewSizeFromBytes(2*1024*1024*1024 + 1),
+			expected: []string{
+				"zfs clone tank/ignite/ignite-image-fedcba9876543210@base tank/ignite/ignite-0123456789abcdef",
+				"zfs set volsize=2049M tank/ignite/ignite-0123456789abcdef",
+			},
+		},
+		{
+			name:   "failed clone",
+			size:   imageSize,
+			failed: "zfs clone",
+			expected: []string{
+				"zfs clone tank/ignite/ignite-image-fedcba9876543210@base tank/ignite/ignite-0123456789abcdef",
+			},
+		},
+		{
+			name:   "no space to grow the clone",
+			size:   meta.NewSizeFromBytes(2 * 1024 * 1024 * 1024),
+			failed: "zfs set",
+			expected: []string{
+				"zfs clone tank/ignite/ignite-image-fedcba9876543210@base tank/ignite/ignite-0123456789abcdef",
+				"zfs set volsize=2048M tank/ignite/ignite-0123456789abcdef",
+				"zfs destroy tank/ignite/ignite-0123456789abcdef",
+			},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			if len(rt.failed) > 0 {
+				commands.Fail(rt.failed, fmt.Errorf("exit status 1"))
+			}
+
+			err := createClone("tank/ignite/ignite-image-fedcba9876543210", "tank/ignite/ignite-0123456789abcdef", imageSize, rt.size)
+			assert.Equal(t, err != nil, len(rt.failed) > 0, "unexpected error: %v", err)
+			assert.DeepEqual(t, commands.Run, rt.expected)
+		})
+	}
+}
+
+func TestActivateVolume(t *testing.T) {
+	fakeZvolDir(t)
+	volume := "tank/ignite/ignite-0123456789abcdef"
+	createZvol(t, volume)
+
+	devicePath := zvolPath(volume)
+	activation := []string{
+		"e2fsck -p -f -E discard " + devicePath,
+		"resize2fs " + devicePath,
+	}
+
+	// Every VM start activates the zvol again, which needs to succeed
+	commands := snapshottertest.Fake(t)
+	assert.NilError(t, activateVolume(devicePath))
+	assert.NilError(t, activateVolume(devicePath))
+	assert.DeepEqual(t, commands.Run, append(activation, activation...))
+}
+
+func TestVolumeUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string // of zfs get
+		expected meta.Size
+		err      bool
+	}{
+		{
+			name:     "used space",
+			output:   "5242880\n",
+			expected: meta.NewSizeFromBytes(5 * 1024 * 1024),
+		},
+		{
+			name:   "unparsable used space",
+			output: "-\n",
+			err:    true,
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			commands := snapshottertest.Fake(t)
+			commands.Output("zfs get", rt.output)
+
+			usage, err := volumeUsage("tank/ignite/ignite-0123456789abcdef")
+			assert.Equal(t, err != nil, rt.err, "unexpected error: %v", err)
+			assert.Equal(t, usage, rt.expected)
+			assert.DeepEqual(t, commands.Run, []string{"zfs get -Hp -o value used tank/ignite/ignite-0123456789abcdef"})
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package sops
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// IsEncrypted returns true if the YAML or JSON document is encrypted with SOPS, which adds
+// its metadata with the MAC of the document under the top-level sops key
+func IsEncrypted(doc []byte) bool {
+	var obj struct {
+		SOPS *struct {
+			MAC string `json:"mac"`
+		} `json:"sops"`
+	}
+	if err := yaml.Unmarshal(doc, &obj); err != nil {
+		return false
+	}
+
+	return obj.SOPS != nil && len(obj.SOPS.MAC) > 0
+}
+
+// Format returns the SOPS format of the manifest file, json for .json files and yaml otherwise
+func Format(filename string) string {
+	if filepath.Ext(filename) == ".json" {
+		return "json"
+	}
+
+	return "yaml"
+}
+
+// Decrypt decrypts the document of the given format with the sops binary. It finds the keys
+// the way sops does, e.g. age keys in $SOPS_AGE_KEY_FILE, GPG keys in the keyring of the
+// user and KMS keys with the cloud credentials of the environment.
+var Decrypt = func(doc []byte, format string) ([]byte, error) {
+	if _, err := exec.LookPath("sops"); err != nil {
+		return nil, fmt.Errorf("decrypting a manifest encrypted with SOPS requires sops: %v", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("sops", "--decrypt", "--input-type", format, "--output-type", format, "/dev/stdin")
+	cmd.Stdin = bytes.NewReader(doc)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("sops failed to decrypt the manifest: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,35 @@
+package sops
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const encryptedVM = `apiVersion: ignite.weave.works/v1alpha5
+kind: VM
+metadata:
+  name: my-vm
+spec:
+  env:
+    TOKEN: ENC[AES256_GCM,data:aGVsbG8=,iv:aXY=,tag:dGFn,type:str]
+sops:
+  age:
+  - recipient: age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+  mac: ENC[AES256_GCM,data:bWFj,iv:aXY=,tag:dGFn,type:str]
+  version: 3.7.3
+`
+
+func TestIsEncrypted(t *testing.T) {
+	assert.Assert(t, IsEncrypted([]byte(encryptedVM)))
+	assert.Assert(t, IsEncrypted([]byte(`{"kind": "VM", "sops": {"mac": "ENC[AES256_GCM,data:bWFj]"}}`)))
+	assert.Assert(t, !IsEncrypted([]byte("apiVersion: ignite.weave.works/v1alpha5\nkind: VM\n")))
+	assert.Assert(t, !IsEncrypted([]byte("kind: VM\nsops: {}\n")))
+	assert.Assert(t, !IsEncrypted([]byte("not: [valid")))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, Format("vms/my-vm.yaml"), "yaml")
+	assert.Equal(t, Format("vms/my-vm.yml"), "yaml")
+	assert.Equal(t, Format("vms/my-vm.json"), "json")
+}
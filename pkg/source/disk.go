@@ -0,0 +1,112 @@
+package source
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"path"
+	"strings"
+)
+
+// ContainerDiskDir is the directory container disks keep their disk image file in,
+// following the convention of the container disks of KubeVirt
+const ContainerDiskDir = "disk"
+
+// runtimeFiles are added by the container runtimes to the filesystems they export,
+// they don't tell whether the image is a container disk
+var runtimeFiles = map[string]bool{
+	".dockerenv":      true,
+	"etc/hostname":    true,
+	"etc/hosts":       true,
+	"etc/mtab":        true,
+	"etc/resolv.conf": true,
+}
+
+// Disk is the disk image file of a container disk, read from the tar stream of its source
+type Disk struct {
+	io.Reader
+	// Name is the name of the disk image file in ContainerDiskDir
+	Name   string
+	closer io.Closer
+}
+
+// Close closes the tar stream the disk image file is read from
+func (d *Disk) Close() error {
+	return d.closer.Close()
+}
+
+// PeekDisk reads the tar stream of the source up to its first file, to find whether its
+// OCI image is a container disk. For container disks, the disk image file is returned.
+// For other images, the returned source replays the tar stream read so far on its next
+// Reader call, so the image isn't exported twice.
+func PeekDisk(src Source) (*Disk, Source, error) {
+	rc, err := src.Reader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rec := &recorder{}
+	tr := tar.NewReader(io.TeeReader(rc, rec))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rc.Close()
+			return nil, nil, err
+		}
+
+		name := strings.TrimLeft(path.Clean(hdr.Name), "/")
+		if hdr.Typeflag == tar.TypeDir || runtimeFiles[name] {
+			continue
+		}
+
+		if hdr.Typeflag == tar.TypeReg && path.Dir(name) == ContainerDiskDir {
+			// The disk image file is only read once, it doesn't need to be replayed
+			rec.stopped = true
+			return &Disk{Reader: tr, Name: path.Base(name), closer: rc}, nil, nil
+		}
+
+		break
+	}
+
+	return nil, &replaySource{
+		Source: src,
+		rc: struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(rec.Bytes()), rc), rc},
+	}, nil
+}
+
+// recorder buffers the bytes of the tar stream read while peeking at it
+type recorder struct {
+	bytes.Buffer
+	stopped bool
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if r.stopped {
+		return len(p), nil
+	}
+
+	return r.Buffer.Write(p)
+}
+
+// replaySource is a source whose tar stream has been peeked at
+type replaySource struct {
+	Source
+	rc io.ReadCloser
+}
+
+// Reader returns the peeked tar stream the first time, and a new one afterwards
+func (s *replaySource) Reader() (io.ReadCloser, error) {
+	if s.rc == nil {
+		return s.Source.Reader()
+	}
+
+	rc := s.rc
+	s.rc = nil
+	return rc, nil
+}
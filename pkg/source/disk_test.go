@@ -0,0 +1,120 @@
+package source
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+)
+
+// tarSource is a source exporting a fixed tar stream
+type tarSource struct {
+	tar     []byte
+	exports int
+}
+
+func (s *tarSource) Ref() meta.OCIImageRef { return meta.OCIImageRef{} }
+
+func (s *tarSource) Parse(meta.OCIImageRef) (*api.OCIImageSource, error) { return nil, nil }
+
+func (s *tarSource) Reader() (io.ReadCloser, error) {
+	s.exports++
+	return ioutil.NopCloser(bytes.NewReader(s.tar)), nil
+}
+
+func (s *tarSource) Cleanup() error { return nil }
+
+func writeTar(t *testing.T, files map[string]string, names ...string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0644, Typeflag: tar.TypeDir}
+		if content, ok := files[name]; ok {
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = int64(len(content))
+		}
+		assert.NilError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(files[name]))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestPeekDisk(t *testing.T) {
+	files := map[string]string{
+		".dockerenv":          "",
+		"./etc/hostname":      "container\n",
+		"./bin/sh":            "shell",
+		"./disk/disk.qcow2":   "QFI\xfb disk",
+		"./disk/another.img":  "another disk",
+		"./usr/bin/something": "binary",
+	}
+
+	tests := []struct {
+		name     string
+		names    []string
+		disk     string
+		diskName string
+	}{
+		{
+			name:     "container disk",
+			names:    []string{".dockerenv", "./", "./disk/", "./disk/disk.qcow2", "./disk/another.img", "./etc/", "./etc/hostname"},
+			disk:     "QFI\xfb disk",
+			diskName: "disk.qcow2",
+		},
+		{
+			name:  "filesystem image",
+			names: []string{".dockerenv", "./", "./bin/", "./bin/sh", "./disk/", "./disk/disk.qcow2", "./usr/", "./usr/bin/", "./usr/bin/something"},
+		},
+		{
+			name:  "file out of the disk directory",
+			names: []string{"./", "./disk/", "./usr/bin/something"},
+		},
+		{
+			name:  "empty image",
+			names: []string{"./"},
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			tarStream := writeTar(t, files, rt.names...)
+			src := &tarSource{tar: tarStream}
+
+			disk, replay, err := PeekDisk(src)
+			assert.NilError(t, err)
+
+			if len(rt.disk) > 0 {
+				assert.Assert(t, replay == nil)
+				assert.Equal(t, disk.Name, rt.diskName)
+				content, err := ioutil.ReadAll(disk)
+				assert.NilError(t, err)
+				assert.Equal(t, string(content), rt.disk)
+				assert.NilError(t, disk.Close())
+				return
+			}
+
+			// The whole tar stream is replayed without exporting the image again
+			assert.Assert(t, disk == nil)
+			rc, err := replay.Reader()
+			assert.NilError(t, err)
+			content, err := ioutil.ReadAll(rc)
+			assert.NilError(t, err)
+			assert.NilError(t, rc.Close())
+			assert.DeepEqual(t, content, tarStream)
+			assert.Equal(t, src.exports, 1)
+
+			// The image is exported again when it's read a second time
+			_, err = replay.Reader()
+			assert.NilError(t, err)
+			assert.Equal(t, src.exports, 2)
+		})
+	}
+}
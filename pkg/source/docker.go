@@ -47,8 +47,9 @@ func (ds *DockerSource) Parse(ociRef meta.OCIImageRef) (*api.OCIImageSource, err
 	ds.imageRef = ociRef
 
 	return &api.OCIImageSource{
-		ID:   res.ID,
-		Size: meta.NewSizeFromBytes(uint64(res.Size)),
+		ID:           res.ID,
+		Size:         meta.NewSizeFromBytes(uint64(res.Size)),
+		Architecture: res.Architecture,
 	}, nil
 }
 
@@ -0,0 +1,77 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
+	"github.com/weaveworks/ignite/pkg/estargz"
+)
+
+// EStargzSource reads the files an image with eStargz layers needs at boot from its
+// registry, without pulling the image. The rest of the files are read by LazyRef later.
+type EStargzSource struct {
+	imageRef meta.OCIImageRef
+	image    *estargz.Image
+}
+
+// Compile-time assert to verify interface compatibility
+var _ Source = &EStargzSource{}
+
+func NewEStargzSource() *EStargzSource {
+	return &EStargzSource{}
+}
+
+func (es *EStargzSource) Ref() meta.OCIImageRef {
+	return es.imageRef
+}
+
+// Parse reads the TOCs of the layers of the image. estargz.ErrNotEStargz is returned
+// if the image has other layers.
+func (es *EStargzSource) Parse(ociRef meta.OCIImageRef) (*api.OCIImageSource, error) {
+	image, err := estargz.Open(context.Background(), ociRef.Normalized())
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := meta.ParseOCIContentID(fmt.Sprintf("%s@%s", image.Name, image.Config))
+	if err != nil {
+		image.Close()
+		return nil, err
+	}
+
+	es.imageRef = ociRef
+	es.image = image
+
+	// The size is of all files, the VMs get the rest of them written into their disks
+	boot, rest := image.FS.Sizes()
+	return &api.OCIImageSource{
+		ID:           id,
+		Size:         meta.NewSizeFromBytes(uint64(boot + rest)),
+		Architecture: image.Architecture,
+	}, nil
+}
+
+// LazyRef returns the reference of the manifest the rest of the files are read from
+func (es *EStargzSource) LazyRef() string {
+	return fmt.Sprintf("%s@%s", es.image.Name, es.image.Manifest)
+}
+
+func (es *EStargzSource) Reader() (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(es.image.FS.WriteBoot(pw))
+	}()
+
+	return pr, nil
+}
+
+func (es *EStargzSource) Cleanup() error {
+	if es.image != nil {
+		return es.image.Close()
+	}
+
+	return nil
+}
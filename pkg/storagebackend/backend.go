@@ -0,0 +1,161 @@
+// Package storagebackend abstracts where the metadata of the VMs, images, kernels and VM
+// templates is stored. A backend provides the RawStorage of a data directory, which is used
+// behind the same storage, cache and client whatever the backend, so the callers don't
+// depend on it. The objects are moved between the metadata files and the other backends
+// with Import and Export.
+package storagebackend
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/libgitops/pkg/runtime"
+	"github.com/weaveworks/libgitops/pkg/storage"
+)
+
+// Kinds are the kinds of objects stored by the backends
+var Kinds = []runtime.Kind{api.KindVM, api.KindImage, api.KindKernel, api.KindVMTemplate}
+
+// Backend stores the metadata of the objects of a data directory
+type Backend interface {
+	// Name is the name of the backend, e.g. "bolt"
+	Name() string
+	// Enabled returns whether the metadata of the data directory dir is stored in the backend
+	Enabled(dir string) bool
+	// Location describes where the metadata of the data directory dir is stored
+	Location(dir string) string
+	// NewRawStorage returns the RawStorage of the metadata of the data directory dir
+	NewRawStorage(dir string) (storage.RawStorage, error)
+	// Import copies the objects of the metadata files of the data directory dir to the
+	// backend and enables it, the metadata files are kept. It returns how many objects
+	// were copied.
+	Import(dir string) (int, error)
+	// Export writes the objects of the backend to the metadata files of the data directory
+	// dir and disables the backend. It returns how many objects were written.
+	Export(dir string) (int, error)
+}
+
+// File stores the metadata in a metadata file in the directory of every object, it's used
+// when no other backend is enabled
+var File Backend = fileBackend{}
+
+type fileBackend struct{}
+
+func (fileBackend) Name() string {
+	return "file"
+}
+
+func (fileBackend) Enabled(string) bool {
+	return true
+}
+
+func (fileBackend) Location(dir string) string {
+	return dir
+}
+
+func (fileBackend) NewRawStorage(dir string) (storage.RawStorage, error) {
+	return storage.NewGenericRawStorage(dir), nil
+}
+
+func (fileBackend) Import(dir string) (int, error) {
+	return 0, fmt.Errorf("the metadata of %q is stored in metadata files", dir)
+}
+
+func (fileBackend) Export(dir string) (int, error) {
+	return 0, fmt.Errorf("the metadata of %q is stored in metadata files", dir)
+}
+
+// Get returns the backend of the given name, File or one of the backends
+func Get(backends []Backend, name string) (Backend, error) {
+	names := []string{File.Name()}
+	if name == File.Name() {
+		return File, nil
+	}
+
+	for _, b := range backends {
+		if b.Name() == name {
+			return b, nil
+		}
+		names = append(names, b.Name())
+	}
+
+	return nil, fmt.Errorf("unknown storage %q, supported storages: %s", name, strings.Join(names, "|"))
+}
+
+// For returns the first of the backends enabled for the data directory dir, or File
+func For(backends []Backend, dir string) Backend {
+	for _, b := range backends {
+		if b.Enabled(dir) {
+			return b
+		}
+	}
+
+	return File
+}
+
+// ReadFiles calls fn with the content of the metadata file of every object in the data
+// directory dir, and for VMs its modification time in nanoseconds
+func ReadFiles(dir string, fn func(key storage.Key, content []byte, modTime int64) error) error {
+	files := storage.NewGenericRawStorage(dir)
+	for _, kind := range Kinds {
+		keys, err := files.List(storage.NewKindKey(kind))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			// Directories without a metadata file aren't objects
+			if !files.Exists(key) {
+				log.Debugf("Skipping %s without metadata", key)
+				continue
+			}
+
+			content, err := files.Read(key)
+			if err != nil {
+				return err
+			}
+
+			modTime, err := FileModTime(dir, key)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(key, content, modTime); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteFiles writes all objects of the RawStorage to metadata files in the data directory dir
+func WriteFiles(raw storage.RawStorage, dir string) (int, error) {
+	files := storage.NewGenericRawStorage(dir)
+	count := 0
+	for _, kind := range Kinds {
+		keys, err := raw.List(storage.NewKindKey(kind))
+		if err != nil {
+			return count, err
+		}
+
+		for _, key := range keys {
+			content, err := raw.Read(key)
+			if err != nil {
+				return count, err
+			}
+
+			if err := files.Write(key, content); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+
+	return count, nil
+}
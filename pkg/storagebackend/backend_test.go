@@ -0,0 +1,59 @@
+package storagebackend
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/weaveworks/libgitops/pkg/storage"
+	"gotest.tools/assert"
+)
+
+// fakeBackend is enabled if its file exists in the data directory
+type fakeBackend struct {
+	fileBackend
+	name string
+}
+
+func (b fakeBackend) Name() string {
+	return b.name
+}
+
+func (b fakeBackend) Enabled(dir string) bool {
+	_, err := os.Stat(path.Join(dir, b.name))
+	return err == nil
+}
+
+func TestFor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-storagebackend-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	backends := []Backend{fakeBackend{name: "bolt"}, fakeBackend{name: "etcd"}}
+	assert.Equal(t, For(backends, dir), File)
+
+	assert.NilError(t, ioutil.WriteFile(path.Join(dir, "etcd"), nil, 0600))
+	assert.Equal(t, For(backends, dir).Name(), "etcd")
+
+	b, err := Get(backends, "file")
+	assert.NilError(t, err)
+	assert.Equal(t, b, File)
+
+	b, err = Get(backends, "bolt")
+	assert.NilError(t, err)
+	assert.Equal(t, b.Name(), "bolt")
+
+	_, err = Get(backends, "sqlite")
+	assert.Error(t, err, `unknown storage "sqlite", supported storages: file|bolt|etcd`)
+}
+
+func TestKinds(t *testing.T) {
+	// The kinds are stored in the directories of the metadata files
+	var names []string
+	for _, kind := range Kinds {
+		names = append(names, storage.NewKindKey(kind).String())
+	}
+
+	assert.DeepEqual(t, names, []string{"vm", "image", "kernel", "vmtemplate"})
+}
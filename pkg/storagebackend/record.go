@@ -0,0 +1,119 @@
+package storagebackend
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/libgitops/pkg/storage"
+)
+
+// Record is how the backends store an object
+type Record struct {
+	// Revision increases with every write of the objects of the kind, if the backend doesn't
+	// keep the revisions itself
+	Revision uint64 `json:"revision,omitempty"`
+	// ModTime is the modification time of the metadata file of a VM when it was last
+	// written to the backend, in nanoseconds
+	ModTime int64 `json:"modTime,omitempty"`
+	// Object is the object, encoded in JSON
+	Object json.RawMessage `json:"object"`
+}
+
+// HasFile returns whether the object keeps its metadata file in the other backends, only VMs
+// do. It's mounted into the container of the VM for ignite-spawn, which updates the status of
+// the VM in it.
+func HasFile(key storage.Key) bool {
+	return key.Kind == api.KindVM
+}
+
+// ObjectDir returns the directory of the files of the object in the data directory dir
+func ObjectDir(dir string, key storage.Key) string {
+	return path.Join(dir, key.String())
+}
+
+// WriteFile creates the directory of the object, and writes the metadata file of a VM
+func WriteFile(dir string, key storage.Key, content []byte) error {
+	// The files of the object, e.g. the disk of an image, are kept in its directory
+	objectDir := ObjectDir(dir, key)
+	if err := os.MkdirAll(objectDir, constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	if !HasFile(key) {
+		return nil
+	}
+
+	return ioutil.WriteFile(path.Join(objectDir, constants.METADATA), content, 0644)
+}
+
+// FileModTime returns the modification time of the metadata file of a VM in nanoseconds,
+// other objects don't keep theirs
+func FileModTime(dir string, key storage.Key) (int64, error) {
+	if !HasFile(key) {
+		return 0, nil
+	}
+
+	fi, err := os.Stat(path.Join(ObjectDir(dir, key), constants.METADATA))
+	if err != nil {
+		return 0, err
+	}
+
+	return fi.ModTime().UnixNano(), nil
+}
+
+// FileChanged returns the modification time of the metadata file of a VM in nanoseconds, if
+// it changed since the record was written. Otherwise, or if the file doesn't exist, e.g. as
+// the VM is on another host, it returns 0.
+func FileChanged(dir string, key storage.Key, rec *Record) int64 {
+	if !HasFile(key) {
+		return 0
+	}
+
+	fi, err := os.Stat(path.Join(ObjectDir(dir, key), constants.METADATA))
+	if err != nil || fi.ModTime().UnixNano() == rec.ModTime {
+		return 0
+	}
+
+	return fi.ModTime().UnixNano()
+}
+
+// ReadChangedFile reads the metadata file of a VM if it changed since the record was
+// written. A file being written is left for the next read.
+func ReadChangedFile(dir string, key storage.Key, rec *Record) ([]byte, int64, bool, error) {
+	if !HasFile(key) {
+		return nil, 0, false, nil
+	}
+
+	file := path.Join(ObjectDir(dir, key), constants.METADATA)
+	fi, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return nil, 0, false, nil
+	} else if err != nil {
+		return nil, 0, false, err
+	}
+
+	modTime := fi.ModTime().UnixNano()
+	if modTime == rec.ModTime {
+		return nil, 0, false, nil
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	if !json.Valid(content) {
+		return nil, 0, false, nil
+	}
+
+	return content, modTime, true, nil
+}
+
+// NotExist returns the error of reading an object that doesn't exist from a backend
+func NotExist(location string, key storage.Key) error {
+	return &os.PathError{Op: "read", Path: location + ":" + key.String(), Err: os.ErrNotExist}
+}
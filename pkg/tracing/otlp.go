@@ -0,0 +1,289 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/version"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// The spans are exported in batches of up to maxBatch spans, at least every exportInterval
+	maxBatch       = 512
+	exportInterval = 5 * time.Second
+	// maxPending spans are kept while the endpoint is unreachable, the oldest are dropped
+	maxPending    = 4096
+	exportTimeout = 10 * time.Second
+	scopeName     = "github.com/weaveworks/ignite"
+)
+
+// exporter exports the spans to an OTLP/HTTP endpoint in batches, with the JSON encoding
+type exporter struct {
+	endpoint string
+	headers  map[string]string
+	service  string
+	client   *http.Client
+
+	mu      sync.Mutex
+	pending []*trace.SpanData
+	// full is signalled when a batch is full, to export it before the interval
+	full chan struct{}
+	// exportMu serializes the exports of the interval, full batches and flushes
+	exportMu sync.Mutex
+}
+
+var _ trace.Exporter = &exporter{}
+
+func newExporter(endpoint string, headers map[string]string, service string) *exporter {
+	return &exporter{
+		endpoint: endpoint,
+		headers:  headers,
+		service:  service,
+		client:   &http.Client{Timeout: exportTimeout},
+		full:     make(chan struct{}, 1),
+	}
+}
+
+// ExportSpan queues the ended span for the next batch
+func (e *exporter) ExportSpan(s *trace.SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.pending) >= maxPending {
+		e.pending = e.pending[1:]
+	}
+	e.pending = append(e.pending, s)
+
+	if len(e.pending) >= maxBatch {
+		select {
+		case e.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run exports the pending spans at the interval, or once a batch is full
+func (e *exporter) run() {
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.full:
+		}
+
+		e.flush()
+	}
+}
+
+// flush exports all the pending spans, the spans of a batch that fails are dropped
+func (e *exporter) flush() {
+	e.exportMu.Lock()
+	defer e.exportMu.Unlock()
+
+	for {
+		e.mu.Lock()
+		n := len(e.pending)
+		if n > maxBatch {
+			n = maxBatch
+		}
+		batch := e.pending[:n]
+		e.pending = e.pending[n:]
+		e.mu.Unlock()
+
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := e.export(batch); err != nil {
+			log.Warnf("Failed to export %d spans to %s: %v", len(batch), e.endpoint, err)
+		}
+	}
+}
+
+// export posts the batch of spans to the endpoint
+func (e *exporter) export(batch []*trace.SpanData) error {
+	body, err := json.Marshal(e.request(batch))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
+
+// The OTLP/HTTP JSON encoding of the spans, as specified by the protobuf JSON mapping of
+// opentelemetry/proto/collector/trace/v1/trace_service.proto. The trace and span IDs are
+// encoded in hex, and the 64-bit integers as strings.
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// The OTLP span kinds and status codes
+const (
+	spanKindInternal = 1
+	spanKindServer   = 2
+	spanKindClient   = 3
+	statusCodeError  = 2
+)
+
+// request converts the batch of spans to the OTLP export request
+func (e *exporter) request(batch []*trace.SpanData) *exportRequest {
+	spans := make([]span, 0, len(batch))
+	for _, s := range batch {
+		spans = append(spans, convertSpan(s))
+	}
+
+	return &exportRequest{
+		ResourceSpans: []resourceSpans{
+			{
+				Resource: resource{
+					Attributes: []keyValue{attribute("service.name", e.service)},
+				},
+				ScopeSpans: []scopeSpans{
+					{
+						Scope: scope{Name: scopeName, Version: version.GetIgnite().GitVersion},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func convertSpan(s *trace.SpanData) span {
+	out := span{
+		TraceID:           s.TraceID.String(),
+		SpanID:            s.SpanID.String(),
+		Name:              s.Name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+	}
+
+	if s.ParentSpanID != (trace.SpanID{}) {
+		out.ParentSpanID = s.ParentSpanID.String()
+	}
+
+	switch s.SpanKind {
+	case trace.SpanKindServer:
+		out.Kind = spanKindServer
+	case trace.SpanKindClient:
+		out.Kind = spanKindClient
+	}
+
+	keys := make([]string, 0, len(s.Attributes))
+	for k := range s.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out.Attributes = append(out.Attributes, attribute(k, s.Attributes[k]))
+	}
+
+	if s.Code != trace.StatusCodeOK {
+		out.Status = status{Code: statusCodeError, Message: s.Message}
+	}
+
+	return out
+}
+
+// attribute converts the value of the attribute, which is a string, bool, int64 or float64
+func attribute(key string, value interface{}) keyValue {
+	var v anyValue
+	switch value := value.(type) {
+	case string:
+		v.StringValue = &value
+	case bool:
+		v.BoolValue = &value
+	case int64:
+		i := strconv.FormatInt(value, 10)
+		v.IntValue = &i
+	case float64:
+		v.DoubleValue = &value
+	default:
+		str := fmt.Sprint(value)
+		v.StringValue = &str
+	}
+
+	return keyValue{Key: key, Value: v}
+}
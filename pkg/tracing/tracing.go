@@ -0,0 +1,162 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/logs"
+	"go.opencensus.io/trace"
+)
+
+// The standard OpenTelemetry environment variables configuring the export of the spans
+const (
+	EndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	HeadersEnv        = "OTEL_EXPORTER_OTLP_HEADERS"
+	ServiceNameEnv    = "OTEL_SERVICE_NAME"
+)
+
+// tracesPath is appended to the endpoint of $OTEL_EXPORTER_OTLP_ENDPOINT for the spans
+const tracesPath = "/v1/traces"
+
+// Setup exports the spans over OTLP/HTTP to the endpoint in $OTEL_EXPORTER_OTLP_TRACES_ENDPOINT
+// or $OTEL_EXPORTER_OTLP_ENDPOINT, as the service in $OTEL_SERVICE_NAME or the given one.
+// Without an endpoint no spans are recorded. The returned function exports the pending
+// spans, call it before exiting.
+func Setup(service string) (func(), error) {
+	endpoint, err := tracesEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoint) == 0 {
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+		return func() {}, nil
+	}
+
+	headers, err := parseHeaders(os.Getenv(HeadersEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	if name := os.Getenv(ServiceNameEnv); len(name) > 0 {
+		service = name
+	}
+
+	e := newExporter(endpoint, headers, service)
+	go e.run()
+
+	trace.RegisterExporter(e)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	log.Debugf("Exporting the spans of %s to %s", service, endpoint)
+
+	return e.flush, nil
+}
+
+// Span is a span of an operation or of one of its phases, which logs its duration with the
+// fields of the operation when it ends
+type Span struct {
+	*trace.Span
+	name   string
+	start  time.Time
+	fields log.Fields
+}
+
+// fieldsKey is the context key of the log fields of the span in the context
+type fieldsKey struct{}
+
+// Start starts a span of the operation, as a child of the span in the context if any. A child
+// span is a phase of the operation of its parent, and logs its attributes along with the
+// ones of its parent.
+func Start(ctx context.Context, name string, attributes ...trace.Attribute) (context.Context, *Span) {
+	ctx, span := trace.StartSpan(ctx, name)
+	span.AddAttributes(attributes...)
+
+	fields := log.Fields{}
+	if parent, ok := ctx.Value(fieldsKey{}).(log.Fields); ok {
+		for k, v := range parent {
+			fields[k] = v
+		}
+		fields[logs.FieldPhase] = name
+	} else {
+		fields[logs.FieldOperation] = name
+	}
+
+	for _, a := range attributes {
+		fields[strings.ReplaceAll(a.Key(), ".", "_")] = a.Value()
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, fields), &Span{
+		Span:   span,
+		name:   name,
+		start:  time.Now(),
+		fields: fields,
+	}
+}
+
+// End ends the span, marking it as failed if the operation returned an error, and logs
+// its duration in seconds
+func End(span *Span, err error) {
+	entry := logs.Logger.WithFields(span.fields).WithField(logs.FieldDuration, time.Since(span.start).Seconds())
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+		entry.WithError(err).Debugf("Failed %s", span.name)
+	} else {
+		entry.Debugf("Finished %s", span.name)
+	}
+
+	span.Span.End()
+}
+
+// tracesEndpoint returns the URL the spans are posted to, empty if not configured
+func tracesEndpoint() (string, error) {
+	endpoint := os.Getenv(TracesEndpointEnv)
+	if len(endpoint) == 0 {
+		if endpoint = os.Getenv(EndpointEnv); len(endpoint) == 0 {
+			return "", nil
+		}
+
+		endpoint = strings.TrimSuffix(endpoint, "/") + tracesPath
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid OTLP endpoint %q: %v", endpoint, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid OTLP endpoint %q: only http and https are supported", endpoint)
+	}
+
+	return endpoint, nil
+}
+
+// parseHeaders parses the comma-separated key=value pairs of $OTEL_EXPORTER_OTLP_HEADERS,
+// the values may be URL-encoded
+func parseHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if len(strings.TrimSpace(pair)) == 0 {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || len(strings.TrimSpace(kv[0])) == 0 {
+			return nil, fmt.Errorf("invalid OTLP header %q, expected key=value", pair)
+		}
+
+		value, err := url.QueryUnescape(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP header %q: %v", pair, err)
+		}
+
+		headers[strings.TrimSpace(kv[0])] = value
+	}
+
+	return headers, nil
+}
@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/weaveworks/ignite/pkg/logs"
+	"go.opencensus.io/trace"
+	"gotest.tools/assert"
+)
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := parseHeaders("Authorization=Bearer%20token, x-scope=ignite,")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, headers, map[string]string{"Authorization": "Bearer token", "x-scope": "ignite"})
+
+	_, err = parseHeaders("Authorization")
+	assert.ErrorContains(t, err, "expected key=value")
+}
+
+func TestTracesEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		traces   string
+		expected string
+		err      string
+	}{
+		{"disabled", "", "", "", ""},
+		{"base endpoint", "http://localhost:4318/", "", "http://localhost:4318/v1/traces", ""},
+		{"traces endpoint", "http://localhost:4318", "https://collector/custom", "https://collector/custom", ""},
+		{"grpc endpoint", "grpc://localhost:4317", "", "", "only http and https"},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			os.Setenv(EndpointEnv, rt.endpoint)
+			os.Setenv(TracesEndpointEnv, rt.traces)
+			defer os.Unsetenv(EndpointEnv)
+			defer os.Unsetenv(TracesEndpointEnv)
+
+			endpoint, err := tracesEndpoint()
+			if len(rt.err) > 0 {
+				assert.ErrorContains(t, err, rt.err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, endpoint, rt.expected)
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	var received exportRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.NilError(t, json.Unmarshal(body, &received))
+	}))
+	defer server.Close()
+
+	e := newExporter(server.URL+tracesPath, map[string]string{"Authorization": "Bearer token"}, "ignited")
+	start := time.Unix(1600000000, 0)
+	e.ExportSpan(&trace.SpanData{
+		SpanContext: trace.SpanContext{
+			TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+			SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		},
+		ParentSpanID: trace.SpanID{0x01},
+		Name:         "image.mkfs",
+		StartTime:    start,
+		EndTime:      start.Add(time.Second),
+		Attributes:   map[string]interface{}{"oci": "weaveworks/ignite-ubuntu", "kill": true},
+		Status:       trace.Status{Code: trace.StatusCodeUnknown, Message: "mkfs.ext4 failed"},
+	})
+	e.flush()
+
+	assert.Equal(t, auth, "Bearer token")
+	assert.Equal(t, len(received.ResourceSpans), 1)
+	assert.Equal(t, *received.ResourceSpans[0].Resource.Attributes[0].Value.StringValue, "ignited")
+
+	spans := received.ResourceSpans[0].ScopeSpans[0].Spans
+	assert.Equal(t, len(spans), 1)
+	s := spans[0]
+	assert.Equal(t, s.TraceID, "4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Equal(t, s.SpanID, "00f067aa0ba902b7")
+	assert.Equal(t, s.ParentSpanID, "0100000000000000")
+	assert.Equal(t, s.Kind, spanKindInternal)
+	assert.Equal(t, s.StartTimeUnixNano, "1600000000000000000")
+	assert.Equal(t, s.EndTimeUnixNano, "1600000001000000000")
+	assert.Equal(t, s.Attributes[0].Key, "kill")
+	assert.Equal(t, *s.Attributes[0].Value.BoolValue, true)
+	assert.Equal(t, *s.Attributes[1].Value.StringValue, "weaveworks/ignite-ubuntu")
+	assert.DeepEqual(t, s.Status, status{Code: statusCodeError, Message: "mkfs.ext4 failed"})
+
+	// The exported spans aren't sent again
+	received = exportRequest{}
+	e.flush()
+	assert.Equal(t, len(received.ResourceSpans), 0)
+}
+
+func TestEnd(t *testing.T) {
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	defer trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+
+	e := newExporter("http://localhost", nil, "ignite")
+	trace.RegisterExporter(e)
+	defer trace.UnregisterExporter(e)
+
+	ctx, parent := Start(context.Background(), "vm.start", trace.StringAttribute("vm.uid", "599615df99804ae8"))
+	_, child := Start(ctx, "vm.network")
+	assert.DeepEqual(t, child.fields, log.Fields{
+		logs.FieldOperation: "vm.start",
+		logs.FieldPhase:     "vm.network",
+		logs.FieldVMUID:     "599615df99804ae8",
+	})
+	End(child, errors.New("CNI failed"))
+	End(parent, nil)
+
+	assert.Equal(t, len(e.pending), 2)
+	assert.Equal(t, e.pending[0].ParentSpanID, e.pending[1].SpanID)
+	assert.Equal(t, e.pending[0].Message, "CNI failed")
+	assert.Equal(t, e.pending[1].Code, int32(trace.StatusCodeOK))
+}
@@ -7,15 +7,19 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"syscall"
 
 	"github.com/otiai10/copy"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
 	"github.com/weaveworks/ignite/pkg/constants"
 )
 
 // Creates the /var/lib/firecracker/{vm,image,kernel} directories
 func CreateDirectories() error {
-	for _, dir := range []string{constants.VM_DIR, constants.IMAGE_DIR, constants.KERNEL_DIR, constants.MANIFEST_DIR} {
+	for _, dir := range []string{constants.VM_DIR, constants.VM_TEMPLATE_DIR, constants.IMAGE_DIR, constants.KERNEL_DIR, constants.MANIFEST_DIR} {
 		if err := os.MkdirAll(dir, constants.DATA_DIR_PERM); err != nil {
 			return fmt.Errorf("failed to create directory %q: %v", dir, err)
 		}
@@ -85,17 +89,23 @@ func CopyFile(src string, dst string) error {
 	return copy.Copy(src, dst)
 }
 
+// MountFSType is the filesystem of the images and of the VM snapshots, they're
+// formatted with mkfs.ext4
+const MountFSType = "ext4"
+
 type MountPoint struct {
 	Path string
 }
 
+// Mount mounts the ext4 filesystem of the block device at a temporary directory
 func Mount(volume string) (*MountPoint, error) {
 	tempDir, err := ioutil.TempDir("", "")
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := ExecuteCommand("mount", volume, tempDir); err != nil {
+	if err := unix.Mount(volume, tempDir, MountFSType, 0, ""); err != nil {
+		_ = os.Remove(tempDir)
 		return nil, fmt.Errorf("failed to mount volume %q: %v", volume, err)
 	}
 
@@ -105,7 +115,7 @@ func Mount(volume string) (*MountPoint, error) {
 }
 
 func (mp *MountPoint) Umount() error {
-	if _, err := ExecuteCommand("umount", mp.Path); err != nil {
+	if err := unix.Unmount(mp.Path, 0); err != nil {
 		return fmt.Errorf("failed to unmount volume %q: %v", mp.Path, err)
 	}
 
@@ -173,3 +183,20 @@ func WriteFileIfChanged(filename string, data []byte, perm os.FileMode) error {
 
 	return nil
 }
+
+// DiskUsage returns the disk space allocated for the file, or the files in the directory,
+// at the given path. Sparse files only count their allocated blocks.
+func DiskUsage(path string) (usage uint64, err error) {
+	err = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			usage += uint64(stat.Blocks) * 512 // st_blocks is always counted in 512-byte units
+		}
+		return nil
+	})
+
+	return
+}
@@ -0,0 +1,230 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/flock"
+)
+
+// leaseDir holds the directories of the VMs the leases are recorded in, the tests replace it
+var leaseDir = constants.VM_DIR
+
+// lease is a renewable lease of a secret read for a VM
+type lease struct {
+	ID       string        `json:"id"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Track records the lease of the secret read for the VM with the given UID in the directory
+// of the VM, so it's renewed by the Renewer of ignited and can be revoked by any process.
+// Secrets without a renewable lease, e.g. KV secrets, are ignored.
+func Track(vmUID string, secret *Secret) error {
+	if len(secret.LeaseID) == 0 || !secret.Renewable {
+		return nil
+	}
+
+	return updateLeases(vmUID, func(leases []*lease) ([]*lease, error) {
+		return append(leases, &lease{
+			ID:       secret.LeaseID,
+			Duration: time.Duration(secret.LeaseDuration) * time.Second,
+		}), nil
+	})
+}
+
+// Release revokes the tracked leases of the VM with the given UID, e.g. before its
+// secrets are read again, or when it's removed
+func Release(vmUID string) error {
+	return updateLeases(vmUID, func(leases []*lease) ([]*lease, error) {
+		if len(leases) == 0 {
+			return nil, nil
+		}
+
+		c, err := Default()
+		if err != nil {
+			return leases, err
+		}
+
+		for i, l := range leases {
+			if err := c.do(http.MethodPut, "sys/leases/revoke", map[string]string{"lease_id": l.ID}, nil); err != nil {
+				// Keep the leases that weren't revoked, so revoking them can be retried
+				return leases[i:], err
+			}
+		}
+
+		return nil, nil
+	})
+}
+
+// updateLeases replaces the recorded leases of the VM with the ones returned by update,
+// even if it fails. The file is removed when no leases are left.
+func updateLeases(vmUID string, update func([]*lease) ([]*lease, error)) error {
+	unlock, err := flock.Lock("vault-leases-" + vmUID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file := path.Join(leaseDir, vmUID, constants.VAULT_LEASES_FILE)
+	var leases []*lease
+	if b, err := ioutil.ReadFile(file); err == nil {
+		if err := json.Unmarshal(b, &leases); err != nil {
+			return fmt.Errorf("failed to read the Vault leases of VM %q: %v", vmUID, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	leases, updateErr := update(leases)
+	if len(leases) == 0 {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return updateErr
+	}
+
+	b, err := json.Marshal(leases)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(file, b, 0600); err != nil {
+		return err
+	}
+
+	return updateErr
+}
+
+// trackingVMs returns the UIDs of the VMs with recorded leases
+func trackingVMs() ([]string, error) {
+	files, err := filepath.Glob(path.Join(leaseDir, "*", constants.VAULT_LEASES_FILE))
+	if err != nil {
+		return nil, err
+	}
+
+	vmUIDs := make([]string, 0, len(files))
+	for _, file := range files {
+		vmUIDs = append(vmUIDs, path.Base(path.Dir(file)))
+	}
+
+	return vmUIDs, nil
+}
+
+// Renewer renews the token of ignited and the tracked leases before they expire
+type Renewer struct {
+	client *Client
+}
+
+// NewRenewer creates a new Renewer renewing the token of the client
+func NewRenewer(client *Client) *Renewer {
+	return &Renewer{client}
+}
+
+// Run renews the token and leases at two thirds of their TTL, at least every
+// VAULT_RENEW_INTERVAL. It never returns.
+func (r *Renewer) Run() {
+	for {
+		time.Sleep(r.renew())
+	}
+}
+
+// renew renews the token and the leases, and returns when to renew them next
+func (r *Renewer) renew() time.Duration {
+	next := constants.VAULT_RENEW_INTERVAL
+
+	ttl, err := r.renewToken()
+	if err != nil {
+		log.Errorf("Failed to renew the Vault token: %v", err)
+		next = constants.VAULT_RENEW_RETRY
+	} else if ttl > 0 {
+		next = minDuration(next, renewAfter(ttl))
+	}
+
+	vmUIDs, err := trackingVMs()
+	if err != nil {
+		log.Errorf("Failed to list the Vault leases of the VMs: %v", err)
+		return constants.VAULT_RENEW_RETRY
+	}
+
+	for _, vmUID := range vmUIDs {
+		err := updateLeases(vmUID, func(leases []*lease) ([]*lease, error) {
+			kept := leases[:0]
+			for _, l := range leases {
+				var renewed Secret
+				if err := r.client.do(http.MethodPut, "sys/leases/renew", map[string]string{"lease_id": l.ID}, &renewed); err != nil {
+					// Expired leases can't be renewed anymore, the VM gets new secrets when it's restarted
+					log.Errorf("Failed to renew the Vault lease %q of VM %q, not renewing it anymore: %v", l.ID, vmUID, err)
+					continue
+				}
+
+				l.Duration = time.Duration(renewed.LeaseDuration) * time.Second
+				next = minDuration(next, renewAfter(l.Duration))
+				kept = append(kept, l)
+			}
+
+			return kept, nil
+		})
+		if err != nil {
+			log.Errorf("Failed to renew the Vault leases of VM %q: %v", vmUID, err)
+		}
+	}
+
+	return next
+}
+
+// renewToken renews the token if it's renewable, and returns its TTL. Tokens that don't
+// expire, like root tokens, have no TTL.
+func (r *Renewer) renewToken() (time.Duration, error) {
+	var lookup struct {
+		Data struct {
+			TTL       int  `json:"ttl"`
+			Renewable bool `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := r.client.do(http.MethodGet, "auth/token/lookup-self", nil, &lookup); err != nil {
+		return 0, err
+	}
+
+	if !lookup.Data.Renewable {
+		return time.Duration(lookup.Data.TTL) * time.Second, nil
+	}
+
+	var renewed struct {
+		Auth struct {
+			LeaseDuration int `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := r.client.do(http.MethodPut, "auth/token/renew-self", struct{}{}, &renewed); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(renewed.Auth.LeaseDuration) * time.Second, nil
+}
+
+// renewAfter returns when to renew something expiring after the TTL
+func renewAfter(ttl time.Duration) time.Duration {
+	d := ttl * 2 / 3
+	if d < time.Second {
+		d = time.Second
+	}
+
+	return d
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+
+	return b
+}
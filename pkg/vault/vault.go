@@ -0,0 +1,242 @@
+// Package vault reads the secrets of the VMs, and the SSH keys authorized in them, from
+// HashiCorp Vault through its HTTP API. The leases of the secrets are recorded in the
+// directories of the VMs, so ignited renews them while the VMs run, whichever process
+// started them, and they're revoked when the VMs are removed.
+package vault
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/providers"
+)
+
+// ErrNotConfigured is returned when there's no Vault address in the configuration or environment
+var ErrNotConfigured = errors.New("Vault isn't configured, set vault.address in the ignite configuration or $VAULT_ADDR")
+
+// Client is a client of the Vault HTTP API
+type Client struct {
+	address   string
+	token     string
+	namespace string
+	http      *http.Client
+}
+
+// Secret is a secret read from Vault. The data of KV version 2 secrets is unwrapped.
+type Secret struct {
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int                    `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+}
+
+// NewClient creates a client of the Vault server in the configuration. The address and
+// token default to $VAULT_ADDR and $VAULT_TOKEN.
+func NewClient(cfg *api.VaultConfiguration) (*Client, error) {
+	c := &Client{
+		address:   strings.TrimSuffix(cfg.Address, "/"),
+		namespace: cfg.Namespace,
+		http:      &http.Client{Timeout: constants.VAULT_TIMEOUT},
+	}
+
+	if len(c.address) == 0 {
+		c.address = strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/")
+	}
+	if len(c.address) == 0 {
+		return nil, ErrNotConfigured
+	}
+
+	if len(cfg.TokenFile) != 0 {
+		b, err := ioutil.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the Vault token: %v", err)
+		}
+		c.token = strings.TrimSpace(string(b))
+	} else {
+		c.token = os.Getenv("VAULT_TOKEN")
+	}
+	if len(c.token) == 0 {
+		return nil, fmt.Errorf("no Vault token, set vault.tokenFile in the ignite configuration or $VAULT_TOKEN")
+	}
+
+	if len(cfg.CAFile) != 0 {
+		b, err := ioutil.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the Vault CA certificate: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificate found in %q", cfg.CAFile)
+		}
+		c.http.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	}
+
+	return c, nil
+}
+
+// Default creates a client of the Vault server in the ignite configuration
+func Default() (*Client, error) {
+	cfg := &api.VaultConfiguration{}
+	if providers.ComponentConfig != nil {
+		cfg = &providers.ComponentConfig.Spec.Vault
+	}
+
+	return NewClient(cfg)
+}
+
+// Read reads the secret at the path
+func (c *Client) Read(path string) (*Secret, error) {
+	secret := &Secret{}
+	if err := c.do(http.MethodGet, path, nil, secret); err != nil {
+		return nil, err
+	}
+
+	// KV version 2 wraps the data with its metadata
+	if data, ok := secret.Data["data"].(map[string]interface{}); ok {
+		if _, ok := secret.Data["metadata"]; ok {
+			secret.Data = data
+		}
+	}
+
+	return secret, nil
+}
+
+// ReadField reads the field of the secret referenced as <path>#<field>. The lease of the
+// secret is tracked for the VM with the given UID, see Track.
+func (c *Client) ReadField(vmUID, ref string) ([]byte, error) {
+	path, field, err := ParseRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := c.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Track(vmUID, secret); err != nil {
+		return nil, fmt.Errorf("failed to track the lease of the Vault secret %q: %v", path, err)
+	}
+
+	value, ok := secret.Data[field]
+	if !ok {
+		return nil, fmt.Errorf("the Vault secret %q has no field %q", path, field)
+	}
+
+	// Strings are used as is, other values as JSON
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+
+	return json.Marshal(value)
+}
+
+// AuthorizedKeys returns the public keys in the fields of the secret at the SSH keys path
+// of the ignite configuration. No keys are returned if the path isn't configured.
+func AuthorizedKeys() ([]string, error) {
+	if providers.ComponentConfig == nil || len(providers.ComponentConfig.Spec.Vault.SSHKeysPath) == 0 {
+		return nil, nil
+	}
+
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+
+	path := providers.ComponentConfig.Spec.Vault.SSHKeysPath
+	secret, err := c.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the SSH keys from Vault: %v", err)
+	}
+
+	var keys []string
+	for field, value := range secret.Data {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("the field %q of the Vault secret %q isn't a public key", field, path)
+		}
+
+		keys = append(keys, parseKeys(s)...)
+	}
+
+	return keys, nil
+}
+
+// ParseRef splits a reference to a field of a secret into its path and field
+func ParseRef(ref string) (path, field string, err error) {
+	i := strings.LastIndex(ref, "#")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", fmt.Errorf("invalid Vault reference %q, expected <path>#<field>", ref)
+	}
+
+	return strings.Trim(ref[:i], "/"), ref[i+1:], nil
+}
+
+// parseKeys returns the keys of a value in the authorized_keys format, without the comments
+func parseKeys(value string) (keys []string) {
+	scanner := bufio.NewScanner(strings.NewReader(value))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) != 0 && !strings.HasPrefix(line, "#") {
+			keys = append(keys, line)
+		}
+	}
+
+	return
+}
+
+// do sends a request to the Vault API, and decodes the response into out if it's set
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/v1/%s", c.address, strings.TrimPrefix(path, "/")), &reqBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-Vault-Token", c.token)
+	if len(c.namespace) != 0 {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&vaultErr)
+		if len(vaultErr.Errors) == 0 {
+			return fmt.Errorf("Vault returned %s for %s %s", resp.Status, method, path)
+		}
+
+		return fmt.Errorf("Vault returned %s for %s %s: %s", resp.Status, method, path, strings.Join(vaultErr.Errors, "; "))
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
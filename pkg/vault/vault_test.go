@@ -0,0 +1,200 @@
+package vault
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/flock"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+// setLeaseDir records the leases, and takes their locks, in a temporary directory holding the directory of VM vm1
+func setLeaseDir(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "ignite-vault-test")
+	assert.NilError(t, err)
+	assert.NilError(t, os.MkdirAll(path.Join(dir, "vm", "vm1"), 0755))
+
+	vmDir, lockDir := leaseDir, flock.Dir
+	leaseDir, flock.Dir = path.Join(dir, "vm"), path.Join(dir, "lock")
+	return func() {
+		leaseDir, flock.Dir = vmDir, lockDir
+		os.RemoveAll(dir)
+	}
+}
+
+// readLeases returns the IDs of the recorded leases of the VM
+func readLeases(t *testing.T, vmUID string) (ids []string) {
+	assert.NilError(t, updateLeases(vmUID, func(leases []*lease) ([]*lease, error) {
+		for _, l := range leases {
+			ids = append(ids, l.ID)
+		}
+		return leases, nil
+	}))
+
+	return
+}
+
+// fakeVault serves the responses by path, and records the renewed leases
+func fakeVault(t *testing.T, responses map[string]interface{}, renewed *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("X-Vault-Token"), "s.token")
+
+		if r.URL.Path == "/v1/sys/leases/renew" || r.URL.Path == "/v1/sys/leases/revoke" {
+			var body map[string]string
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+			*renewed = append(*renewed, body["lease_id"])
+		}
+
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {}})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestReadField(t *testing.T) {
+	defer setLeaseDir(t)()
+
+	server := fakeVault(t, map[string]interface{}{
+		// KV version 2
+		"/v1/secret/data/app": map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"password": "hunter2", "port": 5432},
+				"metadata": map[string]interface{}{"version": 3},
+			},
+		},
+		// A dynamic secret with a lease
+		"/v1/database/creds/app": map[string]interface{}{
+			"lease_id":       "database/creds/app/abcd",
+			"lease_duration": 3600,
+			"renewable":      true,
+			"data":           map[string]interface{}{"username": "v-app"},
+		},
+	}, nil)
+	defer server.Close()
+
+	os.Unsetenv("VAULT_TOKEN")
+	_, err := NewClient(&api.VaultConfiguration{Address: server.URL + "/"})
+	assert.ErrorContains(t, err, "no Vault token")
+
+	os.Setenv("VAULT_TOKEN", "s.token")
+	defer os.Unsetenv("VAULT_TOKEN")
+	c, err := NewClient(&api.VaultConfiguration{Address: server.URL + "/"})
+	assert.NilError(t, err)
+
+	value, err := c.ReadField("vm1", "secret/data/app#password")
+	assert.NilError(t, err)
+	assert.Equal(t, string(value), "hunter2")
+
+	value, err = c.ReadField("vm1", "secret/data/app#port")
+	assert.NilError(t, err)
+	assert.Equal(t, string(value), "5432")
+
+	_, err = c.ReadField("vm1", "secret/data/app#user")
+	assert.ErrorContains(t, err, "no field \"user\"")
+
+	_, err = c.ReadField("vm1", "secret/data/missing#user")
+	assert.ErrorContains(t, err, "404 Not Found")
+
+	_, err = c.ReadField("vm1", "secret/data/app")
+	assert.ErrorContains(t, err, "invalid Vault reference")
+
+	value, err = c.ReadField("vm1", "database/creds/app#username")
+	assert.NilError(t, err)
+	assert.Equal(t, string(value), "v-app")
+
+	// Only the lease of the dynamic secret is recorded
+	assert.DeepEqual(t, readLeases(t, "vm1"), []string{"database/creds/app/abcd"})
+}
+
+func TestRenew(t *testing.T) {
+	defer setLeaseDir(t)()
+
+	var renewed []string
+	server := fakeVault(t, map[string]interface{}{
+		"/v1/auth/token/lookup-self": map[string]interface{}{
+			"data": map[string]interface{}{"ttl": 7200, "renewable": true},
+		},
+		"/v1/auth/token/renew-self": map[string]interface{}{
+			"auth": map[string]interface{}{"lease_duration": 7200},
+		},
+		"/v1/sys/leases/renew": map[string]interface{}{
+			"lease_id":       "database/creds/app/abcd",
+			"lease_duration": 900,
+			"renewable":      true,
+		},
+	}, &renewed)
+	defer server.Close()
+
+	os.Setenv("VAULT_TOKEN", "s.token")
+	defer os.Unsetenv("VAULT_TOKEN")
+	c, err := NewClient(&api.VaultConfiguration{Address: server.URL})
+	assert.NilError(t, err)
+
+	// Without leases, the token is renewed at two thirds of its TTL, at most every hour
+	r := NewRenewer(c)
+	assert.Equal(t, r.renew(), time.Hour)
+
+	assert.NilError(t, Track("vm1", &Secret{LeaseID: "database/creds/app/abcd", LeaseDuration: 900, Renewable: true}))
+	assert.NilError(t, Track("vm1", &Secret{LeaseID: "", Renewable: false}))
+	assert.Equal(t, r.renew(), 10*time.Minute)
+	assert.DeepEqual(t, renewed, []string{"database/creds/app/abcd"})
+
+	// Leases that fail to renew are dropped
+	assert.NilError(t, Track("vm1", &Secret{LeaseID: "database/creds/app/expired", LeaseDuration: 900, Renewable: true}))
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/sys/leases/renew" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"ttl": 0}})
+	})
+	r.renew()
+	assert.Equal(t, len(readLeases(t, "vm1")), 0)
+	assert.Assert(t, !util.FileExists(path.Join(leaseDir, "vm1", constants.VAULT_LEASES_FILE)))
+}
+
+func TestRelease(t *testing.T) {
+	defer setLeaseDir(t)()
+
+	var revoked []string
+	server := fakeVault(t, map[string]interface{}{
+		"/v1/sys/leases/revoke": map[string]interface{}{},
+	}, &revoked)
+	defer server.Close()
+
+	os.Setenv("VAULT_ADDR", server.URL)
+	os.Setenv("VAULT_TOKEN", "s.token")
+	defer os.Unsetenv("VAULT_ADDR")
+	defer os.Unsetenv("VAULT_TOKEN")
+
+	// The leases recorded by the process that started the VM are revoked by another one
+	assert.NilError(t, Track("vm1", &Secret{LeaseID: "database/creds/app/abcd", LeaseDuration: 900, Renewable: true}))
+	assert.NilError(t, Track("vm1", &Secret{LeaseID: "aws/creds/app/efgh", LeaseDuration: 900, Renewable: true}))
+	assert.NilError(t, Release("vm1"))
+	assert.DeepEqual(t, revoked, []string{"database/creds/app/abcd", "aws/creds/app/efgh"})
+	assert.Equal(t, len(readLeases(t, "vm1")), 0)
+
+	// VMs without leases don't need Vault
+	os.Unsetenv("VAULT_ADDR")
+	assert.NilError(t, Release("vm2"))
+}
+
+func TestParseKeys(t *testing.T) {
+	keys := parseKeys("# ops team\nssh-ed25519 AAAAC3Nza alice\n\n  ssh-rsa AAAAB3Nza bob  \n")
+	assert.DeepEqual(t, keys, []string{"ssh-ed25519 AAAAC3Nza alice", "ssh-rsa AAAAB3Nza bob"})
+}
@@ -0,0 +1,346 @@
+// Package vfio passes host PCI devices, like GPUs, through into the VMs with VFIO. The devices
+// are bound to the vfio-pci driver while the VMs run, and rebound to their host drivers after.
+package vfio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+	"github.com/weaveworks/ignite/pkg/constants"
+	"github.com/weaveworks/ignite/pkg/util"
+)
+
+const (
+	// driver is the driver the devices are bound to, for QEMU to pass them through
+	driver = "vfio-pci"
+	// ContainerDevice is the VFIO container device QEMU opens the IOMMU groups with
+	ContainerDevice = "/dev/vfio/vfio"
+	// pciBridgeClass is the class of PCI bridges, which stay on the host
+	pciBridgeClass = "0x0604"
+)
+
+// sysfsDir is where sysfs is mounted, the tests replace it
+var sysfsDir = "/sys"
+
+// ownerDir records the VM each device is held by, the tests replace it
+var ownerDir = constants.VFIO_OWNER_DIR
+
+// Addresses returns the PCI addresses of the devices of the VM
+func Addresses(vm *api.VM) []string {
+	addresses := make([]string, 0, len(vm.Spec.Devices))
+	for _, d := range vm.Spec.Devices {
+		addresses = append(addresses, d.Address)
+	}
+
+	return addresses
+}
+
+// IOMMUGroup returns the IOMMU group of the device. Devices only have one if the IOMMU of
+// the host is enabled.
+func IOMMUGroup(address string) (string, error) {
+	if !util.DirExists(devicePath(address)) {
+		return "", fmt.Errorf("PCI device %s doesn't exist", address)
+	}
+
+	group, err := os.Readlink(devicePath(address, "iommu_group"))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("PCI device %s has no IOMMU group, the IOMMU of the host needs to be enabled (intel_iommu=on or amd_iommu=on)", address)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return path.Base(group), nil
+}
+
+// GroupDevice returns the device node of the IOMMU group
+func GroupDevice(group string) string {
+	return path.Join("/dev/vfio", group)
+}
+
+// Validate verifies the devices can be passed through. VFIO passes whole IOMMU groups through,
+// so the other devices in the groups of the devices need to be passed through too, except
+// for the PCI bridges.
+func Validate(addresses []string) error {
+	passed := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		passed[address] = true
+	}
+
+	for _, address := range addresses {
+		group, err := IOMMUGroup(address)
+		if err != nil {
+			return err
+		}
+
+		members, err := ioutil.ReadDir(path.Join(sysfsDir, "kernel/iommu_groups", group, "devices"))
+		if err != nil {
+			return fmt.Errorf("failed to list the devices of IOMMU group %s: %v", group, err)
+		}
+
+		for _, member := range members {
+			if passed[member.Name()] {
+				continue
+			}
+
+			class, err := ioutil.ReadFile(devicePath(member.Name(), "class"))
+			if err != nil {
+				return err
+			}
+
+			if !strings.HasPrefix(strings.TrimSpace(string(class)), pciBridgeClass) {
+				return fmt.Errorf("PCI device %s shares IOMMU group %s with PCI device %s, which needs to be passed through too", address, group, member.Name())
+			}
+		}
+	}
+
+	return nil
+}
+
+// Bind binds the devices of the VM to vfio-pci, and returns the device nodes QEMU needs to pass
+// them through. The host drivers of the devices are recorded to rebind them with Release.
+// Devices that are already bound to vfio-pci are left bound to it. A device is only passed
+// through into one VM at a time, devices held by other VMs are refused. If a device fails
+// to bind, the devices bound before it are rebound to their host drivers.
+func Bind(vm *api.VM) (deviceNodes []string, err error) {
+	addresses := Addresses(vm)
+	if err := Validate(addresses); err != nil {
+		return nil, err
+	}
+
+	if !util.DirExists(driverPath(driver)) {
+		if _, err := util.ExecuteCommand("modprobe", driver); err != nil {
+			return nil, fmt.Errorf("failed to load the %s driver: %v", driver, err)
+		}
+	}
+
+	if err := os.MkdirAll(deviceDir(vm), constants.DATA_DIR_PERM); err != nil {
+		return nil, err
+	}
+
+	// Give back the devices bound so far if a later one fails
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		if releaseErr := Release(vm); releaseErr != nil {
+			log.Warnf("Failed to release the PCI devices of VM %q: %v", vm.GetUID(), releaseErr)
+		}
+	}()
+
+	deviceNodes = []string{ContainerDevice}
+	seen := map[string]bool{}
+	for _, address := range addresses {
+		if err := bind(vm, address); err != nil {
+			return nil, fmt.Errorf("failed to bind PCI device %s of VM %q to %s: %v", address, vm.GetUID(), driver, err)
+		}
+
+		group, err := IOMMUGroup(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[group] {
+			seen[group] = true
+			deviceNodes = append(deviceNodes, GroupDevice(group))
+		}
+	}
+
+	return deviceNodes, nil
+}
+
+// bind unbinds the device from its host driver, and binds it to vfio-pci
+func bind(vm *api.VM, address string) error {
+	if err := claim(vm, address); err != nil {
+		return err
+	}
+
+	hostDriver, err := boundDriver(address)
+	if err != nil || hostDriver == driver {
+		return err
+	}
+
+	// Record the host driver first, so the device is rebound even if binding fails halfway
+	if err := ioutil.WriteFile(path.Join(deviceDir(vm), address), []byte(hostDriver), 0644); err != nil {
+		return err
+	}
+
+	if len(hostDriver) > 0 {
+		if err := writeSysfs(devicePath(address, "driver", "unbind"), address); err != nil {
+			return err
+		}
+	}
+
+	// The override makes only vfio-pci probe the device
+	if err := writeSysfs(devicePath(address, "driver_override"), driver); err != nil {
+		return err
+	}
+
+	if err := writeSysfs(path.Join(sysfsDir, "bus/pci/drivers_probe"), address); err != nil {
+		return err
+	}
+
+	if bound, err := boundDriver(address); err != nil || bound != driver {
+		return fmt.Errorf("the device is bound to %q after probing: %v", bound, err)
+	}
+
+	log.Debugf("Unbound PCI device %s from driver %q for VM %q", address, hostDriver, vm.GetUID())
+	return nil
+}
+
+// Release rebinds the devices of the VM that were bound to vfio-pci by Bind to their host drivers
+func Release(vm *api.VM) error {
+	// Devices that were already bound to vfio-pci have no records, but are still claimed
+	records, err := ioutil.ReadDir(deviceDir(vm))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, record := range records {
+		address := record.Name()
+		hostDriver, err := ioutil.ReadFile(path.Join(deviceDir(vm), address))
+		if err != nil {
+			return err
+		}
+
+		if err := release(address, string(hostDriver)); err != nil {
+			return fmt.Errorf("failed to rebind PCI device %s of VM %q to driver %q: %v", address, vm.GetUID(), hostDriver, err)
+		}
+
+		if err := os.Remove(path.Join(deviceDir(vm), address)); err != nil {
+			return err
+		}
+	}
+
+	return unclaim(vm)
+}
+
+// Owner returns the UID of the VM holding the device, or an empty string if it's not held
+func Owner(address string) (string, error) {
+	owner, err := ioutil.ReadFile(path.Join(ownerDir, address))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return string(owner), err
+}
+
+// claim records the VM as the owner of the device. It fails if another VM holds the device,
+// as the device would be rebound to its host driver while still in use by the other VM.
+func claim(vm *api.VM, address string) error {
+	if err := os.MkdirAll(ownerDir, constants.DATA_DIR_PERM); err != nil {
+		return err
+	}
+
+	file := path.Join(ownerDir, address)
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		owner, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		// The VM still holds the device if it wasn't released when it stopped
+		if string(owner) != vm.GetUID().String() {
+			return fmt.Errorf("the device is held by VM %q", owner)
+		}
+
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(vm.GetUID().String())
+	return err
+}
+
+// unclaim removes the VM as the owner of its devices, so other VMs can use them
+func unclaim(vm *api.VM) error {
+	files, err := ioutil.ReadDir(ownerDir)
+	if os.IsNotExist(err) {
+		return nil // No devices are held
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, fi := range files {
+		owner, err := ioutil.ReadFile(path.Join(ownerDir, fi.Name()))
+		if err != nil {
+			return err
+		}
+
+		if string(owner) != vm.GetUID().String() {
+			continue
+		}
+
+		if err := os.Remove(path.Join(ownerDir, fi.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// release unbinds the device from vfio-pci, and binds it to its host driver if it had one
+func release(address, hostDriver string) error {
+	if bound, err := boundDriver(address); err != nil {
+		return err
+	} else if bound == driver {
+		if err := writeSysfs(devicePath(address, "driver", "unbind"), address); err != nil {
+			return err
+		}
+	}
+
+	// An empty override lets the device be probed by other drivers again
+	if err := writeSysfs(devicePath(address, "driver_override"), "\n"); err != nil {
+		return err
+	}
+
+	if len(hostDriver) == 0 {
+		return nil
+	}
+
+	return writeSysfs(path.Join(driverPath(hostDriver), "bind"), address)
+}
+
+// boundDriver returns the driver the device is bound to, or an empty string if it's unbound
+func boundDriver(address string) (string, error) {
+	link, err := os.Readlink(devicePath(address, "driver"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return path.Base(link), nil
+}
+
+// writeSysfs writes the value to the sysfs attribute, the tests replace it to emulate the kernel
+var writeSysfs = func(file, value string) error {
+	return ioutil.WriteFile(file, []byte(value), 0200)
+}
+
+// devicePath returns the sysfs path of the device, or of an attribute of it
+func devicePath(address string, elem ...string) string {
+	return path.Join(append([]string{sysfsDir, "bus/pci/devices", address}, elem...)...)
+}
+
+// driverPath returns the sysfs path of the PCI driver
+func driverPath(name string) string {
+	return path.Join(sysfsDir, "bus/pci/drivers", name)
+}
+
+// deviceDir returns the directory recording the host drivers of the devices of the VM, the tests replace it
+var deviceDir = func(vm *api.VM) string {
+	return path.Join(vm.ObjectPath(), constants.VFIO_DEVICE_DIR)
+}
@@ -0,0 +1,189 @@
+package vfio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	api "github.com/weaveworks/ignite/pkg/apis/ignite"
+)
+
+func TestValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-vfio-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	sysfs := sysfsDir
+	defer func() { sysfsDir = sysfs }()
+	sysfsDir = dir
+
+	// device creates a PCI device of the class in the IOMMU group, or without a group if it's empty
+	device := func(address, class, group string) {
+		assert.NilError(t, os.MkdirAll(devicePath(address), 0755))
+		assert.NilError(t, ioutil.WriteFile(devicePath(address, "class"), []byte(class+"\n"), 0644))
+		if len(group) == 0 {
+			return
+		}
+
+		groupDir := path.Join(dir, "kernel/iommu_groups", group)
+		assert.NilError(t, os.MkdirAll(path.Join(groupDir, "devices"), 0755))
+		assert.NilError(t, os.Symlink(devicePath(address), path.Join(groupDir, "devices", address)))
+		assert.NilError(t, os.Symlink(groupDir, devicePath(address, "iommu_group")))
+	}
+
+	// A GPU with its audio function, behind a bridge in the same group
+	device("0000:00:01.0", "0x060400", "1")
+	device("0000:01:00.0", "0x030000", "1")
+	device("0000:01:00.1", "0x040300", "1")
+	// A device on a host without IOMMU
+	device("0000:02:00.0", "0x020000", "")
+
+	tests := []struct {
+		name      string
+		addresses []string
+		err       string
+	}{
+		{
+			name:      "whole group",
+			addresses: []string{"0000:01:00.0", "0000:01:00.1"},
+		},
+		{
+			name:      "part of a group",
+			addresses: []string{"0000:01:00.0"},
+			err:       "PCI device 0000:01:00.0 shares IOMMU group 1 with PCI device 0000:01:00.1, which needs to be passed through too",
+		},
+		{
+			name:      "no IOMMU group",
+			addresses: []string{"0000:02:00.0"},
+			err:       "PCI device 0000:02:00.0 has no IOMMU group, the IOMMU of the host needs to be enabled (intel_iommu=on or amd_iommu=on)",
+		},
+		{
+			name:      "nonexistent device",
+			addresses: []string{"0000:03:00.0"},
+			err:       "PCI device 0000:03:00.0 doesn't exist",
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			err := Validate(rt.addresses)
+			if len(rt.err) > 0 {
+				assert.Error(t, err, rt.err)
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
+
+func TestClaim(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-vfio-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	owners := ownerDir
+	defer func() { ownerDir = owners }()
+	ownerDir = path.Join(dir, "vfio")
+
+	vm1, vm2 := &api.VM{}, &api.VM{}
+	vm1.SetUID("0123456789abcdef")
+	vm2.SetUID("fedcba9876543210")
+
+	// A device is held by the first VM claiming it, which can claim it again when restarted
+	assert.NilError(t, claim(vm1, "0000:01:00.0"))
+	assert.NilError(t, claim(vm1, "0000:01:00.0"))
+	assert.Error(t, claim(vm2, "0000:01:00.0"), `the device is held by VM "0123456789abcdef"`)
+	assert.NilError(t, claim(vm2, "0000:02:00.0"))
+
+	// Releasing a VM only gives up the devices it holds
+	assert.NilError(t, unclaim(vm1))
+	assert.Error(t, claim(vm1, "0000:02:00.0"), `the device is held by VM "fedcba9876543210"`)
+	assert.NilError(t, claim(vm2, "0000:01:00.0"))
+}
+
+func TestBindRollback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-vfio-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	sysfs, owners, devices, write := sysfsDir, ownerDir, deviceDir, writeSysfs
+	defer func() { sysfsDir, ownerDir, deviceDir, writeSysfs = sysfs, owners, devices, write }()
+	sysfsDir = path.Join(dir, "sys")
+	ownerDir = path.Join(dir, "vfio")
+	deviceDir = func(vm *api.VM) string { return path.Join(dir, "vm", vm.GetUID().String(), "vfio") }
+
+	// Emulate the kernel (un)binding the devices on the writes to sysfs
+	link := func(address, driverName string) {
+		os.Remove(devicePath(address, "driver"))
+		if len(driverName) > 0 {
+			assert.NilError(t, os.Symlink(driverPath(driverName), devicePath(address, "driver")))
+		}
+	}
+	writeSysfs = func(file, value string) error {
+		switch {
+		case path.Base(file) == "unbind":
+			link(value, "")
+		case path.Base(file) == "drivers_probe":
+			override, err := ioutil.ReadFile(devicePath(value, "driver_override"))
+			if err != nil {
+				return err
+			}
+			link(value, strings.TrimSpace(string(override)))
+		case path.Base(file) == "bind":
+			link(value, path.Base(path.Dir(file)))
+		default:
+			return ioutil.WriteFile(file, []byte(value), 0644)
+		}
+		return nil
+	}
+
+	// Two GPUs in their own IOMMU groups, bound to their host driver
+	for _, driverName := range []string{"nvidia", driver} {
+		assert.NilError(t, os.MkdirAll(driverPath(driverName), 0755))
+	}
+	for i, address := range []string{"0000:01:00.0", "0000:02:00.0"} {
+		group := path.Join(sysfsDir, "kernel/iommu_groups", fmt.Sprint(i))
+		assert.NilError(t, os.MkdirAll(path.Join(group, "devices"), 0755))
+		assert.NilError(t, os.MkdirAll(devicePath(address), 0755))
+		assert.NilError(t, os.Symlink(devicePath(address), path.Join(group, "devices", address)))
+		assert.NilError(t, os.Symlink(group, devicePath(address, "iommu_group")))
+		link(address, "nvidia")
+	}
+
+	vm1, vm2 := &api.VM{}, &api.VM{}
+	vm1.SetUID("0123456789abcdef")
+	vm2.SetUID("fedcba9876543210")
+	vm1.Spec.Devices = []api.PCIDevice{{Address: "0000:01:00.0"}, {Address: "0000:02:00.0"}}
+
+	// The second device is held by another VM, so the first one is given back
+	assert.NilError(t, claim(vm2, "0000:02:00.0"))
+	_, err = Bind(vm1)
+	assert.Error(t, err, `failed to bind PCI device 0000:02:00.0 of VM "0123456789abcdef" to vfio-pci: the device is held by VM "fedcba9876543210"`)
+
+	bound, err := boundDriver("0000:01:00.0")
+	assert.NilError(t, err)
+	assert.Equal(t, bound, "nvidia")
+
+	owner, err := Owner("0000:01:00.0")
+	assert.NilError(t, err)
+	assert.Equal(t, owner, "")
+
+	records, err := ioutil.ReadDir(deviceDir(vm1))
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 0)
+
+	// Once the other VM gives the device up, both are bound
+	assert.NilError(t, unclaim(vm2))
+	deviceNodes, err := Bind(vm1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, deviceNodes, []string{ContainerDevice, "/dev/vfio/0", "/dev/vfio/1"})
+
+	bound, err = boundDriver("0000:02:00.0")
+	assert.NilError(t, err)
+	assert.Equal(t, bound, driver)
+}
@@ -0,0 +1,162 @@
+// Package vmlinux extracts the uncompressed kernel Firecracker boots from compressed kernels,
+// like the bzImage or vmlinuz of distribution kernel packages, or the Image.gz of arm64
+package vmlinux
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/weaveworks/ignite/pkg/arch"
+)
+
+// compression is a compression of the kernel payload, found by its magic number. Compressions
+// without a Go decompressor are decompressed by their command line tool.
+type compression struct {
+	name    string
+	magic   []byte
+	reader  func(io.Reader) (io.Reader, error)
+	command []string
+}
+
+// compressions are the compressions of the kernel, in the order scripts/extract-vmlinux of
+// the kernel tries them
+var compressions = []compression{
+	{name: "gzip", magic: []byte{0x1f, 0x8b, 0x08}, reader: func(r io.Reader) (io.Reader, error) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		// The payload is followed by the rest of the kernel
+		gz.Multistream(false)
+		return gz, nil
+	}},
+	{name: "xz", magic: []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, command: []string{"xz", "-dc"}},
+	{name: "bzip2", magic: []byte("BZh"), reader: func(r io.Reader) (io.Reader, error) {
+		return bzip2.NewReader(r), nil
+	}},
+	{name: "lzma", magic: []byte{0x5d, 0x00, 0x00, 0x00}, command: []string{"xz", "--format=lzma", "-dc"}},
+	{name: "lzo", magic: []byte{0x89, 'L', 'Z', 'O'}, command: []string{"lzop", "-dc"}},
+	{name: "lz4", magic: []byte{0x02, 0x21, 0x4c, 0x18}, command: []string{"lz4", "-dc"}},
+	{name: "zstd", magic: []byte{0x28, 0xb5, 0x2f, 0xfd}, reader: func(r io.Reader) (io.Reader, error) {
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	}},
+}
+
+// ErrNoKernel is returned if no kernel is found in the payloads of the compressed kernel
+var ErrNoKernel = errors.New("no kernel found in the compressed kernel")
+
+// Compressed returns true if the kernel file isn't an uncompressed kernel Firecracker boots,
+// i.e. an ELF vmlinux or an arm64 Image
+func Compressed(kernelFile string) (bool, error) {
+	_, isELF, err := arch.Kernel(kernelFile)
+	if os.IsNotExist(err) {
+		return false, err
+	}
+
+	return err != nil && !isELF, nil
+}
+
+// Extract extracts the uncompressed kernel of the compressed kernel file into the vmlinux file,
+// and returns the compression of the kernel. The payloads are tried in order until one
+// decompresses to a kernel. Their decompression errors are ignored, as the payloads are
+// followed by the rest of the compressed kernel.
+func Extract(kernelFile, vmlinuxFile string) (string, error) {
+	b, err := ioutil.ReadFile(kernelFile)
+	if err != nil {
+		return "", err
+	}
+
+	// The kernel is extracted under a temporary name, so a failed extraction isn't booted
+	out, err := ioutil.TempFile(path.Dir(vmlinuxFile), path.Base(vmlinuxFile)+".*.partial")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	var missingTools []string
+	for _, c := range compressions {
+		for offset := 0; ; {
+			i := bytes.Index(b[offset:], c.magic)
+			if i < 0 {
+				break
+			}
+			offset += i
+
+			err := decompress(c, bytes.NewReader(b[offset:]), out)
+			if errors.Is(err, exec.ErrNotFound) {
+				missingTools = append(missingTools, c.command[0])
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+			offset += len(c.magic)
+
+			// Kernels of other architectures are extracted too, they're rejected on import
+			if _, isELF, err := arch.Kernel(out.Name()); err != nil && !isELF {
+				continue
+			}
+
+			if err := out.Close(); err != nil {
+				return "", err
+			}
+
+			return c.name, os.Rename(out.Name(), vmlinuxFile)
+		}
+	}
+
+	if len(missingTools) > 0 {
+		return "", fmt.Errorf("%w, it may be compressed with a compression of the missing tools %v", ErrNoKernel, missingTools)
+	}
+
+	return "", ErrNoKernel
+}
+
+// decompress decompresses the payload into the truncated output file. Only errors running the
+// decompression command are returned.
+func decompress(c compression, payload io.Reader, out *os.File) error {
+	if err := out.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if c.reader == nil {
+		cmd := exec.Command(c.command[0], c.command[1:]...)
+		cmd.Stdin = payload
+		cmd.Stdout = out
+		if err := cmd.Run(); err != nil {
+			if _, ok := err.(*exec.ExitError); !ok {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	r, err := c.reader(payload)
+	if err != nil {
+		return nil
+	}
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	_, _ = io.Copy(out, r)
+	return nil
+}
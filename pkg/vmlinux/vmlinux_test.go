@@ -0,0 +1,112 @@
+package vmlinux
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/weaveworks/ignite/pkg/util"
+	"gotest.tools/assert"
+)
+
+// imageKernel returns an arm64 Image kernel, recognized on all hosts
+func imageKernel() []byte {
+	kernel := make([]byte, 4096)
+	binary.LittleEndian.PutUint32(kernel[56:], 0x644d5241)
+	copy(kernel[64:], "Linux version 5.10.51")
+	return kernel
+}
+
+func TestExtract(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignite-vmlinux-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	gzipped := func(b []byte) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write(b)
+		assert.NilError(t, err)
+		assert.NilError(t, gz.Close())
+		return buf.Bytes()
+	}
+
+	zstded := func(b []byte) []byte {
+		var buf bytes.Buffer
+		zw, err := zstd.NewWriter(&buf)
+		assert.NilError(t, err)
+		_, err = zw.Write(b)
+		assert.NilError(t, err)
+		assert.NilError(t, zw.Close())
+		return buf.Bytes()
+	}
+
+	// A setup stub in front of the payload, which is followed by the rest of the kernel
+	wrapped := func(payload []byte) []byte {
+		return append(append([]byte("MZ setup \x1f\x8b no gzip here"), payload...), "trailer"...)
+	}
+
+	tests := []struct {
+		name        string
+		content     []byte
+		compression string
+		err         error
+	}{
+		{
+			name:        "Image.gz",
+			content:     gzipped(imageKernel()),
+			compression: "gzip",
+		},
+		{
+			name:        "gzip bzImage",
+			content:     wrapped(gzipped(imageKernel())),
+			compression: "gzip",
+		},
+		{
+			name:        "zstd bzImage",
+			content:     wrapped(zstded(imageKernel())),
+			compression: "zstd",
+		},
+		{
+			name:    "no kernel in the payload",
+			content: wrapped(gzipped([]byte("not a kernel"))),
+			err:     ErrNoKernel,
+		},
+	}
+
+	for _, rt := range tests {
+		t.Run(rt.name, func(t *testing.T) {
+			kernelFile := path.Join(dir, "vmlinuz")
+			vmlinuxFile := path.Join(dir, "vmlinux")
+			assert.NilError(t, ioutil.WriteFile(kernelFile, rt.content, 0644))
+			defer os.Remove(vmlinuxFile)
+
+			compressed, err := Compressed(kernelFile)
+			assert.NilError(t, err)
+			assert.Assert(t, compressed)
+
+			compression, err := Extract(kernelFile, vmlinuxFile)
+			if rt.err != nil {
+				assert.ErrorType(t, err, func(err error) bool { return err == rt.err })
+				assert.Assert(t, !util.FileExists(vmlinuxFile))
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, compression, rt.compression)
+
+			vmlinux, err := ioutil.ReadFile(vmlinuxFile)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, vmlinux, imageKernel())
+
+			compressed, err = Compressed(vmlinuxFile)
+			assert.NilError(t, err)
+			assert.Assert(t, !compressed)
+		})
+	}
+}